@@ -0,0 +1,133 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/pkg/errors"
+
+	"github.com/sourcenetwork/defradb/datastore/branch"
+)
+
+// createBranchRequest is the body of a request to create a branch.
+type createBranchRequest struct {
+	// Name is the name of the branch.
+	Name string `json:"name"`
+}
+
+func listBranchesHandler(rw http.ResponseWriter, req *http.Request) {
+	db, err := dbFromContext(req.Context())
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	branches, err := db.GetAllBranches(req.Context())
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	sendJSON(req.Context(), rw, DataResponse{Data: branches}, http.StatusOK)
+}
+
+// createBranchHandler creates a branch of the database with the name given in the request body.
+func createBranchHandler(rw http.ResponseWriter, req *http.Request) {
+	body := createBranchRequest{}
+	if err := getJSON(req, &body); err != nil {
+		handleErr(req.Context(), rw, err, http.StatusBadRequest)
+		return
+	}
+
+	db, err := dbFromContext(req.Context())
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	created, err := db.CreateBranch(req.Context(), body.Name)
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusBadRequest)
+		return
+	}
+
+	sendJSON(req.Context(), rw, DataResponse{Data: created}, http.StatusOK)
+}
+
+// diffBranchHandler returns the differences between the documents and schemas of the branch and
+// those of the database.
+func diffBranchHandler(rw http.ResponseWriter, req *http.Request) {
+	db, err := dbFromContext(req.Context())
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	diff, err := db.DiffBranch(req.Context(), chi.URLParam(req, "name"))
+	if errors.Is(err, branch.ErrBranchNotFound) {
+		handleErr(req.Context(), rw, err, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	sendJSON(req.Context(), rw, DataResponse{Data: diff}, http.StatusOK)
+}
+
+// discardBranchHandler deletes the branch along with all its writes.
+func discardBranchHandler(rw http.ResponseWriter, req *http.Request) {
+	db, err := dbFromContext(req.Context())
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	err = db.DiscardBranch(req.Context(), chi.URLParam(req, "name"))
+	if errors.Is(err, branch.ErrBranchNotFound) {
+		handleErr(req.Context(), rw, err, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	sendJSON(req.Context(), rw, simpleDataResponse("response", "ok"), http.StatusOK)
+}
+
+// onBranch returns a handler calling the given handler with the database of the branch named in
+// the request path in place of the database.
+func onBranch(f http.HandlerFunc) http.HandlerFunc {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		db, err := dbFromContext(req.Context())
+		if err != nil {
+			handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+			return
+		}
+
+		branchDB, err := db.GetBranch(req.Context(), chi.URLParam(req, "name"))
+		if errors.Is(err, branch.ErrBranchNotFound) {
+			handleErr(req.Context(), rw, err, http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+			return
+		}
+
+		f(rw, req.WithContext(context.WithValue(req.Context(), ctxDB{}, branchDB)))
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package http
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/config"
+)
+
+func TestBranchHandlers(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+	testLoadSchema(t, ctx, defra)
+
+	cfg := config.DefaultConfig()
+	cfg.API.AdminToken = "secret"
+	opts := testOptions{
+		Testing:        t,
+		DB:             defra,
+		Headers:        map[string]string{"Authorization": "Bearer secret"},
+		ExpectedStatus: 200,
+		ServerOptions:  serverOptions{cfg: cfg},
+	}
+
+	createResp := struct {
+		Data client.Branch `json:"data"`
+	}{}
+	createOpts := opts
+	createOpts.Method = "POST"
+	createOpts.Path = BranchesPath
+	createOpts.Body = bytes.NewBufferString(`{"name": "dev"}`)
+	createOpts.ResponseData = &createResp
+	testRequest(createOpts)
+	assert.Equal(t, "dev", createResp.Data.Name)
+
+	gqlOpts := opts
+	gqlOpts.Method = "POST"
+	gqlOpts.Path = BranchesPath + "/dev/graphql"
+	gqlOpts.Body = bytes.NewBufferString(`mutation { create_user(data: "{\"name\": \"Bob\"}") { _key } }`)
+	gqlOpts.ResponseData = &map[string]any{}
+	testRequest(gqlOpts)
+
+	// The document is only created on the branch.
+	col, err := defra.GetCollectionByName(ctx, "user")
+	require.NoError(t, err)
+	count, err := col.Count(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), count)
+
+	diffResp := struct {
+		Data client.BranchDiff `json:"data"`
+	}{}
+	diffOpts := opts
+	diffOpts.Method = "GET"
+	diffOpts.Path = BranchesPath + "/dev/diff"
+	diffOpts.ResponseData = &diffResp
+	testRequest(diffOpts)
+	require.Len(t, diffResp.Data.Collections, 1)
+	assert.Equal(t, "user", diffResp.Data.Collections[0].Name)
+	assert.Len(t, diffResp.Data.Collections[0].Added, 1)
+
+	discardOpts := opts
+	discardOpts.Method = "DELETE"
+	discardOpts.Path = BranchesPath + "/dev"
+	discardOpts.ResponseData = &map[string]any{}
+	testRequest(discardOpts)
+
+	errResponse := ErrorResponse{}
+	diffOpts.ExpectedStatus = 404
+	diffOpts.ResponseData = &errResponse
+	testRequest(diffOpts)
+	assert.Contains(t, errResponse.Errors[0].Message, "branch not found")
+}
+
+func TestBranchHandlersWithoutAdminToken(t *testing.T) {
+	errResponse := ErrorResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		Method:         "GET",
+		Path:           BranchesPath,
+		ExpectedStatus: 403,
+		ResponseData:   &errResponse,
+		ServerOptions: serverOptions{
+			cfg: config.DefaultConfig(),
+		},
+	})
+
+	assert.Equal(t, ErrAdminDisabled.Error(), errResponse.Errors[0].Message)
+}
@@ -0,0 +1,57 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package http
+
+import (
+	"net/http"
+
+	"github.com/sourcenetwork/defradb/client"
+)
+
+// cloneCollectionRequest is the body of a request to clone a collection.
+type cloneCollectionRequest struct {
+	// Name is the name of the new collection.
+	Name string `json:"name"`
+	// Filter restricts the documents copied to the ones matching it.
+	Filter string `json:"filter"`
+}
+
+// cloneCollectionHandler clones the collection into a new collection of the name given in the
+// request body, along with its documents matching the given filter.
+func cloneCollectionHandler(rw http.ResponseWriter, req *http.Request) {
+	body := cloneCollectionRequest{}
+	if err := getJSON(req, &body); err != nil {
+		handleErr(req.Context(), rw, err, http.StatusBadRequest)
+		return
+	}
+
+	col, ok := collectionFromRequest(rw, req)
+	if !ok {
+		return
+	}
+	db, err := dbFromContext(req.Context())
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	result, err := db.CloneCollection(req.Context(), client.CloneCollectionOptions{
+		Source: col.Name(),
+		Name:   body.Name,
+		Filter: body.Filter,
+	})
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusBadRequest)
+		return
+	}
+
+	sendJSON(req.Context(), rw, DataResponse{Data: result}, http.StatusOK)
+}
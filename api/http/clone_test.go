@@ -0,0 +1,77 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package http
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/client"
+)
+
+func TestCloneCollectionHandler(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+	testLoadSchema(t, ctx, defra)
+
+	col, err := defra.GetCollectionByName(ctx, "user")
+	require.NoError(t, err)
+	for _, data := range []string{`{"name": "John"}`, `{"name": "Fred"}`} {
+		doc, err := client.NewDocFromJSON([]byte(data))
+		require.NoError(t, err)
+		require.NoError(t, col.Create(ctx, doc))
+	}
+
+	resp := struct {
+		Data client.CloneCollectionResult `json:"data"`
+	}{}
+	testRequest(testOptions{
+		Testing:        t,
+		DB:             defra,
+		Method:         "POST",
+		Path:           CollectionsPath + "/user/clone",
+		Body:           bytes.NewBufferString(`{"name": "staging", "filter": "{name: {_eq: \"John\"}}"}`),
+		ExpectedStatus: 200,
+		ResponseData:   &resp,
+	})
+	assert.Equal(t, "staging", resp.Data.Collection.Name)
+	assert.Equal(t, 1, resp.Data.Documents)
+
+	clone, err := defra.GetCollectionByName(ctx, "staging")
+	require.NoError(t, err)
+	count, err := clone.Count(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), count)
+}
+
+func TestCloneCollectionHandlerWithUnknownCollection(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+
+	errResponse := ErrorResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		DB:             defra,
+		Method:         "POST",
+		Path:           CollectionsPath + "/unknown/clone",
+		Body:           bytes.NewBufferString(`{"name": "staging"}`),
+		ExpectedStatus: 404,
+		ResponseData:   &errResponse,
+	})
+
+	assert.Equal(t, ErrCollectionNotFound.Error(), errResponse.Errors[0].Message)
+}
@@ -0,0 +1,68 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package http
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/ipfs/go-cid"
+	"github.com/pkg/errors"
+)
+
+// publishDatasetHandler publishes the documents of the collection as a dataset, and returns its
+// CID.
+func publishDatasetHandler(rw http.ResponseWriter, req *http.Request) {
+	col, ok := collectionFromRequest(rw, req)
+	if !ok {
+		return
+	}
+
+	root, err := col.PublishDataset(req.Context())
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	sendJSON(
+		req.Context(),
+		rw,
+		simpleDataResponse("cid", root.String()),
+		http.StatusOK,
+	)
+}
+
+// importDatasetHandler imports the dataset with the CID given in the URL into the collection,
+// fetching its blocks from the peers of the node.
+func (h *handler) importDatasetHandler(rw http.ResponseWriter, req *http.Request) {
+	root, err := cid.Decode(chi.URLParam(req, "cid"))
+	if err != nil {
+		handleErr(req.Context(), rw, errors.Wrap(err, "invalid dataset CID"), http.StatusBadRequest)
+		return
+	}
+	if h.options.blockFetcher == nil {
+		handleErr(req.Context(), rw, ErrNoBlockFetcher, http.StatusServiceUnavailable)
+		return
+	}
+
+	col, ok := collectionFromRequest(rw, req)
+	if !ok {
+		return
+	}
+
+	result, err := col.ImportDataset(req.Context(), root, h.options.blockFetcher)
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusBadRequest)
+		return
+	}
+
+	sendJSON(req.Context(), rw, DataResponse{Data: result}, http.StatusOK)
+}
@@ -0,0 +1,88 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ipfs/boxo/blockservice"
+	"github.com/ipfs/boxo/exchange/offline"
+	dag "github.com/ipfs/boxo/ipld/merkledag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/client"
+)
+
+func TestPublishAndImportDatasetHandlers(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+	testLoadSchema(t, ctx, defra)
+
+	col, err := defra.GetCollectionByName(ctx, "user")
+	require.NoError(t, err)
+	doc, err := client.NewDocFromJSON([]byte(`{"name": "John"}`))
+	require.NoError(t, err)
+	require.NoError(t, col.Create(ctx, doc))
+
+	publishResp := DataResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		DB:             defra,
+		Method:         "POST",
+		Path:           CollectionsPath + "/user/dataset",
+		Body:           nil,
+		ExpectedStatus: 200,
+		ResponseData:   &publishResp,
+	})
+	root, ok := publishResp.Data.(map[string]any)["cid"].(string)
+	require.True(t, ok)
+
+	bs := defra.Blockstore()
+	fetcher := dag.NewDAGService(blockservice.New(bs, offline.Exchange(bs)))
+	req, err := http.NewRequest(http.MethodPost, CollectionsPath+"/user/dataset/"+root, nil)
+	require.NoError(t, err)
+	rec := httptest.NewRecorder()
+	newHandler(defra, serverOptions{blockFetcher: fetcher}).ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	importResp := struct {
+		Data client.ImportDatasetResult `json:"data"`
+	}{}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&importResp))
+	// The document is already known by the node that published it.
+	assert.Equal(t, client.ImportDatasetResult{Heads: 1, Known: 1, Blocks: 1}, importResp.Data)
+}
+
+func TestImportDatasetHandlerWithoutP2P(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+	testLoadSchema(t, ctx, defra)
+
+	errResponse := ErrorResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		DB:             defra,
+		Method:         "POST",
+		Path:           CollectionsPath + "/user/dataset/bafybeiewibqtvepvhkrosyo7eqo66kn6jtxhm6w6fknza5hxn3osnbcjzq",
+		Body:           nil,
+		ExpectedStatus: 503,
+		ResponseData:   &errResponse,
+	})
+
+	assert.Equal(t, ErrNoBlockFetcher.Error(), errResponse.Errors[0].Message)
+}
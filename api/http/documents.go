@@ -0,0 +1,360 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/go-chi/chi/v5"
+	"github.com/graphql-go/graphql/language/ast"
+	gqll "github.com/graphql-go/graphql/language/lexer"
+	gqlp "github.com/graphql-go/graphql/language/parser"
+	gqlpr "github.com/graphql-go/graphql/language/printer"
+	gqls "github.com/graphql-go/graphql/language/source"
+	"github.com/pkg/errors"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/client/request"
+)
+
+// listDocumentsHandler returns the documents of a collection, without their relations.
+//
+// The documents may be filtered with the `filter` query parameter, given in the GQL syntax of
+// the filter argument, and paginated with the `limit` and `offset` query parameters.
+func listDocumentsHandler(rw http.ResponseWriter, req *http.Request) {
+	col, ok := collectionFromRequest(rw, req)
+	if !ok {
+		return
+	}
+
+	var args []string
+	if f := req.URL.Query().Get("filter"); f != "" {
+		filter, err := parseFilterLiteral(f)
+		if err != nil {
+			handleErr(req.Context(), rw, errors.Wrap(err, "invalid filter parameter"), http.StatusBadRequest)
+			return
+		}
+		args = append(args, request.FilterClause+": "+filter)
+	}
+	for _, name := range []string{request.LimitClause, request.OffsetClause} {
+		v := req.URL.Query().Get(name)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			handleErr(req.Context(), rw, errors.Wrap(err, "invalid "+name+" parameter"), http.StatusBadRequest)
+			return
+		}
+		args = append(args, fmt.Sprintf("%s: %d", name, n))
+	}
+
+	db, err := dbFromContext(req.Context())
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+		return
+	}
+	query, _ := documentsRequest(col, args)
+	result := db.ExecRequest(req.Context(), query)
+	if len(result.GQL.Errors) > 0 {
+		handleErr(req.Context(), rw, result.GQL.Errors[0], http.StatusBadRequest)
+		return
+	}
+
+	sendJSON(req.Context(), rw, DataResponse{Data: result.GQL.Data}, http.StatusOK)
+}
+
+// documentsRequest returns a request selecting the fields of the documents of the given
+// collection, without their relations, with the given arguments, along with the selected fields.
+func documentsRequest(col client.Collection, args []string) (string, []string) {
+	fields := []string{request.KeyFieldName}
+	for _, field := range col.Schema().Fields {
+		if field.Name == request.KeyFieldName || field.IsObject() {
+			continue
+		}
+		fields = append(fields, field.Name)
+	}
+
+	var query strings.Builder
+	query.WriteString("query {\n")
+	query.WriteString(col.Name())
+	if len(args) > 0 {
+		query.WriteString("(" + strings.Join(args, ", ") + ")")
+	}
+	query.WriteString(" {\n" + strings.Join(fields, "\n") + "\n}\n}")
+	return query.String(), fields
+}
+
+// parseFilterLiteral parses the given GQL object literal and returns it printed back, so that
+// nothing but the literal may be added to the request it is embedded in.
+func parseFilterLiteral(filter string) (string, error) {
+	if !strings.HasPrefix(filter, "{") {
+		filter = "{" + filter + "}"
+	}
+	src := gqls.NewSource(&gqls.Source{Body: []byte(filter)})
+	p, err := gqlp.MakeParser(src, gqlp.ParseOptions{})
+	if err != nil {
+		return "", err
+	}
+	obj, err := gqlp.ParseObject(p, false)
+	if err != nil {
+		return "", err
+	}
+	if p.Token.Kind != gqll.EOF {
+		return "", errors.New("unexpected content after the filter object")
+	}
+	printed, ok := gqlpr.Print(ast.Node(obj)).(string)
+	if !ok {
+		return "", errors.New("failed to print the filter object")
+	}
+	return printed, nil
+}
+
+// createDocumentHandler creates a document from the JSON object given as body, and returns it.
+func createDocumentHandler(rw http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	col, ok := collectionFromRequest(rw, req)
+	if !ok {
+		return
+	}
+
+	doc, err := client.NewDocFromJSON(body)
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusBadRequest)
+		return
+	}
+	if err := col.Create(req.Context(), doc); err != nil {
+		handleErr(req.Context(), rw, err, documentErrStatus(err))
+		return
+	}
+
+	docMap, err := doc.ToMap()
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+		return
+	}
+	sendJSON(req.Context(), rw, DataResponse{Data: docMap}, http.StatusCreated)
+}
+
+// docKeyPreviewHandler returns the dockey the document created from the JSON object given as
+// body would get, without creating it, so that clients can precompute references to documents.
+//
+// The fields of the object must exist in the collection.
+func docKeyPreviewHandler(rw http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	col, ok := collectionFromRequest(rw, req)
+	if !ok {
+		return
+	}
+
+	doc, err := client.NewDocFromJSON(body)
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusBadRequest)
+		return
+	}
+	for name := range doc.Fields() {
+		if _, ok := col.Description().GetField(name); !ok {
+			handleErr(req.Context(), rw, client.NewErrFieldNotExist(name), http.StatusBadRequest)
+			return
+		}
+	}
+
+	sendJSON(req.Context(), rw, simpleDataResponse("dockey", doc.Key().String()), http.StatusOK)
+}
+
+// getDocumentHandler returns a document, without its relations.
+func getDocumentHandler(rw http.ResponseWriter, req *http.Request) {
+	key, ok := docKeyFromRequest(rw, req)
+	if !ok {
+		return
+	}
+
+	col, ok := collectionFromRequest(rw, req)
+	if !ok {
+		return
+	}
+
+	doc, err := col.Get(req.Context(), key, false)
+	if err != nil {
+		handleErr(req.Context(), rw, err, documentErrStatus(err))
+		return
+	}
+
+	docMap, err := doc.ToMap()
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+		return
+	}
+	sendJSON(req.Context(), rw, DataResponse{Data: docMap}, http.StatusOK)
+}
+
+// replaceDocumentHandler replaces the fields of a document with those of the JSON object given as
+// body, and returns the replaced document.
+//
+// The fields of the document missing from the body are cleared.
+func replaceDocumentHandler(rw http.ResponseWriter, req *http.Request) {
+	updateDocument(rw, req, func(doc *client.Document, body []byte) ([]byte, error) {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(body, &fields); err != nil {
+			return nil, errors.Wrap(err, "invalid document")
+		}
+		if key, ok := fields[request.KeyFieldName]; ok {
+			if string(key) != strconv.Quote(doc.Key().String()) {
+				return nil, errors.New("the key of the document does not match the requested key")
+			}
+			delete(fields, request.KeyFieldName)
+		}
+		replacement, err := json.Marshal(fields)
+		if err != nil {
+			return nil, err
+		}
+
+		original, err := documentJSON(doc)
+		if err != nil {
+			return nil, err
+		}
+		return jsonpatch.CreateMergePatch(original, replacement)
+	})
+}
+
+// deleteDocumentHandler deletes a document.
+func deleteDocumentHandler(rw http.ResponseWriter, req *http.Request) {
+	key, ok := docKeyFromRequest(rw, req)
+	if !ok {
+		return
+	}
+
+	col, ok := collectionFromRequest(rw, req)
+	if !ok {
+		return
+	}
+
+	if _, err := col.Delete(req.Context(), key); err != nil {
+		handleErr(req.Context(), rw, err, documentErrStatus(err))
+		return
+	}
+
+	sendJSON(req.Context(), rw, simpleDataResponse("dockey", key.String()), http.StatusOK)
+}
+
+// updateDocument updates a document with the merge patch built from the document and the body
+// of the request by the given function, and returns the updated document.
+func updateDocument(
+	rw http.ResponseWriter,
+	req *http.Request,
+	makeMerge func(doc *client.Document, body []byte) ([]byte, error),
+) {
+	key, ok := docKeyFromRequest(rw, req)
+	if !ok {
+		return
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	db, err := dbFromContext(req.Context())
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	col, ok := collectionFromRequest(rw, req)
+	if !ok {
+		return
+	}
+
+	// The document is read and updated in the same transaction, so that the merge patch is built
+	// from the state it is applied to.
+	txn, err := db.NewTxn(req.Context(), false)
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+		return
+	}
+	defer txn.Discard(req.Context())
+	col = col.WithTxn(txn)
+
+	doc, err := col.Get(req.Context(), key, false)
+	if err != nil {
+		handleErr(req.Context(), rw, err, documentErrStatus(err))
+		return
+	}
+
+	merge, err := makeMerge(doc, body)
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusBadRequest)
+		return
+	}
+
+	if !bytes.Equal(merge, []byte("{}")) {
+		_, err = col.UpdateWithKey(req.Context(), key, string(merge))
+		if err != nil {
+			handleErr(req.Context(), rw, err, http.StatusBadRequest)
+			return
+		}
+		doc, err = col.Get(req.Context(), key, false)
+		if err != nil {
+			handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+			return
+		}
+	}
+	docMap, err := doc.ToMap()
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	if err := txn.Commit(req.Context()); err != nil {
+		handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	sendJSON(req.Context(), rw, DataResponse{Data: docMap}, http.StatusOK)
+}
+
+// documentJSON returns the JSON representation of the fields of the given document, without its
+// key.
+func documentJSON(doc *client.Document) ([]byte, error) {
+	docMap, err := doc.ToMap()
+	if err != nil {
+		return nil, err
+	}
+	delete(docMap, request.KeyFieldName)
+	return json.Marshal(docMap)
+}
+
+func docKeyFromRequest(rw http.ResponseWriter, req *http.Request) (client.DocKey, bool) {
+	key, err := client.NewDocKeyFromString(chi.URLParam(req, "dockey"))
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusBadRequest)
+		return client.DocKey{}, false
+	}
+	return key, true
+}
@@ -0,0 +1,287 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package http
+
+import (
+	"bytes"
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/client"
+)
+
+func testCreateUsers(t *testing.T, ctx context.Context, defra client.DB, docs ...string) []*client.Document {
+	col, err := defra.GetCollectionByName(ctx, "user")
+	require.NoError(t, err)
+	created := make([]*client.Document, len(docs))
+	for i, docStr := range docs {
+		doc, err := client.NewDocFromJSON([]byte(docStr))
+		require.NoError(t, err)
+		require.NoError(t, col.Create(ctx, doc))
+		created[i] = doc
+	}
+	return created
+}
+
+func TestListDocumentsHandler(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+	testLoadSchema(t, ctx, defra)
+	testCreateUsers(
+		t, ctx, defra,
+		`{"name": "Bob", "age": 31}`,
+		`{"name": "Alice", "age": 35}`,
+		`{"name": "John", "age": 21}`,
+	)
+
+	resp := struct {
+		Data []map[string]any `json:"data"`
+	}{}
+	testRequest(testOptions{
+		Testing:        t,
+		DB:             defra,
+		Method:         "GET",
+		Path:           CollectionsPath + "/user?filter=" + url.QueryEscape(`{age: {_gt: 30}}`) + "&limit=1",
+		ExpectedStatus: 200,
+		ResponseData:   &resp,
+	})
+
+	require.Len(t, resp.Data, 1)
+	assert.Contains(t, []any{"Bob", "Alice"}, resp.Data[0]["name"])
+	assert.NotEmpty(t, resp.Data[0]["_key"])
+}
+
+func TestListDocumentsHandlerWithTrailingFilterContent(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+	testLoadSchema(t, ctx, defra)
+
+	errResponse := ErrorResponse{}
+	testRequest(testOptions{
+		Testing: t,
+		DB:      defra,
+		Method:  "GET",
+		Path: CollectionsPath + "/user?filter=" +
+			url.QueryEscape(`{}) { _key } delete_user(filter: {}`),
+		ExpectedStatus: 400,
+		ResponseData:   &errResponse,
+	})
+
+	assert.Contains(t, errResponse.Errors[0].Message, "invalid filter parameter")
+}
+
+func TestCreateDocumentHandler(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+	testLoadSchema(t, ctx, defra)
+
+	resp := DataResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		DB:             defra,
+		Method:         "POST",
+		Path:           CollectionsPath + "/user",
+		Body:           bytes.NewBufferString(`{"name": "Bob", "age": 31}`),
+		ExpectedStatus: 201,
+		ResponseData:   &resp,
+	})
+
+	data, ok := resp.Data.(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "Bob", data["name"])
+
+	key, err := client.NewDocKeyFromString(data["_key"].(string))
+	require.NoError(t, err)
+	col, err := defra.GetCollectionByName(ctx, "user")
+	require.NoError(t, err)
+	exists, err := col.Exists(ctx, key)
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
+
+func TestCreateDocumentHandlerWithExistingDocument(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+	testLoadSchema(t, ctx, defra)
+	testCreateUsers(t, ctx, defra, `{"name": "Bob", "age": 31}`)
+
+	testRequest(testOptions{
+		Testing:        t,
+		DB:             defra,
+		Method:         "POST",
+		Path:           CollectionsPath + "/user",
+		Body:           bytes.NewBufferString(`{"name": "Bob", "age": 31}`),
+		ExpectedStatus: 409,
+		ResponseData:   &ErrorResponse{},
+	})
+}
+
+func TestDocKeyPreviewHandler(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+	testLoadSchema(t, ctx, defra)
+
+	resp := DataResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		DB:             defra,
+		Method:         "POST",
+		Path:           CollectionsPath + "/user/dockey",
+		Body:           bytes.NewBufferString(`{"name": "Bob", "age": 31}`),
+		ExpectedStatus: 200,
+		ResponseData:   &resp,
+	})
+
+	docs := testCreateUsers(t, ctx, defra, `{"age": 31, "name": "Bob"}`)
+	assert.Equal(t, map[string]any{"dockey": docs[0].Key().String()}, resp.Data)
+}
+
+func TestDocKeyPreviewHandlerWithUnknownField(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+	testLoadSchema(t, ctx, defra)
+
+	errResponse := ErrorResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		DB:             defra,
+		Method:         "POST",
+		Path:           CollectionsPath + "/user/dockey",
+		Body:           bytes.NewBufferString(`{"name": "Bob", "email": "bob@example.com"}`),
+		ExpectedStatus: 400,
+		ResponseData:   &errResponse,
+	})
+	assert.Contains(t, errResponse.Errors[0].Message, "email")
+}
+
+func TestGetDocumentHandler(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+	testLoadSchema(t, ctx, defra)
+	docs := testCreateUsers(t, ctx, defra, `{"name": "Bob", "age": 31}`)
+
+	resp := DataResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		DB:             defra,
+		Method:         "GET",
+		Path:           CollectionsPath + "/user/" + docs[0].Key().String(),
+		ExpectedStatus: 200,
+		ResponseData:   &resp,
+	})
+
+	assert.Equal(
+		t,
+		map[string]any{"_key": docs[0].Key().String(), "name": "Bob", "age": float64(31)},
+		resp.Data,
+	)
+}
+
+func TestGetDocumentHandlerWithUnknownDocument(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+	testLoadSchema(t, ctx, defra)
+
+	testRequest(testOptions{
+		Testing:        t,
+		DB:             defra,
+		Method:         "GET",
+		Path:           CollectionsPath + "/user/bae-52b9170d-b77a-5887-b877-cbdbb99b009f",
+		ExpectedStatus: 404,
+		ResponseData:   &ErrorResponse{},
+	})
+}
+
+func TestReplaceDocumentHandler(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+	testLoadSchema(t, ctx, defra)
+	docs := testCreateUsers(t, ctx, defra, `{"name": "Bob", "age": 31, "verified": true}`)
+
+	resp := DataResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		DB:             defra,
+		Method:         "PUT",
+		Path:           CollectionsPath + "/user/" + docs[0].Key().String(),
+		Body:           bytes.NewBufferString(`{"name": "Alice", "age": 31}`),
+		ExpectedStatus: 200,
+		ResponseData:   &resp,
+	})
+
+	assert.Equal(
+		t,
+		map[string]any{"_key": docs[0].Key().String(), "name": "Alice", "age": float64(31), "verified": nil},
+		resp.Data,
+	)
+}
+
+func TestReplaceDocumentHandlerWithMismatchingKey(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+	testLoadSchema(t, ctx, defra)
+	docs := testCreateUsers(t, ctx, defra, `{"name": "Bob", "age": 31}`)
+
+	testRequest(testOptions{
+		Testing: t,
+		DB:      defra,
+		Method:  "PUT",
+		Path:    CollectionsPath + "/user/" + docs[0].Key().String(),
+		Body: bytes.NewBufferString(
+			`{"_key": "bae-52b9170d-b77a-5887-b877-cbdbb99b009f", "name": "Alice"}`,
+		),
+		ExpectedStatus: 400,
+		ResponseData:   &ErrorResponse{},
+	})
+}
+
+func TestDeleteDocumentHandler(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+	testLoadSchema(t, ctx, defra)
+	docs := testCreateUsers(t, ctx, defra, `{"name": "Bob", "age": 31}`)
+
+	resp := DataResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		DB:             defra,
+		Method:         "DELETE",
+		Path:           CollectionsPath + "/user/" + docs[0].Key().String(),
+		ExpectedStatus: 200,
+		ResponseData:   &resp,
+	})
+
+	assert.Equal(t, map[string]any{"dockey": docs[0].Key().String()}, resp.Data)
+
+	testRequest(testOptions{
+		Testing:        t,
+		DB:             defra,
+		Method:         "GET",
+		Path:           CollectionsPath + "/user/" + docs[0].Key().String(),
+		ExpectedStatus: 404,
+		ResponseData:   &ErrorResponse{},
+	})
+}
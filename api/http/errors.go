@@ -15,13 +15,30 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"regexp"
 	"strings"
 
+	"github.com/sourcenetwork/defradb/config"
 	"github.com/sourcenetwork/defradb/errors"
+	"github.com/sourcenetwork/defradb/logging"
 )
 
 var env = os.Getenv("DEFRA_ENV")
 
+// pathPattern matches the absolute file paths of the error messages, preceded by the start of the
+// message or a separator. Only paths of at least two elements are matched.
+var pathPattern = regexp.MustCompile(`(^|[\s"'=(\[])(?:[A-Za-z]:\\|/)[^\s"',:;()\[\]]*[/\\][^\s"',:;()\[\]]*`)
+
+const (
+	errInvalidImportOption string = "invalid import option"
+	errInvalidImportValue  string = "invalid import value"
+	errInvalidPlanDebug    string = "invalid plan debug header value"
+	errInvalidSchemaSig    string = "invalid schema signature header value"
+	errInvalidFieldKeys    string = "invalid field keys header value"
+	errInvalidExportFormat string = "invalid export format"
+	errUnsupportedPatch    string = "unsupported patch content type"
+)
+
 // Errors returnable from this package.
 //
 // This list is incomplete. Undefined errors may also be returned.
@@ -29,15 +46,114 @@ var env = os.Getenv("DEFRA_ENV")
 var (
 	ErrNoListener           = errors.New("cannot serve with no listener")
 	ErrSchema               = errors.New("base must start with the http or https scheme")
-	ErrDatabaseNotAvailable = errors.New("no database available")
-	ErrFormNotSupported     = errors.New("content type application/x-www-form-urlencoded not yet supported")
-	ErrBodyEmpty            = errors.New("body cannot be empty")
-	ErrMissingGQLRequest    = errors.New("missing GraphQL request")
-	ErrPeerIdUnavailable    = errors.New("no peer ID available. P2P might be disabled")
+	ErrDatabaseNotAvailable = errors.WithCode(errors.CodeUnavailable, errors.New("no database available"))
+	ErrFormNotSupported     = errors.WithCode(
+		errors.CodeInvalidRequest,
+		errors.New("content type application/x-www-form-urlencoded not yet supported"),
+	)
+	ErrBodyEmpty         = errors.WithCode(errors.CodeInvalidRequest, errors.New("body cannot be empty"))
+	ErrMissingGQLRequest = errors.WithCode(errors.CodeInvalidRequest, errors.New("missing GraphQL request"))
+	ErrMissingSQLQuery   = errors.WithCode(errors.CodeInvalidRequest, errors.New("missing SQL query"))
+	ErrPeerIdUnavailable = errors.New("no peer ID available. P2P might be disabled")
+	ErrNoBlockFetcher    = errors.WithCode(
+		errors.CodeUnavailable,
+		errors.New("no block fetcher available. P2P might be disabled"),
+	)
 	ErrStreamingUnsupported = errors.New("streaming unsupported")
 	ErrNoEmail              = errors.New("email address must be specified for tls with autocert")
+	ErrTooManyRequests      = errors.WithCode(errors.CodeTooManyRequests, errors.New("too many requests"))
+	ErrAdminDisabled        = errors.WithCode(
+		errors.CodeUnavailable,
+		errors.New("admin endpoints are disabled. An admin token must be configured"),
+	)
+	ErrProfilingDisabled = errors.WithCode(
+		errors.CodeUnavailable,
+		errors.New("profiling endpoints are disabled. Profiling must be enabled in the configuration"),
+	)
+	ErrUnauthorized        = errors.WithCode(errors.CodeUnauthorized, errors.New("invalid or missing admin token"))
+	ErrInvalidRequestID    = errors.WithCode(errors.CodeInvalidRequest, errors.New("invalid request ID"))
+	ErrCollectionNotFound  = errors.WithCode(errors.CodeCollectionNotFound, errors.New("collection not found"))
+	ErrInvalidImportOption = errors.WithCode(errors.CodeInvalidRequest, errors.New(errInvalidImportOption))
+	ErrInvalidImportValue  = errors.WithCode(errors.CodeInvalidRequest, errors.New(errInvalidImportValue))
+	ErrMissingProofRoot    = errors.WithCode(errors.CodeInvalidRequest, errors.New("missing root commit CID"))
+	ErrInvalidPlanDebug    = errors.WithCode(errors.CodeInvalidRequest, errors.New(errInvalidPlanDebug))
+	ErrInvalidSchemaSig    = errors.WithCode(errors.CodeInvalidRequest, errors.New(errInvalidSchemaSig))
+	ErrInvalidFieldKeys    = errors.WithCode(errors.CodeInvalidRequest, errors.New(errInvalidFieldKeys))
+	ErrInvalidExportFormat = errors.WithCode(errors.CodeInvalidRequest, errors.New(errInvalidExportFormat))
+	ErrUnsupportedPatch    = errors.WithCode(errors.CodeInvalidRequest, errors.New(errUnsupportedPatch))
 )
 
+// NewErrInvalidImportOption returns an error indicating that the given import option is invalid.
+func NewErrInvalidImportOption(name string, value string) error {
+	return errors.New(
+		errInvalidImportOption,
+		errors.NewKV("Option", name),
+		errors.NewKV("Value", value),
+	)
+}
+
+// NewErrInvalidImportValue returns an error indicating that the given value can't be parsed
+// into a value of the given field.
+func NewErrInvalidImportValue(field string, value string, inner error) error {
+	return errors.Wrap(
+		errInvalidImportValue,
+		inner,
+		errors.NewKV("Field", field),
+		errors.NewKV("Value", value),
+	)
+}
+
+// NewErrInvalidPlanDebug returns an error indicating that the given value of the plan debug
+// header is not a boolean.
+func NewErrInvalidPlanDebug(value string) error {
+	return errors.New(errInvalidPlanDebug, errors.NewKV("Value", value))
+}
+
+// NewErrInvalidSchemaSig returns an error indicating that the value of the schema signature
+// header is not base64 encoded.
+func NewErrInvalidSchemaSig(inner error) error {
+	return errors.Wrap(errInvalidSchemaSig, inner)
+}
+
+// NewErrInvalidFieldKeys returns an error indicating that the value of the field keys header is
+// not made of `Collection.field=key` pairs with base64 encoded keys.
+func NewErrInvalidFieldKeys(value string) error {
+	return errors.New(errInvalidFieldKeys, errors.NewKV("Value", value))
+}
+
+// NewErrInvalidExportFormat returns an error indicating that the given export format is neither
+// `ndjson` nor `csv`.
+func NewErrInvalidExportFormat(format string) error {
+	return errors.New(errInvalidExportFormat, errors.NewKV("Format", format))
+}
+
+// NewErrUnsupportedPatchType returns an error indicating that the given content type is neither
+// a JSON Patch nor a JSON Merge Patch.
+func NewErrUnsupportedPatchType(contentType string) error {
+	return errors.New(errUnsupportedPatch, errors.NewKV("ContentType", contentType))
+}
+
+// schemaUpdateErrStatus returns the status of the response to a failed schema update.
+func schemaUpdateErrStatus(err error) int {
+	if errors.CodeOf(err) == errors.CodeUnauthorized {
+		return http.StatusForbidden
+	}
+	return http.StatusInternalServerError
+}
+
+// documentErrStatus returns the status of the response to a failed document operation.
+func documentErrStatus(err error) int {
+	switch errors.CodeOf(err) {
+	case errors.CodeDocumentNotFound, errors.CodeDocumentDeleted:
+		return http.StatusNotFound
+	case errors.CodeDocumentExists:
+		return http.StatusConflict
+	case errors.CodeQuotaExceeded:
+		return http.StatusInsufficientStorage
+	}
+	return http.StatusBadRequest
+}
+
 // ErrorResponse is the GQL top level object holding error items for the response payload.
 type ErrorResponse struct {
 	Errors []ErrorItem `json:"errors"`
@@ -50,14 +166,29 @@ type ErrorItem struct {
 }
 
 type extensions struct {
-	Status    int    `json:"status"`
-	HTTPError string `json:"httpError"`
-	Stack     string `json:"stack,omitempty"`
+	Status    int         `json:"status"`
+	HTTPError string      `json:"httpError"`
+	Code      errors.Code `json:"code,omitempty"`
+	TraceID   string      `json:"traceID,omitempty"`
+	Stack     string      `json:"stack,omitempty"`
 }
 
+// handleErr sends the given error to the client with the detail allowed by the error verbosity of
+// the request.
+//
+// Internal server errors are logged in full. The other errors are logged in full at the debug
+// level if their stack is not sent to the client.
 func handleErr(ctx context.Context, rw http.ResponseWriter, err error, status int) {
+	verbosity := errorVerbosity(ctx)
 	if status == http.StatusInternalServerError {
 		log.ErrorE(ctx, http.StatusText(status), err)
+	} else if verbosity != config.ErrorVerbosityStack {
+		log.Debug(
+			ctx,
+			http.StatusText(status),
+			logging.NewKV("Error", err.Error()),
+			logging.NewKV("Stack", fmt.Sprintf("%+v", err)),
+		)
 	}
 
 	sendJSON(
@@ -66,11 +197,13 @@ func handleErr(ctx context.Context, rw http.ResponseWriter, err error, status in
 		ErrorResponse{
 			Errors: []ErrorItem{
 				{
-					Message: err.Error(),
+					Message: errorMessage(verbosity, err),
 					Extensions: extensions{
 						Status:    status,
 						HTTPError: http.StatusText(status),
-						Stack:     formatError(err),
+						Code:      errorCode(verbosity, err),
+						TraceID:   logging.TraceIDFromContext(ctx),
+						Stack:     formatError(verbosity, err),
 					},
 				},
 			},
@@ -79,8 +212,37 @@ func handleErr(ctx context.Context, rw http.ResponseWriter, err error, status in
 	)
 }
 
-func formatError(err error) string {
+// errorVerbosity returns the verbosity of the errors sent in response to the request of the given
+// context: the one of the API configuration, or the stack if DEFRA_ENV is set to dev.
+func errorVerbosity(ctx context.Context) string {
 	if strings.ToLower(env) == "dev" || strings.ToLower(env) == "development" {
+		return config.ErrorVerbosityStack
+	}
+	if verbosity, ok := ctx.Value(ctxErrorVerbosity{}).(string); ok && verbosity != "" {
+		return verbosity
+	}
+	return config.ErrorVerbosityCode
+}
+
+// errorMessage returns the message of the given error, with its file paths redacted unless the
+// stack is sent.
+func errorMessage(verbosity string, err error) string {
+	if verbosity == config.ErrorVerbosityStack {
+		return err.Error()
+	}
+	return pathPattern.ReplaceAllString(err.Error(), "$1<path>")
+}
+
+// errorCode returns the code of the given error, or an empty code if only the message is sent.
+func errorCode(verbosity string, err error) errors.Code {
+	if verbosity == config.ErrorVerbosityMessage {
+		return ""
+	}
+	return errors.CodeOf(err)
+}
+
+func formatError(verbosity string, err error) string {
+	if verbosity == config.ErrorVerbosityStack {
 		return fmt.Sprintf("[DEV] %+v\n", err)
 	}
 	return ""
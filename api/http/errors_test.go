@@ -11,6 +11,7 @@
 package http
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -19,6 +20,8 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/sourcenetwork/defradb/config"
 )
 
 func CleanupEnv() {
@@ -26,17 +29,57 @@ func CleanupEnv() {
 }
 
 func TestFormatError(t *testing.T) {
-	t.Cleanup(CleanupEnv)
-	env = "prod"
-	s := formatError(errors.New("test error"))
+	s := formatError(config.ErrorVerbosityCode, errors.New("test error"))
 	assert.Equal(t, "", s)
 
-	env = "dev"
-	s = formatError(errors.New("test error"))
+	s = formatError(config.ErrorVerbosityStack, errors.New("test error"))
 	lines := strings.Split(s, "\n")
 	assert.Equal(t, "[DEV] test error", lines[0])
 }
 
+func TestErrorVerbosity(t *testing.T) {
+	t.Cleanup(CleanupEnv)
+	ctx := context.WithValue(context.Background(), ctxErrorVerbosity{}, config.ErrorVerbosityMessage)
+
+	env = "prod"
+	assert.Equal(t, config.ErrorVerbosityCode, errorVerbosity(context.Background()))
+	assert.Equal(t, config.ErrorVerbosityMessage, errorVerbosity(ctx))
+
+	env = "dev"
+	assert.Equal(t, config.ErrorVerbosityStack, errorVerbosity(ctx))
+}
+
+func TestErrorMessageRedactsFilePaths(t *testing.T) {
+	err := errors.New(`open /var/lib/defradb/data/000001.log: permission denied, see "C:\\defradb\\logs"`)
+	assert.Equal(
+		t,
+		`open <path>: permission denied, see "<path>"`,
+		errorMessage(config.ErrorVerbosityCode, err),
+	)
+	assert.Equal(t, err.Error(), errorMessage(config.ErrorVerbosityStack, err))
+
+	err = errors.New("field users/name not found")
+	assert.Equal(t, err.Error(), errorMessage(config.ErrorVerbosityMessage, err))
+}
+
+func TestHandleErrWithMessageVerbosity(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.API.ErrorVerbosity = config.ErrorVerbosityMessage
+	errResponse := ErrorResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		Method:         "POST",
+		Path:           GraphQLPath,
+		ExpectedStatus: 400,
+		ResponseData:   &errResponse,
+		ServerOptions:  serverOptions{cfg: cfg},
+	})
+
+	assert.Equal(t, "body cannot be empty", errResponse.Errors[0].Message)
+	assert.Empty(t, errResponse.Errors[0].Extensions.Code)
+	assert.Equal(t, "", errResponse.Errors[0].Extensions.Stack)
+}
+
 func TestHandleErrOnBadRequest(t *testing.T) {
 	t.Cleanup(CleanupEnv)
 	env = "dev"
@@ -0,0 +1,114 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package http
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+
+	"github.com/sourcenetwork/defradb/client"
+)
+
+// exportHandler streams the documents of a collection, without their relations, as newline
+// delimited JSON or, with the `format=csv` query parameter, as CSV with a header row, in the
+// formats accepted by the import.
+//
+// The values of the masked fields are masked according to the masking rules of the database,
+// so that the exports can be shared outside of operations.
+func exportHandler(rw http.ResponseWriter, req *http.Request) {
+	format := req.URL.Query().Get("format")
+	if format != "" && format != "ndjson" && format != "csv" {
+		handleErr(req.Context(), rw, NewErrInvalidExportFormat(format), http.StatusBadRequest)
+		return
+	}
+
+	col, ok := collectionFromRequest(rw, req)
+	if !ok {
+		return
+	}
+
+	db, err := dbFromContext(req.Context())
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+		return
+	}
+	query, fields := documentsRequest(col, nil)
+	result := db.ExecRequest(client.WithMaskedResults(req.Context()), query)
+	if len(result.GQL.Errors) > 0 {
+		handleErr(req.Context(), rw, result.GQL.Errors[0], http.StatusBadRequest)
+		return
+	}
+	docs, _ := result.GQL.Data.([]map[string]any)
+
+	if format == "csv" {
+		rw.Header().Set("Content-Type", contentTypeCSV)
+		rw.WriteHeader(http.StatusOK)
+		err = writeCSVDocuments(rw, fields, docs)
+	} else {
+		rw.Header().Set("Content-Type", contentTypeNDJSON)
+		rw.WriteHeader(http.StatusOK)
+		err = writeNDJSONDocuments(rw, docs)
+	}
+	if err != nil {
+		log.ErrorE(req.Context(), "Failed to write export", err)
+	}
+}
+
+func writeNDJSONDocuments(rw http.ResponseWriter, docs []map[string]any) error {
+	encoder := json.NewEncoder(rw)
+	for _, doc := range docs {
+		if err := encoder.Encode(doc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeCSVDocuments(rw http.ResponseWriter, fields []string, docs []map[string]any) error {
+	writer := csv.NewWriter(rw)
+	if err := writer.Write(fields); err != nil {
+		return err
+	}
+	record := make([]string, len(fields))
+	for _, doc := range docs {
+		for i, field := range fields {
+			cell, err := formatCSVValue(doc[field])
+			if err != nil {
+				return err
+			}
+			record[i] = cell
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// formatCSVValue formats the given value into a cell, as parsed back by the import.
+//
+// Null values are empty, strings are kept as is, and the other values are JSON encoded.
+func formatCSVValue(value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	default:
+		buf, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return string(buf), nil
+	}
+}
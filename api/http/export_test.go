@@ -0,0 +1,84 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/db"
+)
+
+func testExportRequest(t *testing.T, defra client.DB, query string) *httptest.ResponseRecorder {
+	req, err := http.NewRequest("GET", CollectionsPath+"/user/export"+query, nil)
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	newHandler(defra, serverOptions{}).ServeHTTP(rec, req)
+	return rec
+}
+
+func TestExportHandlerMasksFields(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(
+		t,
+		ctx,
+		db.WithMasking(
+			nil,
+			db.MaskingRule{Collection: "user", Field: "name", Method: db.MaskTruncate, Length: 2},
+			db.MaskingRule{Collection: "user", Field: "age", Method: db.MaskRedact},
+		),
+	)
+	defer defra.Close(ctx)
+
+	testLoadSchema(t, ctx, defra)
+	events := testImportRequest(t, defra, "", contentTypeNDJSON, `{"name": "Alice", "age": 22, "verified": true}`)
+	require.Equal(t, importEvent{Imported: 1, Done: true}, events[len(events)-1])
+
+	rec := testExportRequest(t, defra, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, contentTypeNDJSON, rec.Header().Get("Content-Type"))
+	var doc map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &doc))
+	assert.Equal(t, "Al", doc["name"])
+	assert.Nil(t, doc["age"])
+	assert.Equal(t, true, doc["verified"])
+
+	rec = testExportRequest(t, defra, "?format=csv")
+	require.Equal(t, http.StatusOK, rec.Code)
+	lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, "_key,age,name,points,verified", lines[0])
+	assert.True(t, strings.HasSuffix(lines[1], ",,Al,,true"), lines[1])
+
+	// Queries executed outside of exports are not masked.
+	result := defra.ExecRequest(ctx, `query { user { name } }`)
+	require.Empty(t, result.GQL.Errors)
+	assert.Equal(t, []map[string]any{{"name": "Alice"}}, result.GQL.Data)
+}
+
+func TestExportHandlerWithInvalidFormat(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+
+	testLoadSchema(t, ctx, defra)
+	rec := testExportRequest(t, defra, "?format=xml")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
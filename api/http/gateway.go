@@ -0,0 +1,520 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/kinds"
+	gqlp "github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/printer"
+	"github.com/graphql-go/graphql/language/visitor"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/client/request"
+	"github.com/sourcenetwork/defradb/db"
+	"github.com/sourcenetwork/defradb/db/base"
+	"github.com/sourcenetwork/defradb/errors"
+	"github.com/sourcenetwork/defradb/logging"
+	"github.com/sourcenetwork/defradb/request/graphql/parser"
+)
+
+const (
+	// gatewaySchemaTTL is the duration for which the query fields served by a remote node are
+	// cached by the gateway.
+	gatewaySchemaTTL = time.Minute
+
+	// gatewayTimeout is the maximum duration of a request to a remote node.
+	gatewayTimeout = 30 * time.Second
+
+	gatewaySchemaRequest = `query { __schema { queryType { fields { name } } } }`
+)
+
+var _ db.RequestForwarder = (*Gateway)(nil)
+
+// Gateway delegates the queries for the collections a node does not hold to remote nodes over
+// their HTTP API, so that the node serves the combined collections of a fleet of nodes.
+//
+// A query is sent to all the remote nodes serving the queried collection, and the documents they
+// return are concatenated in the order of the remote nodes. The order, limit and offset of a query
+// served by several remote nodes are applied to the concatenated documents: each remote node is
+// asked for its first limit plus offset documents, and the ordered fields must be selected.
+type Gateway struct {
+	remotes []gatewayRemote
+	client  *http.Client
+}
+
+type gatewayRemote struct {
+	url string
+
+	mu        sync.Mutex
+	fields    map[string]struct{}
+	fetchedAt time.Time
+}
+
+// NewGateway creates a new gateway to the remote nodes of the given HTTP API URLs.
+func NewGateway(remotes []string) (*Gateway, error) {
+	g := &Gateway{
+		remotes: make([]gatewayRemote, len(remotes)),
+		client:  &http.Client{Timeout: gatewayTimeout},
+	}
+	for i, remote := range remotes {
+		endpoint, err := JoinPaths(remote, GraphQLPath)
+		if err != nil {
+			return nil, errors.Wrap("invalid gateway remote URL", err, errors.NewKV("URL", remote))
+		}
+		g.remotes[i].url = endpoint.String()
+	}
+	return g, nil
+}
+
+// ForwardRequest executes the given query, with the variables of the given context, on the
+// remote nodes serving the collections it queries, and merges their results.
+func (g *Gateway) ForwardRequest(ctx context.Context, request string) *client.RequestResult {
+	res := &client.RequestResult{}
+	doc, err := gqlp.Parse(gqlp.ParseParams{Source: request})
+	if err != nil {
+		res.GQL.Errors = []error{err}
+		return res
+	}
+	fields, err := queryFields(doc)
+	if err != nil {
+		res.GQL.Errors = []error{err}
+		return res
+	}
+
+	var remotes []*gatewayRemote
+	for i := range g.remotes {
+		if g.serves(ctx, &g.remotes[i], fields) {
+			remotes = append(remotes, &g.remotes[i])
+		}
+	}
+	if len(remotes) == 0 {
+		res.GQL.Errors = []error{
+			errors.New("no gateway remote serves the queried fields", errors.NewKV("Fields", fields)),
+		}
+		return res
+	}
+
+	variables := client.RequestVariablesFromContext(ctx)
+	var page gatewayPage
+	if len(remotes) > 1 {
+		page, err = paginate(doc, variables)
+		if err != nil {
+			res.GQL.Errors = []error{err}
+			return res
+		}
+		if page.paginated() {
+			// The remotes are asked for the pages holding the merged page.
+			request = printer.Print(doc).(string)
+		}
+	}
+
+	results := make([]gatewayResult, len(remotes))
+	var wg sync.WaitGroup
+	for i, remote := range remotes {
+		wg.Add(1)
+		go func(i int, remote *gatewayRemote) {
+			defer wg.Done()
+			results[i] = g.exec(ctx, remote.url, request, variables)
+		}(i, remote)
+	}
+	wg.Wait()
+
+	docs := []map[string]any{}
+	for i, result := range results {
+		for _, err := range result.errors {
+			res.GQL.Errors = append(res.GQL.Errors, errors.Wrap(
+				"gateway remote request failed",
+				err,
+				errors.NewKV("URL", remotes[i].url),
+			))
+		}
+		if len(result.data) == 0 {
+			continue
+		}
+		var data []map[string]any
+		if err := json.Unmarshal(result.data, &data); err != nil {
+			res.GQL.Errors = append(res.GQL.Errors, errors.Wrap(
+				"failed to decode gateway remote data",
+				err,
+				errors.NewKV("URL", remotes[i].url),
+			))
+			continue
+		}
+		docs = append(docs, data...)
+	}
+	res.GQL.Data = page.apply(docs)
+	return res
+}
+
+// gatewayPage is the order, limit and offset of a query, applied to the documents of all the
+// remote nodes.
+type gatewayPage struct {
+	// orders are the orderings of the documents, by the path of the ordered field in them.
+	orders []gatewayOrder
+	// limit is the maximum number of documents, or zero if unlimited.
+	limit int
+	// offset is the number of documents skipped.
+	offset int
+}
+
+type gatewayOrder struct {
+	path []string
+	desc bool
+}
+
+// paginated returns true if the page orders or limits the documents.
+func (p gatewayPage) paginated() bool {
+	return len(p.orders) > 0 || p.limit > 0 || p.offset > 0
+}
+
+// apply returns the page of the given documents of the remote nodes.
+//
+// The documents that are equal by all the orderings keep the order of the remote nodes.
+func (p gatewayPage) apply(docs []map[string]any) []map[string]any {
+	if len(p.orders) > 0 {
+		sort.SliceStable(docs, func(i, j int) bool {
+			for _, order := range p.orders {
+				compare := base.Compare(docPathValue(docs[i], order.path), docPathValue(docs[j], order.path))
+				if compare != 0 {
+					return compare < 0 != order.desc
+				}
+			}
+			return false
+		})
+	}
+	if p.offset >= len(docs) {
+		return []map[string]any{}
+	}
+	docs = docs[p.offset:]
+	if p.limit > 0 && p.limit < len(docs) {
+		docs = docs[:p.limit]
+	}
+	return docs
+}
+
+// docPathValue returns the value at the given path of the given document, or nil if there is
+// none.
+func docPathValue(doc map[string]any, path []string) any {
+	var value any = doc
+	for _, key := range path {
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = obj[key]
+	}
+	return value
+}
+
+// paginate returns the page of the given query to apply to the documents of the remote nodes,
+// and rewrites the query so that each remote node returns the documents of its own that the page
+// may hold: its first limit plus offset documents.
+//
+// The values of the variables of the order, limit and offset arguments are taken from the given
+// variables.
+func paginate(doc *ast.Document, variables map[string]any) (gatewayPage, error) {
+	var page gatewayPage
+	var opDef *ast.OperationDefinition
+	var field *ast.Field
+	for _, def := range doc.Definitions {
+		d, isOpDef := def.(*ast.OperationDefinition)
+		if !isOpDef {
+			continue
+		}
+		for _, selection := range d.SelectionSet.Selections {
+			f, isField := selection.(*ast.Field)
+			if !isField || !hasPageArguments(f) {
+				continue
+			}
+			if field != nil || len(d.SelectionSet.Selections) > 1 {
+				return page, errors.New("the order, limit and offset of a forwarded query must be given to its only field")
+			}
+			opDef, field = d, f
+		}
+	}
+	if field == nil {
+		return page, nil
+	}
+
+	values := make(map[string]any, len(variables))
+	for name, value := range variables {
+		values[name] = value
+	}
+	for _, varDef := range opDef.VariableDefinitions {
+		if _, ok := values[varDef.Variable.Name.Value]; !ok && varDef.DefaultValue != nil {
+			values[varDef.Variable.Name.Value] = varDef.DefaultValue
+		}
+	}
+
+	collated := false
+	forwarded := make([]*ast.Argument, 0, len(field.Arguments))
+	for _, argument := range field.Arguments {
+		inlined := parser.InlineVariables([]*ast.Argument{argument}, values)
+		if len(inlined) == 0 {
+			forwarded = append(forwarded, argument)
+			continue
+		}
+		switch argument.Name.Value {
+		case request.LimitClause, request.OffsetClause:
+			value, ok := inlined[0].Value.(*ast.IntValue)
+			if !ok {
+				return page, errors.New("invalid forwarded query page", errors.NewKV("Argument", argument.Name.Value))
+			}
+			n, err := strconv.Atoi(value.Value)
+			if err != nil {
+				return page, err
+			}
+			if argument.Name.Value == request.OffsetClause {
+				// The documents are skipped from the merged documents.
+				page.offset = n
+				continue
+			}
+			page.limit = n
+
+		case request.OrderClause:
+			conditions, err := parser.ParseOrderBy(inlined[0].Value)
+			if err != nil {
+				return page, err
+			}
+			for _, condition := range conditions {
+				path, ok := responsePath(field.SelectionSet, condition.Fields)
+				if !ok {
+					return page, errors.New(
+						"the ordered fields of a forwarded query must be selected",
+						errors.NewKV("Field", strings.Join(condition.Fields, ".")),
+					)
+				}
+				page.orders = append(page.orders, gatewayOrder{path: path, desc: condition.Direction == request.DESC})
+			}
+
+		case request.CollationClause:
+			collated = true
+		}
+		forwarded = append(forwarded, argument)
+	}
+	if collated && len(page.orders) > 0 {
+		return page, errors.New("the collation of a forwarded query can't be applied")
+	}
+	for i, argument := range forwarded {
+		if argument.Name.Value == request.LimitClause && page.limit > 0 {
+			limit := *argument
+			limit.Value = ast.NewIntValue(&ast.IntValue{Value: strconv.Itoa(page.limit + page.offset)})
+			forwarded[i] = &limit
+		}
+	}
+
+	field.Arguments = forwarded
+	opDef.VariableDefinitions = usedVariableDefinitions(opDef)
+	return page, nil
+}
+
+// hasPageArguments returns true if the given field is given an order, limit or offset.
+func hasPageArguments(field *ast.Field) bool {
+	for _, argument := range field.Arguments {
+		switch argument.Name.Value {
+		case request.OrderClause, request.LimitClause, request.OffsetClause:
+			return true
+		}
+	}
+	return false
+}
+
+// responsePath returns the keys of the documents of the given selections holding the value of
+// the field of the given path, or false if it isn't selected.
+func responsePath(selectionSet *ast.SelectionSet, fields []string) ([]string, bool) {
+	path := make([]string, 0, len(fields))
+	for _, name := range fields {
+		if selectionSet == nil {
+			return nil, false
+		}
+		var selected *ast.Field
+		for _, selection := range selectionSet.Selections {
+			if field, ok := selection.(*ast.Field); ok && field.Name.Value == name {
+				selected = field
+				break
+			}
+		}
+		if selected == nil {
+			return nil, false
+		}
+		key := selected.Name.Value
+		if selected.Alias != nil {
+			key = selected.Alias.Value
+		}
+		path = append(path, key)
+		selectionSet = selected.SelectionSet
+	}
+	return path, true
+}
+
+// usedVariableDefinitions returns the definitions of the variables the given operation still
+// uses, as the remote nodes reject the operations defining unused variables.
+func usedVariableDefinitions(opDef *ast.OperationDefinition) []*ast.VariableDefinition {
+	used := map[string]struct{}{}
+	visitor.Visit(opDef.SelectionSet, &visitor.VisitorOptions{
+		KindFuncMap: map[string]visitor.NamedVisitFuncs{
+			kinds.Variable: {
+				Kind: func(p visitor.VisitFuncParams) (string, any) {
+					if variable, ok := p.Node.(*ast.Variable); ok && variable.Name != nil {
+						used[variable.Name.Value] = struct{}{}
+					}
+					return visitor.ActionNoChange, nil
+				},
+			},
+		},
+	}, nil)
+
+	defs := make([]*ast.VariableDefinition, 0, len(opDef.VariableDefinitions))
+	for _, varDef := range opDef.VariableDefinitions {
+		if _, ok := used[varDef.Variable.Name.Value]; ok {
+			defs = append(defs, varDef)
+		}
+	}
+	return defs
+}
+
+// serves returns true if the given remote serves all the given query fields.
+//
+// The query fields of the remote are cached, and the stale cache is used if they can't be
+// fetched.
+func (g *Gateway) serves(ctx context.Context, remote *gatewayRemote, fields []string) bool {
+	remote.mu.Lock()
+	defer remote.mu.Unlock()
+
+	if time.Since(remote.fetchedAt) > gatewaySchemaTTL {
+		remoteFields, err := g.fetchQueryFields(ctx, remote.url)
+		if err != nil {
+			log.ErrorE(ctx, "Failed to fetch the schema of a gateway remote", err, logging.NewKV("URL", remote.url))
+		} else {
+			remote.fields = remoteFields
+			remote.fetchedAt = time.Now()
+		}
+	}
+
+	for _, field := range fields {
+		if _, ok := remote.fields[field]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// fetchQueryFields returns the names of the query fields served by the remote of the given URL.
+func (g *Gateway) fetchQueryFields(ctx context.Context, url string) (map[string]struct{}, error) {
+	result := g.exec(ctx, url, gatewaySchemaRequest, nil)
+	if len(result.errors) > 0 {
+		return nil, result.errors[0]
+	}
+
+	var data struct {
+		Schema struct {
+			QueryType struct {
+				Fields []struct {
+					Name string `json:"name"`
+				} `json:"fields"`
+			} `json:"queryType"`
+		} `json:"__schema"`
+	}
+	if err := json.Unmarshal(result.data, &data); err != nil {
+		return nil, errors.Wrap("failed to decode the schema of the gateway remote", err)
+	}
+
+	fields := make(map[string]struct{}, len(data.Schema.QueryType.Fields))
+	for _, field := range data.Schema.QueryType.Fields {
+		fields[field.Name] = struct{}{}
+	}
+	return fields, nil
+}
+
+type gatewayResult struct {
+	data   json.RawMessage
+	errors []error
+}
+
+// exec executes the given request on the remote of the given GraphQL endpoint URL.
+func (g *Gateway) exec(
+	ctx context.Context,
+	url string,
+	request string,
+	variables map[string]any,
+) gatewayResult {
+	body, err := json.Marshal(gqlRequest{Request: request, Variables: variables})
+	if err != nil {
+		return gatewayResult{errors: []error{err}}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(body))
+	if err != nil {
+		return gatewayResult{errors: []error{err}}
+	}
+	req.Header.Set("Content-Type", contentTypeJSON)
+
+	res, err := g.client.Do(req)
+	if err != nil {
+		return gatewayResult{errors: []error{err}}
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			log.ErrorE(ctx, "Failed to close the response of a gateway remote", err)
+		}
+	}()
+
+	var gqlRes struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []ErrorItem     `json:"errors"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&gqlRes); err != nil {
+		return gatewayResult{errors: []error{errors.Wrap("failed to decode the gateway remote response", err)}}
+	}
+
+	result := gatewayResult{data: gqlRes.Data}
+	for _, item := range gqlRes.Errors {
+		result.errors = append(result.errors, errors.New(item.Message))
+	}
+	if res.StatusCode != http.StatusOK && len(result.errors) == 0 {
+		result.errors = []error{
+			errors.New("gateway remote request failed", errors.NewKV("Status", res.StatusCode)),
+		}
+	}
+	return result
+}
+
+// queryFields returns the names of the top level fields of the given request, which must be made
+// of queries only.
+func queryFields(doc *ast.Document) ([]string, error) {
+	var fields []string
+	for _, def := range doc.Definitions {
+		opDef, isOpDef := def.(*ast.OperationDefinition)
+		if !isOpDef {
+			continue
+		}
+		if opDef.Operation != ast.OperationTypeQuery {
+			return nil, errors.New("only queries can be forwarded", errors.NewKV("Operation", opDef.Operation))
+		}
+		for _, selection := range opDef.SelectionSet.Selections {
+			field, isField := selection.(*ast.Field)
+			if isField && !strings.HasPrefix(field.Name.Value, "__") {
+				fields = append(fields, field.Name.Value)
+			}
+		}
+	}
+	return fields, nil
+}
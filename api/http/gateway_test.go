@@ -0,0 +1,158 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package http
+
+import (
+	"context"
+	"net/http/httptest"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/db"
+)
+
+// testNewGatewayRemote returns the URL of a remote node holding the given schema and documents of
+// the user collection.
+func testNewGatewayRemote(t *testing.T, ctx context.Context, schema string, users ...string) string {
+	defra := testNewInMemoryDB(t, ctx)
+	t.Cleanup(func() { defra.Close(ctx) })
+	require.NoError(t, defra.AddSchema(ctx, schema))
+	if len(users) > 0 {
+		testCreateUsers(t, ctx, defra, users...)
+	}
+
+	server := httptest.NewServer(newHandler(defra, serverOptions{}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func TestGatewayMergesRemoteResults(t *testing.T) {
+	ctx := context.Background()
+	userSchema := `type user { name: String age: Int }`
+	remotes := []string{
+		testNewGatewayRemote(t, ctx, userSchema, `{"name": "Bob", "age": 31}`, `{"name": "Alice", "age": 35}`),
+		testNewGatewayRemote(t, ctx, `type book { name: String }`),
+		testNewGatewayRemote(t, ctx, userSchema, `{"name": "John", "age": 21}`),
+	}
+
+	gateway, err := NewGateway(remotes)
+	require.NoError(t, err)
+	defra := testNewInMemoryDB(t, ctx, db.WithRequestForwarder(gateway))
+	defer defra.Close(ctx)
+
+	result := defra.ExecRequest(ctx, `query { user(filter: {age: {_gt: 25}}) { name } }`)
+	require.Empty(t, result.GQL.Errors)
+
+	docs, ok := result.GQL.Data.([]map[string]any)
+	require.True(t, ok)
+	var names []string
+	for _, doc := range docs {
+		names = append(names, doc["name"].(string))
+	}
+	sort.Strings(names)
+	assert.Equal(t, []string{"Alice", "Bob"}, names)
+}
+
+func TestGatewayAppliesLimitToMergedResults(t *testing.T) {
+	ctx := context.Background()
+	userSchema := `type user { name: String age: Int }`
+	remotes := []string{
+		testNewGatewayRemote(t, ctx, userSchema, `{"name": "Bob", "age": 31}`, `{"name": "Alice", "age": 35}`),
+		testNewGatewayRemote(t, ctx, userSchema, `{"name": "John", "age": 21}`, `{"name": "Fred", "age": 40}`),
+	}
+
+	gateway, err := NewGateway(remotes)
+	require.NoError(t, err)
+	defra := testNewInMemoryDB(t, ctx, db.WithRequestForwarder(gateway))
+	defer defra.Close(ctx)
+
+	result := defra.ExecRequest(ctx, `query { user(limit: 3) { name } }`)
+	require.Empty(t, result.GQL.Errors)
+	assert.Len(t, result.GQL.Data, 3)
+
+	result = defra.ExecRequest(ctx, `query { user(order: {age: DESC}, limit: 2) { name age } }`)
+	require.Empty(t, result.GQL.Errors)
+	assert.Equal(t, []map[string]any{
+		{"name": "Fred", "age": float64(40)},
+		{"name": "Alice", "age": float64(35)},
+	}, result.GQL.Data)
+
+	ctx = client.WithRequestVariables(ctx, map[string]any{"limit": 2, "offset": 1})
+	result = defra.ExecRequest(
+		ctx,
+		`query($limit: Int, $offset: Int) { user(order: {age: ASC}, limit: $limit, offset: $offset) { name age } }`,
+	)
+	require.Empty(t, result.GQL.Errors)
+	assert.Equal(t, []map[string]any{
+		{"name": "Bob", "age": float64(31)},
+		{"name": "Alice", "age": float64(35)},
+	}, result.GQL.Data)
+}
+
+func TestGatewayRejectsOrderByUnselectedField(t *testing.T) {
+	ctx := context.Background()
+	userSchema := `type user { name: String age: Int }`
+	remotes := []string{
+		testNewGatewayRemote(t, ctx, userSchema, `{"name": "Bob", "age": 31}`),
+		testNewGatewayRemote(t, ctx, userSchema, `{"name": "John", "age": 21}`),
+	}
+
+	gateway, err := NewGateway(remotes)
+	require.NoError(t, err)
+	defra := testNewInMemoryDB(t, ctx, db.WithRequestForwarder(gateway))
+	defer defra.Close(ctx)
+
+	result := defra.ExecRequest(ctx, `query { user(order: {age: DESC}) { name } }`)
+	require.Len(t, result.GQL.Errors, 1)
+	assert.Contains(t, result.GQL.Errors[0].Error(), "the ordered fields of a forwarded query must be selected")
+}
+
+func TestGatewayForwardsRequestVariables(t *testing.T) {
+	ctx := context.Background()
+	remote := testNewGatewayRemote(
+		t, ctx,
+		`type user { name: String age: Int }`,
+		`{"name": "Bob", "age": 31}`,
+	)
+
+	gateway, err := NewGateway([]string{remote})
+	require.NoError(t, err)
+	defra := testNewInMemoryDB(t, ctx, db.WithRequestForwarder(gateway))
+	defer defra.Close(ctx)
+
+	ctx = client.WithRequestVariables(ctx, map[string]any{"skipAge": true})
+	result := defra.ExecRequest(ctx, `query($skipAge: Boolean!) { user { name age @skip(if: $skipAge) } }`)
+	require.Empty(t, result.GQL.Errors)
+	assert.Equal(t, []map[string]any{{"name": "Bob"}}, result.GQL.Data)
+}
+
+func TestGatewayWithoutServingRemote(t *testing.T) {
+	ctx := context.Background()
+	remote := testNewGatewayRemote(t, ctx, `type book { name: String }`)
+
+	gateway, err := NewGateway([]string{remote})
+	require.NoError(t, err)
+	defra := testNewInMemoryDB(t, ctx, db.WithRequestForwarder(gateway))
+	defer defra.Close(ctx)
+
+	result := defra.ExecRequest(ctx, `query { user { name } }`)
+	require.Len(t, result.GQL.Errors, 1)
+	assert.Contains(t, result.GQL.Errors[0].Error(), "no gateway remote serves the queried fields")
+}
+
+func TestNewGatewayWithInvalidRemote(t *testing.T) {
+	_, err := NewGateway([]string{"node1:9181"})
+	assert.ErrorIs(t, err, ErrSchema)
+}
@@ -12,15 +12,21 @@ package http
 
 import (
 	"context"
+	"crypto/subtle"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
+	"sync/atomic"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/cors"
 	"github.com/pkg/errors"
 
 	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/logging"
 )
 
 type handler struct {
@@ -29,12 +35,26 @@ type handler struct {
 
 	// user configurable options
 	options serverOptions
+
+	// closed when the server is shutting down.
+	draining <-chan struct{}
+
+	// CORS handling, replaced whenever the allowed origins change.
+	cors atomic.Pointer[cors.Cors]
+	// limits the number of requests served per second.
+	limiter *rateLimiter
 }
 
+// AdminIdentity is the identity of the requests authenticated by the admin token.
+const AdminIdentity = "admin"
+
 // context variables
 type (
-	ctxDB     struct{}
-	ctxPeerID struct{}
+	ctxDB       struct{}
+	ctxPeerID   struct{}
+	ctxDraining struct{}
+	// ctxErrorVerbosity holds the error verbosity of the API configuration.
+	ctxErrorVerbosity struct{}
 )
 
 // DataResponse is the GQL top level object holding data for the response payload.
@@ -68,12 +88,134 @@ func simpleDataResponse(args ...any) DataResponse {
 
 // newHandler returns a handler with the router instantiated.
 func newHandler(db client.DB, opts serverOptions) *handler {
-	return setRoutes(&handler{
+	h := &handler{
 		db:      db,
 		options: opts,
+		limiter: &rateLimiter{},
+	}
+	h.applyConfig()
+	return setRoutes(h)
+}
+
+// applyConfig applies the runtime configurable options (CORS and rate limits).
+//
+// If the handler has a node configuration, the values are read from it on top of the
+// options the handler was created with.
+func (h *handler) applyConfig() {
+	origins := h.options.allowedOrigins
+	rateLimit := 0
+	if h.options.cfg != nil {
+		origins = append(append([]string{}, origins...), h.options.cfg.API.AllowedOriginsList()...)
+		rateLimit = h.options.cfg.API.RateLimit
+	}
+
+	if len(origins) == 0 {
+		h.cors.Store(nil)
+	} else {
+		h.cors.Store(cors.New(cors.Options{
+			AllowedOrigins: origins,
+			AllowedMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+			AllowedHeaders: []string{"Content-Type", "Authorization"},
+			MaxAge:         300,
+		}))
+	}
+	h.limiter.setLimit(rateLimit)
+}
+
+// corsMiddleware handles CORS with the currently allowed origins.
+func (h *handler) corsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		c := h.cors.Load()
+		if c == nil {
+			next.ServeHTTP(rw, req)
+			return
+		}
+		c.Handler(next).ServeHTTP(rw, req)
+	})
+}
+
+// errorVerbosityMiddleware tags the context of the request with the error verbosity of the API
+// configuration, read on each request as it can be changed while the node is running.
+func (h *handler) errorVerbosityMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if h.options.cfg == nil {
+			next.ServeHTTP(rw, req)
+			return
+		}
+		ctx := context.WithValue(req.Context(), ctxErrorVerbosity{}, h.options.cfg.API.ErrorVerbosity)
+		next.ServeHTTP(rw, req.WithContext(ctx))
 	})
 }
 
+// requireAdmin only calls f if the request carries the configured admin token.
+func (h *handler) requireAdmin(f http.HandlerFunc) http.HandlerFunc {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		if h.options.cfg == nil || h.options.cfg.API.AdminToken == "" {
+			handleErr(req.Context(), rw, ErrAdminDisabled, http.StatusForbidden)
+			return
+		}
+		if !h.hasAdminToken(req) {
+			handleErr(req.Context(), rw, ErrUnauthorized, http.StatusUnauthorized)
+			return
+		}
+		f(rw, req)
+	}
+}
+
+// hasAdminToken returns true if the admin endpoints are enabled and the request carries the
+// configured admin token.
+func (h *handler) hasAdminToken(req *http.Request) bool {
+	if h.options.cfg == nil || h.options.cfg.API.AdminToken == "" {
+		return false
+	}
+	token := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+	return subtle.ConstantTimeCompare([]byte(token), []byte(h.options.cfg.API.AdminToken)) == 1
+}
+
+// requestContext returns the context the database requests of the given HTTP request are executed
+// with.
+//
+// The request is authenticated as the [AdminIdentity] if it carries the admin token, and its
+// metadata holds the values of the headers listed in the API configuration.
+func (h *handler) requestContext(req *http.Request) client.RequestContext {
+	requestContext := client.RequestContext{TraceID: logging.TraceIDFromContext(req.Context())}
+	if h.options.cfg == nil {
+		return requestContext
+	}
+	if h.hasAdminToken(req) {
+		requestContext.Identity = AdminIdentity
+	}
+	for _, name := range h.options.cfg.API.MetadataHeadersList() {
+		value := req.Header.Get(name)
+		if value == "" {
+			continue
+		}
+		if requestContext.Metadata == nil {
+			requestContext.Metadata = map[string]string{}
+		}
+		requestContext.Metadata[name] = value
+	}
+	return requestContext
+}
+
+// parseFieldKeys returns the keys of the encrypted fields held by the given value of the field
+// keys header, by field name.
+func parseFieldKeys(value string) (map[string][]byte, error) {
+	keys := map[string][]byte{}
+	for _, pair := range strings.Split(value, ",") {
+		name, encoded, found := strings.Cut(strings.TrimSpace(pair), "=")
+		if !found || !strings.Contains(name, ".") {
+			return nil, NewErrInvalidFieldKeys(value)
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, NewErrInvalidFieldKeys(value)
+		}
+		keys[name] = key
+	}
+	return keys, nil
+}
+
 func (h *handler) handle(f http.HandlerFunc) http.HandlerFunc {
 	return func(rw http.ResponseWriter, req *http.Request) {
 		if h.options.tls.HasValue() {
@@ -83,6 +225,18 @@ func (h *handler) handle(f http.HandlerFunc) http.HandlerFunc {
 		if h.options.peerID != "" {
 			ctx = context.WithValue(ctx, ctxPeerID{}, h.options.peerID)
 		}
+		if h.draining != nil {
+			ctx = context.WithValue(ctx, ctxDraining{}, h.draining)
+		}
+		ctx = client.WithRequestContext(ctx, h.requestContext(req))
+		if v := req.Header.Get(FieldKeysHeader); v != "" {
+			keys, err := parseFieldKeys(v)
+			if err != nil {
+				handleErr(ctx, rw, err, http.StatusBadRequest)
+				return
+			}
+			ctx = client.WithFieldKeys(ctx, keys)
+		}
 		f(rw, req.WithContext(ctx))
 	}
 }
@@ -24,6 +24,8 @@ import (
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/config"
 	badgerds "github.com/sourcenetwork/defradb/datastore/badger/v3"
 	"github.com/sourcenetwork/defradb/db"
 	"github.com/sourcenetwork/defradb/logging"
@@ -215,6 +217,41 @@ func TestDbFromContext(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestRequestContextWithAdminTokenAndMetadataHeaders(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.API.AdminToken = "secret"
+	cfg.API.MetadataHeaders = "X-Request-ID, X-Tenant-ID"
+	h := newHandler(nil, serverOptions{cfg: cfg})
+
+	req, err := http.NewRequest("POST", GraphQLPath, nil)
+	assert.NoError(t, err)
+	req.Header.Set("X-Request-ID", "42")
+	req.Header.Set("X-Other", "ignored")
+	assert.Equal(t, client.RequestContext{Metadata: map[string]string{"X-Request-ID": "42"}}, h.requestContext(req))
+
+	req.Header.Set("Authorization", "Bearer secret")
+	assert.Equal(
+		t,
+		client.RequestContext{Identity: AdminIdentity, Metadata: map[string]string{"X-Request-ID": "42"}},
+		h.requestContext(req),
+	)
+
+	req.Header.Set("Authorization", "Bearer wrong")
+	assert.Empty(t, h.requestContext(req).Identity)
+}
+
+func TestParseFieldKeys(t *testing.T) {
+	keys, err := parseFieldKeys("Users.ssn=AQID, Users.email=BAUG")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"Users.ssn": {1, 2, 3}, "Users.email": {4, 5, 6}}, keys)
+
+	_, err = parseFieldKeys("Users=AQID")
+	assert.ErrorIs(t, err, ErrInvalidFieldKeys)
+
+	_, err = parseFieldKeys("Users.ssn=not base64")
+	assert.ErrorIs(t, err, ErrInvalidFieldKeys)
+}
+
 func TestCORSRequest(t *testing.T) {
 	cases := []struct {
 		name       string
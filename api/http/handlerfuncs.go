@@ -11,11 +11,15 @@
 package http
 
 import (
+	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
 	"mime"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	dshelp "github.com/ipfs/boxo/datastore/dshelp"
@@ -25,6 +29,7 @@ import (
 	"github.com/multiformats/go-multihash"
 	"github.com/pkg/errors"
 
+	"github.com/sourcenetwork/defradb/client"
 	corecrdt "github.com/sourcenetwork/defradb/core/crdt"
 	"github.com/sourcenetwork/defradb/events"
 )
@@ -33,6 +38,28 @@ const (
 	contentTypeJSON           = "application/json"
 	contentTypeGraphQL        = "application/graphql"
 	contentTypeFormURLEncoded = "application/x-www-form-urlencoded"
+	contentTypeNDJSON         = "application/x-ndjson"
+
+	// IsolationLevelHeader is the header selecting the isolation level of a GraphQL request,
+	// either `snapshot`, the default, or `read-committed`.
+	IsolationLevelHeader = "X-Isolation-Level"
+
+	// PlanDebugHeader is the header enabling the logging of the construction of the plan of a
+	// GraphQL request, when set to `true`.
+	PlanDebugHeader = "X-Debug-Plan"
+
+	// SchemaSignatureHeader is the header holding the base64 encoded signature of a schema
+	// addition or patch, required if the node has a schema admin identity.
+	SchemaSignatureHeader = "X-Schema-Signature"
+
+	// FieldKeysHeader is the header holding the keys of the encrypted fields the request may read
+	// and write, as comma separated `Collection.field=key` pairs, the keys being base64 encoded.
+	FieldKeysHeader = "X-Field-Keys"
+
+	// TraceIDHeader is the header holding the trace ID of a request, identifying it in the logs
+	// and the error responses. A trace ID is generated if the request has none, and returned in
+	// the same header of the response.
+	TraceIDHeader = "X-Trace-ID"
 )
 
 func rootHandler(rw http.ResponseWriter, req *http.Request) {
@@ -77,12 +104,81 @@ func dumpHandler(rw http.ResponseWriter, req *http.Request) {
 	)
 }
 
+type dumpItem struct {
+	Key   string `json:"key,omitempty"`
+	Size  int    `json:"size"`
+	Value any    `json:"value,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// dumpDataHandler streams the entries of the rootstore as newline delimited JSON.
+//
+// The `keyspace` and `prefix` query parameters restrict the entries that are dumped, and the
+// `decode` query parameter sets whether their values are decoded. An error occurring after the
+// stream has started is sent as a final line with an `error` field.
+//
+// It requires the admin token, as it exposes the raw entries of every keyspace.
+func dumpDataHandler(rw http.ResponseWriter, req *http.Request) {
+	opts := client.DumpOptions{
+		Keyspace: req.URL.Query().Get("keyspace"),
+		Prefix:   req.URL.Query().Get("prefix"),
+	}
+	if v := req.URL.Query().Get("decode"); v != "" {
+		var err error
+		opts.Decode, err = strconv.ParseBool(v)
+		if err != nil {
+			handleErr(req.Context(), rw, errors.Wrap(err, "invalid decode parameter"), http.StatusBadRequest)
+			return
+		}
+	}
+
+	flusher, ok := rw.(http.Flusher)
+	if !ok {
+		handleErr(req.Context(), rw, ErrStreamingUnsupported, http.StatusInternalServerError)
+		return
+	}
+
+	db, err := dbFromContext(req.Context())
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	entries, err := db.Dump(req.Context(), opts)
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusBadRequest)
+		return
+	}
+
+	rw.Header().Set("Content-Type", contentTypeNDJSON)
+	rw.WriteHeader(http.StatusOK)
+
+	encoder := json.NewEncoder(rw)
+	for entry := range entries {
+		item := dumpItem{
+			Key:   entry.Key,
+			Size:  entry.Size,
+			Value: entry.Value,
+		}
+		if entry.Err != nil {
+			item = dumpItem{Error: entry.Err.Error()}
+		}
+		if err := encoder.Encode(item); err != nil {
+			log.ErrorE(req.Context(), "Failed to write dump entry", err)
+			return
+		}
+		flusher.Flush()
+	}
+}
+
 type gqlRequest struct {
-	Request string `json:"query"`
+	Request   string         `json:"query"`
+	Variables map[string]any `json:"variables"`
 }
 
 func execGQLHandler(rw http.ResponseWriter, req *http.Request) {
 	request := req.URL.Query().Get("query")
+	var variables map[string]any
 	if request == "" {
 		// extract the media type from the content-type header
 		contentType, _, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
@@ -106,6 +202,7 @@ func execGQLHandler(rw http.ResponseWriter, req *http.Request) {
 			}
 
 			request = gqlReq.Request
+			variables = gqlReq.Variables
 
 		case contentTypeFormURLEncoded:
 			handleErr(
@@ -139,19 +236,42 @@ func execGQLHandler(rw http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	ctx := req.Context()
+	if v := req.Header.Get(IsolationLevelHeader); v != "" {
+		level, err := client.ParseIsolationLevel(v)
+		if err != nil {
+			handleErr(req.Context(), rw, err, http.StatusBadRequest)
+			return
+		}
+		ctx = client.WithIsolationLevel(ctx, level)
+	}
+	if v := req.Header.Get(PlanDebugHeader); v != "" {
+		enabled, err := strconv.ParseBool(v)
+		if err != nil {
+			handleErr(req.Context(), rw, NewErrInvalidPlanDebug(v), http.StatusBadRequest)
+			return
+		}
+		if enabled {
+			ctx = client.WithPlanDebug(ctx)
+		}
+	}
+	if variables != nil {
+		ctx = client.WithRequestVariables(ctx, variables)
+	}
+
 	db, err := dbFromContext(req.Context())
 	if err != nil {
 		handleErr(req.Context(), rw, err, http.StatusInternalServerError)
 		return
 	}
-	result := db.ExecRequest(req.Context(), request)
+	result := db.ExecRequest(ctx, request)
 
 	if result.Pub != nil {
 		subscriptionHandler(result.Pub, rw, req)
 		return
 	}
 
-	sendJSON(req.Context(), rw, newGQLResult(result.GQL), http.StatusOK)
+	sendJSON(req.Context(), rw, newGQLResult(ctx, result.GQL), http.StatusOK)
 }
 
 func loadSchemaHandler(rw http.ResponseWriter, req *http.Request) {
@@ -167,9 +287,15 @@ func loadSchemaHandler(rw http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	err = db.AddSchema(req.Context(), string(sdl))
+	ctx, err := schemaSignatureContext(req)
 	if err != nil {
-		handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+		handleErr(req.Context(), rw, err, http.StatusBadRequest)
+		return
+	}
+
+	err = db.AddSchema(ctx, string(sdl))
+	if err != nil {
+		handleErr(req.Context(), rw, err, schemaUpdateErrStatus(err))
 		return
 	}
 
@@ -194,9 +320,15 @@ func patchSchemaHandler(rw http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	err = db.PatchSchema(req.Context(), string(patch))
+	ctx, err := schemaSignatureContext(req)
 	if err != nil {
-		handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+		handleErr(req.Context(), rw, err, http.StatusBadRequest)
+		return
+	}
+
+	err = db.PatchSchema(ctx, string(patch))
+	if err != nil {
+		handleErr(req.Context(), rw, err, schemaUpdateErrStatus(err))
 		return
 	}
 
@@ -208,6 +340,20 @@ func patchSchemaHandler(rw http.ResponseWriter, req *http.Request) {
 	)
 }
 
+// schemaSignatureContext returns the context of the given request with the signature of the schema
+// update given in the schema signature header, if any.
+func schemaSignatureContext(req *http.Request) (context.Context, error) {
+	v := req.Header.Get(SchemaSignatureHeader)
+	if v == "" {
+		return req.Context(), nil
+	}
+	signature, err := base64.StdEncoding.DecodeString(v)
+	if err != nil {
+		return nil, NewErrInvalidSchemaSig(err)
+	}
+	return client.WithSchemaSignature(req.Context(), signature), nil
+}
+
 func getBlockHandler(rw http.ResponseWriter, req *http.Request) {
 	cidStr := chi.URLParam(req, "cid")
 
@@ -292,6 +438,541 @@ func peerIDHandler(rw http.ResponseWriter, req *http.Request) {
 	)
 }
 
+type docKeyItem struct {
+	Key   string   `json:"key,omitempty"`
+	Heads []string `json:"heads,omitempty"`
+	Error string   `json:"error,omitempty"`
+}
+
+// docKeysHandler streams the dockeys of a collection as newline delimited JSON.
+//
+// The head CIDs of each document are included if the `heads` query parameter is true.
+// An error occurring after the stream has started is sent as a final line with an `error` field.
+func docKeysHandler(rw http.ResponseWriter, req *http.Request) {
+	withHeads := false
+	if v := req.URL.Query().Get("heads"); v != "" {
+		var err error
+		withHeads, err = strconv.ParseBool(v)
+		if err != nil {
+			handleErr(req.Context(), rw, errors.Wrap(err, "invalid heads parameter"), http.StatusBadRequest)
+			return
+		}
+	}
+
+	flusher, ok := rw.(http.Flusher)
+	if !ok {
+		handleErr(req.Context(), rw, ErrStreamingUnsupported, http.StatusInternalServerError)
+		return
+	}
+
+	col, ok := collectionFromRequest(rw, req)
+	if !ok {
+		return
+	}
+
+	var keysCh <-chan client.DocKeysResult
+	var err error
+	if withHeads {
+		keysCh, err = col.GetAllDocKeysWithHeads(req.Context())
+	} else {
+		keysCh, err = col.GetAllDocKeys(req.Context())
+	}
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	rw.Header().Set("Content-Type", contentTypeNDJSON)
+	rw.WriteHeader(http.StatusOK)
+
+	encoder := json.NewEncoder(rw)
+	for res := range keysCh {
+		item := docKeyItem{}
+		if res.Err != nil {
+			item.Error = res.Err.Error()
+		} else {
+			item.Key = res.Key.String()
+			for _, head := range res.Heads {
+				item.Heads = append(item.Heads, head.String())
+			}
+		}
+		if err := encoder.Encode(item); err != nil {
+			log.ErrorE(req.Context(), "Failed to write dockey", err)
+			return
+		}
+		flusher.Flush()
+	}
+}
+
+func collectionsStatsHandler(rw http.ResponseWriter, req *http.Request) {
+	db, err := dbFromContext(req.Context())
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	cols, err := db.GetAllCollections(req.Context())
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	stats := make([]client.CollectionStats, 0, len(cols))
+	for _, col := range cols {
+		colStats, err := col.Stats(req.Context())
+		if err != nil {
+			handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+			return
+		}
+		stats = append(stats, colStats)
+	}
+
+	sendJSON(
+		req.Context(),
+		rw,
+		DataResponse{
+			Data: stats,
+		},
+		http.StatusOK,
+	)
+}
+
+func collectionStatsHandler(rw http.ResponseWriter, req *http.Request) {
+	col, ok := collectionFromRequest(rw, req)
+	if !ok {
+		return
+	}
+
+	stats, err := col.Stats(req.Context())
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	sendJSON(
+		req.Context(),
+		rw,
+		DataResponse{
+			Data: stats,
+		},
+		http.StatusOK,
+	)
+}
+
+// countHandler returns the number of documents of a collection, optionally matching
+// the GQL filter given by the `filter` query parameter (e.g. `{name: {_eq: "John"}}`).
+func countHandler(rw http.ResponseWriter, req *http.Request) {
+	col, ok := collectionFromRequest(rw, req)
+	if !ok {
+		return
+	}
+
+	var filter any
+	if f := req.URL.Query().Get("filter"); f != "" {
+		filter = f
+	}
+
+	count, err := col.Count(req.Context(), filter)
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusBadRequest)
+		return
+	}
+
+	sendJSON(
+		req.Context(),
+		rw,
+		simpleDataResponse("count", count),
+		http.StatusOK,
+	)
+}
+
+type proofBlockItem struct {
+	Cid  string `json:"cid"`
+	Data []byte `json:"data"`
+}
+
+type proofResponse struct {
+	Root   string           `json:"root"`
+	Heads  []string         `json:"heads"`
+	Blocks []proofBlockItem `json:"blocks"`
+}
+
+// proofHandler returns a Merkle proof that the current state of a document derives from the
+// commit given by the `root` query parameter.
+//
+// The raw data of the blocks is base64 encoded.
+func proofHandler(rw http.ResponseWriter, req *http.Request) {
+	rootStr := req.URL.Query().Get("root")
+	if rootStr == "" {
+		handleErr(req.Context(), rw, ErrMissingProofRoot, http.StatusBadRequest)
+		return
+	}
+	root, err := cid.Decode(rootStr)
+	if err != nil {
+		handleErr(req.Context(), rw, errors.Wrap(err, "invalid root parameter"), http.StatusBadRequest)
+		return
+	}
+
+	key, err := client.NewDocKeyFromString(chi.URLParam(req, "dockey"))
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusBadRequest)
+		return
+	}
+
+	col, ok := collectionFromRequest(rw, req)
+	if !ok {
+		return
+	}
+
+	proof, err := col.GetProof(req.Context(), key, root)
+	if errors.Is(err, client.ErrDocumentNotFound) {
+		handleErr(req.Context(), rw, err, http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, client.ErrCommitNotInHistory) {
+		handleErr(req.Context(), rw, err, http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	res := proofResponse{
+		Root:   proof.Root.String(),
+		Heads:  make([]string, len(proof.Heads)),
+		Blocks: make([]proofBlockItem, len(proof.Blocks)),
+	}
+	for i, head := range proof.Heads {
+		res.Heads[i] = head.String()
+	}
+	for i, block := range proof.Blocks {
+		res.Blocks[i] = proofBlockItem{
+			Cid:  block.Cid.String(),
+			Data: block.Data,
+		}
+	}
+
+	sendJSON(
+		req.Context(),
+		rw,
+		DataResponse{Data: res},
+		http.StatusOK,
+	)
+}
+
+type mergeBlocksRequest struct {
+	Blocks [][]byte `json:"blocks"`
+}
+
+// mergeBlocksHandler merges the blocks of a commit of a document produced outside of the node
+// into the document, and returns the CID of the commit. The body must be a JSON object holding
+// the base64 encoded raw data of the blocks, for example `{"blocks": ["EkQKJAFw..."]}`.
+func mergeBlocksHandler(rw http.ResponseWriter, req *http.Request) {
+	body := mergeBlocksRequest{}
+	err := getJSON(req, &body)
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusBadRequest)
+		return
+	}
+
+	col, ok := collectionFromRequest(rw, req)
+	if !ok {
+		return
+	}
+
+	root, err := col.MergeBlocks(req.Context(), body.Blocks)
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusBadRequest)
+		return
+	}
+
+	sendJSON(
+		req.Context(),
+		rw,
+		simpleDataResponse("cid", root.String()),
+		http.StatusOK,
+	)
+}
+
+// collectionFromRequest returns the collection named by the `name` URL parameter.
+//
+// If the collection can't be obtained the error is sent to the client and false is returned.
+func collectionFromRequest(rw http.ResponseWriter, req *http.Request) (client.Collection, bool) {
+	db, err := dbFromContext(req.Context())
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+		return nil, false
+	}
+
+	col, err := db.GetCollectionByName(req.Context(), chi.URLParam(req, "name"))
+	if errors.Is(err, ds.ErrNotFound) {
+		handleErr(req.Context(), rw, ErrCollectionNotFound, http.StatusNotFound)
+		return nil, false
+	}
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+		return nil, false
+	}
+	return col, true
+}
+
+type activeQuery struct {
+	ID      uint64 `json:"id"`
+	Request string `json:"request"`
+	Elapsed string `json:"elapsed"`
+	Plan    string `json:"plan"`
+}
+
+func listQueriesHandler(rw http.ResponseWriter, req *http.Request) {
+	db, err := dbFromContext(req.Context())
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	queries := []activeQuery{}
+	for _, r := range db.ActiveRequests() {
+		queries = append(queries, activeQuery{
+			ID:      r.ID,
+			Request: r.Request,
+			Elapsed: time.Since(r.StartedAt).String(),
+			Plan:    r.Plan,
+		})
+	}
+
+	sendJSON(
+		req.Context(),
+		rw,
+		DataResponse{
+			Data: queries,
+		},
+		http.StatusOK,
+	)
+}
+
+func cancelQueryHandler(rw http.ResponseWriter, req *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(req, "id"), 10, 64)
+	if err != nil {
+		handleErr(req.Context(), rw, ErrInvalidRequestID, http.StatusBadRequest)
+		return
+	}
+
+	db, err := dbFromContext(req.Context())
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	err = db.CancelRequest(id)
+	if errors.Is(err, client.ErrRequestNotFound) {
+		handleErr(req.Context(), rw, err, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	sendJSON(
+		req.Context(),
+		rw,
+		simpleDataResponse("response", "ok"),
+		http.StatusOK,
+	)
+}
+
+func txnStatsHandler(rw http.ResponseWriter, req *http.Request) {
+	db, err := dbFromContext(req.Context())
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	sendJSON(
+		req.Context(),
+		rw,
+		DataResponse{
+			Data: db.TxnStats(),
+		},
+		http.StatusOK,
+	)
+}
+
+// metricsHandler returns the metrics gathered by the database, such as those of the merges of the
+// deltas produced by other nodes.
+func metricsHandler(rw http.ResponseWriter, req *http.Request) {
+	db, err := dbFromContext(req.Context())
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	metrics, err := db.Metrics(req.Context())
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	sendJSON(
+		req.Context(),
+		rw,
+		DataResponse{
+			Data: json.RawMessage(metrics),
+		},
+		http.StatusOK,
+	)
+}
+
+func (h *handler) getConfigHandler(rw http.ResponseWriter, req *http.Request) {
+	sendJSON(
+		req.Context(),
+		rw,
+		DataResponse{
+			Data: h.options.cfg,
+		},
+		http.StatusOK,
+	)
+}
+
+// updateConfigHandler updates the runtime configurable settings. The body must be a JSON object
+// of config keys to values (e.g. `{"log.level": "debug", "api.ratelimit": 100}`).
+func (h *handler) updateConfigHandler(rw http.ResponseWriter, req *http.Request) {
+	values := map[string]any{}
+	err := getJSON(req, &values)
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusBadRequest)
+		return
+	}
+
+	err = h.options.cfg.Update(values)
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusBadRequest)
+		return
+	}
+
+	h.applyConfig()
+	if h.options.onConfigUpdate != nil {
+		h.options.onConfigUpdate(req.Context())
+	}
+
+	sendJSON(
+		req.Context(),
+		rw,
+		DataResponse{
+			Data: h.options.cfg,
+		},
+		http.StatusOK,
+	)
+}
+
+func listWebhooksHandler(rw http.ResponseWriter, req *http.Request) {
+	db, err := dbFromContext(req.Context())
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	webhooks, err := db.GetAllWebhooks(req.Context())
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	sendJSON(
+		req.Context(),
+		rw,
+		DataResponse{
+			Data: webhooks,
+		},
+		http.StatusOK,
+	)
+}
+
+// addWebhookHandler registers a webhook. The body must be a JSON webhook, for example
+// `{"url": "https://example.com/hook", "collection": "User", "filter": "{age: {_gt: 18}}"}`.
+func addWebhookHandler(rw http.ResponseWriter, req *http.Request) {
+	webhook := client.Webhook{}
+	err := getJSON(req, &webhook)
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusBadRequest)
+		return
+	}
+
+	db, err := dbFromContext(req.Context())
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	webhook, err = db.AddWebhook(req.Context(), webhook)
+	if errors.Is(err, ds.ErrNotFound) {
+		handleErr(req.Context(), rw, ErrCollectionNotFound, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusBadRequest)
+		return
+	}
+
+	sendJSON(
+		req.Context(),
+		rw,
+		DataResponse{
+			Data: webhook,
+		},
+		http.StatusOK,
+	)
+}
+
+func deleteWebhookHandler(rw http.ResponseWriter, req *http.Request) {
+	db, err := dbFromContext(req.Context())
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	err = db.DeleteWebhook(req.Context(), chi.URLParam(req, "id"))
+	if errors.Is(err, client.ErrWebhookNotFound) {
+		handleErr(req.Context(), rw, err, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	sendJSON(
+		req.Context(),
+		rw,
+		simpleDataResponse("response", "ok"),
+		http.StatusOK,
+	)
+}
+
+func webhookDeadLettersHandler(rw http.ResponseWriter, req *http.Request) {
+	db, err := dbFromContext(req.Context())
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	deadLetters, err := db.GetWebhookDeadLetters(req.Context())
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	sendJSON(
+		req.Context(),
+		rw,
+		DataResponse{
+			Data: deadLetters,
+		},
+		http.StatusOK,
+	)
+}
+
 func subscriptionHandler(pub *events.Publisher[events.Update], rw http.ResponseWriter, req *http.Request) {
 	flusher, ok := rw.(http.Flusher)
 	if !ok {
@@ -303,22 +984,52 @@ func subscriptionHandler(pub *events.Publisher[events.Update], rw http.ResponseW
 	rw.Header().Set("Cache-Control", "no-cache")
 	rw.Header().Set("Connection", "keep-alive")
 
+	// draining is nil (and thus blocks forever) if the handler is not served by a [Server].
+	draining, _ := req.Context().Value(ctxDraining{}).(<-chan struct{})
+
 	for {
 		select {
 		case <-req.Context().Done():
 			pub.Unsubscribe()
 			return
+		case <-draining:
+			// The server is shutting down, send whatever is already buffered
+			// to the client before ending the stream.
+			for {
+				select {
+				case s, open := <-pub.Stream():
+					if !open {
+						return
+					}
+					if err := writeEvent(rw, flusher, s); err != nil {
+						log.ErrorE(req.Context(), "Failed to flush subscription event", err)
+						pub.Unsubscribe()
+						return
+					}
+				default:
+					pub.Unsubscribe()
+					return
+				}
+			}
 		case s, open := <-pub.Stream():
 			if !open {
 				return
 			}
-			b, err := json.Marshal(s)
-			if err != nil {
+			if err := writeEvent(rw, flusher, s); err != nil {
 				handleErr(req.Context(), rw, err, http.StatusInternalServerError)
 				return
 			}
-			fmt.Fprintf(rw, "data: %s\n\n", b)
-			flusher.Flush()
 		}
 	}
 }
+
+// writeEvent writes the given value to the response as a server-sent event.
+func writeEvent(rw http.ResponseWriter, flusher http.Flusher, v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(rw, "data: %s\n\n", b)
+	flusher.Flush()
+	return nil
+}
@@ -13,22 +13,28 @@ package http
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
 	"time"
 
 	badger "github.com/dgraph-io/badger/v3"
 	dshelp "github.com/ipfs/boxo/datastore/dshelp"
+	dag "github.com/ipfs/boxo/ipld/merkledag"
 	"github.com/ipfs/go-cid"
+	"github.com/libp2p/go-libp2p/core/crypto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/config"
 	badgerds "github.com/sourcenetwork/defradb/datastore/badger/v3"
 	"github.com/sourcenetwork/defradb/db"
 	"github.com/sourcenetwork/defradb/errors"
@@ -135,9 +141,105 @@ func TestDumpHandlerWithDBError(t *testing.T) {
 	assert.Contains(t, errResponse.Errors[0].Extensions.Stack, "no database available")
 	assert.Equal(t, http.StatusInternalServerError, errResponse.Errors[0].Extensions.Status)
 	assert.Equal(t, "Internal Server Error", errResponse.Errors[0].Extensions.HTTPError)
+	assert.Equal(t, errors.CodeUnavailable, errResponse.Errors[0].Extensions.Code)
 	assert.Equal(t, "no database available", errResponse.Errors[0].Message)
 }
 
+func TestDumpDataHandler(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+
+	testLoadSchema(t, ctx, defra)
+
+	col, err := defra.GetCollectionByName(ctx, "user")
+	require.NoError(t, err)
+	doc, err := client.NewDocFromJSON([]byte(`{"name": "Bob"}`))
+	require.NoError(t, err)
+	err = col.Create(ctx, doc)
+	require.NoError(t, err)
+
+	cfg := config.DefaultConfig()
+	cfg.API.AdminToken = "secret"
+
+	req, err := http.NewRequest("GET", DumpPath+"/data?keyspace=data&prefix=/1/v&decode=true", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+	newHandler(defra, serverOptions{cfg: cfg}).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/x-ndjson", rec.Header().Get("Content-Type"))
+
+	items := []dumpItem{}
+	decoder := json.NewDecoder(rec.Body)
+	for decoder.More() {
+		item := dumpItem{}
+		require.NoError(t, decoder.Decode(&item))
+		items = append(items, item)
+	}
+	require.Len(t, items, 1)
+	assert.Equal(t, "/db/data/1/v/"+doc.Key().String()+"/2", items[0].Key)
+	assert.Equal(t, "Bob", items[0].Value)
+	assert.Empty(t, items[0].Error)
+}
+
+func TestDumpDataHandlerWithUnknownKeyspace(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+
+	cfg := config.DefaultConfig()
+	cfg.API.AdminToken = "secret"
+
+	errResponse := ErrorResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		DB:             defra,
+		Method:         "GET",
+		Path:           DumpPath + "/data?keyspace=unknown",
+		Body:           nil,
+		Headers:        map[string]string{"Authorization": "Bearer secret"},
+		ExpectedStatus: 400,
+		ResponseData:   &errResponse,
+		ServerOptions:  serverOptions{cfg: cfg},
+	})
+
+	assert.Contains(t, errResponse.Errors[0].Message, "unknown keyspace")
+}
+
+func TestDumpDataHandlerWithoutAdminToken(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.API.AdminToken = "secret"
+
+	errResponse := ErrorResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		Method:         "GET",
+		Path:           DumpPath + "/data?keyspace=system&decode=true",
+		Body:           nil,
+		ExpectedStatus: 401,
+		ResponseData:   &errResponse,
+		ServerOptions:  serverOptions{cfg: cfg},
+	})
+
+	assert.Equal(t, ErrUnauthorized.Error(), errResponse.Errors[0].Message)
+}
+
+func TestDumpDataHandlerWithAdminDisabled(t *testing.T) {
+	errResponse := ErrorResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		Method:         "GET",
+		Path:           DumpPath + "/data",
+		Body:           nil,
+		ExpectedStatus: 403,
+		ResponseData:   &errResponse,
+	})
+
+	assert.Equal(t, ErrAdminDisabled.Error(), errResponse.Errors[0].Message)
+}
+
 func TestExecGQLWithNilBody(t *testing.T) {
 	t.Cleanup(CleanupEnv)
 	env = "dev"
@@ -155,6 +257,7 @@ func TestExecGQLWithNilBody(t *testing.T) {
 	assert.Contains(t, errResponse.Errors[0].Extensions.Stack, "body cannot be empty")
 	assert.Equal(t, http.StatusBadRequest, errResponse.Errors[0].Extensions.Status)
 	assert.Equal(t, "Bad Request", errResponse.Errors[0].Extensions.HTTPError)
+	assert.Equal(t, errors.CodeInvalidRequest, errResponse.Errors[0].Extensions.Code)
 	assert.Equal(t, "body cannot be empty", errResponse.Errors[0].Message)
 }
 
@@ -175,6 +278,7 @@ func TestExecGQLWithEmptyBody(t *testing.T) {
 	assert.Contains(t, errResponse.Errors[0].Extensions.Stack, "missing GraphQL request")
 	assert.Equal(t, http.StatusBadRequest, errResponse.Errors[0].Extensions.Status)
 	assert.Equal(t, "Bad Request", errResponse.Errors[0].Extensions.HTTPError)
+	assert.Equal(t, errors.CodeInvalidRequest, errResponse.Errors[0].Extensions.Code)
 	assert.Equal(t, "missing GraphQL request", errResponse.Errors[0].Message)
 }
 
@@ -347,6 +451,41 @@ func TestExecGQLHandlerContentTypeJSON(t *testing.T) {
 	assert.Contains(t, users[0].Key, "bae-")
 }
 
+func TestExecGQLHandlerContentTypeJSONWithVariables(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+
+	testLoadSchema(t, ctx, defra)
+
+	res := defra.ExecRequest(ctx, `mutation { create_user(data: "{\"name\": \"Bob\"}") { _key } }`)
+	require.Empty(t, res.GQL.Errors)
+
+	stmt := `{
+		"query": "query($withVersion: Boolean!) { user { _key _version @include(if: $withVersion) { cid } } }",
+		"variables": {"withVersion": false}
+	}`
+
+	users := []testUser{}
+	resp := DataResponse{
+		Data: &users,
+	}
+	testRequest(testOptions{
+		Testing:        t,
+		DB:             defra,
+		Method:         "POST",
+		Path:           GraphQLPath,
+		Body:           bytes.NewBuffer([]byte(stmt)),
+		Headers:        map[string]string{"Content-Type": contentTypeJSON},
+		ExpectedStatus: 200,
+		ResponseData:   &resp,
+	})
+
+	require.Len(t, users, 1)
+	assert.Contains(t, users[0].Key, "bae-")
+	assert.Empty(t, users[0].Versions)
+}
+
 func TestExecGQLHandlerContentTypeJSONWithError(t *testing.T) {
 	ctx := context.Background()
 	defra := testNewInMemoryDB(t, ctx)
@@ -384,8 +523,9 @@ func TestExecGQLHandlerContentTypeJSONWithError(t *testing.T) {
 		ResponseData:   &resp,
 	})
 
-	assert.Contains(t, resp.Errors, "The given field does not exist. Name: notAField")
-	assert.Len(t, resp.Errors, 1)
+	require.Len(t, resp.Errors, 1)
+	assert.Equal(t, "The given field does not exist. Name: notAField", resp.Errors[0].Message)
+	assert.Equal(t, errors.CodeFieldNotExist, resp.Errors[0].Extensions.Code)
 }
 
 func TestExecGQLHandlerContentTypeJSONWithCharset(t *testing.T) {
@@ -432,6 +572,105 @@ func TestExecGQLHandlerContentTypeJSONWithCharset(t *testing.T) {
 	assert.Contains(t, users[0].Key, "bae-")
 }
 
+func TestExecGQLHandlerWithReadCommittedIsolation(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+
+	testLoadSchema(t, ctx, defra)
+
+	resp := GQLResult{}
+	testRequest(testOptions{
+		Testing: t,
+		DB:      defra,
+		Method:  "POST",
+		Path:    GraphQLPath,
+		Body:    bytes.NewBuffer([]byte(`mutation { create_user(data: "{\"name\": \"Bob\"}") { _key } }`)),
+		Headers: map[string]string{
+			"Content-Type":       contentTypeGraphQL,
+			IsolationLevelHeader: "read-committed",
+		},
+		ExpectedStatus: 200,
+		ResponseData:   &resp,
+	})
+
+	require.Len(t, resp.Errors, 1)
+	assert.Contains(t, resp.Errors[0].Message, "read only transaction")
+}
+
+func TestExecGQLHandlerWithUnknownIsolationLevel(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+
+	errResponse := ErrorResponse{}
+	testRequest(testOptions{
+		Testing: t,
+		DB:      defra,
+		Method:  "POST",
+		Path:    GraphQLPath,
+		Body:    bytes.NewBuffer([]byte(`query { user { name } }`)),
+		Headers: map[string]string{
+			"Content-Type":       contentTypeGraphQL,
+			IsolationLevelHeader: "serializable",
+		},
+		ExpectedStatus: 400,
+		ResponseData:   &errResponse,
+	})
+
+	assert.Equal(t, "unknown isolation level. Name: serializable", errResponse.Errors[0].Message)
+	assert.Equal(t, errors.CodeInvalidRequest, errResponse.Errors[0].Extensions.Code)
+}
+
+func TestExecGQLHandlerWithPlanDebug(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+
+	testLoadSchema(t, ctx, defra)
+
+	resp := GQLResult{}
+	testRequest(testOptions{
+		Testing: t,
+		DB:      defra,
+		Method:  "POST",
+		Path:    GraphQLPath,
+		Body:    bytes.NewBuffer([]byte(`query { user { name } }`)),
+		Headers: map[string]string{
+			"Content-Type":  contentTypeGraphQL,
+			PlanDebugHeader: "true",
+		},
+		ExpectedStatus: 200,
+		ResponseData:   &resp,
+	})
+
+	assert.Empty(t, resp.Errors)
+}
+
+func TestExecGQLHandlerWithInvalidPlanDebug(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+
+	errResponse := ErrorResponse{}
+	testRequest(testOptions{
+		Testing: t,
+		DB:      defra,
+		Method:  "POST",
+		Path:    GraphQLPath,
+		Body:    bytes.NewBuffer([]byte(`query { user { name } }`)),
+		Headers: map[string]string{
+			"Content-Type":  contentTypeGraphQL,
+			PlanDebugHeader: "sometimes",
+		},
+		ExpectedStatus: 400,
+		ResponseData:   &errResponse,
+	})
+
+	assert.Equal(t, "invalid plan debug header value. Value: sometimes", errResponse.Errors[0].Message)
+	assert.Equal(t, errors.CodeInvalidRequest, errResponse.Errors[0].Extensions.Code)
+}
+
 func TestExecGQLHandlerContentTypeFormURLEncoded(t *testing.T) {
 	t.Cleanup(CleanupEnv)
 	env = "dev"
@@ -720,6 +959,62 @@ type user {
 	}
 }
 
+func TestLoadSchemaHandlerWithSchemaSignature(t *testing.T) {
+	ctx := context.Background()
+	admin, adminPub, err := crypto.GenerateKeyPair(crypto.Ed25519, 0)
+	require.NoError(t, err)
+	defra := testNewInMemoryDB(t, ctx, db.WithSchemaAdmin(adminPub))
+	defer defra.Close(ctx)
+
+	stmt := `type user { name: String }`
+	signature, err := admin.Sign(client.SchemaUpdatePayload(client.SchemaUpdateAdd, stmt))
+	require.NoError(t, err)
+
+	resp := DataResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		DB:             defra,
+		Method:         "POST",
+		Path:           SchemaLoadPath,
+		Body:           bytes.NewBuffer([]byte(stmt)),
+		Headers:        map[string]string{SchemaSignatureHeader: base64.StdEncoding.EncodeToString(signature)},
+		ExpectedStatus: 200,
+		ResponseData:   &resp,
+	})
+	assert.Equal(t, map[string]any{"result": "success"}, resp.Data)
+}
+
+func TestLoadSchemaHandlerWithoutSchemaSignature(t *testing.T) {
+	ctx := context.Background()
+	_, adminPub, err := crypto.GenerateKeyPair(crypto.Ed25519, 0)
+	require.NoError(t, err)
+	defra := testNewInMemoryDB(t, ctx, db.WithSchemaAdmin(adminPub))
+	defer defra.Close(ctx)
+
+	errResponse := ErrorResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		DB:             defra,
+		Method:         "POST",
+		Path:           SchemaLoadPath,
+		Body:           bytes.NewBuffer([]byte(`type user { name: String }`)),
+		ExpectedStatus: 403,
+		ResponseData:   &errResponse,
+	})
+	assert.Equal(t, errors.CodeUnauthorized, errResponse.Errors[0].Extensions.Code)
+
+	testRequest(testOptions{
+		Testing:        t,
+		DB:             defra,
+		Method:         "POST",
+		Path:           SchemaLoadPath,
+		Body:           bytes.NewBuffer([]byte(`type user { name: String }`)),
+		Headers:        map[string]string{SchemaSignatureHeader: "not base64"},
+		ExpectedStatus: 400,
+		ResponseData:   &errResponse,
+	})
+}
+
 func TestGetBlockHandlerWithMultihashError(t *testing.T) {
 	t.Cleanup(CleanupEnv)
 	env = "dev"
@@ -943,6 +1238,684 @@ func TestPeerIDHandlerWithNoPeerIDInContext(t *testing.T) {
 	assert.Equal(t, "no peer ID available. P2P might be disabled", errResponse.Errors[0].Message)
 }
 
+func TestGetConfigHandlerWithoutAdminToken(t *testing.T) {
+	errResponse := ErrorResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		Method:         "GET",
+		Path:           ConfigPath,
+		ExpectedStatus: 403,
+		ResponseData:   &errResponse,
+		ServerOptions: serverOptions{
+			cfg: config.DefaultConfig(),
+		},
+	})
+
+	assert.Equal(t, ErrAdminDisabled.Error(), errResponse.Errors[0].Message)
+}
+
+func TestGetConfigHandlerWithInvalidAdminToken(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.API.AdminToken = "secret"
+
+	errResponse := ErrorResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		Method:         "GET",
+		Path:           ConfigPath,
+		Headers:        map[string]string{"Authorization": "Bearer not-the-secret"},
+		ExpectedStatus: 401,
+		ResponseData:   &errResponse,
+		ServerOptions: serverOptions{
+			cfg: cfg,
+		},
+	})
+
+	assert.Equal(t, ErrUnauthorized.Error(), errResponse.Errors[0].Message)
+}
+
+func TestGetConfigHandler(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.API.AdminToken = "secret"
+
+	resp := DataResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		Method:         "GET",
+		Path:           ConfigPath,
+		Headers:        map[string]string{"Authorization": "Bearer secret"},
+		ExpectedStatus: 200,
+		ResponseData:   &resp,
+		ServerOptions: serverOptions{
+			cfg: cfg,
+		},
+	})
+
+	data, ok := resp.Data.(map[string]any)
+	if !ok {
+		t.Fatalf("data should be of type map[string]any but got %T", resp.Data)
+	}
+	api := data["API"].(map[string]any)
+	assert.Equal(t, "localhost:9181", api["Address"])
+	assert.NotContains(t, api, "AdminToken")
+}
+
+func TestUpdateConfigHandler(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.API.AdminToken = "secret"
+	var updated bool
+
+	resp := DataResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		Method:         "PUT",
+		Path:           ConfigPath,
+		Body:           bytes.NewBuffer([]byte(`{"api.ratelimit": 10, "api.allowedorigins": "https://example.com"}`)),
+		Headers:        map[string]string{"Authorization": "Bearer secret"},
+		ExpectedStatus: 200,
+		ResponseData:   &resp,
+		ServerOptions: serverOptions{
+			cfg: cfg,
+			onConfigUpdate: func(context.Context) {
+				updated = true
+			},
+		},
+	})
+
+	assert.True(t, updated)
+	assert.Equal(t, 10, cfg.API.RateLimit)
+	assert.Equal(t, "https://example.com", cfg.API.AllowedOrigins)
+}
+
+func TestUpdateConfigHandlerWithNonReloadableKey(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.API.AdminToken = "secret"
+
+	errResponse := ErrorResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		Method:         "PUT",
+		Path:           ConfigPath,
+		Body:           bytes.NewBuffer([]byte(`{"datastore.store": "memory"}`)),
+		Headers:        map[string]string{"Authorization": "Bearer secret"},
+		ExpectedStatus: 400,
+		ResponseData:   &errResponse,
+		ServerOptions: serverOptions{
+			cfg: cfg,
+		},
+	})
+
+	assert.Contains(t, errResponse.Errors[0].Message, "config key cannot be changed at runtime")
+	assert.Equal(t, "badger", cfg.Datastore.Store)
+}
+
+func TestDocKeysHandler(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+
+	testLoadSchema(t, ctx, defra)
+
+	col, err := defra.GetCollectionByName(ctx, "user")
+	require.NoError(t, err)
+	expectedKeys := []string{}
+	for _, name := range []string{"Bob", "Alice"} {
+		doc, err := client.NewDocFromJSON([]byte(fmt.Sprintf(`{"name": "%s"}`, name)))
+		require.NoError(t, err)
+		err = col.Create(ctx, doc)
+		require.NoError(t, err)
+		expectedKeys = append(expectedKeys, doc.Key().String())
+	}
+
+	for _, withHeads := range []bool{false, true} {
+		req, err := http.NewRequest("GET", fmt.Sprintf("%s/user/dockeys?heads=%v", CollectionsPath, withHeads), nil)
+		require.NoError(t, err)
+		rec := httptest.NewRecorder()
+		newHandler(defra, serverOptions{}).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/x-ndjson", rec.Header().Get("Content-Type"))
+
+		keys := []string{}
+		decoder := json.NewDecoder(rec.Body)
+		for decoder.More() {
+			item := docKeyItem{}
+			require.NoError(t, decoder.Decode(&item))
+			assert.Empty(t, item.Error)
+			if withHeads {
+				assert.Len(t, item.Heads, 1)
+			} else {
+				assert.Empty(t, item.Heads)
+			}
+			keys = append(keys, item.Key)
+		}
+		assert.ElementsMatch(t, expectedKeys, keys)
+	}
+}
+
+func TestDocKeysHandlerWithUnknownCollection(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+
+	errResponse := ErrorResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		DB:             defra,
+		Method:         "GET",
+		Path:           CollectionsPath + "/user/dockeys",
+		Body:           nil,
+		ExpectedStatus: 404,
+		ResponseData:   &errResponse,
+	})
+
+	assert.Equal(t, "collection not found", errResponse.Errors[0].Message)
+	assert.Equal(t, errors.CodeCollectionNotFound, errResponse.Errors[0].Extensions.Code)
+}
+
+func TestCollectionsStatsHandler(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+
+	testLoadSchema(t, ctx, defra)
+
+	col, err := defra.GetCollectionByName(ctx, "user")
+	require.NoError(t, err)
+	doc, err := client.NewDocFromJSON([]byte(`{"name": "Bob", "age": 31}`))
+	require.NoError(t, err)
+	err = col.Create(ctx, doc)
+	require.NoError(t, err)
+
+	stats := []client.CollectionStats{}
+	testRequest(testOptions{
+		Testing:        t,
+		DB:             defra,
+		Method:         "GET",
+		Path:           CollectionsPath + "/stats",
+		Body:           nil,
+		ExpectedStatus: 200,
+		ResponseData:   &DataResponse{Data: &stats},
+	})
+
+	require.Len(t, stats, 1)
+	assert.Equal(t, "user", stats[0].Name)
+	assert.Equal(t, uint64(1), stats[0].DocCount)
+	assert.Equal(t, uint64(0), stats[0].TombstoneCount)
+	assert.NotNil(t, stats[0].LastUpdatedAt)
+}
+
+func TestCollectionStatsHandlerWithUnknownCollection(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+
+	errResponse := ErrorResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		DB:             defra,
+		Method:         "GET",
+		Path:           CollectionsPath + "/user/stats",
+		Body:           nil,
+		ExpectedStatus: 404,
+		ResponseData:   &errResponse,
+	})
+
+	assert.Equal(t, "collection not found", errResponse.Errors[0].Message)
+}
+
+func TestCountHandlerWithFilter(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+
+	testLoadSchema(t, ctx, defra)
+
+	col, err := defra.GetCollectionByName(ctx, "user")
+	require.NoError(t, err)
+	for _, data := range []string{`{"name": "Bob", "age": 31}`, `{"name": "Alice", "age": 22}`} {
+		doc, err := client.NewDocFromJSON([]byte(data))
+		require.NoError(t, err)
+		err = col.Create(ctx, doc)
+		require.NoError(t, err)
+	}
+
+	resp := DataResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		DB:             defra,
+		Method:         "GET",
+		Path:           CollectionsPath + "/user/count?filter=" + url.QueryEscape(`{age: {_gt: 30}}`),
+		Body:           nil,
+		ExpectedStatus: 200,
+		ResponseData:   &resp,
+	})
+
+	assert.Equal(t, map[string]any{"count": float64(1)}, resp.Data)
+}
+
+func TestProofHandler(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+
+	testLoadSchema(t, ctx, defra)
+
+	col, err := defra.GetCollectionByName(ctx, "user")
+	require.NoError(t, err)
+	doc, err := client.NewDocFromJSON([]byte(`{"name": "Bob", "age": 31}`))
+	require.NoError(t, err)
+	err = col.Create(ctx, doc)
+	require.NoError(t, err)
+	root := doc.Head()
+
+	err = doc.Set("age", 32)
+	require.NoError(t, err)
+	err = col.Update(ctx, doc)
+	require.NoError(t, err)
+
+	resp := struct {
+		Data proofResponse `json:"data"`
+	}{}
+	testRequest(testOptions{
+		Testing:        t,
+		DB:             defra,
+		Method:         "GET",
+		Path:           CollectionsPath + "/user/proof/" + doc.Key().String() + "?root=" + root.String(),
+		Body:           nil,
+		ExpectedStatus: 200,
+		ResponseData:   &resp,
+	})
+
+	assert.Equal(t, root.String(), resp.Data.Root)
+	assert.Equal(t, []string{doc.Head().String()}, resp.Data.Heads)
+	require.Len(t, resp.Data.Blocks, 2)
+	assert.Equal(t, doc.Head().String(), resp.Data.Blocks[0].Cid)
+	assert.Equal(t, root.String(), resp.Data.Blocks[1].Cid)
+	for _, block := range resp.Data.Blocks {
+		c, err := cid.Decode(block.Cid)
+		require.NoError(t, err)
+		sum, err := c.Prefix().Sum(block.Data)
+		require.NoError(t, err)
+		assert.Equal(t, c, sum)
+	}
+}
+
+func TestProofHandlerWithoutRoot(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+
+	testLoadSchema(t, ctx, defra)
+
+	errResponse := ErrorResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		DB:             defra,
+		Method:         "GET",
+		Path:           CollectionsPath + "/user/proof/bae-52b9170d-b77a-5887-b877-cbdbb99b009f",
+		Body:           nil,
+		ExpectedStatus: 400,
+		ResponseData:   &errResponse,
+	})
+
+	assert.Equal(t, "missing root commit CID", errResponse.Errors[0].Message)
+	assert.Equal(t, errors.CodeInvalidRequest, errResponse.Errors[0].Extensions.Code)
+}
+
+func TestMergeBlocksHandler(t *testing.T) {
+	ctx := context.Background()
+	offline := testNewInMemoryDB(t, ctx)
+	defer offline.Close(ctx)
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+
+	testLoadSchema(t, ctx, offline)
+	testLoadSchema(t, ctx, defra)
+
+	offlineCol, err := offline.GetCollectionByName(ctx, "user")
+	require.NoError(t, err)
+	doc, err := client.NewDocFromJSON([]byte(`{"name": "Bob", "age": 31}`))
+	require.NoError(t, err)
+	err = offlineCol.Create(ctx, doc)
+	require.NoError(t, err)
+
+	block, err := offline.Blockstore().Get(ctx, doc.Head())
+	require.NoError(t, err)
+	blocks := [][]byte{block.RawData()}
+	nd, err := dag.DecodeProtobuf(block.RawData())
+	require.NoError(t, err)
+	for _, link := range nd.Links() {
+		fieldBlock, err := offline.Blockstore().Get(ctx, link.Cid)
+		require.NoError(t, err)
+		blocks = append(blocks, fieldBlock.RawData())
+	}
+	body, err := json.Marshal(mergeBlocksRequest{Blocks: blocks})
+	require.NoError(t, err)
+
+	resp := DataResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		DB:             defra,
+		Method:         "POST",
+		Path:           CollectionsPath + "/user/merge",
+		Body:           bytes.NewBuffer(body),
+		ExpectedStatus: 200,
+		ResponseData:   &resp,
+	})
+
+	assert.Equal(t, map[string]any{"cid": doc.Head().String()}, resp.Data)
+
+	col, err := defra.GetCollectionByName(ctx, "user")
+	require.NoError(t, err)
+	merged, err := col.Get(ctx, doc.Key(), false)
+	require.NoError(t, err)
+	name, err := merged.Get("name")
+	require.NoError(t, err)
+	assert.Equal(t, "Bob", name)
+}
+
+func TestMergeBlocksHandlerWithoutBlocks(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+
+	testLoadSchema(t, ctx, defra)
+
+	errResponse := ErrorResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		DB:             defra,
+		Method:         "POST",
+		Path:           CollectionsPath + "/user/merge",
+		Body:           bytes.NewBuffer([]byte(`{"blocks": []}`)),
+		ExpectedStatus: 400,
+		ResponseData:   &errResponse,
+	})
+
+	assert.Equal(t, "no blocks to merge", errResponse.Errors[0].Message)
+}
+
+func TestListQueriesHandler(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+
+	cfg := config.DefaultConfig()
+	cfg.API.AdminToken = "secret"
+
+	resp := DataResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		DB:             defra,
+		Method:         "GET",
+		Path:           QueriesPath,
+		Body:           nil,
+		Headers:        map[string]string{"Authorization": "Bearer secret"},
+		ExpectedStatus: 200,
+		ResponseData:   &resp,
+		ServerOptions:  serverOptions{cfg: cfg},
+	})
+
+	assert.Equal(t, []any{}, resp.Data)
+}
+
+func TestListQueriesHandlerWithoutAdminToken(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.API.AdminToken = "secret"
+
+	errResponse := ErrorResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		Method:         "GET",
+		Path:           QueriesPath,
+		Body:           nil,
+		ExpectedStatus: 401,
+		ResponseData:   &errResponse,
+		ServerOptions:  serverOptions{cfg: cfg},
+	})
+
+	assert.Equal(t, ErrUnauthorized.Error(), errResponse.Errors[0].Message)
+}
+
+func TestTxnStatsHandler(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+
+	resp := DataResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		DB:             defra,
+		Method:         "GET",
+		Path:           TxnsPath + "/stats",
+		Body:           nil,
+		ExpectedStatus: 200,
+		ResponseData:   &resp,
+	})
+
+	stats, ok := resp.Data.(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, float64(0), stats["activeReads"])
+	assert.Equal(t, float64(0), stats["queuedReads"])
+	assert.Equal(t, float64(0), stats["maxReads"])
+}
+
+func TestMetricsHandler(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+
+	testLoadSchema(t, ctx, defra)
+
+	col, err := defra.GetCollectionByName(ctx, "user")
+	require.NoError(t, err)
+	col.RecordRemoteMerge(client.RemoteMerge{Priority: 1, Commit: true})
+
+	resp := DataResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		DB:             defra,
+		Method:         "GET",
+		Path:           MetricsPath,
+		Body:           nil,
+		ExpectedStatus: 200,
+		ResponseData:   &resp,
+	})
+
+	scopes, ok := resp.Data.([]any)
+	require.True(t, ok)
+	require.Len(t, scopes, 1)
+	scope, ok := scopes[0].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "defradb", scope["Scope"].(map[string]any)["Name"])
+	assert.Len(t, scope["Metrics"], 2)
+}
+
+func TestCancelQueryHandlerWithUnknownID(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+
+	cfg := config.DefaultConfig()
+	cfg.API.AdminToken = "secret"
+
+	errResponse := ErrorResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		DB:             defra,
+		Method:         "DELETE",
+		Path:           QueriesPath + "/1",
+		Body:           nil,
+		Headers:        map[string]string{"Authorization": "Bearer secret"},
+		ExpectedStatus: 404,
+		ResponseData:   &errResponse,
+		ServerOptions:  serverOptions{cfg: cfg},
+	})
+
+	assert.Equal(t, http.StatusNotFound, errResponse.Errors[0].Extensions.Status)
+	assert.Contains(t, errResponse.Errors[0].Message, "no active request with the given ID")
+}
+
+func TestCancelQueryHandlerWithInvalidID(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+
+	cfg := config.DefaultConfig()
+	cfg.API.AdminToken = "secret"
+
+	errResponse := ErrorResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		DB:             defra,
+		Method:         "DELETE",
+		Path:           QueriesPath + "/abc",
+		Body:           nil,
+		Headers:        map[string]string{"Authorization": "Bearer secret"},
+		ExpectedStatus: 400,
+		ResponseData:   &errResponse,
+		ServerOptions:  serverOptions{cfg: cfg},
+	})
+
+	assert.Equal(t, "invalid request ID", errResponse.Errors[0].Message)
+}
+
+func TestCancelQueryHandlerWithInvalidAdminToken(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.API.AdminToken = "secret"
+
+	errResponse := ErrorResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		Method:         "DELETE",
+		Path:           QueriesPath + "/1",
+		Headers:        map[string]string{"Authorization": "Bearer not-the-secret"},
+		ExpectedStatus: 401,
+		ResponseData:   &errResponse,
+		ServerOptions:  serverOptions{cfg: cfg},
+	})
+
+	assert.Equal(t, ErrUnauthorized.Error(), errResponse.Errors[0].Message)
+}
+
+func TestWebhookHandlers(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+	testLoadSchema(t, ctx, defra)
+
+	cfg := config.DefaultConfig()
+	cfg.API.AdminToken = "secret"
+	headers := map[string]string{"Authorization": "Bearer secret"}
+
+	addResp := DataResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		DB:             defra,
+		Method:         "POST",
+		Path:           WebhooksPath,
+		Body:           bytes.NewBuffer([]byte(`{"url": "https://example.com/hook", "collection": "user"}`)),
+		Headers:        headers,
+		ExpectedStatus: 200,
+		ResponseData:   &addResp,
+		ServerOptions:  serverOptions{cfg: cfg},
+	})
+	webhook := addResp.Data.(map[string]any)
+	assert.Equal(t, "1", webhook["id"])
+
+	listResp := DataResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		DB:             defra,
+		Method:         "GET",
+		Path:           WebhooksPath,
+		Headers:        headers,
+		ExpectedStatus: 200,
+		ResponseData:   &listResp,
+		ServerOptions:  serverOptions{cfg: cfg},
+	})
+	assert.Equal(t, []any{webhook}, listResp.Data)
+
+	deadLettersResp := DataResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		DB:             defra,
+		Method:         "GET",
+		Path:           WebhooksPath + "/deadletters",
+		Headers:        headers,
+		ExpectedStatus: 200,
+		ResponseData:   &deadLettersResp,
+		ServerOptions:  serverOptions{cfg: cfg},
+	})
+	assert.Equal(t, []any{}, deadLettersResp.Data)
+
+	deleteResp := DataResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		DB:             defra,
+		Method:         "DELETE",
+		Path:           WebhooksPath + "/1",
+		Headers:        headers,
+		ExpectedStatus: 200,
+		ResponseData:   &deleteResp,
+		ServerOptions:  serverOptions{cfg: cfg},
+	})
+
+	errResponse := ErrorResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		DB:             defra,
+		Method:         "DELETE",
+		Path:           WebhooksPath + "/1",
+		Headers:        headers,
+		ExpectedStatus: 404,
+		ResponseData:   &errResponse,
+		ServerOptions:  serverOptions{cfg: cfg},
+	})
+	assert.Equal(t, errors.CodeWebhookNotFound, errResponse.Errors[0].Extensions.Code)
+}
+
+func TestAddWebhookHandlerWithInvalidURL(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+	testLoadSchema(t, ctx, defra)
+
+	cfg := config.DefaultConfig()
+	cfg.API.AdminToken = "secret"
+
+	errResponse := ErrorResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		DB:             defra,
+		Method:         "POST",
+		Path:           WebhooksPath,
+		Body:           bytes.NewBuffer([]byte(`{"url": "example.com", "collection": "user"}`)),
+		Headers:        map[string]string{"Authorization": "Bearer secret"},
+		ExpectedStatus: 400,
+		ResponseData:   &errResponse,
+		ServerOptions:  serverOptions{cfg: cfg},
+	})
+	assert.Equal(t, errors.CodeInvalidRequest, errResponse.Errors[0].Extensions.Code)
+}
+
+func TestListWebhooksHandlerWithoutAdminToken(t *testing.T) {
+	errResponse := ErrorResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		Method:         "GET",
+		Path:           WebhooksPath,
+		ExpectedStatus: 403,
+		ResponseData:   &errResponse,
+		ServerOptions: serverOptions{
+			cfg: config.DefaultConfig(),
+		},
+	})
+
+	assert.Equal(t, ErrAdminDisabled.Error(), errResponse.Errors[0].Message)
+}
+
 func testRequest(opt testOptions) {
 	req, err := http.NewRequest(opt.Method, opt.Path, opt.Body)
 	if err != nil {
@@ -996,7 +1969,7 @@ func testSubscriptionRequest(ctx context.Context, opt testOptions, ch chan []byt
 	ch <- respBody
 }
 
-func testNewInMemoryDB(t *testing.T, ctx context.Context) client.DB {
+func testNewInMemoryDB(t *testing.T, ctx context.Context, dbOptions ...db.Option) client.DB {
 	// init in memory DB
 	opts := badgerds.Options{Options: badger.DefaultOptions("").WithInMemory(true)}
 	rootstore, err := badgerds.NewDatastore("", &opts)
@@ -1007,6 +1980,7 @@ func testNewInMemoryDB(t *testing.T, ctx context.Context) client.DB {
 	options := []db.Option{
 		db.WithUpdateEvents(),
 	}
+	options = append(options, dbOptions...)
 
 	defra, err := db.NewDB(ctx, rootstore, options...)
 	if err != nil {
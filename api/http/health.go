@@ -0,0 +1,127 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package http
+
+import (
+	"context"
+	"net/http"
+
+	ds "github.com/ipfs/go-datastore"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/core"
+)
+
+// Subsystems of the node reported by the readiness probe.
+const (
+	SubsystemAPI       = "api"
+	SubsystemDatastore = "datastore"
+	SubsystemSchema    = "schema"
+	SubsystemP2P       = "p2p"
+)
+
+// Readiness is the response of the readiness probe.
+type Readiness struct {
+	// Ready is true if all the subsystems are ready.
+	Ready bool `json:"ready"`
+	// Subsystems holds the status of the subsystems of the node, by name.
+	Subsystems map[string]SubsystemStatus `json:"subsystems"`
+}
+
+// SubsystemStatus is the readiness status of a subsystem of the node.
+type SubsystemStatus struct {
+	Ready bool `json:"ready"`
+	// Disabled is true if the subsystem is disabled, in which case it is reported as ready.
+	Disabled bool `json:"disabled,omitempty"`
+	// Error is the reason why the subsystem is not ready.
+	Error string `json:"error,omitempty"`
+}
+
+// healthzHandler reports that the process is up, whatever the state of its subsystems.
+func healthzHandler(rw http.ResponseWriter, req *http.Request) {
+	sendJSON(req.Context(), rw, simpleDataResponse("status", "ok"), http.StatusOK)
+}
+
+// readyzHandler reports whether the node is ready to serve requests: the API is not draining, the
+// datastore is open, the schema is loaded and the P2P node, if enabled, has bootstrapped.
+//
+// The response has the 503 status if any subsystem is not ready.
+func (h *handler) readyzHandler(rw http.ResponseWriter, req *http.Request) {
+	readiness := Readiness{
+		Ready: true,
+		Subsystems: map[string]SubsystemStatus{
+			SubsystemAPI: h.apiStatus(),
+			SubsystemP2P: h.p2pStatus(),
+		},
+	}
+
+	db, err := dbFromContext(req.Context())
+	if err != nil {
+		readiness.Subsystems[SubsystemDatastore] = errStatus(req.Context(), err)
+		readiness.Subsystems[SubsystemSchema] = errStatus(req.Context(), err)
+	} else {
+		readiness.Subsystems[SubsystemDatastore] = datastoreStatus(req.Context(), db)
+		readiness.Subsystems[SubsystemSchema] = schemaStatus(req.Context(), db)
+	}
+
+	status := http.StatusOK
+	for _, s := range readiness.Subsystems {
+		if !s.Ready {
+			readiness.Ready = false
+			status = http.StatusServiceUnavailable
+		}
+	}
+	sendJSON(req.Context(), rw, DataResponse{Data: readiness}, status)
+}
+
+// apiStatus returns the status of the API, which is not ready once the server is draining.
+func (h *handler) apiStatus() SubsystemStatus {
+	select {
+	case <-h.draining:
+		return SubsystemStatus{Error: "server is shutting down"}
+	default:
+		return SubsystemStatus{Ready: true}
+	}
+}
+
+// p2pStatus returns the status of the P2P node, which is ready once it has bootstrapped.
+func (h *handler) p2pStatus() SubsystemStatus {
+	if h.options.p2pBootstrapped == nil {
+		return SubsystemStatus{Ready: true, Disabled: true}
+	}
+	if !h.options.p2pBootstrapped() {
+		return SubsystemStatus{Error: "node has not bootstrapped"}
+	}
+	return SubsystemStatus{Ready: true}
+}
+
+// datastoreStatus returns the status of the datastore, which is ready if it can be read from.
+func datastoreStatus(ctx context.Context, db client.DB) SubsystemStatus {
+	if _, err := db.Root().Has(ctx, ds.NewKey(core.COLLECTION)); err != nil {
+		return errStatus(ctx, err)
+	}
+	return SubsystemStatus{Ready: true}
+}
+
+// schemaStatus returns the status of the schema, which is ready if the descriptions of the
+// collections can be loaded.
+func schemaStatus(ctx context.Context, db client.DB) SubsystemStatus {
+	if _, err := db.GetAllCollections(ctx); err != nil {
+		return errStatus(ctx, err)
+	}
+	return SubsystemStatus{Ready: true}
+}
+
+// errStatus returns the status of a subsystem failing with the given error, whose message is
+// redacted according to the error verbosity of the request.
+func errStatus(ctx context.Context, err error) SubsystemStatus {
+	return SubsystemStatus{Error: errorMessage(errorVerbosity(ctx), err)}
+}
@@ -0,0 +1,102 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package http
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthzHandler(t *testing.T) {
+	resp := DataResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		Method:         "GET",
+		Path:           HealthzPath,
+		ExpectedStatus: 200,
+		ResponseData:   &resp,
+	})
+
+	assert.Equal(t, map[string]any{"status": "ok"}, resp.Data)
+}
+
+func TestReadyzHandler(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+
+	resp := struct {
+		Data Readiness `json:"data"`
+	}{}
+	testRequest(testOptions{
+		Testing:        t,
+		DB:             defra,
+		Method:         "GET",
+		Path:           ReadyzPath,
+		ExpectedStatus: 200,
+		ResponseData:   &resp,
+	})
+
+	assert.True(t, resp.Data.Ready)
+	assert.Equal(t, SubsystemStatus{Ready: true}, resp.Data.Subsystems[SubsystemDatastore])
+	assert.Equal(t, SubsystemStatus{Ready: true}, resp.Data.Subsystems[SubsystemSchema])
+	assert.Equal(t, SubsystemStatus{Ready: true, Disabled: true}, resp.Data.Subsystems[SubsystemP2P])
+}
+
+func TestReadyzHandlerWithP2PNotBootstrapped(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+
+	resp := struct {
+		Data Readiness `json:"data"`
+	}{}
+	testRequest(testOptions{
+		Testing:        t,
+		DB:             defra,
+		Method:         "GET",
+		Path:           ReadyzPath,
+		ExpectedStatus: 503,
+		ResponseData:   &resp,
+		ServerOptions: serverOptions{
+			p2pBootstrapped: func() bool { return false },
+		},
+	})
+
+	assert.False(t, resp.Data.Ready)
+	assert.True(t, resp.Data.Subsystems[SubsystemDatastore].Ready)
+	assert.False(t, resp.Data.Subsystems[SubsystemP2P].Ready)
+	assert.Equal(t, "node has not bootstrapped", resp.Data.Subsystems[SubsystemP2P].Error)
+}
+
+func TestReadyzHandlerWithClosedDatastore(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defra.Close(ctx)
+
+	resp := struct {
+		Data Readiness `json:"data"`
+	}{}
+	testRequest(testOptions{
+		Testing:        t,
+		DB:             defra,
+		Method:         "GET",
+		Path:           ReadyzPath,
+		ExpectedStatus: 503,
+		ResponseData:   &resp,
+	})
+
+	assert.False(t, resp.Data.Ready)
+	assert.False(t, resp.Data.Subsystems[SubsystemDatastore].Ready)
+	assert.NotEmpty(t, resp.Data.Subsystems[SubsystemDatastore].Error)
+}
@@ -0,0 +1,379 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package http
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/sourcenetwork/defradb/client"
+)
+
+const (
+	contentTypeCSV = "text/csv"
+
+	defaultImportBatchSize = 100
+)
+
+// importEvent is a line of the newline delimited JSON response of an import.
+//
+// Row errors have a `row` and an `error`, progress reports have the `imported` and `failed`
+// counts, and the final report also has `done` set.
+type importEvent struct {
+	Row      int    `json:"row,omitempty"`
+	Error    string `json:"error,omitempty"`
+	Imported int    `json:"imported"`
+	Failed   int    `json:"failed"`
+	Done     bool   `json:"done,omitempty"`
+}
+
+// importOptions are the options of an import, given as query parameters.
+type importOptions struct {
+	// csv is true if the body is CSV, with a header row, rather than newline delimited JSON.
+	csv bool
+	// batchSize is the number of rows inserted per transaction.
+	batchSize int
+	// fields maps the input field names to collection field names. Fields mapped to an
+	// empty name are ignored.
+	fields map[string]string
+}
+
+// importRow is a row of the input, either decoded or failing to be.
+type importRow struct {
+	number int
+	values map[string]any
+	err    error
+}
+
+func parseImportOptions(req *http.Request) (importOptions, error) {
+	opts := importOptions{
+		batchSize: defaultImportBatchSize,
+		fields:    map[string]string{},
+	}
+	query := req.URL.Query()
+
+	format := query.Get("format")
+	if format == "" {
+		contentType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+		if contentType == contentTypeCSV {
+			format = "csv"
+		}
+	}
+	switch format {
+	case "", "ndjson":
+	case "csv":
+		opts.csv = true
+	default:
+		return importOptions{}, NewErrInvalidImportOption("format", format)
+	}
+
+	if v := query.Get("batch"); v != "" {
+		size, err := strconv.Atoi(v)
+		if err != nil || size <= 0 {
+			return importOptions{}, NewErrInvalidImportOption("batch", v)
+		}
+		opts.batchSize = size
+	}
+
+	if v := query.Get("map"); v != "" {
+		for _, mapping := range strings.Split(v, ",") {
+			from, to, ok := strings.Cut(mapping, ":")
+			if !ok || from == "" {
+				return importOptions{}, NewErrInvalidImportOption("map", mapping)
+			}
+			opts.fields[from] = to
+		}
+	}
+
+	return opts, nil
+}
+
+// importHandler creates documents in a collection from the rows of the request body.
+//
+// Rows are inserted in batches, each batch in its own transaction. The progress is streamed as
+// newline delimited JSON: an event for each failed row, one after each batch, and a final one.
+func importHandler(rw http.ResponseWriter, req *http.Request) {
+	opts, err := parseImportOptions(req)
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusBadRequest)
+		return
+	}
+
+	flusher, ok := rw.(http.Flusher)
+	if !ok {
+		handleErr(req.Context(), rw, ErrStreamingUnsupported, http.StatusInternalServerError)
+		return
+	}
+
+	db, err := dbFromContext(req.Context())
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	col, ok := collectionFromRequest(rw, req)
+	if !ok {
+		return
+	}
+
+	rows := make(chan importRow)
+	ctx, cancel := context.WithCancel(req.Context())
+	defer cancel()
+	go func() {
+		defer close(rows)
+		if opts.csv {
+			readCSVRows(ctx, req.Body, col.Schema(), opts, rows)
+		} else {
+			readJSONRows(ctx, req.Body, opts, rows)
+		}
+	}()
+
+	rw.Header().Set("Content-Type", contentTypeNDJSON)
+	rw.WriteHeader(http.StatusOK)
+
+	encoder := json.NewEncoder(rw)
+	progress := importEvent{}
+	send := func(event importEvent) bool {
+		if err := encoder.Encode(event); err != nil {
+			log.ErrorE(ctx, "Failed to write import progress", err)
+			return false
+		}
+		flusher.Flush()
+		return true
+	}
+
+	batch := make([]importRow, 0, opts.batchSize)
+	flush := func() bool {
+		if len(batch) == 0 {
+			return true
+		}
+		imported, rowErrs := importBatch(ctx, db, col, batch)
+		batch = batch[:0]
+		progress.Imported += imported
+		progress.Failed += len(rowErrs)
+		for _, rowErr := range rowErrs {
+			if !send(rowErr) {
+				return false
+			}
+		}
+		return send(progress)
+	}
+
+	for row := range rows {
+		if row.err != nil {
+			progress.Failed++
+			if !send(importEvent{Row: row.number, Error: row.err.Error()}) {
+				return
+			}
+			continue
+		}
+		batch = append(batch, row)
+		if len(batch) == opts.batchSize && !flush() {
+			return
+		}
+	}
+	if !flush() {
+		return
+	}
+
+	progress.Done = true
+	send(progress)
+}
+
+// importBatch creates the documents of the given rows in a single transaction and returns the
+// number of documents created along with the errors of the rows that failed.
+//
+// If the transaction fails to commit, all the rows of the batch are reported as failed.
+func importBatch(
+	ctx context.Context,
+	db client.DB,
+	col client.Collection,
+	batch []importRow,
+) (int, []importEvent) {
+	failAll := func(err error) (int, []importEvent) {
+		events := make([]importEvent, len(batch))
+		for i, row := range batch {
+			events[i] = importEvent{Row: row.number, Error: err.Error()}
+		}
+		return 0, events
+	}
+
+	txn, err := db.NewTxn(ctx, false)
+	if err != nil {
+		return failAll(err)
+	}
+	defer txn.Discard(ctx)
+
+	txnCol := col.WithTxn(txn)
+	rowErrs := []importEvent{}
+	for _, row := range batch {
+		doc, err := client.NewDocFromMap(row.values)
+		if err == nil {
+			err = txnCol.Create(ctx, doc)
+		}
+		if err != nil {
+			rowErrs = append(rowErrs, importEvent{Row: row.number, Error: err.Error()})
+		}
+	}
+
+	if err := txn.Commit(ctx); err != nil {
+		return failAll(err)
+	}
+	return len(batch) - len(rowErrs), rowErrs
+}
+
+// readJSONRows decodes the newline delimited JSON objects of the given reader into rows.
+func readJSONRows(ctx context.Context, r io.Reader, opts importOptions, rows chan<- importRow) {
+	decoder := json.NewDecoder(r)
+	for number := 1; ; number++ {
+		values := map[string]any{}
+		err := decoder.Decode(&values)
+		if errors.Is(err, io.EOF) {
+			return
+		}
+		if err != nil {
+			// the decoder can't recover from syntax errors, so the rest of the input is lost.
+			sendRow(ctx, rows, importRow{number: number, err: err})
+			return
+		}
+		if !sendRow(ctx, rows, importRow{number: number, values: mapFields(values, opts.fields)}) {
+			return
+		}
+	}
+}
+
+// readCSVRows decodes the records of the given CSV reader into rows, using the header row as
+// field names and the kinds of the schema fields to parse the values.
+func readCSVRows(
+	ctx context.Context,
+	r io.Reader,
+	schema client.SchemaDescription,
+	opts importOptions,
+	rows chan<- importRow,
+) {
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return
+	}
+	if err != nil {
+		sendRow(ctx, rows, importRow{number: 1, err: err})
+		return
+	}
+
+	kinds := map[string]client.FieldKind{}
+	for _, field := range schema.Fields {
+		kinds[field.Name] = field.Kind
+	}
+
+	// row numbers count the header row so that they match the lines of the input.
+	for number := 2; ; number++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return
+		}
+		row := importRow{number: number, err: err}
+		if err == nil {
+			row.values, row.err = parseCSVRecord(header, record, kinds, opts.fields)
+		}
+		if !sendRow(ctx, rows, row) {
+			return
+		}
+	}
+}
+
+func parseCSVRecord(
+	header []string,
+	record []string,
+	kinds map[string]client.FieldKind,
+	fields map[string]string,
+) (map[string]any, error) {
+	values := map[string]any{}
+	for i, cell := range record {
+		if cell == "" {
+			continue
+		}
+		name := header[i]
+		if mapped, ok := fields[name]; ok {
+			name = mapped
+		}
+		if name == "" {
+			continue
+		}
+		value, err := parseCSVValue(cell, kinds[name])
+		if err != nil {
+			return nil, NewErrInvalidImportValue(name, cell, err)
+		}
+		values[name] = value
+	}
+	return values, nil
+}
+
+// parseCSVValue parses the given cell into a value of the given field kind.
+//
+// Arrays are expected to be JSON encoded. Cells of unknown fields are kept as strings.
+func parseCSVValue(cell string, kind client.FieldKind) (any, error) {
+	switch kind {
+	case client.FieldKind_BOOL:
+		return strconv.ParseBool(cell)
+	case client.FieldKind_INT, client.FieldKind_INT32:
+		// documents are built from the types produced by JSON decoding, which include int but not int64.
+		return strconv.Atoi(cell)
+	case client.FieldKind_UINT64:
+		return strconv.ParseUint(cell, 10, 64)
+	case client.FieldKind_FLOAT:
+		return strconv.ParseFloat(cell, 64)
+	case client.FieldKind_BOOL_ARRAY, client.FieldKind_NILLABLE_BOOL_ARRAY,
+		client.FieldKind_INT_ARRAY, client.FieldKind_NILLABLE_INT_ARRAY,
+		client.FieldKind_FLOAT_ARRAY, client.FieldKind_NILLABLE_FLOAT_ARRAY,
+		client.FieldKind_STRING_ARRAY, client.FieldKind_NILLABLE_STRING_ARRAY:
+		var value []any
+		err := json.Unmarshal([]byte(cell), &value)
+		return value, err
+	default:
+		return cell, nil
+	}
+}
+
+// mapFields renames the given values according to the given field mapping.
+func mapFields(values map[string]any, fields map[string]string) map[string]any {
+	if len(fields) == 0 {
+		return values
+	}
+	mapped := make(map[string]any, len(values))
+	for name, value := range values {
+		if to, ok := fields[name]; ok {
+			name = to
+		}
+		if name != "" {
+			mapped[name] = value
+		}
+	}
+	return mapped
+}
+
+// sendRow sends the given row, returning false if the import has been cancelled instead.
+func sendRow(ctx context.Context, rows chan<- importRow, row importRow) bool {
+	select {
+	case rows <- row:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
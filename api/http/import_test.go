@@ -0,0 +1,123 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/client"
+)
+
+func testImportRequest(t *testing.T, defra client.DB, query string, contentType string, body string) []importEvent {
+	req, err := http.NewRequest("POST", CollectionsPath+"/user/import"+query, strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", contentType)
+
+	rec := httptest.NewRecorder()
+	newHandler(defra, serverOptions{}).ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	events := []importEvent{}
+	decoder := json.NewDecoder(rec.Body)
+	for decoder.More() {
+		event := importEvent{}
+		require.NoError(t, decoder.Decode(&event))
+		events = append(events, event)
+	}
+	return events
+}
+
+func testCountUsers(t *testing.T, ctx context.Context, defra client.DB, filter any) uint64 {
+	col, err := defra.GetCollectionByName(ctx, "user")
+	require.NoError(t, err)
+	count, err := col.Count(ctx, filter)
+	require.NoError(t, err)
+	return count
+}
+
+func TestImportHandlerWithNDJSON(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+
+	testLoadSchema(t, ctx, defra)
+
+	body := `{"fullname": "Bob", "age": 31}
+{"fullname": "Alice", "age": 22, "ignored": true}
+{"fullname": "Bob", "age": 31}
+{"fullname": "John", "age": 40}
+`
+	events := testImportRequest(t, defra, "?batch=2&map=fullname:name,ignored:", contentTypeNDJSON, body)
+
+	require.Len(t, events, 4)
+	// the duplicate document fails to be created in the second batch.
+	assert.Equal(t, importEvent{Imported: 2}, events[0])
+	assert.Equal(t, 3, events[1].Row)
+	assert.Contains(t, events[1].Error, "a document with the given dockey already exists")
+	assert.Equal(t, importEvent{Imported: 3, Failed: 1}, events[2])
+	assert.Equal(t, importEvent{Imported: 3, Failed: 1, Done: true}, events[3])
+
+	assert.Equal(t, uint64(3), testCountUsers(t, ctx, defra, nil))
+	assert.Equal(t, uint64(1), testCountUsers(t, ctx, defra, `{name: {_eq: "Alice"}}`))
+}
+
+func TestImportHandlerWithCSV(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+
+	testLoadSchema(t, ctx, defra)
+
+	body := `name,years,verified,points
+Bob,31,true,4.5
+Alice,twenty,false,
+John,40,,1
+`
+	events := testImportRequest(t, defra, "?map=years:age", "text/csv", body)
+
+	require.Len(t, events, 3)
+	assert.Equal(t, 3, events[0].Row)
+	assert.Contains(t, events[0].Error, "invalid import value")
+	assert.Equal(t, importEvent{Imported: 2, Failed: 1}, events[1])
+	assert.Equal(t, importEvent{Imported: 2, Failed: 1, Done: true}, events[2])
+
+	assert.Equal(t, uint64(2), testCountUsers(t, ctx, defra, nil))
+	assert.Equal(t, uint64(1), testCountUsers(t, ctx, defra, `{age: {_eq: 31}, verified: {_eq: true}, points: {_eq: 4.5}}`))
+}
+
+func TestImportHandlerWithInvalidOption(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+
+	testLoadSchema(t, ctx, defra)
+
+	errResponse := ErrorResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		DB:             defra,
+		Method:         "POST",
+		Path:           CollectionsPath + "/user/import?batch=0",
+		Body:           strings.NewReader(`{"name": "Bob"}`),
+		ExpectedStatus: 400,
+		ResponseData:   &errResponse,
+	})
+
+	assert.Contains(t, errResponse.Errors[0].Message, "invalid import option")
+}
@@ -0,0 +1,79 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/pkg/errors"
+
+	"github.com/sourcenetwork/defradb/client"
+)
+
+const defaultMigrationBatchSize = 100
+
+// migrateRelationsEvent is a line of the newline delimited JSON response of a relation migration.
+type migrateRelationsEvent struct {
+	client.MigrateRelationsProgress
+	Error string `json:"error,omitempty"`
+}
+
+// migrateRelationsHandler migrates the relation ID fields of the documents to the current schema.
+//
+// The options are given as a JSON body, which may be empty. The progress is streamed as newline
+// delimited JSON: an event after each batch, and a final one that is either `done` or has an
+// `error`.
+func migrateRelationsHandler(rw http.ResponseWriter, req *http.Request) {
+	opts := client.MigrateRelationsOptions{}
+	if err := getJSON(req, &opts); err != nil && !errors.Is(err, io.EOF) {
+		handleErr(req.Context(), rw, err, http.StatusBadRequest)
+		return
+	}
+	if opts.BatchSize == 0 {
+		opts.BatchSize = defaultMigrationBatchSize
+	}
+
+	flusher, ok := rw.(http.Flusher)
+	if !ok {
+		handleErr(req.Context(), rw, ErrStreamingUnsupported, http.StatusInternalServerError)
+		return
+	}
+
+	db, err := dbFromContext(req.Context())
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	progress, err := db.MigrateRelations(req.Context(), opts)
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusBadRequest)
+		return
+	}
+
+	rw.Header().Set("Content-Type", contentTypeNDJSON)
+	rw.WriteHeader(http.StatusOK)
+
+	encoder := json.NewEncoder(rw)
+	for report := range progress {
+		event := migrateRelationsEvent{MigrateRelationsProgress: report}
+		if report.Err != nil {
+			event.Error = report.Err.Error()
+		}
+		if err := encoder.Encode(event); err != nil {
+			log.ErrorE(req.Context(), "Failed to write migration progress", err)
+			return
+		}
+		flusher.Flush()
+	}
+}
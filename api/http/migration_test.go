@@ -0,0 +1,84 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/config"
+)
+
+func TestMigrateRelationsHandler(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+	testLoadSchema(t, ctx, defra)
+
+	cfg := config.DefaultConfig()
+	cfg.API.AdminToken = "secret"
+
+	body := bytes.NewBufferString(`{"collections": ["user"]}`)
+	req, err := http.NewRequest(http.MethodPost, MigratePath+"/relations", body)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+	newHandler(defra, serverOptions{cfg: cfg}).ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, contentTypeNDJSON, rec.Header().Get("Content-Type"))
+
+	event := migrateRelationsEvent{}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&event))
+	assert.True(t, event.Done)
+	assert.Empty(t, event.Error)
+}
+
+func TestMigrateRelationsHandlerWithUnknownCollection(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+
+	cfg := config.DefaultConfig()
+	cfg.API.AdminToken = "secret"
+
+	body := bytes.NewBufferString(`{"collections": ["Unknown"]}`)
+	req, err := http.NewRequest(http.MethodPost, MigratePath+"/relations", body)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+	newHandler(defra, serverOptions{cfg: cfg}).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestMigrateRelationsHandlerWithoutAdminToken(t *testing.T) {
+	errResponse := ErrorResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		Method:         "POST",
+		Path:           MigratePath + "/relations",
+		ExpectedStatus: 403,
+		ResponseData:   &errResponse,
+		ServerOptions: serverOptions{
+			cfg: config.DefaultConfig(),
+		},
+	})
+
+	assert.Equal(t, ErrAdminDisabled.Error(), errResponse.Errors[0].Message)
+}
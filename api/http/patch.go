@@ -0,0 +1,83 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package http
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/pkg/errors"
+
+	"github.com/sourcenetwork/defradb/client"
+)
+
+const (
+	contentTypeJSONPatch  = "application/json-patch+json"
+	contentTypeMergePatch = "application/merge-patch+json"
+)
+
+// patchDocumentHandler applies the patch given as body to a document, and returns the patched
+// document.
+//
+// The patch is either a JSON Patch (RFC 6902) or a JSON Merge Patch (RFC 7386), as set by the
+// content type of the request. The operations of a JSON Patch are applied to the JSON
+// representation of the document, and the resulting changes are written as field updates.
+func patchDocumentHandler(rw http.ResponseWriter, req *http.Request) {
+	contentType, _, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil || (contentType != contentTypeJSONPatch && contentType != contentTypeMergePatch) {
+		handleErr(
+			req.Context(),
+			rw,
+			NewErrUnsupportedPatchType(req.Header.Get("Content-Type")),
+			http.StatusUnsupportedMediaType,
+		)
+		return
+	}
+
+	updateDocument(rw, req, func(doc *client.Document, body []byte) ([]byte, error) {
+		if contentType == contentTypeJSONPatch {
+			return jsonPatchToMergePatch(doc, body)
+		}
+		return validateMergePatch(body)
+	})
+}
+
+// validateMergePatch returns the given JSON Merge Patch if it is a JSON object, as the updater
+// of a document would otherwise be taken as a patch of another format.
+func validateMergePatch(body []byte) ([]byte, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil, errors.Wrap(err, "invalid merge patch")
+	}
+	return body, nil
+}
+
+// jsonPatchToMergePatch applies the given JSON Patch to the JSON representation of the given
+// document, and returns the changes it made as a JSON Merge Patch.
+func jsonPatchToMergePatch(doc *client.Document, body []byte) ([]byte, error) {
+	patch, err := jsonpatch.DecodePatch(body)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid JSON patch")
+	}
+
+	original, err := documentJSON(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	patched, err := patch.Apply(original)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to apply JSON patch")
+	}
+	return jsonpatch.CreateMergePatch(original, patched)
+}
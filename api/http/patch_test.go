@@ -0,0 +1,161 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package http
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/errors"
+)
+
+func testCreatePatchedDoc(t *testing.T, ctx context.Context, defra client.DB) *client.Document {
+	col, err := defra.GetCollectionByName(ctx, "user")
+	require.NoError(t, err)
+	doc, err := client.NewDocFromJSON([]byte(`{"name": "Bob", "age": 31, "verified": true}`))
+	require.NoError(t, err)
+	require.NoError(t, col.Create(ctx, doc))
+	return doc
+}
+
+func TestPatchDocumentHandlerWithMergePatch(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+	testLoadSchema(t, ctx, defra)
+	doc := testCreatePatchedDoc(t, ctx, defra)
+
+	resp := DataResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		DB:             defra,
+		Method:         "PATCH",
+		Path:           CollectionsPath + "/user/" + doc.Key().String(),
+		Body:           bytes.NewBufferString(`{"age": 32, "verified": null}`),
+		Headers:        map[string]string{"Content-Type": contentTypeMergePatch},
+		ExpectedStatus: 200,
+		ResponseData:   &resp,
+	})
+
+	assert.Equal(
+		t,
+		map[string]any{"_key": doc.Key().String(), "name": "Bob", "age": float64(32), "verified": nil},
+		resp.Data,
+	)
+}
+
+func TestPatchDocumentHandlerWithJSONPatch(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+	testLoadSchema(t, ctx, defra)
+	doc := testCreatePatchedDoc(t, ctx, defra)
+
+	resp := DataResponse{}
+	testRequest(testOptions{
+		Testing: t,
+		DB:      defra,
+		Method:  "PATCH",
+		Path:    CollectionsPath + "/user/" + doc.Key().String(),
+		Body: bytes.NewBufferString(`[
+			{"op": "test", "path": "/name", "value": "Bob"},
+			{"op": "replace", "path": "/name", "value": "Alice"},
+			{"op": "remove", "path": "/verified"}
+		]`),
+		Headers:        map[string]string{"Content-Type": contentTypeJSONPatch},
+		ExpectedStatus: 200,
+		ResponseData:   &resp,
+	})
+
+	assert.Equal(
+		t,
+		map[string]any{"_key": doc.Key().String(), "name": "Alice", "age": float64(31), "verified": nil},
+		resp.Data,
+	)
+}
+
+func TestPatchDocumentHandlerWithFailedJSONPatchTest(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+	testLoadSchema(t, ctx, defra)
+	doc := testCreatePatchedDoc(t, ctx, defra)
+
+	errResponse := ErrorResponse{}
+	testRequest(testOptions{
+		Testing: t,
+		DB:      defra,
+		Method:  "PATCH",
+		Path:    CollectionsPath + "/user/" + doc.Key().String(),
+		Body: bytes.NewBufferString(`[
+			{"op": "test", "path": "/name", "value": "Alice"},
+			{"op": "replace", "path": "/age", "value": 40}
+		]`),
+		Headers:        map[string]string{"Content-Type": contentTypeJSONPatch},
+		ExpectedStatus: 400,
+		ResponseData:   &errResponse,
+	})
+
+	col, err := defra.GetCollectionByName(ctx, "user")
+	require.NoError(t, err)
+	stored, err := col.Get(ctx, doc.Key(), false)
+	require.NoError(t, err)
+	age, err := stored.Get("age")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(31), age)
+}
+
+func TestPatchDocumentHandlerWithUnsupportedContentType(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+	testLoadSchema(t, ctx, defra)
+	doc := testCreatePatchedDoc(t, ctx, defra)
+
+	errResponse := ErrorResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		DB:             defra,
+		Method:         "PATCH",
+		Path:           CollectionsPath + "/user/" + doc.Key().String(),
+		Body:           bytes.NewBufferString(`{"age": 32}`),
+		Headers:        map[string]string{"Content-Type": contentTypeJSON},
+		ExpectedStatus: 415,
+		ResponseData:   &errResponse,
+	})
+
+	assert.Contains(t, errResponse.Errors[0].Message, errUnsupportedPatch)
+	assert.Equal(t, errors.CodeInvalidRequest, errResponse.Errors[0].Extensions.Code)
+}
+
+func TestPatchDocumentHandlerWithUnknownDocument(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+	testLoadSchema(t, ctx, defra)
+
+	errResponse := ErrorResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		DB:             defra,
+		Method:         "PATCH",
+		Path:           CollectionsPath + "/user/bae-52b9170d-b77a-5887-b877-cbdbb99b009f",
+		Body:           bytes.NewBufferString(`{"age": 32}`),
+		Headers:        map[string]string{"Content-Type": contentTypeMergePatch},
+		ExpectedStatus: 404,
+		ResponseData:   &errResponse,
+	})
+}
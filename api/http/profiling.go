@@ -0,0 +1,153 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"net/http"
+	"net/http/pprof"
+	runtimePprof "runtime/pprof"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// otherSubsystem is the subsystem of the goroutines not running the code of a known subsystem.
+const otherSubsystem = "other"
+
+// subsystemPrefixes are the prefixes of the functions run by the goroutines of each subsystem.
+var subsystemPrefixes = []struct {
+	subsystem string
+	prefixes  []string
+}{
+	{"badger", []string{"github.com/dgraph-io/badger", "github.com/dgraph-io/ristretto"}},
+	{"planner", []string{"github.com/sourcenetwork/defradb/planner"}},
+	{"p2p", []string{
+		"github.com/sourcenetwork/defradb/net.",
+		"github.com/sourcenetwork/defradb/net/",
+		"github.com/libp2p/",
+		"github.com/ipfs/go-bitswap",
+		"github.com/textileio/",
+	}},
+	{"http", []string{"github.com/sourcenetwork/defradb/api/http", "github.com/go-chi/", "net/http."}},
+}
+
+// goroutineSummary is the number of goroutines, by subsystem and by state.
+type goroutineSummary struct {
+	Total      int            `json:"total"`
+	Subsystems map[string]int `json:"subsystems"`
+	States     map[string]int `json:"states"`
+}
+
+// requireProfiling only calls f if profiling is enabled and the request carries the configured
+// admin token.
+func (h *handler) requireProfiling(f http.HandlerFunc) http.HandlerFunc {
+	return h.requireAdmin(func(rw http.ResponseWriter, req *http.Request) {
+		if !h.options.cfg.API.Profiling {
+			handleErr(req.Context(), rw, ErrProfilingDisabled, http.StatusForbidden)
+			return
+		}
+		f(rw, req)
+	})
+}
+
+// pprofHandler serves the profile named by the request path, such as the heap or goroutine
+// profiles, in the format expected by `go tool pprof`.
+func pprofHandler(rw http.ResponseWriter, req *http.Request) {
+	switch profile := chi.URLParam(req, "profile"); profile {
+	case "cmdline":
+		pprof.Cmdline(rw, req)
+	case "profile":
+		pprof.Profile(rw, req)
+	case "symbol":
+		pprof.Symbol(rw, req)
+	case "trace":
+		pprof.Trace(rw, req)
+	default:
+		pprof.Handler(profile).ServeHTTP(rw, req)
+	}
+}
+
+// goroutinesHandler responds with the number of running goroutines, by subsystem and by state.
+func goroutinesHandler(rw http.ResponseWriter, req *http.Request) {
+	var dump bytes.Buffer
+	err := runtimePprof.Lookup("goroutine").WriteTo(&dump, 2)
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	sendJSON(
+		req.Context(),
+		rw,
+		DataResponse{
+			Data: summarizeGoroutines(dump.Bytes()),
+		},
+		http.StatusOK,
+	)
+}
+
+// summarizeGoroutines counts the goroutines of the given dump, in the format of the stack traces
+// of panics, by subsystem and by state.
+func summarizeGoroutines(dump []byte) goroutineSummary {
+	summary := goroutineSummary{
+		Subsystems: map[string]int{},
+		States:     map[string]int{},
+	}
+
+	var functions []string
+	addGoroutine := func() {
+		if functions == nil {
+			return
+		}
+		summary.Total++
+		summary.Subsystems[goroutineSubsystem(functions)]++
+		functions = nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(dump))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "goroutine "):
+			addGoroutine()
+			functions = []string{}
+			// The header is of the form `goroutine 1 [state, 2 minutes]:`.
+			start, end := strings.Index(line, "["), strings.LastIndex(line, "]")
+			if start >= 0 && end > start {
+				state, _, _ := strings.Cut(line[start+1:end], ",")
+				summary.States[state]++
+			}
+		case functions != nil && line != "" && !strings.HasPrefix(line, "\t"):
+			functions = append(functions, strings.TrimPrefix(line, "created by "))
+		}
+	}
+	addGoroutine()
+
+	return summary
+}
+
+// goroutineSubsystem returns the subsystem of the innermost function of the given stack that
+// belongs to a known subsystem, so that the goroutines serving requests are labelled by the
+// subsystem they are waiting on.
+func goroutineSubsystem(functions []string) string {
+	for _, function := range functions {
+		for _, s := range subsystemPrefixes {
+			for _, prefix := range s.prefixes {
+				if strings.HasPrefix(function, prefix) {
+					return s.subsystem
+				}
+			}
+		}
+	}
+	return otherSubsystem
+}
@@ -0,0 +1,151 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/config"
+)
+
+func TestSummarizeGoroutines(t *testing.T) {
+	dump := `goroutine 1 [running]:
+main.main()
+	/src/main.go:10 +0x1d
+
+goroutine 7 [select, 2 minutes]:
+github.com/dgraph-io/badger/v3.(*DB).updateSize(0xc000123)
+	/go/pkg/mod/github.com/dgraph-io/badger/v3/db.go:1171 +0x13e
+created by github.com/dgraph-io/badger/v3.Open
+	/go/pkg/mod/github.com/dgraph-io/badger/v3/db.go:331 +0x1b8b
+
+goroutine 21 [IO wait]:
+internal/poll.(*FD).Read(0xc000456)
+	/usr/local/go/src/internal/poll/fd_unix.go:167 +0x299
+net/http.(*connReader).backgroundRead(0xc000789)
+	/usr/local/go/src/net/http/server.go:674 +0x3f
+created by net/http.(*connReader).startBackgroundRead
+	/usr/local/go/src/net/http/server.go:670 +0xca
+
+goroutine 35 [chan receive]:
+github.com/sourcenetwork/defradb/planner.(*Planner).executeRequest(0xc000abc)
+	/src/planner/planner.go:542 +0x5a
+github.com/sourcenetwork/defradb/api/http.execGQLHandler(0xc000def)
+	/src/api/http/handlerfuncs.go:120 +0x85
+
+goroutine 40 [select]:
+github.com/libp2p/go-libp2p-pubsub.(*PubSub).processLoop(0xc000fed)
+	/go/pkg/mod/github.com/libp2p/go-libp2p-pubsub/pubsub.go:588 +0x1a5
+`
+	assert.Equal(
+		t,
+		goroutineSummary{
+			Total: 5,
+			Subsystems: map[string]int{
+				"other":   1,
+				"badger":  1,
+				"http":    1,
+				"planner": 1,
+				"p2p":     1,
+			},
+			States: map[string]int{
+				"running":      1,
+				"select":       2,
+				"IO wait":      1,
+				"chan receive": 1,
+			},
+		},
+		summarizeGoroutines([]byte(dump)),
+	)
+}
+
+func TestGoroutinesHandler(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.API.AdminToken = "secret"
+	cfg.API.Profiling = true
+
+	resp := DataResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		Method:         "GET",
+		Path:           GoroutinesPath,
+		Headers:        map[string]string{"Authorization": "Bearer secret"},
+		ExpectedStatus: 200,
+		ResponseData:   &resp,
+		ServerOptions:  serverOptions{cfg: cfg},
+	})
+
+	summary, ok := resp.Data.(map[string]any)
+	require.True(t, ok)
+	assert.Greater(t, summary["total"], float64(0))
+	assert.NotEmpty(t, summary["subsystems"])
+}
+
+func TestGoroutinesHandlerWithProfilingDisabled(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.API.AdminToken = "secret"
+
+	errResponse := ErrorResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		Method:         "GET",
+		Path:           GoroutinesPath,
+		Headers:        map[string]string{"Authorization": "Bearer secret"},
+		ExpectedStatus: 403,
+		ResponseData:   &errResponse,
+		ServerOptions:  serverOptions{cfg: cfg},
+	})
+
+	assert.Equal(t, ErrProfilingDisabled.Error(), errResponse.Errors[0].Message)
+}
+
+func TestPprofHandlerWithoutAdminToken(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.API.Profiling = true
+
+	errResponse := ErrorResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		Method:         "GET",
+		Path:           PprofPath + "/heap",
+		ExpectedStatus: 403,
+		ResponseData:   &errResponse,
+		ServerOptions:  serverOptions{cfg: cfg},
+	})
+
+	assert.Equal(t, ErrAdminDisabled.Error(), errResponse.Errors[0].Message)
+}
+
+func TestPprofHandlerWithHeapProfile(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+
+	cfg := config.DefaultConfig()
+	cfg.API.AdminToken = "secret"
+	cfg.API.Profiling = true
+
+	req, err := http.NewRequest(http.MethodGet, PprofPath+"/heap", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+	newHandler(defra, serverOptions{cfg: cfg}).ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/octet-stream", rec.Header().Get("Content-Type"))
+	assert.NotZero(t, rec.Body.Len())
+}
@@ -0,0 +1,72 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package http
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// rateLimiter is a token bucket allowing up to `limit` requests per second.
+//
+// The limit can be changed at any time. A limit of zero disables rate limiting.
+type rateLimiter struct {
+	mu     sync.Mutex
+	limit  int
+	tokens float64
+	last   time.Time
+}
+
+// setLimit sets the maximum number of requests per second.
+func (l *rateLimiter) setLimit(limit int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.limit = limit
+	l.tokens = float64(limit)
+	l.last = time.Now()
+}
+
+// allow returns true if a request can be served now, consuming a token if so.
+func (l *rateLimiter) allow() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.limit <= 0 {
+		return true
+	}
+
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * float64(l.limit)
+	if l.tokens > float64(l.limit) {
+		l.tokens = float64(l.limit)
+	}
+	l.last = now
+
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
+
+func (l *rateLimiter) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		// the probes are not limited, so that a busy node is not restarted by its orchestrator.
+		if req.URL.Path != HealthzPath && req.URL.Path != ReadyzPath && !l.allow() {
+			rw.Header().Set("Retry-After", "1")
+			handleErr(req.Context(), rw, ErrTooManyRequests, http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(rw, req)
+	})
+}
@@ -0,0 +1,53 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiterWithNoLimit(t *testing.T) {
+	l := &rateLimiter{}
+	for i := 0; i < 100; i++ {
+		assert.True(t, l.allow())
+	}
+}
+
+func TestRateLimiterWithLimit(t *testing.T) {
+	l := &rateLimiter{}
+	l.setLimit(2)
+	assert.True(t, l.allow())
+	assert.True(t, l.allow())
+	assert.False(t, l.allow())
+}
+
+func TestRateLimiterMiddleware(t *testing.T) {
+	h := newHandler(nil, serverOptions{})
+	h.limiter.setLimit(1)
+
+	req, err := http.NewRequest("GET", PingPath, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Result().StatusCode)
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Result().StatusCode)
+	assert.Equal(t, "1", rec.Result().Header.Get("Retry-After"))
+}
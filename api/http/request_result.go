@@ -10,22 +10,59 @@
 
 package http
 
-import "github.com/sourcenetwork/defradb/client"
+import (
+	"context"
+	"fmt"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/config"
+	"github.com/sourcenetwork/defradb/errors"
+	"github.com/sourcenetwork/defradb/logging"
+)
 
 type GQLResult struct {
-	Errors []string `json:"errors,omitempty"`
+	Errors []GQLError `json:"errors,omitempty"`
 
 	Data any `json:"data"`
 }
 
-func newGQLResult(r client.GQLResult) *GQLResult {
-	errors := make([]string, len(r.Errors))
+// GQLError is an error of a GQL result, with the code of the error and the trace ID of the request
+// in its extensions.
+type GQLError struct {
+	Message    string             `json:"message"`
+	Extensions gqlErrorExtensions `json:"extensions"`
+}
+
+type gqlErrorExtensions struct {
+	Code    errors.Code `json:"code,omitempty"`
+	TraceID string      `json:"traceID,omitempty"`
+}
+
+// newGQLResult returns the GQL result of the request with the given context, its errors holding
+// the detail allowed by the error verbosity of the request.
+func newGQLResult(ctx context.Context, r client.GQLResult) *GQLResult {
+	verbosity := errorVerbosity(ctx)
+	errs := make([]GQLError, len(r.Errors))
 	for i := range r.Errors {
-		errors[i] = r.Errors[i].Error()
+		if verbosity != config.ErrorVerbosityStack {
+			log.Debug(
+				ctx,
+				"GQL request error",
+				logging.NewKV("Error", r.Errors[i].Error()),
+				logging.NewKV("Stack", fmt.Sprintf("%+v", r.Errors[i])),
+			)
+		}
+		errs[i] = GQLError{
+			Message: errorMessage(verbosity, r.Errors[i]),
+			Extensions: gqlErrorExtensions{
+				Code:    errorCode(verbosity, r.Errors[i]),
+				TraceID: logging.TraceIDFromContext(ctx),
+			},
+		}
 	}
 
 	return &GQLResult{
-		Errors: errors,
+		Errors: errs,
 		Data:   r.Data,
 	}
 }
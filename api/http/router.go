@@ -11,12 +11,12 @@
 package http
 
 import (
+	"net/http/pprof"
 	"net/url"
 	"path"
 	"strings"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/cors"
 	"github.com/pkg/errors"
 )
 
@@ -27,40 +27,109 @@ const (
 
 	RootPath        string = versionedAPIPath + ""
 	PingPath        string = versionedAPIPath + "/ping"
+	HealthzPath     string = versionedAPIPath + "/healthz"
+	ReadyzPath      string = versionedAPIPath + "/readyz"
+	StatusPath      string = versionedAPIPath + "/status"
+	MetricsPath     string = versionedAPIPath + "/metrics"
+	VerifyPath      string = versionedAPIPath + "/verify"
 	DumpPath        string = versionedAPIPath + "/debug/dump"
+	PprofPath       string = versionedAPIPath + "/debug/pprof"
+	GoroutinesPath  string = versionedAPIPath + "/debug/goroutines"
 	BlocksPath      string = versionedAPIPath + "/blocks"
 	GraphQLPath     string = versionedAPIPath + "/graphql"
+	SQLPath         string = versionedAPIPath + "/sql"
 	SchemaLoadPath  string = versionedAPIPath + "/schema/load"
 	SchemaPatchPath string = versionedAPIPath + "/schema/patch"
 	PeerIDPath      string = versionedAPIPath + "/peerid"
+	ConfigPath      string = versionedAPIPath + "/config"
+	QueriesPath     string = versionedAPIPath + "/queries"
+	TxnsPath        string = versionedAPIPath + "/txns"
+	CollectionsPath string = versionedAPIPath + "/collections"
+	WebhooksPath    string = versionedAPIPath + "/webhooks"
+	SnapshotPath    string = versionedAPIPath + "/snapshot"
+	MigratePath     string = versionedAPIPath + "/migrate"
+	BranchesPath    string = versionedAPIPath + "/branches"
 )
 
 func setRoutes(h *handler) *handler {
 	h.Mux = chi.NewRouter()
 
 	// setup CORS
-	if len(h.options.allowedOrigins) != 0 {
-		h.Use(cors.Handler(cors.Options{
-			AllowedOrigins: h.options.allowedOrigins,
-			AllowedMethods: []string{"GET", "POST", "OPTIONS"},
-			AllowedHeaders: []string{"Content-Type"},
-			MaxAge:         300,
-		}))
-	}
+	h.Use(h.corsMiddleware)
+
+	// setup trace ID middleware, before the logger middleware so that the request is logged with it
+	h.Use(traceMiddleware)
+
+	// setup error verbosity middleware, before any error can be returned
+	h.Use(h.errorVerbosityMiddleware)
 
 	// setup logger middleware
 	h.Use(loggerMiddleware)
 
+	// setup rate limiting
+	h.Use(h.limiter.middleware)
+
 	// define routes
 	h.Get(RootPath, h.handle(rootHandler))
 	h.Get(PingPath, h.handle(pingHandler))
+	h.Get(HealthzPath, h.handle(healthzHandler))
+	h.Get(ReadyzPath, h.handle(h.readyzHandler))
+	h.Get(StatusPath, h.handle(statusHandler))
 	h.Get(DumpPath, h.handle(dumpHandler))
+	h.Get(DumpPath+"/data", h.handle(h.requireAdmin(dumpDataHandler)))
 	h.Get(BlocksPath+"/{cid}", h.handle(getBlockHandler))
 	h.Get(GraphQLPath, h.handle(execGQLHandler))
 	h.Post(GraphQLPath, h.handle(execGQLHandler))
+	h.Get(SQLPath, h.handle(execSQLHandler))
+	h.Post(SQLPath, h.handle(execSQLHandler))
 	h.Post(SchemaLoadPath, h.handle(loadSchemaHandler))
 	h.Post(SchemaPatchPath, h.handle(patchSchemaHandler))
 	h.Get(PeerIDPath, h.handle(peerIDHandler))
+	h.Get(CollectionsPath+"/stats", h.handle(collectionsStatsHandler))
+	h.Get(CollectionsPath+"/{name}/stats", h.handle(collectionStatsHandler))
+	h.Get(CollectionsPath+"/{name}/count", h.handle(countHandler))
+	h.Get(CollectionsPath+"/{name}/dockeys", h.handle(docKeysHandler))
+	h.Post(CollectionsPath+"/{name}/dockey", h.handle(docKeyPreviewHandler))
+	h.Post(CollectionsPath+"/{name}/import", h.handle(importHandler))
+	h.Get(CollectionsPath+"/{name}/export", h.handle(exportHandler))
+	h.Get(CollectionsPath+"/{name}/proof/{dockey}", h.handle(proofHandler))
+	h.Post(CollectionsPath+"/{name}/merge", h.handle(mergeBlocksHandler))
+	h.Post(CollectionsPath+"/{name}/dataset", h.handle(publishDatasetHandler))
+	h.Post(CollectionsPath+"/{name}/dataset/{cid}", h.handle(h.importDatasetHandler))
+	h.Post(CollectionsPath+"/{name}/clone", h.handle(cloneCollectionHandler))
+	h.Get(CollectionsPath+"/{name}", h.handle(listDocumentsHandler))
+	h.Post(CollectionsPath+"/{name}", h.handle(createDocumentHandler))
+	h.Get(CollectionsPath+"/{name}/{dockey}", h.handle(getDocumentHandler))
+	h.Put(CollectionsPath+"/{name}/{dockey}", h.handle(replaceDocumentHandler))
+	h.Patch(CollectionsPath+"/{name}/{dockey}", h.handle(patchDocumentHandler))
+	h.Delete(CollectionsPath+"/{name}/{dockey}", h.handle(deleteDocumentHandler))
+	h.Get(QueriesPath, h.handle(h.requireAdmin(listQueriesHandler)))
+	h.Delete(QueriesPath+"/{id}", h.handle(h.requireAdmin(cancelQueryHandler)))
+	h.Get(TxnsPath+"/stats", h.handle(txnStatsHandler))
+	h.Get(MetricsPath, h.handle(metricsHandler))
+	h.Get(ConfigPath, h.handle(h.requireAdmin(h.getConfigHandler)))
+	h.Put(ConfigPath, h.handle(h.requireAdmin(h.updateConfigHandler)))
+	h.Get(WebhooksPath, h.handle(h.requireAdmin(listWebhooksHandler)))
+	h.Post(WebhooksPath, h.handle(h.requireAdmin(addWebhookHandler)))
+	h.Get(WebhooksPath+"/deadletters", h.handle(h.requireAdmin(webhookDeadLettersHandler)))
+	h.Delete(WebhooksPath+"/{id}", h.handle(h.requireAdmin(deleteWebhookHandler)))
+	h.Get(SnapshotPath, h.handle(h.requireAdmin(snapshotHandler)))
+	h.Post(SnapshotPath, h.handle(h.requireAdmin(incrementalSnapshotHandler)))
+	h.Post(MigratePath+"/relations", h.handle(h.requireAdmin(migrateRelationsHandler)))
+	h.Post(VerifyPath, h.handle(h.requireAdmin(h.verifyBlocksHandler)))
+	h.Post(StatusPath+"/integrity", h.handle(h.requireAdmin(checkIntegrityHandler)))
+	h.Get(BranchesPath, h.handle(h.requireAdmin(listBranchesHandler)))
+	h.Post(BranchesPath, h.handle(h.requireAdmin(createBranchHandler)))
+	h.Get(BranchesPath+"/{name}/diff", h.handle(h.requireAdmin(diffBranchHandler)))
+	h.Delete(BranchesPath+"/{name}", h.handle(h.requireAdmin(discardBranchHandler)))
+	h.Get(BranchesPath+"/{name}/graphql", h.handle(h.requireAdmin(onBranch(execGQLHandler))))
+	h.Post(BranchesPath+"/{name}/graphql", h.handle(h.requireAdmin(onBranch(execGQLHandler))))
+	h.Post(BranchesPath+"/{name}/schema/load", h.handle(h.requireAdmin(onBranch(loadSchemaHandler))))
+	h.Post(BranchesPath+"/{name}/schema/patch", h.handle(h.requireAdmin(onBranch(patchSchemaHandler))))
+	h.Get(PprofPath, h.handle(h.requireProfiling(pprof.Index)))
+	h.Get(PprofPath+"/{profile}", h.handle(h.requireProfiling(pprofHandler)))
+	h.Post(PprofPath+"/symbol", h.handle(h.requireProfiling(pprof.Symbol)))
+	h.Get(GoroutinesPath, h.handle(h.requireProfiling(goroutinesHandler)))
 
 	return h
 }
@@ -18,7 +18,9 @@ import (
 	"net/http"
 	"path"
 	"strings"
+	"sync"
 
+	ipld "github.com/ipfs/go-ipld-format"
 	"github.com/sourcenetwork/immutable"
 	"golang.org/x/crypto/acme/autocert"
 
@@ -53,6 +55,15 @@ type Server struct {
 	listener    net.Listener
 	certManager *autocert.Manager
 
+	// closed when the server starts shutting down so that long lived
+	// requests (i.e. subscriptions) can flush and return.
+	draining  chan struct{}
+	drainOnce sync.Once
+	// cancels the base context of all requests served by this server.
+	cancelRequests context.CancelFunc
+
+	handler *handler
+
 	http.Server
 }
 
@@ -67,6 +78,14 @@ type serverOptions struct {
 	rootDir string
 	// The domain for the API (optional).
 	domain immutable.Option[string]
+	// node configuration, exposed through the admin endpoints.
+	cfg *config.Config
+	// called after the configuration has been updated through the API.
+	onConfigUpdate func(context.Context)
+	// fetches the blocks to repair from the peers of the node.
+	blockFetcher ipld.NodeGetter
+	// reports whether the P2P node has bootstrapped, nil if P2P is disabled.
+	p2pBootstrapped func() bool
 }
 
 type tlsOptions struct {
@@ -82,11 +101,17 @@ type tlsOptions struct {
 
 // NewServer instantiates a new server with the given http.Handler.
 func NewServer(db client.DB, options ...func(*Server)) *Server {
+	baseCtx, cancel := context.WithCancel(context.Background())
 	srv := &Server{
+		draining:       make(chan struct{}),
+		cancelRequests: cancel,
 		Server: http.Server{
 			ReadTimeout:  readTimeout,
 			WriteTimeout: writeTimeout,
 			IdleTimeout:  idleTimeout,
+			BaseContext: func(net.Listener) context.Context {
+				return baseCtx
+			},
 		},
 	}
 
@@ -94,7 +119,10 @@ func NewServer(db client.DB, options ...func(*Server)) *Server {
 		opt(srv)
 	}
 
-	srv.Handler = newHandler(db, srv.options)
+	h := newHandler(db, srv.options)
+	h.draining = srv.draining
+	srv.handler = h
+	srv.Handler = h
 
 	return srv
 }
@@ -160,6 +188,17 @@ func WithCAEmail(email string) func(*Server) {
 	}
 }
 
+// WithConfig returns an option to source the runtime configurable settings (CORS, rate limits)
+// from the given node configuration and to expose it through the admin config endpoints.
+//
+// The given function, if any, is called after the configuration has been updated through the API.
+func WithConfig(cfg *config.Config, onUpdate func(context.Context)) func(*Server) {
+	return func(s *Server) {
+		s.options.cfg = cfg
+		s.options.onConfigUpdate = onUpdate
+	}
+}
+
 // WithPeerID returns an option to set the identifier of the server node.
 func WithPeerID(id string) func(*Server) {
 	return func(s *Server) {
@@ -167,6 +206,22 @@ func WithPeerID(id string) func(*Server) {
 	}
 }
 
+// WithBlockFetcher returns an option to set the fetcher of the blocks repaired by the
+// verification of the blocks, such as the DAG service of the P2P node.
+func WithBlockFetcher(fetcher ipld.NodeGetter) func(*Server) {
+	return func(s *Server) {
+		s.options.blockFetcher = fetcher
+	}
+}
+
+// WithP2PBootstrapped returns an option to set the function reporting whether the P2P node has
+// bootstrapped with its peers, the node being reported as not ready until it has.
+func WithP2PBootstrapped(bootstrapped func() bool) func(*Server) {
+	return func(s *Server) {
+		s.options.p2pBootstrapped = bootstrapped
+	}
+}
+
 // WithRootDir returns an option to set the root directory for the node config.
 func WithRootDir(rootDir string) func(*Server) {
 	return func(s *Server) {
@@ -305,3 +360,35 @@ func (s *Server) Run(ctx context.Context) error {
 	}
 	return s.Serve(s.listener)
 }
+
+// ApplyConfig applies changes to the runtime configurable settings of the server (CORS, rate limits).
+//
+// It must be called after the configuration given with [WithConfig] has been reloaded.
+func (s *Server) ApplyConfig() {
+	s.handler.applyConfig()
+}
+
+// Shutdown gracefully shuts down the server.
+//
+// The listener is closed first so that no new requests are accepted. Open subscription
+// streams are then flushed and ended, and in-flight requests are given until the given
+// context expires to complete. If they don't complete in time, their contexts are cancelled
+// (which cancels any running request plans) and the remaining connections are closed.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.drainOnce.Do(func() {
+		close(s.draining)
+	})
+
+	err := s.Server.Shutdown(ctx)
+	if err == nil {
+		s.cancelRequests()
+		return nil
+	}
+
+	log.Info(ctx, "Timed out waiting for in-flight requests, cancelling them", logging.NewKV("Error", err))
+	s.cancelRequests()
+	if closeErr := s.Server.Close(); closeErr != nil {
+		return errors.Wrap("failed to close the server", closeErr)
+	}
+	return nil
+}
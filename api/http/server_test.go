@@ -12,12 +12,16 @@ package http
 
 import (
 	"context"
+	"io"
 	"net/http"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"golang.org/x/crypto/acme/autocert"
+
+	"github.com/sourcenetwork/defradb/events"
 )
 
 func TestNewServerAndRunWithoutListener(t *testing.T) {
@@ -57,6 +61,81 @@ func TestNewServerAndRunWithListenerAndValidPort(t *testing.T) {
 	<-serverDone
 }
 
+func TestServerShutdownCancelsInFlightRequests(t *testing.T) {
+	ctx := context.Background()
+	serverDone := make(chan struct{})
+	requestStarted := make(chan struct{})
+	requestCancelled := make(chan struct{})
+
+	s := NewServer(nil, WithAddress("localhost:0"))
+	s.Handler = http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		close(requestStarted)
+		<-req.Context().Done()
+		close(requestCancelled)
+	})
+	err := s.Listen(ctx)
+	assert.NoError(t, err)
+
+	go func() {
+		defer close(serverDone)
+		err := s.Run(ctx)
+		assert.ErrorIs(t, err, http.ErrServerClosed)
+	}()
+
+	go func() {
+		res, err := http.Get("http://" + s.listener.Addr().String())
+		if err == nil {
+			res.Body.Close()
+		}
+	}()
+
+	<-requestStarted
+
+	shutdownCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	err = s.Shutdown(shutdownCtx)
+	assert.NoError(t, err)
+
+	<-requestCancelled
+	<-serverDone
+}
+
+func TestServerShutdownEndsSubscriptionStreams(t *testing.T) {
+	ctx := context.Background()
+	s := NewServer(nil, WithAddress("localhost:0"))
+
+	evtChan := events.New[events.Update](0, 1)
+	pub, err := events.NewPublisher(evtChan, 2)
+	assert.NoError(t, err)
+
+	streamDone := make(chan struct{})
+	s.Handler = http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		defer close(streamDone)
+		ctx := context.WithValue(req.Context(), ctxDraining{}, (<-chan struct{})(s.draining))
+		subscriptionHandler(pub, rw, req.WithContext(ctx))
+	})
+	err = s.Listen(ctx)
+	assert.NoError(t, err)
+	go func() {
+		_ = s.Run(ctx)
+	}()
+
+	pub.Publish("first event")
+	pub.Publish("last event")
+
+	res, err := http.Get("http://" + s.listener.Addr().String())
+	assert.NoError(t, err)
+	defer res.Body.Close()
+
+	err = s.Shutdown(ctx)
+	assert.NoError(t, err)
+	<-streamDone
+
+	body, err := io.ReadAll(res.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, "data: \"first event\"\n\ndata: \"last event\"\n\n", string(body))
+}
+
 func TestNewServerAndRunWithAutocertWithoutEmail(t *testing.T) {
 	ctx := context.Background()
 	dir := t.TempDir()
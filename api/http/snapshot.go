@@ -0,0 +1,80 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/pkg/errors"
+
+	"github.com/sourcenetwork/defradb/datastore/snapshot"
+	"github.com/sourcenetwork/defradb/logging"
+)
+
+const (
+	contentTypeGzip = "application/gzip"
+
+	snapshotFileName            = "defradb-snapshot.gz"
+	incrementalSnapshotFileName = "defradb-snapshot-incremental.gz"
+)
+
+// snapshotHandler streams a snapshot archive of the entire datastore.
+//
+// As the archive is streamed, an error occurring once it has started cannot be reported in the
+// response, but the archive is then left truncated, which restoring it detects.
+func snapshotHandler(rw http.ResponseWriter, req *http.Request) {
+	db, err := dbFromContext(req.Context())
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	rw.Header().Set("Content-Type", contentTypeGzip)
+	rw.Header().Set("Content-Disposition", "attachment; filename="+snapshotFileName)
+	rw.WriteHeader(http.StatusOK)
+
+	count, err := snapshot.Write(req.Context(), db.Root(), rw)
+	if err != nil {
+		log.ErrorE(req.Context(), "Failed to write snapshot", err)
+		return
+	}
+	log.Info(req.Context(), "Wrote snapshot", logging.NewKV("Entries", count))
+}
+
+// incrementalSnapshotHandler streams an incremental snapshot archive of the documents changed since
+// the snapshot of the manifest given in the request body.
+//
+// As with snapshotHandler, an error occurring once the archive has started leaves it truncated.
+func incrementalSnapshotHandler(rw http.ResponseWriter, req *http.Request) {
+	db, err := dbFromContext(req.Context())
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	manifest := snapshot.Manifest{}
+	if err := json.NewDecoder(req.Body).Decode(&manifest); err != nil {
+		handleErr(req.Context(), rw, errors.Wrap(err, "invalid snapshot manifest"), http.StatusBadRequest)
+		return
+	}
+
+	rw.Header().Set("Content-Type", contentTypeGzip)
+	rw.Header().Set("Content-Disposition", "attachment; filename="+incrementalSnapshotFileName)
+	rw.WriteHeader(http.StatusOK)
+
+	count, err := snapshot.WriteIncremental(req.Context(), db.Root(), rw, manifest)
+	if err != nil {
+		log.ErrorE(req.Context(), "Failed to write incremental snapshot", err)
+		return
+	}
+	log.Info(req.Context(), "Wrote incremental snapshot", logging.NewKV("Entries", count))
+}
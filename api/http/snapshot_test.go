@@ -0,0 +1,119 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package http
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	badger "github.com/dgraph-io/badger/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/config"
+	badgerds "github.com/sourcenetwork/defradb/datastore/badger/v3"
+	"github.com/sourcenetwork/defradb/datastore/snapshot"
+)
+
+func TestSnapshotHandler(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+	testLoadSchema(t, ctx, defra)
+
+	cfg := config.DefaultConfig()
+	cfg.API.AdminToken = "secret"
+
+	req, err := http.NewRequest(http.MethodGet, SnapshotPath, nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+	newHandler(defra, serverOptions{cfg: cfg}).ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, contentTypeGzip, rec.Header().Get("Content-Type"))
+
+	opts := badgerds.Options{Options: badger.DefaultOptions("").WithInMemory(true)}
+	rootstore, err := badgerds.NewDatastore("", &opts)
+	require.NoError(t, err)
+	defer rootstore.Close() //nolint:errcheck
+	count, err := snapshot.Restore(ctx, rootstore, rec.Body)
+	require.NoError(t, err)
+	assert.Greater(t, count, 0)
+}
+
+func TestSnapshotHandlerWithoutAdminToken(t *testing.T) {
+	errResponse := ErrorResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		Method:         "GET",
+		Path:           SnapshotPath,
+		ExpectedStatus: 403,
+		ResponseData:   &errResponse,
+		ServerOptions: serverOptions{
+			cfg: config.DefaultConfig(),
+		},
+	})
+
+	assert.Equal(t, ErrAdminDisabled.Error(), errResponse.Errors[0].Message)
+}
+
+func TestIncrementalSnapshotHandler(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+	testLoadSchema(t, ctx, defra)
+
+	cfg := config.DefaultConfig()
+	cfg.API.AdminToken = "secret"
+
+	req, err := http.NewRequest(http.MethodPost, SnapshotPath, bytes.NewBufferString(`{"heads": {}}`))
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+	newHandler(defra, serverOptions{cfg: cfg}).ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, contentTypeGzip, rec.Header().Get("Content-Type"))
+
+	manifest, err := snapshot.ReadManifest(rec.Body)
+	require.NoError(t, err)
+	assert.Empty(t, manifest.Heads)
+}
+
+func TestIncrementalSnapshotHandlerWithInvalidManifest(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+
+	cfg := config.DefaultConfig()
+	cfg.API.AdminToken = "secret"
+
+	errResponse := ErrorResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		DB:             defra,
+		Method:         "POST",
+		Path:           SnapshotPath,
+		Body:           bytes.NewBufferString(`not a manifest`),
+		Headers:        map[string]string{"Authorization": "Bearer secret"},
+		ExpectedStatus: 400,
+		ResponseData:   &errResponse,
+		ServerOptions: serverOptions{
+			cfg: cfg,
+		},
+	})
+
+	assert.Contains(t, errResponse.Errors[0].Message, "invalid snapshot manifest")
+}
@@ -0,0 +1,79 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package http
+
+import (
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/pkg/errors"
+
+	"github.com/sourcenetwork/defradb/request/sql"
+)
+
+type sqlRequest struct {
+	Query string `json:"query"`
+}
+
+// sqlResult is the result of a SQL query: the names of its columns and its rows of values.
+type sqlResult struct {
+	Columns []string `json:"columns"`
+	Rows    [][]any  `json:"rows"`
+}
+
+// execSQLHandler executes a SELECT statement of the SQL dialect of the request/sql package.
+//
+// The statement is given by the `query` parameter, or as the `query` field of a JSON body, or
+// as the raw body.
+func execSQLHandler(rw http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query().Get("query")
+	if query == "" && req.Body != nil {
+		contentType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+		if contentType == contentTypeJSON {
+			sqlReq := sqlRequest{}
+			if err := getJSON(req, &sqlReq); err != nil && !errors.Is(err, io.EOF) {
+				handleErr(req.Context(), rw, err, http.StatusBadRequest)
+				return
+			}
+			query = sqlReq.Query
+		} else {
+			body, err := io.ReadAll(req.Body)
+			if err != nil {
+				handleErr(req.Context(), rw, errors.WithStack(err), http.StatusInternalServerError)
+				return
+			}
+			query = string(body)
+		}
+	}
+	if query == "" {
+		handleErr(req.Context(), rw, ErrMissingSQLQuery, http.StatusBadRequest)
+		return
+	}
+
+	db, err := dbFromContext(req.Context())
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	result, err := sql.Query(req.Context(), db, query)
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusBadRequest)
+		return
+	}
+
+	res := sqlResult{Columns: make([]string, len(result.Columns)), Rows: result.Rows}
+	for i, c := range result.Columns {
+		res.Columns[i] = c.Name
+	}
+	sendJSON(req.Context(), rw, DataResponse{Data: res}, http.StatusOK)
+}
@@ -0,0 +1,116 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package http
+
+import (
+	"bytes"
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/client"
+)
+
+func testCreateSQLUsers(t *testing.T, ctx context.Context, defra client.DB) {
+	testLoadSchema(t, ctx, defra)
+	col, err := defra.GetCollectionByName(ctx, "user")
+	require.NoError(t, err)
+	for _, doc := range []string{
+		`{"name": "Bob", "age": 31, "verified": true, "points": 90}`,
+		`{"name": "Alice", "age": 25, "verified": false, "points": 70.5}`,
+	} {
+		d, err := client.NewDocFromJSON([]byte(doc))
+		require.NoError(t, err)
+		require.NoError(t, col.Create(ctx, d))
+	}
+}
+
+func TestExecSQLHandlerWithQueryParam(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+	testCreateSQLUsers(t, ctx, defra)
+
+	resp := DataResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		DB:             defra,
+		Method:         "GET",
+		Path:           SQLPath + "?query=" + url.QueryEscape(`SELECT name, points FROM user ORDER BY age`),
+		ExpectedStatus: 200,
+		ResponseData:   &resp,
+	})
+
+	assert.Equal(t, map[string]any{
+		"columns": []any{"name", "points"},
+		"rows":    []any{[]any{"Alice", 70.5}, []any{"Bob", float64(90)}},
+	}, resp.Data)
+}
+
+func TestExecSQLHandlerWithJSONBody(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+	testCreateSQLUsers(t, ctx, defra)
+
+	resp := DataResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		DB:             defra,
+		Method:         "POST",
+		Path:           SQLPath,
+		Body:           bytes.NewBufferString(`{"query": "SELECT name FROM user WHERE verified = true"}`),
+		Headers:        map[string]string{"Content-Type": contentTypeJSON},
+		ExpectedStatus: 200,
+		ResponseData:   &resp,
+	})
+
+	assert.Equal(t, map[string]any{
+		"columns": []any{"name"},
+		"rows":    []any{[]any{"Bob"}},
+	}, resp.Data)
+}
+
+func TestExecSQLHandlerWithInvalidQuery(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+	testLoadSchema(t, ctx, defra)
+
+	errResponse := ErrorResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		DB:             defra,
+		Method:         "POST",
+		Path:           SQLPath,
+		Body:           bytes.NewBufferString(`SELECT title FROM user`),
+		ExpectedStatus: 400,
+		ResponseData:   &errResponse,
+	})
+
+	assert.Equal(t, "column does not exist. Name: title", errResponse.Errors[0].Message)
+}
+
+func TestExecSQLHandlerWithoutQuery(t *testing.T) {
+	errResponse := ErrorResponse{}
+	testRequest(testOptions{
+		Testing:        t,
+		Method:         "GET",
+		Path:           SQLPath,
+		ExpectedStatus: 400,
+		ResponseData:   &errResponse,
+	})
+
+	assert.Equal(t, "missing SQL query", errResponse.Errors[0].Message)
+}
@@ -0,0 +1,67 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package http
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/pkg/errors"
+
+	"github.com/sourcenetwork/defradb/client"
+)
+
+// StatusResponse is the status of the node.
+type StatusResponse struct {
+	// Integrity is the report of the last integrity check of the system keyspace, such as the one
+	// run on startup, or nil if none was run.
+	Integrity *client.IntegrityReport `json:"integrity"`
+}
+
+// statusHandler returns the status of the node.
+func statusHandler(rw http.ResponseWriter, req *http.Request) {
+	db, err := dbFromContext(req.Context())
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	status := StatusResponse{}
+	if report := db.LastIntegrityReport(); report.HasValue() {
+		integrity := report.Value()
+		status.Integrity = &integrity
+	}
+	sendJSON(req.Context(), rw, DataResponse{Data: status}, http.StatusOK)
+}
+
+// checkIntegrityHandler checks the integrity of the system keyspace with the options given in the
+// request body, if any, and returns the report.
+func checkIntegrityHandler(rw http.ResponseWriter, req *http.Request) {
+	opts := client.CheckIntegrityOptions{}
+	if err := getJSON(req, &opts); err != nil && !errors.Is(err, io.EOF) {
+		handleErr(req.Context(), rw, err, http.StatusBadRequest)
+		return
+	}
+
+	db, err := dbFromContext(req.Context())
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	report, err := db.CheckIntegrity(req.Context(), opts)
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	sendJSON(req.Context(), rw, DataResponse{Data: report}, http.StatusOK)
+}
@@ -0,0 +1,69 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package http
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/config"
+)
+
+func TestStatusHandlerWithIntegrityCheck(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+	testLoadSchema(t, ctx, defra)
+
+	cfg := config.DefaultConfig()
+	cfg.API.AdminToken = "secret"
+
+	statusResp := struct {
+		Data StatusResponse `json:"data"`
+	}{}
+	statusOpts := testOptions{
+		Testing:        t,
+		DB:             defra,
+		Method:         "GET",
+		Path:           StatusPath,
+		ExpectedStatus: 200,
+		ResponseData:   &statusResp,
+		ServerOptions:  serverOptions{cfg: cfg},
+	}
+	testRequest(statusOpts)
+	assert.Nil(t, statusResp.Data.Integrity)
+
+	checkResp := struct {
+		Data client.IntegrityReport `json:"data"`
+	}{}
+	testRequest(testOptions{
+		Testing:        t,
+		DB:             defra,
+		Method:         "POST",
+		Path:           StatusPath + "/integrity",
+		Body:           bytes.NewBufferString(`{"repair": true}`),
+		Headers:        map[string]string{"Authorization": "Bearer secret"},
+		ExpectedStatus: 200,
+		ResponseData:   &checkResp,
+		ServerOptions:  serverOptions{cfg: cfg},
+	})
+	assert.Equal(t, 1, checkResp.Data.Collections)
+	assert.Empty(t, checkResp.Data.Issues)
+
+	testRequest(statusOpts)
+	require.NotNil(t, statusResp.Data.Integrity)
+	assert.Equal(t, 1, statusResp.Data.Integrity.Collections)
+}
@@ -0,0 +1,58 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package http
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/sourcenetwork/defradb/logging"
+)
+
+// maxTraceIDLength is the maximum length of the trace IDs given by the clients.
+const maxTraceIDLength = 128
+
+// traceMiddleware tags the context of the request with its trace ID, as given in the trace ID
+// header or generated if the request has none, and returns it in the trace ID header of the
+// response.
+func traceMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		traceID := req.Header.Get(TraceIDHeader)
+		if !isValidTraceID(traceID) {
+			traceID = newTraceID()
+		}
+		rw.Header().Set(TraceIDHeader, traceID)
+		next.ServeHTTP(rw, req.WithContext(logging.WithTraceID(req.Context(), traceID)))
+	})
+}
+
+// isValidTraceID returns true if the given trace ID is not empty, not too long and only made of
+// printable ASCII characters, so that it can be safely written to the logs.
+func isValidTraceID(traceID string) bool {
+	if traceID == "" || len(traceID) > maxTraceIDLength {
+		return false
+	}
+	for i := 0; i < len(traceID); i++ {
+		if traceID[i] < '!' || traceID[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// newTraceID returns a new random trace ID.
+func newTraceID() string {
+	b := make([]byte, 16)
+	// The reader of crypto/rand never fails on the supported platforms.
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
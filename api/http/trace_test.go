@@ -0,0 +1,57 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTraceIDInGQLErrors(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+
+	req, err := http.NewRequest(http.MethodPost, GraphQLPath, bytes.NewBufferString(`query { unknown { name } }`))
+	require.NoError(t, err)
+	req.Header.Set(TraceIDHeader, "trace-1")
+	rec := httptest.NewRecorder()
+	newHandler(defra, serverOptions{}).ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "trace-1", rec.Header().Get(TraceIDHeader))
+	result := GQLResult{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+	require.NotEmpty(t, result.Errors)
+	assert.Equal(t, "trace-1", result.Errors[0].Extensions.TraceID)
+}
+
+func TestTraceIDGeneratedInErrorResponse(t *testing.T) {
+	req, err := http.NewRequest(http.MethodPost, GraphQLPath, nil)
+	require.NoError(t, err)
+	// Trace IDs with control characters are replaced, so that they can't forge log entries.
+	req.Header.Set(TraceIDHeader, "trace\n1")
+	rec := httptest.NewRecorder()
+	newHandler(nil, serverOptions{}).ServeHTTP(rec, req)
+
+	traceID := rec.Header().Get(TraceIDHeader)
+	assert.Len(t, traceID, 32)
+	errResponse := ErrorResponse{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &errResponse))
+	assert.Equal(t, traceID, errResponse.Errors[0].Extensions.TraceID)
+}
@@ -0,0 +1,48 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package http
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/pkg/errors"
+
+	"github.com/sourcenetwork/defradb/client"
+)
+
+// verifyBlocksHandler verifies the blocks of the DAGs of the documents, and reports the missing
+// and corrupt ones.
+//
+// The options are given as a JSON body, which may be empty. The blocks are repaired from the
+// connected peers if `repair` is true, which requires the P2P node to be running.
+func (h *handler) verifyBlocksHandler(rw http.ResponseWriter, req *http.Request) {
+	opts := client.VerifyBlocksOptions{}
+	if err := getJSON(req, &opts); err != nil && !errors.Is(err, io.EOF) {
+		handleErr(req.Context(), rw, err, http.StatusBadRequest)
+		return
+	}
+	opts.Fetcher = h.options.blockFetcher
+
+	db, err := dbFromContext(req.Context())
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	report, err := db.VerifyBlocks(req.Context(), opts)
+	if err != nil {
+		handleErr(req.Context(), rw, err, http.StatusBadRequest)
+		return
+	}
+
+	sendJSON(req.Context(), rw, DataResponse{Data: report}, http.StatusOK)
+}
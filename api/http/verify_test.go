@@ -0,0 +1,79 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/config"
+)
+
+func TestVerifyBlocksHandler(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+	testLoadSchema(t, ctx, defra)
+
+	col, err := defra.GetCollectionByName(ctx, "user")
+	require.NoError(t, err)
+	doc, err := client.NewDocFromJSON([]byte(`{"name": "John"}`))
+	require.NoError(t, err)
+	require.NoError(t, col.Create(ctx, doc))
+
+	cfg := config.DefaultConfig()
+	cfg.API.AdminToken = "secret"
+
+	body := bytes.NewBufferString(`{"collections": ["user"]}`)
+	req, err := http.NewRequest(http.MethodPost, VerifyPath, body)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+	newHandler(defra, serverOptions{cfg: cfg}).ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	resp := struct {
+		Data client.VerifyBlocksReport `json:"data"`
+	}{}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, 1, resp.Data.Documents)
+	assert.Equal(t, 2, resp.Data.Blocks)
+	assert.Empty(t, resp.Data.Issues)
+}
+
+func TestVerifyBlocksHandlerRepairWithoutP2P(t *testing.T) {
+	ctx := context.Background()
+	defra := testNewInMemoryDB(t, ctx)
+	defer defra.Close(ctx)
+
+	cfg := config.DefaultConfig()
+	cfg.API.AdminToken = "secret"
+
+	body := bytes.NewBufferString(`{"repair": true}`)
+	req, err := http.NewRequest(http.MethodPost, VerifyPath, body)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+	newHandler(defra, serverOptions{cfg: cfg}).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	errResponse := ErrorResponse{}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&errResponse))
+	assert.Contains(t, errResponse.Errors[0].Message, "without a block fetcher")
+}
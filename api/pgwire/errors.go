@@ -0,0 +1,82 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package pgwire
+
+import (
+	"github.com/sourcenetwork/defradb/errors"
+	"github.com/sourcenetwork/defradb/request/sql"
+)
+
+const (
+	errUnsupportedProtocol   string = "unsupported protocol version"
+	errUnsupportedMessage    string = "unsupported message"
+	errInvalidMessageSize    string = "invalid message size"
+	errExtendedQueryProtocol string = "the extended query protocol is not supported, use simple queries"
+)
+
+var (
+	ErrUnsupportedProtocol   = errors.New(errUnsupportedProtocol)
+	ErrUnsupportedMessage    = errors.New(errUnsupportedMessage)
+	ErrInvalidMessageSize    = errors.New(errInvalidMessageSize)
+	ErrExtendedQueryProtocol = errors.New(errExtendedQueryProtocol)
+)
+
+// NewErrUnsupportedProtocol returns a new error indicating that a client requested an unsupported
+// version of the protocol.
+func NewErrUnsupportedProtocol(version uint32) error {
+	return errors.New(
+		errUnsupportedProtocol,
+		errors.NewKV("Major", version>>16),
+		errors.NewKV("Minor", version&0xffff),
+	)
+}
+
+// NewErrUnsupportedMessage returns a new error indicating that a client sent a message of an
+// unsupported type.
+func NewErrUnsupportedMessage(msgType byte) error {
+	return errors.New(errUnsupportedMessage, errors.NewKV("Type", string(msgType)))
+}
+
+// NewErrInvalidMessageSize returns a new error indicating that a client sent a message of a size
+// that is too small for its type, or larger than accepted.
+func NewErrInvalidMessageSize(size int) error {
+	return errors.New(errInvalidMessageSize, errors.NewKV("Size", size))
+}
+
+// sqlState returns the SQLSTATE code reported to clients for the given error.
+func sqlState(err error) string {
+	switch {
+	case errors.Is(err, sql.ErrSyntax):
+		return "42601" // syntax_error
+	case errors.Is(err, sql.ErrUnsupportedStatement),
+		errors.Is(err, sql.ErrUnsupportedFeature),
+		errors.Is(err, ErrExtendedQueryProtocol):
+		return "0A000" // feature_not_supported
+	case errors.Is(err, sql.ErrUndefinedTable):
+		return "42P01" // undefined_table
+	case errors.Is(err, sql.ErrUndefinedColumn):
+		return "42703" // undefined_column
+	case errors.Is(err, sql.ErrAmbiguousColumn):
+		return "42702" // ambiguous_column
+	case errors.Is(err, sql.ErrDuplicateAlias):
+		return "42712" // duplicate_alias
+	case errors.Is(err, sql.ErrNotRelation):
+		return "42809" // wrong_object_type
+	case errors.Is(err, sql.ErrNullComparison):
+		return "42883" // undefined_function
+	case errors.Is(err, ErrUnsupportedProtocol),
+		errors.Is(err, ErrUnsupportedMessage),
+		errors.Is(err, ErrInvalidMessageSize):
+		return "08P01" // protocol_violation
+	default:
+		return "XX000" // internal_error
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package pgwire
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+)
+
+// The codes of the startup messages.
+const (
+	protocolVersion3  uint32 = 3 << 16
+	cancelRequestCode uint32 = 80877102
+	sslRequestCode    uint32 = 80877103
+	gssEncRequestCode uint32 = 80877104
+)
+
+// The types of the frontend messages.
+const (
+	msgQuery     byte = 'Q'
+	msgTerminate byte = 'X'
+	msgSync      byte = 'S'
+	msgFlush     byte = 'H'
+	msgParse     byte = 'P'
+	msgBind      byte = 'B'
+	msgDescribe  byte = 'D'
+	msgExecute   byte = 'E'
+	msgClose     byte = 'C'
+)
+
+// maxMessageSize is the maximum size of the messages accepted from clients.
+const maxMessageSize = 1 << 20
+
+// readStartupMessage reads a startup message, which has no type, and returns its code and the
+// rest of its payload.
+func readStartupMessage(r io.Reader) (uint32, []byte, error) {
+	payload, err := readPayload(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(payload) < 4 {
+		return 0, nil, NewErrInvalidMessageSize(len(payload))
+	}
+	return binary.BigEndian.Uint32(payload), payload[4:], nil
+}
+
+// readMessage reads a message and returns its type and payload.
+func readMessage(r io.Reader) (byte, []byte, error) {
+	var msgType [1]byte
+	if _, err := io.ReadFull(r, msgType[:]); err != nil {
+		return 0, nil, err
+	}
+	payload, err := readPayload(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	return msgType[0], payload, nil
+}
+
+// readPayload reads the length prefixed payload of a message.
+func readPayload(r io.Reader) ([]byte, error) {
+	var length [4]byte
+	if _, err := io.ReadFull(r, length[:]); err != nil {
+		return nil, err
+	}
+	// The length includes itself.
+	size := int(binary.BigEndian.Uint32(length[:])) - 4
+	if size < 0 || size > maxMessageSize {
+		return nil, NewErrInvalidMessageSize(size)
+	}
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+// cstring returns the null terminated string at the start of the given payload.
+func cstring(payload []byte) string {
+	if i := bytes.IndexByte(payload, 0); i >= 0 {
+		return string(payload[:i])
+	}
+	return string(payload)
+}
+
+// startupParams returns the parameters of a startup message, given as pairs of null terminated
+// names and values.
+func startupParams(payload []byte) map[string]string {
+	params := map[string]string{}
+	fields := bytes.Split(payload, []byte{0})
+	for i := 0; i+1 < len(fields) && len(fields[i]) > 0; i += 2 {
+		params[string(fields[i])] = string(fields[i+1])
+	}
+	return params
+}
+
+// message is a backend message being built.
+type message struct {
+	buf []byte
+}
+
+// newMessage returns a new backend message of the given type.
+func newMessage(msgType byte) *message {
+	// The length is set once the message is built.
+	return &message{buf: []byte{msgType, 0, 0, 0, 0}}
+}
+
+func (m *message) int16(v int16) *message {
+	m.buf = binary.BigEndian.AppendUint16(m.buf, uint16(v))
+	return m
+}
+
+func (m *message) int32(v int32) *message {
+	m.buf = binary.BigEndian.AppendUint32(m.buf, uint32(v))
+	return m
+}
+
+func (m *message) byte(v byte) *message {
+	m.buf = append(m.buf, v)
+	return m
+}
+
+func (m *message) bytes(v []byte) *message {
+	m.buf = append(m.buf, v...)
+	return m
+}
+
+// string appends the given string, null terminated.
+func (m *message) string(v string) *message {
+	m.buf = append(append(m.buf, v...), 0)
+	return m
+}
+
+// encode returns the encoded message.
+func (m *message) encode() []byte {
+	binary.BigEndian.PutUint32(m.buf[1:5], uint32(len(m.buf)-1))
+	return m.buf
+}
+
+// typeSize returns the size of the Postgres type of the given OID, -1 for variable length types.
+func typeSize(oid uint32) int16 {
+	switch oid {
+	case oidBool:
+		return 1
+	case oidInt8, oidFloat8:
+		return 8
+	default:
+		return -1
+	}
+}
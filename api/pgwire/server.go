@@ -0,0 +1,348 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+/*
+Package pgwire serves read-only SQL access to the collections of a database over the Postgres
+wire protocol, so that BI tools and Postgres clients can connect to DefraDB directly.
+
+SELECT statements are executed by the SQL frontend of the request/sql package; see sql.Select for
+the supported subset of SQL. The columns of a collection are its key and its scalar fields. Only
+the simple query protocol is supported, connections are not encrypted and clients are not
+authenticated.
+*/
+package pgwire
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/errors"
+	"github.com/sourcenetwork/defradb/logging"
+	"github.com/sourcenetwork/defradb/request/sql"
+)
+
+var log = logging.MustNewLogger("defra.pgwire")
+
+// serverVersion is the Postgres version reported to clients, which some of them require to
+// enable their features.
+const serverVersion = "14.0 (DefraDB)"
+
+// Server serves the collections of a database over the Postgres wire protocol.
+type Server struct {
+	db       client.DB
+	listener net.Listener
+
+	mu     sync.Mutex
+	conns  map[net.Conn]struct{}
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// NewServer returns a new server of the collections of the given database.
+func NewServer(db client.DB) *Server {
+	return &Server{
+		db:    db,
+		conns: map[net.Conn]struct{}{},
+	}
+}
+
+// Start starts accepting connections on the given address.
+//
+// The connections are served until the server is closed.
+func (s *Server) Start(ctx context.Context, address string) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+	s.listener = listener
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				if !errors.Is(err, net.ErrClosed) {
+					log.ErrorE(ctx, "Failed to accept Postgres connection", err)
+				}
+				return
+			}
+			if !s.track(conn) {
+				_ = conn.Close()
+				return
+			}
+			go func() {
+				defer s.untrack(conn)
+				newSession(s.db, conn).run(ctx)
+			}()
+		}
+	}()
+	return nil
+}
+
+// Addr returns the address the server accepts connections on.
+func (s *Server) Addr() net.Addr {
+	return s.listener.Addr()
+}
+
+// Close stops accepting connections, closes the open ones and waits for them to end.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	err := s.listener.Close()
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return err
+}
+
+// track registers the given connection, and returns false if the server is closed.
+func (s *Server) track(conn net.Conn) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return false
+	}
+	s.conns[conn] = struct{}{}
+	s.wg.Add(1)
+	return true
+}
+
+func (s *Server) untrack(conn net.Conn) {
+	s.mu.Lock()
+	delete(s.conns, conn)
+	s.mu.Unlock()
+	_ = conn.Close()
+	s.wg.Done()
+}
+
+// session is the connection of a client.
+type session struct {
+	db   client.DB
+	conn net.Conn
+	r    *bufio.Reader
+	w    *bufio.Writer
+}
+
+func newSession(db client.DB, conn net.Conn) *session {
+	return &session{
+		db:   db,
+		conn: conn,
+		r:    bufio.NewReader(conn),
+		w:    bufio.NewWriter(conn),
+	}
+}
+
+// run serves the client until it terminates the session or the connection is closed.
+func (s *session) run(ctx context.Context) {
+	err := s.serve(ctx)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+		log.ErrorE(ctx, "Postgres session failed", err, logging.NewKV("Remote", s.conn.RemoteAddr()))
+		// The error is reported to the client on a best effort basis, as the connection may be broken.
+		s.sendError(err)
+		_ = s.w.Flush()
+	}
+}
+
+func (s *session) serve(ctx context.Context) error {
+	ok, err := s.startup(ctx)
+	if err != nil || !ok {
+		return err
+	}
+
+	// failed is set when a message of the extended query protocol is received, after which the
+	// messages are discarded until the next Sync, as Postgres does on errors.
+	failed := false
+	for {
+		msgType, payload, err := readMessage(s.r)
+		if err != nil {
+			return err
+		}
+
+		switch msgType {
+		case msgQuery:
+			s.simpleQuery(ctx, cstring(payload))
+
+		case msgTerminate:
+			return nil
+
+		case msgSync:
+			failed = false
+			s.readyForQuery()
+
+		case msgFlush:
+
+		case msgParse, msgBind, msgDescribe, msgExecute, msgClose:
+			if !failed {
+				s.sendError(ErrExtendedQueryProtocol)
+				failed = true
+			}
+
+		default:
+			return NewErrUnsupportedMessage(msgType)
+		}
+
+		if err := s.w.Flush(); err != nil {
+			return err
+		}
+	}
+}
+
+// startup negotiates the session, and returns false if the client does not request one.
+func (s *session) startup(ctx context.Context) (bool, error) {
+	for {
+		code, payload, err := readStartupMessage(s.r)
+		if err != nil {
+			return false, err
+		}
+
+		switch {
+		case code == sslRequestCode || code == gssEncRequestCode:
+			// Encryption is not supported, the client may go on unencrypted.
+			if _, err := s.conn.Write([]byte{'N'}); err != nil {
+				return false, err
+			}
+
+		case code == cancelRequestCode:
+			// The statements are not cancellable, the request is ignored.
+			return false, nil
+
+		case code>>16 == protocolVersion3>>16:
+			params := startupParams(payload)
+			log.Debug(
+				ctx,
+				"Postgres session started",
+				logging.NewKV("Remote", s.conn.RemoteAddr()),
+				logging.NewKV("User", params["user"]),
+				logging.NewKV("Application", params["application_name"]),
+			)
+
+			s.write(newMessage('R').int32(0)) // AuthenticationOk
+			for _, param := range [][2]string{
+				{"server_version", serverVersion},
+				{"server_encoding", "UTF8"},
+				{"client_encoding", "UTF8"},
+				{"DateStyle", "ISO, MDY"},
+				{"TimeZone", "UTC"},
+				{"integer_datetimes", "on"},
+				{"standard_conforming_strings", "on"},
+			} {
+				s.write(newMessage('S').string(param[0]).string(param[1])) // ParameterStatus
+			}
+			s.readyForQuery()
+			return true, s.w.Flush()
+
+		default:
+			return false, NewErrUnsupportedProtocol(code)
+		}
+	}
+}
+
+// simpleQuery executes the statements of the given SQL, up to the first failing one.
+func (s *session) simpleQuery(ctx context.Context, query string) {
+	defer s.readyForQuery()
+
+	statements, err := sql.Parse(query)
+	if err != nil {
+		s.sendError(err)
+		return
+	}
+	if len(statements) == 0 {
+		s.write(newMessage('I')) // EmptyQueryResponse
+		return
+	}
+	for _, stmt := range statements {
+		if err := s.execute(ctx, stmt); err != nil {
+			s.sendError(err)
+			return
+		}
+	}
+}
+
+func (s *session) execute(ctx context.Context, stmt sql.Statement) error {
+	sel, isSelect := stmt.(*sql.Select)
+	if !isSelect {
+		s.commandComplete(stmt.Tag())
+		return nil
+	}
+	result, err := sql.Execute(ctx, s.db, sel)
+	if err != nil {
+		return err
+	}
+	return s.sendRows(result.Columns, result.Rows)
+}
+
+// sendRows sends the description of the given columns, and the given rows of values.
+func (s *session) sendRows(columns []sql.Column, rows [][]any) error {
+	desc := newMessage('T').int16(int16(len(columns))) // RowDescription
+	for _, c := range columns {
+		oid := fieldOID(c.Kind)
+		desc.string(c.Name).
+			int32(0). // table OID
+			int16(0). // column attribute number
+			int32(int32(oid)).
+			int16(typeSize(oid)).
+			int32(-1). // type modifier
+			int16(0)   // text format
+	}
+	s.write(desc)
+
+	for _, row := range rows {
+		data := newMessage('D').int16(int16(len(columns))) // DataRow
+		for _, v := range row {
+			value, notNull, err := textValue(v)
+			if err != nil {
+				return err
+			}
+			if !notNull {
+				data.int32(-1)
+				continue
+			}
+			data.int32(int32(len(value))).bytes(value)
+		}
+		s.write(data)
+	}
+	s.commandComplete(fmt.Sprintf("SELECT %d", len(rows)))
+	return nil
+}
+
+func (s *session) commandComplete(tag string) {
+	s.write(newMessage('C').string(tag))
+}
+
+func (s *session) readyForQuery() {
+	s.write(newMessage('Z').byte('I')) // idle
+}
+
+func (s *session) sendError(err error) {
+	s.write(newMessage('E').
+		byte('S').string("ERROR").
+		byte('V').string("ERROR").
+		byte('C').string(sqlState(err)).
+		byte('M').string(err.Error()).
+		byte(0))
+}
+
+// write buffers the given message. The write errors are returned by the next flush.
+func (s *session) write(m *message) {
+	_, _ = s.w.Write(m.encode())
+}
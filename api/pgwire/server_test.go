@@ -0,0 +1,268 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package pgwire
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"net"
+	"testing"
+
+	badger "github.com/dgraph-io/badger/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/client"
+	badgerds "github.com/sourcenetwork/defradb/datastore/badger/v3"
+	"github.com/sourcenetwork/defradb/db"
+)
+
+// testClient is a minimal Postgres client of the simple query protocol.
+type testClient struct {
+	t    *testing.T
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// queryResult is the result of a simple query.
+type queryResult struct {
+	columns []string
+	oids    []uint32
+	rows    [][]*string
+	tags    []string
+	// errCode is the SQLSTATE code of the error of the query, if any.
+	errCode string
+}
+
+func newTestServer(t *testing.T, ctx context.Context) *Server {
+	opts := badgerds.Options{Options: badger.DefaultOptions("").WithInMemory(true)}
+	rootstore, err := badgerds.NewDatastore("", &opts)
+	require.NoError(t, err)
+	defra, err := db.NewDB(ctx, rootstore)
+	require.NoError(t, err)
+	t.Cleanup(func() { defra.Close(ctx) })
+
+	err = defra.AddSchema(ctx, `type User {
+		name: String
+		age: Int
+		points: Float
+		verified: Boolean
+		tags: [String!]
+	}`)
+	require.NoError(t, err)
+	col, err := defra.GetCollectionByName(ctx, "User")
+	require.NoError(t, err)
+	for _, doc := range []string{
+		`{"name": "John", "age": 21, "points": 4.5, "verified": true, "tags": ["a", "b"]}`,
+		`{"name": "Islam", "age": 32, "points": 10, "verified": false}`,
+		`{"name": "Fred", "age": 17}`,
+	} {
+		d, err := client.NewDocFromJSON([]byte(doc))
+		require.NoError(t, err)
+		require.NoError(t, col.Create(ctx, d))
+	}
+
+	server := NewServer(defra)
+	require.NoError(t, server.Start(ctx, "127.0.0.1:0"))
+	t.Cleanup(func() { require.NoError(t, server.Close()) })
+	return server
+}
+
+func newTestClient(t *testing.T, addr net.Addr) *testClient {
+	conn, err := net.Dial("tcp", addr.String())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	c := &testClient{t: t, conn: conn, r: bufio.NewReader(conn)}
+
+	// The client asks for encryption first, as most clients do by default.
+	c.send(0, binary.BigEndian.AppendUint32(nil, sslRequestCode))
+	reply, err := c.r.ReadByte()
+	require.NoError(t, err)
+	require.Equal(t, byte('N'), reply)
+
+	startup := binary.BigEndian.AppendUint32(nil, protocolVersion3)
+	startup = append(startup, "user\x00bob\x00database\x00defradb\x00\x00"...)
+	c.send(0, startup)
+	for {
+		msgType, _ := c.receive()
+		if msgType == 'Z' {
+			return c
+		}
+	}
+}
+
+// send sends a message of the given type, or a startup message if the type is zero.
+func (c *testClient) send(msgType byte, payload []byte) {
+	var msg []byte
+	if msgType != 0 {
+		msg = append(msg, msgType)
+	}
+	msg = binary.BigEndian.AppendUint32(msg, uint32(len(payload)+4))
+	_, err := c.conn.Write(append(msg, payload...))
+	require.NoError(c.t, err)
+}
+
+func (c *testClient) receive() (byte, []byte) {
+	msgType, payload, err := readMessage(c.r)
+	require.NoError(c.t, err)
+	return msgType, payload
+}
+
+// query executes the given SQL with a simple query, and returns the result of its last statement.
+func (c *testClient) query(sql string) queryResult {
+	c.send(msgQuery, append([]byte(sql), 0))
+	return c.readResult()
+}
+
+func (c *testClient) readResult() queryResult {
+	var result queryResult
+	for {
+		msgType, payload := c.receive()
+		switch msgType {
+		case 'T':
+			result.columns, result.oids, result.rows = nil, nil, nil
+			n := int(binary.BigEndian.Uint16(payload))
+			payload = payload[2:]
+			for i := 0; i < n; i++ {
+				name := cstring(payload)
+				payload = payload[len(name)+1:]
+				result.columns = append(result.columns, name)
+				result.oids = append(result.oids, binary.BigEndian.Uint32(payload[6:]))
+				payload = payload[18:]
+			}
+
+		case 'D':
+			n := int(binary.BigEndian.Uint16(payload))
+			payload = payload[2:]
+			row := make([]*string, n)
+			for i := 0; i < n; i++ {
+				size := int32(binary.BigEndian.Uint32(payload))
+				payload = payload[4:]
+				if size < 0 {
+					continue
+				}
+				value := string(payload[:size])
+				row[i] = &value
+				payload = payload[size:]
+			}
+			result.rows = append(result.rows, row)
+
+		case 'C':
+			result.tags = append(result.tags, cstring(payload))
+
+		case 'E':
+			for len(payload) > 1 {
+				field := payload[0]
+				value := cstring(payload[1:])
+				payload = payload[len(value)+2:]
+				if field == 'C' {
+					result.errCode = value
+				}
+			}
+
+		case 'Z':
+			return result
+		}
+	}
+}
+
+func values(values ...any) []*string {
+	row := make([]*string, len(values))
+	for i, v := range values {
+		if s, ok := v.(string); ok {
+			row[i] = &s
+		}
+	}
+	return row
+}
+
+func TestServerSelect(t *testing.T) {
+	ctx := context.Background()
+	server := newTestServer(t, ctx)
+	c := newTestClient(t, server.Addr())
+
+	result := c.query(`SELECT name, age, points, verified, tags FROM User WHERE age > 18 ORDER BY age DESC`)
+	assert.Empty(t, result.errCode)
+	assert.Equal(t, []string{"name", "age", "points", "verified", "tags"}, result.columns)
+	assert.Equal(t, []uint32{oidText, oidInt8, oidFloat8, oidBool, oidJSON}, result.oids)
+	assert.Equal(t, [][]*string{
+		values("Islam", "32", "10", "f", nil),
+		values("John", "21", "4.5", "t", `["a","b"]`),
+	}, result.rows)
+	assert.Equal(t, []string{"SELECT 2"}, result.tags)
+}
+
+func TestServerSelectAll(t *testing.T) {
+	ctx := context.Background()
+	server := newTestServer(t, ctx)
+	c := newTestClient(t, server.Addr())
+
+	result := c.query(`SET DateStyle = 'ISO'; SELECT * FROM User WHERE verified IS NULL`)
+	assert.Empty(t, result.errCode)
+	assert.Equal(t, []string{"_key", "age", "name", "points", "tags", "verified"}, result.columns)
+	require.Len(t, result.rows, 1)
+	assert.Equal(t, "Fred", *result.rows[0][2])
+	assert.Nil(t, result.rows[0][3])
+	assert.Equal(t, []string{"SET", "SELECT 1"}, result.tags)
+}
+
+func TestServerSelectConstants(t *testing.T) {
+	ctx := context.Background()
+	server := newTestServer(t, ctx)
+	c := newTestClient(t, server.Addr())
+
+	result := c.query(`SELECT 1`)
+	assert.Empty(t, result.errCode)
+	assert.Equal(t, []uint32{oidInt8}, result.oids)
+	assert.Equal(t, [][]*string{values("1")}, result.rows)
+}
+
+func TestServerErrors(t *testing.T) {
+	ctx := context.Background()
+	server := newTestServer(t, ctx)
+	c := newTestClient(t, server.Addr())
+
+	assert.Equal(t, "42P01", c.query(`SELECT * FROM Book`).errCode)
+	assert.Equal(t, "42703", c.query(`SELECT title FROM User`).errCode)
+	assert.Equal(t, "42601", c.query(`SELECT FROM User`).errCode)
+	assert.Equal(t, "0A000", c.query(`DELETE FROM User`).errCode)
+
+	// The session goes on after errors.
+	result := c.query(`SELECT name FROM User WHERE name = 'John'`)
+	assert.Empty(t, result.errCode)
+	assert.Equal(t, [][]*string{values("John")}, result.rows)
+}
+
+func TestServerRejectsExtendedQueryProtocol(t *testing.T) {
+	ctx := context.Background()
+	server := newTestServer(t, ctx)
+	c := newTestClient(t, server.Addr())
+
+	c.send(msgParse, []byte("\x00SELECT 1\x00\x00\x00"))
+	c.send(msgBind, []byte("\x00\x00\x00\x00\x00\x00\x00\x00"))
+	c.send(msgExecute, []byte("\x00\x00\x00\x00\x00"))
+	c.send(msgSync, nil)
+	assert.Equal(t, "0A000", c.readResult().errCode)
+
+	// The session is ready for simple queries after the Sync.
+	assert.Equal(t, [][]*string{values("1")}, c.query(`SELECT 1`).rows)
+}
+
+func TestServerCloseEndsSessions(t *testing.T) {
+	ctx := context.Background()
+	server := newTestServer(t, ctx)
+	c := newTestClient(t, server.Addr())
+
+	require.NoError(t, server.Close())
+	_, _, err := readMessage(c.r)
+	assert.Error(t, err)
+}
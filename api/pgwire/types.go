@@ -0,0 +1,79 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package pgwire
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/sourcenetwork/defradb/client"
+)
+
+// The OIDs of the Postgres types the values are returned as.
+const (
+	oidBool   uint32 = 16
+	oidInt8   uint32 = 20
+	oidText   uint32 = 25
+	oidJSON   uint32 = 114
+	oidFloat8 uint32 = 701
+)
+
+// fieldOID returns the OID of the Postgres type the values of the fields of the given kind are
+// returned as. Arrays are returned as JSON, and date times as text in the RFC 3339 format.
+func fieldOID(kind client.FieldKind) uint32 {
+	switch kind {
+	case client.FieldKind_BOOL:
+		return oidBool
+	case client.FieldKind_INT, client.FieldKind_INT32, client.FieldKind_UINT64:
+		return oidInt8
+	case client.FieldKind_FLOAT:
+		return oidFloat8
+	case client.FieldKind_BOOL_ARRAY,
+		client.FieldKind_INT_ARRAY,
+		client.FieldKind_FLOAT_ARRAY,
+		client.FieldKind_STRING_ARRAY,
+		client.FieldKind_NILLABLE_BOOL_ARRAY,
+		client.FieldKind_NILLABLE_INT_ARRAY,
+		client.FieldKind_NILLABLE_FLOAT_ARRAY,
+		client.FieldKind_NILLABLE_STRING_ARRAY:
+		return oidJSON
+	default:
+		return oidText
+	}
+}
+
+// textValue returns the text format of the given value, and false if it is null.
+func textValue(value any) ([]byte, bool, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, false, nil
+	case string:
+		return []byte(v), true, nil
+	case bool:
+		if v {
+			return []byte("t"), true, nil
+		}
+		return []byte("f"), true, nil
+	case float32:
+		return []byte(strconv.FormatFloat(float64(v), 'g', -1, 32)), true, nil
+	case float64:
+		return []byte(strconv.FormatFloat(v, 'g', -1, 64)), true, nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return []byte(fmt.Sprint(v)), true, nil
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, false, err
+		}
+		return b, true, nil
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/spf13/cobra"
+
+	httpapi "github.com/sourcenetwork/defradb/api/http"
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/config"
+	"github.com/sourcenetwork/defradb/errors"
+	"github.com/sourcenetwork/defradb/logging"
+)
+
+// MakeBranchCommand returns the parent command of the branches of the database.
+func MakeBranchCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "branch",
+		Short: "Manage the branches of the database",
+		Long: `Manage the branches of the database.
+
+A branch shares the history of the database up to its creation, the writes and schema updates
+made to either of them afterwards being isolated from the other. Branches can be used to try out
+schema changes and updates against live data, then diffed against the database and discarded.
+
+Requests are sent to a branch with the --branch flag of the query command. The admin token of
+the configuration authenticates the requests.`,
+	}
+	return cmd
+}
+
+// MakeBranchCreateCommand returns the command creating a branch of the database.
+func MakeBranchCreateCommand(cfg *config.Config) *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "create [NAME]",
+		Short: "Create a branch of the database",
+		Long: `Create a branch of the database.
+
+Example: create a branch and query it
+  defradb client branch create dev
+  defradb client query --branch dev 'query { User { name } }'`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			created := client.Branch{}
+			err := sendBranchRequest(cmd, cfg, http.MethodPost, map[string]string{"name": args[0]}, &created)
+			if err != nil {
+				return err
+			}
+			log.FeedbackInfo(
+				cmd.Context(),
+				"Branch created",
+				logging.NewKV("Name", created.Name),
+				logging.NewKV("CreatedAt", created.CreatedAt),
+			)
+			return nil
+		},
+	}
+	return cmd
+}
+
+// MakeBranchListCommand returns the command listing the branches of the database.
+func MakeBranchListCommand(cfg *config.Config) *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "list",
+		Short: "List the branches of the database",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			branches := []client.Branch{}
+			if err := sendBranchRequest(cmd, cfg, http.MethodGet, nil, &branches); err != nil {
+				return err
+			}
+			return printBranchResult(cmd, branches)
+		},
+	}
+	return cmd
+}
+
+// MakeBranchDiffCommand returns the command diffing a branch against the database.
+func MakeBranchDiffCommand(cfg *config.Config) *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "diff [NAME]",
+		Short: "Diff a branch against the database",
+		Long: `Diff a branch against the database.
+
+The collections whose schema version or documents differ are listed, with the keys of the
+documents added, changed and removed on the branch. The documents updated on either side since
+the creation of the branch are reported as changed.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			diff := client.BranchDiff{}
+			if err := sendBranchRequest(cmd, cfg, http.MethodGet, nil, &diff, args[0], "diff"); err != nil {
+				return err
+			}
+			return printBranchResult(cmd, diff)
+		},
+	}
+	return cmd
+}
+
+// MakeBranchDiscardCommand returns the command discarding a branch of the database.
+func MakeBranchDiscardCommand(cfg *config.Config) *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "discard [NAME]",
+		Short: "Discard a branch of the database, along with all its writes",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := sendBranchRequest(cmd, cfg, http.MethodDelete, nil, nil, args[0]); err != nil {
+				return err
+			}
+			log.FeedbackInfo(cmd.Context(), "Branch discarded", logging.NewKV("Name", args[0]))
+			return nil
+		},
+	}
+	return cmd
+}
+
+// sendBranchRequest sends a request with the given method and JSON body, if any, to the branches
+// endpoint joined with the given paths, and decodes the data of the response into the given
+// result, if any.
+func sendBranchRequest(
+	cmd *cobra.Command,
+	cfg *config.Config,
+	method string,
+	body any,
+	result any,
+	paths ...string,
+) (err error) {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return errors.Wrap("failed to marshal branch request", err)
+		}
+		reqBody = bytes.NewBuffer(data)
+	}
+
+	endpoint, err := httpapi.JoinPaths(cfg.API.AddressToURL(), append([]string{httpapi.BranchesPath}, paths...)...)
+	if err != nil {
+		return NewErrFailedToJoinEndpoint(err)
+	}
+	req, err := http.NewRequestWithContext(cmd.Context(), method, endpoint.String(), reqBody)
+	if err != nil {
+		return NewErrFailedToSendRequest(err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if cfg.API.AdminToken != "" {
+		req.Header.Set("Authorization", "Bearer "+cfg.API.AdminToken)
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return NewErrFailedToSendRequest(err)
+	}
+	defer func() {
+		if e := res.Body.Close(); e != nil && err == nil {
+			err = NewErrFailedToReadResponseBody(e)
+		}
+	}()
+
+	if res.StatusCode != http.StatusOK {
+		r := httpapi.ErrorResponse{}
+		if err := json.NewDecoder(res.Body).Decode(&r); err != nil {
+			return NewErrFailedToUnmarshalResponse(err)
+		}
+		if len(r.Errors) > 0 {
+			return errors.New(r.Errors[0].Message)
+		}
+		return errors.New("branch request failed", errors.NewKV("Status", res.StatusCode))
+	}
+	if result == nil {
+		return nil
+	}
+
+	r := struct {
+		Data any `json:"data"`
+	}{Data: result}
+	if err := json.NewDecoder(res.Body).Decode(&r); err != nil {
+		return NewErrFailedToUnmarshalResponse(err)
+	}
+	return nil
+}
+
+// printBranchResult prints the given result as indented JSON.
+func printBranchResult(cmd *cobra.Command, result any) error {
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return errors.Wrap("failed to marshal branch result", err)
+	}
+	cmd.Println(string(data))
+	return nil
+}
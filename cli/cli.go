@@ -58,6 +58,9 @@ func NewDefraCommand(cfg *config.Config) DefraCommand {
 	blocksCmd := MakeBlocksCommand()
 	schemaCmd := MakeSchemaCommand()
 	clientCmd := MakeClientCommand()
+	configCmd := MakeConfigCommand()
+	migrateCmd := MakeMigrateCommand()
+	branchCmd := MakeBranchCommand()
 	rpcReplicatorCmd := MakeReplicatorCommand()
 	p2pCollectionCmd := MakeP2PCollectionCommand()
 	p2pCollectionCmd.AddCommand(
@@ -80,22 +83,43 @@ func NewDefraCommand(cfg *config.Config) DefraCommand {
 	schemaCmd.AddCommand(
 		MakeSchemaAddCommand(cfg),
 		MakeSchemaPatchCommand(cfg),
+		MakeSchemaCloneCommand(cfg),
+	)
+	configCmd.AddCommand(
+		MakeConfigValidateCommand(cfg),
+	)
+	migrateCmd.AddCommand(
+		MakeMigrateRelationsCommand(cfg),
+	)
+	branchCmd.AddCommand(
+		MakeBranchCreateCommand(cfg),
+		MakeBranchListCommand(cfg),
+		MakeBranchDiffCommand(cfg),
+		MakeBranchDiscardCommand(cfg),
 	)
 	clientCmd.AddCommand(
 		MakeDumpCommand(cfg),
 		MakePingCommand(cfg),
 		MakeRequestCommand(cfg),
+		MakeSQLCommand(cfg),
 		MakePeerIDCommand(cfg),
+		MakeSnapshotCommand(cfg),
+		MakeVerifyCommand(cfg),
+		MakeStatusCommand(cfg),
 		schemaCmd,
 		rpcCmd,
 		blocksCmd,
+		migrateCmd,
+		branchCmd,
 	)
 	rootCmd.AddCommand(
 		clientCmd,
 		MakeStartCommand(cfg),
 		MakeServerDumpCmd(cfg),
+		MakeRestoreSnapshotCommand(cfg),
 		MakeVersionCommand(),
 		MakeInitCommand(cfg),
+		configCmd,
 	)
 
 	return DefraCommand{rootCmd, cfg}
@@ -27,6 +27,7 @@ import (
 func TestNewDefraCommand(t *testing.T) {
 	expectedCommandNames := []string{
 		"client",
+		"config",
 		"init",
 		"server-dump",
 		"start",
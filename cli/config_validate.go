@@ -0,0 +1,54 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sourcenetwork/defradb/config"
+	"github.com/sourcenetwork/defradb/errors"
+)
+
+func MakeConfigValidateCommand(cfg *config.Config) *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "validate",
+		Short: "Validate the configuration file",
+		Long: `Validate the configuration file of the root directory, with the environment variables and
+flags overriding its parameters.
+
+The configuration is invalid if a parameter has an invalid value, if the file has parameters
+that are not configuration parameters, or if its version is not supported.`,
+		// The configuration is loaded by the command itself, to report why it is invalid.
+		PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
+			return cfg.LoadRootDirFromFlagOrDefault()
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if !cfg.ConfigFileExists() {
+				return NewErrMissingConfigFile(cfg.ConfigFilePath())
+			}
+			if err := cfg.LoadWithRootdir(true); err != nil {
+				return errors.Wrap("invalid configuration", err)
+			}
+			if err := cfg.CheckUnknownKeys(); err != nil {
+				return errors.Wrap("invalid configuration", err)
+			}
+			log.FeedbackInfo(
+				cmd.Context(),
+				fmt.Sprintf("Configuration file at %v is valid (version %v)", cfg.ConfigFilePath(), cfg.Version),
+			)
+			return nil
+		},
+	}
+
+	return cmd
+}
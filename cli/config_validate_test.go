@@ -0,0 +1,49 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/config"
+)
+
+func TestConfigValidate(t *testing.T) {
+	rootdir := t.TempDir()
+	err := os.WriteFile(filepath.Join(rootdir, config.DefaultConfigFileName), []byte("version: 1\n"), 0644)
+	require.NoError(t, err)
+
+	defra := NewDefraCommand(config.DefaultConfig())
+	defra.RootCmd.SetArgs([]string{"config", "validate", "--rootdir", rootdir})
+	assert.NoError(t, defra.Execute(context.Background()))
+}
+
+func TestConfigValidateWithUnknownKeys(t *testing.T) {
+	rootdir := t.TempDir()
+	err := os.WriteFile(filepath.Join(rootdir, config.DefaultConfigFileName), []byte("api:\n    rate: 1\n"), 0644)
+	require.NoError(t, err)
+
+	defra := NewDefraCommand(config.DefaultConfig())
+	defra.RootCmd.SetArgs([]string{"config", "validate", "--rootdir", rootdir})
+	assert.ErrorIs(t, defra.Execute(context.Background()), config.ErrUnknownConfigKeys)
+}
+
+func TestConfigValidateWithoutConfigFile(t *testing.T) {
+	defra := NewDefraCommand(config.DefaultConfig())
+	defra.RootCmd.SetArgs([]string{"config", "validate", "--rootdir", t.TempDir()})
+	assert.ErrorIs(t, defra.Execute(context.Background()), ErrMissingConfigFile)
+}
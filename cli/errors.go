@@ -29,6 +29,8 @@ const (
 	errFailedToHandleGQLErrors     string = "failed to handle GraphQL errors"
 	errFailedToPrettyPrintResponse string = "failed to pretty print response"
 	errFailedToUnmarshalResponse   string = "failed to unmarshal response"
+	errFailedToSignSchemaUpdate    string = "failed to sign schema update"
+	errMissingConfigFile           string = "missing configuration file"
 )
 
 // Errors returnable from this package.
@@ -52,6 +54,8 @@ var (
 	ErrFailedToHandleGQLErrors     = errors.New(errFailedToHandleGQLErrors)
 	ErrFailedToPrettyPrintResponse = errors.New(errFailedToPrettyPrintResponse)
 	ErrFailedToUnmarshalResponse   = errors.New(errFailedToUnmarshalResponse)
+	ErrFailedToSignSchemaUpdate    = errors.New(errFailedToSignSchemaUpdate)
+	ErrMissingConfigFile           = errors.New(errMissingConfigFile)
 )
 
 func NewErrMissingArg(name string) error {
@@ -105,3 +109,11 @@ func NewErrFailedToPrettyPrintResponse(inner error) error {
 func NewErrFailedToUnmarshalResponse(inner error) error {
 	return errors.Wrap(errFailedToUnmarshalResponse, inner)
 }
+
+func NewErrFailedToSignSchemaUpdate(inner error) error {
+	return errors.Wrap(errFailedToSignSchemaUpdate, inner)
+}
+
+func NewErrMissingConfigFile(path string) error {
+	return errors.New(errMissingConfigFile, errors.NewKV("Path", path))
+}
@@ -0,0 +1,159 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	httpapi "github.com/sourcenetwork/defradb/api/http"
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/config"
+	"github.com/sourcenetwork/defradb/errors"
+	"github.com/sourcenetwork/defradb/logging"
+)
+
+// MakeMigrateCommand returns the parent command of the data migrations.
+func MakeMigrateCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "migrate",
+		Short: "Migrate the data of the node to the current schema",
+	}
+	return cmd
+}
+
+// MakeMigrateRelationsCommand returns the command migrating the relation ID fields of the
+// documents of the node.
+func MakeMigrateRelationsCommand(cfg *config.Config) *cobra.Command {
+	var collections []string
+	var batchSize int
+	var keysFile string
+	var cmd = &cobra.Command{
+		Use:   "relations",
+		Short: "Migrate the relation ID fields of the documents to the current schema",
+		Long: `Migrate the relation ID fields of the documents to the current schema.
+
+The relation IDs held by the secondary side of one-to-one relations, as written under a schema
+in which that side was primary, are moved to the related documents. The relation IDs
+referencing re-keyed documents are rewritten to reference their new keys, given as a JSON
+object mapping the previous keys to the new ones.
+
+The documents are migrated in batches, each in its own transaction, and the progress is
+reported after each batch. The admin token of the configuration authenticates the request.
+
+Example: migrate the books, remapping re-keyed authors
+  defradb client migrate relations --collection book --keys keys.json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) (err error) {
+			opts := client.MigrateRelationsOptions{
+				Collections: collections,
+				BatchSize:   batchSize,
+			}
+			if keysFile != "" {
+				data, err := os.ReadFile(keysFile)
+				if err != nil {
+					return NewFailedToReadFile(err)
+				}
+				if err := json.Unmarshal(data, &opts.Keys); err != nil {
+					return errors.Wrap("failed to parse keys file", err)
+				}
+			}
+			body, err := json.Marshal(opts)
+			if err != nil {
+				return errors.Wrap("failed to marshal migration options", err)
+			}
+
+			endpoint, err := httpapi.JoinPaths(cfg.API.AddressToURL(), httpapi.MigratePath, "relations")
+			if err != nil {
+				return NewErrFailedToJoinEndpoint(err)
+			}
+			req, err := http.NewRequestWithContext(
+				cmd.Context(),
+				http.MethodPost,
+				endpoint.String(),
+				bytes.NewBuffer(body),
+			)
+			if err != nil {
+				return NewErrFailedToSendRequest(err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+			if cfg.API.AdminToken != "" {
+				req.Header.Set("Authorization", "Bearer "+cfg.API.AdminToken)
+			}
+
+			log.FeedbackInfo(cmd.Context(), "Migrating relations...")
+			res, err := http.DefaultClient.Do(req)
+			if err != nil {
+				return NewErrFailedToSendRequest(err)
+			}
+			defer func() {
+				if e := res.Body.Close(); e != nil && err == nil {
+					err = NewErrFailedToReadResponseBody(e)
+				}
+			}()
+
+			if res.StatusCode != http.StatusOK {
+				r := httpapi.ErrorResponse{}
+				if err := json.NewDecoder(res.Body).Decode(&r); err != nil {
+					return NewErrFailedToUnmarshalResponse(err)
+				}
+				if len(r.Errors) > 0 {
+					return errors.New(r.Errors[0].Message)
+				}
+				return errors.New("migration request failed", errors.NewKV("Status", res.StatusCode))
+			}
+
+			decoder := json.NewDecoder(res.Body)
+			for decoder.More() {
+				var event struct {
+					client.MigrateRelationsProgress
+					Error string `json:"error"`
+				}
+				if err := decoder.Decode(&event); err != nil {
+					return NewErrFailedToUnmarshalResponse(err)
+				}
+				if event.Error != "" {
+					return errors.New(event.Error, errors.NewKV("Collection", event.Collection))
+				}
+				msg := "Migrating relations"
+				if event.Done {
+					msg = "Relations migrated"
+				}
+				log.FeedbackInfo(
+					cmd.Context(),
+					msg,
+					logging.NewKV("Collection", event.Collection),
+					logging.NewKV("Scanned", event.Scanned),
+					logging.NewKV("Migrated", event.Migrated),
+					logging.NewKV("Skipped", event.Skipped),
+				)
+				if event.Done {
+					return nil
+				}
+			}
+			return errors.New("migration ended before completing")
+		},
+	}
+	cmd.Flags().StringArrayVar(
+		&collections, "collection", nil,
+		"Collection to migrate, all collections are migrated if none is given",
+	)
+	cmd.Flags().IntVar(&batchSize, "batch-size", 100, "Number of documents migrated per transaction")
+	cmd.Flags().StringVar(
+		&keysFile, "keys", "",
+		"JSON file mapping the previous keys of re-keyed documents to their new keys",
+	)
+	return cmd
+}
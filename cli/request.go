@@ -25,6 +25,7 @@ import (
 )
 
 func MakeRequestCommand(cfg *config.Config) *cobra.Command {
+	var branch string
 	var cmd = &cobra.Command{
 		Use:   "query [query request]",
 		Short: "Send a DefraDB GraphQL query request",
@@ -39,6 +40,10 @@ cat request.graphql | defradb client query -
 A GraphQL client such as GraphiQL (https://github.com/graphql/graphiql) can be used to interact
 with the database more conveniently.
 
+The request is sent to a branch of the database with the --branch flag, the admin token of the
+configuration authenticating it. Example command:
+defradb client query --branch dev 'query { ... }'
+
 To learn more about the DefraDB GraphQL Query Language, refer to https://docs.source.network.`,
 		RunE: func(cmd *cobra.Command, args []string) (err error) {
 			var request string
@@ -86,6 +91,9 @@ To learn more about the DefraDB GraphQL Query Language, refer to https://docs.so
 			}
 
 			endpoint, err := httpapi.JoinPaths(cfg.API.AddressToURL(), httpapi.GraphQLPath)
+			if branch != "" {
+				endpoint, err = httpapi.JoinPaths(cfg.API.AddressToURL(), httpapi.BranchesPath, branch, "graphql")
+			}
 			if err != nil {
 				return errors.Wrap("joining paths failed", err)
 			}
@@ -94,7 +102,14 @@ To learn more about the DefraDB GraphQL Query Language, refer to https://docs.so
 			p.Add("query", request)
 			endpoint.RawQuery = p.Encode()
 
-			res, err := http.Get(endpoint.String())
+			req, err := http.NewRequestWithContext(cmd.Context(), http.MethodGet, endpoint.String(), nil)
+			if err != nil {
+				return errors.Wrap("failed request", err)
+			}
+			if branch != "" && cfg.API.AdminToken != "" {
+				req.Header.Set("Authorization", "Bearer "+cfg.API.AdminToken)
+			}
+			res, err := http.DefaultClient.Do(req)
 			if err != nil {
 				return errors.Wrap("failed request", err)
 			}
@@ -135,6 +150,7 @@ To learn more about the DefraDB GraphQL Query Language, refer to https://docs.so
 			return nil
 		},
 	}
+	cmd.Flags().StringVar(&branch, "branch", "", "Branch of the database to send the request to")
 
 	return cmd
 }
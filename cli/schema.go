@@ -11,7 +11,17 @@
 package cli
 
 import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/libp2p/go-libp2p/core/crypto"
 	"github.com/spf13/cobra"
+
+	httpapi "github.com/sourcenetwork/defradb/api/http"
+	"github.com/sourcenetwork/defradb/client"
 )
 
 func MakeSchemaCommand() *cobra.Command {
@@ -23,3 +33,40 @@ func MakeSchemaCommand() *cobra.Command {
 
 	return cmd
 }
+
+// newSchemaUpdateRequest returns the request applying the given schema update to the given
+// endpoint, signed with the private key at the given path if not empty.
+//
+// The key file is in the format of the node key files, so that the key of a node can be used as
+// the schema admin identity.
+func newSchemaUpdateRequest(
+	ctx context.Context,
+	endpoint string,
+	kind client.SchemaUpdateKind,
+	update string,
+	signKeyPath string,
+) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(update))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "text")
+
+	if signKeyPath == "" {
+		return req, nil
+	}
+	buf, err := os.ReadFile(signKeyPath)
+	if err != nil {
+		return nil, NewFailedToReadFile(err)
+	}
+	key, err := crypto.UnmarshalPrivateKey(buf)
+	if err != nil {
+		return nil, NewErrFailedToSignSchemaUpdate(err)
+	}
+	signature, err := key.Sign(client.SchemaUpdatePayload(kind, update))
+	if err != nil {
+		return nil, NewErrFailedToSignSchemaUpdate(err)
+	}
+	req.Header.Set(httpapi.SchemaSignatureHeader, base64.StdEncoding.EncodeToString(signature))
+	return req, nil
+}
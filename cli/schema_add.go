@@ -16,17 +16,18 @@ import (
 	"io"
 	"net/http"
 	"os"
-	"strings"
 
 	"github.com/spf13/cobra"
 
 	httpapi "github.com/sourcenetwork/defradb/api/http"
+	"github.com/sourcenetwork/defradb/client"
 	"github.com/sourcenetwork/defradb/config"
 	"github.com/sourcenetwork/defradb/errors"
 )
 
 func MakeSchemaAddCommand(cfg *config.Config) *cobra.Command {
 	var schemaFile string
+	var signKeyPath string
 	var cmd = &cobra.Command{
 		Use:   "add [schema]",
 		Short: "Add a new schema type to DefraDB",
@@ -98,7 +99,13 @@ Learn more about the DefraDB GraphQL Schema Language on https://docs.source.netw
 				return errors.Wrap("join paths failed", err)
 			}
 
-			res, err := http.Post(endpoint.String(), "text", strings.NewReader(schema))
+			req, err := newSchemaUpdateRequest(
+				cmd.Context(), endpoint.String(), client.SchemaUpdateAdd, schema, signKeyPath,
+			)
+			if err != nil {
+				return err
+			}
+			res, err := http.DefaultClient.Do(req)
 			if err != nil {
 				return errors.Wrap("failed to post schema", err)
 			}
@@ -149,5 +156,9 @@ Learn more about the DefraDB GraphQL Schema Language on https://docs.source.netw
 		},
 	}
 	cmd.Flags().StringVarP(&schemaFile, "file", "f", "", "File to load a schema from")
+	cmd.Flags().StringVar(
+		&signKeyPath, "sign-key", "",
+		"Private key file to sign the schema with, required if the node has a schema admin identity",
+	)
 	return cmd
 }
@@ -0,0 +1,106 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+
+	"github.com/spf13/cobra"
+
+	httpapi "github.com/sourcenetwork/defradb/api/http"
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/config"
+	"github.com/sourcenetwork/defradb/errors"
+	"github.com/sourcenetwork/defradb/logging"
+)
+
+// MakeSchemaCloneCommand returns the command cloning a collection, along with its documents, into
+// a new collection.
+func MakeSchemaCloneCommand(cfg *config.Config) *cobra.Command {
+	var filter string
+	var cmd = &cobra.Command{
+		Use:   "clone [SOURCE] [NAME]",
+		Short: "Clone a collection and its documents into a new collection",
+		Long: `Clone a collection and its documents into a new collection.
+
+The new collection has the current schema of the source collection under the new name, and
+holds copies of its documents, optionally restricted to the ones matching a filter. The copies
+have a history of their own, so that the new collection can be used to try out schema changes
+and updates against the data of the source collection without altering it.
+
+Collections with relation fields can't be cloned.
+
+Example: clone the adult users into a staging collection
+  defradb client schema clone User StagingUser --filter '{age: {_gte: 18}}'`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			body, err := json.Marshal(map[string]string{"name": args[1], "filter": filter})
+			if err != nil {
+				return errors.Wrap("failed to marshal clone request", err)
+			}
+
+			endpoint, err := httpapi.JoinPaths(cfg.API.AddressToURL(), httpapi.CollectionsPath, args[0], "clone")
+			if err != nil {
+				return NewErrFailedToJoinEndpoint(err)
+			}
+			req, err := http.NewRequestWithContext(
+				cmd.Context(),
+				http.MethodPost,
+				endpoint.String(),
+				bytes.NewBuffer(body),
+			)
+			if err != nil {
+				return NewErrFailedToSendRequest(err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+
+			res, err := http.DefaultClient.Do(req)
+			if err != nil {
+				return NewErrFailedToSendRequest(err)
+			}
+			defer func() {
+				if e := res.Body.Close(); e != nil && err == nil {
+					err = NewErrFailedToReadResponseBody(e)
+				}
+			}()
+
+			if res.StatusCode != http.StatusOK {
+				r := httpapi.ErrorResponse{}
+				if err := json.NewDecoder(res.Body).Decode(&r); err != nil {
+					return NewErrFailedToUnmarshalResponse(err)
+				}
+				if len(r.Errors) > 0 {
+					return errors.New(r.Errors[0].Message)
+				}
+				return errors.New("clone request failed", errors.NewKV("Status", res.StatusCode))
+			}
+
+			r := struct {
+				Data client.CloneCollectionResult `json:"data"`
+			}{}
+			if err := json.NewDecoder(res.Body).Decode(&r); err != nil {
+				return NewErrFailedToUnmarshalResponse(err)
+			}
+			log.FeedbackInfo(
+				cmd.Context(),
+				"Collection cloned",
+				logging.NewKV("Name", r.Data.Collection.Name),
+				logging.NewKV("SchemaID", r.Data.Collection.Schema.SchemaID),
+				logging.NewKV("Documents", r.Data.Documents),
+			)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&filter, "filter", "", "Filter of the documents to copy")
+	return cmd
+}
@@ -15,16 +15,17 @@ import (
 	"io"
 	"net/http"
 	"os"
-	"strings"
 
 	"github.com/spf13/cobra"
 
 	httpapi "github.com/sourcenetwork/defradb/api/http"
+	"github.com/sourcenetwork/defradb/client"
 	"github.com/sourcenetwork/defradb/config"
 )
 
 func MakeSchemaPatchCommand(cfg *config.Config) *cobra.Command {
 	var patchFile string
+	var signKeyPath string
 
 	var cmd = &cobra.Command{
 		Use:   "patch [schema]",
@@ -100,7 +101,13 @@ To learn more about the DefraDB GraphQL Schema Language, refer to https://docs.s
 				return err
 			}
 
-			res, err := http.Post(endpoint.String(), "text", strings.NewReader(patch))
+			req, err := newSchemaUpdateRequest(
+				cmd.Context(), endpoint.String(), client.SchemaUpdatePatch, patch, signKeyPath,
+			)
+			if err != nil {
+				return err
+			}
+			res, err := http.DefaultClient.Do(req)
 			if err != nil {
 				return NewErrFailedToSendRequest(err)
 			}
@@ -147,5 +154,9 @@ To learn more about the DefraDB GraphQL Schema Language, refer to https://docs.s
 		},
 	}
 	cmd.Flags().StringVarP(&patchFile, "file", "f", "", "File to load a patch from")
+	cmd.Flags().StringVar(
+		&signKeyPath, "sign-key", "",
+		"Private key file to sign the patch with, required if the node has a schema admin identity",
+	)
 	return cmd
 }
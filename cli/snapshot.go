@@ -0,0 +1,292 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	httpapi "github.com/sourcenetwork/defradb/api/http"
+	"github.com/sourcenetwork/defradb/config"
+	"github.com/sourcenetwork/defradb/datastore"
+	badgerds "github.com/sourcenetwork/defradb/datastore/badger/v3"
+	"github.com/sourcenetwork/defradb/datastore/snapshot"
+	"github.com/sourcenetwork/defradb/db"
+	"github.com/sourcenetwork/defradb/errors"
+	"github.com/sourcenetwork/defradb/logging"
+)
+
+// MakeSnapshotCommand returns the command downloading a snapshot archive of the datastore of the
+// node.
+func MakeSnapshotCommand(cfg *config.Config) *cobra.Command {
+	var since string
+	var cmd = &cobra.Command{
+		Use:   "snapshot [FILE]",
+		Short: "Download a snapshot archive of the entire datastore of the node",
+		Long: `Download a consistent snapshot archive of the entire datastore of the node.
+
+A new node is bootstrapped from the archive with the restore-snapshot command. The admin token
+of the configuration authenticates the request.
+
+The masked fields are not masked in the archive.
+
+With --since, only the documents whose heads have changed since the given previous archive, full
+or incremental, are downloaded, along with the blocks added to their DAGs. The incremental archive
+is restored after the archives it follows.
+
+Example: download a full archive, then an incremental one
+  defradb client snapshot backup-0.gz
+  defradb client snapshot backup-1.gz --since backup-0.gz`,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			if len(args) != 1 {
+				return NewErrMissingArg("FILE")
+			}
+
+			endpoint, err := httpapi.JoinPaths(cfg.API.AddressToURL(), httpapi.SnapshotPath)
+			if err != nil {
+				return NewErrFailedToJoinEndpoint(err)
+			}
+			// The changes since a previous archive are requested with the manifest of its heads.
+			method, body := http.MethodGet, []byte(nil)
+			if since != "" {
+				manifest, err := readSnapshotManifest(since)
+				if err != nil {
+					return err
+				}
+				method = http.MethodPost
+				body, err = json.Marshal(manifest)
+				if err != nil {
+					return errors.Wrap("failed to marshal snapshot manifest", err)
+				}
+			}
+			req, err := http.NewRequestWithContext(cmd.Context(), method, endpoint.String(), bytes.NewReader(body))
+			if err != nil {
+				return NewErrFailedToSendRequest(err)
+			}
+			if cfg.API.AdminToken != "" {
+				req.Header.Set("Authorization", "Bearer "+cfg.API.AdminToken)
+			}
+
+			log.FeedbackInfo(cmd.Context(), "Requesting snapshot...")
+			res, err := http.DefaultClient.Do(req)
+			if err != nil {
+				return NewErrFailedToSendRequest(err)
+			}
+			defer func() {
+				if e := res.Body.Close(); e != nil && err == nil {
+					err = NewErrFailedToReadResponseBody(e)
+				}
+			}()
+
+			if res.StatusCode != http.StatusOK {
+				r := httpapi.ErrorResponse{}
+				if err := json.NewDecoder(res.Body).Decode(&r); err != nil {
+					return NewErrFailedToUnmarshalResponse(err)
+				}
+				if len(r.Errors) > 0 {
+					return errors.New(r.Errors[0].Message)
+				}
+				return errors.New("snapshot request failed", errors.NewKV("Status", res.StatusCode))
+			}
+
+			// The archive is downloaded to a temporary file first, so that an interrupted download
+			// does not leave a truncated archive at the given path.
+			file, err := os.CreateTemp(filepath.Dir(args[0]), ".defradb-snapshot-*")
+			if err != nil {
+				return errors.Wrap("failed to create snapshot file", err)
+			}
+			defer func() {
+				if err := os.Remove(file.Name()); err != nil && !os.IsNotExist(err) {
+					log.FeedbackErrorE(cmd.Context(), "Failed to remove temporary snapshot file", err)
+				}
+			}()
+			size, err := io.Copy(file, res.Body)
+			if err != nil {
+				_ = file.Close()
+				return NewErrFailedToReadResponseBody(err)
+			}
+			if err := file.Close(); err != nil {
+				return errors.Wrap("failed to write snapshot file", err)
+			}
+			if err := os.Rename(file.Name(), args[0]); err != nil {
+				return errors.Wrap("failed to write snapshot file", err)
+			}
+
+			log.FeedbackInfo(
+				cmd.Context(),
+				"Snapshot downloaded",
+				logging.NewKV("File", args[0]),
+				logging.NewKV("Size", size),
+			)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&since, "since", "", "Previous snapshot archive to download the changes since")
+	return cmd
+}
+
+// readSnapshotManifest returns the manifest of the heads archived by the snapshot archive of the
+// given file.
+func readSnapshotManifest(path string) (snapshot.Manifest, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return snapshot.Manifest{}, NewFailedToReadFile(err)
+	}
+	defer file.Close() //nolint:errcheck
+	manifest, err := snapshot.ReadManifest(file)
+	if err != nil {
+		return snapshot.Manifest{}, errors.Wrap("failed to read previous snapshot", err)
+	}
+	return manifest, nil
+}
+
+// MakeRestoreSnapshotCommand returns the command bootstrapping the datastore of a new node from a
+// snapshot archive.
+func MakeRestoreSnapshotCommand(cfg *config.Config) *cobra.Command {
+	var history string
+	var until string
+	var height uint64
+	var cmd = &cobra.Command{
+		Use:   "restore-snapshot [FILE]",
+		Short: "Bootstrap the datastore of a new node from a snapshot archive",
+		Long: `Bootstrap the datastore of a new node from a snapshot archive.
+
+The datastore must not hold a database yet, and the node must not be running. Once started,
+the node catches up with the changes made since the snapshot was taken through the P2P
+synchronization.
+
+An incremental archive is restored on top of the database restored from the archives it
+follows, in the order they were taken.
+
+With --history, the commits of a later archive, such as one taken after a bad bulk update, are
+then replayed on top of the restored database up to the time given by --until, or the height
+given by --height, recovering the documents as they were at that point. The schema updates made
+after the restored archive was taken are not replayed.
+
+Example: recover the documents as they were before a bad update made at 14:00
+  defradb restore-snapshot backup.gz --history latest.gz --until 2023-06-01T13:59:00Z`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return NewErrMissingArg("FILE")
+			}
+			if cfg.Datastore.Store != badgerDatastoreName {
+				return errors.New("restoring a snapshot is only supported for the Badger datastore")
+			}
+			target := snapshot.ReplayTarget{Height: height}
+			if until != "" {
+				t, err := time.Parse(time.RFC3339, until)
+				if err != nil {
+					return errors.Wrap("invalid --until time", err)
+				}
+				target.Until = t
+			}
+
+			file, err := os.Open(args[0])
+			if err != nil {
+				return NewFailedToReadFile(err)
+			}
+			defer func() {
+				if err := file.Close(); err != nil {
+					log.FeedbackErrorE(cmd.Context(), "Failed to close snapshot file", err)
+				}
+			}()
+
+			rootstore, err := openBadgerstore(cmd.Context(), cfg)
+			if err != nil {
+				return errors.Wrap("failed to open datastore", err)
+			}
+			defer func() {
+				if err := rootstore.Close(); err != nil {
+					log.FeedbackErrorE(cmd.Context(), "Failed to close datastore", err)
+				}
+			}()
+
+			log.FeedbackInfo(cmd.Context(), "Restoring snapshot...", logging.NewKV("File", args[0]))
+			count, err := snapshot.Restore(cmd.Context(), rootstore, file)
+			if err != nil {
+				return errors.Wrap("failed to restore snapshot", err)
+			}
+			log.FeedbackInfo(cmd.Context(), "Snapshot restored", logging.NewKV("Entries", count))
+
+			if history == "" {
+				return nil
+			}
+			log.FeedbackInfo(cmd.Context(), "Replaying history...", logging.NewKV("File", history))
+			result, err := replaySnapshotHistory(cmd.Context(), rootstore, history, target)
+			if err != nil {
+				return errors.Wrap("failed to replay history", err)
+			}
+			log.FeedbackInfo(
+				cmd.Context(),
+				"History replayed",
+				logging.NewKV("Documents", result.Documents),
+				logging.NewKV("Commits", result.Commits),
+				logging.NewKV("Blocks", result.Blocks),
+			)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&history, "history", "", "Later full snapshot archive whose history is replayed")
+	cmd.Flags().StringVar(&until, "until", "", "Time up to which the history is replayed, in RFC 3339 format")
+	cmd.Flags().Uint64Var(&height, "height", 0, "Height up to which the history is replayed")
+	return cmd
+}
+
+// replaySnapshotHistory replays the history of the snapshot archive of the given file up to the
+// given target on top of the database of the given rootstore.
+//
+// The archive is restored to a temporary datastore first, removed once the history is replayed.
+func replaySnapshotHistory(
+	ctx context.Context,
+	rootstore datastore.RootStore,
+	path string,
+	target snapshot.ReplayTarget,
+) (snapshot.ReplayResult, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return snapshot.ReplayResult{}, NewFailedToReadFile(err)
+	}
+	defer file.Close() //nolint:errcheck
+
+	dir, err := os.MkdirTemp("", "defradb-history-*")
+	if err != nil {
+		return snapshot.ReplayResult{}, err
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			log.FeedbackErrorE(ctx, "Failed to remove temporary history datastore", err)
+		}
+	}()
+	opts := badgerds.DefaultOptions
+	history, err := badgerds.NewDatastore(dir, &opts)
+	if err != nil {
+		return snapshot.ReplayResult{}, err
+	}
+	defer history.Close() //nolint:errcheck
+	if _, err := snapshot.Restore(ctx, history, file); err != nil {
+		return snapshot.ReplayResult{}, err
+	}
+
+	d, err := db.NewDB(ctx, rootstore)
+	if err != nil {
+		return snapshot.ReplayResult{}, err
+	}
+	defer d.Close(ctx)
+	return snapshot.Replay(ctx, d, history, target)
+}
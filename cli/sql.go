@@ -0,0 +1,99 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package cli
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	httpapi "github.com/sourcenetwork/defradb/api/http"
+	"github.com/sourcenetwork/defradb/config"
+	"github.com/sourcenetwork/defradb/errors"
+)
+
+// MakeSQLCommand returns the command executing a SQL query.
+func MakeSQLCommand(cfg *config.Config) *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "sql [query]",
+		Short: "Send a SQL query",
+		Long: `Send a SELECT statement to the database, which translates it into a GraphQL request.
+
+Collections are joined along their relation fields, which imply the join conditions. Example command:
+defradb client sql 'SELECT a.name, b.name FROM Author a JOIN a.published b WHERE b.rating > 4'
+
+Or it can be sent via stdin by using the '-' special syntax. Example command:
+cat query.sql | defradb client sql -
+
+The rows are returned with the names of their columns.`,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			if len(args) != 1 {
+				return NewErrMissingArg("query")
+			}
+			query := args[0]
+			if query == "-" {
+				query, err = readStdin()
+				if err != nil {
+					return errors.Wrap("failed to read stdin", err)
+				}
+			}
+			if query == "" {
+				return errors.New("query cannot be empty")
+			}
+
+			endpoint, err := httpapi.JoinPaths(cfg.API.AddressToURL(), httpapi.SQLPath)
+			if err != nil {
+				return NewErrFailedToJoinEndpoint(err)
+			}
+			p := url.Values{}
+			p.Add("query", query)
+			endpoint.RawQuery = p.Encode()
+
+			res, err := http.Get(endpoint.String())
+			if err != nil {
+				return NewErrFailedToSendRequest(err)
+			}
+			defer func() {
+				if e := res.Body.Close(); e != nil && err == nil {
+					err = NewErrFailedToReadResponseBody(e)
+				}
+			}()
+
+			response, err := io.ReadAll(res.Body)
+			if err != nil {
+				return NewErrFailedToReadResponseBody(err)
+			}
+
+			fi, err := os.Stdout.Stat()
+			if err != nil {
+				return errors.Wrap("failed to stat stdout", err)
+			}
+			if isFileInfoPipe(fi) {
+				cmd.Println(string(response))
+				return nil
+			}
+			indentedResult, err := indentJSON(response)
+			if err != nil {
+				return errors.Wrap("failed to pretty print result", err)
+			}
+			if res.StatusCode != http.StatusOK {
+				log.FeedbackError(cmd.Context(), indentedResult)
+			} else {
+				log.FeedbackInfo(cmd.Context(), indentedResult)
+			}
+			return nil
+		},
+	}
+	return cmd
+}
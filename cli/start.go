@@ -18,6 +18,9 @@ import (
 	"os"
 	"os/signal"
 	"strings"
+	"sync"
+	"syscall"
+	"time"
 
 	badger "github.com/dgraph-io/badger/v3"
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
@@ -25,16 +28,24 @@ import (
 	ma "github.com/multiformats/go-multiaddr"
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/keepalive"
 
 	httpapi "github.com/sourcenetwork/defradb/api/http"
+	"github.com/sourcenetwork/defradb/api/pgwire"
 	"github.com/sourcenetwork/defradb/client"
 	"github.com/sourcenetwork/defradb/config"
 	ds "github.com/sourcenetwork/defradb/datastore"
 	badgerds "github.com/sourcenetwork/defradb/datastore/badger/v3"
+	"github.com/sourcenetwork/defradb/datastore/standby"
+	standbypb "github.com/sourcenetwork/defradb/datastore/standby/pb"
+	"github.com/sourcenetwork/defradb/datastore/tiered"
 	"github.com/sourcenetwork/defradb/db"
 	"github.com/sourcenetwork/defradb/errors"
+	"github.com/sourcenetwork/defradb/events/pinning"
+	"github.com/sourcenetwork/defradb/events/sink"
 	"github.com/sourcenetwork/defradb/logging"
+	"github.com/sourcenetwork/defradb/net"
 	netapi "github.com/sourcenetwork/defradb/net/api"
 	netpb "github.com/sourcenetwork/defradb/net/api/pb"
 	netutils "github.com/sourcenetwork/defradb/net/utils"
@@ -73,12 +84,16 @@ func MakeStartCommand(cfg *config.Config) *cobra.Command {
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if cfg.Replication.Role == config.ReplicationRoleStandby {
+				return runStandby(cmd.Context(), cfg)
+			}
+
 			di, err := start(cmd.Context(), cfg)
 			if err != nil {
 				return err
 			}
 
-			return wait(cmd.Context(), di)
+			return wait(cmd.Context(), cfg, di)
 		},
 	}
 
@@ -100,6 +115,69 @@ func MakeStartCommand(cfg *config.Config) *cobra.Command {
 		log.FeedbackFatalE(context.Background(), "Could not bind datastore.maxtxnretries", err)
 	}
 
+	cmd.Flags().String(
+		"txn-retry-backoff", cfg.Datastore.TxnRetryBackoff,
+		"Specify the initial delay before retrying a conflicting transaction (0 disables the retries)",
+	)
+	err = cfg.BindFlag("datastore.txnretrybackoff", cmd.Flags().Lookup("txn-retry-backoff"))
+	if err != nil {
+		log.FeedbackFatalE(context.Background(), "Could not bind datastore.txnretrybackoff", err)
+	}
+
+	cmd.Flags().Int(
+		"document-cache-size", cfg.Datastore.DocumentCacheSize,
+		"Specify the maximum number of recently fetched documents cached per collection (0 disables the cache)",
+	)
+	err = cfg.BindFlag("datastore.documentcachesize", cmd.Flags().Lookup("document-cache-size"))
+	if err != nil {
+		log.FeedbackFatalE(context.Background(), "Could not bind datastore.documentcachesize", err)
+	}
+
+	cmd.Flags().Int(
+		"max-read-txns", cfg.Datastore.MaxReadTxns,
+		"Specify the maximum number of concurrent read only transactions, the others being queued (0 is unlimited)",
+	)
+	err = cfg.BindFlag("datastore.maxreadtxns", cmd.Flags().Lookup("max-read-txns"))
+	if err != nil {
+		log.FeedbackFatalE(context.Background(), "Could not bind datastore.maxreadtxns", err)
+	}
+
+	cmd.Flags().Int(
+		"max-request-cost", cfg.Datastore.MaxRequestCost,
+		"Specify the maximum cost of the requests, weighted by the @cost multipliers of the collections (0 is unlimited)",
+	)
+	err = cfg.BindFlag("datastore.maxrequestcost", cmd.Flags().Lookup("max-request-cost"))
+	if err != nil {
+		log.FeedbackFatalE(context.Background(), "Could not bind datastore.maxrequestcost", err)
+	}
+
+	cmd.Flags().Bool(
+		"compress-blocks", cfg.Datastore.CompressBlocks,
+		"Compress the blocks at rest",
+	)
+	err = cfg.BindFlag("datastore.compressblocks", cmd.Flags().Lookup("compress-blocks"))
+	if err != nil {
+		log.FeedbackFatalE(context.Background(), "Could not bind datastore.compressblocks", err)
+	}
+
+	cmd.Flags().Bool(
+		"check-integrity", cfg.Datastore.CheckIntegrity,
+		"Check the integrity of the system keyspace on startup",
+	)
+	err = cfg.BindFlag("datastore.checkintegrity", cmd.Flags().Lookup("check-integrity"))
+	if err != nil {
+		log.FeedbackFatalE(context.Background(), "Could not bind datastore.checkintegrity", err)
+	}
+
+	cmd.Flags().Bool(
+		"repair", cfg.Datastore.RepairIntegrity,
+		"Check the integrity of the system keyspace on startup, repairing the issues known to be fixable",
+	)
+	err = cfg.BindFlag("datastore.repairintegrity", cmd.Flags().Lookup("repair"))
+	if err != nil {
+		log.FeedbackFatalE(context.Background(), "Could not bind datastore.repairintegrity", err)
+	}
+
 	cmd.Flags().String(
 		"store", cfg.Datastore.Store,
 		"Specify the datastore to use (supported: badger, memory)",
@@ -145,6 +223,42 @@ func MakeStartCommand(cfg *config.Config) *cobra.Command {
 		log.FeedbackFatalE(context.Background(), "Could not bind net.p2pdisabled", err)
 	}
 
+	cmd.Flags().Bool(
+		"mdns", cfg.Net.MDNSEnabled,
+		"Discover and connect to the other nodes of the local network through mDNS",
+	)
+	err = cfg.BindFlag("net.mdns", cmd.Flags().Lookup("mdns"))
+	if err != nil {
+		log.FeedbackFatalE(context.Background(), "Could not bind net.mdns", err)
+	}
+
+	cmd.Flags().Bool(
+		"rendezvous", cfg.Net.RendezvousEnabled,
+		"Advertise the P2P collections on the DHT and connect to the peers holding the same collections",
+	)
+	err = cfg.BindFlag("net.rendezvous", cmd.Flags().Lookup("rendezvous"))
+	if err != nil {
+		log.FeedbackFatalE(context.Background(), "Could not bind net.rendezvous", err)
+	}
+
+	cmd.Flags().Float64(
+		"ingest-peer-rate", cfg.Net.IngestPeerRate,
+		"Number of push logs ingested per second from each peer (unlimited if zero)",
+	)
+	err = cfg.BindFlag("net.ingestpeerrate", cmd.Flags().Lookup("ingest-peer-rate"))
+	if err != nil {
+		log.FeedbackFatalE(context.Background(), "Could not bind net.ingestpeerrate", err)
+	}
+
+	cmd.Flags().Float64(
+		"ingest-collection-rate", cfg.Net.IngestCollectionRate,
+		"Number of push logs ingested per second into each collection (unlimited if zero)",
+	)
+	err = cfg.BindFlag("net.ingestcollectionrate", cmd.Flags().Lookup("ingest-collection-rate"))
+	if err != nil {
+		log.FeedbackFatalE(context.Background(), "Could not bind net.ingestcollectionrate", err)
+	}
+
 	cmd.Flags().Bool(
 		"tls", cfg.API.TLS,
 		"Enable serving the API over https",
@@ -180,16 +294,157 @@ func MakeStartCommand(cfg *config.Config) *cobra.Command {
 	if err != nil {
 		log.FeedbackFatalE(context.Background(), "Could not bind api.email", err)
 	}
+
+	cmd.Flags().String(
+		"allowed-origins", cfg.API.AllowedOrigins,
+		"Comma separated list of origins allowed to make CORS requests",
+	)
+	err = cfg.BindFlag("api.allowedorigins", cmd.Flags().Lookup("allowed-origins"))
+	if err != nil {
+		log.FeedbackFatalE(context.Background(), "Could not bind api.allowedorigins", err)
+	}
+
+	cmd.Flags().Int(
+		"rate-limit", cfg.API.RateLimit,
+		"Maximum number of requests per second served by the API (0 means unlimited)",
+	)
+	err = cfg.BindFlag("api.ratelimit", cmd.Flags().Lookup("rate-limit"))
+	if err != nil {
+		log.FeedbackFatalE(context.Background(), "Could not bind api.ratelimit", err)
+	}
+
+	cmd.Flags().String(
+		"error-verbosity", cfg.API.ErrorVerbosity,
+		"Detail of the errors returned by the API: message, code or stack",
+	)
+	err = cfg.BindFlag("api.errorverbosity", cmd.Flags().Lookup("error-verbosity"))
+	if err != nil {
+		log.FeedbackFatalE(context.Background(), "Could not bind api.errorverbosity", err)
+	}
+
+	cmd.Flags().Bool(
+		"profiling", cfg.API.Profiling,
+		"Serve the profiling endpoints, which require the admin token",
+	)
+	err = cfg.BindFlag("api.profiling", cmd.Flags().Lookup("profiling"))
+	if err != nil {
+		log.FeedbackFatalE(context.Background(), "Could not bind api.profiling", err)
+	}
+
+	cmd.Flags().String(
+		"metadata-headers", cfg.API.MetadataHeaders,
+		"Comma separated list of the HTTP headers passed to the request hooks as the request metadata",
+	)
+	err = cfg.BindFlag("api.metadataheaders", cmd.Flags().Lookup("metadata-headers"))
+	if err != nil {
+		log.FeedbackFatalE(context.Background(), "Could not bind api.metadataheaders", err)
+	}
+
+	cmd.Flags().String(
+		"gateway-remotes", cfg.API.GatewayRemotes,
+		"Comma separated list of the URLs of the remote nodes the queries for the collections not held are delegated to",
+	)
+	err = cfg.BindFlag("api.gatewayremotes", cmd.Flags().Lookup("gateway-remotes"))
+	if err != nil {
+		log.FeedbackFatalE(context.Background(), "Could not bind api.gatewayremotes", err)
+	}
 	return cmd
 }
 
+// shutdownTimeout is the maximum amount of time in-flight requests are given to
+// complete when the node is shutting down.
+const shutdownTimeout = 10 * time.Second
+
 type defraInstance struct {
-	node   *node.Node
-	db     client.DB
-	server *httpapi.Server
+	node      *node.Node
+	db        client.DB
+	server    *httpapi.Server
+	rpcServer *grpc.Server
+	// primaryServer serves the changes of the datastore to the standby, if any.
+	primaryServer *grpc.Server
+	// pgServer serves the collections over the Postgres wire protocol, if enabled.
+	pgServer *pgwire.Server
+	sink     *sink.Sink
+	pinner   *pinning.Pinner
+
+	// the bootstrap peers the node has been connected to.
+	peers string
+	mu    sync.Mutex
+}
+
+// reload reloads the configuration from the config file and environment
+// and applies the runtime configurable settings.
+func (di *defraInstance) reload(ctx context.Context, cfg *config.Config) {
+	if err := cfg.Reload(); err != nil {
+		log.FeedbackErrorE(ctx, "Failed to reload the configuration", err)
+		return
+	}
+	di.server.ApplyConfig()
+	di.applyConfig(ctx, cfg)
+	log.FeedbackInfo(ctx, "Configuration reloaded")
+}
+
+// applyConfig applies the runtime configurable settings that are not owned by the HTTP server.
+func (di *defraInstance) applyConfig(ctx context.Context, cfg *config.Config) {
+	di.mu.Lock()
+	defer di.mu.Unlock()
+
+	if di.node == nil {
+		return
+	}
+	if err := di.node.SetMDNS(cfg.Net.MDNSEnabled); err != nil {
+		log.FeedbackErrorE(ctx, "Failed to apply the mDNS discovery setting", err)
+	}
+	di.node.SetRendezvous(cfg.Net.RendezvousEnabled)
+
+	if cfg.Net.Peers == di.peers {
+		return
+	}
+	di.peers = cfg.Net.Peers
+	if di.peers == "" {
+		return
+	}
+	addrs, err := netutils.ParsePeers(strings.Split(di.peers, ","))
+	if err != nil {
+		log.FeedbackErrorE(ctx, fmt.Sprintf("Failed to parse bootstrap peers %v", di.peers), err)
+		return
+	}
+	log.Debug(ctx, "Bootstrapping with peers", logging.NewKV("Addresses", addrs))
+	di.node.Boostrap(addrs)
 }
 
+// close shuts the instance down in order: the HTTP server stops accepting new requests
+// and drains the in-flight ones, the RPC, Postgres and replication servers are stopped, the node (or the database
+// if P2P is disabled) is closed, and the events sink and the pinner are closed last.
 func (di *defraInstance) close(ctx context.Context) {
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := di.server.Shutdown(shutdownCtx); err != nil {
+		log.FeedbackInfo(
+			ctx,
+			"The server could not be closed successfully",
+			logging.NewKV("Error", err.Error()),
+		)
+	}
+
+	if di.rpcServer != nil {
+		di.rpcServer.GracefulStop()
+	}
+	if di.pgServer != nil {
+		if err := di.pgServer.Close(); err != nil {
+			log.FeedbackInfo(
+				ctx,
+				"The Postgres server could not be closed successfully",
+				logging.NewKV("Error", err.Error()),
+			)
+		}
+	}
+	// The streams of the datastore changes never end by themselves, so they are not drained.
+	if di.primaryServer != nil {
+		di.primaryServer.Stop()
+	}
+
 	if di.node != nil {
 		if err := di.node.Close(); err != nil {
 			log.FeedbackInfo(
@@ -198,79 +453,262 @@ func (di *defraInstance) close(ctx context.Context) {
 				logging.NewKV("Error", err.Error()),
 			)
 		}
+	} else {
+		di.db.Close(ctx)
 	}
-	di.db.Close(ctx)
-	if err := di.server.Close(); err != nil {
-		log.FeedbackInfo(
-			ctx,
-			"The server could not be closed successfully",
-			logging.NewKV("Error", err.Error()),
-		)
+
+	// The sink is closed once the database is, so that all the update events are published.
+	if di.sink != nil {
+		if err := di.sink.Close(); err != nil {
+			log.FeedbackInfo(
+				ctx,
+				"The events sink could not be closed successfully",
+				logging.NewKV("Error", err.Error()),
+			)
+		}
+	}
+	if di.pinner != nil {
+		di.pinner.Wait()
 	}
 }
 
 func start(ctx context.Context, cfg *config.Config) (*defraInstance, error) {
 	log.FeedbackInfo(ctx, "Starting DefraDB service...")
 
-	var rootstore ds.RootStore
-
-	var err error
-	if cfg.Datastore.Store == badgerDatastoreName {
-		log.FeedbackInfo(ctx, "Opening badger store", logging.NewKV("Path", cfg.Datastore.Badger.Path))
-		rootstore, err = badgerds.NewDatastore(
-			cfg.Datastore.Badger.Path,
-			cfg.Datastore.Badger.Options,
-		)
-	} else if cfg.Datastore.Store == "memory" {
-		log.FeedbackInfo(ctx, "Building new memory store")
-		opts := badgerds.Options{Options: badger.DefaultOptions("").WithInMemory(true)}
-		rootstore, err = badgerds.NewDatastore("", &opts)
-	}
-
+	badgerstore, err := openBadgerstore(ctx, cfg)
 	if err != nil {
 		return nil, errors.Wrap("failed to open datastore", err)
 	}
+	var rootstore ds.RootStore = badgerstore
+
+	if cfg.Datastore.S3.Endpoint != "" {
+		log.FeedbackInfo(
+			ctx,
+			"Offloading cold blocks",
+			logging.NewKV("Endpoint", cfg.Datastore.S3.Endpoint),
+			logging.NewKV("Bucket", cfg.Datastore.S3.Bucket),
+		)
+		tieredstore, err := newTieredStore(rootstore, cfg.Datastore.S3)
+		if err != nil {
+			if closeErr := rootstore.Close(); closeErr != nil {
+				log.ErrorE(ctx, "Failed to close datastore", closeErr)
+			}
+			return nil, errors.Wrap("failed to open tiered datastore", err)
+		}
+		rootstore = tieredstore
+	}
+	ds.SetBlockCompression(cfg.Datastore.CompressBlocks)
 
 	options := []db.Option{
 		db.WithUpdateEvents(),
 		db.WithMaxRetries(cfg.Datastore.MaxTxnRetries),
 	}
 
+	txnRetryBackoff, err := cfg.Datastore.TxnRetryBackoffDuration()
+	if err != nil {
+		return nil, err
+	}
+	if txnRetryBackoff > 0 {
+		options = append(options, db.WithTxnRetryBackoff(txnRetryBackoff))
+	}
+	if cfg.Datastore.DocumentCacheSize > 0 {
+		options = append(options, db.WithDocumentCache(cfg.Datastore.DocumentCacheSize))
+	}
+	if cfg.Datastore.MaxReadTxns > 0 {
+		options = append(options, db.WithMaxReadTxns(cfg.Datastore.MaxReadTxns))
+	}
+	if cfg.Datastore.MaxRequestCost > 0 {
+		options = append(options, db.WithMaxRequestCost(cfg.Datastore.MaxRequestCost))
+	}
+	if cfg.Datastore.CheckIntegrity || cfg.Datastore.RepairIntegrity {
+		options = append(options, db.WithIntegrityCheck(cfg.Datastore.RepairIntegrity))
+	}
+	// The keyring is shared with the P2P node, which merges the encrypted deltas it receives.
+	keyring, err := cfg.Datastore.Keyring()
+	if err != nil {
+		return nil, err
+	}
+	if keyring != nil {
+		options = append(options, db.WithKeyring(keyring))
+	}
+	fieldKeys, err := cfg.Datastore.IdentityFieldKeys()
+	if err != nil {
+		return nil, err
+	}
+	if fieldKeys != nil {
+		options = append(options, db.WithIdentityFieldKeys(fieldKeys))
+	}
+	// Schema updates are only required to be signed in networked mode, where they are shared
+	// with the peers.
+	if !cfg.Net.P2PDisabled {
+		schemaAdmin, err := cfg.Net.SchemaAdminKey()
+		if err != nil {
+			return nil, err
+		}
+		if schemaAdmin != nil {
+			options = append(options, db.WithSchemaAdmin(schemaAdmin))
+		}
+	}
+	// The forwarder is shared with the P2P node, through which the upstream peer is reached.
+	var forwarder *net.RequestForwarder
+	if !cfg.Net.P2PDisabled && cfg.Net.Upstream != "" {
+		forwarder, err = net.NewRequestForwarder(cfg.Net.Upstream)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, db.WithRequestForwarder(forwarder))
+	}
+	if remotes := cfg.API.GatewayRemotesList(); len(remotes) > 0 {
+		if forwarder != nil {
+			return nil, errors.New("a node can't both forward requests upstream and be a gateway")
+		}
+		gateway, err := httpapi.NewGateway(remotes)
+		if err != nil {
+			return nil, err
+		}
+		log.FeedbackInfo(ctx, "Acting as a gateway", logging.NewKV("Remotes", remotes))
+		options = append(options, db.WithRequestForwarder(gateway))
+	}
+
+	retentionWindows, err := cfg.Retention.Windows()
+	if err != nil {
+		return nil, err
+	}
+	if len(retentionWindows) > 0 {
+		retentionInterval, err := cfg.Retention.IntervalDuration()
+		if err != nil {
+			return nil, err
+		}
+		policies := make([]db.RetentionPolicy, 0, len(retentionWindows))
+		for collection, maxAge := range retentionWindows {
+			policies = append(policies, db.RetentionPolicy{
+				Collection:   collection,
+				MaxAge:       maxAge,
+				ByUpdate:     cfg.Retention.ByUpdate,
+				PurgeHistory: cfg.Retention.PurgeHistory,
+			})
+		}
+		options = append(options, db.WithRetention(retentionInterval, policies...))
+	}
+
+	masks, err := cfg.Masking.Masks()
+	if err != nil {
+		return nil, err
+	}
+	if len(masks) > 0 {
+		rules := make([]db.MaskingRule, 0, len(masks))
+		for _, mask := range masks {
+			rule := db.MaskingRule{Collection: mask.Collection, Field: mask.Field, Length: mask.Length}
+			switch mask.Method {
+			case config.MaskMethodHash:
+				rule.Method = db.MaskHash
+			case config.MaskMethodTruncate:
+				rule.Method = db.MaskTruncate
+			default:
+				rule.Method = db.MaskRedact
+			}
+			rules = append(rules, rule)
+		}
+		options = append(options, db.WithMasking([]byte(cfg.Masking.HashKey), rules...))
+	}
+
+	quotas, err := cfg.Quota.Quotas()
+	if err != nil {
+		return nil, err
+	}
+	if len(quotas) > 0 {
+		collectionQuotas := make([]db.CollectionQuota, 0, len(quotas))
+		for _, quota := range quotas {
+			collectionQuotas = append(collectionQuotas, db.CollectionQuota{
+				Collection:   quota.Collection,
+				MaxDocuments: quota.MaxDocuments,
+				MaxBytes:     quota.MaxBytes,
+				Prune:        cfg.Quota.Prune,
+			})
+		}
+		options = append(options, db.WithQuotas(collectionQuotas...))
+	}
+
 	db, err := db.NewDB(ctx, rootstore, options...)
 	if err != nil {
 		return nil, errors.Wrap("failed to create database", err)
 	}
 
+	var primaryServer *grpc.Server
+	if cfg.Replication.Role == config.ReplicationRolePrimary {
+		log.FeedbackInfo(
+			ctx,
+			"Serving the datastore changes to the standby",
+			logging.NewKV("Address", cfg.Replication.Address),
+		)
+		primaryServer, err = startPrimary(ctx, badgerstore.DB, cfg.Replication.Address)
+		if err != nil {
+			db.Close(ctx)
+			return nil, errors.Wrap("failed to start replication primary", err)
+		}
+	}
+
+	var eventsSink *sink.Sink
+	if cfg.Sink.Type != "" {
+		log.FeedbackInfo(
+			ctx,
+			"Publishing update events",
+			logging.NewKV("Type", cfg.Sink.Type),
+			logging.NewKV("Address", cfg.Sink.Address),
+			logging.NewKV("Topic", cfg.Sink.Topic),
+		)
+		eventsSink, err = startSink(ctx, db, cfg.Sink)
+		if err != nil {
+			if primaryServer != nil {
+				primaryServer.Stop()
+			}
+			db.Close(ctx)
+			return nil, errors.Wrap("failed to start events sink", err)
+		}
+	}
+
 	// init the p2p node
 	var n *node.Node
+	var server *grpc.Server
 	if !cfg.Net.P2PDisabled {
 		log.FeedbackInfo(ctx, "Starting P2P node", logging.NewKV("P2P address", cfg.Net.P2PAddress))
 		n, err = node.NewNode(
 			ctx,
 			db,
 			cfg.NodeConfig(),
+			node.WithKeyring(keyring),
+			node.WithRequestForwarder(forwarder),
+			node.WithIngestLimits(net.IngestLimits{
+				PeerRate:       cfg.Net.IngestPeerRate,
+				CollectionRate: cfg.Net.IngestCollectionRate,
+				Burst:          cfg.Net.IngestBurst,
+				MaxQueue:       cfg.Net.IngestQueue,
+			}),
 		)
 		if err != nil {
 			db.Close(ctx)
 			return nil, errors.Wrap("failed to start P2P node", err)
 		}
 
-		// parse peers and bootstrap
+		// parse peers and bootstrap, along with the peers the node was connected to before
+		// it restarted.
+		addrs := n.KnownPeers()
 		if len(cfg.Net.Peers) != 0 {
 			log.Debug(ctx, "Parsing bootstrap peers", logging.NewKV("Peers", cfg.Net.Peers))
-			addrs, err := netutils.ParsePeers(strings.Split(cfg.Net.Peers, ","))
+			bootstrapAddrs, err := netutils.ParsePeers(strings.Split(cfg.Net.Peers, ","))
 			if err != nil {
 				return nil, errors.Wrap(fmt.Sprintf("failed to parse bootstrap peers %v", cfg.Net.Peers), err)
 			}
-			log.Debug(ctx, "Bootstrapping with peers", logging.NewKV("Addresses", addrs))
-			n.Boostrap(addrs)
+			addrs = append(addrs, bootstrapAddrs...)
 		}
+		// the node is bootstrapped even without peers, so that it is reported as ready.
+		log.Debug(ctx, "Bootstrapping with peers", logging.NewKV("Addresses", addrs))
+		n.Boostrap(addrs)
 
 		if err := n.Start(); err != nil {
 			if e := n.Close(); e != nil {
 				err = errors.Wrap(fmt.Sprintf("failed to close node: %v", e.Error()), err)
 			}
-			db.Close(ctx)
 			return nil, errors.Wrap("failed to start P2P listeners", err)
 		}
 
@@ -288,7 +726,7 @@ func start(ctx context.Context, cfg *config.Config) (*defraInstance, error) {
 			return nil, errors.Wrap("failed to parse RPC timeout duration", err)
 		}
 
-		server := grpc.NewServer(
+		server = grpc.NewServer(
 			grpc.UnaryInterceptor(
 				grpc_middleware.ChainUnaryServer(
 					grpc_recovery.UnaryServerInterceptor(),
@@ -316,13 +754,59 @@ func start(ctx context.Context, cfg *config.Config) (*defraInstance, error) {
 		}()
 	}
 
+	var pinner *pinning.Pinner
+	if cfg.Pinning.Type != "" {
+		log.FeedbackInfo(
+			ctx,
+			"Pinning document blocks",
+			logging.NewKV("Type", cfg.Pinning.Type),
+			logging.NewKV("URL", cfg.Pinning.URL),
+		)
+		pinner, err = startPinner(ctx, db, n, cfg.Pinning)
+		if err != nil {
+			return nil, errors.Wrap("failed to start pinner", err)
+		}
+	}
+
+	var pgServer *pgwire.Server
+	if cfg.PGWire.Address != "" {
+		pgServer = pgwire.NewServer(db)
+		if err := pgServer.Start(ctx, cfg.PGWire.Address); err != nil {
+			return nil, errors.Wrap(fmt.Sprintf("failed to listen on TCP address %v", cfg.PGWire.Address), err)
+		}
+		log.FeedbackInfo(
+			ctx,
+			"Serving the collections over the Postgres wire protocol",
+			logging.NewKV("Address", pgServer.Addr()),
+		)
+	}
+
+	di := &defraInstance{
+		node:          n,
+		db:            db,
+		rpcServer:     server,
+		primaryServer: primaryServer,
+		pgServer:      pgServer,
+		sink:          eventsSink,
+		pinner:        pinner,
+		peers:         cfg.Net.Peers,
+	}
+
 	sOpt := []func(*httpapi.Server){
 		httpapi.WithAddress(cfg.API.Address),
 		httpapi.WithRootDir(cfg.Rootdir),
+		httpapi.WithConfig(cfg, func(ctx context.Context) {
+			di.applyConfig(ctx, cfg)
+		}),
 	}
 
 	if n != nil {
-		sOpt = append(sOpt, httpapi.WithPeerID(n.PeerID().String()))
+		sOpt = append(
+			sOpt,
+			httpapi.WithPeerID(n.PeerID().String()),
+			httpapi.WithBlockFetcher(n.Peer),
+			httpapi.WithP2PBootstrapped(n.Bootstrapped),
+		)
 	}
 
 	if cfg.API.TLS {
@@ -335,6 +819,7 @@ func start(ctx context.Context, cfg *config.Config) (*defraInstance, error) {
 	}
 
 	s := httpapi.NewServer(db, sOpt...)
+	di.server = s
 	if err := s.Listen(ctx); err != nil {
 		return nil, errors.Wrap(fmt.Sprintf("failed to listen on TCP address %v", s.Addr), err)
 	}
@@ -357,33 +842,189 @@ func start(ctx context.Context, cfg *config.Config) (*defraInstance, error) {
 				if err := n.Close(); err != nil {
 					log.FeedbackErrorE(ctx, "Failed to close node", err)
 				}
+			} else {
+				db.Close(ctx)
 			}
-			db.Close(ctx)
 			os.Exit(1)
 		}
 	}()
 
-	return &defraInstance{
-		node:   n,
-		db:     db,
-		server: s,
-	}, nil
+	return di, nil
 }
 
 // wait waits for an interrupt signal to close the program.
-func wait(ctx context.Context, di *defraInstance) error {
+//
+// A SIGHUP signal reloads the configuration instead.
+func wait(ctx context.Context, cfg *config.Config, di *defraInstance) error {
 	// setup signal handlers
 	signalCh := make(chan os.Signal, 1)
-	signal.Notify(signalCh, os.Interrupt)
+	signal.Notify(signalCh, os.Interrupt, syscall.SIGTERM)
+	reloadCh := make(chan os.Signal, 1)
+	signal.Notify(reloadCh, syscall.SIGHUP)
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.FeedbackInfo(ctx, "Received context cancellation; closing database...")
+			di.close(ctx)
+			return ctx.Err()
+		case <-signalCh:
+			log.FeedbackInfo(ctx, "Received interrupt; shutting down...")
+			di.close(ctx)
+			return ctx.Err()
+		case <-reloadCh:
+			log.FeedbackInfo(ctx, "Received hangup; reloading configuration...")
+			di.reload(ctx, cfg)
+		}
+	}
+}
+
+// openBadgerstore opens the configured badger datastore.
+func openBadgerstore(ctx context.Context, cfg *config.Config) (*badgerds.Datastore, error) {
+	if cfg.Datastore.Store == "memory" {
+		log.FeedbackInfo(ctx, "Building new memory store")
+		opts := badgerds.Options{Options: badger.DefaultOptions("").WithInMemory(true)}
+		return badgerds.NewDatastore("", &opts)
+	}
+	log.FeedbackInfo(ctx, "Opening badger store", logging.NewKV("Path", cfg.Datastore.Badger.Path))
+	return badgerds.NewDatastore(
+		cfg.Datastore.Badger.Path,
+		cfg.Datastore.Badger.Options,
+	)
+}
+
+// startPrimary starts serving the changes of the given badger datastore to the standby on the
+// given address.
+func startPrimary(ctx context.Context, db *badger.DB, address string) (*grpc.Server, error) {
+	listener, err := gonet.Listen("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	server := grpc.NewServer(
+		grpc.StreamInterceptor(grpc_recovery.StreamServerInterceptor()),
+	)
+	standbypb.RegisterStandbyServer(server, standby.NewPrimary(db))
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			log.FeedbackErrorE(ctx, "Failed to run the replication server", err)
+		}
+	}()
+	return server, nil
+}
+
+// runStandby follows the configured primary, applying the changes of its datastore to the
+// local datastore, until an interrupt signal is received.
+func runStandby(ctx context.Context, cfg *config.Config) error {
+	badgerstore, err := openBadgerstore(ctx, cfg)
+	if err != nil {
+		return errors.Wrap("failed to open datastore", err)
+	}
+	defer func() {
+		if err := badgerstore.Close(); err != nil {
+			log.FeedbackErrorE(ctx, "Failed to close datastore", err)
+		}
+	}()
+
+	conn, err := grpc.Dial(cfg.Replication.Address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return errors.Wrap("failed to connect to the replication primary", err)
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			log.FeedbackErrorE(ctx, "Failed to close the connection to the replication primary", err)
+		}
+	}()
+
+	log.FeedbackInfo(ctx, "Following the replication primary", logging.NewKV("Address", cfg.Replication.Address))
+	standbyCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		standby.New(badgerstore.DB, conn).Run(standbyCtx)
+	}()
 
+	signalCh := make(chan os.Signal, 1)
+	signal.Notify(signalCh, os.Interrupt, syscall.SIGTERM)
 	select {
 	case <-ctx.Done():
-		log.FeedbackInfo(ctx, "Received context cancellation; closing database...")
-		di.close(ctx)
-		return ctx.Err()
+		log.FeedbackInfo(ctx, "Received context cancellation; closing datastore...")
 	case <-signalCh:
-		log.FeedbackInfo(ctx, "Received interrupt; closing database...")
-		di.close(ctx)
-		return ctx.Err()
+		log.FeedbackInfo(ctx, "Received interrupt; shutting down...")
+	}
+	cancel()
+	<-done
+	return ctx.Err()
+}
+
+// newTieredStore returns the given rootstore offloading its cold blocks to the configured
+// object storage.
+func newTieredStore(rootstore ds.RootStore, cfg config.S3Config) (*tiered.Store, error) {
+	offloadInterval, err := cfg.OffloadIntervalDuration()
+	if err != nil {
+		return nil, err
+	}
+	cold, err := tiered.NewS3Store(tiered.S3Options{
+		Endpoint:  cfg.Endpoint,
+		Secure:    cfg.Secure,
+		Region:    cfg.Region,
+		Bucket:    cfg.Bucket,
+		Prefix:    cfg.Prefix,
+		AccessKey: cfg.AccessKey,
+		SecretKey: cfg.SecretKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+	tieredstore, err := tiered.New(rootstore, cold, cfg.CacheSize)
+	if err != nil {
+		return nil, err
+	}
+	tieredstore.StartOffloading(offloadInterval)
+	return tieredstore, nil
+}
+
+// startSink starts publishing the update events of the given database as configured.
+func startSink(ctx context.Context, db client.DB, cfg *config.SinkConfig) (*sink.Sink, error) {
+	encoder, err := sink.NewEncoder(cfg.Format)
+	if err != nil {
+		return nil, err
+	}
+	publisher, err := sink.NewPublisher(cfg.Type, cfg.Address)
+	if err != nil {
+		return nil, err
+	}
+	eventsSink := sink.New(db, publisher, encoder, cfg.Topic)
+	if err := eventsSink.Start(ctx); err != nil {
+		if closeErr := publisher.Close(); closeErr != nil {
+			log.ErrorE(ctx, "Failed to close events sink publisher", closeErr)
+		}
+		return nil, err
+	}
+	return eventsSink, nil
+}
+
+// startPinner starts pinning the blocks of the documents of the given database as configured.
+//
+// The remote fetches the blocks from the given node, if any.
+func startPinner(
+	ctx context.Context,
+	db client.DB,
+	n *node.Node,
+	cfg *config.PinningConfig,
+) (*pinning.Pinner, error) {
+	pinClient, err := pinning.NewClient(cfg.Type, cfg.URL, cfg.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	origins := []string{}
+	if n != nil {
+		for _, addr := range n.ListenAddrs() {
+			origins = append(origins, fmt.Sprintf("%s/p2p/%s", addr, n.PeerID()))
+		}
+	}
+	pinner := pinning.New(db, pinClient, cfg.CollectionNames(), origins)
+	if err := pinner.Start(ctx); err != nil {
+		return nil, err
 	}
+	return pinner, nil
 }
@@ -0,0 +1,109 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/spf13/cobra"
+
+	httpapi "github.com/sourcenetwork/defradb/api/http"
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/config"
+	"github.com/sourcenetwork/defradb/errors"
+)
+
+// MakeStatusCommand returns the command printing the status of the node.
+func MakeStatusCommand(cfg *config.Config) *cobra.Command {
+	var check bool
+	var repair bool
+	var cmd = &cobra.Command{
+		Use:   "status",
+		Short: "Print the status of the node, including the integrity report of its system keyspace",
+		Long: `Print the status of the node, including the integrity report of its system keyspace.
+
+The integrity report is the one of the last check, such as the one run on startup with the
+--check-integrity or --repair flags of the start command. The integrity is checked anew if
+--check is given, repairing the issues known to be fixable if --repair is given. Checking the
+integrity requires the admin token of the configuration.
+
+Example: check the integrity of the system keyspace, repairing it
+  defradb client status --check --repair`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) (err error) {
+			method := http.MethodGet
+			path := httpapi.StatusPath
+			var body io.Reader
+			if check || repair {
+				data, err := json.Marshal(client.CheckIntegrityOptions{Repair: repair})
+				if err != nil {
+					return errors.Wrap("failed to marshal integrity check options", err)
+				}
+				method = http.MethodPost
+				path = httpapi.StatusPath + "/integrity"
+				body = bytes.NewBuffer(data)
+			}
+
+			endpoint, err := httpapi.JoinPaths(cfg.API.AddressToURL(), path)
+			if err != nil {
+				return NewErrFailedToJoinEndpoint(err)
+			}
+			req, err := http.NewRequestWithContext(cmd.Context(), method, endpoint.String(), body)
+			if err != nil {
+				return NewErrFailedToSendRequest(err)
+			}
+			if body != nil {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			if cfg.API.AdminToken != "" {
+				req.Header.Set("Authorization", "Bearer "+cfg.API.AdminToken)
+			}
+
+			res, err := http.DefaultClient.Do(req)
+			if err != nil {
+				return NewErrFailedToSendRequest(err)
+			}
+			defer func() {
+				if e := res.Body.Close(); e != nil && err == nil {
+					err = NewErrFailedToReadResponseBody(e)
+				}
+			}()
+
+			if res.StatusCode != http.StatusOK {
+				r := httpapi.ErrorResponse{}
+				if err := json.NewDecoder(res.Body).Decode(&r); err != nil {
+					return NewErrFailedToUnmarshalResponse(err)
+				}
+				if len(r.Errors) > 0 {
+					return errors.New(r.Errors[0].Message)
+				}
+				return errors.New("status request failed", errors.NewKV("Status", res.StatusCode))
+			}
+
+			r := httpapi.DataResponse{}
+			if err := json.NewDecoder(res.Body).Decode(&r); err != nil {
+				return NewErrFailedToUnmarshalResponse(err)
+			}
+			data, err := json.MarshalIndent(r.Data, "", "  ")
+			if err != nil {
+				return errors.Wrap("failed to marshal status", err)
+			}
+			cmd.Println(string(data))
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&check, "check", false, "Check the integrity of the system keyspace anew")
+	cmd.Flags().BoolVar(&repair, "repair", false, "Repair the integrity issues known to be fixable, implies --check")
+	return cmd
+}
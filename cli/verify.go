@@ -0,0 +1,131 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+
+	"github.com/spf13/cobra"
+
+	httpapi "github.com/sourcenetwork/defradb/api/http"
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/config"
+	"github.com/sourcenetwork/defradb/errors"
+	"github.com/sourcenetwork/defradb/logging"
+)
+
+// MakeVerifyCommand returns the command verifying the blocks of the document DAGs of the node.
+func MakeVerifyCommand(cfg *config.Config) *cobra.Command {
+	var collections []string
+	var repair bool
+	var cmd = &cobra.Command{
+		Use:   "verify",
+		Short: "Verify the blocks of the documents against their CIDs",
+		Long: `Verify the blocks of the documents against their CIDs.
+
+The DAG of each document is walked from its current heads, and each of its blocks is verified
+to be stored with data matching its CID. The missing and corrupt blocks are reported, and
+re-fetched from the connected peers if --repair is given, which requires P2P to be enabled.
+The admin token of the configuration authenticates the request.
+
+Example: verify the blocks of the users, repairing them
+  defradb client verify --collection users --repair`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) (err error) {
+			body, err := json.Marshal(client.VerifyBlocksOptions{
+				Collections: collections,
+				Repair:      repair,
+			})
+			if err != nil {
+				return errors.Wrap("failed to marshal verification options", err)
+			}
+
+			endpoint, err := httpapi.JoinPaths(cfg.API.AddressToURL(), httpapi.VerifyPath)
+			if err != nil {
+				return NewErrFailedToJoinEndpoint(err)
+			}
+			req, err := http.NewRequestWithContext(
+				cmd.Context(),
+				http.MethodPost,
+				endpoint.String(),
+				bytes.NewBuffer(body),
+			)
+			if err != nil {
+				return NewErrFailedToSendRequest(err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+			if cfg.API.AdminToken != "" {
+				req.Header.Set("Authorization", "Bearer "+cfg.API.AdminToken)
+			}
+
+			log.FeedbackInfo(cmd.Context(), "Verifying blocks...")
+			res, err := http.DefaultClient.Do(req)
+			if err != nil {
+				return NewErrFailedToSendRequest(err)
+			}
+			defer func() {
+				if e := res.Body.Close(); e != nil && err == nil {
+					err = NewErrFailedToReadResponseBody(e)
+				}
+			}()
+
+			if res.StatusCode != http.StatusOK {
+				r := httpapi.ErrorResponse{}
+				if err := json.NewDecoder(res.Body).Decode(&r); err != nil {
+					return NewErrFailedToUnmarshalResponse(err)
+				}
+				if len(r.Errors) > 0 {
+					return errors.New(r.Errors[0].Message)
+				}
+				return errors.New("verification request failed", errors.NewKV("Status", res.StatusCode))
+			}
+
+			r := struct {
+				Data client.VerifyBlocksReport `json:"data"`
+			}{}
+			if err := json.NewDecoder(res.Body).Decode(&r); err != nil {
+				return NewErrFailedToUnmarshalResponse(err)
+			}
+			for _, issue := range r.Data.Issues {
+				kvs := []logging.KV{
+					logging.NewKV("Collection", issue.Collection),
+					logging.NewKV("DocKey", issue.DocKey),
+					logging.NewKV("CID", issue.Cid),
+					logging.NewKV("Problem", issue.Problem),
+				}
+				switch {
+				case issue.Repaired:
+					log.FeedbackInfo(cmd.Context(), "Block repaired", kvs...)
+				case issue.Error != "":
+					log.FeedbackError(cmd.Context(), "Block not repaired", append(kvs, logging.NewKV("Error", issue.Error))...)
+				default:
+					log.FeedbackError(cmd.Context(), "Invalid block", kvs...)
+				}
+			}
+			log.FeedbackInfo(
+				cmd.Context(),
+				"Blocks verified",
+				logging.NewKV("Documents", r.Data.Documents),
+				logging.NewKV("Blocks", r.Data.Blocks),
+				logging.NewKV("Issues", len(r.Data.Issues)),
+			)
+			return nil
+		},
+	}
+	cmd.Flags().StringArrayVar(
+		&collections, "collection", nil,
+		"Collection to verify, all collections are verified if none is given",
+	)
+	cmd.Flags().BoolVar(&repair, "repair", false, "Re-fetch the missing and corrupt blocks from the connected peers")
+	return cmd
+}
@@ -0,0 +1,57 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package client
+
+import "time"
+
+// Branch describes a branch of the database, created with [DB.CreateBranch].
+type Branch struct {
+	// Name is the name of the branch.
+	Name string `json:"name"`
+
+	// CreatedAt is the time the branch was created at.
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// BranchDiff is the difference between the documents and schemas of a branch and those of the
+// database it was created from, as returned by [DB.DiffBranch].
+type BranchDiff struct {
+	// Branch is the name of the branch.
+	Branch string `json:"branch"`
+
+	// Collections are the differences of the collections that differ, ordered by name.
+	Collections []CollectionDiff `json:"collections"`
+}
+
+// CollectionDiff is the difference between a collection of a branch and the collection with the
+// same name of the database it was created from.
+type CollectionDiff struct {
+	// Name is the name of the collection.
+	Name string `json:"name"`
+
+	// MainVersionID is the schema version ID of the collection of the database, empty if the
+	// collection only exists on the branch.
+	MainVersionID string `json:"mainVersionId"`
+
+	// BranchVersionID is the schema version ID of the collection of the branch, empty if the
+	// collection only exists on the database.
+	BranchVersionID string `json:"branchVersionId"`
+
+	// Added are the keys of the documents that only exist on the branch.
+	Added []string `json:"added"`
+
+	// Changed are the keys of the documents whose heads differ between the branch and the
+	// database.
+	Changed []string `json:"changed"`
+
+	// Removed are the keys of the documents that only exist on the database.
+	Removed []string `json:"removed"`
+}
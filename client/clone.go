@@ -0,0 +1,33 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package client
+
+// CloneCollectionOptions sets the collection cloned by [Store.CloneCollection] and how.
+type CloneCollectionOptions struct {
+	// Source is the name of the collection to clone.
+	Source string `json:"source"`
+
+	// Name is the name of the new collection.
+	Name string `json:"name"`
+
+	// Filter restricts the documents copied to the ones matching it, in the same format as the
+	// filter of a request. All the documents are copied if it is empty.
+	Filter string `json:"filter,omitempty"`
+}
+
+// CloneCollectionResult is the result of [Store.CloneCollection].
+type CloneCollectionResult struct {
+	// Collection is the description of the new collection.
+	Collection CollectionDescription `json:"collection"`
+
+	// Documents is the number of documents copied.
+	Documents int `json:"documents"`
+}
@@ -12,8 +12,13 @@ package client
 
 import (
 	"context"
+	"time"
+
+	"github.com/ipfs/go-cid"
+	ipld "github.com/ipfs/go-ipld-format"
 
 	"github.com/sourcenetwork/defradb/datastore"
+	"github.com/sourcenetwork/defradb/events"
 )
 
 // Collection represents a defradb collection.
@@ -42,6 +47,13 @@ type Collection interface {
 	//
 	// Will verify the DocKeys/CIDs to ensure that the new documents are correctly formatted.
 	CreateMany(context.Context, []*Document) error
+	// CreateBatched creates new documents in batches of the given size, each batch in its own
+	// transaction, for the fast ingest of many documents.
+	//
+	// The update events of the documents of a batch are published once the batch is committed.
+	// If an error is returned, the documents of the batches committed before the error remain
+	// created. All the documents are created in the transaction of the collection if it has one.
+	CreateBatched(ctx context.Context, docs []*Document, batchSize int) error
 	// Update an existing document with the new values.
 	//
 	// Any field that needs to be removed or cleared should call doc.Clear(field) before.
@@ -136,12 +148,150 @@ type Collection interface {
 
 	// GetAllDocKeys returns all the document keys that exist in the collection.
 	GetAllDocKeys(ctx context.Context) (<-chan DocKeysResult, error)
+
+	// GetAllDocKeysWithHeads returns all the document keys that exist in the collection, along
+	// with the CIDs of the current heads of each document.
+	//
+	// Document values are not fetched.
+	GetAllDocKeysWithHeads(ctx context.Context) (<-chan DocKeysResult, error)
+
+	// Count returns the number of documents in the collection matching the given filter.
+	//
+	// The filter may be nil, in which case all the documents that have not been deleted are counted
+	// without fetching their values. Otherwise it accepts the same values as [UpdateWithFilter].
+	Count(ctx context.Context, filter any) (uint64, error)
+
+	// Stats returns statistics about the documents of the collection.
+	Stats(ctx context.Context) (CollectionStats, error)
+
+	// GetProof returns a Merkle proof that the current state of the document with the given
+	// DocKey derives from the commit with the given CID.
+	//
+	// Returns an ErrDocumentNotFound if the document has no commits, and an ErrCommitNotInHistory
+	// if the given commit is not an ancestor of each of its current heads.
+	GetProof(ctx context.Context, key DocKey, root cid.Cid) (Proof, error)
+
+	// MergeBlocks merges the given blocks into a document of the collection, and returns the CID
+	// of the commit they hold.
+	//
+	// The blocks are the raw data of a commit of the document produced outside of the node, such
+	// as by a client writing offline, and of the blocks it links to that the node doesn't have.
+	// They are validated and merged like the commits received from other peers, and their
+	// update is published like the updates made locally.
+	MergeBlocks(ctx context.Context, blocks [][]byte) (cid.Cid, error)
+
+	// PublishDataset stores a dataset block linking to the current heads of the documents of the
+	// collection, and returns its CID.
+	//
+	// The documents are distributed content-addressably by their dataset: other nodes import
+	// them from its CID with [ImportDataset], fetching the blocks from the peers holding them.
+	PublishDataset(ctx context.Context) (cid.Cid, error)
+
+	// ImportDataset fetches the dataset with the given root CID, along with the DAGs of the
+	// documents it links to, with the given fetcher, and merges the documents into the collection.
+	//
+	// The blocks already stored by the node are not fetched, and the documents whose heads are
+	// already known are skipped.
+	ImportDataset(ctx context.Context, root cid.Cid, fetcher ipld.NodeGetter) (ImportDatasetResult, error)
+
+	// RecordRemoteMerge counts the given merge of a delta produced by another node into a document
+	// of the collection in its statistics, once the transaction of the collection is committed
+	// if it has one.
+	RecordRemoteMerge(merge RemoteMerge)
+
+	// RecordRemoteCommit records the time, given by the clock of the database, at which the given
+	// composite commit of the document with the given key, produced by another node, is received,
+	// so that the document can be requested as it was at a given time.
+	RecordRemoteCommit(ctx context.Context, docKey string, commit cid.Cid, status DocumentStatus) error
+
+	// SubscribeUpdates returns the updates of the documents of the collection recorded in its
+	// changefeed after the given sequence number, followed by the new updates as they are
+	// committed, in sequence order.
+	//
+	// A client that disconnects may resume from the sequence number of the last update it
+	// received without missing any. The channel is closed once the given context is done.
+	// Returns an error if the database has not been created with a changefeed.
+	SubscribeUpdates(ctx context.Context, since uint64) (<-chan events.Update, error)
+}
+
+// Proof is a Merkle proof that the current state of a document derives from a root commit.
+//
+// It holds, for each current head of the document, the chain of composite commits linking the
+// head to the root commit. The CID of each block can be verified against the hash of its data,
+// and each block links to the CID of the next block of its chain.
+type Proof struct {
+	// Root is the CID of the commit the proof leads to.
+	Root cid.Cid
+	// Heads are the CIDs of the current heads of the document.
+	Heads []cid.Cid
+	// Blocks are the blocks of the chains from the heads to the root, without duplicates.
+	Blocks []ProofBlock
+}
+
+// ProofBlock is a block of a Merkle proof.
+type ProofBlock struct {
+	// Cid is the CID of the block.
+	Cid cid.Cid
+	// Data is the raw, encoded, block.
+	Data []byte
+}
+
+// CollectionStats contains statistics about the documents of a collection.
+type CollectionStats struct {
+	// Name is the name of the collection.
+	Name string `json:"name"`
+
+	// DocCount is the number of documents that have not been deleted.
+	DocCount uint64 `json:"docCount"`
+
+	// TombstoneCount is the number of deleted documents.
+	TombstoneCount uint64 `json:"tombstoneCount"`
+
+	// AvgDocSize is the average size in bytes of the stored field values of the documents
+	// that have not been deleted.
+	AvgDocSize float64 `json:"avgDocSize"`
+
+	// LastUpdatedAt is the time of the last local create, update or delete of a document.
+	//
+	// It is only tracked while the node is running and is nil if no document has been written since.
+	LastUpdatedAt *time.Time `json:"lastUpdatedAt"`
+
+	// RemoteDeltasMerged is the number of deltas produced by other nodes merged into the documents.
+	//
+	// It is only tracked while the node is running, as are the other merge statistics.
+	RemoteDeltasMerged uint64 `json:"remoteDeltasMerged"`
+
+	// LWWConflicts is the number of concurrent values of fields resolved by keeping the last
+	// writer's while merging the deltas produced by other nodes.
+	LWWConflicts uint64 `json:"lwwConflicts"`
+
+	// AvgDAGDepth is the average depth, in the DAGs of their documents, of the commits produced by
+	// other nodes merged into the documents.
+	AvgDAGDepth float64 `json:"avgDagDepth"`
+}
+
+// RemoteMerge describes the merge of a delta produced by another node into a document.
+type RemoteMerge struct {
+	// DocKey is the key of the document the delta is merged into.
+	DocKey string
+
+	// Priority is the height of the delta in its DAG, which is its depth.
+	Priority uint64
+
+	// Commit is true if the delta is a commit of the document, rather than of one of its fields.
+	Commit bool
+
+	// Conflicts is the number of concurrent values of fields resolved by keeping the last
+	// writer's while merging the delta.
+	Conflicts uint64
 }
 
 // DocKeysResult wraps the result of an attempt at a DocKey retrieval operation.
 type DocKeysResult struct {
 	// If a DocKey was successfully retrieved, this will be that key.
 	Key DocKey
+	// Heads contains the CIDs of the current heads of the document if they were requested.
+	Heads []cid.Cid
 	// If an error was generated whilst attempting to retrieve the DocKey, this will be the error.
 	Err error
 }
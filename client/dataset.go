@@ -0,0 +1,26 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package client
+
+// ImportDatasetResult is the result of [Collection.ImportDataset].
+type ImportDatasetResult struct {
+	// Heads is the number of document heads the dataset links to.
+	Heads int `json:"heads"`
+
+	// Merged is the number of document heads merged into the collection.
+	Merged int `json:"merged"`
+
+	// Known is the number of document heads skipped as they were already known.
+	Known int `json:"known"`
+
+	// Blocks is the number of blocks fetched.
+	Blocks int `json:"blocks"`
+}
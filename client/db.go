@@ -12,8 +12,10 @@ package client
 
 import (
 	"context"
+	"time"
 
 	blockstore "github.com/ipfs/boxo/blockstore"
+	"github.com/sourcenetwork/immutable"
 
 	"github.com/sourcenetwork/defradb/datastore"
 	"github.com/sourcenetwork/defradb/events"
@@ -76,6 +78,102 @@ type DB interface {
 	//
 	// It is likely unwise to call this on a large database instance.
 	PrintDump(ctx context.Context) error
+
+	// Dump returns the entries of the rootstore matching the given options, ordered by key.
+	//
+	// The channel is closed once all the entries have been sent or the given context is done.
+	Dump(ctx context.Context, opts DumpOptions) (<-chan DumpEntry, error)
+
+	// MigrateRelations rewrites the relation ID fields of the documents to match the current
+	// schema, in batches of documents each migrated in its own transaction.
+	//
+	// The relation IDs held by the secondary side of one-to-one relations, as written under a
+	// schema in which that side was primary, are moved to the related documents, and the
+	// relation IDs referencing re-keyed documents are rewritten to reference their new keys.
+	//
+	// A progress report is sent after each batch, followed by a final report once the migration
+	// is done or has failed. The channel is closed after the final report or once the given
+	// context is done.
+	MigrateRelations(ctx context.Context, opts MigrateRelationsOptions) (<-chan MigrateRelationsProgress, error)
+
+	// VerifyBlocks walks the DAGs of the documents from their current heads, verifying that each
+	// of their blocks is stored and that its data matches its CID.
+	//
+	// The missing and corrupt blocks are reported, and re-fetched with the fetcher of the given
+	// options, such as from the connected peers, if they are to be repaired.
+	VerifyBlocks(ctx context.Context, opts VerifyBlocksOptions) (VerifyBlocksReport, error)
+
+	// CheckIntegrity checks the invariants of the system keyspace: the collection descriptions
+	// are stored and parse, the schemas point to the versions of their collections, and the
+	// sequence counters are not behind the IDs they generated.
+	//
+	// The issues known to be fixable are repaired if requested by the given options. The report
+	// is returned by [LastIntegrityReport] afterwards.
+	CheckIntegrity(ctx context.Context, opts CheckIntegrityOptions) (IntegrityReport, error)
+
+	// LastIntegrityReport returns the report of the last integrity check of this DefraDB instance,
+	// such as the one run on open, if any.
+	LastIntegrityReport() immutable.Option[IntegrityReport]
+
+	// ActiveRequests returns the requests that are currently being executed by this DefraDB instance.
+	//
+	// Subscriptions and introspection requests are not tracked.
+	ActiveRequests() []ActiveRequest
+
+	// CancelRequest cancels the active request with the given ID by cancelling its context.
+	//
+	// It will return an error if no active request with the given ID exists. The request will
+	// return an error once its plan notices the cancellation.
+	CancelRequest(id uint64) error
+
+	// TxnStats returns the statistics of the transactions opened by this DefraDB instance.
+	TxnStats() TxnStats
+
+	// Metrics returns the metrics gathered by this DefraDB instance, such as those of the merges of
+	// the deltas produced by other nodes, in JSON.
+	Metrics(ctx context.Context) (string, error)
+
+	// AddWebhook registers the given webhook, replacing any existing webhook with the same ID,
+	// and returns it along with its ID.
+	//
+	// The events of the documents of its collection are POSTed to its URL once committed. Failed
+	// deliveries are retried with backoff, and recorded as dead letters once the retries are
+	// exhausted. It will return an error if the update events are not enabled.
+	AddWebhook(ctx context.Context, webhook Webhook) (Webhook, error)
+
+	// DeleteWebhook deletes the webhook with the given ID.
+	//
+	// It will return an ErrWebhookNotFound if no webhook with the given ID exists.
+	DeleteWebhook(ctx context.Context, id string) error
+
+	// GetAllWebhooks returns all the registered webhooks.
+	GetAllWebhooks(ctx context.Context) ([]Webhook, error)
+
+	// GetWebhookDeadLetters returns the events that could not be delivered to the webhooks,
+	// ordered by the time of their last delivery attempt.
+	GetWebhookDeadLetters(ctx context.Context) ([]WebhookDeadLetter, error)
+
+	// CreateBranch creates a branch of the database with the given name.
+	//
+	// The branch shares the history of the database up to its creation, the writes and schema
+	// updates made to either of them afterwards being isolated from the other.
+	CreateBranch(ctx context.Context, name string) (Branch, error)
+
+	// GetBranch returns the database of the branch with the given name.
+	//
+	// The database of the branch is closed along with this database. The webhooks, retention and
+	// P2P of this database do not apply to it.
+	GetBranch(ctx context.Context, name string) (DB, error)
+
+	// GetAllBranches returns all the branches of the database, ordered by name.
+	GetAllBranches(ctx context.Context) ([]Branch, error)
+
+	// DiffBranch returns the differences between the documents and schemas of the branch with the
+	// given name and those of the database.
+	DiffBranch(ctx context.Context, name string) (BranchDiff, error)
+
+	// DiscardBranch deletes the branch with the given name, along with all its writes.
+	DiscardBranch(ctx context.Context, name string) error
 }
 
 // Store contains the core DefraDB read-write operations.
@@ -83,11 +181,18 @@ type Store interface {
 	// P2P holds the P2P related methods that must be implemented by the database.
 	P2P
 
+	// KV holds the methods of the key-value store of the applications, whose values are written
+	// transactionally alongside the documents.
+	KV
+
 	// AddSchema takes the provided GQL schema in SDL format, and applies it to the [Store],
 	// creating the necessary collections, request types, etc.
 	//
 	// All schema types provided must not exist prior to calling this, and they may not reference existing
 	// types previously defined.
+	//
+	// If a schema admin identity is configured, the schema must be signed by it, the signature being
+	// given with [WithSchemaSignature].
 	AddSchema(context.Context, string) error
 
 	// PatchSchema takes the given JSON patch string and applies it to the set of CollectionDescriptions
@@ -104,8 +209,35 @@ type Store interface {
 	//
 	// Field [FieldKind] values may be provided in either their raw integer form, or as string as per
 	// [FieldKindStringToEnumMapping].
+	//
+	// If a schema admin identity is configured, the patch must be signed by it, the signature being
+	// given with [WithSchemaSignature].
 	PatchSchema(context.Context, string) error
 
+	// GetSchemaVersions returns the descriptions of all the versions of the schema with the given
+	// schema ID, ordered from the initial version to the current one.
+	GetSchemaVersions(context.Context, string) ([]CollectionDescription, error)
+
+	// AddSchemaVersions adds the collection of the given schema versions, as returned by
+	// [GetSchemaVersions] on another node, so that the collection is shared between them.
+	//
+	// The schema ID and version IDs are recomputed and must match the given ones. The collection
+	// must not exist prior to calling this.
+	AddSchemaVersions(context.Context, []CollectionDescription) (Collection, error)
+
+	// CloneCollection creates a collection with the current schema of the source collection of the
+	// given options under a new name, and copies the documents of the source collection into it.
+	//
+	// The copies have keys of their own, and start a new history. Collections with relation fields
+	// can't be cloned, as their related collections are not.
+	CloneCollection(context.Context, CloneCollectionOptions) (CloneCollectionResult, error)
+
+	// GetSignedSchemaUpdate returns the signed schema update that produced the schema version
+	// with the given ID.
+	//
+	// If the schema version was not produced by a signed update an error will be returned.
+	GetSignedSchemaUpdate(context.Context, string) (SignedSchemaUpdate, error)
+
 	// GetCollectionByName attempts to retrieve a collection matching the given name.
 	//
 	// If no matching collection is found an error will be returned.
@@ -154,3 +286,81 @@ type RequestResult struct {
 	// if the request was a GQL subscription.
 	Pub *events.Publisher[events.Update]
 }
+
+// ActiveRequest describes a request that is currently being executed.
+type ActiveRequest struct {
+	// ID uniquely identifies the request within the DefraDB instance.
+	ID uint64 `json:"id"`
+
+	// Request is the GQL request string.
+	Request string `json:"request"`
+
+	// StartedAt is the time at which the execution of the request started.
+	StartedAt time.Time `json:"startedAt"`
+
+	// Plan is a summary of the plan executing the request.
+	//
+	// It will be empty if the plan has not been built yet.
+	Plan string `json:"plan"`
+
+	// Context describes the origin of the request.
+	Context RequestContext `json:"context"`
+}
+
+// TxnStats contains statistics about the transactions opened by a DefraDB instance.
+//
+// The transactions of the queries are read only, while those of the mutations are read-write.
+type TxnStats struct {
+	// ActiveReads is the number of open read only transactions.
+	ActiveReads int64 `json:"activeReads"`
+
+	// ActiveWrites is the number of open read-write transactions.
+	ActiveWrites int64 `json:"activeWrites"`
+
+	// QueuedReads is the number of read only transactions waiting for others to end before being
+	// opened, the maximum number of concurrent read only transactions being open.
+	QueuedReads int64 `json:"queuedReads"`
+
+	// MaxReads is the maximum number of concurrent read only transactions, or zero if unlimited.
+	MaxReads int `json:"maxReads"`
+
+	// TotalReads is the number of read only transactions opened since the instance started.
+	TotalReads uint64 `json:"totalReads"`
+
+	// TotalWrites is the number of read-write transactions opened since the instance started.
+	TotalWrites uint64 `json:"totalWrites"`
+
+	// TotalQueuedReads is the number of read only transactions that were queued since the
+	// instance started.
+	TotalQueuedReads uint64 `json:"totalQueuedReads"`
+}
+
+// DumpOptions filters and formats the entries returned by [DB.Dump].
+type DumpOptions struct {
+	// Keyspace restricts the dump to one of the stores of the rootstore: `system`, `data`,
+	// `heads` or `blocks`. All the stores are dumped if it is empty.
+	Keyspace string
+
+	// Prefix restricts the dump to the keys with the given prefix within the keyspace.
+	Prefix string
+
+	// Decode sets whether the values of the entries should be decoded.
+	Decode bool
+}
+
+// DumpEntry is an entry of the rootstore returned by [DB.Dump].
+type DumpEntry struct {
+	// Key is the key of the entry in the rootstore.
+	Key string
+
+	// Size is the size of the value in bytes.
+	Size int
+
+	// Value is the decoded value of the entry.
+	//
+	// It is only set if decoding was requested, and is nil if the value has no known encoding.
+	Value any
+
+	// If an error was generated whilst attempting to read the entry, this will be the error.
+	Err error
+}
@@ -85,6 +85,18 @@ type SchemaDescription struct {
 	//
 	// Currently new fields may be added after initial declaration, but they cannot be removed.
 	Fields []FieldDescription
+
+	// AppendOnly is true if the documents of this Schema can only be created, not updated nor
+	// deleted, as declared with the `@appendOnly` directive.
+	//
+	// It is omitted when false so that the IDs of the other schemas are unchanged. It is immutable.
+	AppendOnly bool `json:",omitempty"`
+
+	// CostMultiplier is the weight of the selections of the documents of this Schema in the cost of
+	// the requests, as declared with the `@cost(multiplier: N)` directive. A zero value counts as one.
+	//
+	// It is omitted when zero so that the IDs of the other schemas are unchanged.
+	CostMultiplier int `json:",omitempty"`
 }
 
 // IsEmpty returns true if the SchemaDescription is empty and uninitialized
@@ -120,8 +132,8 @@ const (
 	FieldKind_DATETIME     FieldKind = 10
 	FieldKind_STRING       FieldKind = 11
 	FieldKind_STRING_ARRAY FieldKind = 12
-	_                      FieldKind = 13 // safe to repurpose (was never used)
-	_                      FieldKind = 14 // safe to repurpose (was never used)
+	FieldKind_INT32        FieldKind = 13
+	FieldKind_UINT64       FieldKind = 14
 	_                      FieldKind = 15 // safe to repurpose (was never used)
 
 	// Embedded object, but accessed via foreign keys
@@ -151,6 +163,8 @@ var FieldKindStringToEnumMapping = map[string]FieldKind{
 	"Integer":    FieldKind_INT,
 	"[Integer]":  FieldKind_NILLABLE_INT_ARRAY,
 	"[Integer!]": FieldKind_INT_ARRAY,
+	"Int32":      FieldKind_INT32,
+	"UInt64":     FieldKind_UINT64,
 	"DateTime":   FieldKind_DATETIME,
 	"Float":      FieldKind_FLOAT,
 	"[Float]":    FieldKind_NILLABLE_FLOAT_ARRAY,
@@ -218,6 +232,13 @@ type FieldDescription struct {
 	// RelationType contains the relationship type if this field is a relation field. Otherwise this
 	// will be empty.
 	RelationType RelationType
+
+	// Encrypted is true if the values of this field are encrypted before being stored, including
+	// within the blocks of its deltas, as declared with the `@encrypted` directive. They are only
+	// readable by the callers presenting its key.
+	//
+	// It is omitted when false so that the IDs of the other schemas are unchanged. It is immutable.
+	Encrypted bool `json:",omitempty"`
 }
 
 // IsObject returns true if this field is an object type.
@@ -11,7 +11,10 @@
 package client
 
 import (
+	"bytes"
 	"encoding/json"
+	"math"
+	"strconv"
 	"strings"
 	"sync"
 
@@ -128,7 +131,7 @@ func NewDocFromMap(data map[string]any) (*Document, error) {
 // NewFromJSON creates a new instance of a Document from a raw JSON object byte array.
 func NewDocFromJSON(obj []byte) (*Document, error) {
 	data := make(map[string]any)
-	err := json.Unmarshal(obj, &data)
+	err := unmarshalJSONWithNumbers(obj, &data)
 	if err != nil {
 		return nil, err
 	}
@@ -209,7 +212,7 @@ func (doc *Document) GetValueWithField(f Field) (Value, error) {
 // @todo: Handle sub documents for SetWithJSON
 func (doc *Document) SetWithJSON(patch []byte) error {
 	var patchObj map[string]any
-	err := json.Unmarshal(patch, &patchObj)
+	err := unmarshalJSONWithNumbers(patch, &patchObj)
 	if err != nil {
 		return err
 	}
@@ -294,9 +297,32 @@ func (doc *Document) setAndParseType(field string, value any) error {
 		if err != nil {
 			return err
 		}
+	case int64, uint64:
+		err := doc.setCBOR(LWW_REGISTER, field, val)
+		if err != nil {
+			return err
+		}
+	case json.Number:
+		// Integers are kept whole, so that those beyond the precision of floats, such as
+		// large unsigned ones, are not rounded.
+		if i, err := val.Int64(); err == nil {
+			return doc.setAndParseType(field, i)
+		}
+		if u, err := strconv.ParseUint(val.String(), 10, 64); err == nil {
+			return doc.setAndParseType(field, u)
+		}
+		f, err := val.Float64()
+		if err != nil {
+			return err
+		}
+		return doc.setAndParseType(field, f)
 	case float64:
 		// case int64:
 
+		if math.IsNaN(val) || math.IsInf(val, 0) {
+			return NewErrNonFiniteFloat(field, val)
+		}
+
 		// Check if its actually a float or just an int
 		if float64(int64(val)) == val { //int
 			err := doc.setCBOR(LWW_REGISTER, field, int64(val))
@@ -311,7 +337,28 @@ func (doc *Document) setAndParseType(field string, value any) error {
 		}
 
 	// string, bool, and more
-	case string, bool, []any:
+	case string, bool:
+		err := doc.setCBOR(LWW_REGISTER, field, val)
+		if err != nil {
+			return err
+		}
+
+	case []any:
+		for i, item := range val {
+			// The numbers of arrays are stored as floats, and converted to the kind of the
+			// array when read.
+			if n, ok := item.(json.Number); ok {
+				f, err := n.Float64()
+				if err != nil {
+					return err
+				}
+				val[i] = f
+				item = f
+			}
+			if f, ok := item.(float64); ok && (math.IsNaN(f) || math.IsInf(f, 0)) {
+				return NewErrNonFiniteFloat(field, f)
+			}
+		}
 		err := doc.setCBOR(LWW_REGISTER, field, val)
 		if err != nil {
 			return err
@@ -354,6 +401,18 @@ func (doc *Document) setAndParseObjectType(value map[string]any) error {
 	return nil
 }
 
+// unmarshalJSONWithNumbers unmarshals the given JSON into the given value, decoding its numbers
+// as json.Number rather than float64 so that they keep their precision.
+func unmarshalJSONWithNumbers(data []byte, v any) error {
+	if !json.Valid(data) {
+		// Returns the same syntax errors as when the numbers are decoded as float64.
+		return json.Unmarshal(data, v)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
+
 // Fields gets the document fields as a map.
 func (doc *Document) Fields() map[string]Field {
 	doc.mu.RLock()
@@ -474,7 +533,7 @@ func (doc *Document) toMapWithKey() (map[string]any, error) {
 }
 
 // DocumentStatus represent the state of the document in the DAG store.
-// It can either be `Active“ or `Deleted`.
+// It can either be `Active“, `Deleted` or `Pruned`.
 type DocumentStatus uint8
 
 const (
@@ -484,11 +543,16 @@ const (
 	// can still be in the datastore but a normal request won't return it. The DAG store will still have all
 	// the associated links.
 	Deleted DocumentStatus = 2
+	// Pruned represents a document that has been deleted by a retention policy or a storage quota
+	// rather than by a user. The peers don't merge its deletion if its collection is append-only,
+	// each node pruning its own documents by its own policies.
+	Pruned DocumentStatus = 3
 )
 
 var DocumentStatusToString = map[DocumentStatus]string{
 	Active:  "Active",
 	Deleted: "Deleted",
+	Pruned:  "Pruned",
 }
 
 func (dStatus DocumentStatus) UInt8() uint8 {
@@ -11,8 +11,10 @@
 package client
 
 import (
+	"math"
 	"testing"
 
+	"github.com/fxamacker/cbor/v2"
 	"github.com/ipfs/go-cid"
 	mh "github.com/multiformats/go-multihash"
 	"github.com/stretchr/testify/assert"
@@ -151,3 +153,48 @@ func TestSetWithJSON(t *testing.T) {
 	// assert.Equal(t, subDoc.values[subDoc.fields["Street"]].IsDocument(), false)
 	// assert.Equal(t, subDoc.values[subDoc.fields["City"]].Value(), "Toronto")
 }
+
+func TestSetWithNonFiniteFloat(t *testing.T) {
+	doc, err := NewDocFromJSON(testJSONObj)
+	if err != nil {
+		t.Error("Error creating new doc from JSON:", err)
+		return
+	}
+
+	for _, value := range []any{math.NaN(), math.Inf(1), math.Inf(-1), []any{1.5, math.NaN()}} {
+		err = doc.Set("Rate", value)
+		assert.ErrorIs(t, err, ErrNonFiniteFloat)
+	}
+	_, exists := doc.fields["Rate"]
+	assert.False(t, exists)
+}
+
+func TestFloatCBORRoundTrip(t *testing.T) {
+	doc, err := NewDocFromJSON(testJSONObj)
+	if err != nil {
+		t.Error("Error creating new doc from JSON:", err)
+		return
+	}
+
+	values := map[string]float64{
+		"Max":      math.MaxFloat64,
+		"Min":      -math.MaxFloat64,
+		"Smallest": math.SmallestNonzeroFloat64,
+		"Fraction": 0.1,
+		"Negative": -2.5,
+	}
+	for field, value := range values {
+		err = doc.Set(field, value)
+		assert.NoError(t, err)
+	}
+
+	buf, err := doc.Bytes()
+	assert.NoError(t, err)
+
+	var decoded map[string]any
+	err = cbor.Unmarshal(buf, &decoded)
+	assert.NoError(t, err)
+	for field, value := range values {
+		assert.Equal(t, value, decoded[field])
+	}
+}
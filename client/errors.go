@@ -23,6 +23,14 @@ const (
 	errParsingFailed         string = "failed to parse argument"
 	errUninitializeProperty  string = "invalid state, required property is uninitialized"
 	errMaxTxnRetries         string = "reached maximum transaction reties"
+	errRequestNotFound       string = "no active request with the given ID"
+	errCommitNotInHistory    string = "the commit is not in the history of the document"
+	errUnknownIsolationLevel string = "unknown isolation level"
+	errWebhookNotFound       string = "no webhook with the given ID"
+	errNonFiniteFloat        string = "float values must be finite, NaN and infinities are not supported"
+	errKVKeyNotFound         string = "no value for the given key in the namespace"
+	errInvalidKVNamespace    string = "invalid key-value namespace"
+	errInvalidKVKey          string = "invalid key-value key"
 )
 
 // Errors returnable from this package.
@@ -30,7 +38,7 @@ const (
 // This list is incomplete and undefined errors may also be returned.
 // Errors returned from this package may be tested against these errors with errors.Is.
 var (
-	ErrFieldNotExist         = errors.New(errFieldNotExist)
+	ErrFieldNotExist         = errors.WithCode(errors.CodeFieldNotExist, errors.New(errFieldNotExist))
 	ErrSelectOfNonGroupField = errors.New(errSelectOfNonGroupField)
 	ErrUnexpectedType        = errors.New(errUnexpectedType)
 	ErrParsingFailed         = errors.New(errParsingFailed)
@@ -38,13 +46,27 @@ var (
 	ErrFieldNotObject        = errors.New("trying to access field on a non object type")
 	ErrValueTypeMismatch     = errors.New("value does not match indicated type")
 	ErrIndexNotFound         = errors.New("no index found for given ID")
-	ErrDocumentNotFound      = errors.New("no document for the given key exists")
-	ErrInvalidUpdateTarget   = errors.New("the target document to update is of invalid type")
-	ErrInvalidUpdater        = errors.New("the updater of a document is of invalid type")
-	ErrInvalidDeleteTarget   = errors.New("the target document to delete is of invalid type")
-	ErrMalformedDocKey       = errors.New("malformed DocKey, missing either version or cid")
-	ErrInvalidDocKeyVersion  = errors.New("invalid DocKey version")
-	ErrMaxTxnRetries         = errors.New(errMaxTxnRetries)
+	ErrDocumentNotFound      = errors.WithCode(
+		errors.CodeDocumentNotFound,
+		errors.New("no document for the given key exists"),
+	)
+	ErrInvalidUpdateTarget = errors.New("the target document to update is of invalid type")
+	ErrInvalidUpdater      = errors.New("the updater of a document is of invalid type")
+	ErrInvalidDeleteTarget = errors.New("the target document to delete is of invalid type")
+	ErrMalformedDocKey     = errors.WithCode(
+		errors.CodeInvalidDocKey,
+		errors.New("malformed DocKey, missing either version or cid"),
+	)
+	ErrInvalidDocKeyVersion  = errors.WithCode(errors.CodeInvalidDocKey, errors.New("invalid DocKey version"))
+	ErrMaxTxnRetries         = errors.WithCode(errors.CodeTransactionConflict, errors.New(errMaxTxnRetries))
+	ErrRequestNotFound       = errors.WithCode(errors.CodeRequestNotFound, errors.New(errRequestNotFound))
+	ErrCommitNotInHistory    = errors.WithCode(errors.CodeCommitNotInHistory, errors.New(errCommitNotInHistory))
+	ErrUnknownIsolationLevel = errors.WithCode(errors.CodeInvalidRequest, errors.New(errUnknownIsolationLevel))
+	ErrWebhookNotFound       = errors.WithCode(errors.CodeWebhookNotFound, errors.New(errWebhookNotFound))
+	ErrNonFiniteFloat        = errors.WithCode(errors.CodeInvalidRequest, errors.New(errNonFiniteFloat))
+	ErrKVKeyNotFound         = errors.WithCode(errors.CodeKVKeyNotFound, errors.New(errKVKeyNotFound))
+	ErrInvalidKVNamespace    = errors.WithCode(errors.CodeInvalidRequest, errors.New(errInvalidKVNamespace))
+	ErrInvalidKVKey          = errors.WithCode(errors.CodeInvalidRequest, errors.New(errInvalidKVKey))
 )
 
 // NewErrFieldNotExist returns an error indicating that the given field does not exist.
@@ -105,3 +127,53 @@ func NewErrUninitializeProperty(host string, propertyName string) error {
 func NewErrMaxTxnRetries(inner error) error {
 	return errors.Wrap(errMaxTxnRetries, inner)
 }
+
+// NewErrRequestNotFound returns an error indicating that no active request with the given ID exists.
+func NewErrRequestNotFound(id uint64) error {
+	return errors.New(errRequestNotFound, errors.NewKV("ID", id))
+}
+
+// NewErrUnknownIsolationLevel returns an error indicating that there is no isolation level with
+// the given name.
+func NewErrUnknownIsolationLevel(name string) error {
+	return errors.New(errUnknownIsolationLevel, errors.NewKV("Name", name))
+}
+
+// NewErrCommitNotInHistory returns an error indicating that the commit with the given CID is not
+// an ancestor of the current heads of the document with the given DocKey.
+func NewErrCommitNotInHistory(docKey string, cid string) error {
+	return errors.New(
+		errCommitNotInHistory,
+		errors.NewKV("DocKey", docKey),
+		errors.NewKV("CID", cid),
+	)
+}
+
+// NewErrWebhookNotFound returns an error indicating that no webhook with the given ID exists.
+func NewErrWebhookNotFound(id string) error {
+	return errors.New(errWebhookNotFound, errors.NewKV("ID", id))
+}
+
+// NewErrNonFiniteFloat returns an error indicating that the value given to the field with the
+// given name is NaN or an infinity, which can't be stored.
+func NewErrNonFiniteFloat(field string, value float64) error {
+	return errors.New(errNonFiniteFloat, errors.NewKV("Field", field), errors.NewKV("Value", value))
+}
+
+// NewErrKVKeyNotFound returns an error indicating that the given key has no value in the given
+// namespace of the key-value store.
+func NewErrKVKeyNotFound(namespace string, key string) error {
+	return errors.New(errKVKeyNotFound, errors.NewKV("Namespace", namespace), errors.NewKV("Key", key))
+}
+
+// NewErrInvalidKVNamespace returns an error indicating that the given namespace of the key-value
+// store is empty or contains characters other than letters, digits, `_` and `-`.
+func NewErrInvalidKVNamespace(namespace string) error {
+	return errors.New(errInvalidKVNamespace, errors.NewKV("Namespace", namespace))
+}
+
+// NewErrInvalidKVKey returns an error indicating that the given key of the key-value store is
+// empty.
+func NewErrInvalidKVKey(key string) error {
+	return errors.New(errInvalidKVKey, errors.NewKV("Key", key))
+}
@@ -0,0 +1,30 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package client
+
+import "context"
+
+type fieldKeysContextKey struct{}
+
+// WithFieldKeys returns a new context in which the values of the encrypted fields are encrypted
+// and decrypted with the given AES-256 keys, by field name of the form `Collection.field`.
+//
+// The values of the encrypted fields without a key can't be written, and are read as null.
+func WithFieldKeys(ctx context.Context, keys map[string][]byte) context.Context {
+	return context.WithValue(ctx, fieldKeysContextKey{}, keys)
+}
+
+// FieldKeysFromContext returns the keys of the encrypted fields of the given context, by field
+// name, or nil if it has none.
+func FieldKeysFromContext(ctx context.Context) map[string][]byte {
+	keys, _ := ctx.Value(fieldKeysContextKey{}).(map[string][]byte)
+	return keys
+}
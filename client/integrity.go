@@ -0,0 +1,81 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package client
+
+import "time"
+
+// IntegrityProblem is the problem found with the system keyspace by [DB.CheckIntegrity].
+type IntegrityProblem string
+
+const (
+	// IntegrityDescriptionMissing is the problem of a collection whose current schema version has
+	// no stored description.
+	IntegrityDescriptionMissing IntegrityProblem = "description-missing"
+	// IntegrityDescriptionInvalid is the problem of a stored collection description that can't
+	// be parsed.
+	IntegrityDescriptionInvalid IntegrityProblem = "description-invalid"
+	// IntegrityDescriptionMismatch is the problem of a collection description whose name is not
+	// the one of the collection it is stored for.
+	IntegrityDescriptionMismatch IntegrityProblem = "description-mismatch"
+	// IntegritySchemaHeadMismatch is the problem of a schema whose current version is not the one
+	// of its collection. It is repaired by pointing the schema to the version of its collection.
+	IntegritySchemaHeadMismatch IntegrityProblem = "schema-head-mismatch"
+	// IntegritySequenceBehind is the problem of a sequence counter lower than the highest ID it
+	// has been used to generate, such that it would generate IDs already in use. It is repaired by
+	// setting the counter to the highest ID in use.
+	IntegritySequenceBehind IntegrityProblem = "sequence-behind"
+)
+
+// CheckIntegrityOptions sets how the system keyspace is checked by [DB.CheckIntegrity].
+type CheckIntegrityOptions struct {
+	// Repair repairs the issues that are known to be fixable.
+	Repair bool `json:"repair,omitempty"`
+}
+
+// IntegrityReport is the result of [DB.CheckIntegrity].
+type IntegrityReport struct {
+	// CheckedAt is the time the system keyspace was checked at.
+	CheckedAt time.Time `json:"checkedAt"`
+
+	// Collections is the number of collections checked.
+	Collections int `json:"collections"`
+
+	// Issues are the inconsistencies found in the system keyspace.
+	Issues []IntegrityIssue `json:"issues"`
+}
+
+// Healthy returns true if no issue was found, or if all of them have been repaired.
+func (r IntegrityReport) Healthy() bool {
+	for _, issue := range r.Issues {
+		if !issue.Repaired {
+			return false
+		}
+	}
+	return true
+}
+
+// IntegrityIssue is an inconsistency of the system keyspace.
+type IntegrityIssue struct {
+	// Key is the system key holding the inconsistent value.
+	Key string `json:"key"`
+
+	// Problem is the problem found with the value of the key.
+	Problem IntegrityProblem `json:"problem"`
+
+	// Detail describes the inconsistency.
+	Detail string `json:"detail,omitempty"`
+
+	// Fixable is true if the issue is known to be repairable.
+	Fixable bool `json:"fixable"`
+
+	// Repaired is true if the issue has been repaired.
+	Repaired bool `json:"repaired"`
+}
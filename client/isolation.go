@@ -0,0 +1,74 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package client
+
+import "context"
+
+// IsolationLevel is the isolation level of the transaction a request runs on.
+//
+// It only applies to requests executed against a [DB] without an explicit transaction, explicit
+// transactions always run at the snapshot isolation level.
+type IsolationLevel int
+
+const (
+	// SnapshotIsolation runs the request on a stable snapshot of the database, taken when its
+	// transaction is created. Writes committed by other transactions afterwards are not seen, and
+	// the request fails with a transaction conflict if it writes to data they modified.
+	//
+	// This is the default isolation level.
+	SnapshotIsolation IsolationLevel = iota
+
+	// ReadCommittedIsolation runs the request without a snapshot, each read seeing the writes
+	// committed at the time of the read, including those committed while the request is running.
+	// Results may thus be inconsistent with one another, but are as fresh as possible.
+	//
+	// Requests run at this level are read-only, mutations fail.
+	ReadCommittedIsolation
+)
+
+// isolationLevelNames maps the isolation levels to their name, as used by the HTTP API.
+var isolationLevelNames = map[IsolationLevel]string{
+	SnapshotIsolation:      "snapshot",
+	ReadCommittedIsolation: "read-committed",
+}
+
+// String returns the name of the isolation level.
+func (l IsolationLevel) String() string {
+	return isolationLevelNames[l]
+}
+
+// ParseIsolationLevel returns the isolation level with the given name.
+func ParseIsolationLevel(name string) (IsolationLevel, error) {
+	for level, levelName := range isolationLevelNames {
+		if levelName == name {
+			return level, nil
+		}
+	}
+	return SnapshotIsolation, NewErrUnknownIsolationLevel(name)
+}
+
+type isolationLevelContextKey struct{}
+
+// WithIsolationLevel returns a new context in which requests run at the given isolation level.
+func WithIsolationLevel(ctx context.Context, level IsolationLevel) context.Context {
+	return context.WithValue(ctx, isolationLevelContextKey{}, level)
+}
+
+// IsolationLevelFromContext returns the isolation level requests run at in the given context.
+//
+// It defaults to SnapshotIsolation.
+func IsolationLevelFromContext(ctx context.Context) IsolationLevel {
+	level, ok := ctx.Value(isolationLevelContextKey{}).(IsolationLevel)
+	if !ok {
+		return SnapshotIsolation
+	}
+	return level
+}
@@ -0,0 +1,48 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIsolationLevel(t *testing.T) {
+	level, err := ParseIsolationLevel("read-committed")
+	require.NoError(t, err)
+	assert.Equal(t, ReadCommittedIsolation, level)
+
+	level, err = ParseIsolationLevel("snapshot")
+	require.NoError(t, err)
+	assert.Equal(t, SnapshotIsolation, level)
+
+	_, err = ParseIsolationLevel("serializable")
+	assert.ErrorIs(t, err, ErrUnknownIsolationLevel)
+}
+
+func TestIsolationLevelFromContext(t *testing.T) {
+	ctx := context.Background()
+	assert.Equal(t, SnapshotIsolation, IsolationLevelFromContext(ctx))
+
+	ctx = WithIsolationLevel(ctx, ReadCommittedIsolation)
+	assert.Equal(t, ReadCommittedIsolation, IsolationLevelFromContext(ctx))
+}
+
+func TestPlanDebugFromContext(t *testing.T) {
+	ctx := context.Background()
+	assert.False(t, PlanDebugFromContext(ctx))
+
+	ctx = WithPlanDebug(ctx)
+	assert.True(t, PlanDebugFromContext(ctx))
+}
@@ -0,0 +1,46 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package client
+
+import (
+	"context"
+)
+
+// KV is the key-value store of the applications, in which they can persist small metadata (e.g.
+// sync cursors or settings) in the same transactions as their documents.
+//
+// Keys are isolated by namespace: the keys of a namespace can't be read or written through
+// another namespace, and none of them overlap with the data managed by DefraDB. Namespaces are
+// made of letters, digits, `_` and `-`, and keys may be any non-empty string.
+type KV interface {
+	// GetKV returns the value of the given key in the given namespace.
+	//
+	// It will return an ErrKVKeyNotFound if the key has no value.
+	GetKV(ctx context.Context, namespace string, key string) ([]byte, error)
+
+	// SetKV sets the value of the given key in the given namespace, replacing any existing value.
+	SetKV(ctx context.Context, namespace string, key string, value []byte) error
+
+	// DeleteKV deletes the value of the given key in the given namespace.
+	//
+	// Deleting a key that has no value is not an error.
+	DeleteKV(ctx context.Context, namespace string, key string) error
+
+	// GetAllKV returns the entries of the given namespace whose key starts with the given prefix,
+	// ordered by key.
+	GetAllKV(ctx context.Context, namespace string, prefix string) ([]KVEntry, error)
+}
+
+// KVEntry is an entry of a namespace of the key-value store.
+type KVEntry struct {
+	Key   string `json:"key"`
+	Value []byte `json:"value"`
+}
@@ -0,0 +1,29 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package client
+
+import "context"
+
+type maskedResultsContextKey struct{}
+
+// WithMaskedResults returns a new context in which the values of the fields selected by queries
+// are masked according to the masking rules of the database, as done for exports shared outside
+// of operations.
+func WithMaskedResults(ctx context.Context) context.Context {
+	return context.WithValue(ctx, maskedResultsContextKey{}, true)
+}
+
+// MaskedResultsFromContext returns true if the results of the queries executed in the given
+// context are masked.
+func MaskedResultsFromContext(ctx context.Context) bool {
+	masked, _ := ctx.Value(maskedResultsContextKey{}).(bool)
+	return masked
+}
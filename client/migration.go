@@ -0,0 +1,49 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package client
+
+// MigrateRelationsOptions sets the documents migrated by [DB.MigrateRelations] and how.
+type MigrateRelationsOptions struct {
+	// Collections restricts the migration to the collections of the given names. All the
+	// collections are migrated if it is empty.
+	Collections []string `json:"collections,omitempty"`
+
+	// BatchSize is the number of documents migrated per transaction.
+	BatchSize int `json:"batchSize"`
+
+	// Keys maps the previous keys of documents that have been re-keyed to their new keys.
+	//
+	// The relation IDs referencing a previous key are rewritten to reference the new key.
+	Keys map[string]string `json:"keys,omitempty"`
+}
+
+// MigrateRelationsProgress reports the progress of [DB.MigrateRelations].
+type MigrateRelationsProgress struct {
+	// Collection is the name of the collection being migrated.
+	Collection string `json:"collection,omitempty"`
+
+	// Scanned is the number of documents scanned so far.
+	Scanned int `json:"scanned"`
+
+	// Migrated is the number of relation IDs rewritten or moved to the primary side of their
+	// relation so far.
+	Migrated int `json:"migrated"`
+
+	// Skipped is the number of relation IDs that could not be migrated so far, as the document
+	// they reference does not exist or already references another document.
+	Skipped int `json:"skipped"`
+
+	// Done is true for the final report of a completed migration.
+	Done bool `json:"done,omitempty"`
+
+	// If the migration failed, this will be the error. It is the final report.
+	Err error `json:"-"`
+}
@@ -0,0 +1,251 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package offline
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/ipfs/go-cid"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/client/request"
+	"github.com/sourcenetwork/defradb/core"
+	corecrdt "github.com/sourcenetwork/defradb/core/crdt"
+	"github.com/sourcenetwork/defradb/merkle/clock"
+)
+
+// DAGState is the state of the DAG of a document, or of one of its fields, at the node.
+//
+// It can be requested with a `latestCommits` query of the document, with the `fieldName` of the
+// field if any, the heads being the CIDs of the returned commits and the height their greatest
+// height.
+type DAGState struct {
+	// Heads are the CIDs of the head commits of the DAG.
+	Heads []cid.Cid
+	// Height is the height of the highest head commit of the DAG.
+	Height uint64
+}
+
+// Commit is a commit of a document, as merged by [client.Collection.MergeBlocks].
+type Commit struct {
+	// Cid is the CID of the commit.
+	Cid cid.Cid
+	// Blocks are the raw data of the block of the commit, followed by the blocks of the fields
+	// it modifies.
+	Blocks [][]byte
+}
+
+// DocumentBuilder tracks the local modifications of a document of a collection, and exports
+// them as a mutation request or as a commit to merge into the document at the node.
+//
+// The document is modified through [DocumentBuilder.Document], its modified fields being those
+// that are dirty. Exporting a commit cleans the document, so that each commit holds the modifications made
+// since the previous one.
+type DocumentBuilder struct {
+	desc client.CollectionDescription
+	doc  *client.Document
+
+	docState    DAGState
+	fieldStates map[string]DAGState
+}
+
+// NewDocumentBuilder returns a builder tracking the modifications of the given document of the
+// collection with the given description.
+//
+// The fields of a document fetched from the node are dirty, and the document must be cleaned
+// before being modified. The document is treated as new until the state of its DAG at the node is set with
+// [DocumentBuilder.SetDocumentState].
+func NewDocumentBuilder(desc client.CollectionDescription, doc *client.Document) *DocumentBuilder {
+	return &DocumentBuilder{
+		desc:        desc,
+		doc:         doc,
+		fieldStates: map[string]DAGState{},
+	}
+}
+
+// Document returns the document whose modifications are tracked.
+func (b *DocumentBuilder) Document() *client.Document {
+	return b.doc
+}
+
+// SetDocumentState sets the state of the DAG of the document at the node, on which the
+// modifications are made.
+func (b *DocumentBuilder) SetDocumentState(state DAGState) {
+	b.docState = state
+}
+
+// SetFieldState sets the state of the DAG of the field with the given name at the node, on which
+// the modifications of the field are made.
+func (b *DocumentBuilder) SetFieldState(field string, state DAGState) {
+	b.fieldStates[field] = state
+}
+
+// IsNew returns true if the document doesn't exist at the node.
+func (b *DocumentBuilder) IsNew() bool {
+	return b.docState.Height == 0
+}
+
+// Modified returns the names of the modified fields of the document, in alphabetical order.
+func (b *DocumentBuilder) Modified() []string {
+	modified := []string{}
+	for name, field := range b.doc.Fields() {
+		val, err := b.doc.GetValueWithField(field)
+		if err == nil && val.IsDirty() {
+			modified = append(modified, name)
+		}
+	}
+	sort.Strings(modified)
+	return modified
+}
+
+// Mutation returns the mutation request applying the modifications of the document at the node,
+// creating the document if it is new.
+//
+// Unlike merging a commit, the modifications are applied as if made at the node at the time
+// the request is executed.
+func (b *DocumentBuilder) Mutation() (string, error) {
+	patch, err := b.patch()
+	if err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return "", err
+	}
+	quotedData, err := json.Marshal(string(data))
+	if err != nil {
+		return "", err
+	}
+
+	var mutation string
+	if b.IsNew() {
+		mutation = fmt.Sprintf("create_%s(data: %s)", b.desc.Name, quotedData)
+	} else {
+		mutation = fmt.Sprintf("update_%s(id: %q, data: %s)", b.desc.Name, b.doc.Key().String(), quotedData)
+	}
+	return fmt.Sprintf("mutation {\n\t%s {\n\t\t%s\n\t}\n}", mutation, request.KeyFieldName), nil
+}
+
+// Commit returns the commit holding the modifications of the document, to be merged into the
+// document at the node.
+//
+// The document is cleaned, and the states of its DAGs advanced to the commit, so that the next
+// commit follows this one. Several commits may be merged at once, or one after the other.
+func (b *DocumentBuilder) Commit() (Commit, error) {
+	patch, err := b.patch()
+	if err != nil {
+		return Commit{}, err
+	}
+
+	docKey := []byte(b.doc.Key().String())
+	commit := Commit{Blocks: [][]byte{nil}}
+	links := make([]core.DAGLink, 0, len(patch))
+	fieldStates := make(map[string]DAGState, len(patch))
+	for _, name := range b.Modified() {
+		val, err := b.doc.GetValue(name)
+		if err != nil {
+			return Commit{}, err
+		}
+		var data []byte
+		if !val.IsDelete() {
+			wval, ok := val.(client.WriteableValue)
+			if !ok {
+				return Commit{}, client.ErrValueTypeMismatch
+			}
+			data, err = wval.Bytes()
+			if err != nil {
+				return Commit{}, err
+			}
+		}
+
+		state := b.fieldStates[name]
+		delta := &corecrdt.LWWRegDelta{
+			SchemaVersionID: b.desc.Schema.VersionID,
+			Priority:        state.Height + 1,
+			Data:            data,
+			DocKey:          docKey,
+		}
+		nd, err := clock.NewNode(delta, state.Heads)
+		if err != nil {
+			return Commit{}, err
+		}
+		commit.Blocks = append(commit.Blocks, nd.RawData())
+		links = append(links, core.DAGLink{Name: name, Cid: nd.Cid()})
+		fieldStates[name] = DAGState{Heads: []cid.Cid{nd.Cid()}, Height: delta.Priority}
+	}
+
+	em, err := cbor.CanonicalEncOptions().EncMode()
+	if err != nil {
+		return Commit{}, err
+	}
+	data, err := em.Marshal(patch)
+	if err != nil {
+		return Commit{}, err
+	}
+	// The links are sorted by CID, as they are by the node.
+	sort.Slice(links, func(i, j int) bool {
+		return strings.Compare(links[i].Cid.String(), links[j].Cid.String()) < 0
+	})
+	delta := &corecrdt.CompositeDAGDelta{
+		SchemaVersionID: b.desc.Schema.VersionID,
+		Priority:        b.docState.Height + 1,
+		Data:            data,
+		DocKey:          docKey,
+		SubDAGs:         links,
+		Status:          client.Active,
+	}
+	nd, err := clock.NewNode(delta, b.docState.Heads)
+	if err != nil {
+		return Commit{}, err
+	}
+	commit.Cid = nd.Cid()
+	commit.Blocks[0] = nd.RawData()
+
+	b.docState = DAGState{Heads: []cid.Cid{nd.Cid()}, Height: delta.Priority}
+	for name, state := range fieldStates {
+		b.fieldStates[name] = state
+	}
+	b.doc.Clean()
+	b.doc.SetHead(nd.Cid())
+
+	return commit, nil
+}
+
+// patch returns the values of the modified fields of the document, by field name, the deleted
+// fields having a nil value.
+func (b *DocumentBuilder) patch() (map[string]any, error) {
+	modified := b.Modified()
+	if len(modified) == 0 {
+		return nil, ErrNoModifications
+	}
+
+	patch := make(map[string]any, len(modified))
+	for _, name := range modified {
+		field, ok := b.desc.GetField(name)
+		if !ok || field.IsObject() || field.Name == request.KeyFieldName {
+			return nil, NewErrUnsupportedField(name)
+		}
+		val, err := b.doc.GetValue(name)
+		if err != nil {
+			return nil, err
+		}
+		if val.IsDelete() {
+			patch[name] = nil
+		} else {
+			patch[name] = val.Value()
+		}
+	}
+	return patch, nil
+}
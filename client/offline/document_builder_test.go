@@ -0,0 +1,216 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package offline
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	badger "github.com/dgraph-io/badger/v3"
+	"github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/client"
+	badgerds "github.com/sourcenetwork/defradb/datastore/badger/v3"
+	"github.com/sourcenetwork/defradb/db"
+)
+
+func newTestCollection(t *testing.T, ctx context.Context) (client.DB, client.Collection) {
+	opts := badgerds.Options{Options: badger.DefaultOptions("").WithInMemory(true)}
+	rootstore, err := badgerds.NewDatastore("", &opts)
+	require.NoError(t, err)
+	defra, err := db.NewDB(ctx, rootstore)
+	require.NoError(t, err)
+	t.Cleanup(func() { defra.Close(ctx) })
+
+	err = defra.AddSchema(ctx, `type users { Name: String Age: Int }`)
+	require.NoError(t, err)
+	col, err := defra.GetCollectionByName(ctx, "users")
+	require.NoError(t, err)
+	return defra, col
+}
+
+// getDAGState returns the state of the DAG of the document with the given key, or of its field
+// with the given name if not empty, as requested from the node.
+func getDAGState(t *testing.T, ctx context.Context, defra client.DB, key client.DocKey, field string) DAGState {
+	args := fmt.Sprintf("dockey: %q", key.String())
+	if field != "" {
+		args += fmt.Sprintf(", fieldName: %q", field)
+	}
+	res := defra.ExecRequest(ctx, fmt.Sprintf(`query { latestCommits(%s) { cid height } }`, args))
+	require.Empty(t, res.GQL.Errors)
+
+	state := DAGState{}
+	for _, commit := range res.GQL.Data.([]map[string]any) {
+		c, err := cid.Decode(commit["cid"].(string))
+		require.NoError(t, err)
+		state.Heads = append(state.Heads, c)
+		if height := uint64(commit["height"].(int64)); height > state.Height {
+			state.Height = height
+		}
+	}
+	return state
+}
+
+func TestDocumentBuilderCommitOfNewDocument(t *testing.T) {
+	ctx := context.Background()
+	_, col := newTestCollection(t, ctx)
+
+	doc, err := client.NewDocFromJSON([]byte(`{"Name": "John", "Age": 21}`))
+	require.NoError(t, err)
+	builder := NewDocumentBuilder(col.Description(), doc)
+	assert.True(t, builder.IsNew())
+	assert.Equal(t, []string{"Age", "Name"}, builder.Modified())
+
+	commit, err := builder.Commit()
+	require.NoError(t, err)
+	assert.Len(t, commit.Blocks, 3)
+	assert.Empty(t, builder.Modified())
+	assert.False(t, builder.IsNew())
+
+	root, err := col.MergeBlocks(ctx, commit.Blocks)
+	require.NoError(t, err)
+	assert.Equal(t, commit.Cid, root)
+
+	merged, err := col.Get(ctx, doc.Key(), false)
+	require.NoError(t, err)
+	name, err := merged.Get("Name")
+	require.NoError(t, err)
+	assert.Equal(t, "John", name)
+}
+
+func TestDocumentBuilderCommitsMergedAtOnce(t *testing.T) {
+	ctx := context.Background()
+	_, col := newTestCollection(t, ctx)
+
+	doc, err := client.NewDocFromJSON([]byte(`{"Name": "John", "Age": 21}`))
+	require.NoError(t, err)
+	builder := NewDocumentBuilder(col.Description(), doc)
+
+	var blocks [][]byte
+	for _, age := range []int{21, 22, 23} {
+		err = builder.Document().Set("Age", age)
+		require.NoError(t, err)
+		commit, err := builder.Commit()
+		require.NoError(t, err)
+		blocks = append(blocks, commit.Blocks...)
+	}
+
+	root, err := col.MergeBlocks(ctx, blocks)
+	require.NoError(t, err)
+	assert.Equal(t, doc.Head(), root)
+
+	merged, err := col.Get(ctx, doc.Key(), false)
+	require.NoError(t, err)
+	age, err := merged.Get("Age")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(23), age)
+}
+
+func TestDocumentBuilderCommitOfExistingDocument(t *testing.T) {
+	ctx := context.Background()
+	defra, col := newTestCollection(t, ctx)
+
+	doc, err := client.NewDocFromJSON([]byte(`{"Name": "John", "Age": 21}`))
+	require.NoError(t, err)
+	err = col.Create(ctx, doc)
+	require.NoError(t, err)
+	err = doc.Set("Age", 22)
+	require.NoError(t, err)
+	err = col.Update(ctx, doc)
+	require.NoError(t, err)
+
+	offlineDoc, err := col.Get(ctx, doc.Key(), false)
+	require.NoError(t, err)
+	offlineDoc.Clean()
+	builder := NewDocumentBuilder(col.Description(), offlineDoc)
+	builder.SetDocumentState(getDAGState(t, ctx, defra, doc.Key(), ""))
+	builder.SetFieldState("Age", getDAGState(t, ctx, defra, doc.Key(), "Age"))
+	assert.False(t, builder.IsNew())
+
+	err = offlineDoc.Set("Age", 30)
+	require.NoError(t, err)
+	commit, err := builder.Commit()
+	require.NoError(t, err)
+	assert.Len(t, commit.Blocks, 2)
+
+	_, err = col.MergeBlocks(ctx, commit.Blocks)
+	require.NoError(t, err)
+
+	merged, err := col.Get(ctx, doc.Key(), false)
+	require.NoError(t, err)
+	age, err := merged.Get("Age")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(30), age)
+	assert.Equal(t, DAGState{Heads: []cid.Cid{commit.Cid}, Height: 3}, getDAGState(t, ctx, defra, doc.Key(), ""))
+	assert.Equal(t, uint64(3), getDAGState(t, ctx, defra, doc.Key(), "Age").Height)
+}
+
+func TestDocumentBuilderMutation(t *testing.T) {
+	ctx := context.Background()
+	defra, col := newTestCollection(t, ctx)
+
+	doc, err := client.NewDocFromJSON([]byte(`{"Name": "John"}`))
+	require.NoError(t, err)
+	builder := NewDocumentBuilder(col.Description(), doc)
+
+	mutation, err := builder.Mutation()
+	require.NoError(t, err)
+	assert.Equal(t, "mutation {\n\tcreate_users(data: \"{\\\"Name\\\":\\\"John\\\"}\") {\n\t\t_key\n\t}\n}", mutation)
+	res := defra.ExecRequest(ctx, mutation)
+	require.Empty(t, res.GQL.Errors)
+
+	doc.Clean()
+	builder.SetDocumentState(getDAGState(t, ctx, defra, doc.Key(), ""))
+	err = doc.Set("Age", 22)
+	require.NoError(t, err)
+	err = doc.Set("Name", "Johnny")
+	require.NoError(t, err)
+
+	mutation, err = builder.Mutation()
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		fmt.Sprintf(
+			"mutation {\n\tupdate_users(id: %q, data: \"{\\\"Age\\\":22,\\\"Name\\\":\\\"Johnny\\\"}\") {\n\t\t_key\n\t}\n}",
+			doc.Key().String(),
+		),
+		mutation,
+	)
+	res = defra.ExecRequest(ctx, mutation)
+	require.Empty(t, res.GQL.Errors)
+
+	updated, err := col.Get(ctx, doc.Key(), false)
+	require.NoError(t, err)
+	age, err := updated.Get("Age")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(22), age)
+	name, err := updated.Get("Name")
+	require.NoError(t, err)
+	assert.Equal(t, "Johnny", name)
+}
+
+func TestDocumentBuilderWithoutModifications(t *testing.T) {
+	ctx := context.Background()
+	_, col := newTestCollection(t, ctx)
+
+	doc, err := client.NewDocFromJSON([]byte(`{"Name": "John"}`))
+	require.NoError(t, err)
+	doc.Clean()
+	builder := NewDocumentBuilder(col.Description(), doc)
+
+	_, err = builder.Commit()
+	require.ErrorIs(t, err, ErrNoModifications)
+	_, err = builder.Mutation()
+	require.ErrorIs(t, err, ErrNoModifications)
+}
@@ -0,0 +1,35 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package offline
+
+import (
+	"github.com/sourcenetwork/defradb/errors"
+)
+
+const (
+	errNoModifications  string = "the document has no local modifications"
+	errUnsupportedField string = "the field can't be modified offline"
+)
+
+// Errors returnable from this package.
+//
+// This list is incomplete and undefined errors may also be returned.
+// Errors returned from this package may be tested against these errors with errors.Is.
+var (
+	ErrNoModifications  = errors.New(errNoModifications)
+	ErrUnsupportedField = errors.New(errUnsupportedField)
+)
+
+// NewErrUnsupportedField returns a new error indicating that the field with the given name is
+// not a scalar field of the collection, which are the only fields that can be modified offline.
+func NewErrUnsupportedField(name string) error {
+	return errors.New(errUnsupportedField, errors.NewKV("Field", name))
+}
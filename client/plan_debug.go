@@ -0,0 +1,28 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package client
+
+import "context"
+
+type planDebugContextKey struct{}
+
+// WithPlanDebug returns a new context in which the planner logs the steps of the construction
+// of the request plans (expand, optimize, select), along with the reasons for its decisions.
+func WithPlanDebug(ctx context.Context) context.Context {
+	return context.WithValue(ctx, planDebugContextKey{}, true)
+}
+
+// PlanDebugFromContext returns true if the planner logs the construction of the request plans
+// in the given context.
+func PlanDebugFromContext(ctx context.Context) bool {
+	enabled, _ := ctx.Value(planDebugContextKey{}).(bool)
+	return enabled
+}
@@ -21,9 +21,18 @@ type Aggregate struct {
 type AggregateTarget struct {
 	HostName  string
 	ChildName immutables.Option[string]
+	// The names of the relations leading from the items of the host to the item
+	// holding the child, if the child is not a property of the host's items.
+	//
+	// For example `reviews` in `_sum(published: {field: {reviews: rating}})`.
+	ChildPath []string
 
 	Limit   immutables.Option[uint64]
 	Offset  immutables.Option[uint64]
 	OrderBy immutables.Option[OrderBy]
 	Filter  immutables.Option[Filter]
+
+	// The number of items, ending with each item, that each value of a running aggregate
+	// aggregates. If none, each value aggregates all the items up to its item.
+	Window immutables.Option[uint64]
 }
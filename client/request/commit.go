@@ -24,6 +24,10 @@ type CommitSelect struct {
 	Cid       immutable.Option[string]
 	Depth     immutable.Option[uint64]
 
+	// FieldNameFilter is the name of the field to return the commits of,
+	// whereas FieldName holds its ID.
+	FieldNameFilter immutable.Option[string]
+
 	Limit   immutable.Option[uint64]
 	Offset  immutable.Option[uint64]
 	OrderBy immutable.Option[OrderBy]
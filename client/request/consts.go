@@ -23,21 +23,42 @@ const (
 	Id          = "id"
 	Ids         = "ids"
 	ShowDeleted = "showDeleted"
-
-	FilterClause  = "filter"
-	GroupByClause = "groupBy"
-	LimitClause   = "limit"
-	OffsetClause  = "offset"
-	OrderClause   = "order"
-	DepthClause   = "depth"
-
-	AverageFieldName = "_avg"
-	CountFieldName   = "_count"
-	KeyFieldName     = "_key"
-	GroupFieldName   = "_group"
-	DeletedFieldName = "_deleted"
-	SumFieldName     = "_sum"
-	VersionFieldName = "_version"
+	AtTime      = "atTime"
+
+	FilterClause    = "filter"
+	GroupByClause   = "groupBy"
+	LimitClause     = "limit"
+	OffsetClause    = "offset"
+	OrderClause     = "order"
+	CollationClause = "collation"
+	DepthClause     = "depth"
+	WindowClause    = "window"
+
+	IncludeMissingClause = "includeMissing"
+
+	LocalFieldClause   = "localField"
+	ForeignFieldClause = "foreignField"
+
+	LocaleArgName          = "locale"
+	CaseInsensitiveArgName = "caseInsensitive"
+	NumericArgName         = "numeric"
+
+	AverageFieldName        = "_avg"
+	CountFieldName          = "_count"
+	KeyFieldName            = "_key"
+	GroupFieldName          = "_group"
+	DeletedFieldName        = "_deleted"
+	CreatedAtFieldName      = "_createdAt"
+	UpdatedAtFieldName      = "_updatedAt"
+	SumFieldName            = "_sum"
+	VersionFieldName        = "_version"
+	HistoryFieldName        = "_history"
+	MetaFieldName           = "_meta"
+	RunningSumFieldName     = "_runningSum"
+	RunningAverageFieldName = "_runningAvg"
+
+	// JoinFieldPrefix prefixes the name of the collection joined by an ad-hoc join field.
+	JoinFieldPrefix = "_join_"
 
 	ExplainLabel = "explain"
 
@@ -52,10 +73,22 @@ const (
 	CollectionIDFieldName    = "collectionID"
 	SchemaVersionIDFieldName = "schemaVersionId"
 	DeltaFieldName           = "delta"
+	FieldIDFieldName         = "fieldId"
+	FieldNameFieldName       = "fieldName"
+	PeerFieldName            = "peer"
 
 	LinksNameFieldName = "name"
 	LinksCidFieldName  = "cid"
 
+	HistoryTypeName    = "FieldHistory"
+	ValueFieldName     = "value"
+	TimestampFieldName = "timestamp"
+
+	MetaTypeName           = "DocMeta"
+	HeadsFieldName         = "heads"
+	DeletedStatusFieldName = "deleted"
+	DeltaCountFieldName    = "deltaCount"
+
 	ASC  = OrderDirection("ASC")
 	DESC = OrderDirection("DESC")
 )
@@ -67,20 +100,42 @@ var (
 	}
 
 	ReservedFields = map[string]bool{
-		TypeNameFieldName: true,
-		VersionFieldName:  true,
-		GroupFieldName:    true,
-		CountFieldName:    true,
-		SumFieldName:      true,
-		AverageFieldName:  true,
-		KeyFieldName:      true,
-		DeletedFieldName:  true,
+		TypeNameFieldName:       true,
+		VersionFieldName:        true,
+		HistoryFieldName:        true,
+		MetaFieldName:           true,
+		GroupFieldName:          true,
+		CountFieldName:          true,
+		SumFieldName:            true,
+		AverageFieldName:        true,
+		RunningSumFieldName:     true,
+		RunningAverageFieldName: true,
+		KeyFieldName:            true,
+		DeletedFieldName:        true,
+		CreatedAtFieldName:      true,
+		UpdatedAtFieldName:      true,
+	}
+
+	// TimestampFields are the meta fields holding the times at which documents were committed,
+	// which can be filtered and ordered by unlike the other reserved fields.
+	TimestampFields = map[string]struct{}{
+		CreatedAtFieldName: {},
+		UpdatedAtFieldName: {},
 	}
 
 	Aggregates = map[string]struct{}{
-		CountFieldName:   {},
-		SumFieldName:     {},
-		AverageFieldName: {},
+		CountFieldName:          {},
+		SumFieldName:            {},
+		AverageFieldName:        {},
+		RunningSumFieldName:     {},
+		RunningAverageFieldName: {},
+	}
+
+	// RunningAggregates are the aggregates returning a value for each of the
+	// aggregated items, aggregated with the items before it.
+	RunningAggregates = map[string]struct{}{
+		RunningSumFieldName:     {},
+		RunningAverageFieldName: {},
 	}
 
 	CommitQueries = map[string]struct{}{
@@ -95,10 +150,27 @@ var (
 		CollectionIDFieldName,
 		SchemaVersionIDFieldName,
 		DeltaFieldName,
+		FieldIDFieldName,
+		FieldNameFieldName,
+		PeerFieldName,
 	}
 
 	LinksFields = []string{
 		LinksNameFieldName,
 		LinksCidFieldName,
 	}
+
+	HistoryFields = []string{
+		ValueFieldName,
+		CidFieldName,
+		HeightFieldName,
+		TimestampFieldName,
+	}
+
+	MetaFields = []string{
+		HeadsFieldName,
+		SchemaVersionIDFieldName,
+		DeletedStatusFieldName,
+		DeltaCountFieldName,
+	}
 )
@@ -29,3 +29,18 @@ type (
 		Conditions []OrderCondition
 	}
 )
+
+// Collation describes how the string values of documents are compared when ordering them,
+// instead of by their bytes.
+type Collation struct {
+	// Locale is the BCP 47 tag of the language whose collation rules apply, the root Unicode
+	// collation applying if it is empty.
+	Locale string
+
+	// CaseInsensitive is true if strings differing only by case are equal.
+	CaseInsensitive bool
+
+	// Numeric is true if sequences of digits are compared by their numeric value, so that
+	// "file2" comes before "file10".
+	Numeric bool
+}
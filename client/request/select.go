@@ -11,6 +11,8 @@
 package request
 
 import (
+	"time"
+
 	"github.com/sourcenetwork/immutable"
 
 	"github.com/sourcenetwork/defradb/client"
@@ -22,6 +24,8 @@ type SelectionType int
 const (
 	ObjectSelection SelectionType = iota
 	CommitSelection
+	HistorySelection
+	MetaSelection
 )
 
 // Select is a complex Field with strong typing.
@@ -33,6 +37,9 @@ type Select struct {
 	DocKeys immutable.Option[[]string]
 	CID     immutable.Option[string]
 
+	// AtTime is the time at which the documents are to be selected as they were.
+	AtTime immutable.Option[time.Time]
+
 	// Root is the top level type of parsed request
 	Root SelectionType
 
@@ -42,9 +49,51 @@ type Select struct {
 	GroupBy immutable.Option[GroupBy]
 	Filter  immutable.Option[Filter]
 
+	// Collation is how the strings are compared when ordering the selected documents, if not
+	// by their bytes.
+	Collation immutable.Option[Collation]
+
 	Fields []Selection
 
 	ShowDeleted bool
+
+	// Depth is the number of levels, including this one, to which this self-referencing
+	// relation is expanded.
+	//
+	// The levels below this one are selected as a child of this Select, each with a Depth
+	// one lower than the level above it.
+	Depth immutable.Option[uint64]
+
+	// IncludeMissing is whether the host documents of this to-one relation are returned when
+	// the related document is missing, with this field set to null. They are returned unless
+	// it is set to false.
+	IncludeMissing immutable.Option[bool]
+
+	// Join holds the fields this collection is joined on with the parent collection, if this
+	// is an ad-hoc join rather than a relation.
+	Join immutable.Option[Join]
+
+	// HistoryField is the name of the field whose history of values is selected, if this is a
+	// selection of the history of a field.
+	HistoryField immutable.Option[string]
+}
+
+// Join describes an ad-hoc join of a collection with the parent collection, on the equality of
+// a field of each rather than on a declared relation.
+type Join struct {
+	// Collection is the name of the joined collection.
+	Collection string
+
+	// LocalField is the name of the field of the parent collection.
+	LocalField string
+
+	// ForeignField is the name of the field of the joined collection.
+	ForeignField string
+}
+
+// JoinFieldName returns the name of the field joining the given collection with an ad-hoc join.
+func JoinFieldName(collection string) string {
+	return JoinFieldPrefix + collection
 }
 
 // Validate validates the Select.
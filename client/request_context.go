@@ -0,0 +1,51 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package client
+
+import "context"
+
+// RequestContext describes the origin of a request, so that the request hooks of the database
+// and the planner can audit the request or restrict what it may access.
+type RequestContext struct {
+	// Identity is the identity the request has been authenticated as. It is empty if the request
+	// is anonymous.
+	Identity string `json:"identity,omitempty"`
+
+	// PeerID is the ID of the peer that forwarded the request. It is empty if the request has not
+	// been forwarded by a peer.
+	PeerID string `json:"peerID,omitempty"`
+
+	// Metadata holds arbitrary values supplied by the client, such as the values of the HTTP
+	// headers mapped by the API configuration, by name.
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// TraceID identifies the request in the logs and the error responses, as given by the client
+	// or generated by the API that received the request. It is kept when the request is forwarded
+	// to another peer.
+	TraceID string `json:"traceID,omitempty"`
+}
+
+type requestContextContextKey struct{}
+
+// WithRequestContext returns a new context in which requests are executed with the given request
+// context.
+func WithRequestContext(ctx context.Context, requestContext RequestContext) context.Context {
+	return context.WithValue(ctx, requestContextContextKey{}, requestContext)
+}
+
+// RequestContextFromContext returns the request context of the requests executed in the given
+// context.
+//
+// It returns an empty request context, that of an anonymous local request, if none has been given.
+func RequestContextFromContext(ctx context.Context) RequestContext {
+	requestContext, _ := ctx.Value(requestContextContextKey{}).(RequestContext)
+	return requestContext
+}
@@ -0,0 +1,61 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package client
+
+import "context"
+
+// SchemaUpdateKind is the kind of a schema update.
+type SchemaUpdateKind string
+
+const (
+	// SchemaUpdateAdd is the addition of a schema, as given to [Store.AddSchema].
+	SchemaUpdateAdd SchemaUpdateKind = "add"
+	// SchemaUpdatePatch is the patch of a schema, as given to [Store.PatchSchema].
+	SchemaUpdatePatch SchemaUpdateKind = "patch"
+)
+
+// SignedSchemaUpdate is a schema update signed by the schema admin identity.
+//
+// When a schema admin identity is configured, schema updates are only applied if signed by it,
+// and the signed updates are shared with the other nodes so that they can verify them too.
+type SignedSchemaUpdate struct {
+	// Kind is the kind of the update.
+	Kind SchemaUpdateKind
+	// Update is the SDL of the added schema, or the JSON patch of the patched schema.
+	Update string
+	// Signature is the signature of the payload of the update, as returned by
+	// [SchemaUpdatePayload], by the schema admin identity.
+	Signature []byte
+}
+
+// SchemaUpdatePayload returns the payload signed by the schema admin identity for the schema
+// update of the given kind.
+//
+// The kind is part of the payload, so that the signature of a schema addition can't be used for
+// a patch and vice versa.
+func SchemaUpdatePayload(kind SchemaUpdateKind, update string) []byte {
+	return []byte("defradb-schema-" + string(kind) + "\n" + update)
+}
+
+type schemaSignatureContextKey struct{}
+
+// WithSchemaSignature returns a new context in which schema updates are signed with the given
+// signature.
+func WithSchemaSignature(ctx context.Context, signature []byte) context.Context {
+	return context.WithValue(ctx, schemaSignatureContextKey{}, signature)
+}
+
+// SchemaSignatureFromContext returns the signature of the schema updates made in the given
+// context, if any.
+func SchemaSignatureFromContext(ctx context.Context) ([]byte, bool) {
+	signature, ok := ctx.Value(schemaSignatureContextKey{}).([]byte)
+	return signature, ok
+}
@@ -0,0 +1,107 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// The functions below map documents to and from Go values, typically structs, allowing embedded
+// users to work with their own types instead of building JSON documents.
+//
+// Values are mapped through their JSON encoding, so struct fields are matched to document fields
+// using their `json` tags. A field tagged `json:"_key,omitempty"` holds the document's DocKey.
+
+// GetInto gets the document with the given DocKey from the given collection and decodes it into dst.
+//
+// Returns an ErrDocumentNotFound if a document matching the given DocKey is not found.
+func GetInto[T any](ctx context.Context, col Collection, key DocKey, showDeleted bool, dst *T) error {
+	doc, err := col.Get(ctx, key, showDeleted)
+	if err != nil {
+		return err
+	}
+	docMap, err := doc.ToMap()
+	if err != nil {
+		return err
+	}
+	return remarshal(docMap, dst)
+}
+
+// CreateFrom creates a new document in the given collection from the given value and returns it.
+//
+// The DocKey of the new document is written back to the `_key` field of src, if it has one.
+func CreateFrom[T any](ctx context.Context, col Collection, src *T) (*Document, error) {
+	data, err := json.Marshal(src)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := NewDocFromJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	err = col.Create(ctx, doc)
+	if err != nil {
+		return nil, err
+	}
+	err = remarshal(map[string]any{"_key": doc.Key().String()}, src)
+	if err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// UpdateFrom updates the document with the given DocKey in the given collection with the
+// values of src and returns it.
+//
+// Fields encoded as null are set to null, fields omitted from the encoding are left unchanged.
+//
+// Returns an ErrDocumentNotFound if a document matching the given DocKey is not found.
+func UpdateFrom[T any](ctx context.Context, col Collection, key DocKey, src *T) (*Document, error) {
+	doc, err := col.Get(ctx, key, false)
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]any{}
+	err = remarshal(src, &values)
+	if err != nil {
+		return nil, err
+	}
+	delete(values, "_key")
+
+	fields := doc.Fields()
+	for name, value := range values {
+		if value != nil {
+			err = doc.Set(name, value)
+		} else if field, exists := fields[name]; exists {
+			err = doc.SetAs(name, nil, field.Type())
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = col.Update(ctx, doc)
+	if err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// remarshal decodes the JSON encoding of src into dst.
+func remarshal(src any, dst any) error {
+	data, err := json.Marshal(src)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dst)
+}
@@ -0,0 +1,107 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package client_test
+
+import (
+	"context"
+	"testing"
+
+	badger "github.com/dgraph-io/badger/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/client"
+	badgerds "github.com/sourcenetwork/defradb/datastore/badger/v3"
+	"github.com/sourcenetwork/defradb/db"
+)
+
+type user struct {
+	Key     string   `json:"_key,omitempty"`
+	Name    string   `json:"name"`
+	Age     int      `json:"age"`
+	Points  float64  `json:"points"`
+	Email   *string  `json:"email"`
+	Friends []string `json:"friends,omitempty"`
+}
+
+func newTestUsersCollection(t *testing.T, ctx context.Context) client.Collection {
+	opts := badgerds.Options{Options: badger.DefaultOptions("").WithInMemory(true)}
+	rootstore, err := badgerds.NewDatastore("", &opts)
+	require.NoError(t, err)
+
+	defraDB, err := db.NewDB(ctx, rootstore)
+	require.NoError(t, err)
+	t.Cleanup(func() { defraDB.Close(ctx) })
+
+	err = defraDB.AddSchema(ctx, `
+		type users {
+			name: String
+			age: Int
+			points: Float
+			email: String
+			friends: [String!]
+		}
+	`)
+	require.NoError(t, err)
+
+	col, err := defraDB.GetCollectionByName(ctx, "users")
+	require.NoError(t, err)
+	return col
+}
+
+func TestCreateFromAndGetInto(t *testing.T) {
+	ctx := context.Background()
+	col := newTestUsersCollection(t, ctx)
+
+	email := "john@example.com"
+	src := user{Name: "John", Age: 21, Points: 4.5, Email: &email, Friends: []string{"Fred"}}
+	doc, err := client.CreateFrom(ctx, col, &src)
+	require.NoError(t, err)
+	assert.Equal(t, doc.Key().String(), src.Key)
+
+	var dst user
+	err = client.GetInto(ctx, col, doc.Key(), false, &dst)
+	require.NoError(t, err)
+	assert.Equal(t, src, dst)
+}
+
+func TestGetIntoWithUnknownKey(t *testing.T) {
+	ctx := context.Background()
+	col := newTestUsersCollection(t, ctx)
+
+	key, err := client.NewDocKeyFromString("bae-d4303725-7db9-53d2-b324-f3ee44020e52")
+	require.NoError(t, err)
+
+	var dst user
+	err = client.GetInto(ctx, col, key, false, &dst)
+	assert.ErrorIs(t, err, client.ErrDocumentNotFound)
+}
+
+func TestUpdateFrom(t *testing.T) {
+	ctx := context.Background()
+	col := newTestUsersCollection(t, ctx)
+
+	email := "john@example.com"
+	src := user{Name: "John", Age: 21, Points: 4.5, Email: &email}
+	doc, err := client.CreateFrom(ctx, col, &src)
+	require.NoError(t, err)
+
+	src.Age = 22
+	src.Points = 5
+	src.Email = nil
+	_, err = client.UpdateFrom(ctx, col, doc.Key(), &src)
+	require.NoError(t, err)
+
+	var dst user
+	err = client.GetInto(ctx, col, doc.Key(), false, &dst)
+	require.NoError(t, err)
+	assert.Equal(t, src, dst)
+}
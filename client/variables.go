@@ -0,0 +1,30 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package client
+
+import "context"
+
+type requestVariablesContextKey struct{}
+
+// WithRequestVariables returns a new context in which requests are executed with the given
+// values of their variables, by variable name.
+func WithRequestVariables(ctx context.Context, variables map[string]any) context.Context {
+	return context.WithValue(ctx, requestVariablesContextKey{}, variables)
+}
+
+// RequestVariablesFromContext returns the values of the variables of the requests executed in
+// the given context, by variable name.
+//
+// It returns nil if no values have been given.
+func RequestVariablesFromContext(ctx context.Context) map[string]any {
+	variables, _ := ctx.Value(requestVariablesContextKey{}).(map[string]any)
+	return variables
+}
@@ -0,0 +1,73 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package client
+
+import (
+	ipld "github.com/ipfs/go-ipld-format"
+)
+
+// BlockProblem is the problem found with a block by [DB.VerifyBlocks].
+type BlockProblem string
+
+const (
+	// BlockMissing is the problem of a block linked to by a document DAG that is not stored.
+	BlockMissing BlockProblem = "missing"
+	// BlockCorrupt is the problem of a stored block whose data doesn't match its CID, or can't be
+	// decoded.
+	BlockCorrupt BlockProblem = "corrupt"
+)
+
+// VerifyBlocksOptions sets the documents whose blocks are verified by [DB.VerifyBlocks] and how.
+type VerifyBlocksOptions struct {
+	// Collections restricts the verification to the collections of the given names. All the
+	// collections are verified if it is empty.
+	Collections []string `json:"collections,omitempty"`
+
+	// Repair re-fetches the missing and corrupt blocks with the Fetcher.
+	Repair bool `json:"repair,omitempty"`
+
+	// Fetcher fetches the blocks to repair from outside of the node, such as from its connected
+	// peers. It is required to repair the blocks.
+	Fetcher ipld.NodeGetter `json:"-"`
+}
+
+// VerifyBlocksReport is the result of [DB.VerifyBlocks].
+type VerifyBlocksReport struct {
+	// Documents is the number of documents whose DAG has been walked.
+	Documents int `json:"documents"`
+
+	// Blocks is the number of distinct blocks verified.
+	Blocks int `json:"blocks"`
+
+	// Issues are the blocks found missing or corrupt.
+	Issues []BlockIssue `json:"issues"`
+}
+
+// BlockIssue is a missing or corrupt block of the DAG of a document.
+type BlockIssue struct {
+	// Collection is the name of the collection of the document.
+	Collection string `json:"collection"`
+
+	// DocKey is the key of the document.
+	DocKey string `json:"docKey"`
+
+	// Cid is the CID of the block.
+	Cid string `json:"cid"`
+
+	// Problem is the problem found with the block.
+	Problem BlockProblem `json:"problem"`
+
+	// Repaired is true if a valid copy of the block has been fetched and stored.
+	Repaired bool `json:"repaired"`
+
+	// Error is the reason the block could not be repaired, if the repair was requested.
+	Error string `json:"error,omitempty"`
+}
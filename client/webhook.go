@@ -0,0 +1,89 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package client
+
+import "time"
+
+// The types of the document events POSTed to webhooks.
+const (
+	WebhookEventCreate = "create"
+	WebhookEventUpdate = "update"
+	WebhookEventDelete = "delete"
+)
+
+// WebhookSignatureHeader is the header holding the signature of the payloads POSTed to the
+// webhooks that have a secret.
+//
+// The signature is `sha256=` followed by the hex encoded HMAC-SHA256 of the payload, keyed
+// with the secret of the webhook.
+const WebhookSignatureHeader = "X-Defra-Signature"
+
+// Webhook is an external URL that the events of the documents of a collection are POSTed to.
+type Webhook struct {
+	// ID uniquely identifies the webhook. It is assigned on registration if empty.
+	ID string `json:"id"`
+
+	// URL is the URL that the events are POSTed to.
+	URL string `json:"url"`
+
+	// Collection is the name of the collection whose document events are POSTed.
+	Collection string `json:"collection"`
+
+	// Filter restricts the create and update events to the documents matching it, using the
+	// same syntax as the filters of requests. All the events are POSTed if it is empty.
+	//
+	// Delete events are always POSTed as deleted documents cannot be filtered.
+	Filter string `json:"filter,omitempty"`
+
+	// Secret is the key that the payloads are signed with, the payloads are not signed if it
+	// is empty.
+	Secret string `json:"secret,omitempty"`
+}
+
+// WebhookEvent is the payload POSTed to webhooks for each document event.
+type WebhookEvent struct {
+	// Event is the type of the event: `create`, `update` or `delete`.
+	Event string `json:"event"`
+
+	// Collection is the name of the collection of the document.
+	Collection string `json:"collection"`
+
+	// DocKey is the key of the document.
+	DocKey string `json:"docKey"`
+
+	// Cid is the CID of the commit of the event.
+	Cid string `json:"cid"`
+
+	// Document holds the fields of the document after the event. It is not set for delete
+	// events.
+	Document map[string]any `json:"document,omitempty"`
+}
+
+// WebhookDeadLetter records an event that could not be delivered to a webhook.
+type WebhookDeadLetter struct {
+	// WebhookID is the ID of the webhook that the event could not be delivered to.
+	WebhookID string `json:"webhookID"`
+
+	// URL is the URL of the webhook at the time of the delivery.
+	URL string `json:"url"`
+
+	// Event is the event that could not be delivered.
+	Event WebhookEvent `json:"event"`
+
+	// Attempts is the number of delivery attempts made.
+	Attempts int `json:"attempts"`
+
+	// Error is the error of the last delivery attempt.
+	Error string `json:"error"`
+
+	// FailedAt is the time of the last delivery attempt.
+	FailedAt time.Time `json:"failedAt"`
+}
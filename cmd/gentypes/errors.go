@@ -0,0 +1,35 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package main
+
+import (
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/errors"
+)
+
+const (
+	errUnsupportedFieldKind string = "unsupported field kind"
+)
+
+var (
+	ErrUnsupportedFieldKind = errors.New(errUnsupportedFieldKind)
+)
+
+// NewErrUnsupportedFieldKind returns an error indicating that no Go type can be generated
+// for the given field.
+func NewErrUnsupportedFieldKind(collection string, field string, kind client.FieldKind) error {
+	return errors.New(
+		errUnsupportedFieldKind,
+		errors.NewKV("Collection", collection),
+		errors.NewKV("Field", field),
+		errors.NewKV("Kind", kind),
+	)
+}
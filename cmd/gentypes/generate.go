@@ -0,0 +1,371 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"go/format"
+	"sort"
+	"strings"
+	"text/template"
+
+	badger "github.com/dgraph-io/badger/v3"
+
+	"github.com/sourcenetwork/defradb/client"
+	badgerds "github.com/sourcenetwork/defradb/datastore/badger/v3"
+	"github.com/sourcenetwork/defradb/db"
+)
+
+// goFieldTypes maps the scalar field kinds to the Go type used to hold their values.
+var goFieldTypes = map[client.FieldKind]string{
+	client.FieldKind_DocKey:                "string",
+	client.FieldKind_BOOL:                  "bool",
+	client.FieldKind_BOOL_ARRAY:            "[]bool",
+	client.FieldKind_INT:                   "int64",
+	client.FieldKind_INT_ARRAY:             "[]int64",
+	client.FieldKind_FLOAT:                 "float64",
+	client.FieldKind_FLOAT_ARRAY:           "[]float64",
+	client.FieldKind_DATETIME:              "time.Time",
+	client.FieldKind_STRING:                "string",
+	client.FieldKind_STRING_ARRAY:          "[]string",
+	client.FieldKind_NILLABLE_BOOL_ARRAY:   "[]*bool",
+	client.FieldKind_NILLABLE_INT_ARRAY:    "[]*int64",
+	client.FieldKind_NILLABLE_FLOAT_ARRAY:  "[]*float64",
+	client.FieldKind_NILLABLE_STRING_ARRAY: "[]*string",
+	client.FieldKind_INT32:                 "int32",
+	client.FieldKind_UINT64:                "uint64",
+}
+
+// typeDef is the template data of a collection.
+type typeDef struct {
+	// Name is the name of the collection.
+	Name string
+	// GoName is the name of the generated struct.
+	GoName string
+	// Fields are the fields of the generated struct.
+	Fields []fieldDef
+	// Selection is the GQL selection set of the scalar fields, used by the generated requests.
+	Selection string
+}
+
+// fieldDef is the template data of a collection field.
+type fieldDef struct {
+	GoName string
+	GoType string
+	Tag    string
+}
+
+// loadCollections loads the given SDL schema into an in-memory database and returns the
+// descriptions of the resulting collections, ordered by name.
+func loadCollections(ctx context.Context, sdl string) ([]client.CollectionDescription, error) {
+	opts := badgerds.Options{Options: badger.DefaultOptions("").WithInMemory(true)}
+	rootstore, err := badgerds.NewDatastore("", &opts)
+	if err != nil {
+		return nil, err
+	}
+	defraDB, err := db.NewDB(ctx, rootstore)
+	if err != nil {
+		return nil, err
+	}
+	defer defraDB.Close(ctx)
+
+	err = defraDB.AddSchema(ctx, sdl)
+	if err != nil {
+		return nil, err
+	}
+
+	cols, err := defraDB.GetAllCollections(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	descriptions := make([]client.CollectionDescription, len(cols))
+	for i, col := range cols {
+		descriptions[i] = col.Description()
+	}
+	sort.Slice(descriptions, func(i, j int) bool {
+		return descriptions[i].Name < descriptions[j].Name
+	})
+	return descriptions, nil
+}
+
+// generate returns the formatted Go source of the types and request builders
+// of the given collections.
+func generate(pkg string, cols []client.CollectionDescription) ([]byte, error) {
+	types := make([]typeDef, 0, len(cols))
+	usesTime := false
+	for _, col := range cols {
+		def, err := newTypeDef(col)
+		if err != nil {
+			return nil, err
+		}
+		for _, field := range def.Fields {
+			usesTime = usesTime || field.GoType == "time.Time"
+		}
+		types = append(types, def)
+	}
+
+	var buf bytes.Buffer
+	err := typesTemplate.Execute(&buf, map[string]any{
+		"Package":  pkg,
+		"Types":    types,
+		"UsesTime": usesTime,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+func newTypeDef(col client.CollectionDescription) (typeDef, error) {
+	def := typeDef{
+		Name:   col.Name,
+		GoName: goName(col.Name),
+	}
+	selection := []string{}
+	for _, field := range col.Schema.Fields {
+		fieldDef := fieldDef{
+			GoName: goName(field.Name),
+			Tag:    field.Name,
+		}
+		switch {
+		case field.IsObjectArray():
+			fieldDef.GoType = "[]" + goName(field.Schema)
+			fieldDef.Tag += ",omitempty"
+
+		case field.IsObject():
+			fieldDef.GoType = "*" + goName(field.Schema)
+			fieldDef.Tag += ",omitempty"
+
+		default:
+			goType, ok := goFieldTypes[field.Kind]
+			if !ok {
+				return typeDef{}, NewErrUnsupportedFieldKind(col.Name, field.Name, field.Kind)
+			}
+			fieldDef.GoType = goType
+			if field.Kind == client.FieldKind_DocKey {
+				// dockeys are generated on creation and relation IDs are optional.
+				fieldDef.Tag += ",omitempty"
+			}
+			selection = append(selection, field.Name)
+		}
+		def.Fields = append(def.Fields, fieldDef)
+	}
+	def.Selection = strings.Join(selection, " ")
+	return def, nil
+}
+
+// goName returns the exported Go identifier of the given schema name,
+// e.g. `author_id` becomes `AuthorID`.
+func goName(name string) string {
+	parts := strings.Split(name, "_")
+	var b strings.Builder
+	for _, part := range parts {
+		if part == "" {
+			continue
+		}
+		if part == "id" {
+			b.WriteString("ID")
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+var typesTemplate = template.Must(template.New("types").Parse(`// Code generated by gentypes. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+{{- if .UsesTime}}
+	"time"
+{{- end}}
+
+	"github.com/sourcenetwork/defradb/client"
+)
+
+{{range .Types -}}
+// {{.GoName}} is a document of the {{.Name}} collection.
+type {{.GoName}} struct {
+{{- range .Fields}}
+	{{.GoName}} {{.GoType}} ` + "`json:\"{{.Tag}}\"`" + `
+{{- end}}
+}
+
+// {{.GoName}}Collection provides typed requests against the {{.Name}} collection.
+type {{.GoName}}Collection struct {
+	db client.DB
+}
+
+// New{{.GoName}}Collection returns the typed {{.Name}} collection of the given database.
+func New{{.GoName}}Collection(db client.DB) {{.GoName}}Collection {
+	return {{.GoName}}Collection{db: db}
+}
+
+// Create creates the given document and returns it as stored.
+func (c {{.GoName}}Collection) Create(ctx context.Context, doc {{.GoName}}) ({{.GoName}}, error) {
+	data, err := gqlData(doc)
+	if err != nil {
+		return {{.GoName}}{}, err
+	}
+	var docs []{{.GoName}}
+	err = execRequest(ctx, c.db, fmt.Sprintf("mutation { create_{{.Name}}(data: %s) { {{.Selection}} } }", data), &docs)
+	if err != nil {
+		return {{.GoName}}{}, err
+	}
+	if len(docs) == 0 {
+		return {{.GoName}}{}, client.ErrDocumentNotFound
+	}
+	return docs[0], nil
+}
+
+// Get returns the document with the given dockey.
+func (c {{.GoName}}Collection) Get(ctx context.Context, dockey string) ({{.GoName}}, error) {
+	var docs []{{.GoName}}
+	err := execRequest(ctx, c.db, fmt.Sprintf("query { {{.Name}}(dockey: %s) { {{.Selection}} } }", gqlValue(dockey)), &docs)
+	if err != nil {
+		return {{.GoName}}{}, err
+	}
+	if len(docs) == 0 {
+		return {{.GoName}}{}, client.ErrDocumentNotFound
+	}
+	return docs[0], nil
+}
+
+// Find returns the documents matching the given filter, e.g. ` + "`" + `map[string]any{"name": map[string]any{"_eq": "John"}}` + "`" + `.
+//
+// All documents are returned if the filter is empty.
+func (c {{.GoName}}Collection) Find(ctx context.Context, filter map[string]any) ([]{{.GoName}}, error) {
+	args := ""
+	if len(filter) > 0 {
+		args = fmt.Sprintf("(filter: %s)", gqlValue(filter))
+	}
+	docs := []{{.GoName}}{}
+	err := execRequest(ctx, c.db, fmt.Sprintf("query { {{.Name}}%s { {{.Selection}} } }", args), &docs)
+	return docs, err
+}
+
+// Update replaces the values of the document with the given dockey by those of the given document.
+func (c {{.GoName}}Collection) Update(ctx context.Context, dockey string, doc {{.GoName}}) ({{.GoName}}, error) {
+	data, err := gqlData(doc)
+	if err != nil {
+		return {{.GoName}}{}, err
+	}
+	var docs []{{.GoName}}
+	request := fmt.Sprintf("mutation { update_{{.Name}}(id: %s, data: %s) { {{.Selection}} } }", gqlValue(dockey), data)
+	err = execRequest(ctx, c.db, request, &docs)
+	if err != nil {
+		return {{.GoName}}{}, err
+	}
+	if len(docs) == 0 {
+		return {{.GoName}}{}, client.ErrDocumentNotFound
+	}
+	return docs[0], nil
+}
+
+// Delete deletes the document with the given dockey.
+func (c {{.GoName}}Collection) Delete(ctx context.Context, dockey string) error {
+	var docs []{{.GoName}}
+	err := execRequest(ctx, c.db, fmt.Sprintf("mutation { delete_{{.Name}}(id: %s) { _key } }", gqlValue(dockey)), &docs)
+	if err != nil {
+		return err
+	}
+	if len(docs) == 0 {
+		return client.ErrDocumentNotFound
+	}
+	return nil
+}
+
+{{end -}}
+// execRequest executes the given request and decodes its data into the given value.
+func execRequest(ctx context.Context, db client.DB, request string, v any) error {
+	res := db.ExecRequest(ctx, request)
+	if len(res.GQL.Errors) > 0 {
+		return res.GQL.Errors[0]
+	}
+	data, err := json.Marshal(res.GQL.Data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// gqlData returns the GQL string literal of the JSON document of the given value,
+// excluding the dockey and the related documents.
+func gqlData(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	fields := map[string]any{}
+	err = json.Unmarshal(data, &fields)
+	if err != nil {
+		return "", err
+	}
+	delete(fields, "_key")
+	for name, value := range fields {
+		if isDocument(value) {
+			delete(fields, name)
+		}
+	}
+	data, err = json.Marshal(fields)
+	if err != nil {
+		return "", err
+	}
+	return gqlValue(string(data)), nil
+}
+
+// isDocument returns true if the given JSON value is a document or an array of documents.
+func isDocument(v any) bool {
+	switch value := v.(type) {
+	case map[string]any:
+		return true
+	case []any:
+		for _, item := range value {
+			if isDocument(item) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// gqlValue returns the GQL literal of the given value.
+//
+// Maps are written as input objects, strings are quoted and any other value is written as JSON.
+func gqlValue(v any) string {
+	switch value := v.(type) {
+	case map[string]any:
+		fields := ""
+		for name, field := range value {
+			fields += fmt.Sprintf("%s: %s, ", name, gqlValue(field))
+		}
+		return "{" + fields + "}"
+	case []any:
+		items := ""
+		for _, item := range value {
+			items += gqlValue(item) + ", "
+		}
+		return "[" + items + "]"
+	default:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return "null"
+		}
+		return string(data)
+	}
+}
+`))
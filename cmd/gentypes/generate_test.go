@@ -0,0 +1,88 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package main
+
+import (
+	"context"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/client"
+)
+
+const testSchema = `
+type Author {
+	name: String
+	age: Int
+	born: DateTime
+	books: [Book]
+}
+
+type Book {
+	name: String
+	rating: Float
+	author: Author
+}
+`
+
+func TestGoName(t *testing.T) {
+	assert.Equal(t, "Key", goName("_key"))
+	assert.Equal(t, "Name", goName("name"))
+	assert.Equal(t, "AuthorID", goName("author_id"))
+	assert.Equal(t, "FirstName", goName("first_name"))
+}
+
+func TestGenerate(t *testing.T) {
+	ctx := context.Background()
+	cols, err := loadCollections(ctx, testSchema)
+	require.NoError(t, err)
+	require.Len(t, cols, 2)
+
+	src, err := generate("models", cols)
+	require.NoError(t, err)
+
+	_, err = parser.ParseFile(token.NewFileSet(), "models.go", src, parser.AllErrors)
+	require.NoError(t, err)
+
+	code := string(src)
+	assert.Contains(t, code, "package models")
+	assert.Contains(t, code, `"time"`)
+	assert.Contains(t, code, "type Author struct {")
+	assert.Regexp(t, "Born +time.Time +`json:\"born\"`", code)
+	assert.Regexp(t, "Books +\\[\\]Book +`json:\"books,omitempty\"`", code)
+	assert.Contains(t, code, "type Book struct {")
+	assert.Regexp(t, "Author +\\*Author +`json:\"author,omitempty\"`", code)
+	assert.Regexp(t, "AuthorID +string +`json:\"author_id,omitempty\"`", code)
+	assert.Contains(t, code, "func NewBookCollection(db client.DB) BookCollection {")
+	assert.Contains(t, code, "create_Book(data: %s) { _key author_id name rating }")
+}
+
+func TestGenerateWithUnsupportedFieldKind(t *testing.T) {
+	cols := []client.CollectionDescription{
+		{
+			Name: "User",
+			Schema: client.SchemaDescription{
+				Name: "User",
+				Fields: []client.FieldDescription{
+					{Name: "_key", Kind: client.FieldKind_DocKey},
+					{Name: "name", Kind: client.FieldKind_None},
+				},
+			},
+		},
+	}
+
+	_, err := generate("models", cols)
+	assert.ErrorIs(t, err, ErrUnsupportedFieldKind)
+}
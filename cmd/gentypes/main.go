@@ -0,0 +1,69 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+/*
+gentypes is a tool to generate typed Go structs and request builders from a DefraDB schema.
+
+The schema is loaded into an in-memory DefraDB instance and the resulting collection
+descriptions are used to generate, for each collection, a struct holding its documents and
+a collection type exposing typed create, get, find, update and delete operations against a
+[client.DB].
+
+It is intended to be used with go:generate:
+
+	//go:generate go run github.com/sourcenetwork/defradb/cmd/gentypes -schema schema.graphql -o models.go -package models
+*/
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+
+	"github.com/sourcenetwork/defradb/logging"
+)
+
+var log = logging.MustNewLogger("defra.gentypes")
+
+func main() {
+	schemaPath := flag.String("schema", "schema.graphql", "path to the GraphQL SDL schema to generate types from")
+	outputPath := flag.String("o", "defradb_types.go", "path to write the generated Go file to")
+	pkg := flag.String("package", "", "name of the package of the generated file (defaults to $GOPACKAGE)")
+	flag.Parse()
+
+	ctx := context.Background()
+
+	if *pkg == "" {
+		*pkg = os.Getenv("GOPACKAGE")
+	}
+	if *pkg == "" {
+		log.Fatal(ctx, "A package name must be given with -package when not run by go generate")
+	}
+
+	sdl, err := os.ReadFile(*schemaPath)
+	if err != nil {
+		log.FatalE(ctx, "Reading the schema failed", err)
+	}
+
+	cols, err := loadCollections(ctx, string(sdl))
+	if err != nil {
+		log.FatalE(ctx, "Loading the schema failed", err)
+	}
+
+	src, err := generate(*pkg, cols)
+	if err != nil {
+		log.FatalE(ctx, "Generating types failed", err)
+	}
+
+	err = os.WriteFile(*outputPath, src, 0o644)
+	if err != nil {
+		log.FatalE(ctx, "Writing the generated types failed", err)
+	}
+}
@@ -24,13 +24,19 @@ For each, a way to go from top-level configuration to package-specific configura
 Parameters are determined by, in order of least importance: defaults, configuration file, env. variables, and then CLI
 flags. That is, CLI flags can override everything else.
 
+The configuration file is `config.yaml` in the root directory, or `config.toml` if there is no YAML file. Its format
+is versioned by its `version` parameter, and the files of an unsupported version are rejected. Parameters missing from
+the file take their default values, and can still be set with env. variables.
+
 For example `DEFRA_DATASTORE_BADGER_PATH` matches [Config.Datastore.Badger.Path] and in the config file:
 
 	datastore:
 		badger:
 			path: /tmp/badger
 
-This implementation does not support online modification of configuration.
+Some parameters (logging, CORS, rate limits, bootstrap peers) can be modified while the node is
+running, either by reloading the configuration with [Config.Reload] or by updating individual keys
+with [Config.Update]. Other parameters only take effect after a restart.
 
 How to use, e.g. without using a rootdir:
 
@@ -47,18 +53,23 @@ import (
 	"encoding/json"
 	"fmt"
 	"net"
+	"net/url"
+	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
 	"text/template"
 	"time"
 
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/mitchellh/mapstructure"
 	ma "github.com/multiformats/go-multiaddr"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 	"golang.org/x/net/idna"
 
+	corecrdt "github.com/sourcenetwork/defradb/core/crdt"
 	badgerds "github.com/sourcenetwork/defradb/datastore/badger/v3"
 	"github.com/sourcenetwork/defradb/logging"
 	"github.com/sourcenetwork/defradb/node"
@@ -74,27 +85,47 @@ const (
 	logLevelInfo    = "info"
 	logLevelError   = "error"
 	logLevelFatal   = "fatal"
+
+	// ConfigVersion is the version of the format of the configuration file.
+	ConfigVersion = 1
 )
 
 // Config is DefraDB's main configuration struct, embedding component-specific config structs.
 type Config struct {
-	Datastore *DatastoreConfig
-	API       *APIConfig
-	Net       *NetConfig
-	Log       *LoggingConfig
-	Rootdir   string
-	v         *viper.Viper
+	// Version of the format of the configuration file.
+	Version     int
+	Datastore   *DatastoreConfig
+	API         *APIConfig
+	Net         *NetConfig
+	Log         *LoggingConfig
+	Sink        *SinkConfig
+	Pinning     *PinningConfig
+	Replication *ReplicationConfig
+	PGWire      *PGWireConfig
+	Retention   *RetentionConfig
+	Masking     *MaskingConfig
+	Quota       *QuotaConfig
+	Rootdir     string
+	v           *viper.Viper
 }
 
 // DefaultConfig returns the default configuration (or panics).
 func DefaultConfig() *Config {
 	cfg := &Config{
-		Datastore: defaultDatastoreConfig(),
-		API:       defaultAPIConfig(),
-		Net:       defaultNetConfig(),
-		Log:       defaultLogConfig(),
-		Rootdir:   "",
-		v:         viper.New(),
+		Version:     ConfigVersion,
+		Datastore:   defaultDatastoreConfig(),
+		API:         defaultAPIConfig(),
+		Net:         defaultNetConfig(),
+		Log:         defaultLogConfig(),
+		Sink:        defaultSinkConfig(),
+		Pinning:     defaultPinningConfig(),
+		Replication: defaultReplicationConfig(),
+		PGWire:      defaultPGWireConfig(),
+		Retention:   defaultRetentionConfig(),
+		Masking:     defaultMaskingConfig(),
+		Quota:       defaultQuotaConfig(),
+		Rootdir:     "",
+		v:           viper.New(),
 	}
 
 	cfg.v.SetEnvPrefix(defraEnvPrefix)
@@ -111,6 +142,11 @@ func DefaultConfig() *Config {
 	if err = cfg.v.ReadConfig(bytes.NewReader(b)); err != nil {
 		panic(NewErrReadingConfigFile(err))
 	}
+	// The default values are kept apart from those of the config file, which replaces them once
+	// read, so that the parameters missing from the file keep their default values.
+	for _, key := range cfg.v.AllKeys() {
+		cfg.v.SetDefault(key, cfg.v.Get(key))
+	}
 
 	return cfg
 }
@@ -132,6 +168,7 @@ func (cfg *Config) LoadWithRootdir(withRootdir bool) error {
 	}
 
 	if withRootdir {
+		cfg.useConfigFile()
 		if err := cfg.v.ReadInConfig(); err != nil {
 			return NewErrReadingConfigFile(err)
 		}
@@ -184,6 +221,9 @@ func (cfg *Config) setRootdir(rootdir string) error {
 }
 
 func (cfg *Config) validate() error {
+	if cfg.Version < 1 || cfg.Version > ConfigVersion {
+		return NewErrFailedToValidateConfig(NewErrUnsupportedConfigVersion(cfg.Version))
+	}
 	if err := cfg.Datastore.validate(); err != nil {
 		return NewErrFailedToValidateConfig(err)
 	}
@@ -196,6 +236,52 @@ func (cfg *Config) validate() error {
 	if err := cfg.Log.validate(); err != nil {
 		return NewErrFailedToValidateConfig(err)
 	}
+	if err := cfg.Sink.validate(); err != nil {
+		return NewErrFailedToValidateConfig(err)
+	}
+	if err := cfg.Pinning.validate(); err != nil {
+		return NewErrFailedToValidateConfig(err)
+	}
+	if err := cfg.Replication.validate(); err != nil {
+		return NewErrFailedToValidateConfig(err)
+	}
+	if err := cfg.PGWire.validate(); err != nil {
+		return NewErrFailedToValidateConfig(err)
+	}
+	if err := cfg.Retention.validate(); err != nil {
+		return NewErrFailedToValidateConfig(err)
+	}
+	if err := cfg.Masking.validate(); err != nil {
+		return NewErrFailedToValidateConfig(err)
+	}
+	if err := cfg.Quota.validate(); err != nil {
+		return NewErrFailedToValidateConfig(err)
+	}
+	return nil
+}
+
+// CheckUnknownKeys returns an error listing the parameters of the loaded configuration that are
+// not configuration parameters, such as the misspelled keys of the configuration file.
+func (cfg *Config) CheckUnknownKeys() error {
+	settings := cfg.v.AllSettings()
+	delete(settings, RootdirKey)
+
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook: mapstructure.ComposeDecodeHookFunc(
+			mapstructure.TextUnmarshallerHookFunc(),
+			mapstructure.StringToTimeDurationHookFunc(),
+			mapstructure.StringToSliceHookFunc(","),
+		),
+		ErrorUnused:      true,
+		WeaklyTypedInput: true,
+		Result:           &Config{},
+	})
+	if err != nil {
+		return err
+	}
+	if err := decoder.Decode(settings); err != nil {
+		return NewErrUnknownConfigKeys(err)
+	}
 	return nil
 }
 
@@ -210,6 +296,10 @@ func (cfg *Config) paramsPreprocessing() error {
 	if !filepath.IsAbs(cfg.v.GetString("api.pubkeypath")) {
 		cfg.v.Set("api.pubkeypath", filepath.Join(cfg.Rootdir, cfg.v.GetString("api.pubkeypath")))
 	}
+	keysPath := cfg.v.GetString("datastore.encryptionkeys")
+	if keysPath != "" && !filepath.IsAbs(keysPath) {
+		cfg.v.Set("datastore.encryptionkeys", filepath.Join(cfg.Rootdir, keysPath))
+	}
 
 	// log.logger configuration as a string
 	logloggerAsStringSlice := cfg.v.GetStringSlice("log.logger")
@@ -248,6 +338,77 @@ type DatastoreConfig struct {
 	Memory        MemoryConfig
 	Badger        BadgerConfig
 	MaxTxnRetries int
+	// Initial delay before retrying a request whose transaction conflicted. Zero disables the retries.
+	TxnRetryBackoff string
+	// Maximum number of recently fetched documents cached per collection. Zero disables the cache.
+	DocumentCacheSize int
+	// Maximum number of concurrent read only transactions, those of the queries. Zero is unlimited.
+	MaxReadTxns int
+	// Maximum cost of the requests, weighted by the `@cost` multipliers of the collections. Zero is
+	// unlimited.
+	MaxRequestCost int
+	// Whether the blocks are compressed at rest.
+	CompressBlocks bool
+	// Whether the integrity of the system keyspace is checked on startup.
+	CheckIntegrity bool
+	// Whether the integrity issues known to be fixable are repaired on startup. It implies the
+	// integrity check.
+	RepairIntegrity bool
+	// Path of the JSON file holding the base64 encoded AES-256 keys the deltas of the encrypted
+	// collections are encrypted with, by collection name. No collection is encrypted if empty.
+	EncryptionKeys string
+	// Path of the JSON file holding the base64 encoded AES-256 keys of the encrypted fields the
+	// requests authenticated as each identity may read and write, by identity then by field name of
+	// the form `Collection.field`. Keys may otherwise be presented along with the requests.
+	FieldKeys string
+	// Object storage the cold blocks are offloaded to.
+	S3 S3Config
+}
+
+// S3Config configures the S3-compatible object storage that the cold blocks, those of the old
+// commits, are offloaded to. Blocks are not offloaded if the endpoint is empty.
+type S3Config struct {
+	Endpoint  string
+	Secure    bool
+	Region    string
+	Bucket    string
+	Prefix    string
+	AccessKey string `json:"-"`
+	SecretKey string `json:"-"`
+	// Maximum number of recently read cold blocks cached locally.
+	CacheSize int
+	// Interval at which the cold blocks are offloaded.
+	OffloadInterval string
+}
+
+// OffloadIntervalDuration gives the interval at which the cold blocks are offloaded as a
+// time.Duration.
+func (s3cfg S3Config) OffloadIntervalDuration() (time.Duration, error) {
+	d, err := time.ParseDuration(s3cfg.OffloadInterval)
+	if err != nil {
+		return d, NewErrInvalidOffloadInterval(err, s3cfg.OffloadInterval)
+	}
+	return d, nil
+}
+
+func (s3cfg S3Config) validate() error {
+	if s3cfg.Endpoint == "" {
+		return nil
+	}
+	if s3cfg.Bucket == "" {
+		return ErrMissingS3Bucket
+	}
+	if s3cfg.CacheSize <= 0 {
+		return NewErrInvalidColdCacheSize(s3cfg.CacheSize)
+	}
+	d, err := s3cfg.OffloadIntervalDuration()
+	if err != nil {
+		return err
+	}
+	if d <= 0 {
+		return NewErrInvalidOffloadInterval(nil, s3cfg.OffloadInterval)
+	}
+	return nil
 }
 
 // BadgerConfig configures Badger's on-disk / filesystem mode.
@@ -272,7 +433,13 @@ func defaultDatastoreConfig() *DatastoreConfig {
 			ValueLogFileSize: 1 * GiB,
 			Options:          &opts,
 		},
-		MaxTxnRetries: 5,
+		MaxTxnRetries:   5,
+		TxnRetryBackoff: "0s",
+		S3: S3Config{
+			Secure:          true,
+			CacheSize:       1024,
+			OffloadInterval: "1h",
+		},
 	}
 }
 
@@ -282,7 +449,75 @@ func (dbcfg DatastoreConfig) validate() error {
 	default:
 		return NewErrInvalidDatastoreType(dbcfg.Store)
 	}
-	return nil
+	_, err := time.ParseDuration(dbcfg.TxnRetryBackoff)
+	if err != nil {
+		return NewErrInvalidTxnRetryBackoff(err, dbcfg.TxnRetryBackoff)
+	}
+	if dbcfg.DocumentCacheSize < 0 {
+		return NewErrInvalidDocumentCacheSize(dbcfg.DocumentCacheSize)
+	}
+	if dbcfg.MaxReadTxns < 0 {
+		return NewErrInvalidMaxReadTxns(dbcfg.MaxReadTxns)
+	}
+	if dbcfg.MaxRequestCost < 0 {
+		return NewErrInvalidMaxRequestCost(dbcfg.MaxRequestCost)
+	}
+	return dbcfg.S3.validate()
+}
+
+// TxnRetryBackoffDuration gives the transaction retry backoff as a time.Duration.
+// Keyring loads the keys the deltas of the encrypted collections are encrypted with.
+//
+// Returns nil if no collection is encrypted.
+func (dbcfg DatastoreConfig) Keyring() (*corecrdt.Keyring, error) {
+	if dbcfg.EncryptionKeys == "" {
+		return nil, nil
+	}
+	buf, err := os.ReadFile(dbcfg.EncryptionKeys)
+	if err != nil {
+		return nil, NewErrInvalidEncryptionKeys(err, dbcfg.EncryptionKeys)
+	}
+	// The keys are base64 encoded, as done by encoding/json for byte slices.
+	var keys map[string][]byte
+	if err := json.Unmarshal(buf, &keys); err != nil {
+		return nil, NewErrInvalidEncryptionKeys(err, dbcfg.EncryptionKeys)
+	}
+	keyring, err := corecrdt.NewKeyring(keys)
+	if err != nil {
+		return nil, NewErrInvalidEncryptionKeys(err, dbcfg.EncryptionKeys)
+	}
+	return keyring, nil
+}
+
+// IdentityFieldKeys loads the keys of the encrypted fields readable by each identity, by
+// identity then by field name.
+func (dbcfg DatastoreConfig) IdentityFieldKeys() (map[string]map[string][]byte, error) {
+	if dbcfg.FieldKeys == "" {
+		return nil, nil
+	}
+	buf, err := os.ReadFile(dbcfg.FieldKeys)
+	if err != nil {
+		return nil, NewErrInvalidFieldKeys(err, dbcfg.FieldKeys)
+	}
+	// The keys are base64 encoded, as done by encoding/json for byte slices.
+	var keys map[string]map[string][]byte
+	if err := json.Unmarshal(buf, &keys); err != nil {
+		return nil, NewErrInvalidFieldKeys(err, dbcfg.FieldKeys)
+	}
+	for _, identityKeys := range keys {
+		if _, err := corecrdt.NewKeyring(identityKeys); err != nil {
+			return nil, NewErrInvalidFieldKeys(err, dbcfg.FieldKeys)
+		}
+	}
+	return keys, nil
+}
+
+func (dbcfg DatastoreConfig) TxnRetryBackoffDuration() (time.Duration, error) {
+	d, err := time.ParseDuration(dbcfg.TxnRetryBackoff)
+	if err != nil {
+		return d, NewErrInvalidTxnRetryBackoff(err, dbcfg.TxnRetryBackoff)
+	}
+	return d, nil
 }
 
 // APIConfig configures the API endpoints.
@@ -292,19 +527,101 @@ type APIConfig struct {
 	PubKeyPath  string
 	PrivKeyPath string
 	Email       string
+	// Comma separated list of origins allowed for CORS requests.
+	AllowedOrigins string
+	// Maximum number of requests per second served by the API. Zero means unlimited.
+	RateLimit int
+	// Token required to access the admin endpoints. The admin endpoints are disabled if empty.
+	AdminToken string `json:"-"`
+	// Whether the profiling endpoints are served. They are admin endpoints, requiring the admin token.
+	Profiling bool
+	// Comma separated list of the HTTP headers whose values are passed to the request hooks as the
+	// metadata of the requests.
+	MetadataHeaders string
+	// Comma separated list of the URLs of the remote nodes the queries for the collections the
+	// node does not hold are delegated to, the node acting as a gateway to them.
+	GatewayRemotes string
+	// Detail of the errors returned to the clients: `message` only, `code` to add the error codes
+	// or `stack` to also add the stack traces. Errors are always logged in full.
+	ErrorVerbosity string
 }
 
+// Error verbosities of the API responses.
+const (
+	ErrorVerbosityMessage = "message"
+	ErrorVerbosityCode    = "code"
+	ErrorVerbosityStack   = "stack"
+)
+
 func defaultAPIConfig() *APIConfig {
 	return &APIConfig{
-		Address:     "localhost:9181",
-		TLS:         false,
-		PubKeyPath:  "certs/server.key",
-		PrivKeyPath: "certs/server.crt",
-		Email:       DefaultAPIEmail,
+		Address:        "localhost:9181",
+		TLS:            false,
+		PubKeyPath:     "certs/server.key",
+		PrivKeyPath:    "certs/server.crt",
+		Email:          DefaultAPIEmail,
+		AllowedOrigins: "",
+		RateLimit:      0,
+		AdminToken:     "",
+		Profiling:      false,
+		ErrorVerbosity: ErrorVerbosityCode,
+	}
+}
+
+// AllowedOriginsList returns the allowed CORS origins as a list.
+func (apicfg *APIConfig) AllowedOriginsList() []string {
+	if apicfg.AllowedOrigins == "" {
+		return nil
+	}
+	origins := strings.Split(apicfg.AllowedOrigins, ",")
+	for i, origin := range origins {
+		origins[i] = strings.TrimSpace(origin)
+	}
+	return origins
+}
+
+// MetadataHeadersList returns the HTTP headers mapped to the metadata of the requests as a list.
+func (apicfg *APIConfig) MetadataHeadersList() []string {
+	if apicfg.MetadataHeaders == "" {
+		return nil
+	}
+	headers := strings.Split(apicfg.MetadataHeaders, ",")
+	for i, header := range headers {
+		headers[i] = strings.TrimSpace(header)
+	}
+	return headers
+}
+
+// GatewayRemotesList returns the URLs of the remote nodes of the gateway as a list.
+func (apicfg *APIConfig) GatewayRemotesList() []string {
+	if apicfg.GatewayRemotes == "" {
+		return nil
 	}
+	remotes := strings.Split(apicfg.GatewayRemotes, ",")
+	for i, remote := range remotes {
+		remotes[i] = strings.TrimSpace(remote)
+	}
+	return remotes
 }
 
 func (apicfg *APIConfig) validate() error {
+	if apicfg.RateLimit < 0 {
+		return NewErrInvalidRateLimit(apicfg.RateLimit)
+	}
+
+	switch apicfg.ErrorVerbosity {
+	case ErrorVerbosityMessage, ErrorVerbosityCode, ErrorVerbosityStack:
+	default:
+		return NewErrInvalidErrorVerbosity(apicfg.ErrorVerbosity)
+	}
+
+	for _, remote := range apicfg.GatewayRemotesList() {
+		u, err := url.Parse(remote)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return NewErrInvalidGatewayRemote(remote)
+		}
+	}
+
 	if apicfg.Address == "" {
 		return ErrInvalidDatabaseURL
 	}
@@ -352,8 +669,19 @@ type NetConfig struct {
 	P2PAddress           string
 	P2PDisabled          bool
 	Peers                string
-	PubSubEnabled        bool `mapstructure:"pubsub"`
-	RelayEnabled         bool `mapstructure:"relay"`
+	PubSubEnabled        bool   `mapstructure:"pubsub"`
+	RelayEnabled         bool   `mapstructure:"relay"`
+	SchemaSyncEnabled    bool   `mapstructure:"schemasync"`
+	SchemaAdmin          string `mapstructure:"schemaadmin"`
+	Upstream             string
+	ServeRequests        bool    `mapstructure:"serverequests"`
+	RejoinPeers          bool    `mapstructure:"rejoinpeers"`
+	MDNSEnabled          bool    `mapstructure:"mdns"`
+	RendezvousEnabled    bool    `mapstructure:"rendezvous"`
+	IngestPeerRate       float64 `mapstructure:"ingestpeerrate"`
+	IngestCollectionRate float64 `mapstructure:"ingestcollectionrate"`
+	IngestBurst          int     `mapstructure:"ingestburst"`
+	IngestQueue          int     `mapstructure:"ingestqueue"`
 	RPCAddress           string
 	RPCMaxConnectionIdle string
 	RPCTimeout           string
@@ -367,6 +695,17 @@ func defaultNetConfig() *NetConfig {
 		Peers:                "",
 		PubSubEnabled:        true,
 		RelayEnabled:         false,
+		SchemaSyncEnabled:    false,
+		SchemaAdmin:          "",
+		Upstream:             "",
+		ServeRequests:        false,
+		RejoinPeers:          true,
+		MDNSEnabled:          false,
+		RendezvousEnabled:    false,
+		IngestPeerRate:       0,
+		IngestCollectionRate: 0,
+		IngestBurst:          10,
+		IngestQueue:          100,
 		RPCAddress:           "0.0.0.0:9161",
 		RPCMaxConnectionIdle: "5m",
 		RPCTimeout:           "10s",
@@ -401,9 +740,41 @@ func (netcfg *NetConfig) validate() error {
 			}
 		}
 	}
+	_, err = netcfg.SchemaAdminKey()
+	if err != nil {
+		return err
+	}
+	if netcfg.Upstream != "" {
+		_, err = peer.AddrInfoFromString(netcfg.Upstream)
+		if err != nil {
+			return NewErrInvalidUpstream(err, netcfg.Upstream)
+		}
+	}
+	if netcfg.IngestPeerRate < 0 || netcfg.IngestCollectionRate < 0 || netcfg.IngestBurst < 0 ||
+		netcfg.IngestQueue < 0 {
+		return ErrInvalidIngestLimits
+	}
 	return nil
 }
 
+// SchemaAdminKey gives the public key of the schema admin identity, or nil if none is configured.
+//
+// The schema admin identity is given as a peer ID, from which the public key is extracted.
+func (netcfg *NetConfig) SchemaAdminKey() (crypto.PubKey, error) {
+	if netcfg.SchemaAdmin == "" {
+		return nil, nil
+	}
+	id, err := peer.Decode(netcfg.SchemaAdmin)
+	if err != nil {
+		return nil, NewErrInvalidSchemaAdmin(err, netcfg.SchemaAdmin)
+	}
+	key, err := id.ExtractPublicKey()
+	if err != nil {
+		return nil, NewErrInvalidSchemaAdmin(err, netcfg.SchemaAdmin)
+	}
+	return key, nil
+}
+
 // RPCTimeoutDuration gives the RPC timeout as a time.Duration.
 func (netcfg *NetConfig) RPCTimeoutDuration() (time.Duration, error) {
 	d, err := time.ParseDuration(netcfg.RPCTimeout)
@@ -436,6 +807,11 @@ func (cfg *Config) NodeConfig() node.NodeOpt {
 		}
 		opt.EnableRelay = cfg.Net.RelayEnabled
 		opt.EnablePubSub = cfg.Net.PubSubEnabled
+		opt.EnableSchemaSync = cfg.Net.SchemaSyncEnabled
+		opt.ServeRequests = cfg.Net.ServeRequests
+		opt.RejoinPeers = cfg.Net.RejoinPeers
+		opt.EnableMDNS = cfg.Net.MDNSEnabled
+		opt.EnableRendezvous = cfg.Net.RendezvousEnabled
 		opt.DataPath = cfg.Datastore.Badger.Path
 		opt.ConnManager, err = node.NewConnManager(100, 400, time.Second*20)
 		if err != nil {
@@ -445,6 +821,376 @@ func (cfg *Config) NodeConfig() node.NodeOpt {
 	}
 }
 
+// SinkConfig configures the publishing of the update events to a message broker.
+type SinkConfig struct {
+	// Type is the type of the broker, `kafka` or `nats`. The events are not published if empty.
+	Type string
+	// Address is the comma separated list of the Kafka brokers, or the URL of the NATS server.
+	Address string
+	// Topic is the Kafka topic, or the NATS subject, that the events are published to.
+	Topic string
+	// Format is the serialization format of the events: `json`, `cbor` or `avro`.
+	Format string
+}
+
+func defaultSinkConfig() *SinkConfig {
+	return &SinkConfig{
+		Type:    "",
+		Address: "",
+		Topic:   "defradb.updates",
+		Format:  "json",
+	}
+}
+
+func (sinkcfg *SinkConfig) validate() error {
+	switch sinkcfg.Type {
+	case "":
+		return nil
+	case "kafka", "nats":
+	default:
+		return NewErrInvalidSinkType(sinkcfg.Type)
+	}
+	switch sinkcfg.Format {
+	case "json", "cbor", "avro":
+	default:
+		return NewErrInvalidSinkFormat(sinkcfg.Format)
+	}
+	if sinkcfg.Address == "" {
+		return ErrMissingSinkAddress
+	}
+	if sinkcfg.Topic == "" {
+		return ErrMissingSinkTopic
+	}
+	return nil
+}
+
+// PinningConfig configures the pinning of the blocks of the documents to a remote IPFS node or
+// pinning service.
+type PinningConfig struct {
+	// Type is the type of the remote, `service` for an IPFS pinning service or `kubo` for a Kubo
+	// IPFS node. Blocks are not pinned if empty.
+	Type string
+	// URL is the endpoint of the pinning service, or the RPC API URL of the IPFS node.
+	URL string
+	// AccessToken is the bearer token authenticating the pin requests, if required.
+	AccessToken string `json:"-"`
+	// Collections is the comma separated list of the collections whose documents are pinned.
+	// The documents of all the collections are pinned if empty.
+	Collections string
+}
+
+func defaultPinningConfig() *PinningConfig {
+	return &PinningConfig{
+		Type:        "",
+		URL:         "",
+		AccessToken: "",
+		Collections: "",
+	}
+}
+
+func (pincfg *PinningConfig) validate() error {
+	switch pincfg.Type {
+	case "":
+		return nil
+	case "service", "kubo":
+	default:
+		return NewErrInvalidPinningType(pincfg.Type)
+	}
+	u, err := url.Parse(pincfg.URL)
+	if err != nil || !u.IsAbs() {
+		return NewErrInvalidPinningURL(pincfg.URL)
+	}
+	return nil
+}
+
+// CollectionNames returns the names of the collections whose documents are pinned.
+func (pincfg *PinningConfig) CollectionNames() []string {
+	names := []string{}
+	for _, name := range strings.Split(pincfg.Collections, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+const (
+	// ReplicationRolePrimary is the role of a node serving the changes of its datastore to a standby.
+	ReplicationRolePrimary = "primary"
+	// ReplicationRoleStandby is the role of a node following the changes of the datastore of a primary.
+	ReplicationRoleStandby = "standby"
+)
+
+// ReplicationConfig configures the physical replication of the datastore to a warm standby node.
+type ReplicationConfig struct {
+	// Role is the role of the node, `primary` to serve the changes of its datastore to a standby,
+	// or `standby` to follow the changes of the datastore of a primary. The datastore is not
+	// replicated if empty.
+	Role string
+	// Address is the address the primary serves the changes of its datastore on, or the address
+	// of the primary the standby follows.
+	Address string
+}
+
+func defaultReplicationConfig() *ReplicationConfig {
+	return &ReplicationConfig{
+		Role:    "",
+		Address: "",
+	}
+}
+
+func (replcfg *ReplicationConfig) validate() error {
+	switch replcfg.Role {
+	case "":
+		return nil
+	case ReplicationRolePrimary, ReplicationRoleStandby:
+	default:
+		return NewErrInvalidReplicationRole(replcfg.Role)
+	}
+	if _, _, err := net.SplitHostPort(replcfg.Address); err != nil {
+		return NewErrInvalidReplicationAddress(err, replcfg.Address)
+	}
+	return nil
+}
+
+// PGWireConfig configures the read-only SQL access to the collections over the Postgres wire
+// protocol.
+type PGWireConfig struct {
+	// Address is the address the Postgres wire protocol is served on. It is not served if empty.
+	Address string
+}
+
+func defaultPGWireConfig() *PGWireConfig {
+	return &PGWireConfig{
+		Address: "",
+	}
+}
+
+func (pgcfg *PGWireConfig) validate() error {
+	if pgcfg.Address == "" {
+		return nil
+	}
+	if _, _, err := net.SplitHostPort(pgcfg.Address); err != nil {
+		return NewErrInvalidPGWireAddress(err, pgcfg.Address)
+	}
+	return nil
+}
+
+// RetentionConfig configures the retention windows of the collections, such as those of logs or
+// telemetry, whose documents are deleted once they are older than the window of their collection.
+type RetentionConfig struct {
+	// Collections is the comma separated list of the retention windows of the collections, as
+	// `name:days` pairs. Documents are not deleted if empty.
+	Collections string
+	// ByUpdate ages the documents from their last update rather than from their creation.
+	ByUpdate bool
+	// PurgeHistory removes the history of the deleted documents, freeing their space.
+	PurgeHistory bool
+	// Interval at which the expired documents are deleted.
+	Interval string
+}
+
+func defaultRetentionConfig() *RetentionConfig {
+	return &RetentionConfig{
+		Collections:  "",
+		ByUpdate:     false,
+		PurgeHistory: false,
+		Interval:     "1h",
+	}
+}
+
+func (retcfg *RetentionConfig) validate() error {
+	if _, err := retcfg.Windows(); err != nil {
+		return err
+	}
+	d, err := retcfg.IntervalDuration()
+	if err != nil {
+		return err
+	}
+	if d <= 0 {
+		return NewErrInvalidRetentionInterval(nil, retcfg.Interval)
+	}
+	return nil
+}
+
+// Windows returns the retention windows of the collections, by collection name.
+func (retcfg *RetentionConfig) Windows() (map[string]time.Duration, error) {
+	windows := map[string]time.Duration{}
+	for _, window := range strings.Split(retcfg.Collections, ",") {
+		if window = strings.TrimSpace(window); window == "" {
+			continue
+		}
+		name, days, ok := strings.Cut(window, ":")
+		if !ok {
+			return nil, NewErrInvalidRetentionWindow(window)
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(days))
+		if err != nil || n <= 0 || strings.TrimSpace(name) == "" {
+			return nil, NewErrInvalidRetentionWindow(window)
+		}
+		windows[strings.TrimSpace(name)] = time.Duration(n) * 24 * time.Hour
+	}
+	return windows, nil
+}
+
+// IntervalDuration gives the interval at which the expired documents are deleted as a
+// time.Duration.
+func (retcfg *RetentionConfig) IntervalDuration() (time.Duration, error) {
+	d, err := time.ParseDuration(retcfg.Interval)
+	if err != nil {
+		return d, NewErrInvalidRetentionInterval(err, retcfg.Interval)
+	}
+	return d, nil
+}
+
+// The methods the fields can be masked with.
+const (
+	MaskMethodHash     = "hash"
+	MaskMethodTruncate = "truncate"
+	MaskMethodRedact   = "redact"
+)
+
+// defaultMaskTruncateLength is the number of leading characters kept by the truncate method if
+// none is given.
+const defaultMaskTruncateLength = 4
+
+// MaskingConfig configures the masking of the fields holding personal data in the exports and
+// the subscription payloads, so that operational data can be shared without leaking it.
+type MaskingConfig struct {
+	// Fields is the comma separated list of the masked fields, as `Collection.field:method`
+	// pairs, the method being `hash`, `redact` or `truncate`, optionally followed by the number
+	// of characters kept (e.g. `truncate:2`). No field is masked if empty.
+	Fields string
+	// HashKey is the secret of the node keying the hashes of the hashed fields, so that they can't
+	// be matched against the hashes of guessed values. Required if a field is hashed.
+	HashKey string `json:"-"`
+}
+
+// FieldMask is the masking of a field.
+type FieldMask struct {
+	Collection string
+	Field      string
+	// Method is either MaskMethodHash, MaskMethodTruncate or MaskMethodRedact.
+	Method string
+	// Length is the number of leading characters kept by MaskMethodTruncate.
+	Length int
+}
+
+func defaultMaskingConfig() *MaskingConfig {
+	return &MaskingConfig{
+		Fields:  "",
+		HashKey: "",
+	}
+}
+
+func (maskcfg *MaskingConfig) validate() error {
+	masks, err := maskcfg.Masks()
+	if err != nil {
+		return err
+	}
+	for _, mask := range masks {
+		if mask.Method == MaskMethodHash && maskcfg.HashKey == "" {
+			return ErrMissingMaskHashKey
+		}
+	}
+	return nil
+}
+
+// Masks returns the maskings of the masked fields.
+func (maskcfg *MaskingConfig) Masks() ([]FieldMask, error) {
+	var masks []FieldMask
+	for _, entry := range strings.Split(maskcfg.Fields, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		parts := strings.Split(entry, ":")
+		collection, field, ok := strings.Cut(strings.TrimSpace(parts[0]), ".")
+		if len(parts) < 2 || !ok || collection == "" || field == "" {
+			return nil, NewErrInvalidFieldMask(entry)
+		}
+		mask := FieldMask{
+			Collection: collection,
+			Field:      field,
+			Method:     strings.TrimSpace(parts[1]),
+		}
+		switch {
+		case mask.Method == MaskMethodTruncate && len(parts) == 2:
+			mask.Length = defaultMaskTruncateLength
+		case mask.Method == MaskMethodTruncate && len(parts) == 3:
+			n, err := strconv.Atoi(strings.TrimSpace(parts[2]))
+			if err != nil || n < 0 {
+				return nil, NewErrInvalidFieldMask(entry)
+			}
+			mask.Length = n
+		case (mask.Method == MaskMethodHash || mask.Method == MaskMethodRedact) && len(parts) == 2:
+		default:
+			return nil, NewErrInvalidFieldMask(entry)
+		}
+		masks = append(masks, mask)
+	}
+	return masks, nil
+}
+
+// QuotaConfig configures the storage quotas of the collections, such as those of the tenants of a
+// multi-tenant node, past which their writes are rejected.
+type QuotaConfig struct {
+	// Collections is the comma separated list of the quotas of the collections, as
+	// `name:documents:bytes` triples, a zero limit being unlimited. No quota is set if empty.
+	Collections string
+	// Prune deletes the oldest documents of the collections to make room for the writes exceeding
+	// their quota, rather than rejecting them.
+	Prune bool
+}
+
+// CollectionQuota is the storage quota of a collection.
+type CollectionQuota struct {
+	Collection string
+	// MaxDocuments is the maximum number of documents of the collection, or zero if unlimited.
+	MaxDocuments uint64
+	// MaxBytes is the maximum size in bytes of the collection, or zero if unlimited.
+	MaxBytes uint64
+}
+
+func defaultQuotaConfig() *QuotaConfig {
+	return &QuotaConfig{
+		Collections: "",
+		Prune:       false,
+	}
+}
+
+func (quotacfg *QuotaConfig) validate() error {
+	_, err := quotacfg.Quotas()
+	return err
+}
+
+// Quotas returns the storage quotas of the collections.
+func (quotacfg *QuotaConfig) Quotas() ([]CollectionQuota, error) {
+	var quotas []CollectionQuota
+	for _, entry := range strings.Split(quotacfg.Collections, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		parts := strings.Split(entry, ":")
+		if len(parts) != 3 || strings.TrimSpace(parts[0]) == "" {
+			return nil, NewErrInvalidCollectionQuota(entry)
+		}
+		documents, err := strconv.ParseUint(strings.TrimSpace(parts[1]), 10, 64)
+		if err != nil {
+			return nil, NewErrInvalidCollectionQuota(entry)
+		}
+		bytes, err := strconv.ParseUint(strings.TrimSpace(parts[2]), 10, 64)
+		if err != nil || (documents == 0 && bytes == 0) {
+			return nil, NewErrInvalidCollectionQuota(entry)
+		}
+		quotas = append(quotas, CollectionQuota{
+			Collection:   strings.TrimSpace(parts[0]),
+			MaxDocuments: documents,
+			MaxBytes:     bytes,
+		})
+	}
+	return quotas, nil
+}
+
 // LogConfig configures output and logger.
 type LoggingConfig struct {
 	Level          string
@@ -14,11 +14,15 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
 	ma "github.com/multiformats/go-multiaddr"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/sourcenetwork/defradb/node"
 )
@@ -232,6 +236,7 @@ func TestNodeConfig(t *testing.T) {
 	cfg.Net.RPCMaxConnectionIdle = "111s"
 	cfg.Net.RelayEnabled = true
 	cfg.Net.PubSubEnabled = true
+	cfg.Net.SchemaSyncEnabled = true
 	cfg.Datastore.Badger.Path = "/tmp/defra_cli/badger"
 
 	err := cfg.validate()
@@ -251,6 +256,8 @@ func TestNodeConfig(t *testing.T) {
 		EnablePubSub: true,
 		EnableRelay:  true,
 		ConnManager:  connManager,
+
+		EnableSchemaSync: true,
 	}
 	assert.NoError(t, errOptionsMerge)
 	assert.NoError(t, errP2P)
@@ -263,6 +270,7 @@ func TestNodeConfig(t *testing.T) {
 	assert.Equal(t, expectedOptions.DataPath, options.DataPath)
 	assert.Equal(t, expectedOptions.EnablePubSub, options.EnablePubSub)
 	assert.Equal(t, expectedOptions.EnableRelay, options.EnableRelay)
+	assert.Equal(t, expectedOptions.EnableSchemaSync, options.EnableSchemaSync)
 }
 
 func TestCreateAndLoadCustomConfig(t *testing.T) {
@@ -434,6 +442,34 @@ func TestValidationInvalidNetConfigPeers(t *testing.T) {
 	assert.ErrorIs(t, err, ErrFailedToValidateConfig)
 }
 
+func TestValidationNetConfigSchemaAdmin(t *testing.T) {
+	_, pub, err := crypto.GenerateKeyPair(crypto.Ed25519, 0)
+	require.NoError(t, err)
+	id, err := peer.IDFromPublicKey(pub)
+	require.NoError(t, err)
+
+	cfg := DefaultConfig()
+	cfg.Net.SchemaAdmin = id.String()
+	err = cfg.validate()
+	assert.NoError(t, err)
+
+	key, err := cfg.Net.SchemaAdminKey()
+	require.NoError(t, err)
+	assert.True(t, key.Equals(pub))
+}
+
+func TestValidationInvalidNetConfigSchemaAdmin(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Net.SchemaAdmin = "not a peer ID"
+	err := cfg.validate()
+	assert.ErrorIs(t, err, ErrFailedToValidateConfig)
+
+	// The public key of the schema admin must be embedded in its peer ID.
+	cfg.Net.SchemaAdmin = "QmYyQSo1c1Ym7orWxLYvCrM2EmxFTANf8wXmmE7DWjhx5N"
+	_, err = cfg.Net.SchemaAdminKey()
+	assert.ErrorIs(t, err, ErrInvalidSchemaAdmin)
+}
+
 func TestValidationInvalidRPCMaxConnectionIdle(t *testing.T) {
 	cfg := DefaultConfig()
 	cfg.Net.RPCMaxConnectionIdle = "123123"
@@ -529,3 +565,408 @@ func TestValidationAddressDomainWithSubdomainWrongPortIsInvalid(t *testing.T) {
 	err := cfg.validate()
 	assert.ErrorIs(t, err, ErrNoPortWithDomain)
 }
+
+func TestValidationTxnRetryBackoffDuration(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Datastore.TxnRetryBackoff = "50ms"
+	err := cfg.validate()
+	assert.NoError(t, err)
+	duration, err := cfg.Datastore.TxnRetryBackoffDuration()
+	assert.NoError(t, err)
+	assert.Equal(t, 50*time.Millisecond, duration)
+}
+
+func TestValidationInvalidTxnRetryBackoffDuration(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Datastore.TxnRetryBackoff = "123123"
+	err := cfg.validate()
+	assert.ErrorIs(t, err, ErrInvalidTxnRetryBackoff)
+}
+
+func TestValidationInvalidDocumentCacheSize(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Datastore.DocumentCacheSize = -1
+	err := cfg.validate()
+	assert.ErrorIs(t, err, ErrInvalidDocumentCacheSize)
+}
+
+func TestValidationInvalidMaxReadTxns(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Datastore.MaxReadTxns = -1
+	err := cfg.validate()
+	assert.ErrorIs(t, err, ErrInvalidMaxReadTxns)
+}
+
+func TestValidationInvalidMaxRequestCost(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Datastore.MaxRequestCost = -1
+	err := cfg.validate()
+	assert.ErrorIs(t, err, ErrInvalidMaxRequestCost)
+}
+
+func TestValidationSink(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Sink.Type = "kafka"
+	cfg.Sink.Address = "localhost:9092"
+	cfg.Sink.Format = "avro"
+	err := cfg.validate()
+	assert.NoError(t, err)
+}
+
+func TestValidationInvalidSinkType(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Sink.Type = "rabbitmq"
+	cfg.Sink.Address = "localhost:5672"
+	err := cfg.validate()
+	assert.ErrorIs(t, err, ErrInvalidSinkType)
+}
+
+func TestValidationInvalidSinkFormat(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Sink.Type = "nats"
+	cfg.Sink.Address = "nats://localhost:4222"
+	cfg.Sink.Format = "xml"
+	err := cfg.validate()
+	assert.ErrorIs(t, err, ErrInvalidSinkFormat)
+}
+
+func TestValidationMissingSinkAddress(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Sink.Type = "nats"
+	err := cfg.validate()
+	assert.ErrorIs(t, err, ErrMissingSinkAddress)
+}
+
+func TestValidationPinning(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Pinning.Type = "service"
+	cfg.Pinning.URL = "https://api.pinata.cloud/psa"
+	cfg.Pinning.Collections = "User, Book,"
+	err := cfg.validate()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"User", "Book"}, cfg.Pinning.CollectionNames())
+}
+
+func TestValidationInvalidPinningType(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Pinning.Type = "pinata"
+	cfg.Pinning.URL = "https://api.pinata.cloud/psa"
+	err := cfg.validate()
+	assert.ErrorIs(t, err, ErrInvalidPinningType)
+}
+
+func TestValidationInvalidPinningURL(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Pinning.Type = "kubo"
+	cfg.Pinning.URL = "127.0.0.1:5001"
+	err := cfg.validate()
+	assert.ErrorIs(t, err, ErrInvalidPinningURL)
+}
+
+func TestValidationS3(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Datastore.S3.Endpoint = "s3.amazonaws.com"
+	cfg.Datastore.S3.Bucket = "defradb"
+	err := cfg.validate()
+	assert.NoError(t, err)
+	interval, err := cfg.Datastore.S3.OffloadIntervalDuration()
+	assert.NoError(t, err)
+	assert.Equal(t, time.Hour, interval)
+}
+
+func TestValidationMissingS3Bucket(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Datastore.S3.Endpoint = "s3.amazonaws.com"
+	err := cfg.validate()
+	assert.ErrorIs(t, err, ErrMissingS3Bucket)
+}
+
+func TestValidationInvalidColdCacheSize(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Datastore.S3.Endpoint = "s3.amazonaws.com"
+	cfg.Datastore.S3.Bucket = "defradb"
+	cfg.Datastore.S3.CacheSize = 0
+	err := cfg.validate()
+	assert.ErrorIs(t, err, ErrInvalidColdCacheSize)
+}
+
+func TestValidationInvalidOffloadInterval(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Datastore.S3.Endpoint = "s3.amazonaws.com"
+	cfg.Datastore.S3.Bucket = "defradb"
+	cfg.Datastore.S3.OffloadInterval = "hourly"
+	err := cfg.validate()
+	assert.ErrorIs(t, err, ErrInvalidOffloadInterval)
+}
+
+func TestValidationReplication(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Replication.Role = ReplicationRoleStandby
+	cfg.Replication.Address = "10.0.0.1:9182"
+	err := cfg.validate()
+	assert.NoError(t, err)
+}
+
+func TestValidationInvalidReplicationRole(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Replication.Role = "replica"
+	cfg.Replication.Address = "10.0.0.1:9182"
+	err := cfg.validate()
+	assert.ErrorIs(t, err, ErrInvalidReplicationRole)
+}
+
+func TestValidationInvalidReplicationAddress(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Replication.Role = ReplicationRolePrimary
+	cfg.Replication.Address = "0.0.0.0"
+	err := cfg.validate()
+	assert.ErrorIs(t, err, ErrInvalidReplicationAddress)
+}
+
+func TestDatastoreKeyring(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Datastore.EncryptionKeys = filepath.Join(t.TempDir(), "keys.json")
+	err := os.WriteFile(cfg.Datastore.EncryptionKeys, []byte(`{"users": "`+strings.Repeat("A", 43)+`="}`), 0600)
+	require.NoError(t, err)
+	keyring, err := cfg.Datastore.Keyring()
+	require.NoError(t, err)
+	assert.NotNil(t, keyring.Cipher("users"))
+	assert.Nil(t, keyring.Cipher("books"))
+}
+
+func TestDatastoreKeyringInvalidKey(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Datastore.EncryptionKeys = filepath.Join(t.TempDir(), "keys.json")
+	err := os.WriteFile(cfg.Datastore.EncryptionKeys, []byte(`{"users": "AAAA"}`), 0600)
+	require.NoError(t, err)
+	_, err = cfg.Datastore.Keyring()
+	assert.ErrorIs(t, err, ErrInvalidEncryptionKeys)
+}
+
+func TestDatastoreIdentityFieldKeys(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Datastore.FieldKeys = filepath.Join(t.TempDir(), "field_keys.json")
+	err := os.WriteFile(
+		cfg.Datastore.FieldKeys,
+		[]byte(`{"admin": {"users.ssn": "`+strings.Repeat("A", 43)+`="}}`),
+		0600,
+	)
+	require.NoError(t, err)
+	keys, err := cfg.Datastore.IdentityFieldKeys()
+	require.NoError(t, err)
+	assert.Len(t, keys["admin"]["users.ssn"], 32)
+}
+
+func TestDatastoreIdentityFieldKeysInvalidKey(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Datastore.FieldKeys = filepath.Join(t.TempDir(), "field_keys.json")
+	err := os.WriteFile(cfg.Datastore.FieldKeys, []byte(`{"admin": {"users.ssn": "AAAA"}}`), 0600)
+	require.NoError(t, err)
+	_, err = cfg.Datastore.IdentityFieldKeys()
+	assert.ErrorIs(t, err, ErrInvalidFieldKeys)
+}
+
+func TestValidationInvalidGatewayRemote(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.API.GatewayRemotes = "http://node1:9181, node2:9181"
+	err := cfg.validate()
+	assert.ErrorIs(t, err, ErrInvalidGatewayRemote)
+}
+
+func TestValidationInvalidErrorVerbosity(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.API.ErrorVerbosity = "verbose"
+	err := cfg.validate()
+	assert.ErrorIs(t, err, ErrInvalidErrorVerbosity)
+}
+
+func TestGatewayRemotesList(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.API.GatewayRemotes = "http://node1:9181, https://node2:9181"
+	assert.NoError(t, cfg.validate())
+	assert.Equal(t, []string{"http://node1:9181", "https://node2:9181"}, cfg.API.GatewayRemotesList())
+}
+
+func TestValidationInvalidUpstream(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Net.Upstream = "/ip4/127.0.0.1/tcp/9171"
+	err := cfg.validate()
+	assert.ErrorIs(t, err, ErrInvalidUpstream)
+}
+
+func TestLoadTOMLConfigFileWithEnvOverride(t *testing.T) {
+	tmpdir := t.TempDir()
+	err := os.WriteFile(
+		filepath.Join(tmpdir, TOMLConfigFileName),
+		[]byte("version = 1\n[api]\nratelimit = 7\n"),
+		0644,
+	)
+	assert.NoError(t, err)
+	t.Setenv("DEFRA_DATASTORE_STORE", "memory")
+
+	cfg := DefaultConfig()
+	err = cfg.setRootdir(tmpdir)
+	assert.NoError(t, err)
+	err = cfg.LoadWithRootdir(true)
+	assert.NoError(t, err)
+
+	assert.Equal(t, filepath.Join(tmpdir, TOMLConfigFileName), cfg.ConfigFilePath())
+	assert.Equal(t, 7, cfg.API.RateLimit)
+	assert.Equal(t, "memory", cfg.Datastore.Store)
+	// The parameters missing from the file keep their default values.
+	assert.Equal(t, defaultAPIConfig().Address, cfg.API.Address)
+	assert.Equal(t, filepath.Join(tmpdir, "data"), cfg.Datastore.Badger.Path)
+}
+
+func TestLoadConfigFileWithUnsupportedVersion(t *testing.T) {
+	tmpdir := t.TempDir()
+	err := os.WriteFile(filepath.Join(tmpdir, DefaultConfigFileName), []byte("version: 2\n"), 0644)
+	assert.NoError(t, err)
+
+	cfg := DefaultConfig()
+	err = cfg.setRootdir(tmpdir)
+	assert.NoError(t, err)
+	err = cfg.LoadWithRootdir(true)
+	assert.ErrorIs(t, err, ErrUnsupportedConfigVersion)
+}
+
+func TestCheckUnknownKeys(t *testing.T) {
+	tmpdir := t.TempDir()
+	err := os.WriteFile(
+		filepath.Join(tmpdir, DefaultConfigFileName),
+		[]byte("api:\n    ratelimit: 7\n    ratelimt: 5\n"),
+		0644,
+	)
+	assert.NoError(t, err)
+
+	cfg := DefaultConfig()
+	err = cfg.setRootdir(tmpdir)
+	assert.NoError(t, err)
+	err = cfg.LoadWithRootdir(true)
+	assert.NoError(t, err)
+
+	err = cfg.CheckUnknownKeys()
+	assert.ErrorIs(t, err, ErrUnknownConfigKeys)
+	assert.ErrorContains(t, err, "ratelimt")
+}
+
+func TestCheckUnknownKeysOfDefaultConfigFile(t *testing.T) {
+	cfg := DefaultConfig()
+	err := cfg.setRootdir(t.TempDir())
+	assert.NoError(t, err)
+	err = cfg.WriteConfigFile()
+	assert.NoError(t, err)
+	err = cfg.LoadWithRootdir(true)
+	assert.NoError(t, err)
+
+	assert.NoError(t, cfg.CheckUnknownKeys())
+}
+
+func TestValidationPGWire(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.PGWire.Address = "127.0.0.1:5432"
+	err := cfg.validate()
+	assert.NoError(t, err)
+}
+
+func TestValidationInvalidPGWireAddress(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.PGWire.Address = "localhost"
+	err := cfg.validate()
+	assert.ErrorIs(t, err, ErrInvalidPGWireAddress)
+}
+
+func TestValidationRetention(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Retention.Collections = "Log:7, Metric: 30,"
+	cfg.Retention.Interval = "30m"
+	err := cfg.validate()
+	assert.NoError(t, err)
+	windows, err := cfg.Retention.Windows()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]time.Duration{"Log": 7 * 24 * time.Hour, "Metric": 30 * 24 * time.Hour}, windows)
+	interval, err := cfg.Retention.IntervalDuration()
+	assert.NoError(t, err)
+	assert.Equal(t, 30*time.Minute, interval)
+}
+
+func TestValidationInvalidRetentionWindow(t *testing.T) {
+	for _, collections := range []string{"Log", "Log:0", "Log:seven", ":7"} {
+		cfg := DefaultConfig()
+		cfg.Retention.Collections = collections
+		err := cfg.validate()
+		assert.ErrorIs(t, err, ErrInvalidRetentionWindow, collections)
+	}
+}
+
+func TestValidationInvalidRetentionInterval(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Retention.Interval = "0s"
+	err := cfg.validate()
+	assert.ErrorIs(t, err, ErrInvalidRetentionInterval)
+}
+
+func TestValidationInvalidIngestLimits(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Net.IngestPeerRate = -1
+	err := cfg.validate()
+	assert.ErrorIs(t, err, ErrInvalidIngestLimits)
+}
+
+func TestValidationQuota(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Quota.Collections = "Tenant1:100:0, Tenant2: 0: 1024,"
+	err := cfg.validate()
+	assert.NoError(t, err)
+	quotas, err := cfg.Quota.Quotas()
+	assert.NoError(t, err)
+	assert.Equal(
+		t,
+		[]CollectionQuota{
+			{Collection: "Tenant1", MaxDocuments: 100},
+			{Collection: "Tenant2", MaxBytes: 1024},
+		},
+		quotas,
+	)
+}
+
+func TestValidationInvalidCollectionQuota(t *testing.T) {
+	for _, collections := range []string{"Tenant", "Tenant:100", "Tenant:0:0", "Tenant:-1:0", ":100:0"} {
+		cfg := DefaultConfig()
+		cfg.Quota.Collections = collections
+		err := cfg.validate()
+		assert.ErrorIs(t, err, ErrInvalidCollectionQuota, collections)
+	}
+}
+
+func TestValidationMasking(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Masking.Fields = "Users.email:hash, Users.phone:truncate, Users.zip:truncate:2, Users.ssn:redact,"
+	cfg.Masking.HashKey = "secret"
+	err := cfg.validate()
+	assert.NoError(t, err)
+	masks, err := cfg.Masking.Masks()
+	assert.NoError(t, err)
+	assert.Equal(t, []FieldMask{
+		{Collection: "Users", Field: "email", Method: MaskMethodHash},
+		{Collection: "Users", Field: "phone", Method: MaskMethodTruncate, Length: 4},
+		{Collection: "Users", Field: "zip", Method: MaskMethodTruncate, Length: 2},
+		{Collection: "Users", Field: "ssn", Method: MaskMethodRedact},
+	}, masks)
+}
+
+func TestValidationInvalidFieldMask(t *testing.T) {
+	for _, fields := range []string{"Users.email", "email:hash", "Users.email:encrypt", "Users.zip:truncate:x", "Users.ssn:redact:2"} {
+		cfg := DefaultConfig()
+		cfg.Masking.Fields = fields
+		err := cfg.validate()
+		assert.ErrorIs(t, err, ErrInvalidFieldMask, fields)
+	}
+}
+
+func TestValidationMissingMaskHashKey(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Masking.Fields = "Users.email:hash"
+	err := cfg.validate()
+	assert.ErrorIs(t, err, ErrMissingMaskHashKey)
+}
@@ -16,10 +16,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 const (
 	DefaultConfigFileName = "config.yaml"
+	TOMLConfigFileName    = "config.toml"
 	configType            = "yaml"
 	defaultDirPerm        = 0o700
 	defaultConfigFilePerm = 0o644
@@ -32,8 +34,23 @@ const (
 //go:embed configfile_yaml.gotmpl
 var defaultConfigTemplate string
 
+// ConfigFilePath returns the path of the configuration file of the root directory, which is the
+// TOML file if there is no YAML file.
 func (cfg *Config) ConfigFilePath() string {
-	return filepath.Join(cfg.Rootdir, DefaultConfigFileName)
+	path := filepath.Join(cfg.Rootdir, DefaultConfigFileName)
+	tomlPath := filepath.Join(cfg.Rootdir, TOMLConfigFileName)
+	if !fileExists(path) && fileExists(tomlPath) {
+		return tomlPath
+	}
+	return path
+}
+
+// useConfigFile sets the configuration file of the root directory as the one to read, in the
+// format given by its extension.
+func (cfg *Config) useConfigFile() {
+	path := cfg.ConfigFilePath()
+	cfg.v.SetConfigFile(path)
+	cfg.v.SetConfigType(strings.TrimPrefix(filepath.Ext(path), "."))
 }
 
 func (cfg *Config) WriteConfigFile() error {
@@ -69,7 +86,11 @@ func (cfg *Config) CreateRootDirAndConfigFile() error {
 }
 
 func (cfg *Config) ConfigFileExists() bool {
-	statInfo, err := os.Stat(cfg.ConfigFilePath())
+	return fileExists(cfg.ConfigFilePath())
+}
+
+func fileExists(path string) bool {
+	statInfo, err := os.Stat(path)
 	existsAsFile := (err == nil && !statInfo.IsDir())
 	return existsAsFile
 }
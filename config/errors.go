@@ -50,6 +50,38 @@ const (
 	errMissingPortNumber           string = "missing port number"
 	errNoPortWithDomain            string = "cannot provide port with domain name"
 	errInvalidRootDir              string = "invalid root directory"
+	errInvalidRateLimit            string = "invalid rate limit"
+	errInvalidErrorVerbosity       string = "invalid error verbosity"
+	errKeyNotReloadable            string = "config key cannot be changed at runtime"
+	errInvalidTxnRetryBackoff      string = "invalid transaction retry backoff"
+	errInvalidDocumentCacheSize    string = "invalid document cache size"
+	errInvalidMaxReadTxns          string = "invalid maximum number of read transactions"
+	errInvalidMaxRequestCost       string = "invalid maximum cost of the requests"
+	errInvalidSinkType             string = "invalid events sink type"
+	errInvalidSinkFormat           string = "invalid events sink format"
+	errMissingSinkAddress          string = "missing events sink address"
+	errMissingSinkTopic            string = "missing events sink topic"
+	errInvalidPinningType          string = "invalid pinning remote type"
+	errInvalidPinningURL           string = "invalid pinning remote URL"
+	errMissingS3Bucket             string = "missing S3 bucket"
+	errInvalidColdCacheSize        string = "invalid cold block cache size"
+	errInvalidOffloadInterval      string = "invalid cold block offload interval"
+	errInvalidReplicationRole      string = "invalid replication role"
+	errInvalidReplicationAddress   string = "invalid replication address"
+	errInvalidSchemaAdmin          string = "invalid schema admin identity"
+	errInvalidUpstream             string = "invalid upstream peer address"
+	errInvalidGatewayRemote        string = "invalid gateway remote URL"
+	errInvalidPGWireAddress        string = "invalid Postgres wire protocol address"
+	errInvalidRetentionWindow      string = "invalid collection retention window"
+	errInvalidRetentionInterval    string = "invalid retention interval"
+	errInvalidFieldMask            string = "invalid field mask"
+	errMissingMaskHashKey          string = "missing masking hash key"
+	errInvalidCollectionQuota      string = "invalid collection quota"
+	errInvalidIngestLimits         string = "the ingest rates, burst and queue can't be negative"
+	errInvalidEncryptionKeys       string = "invalid encryption keys file"
+	errInvalidFieldKeys            string = "invalid field keys file"
+	errUnsupportedConfigVersion    string = "unsupported config version"
+	errUnknownConfigKeys           string = "unknown config keys"
 )
 
 var (
@@ -88,6 +120,38 @@ var (
 	ErrMissingPortNumber           = errors.New(errMissingPortNumber)
 	ErrNoPortWithDomain            = errors.New(errNoPortWithDomain)
 	ErrorInvalidRootDir            = errors.New(errInvalidRootDir)
+	ErrInvalidRateLimit            = errors.New(errInvalidRateLimit)
+	ErrInvalidErrorVerbosity       = errors.New(errInvalidErrorVerbosity)
+	ErrKeyNotReloadable            = errors.New(errKeyNotReloadable)
+	ErrInvalidTxnRetryBackoff      = errors.New(errInvalidTxnRetryBackoff)
+	ErrInvalidDocumentCacheSize    = errors.New(errInvalidDocumentCacheSize)
+	ErrInvalidMaxReadTxns          = errors.New(errInvalidMaxReadTxns)
+	ErrInvalidMaxRequestCost       = errors.New(errInvalidMaxRequestCost)
+	ErrInvalidSinkType             = errors.New(errInvalidSinkType)
+	ErrInvalidSinkFormat           = errors.New(errInvalidSinkFormat)
+	ErrMissingSinkAddress          = errors.New(errMissingSinkAddress)
+	ErrMissingSinkTopic            = errors.New(errMissingSinkTopic)
+	ErrInvalidPinningType          = errors.New(errInvalidPinningType)
+	ErrInvalidPinningURL           = errors.New(errInvalidPinningURL)
+	ErrMissingS3Bucket             = errors.New(errMissingS3Bucket)
+	ErrInvalidColdCacheSize        = errors.New(errInvalidColdCacheSize)
+	ErrInvalidOffloadInterval      = errors.New(errInvalidOffloadInterval)
+	ErrInvalidReplicationRole      = errors.New(errInvalidReplicationRole)
+	ErrInvalidReplicationAddress   = errors.New(errInvalidReplicationAddress)
+	ErrInvalidSchemaAdmin          = errors.New(errInvalidSchemaAdmin)
+	ErrInvalidUpstream             = errors.New(errInvalidUpstream)
+	ErrInvalidGatewayRemote        = errors.New(errInvalidGatewayRemote)
+	ErrInvalidPGWireAddress        = errors.New(errInvalidPGWireAddress)
+	ErrInvalidRetentionWindow      = errors.New(errInvalidRetentionWindow)
+	ErrInvalidRetentionInterval    = errors.New(errInvalidRetentionInterval)
+	ErrInvalidFieldMask            = errors.New(errInvalidFieldMask)
+	ErrMissingMaskHashKey          = errors.New(errMissingMaskHashKey)
+	ErrInvalidCollectionQuota      = errors.New(errInvalidCollectionQuota)
+	ErrInvalidIngestLimits         = errors.New(errInvalidIngestLimits)
+	ErrInvalidEncryptionKeys       = errors.New(errInvalidEncryptionKeys)
+	ErrInvalidFieldKeys            = errors.New(errInvalidFieldKeys)
+	ErrUnsupportedConfigVersion    = errors.New(errUnsupportedConfigVersion)
+	ErrUnknownConfigKeys           = errors.New(errUnknownConfigKeys)
 )
 
 func NewErrFailedToWriteFile(inner error, path string) error {
@@ -126,6 +190,22 @@ func NewErrInvalidRPCTimeout(inner error, timeout string) error {
 	return errors.Wrap(errInvalidRPCTimeout, inner, errors.NewKV("timeout", timeout))
 }
 
+func NewErrInvalidTxnRetryBackoff(inner error, backoff string) error {
+	return errors.Wrap(errInvalidTxnRetryBackoff, inner, errors.NewKV("backoff", backoff))
+}
+
+func NewErrInvalidDocumentCacheSize(size int) error {
+	return errors.New(errInvalidDocumentCacheSize, errors.NewKV("size", size))
+}
+
+func NewErrInvalidMaxReadTxns(num int) error {
+	return errors.New(errInvalidMaxReadTxns, errors.NewKV("num", num))
+}
+
+func NewErrInvalidMaxRequestCost(cost int) error {
+	return errors.New(errInvalidMaxRequestCost, errors.NewKV("cost", cost))
+}
+
 func NewErrInvalidRPCMaxConnectionIdle(inner error, timeout string) error {
 	return errors.Wrap(errInvalidRPCMaxConnectionIdle, inner, errors.NewKV("timeout", timeout))
 }
@@ -217,3 +297,99 @@ func NewErrInvalidDatastorePath(path string) error {
 func NewErrInvalidRootDir(path string) error {
 	return errors.New(errInvalidRootDir, errors.NewKV("path", path))
 }
+
+func NewErrInvalidRateLimit(limit int) error {
+	return errors.New(errInvalidRateLimit, errors.NewKV("limit", limit))
+}
+
+func NewErrInvalidErrorVerbosity(verbosity string) error {
+	return errors.New(errInvalidErrorVerbosity, errors.NewKV("verbosity", verbosity))
+}
+
+func NewErrKeyNotReloadable(key string) error {
+	return errors.New(errKeyNotReloadable, errors.NewKV("key", key))
+}
+
+func NewErrInvalidSinkType(sinkType string) error {
+	return errors.New(errInvalidSinkType, errors.NewKV("type", sinkType))
+}
+
+func NewErrInvalidSinkFormat(format string) error {
+	return errors.New(errInvalidSinkFormat, errors.NewKV("format", format))
+}
+
+func NewErrInvalidPinningType(pinningType string) error {
+	return errors.New(errInvalidPinningType, errors.NewKV("type", pinningType))
+}
+
+func NewErrInvalidPinningURL(url string) error {
+	return errors.New(errInvalidPinningURL, errors.NewKV("url", url))
+}
+
+func NewErrInvalidColdCacheSize(size int) error {
+	return errors.New(errInvalidColdCacheSize, errors.NewKV("size", size))
+}
+
+func NewErrInvalidOffloadInterval(inner error, interval string) error {
+	return errors.Wrap(errInvalidOffloadInterval, inner, errors.NewKV("interval", interval))
+}
+
+func NewErrInvalidReplicationRole(role string) error {
+	return errors.New(errInvalidReplicationRole, errors.NewKV("role", role))
+}
+
+func NewErrInvalidReplicationAddress(inner error, address string) error {
+	return errors.Wrap(errInvalidReplicationAddress, inner, errors.NewKV("address", address))
+}
+
+func NewErrInvalidSchemaAdmin(inner error, admin string) error {
+	return errors.Wrap(errInvalidSchemaAdmin, inner, errors.NewKV("admin", admin))
+}
+
+func NewErrInvalidUpstream(inner error, upstream string) error {
+	return errors.Wrap(errInvalidUpstream, inner, errors.NewKV("upstream", upstream))
+}
+
+func NewErrInvalidGatewayRemote(url string) error {
+	return errors.New(errInvalidGatewayRemote, errors.NewKV("url", url))
+}
+
+func NewErrInvalidPGWireAddress(inner error, address string) error {
+	return errors.Wrap(errInvalidPGWireAddress, inner, errors.NewKV("address", address))
+}
+
+func NewErrInvalidRetentionWindow(window string) error {
+	return errors.New(errInvalidRetentionWindow, errors.NewKV("window", window))
+}
+
+func NewErrInvalidRetentionInterval(inner error, interval string) error {
+	return errors.Wrap(errInvalidRetentionInterval, inner, errors.NewKV("interval", interval))
+}
+
+func NewErrInvalidFieldMask(mask string) error {
+	return errors.New(errInvalidFieldMask, errors.NewKV("mask", mask))
+}
+
+func NewErrInvalidCollectionQuota(quota string) error {
+	return errors.New(errInvalidCollectionQuota, errors.NewKV("quota", quota))
+}
+
+func NewErrInvalidEncryptionKeys(inner error, path string) error {
+	return errors.Wrap(errInvalidEncryptionKeys, inner, errors.NewKV("path", path))
+}
+
+func NewErrInvalidFieldKeys(inner error, path string) error {
+	return errors.Wrap(errInvalidFieldKeys, inner, errors.NewKV("path", path))
+}
+
+func NewErrUnsupportedConfigVersion(version int) error {
+	return errors.New(
+		errUnsupportedConfigVersion,
+		errors.NewKV("version", version),
+		errors.NewKV("supported", ConfigVersion),
+	)
+}
+
+func NewErrUnknownConfigKeys(inner error) error {
+	return errors.Wrap(errUnknownConfigKeys, inner)
+}
@@ -0,0 +1,144 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package config
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/viper"
+)
+
+// reloadableKeys are the config keys that can be changed while the node is running.
+//
+// Keys ending with a `.` are prefixes matching any key of the section.
+var reloadableKeys = []string{
+	"log.",
+	"api.allowedorigins",
+	"api.ratelimit",
+	"api.errorverbosity",
+	"api.metadataheaders",
+	"net.peers",
+	"net.mdns",
+	"net.rendezvous",
+}
+
+// reloadMu serializes the reloading and updating of configurations.
+var reloadMu sync.Mutex
+
+// IsReloadable returns true if the given config key can be changed while the node is running.
+func IsReloadable(key string) bool {
+	key = strings.ToLower(key)
+	for _, k := range reloadableKeys {
+		if key == k || (strings.HasSuffix(k, ".") && strings.HasPrefix(key, k)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Reload re-reads the configuration from the config file and the environment, validates it,
+// and applies the logging configuration.
+//
+// Values that are only read at startup (e.g. addresses and datastore options) are refreshed on
+// the config but only take effect after a restart. The config is left untouched if the new
+// values are invalid.
+func (cfg *Config) Reload() error {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	if cfg.ConfigFileExists() {
+		cfg.useConfigFile()
+		if err := cfg.v.ReadInConfig(); err != nil {
+			return NewErrReadingConfigFile(err)
+		}
+	}
+	return cfg.reload()
+}
+
+// Update sets the given config keys (e.g. `log.level`) to the given values and applies them
+// as with [Config.Reload].
+//
+// Only keys that can be changed at runtime are accepted. Updated values take precedence over the
+// config file and the environment until the node is restarted. Nothing is changed if any of the
+// new values is invalid.
+func (cfg *Config) Update(values map[string]any) error {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	for key := range values {
+		if !IsReloadable(key) {
+			return NewErrKeyNotReloadable(key)
+		}
+	}
+
+	previous := make(map[string]any, len(values))
+	for key, value := range values {
+		previous[key] = cfg.v.Get(key)
+		cfg.v.Set(key, value)
+	}
+
+	if err := cfg.reload(); err != nil {
+		for key, value := range previous {
+			cfg.v.Set(key, value)
+		}
+		return err
+	}
+	return nil
+}
+
+// reload builds a new config from the current viper state and swaps it in if it is valid.
+func (cfg *Config) reload() error {
+	next := &Config{
+		Version:     ConfigVersion,
+		Datastore:   defaultDatastoreConfig(),
+		API:         defaultAPIConfig(),
+		Net:         defaultNetConfig(),
+		Log:         defaultLogConfig(),
+		Sink:        defaultSinkConfig(),
+		Pinning:     defaultPinningConfig(),
+		Replication: defaultReplicationConfig(),
+		PGWire:      defaultPGWireConfig(),
+		Retention:   defaultRetentionConfig(),
+		Masking:     defaultMaskingConfig(),
+		Quota:       defaultQuotaConfig(),
+		Rootdir:     cfg.Rootdir,
+		v:           cfg.v,
+	}
+
+	if err := next.paramsPreprocessing(); err != nil {
+		return err
+	}
+	if err := next.v.Unmarshal(next, viper.DecodeHook(mapstructure.TextUnmarshallerHookFunc())); err != nil {
+		return NewErrLoadingConfig(err)
+	}
+	if err := next.validate(); err != nil {
+		return err
+	}
+	if err := next.load(); err != nil {
+		return err
+	}
+
+	cfg.Version = next.Version
+	cfg.Datastore = next.Datastore
+	cfg.API = next.API
+	cfg.Net = next.Net
+	cfg.Log = next.Log
+	cfg.Sink = next.Sink
+	cfg.Pinning = next.Pinning
+	cfg.Replication = next.Replication
+	cfg.PGWire = next.PGWire
+	cfg.Retention = next.Retention
+	cfg.Masking = next.Masking
+	cfg.Quota = next.Quota
+	return nil
+}
@@ -0,0 +1,109 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package config
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsReloadable(t *testing.T) {
+	assert.True(t, IsReloadable("log.level"))
+	assert.True(t, IsReloadable("LOG.Format"))
+	assert.True(t, IsReloadable("api.ratelimit"))
+	assert.True(t, IsReloadable("net.peers"))
+	assert.True(t, IsReloadable("net.mdns"))
+	assert.True(t, IsReloadable("net.rendezvous"))
+	assert.False(t, IsReloadable("api.address"))
+	assert.False(t, IsReloadable("datastore.store"))
+	assert.False(t, IsReloadable("logger"))
+}
+
+func TestReloadFromConfigFile(t *testing.T) {
+	cfg := DefaultConfig()
+	err := cfg.setRootdir(t.TempDir())
+	assert.NoError(t, err)
+	err = cfg.WriteConfigFile()
+	assert.NoError(t, err)
+	err = cfg.LoadWithRootdir(true)
+	assert.NoError(t, err)
+
+	b, err := os.ReadFile(cfg.ConfigFilePath())
+	assert.NoError(t, err)
+	content := strings.Replace(string(b), "ratelimit: 0", "ratelimit: 42", 1)
+	err = os.WriteFile(cfg.ConfigFilePath(), []byte(content), defaultConfigFilePerm)
+	assert.NoError(t, err)
+
+	err = cfg.Reload()
+	assert.NoError(t, err)
+	assert.Equal(t, 42, cfg.API.RateLimit)
+}
+
+func TestReloadWithInvalidConfigFileKeepsConfig(t *testing.T) {
+	cfg := DefaultConfig()
+	err := cfg.setRootdir(t.TempDir())
+	assert.NoError(t, err)
+	err = cfg.WriteConfigFile()
+	assert.NoError(t, err)
+	err = cfg.LoadWithRootdir(true)
+	assert.NoError(t, err)
+
+	b, err := os.ReadFile(cfg.ConfigFilePath())
+	assert.NoError(t, err)
+	content := strings.Replace(string(b), "ratelimit: 0", "ratelimit: -1", 1)
+	err = os.WriteFile(cfg.ConfigFilePath(), []byte(content), defaultConfigFilePerm)
+	assert.NoError(t, err)
+
+	err = cfg.Reload()
+	assert.ErrorIs(t, err, ErrInvalidRateLimit)
+	assert.Equal(t, 0, cfg.API.RateLimit)
+}
+
+func TestUpdate(t *testing.T) {
+	cfg := DefaultConfig()
+	err := cfg.LoadWithRootdir(false)
+	assert.NoError(t, err)
+
+	err = cfg.Update(map[string]any{
+		"log.level":          "error",
+		"api.allowedorigins": "https://a.com, https://b.com",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "error", cfg.Log.Level)
+	assert.Equal(t, []string{"https://a.com", "https://b.com"}, cfg.API.AllowedOriginsList())
+}
+
+func TestUpdateWithNonReloadableKey(t *testing.T) {
+	cfg := DefaultConfig()
+	err := cfg.LoadWithRootdir(false)
+	assert.NoError(t, err)
+
+	err = cfg.Update(map[string]any{"api.address": "localhost:1234"})
+	assert.ErrorIs(t, err, ErrKeyNotReloadable)
+	assert.Equal(t, "localhost:9181", cfg.API.Address)
+}
+
+func TestUpdateWithInvalidValueRollsBack(t *testing.T) {
+	cfg := DefaultConfig()
+	err := cfg.LoadWithRootdir(false)
+	assert.NoError(t, err)
+
+	err = cfg.Update(map[string]any{"log.level": "not-a-level"})
+	assert.ErrorIs(t, err, ErrFailedToValidateConfig)
+	assert.Equal(t, "info", cfg.Log.Level)
+
+	err = cfg.Update(map[string]any{"api.ratelimit": 5})
+	assert.NoError(t, err)
+	assert.Equal(t, "info", cfg.Log.Level)
+}
@@ -63,6 +63,8 @@ func eq(condition, data any) (bool, error) {
 		return numbers.Equal(cn, data), nil
 	case int:
 		return numbers.Equal(cn, data), nil
+	case uint64:
+		return numbers.Equal(cn, data), nil
 	case float64:
 		return numbers.Equal(cn, data), nil
 	case map[FilterKey]any:
@@ -37,6 +37,8 @@ func le(condition, data any) (bool, error) {
 				return dn <= cn, nil
 			case int64:
 				return float64(dn) <= cn, nil
+			case uint64:
+				return float64(dn) <= cn, nil
 			}
 
 			return false, nil
@@ -46,6 +48,21 @@ func le(condition, data any) (bool, error) {
 				return dn <= float64(cn), nil
 			case int64:
 				return dn <= cn, nil
+			case uint64:
+				// The data is above the range of int64 values.
+				return false, nil
+			}
+
+			return false, nil
+		case uint64:
+			// The condition is above the range of int64 values.
+			switch dn := numbers.TryUpcast(data).(type) {
+			case float64:
+				return dn <= float64(cn), nil
+			case int64:
+				return true, nil
+			case uint64:
+				return dn <= cn, nil
 			}
 
 			return false, nil
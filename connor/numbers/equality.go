@@ -21,6 +21,14 @@ func Equal(condition, data any) bool {
 			return iucv == udv && float64(iucv) == ucv
 		}
 		return false
+	case uint64:
+		// The condition is above the range of int64 values, which no int64 data can equal.
+		if udv, ok := ud.(uint64); ok {
+			return ucv == udv
+		} else if udv, ok := ud.(float64); ok {
+			return float64(ucv) == udv
+		}
+		return false
 	default:
 		return false
 	}
@@ -1,5 +1,7 @@
 package numbers
 
+import "math"
+
 func TryUpcast(n any) any {
 	switch nn := n.(type) {
 	case int8:
@@ -13,9 +15,8 @@ func TryUpcast(n any) any {
 	case int64:
 		return nn
 	case uint64:
-		nnn := int64(nn)
-		if uint64(nnn) == nn { // if we can safely convert from uint64 -> int64 without losing data
-			return nnn
+		if nn <= math.MaxInt64 { // if we can safely convert from uint64 -> int64 without losing data
+			return int64(nn)
 		}
 		return n
 	case float32:
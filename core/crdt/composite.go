@@ -59,6 +59,12 @@ func (delta *CompositeDAGDelta) SetPriority(prio uint64) {
 	delta.Priority = prio
 }
 
+// IsCreation returns true if this delta is the commit creating its document, which is the first
+// commit of its DAG.
+func (delta *CompositeDAGDelta) IsCreation() bool {
+	return delta.Priority == 1 && !delta.Status.IsDeleted()
+}
+
 // Marshal will serialize this delta to a byte array.
 func (delta *CompositeDAGDelta) Marshal() ([]byte, error) {
 	h := &codec.CborHandle{}
@@ -219,17 +225,54 @@ func (c CompositeDAG) DeltaDecode(node ipld.Node) (core.Delta, error) {
 	if err != nil {
 		return nil, err
 	}
+	delta.SubDAGs = subDAGLinks(pbNode)
+	return delta, nil
+}
 
-	// get links
+// DeltaDecodeWithoutData is like DeltaDecode, but skips the data of the delta, which holds the
+// whole encoded document, instead of decoding it.
+//
+// It is used when only the fields required to merge the delta are read.
+func (c CompositeDAG) DeltaDecodeWithoutData(node ipld.Node) (core.Delta, error) {
+	pbNode, ok := node.(*dag.ProtoNode)
+	if !ok {
+		return nil, client.NewErrUnexpectedType[*dag.ProtoNode]("ipld.Node", node)
+	}
+	// The data is skipped by the decoder, as the struct has no field for it.
+	header := struct {
+		SchemaVersionID string
+		Priority        uint64
+		DocKey          []byte
+		Status          client.DocumentStatus
+	}{}
+	h := &codec.CborHandle{}
+	dec := codec.NewDecoderBytes(pbNode.Data(), h)
+	err := dec.Decode(&header)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CompositeDAGDelta{
+		SchemaVersionID: header.SchemaVersionID,
+		Priority:        header.Priority,
+		DocKey:          header.DocKey,
+		SubDAGs:         subDAGLinks(pbNode),
+		Status:          header.Status,
+	}, nil
+}
+
+// subDAGLinks returns the links of the given node to the sub-DAGs of its fields.
+func subDAGLinks(pbNode *dag.ProtoNode) []core.DAGLink {
+	var links []core.DAGLink
 	for _, link := range pbNode.Links() {
 		if link.Name == "head" { // ignore the head links
 			continue
 		}
 
-		delta.SubDAGs = append(delta.SubDAGs, core.DAGLink{
+		links = append(links, core.DAGLink{
 			Name: link.Name,
 			Cid:  link.Cid,
 		})
 	}
-	return delta, nil
+	return links
 }
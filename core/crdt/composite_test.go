@@ -0,0 +1,47 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package crdt
+
+import (
+	"testing"
+
+	"github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/client"
+)
+
+func TestCompositeDAGDeltaDecodeWithoutData(t *testing.T) {
+	delta := &CompositeDAGDelta{
+		SchemaVersionID: "schemaVersionID",
+		Priority:        uint64(10),
+		Data:            []byte("test"),
+		DocKey:          []byte("AAAA-BBBB"),
+		Status:          client.Deleted,
+	}
+
+	node, err := makeNode(delta, []cid.Cid{})
+	require.NoError(t, err)
+
+	extractedDelta, err := CompositeDAG{}.DeltaDecodeWithoutData(node)
+	require.NoError(t, err)
+
+	require.Equal(
+		t,
+		&CompositeDAGDelta{
+			SchemaVersionID: "schemaVersionID",
+			Priority:        uint64(10),
+			DocKey:          []byte("AAAA-BBBB"),
+			Status:          client.Deleted,
+		},
+		extractedDelta,
+	)
+}
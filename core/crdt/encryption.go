@@ -0,0 +1,247 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package crdt
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"io"
+)
+
+// DeltaKeySize is the size of the keys the data of the deltas is encrypted with (AES-256).
+const DeltaKeySize = 32
+
+// encryptedDeltaPrefix prefixes the encrypted data of the deltas.
+//
+// The data of unencrypted deltas is CBOR encoded, and 0xff is the CBOR break code, which can't
+// start a CBOR data item, so encrypted data can't be mistaken for unencrypted data.
+var encryptedDeltaPrefix = []byte{0xff, 'e', 'n', 'c', 1}
+
+// DeltaCipher encrypts and decrypts the data of the deltas of a collection.
+//
+// Only the data of the deltas is encrypted, the rest of the blocks, including their links and
+// priority, is left as is, so that the peers without the key can still sync and replicate the
+// blocks without being able to read their contents.
+type DeltaCipher struct {
+	aead cipher.AEAD
+}
+
+// NewDeltaCipher returns a new cipher using the given AES-256 key.
+func NewDeltaCipher(key []byte) (*DeltaCipher, error) {
+	if len(key) != DeltaKeySize {
+		return nil, NewErrInvalidDeltaKey("", len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &DeltaCipher{aead: aead}, nil
+}
+
+// Encrypt returns the encrypted data of a delta.
+func (c *DeltaCipher) Encrypt(data []byte) ([]byte, error) {
+	return c.seal(encryptedDeltaPrefix, data)
+}
+
+// Decrypt returns the decrypted data of a delta, or the data as is if it is not encrypted.
+func (c *DeltaCipher) Decrypt(data []byte) ([]byte, error) {
+	if !IsEncryptedDeltaData(data) {
+		return data, nil
+	}
+	plain, ok := c.open(encryptedDeltaPrefix, data)
+	if !ok {
+		return nil, ErrInvalidEncryptedDelta
+	}
+	return plain, nil
+}
+
+// seal returns the given data encrypted, prefixed with the given prefix and the nonce.
+func (c *DeltaCipher) seal(prefix []byte, data []byte) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, 0, len(prefix)+len(nonce)+len(data)+c.aead.Overhead())
+	buf = append(buf, prefix...)
+	buf = append(buf, nonce...)
+	return c.aead.Seal(buf, nonce, data, nil), nil
+}
+
+// open returns the given data, sealed with the given prefix, decrypted.
+//
+// Returns false if it can't be decrypted.
+func (c *DeltaCipher) open(prefix []byte, data []byte) ([]byte, bool) {
+	data = data[len(prefix):]
+	if len(data) < c.aead.NonceSize() {
+		return nil, false
+	}
+	nonce, sealed := data[:c.aead.NonceSize()], data[c.aead.NonceSize():]
+	plain, err := c.aead.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return nil, false
+	}
+	return plain, true
+}
+
+// IsEncryptedDeltaData returns true if the given delta data is encrypted.
+func IsEncryptedDeltaData(data []byte) bool {
+	return bytes.HasPrefix(data, encryptedDeltaPrefix)
+}
+
+// IsEncryptedDelta returns true if the data of the given delta is encrypted.
+func IsEncryptedDelta(delta any) bool {
+	switch d := delta.(type) {
+	case *LWWRegDelta:
+		return IsEncryptedDeltaData(d.Data)
+	case *CompositeDAGDelta:
+		return IsEncryptedDeltaData(d.Data)
+	default:
+		return false
+	}
+}
+
+// Keyring holds the ciphers of the collections whose deltas are encrypted, by collection name.
+//
+// The keys are distributed out-of-band, the deltas of a collection being encrypted by the nodes
+// that have its key, and only readable by them.
+type Keyring struct {
+	ciphers map[string]*DeltaCipher
+}
+
+// NewKeyring returns a new keyring holding the given AES-256 keys by collection name.
+func NewKeyring(keys map[string][]byte) (*Keyring, error) {
+	ciphers := make(map[string]*DeltaCipher, len(keys))
+	for collection, key := range keys {
+		if len(key) != DeltaKeySize {
+			return nil, NewErrInvalidDeltaKey(collection, len(key))
+		}
+		c, err := NewDeltaCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		ciphers[collection] = c
+	}
+	return &Keyring{ciphers: ciphers}, nil
+}
+
+// Cipher returns the cipher of the collection with the given name, or nil if its deltas are not
+// encrypted.
+func (k *Keyring) Cipher(collection string) *DeltaCipher {
+	if k == nil {
+		return nil
+	}
+	return k.ciphers[collection]
+}
+
+type keyringContextKey struct{}
+
+type deltaCipherContextKey struct{}
+
+// WithKeyring returns a new context holding the given keyring, from which the cipher of the
+// collections whose deltas are replayed in this context is obtained.
+func WithKeyring(ctx context.Context, keyring *Keyring) context.Context {
+	return context.WithValue(ctx, keyringContextKey{}, keyring)
+}
+
+// KeyringFromContext returns the keyring of the given context, or nil if it has none.
+func KeyringFromContext(ctx context.Context) *Keyring {
+	keyring, _ := ctx.Value(keyringContextKey{}).(*Keyring)
+	return keyring
+}
+
+// WithDeltaCipher returns a new context in which the data of the created deltas is encrypted,
+// and that of the merged deltas decrypted, with the given cipher.
+//
+// The data of the deltas is left unencrypted if the cipher is nil.
+func WithDeltaCipher(ctx context.Context, c *DeltaCipher) context.Context {
+	return context.WithValue(ctx, deltaCipherContextKey{}, c)
+}
+
+// EncryptDeltaData returns the given delta data encrypted with the cipher of the given context,
+// if any.
+func EncryptDeltaData(ctx context.Context, data []byte) ([]byte, error) {
+	c, _ := ctx.Value(deltaCipherContextKey{}).(*DeltaCipher)
+	if c == nil || len(data) == 0 {
+		return data, nil
+	}
+	return c.Encrypt(data)
+}
+
+// DecryptDeltaData returns the given delta data decrypted with the cipher of the given context,
+// if it is encrypted.
+func DecryptDeltaData(ctx context.Context, data []byte) ([]byte, error) {
+	if !IsEncryptedDeltaData(data) {
+		return data, nil
+	}
+	c, _ := ctx.Value(deltaCipherContextKey{}).(*DeltaCipher)
+	if c == nil {
+		return nil, ErrMissingDeltaKey
+	}
+	return c.Decrypt(data)
+}
+
+// encryptedFieldPrefix prefixes the encrypted values of the encrypted fields.
+//
+// It differs from the prefix of the encrypted delta data, so that the deltas holding the value
+// of an encrypted field aren't mistaken for the encrypted deltas of a collection.
+var encryptedFieldPrefix = []byte{0xff, 'f', 'l', 'd', 1}
+
+// EncryptFieldValue returns the encrypted CBOR encoded value of an encrypted field.
+func (c *DeltaCipher) EncryptFieldValue(value []byte) ([]byte, error) {
+	return c.seal(encryptedFieldPrefix, value)
+}
+
+// DecryptFieldValue returns the decrypted value of an encrypted field, or the value as is if it
+// is not encrypted.
+func (c *DeltaCipher) DecryptFieldValue(value []byte) ([]byte, error) {
+	if !IsEncryptedFieldValue(value) {
+		return value, nil
+	}
+	plain, ok := c.open(encryptedFieldPrefix, value)
+	if !ok {
+		return nil, ErrInvalidEncryptedField
+	}
+	return plain, nil
+}
+
+// IsEncryptedFieldValue returns true if the given field value is encrypted.
+func IsEncryptedFieldValue(value []byte) bool {
+	return bytes.HasPrefix(value, encryptedFieldPrefix)
+}
+
+// FieldKeyName returns the name of the key of the given encrypted field in a keyring of field
+// keys, of the form `Collection.field`.
+func FieldKeyName(collection string, field string) string {
+	return collection + "." + field
+}
+
+type fieldKeyringContextKey struct{}
+
+// WithFieldKeyring returns a new context holding the given keyring of field keys, by field key
+// name, with which the values of the encrypted fields are encrypted when written and decrypted
+// when read in this context.
+func WithFieldKeyring(ctx context.Context, keyring *Keyring) context.Context {
+	return context.WithValue(ctx, fieldKeyringContextKey{}, keyring)
+}
+
+// FieldKeyringFromContext returns the keyring of field keys of the given context, or nil if it
+// has none.
+func FieldKeyringFromContext(ctx context.Context) *Keyring {
+	keyring, _ := ctx.Value(fieldKeyringContextKey{}).(*Keyring)
+	return keyring
+}
@@ -0,0 +1,94 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package crdt
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestDeltaCipher(t *testing.T, seed byte) *DeltaCipher {
+	c, err := NewDeltaCipher(bytes.Repeat([]byte{seed}, DeltaKeySize))
+	require.NoError(t, err)
+	return c
+}
+
+func TestDeltaCipherEncryptDecrypt(t *testing.T) {
+	c := newTestDeltaCipher(t, 1)
+
+	data, err := c.Encrypt([]byte("John"))
+	require.NoError(t, err)
+	assert.True(t, IsEncryptedDeltaData(data))
+	assert.NotContains(t, string(data), "John")
+
+	plain, err := c.Decrypt(data)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("John"), plain)
+
+	// Unencrypted data is returned as is.
+	plain, err = c.Decrypt([]byte("John"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("John"), plain)
+}
+
+func TestDeltaCipherDecryptWithAnotherKey(t *testing.T) {
+	data, err := newTestDeltaCipher(t, 1).Encrypt([]byte("John"))
+	require.NoError(t, err)
+
+	_, err = newTestDeltaCipher(t, 2).Decrypt(data)
+	require.ErrorIs(t, err, ErrInvalidEncryptedDelta)
+}
+
+func TestDeltaCipherEncryptDecryptFieldValue(t *testing.T) {
+	c := newTestDeltaCipher(t, 1)
+
+	value, err := c.EncryptFieldValue([]byte("John"))
+	require.NoError(t, err)
+	assert.True(t, IsEncryptedFieldValue(value))
+	assert.False(t, IsEncryptedDeltaData(value))
+	assert.NotContains(t, string(value), "John")
+
+	plain, err := c.DecryptFieldValue(value)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("John"), plain)
+
+	_, err = newTestDeltaCipher(t, 2).DecryptFieldValue(value)
+	require.ErrorIs(t, err, ErrInvalidEncryptedField)
+}
+
+func TestNewKeyringWithInvalidKey(t *testing.T) {
+	_, err := NewKeyring(map[string][]byte{"Users": []byte("too short")})
+	require.ErrorIs(t, err, ErrInvalidDeltaKey)
+}
+
+func TestLWWRegisterMergeEncryptedDelta(t *testing.T) {
+	ctx := WithDeltaCipher(context.Background(), newTestDeltaCipher(t, 1))
+	data, err := EncryptDeltaData(ctx, []byte("test"))
+	require.NoError(t, err)
+
+	lww := setupLWWRegister()
+	delta := lww.Set(data)
+	delta.SetPriority(1)
+
+	// The delta can't be merged without the key.
+	err = lww.Merge(context.Background(), delta, "test")
+	require.ErrorIs(t, err, ErrMissingDeltaKey)
+
+	err = lww.Merge(ctx, delta, "test")
+	require.NoError(t, err)
+	val, err := lww.Value(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("test"), val)
+}
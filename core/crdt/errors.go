@@ -17,6 +17,8 @@ import (
 const (
 	errFailedToGetPriority string = "failed to get priority"
 	errFailedToStoreValue  string = "failed to store value"
+	errInvalidDeltaKey     string = "delta encryption keys must be 32 bytes long"
+	errMissingDeltaKey     string = "the delta is encrypted and no key is available to decrypt it"
 )
 
 // Errors returnable from this package.
@@ -30,6 +32,15 @@ var (
 	ErrDecodingPriority    = errors.New("error decoding priority")
 	// ErrMismatchedMergeType - Tying to merge two ReplicatedData of different types
 	ErrMismatchedMergeType = errors.New("given type to merge does not match source")
+	ErrInvalidDeltaKey     = errors.New(errInvalidDeltaKey)
+	ErrMissingDeltaKey     = errors.New(errMissingDeltaKey)
+	// ErrInvalidEncryptedDelta is returned when the encrypted data of a delta can't be decrypted
+	// with the given key, either because it is not the one it was encrypted with or because the
+	// data was tampered with.
+	ErrInvalidEncryptedDelta = errors.New("failed to decrypt the data of the delta")
+	// ErrInvalidEncryptedField is returned when the encrypted value of a field can't be decrypted
+	// with the given key.
+	ErrInvalidEncryptedField = errors.New("failed to decrypt the value of the field")
 )
 
 // NewErrFailedToGetPriority returns an error indicating that the priority could not be retrieved.
@@ -41,3 +52,9 @@ func NewErrFailedToGetPriority(inner error) error {
 func NewErrFailedToStoreValue(inner error) error {
 	return errors.Wrap(errFailedToStoreValue, inner)
 }
+
+// NewErrInvalidDeltaKey returns an error indicating that the delta encryption key of the given
+// collection is not of the expected length.
+func NewErrInvalidDeltaKey(collection string, length int) error {
+	return errors.New(errInvalidDeltaKey, errors.NewKV("Collection", collection), errors.NewKV("Length", length))
+}
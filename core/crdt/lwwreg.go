@@ -146,7 +146,11 @@ func (reg LWWRegister) Merge(ctx context.Context, delta core.Delta, id string) e
 		return ErrMismatchedMergeType
 	}
 
-	return reg.setValue(ctx, d.Data, d.GetPriority())
+	data, err := DecryptDeltaData(ctx, d.Data)
+	if err != nil {
+		return err
+	}
+	return reg.setValue(ctx, data, d.GetPriority())
 }
 
 func (reg LWWRegister) setValue(ctx context.Context, val []byte, priority uint64) error {
@@ -175,7 +179,12 @@ func (reg LWWRegister) setValue(ctx context.Context, val []byte, priority uint64
 		if len(curValue) > 0 {
 			curValue = curValue[1:]
 		}
-		if bytes.Compare(curValue, val) >= 0 {
+		// The values set at the same height are concurrent, the greatest one winning.
+		cmp := bytes.Compare(curValue, val)
+		if cmp != 0 {
+			recordConflict(ctx)
+		}
+		if cmp >= 0 {
 			return nil
 		}
 	}
@@ -109,6 +109,30 @@ func TestLWWRegisterOldMerge(t *testing.T) {
 	}
 }
 
+func TestLWWRegisterConcurrentMergeCountsConflict(t *testing.T) {
+	stats := &MergeStats{}
+	ctx := WithMergeStats(context.Background(), stats)
+	lww := setupLoadedLWWRegster(ctx)
+
+	for _, value := range []string{"test", "test0", "abc"} {
+		addDelta := lww.Set([]byte(value))
+		addDelta.SetPriority(1)
+		lww.Merge(ctx, addDelta, "test")
+	}
+
+	val, err := lww.Value(ctx)
+	if err != nil {
+		t.Error(err)
+	}
+	if string(val) != "test0" {
+		t.Errorf("Incorrect merge state, want %s, have %s", "test0", val)
+	}
+	// The value equal to the current one is not a conflict.
+	if stats.Conflicts() != 2 {
+		t.Errorf("Incorrect conflict count, want %d, have %d", 2, stats.Conflicts())
+	}
+}
+
 func TestLWWRegisterDeltaInit(t *testing.T) {
 	delta := &LWWRegDelta{
 		Data: []byte("test"),
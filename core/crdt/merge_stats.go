@@ -0,0 +1,42 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package crdt
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// MergeStats counts the conflicts resolved while merging deltas.
+type MergeStats struct {
+	conflicts atomic.Uint64
+}
+
+// Conflicts returns the number of concurrent values of registers resolved by keeping the last
+// writer's, the values set at the same height of the DAG of a register being concurrent.
+func (s *MergeStats) Conflicts() uint64 {
+	return s.conflicts.Load()
+}
+
+type mergeStatsContextKey struct{}
+
+// WithMergeStats returns a new context in which the conflicts resolved while merging deltas are
+// counted in the given stats.
+func WithMergeStats(ctx context.Context, s *MergeStats) context.Context {
+	return context.WithValue(ctx, mergeStatsContextKey{}, s)
+}
+
+// recordConflict counts a conflict in the merge stats of the given context, if any.
+func recordConflict(ctx context.Context) {
+	if s, _ := ctx.Value(mergeStatsContextKey{}).(*MergeStats); s != nil {
+		s.conflicts.Add(1)
+	}
+}
@@ -191,7 +191,7 @@ func MergeAscending(spans []Span) []Span {
 				// Shift all remaining unique spans one place to the right
 				newArray := make([]Span, len(uniqueSpans)+1)
 				for j := len(uniqueSpans); j > i; j-- {
-					newArray[j] = uniqueSpans[i]
+					newArray[j] = uniqueSpans[j-1]
 				}
 
 				// Then we insert
@@ -700,3 +700,27 @@ func TestMergeAscending_ReturnsSingle_GivenStartAdjacentAndAfterEndEndAfter(t *t
 	assert.Equal(t, start1, result[0].Start().ToString())
 	assert.Equal(t, end2, result[0].End().ToString())
 }
+
+func TestMergeAscending_ReturnsItemsInOrder_GivenKeyBeforeMultipleItems(t *testing.T) {
+	start1 := "/p/0/0/k4"
+	end1 := "/p/0/0/k5"
+	start2 := "/p/0/0/k7"
+	end2 := "/p/0/0/k8"
+	start3 := "/p/0/0/k1"
+	end3 := "/p/0/0/k2"
+	input := []Span{
+		NewSpan(MustNewDataStoreKey(start1), MustNewDataStoreKey(end1)),
+		NewSpan(MustNewDataStoreKey(start2), MustNewDataStoreKey(end2)),
+		NewSpan(MustNewDataStoreKey(start3), MustNewDataStoreKey(end3)),
+	}
+
+	result := MergeAscending(input)
+
+	assert.Len(t, result, 3)
+	assert.Equal(t, start3, result[0].Start().ToString())
+	assert.Equal(t, end3, result[0].End().ToString())
+	assert.Equal(t, start1, result[1].Start().ToString())
+	assert.Equal(t, end1, result[1].End().ToString())
+	assert.Equal(t, start2, result[2].Start().ToString())
+	assert.Equal(t, end2, result[2].End().ToString())
+}
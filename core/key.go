@@ -11,8 +11,11 @@
 package core
 
 import (
+	"encoding/hex"
+	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/ipfs/go-cid"
 	ds "github.com/ipfs/go-datastore"
@@ -38,6 +41,8 @@ const (
 	PriorityKey = InstanceType("p")
 	// DeletedKey is a type that represents a deleted document.
 	DeletedKey = InstanceType("d")
+	// PrimaryKey is a type that represents the existence marker of a document.
+	PrimaryKey = InstanceType("pk")
 )
 
 const (
@@ -48,6 +53,17 @@ const (
 	PRIMARY_KEY               = "/pk"
 	REPLICATOR                = "/replicator/id"
 	P2P_COLLECTION            = "/p2p/collection"
+	COMMIT_AUTHOR             = "/commit/author"
+	COMMIT_TIME               = "/commit/time"
+	CHANGEFEED                = "/changefeed"
+	WEBHOOK                   = "/webhook/id"
+	WEBHOOK_DEAD_LETTER       = "/webhook/deadletter"
+	SCHEMA_UPDATE             = "/schema/update"
+	KV_ENTRY                  = "/kv"
+	QUOTA_COLLECTION          = "/quota/collection"
+	QUOTA_TOTAL               = "/quota/total"
+	QUOTA_USAGE               = "/quota/usage"
+	QUOTA_AGE                 = "/quota/age"
 )
 
 // Key is an interface that represents a key in the database.
@@ -124,6 +140,115 @@ type ReplicatorKey struct {
 
 var _ Key = (*ReplicatorKey)(nil)
 
+// CommitAuthorKey is the key of the ID of the peer that authored a commit.
+type CommitAuthorKey struct {
+	Cid cid.Cid
+}
+
+var _ Key = (*CommitAuthorKey)(nil)
+
+// CommitTimeKey is the key of a composite commit of a document, by the time at which it was
+// committed to the local store. Its value is the status of the document at that commit.
+//
+// Keys are ordered by time for each document, so that the commit of a document at a
+// given time can be found by scanning its keys.
+type CommitTimeKey struct {
+	DocKey string
+	// The time of the commit, in nanoseconds since the Unix epoch, or zero if not set.
+	UnixNano int64
+	Cid      cid.Cid
+}
+
+var _ Key = (*CommitTimeKey)(nil)
+
+// ChangefeedKey is the key of an update of the changefeed of a collection, by its sequence
+// number within the changefeed.
+//
+// Keys are ordered by sequence number for each collection.
+type ChangefeedKey struct {
+	CollectionID uint32
+	// The sequence number of the update, starting at one, or zero if not set.
+	Sequence uint64
+}
+
+var _ Key = (*ChangefeedKey)(nil)
+
+// SchemaUpdateKey is the key of the signed schema update that produced a schema version.
+type SchemaUpdateKey struct {
+	SchemaVersionID string
+}
+
+var _ Key = (*SchemaUpdateKey)(nil)
+
+// WebhookKey is the key of a registered webhook.
+type WebhookKey struct {
+	WebhookID string
+}
+
+var _ Key = (*WebhookKey)(nil)
+
+// KVKey is the key of an entry of a namespace of the key-value store of the applications.
+//
+// The key of the entry is hex encoded, so that it may contain any character without escaping its
+// namespace, while the entries are still ordered by key.
+type KVKey struct {
+	Namespace string
+	Key       string
+}
+
+var _ Key = (*KVKey)(nil)
+
+// WebhookDeadLetterKey is the key of an event that could not be delivered to a webhook.
+//
+// Keys are ordered by the time of the failed delivery.
+type WebhookDeadLetterKey struct {
+	// The time of the failed delivery, in nanoseconds since the Unix epoch, or zero if not set.
+	UnixNano  int64
+	WebhookID string
+	Cid       cid.Cid
+}
+
+var _ Key = (*WebhookDeadLetterKey)(nil)
+
+// QuotaCollectionKey is the key marking that the usage of the documents of a collection with a
+// storage quota is recorded. Its value is the name of the collection.
+type QuotaCollectionKey struct {
+	CollectionID uint32
+}
+
+var _ Key = (*QuotaCollectionKey)(nil)
+
+// QuotaTotalKey is the key of the usage of a collection with a storage quota. Its value is the
+// number of documents of the collection and their size.
+type QuotaTotalKey struct {
+	CollectionID uint32
+}
+
+var _ Key = (*QuotaTotalKey)(nil)
+
+// QuotaUsageKey is the key of the usage of a document of a collection with a storage quota. Its
+// value is the size of the document and the time it was first recorded at.
+type QuotaUsageKey struct {
+	CollectionID uint32
+	DocKey       string
+}
+
+var _ Key = (*QuotaUsageKey)(nil)
+
+// QuotaAgeKey is the key of a document of a collection with a storage quota, by the time its
+// usage was first recorded at. Its value is the size of the document.
+//
+// Keys are ordered by time for each collection, so that the oldest documents are pruned first.
+type QuotaAgeKey struct {
+	CollectionID uint32
+	// The time the usage was first recorded at, in nanoseconds since the Unix epoch, or zero if
+	// not set.
+	UnixNano int64
+	DocKey   string
+}
+
+var _ Key = (*QuotaAgeKey)(nil)
+
 // Creates a new DataStoreKey from a string as best as it can,
 // splitting the input using '/' as a field deliminator.  It assumes
 // that the input string is in the following format:
@@ -232,6 +357,14 @@ func (k DataStoreKey) WithDeletedFlag() DataStoreKey {
 	return newKey
 }
 
+// WithPrimaryFlag returns a copy of the key with the instance type of the existence marker of
+// documents, which is the same as the PrimaryDataStoreKey of the document.
+func (k DataStoreKey) WithPrimaryFlag() DataStoreKey {
+	newKey := k
+	newKey.InstanceType = PrimaryKey
+	return newKey
+}
+
 func (k DataStoreKey) WithDocKey(docKey string) DataStoreKey {
 	newKey := k
 	newKey.DocKey = docKey
@@ -472,6 +605,265 @@ func (k ReplicatorKey) ToDS() ds.Key {
 	return ds.NewKey(k.ToString())
 }
 
+// NewWebhookKey returns the key of the webhook with the given ID.
+func NewWebhookKey(id string) WebhookKey {
+	return WebhookKey{WebhookID: id}
+}
+
+func (k WebhookKey) ToString() string {
+	result := WEBHOOK
+
+	if k.WebhookID != "" {
+		result = result + "/" + k.WebhookID
+	}
+
+	return result
+}
+
+func (k WebhookKey) Bytes() []byte {
+	return []byte(k.ToString())
+}
+
+func (k WebhookKey) ToDS() ds.Key {
+	return ds.NewKey(k.ToString())
+}
+
+// NewKVKey returns the key of the entry with the given key in the given namespace of the key-value
+// store.
+func NewKVKey(namespace string, key string) KVKey {
+	return KVKey{Namespace: namespace, Key: key}
+}
+
+// NewKVKeyFromString parses the given string as a KVKey.
+func NewKVKeyFromString(key string) (KVKey, error) {
+	keyArr := strings.Split(key, "/")
+	if len(keyArr) != 4 || "/"+keyArr[1] != KV_ENTRY {
+		return KVKey{}, errors.WithStack(ErrInvalidKey, errors.NewKV("Key", key))
+	}
+	entryKey, err := hex.DecodeString(keyArr[3])
+	if err != nil {
+		return KVKey{}, errors.WithStack(ErrInvalidKey, errors.NewKV("Key", key))
+	}
+	return NewKVKey(keyArr[2], string(entryKey)), nil
+}
+
+func (k KVKey) ToString() string {
+	result := KV_ENTRY
+
+	if k.Namespace != "" {
+		result = result + "/" + k.Namespace
+	}
+	if k.Key != "" {
+		result = result + "/" + hex.EncodeToString([]byte(k.Key))
+	}
+
+	return result
+}
+
+func (k KVKey) Bytes() []byte {
+	return []byte(k.ToString())
+}
+
+func (k KVKey) ToDS() ds.Key {
+	return ds.NewKey(k.ToString())
+}
+
+// NewWebhookDeadLetterKey returns the key of the event of the commit with the given CID that
+// could not be delivered to the given webhook at the given time.
+func NewWebhookDeadLetterKey(t time.Time, webhookID string, c cid.Cid) WebhookDeadLetterKey {
+	return WebhookDeadLetterKey{
+		UnixNano:  t.UnixNano(),
+		WebhookID: webhookID,
+		Cid:       c,
+	}
+}
+
+func (k WebhookDeadLetterKey) ToString() string {
+	result := WEBHOOK_DEAD_LETTER
+
+	if k.UnixNano != 0 {
+		// The time is zero padded so that the keys are ordered by time.
+		result = result + "/" + fmt.Sprintf("%020d", k.UnixNano)
+	}
+	if k.WebhookID != "" {
+		result = result + "/" + k.WebhookID
+	}
+	if k.Cid.Defined() {
+		result = result + "/" + k.Cid.String()
+	}
+
+	return result
+}
+
+func (k WebhookDeadLetterKey) Bytes() []byte {
+	return []byte(k.ToString())
+}
+
+func (k WebhookDeadLetterKey) ToDS() ds.Key {
+	return ds.NewKey(k.ToString())
+}
+
+// NewSchemaUpdateKey returns the key of the signed schema update that produced the schema
+// version with the given ID.
+func NewSchemaUpdateKey(schemaVersionID string) SchemaUpdateKey {
+	return SchemaUpdateKey{SchemaVersionID: schemaVersionID}
+}
+
+func (k SchemaUpdateKey) ToString() string {
+	result := SCHEMA_UPDATE
+
+	if k.SchemaVersionID != "" {
+		result = result + "/" + k.SchemaVersionID
+	}
+
+	return result
+}
+
+func (k SchemaUpdateKey) Bytes() []byte {
+	return []byte(k.ToString())
+}
+
+func (k SchemaUpdateKey) ToDS() ds.Key {
+	return ds.NewKey(k.ToString())
+}
+
+// NewCommitAuthorKey returns the key of the author of the commit with the given CID.
+func NewCommitAuthorKey(c cid.Cid) CommitAuthorKey {
+	return CommitAuthorKey{Cid: c}
+}
+
+func (k CommitAuthorKey) ToString() string {
+	result := COMMIT_AUTHOR
+
+	if k.Cid.Defined() {
+		result = result + "/" + k.Cid.String()
+	}
+
+	return result
+}
+
+func (k CommitAuthorKey) Bytes() []byte {
+	return []byte(k.ToString())
+}
+
+func (k CommitAuthorKey) ToDS() ds.Key {
+	return ds.NewKey(k.ToString())
+}
+
+// NewCommitTimeKey returns the key of the given composite commit of the document with the given
+// key, committed at the given time.
+func NewCommitTimeKey(docKey string, t time.Time, c cid.Cid) CommitTimeKey {
+	return CommitTimeKey{
+		DocKey:   docKey,
+		UnixNano: t.UnixNano(),
+		Cid:      c,
+	}
+}
+
+// NewCommitTimeKeyFromString parses the given string into a CommitTimeKey, it expects
+// the string to be in the format `/commit/time/[DocKey]/[UnixNano]/[Cid]`.
+func NewCommitTimeKeyFromString(key string) (CommitTimeKey, error) {
+	keyArr := strings.Split(key, "/")
+	if len(keyArr) != 6 || "/"+keyArr[1]+"/"+keyArr[2] != COMMIT_TIME {
+		return CommitTimeKey{}, errors.WithStack(ErrInvalidKey, errors.NewKV("Key", key))
+	}
+	unixNano, err := strconv.ParseInt(keyArr[4], 10, 64)
+	if err != nil {
+		return CommitTimeKey{}, errors.WithStack(ErrInvalidKey, errors.NewKV("Key", key))
+	}
+	c, err := cid.Decode(keyArr[5])
+	if err != nil {
+		return CommitTimeKey{}, errors.WithStack(ErrInvalidKey, errors.NewKV("Key", key))
+	}
+	return CommitTimeKey{
+		DocKey:   keyArr[3],
+		UnixNano: unixNano,
+		Cid:      c,
+	}, nil
+}
+
+// Time returns the time of the commit.
+func (k CommitTimeKey) Time() time.Time {
+	return time.Unix(0, k.UnixNano)
+}
+
+func (k CommitTimeKey) ToString() string {
+	result := COMMIT_TIME
+
+	if k.DocKey != "" {
+		result = result + "/" + k.DocKey
+	}
+	if k.UnixNano != 0 {
+		// The time is zero padded so that the keys are ordered by time.
+		result = result + "/" + fmt.Sprintf("%020d", k.UnixNano)
+	}
+	if k.Cid.Defined() {
+		result = result + "/" + k.Cid.String()
+	}
+
+	return result
+}
+
+func (k CommitTimeKey) Bytes() []byte {
+	return []byte(k.ToString())
+}
+
+func (k CommitTimeKey) ToDS() ds.Key {
+	return ds.NewKey(k.ToString())
+}
+
+// NewChangefeedKey returns the key of the update with the given sequence number within the
+// changefeed of the given collection.
+func NewChangefeedKey(collectionID uint32, sequence uint64) ChangefeedKey {
+	return ChangefeedKey{
+		CollectionID: collectionID,
+		Sequence:     sequence,
+	}
+}
+
+// NewChangefeedKeyFromString parses the given string into a ChangefeedKey, it expects
+// the string to be in the format `/changefeed/[CollectionID]/[Sequence]`.
+func NewChangefeedKeyFromString(key string) (ChangefeedKey, error) {
+	keyArr := strings.Split(key, "/")
+	if len(keyArr) != 4 || "/"+keyArr[1] != CHANGEFEED {
+		return ChangefeedKey{}, errors.WithStack(ErrInvalidKey, errors.NewKV("Key", key))
+	}
+	collectionID, err := strconv.ParseUint(keyArr[2], 10, 32)
+	if err != nil {
+		return ChangefeedKey{}, errors.WithStack(ErrInvalidKey, errors.NewKV("Key", key))
+	}
+	sequence, err := strconv.ParseUint(keyArr[3], 10, 64)
+	if err != nil {
+		return ChangefeedKey{}, errors.WithStack(ErrInvalidKey, errors.NewKV("Key", key))
+	}
+	return ChangefeedKey{
+		CollectionID: uint32(collectionID),
+		Sequence:     sequence,
+	}, nil
+}
+
+func (k ChangefeedKey) ToString() string {
+	result := CHANGEFEED
+
+	if k.CollectionID != 0 {
+		result = result + "/" + strconv.FormatUint(uint64(k.CollectionID), 10)
+	}
+	if k.Sequence != 0 {
+		// The sequence is zero padded so that the keys are ordered by sequence.
+		result = result + "/" + fmt.Sprintf("%020d", k.Sequence)
+	}
+
+	return result
+}
+
+func (k ChangefeedKey) Bytes() []byte {
+	return []byte(k.ToString())
+}
+
+func (k ChangefeedKey) ToDS() ds.Key {
+	return ds.NewKey(k.ToString())
+}
+
 func (k HeadStoreKey) ToString() string {
 	var result string
 
@@ -546,3 +938,151 @@ func bytesPrefixEnd(b []byte) []byte {
 	// maximal byte string (i.e. already \xff...).
 	return b
 }
+
+// NewQuotaCollectionKey returns the key marking that the usage of the documents of the given
+// collection is recorded.
+func NewQuotaCollectionKey(collectionID uint32) QuotaCollectionKey {
+	return QuotaCollectionKey{CollectionID: collectionID}
+}
+
+// NewQuotaCollectionKeyFromString parses the given string into a QuotaCollectionKey, it expects
+// the string to be in the format `/quota/collection/[CollectionID]`.
+func NewQuotaCollectionKeyFromString(key string) (QuotaCollectionKey, error) {
+	keyArr := strings.Split(key, "/")
+	if len(keyArr) != 4 || "/"+keyArr[1]+"/"+keyArr[2] != QUOTA_COLLECTION {
+		return QuotaCollectionKey{}, errors.WithStack(ErrInvalidKey, errors.NewKV("Key", key))
+	}
+	collectionID, err := strconv.ParseUint(keyArr[3], 10, 32)
+	if err != nil {
+		return QuotaCollectionKey{}, errors.WithStack(ErrInvalidKey, errors.NewKV("Key", key))
+	}
+	return QuotaCollectionKey{CollectionID: uint32(collectionID)}, nil
+}
+
+func (k QuotaCollectionKey) ToString() string {
+	result := QUOTA_COLLECTION
+
+	if k.CollectionID != 0 {
+		result = result + "/" + strconv.FormatUint(uint64(k.CollectionID), 10)
+	}
+
+	return result
+}
+
+func (k QuotaCollectionKey) Bytes() []byte {
+	return []byte(k.ToString())
+}
+
+func (k QuotaCollectionKey) ToDS() ds.Key {
+	return ds.NewKey(k.ToString())
+}
+
+// NewQuotaTotalKey returns the key of the usage of the given collection.
+func NewQuotaTotalKey(collectionID uint32) QuotaTotalKey {
+	return QuotaTotalKey{CollectionID: collectionID}
+}
+
+func (k QuotaTotalKey) ToString() string {
+	result := QUOTA_TOTAL
+
+	if k.CollectionID != 0 {
+		result = result + "/" + strconv.FormatUint(uint64(k.CollectionID), 10)
+	}
+
+	return result
+}
+
+func (k QuotaTotalKey) Bytes() []byte {
+	return []byte(k.ToString())
+}
+
+func (k QuotaTotalKey) ToDS() ds.Key {
+	return ds.NewKey(k.ToString())
+}
+
+// NewQuotaUsageKey returns the key of the usage of the document with the given key of the given
+// collection.
+func NewQuotaUsageKey(collectionID uint32, docKey string) QuotaUsageKey {
+	return QuotaUsageKey{
+		CollectionID: collectionID,
+		DocKey:       docKey,
+	}
+}
+
+func (k QuotaUsageKey) ToString() string {
+	result := QUOTA_USAGE
+
+	if k.CollectionID != 0 {
+		result = result + "/" + strconv.FormatUint(uint64(k.CollectionID), 10)
+	}
+	if k.DocKey != "" {
+		result = result + "/" + k.DocKey
+	}
+
+	return result
+}
+
+func (k QuotaUsageKey) Bytes() []byte {
+	return []byte(k.ToString())
+}
+
+func (k QuotaUsageKey) ToDS() ds.Key {
+	return ds.NewKey(k.ToString())
+}
+
+// NewQuotaAgeKey returns the key of the document with the given key of the given collection,
+// whose usage was first recorded at the given time.
+func NewQuotaAgeKey(collectionID uint32, unixNano int64, docKey string) QuotaAgeKey {
+	return QuotaAgeKey{
+		CollectionID: collectionID,
+		UnixNano:     unixNano,
+		DocKey:       docKey,
+	}
+}
+
+// NewQuotaAgeKeyFromString parses the given string into a QuotaAgeKey, it expects
+// the string to be in the format `/quota/age/[CollectionID]/[UnixNano]/[DocKey]`.
+func NewQuotaAgeKeyFromString(key string) (QuotaAgeKey, error) {
+	keyArr := strings.Split(key, "/")
+	if len(keyArr) != 6 || "/"+keyArr[1]+"/"+keyArr[2] != QUOTA_AGE {
+		return QuotaAgeKey{}, errors.WithStack(ErrInvalidKey, errors.NewKV("Key", key))
+	}
+	collectionID, err := strconv.ParseUint(keyArr[3], 10, 32)
+	if err != nil {
+		return QuotaAgeKey{}, errors.WithStack(ErrInvalidKey, errors.NewKV("Key", key))
+	}
+	unixNano, err := strconv.ParseInt(keyArr[4], 10, 64)
+	if err != nil {
+		return QuotaAgeKey{}, errors.WithStack(ErrInvalidKey, errors.NewKV("Key", key))
+	}
+	return QuotaAgeKey{
+		CollectionID: uint32(collectionID),
+		UnixNano:     unixNano,
+		DocKey:       keyArr[5],
+	}, nil
+}
+
+func (k QuotaAgeKey) ToString() string {
+	result := QUOTA_AGE
+
+	if k.CollectionID != 0 {
+		result = result + "/" + strconv.FormatUint(uint64(k.CollectionID), 10)
+	}
+	if k.UnixNano != 0 || k.DocKey != "" {
+		// The time is zero padded so that the keys are ordered by time.
+		result = result + "/" + fmt.Sprintf("%020d", k.UnixNano)
+	}
+	if k.DocKey != "" {
+		result = result + "/" + k.DocKey
+	}
+
+	return result
+}
+
+func (k QuotaAgeKey) Bytes() []byte {
+	return []byte(k.ToString())
+}
+
+func (k QuotaAgeKey) ToDS() ds.Key {
+	return ds.NewKey(k.ToString())
+}
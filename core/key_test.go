@@ -12,8 +12,11 @@ package core
 
 import (
 	"testing"
+	"time"
 
+	"github.com/ipfs/go-cid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewDataStoreKey_ReturnsEmptyStruct_GivenEmptyString(t *testing.T) {
@@ -107,3 +110,101 @@ func TestNewDataStoreKey_GivenAStringWithExtraSuffix(t *testing.T) {
 
 	assert.ErrorIs(t, ErrInvalidKey, err)
 }
+
+func TestNewCommitTimeKeyFromString_ReturnsKey_GivenKeyString(t *testing.T) {
+	c, err := cid.Decode("bafybeiaahzxsfz55nuqnsll42wxrbdjmy5si222l4ydbrwb53tpxnzdmwq")
+	require.NoError(t, err)
+	key := NewCommitTimeKey("bae-41598f0c-19bc-5da6-813b-e80f14a10df3", time.Unix(1, 5), c)
+
+	result, err := NewCommitTimeKeyFromString(key.ToString())
+	require.NoError(t, err)
+
+	assert.Equal(t, key, result)
+	assert.Equal(t, time.Unix(1, 5), result.Time())
+}
+
+func TestCommitTimeKey_IsOrderedByTime(t *testing.T) {
+	c, err := cid.Decode("bafybeiaahzxsfz55nuqnsll42wxrbdjmy5si222l4ydbrwb53tpxnzdmwq")
+	require.NoError(t, err)
+	earlier := NewCommitTimeKey("bae-41598f0c-19bc-5da6-813b-e80f14a10df3", time.Unix(9, 0), c)
+	later := NewCommitTimeKey("bae-41598f0c-19bc-5da6-813b-e80f14a10df3", time.Unix(10, 0), c)
+
+	assert.Less(t, earlier.ToString(), later.ToString())
+}
+
+func TestNewCommitTimeKeyFromString_ReturnsError_GivenOtherKey(t *testing.T) {
+	_, err := NewCommitTimeKeyFromString("/commit/author/bafybeiaahzxsfz55nuqnsll42wxrbdjmy5si222l4ydbrwb53tpxnzdmwq")
+
+	assert.ErrorIs(t, err, ErrInvalidKey)
+}
+
+func TestNewChangefeedKeyFromString_ReturnsKey_GivenKeyString(t *testing.T) {
+	key := NewChangefeedKey(3, 42)
+
+	result, err := NewChangefeedKeyFromString(key.ToString())
+	require.NoError(t, err)
+
+	assert.Equal(t, key, result)
+}
+
+func TestChangefeedKey_IsOrderedBySequence(t *testing.T) {
+	earlier := NewChangefeedKey(1, 9)
+	later := NewChangefeedKey(1, 10)
+
+	assert.Less(t, earlier.ToString(), later.ToString())
+}
+
+func TestNewChangefeedKeyFromString_ReturnsError_GivenOtherKey(t *testing.T) {
+	_, err := NewChangefeedKeyFromString("/seq/changefeed/1")
+
+	assert.ErrorIs(t, err, ErrInvalidKey)
+}
+
+func TestNewQuotaAgeKeyFromString_ReturnsKey_GivenKeyString(t *testing.T) {
+	key := NewQuotaAgeKey(3, 42, "bae-41598f0c-19bc-5da6-813b-e80f14a10df3")
+
+	result, err := NewQuotaAgeKeyFromString(key.ToString())
+	require.NoError(t, err)
+
+	assert.Equal(t, key, result)
+}
+
+func TestQuotaAgeKey_IsOrderedByTime(t *testing.T) {
+	unset := NewQuotaAgeKey(1, 0, "bae-41598f0c-19bc-5da6-813b-e80f14a10df3")
+	earlier := NewQuotaAgeKey(1, 9, "bae-41598f0c-19bc-5da6-813b-e80f14a10df3")
+	later := NewQuotaAgeKey(1, 10, "bae-00000000-19bc-5da6-813b-e80f14a10df3")
+
+	assert.Less(t, unset.ToString(), earlier.ToString())
+	assert.Less(t, earlier.ToString(), later.ToString())
+}
+
+func TestNewQuotaCollectionKeyFromString_ReturnsKey_GivenKeyString(t *testing.T) {
+	key := NewQuotaCollectionKey(3)
+
+	result, err := NewQuotaCollectionKeyFromString(key.ToString())
+	require.NoError(t, err)
+
+	assert.Equal(t, key, result)
+}
+
+func TestNewKVKeyFromString_ReturnsKey_GivenKeyString(t *testing.T) {
+	key := NewKVKey("app", "../cursor/users")
+
+	result, err := NewKVKeyFromString(key.ToDS().String())
+	require.NoError(t, err)
+
+	assert.Equal(t, key, result)
+}
+
+func TestKVKey_IsOrderedByKey(t *testing.T) {
+	earlier := NewKVKey("app", "cursor")
+	later := NewKVKey("app", "cursors")
+
+	assert.Less(t, earlier.ToString(), later.ToString())
+}
+
+func TestNewKVKeyFromString_ReturnsError_GivenOtherKey(t *testing.T) {
+	_, err := NewKVKeyFromString("/webhook/id/app")
+
+	assert.ErrorIs(t, err, ErrInvalidKey)
+}
@@ -26,6 +26,9 @@ const (
 	Version = "0.0.1"
 	// Protocol is the complete libp2p protocol tag.
 	Protocol protocol.ID = "/" + Name + "/" + Version
+	// CompressionProtocol is the libp2p protocol tag advertised by the peers accepting zstd
+	// compressed blocks. No stream is opened over it, it only advertises the capability.
+	CompressionProtocol protocol.ID = "/" + Name + "/zstd/" + Version
 )
 
 func init() {
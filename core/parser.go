@@ -41,11 +41,17 @@ type Parser interface {
 	// Returns true if the given request ast is an introspection request.
 	IsIntrospection(*ast.Document) bool
 
+	// Returns the names of the top-level fields of the queries of the given request ast that are
+	// not in this parser's model, such as the collections it does not hold.
+	UnknownQueryFields(*ast.Document) []string
+
 	// Executes the given introspection request.
 	ExecuteIntrospection(request string) *client.RequestResult
 
 	// Parses the given request, returning a strongly typed model of that request.
-	Parse(*ast.Document) (*request.Request, []error)
+	//
+	// The given variables hold the values of the variables of the request, by variable name.
+	Parse(ast *ast.Document, variables map[string]any) (*request.Request, []error)
 
 	// NewFilterFromString creates a new filter from a string.
 	NewFilterFromString(collectionType string, body string) (immutable.Option[request.Filter], error)
@@ -26,7 +26,7 @@ var log = logger.Logger("badger")
 
 var (
 	ErrClosed      = errors.New("datastore closed")
-	ErrTxnConflict = badger.ErrConflict
+	ErrTxnConflict = errors.WithCode(errors.CodeTransactionConflict, badger.ErrConflict)
 )
 
 type Datastore struct {
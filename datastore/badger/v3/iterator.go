@@ -41,6 +41,15 @@ func (t *txn) GetIterator(q dsq.Query) (iterable.Iterator, error) {
 	opt := badger.DefaultIteratorOptions
 	// Prefetching prevents the re-use of the iterator
 	opt.PrefetchValues = false
+	// Restricting the iterator to the prefix of the query lets Badger skip the tables that
+	// have no keys with it, instead of reading them on every seek.
+	if q.Prefix != "" {
+		prefix := ds.NewKey(q.Prefix).String()
+		if prefix != "/" {
+			prefix += "/"
+		}
+		opt.Prefix = []byte(prefix)
+	}
 
 	var reversedOrder bool
 	// Handle ordering
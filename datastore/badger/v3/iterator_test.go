@@ -0,0 +1,57 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package badger
+
+import (
+	"context"
+	"testing"
+
+	ds "github.com/ipfs/go-datastore"
+	dsq "github.com/ipfs/go-datastore/query"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIteratePrefixWithQueryPrefix(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewDatastore(t.TempDir(), nil)
+	require.NoError(t, err)
+	defer func() {
+		err := s.Close()
+		require.NoError(t, err)
+	}()
+
+	for _, key := range []string{"/1/v/a", "/1/v/b", "/10/v/a", "/2/v/a"} {
+		err := s.Put(ctx, ds.NewKey(key), []byte("value"))
+		require.NoError(t, err)
+	}
+
+	txn, err := s.NewIterableTransaction(ctx, true)
+	require.NoError(t, err)
+	defer txn.Discard(ctx)
+
+	iterator, err := txn.GetIterator(dsq.Query{Prefix: "/1"})
+	require.NoError(t, err)
+	defer func() {
+		err := iterator.Close()
+		require.NoError(t, err)
+	}()
+
+	results, err := iterator.IteratePrefix(ctx, ds.NewKey("/1"), ds.NewKey("/3"))
+	require.NoError(t, err)
+	entries, err := results.Rest()
+	require.NoError(t, err)
+
+	keys := []string{}
+	for _, entry := range entries {
+		keys = append(keys, entry.Key)
+	}
+	require.Equal(t, []string{"/1/v/a", "/1/v/b"}, keys)
+}
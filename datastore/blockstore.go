@@ -20,6 +20,7 @@ import (
 	ds "github.com/ipfs/go-datastore"
 	dsq "github.com/ipfs/go-datastore/query"
 	ipld "github.com/ipfs/go-ipld-format"
+	"github.com/klauspost/compress/zstd"
 
 	"github.com/sourcenetwork/defradb/errors"
 )
@@ -74,6 +75,10 @@ func (bs *bstore) Get(ctx context.Context, k cid.Cid) (blocks.Block, error) {
 	if err != nil {
 		return nil, err
 	}
+	bdata, err = DecompressBlock(bdata)
+	if err != nil {
+		return nil, err
+	}
 	if bs.rehash {
 		rbcid, err := k.Prefix().Sum(bdata)
 		if err != nil {
@@ -98,7 +103,7 @@ func (bs *bstore) Put(ctx context.Context, block blocks.Block) error {
 	if err == nil && exists {
 		return nil // already stored.
 	}
-	return bs.store.Put(ctx, k, block.RawData())
+	return bs.store.Put(ctx, k, blockData(block))
 }
 
 // PutMany stores multiple blocks to the blockstore.
@@ -110,7 +115,7 @@ func (bs *bstore) PutMany(ctx context.Context, blocks []blocks.Block) error {
 			continue
 		}
 
-		err = bs.store.Put(ctx, k, b.RawData())
+		err = bs.store.Put(ctx, k, blockData(b))
 		if err != nil {
 			return err
 		}
@@ -119,17 +124,45 @@ func (bs *bstore) PutMany(ctx context.Context, blocks []blocks.Block) error {
 }
 
 // Has returns whether a block is stored in the blockstore.
+// blockData returns the data of the given block as written to the store, compressed if the
+// block compression is enabled.
+func blockData(block blocks.Block) []byte {
+	if compressBlocks.Load() {
+		return CompressBlock(block.RawData())
+	}
+	return block.RawData()
+}
+
 func (bs *bstore) Has(ctx context.Context, k cid.Cid) (bool, error) {
 	return bs.store.Has(ctx, dshelp.MultihashToDsKey(k.Hash()))
 }
 
 // GetSize returns the size of a block in the blockstore.
+//
+// The size of a compressed block is its decompressed size.
 func (bs *bstore) GetSize(ctx context.Context, k cid.Cid) (int, error) {
-	size, err := bs.store.GetSize(ctx, dshelp.MultihashToDsKey(k.Hash()))
+	bdata, err := bs.store.Get(ctx, dshelp.MultihashToDsKey(k.Hash()))
 	if errors.Is(err, ds.ErrNotFound) {
 		return -1, ipld.ErrNotFound{Cid: k}
 	}
-	return size, err
+	if err != nil {
+		return -1, err
+	}
+	if !IsCompressedBlock(bdata) {
+		return len(bdata), nil
+	}
+	var header zstd.Header
+	if err := header.Decode(bdata); err != nil {
+		return -1, NewErrInvalidCompressedBlock(err)
+	}
+	if header.HasFCS {
+		return int(header.FrameContentSize), nil
+	}
+	bdata, err = DecompressBlock(bdata)
+	if err != nil {
+		return -1, err
+	}
+	return len(bdata), nil
 }
 
 // DeleteBlock removes a block from the blockstore.
@@ -14,6 +14,7 @@ import (
 	"context"
 	"testing"
 
+	dshelp "github.com/ipfs/boxo/datastore/dshelp"
 	blocks "github.com/ipfs/go-block-format"
 	"github.com/ipfs/go-cid"
 	ipld "github.com/ipfs/go-ipld-format"
@@ -205,3 +206,47 @@ func TestPutManyWithStoreClosed(t *testing.T) {
 	err = bs.PutMany(ctx, []blocks.Block{b, b2})
 	require.ErrorIs(t, err, memory.ErrClosed)
 }
+
+func TestBStoreGetWithCompression(t *testing.T) {
+	ctx := context.Background()
+	rootstore := memory.NewDatastore(ctx)
+	dsRW := AsDSReaderWriter(rootstore)
+
+	bs := bstore{
+		store: dsRW,
+	}
+
+	// A block written before the compression is enabled remains readable.
+	cID, err := newSHA256CidV1(data)
+	require.NoError(t, err)
+	b, err := blocks.NewBlockWithCid(data, cID)
+	require.NoError(t, err)
+	err = bs.Put(ctx, b)
+	require.NoError(t, err)
+
+	SetBlockCompression(true)
+	t.Cleanup(func() {
+		SetBlockCompression(false)
+	})
+
+	cID2, err := newSHA256CidV1(data2)
+	require.NoError(t, err)
+	b2, err := blocks.NewBlockWithCid(data2, cID2)
+	require.NoError(t, err)
+	err = bs.Put(ctx, b2)
+	require.NoError(t, err)
+
+	stored, err := dsRW.Get(ctx, dshelp.MultihashToDsKey(cID2.Hash()))
+	require.NoError(t, err)
+	require.True(t, IsCompressedBlock(stored))
+
+	for _, block := range []blocks.Block{b, b2} {
+		fetched, err := bs.Get(ctx, block.Cid())
+		require.NoError(t, err)
+		require.Equal(t, block.RawData(), fetched.RawData())
+
+		size, err := bs.GetSize(ctx, block.Cid())
+		require.NoError(t, err)
+		require.Equal(t, len(block.RawData()), size)
+	}
+}
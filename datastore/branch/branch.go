@@ -0,0 +1,379 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+/*
+Package branch provides lightweight branches of a rootstore.
+
+A branch starts as a copy of the system, data and heads keyspaces of the rootstore it is created
+from, its main rootstore, and is kept within it under a prefix of its own. The blocks are not
+copied: the blocks the branch doesn't have are read from the main rootstore, so that the branch
+shares the history of the main rootstore up to its creation. The writes to the branch, including
+its schema updates, are isolated from the main rootstore, and the writes to the main rootstore
+made after the creation of the branch are not visible to it.
+
+The blocks of the main rootstore are not returned by the queries of the blockstore of a branch.
+*/
+package branch
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	ds "github.com/ipfs/go-datastore"
+	ktds "github.com/ipfs/go-datastore/keytransform"
+	dsq "github.com/ipfs/go-datastore/query"
+
+	"github.com/sourcenetwork/defradb/datastore"
+	"github.com/sourcenetwork/defradb/datastore/iterable"
+	"github.com/sourcenetwork/defradb/errors"
+	"github.com/sourcenetwork/defradb/logging"
+)
+
+var log = logging.MustNewLogger("defra.datastore.branch")
+
+var (
+	// infoKey is the prefix of the entries of the branches, mapping the name of each branch to
+	// the time it was created at.
+	infoKey = ds.NewKey("/branches/info")
+
+	// dataKey is the prefix under which the keys of each branch are kept, by branch name.
+	dataKey = ds.NewKey("/branches/data")
+
+	// copiedKeyspaces are the keyspaces copied into a branch on its creation.
+	copiedKeyspaces = []ds.Key{
+		ds.NewKey("/db/system"),
+		datastore.DataStorePrefix(),
+		datastore.HeadStorePrefix(),
+	}
+)
+
+// Info describes a branch.
+type Info struct {
+	// Name is the name of the branch.
+	Name string
+
+	// CreatedAt is the time the branch was created at.
+	CreatedAt time.Time
+}
+
+// Create creates a branch of the given rootstore with the given name, copying the current state
+// of its system, data and heads keyspaces.
+//
+// The branch is created within a single transaction of the rootstore, so that either the whole
+// branch is created, or nothing is.
+func Create(ctx context.Context, root datastore.RootStore, name string, now time.Time) (Info, error) {
+	if name == "" || strings.Contains(name, "/") {
+		return Info{}, NewErrInvalidBranchName(name)
+	}
+	txn, err := root.NewTransaction(ctx, false)
+	if err != nil {
+		return Info{}, err
+	}
+	defer txn.Discard(ctx)
+
+	exists, err := txn.Has(ctx, infoKey.ChildString(name))
+	if err != nil {
+		return Info{}, err
+	}
+	if exists {
+		return Info{}, NewErrBranchExists(name)
+	}
+
+	prefix := dataKey.ChildString(name)
+	// The keys left by a branch of the same name whose deletion failed are removed first.
+	if err := deleteKeys(ctx, txn, prefix); err != nil {
+		return Info{}, err
+	}
+	for _, keyspace := range copiedKeyspaces {
+		if err := copyKeyspace(ctx, txn, keyspace, prefix); err != nil {
+			return Info{}, err
+		}
+	}
+	createdAt := now.UTC()
+	err = txn.Put(ctx, infoKey.ChildString(name), []byte(createdAt.Format(time.RFC3339Nano)))
+	if err != nil {
+		return Info{}, err
+	}
+	if err := txn.Commit(ctx); err != nil {
+		return Info{}, err
+	}
+	return Info{Name: name, CreatedAt: createdAt}, nil
+}
+
+// copyKeyspace copies the entries of the given keyspace under the given prefix within the given
+// transaction.
+func copyKeyspace(ctx context.Context, txn ds.Txn, keyspace ds.Key, prefix ds.Key) error {
+	results, err := txn.Query(ctx, dsq.Query{Prefix: keyspace.String()})
+	if err != nil {
+		return err
+	}
+	defer closeResults(ctx, results)
+
+	for result := range results.Next() {
+		if result.Error != nil {
+			return result.Error
+		}
+		if err := txn.Put(ctx, prefix.Child(ds.RawKey(result.Key)), result.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteKeys deletes the keys with the given prefix within the given transaction.
+func deleteKeys(ctx context.Context, txn ds.Txn, prefix ds.Key) error {
+	results, err := txn.Query(ctx, dsq.Query{Prefix: prefix.String(), KeysOnly: true})
+	if err != nil {
+		return err
+	}
+	defer closeResults(ctx, results)
+
+	for result := range results.Next() {
+		if result.Error != nil {
+			return result.Error
+		}
+		if err := txn.Delete(ctx, ds.RawKey(result.Key)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Get returns the branch of the given rootstore with the given name.
+func Get(ctx context.Context, root ds.Read, name string) (Info, error) {
+	value, err := root.Get(ctx, infoKey.ChildString(name))
+	if errors.Is(err, ds.ErrNotFound) {
+		return Info{}, NewErrBranchNotFound(name)
+	}
+	if err != nil {
+		return Info{}, err
+	}
+	return parseInfo(name, value)
+}
+
+// List returns all the branches of the given rootstore, ordered by name.
+func List(ctx context.Context, root ds.Read) ([]Info, error) {
+	results, err := root.Query(ctx, dsq.Query{
+		Prefix: infoKey.String(),
+		Orders: []dsq.Order{dsq.OrderByKey{}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer closeResults(ctx, results)
+
+	branches := []Info{}
+	for result := range results.Next() {
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		info, err := parseInfo(ds.RawKey(result.Key).BaseNamespace(), result.Value)
+		if err != nil {
+			return nil, err
+		}
+		branches = append(branches, info)
+	}
+	return branches, nil
+}
+
+func parseInfo(name string, value []byte) (Info, error) {
+	createdAt, err := time.Parse(time.RFC3339Nano, string(value))
+	if err != nil {
+		return Info{}, err
+	}
+	return Info{Name: name, CreatedAt: createdAt}, nil
+}
+
+// Discard deletes the branch of the given rootstore with the given name and all its keys.
+//
+// The stores opened on the branch must not be used afterwards.
+func Discard(ctx context.Context, root datastore.RootStore, name string) error {
+	if _, err := Get(ctx, root, name); err != nil {
+		return err
+	}
+
+	results, err := root.Query(ctx, dsq.Query{Prefix: dataKey.ChildString(name).String(), KeysOnly: true})
+	if err != nil {
+		return err
+	}
+	defer closeResults(ctx, results)
+
+	batch, err := root.Batch(ctx)
+	if err != nil {
+		return err
+	}
+	// The entry of the branch is deleted first, with its keys, so that a branch whose deletion
+	// failed no longer exists.
+	if err := batch.Delete(ctx, infoKey.ChildString(name)); err != nil {
+		return err
+	}
+	for result := range results.Next() {
+		if result.Error != nil {
+			return result.Error
+		}
+		if err := batch.Delete(ctx, ds.RawKey(result.Key)); err != nil {
+			return err
+		}
+	}
+	return batch.Commit(ctx)
+}
+
+func closeResults(ctx context.Context, results dsq.Results) {
+	if err := results.Close(); err != nil {
+		log.ErrorE(ctx, "Failed to close query results", err)
+	}
+}
+
+// Store is the rootstore of a branch, kept within its main rootstore.
+type Store struct {
+	*ktds.Datastore
+
+	main datastore.RootStore
+}
+
+var _ datastore.RootStore = (*Store)(nil)
+var _ iterable.IterableTxnDatastore = (*Store)(nil)
+
+// Open returns the rootstore of the branch of the given main rootstore with the given name, which
+// must have been created with Create.
+func Open(main datastore.RootStore, name string) *Store {
+	return &Store{
+		Datastore: ktds.Wrap(main, ktds.PrefixTransform{Prefix: dataKey.ChildString(name)}),
+		main:      main,
+	}
+}
+
+// Get implements ds.Read, reading the blocks the branch doesn't have from the main rootstore.
+func (s *Store) Get(ctx context.Context, key ds.Key) ([]byte, error) {
+	return get(ctx, s.Datastore, s.main, key)
+}
+
+// Has implements ds.Read, the blocks of the main rootstore being reported as existing.
+func (s *Store) Has(ctx context.Context, key ds.Key) (bool, error) {
+	return has(ctx, s.Datastore, s.main, key)
+}
+
+// GetSize implements ds.Read, returning the size of the blocks of the main rootstore too.
+func (s *Store) GetSize(ctx context.Context, key ds.Key) (int, error) {
+	return getSize(ctx, s.Datastore, s.main, key)
+}
+
+// Close implements io.Closer. The main rootstore is not closed, as the branch doesn't own it.
+func (s *Store) Close() error {
+	return nil
+}
+
+// NewTransaction implements ds.TxnDatastore.
+func (s *Store) NewTransaction(ctx context.Context, readOnly bool) (ds.Txn, error) {
+	t, err := s.main.NewTransaction(ctx, readOnly)
+	if err != nil {
+		return nil, err
+	}
+	return s.newTxn(t), nil
+}
+
+// NewIterableTransaction implements iterable.IterableTxnDatastore.
+func (s *Store) NewIterableTransaction(ctx context.Context, readOnly bool) (iterable.IterableTxn, error) {
+	t, err := s.main.NewTransaction(ctx, readOnly)
+	if err != nil {
+		return nil, err
+	}
+	branchTxn := s.newTxn(t)
+	return &iterableTxn{txn: branchTxn, Iterable: iterable.NewIterable(branchTxn)}, nil
+}
+
+func (s *Store) newTxn(t ds.Txn) *txn {
+	return &txn{
+		Datastore: ktds.Wrap(txnDatastore{t}, s.Datastore.KeyTransform),
+		txn:       t,
+		main:      s.main,
+	}
+}
+
+func get(ctx context.Context, reader ds.Read, main ds.Read, key ds.Key) ([]byte, error) {
+	value, err := reader.Get(ctx, key)
+	if !errors.Is(err, ds.ErrNotFound) || !isBlockKey(key) {
+		return value, err
+	}
+	return main.Get(ctx, key)
+}
+
+func has(ctx context.Context, reader ds.Read, main ds.Read, key ds.Key) (bool, error) {
+	exists, err := reader.Has(ctx, key)
+	if err != nil || exists || !isBlockKey(key) {
+		return exists, err
+	}
+	return main.Has(ctx, key)
+}
+
+func getSize(ctx context.Context, reader ds.Read, main ds.Read, key ds.Key) (int, error) {
+	size, err := reader.GetSize(ctx, key)
+	if !errors.Is(err, ds.ErrNotFound) || !isBlockKey(key) {
+		return size, err
+	}
+	return main.GetSize(ctx, key)
+}
+
+func isBlockKey(key ds.Key) bool {
+	return datastore.BlockStorePrefix().IsAncestorOf(key)
+}
+
+// txn is a transaction of a branch, reading the blocks the branch doesn't have from the main
+// rootstore.
+//
+// The blocks of the main rootstore are read outside of the transaction, as they are immutable.
+type txn struct {
+	*ktds.Datastore
+
+	txn  ds.Txn
+	main ds.Read
+}
+
+var _ ds.Txn = (*txn)(nil)
+
+func (t *txn) Get(ctx context.Context, key ds.Key) ([]byte, error) {
+	return get(ctx, t.Datastore, t.main, key)
+}
+
+func (t *txn) Has(ctx context.Context, key ds.Key) (bool, error) {
+	return has(ctx, t.Datastore, t.main, key)
+}
+
+func (t *txn) GetSize(ctx context.Context, key ds.Key) (int, error) {
+	return getSize(ctx, t.Datastore, t.main, key)
+}
+
+func (t *txn) Commit(ctx context.Context) error {
+	return t.txn.Commit(ctx)
+}
+
+func (t *txn) Discard(ctx context.Context) {
+	t.txn.Discard(ctx)
+}
+
+type iterableTxn struct {
+	*txn
+	iterable.Iterable
+}
+
+// txnDatastore adapts a transaction of the main rootstore so that it can be wrapped with the key
+// transform of a branch.
+type txnDatastore struct {
+	ds.Txn
+}
+
+func (txnDatastore) Sync(context.Context, ds.Key) error {
+	return nil
+}
+
+func (txnDatastore) Close() error {
+	return nil
+}
@@ -0,0 +1,109 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package branch
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	badger "github.com/dgraph-io/badger/v3"
+	ds "github.com/ipfs/go-datastore"
+	dsq "github.com/ipfs/go-datastore/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/datastore"
+	badgerds "github.com/sourcenetwork/defradb/datastore/badger/v3"
+)
+
+func newTestRootstore(t *testing.T) datastore.RootStore {
+	opts := badgerds.Options{Options: badger.DefaultOptions("").WithInMemory(true)}
+	rootstore, err := badgerds.NewDatastore("", &opts)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, rootstore.Close()) })
+	return rootstore
+}
+
+func TestBranchStore(t *testing.T) {
+	ctx := context.Background()
+	root := newTestRootstore(t)
+	dataKey := ds.NewKey("/db/data/1")
+	blockKey := datastore.BlockStorePrefix().ChildString("block")
+	require.NoError(t, root.Put(ctx, dataKey, []byte("main")))
+	require.NoError(t, root.Put(ctx, blockKey, []byte("block")))
+
+	now := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	info, err := Create(ctx, root, "dev", now)
+	require.NoError(t, err)
+	assert.Equal(t, Info{Name: "dev", CreatedAt: now}, info)
+
+	store := Open(root, "dev")
+	value, err := store.Get(ctx, dataKey)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("main"), value)
+
+	// The blocks are read from the main rootstore, but not returned by the queries.
+	value, err = store.Get(ctx, blockKey)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("block"), value)
+	results, err := store.Query(ctx, dsq.Query{Prefix: datastore.BlockStorePrefix().String()})
+	require.NoError(t, err)
+	entries, err := results.Rest()
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	// The writes made through a transaction of the branch are not visible to the main rootstore.
+	txn, err := store.NewTransaction(ctx, false)
+	require.NoError(t, err)
+	require.NoError(t, txn.Put(ctx, dataKey, []byte("dev")))
+	require.NoError(t, txn.Commit(ctx))
+	value, err = root.Get(ctx, dataKey)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("main"), value)
+	value, err = store.Get(ctx, dataKey)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("dev"), value)
+
+	branches, err := List(ctx, root)
+	require.NoError(t, err)
+	assert.Equal(t, []Info{info}, branches)
+
+	require.NoError(t, Discard(ctx, root, "dev"))
+	_, err = Get(ctx, root, "dev")
+	assert.ErrorIs(t, err, ErrBranchNotFound)
+	results, err = root.Query(ctx, dsq.Query{Prefix: "/branches", KeysOnly: true})
+	require.NoError(t, err)
+	entries, err = results.Rest()
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestCreateBranchRemovesKeysLeftByFailedDiscard(t *testing.T) {
+	ctx := context.Background()
+	root := newTestRootstore(t)
+	staleKey := ds.NewKey("/db/data/stale")
+	require.NoError(t, root.Put(ctx, dataKey.ChildString("dev").Child(staleKey), []byte("stale")))
+
+	_, err := Create(ctx, root, "dev", time.Now())
+	require.NoError(t, err)
+
+	_, err = Open(root, "dev").Get(ctx, staleKey)
+	assert.ErrorIs(t, err, ds.ErrNotFound)
+}
+
+func TestDiscardUnknownBranch(t *testing.T) {
+	ctx := context.Background()
+	root := newTestRootstore(t)
+
+	err := Discard(ctx, root, "dev")
+	assert.ErrorIs(t, err, ErrBranchNotFound)
+}
@@ -0,0 +1,42 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package branch
+
+import (
+	"github.com/sourcenetwork/defradb/errors"
+)
+
+const (
+	errInvalidBranchName string = "invalid branch name"
+	errBranchExists      string = "branch already exists"
+	errBranchNotFound    string = "branch not found"
+)
+
+var (
+	ErrInvalidBranchName = errors.New(errInvalidBranchName)
+	ErrBranchExists      = errors.New(errBranchExists)
+	ErrBranchNotFound    = errors.New(errBranchNotFound)
+)
+
+// NewErrInvalidBranchName returns a new error indicating that the given branch name is invalid.
+func NewErrInvalidBranchName(name string) error {
+	return errors.New(errInvalidBranchName, errors.NewKV("Name", name))
+}
+
+// NewErrBranchExists returns a new error indicating that a branch with the given name exists.
+func NewErrBranchExists(name string) error {
+	return errors.New(errBranchExists, errors.NewKV("Name", name))
+}
+
+// NewErrBranchNotFound returns a new error indicating that no branch with the given name exists.
+func NewErrBranchNotFound(name string) error {
+	return errors.New(errBranchNotFound, errors.NewKV("Name", name))
+}
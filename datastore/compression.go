@@ -0,0 +1,63 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package datastore
+
+import (
+	"bytes"
+	"sync/atomic"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// zstdMagic is the magic number starting every zstd frame. It never starts an encoded block, the
+// first byte of which is a CBOR map or a protobuf field tag, so that compressed blocks are told
+// apart from uncompressed ones.
+var zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+
+var (
+	// The encoder and decoder are safe for concurrent use through EncodeAll and DecodeAll.
+	zstdEncoder, _ = zstd.NewWriter(nil)
+	zstdDecoder, _ = zstd.NewReader(nil)
+)
+
+// compressBlocks is set when the blocks are written compressed to the blockstore.
+var compressBlocks atomic.Bool
+
+// SetBlockCompression sets whether the blocks are written compressed to the blockstore.
+//
+// Blocks are decompressed on read whatever the setting, so that a blockstore holding both
+// compressed and uncompressed blocks remains readable after the setting changes.
+func SetBlockCompression(enabled bool) {
+	compressBlocks.Store(enabled)
+}
+
+// CompressBlock returns the zstd compressed data of a block.
+func CompressBlock(data []byte) []byte {
+	return zstdEncoder.EncodeAll(data, make([]byte, 0, len(data)))
+}
+
+// DecompressBlock returns the data of a block compressed with CompressBlock. The data is
+// returned as is if it is not compressed.
+func DecompressBlock(data []byte) ([]byte, error) {
+	if !IsCompressedBlock(data) {
+		return data, nil
+	}
+	decompressed, err := zstdDecoder.DecodeAll(data, nil)
+	if err != nil {
+		return nil, NewErrInvalidCompressedBlock(err)
+	}
+	return decompressed, nil
+}
+
+// IsCompressedBlock returns true if the given data is a block compressed with CompressBlock.
+func IsCompressedBlock(data []byte) bool {
+	return bytes.HasPrefix(data, zstdMagic)
+}
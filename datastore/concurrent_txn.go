@@ -56,6 +56,7 @@ func NewConcurrentTxnFrom(ctx context.Context, rootstore ds.TxnDatastore, readon
 		multistore,
 		[]func(){},
 		[]func(){},
+		false,
 	}, nil
 }
 
@@ -11,9 +11,16 @@
 package datastore
 
 import (
+	badgerds "github.com/sourcenetwork/defradb/datastore/badger/v3"
+	"github.com/sourcenetwork/defradb/datastore/memory"
 	"github.com/sourcenetwork/defradb/errors"
 )
 
+const (
+	errTxnConflict            string = "transaction conflict"
+	errInvalidCompressedBlock string = "invalid compressed block"
+)
+
 // Errors returnable from this package.
 //
 // This list is incomplete and undefined errors may also be returned.
@@ -25,4 +32,31 @@ var (
 	// ipfs-blockstore.ErrNotFound => error
 	// ErrNotFound is an error returned when a block is not found.
 	ErrNotFound = errors.New("blockstore: block not found")
+	// ErrReadOnlyTxn is an error returned when writing to a read only transaction.
+	ErrReadOnlyTxn = errors.New("read only transaction")
+	// ErrTxnConflict is an error returned when a transaction can't be committed because it
+	// conflicts with another transaction. The transaction may succeed if retried.
+	ErrTxnConflict = errors.WithCode(errors.CodeTransactionConflict, errors.New(errTxnConflict))
+	// ErrInvalidCompressedBlock is an error returned when a compressed block can't be decompressed.
+	ErrInvalidCompressedBlock = errors.New(errInvalidCompressedBlock)
 )
+
+// NewErrTxnConflict returns an error indicating that a transaction conflicts with another
+// transaction, wrapping the conflict error of the underlying datastore.
+func NewErrTxnConflict(inner error) error {
+	return errors.Wrap(errTxnConflict, inner)
+}
+
+// NewErrInvalidCompressedBlock returns an error indicating that a compressed block can't be
+// decompressed.
+func NewErrInvalidCompressedBlock(inner error) error {
+	return errors.Wrap(errInvalidCompressedBlock, inner)
+}
+
+// IsTxnConflict returns true if the given error is a transaction conflict error, whether from
+// this package or from one of the supported underlying datastores.
+func IsTxnConflict(err error) bool {
+	return errors.Is(err, ErrTxnConflict) ||
+		errors.Is(err, badgerds.ErrTxnConflict) ||
+		errors.Is(err, memory.ErrTxnConflict)
+}
@@ -57,36 +57,19 @@ func (shim *iteratorShim) IteratePrefix(
 	}
 
 	query := shim.q
-	// If the prefix range only covers one prefix then we don't have to do the
-	// horrible work-around in the else clause
-	if prefixEnd(startPrefix) == endPrefix {
-		query.Prefix = startPrefix.String()
-		results, err := shim.readable.Query(ctx, query)
-		if err != nil {
-			return nil, err
-		}
-		shim.results = results
-	} else {
-		startBytes := startPrefix.Bytes()
-		endBytes := endPrefix.Bytes()
-		lastSharedIndex := 0
-		for i := 0; i < len(startBytes) && i < len(endBytes); i++ {
-			if startBytes[i] != endBytes[i] {
-				break
-			}
-			lastSharedIndex += 1
-		}
-		query.Prefix = string(startBytes[:lastSharedIndex])
-		query.Filters = append(query.Filters, betweenFilter{
-			start: startPrefix.String(),
-			end:   endPrefix.String(),
-		})
-		results, err := shim.readable.Query(ctx, query)
-		if err != nil {
-			return nil, err
-		}
-		shim.results = results
+	// Query prefixes only match the keys below them, so the range is queried from the
+	// deepest path shared by both of its ends and filtered by the range itself. This
+	// keeps keys equal to the ends of the range, as done by iterable stores.
+	query.Prefix = sharedPath(startPrefix.String(), endPrefix.String())
+	query.Filters = append(query.Filters, betweenFilter{
+		start: startPrefix.String(),
+		end:   endPrefix.String(),
+	})
+	results, err := shim.readable.Query(ctx, query)
+	if err != nil {
+		return nil, err
 	}
+	shim.results = results
 	return shim.results, nil
 }
 
@@ -106,29 +89,19 @@ func (shim *iteratorShim) Close() error {
 	return shim.results.Close()
 }
 
-var keyMax = string(([]byte{0xff, 0xff}))
-
-// PrefixEnd determines the end key given key as a prefix, that is the
-// key that sorts precisely behind all keys starting with prefix: "1"
-// is added to the final byte and the carry propagated. The special
-// cases of nil and KeyMin always returns KeyMax.
-func prefixEnd(k ds.Key) ds.Key {
-	if len(k.Bytes()) == 0 {
-		return ds.NewKey(keyMax)
-	}
-	return ds.NewKey(string(bytesPrefixEnd(k.Bytes())))
-}
-
-func bytesPrefixEnd(b []byte) []byte {
-	end := make([]byte, len(b))
-	copy(end, b)
-	for i := len(end) - 1; i >= 0; i-- {
-		end[i] = end[i] + 1
-		if end[i] != 0 {
-			return end[:i+1]
+// sharedPath returns the deepest path that is a prefix of both of the given keys.
+func sharedPath(a string, b string) string {
+	lastSeparator := 0
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			break
+		}
+		if a[i] == '/' {
+			lastSeparator = i
 		}
 	}
-	// This statement will only be reached if the key is already a
-	// maximal byte string (i.e. already \xff...).
-	return b
+	if lastSeparator == 0 {
+		return "/"
+	}
+	return a[:lastSeparator]
 }
@@ -23,6 +23,21 @@ var (
 	blockStoreKey  = rootStoreKey.ChildString("/blocks")
 )
 
+// DataStorePrefix returns the prefix of the keys of the datastore within the rootstore.
+func DataStorePrefix() ds.Key {
+	return dataStoreKey
+}
+
+// HeadStorePrefix returns the prefix of the keys of the headstore within the rootstore.
+func HeadStorePrefix() ds.Key {
+	return headStoreKey
+}
+
+// BlockStorePrefix returns the prefix of the keys of the blockstore within the rootstore.
+func BlockStorePrefix() ds.Key {
+	return blockStoreKey
+}
+
 type multistore struct {
 	root   DSReaderWriter
 	data   DSReaderWriter
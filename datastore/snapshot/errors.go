@@ -0,0 +1,84 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package snapshot
+
+import (
+	"github.com/ipfs/go-cid"
+
+	"github.com/sourcenetwork/defradb/errors"
+)
+
+const (
+	errDatastoreNotEmpty   string = "the datastore already holds a database"
+	errInvalidArchive      string = "invalid snapshot archive"
+	errEntryTooLarge       string = "snapshot archive entry too large"
+	errEntryCountMismatch  string = "snapshot archive entry count mismatch"
+	errNoBaseDatabase      string = "the datastore holds no database to apply the incremental snapshot to"
+	errInvalidManifest     string = "invalid snapshot manifest"
+	errUnknownReplaySchema string = "the schema of the replayed commit is unknown to the database"
+	errInvalidReplayCommit string = "invalid composite commit in the replayed history"
+)
+
+var (
+	ErrDatastoreNotEmpty   = errors.New(errDatastoreNotEmpty)
+	ErrInvalidArchive      = errors.New(errInvalidArchive)
+	ErrEntryTooLarge       = errors.New(errEntryTooLarge)
+	ErrEntryCountMismatch  = errors.New(errEntryCountMismatch)
+	ErrNoBaseDatabase      = errors.New(errNoBaseDatabase)
+	ErrInvalidManifest     = errors.New(errInvalidManifest)
+	ErrUnknownReplaySchema = errors.New(errUnknownReplaySchema)
+	ErrInvalidReplayCommit = errors.New(errInvalidReplayCommit)
+)
+
+// NewErrInvalidArchive returns a new error indicating that the snapshot archive could not be
+// read, being malformed or truncated.
+func NewErrInvalidArchive(inner error) error {
+	return errors.Wrap(errInvalidArchive, inner)
+}
+
+// NewErrEntryTooLarge returns a new error indicating that an entry of the snapshot archive is
+// too large to be valid.
+func NewErrEntryTooLarge(size uint64) error {
+	return errors.New(errEntryTooLarge, errors.NewKV("Size", size))
+}
+
+// NewErrEntryCountMismatch returns a new error indicating that the number of entries of the
+// snapshot archive does not match the number recorded at its end.
+func NewErrEntryCountMismatch(expected uint64, actual int) error {
+	return errors.New(
+		errEntryCountMismatch,
+		errors.NewKV("Expected", expected),
+		errors.NewKV("Actual", actual),
+	)
+}
+
+// NewErrInvalidManifest returns a new error indicating that a head of the manifest of an
+// incremental snapshot is not a valid CID.
+func NewErrInvalidManifest(inner error) error {
+	return errors.Wrap(errInvalidManifest, inner)
+}
+
+// NewErrUnknownReplaySchema returns a new error indicating that the schema version of a replayed
+// commit is not known to the database, such as when it was added after its snapshot was taken.
+func NewErrUnknownReplaySchema(commit cid.Cid, schemaVersionID string, inner error) error {
+	return errors.Wrap(
+		errUnknownReplaySchema,
+		inner,
+		errors.NewKV("Cid", commit),
+		errors.NewKV("SchemaVersionID", schemaVersionID),
+	)
+}
+
+// NewErrInvalidReplayCommit returns a new error indicating that a commit of the replayed history
+// can't be decoded as a composite commit.
+func NewErrInvalidReplayCommit(commit cid.Cid, inner error) error {
+	return errors.Wrap(errInvalidReplayCommit, inner, errors.NewKV("Cid", commit))
+}
@@ -0,0 +1,317 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package snapshot
+
+import (
+	"context"
+	"io"
+	"sort"
+	"strings"
+
+	dshelp "github.com/ipfs/boxo/datastore/dshelp"
+	dag "github.com/ipfs/boxo/ipld/merkledag"
+	"github.com/ipfs/go-cid"
+	ds "github.com/ipfs/go-datastore"
+	dsq "github.com/ipfs/go-datastore/query"
+
+	"github.com/sourcenetwork/defradb/core"
+	"github.com/sourcenetwork/defradb/datastore"
+)
+
+// Manifest is the set of the heads of the documents archived by a snapshot, from which an
+// incremental snapshot archives the documents changed since.
+type Manifest struct {
+	// Heads are the CIDs of the heads of the fields of each document, by document key.
+	Heads map[string][]string `json:"heads"`
+}
+
+// addHead adds the head of the given headstore key, relative to the headstore, to the manifest.
+func (m Manifest) addHead(key string) error {
+	headKey, err := core.NewHeadStoreKey(key)
+	if err != nil {
+		return err
+	}
+	m.Heads[headKey.DocKey] = append(m.Heads[headKey.DocKey], headKey.Cid.String())
+	return nil
+}
+
+// sort sorts the heads of each document, so that they can be compared.
+func (m Manifest) sort() {
+	for _, heads := range m.Heads {
+		sort.Strings(heads)
+	}
+}
+
+// ReadManifest returns the manifest of the heads archived by the snapshot archive read from the
+// given reader, full or incremental.
+func ReadManifest(r io.Reader) (Manifest, error) {
+	archive, err := newArchiveReader(r)
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	manifest := Manifest{Heads: map[string][]string{}}
+	headsPrefix := datastore.HeadStorePrefix().String()
+	_, err = archive.readEntries(func(key []byte, _ []byte) error {
+		if !strings.HasPrefix(string(key), headsPrefix+"/") {
+			return nil
+		}
+		if err := manifest.addHead(strings.TrimPrefix(string(key), headsPrefix)); err != nil {
+			return NewErrInvalidArchive(err)
+		}
+		return nil
+	})
+	if err != nil {
+		return Manifest{}, err
+	}
+	manifest.sort()
+	return manifest, nil
+}
+
+// WriteIncremental writes an incremental snapshot archive of the given rootstore to the given
+// writer, and returns the number of entries written.
+//
+// The archive holds the data of the documents whose heads differ from those of the given manifest,
+// and the blocks of their DAGs added since. The system keyspace and the heads of all the documents
+// are always archived, so that the manifest of the archive is complete. The blocks that are not
+// part of the DAG of a document are not archived.
+func WriteIncremental(
+	ctx context.Context,
+	rootstore datastore.RootStore,
+	w io.Writer,
+	since Manifest,
+) (int, error) {
+	txn, err := datastore.NewTxnFrom(ctx, rootstore, true)
+	if err != nil {
+		return 0, err
+	}
+	defer txn.Discard(ctx)
+
+	current, err := currentManifest(ctx, txn)
+	if err != nil {
+		return 0, err
+	}
+	changed := map[string]struct{}{}
+	for docKey, heads := range current.Heads {
+		if !sameHeads(heads, since.Heads[docKey]) {
+			changed[docKey] = struct{}{}
+		}
+	}
+	blocks, err := addedBlocks(ctx, txn, current, changed, since)
+	if err != nil {
+		return 0, err
+	}
+
+	results, err := txn.Rootstore().Query(ctx, dsq.Query{Prefix: keyspacePrefix})
+	if err != nil {
+		return 0, err
+	}
+	defer closeResults(ctx, results)
+
+	dataPrefix := datastore.DataStorePrefix().String()
+	blocksPrefix := datastore.BlockStorePrefix().String()
+	return writeArchive(w, incrementalHeader, results, func(key string) bool {
+		switch {
+		case strings.HasPrefix(key, blocksPrefix+"/"):
+			_, ok := blocks[key]
+			return ok
+
+		case strings.HasPrefix(key, dataPrefix+"/"):
+			dataKey, err := core.NewDataStoreKey(strings.TrimPrefix(key, dataPrefix))
+			if err != nil {
+				// The entries of the datastore that are not document data are always archived.
+				return true
+			}
+			_, ok := changed[dataKey.DocKey]
+			return ok
+
+		default:
+			return true
+		}
+	})
+}
+
+// currentManifest returns the manifest of the heads of the documents of the given transaction.
+func currentManifest(ctx context.Context, txn datastore.Txn) (Manifest, error) {
+	results, err := txn.Headstore().Query(ctx, dsq.Query{KeysOnly: true})
+	if err != nil {
+		return Manifest{}, err
+	}
+	defer closeResults(ctx, results)
+
+	manifest := Manifest{Heads: map[string][]string{}}
+	for result := range results.Next() {
+		if result.Error != nil {
+			return Manifest{}, result.Error
+		}
+		if err := manifest.addHead(result.Key); err != nil {
+			return Manifest{}, err
+		}
+	}
+	manifest.sort()
+	return manifest, nil
+}
+
+// addedBlocks returns the rootstore keys of the blocks of the DAGs of the given changed documents
+// that are not reachable from the heads of the given manifest.
+func addedBlocks(
+	ctx context.Context,
+	txn datastore.Txn,
+	current Manifest,
+	changed map[string]struct{},
+	since Manifest,
+) (map[string]struct{}, error) {
+	visited := map[cid.Cid]struct{}{}
+	for _, heads := range since.Heads {
+		for _, head := range heads {
+			c, err := cid.Decode(head)
+			if err != nil {
+				return nil, NewErrInvalidManifest(err)
+			}
+			// The blocks reachable from the heads of the manifest have been archived already.
+			visited[c] = struct{}{}
+		}
+	}
+
+	queue := []cid.Cid{}
+	for docKey := range changed {
+		for _, head := range current.Heads[docKey] {
+			c, err := cid.Decode(head)
+			if err != nil {
+				return nil, err
+			}
+			queue = append(queue, c)
+		}
+	}
+
+	blocks := map[string]struct{}{}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if _, ok := visited[current]; ok {
+			continue
+		}
+		visited[current] = struct{}{}
+
+		block, err := txn.DAGstore().Get(ctx, current)
+		if err != nil {
+			return nil, err
+		}
+		nd, err := dag.DecodeProtobuf(block.RawData())
+		if err != nil {
+			return nil, err
+		}
+		blocks[datastore.BlockStorePrefix().Child(dshelp.MultihashToDsKey(current.Hash())).String()] = struct{}{}
+		for _, link := range nd.Links() {
+			queue = append(queue, link.Cid)
+		}
+	}
+	return blocks, nil
+}
+
+// sameHeads returns true if the given sorted heads are the same.
+func sameHeads(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// incrementalRestore keeps track of the entries of an incremental archive being restored, to
+// delete the entries of the datastore that the archive replaces.
+type incrementalRestore struct {
+	// keys are the archived keys of the data and heads keyspaces.
+	keys map[string]struct{}
+	// headDocs are the keys of the documents with archived heads, which are all the documents.
+	headDocs map[string]struct{}
+	// dataDocs are the keys of the documents with archived data, which are the changed documents.
+	dataDocs map[string]struct{}
+}
+
+func newIncrementalRestore() *incrementalRestore {
+	return &incrementalRestore{
+		keys:     map[string]struct{}{},
+		headDocs: map[string]struct{}{},
+		dataDocs: map[string]struct{}{},
+	}
+}
+
+// add tracks the given archived key.
+func (r *incrementalRestore) add(key string) {
+	dataPrefix := datastore.DataStorePrefix().String()
+	headsPrefix := datastore.HeadStorePrefix().String()
+	switch {
+	case strings.HasPrefix(key, dataPrefix+"/"):
+		r.keys[key] = struct{}{}
+		if dataKey, err := core.NewDataStoreKey(strings.TrimPrefix(key, dataPrefix)); err == nil {
+			r.dataDocs[dataKey.DocKey] = struct{}{}
+		}
+
+	case strings.HasPrefix(key, headsPrefix+"/"):
+		r.keys[key] = struct{}{}
+		if headKey, err := core.NewHeadStoreKey(strings.TrimPrefix(key, headsPrefix)); err == nil {
+			r.headDocs[headKey.DocKey] = struct{}{}
+		}
+	}
+}
+
+// deleteStale deletes, with the given batch, the heads of the rootstore that are not archived,
+// and the data of the changed documents and of the documents without archived heads that is not
+// archived.
+func (r *incrementalRestore) deleteStale(ctx context.Context, rootstore datastore.RootStore, batch ds.Batch) error {
+	heads, err := rootstore.Query(ctx, dsq.Query{Prefix: datastore.HeadStorePrefix().String(), KeysOnly: true})
+	if err != nil {
+		return err
+	}
+	defer closeResults(ctx, heads)
+	for result := range heads.Next() {
+		if result.Error != nil {
+			return result.Error
+		}
+		if _, ok := r.keys[result.Key]; !ok {
+			if err := batch.Delete(ctx, ds.RawKey(result.Key)); err != nil {
+				return err
+			}
+		}
+	}
+
+	dataPrefix := datastore.DataStorePrefix().String()
+	data, err := rootstore.Query(ctx, dsq.Query{Prefix: dataPrefix, KeysOnly: true})
+	if err != nil {
+		return err
+	}
+	defer closeResults(ctx, data)
+	for result := range data.Next() {
+		if result.Error != nil {
+			return result.Error
+		}
+		if _, ok := r.keys[result.Key]; ok {
+			continue
+		}
+		dataKey, err := core.NewDataStoreKey(strings.TrimPrefix(result.Key, dataPrefix))
+		if err != nil {
+			continue
+		}
+		_, changed := r.dataDocs[dataKey.DocKey]
+		_, exists := r.headDocs[dataKey.DocKey]
+		if changed || !exists {
+			if err := batch.Delete(ctx, ds.RawKey(result.Key)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,306 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package snapshot
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/ipfs/boxo/blockservice"
+	blockstore "github.com/ipfs/boxo/blockstore"
+	"github.com/ipfs/boxo/exchange/offline"
+	dag "github.com/ipfs/boxo/ipld/merkledag"
+	"github.com/ipfs/go-cid"
+	dsq "github.com/ipfs/go-datastore/query"
+	ipld "github.com/ipfs/go-ipld-format"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/core"
+	corecrdt "github.com/sourcenetwork/defradb/core/crdt"
+	"github.com/sourcenetwork/defradb/datastore"
+)
+
+// ReplayTarget is the point in the history of the documents up to which [Replay] merges their
+// commits.
+type ReplayTarget struct {
+	// Until is the time up to which the commits are merged, by the time at which they were
+	// committed to the store of the history. There is no bound on the time if it is zero.
+	Until time.Time
+
+	// Height is the height up to which the commits are merged. There is no bound on the height
+	// if it is zero.
+	Height uint64
+}
+
+// ReplayResult is the result of [Replay].
+type ReplayResult struct {
+	// Documents is the number of documents into which commits have been merged.
+	Documents int
+
+	// Commits is the number of commits merged, each with the blocks of its DAG the database
+	// didn't have.
+	Commits int
+
+	// Blocks is the number of blocks merged.
+	Blocks int
+}
+
+// Replay merges the commits of the documents of the given history rootstore, such as one restored
+// from a snapshot taken after a bad bulk update, up to the given target into the given database,
+// typically restored from an earlier snapshot.
+//
+// The commits of a document merged are the latest ones committed at or before the time of the
+// target, or the heads if it has no time, replaced by their latest ancestors at or below the
+// height of the target, if it has one. The commits the database already has are left as is, so
+// that the database can't be rewound before the snapshot it was restored from.
+//
+// The schemas of the documents must be known to the database, the schema updates of the history
+// are not replayed.
+func Replay(
+	ctx context.Context,
+	d client.DB,
+	history datastore.RootStore,
+	target ReplayTarget,
+) (ReplayResult, error) {
+	txn, err := datastore.NewTxnFrom(ctx, history, true)
+	if err != nil {
+		return ReplayResult{}, err
+	}
+	defer txn.Discard(ctx)
+
+	heads, err := compositeHeads(ctx, txn)
+	if err != nil {
+		return ReplayResult{}, err
+	}
+	docKeys := make([]string, 0, len(heads))
+	for docKey := range heads {
+		docKeys = append(docKeys, docKey)
+	}
+	sort.Strings(docKeys)
+
+	fetcher := dag.NewDAGService(blockservice.New(txn.DAGstore(), offline.Exchange(txn.DAGstore())))
+	result := ReplayResult{}
+	for _, docKey := range docKeys {
+		commits := heads[docKey]
+		if !target.Until.IsZero() {
+			commits, err = commitsUntil(ctx, txn, docKey, target.Until)
+			if err != nil {
+				return ReplayResult{}, err
+			}
+		}
+		if target.Height > 0 {
+			commits, err = commitsAtHeight(ctx, fetcher, commits, target.Height)
+			if err != nil {
+				return ReplayResult{}, err
+			}
+		}
+
+		merged := false
+		for _, commit := range commits {
+			blocks, err := replayCommit(ctx, d, fetcher, commit)
+			if err != nil {
+				return ReplayResult{}, err
+			}
+			if blocks == 0 {
+				continue
+			}
+			merged = true
+			result.Commits++
+			result.Blocks += blocks
+		}
+		if merged {
+			result.Documents++
+		}
+	}
+	return result, nil
+}
+
+// compositeHeads returns the heads of the composite DAGs of the documents of the given
+// transaction, by document key.
+func compositeHeads(ctx context.Context, txn datastore.Txn) (map[string][]cid.Cid, error) {
+	results, err := txn.Headstore().Query(ctx, dsq.Query{KeysOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer closeResults(ctx, results)
+
+	heads := map[string][]cid.Cid{}
+	for result := range results.Next() {
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		key, err := core.NewHeadStoreKey(result.Key)
+		if err != nil {
+			return nil, err
+		}
+		if key.FieldId != core.COMPOSITE_NAMESPACE {
+			continue
+		}
+		heads[key.DocKey] = append(heads[key.DocKey], key.Cid)
+	}
+	return heads, nil
+}
+
+// commitsUntil returns the composite commits of the given document committed at or before the
+// given time, in the order they were committed.
+func commitsUntil(ctx context.Context, txn datastore.Txn, docKey string, until time.Time) ([]cid.Cid, error) {
+	prefix := core.CommitTimeKey{DocKey: docKey}
+	results, err := txn.Systemstore().Query(ctx, dsq.Query{
+		Prefix:   prefix.ToString() + "/",
+		KeysOnly: true,
+		Orders:   []dsq.Order{dsq.OrderByKey{}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer closeResults(ctx, results)
+
+	commits := []cid.Cid{}
+	for result := range results.Next() {
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		key, err := core.NewCommitTimeKeyFromString(result.Key)
+		if err != nil {
+			return nil, err
+		}
+		// The keys are ordered by time, so the following commits are all after the time.
+		if key.Time().After(until) {
+			break
+		}
+		commits = append(commits, key.Cid)
+	}
+	return commits, nil
+}
+
+// commitsAtHeight returns the latest composite commits at or below the given height among the
+// given composite commits and their ancestors.
+func commitsAtHeight(
+	ctx context.Context,
+	fetcher ipld.NodeGetter,
+	commits []cid.Cid,
+	height uint64,
+) ([]cid.Cid, error) {
+	result := []cid.Cid{}
+	visited := map[cid.Cid]struct{}{}
+	queue := append([]cid.Cid{}, commits...)
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if _, ok := visited[current]; ok {
+			continue
+		}
+		visited[current] = struct{}{}
+
+		nd, delta, err := getCompositeCommit(ctx, fetcher, current)
+		if err != nil {
+			return nil, err
+		}
+		if delta.Priority <= height {
+			result = append(result, current)
+			continue
+		}
+		for _, link := range nd.Links() {
+			if link.Name == core.HEAD {
+				queue = append(queue, link.Cid)
+			}
+		}
+	}
+	return result, nil
+}
+
+// replayCommit merges the given composite commit into its document, with the blocks of its DAG
+// that the given database doesn't have, and returns the number of blocks merged.
+func replayCommit(ctx context.Context, d client.DB, fetcher ipld.NodeGetter, commit cid.Cid) (int, error) {
+	blocks, err := missingBlocks(ctx, d.Blockstore(), fetcher, commit)
+	if err != nil {
+		return 0, err
+	}
+	if len(blocks) == 0 {
+		return 0, nil
+	}
+
+	_, delta, err := getCompositeCommit(ctx, fetcher, commit)
+	if err != nil {
+		return 0, err
+	}
+	col, err := d.GetCollectionByVersionID(ctx, delta.SchemaVersionID)
+	if err != nil {
+		return 0, NewErrUnknownReplaySchema(commit, delta.SchemaVersionID, err)
+	}
+	if _, err := col.MergeBlocks(ctx, blocks); err != nil {
+		return 0, err
+	}
+	return len(blocks), nil
+}
+
+// missingBlocks returns the data of the blocks of the DAG from the given commit that are not in
+// the given blockstore, fetched with the given fetcher.
+//
+// The ancestors of the stored blocks are not fetched, as the blockstore has them too.
+func missingBlocks(
+	ctx context.Context,
+	bs blockstore.Blockstore,
+	fetcher ipld.NodeGetter,
+	commit cid.Cid,
+) ([][]byte, error) {
+	blocks := [][]byte{}
+	visited := map[cid.Cid]struct{}{}
+	queue := []cid.Cid{commit}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if _, ok := visited[current]; ok {
+			continue
+		}
+		visited[current] = struct{}{}
+
+		exists, err := bs.Has(ctx, current)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			continue
+		}
+
+		nd, err := fetcher.Get(ctx, current)
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, nd.RawData())
+		for _, link := range nd.Links() {
+			queue = append(queue, link.Cid)
+		}
+	}
+	return blocks, nil
+}
+
+// getCompositeCommit returns the node and the delta of the given composite commit.
+func getCompositeCommit(
+	ctx context.Context,
+	fetcher ipld.NodeGetter,
+	commit cid.Cid,
+) (*dag.ProtoNode, *corecrdt.CompositeDAGDelta, error) {
+	nd, err := fetcher.Get(ctx, commit)
+	if err != nil {
+		return nil, nil, err
+	}
+	protoNode, err := dag.DecodeProtobuf(nd.RawData())
+	if err != nil {
+		return nil, nil, NewErrInvalidReplayCommit(commit, err)
+	}
+	delta, err := corecrdt.CompositeDAG{}.DeltaDecode(protoNode)
+	if err != nil {
+		return nil, nil, NewErrInvalidReplayCommit(commit, err)
+	}
+	return protoNode, delta.(*corecrdt.CompositeDAGDelta), nil
+}
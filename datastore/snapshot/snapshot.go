@@ -0,0 +1,287 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+/*
+Package snapshot produces snapshot archives of the datastore and bootstraps new nodes from them.
+
+A snapshot holds all the keyspaces of the database (system, data, heads and blocks), read from a
+single transaction so that it is consistent. A node bootstrapped from a snapshot starts with the
+state of the database at the time the snapshot was taken, and catches up with the changes made
+since through the P2P synchronization, instead of replaying the whole history block by block.
+
+The snapshot is a physical copy of the datastore, so the fields masked in the exports and the
+subscription payloads are archived in cleartext: the blocks of the DAGs are addressed by the
+hashes of the cleartext values, and a masked archive could neither be restored nor verified.
+Taking a snapshot requires the admin token, and the exports are the way to share the documents.
+
+The peerstore of the node is not part of the snapshot, so that the bootstrapped node keeps its
+own peers. If the cold blocks of the datastore are offloaded to an object storage, only their
+local index is part of the snapshot, and the bootstrapped node must use the same object storage.
+
+The archive is gzip compressed. It starts with a header identifying the format, followed by the
+entries, each being the uvarint length of its key, its key, the uvarint length of its value and
+its value. The entries are terminated by a zero key length, followed by the uvarint number of
+entries, so that truncated archives are detected.
+
+An incremental snapshot only holds the documents whose heads have changed since a previous
+snapshot, given by the manifest of the heads it archived, along with the blocks added to their DAGs
+since. It is restored on top of the database restored from the snapshots it follows. It has its own
+header, and the same entries format.
+
+A database restored from a snapshot can be brought to a later point in time, such as right before
+a bad bulk update, by replaying the commits of the history of a later snapshot up to a given time or
+height, merging them like the commits received from other peers.
+*/
+package snapshot
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/binary"
+	"io"
+
+	ds "github.com/ipfs/go-datastore"
+	dsq "github.com/ipfs/go-datastore/query"
+
+	"github.com/sourcenetwork/defradb/datastore"
+	"github.com/sourcenetwork/defradb/logging"
+)
+
+var log = logging.MustNewLogger("defra.datastore.snapshot")
+
+// header identifies the format, and its version, of the archive.
+const header = "DEFRADB-SNAPSHOT-1\n"
+
+// incrementalHeader identifies the format, and its version, of the incremental archive.
+const incrementalHeader = "DEFRADB-SNAPSHOT-INCREMENTAL-1\n"
+
+// keyspacePrefix is the prefix of the keys of all the keyspaces of the database.
+const keyspacePrefix = "/db"
+
+// maxEntrySize is the maximum size of the key or value of an entry, beyond which the archive is
+// considered corrupted.
+const maxEntrySize = 1 << 30
+
+// Write writes a snapshot archive of the given rootstore to the given writer, and returns the
+// number of entries written.
+func Write(ctx context.Context, rootstore datastore.RootStore, w io.Writer) (int, error) {
+	txn, err := rootstore.NewTransaction(ctx, true)
+	if err != nil {
+		return 0, err
+	}
+	defer txn.Discard(ctx)
+
+	results, err := txn.Query(ctx, dsq.Query{Prefix: keyspacePrefix})
+	if err != nil {
+		return 0, err
+	}
+	defer closeResults(ctx, results)
+
+	return writeArchive(w, header, results, nil)
+}
+
+// Restore writes the entries of the snapshot archive read from the given reader to the given
+// rootstore, and returns the number of entries restored.
+//
+// The rootstore must not hold a database yet, unless the archive is an incremental one, which is
+// applied on top of the database restored from the archives it follows. If the restore fails, the
+// entries restored so far are not removed, and the rootstore must be emptied before restoring
+// again.
+func Restore(ctx context.Context, rootstore datastore.RootStore, r io.Reader) (int, error) {
+	existing, err := rootstore.Query(ctx, dsq.Query{Prefix: keyspacePrefix, KeysOnly: true, Limit: 1})
+	if err != nil {
+		return 0, err
+	}
+	entries, err := existing.Rest()
+	if err != nil {
+		return 0, err
+	}
+
+	archive, err := newArchiveReader(r)
+	if err != nil {
+		return 0, err
+	}
+	if !archive.incremental && len(entries) > 0 {
+		return 0, ErrDatastoreNotEmpty
+	}
+	if archive.incremental && len(entries) == 0 {
+		return 0, ErrNoBaseDatabase
+	}
+
+	batch, err := rootstore.Batch(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var applied *incrementalRestore
+	if archive.incremental {
+		applied = newIncrementalRestore()
+	}
+	count, err := archive.readEntries(func(key []byte, value []byte) error {
+		if applied != nil {
+			applied.add(string(key))
+		}
+		return batch.Put(ctx, datastoreKey(key), value)
+	})
+	if err != nil {
+		return count, err
+	}
+	if applied != nil {
+		if err := applied.deleteStale(ctx, rootstore, batch); err != nil {
+			return count, err
+		}
+	}
+	return count, batch.Commit(ctx)
+}
+
+// writeArchive writes an archive with the given header holding the given results for which
+// include returns true, or all of them if it is nil, and returns the number of entries written.
+func writeArchive(w io.Writer, hdr string, results dsq.Results, include func(key string) bool) (int, error) {
+	gz := gzip.NewWriter(w)
+	bw := bufio.NewWriter(gz)
+	if _, err := bw.WriteString(hdr); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for result := range results.Next() {
+		if result.Error != nil {
+			return count, result.Error
+		}
+		if include != nil && !include(result.Key) {
+			continue
+		}
+		if err := writeBytes(bw, []byte(result.Key)); err != nil {
+			return count, err
+		}
+		if err := writeBytes(bw, result.Value); err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	if err := writeUvarint(bw, 0); err != nil {
+		return count, err
+	}
+	if err := writeUvarint(bw, uint64(count)); err != nil {
+		return count, err
+	}
+	if err := bw.Flush(); err != nil {
+		return count, err
+	}
+	return count, gz.Close()
+}
+
+// archiveReader reads the entries of an archive, once its header has been read.
+type archiveReader struct {
+	br *bufio.Reader
+	// incremental is true if the archive is an incremental one.
+	incremental bool
+}
+
+// newArchiveReader returns a reader of the archive read from the given reader, having read its
+// header.
+func newArchiveReader(r io.Reader) (*archiveReader, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, NewErrInvalidArchive(err)
+	}
+	br := bufio.NewReader(gz)
+
+	// The full header is shorter than the incremental one, and is read first.
+	readHeader := make([]byte, len(header))
+	if _, err := io.ReadFull(br, readHeader); err != nil {
+		return nil, NewErrInvalidArchive(err)
+	}
+	if string(readHeader) == header {
+		return &archiveReader{br: br}, nil
+	}
+	if string(readHeader) != incrementalHeader[:len(header)] {
+		return nil, NewErrInvalidArchive(nil)
+	}
+	rest := make([]byte, len(incrementalHeader)-len(header))
+	if _, err := io.ReadFull(br, rest); err != nil || string(rest) != incrementalHeader[len(header):] {
+		return nil, NewErrInvalidArchive(err)
+	}
+	return &archiveReader{br: br, incremental: true}, nil
+}
+
+// readEntries calls the given function with the key and value of each entry of the archive, and
+// returns the number of entries read.
+func (a *archiveReader) readEntries(fn func(key []byte, value []byte) error) (int, error) {
+	count := 0
+	for {
+		key, err := readBytes(a.br)
+		if err != nil {
+			return count, NewErrInvalidArchive(err)
+		}
+		if len(key) == 0 {
+			break
+		}
+		value, err := readBytes(a.br)
+		if err != nil {
+			return count, NewErrInvalidArchive(err)
+		}
+		if err := fn(key, value); err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	expected, err := binary.ReadUvarint(a.br)
+	if err != nil {
+		return count, NewErrInvalidArchive(err)
+	}
+	if expected != uint64(count) {
+		return count, NewErrEntryCountMismatch(expected, count)
+	}
+	return count, nil
+}
+
+func closeResults(ctx context.Context, results dsq.Results) {
+	if err := results.Close(); err != nil {
+		log.ErrorE(ctx, "Failed to close snapshot query", err)
+	}
+}
+
+// datastoreKey returns the rootstore key of the given archived key, as is.
+func datastoreKey(key []byte) ds.Key {
+	return ds.RawKey(string(key))
+}
+
+func writeUvarint(w *bufio.Writer, value uint64) error {
+	buf := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(buf, value)
+	_, err := w.Write(buf[:n])
+	return err
+}
+
+func writeBytes(w *bufio.Writer, value []byte) error {
+	if err := writeUvarint(w, uint64(len(value))); err != nil {
+		return err
+	}
+	_, err := w.Write(value)
+	return err
+}
+
+func readBytes(r *bufio.Reader) ([]byte, error) {
+	size, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	if size > maxEntrySize {
+		return nil, NewErrEntryTooLarge(size)
+	}
+	value := make([]byte, size)
+	if _, err := io.ReadFull(r, value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
@@ -0,0 +1,288 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	badger "github.com/dgraph-io/badger/v3"
+	ds "github.com/ipfs/go-datastore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/datastore"
+	badgerds "github.com/sourcenetwork/defradb/datastore/badger/v3"
+	"github.com/sourcenetwork/defradb/db"
+)
+
+func newTestRootstore(t *testing.T) datastore.RootStore {
+	opts := badgerds.Options{Options: badger.DefaultOptions("").WithInMemory(true)}
+	rootstore, err := badgerds.NewDatastore("", &opts)
+	require.NoError(t, err)
+	return rootstore
+}
+
+// newTestSnapshot returns a snapshot archive of a database holding an updated document.
+func newTestSnapshot(t *testing.T, ctx context.Context) []byte {
+	rootstore := newTestRootstore(t)
+	defra, err := db.NewDB(ctx, rootstore)
+	require.NoError(t, err)
+	defer defra.Close(ctx)
+
+	err = defra.AddSchema(ctx, `type User { name: String }`)
+	require.NoError(t, err)
+	col, err := defra.GetCollectionByName(ctx, "User")
+	require.NoError(t, err)
+	doc, err := client.NewDocFromJSON([]byte(`{"name": "John"}`))
+	require.NoError(t, err)
+	require.NoError(t, col.Create(ctx, doc))
+	require.NoError(t, doc.Set("name", "Fred"))
+	require.NoError(t, col.Update(ctx, doc))
+
+	// The peerstore is not part of the snapshot.
+	require.NoError(t, rootstore.Put(ctx, ds.NewKey("/peers/addrs/1"), []byte("/ip4/127.0.0.1")))
+
+	var buf bytes.Buffer
+	count, err := Write(ctx, rootstore, &buf)
+	require.NoError(t, err)
+	assert.Greater(t, count, 0)
+	return buf.Bytes()
+}
+
+func TestRestoreBootstrapsDatabase(t *testing.T) {
+	ctx := context.Background()
+	archive := newTestSnapshot(t, ctx)
+
+	rootstore := newTestRootstore(t)
+	_, err := Restore(ctx, rootstore, bytes.NewReader(archive))
+	require.NoError(t, err)
+
+	exists, err := rootstore.Has(ctx, ds.NewKey("/peers/addrs/1"))
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	defra, err := db.NewDB(ctx, rootstore)
+	require.NoError(t, err)
+	defer defra.Close(ctx)
+
+	result := defra.ExecRequest(ctx, `query { User { name } }`)
+	require.Empty(t, result.GQL.Errors)
+	assert.Equal(t, []map[string]any{{"name": "Fred"}}, result.GQL.Data)
+
+	result = defra.ExecRequest(ctx, `query { commits(field: "C") { height } }`)
+	require.Empty(t, result.GQL.Errors)
+	assert.Len(t, result.GQL.Data, 2)
+
+	col, err := defra.GetCollectionByName(ctx, "User")
+	require.NoError(t, err)
+	doc, err := client.NewDocFromJSON([]byte(`{"name": "Islam"}`))
+	require.NoError(t, err)
+	require.NoError(t, col.Create(ctx, doc))
+}
+
+func TestRestoreIntoDatabase(t *testing.T) {
+	ctx := context.Background()
+	archive := newTestSnapshot(t, ctx)
+
+	rootstore := newTestRootstore(t)
+	defra, err := db.NewDB(ctx, rootstore)
+	require.NoError(t, err)
+	err = defra.AddSchema(ctx, `type User { name: String }`)
+	require.NoError(t, err)
+
+	_, err = Restore(ctx, rootstore, bytes.NewReader(archive))
+	require.ErrorIs(t, err, ErrDatastoreNotEmpty)
+}
+
+func TestRestoreTruncatedArchive(t *testing.T) {
+	ctx := context.Background()
+	archive := newTestSnapshot(t, ctx)
+
+	_, err := Restore(ctx, newTestRootstore(t), bytes.NewReader(archive[:len(archive)/2]))
+	require.ErrorIs(t, err, ErrInvalidArchive)
+}
+
+func TestRestoreInvalidArchive(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := Restore(ctx, newTestRootstore(t), bytes.NewReader([]byte("not a snapshot")))
+	require.ErrorIs(t, err, ErrInvalidArchive)
+}
+
+func TestRestoreIncrementalSnapshots(t *testing.T) {
+	ctx := context.Background()
+	rootstore := newTestRootstore(t)
+	defra, err := db.NewDB(ctx, rootstore)
+	require.NoError(t, err)
+	defer defra.Close(ctx)
+
+	err = defra.AddSchema(ctx, `type User { name: String }`)
+	require.NoError(t, err)
+	col, err := defra.GetCollectionByName(ctx, "User")
+	require.NoError(t, err)
+	john, err := client.NewDocFromJSON([]byte(`{"name": "John"}`))
+	require.NoError(t, err)
+	require.NoError(t, col.Create(ctx, john))
+	fred, err := client.NewDocFromJSON([]byte(`{"name": "Fred"}`))
+	require.NoError(t, err)
+	require.NoError(t, col.Create(ctx, fred))
+
+	var full bytes.Buffer
+	_, err = Write(ctx, rootstore, &full)
+	require.NoError(t, err)
+	manifest, err := ReadManifest(bytes.NewReader(full.Bytes()))
+	require.NoError(t, err)
+	assert.Len(t, manifest.Heads, 2)
+
+	require.NoError(t, john.Set("name", "Johnny"))
+	require.NoError(t, col.Update(ctx, john))
+
+	var first bytes.Buffer
+	firstCount, err := WriteIncremental(ctx, rootstore, &first, manifest)
+	require.NoError(t, err)
+	manifest, err = ReadManifest(bytes.NewReader(first.Bytes()))
+	require.NoError(t, err)
+	assert.Len(t, manifest.Heads, 2)
+
+	// Nothing has changed since the first incremental snapshot.
+	var unchanged bytes.Buffer
+	unchangedCount, err := WriteIncremental(ctx, rootstore, &unchanged, manifest)
+	require.NoError(t, err)
+	assert.Less(t, unchangedCount, firstCount)
+
+	_, err = col.Delete(ctx, fred.Key())
+	require.NoError(t, err)
+
+	var second bytes.Buffer
+	_, err = WriteIncremental(ctx, rootstore, &second, manifest)
+	require.NoError(t, err)
+
+	restored := newTestRootstore(t)
+	for _, archive := range [][]byte{full.Bytes(), first.Bytes(), second.Bytes()} {
+		_, err := Restore(ctx, restored, bytes.NewReader(archive))
+		require.NoError(t, err)
+	}
+
+	restoredDB, err := db.NewDB(ctx, restored)
+	require.NoError(t, err)
+	defer restoredDB.Close(ctx)
+
+	result := restoredDB.ExecRequest(ctx, `query { User { name } }`)
+	require.Empty(t, result.GQL.Errors)
+	assert.Equal(t, []map[string]any{{"name": "Johnny"}}, result.GQL.Data)
+
+	result = restoredDB.ExecRequest(ctx, `query { commits(field: "C") { height } }`)
+	require.Empty(t, result.GQL.Errors)
+	assert.Len(t, result.GQL.Data, 4)
+}
+
+func TestRestoreIncrementalSnapshotWithoutDatabase(t *testing.T) {
+	ctx := context.Background()
+	rootstore := newTestRootstore(t)
+
+	var archive bytes.Buffer
+	_, err := WriteIncremental(ctx, rootstore, &archive, Manifest{})
+	require.NoError(t, err)
+
+	_, err = Restore(ctx, newTestRootstore(t), bytes.NewReader(archive.Bytes()))
+	require.ErrorIs(t, err, ErrNoBaseDatabase)
+}
+
+func TestWriteIncrementalSnapshotWithInvalidManifest(t *testing.T) {
+	ctx := context.Background()
+
+	var archive bytes.Buffer
+	_, err := WriteIncremental(ctx, newTestRootstore(t), &archive, Manifest{
+		Heads: map[string][]string{"bae-123": {"not a cid"}},
+	})
+	require.ErrorIs(t, err, ErrInvalidManifest)
+}
+
+// newTestReplay returns a database restored from a snapshot of a document named John, and the
+// rootstore of the history of the document, renamed Fred then Bob, with the time in between.
+func newTestReplay(t *testing.T, ctx context.Context) (client.DB, datastore.RootStore, time.Time) {
+	rootstore := newTestRootstore(t)
+	defra, err := db.NewDB(ctx, rootstore)
+	require.NoError(t, err)
+	defer defra.Close(ctx)
+
+	err = defra.AddSchema(ctx, `type User { name: String }`)
+	require.NoError(t, err)
+	col, err := defra.GetCollectionByName(ctx, "User")
+	require.NoError(t, err)
+	doc, err := client.NewDocFromJSON([]byte(`{"name": "John"}`))
+	require.NoError(t, err)
+	require.NoError(t, col.Create(ctx, doc))
+
+	var backup bytes.Buffer
+	_, err = Write(ctx, rootstore, &backup)
+	require.NoError(t, err)
+
+	require.NoError(t, doc.Set("name", "Fred"))
+	require.NoError(t, col.Update(ctx, doc))
+	cutoff := time.Now()
+	require.NoError(t, doc.Set("name", "Bob"))
+	require.NoError(t, col.Update(ctx, doc))
+
+	var archive bytes.Buffer
+	_, err = Write(ctx, rootstore, &archive)
+	require.NoError(t, err)
+	history := newTestRootstore(t)
+	_, err = Restore(ctx, history, bytes.NewReader(archive.Bytes()))
+	require.NoError(t, err)
+
+	restored := newTestRootstore(t)
+	_, err = Restore(ctx, restored, bytes.NewReader(backup.Bytes()))
+	require.NoError(t, err)
+	restoredDB, err := db.NewDB(ctx, restored)
+	require.NoError(t, err)
+	return restoredDB, history, cutoff
+}
+
+func TestReplayUntilTime(t *testing.T) {
+	ctx := context.Background()
+	defra, history, cutoff := newTestReplay(t, ctx)
+	defer defra.Close(ctx)
+
+	result, err := Replay(ctx, defra, history, ReplayTarget{Until: cutoff})
+	require.NoError(t, err)
+	assert.Equal(t, ReplayResult{Documents: 1, Commits: 1, Blocks: 2}, result)
+
+	res := defra.ExecRequest(ctx, `query { User { name } }`)
+	require.Empty(t, res.GQL.Errors)
+	assert.Equal(t, []map[string]any{{"name": "Fred"}}, res.GQL.Data)
+}
+
+func TestReplayAtHeight(t *testing.T) {
+	ctx := context.Background()
+	defra, history, _ := newTestReplay(t, ctx)
+	defer defra.Close(ctx)
+
+	_, err := Replay(ctx, defra, history, ReplayTarget{Height: 2})
+	require.NoError(t, err)
+
+	res := defra.ExecRequest(ctx, `query { User { name } }`)
+	require.Empty(t, res.GQL.Errors)
+	assert.Equal(t, []map[string]any{{"name": "Fred"}}, res.GQL.Data)
+
+	// Replaying the whole history again merges the commits past the height only.
+	result, err := Replay(ctx, defra, history, ReplayTarget{})
+	require.NoError(t, err)
+	assert.Equal(t, ReplayResult{Documents: 1, Commits: 1, Blocks: 2}, result)
+
+	res = defra.ExecRequest(ctx, `query { User { name } }`)
+	require.Empty(t, res.GQL.Errors)
+	assert.Equal(t, []map[string]any{{"name": "Bob"}}, res.GQL.Data)
+}
@@ -0,0 +1,29 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package standby
+
+import (
+	"github.com/sourcenetwork/defradb/errors"
+)
+
+const (
+	errInvalidVersion string = "invalid standby version"
+)
+
+var (
+	ErrInvalidVersion = errors.New(errInvalidVersion)
+)
+
+// NewErrInvalidVersion returns a new error indicating that the persisted version of the
+// datastore of the primary is malformed.
+func NewErrInvalidVersion(value []byte) error {
+	return errors.New(errInvalidVersion, errors.NewKV("Value", value))
+}
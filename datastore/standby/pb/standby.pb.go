@@ -0,0 +1,903 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: standby.proto
+
+package standby_pb
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/gogo/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	io "io"
+	math "math"
+	math_bits "math/bits"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
+
+type StreamChangesRequest struct {
+	// Version of the datastore of the primary the standby is consistent with, the whole datastore
+	// being streamed if zero.
+	Since uint64 `protobuf:"varint,1,opt,name=since,proto3" json:"since,omitempty"`
+}
+
+func (m *StreamChangesRequest) Reset()         { *m = StreamChangesRequest{} }
+func (m *StreamChangesRequest) String() string { return proto.CompactTextString(m) }
+func (*StreamChangesRequest) ProtoMessage()    {}
+func (*StreamChangesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_145e9b25121a652f, []int{0}
+}
+func (m *StreamChangesRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *StreamChangesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_StreamChangesRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *StreamChangesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StreamChangesRequest.Merge(m, src)
+}
+func (m *StreamChangesRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *StreamChangesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StreamChangesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StreamChangesRequest proto.InternalMessageInfo
+
+func (m *StreamChangesRequest) GetSince() uint64 {
+	if m != nil {
+		return m.Since
+	}
+	return 0
+}
+
+type StreamChangesReply struct {
+	Kvs []*StreamChangesReply_KV `protobuf:"bytes,1,rep,name=kvs,proto3" json:"kvs,omitempty"`
+	// Version of the datastore of the primary the standby is consistent with once the changes
+	// streamed so far are applied. It is only set on the last reply of each diff.
+	Version uint64 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+}
+
+func (m *StreamChangesReply) Reset()         { *m = StreamChangesReply{} }
+func (m *StreamChangesReply) String() string { return proto.CompactTextString(m) }
+func (*StreamChangesReply) ProtoMessage()    {}
+func (*StreamChangesReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_145e9b25121a652f, []int{1}
+}
+func (m *StreamChangesReply) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *StreamChangesReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_StreamChangesReply.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *StreamChangesReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StreamChangesReply.Merge(m, src)
+}
+func (m *StreamChangesReply) XXX_Size() int {
+	return m.Size()
+}
+func (m *StreamChangesReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_StreamChangesReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StreamChangesReply proto.InternalMessageInfo
+
+func (m *StreamChangesReply) GetKvs() []*StreamChangesReply_KV {
+	if m != nil {
+		return m.Kvs
+	}
+	return nil
+}
+
+func (m *StreamChangesReply) GetVersion() uint64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+type StreamChangesReply_KV struct {
+	Key     []byte `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value   []byte `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	Deleted bool   `protobuf:"varint,3,opt,name=deleted,proto3" json:"deleted,omitempty"`
+}
+
+func (m *StreamChangesReply_KV) Reset()         { *m = StreamChangesReply_KV{} }
+func (m *StreamChangesReply_KV) String() string { return proto.CompactTextString(m) }
+func (*StreamChangesReply_KV) ProtoMessage()    {}
+func (*StreamChangesReply_KV) Descriptor() ([]byte, []int) {
+	return fileDescriptor_145e9b25121a652f, []int{1, 0}
+}
+func (m *StreamChangesReply_KV) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *StreamChangesReply_KV) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_StreamChangesReply_KV.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *StreamChangesReply_KV) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StreamChangesReply_KV.Merge(m, src)
+}
+func (m *StreamChangesReply_KV) XXX_Size() int {
+	return m.Size()
+}
+func (m *StreamChangesReply_KV) XXX_DiscardUnknown() {
+	xxx_messageInfo_StreamChangesReply_KV.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StreamChangesReply_KV proto.InternalMessageInfo
+
+func (m *StreamChangesReply_KV) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+func (m *StreamChangesReply_KV) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (m *StreamChangesReply_KV) GetDeleted() bool {
+	if m != nil {
+		return m.Deleted
+	}
+	return false
+}
+
+func init() {
+	proto.RegisterType((*StreamChangesRequest)(nil), "standby.pb.StreamChangesRequest")
+	proto.RegisterType((*StreamChangesReply)(nil), "standby.pb.StreamChangesReply")
+	proto.RegisterType((*StreamChangesReply_KV)(nil), "standby.pb.StreamChangesReply.KV")
+}
+
+func init() { proto.RegisterFile("standby.proto", fileDescriptor_145e9b25121a652f) }
+
+var fileDescriptor_145e9b25121a652f = []byte{
+	// 252 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xe2, 0x2d, 0x2e, 0x49, 0xcc,
+	0x4b, 0x49, 0xaa, 0xd4, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0xe2, 0x82, 0x73, 0x93, 0x94, 0x74,
+	0xb8, 0x44, 0x82, 0x4b, 0x8a, 0x52, 0x13, 0x73, 0x9d, 0x33, 0x12, 0xf3, 0xd2, 0x53, 0x8b, 0x83,
+	0x52, 0x0b, 0x4b, 0x53, 0x8b, 0x4b, 0x84, 0x44, 0xb8, 0x58, 0x8b, 0x33, 0xf3, 0x92, 0x53, 0x25,
+	0x18, 0x15, 0x18, 0x35, 0x58, 0x82, 0x20, 0x1c, 0xa5, 0xd5, 0x8c, 0x5c, 0x42, 0x68, 0xca, 0x0b,
+	0x72, 0x2a, 0x85, 0x8c, 0xb9, 0x98, 0xb3, 0xcb, 0x8a, 0x25, 0x18, 0x15, 0x98, 0x35, 0xb8, 0x8d,
+	0x14, 0xf5, 0x10, 0xc6, 0xeb, 0x61, 0x2a, 0xd6, 0xf3, 0x0e, 0x0b, 0x02, 0xa9, 0x16, 0x92, 0xe0,
+	0x62, 0x2f, 0x4b, 0x2d, 0x2a, 0xce, 0xcc, 0xcf, 0x93, 0x60, 0x02, 0xdb, 0x01, 0xe3, 0x4a, 0xb9,
+	0x71, 0x31, 0x79, 0x87, 0x09, 0x09, 0x70, 0x31, 0x67, 0xa7, 0x56, 0x82, 0xed, 0xe7, 0x09, 0x02,
+	0x31, 0x41, 0x6e, 0x2a, 0x4b, 0xcc, 0x29, 0x4d, 0x05, 0xab, 0xe7, 0x09, 0x82, 0x70, 0x40, 0xe6,
+	0xa4, 0xa4, 0xe6, 0xa4, 0x96, 0xa4, 0xa6, 0x48, 0x30, 0x2b, 0x30, 0x6a, 0x70, 0x04, 0xc1, 0xb8,
+	0x46, 0x09, 0x5c, 0xec, 0xc1, 0x10, 0xa7, 0x08, 0x85, 0x72, 0xf1, 0xa2, 0x38, 0x45, 0x48, 0x01,
+	0x8f, 0x2b, 0xc1, 0x21, 0x20, 0x25, 0x87, 0xdf, 0x1f, 0x4a, 0x0c, 0x06, 0x8c, 0x4e, 0x12, 0x27,
+	0x1e, 0xc9, 0x31, 0x5e, 0x78, 0x24, 0xc7, 0xf8, 0xe0, 0x91, 0x1c, 0xe3, 0x84, 0xc7, 0x72, 0x0c,
+	0x17, 0x1e, 0xcb, 0x31, 0xdc, 0x78, 0x2c, 0xc7, 0x90, 0xc4, 0x06, 0x0e, 0x6a, 0x63, 0x40, 0x00,
+	0x00, 0x00, 0xff, 0xff, 0xec, 0xca, 0xa2, 0x61, 0x7b, 0x01, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// StandbyClient is the client API for Standby service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type StandbyClient interface {
+	// StreamChanges streams the changes of the datastore of the primary since the given version.
+	StreamChanges(ctx context.Context, in *StreamChangesRequest, opts ...grpc.CallOption) (Standby_StreamChangesClient, error)
+}
+
+type standbyClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewStandbyClient(cc *grpc.ClientConn) StandbyClient {
+	return &standbyClient{cc}
+}
+
+func (c *standbyClient) StreamChanges(ctx context.Context, in *StreamChangesRequest, opts ...grpc.CallOption) (Standby_StreamChangesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Standby_serviceDesc.Streams[0], "/standby.pb.Standby/StreamChanges", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &standbyStreamChangesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Standby_StreamChangesClient interface {
+	Recv() (*StreamChangesReply, error)
+	grpc.ClientStream
+}
+
+type standbyStreamChangesClient struct {
+	grpc.ClientStream
+}
+
+func (x *standbyStreamChangesClient) Recv() (*StreamChangesReply, error) {
+	m := new(StreamChangesReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// StandbyServer is the server API for Standby service.
+type StandbyServer interface {
+	// StreamChanges streams the changes of the datastore of the primary since the given version.
+	StreamChanges(*StreamChangesRequest, Standby_StreamChangesServer) error
+}
+
+// UnimplementedStandbyServer can be embedded to have forward compatible implementations.
+type UnimplementedStandbyServer struct {
+}
+
+func (*UnimplementedStandbyServer) StreamChanges(req *StreamChangesRequest, srv Standby_StreamChangesServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamChanges not implemented")
+}
+
+func RegisterStandbyServer(s *grpc.Server, srv StandbyServer) {
+	s.RegisterService(&_Standby_serviceDesc, srv)
+}
+
+func _Standby_StreamChanges_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamChangesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StandbyServer).StreamChanges(m, &standbyStreamChangesServer{stream})
+}
+
+type Standby_StreamChangesServer interface {
+	Send(*StreamChangesReply) error
+	grpc.ServerStream
+}
+
+type standbyStreamChangesServer struct {
+	grpc.ServerStream
+}
+
+func (x *standbyStreamChangesServer) Send(m *StreamChangesReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _Standby_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "standby.pb.Standby",
+	HandlerType: (*StandbyServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamChanges",
+			Handler:       _Standby_StreamChanges_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "standby.proto",
+}
+
+func (m *StreamChangesRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *StreamChangesRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *StreamChangesRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Since != 0 {
+		i = encodeVarintStandby(dAtA, i, uint64(m.Since))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *StreamChangesReply) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *StreamChangesReply) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *StreamChangesReply) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Version != 0 {
+		i = encodeVarintStandby(dAtA, i, uint64(m.Version))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Kvs) > 0 {
+		for iNdEx := len(m.Kvs) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Kvs[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintStandby(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *StreamChangesReply_KV) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *StreamChangesReply_KV) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *StreamChangesReply_KV) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Deleted {
+		i--
+		if m.Deleted {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.Value) > 0 {
+		i -= len(m.Value)
+		copy(dAtA[i:], m.Value)
+		i = encodeVarintStandby(dAtA, i, uint64(len(m.Value)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Key) > 0 {
+		i -= len(m.Key)
+		copy(dAtA[i:], m.Key)
+		i = encodeVarintStandby(dAtA, i, uint64(len(m.Key)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintStandby(dAtA []byte, offset int, v uint64) int {
+	offset -= sovStandby(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *StreamChangesRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Since != 0 {
+		n += 1 + sovStandby(uint64(m.Since))
+	}
+	return n
+}
+
+func (m *StreamChangesReply) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Kvs) > 0 {
+		for _, e := range m.Kvs {
+			l = e.Size()
+			n += 1 + l + sovStandby(uint64(l))
+		}
+	}
+	if m.Version != 0 {
+		n += 1 + sovStandby(uint64(m.Version))
+	}
+	return n
+}
+
+func (m *StreamChangesReply_KV) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Key)
+	if l > 0 {
+		n += 1 + l + sovStandby(uint64(l))
+	}
+	l = len(m.Value)
+	if l > 0 {
+		n += 1 + l + sovStandby(uint64(l))
+	}
+	if m.Deleted {
+		n += 2
+	}
+	return n
+}
+
+func sovStandby(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozStandby(x uint64) (n int) {
+	return sovStandby(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *StreamChangesRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStandby
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: StreamChangesRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: StreamChangesRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Since", wireType)
+			}
+			m.Since = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStandby
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Since |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStandby(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthStandby
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *StreamChangesReply) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStandby
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: StreamChangesReply: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: StreamChangesReply: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Kvs", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStandby
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStandby
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthStandby
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Kvs = append(m.Kvs, &StreamChangesReply_KV{})
+			if err := m.Kvs[len(m.Kvs)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
+			}
+			m.Version = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStandby
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Version |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStandby(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthStandby
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *StreamChangesReply_KV) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStandby
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: KV: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: KV: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Key", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStandby
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthStandby
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthStandby
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Key = append(m.Key[:0], dAtA[iNdEx:postIndex]...)
+			if m.Key == nil {
+				m.Key = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStandby
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthStandby
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthStandby
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Value = append(m.Value[:0], dAtA[iNdEx:postIndex]...)
+			if m.Value == nil {
+				m.Value = []byte{}
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Deleted", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStandby
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Deleted = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStandby(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthStandby
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func skipStandby(dAtA []byte) (n int, err error) {
+	l := len(dAtA)
+	iNdEx := 0
+	depth := 0
+	for iNdEx < l {
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return 0, ErrIntOverflowStandby
+			}
+			if iNdEx >= l {
+				return 0, io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		wireType := int(wire & 0x7)
+		switch wireType {
+		case 0:
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowStandby
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				iNdEx++
+				if dAtA[iNdEx-1] < 0x80 {
+					break
+				}
+			}
+		case 1:
+			iNdEx += 8
+		case 2:
+			var length int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowStandby
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				length |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if length < 0 {
+				return 0, ErrInvalidLengthStandby
+			}
+			iNdEx += length
+		case 3:
+			depth++
+		case 4:
+			if depth == 0 {
+				return 0, ErrUnexpectedEndOfGroupStandby
+			}
+			depth--
+		case 5:
+			iNdEx += 4
+		default:
+			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
+		}
+		if iNdEx < 0 {
+			return 0, ErrInvalidLengthStandby
+		}
+		if depth == 0 {
+			return iNdEx, nil
+		}
+	}
+	return 0, io.ErrUnexpectedEOF
+}
+
+var (
+	ErrInvalidLengthStandby        = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowStandby          = fmt.Errorf("proto: integer overflow")
+	ErrUnexpectedEndOfGroupStandby = fmt.Errorf("proto: unexpected end of group")
+)
@@ -0,0 +1,149 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package standby
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	badger "github.com/dgraph-io/badger/v3"
+	badgerpb "github.com/dgraph-io/badger/v3/pb"
+	"github.com/dgraph-io/ristretto/z"
+
+	pb "github.com/sourcenetwork/defradb/datastore/standby/pb"
+)
+
+// pollInterval is the interval at which the primary checks for changes that the subscription to
+// the changes of its datastore may have missed.
+const pollInterval = time.Second
+
+// Primary ships the changes of its badger datastore to the standby nodes.
+//
+// It implements the Standby gRPC service.
+type Primary struct {
+	db *badger.DB
+}
+
+var _ pb.StandbyServer = (*Primary)(nil)
+
+// NewPrimary returns a new Primary shipping the changes of the given badger datastore.
+func NewPrimary(db *badger.DB) *Primary {
+	return &Primary{db: db}
+}
+
+// StreamChanges streams the diffs of the datastore since the requested version, starting with
+// the changes the standby missed, until the standby disconnects or the datastore is closed.
+func (p *Primary) StreamChanges(req *pb.StreamChangesRequest, stream pb.Standby_StreamChangesServer) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	changed := make(chan struct{}, 1)
+	subscribed := make(chan error, 1)
+	go func() {
+		// An empty prefix matches all the keys.
+		subscribed <- p.db.Subscribe(ctx, func(*badger.KVList) error {
+			select {
+			case changed <- struct{}{}:
+			default:
+			}
+			return nil
+		}, []badgerpb.Match{{Prefix: []byte{}}})
+	}()
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	since := req.Since
+	for {
+		version, err := p.sendDiff(ctx, since, stream)
+		if err != nil {
+			return err
+		}
+		since = version
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-subscribed:
+			// The subscription only ends once the datastore is closed.
+			return err
+		case <-changed:
+		case <-ticker.C:
+		}
+	}
+}
+
+// sendDiff sends the latest version of the keys changed since the given version, and returns
+// the version of the datastore the standby is consistent with once they are applied.
+func (p *Primary) sendDiff(ctx context.Context, since uint64, stream pb.Standby_StreamChangesServer) (uint64, error) {
+	// The diff is read at or above the read timestamp of this transaction, so the standby is
+	// consistent with it once the diff is applied. Keys changed in the meantime are sent again
+	// in the next diff, which is harmless as only their latest version is sent.
+	txn := p.db.NewTransaction(false)
+	version := txn.ReadTs()
+	txn.Discard()
+	if version <= since {
+		return since, nil
+	}
+
+	badgerStream := p.db.NewStream()
+	badgerStream.LogPrefix = "Standby.Primary"
+	badgerStream.SinceTs = since
+	badgerStream.ChooseKey = func(item *badger.Item) bool {
+		// The version of the primary followed by this node, if it was a standby, is not shipped.
+		return !bytes.Equal(item.Key(), versionKey)
+	}
+	badgerStream.KeyToList = func(key []byte, itr *badger.Iterator) (*badgerpb.KVList, error) {
+		item := itr.Item()
+		kv := &badgerpb.KV{Key: key}
+		if item.IsDeletedOrExpired() {
+			if since == 0 {
+				// A standby following from scratch has nothing to delete.
+				return nil, nil
+			}
+			kv.UserMeta = []byte{bitDeleted}
+		} else {
+			value, err := item.ValueCopy(nil)
+			if err != nil {
+				return nil, err
+			}
+			kv.Value = value
+		}
+		return &badgerpb.KVList{Kv: []*badgerpb.KV{kv}}, nil
+	}
+	badgerStream.Send = func(buf *z.Buffer) error {
+		list, err := badger.BufferToKVList(buf)
+		if err != nil {
+			return err
+		}
+		reply := &pb.StreamChangesReply{Kvs: make([]*pb.StreamChangesReply_KV, 0, len(list.Kv))}
+		for _, kv := range list.Kv {
+			if kv.StreamDone {
+				continue
+			}
+			reply.Kvs = append(reply.Kvs, &pb.StreamChangesReply_KV{
+				Key:     kv.Key,
+				Value:   kv.Value,
+				Deleted: len(kv.UserMeta) > 0 && kv.UserMeta[0]&bitDeleted != 0,
+			})
+		}
+		return stream.Send(reply)
+	}
+
+	if err := badgerStream.Orchestrate(ctx); err != nil {
+		return since, err
+	}
+	if err := stream.Send(&pb.StreamChangesReply{Version: version}); err != nil {
+		return since, err
+	}
+	return version, nil
+}
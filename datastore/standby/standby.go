@@ -0,0 +1,160 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+/*
+Package standby replicates the badger datastore of a primary node to a warm standby node.
+
+The replication is physical, independent of the CRDT-level P2P synchronization: the primary
+ships diffs of its keyspace, holding the latest version of the keys changed since the version
+the standby is consistent with, over gRPC. The first diff holds the whole datastore. The standby
+persists the version it is consistent with alongside the changes, so that it resumes from it
+after a restart or a disconnection.
+
+The standby only follows the primary, it does not serve the database. It is promoted by
+restarting it without the standby role.
+
+Deletions are shipped as long as badger keeps their markers, which it may drop once compacted
+into the last level. A standby that has been disconnected for long should be reseeded from an
+empty datastore.
+*/
+package standby
+
+import (
+	"context"
+	"encoding/binary"
+	"time"
+
+	badger "github.com/dgraph-io/badger/v3"
+	"google.golang.org/grpc"
+
+	pb "github.com/sourcenetwork/defradb/datastore/standby/pb"
+	"github.com/sourcenetwork/defradb/errors"
+	"github.com/sourcenetwork/defradb/logging"
+)
+
+var log = logging.MustNewLogger("defra.datastore.standby")
+
+// retryInterval is the interval at which the standby reconnects to the primary after losing the
+// stream of its changes.
+const retryInterval = 5 * time.Second
+
+// bitDeleted marks the deleted keys in the user metadata of the streamed badger KVs.
+const bitDeleted byte = 1
+
+// versionKey is the key of the version of the datastore of the primary the standby is consistent
+// with.
+var versionKey = []byte("/standby/version")
+
+// Standby applies the changes of the datastore of a primary to its badger datastore.
+type Standby struct {
+	db     *badger.DB
+	client pb.StandbyClient
+}
+
+// New returns a new Standby applying the changes of the primary reached through the given
+// connection to the given badger datastore.
+func New(db *badger.DB, conn *grpc.ClientConn) *Standby {
+	return &Standby{
+		db:     db,
+		client: pb.NewStandbyClient(conn),
+	}
+}
+
+// Version returns the version of the datastore of the primary the standby is consistent with,
+// zero if it has not followed the primary yet.
+func (s *Standby) Version() (uint64, error) {
+	var version uint64
+	err := s.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(versionKey)
+		if errors.Is(err, badger.ErrKeyNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return item.Value(func(value []byte) error {
+			if len(value) != 8 {
+				return NewErrInvalidVersion(value)
+			}
+			version = binary.BigEndian.Uint64(value)
+			return nil
+		})
+	})
+	return version, err
+}
+
+// Run follows the primary until the given context is done, reconnecting to it whenever the
+// stream of its changes is lost.
+func (s *Standby) Run(ctx context.Context) {
+	for {
+		err := s.follow(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		log.ErrorE(ctx, "Lost the stream of changes of the primary", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retryInterval):
+		}
+	}
+}
+
+// follow applies the changes of the primary since the current version, until the stream of its
+// changes ends.
+func (s *Standby) follow(ctx context.Context) error {
+	since, err := s.Version()
+	if err != nil {
+		return err
+	}
+	stream, err := s.client.StreamChanges(ctx, &pb.StreamChangesRequest{Since: since})
+	if err != nil {
+		return err
+	}
+	log.Info(ctx, "Following the primary", logging.NewKV("Since", since))
+
+	// The changes of each diff are written in batches, the version being written with the last
+	// one. If the stream is lost in the middle of a diff, the diff is sent again in full.
+	batch := s.db.NewWriteBatch()
+	defer func() {
+		batch.Cancel()
+	}()
+	for {
+		reply, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		for _, kv := range reply.Kvs {
+			if kv.Deleted {
+				err = batch.Delete(kv.Key)
+			} else {
+				err = batch.Set(kv.Key, kv.Value)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if reply.Version == 0 {
+			continue
+		}
+
+		value := make([]byte, 8)
+		binary.BigEndian.PutUint64(value, reply.Version)
+		if err := batch.Set(versionKey, value); err != nil {
+			return err
+		}
+		if err := batch.Flush(); err != nil {
+			return err
+		}
+		log.Debug(ctx, "Applied diff of the primary", logging.NewKV("Version", reply.Version))
+		batch = s.db.NewWriteBatch()
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package standby
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	badger "github.com/dgraph-io/badger/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	pb "github.com/sourcenetwork/defradb/datastore/standby/pb"
+	"github.com/sourcenetwork/defradb/errors"
+)
+
+func newTestBadger(t *testing.T) *badger.DB {
+	db, err := badger.Open(badger.DefaultOptions("").WithInMemory(true).WithLogger(nil))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+	return db
+}
+
+// startPrimary serves the changes of the given datastore, and returns a connection to it.
+func startPrimary(t *testing.T, db *badger.DB) *grpc.ClientConn {
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	pb.RegisterStandbyServer(server, NewPrimary(db))
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial(
+		"bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, conn.Close())
+	})
+	return conn
+}
+
+// runStandby follows the primary until the returned function is called.
+func runStandby(standby *Standby) func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		standby.Run(ctx)
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+func set(t *testing.T, db *badger.DB, key, value string) {
+	err := db.Update(func(txn *badger.Txn) error {
+		return txn.Set([]byte(key), []byte(value))
+	})
+	require.NoError(t, err)
+}
+
+func del(t *testing.T, db *badger.DB, key string) {
+	err := db.Update(func(txn *badger.Txn) error {
+		return txn.Delete([]byte(key))
+	})
+	require.NoError(t, err)
+}
+
+// get returns the value of the given key, or an empty string if it does not exist.
+func get(t *testing.T, db *badger.DB, key string) string {
+	var value []byte
+	err := db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get([]byte(key))
+		if err != nil {
+			return err
+		}
+		value, err = item.ValueCopy(nil)
+		return err
+	})
+	if errors.Is(err, badger.ErrKeyNotFound) {
+		return ""
+	}
+	require.NoError(t, err)
+	return string(value)
+}
+
+func assertEventually(t *testing.T, db *badger.DB, key, value string) {
+	assert.Eventually(t, func() bool {
+		return get(t, db, key) == value
+	}, 5*time.Second, 10*time.Millisecond, "key %s", key)
+}
+
+func TestStandbyFollowsPrimary(t *testing.T) {
+	primaryDB := newTestBadger(t)
+	set(t, primaryDB, "/db/data/1", "John")
+	set(t, primaryDB, "/db/data/2", "Fred")
+	del(t, primaryDB, "/db/data/2")
+
+	standbyDB := newTestBadger(t)
+	standby := New(standbyDB, startPrimary(t, primaryDB))
+	stop := runStandby(standby)
+	defer stop()
+
+	assertEventually(t, standbyDB, "/db/data/1", "John")
+	assert.Equal(t, "", get(t, standbyDB, "/db/data/2"))
+
+	set(t, primaryDB, "/db/data/1", "Islam")
+	set(t, primaryDB, "/db/data/3", "Andy")
+	assertEventually(t, standbyDB, "/db/data/1", "Islam")
+	assertEventually(t, standbyDB, "/db/data/3", "Andy")
+
+	del(t, primaryDB, "/db/data/1")
+	assertEventually(t, standbyDB, "/db/data/1", "")
+}
+
+func TestStandbyResumesFromVersion(t *testing.T) {
+	primaryDB := newTestBadger(t)
+	set(t, primaryDB, "/db/data/1", "John")
+	set(t, primaryDB, "/db/data/2", "Fred")
+
+	standbyDB := newTestBadger(t)
+	standby := New(standbyDB, startPrimary(t, primaryDB))
+	stop := runStandby(standby)
+	assertEventually(t, standbyDB, "/db/data/2", "Fred")
+	stop()
+
+	version, err := standby.Version()
+	require.NoError(t, err)
+	assert.Greater(t, version, uint64(0))
+
+	del(t, primaryDB, "/db/data/1")
+	set(t, primaryDB, "/db/data/3", "Andy")
+
+	stop = runStandby(standby)
+	defer stop()
+	assertEventually(t, standbyDB, "/db/data/3", "Andy")
+	assertEventually(t, standbyDB, "/db/data/1", "")
+	assert.Equal(t, "Fred", get(t, standbyDB, "/db/data/2"))
+}
+
+func TestPrimaryDoesNotShipItsStandbyVersion(t *testing.T) {
+	primaryDB := newTestBadger(t)
+	set(t, primaryDB, string(versionKey), "00000000")
+	set(t, primaryDB, "/db/data/1", "John")
+
+	standbyDB := newTestBadger(t)
+	standby := New(standbyDB, startPrimary(t, primaryDB))
+	stop := runStandby(standby)
+	defer stop()
+
+	assertEventually(t, standbyDB, "/db/data/1", "John")
+	assert.NotEqual(t, "00000000", get(t, standbyDB, string(versionKey)))
+}
@@ -0,0 +1,28 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tiered
+
+import (
+	"github.com/sourcenetwork/defradb/errors"
+)
+
+const (
+	errInvalidCacheSize string = "invalid cold block cache size"
+)
+
+var (
+	ErrInvalidCacheSize = errors.New(errInvalidCacheSize)
+)
+
+// NewErrInvalidCacheSize returns a new error indicating that the given cache size is invalid.
+func NewErrInvalidCacheSize(size int) error {
+	return errors.New(errInvalidCacheSize, errors.NewKV("Size", size))
+}
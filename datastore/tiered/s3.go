@@ -0,0 +1,100 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tiered
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+
+	ds "github.com/ipfs/go-datastore"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// S3Options configures the connection to an S3-compatible object storage.
+type S3Options struct {
+	// Endpoint is the host (and port) of the object storage (e.g. `s3.amazonaws.com`).
+	Endpoint string
+	// Secure is true if the object storage is served over HTTPS.
+	Secure bool
+	// Region is the region of the bucket.
+	Region string
+	// Bucket is the name of the bucket the blocks are stored in.
+	Bucket string
+	// Prefix is the prefix of the names of the objects of the blocks within the bucket.
+	Prefix string
+	// AccessKey is the access key ID of the credentials.
+	AccessKey string
+	// SecretKey is the secret access key of the credentials.
+	SecretKey string
+}
+
+type s3Store struct {
+	client *minio.Client
+	bucket string
+	prefix string
+}
+
+// NewS3Store returns a ColdStore storing the blocks in the given bucket of an S3-compatible
+// object storage.
+func NewS3Store(opts S3Options) (ColdStore, error) {
+	client, err := minio.New(opts.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(opts.AccessKey, opts.SecretKey, ""),
+		Secure: opts.Secure,
+		Region: opts.Region,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &s3Store{
+		client: client,
+		bucket: opts.Bucket,
+		prefix: opts.Prefix,
+	}, nil
+}
+
+func (s *s3Store) Get(ctx context.Context, name string) ([]byte, error) {
+	object, err := s.client.GetObject(ctx, s.bucket, s.prefix+name, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := object.Close(); err != nil {
+			log.ErrorE(ctx, "Failed to close S3 object", err)
+		}
+	}()
+
+	value, err := io.ReadAll(object)
+	if minio.ToErrorResponse(err).StatusCode == http.StatusNotFound {
+		return nil, ds.ErrNotFound
+	}
+	return value, err
+}
+
+func (s *s3Store) Put(ctx context.Context, name string, value []byte) error {
+	// Blocks are small, so they are sent in a single request with an MD5 checksum rather than
+	// with the chunked signature.
+	_, err := s.client.PutObject(
+		ctx,
+		s.bucket,
+		s.prefix+name,
+		bytes.NewReader(value),
+		int64(len(value)),
+		minio.PutObjectOptions{
+			ContentType:          "application/octet-stream",
+			SendContentMd5:       true,
+			DisableContentSha256: true,
+		},
+	)
+	return err
+}
@@ -0,0 +1,78 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tiered
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	ds "github.com/ipfs/go-datastore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFakeS3Server returns a server serving the objects of path-style requests from memory.
+func newFakeS3Server(t *testing.T) *httptest.Server {
+	var mu sync.Mutex
+	objects := map[string][]byte{}
+	return httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch req.Method {
+		case http.MethodPut:
+			body, err := io.ReadAll(req.Body)
+			require.NoError(t, err)
+			objects[req.URL.Path] = body
+		case http.MethodGet:
+			value, ok := objects[req.URL.Path]
+			if !ok {
+				rw.Header().Set("Content-Type", "application/xml")
+				rw.WriteHeader(http.StatusNotFound)
+				_, err := rw.Write([]byte(`<Error><Code>NoSuchKey</Code></Error>`))
+				require.NoError(t, err)
+				return
+			}
+			rw.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+			_, err := rw.Write(value)
+			require.NoError(t, err)
+		default:
+			rw.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+}
+
+func TestS3StorePutAndGet(t *testing.T) {
+	ctx := context.Background()
+	server := newFakeS3Server(t)
+	defer server.Close()
+
+	store, err := NewS3Store(S3Options{
+		Endpoint: strings.TrimPrefix(server.URL, "http://"),
+		Region:   "us-east-1",
+		Bucket:   "defradb",
+		Prefix:   "node1/",
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, store.Put(ctx, "db/blocks/CIQA", []byte("block")))
+	value, err := store.Get(ctx, "db/blocks/CIQA")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("block"), value)
+
+	_, err = store.Get(ctx, "db/blocks/CIQB")
+	require.ErrorIs(t, err, ds.ErrNotFound)
+}
@@ -0,0 +1,369 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+/*
+Package tiered provides a rootstore whose cold blocks are offloaded to an object storage.
+
+The blocks of the old commits, those that are no longer the head of a document or field, are
+moved from the local rootstore to the cold store by the offloader. An index of the offloaded
+blocks is kept locally, so that checking whether a block exists never requires a request to
+the cold store. Reading an offloaded block fetches it from the cold store, through a local LRU
+cache of the most recently read blocks.
+
+Offloaded blocks are not returned by the queries of the blockstore.
+*/
+package tiered
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	dshelp "github.com/ipfs/boxo/datastore/dshelp"
+	ds "github.com/ipfs/go-datastore"
+	dsq "github.com/ipfs/go-datastore/query"
+
+	"github.com/sourcenetwork/defradb/core"
+	"github.com/sourcenetwork/defradb/datastore"
+	"github.com/sourcenetwork/defradb/datastore/iterable"
+	"github.com/sourcenetwork/defradb/errors"
+	"github.com/sourcenetwork/defradb/logging"
+)
+
+var log = logging.MustNewLogger("defra.datastore.tiered")
+
+// offloadBatchSize is the number of offloaded blocks deleted locally per transaction.
+const offloadBatchSize = 100
+
+// coldIndexKey is the prefix of the local index of the offloaded blocks, mapping the key of
+// each offloaded block to its size.
+var coldIndexKey = ds.NewKey("/db/coldblocks")
+
+// ColdStore is an object storage that cold blocks are offloaded to.
+type ColdStore interface {
+	// Get returns the object with the given name, or ds.ErrNotFound if it does not exist.
+	Get(ctx context.Context, name string) ([]byte, error)
+
+	// Put stores the given object under the given name.
+	Put(ctx context.Context, name string, value []byte) error
+}
+
+// Store is a rootstore whose cold blocks are offloaded to a ColdStore.
+type Store struct {
+	datastore.RootStore
+
+	cold  ColdStore
+	cache *lru.Cache[string, []byte]
+
+	cancel context.CancelFunc
+	done   chan struct{}
+	once   sync.Once
+}
+
+var _ datastore.RootStore = (*Store)(nil)
+var _ iterable.IterableTxnDatastore = (*Store)(nil)
+
+// New returns a new Store offloading the cold blocks of the given rootstore to the given cold
+// store, caching the given number of the most recently read offloaded blocks.
+func New(root datastore.RootStore, cold ColdStore, cacheSize int) (*Store, error) {
+	cache, err := lru.New[string, []byte](cacheSize)
+	if err != nil {
+		return nil, NewErrInvalidCacheSize(cacheSize)
+	}
+	return &Store{
+		RootStore: root,
+		cold:      cold,
+		cache:     cache,
+	}, nil
+}
+
+// Get implements ds.Read, reading offloaded blocks from the cold store.
+func (s *Store) Get(ctx context.Context, key ds.Key) ([]byte, error) {
+	return s.get(ctx, s.RootStore, key)
+}
+
+// Has implements ds.Read, offloaded blocks being reported as existing.
+func (s *Store) Has(ctx context.Context, key ds.Key) (bool, error) {
+	return has(ctx, s.RootStore, key)
+}
+
+// GetSize implements ds.Read, returning the size of offloaded blocks too.
+func (s *Store) GetSize(ctx context.Context, key ds.Key) (int, error) {
+	return getSize(ctx, s.RootStore, key)
+}
+
+// Delete implements ds.Write, deleting the index entry of offloaded blocks too.
+func (s *Store) Delete(ctx context.Context, key ds.Key) error {
+	return deleteKey(ctx, s.RootStore, key)
+}
+
+// NewTransaction implements ds.TxnDatastore.
+func (s *Store) NewTransaction(ctx context.Context, readOnly bool) (ds.Txn, error) {
+	t, err := s.RootStore.NewTransaction(ctx, readOnly)
+	if err != nil {
+		return nil, err
+	}
+	return &txn{Txn: t, store: s}, nil
+}
+
+// NewIterableTransaction implements iterable.IterableTxnDatastore.
+func (s *Store) NewIterableTransaction(ctx context.Context, readOnly bool) (iterable.IterableTxn, error) {
+	if iterableStore, ok := s.RootStore.(iterable.IterableTxnDatastore); ok {
+		t, err := iterableStore.NewIterableTransaction(ctx, readOnly)
+		if err != nil {
+			return nil, err
+		}
+		return &iterableTxn{txn: &txn{Txn: t, store: s}, Iterable: t}, nil
+	}
+
+	t, err := s.RootStore.NewTransaction(ctx, readOnly)
+	if err != nil {
+		return nil, err
+	}
+	tieredTxn := &txn{Txn: t, store: s}
+	return &iterableTxn{txn: tieredTxn, Iterable: iterable.NewIterable(tieredTxn)}, nil
+}
+
+// StartOffloading starts offloading the cold blocks at the given interval, until the store is
+// closed.
+func (s *Store) StartOffloading(interval time.Duration) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.done = make(chan struct{})
+
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				count, err := s.Offload(ctx)
+				if err != nil && !errors.Is(err, context.Canceled) {
+					log.ErrorE(ctx, "Failed to offload cold blocks", err)
+				}
+				if count > 0 {
+					log.Info(ctx, "Offloaded cold blocks", logging.NewKV("Count", count))
+				}
+			}
+		}
+	}()
+}
+
+// Close stops the offloading of the cold blocks and closes the underlying rootstore.
+func (s *Store) Close() error {
+	s.once.Do(func() {
+		if s.cancel != nil {
+			s.cancel()
+			<-s.done
+		}
+	})
+	return s.RootStore.Close()
+}
+
+// Offload moves the cold blocks, those that are not the head of any document or field, to the
+// cold store. It returns the number of blocks offloaded.
+func (s *Store) Offload(ctx context.Context) (int, error) {
+	// The heads and the blocks are read from the same snapshot, so that the blocks of the heads
+	// written in the meantime are not offloaded.
+	snapshot, err := s.RootStore.NewTransaction(ctx, true)
+	if err != nil {
+		return 0, err
+	}
+	defer snapshot.Discard(ctx)
+
+	heads, err := headBlockKeys(ctx, snapshot)
+	if err != nil {
+		return 0, err
+	}
+
+	results, err := snapshot.Query(ctx, dsq.Query{Prefix: datastore.BlockStorePrefix().String()})
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err := results.Close(); err != nil {
+			log.ErrorE(ctx, "Failed to close blocks query", err)
+		}
+	}()
+
+	count := 0
+	offloaded := make([]dsq.Entry, 0, offloadBatchSize)
+	for result := range results.Next() {
+		if result.Error != nil {
+			return count, result.Error
+		}
+		if _, isHead := heads[result.Key]; isHead {
+			continue
+		}
+		if err := s.cold.Put(ctx, objectName(result.Key), result.Value); err != nil {
+			return count, err
+		}
+		offloaded = append(offloaded, result.Entry)
+
+		if len(offloaded) == offloadBatchSize {
+			if err := s.deleteOffloaded(ctx, offloaded); err != nil {
+				return count, err
+			}
+			count += len(offloaded)
+			offloaded = offloaded[:0]
+		}
+	}
+	if err := s.deleteOffloaded(ctx, offloaded); err != nil {
+		return count, err
+	}
+	return count + len(offloaded), nil
+}
+
+// headBlockKeys returns the rootstore keys of the blocks of the heads of all the documents and
+// fields.
+func headBlockKeys(ctx context.Context, reader ds.Read) (map[string]struct{}, error) {
+	prefix := datastore.HeadStorePrefix().String()
+	results, err := reader.Query(ctx, dsq.Query{Prefix: prefix, KeysOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := results.Close(); err != nil {
+			log.ErrorE(ctx, "Failed to close heads query", err)
+		}
+	}()
+
+	heads := map[string]struct{}{}
+	for result := range results.Next() {
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		headKey, err := core.NewHeadStoreKey(strings.TrimPrefix(result.Key, prefix))
+		if err != nil {
+			return nil, err
+		}
+		blockKey := datastore.BlockStorePrefix().Child(dshelp.MultihashToDsKey(headKey.Cid.Hash()))
+		heads[blockKey.String()] = struct{}{}
+	}
+	return heads, nil
+}
+
+// deleteOffloaded replaces the given offloaded blocks by their index entry.
+func (s *Store) deleteOffloaded(ctx context.Context, offloaded []dsq.Entry) error {
+	if len(offloaded) == 0 {
+		return nil
+	}
+	t, err := s.RootStore.NewTransaction(ctx, false)
+	if err != nil {
+		return err
+	}
+	defer t.Discard(ctx)
+
+	for _, entry := range offloaded {
+		key := ds.NewKey(entry.Key)
+		err := t.Put(ctx, coldIndexKey.Child(key), []byte(strconv.Itoa(len(entry.Value))))
+		if err != nil {
+			return err
+		}
+		if err := t.Delete(ctx, key); err != nil {
+			return err
+		}
+	}
+	return t.Commit(ctx)
+}
+
+// get reads the given key from the given reader, reading offloaded blocks from the cold store.
+func (s *Store) get(ctx context.Context, reader ds.Read, key ds.Key) ([]byte, error) {
+	value, err := reader.Get(ctx, key)
+	if !errors.Is(err, ds.ErrNotFound) || !isBlockKey(key) {
+		return value, err
+	}
+	if _, err := reader.Get(ctx, coldIndexKey.Child(key)); err != nil {
+		return nil, err
+	}
+
+	name := objectName(key.String())
+	if value, ok := s.cache.Get(name); ok {
+		return value, nil
+	}
+	value, err = s.cold.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	s.cache.Add(name, value)
+	return value, nil
+}
+
+func has(ctx context.Context, reader ds.Read, key ds.Key) (bool, error) {
+	exists, err := reader.Has(ctx, key)
+	if err != nil || exists || !isBlockKey(key) {
+		return exists, err
+	}
+	return reader.Has(ctx, coldIndexKey.Child(key))
+}
+
+func getSize(ctx context.Context, reader ds.Read, key ds.Key) (int, error) {
+	size, err := reader.GetSize(ctx, key)
+	if !errors.Is(err, ds.ErrNotFound) || !isBlockKey(key) {
+		return size, err
+	}
+	value, err := reader.Get(ctx, coldIndexKey.Child(key))
+	if err != nil {
+		return -1, err
+	}
+	return strconv.Atoi(string(value))
+}
+
+func deleteKey(ctx context.Context, writer ds.Write, key ds.Key) error {
+	if err := writer.Delete(ctx, key); err != nil {
+		return err
+	}
+	if !isBlockKey(key) {
+		return nil
+	}
+	return writer.Delete(ctx, coldIndexKey.Child(key))
+}
+
+func isBlockKey(key ds.Key) bool {
+	return datastore.BlockStorePrefix().IsAncestorOf(key)
+}
+
+// objectName returns the name of the object of the block with the given rootstore key.
+func objectName(key string) string {
+	return strings.TrimPrefix(key, "/")
+}
+
+// txn is a transaction of a Store, reading offloaded blocks from its cold store.
+type txn struct {
+	ds.Txn
+	store *Store
+}
+
+func (t *txn) Get(ctx context.Context, key ds.Key) ([]byte, error) {
+	return t.store.get(ctx, t.Txn, key)
+}
+
+func (t *txn) Has(ctx context.Context, key ds.Key) (bool, error) {
+	return has(ctx, t.Txn, key)
+}
+
+func (t *txn) GetSize(ctx context.Context, key ds.Key) (int, error) {
+	return getSize(ctx, t.Txn, key)
+}
+
+func (t *txn) Delete(ctx context.Context, key ds.Key) error {
+	return deleteKey(ctx, t.Txn, key)
+}
+
+type iterableTxn struct {
+	*txn
+	iterable.Iterable
+}
@@ -0,0 +1,198 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tiered
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	badger "github.com/dgraph-io/badger/v3"
+	ds "github.com/ipfs/go-datastore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/client"
+	badgerds "github.com/sourcenetwork/defradb/datastore/badger/v3"
+	"github.com/sourcenetwork/defradb/db"
+)
+
+// mockColdStore keeps the objects in memory and counts the reads.
+type mockColdStore struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	gets    int
+}
+
+func newMockColdStore() *mockColdStore {
+	return &mockColdStore{objects: map[string][]byte{}}
+}
+
+func (s *mockColdStore) Get(ctx context.Context, name string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.gets++
+	value, ok := s.objects[name]
+	if !ok {
+		return nil, ds.ErrNotFound
+	}
+	return value, nil
+}
+
+func (s *mockColdStore) Put(ctx context.Context, name string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objects[name] = value
+	return nil
+}
+
+func newTestStore(t *testing.T, cold ColdStore, cacheSize int) *Store {
+	opts := badgerds.Options{Options: badger.DefaultOptions("").WithInMemory(true)}
+	rootstore, err := badgerds.NewDatastore("", &opts)
+	require.NoError(t, err)
+	store, err := New(rootstore, cold, cacheSize)
+	require.NoError(t, err)
+	return store
+}
+
+// newTestDoc creates a document and updates it twice, giving it two cold commits.
+func newTestDoc(t *testing.T, ctx context.Context, defra client.DB) *client.Document {
+	err := defra.AddSchema(ctx, `type User { name: String }`)
+	require.NoError(t, err)
+	col, err := defra.GetCollectionByName(ctx, "User")
+	require.NoError(t, err)
+
+	doc, err := client.NewDocFromJSON([]byte(`{"name": "John"}`))
+	require.NoError(t, err)
+	require.NoError(t, col.Create(ctx, doc))
+	for _, name := range []string{"Fred", "Islam"} {
+		require.NoError(t, doc.Set("name", name))
+		require.NoError(t, col.Update(ctx, doc))
+	}
+	return doc
+}
+
+func TestOffloadMovesColdBlocks(t *testing.T) {
+	ctx := context.Background()
+	cold := newMockColdStore()
+	store := newTestStore(t, cold, 16)
+	defra, err := db.NewDB(ctx, store)
+	require.NoError(t, err)
+	defer defra.Close(ctx)
+	doc := newTestDoc(t, ctx, defra)
+
+	count, err := store.Offload(ctx)
+	require.NoError(t, err)
+	// The composite and field blocks of the first two commits are cold.
+	assert.Equal(t, 4, count)
+	assert.Len(t, cold.objects, 4)
+
+	count, err = store.Offload(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	result := defra.ExecRequest(ctx, `query {
+		commits(dockey: "`+doc.Key().String()+`") {
+			height
+		}
+	}`)
+	require.Empty(t, result.GQL.Errors)
+	assert.Len(t, result.GQL.Data, 6)
+
+	result = defra.ExecRequest(ctx, `query { User { name } }`)
+	require.Empty(t, result.GQL.Errors)
+	assert.Equal(t, []map[string]any{{"name": "Islam"}}, result.GQL.Data)
+}
+
+func TestStoreReadsOffloadedBlocks(t *testing.T) {
+	ctx := context.Background()
+	cold := newMockColdStore()
+	store := newTestStore(t, cold, 16)
+	defra, err := db.NewDB(ctx, store)
+	require.NoError(t, err)
+	defer defra.Close(ctx)
+	newTestDoc(t, ctx, defra)
+
+	_, err = store.Offload(ctx)
+	require.NoError(t, err)
+
+	txn, err := store.NewTransaction(ctx, true)
+	require.NoError(t, err)
+	defer txn.Discard(ctx)
+	for name, value := range cold.objects {
+		key := ds.NewKey(name)
+
+		exists, err := store.RootStore.Has(ctx, key)
+		require.NoError(t, err)
+		assert.False(t, exists)
+
+		exists, err = txn.Has(ctx, key)
+		require.NoError(t, err)
+		assert.True(t, exists)
+
+		size, err := txn.GetSize(ctx, key)
+		require.NoError(t, err)
+		assert.Equal(t, len(value), size)
+
+		read, err := store.Get(ctx, key)
+		require.NoError(t, err)
+		assert.Equal(t, value, read)
+	}
+}
+
+func TestStoreCachesOffloadedBlocks(t *testing.T) {
+	ctx := context.Background()
+	cold := newMockColdStore()
+	store := newTestStore(t, cold, 16)
+	defra, err := db.NewDB(ctx, store)
+	require.NoError(t, err)
+	defer defra.Close(ctx)
+	newTestDoc(t, ctx, defra)
+
+	_, err = store.Offload(ctx)
+	require.NoError(t, err)
+
+	for name := range cold.objects {
+		_, err := store.Get(ctx, ds.NewKey(name))
+		require.NoError(t, err)
+		_, err = store.Get(ctx, ds.NewKey(name))
+		require.NoError(t, err)
+	}
+	assert.Equal(t, len(cold.objects), cold.gets)
+}
+
+func TestStoreDeletesOffloadedBlocks(t *testing.T) {
+	ctx := context.Background()
+	cold := newMockColdStore()
+	store := newTestStore(t, cold, 16)
+	defra, err := db.NewDB(ctx, store)
+	require.NoError(t, err)
+	defer defra.Close(ctx)
+	newTestDoc(t, ctx, defra)
+
+	_, err = store.Offload(ctx)
+	require.NoError(t, err)
+
+	for name := range cold.objects {
+		require.NoError(t, store.Delete(ctx, ds.NewKey(name)))
+		exists, err := store.Has(ctx, ds.NewKey(name))
+		require.NoError(t, err)
+		assert.False(t, exists)
+	}
+}
+
+func TestNewWithInvalidCacheSize(t *testing.T) {
+	opts := badgerds.Options{Options: badger.DefaultOptions("").WithInMemory(true)}
+	rootstore, err := badgerds.NewDatastore("", &opts)
+	require.NoError(t, err)
+	_, err = New(rootstore, newMockColdStore(), 0)
+	require.ErrorIs(t, err, ErrInvalidCacheSize)
+}
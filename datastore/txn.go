@@ -25,14 +25,23 @@ type Txn interface {
 	// Commit finalizes a transaction, attempting to commit it to the Datastore.
 	// May return an error if the transaction has gone stale. The presence of an
 	// error is an indication that the data was not committed to the Datastore.
+	// Returns ErrTxnConflict if the transaction conflicts with another transaction.
 	Commit(ctx context.Context) error
 	// Discard throws away changes recorded in a transaction without committing
 	// them to the underlying Datastore. Any calls made to Discard after Commit
 	// has been successfully called will have no effect on the transaction and
 	// state of the Datastore, making it safe to defer.
+	//
+	// Discarding a transaction that has not been committed rolls it back, calling
+	// the functions registered with OnError.
 	Discard(ctx context.Context)
 
+	// OnSuccess registers a function to be called once the transaction is committed.
 	OnSuccess(fn func())
+	// OnError registers a function to be called once the transaction is rolled back,
+	// either because committing it failed or because it was discarded without being
+	// committed. The functions are called at most once, whether Discard is called
+	// after a failed Commit or several times.
 	OnError(fn func())
 }
 
@@ -42,6 +51,9 @@ type txn struct {
 
 	successFns []func()
 	errorFns   []func()
+
+	// Whether the transaction has been committed or rolled back.
+	done bool
 }
 
 var _ Txn = (*txn)(nil)
@@ -60,6 +72,7 @@ func NewTxnFrom(ctx context.Context, rootstore ds.TxnDatastore, readonly bool) (
 			multistore,
 			[]func(){},
 			[]func(){},
+			false,
 		}, nil
 	}
 
@@ -75,13 +88,29 @@ func NewTxnFrom(ctx context.Context, rootstore ds.TxnDatastore, readonly bool) (
 		multistore,
 		[]func(){},
 		[]func(){},
+		false,
 	}, nil
 }
 
+// NewReadCommittedTxnFrom returns a new read only Txn that reads from the rootstore directly
+// instead of from a snapshot.
+//
+// Each read sees the data committed at the time of the read, including the writes committed
+// after the creation of the transaction. Writes fail with ErrReadOnlyTxn.
+func NewReadCommittedTxnFrom(rootstore ds.Datastore) Txn {
+	return &readCommittedTxn{
+		MultiStore: MultiStoreFrom(readOnlyStore{AsDSReaderWriter(rootstore)}),
+	}
+}
+
 // Commit finalizes a transaction, attempting to commit it to the Datastore.
 func (t *txn) Commit(ctx context.Context) error {
+	t.done = true
 	if err := t.t.Commit(ctx); err != nil {
 		t.runErrorFns(ctx)
+		if IsTxnConflict(err) {
+			return NewErrTxnConflict(err)
+		}
 		return err
 	}
 	t.runSuccessFns(ctx)
@@ -89,8 +118,15 @@ func (t *txn) Commit(ctx context.Context) error {
 }
 
 // Discard throws away changes recorded in a transaction without committing.
+//
+// The functions registered with OnError are called if the transaction has not been committed
+// nor rolled back yet.
 func (t *txn) Discard(ctx context.Context) {
 	t.t.Discard(ctx)
+	if !t.done {
+		t.done = true
+		t.runErrorFns(ctx)
+	}
 }
 
 // OnSuccess registers a function to be called when the transaction is committed.
@@ -136,3 +172,50 @@ func (ts ShimTxnStore) Close() error {
 	ts.Discard(context.TODO())
 	return nil
 }
+
+// readCommittedTxn is a read only transaction without a snapshot.
+type readCommittedTxn struct {
+	MultiStore
+
+	successFns []func()
+}
+
+var _ Txn = (*readCommittedTxn)(nil)
+
+// Commit runs the functions registered with OnSuccess, as there is nothing to commit.
+func (t *readCommittedTxn) Commit(ctx context.Context) error {
+	for _, fn := range t.successFns {
+		fn()
+	}
+	return nil
+}
+
+// Discard does nothing, as there is nothing to discard.
+func (t *readCommittedTxn) Discard(ctx context.Context) {}
+
+// OnSuccess registers a function to be called when the transaction is committed.
+func (t *readCommittedTxn) OnSuccess(fn func()) {
+	if fn == nil {
+		return
+	}
+	t.successFns = append(t.successFns, fn)
+}
+
+// OnError does nothing, as committing the transaction can't fail, and discarding it has nothing
+// to roll back.
+func (t *readCommittedTxn) OnError(fn func()) {}
+
+// readOnlyStore is a store that fails writes.
+type readOnlyStore struct {
+	DSReaderWriter
+}
+
+// Put fails with ErrReadOnlyTxn.
+func (s readOnlyStore) Put(ctx context.Context, key ds.Key, value []byte) error {
+	return ErrReadOnlyTxn
+}
+
+// Delete fails with ErrReadOnlyTxn.
+func (s readOnlyStore) Delete(ctx context.Context, key ds.Key) error {
+	return ErrReadOnlyTxn
+}
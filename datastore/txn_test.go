@@ -19,6 +19,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	badgerds "github.com/sourcenetwork/defradb/datastore/badger/v3"
+	"github.com/sourcenetwork/defradb/datastore/memory"
 )
 
 func TestNewTxnFrom(t *testing.T) {
@@ -93,6 +94,37 @@ func TestOnError(t *testing.T) {
 	require.Equal(t, text, "Source Inc")
 }
 
+func TestOnErrorWithDiscard(t *testing.T) {
+	ctx := context.Background()
+	opts := badgerds.Options{Options: badger.DefaultOptions("").WithInMemory(true)}
+	rootstore, err := badgerds.NewDatastore("", &opts)
+	require.NoError(t, err)
+	defer rootstore.Close()
+
+	txn, err := NewTxnFrom(ctx, rootstore, false)
+	require.NoError(t, err)
+
+	rollbacks := 0
+	txn.OnError(func() {
+		rollbacks++
+	})
+	txn.Discard(ctx)
+	txn.Discard(ctx)
+	require.Equal(t, 1, rollbacks)
+
+	// Discarding a committed transaction doesn't roll it back.
+	txn, err = NewTxnFrom(ctx, rootstore, false)
+	require.NoError(t, err)
+
+	rollbacks = 0
+	txn.OnError(func() {
+		rollbacks++
+	})
+	require.NoError(t, txn.Commit(ctx))
+	txn.Discard(ctx)
+	require.Equal(t, 0, rollbacks)
+}
+
 func TestShimTxnStoreSync(t *testing.T) {
 	ctx := context.Background()
 	opts := badgerds.Options{Options: badger.DefaultOptions("").WithInMemory(true)}
@@ -120,3 +152,95 @@ func TestShimTxnStoreClose(t *testing.T) {
 	err = shimTxn.Close()
 	require.NoError(t, err)
 }
+
+func TestReadCommittedTxnSeesLaterCommits(t *testing.T) {
+	ctx := context.Background()
+	opts := badgerds.Options{Options: badger.DefaultOptions("").WithInMemory(true)}
+	rootstore, err := badgerds.NewDatastore("", &opts)
+	require.NoError(t, err)
+
+	snapshotTxn, err := NewTxnFrom(ctx, rootstore, true)
+	require.NoError(t, err)
+	defer snapshotTxn.Discard(ctx)
+	readCommittedTxn := NewReadCommittedTxnFrom(rootstore)
+	defer readCommittedTxn.Discard(ctx)
+
+	writeTxn, err := NewTxnFrom(ctx, rootstore, false)
+	require.NoError(t, err)
+	err = writeTxn.Datastore().Put(ctx, ds.NewKey("key"), []byte("value"))
+	require.NoError(t, err)
+	err = writeTxn.Commit(ctx)
+	require.NoError(t, err)
+
+	_, err = snapshotTxn.Datastore().Get(ctx, ds.NewKey("key"))
+	require.ErrorIs(t, err, ds.ErrNotFound)
+
+	value, err := readCommittedTxn.Datastore().Get(ctx, ds.NewKey("key"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("value"), value)
+}
+
+func TestReadCommittedTxnWrite(t *testing.T) {
+	ctx := context.Background()
+	opts := badgerds.Options{Options: badger.DefaultOptions("").WithInMemory(true)}
+	rootstore, err := badgerds.NewDatastore("", &opts)
+	require.NoError(t, err)
+
+	txn := NewReadCommittedTxnFrom(rootstore)
+
+	err = txn.Datastore().Put(ctx, ds.NewKey("key"), []byte("value"))
+	require.ErrorIs(t, err, ErrReadOnlyTxn)
+
+	err = txn.Headstore().Delete(ctx, ds.NewKey("key"))
+	require.ErrorIs(t, err, ErrReadOnlyTxn)
+}
+
+func TestReadCommittedTxnOnSuccess(t *testing.T) {
+	ctx := context.Background()
+	opts := badgerds.Options{Options: badger.DefaultOptions("").WithInMemory(true)}
+	rootstore, err := badgerds.NewDatastore("", &opts)
+	require.NoError(t, err)
+
+	txn := NewReadCommittedTxnFrom(rootstore)
+
+	called := false
+	txn.OnSuccess(func() { called = true })
+	err = txn.Commit(ctx)
+	require.NoError(t, err)
+	require.True(t, called)
+}
+
+func TestTxnCommitWithConflict(t *testing.T) {
+	ctx := context.Background()
+	opts := badgerds.Options{Options: badger.DefaultOptions("").WithInMemory(true)}
+	rootstore, err := badgerds.NewDatastore("", &opts)
+	require.NoError(t, err)
+
+	txn1, err := NewTxnFrom(ctx, rootstore, false)
+	require.NoError(t, err)
+	defer txn1.Discard(ctx)
+	_, err = txn1.Datastore().Get(ctx, ds.NewKey("key"))
+	require.ErrorIs(t, err, ds.ErrNotFound)
+
+	txn2, err := NewTxnFrom(ctx, rootstore, false)
+	require.NoError(t, err)
+	err = txn2.Datastore().Put(ctx, ds.NewKey("key"), []byte("value2"))
+	require.NoError(t, err)
+	err = txn2.Commit(ctx)
+	require.NoError(t, err)
+
+	err = txn1.Datastore().Put(ctx, ds.NewKey("key"), []byte("value1"))
+	require.NoError(t, err)
+	err = txn1.Commit(ctx)
+	require.ErrorIs(t, err, ErrTxnConflict)
+	require.ErrorIs(t, err, badgerds.ErrTxnConflict)
+	require.True(t, IsTxnConflict(err))
+}
+
+func TestIsTxnConflict(t *testing.T) {
+	require.True(t, IsTxnConflict(ErrTxnConflict))
+	require.True(t, IsTxnConflict(badgerds.ErrTxnConflict))
+	require.True(t, IsTxnConflict(memory.ErrTxnConflict))
+	require.False(t, IsTxnConflict(ErrReadOnlyTxn))
+	require.False(t, IsTxnConflict(nil))
+}
@@ -12,6 +12,7 @@ package base
 
 import (
 	"bytes"
+	"math"
 	"strings"
 	"time"
 )
@@ -85,7 +86,13 @@ func compareUint(a, b uint64) int {
 	}
 	return -1
 }
+
+// compareFloat compares two floats, NaN being equal to itself and less than any number so that
+// values written before NaN was rejected are ordered deterministically.
 func compareFloat(a, b float64) int {
+	if math.IsNaN(a) || math.IsNaN(b) {
+		return compareBool(!math.IsNaN(a), !math.IsNaN(b))
+	}
 	if a == b {
 		return 0
 	} else if a > b {
@@ -0,0 +1,33 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package base
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareFloatNaN(t *testing.T) {
+	nan := math.NaN()
+
+	assert.Equal(t, 0, Compare(nan, nan))
+	assert.Equal(t, -1, Compare(nan, math.Inf(-1)))
+	assert.Equal(t, 1, Compare(-1.5, nan))
+	assert.Equal(t, -1, Compare(nil, nan))
+}
+
+func TestCompareFloatInfinities(t *testing.T) {
+	assert.Equal(t, 0, Compare(math.Inf(1), math.Inf(1)))
+	assert.Equal(t, 1, Compare(math.Inf(1), math.MaxFloat64))
+	assert.Equal(t, -1, Compare(math.Inf(-1), -math.MaxFloat64))
+}
@@ -0,0 +1,248 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+	"sort"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/datastore/branch"
+)
+
+// CreateBranch creates a branch of the database with the given name.
+func (db *db) CreateBranch(ctx context.Context, name string) (client.Branch, error) {
+	info, err := branch.Create(ctx, db.rootstore, name, db.now())
+	if err != nil {
+		return client.Branch{}, err
+	}
+	return client.Branch{Name: info.Name, CreatedAt: info.CreatedAt}, nil
+}
+
+// GetBranch returns the database of the branch with the given name, opening it if needed.
+//
+// The database of the branch is opened with the clock, schema admin, encryption, masking and
+// request restrictions of the database, but not with its webhooks, retention, quotas and P2P,
+// which do not apply to the branch.
+func (db *db) GetBranch(ctx context.Context, name string) (client.DB, error) {
+	db.branchesMu.Lock()
+	defer db.branchesMu.Unlock()
+
+	if branchDB, ok := db.branches[name]; ok {
+		return branchDB, nil
+	}
+	if _, err := branch.Get(ctx, db.rootstore, name); err != nil {
+		return nil, err
+	}
+	branchDB, err := newDB(ctx, branch.Open(db.rootstore, name), db.branchOptions()...)
+	if err != nil {
+		return nil, err
+	}
+	if db.branches == nil {
+		db.branches = map[string]*implicitTxnDB{}
+	}
+	db.branches[name] = branchDB
+	return branchDB, nil
+}
+
+// branchOptions returns the options of the database that the databases of its branches are
+// opened with.
+func (db *db) branchOptions() []Option {
+	options := []Option{
+		WithClock(db.now),
+		WithSchemaAdmin(db.schemaAdmin),
+		WithKeyring(db.keyring),
+		WithIdentityFieldKeys(db.identityFieldKeys),
+		WithMaxRequestCost(db.maxRequestCost),
+	}
+	if db.maskingRules != nil {
+		rules := make([]MaskingRule, 0, len(db.maskingRules))
+		for _, rule := range db.maskingRules {
+			rules = append(rules, rule)
+		}
+		options = append(options, WithMasking(db.maskingKey, rules...))
+	}
+	for _, hook := range db.requestHooks {
+		options = append(options, WithRequestHook(hook))
+	}
+	return options
+}
+
+// GetAllBranches returns all the branches of the database, ordered by name.
+func (db *db) GetAllBranches(ctx context.Context) ([]client.Branch, error) {
+	infos, err := branch.List(ctx, db.rootstore)
+	if err != nil {
+		return nil, err
+	}
+	branches := make([]client.Branch, len(infos))
+	for i, info := range infos {
+		branches[i] = client.Branch{Name: info.Name, CreatedAt: info.CreatedAt}
+	}
+	return branches, nil
+}
+
+// DiscardBranch closes the database of the branch with the given name if it is open, and deletes
+// the branch.
+func (db *db) DiscardBranch(ctx context.Context, name string) error {
+	db.branchesMu.Lock()
+	defer db.branchesMu.Unlock()
+
+	if branchDB, ok := db.branches[name]; ok {
+		branchDB.Close(ctx)
+		delete(db.branches, name)
+	}
+	return branch.Discard(ctx, db.rootstore, name)
+}
+
+// closeBranches closes the databases of the branches that are open.
+func (db *db) closeBranches(ctx context.Context) {
+	db.branchesMu.Lock()
+	defer db.branchesMu.Unlock()
+
+	for name, branchDB := range db.branches {
+		branchDB.Close(ctx)
+		delete(db.branches, name)
+	}
+}
+
+// DiffBranch returns the differences between the documents and schemas of the branch with the
+// given name and those of the database.
+//
+// The documents are compared by the heads of their composite DAGs, the documents updated on
+// either side since the creation of the branch being reported as changed.
+func (db *db) DiffBranch(ctx context.Context, name string) (client.BranchDiff, error) {
+	branchDB, err := db.GetBranch(ctx, name)
+	if err != nil {
+		return client.BranchDiff{}, err
+	}
+	mainState, err := db.collectionStates(ctx)
+	if err != nil {
+		return client.BranchDiff{}, err
+	}
+	branchState, err := branchDB.(*implicitTxnDB).collectionStates(ctx)
+	if err != nil {
+		return client.BranchDiff{}, err
+	}
+
+	names := []string{}
+	for colName := range mainState {
+		names = append(names, colName)
+	}
+	for colName := range branchState {
+		if _, ok := mainState[colName]; !ok {
+			names = append(names, colName)
+		}
+	}
+	sort.Strings(names)
+
+	diff := client.BranchDiff{Branch: name, Collections: []client.CollectionDiff{}}
+	for _, colName := range names {
+		colDiff := diffCollection(colName, mainState[colName], branchState[colName])
+		if colDiff.MainVersionID == colDiff.BranchVersionID &&
+			len(colDiff.Added) == 0 && len(colDiff.Changed) == 0 && len(colDiff.Removed) == 0 {
+			continue
+		}
+		diff.Collections = append(diff.Collections, colDiff)
+	}
+	return diff, nil
+}
+
+// collectionState is the state of a collection compared by DiffBranch.
+type collectionState struct {
+	// versionID is the schema version ID of the collection.
+	versionID string
+
+	// heads are the sorted CIDs of the heads of each document, by document key.
+	heads map[string][]string
+}
+
+// collectionStates returns the states of all the collections of the database, by collection name,
+// read from the same transaction.
+func (db *db) collectionStates(ctx context.Context) (map[string]collectionState, error) {
+	txn, err := db.NewTxn(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	defer txn.Discard(ctx)
+
+	cols, err := db.getAllCollections(ctx, txn)
+	if err != nil {
+		return nil, err
+	}
+	states := map[string]collectionState{}
+	for _, col := range cols {
+		docs, err := col.WithTxn(txn).GetAllDocKeysWithHeads(ctx)
+		if err != nil {
+			return nil, err
+		}
+		state := collectionState{
+			versionID: col.Schema().VersionID,
+			heads:     map[string][]string{},
+		}
+		for doc := range docs {
+			if doc.Err != nil {
+				return nil, doc.Err
+			}
+			heads := make([]string, len(doc.Heads))
+			for i, head := range doc.Heads {
+				heads[i] = head.String()
+			}
+			sort.Strings(heads)
+			state.heads[doc.Key.String()] = heads
+		}
+		states[col.Name()] = state
+	}
+	return states, nil
+}
+
+// diffCollection returns the difference between the given states of the collection with the
+// given name on the database and on a branch.
+func diffCollection(name string, mainState collectionState, branchState collectionState) client.CollectionDiff {
+	diff := client.CollectionDiff{
+		Name:            name,
+		MainVersionID:   mainState.versionID,
+		BranchVersionID: branchState.versionID,
+		Added:           []string{},
+		Changed:         []string{},
+		Removed:         []string{},
+	}
+	for docKey, branchHeads := range branchState.heads {
+		mainHeads, ok := mainState.heads[docKey]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, docKey)
+		case !sameHeads(mainHeads, branchHeads):
+			diff.Changed = append(diff.Changed, docKey)
+		}
+	}
+	for docKey := range mainState.heads {
+		if _, ok := branchState.heads[docKey]; !ok {
+			diff.Removed = append(diff.Removed, docKey)
+		}
+	}
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Changed)
+	sort.Strings(diff.Removed)
+	return diff
+}
+
+// sameHeads returns true if the given sorted heads are the same.
+func sameHeads(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
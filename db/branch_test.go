@@ -0,0 +1,137 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/datastore/branch"
+)
+
+func TestBranchIsolatesWrites(t *testing.T) {
+	ctx := context.Background()
+	col := newTestCollectionWithDocs(t, ctx, `{"Name": "John", "Age": 21}`)
+	d := &implicitTxnDB{col.(*collection).db}
+
+	created, err := d.CreateBranch(ctx, "dev")
+	require.NoError(t, err)
+	assert.Equal(t, "dev", created.Name)
+
+	dev, err := d.GetBranch(ctx, "dev")
+	require.NoError(t, err)
+	res := dev.ExecRequest(ctx, `mutation { update_users(data: "{\"Age\": 22}") { _key } }`)
+	require.Empty(t, res.GQL.Errors)
+	res = dev.ExecRequest(ctx, `mutation { create_users(data: "{\"Name\": \"Fred\"}") { _key } }`)
+	require.Empty(t, res.GQL.Errors)
+	err = dev.PatchSchema(ctx, `[{"op": "add", "path": "/users/Schema/Fields/-", "value": {"Name": "Email", "Kind": 11}}]`)
+	require.NoError(t, err)
+
+	res = d.ExecRequest(ctx, `query { users { Name Age } }`)
+	require.Empty(t, res.GQL.Errors)
+	assert.Equal(t, []map[string]any{{"Name": "John", "Age": uint64(21)}}, res.GQL.Data)
+	res = dev.ExecRequest(ctx, `query { users(order: {Name: ASC}) { Name Email } }`)
+	require.Empty(t, res.GQL.Errors)
+	assert.Equal(t, []map[string]any{{"Name": "Fred", "Email": nil}, {"Name": "John", "Email": nil}}, res.GQL.Data)
+
+	diff, err := d.DiffBranch(ctx, "dev")
+	require.NoError(t, err)
+	require.Len(t, diff.Collections, 1)
+	colDiff := diff.Collections[0]
+	assert.Equal(t, "users", colDiff.Name)
+	assert.Equal(t, col.Schema().VersionID, colDiff.MainVersionID)
+	assert.NotEqual(t, colDiff.MainVersionID, colDiff.BranchVersionID)
+	assert.Len(t, colDiff.Added, 1)
+	assert.Len(t, colDiff.Changed, 1)
+	assert.Empty(t, colDiff.Removed)
+
+	branches, err := d.GetAllBranches(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []client.Branch{created}, branches)
+}
+
+func TestBranchSharesHistory(t *testing.T) {
+	ctx := context.Background()
+	col := newTestCollectionWithDocs(t, ctx, `{"Name": "John", "Age": 21}`)
+	d := &implicitTxnDB{col.(*collection).db}
+
+	_, err := d.CreateBranch(ctx, "dev")
+	require.NoError(t, err)
+	dev, err := d.GetBranch(ctx, "dev")
+	require.NoError(t, err)
+
+	// The commits made before the creation of the branch are read from the blocks of the
+	// database.
+	res := dev.ExecRequest(ctx, `query { commits(field: "C") { height } }`)
+	require.Empty(t, res.GQL.Errors)
+	assert.Equal(t, []map[string]any{{"height": int64(1)}}, res.GQL.Data)
+}
+
+func TestDiscardBranch(t *testing.T) {
+	ctx := context.Background()
+	col := newTestCollectionWithDocs(t, ctx, `{"Name": "John", "Age": 21}`)
+	d := &implicitTxnDB{col.(*collection).db}
+
+	_, err := d.CreateBranch(ctx, "dev")
+	require.NoError(t, err)
+	_, err = d.GetBranch(ctx, "dev")
+	require.NoError(t, err)
+
+	require.NoError(t, d.DiscardBranch(ctx, "dev"))
+	_, err = d.GetBranch(ctx, "dev")
+	assert.ErrorIs(t, err, branch.ErrBranchNotFound)
+
+	// A branch with the same name starts again from the database.
+	_, err = d.CreateBranch(ctx, "dev")
+	require.NoError(t, err)
+	diff, err := d.DiffBranch(ctx, "dev")
+	require.NoError(t, err)
+	assert.Empty(t, diff.Collections)
+}
+
+func TestCreateBranchWithInvalidName(t *testing.T) {
+	ctx := context.Background()
+	d, err := newMemoryDB(ctx)
+	require.NoError(t, err)
+	defer d.Close(ctx)
+
+	_, err = d.CreateBranch(ctx, "dev/test")
+	assert.ErrorIs(t, err, branch.ErrInvalidBranchName)
+
+	_, err = d.CreateBranch(ctx, "dev")
+	require.NoError(t, err)
+	_, err = d.CreateBranch(ctx, "dev")
+	assert.ErrorIs(t, err, branch.ErrBranchExists)
+}
+
+func TestBranchKeepsOptions(t *testing.T) {
+	ctx := context.Background()
+	_, adminPub, err := crypto.GenerateKeyPair(crypto.Ed25519, 0)
+	require.NoError(t, err)
+	d, err := newMemoryDB(ctx, WithSchemaAdmin(adminPub), WithMasking(nil, MaskingRule{Collection: "users", Field: "Name"}))
+	require.NoError(t, err)
+	defer d.Close(ctx)
+
+	_, err = d.CreateBranch(ctx, "dev")
+	require.NoError(t, err)
+	dev, err := d.GetBranch(ctx, "dev")
+	require.NoError(t, err)
+
+	// The schema updates of the branch must be signed by the schema admin of the database.
+	err = dev.AddSchema(ctx, `type users { Name: String }`)
+	require.ErrorIs(t, err, ErrSchemaSignatureRequired)
+	assert.Equal(t, d.maskingRules, dev.(*implicitTxnDB).maskingRules)
+}
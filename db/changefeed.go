@@ -0,0 +1,249 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	dag "github.com/ipfs/boxo/ipld/merkledag"
+	"github.com/ipfs/go-cid"
+	dsq "github.com/ipfs/go-datastore/query"
+
+	"github.com/sourcenetwork/defradb/core"
+	"github.com/sourcenetwork/defradb/datastore"
+	"github.com/sourcenetwork/defradb/events"
+)
+
+// changefeedEntry is the persisted form of an update recorded in the changefeed of a
+// collection. The block of the update is loaded from the blockstore when read.
+type changefeedEntry struct {
+	DocKey   string `json:"docKey"`
+	Cid      string `json:"cid"`
+	SchemaID string `json:"schemaID"`
+	Priority uint64 `json:"priority"`
+}
+
+// changefeedSequenceName returns the name of the sequence of the changefeed of the given
+// collection.
+func changefeedSequenceName(colID uint32) string {
+	return fmt.Sprintf("changefeed/%d", colID)
+}
+
+// recordUpdate records the given update in the changefeed of the collection, if enabled, as part
+// of the given transaction.
+//
+// It returns the update along with its sequence number within the changefeed.
+func (c *collection) recordUpdate(
+	ctx context.Context,
+	txn datastore.Txn,
+	update events.Update,
+) (events.Update, error) {
+	if !c.db.changefeed {
+		return update, nil
+	}
+
+	seq, err := c.db.getSequence(ctx, txn, changefeedSequenceName(c.colID))
+	if err != nil {
+		return events.Update{}, err
+	}
+	sequence, err := seq.next(ctx, txn)
+	if err != nil {
+		return events.Update{}, err
+	}
+
+	buf, err := json.Marshal(changefeedEntry{
+		DocKey:   update.DocKey,
+		Cid:      update.Cid.String(),
+		SchemaID: update.SchemaID,
+		Priority: update.Priority,
+	})
+	if err != nil {
+		return events.Update{}, err
+	}
+	err = txn.Systemstore().Put(ctx, core.NewChangefeedKey(c.colID, sequence).ToDS(), buf)
+	if err != nil {
+		return events.Update{}, err
+	}
+
+	update.Sequence = sequence
+	return update, nil
+}
+
+// publishUpdate records the given update in the changefeed of the collection, if enabled, and
+// publishes it once the given transaction is committed.
+func (c *collection) publishUpdate(ctx context.Context, txn datastore.Txn, update events.Update) error {
+	update, err := c.recordUpdate(ctx, txn, update)
+	if err != nil {
+		return err
+	}
+
+	if c.db.events.Updates.HasValue() {
+		txn.OnSuccess(
+			func() {
+				c.db.events.Updates.Value().Publish(update)
+			},
+		)
+	}
+	return nil
+}
+
+// SubscribeUpdates returns the updates of the documents of the collection, as recorded in its
+// changefeed, from the update following the given sequence number onwards.
+//
+// The updates are sent in sequence order, the recorded updates first, followed by the new
+// updates as they are committed. The channel is closed once the given context is done or
+// the database is closed.
+func (c *collection) SubscribeUpdates(ctx context.Context, since uint64) (<-chan events.Update, error) {
+	if !c.db.changefeed {
+		return nil, ErrChangefeedDisabled
+	}
+
+	// The update events only notify of new updates, which are read from the changefeed
+	// so that the updates missed while catching up, or dropped, are sent too.
+	sub, err := c.db.events.Updates.Value().Subscribe()
+	if err != nil {
+		return nil, err
+	}
+	notify := make(chan struct{}, 1)
+	go func() {
+		for update := range sub {
+			if update.SchemaID != c.schemaID || update.Sequence == 0 {
+				continue
+			}
+			select {
+			case notify <- struct{}{}:
+			default:
+			}
+		}
+		close(notify)
+	}()
+
+	updates := make(chan events.Update)
+	go func() {
+		defer close(updates)
+
+		last := since
+		for {
+			var err error
+			last, err = c.sendRecordedUpdates(ctx, last, updates)
+			if err != nil {
+				log.ErrorE(ctx, "Failed to read the changefeed", err)
+				c.db.events.Updates.Value().Unsubscribe(sub)
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				c.db.events.Updates.Value().Unsubscribe(sub)
+				return
+			case _, open := <-notify:
+				if !open {
+					// The database has been closed.
+					return
+				}
+			}
+		}
+	}()
+
+	return updates, nil
+}
+
+// sendRecordedUpdates sends the updates recorded in the changefeed of the collection after the
+// given sequence number, returning the sequence number of the last update sent.
+func (c *collection) sendRecordedUpdates(
+	ctx context.Context,
+	since uint64,
+	updates chan<- events.Update,
+) (uint64, error) {
+	txn, err := c.db.NewTxn(ctx, true)
+	if err != nil {
+		return since, err
+	}
+	defer txn.Discard(ctx)
+
+	results, err := txn.Systemstore().Query(ctx, dsq.Query{
+		Prefix: core.NewChangefeedKey(c.colID, 0).ToString(),
+		Filters: []dsq.Filter{
+			dsq.FilterKeyCompare{
+				Op:  dsq.GreaterThan,
+				Key: core.NewChangefeedKey(c.colID, since).ToString(),
+			},
+		},
+		Orders: []dsq.Order{dsq.OrderByKey{}},
+	})
+	if err != nil {
+		return since, err
+	}
+	defer func() {
+		if err := results.Close(); err != nil {
+			log.ErrorE(ctx, "Failed to close changefeed query", err)
+		}
+	}()
+
+	last := since
+	for res := range results.Next() {
+		if res.Error != nil {
+			return last, res.Error
+		}
+		update, err := c.decodeChangefeedEntry(ctx, txn, res.Key, res.Value)
+		if err != nil {
+			return last, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return last, nil
+		case updates <- update:
+		}
+		last = update.Sequence
+	}
+	return last, nil
+}
+
+// decodeChangefeedEntry returns the update recorded with the given changefeed key and value.
+func (c *collection) decodeChangefeedEntry(
+	ctx context.Context,
+	txn datastore.Txn,
+	key string,
+	value []byte,
+) (events.Update, error) {
+	changefeedKey, err := core.NewChangefeedKeyFromString(key)
+	if err != nil {
+		return events.Update{}, err
+	}
+	var entry changefeedEntry
+	if err := json.Unmarshal(value, &entry); err != nil {
+		return events.Update{}, err
+	}
+	updateCid, err := cid.Decode(entry.Cid)
+	if err != nil {
+		return events.Update{}, err
+	}
+	block, err := txn.DAGstore().Get(ctx, updateCid)
+	if err != nil {
+		return events.Update{}, err
+	}
+	node, err := dag.DecodeProtobufBlock(block)
+	if err != nil {
+		return events.Update{}, err
+	}
+
+	return events.Update{
+		DocKey:   entry.DocKey,
+		Cid:      updateCid,
+		SchemaID: entry.SchemaID,
+		Block:    node,
+		Priority: entry.Priority,
+		Sequence: changefeedKey.Sequence,
+	}, nil
+}
@@ -0,0 +1,234 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	badger "github.com/dgraph-io/badger/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/client"
+	badgerds "github.com/sourcenetwork/defradb/datastore/badger/v3"
+	"github.com/sourcenetwork/defradb/events"
+)
+
+func newChangefeedTestCollection(t *testing.T, ctx context.Context, db client.DB) client.Collection {
+	err := db.AddSchema(ctx, `type users { Name: String Age: Int }`)
+	require.NoError(t, err)
+	col, err := db.GetCollectionByName(ctx, "users")
+	require.NoError(t, err)
+	return col
+}
+
+// closeUpdates cancels the subscription to the changefeed and waits for its channel to be closed.
+func closeUpdates(cancel context.CancelFunc, updates <-chan events.Update) {
+	cancel()
+	for range updates {
+		// The channel is closed once the subscription has ended.
+	}
+}
+
+// receiveUpdates returns the given number of updates received from the given channel.
+func receiveUpdates(t *testing.T, updates <-chan events.Update, count int) []events.Update {
+	received := make([]events.Update, 0, count)
+	for len(received) < count {
+		select {
+		case update, open := <-updates:
+			require.True(t, open, "the updates channel has been closed")
+			received = append(received, update)
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "timed out waiting for updates", "received: %v", len(received))
+		}
+	}
+	return received
+}
+
+func TestSubscribeUpdatesReturnsRecordedUpdatesInOrder(t *testing.T) {
+	ctx := context.Background()
+	db, err := newMemoryDB(ctx, WithChangefeed())
+	require.NoError(t, err)
+	defer db.Close(ctx)
+	col := newChangefeedTestCollection(t, ctx, db)
+
+	docs := newTestDocs(t, 2)
+	for _, doc := range docs {
+		require.NoError(t, col.Create(ctx, doc))
+	}
+	require.NoError(t, docs[0].Set("Age", 30))
+	require.NoError(t, col.Update(ctx, docs[0]))
+	_, err = col.Delete(ctx, docs[1].Key())
+	require.NoError(t, err)
+
+	subCtx, cancel := context.WithCancel(ctx)
+	updates, err := col.SubscribeUpdates(subCtx, 0)
+	require.NoError(t, err)
+	defer closeUpdates(cancel, updates)
+
+	received := receiveUpdates(t, updates, 4)
+	expectedDocKeys := []string{
+		docs[0].Key().String(),
+		docs[1].Key().String(),
+		docs[0].Key().String(),
+		docs[1].Key().String(),
+	}
+	for i, update := range received {
+		assert.Equal(t, uint64(i+1), update.Sequence)
+		assert.Equal(t, expectedDocKeys[i], update.DocKey)
+		assert.Equal(t, col.SchemaID(), update.SchemaID)
+		require.NotNil(t, update.Block)
+		assert.Equal(t, update.Cid, update.Block.Cid())
+	}
+	assert.Equal(t, docs[0].Head(), received[2].Cid)
+	assert.Equal(t, uint64(2), received[2].Priority)
+}
+
+func TestSubscribeUpdatesResumesAfterGivenSequence(t *testing.T) {
+	ctx := context.Background()
+	db, err := newMemoryDB(ctx, WithChangefeed())
+	require.NoError(t, err)
+	defer db.Close(ctx)
+	col := newChangefeedTestCollection(t, ctx, db)
+
+	docs := newTestDocs(t, 4)
+	for _, doc := range docs {
+		require.NoError(t, col.Create(ctx, doc))
+	}
+
+	subCtx, cancel := context.WithCancel(ctx)
+	updates, err := col.SubscribeUpdates(subCtx, 2)
+	require.NoError(t, err)
+	defer closeUpdates(cancel, updates)
+
+	received := receiveUpdates(t, updates, 2)
+	assert.Equal(t, uint64(3), received[0].Sequence)
+	assert.Equal(t, docs[2].Key().String(), received[0].DocKey)
+	assert.Equal(t, uint64(4), received[1].Sequence)
+	assert.Equal(t, docs[3].Key().String(), received[1].DocKey)
+}
+
+func TestSubscribeUpdatesSendsNewUpdates(t *testing.T) {
+	ctx := context.Background()
+	db, err := newMemoryDB(ctx, WithChangefeed())
+	require.NoError(t, err)
+	defer db.Close(ctx)
+	col := newChangefeedTestCollection(t, ctx, db)
+
+	docs := newTestDocs(t, 5)
+	require.NoError(t, col.Create(ctx, docs[0]))
+
+	subCtx, cancel := context.WithCancel(ctx)
+	updates, err := col.SubscribeUpdates(subCtx, 0)
+	require.NoError(t, err)
+	defer closeUpdates(cancel, updates)
+
+	require.NoError(t, col.CreateBatched(ctx, docs[1:3], 1))
+	require.NoError(t, col.CreateMany(ctx, docs[3:]))
+
+	received := receiveUpdates(t, updates, 5)
+	for i, update := range received {
+		assert.Equal(t, uint64(i+1), update.Sequence)
+		assert.Equal(t, docs[i].Key().String(), update.DocKey)
+	}
+}
+
+func TestSubscribeUpdatesIsolatesCollections(t *testing.T) {
+	ctx := context.Background()
+	db, err := newMemoryDB(ctx, WithChangefeed())
+	require.NoError(t, err)
+	defer db.Close(ctx)
+	users := newChangefeedTestCollection(t, ctx, db)
+	err = db.AddSchema(ctx, `type books { Name: String }`)
+	require.NoError(t, err)
+	books, err := db.GetCollectionByName(ctx, "books")
+	require.NoError(t, err)
+
+	book, err := client.NewDocFromJSON([]byte(`{"Name": "Painted House"}`))
+	require.NoError(t, err)
+	require.NoError(t, books.Create(ctx, book))
+	docs := newTestDocs(t, 1)
+	require.NoError(t, users.Create(ctx, docs[0]))
+
+	subCtx, cancel := context.WithCancel(ctx)
+	updates, err := users.SubscribeUpdates(subCtx, 0)
+	require.NoError(t, err)
+	defer closeUpdates(cancel, updates)
+
+	received := receiveUpdates(t, updates, 1)
+	assert.Equal(t, uint64(1), received[0].Sequence)
+	assert.Equal(t, docs[0].Key().String(), received[0].DocKey)
+}
+
+func TestChangefeedIsPersisted(t *testing.T) {
+	ctx := context.Background()
+	path := t.TempDir()
+	opts := badgerds.Options{Options: badger.DefaultOptions(path)}
+
+	rootstore, err := badgerds.NewDatastore(path, &opts)
+	require.NoError(t, err)
+	db, err := newDB(ctx, rootstore, WithChangefeed())
+	require.NoError(t, err)
+	col := newChangefeedTestCollection(t, ctx, db)
+	docs := newTestDocs(t, 2)
+	require.NoError(t, col.Create(ctx, docs[0]))
+	db.Close(ctx)
+
+	rootstore, err = badgerds.NewDatastore(path, &opts)
+	require.NoError(t, err)
+	db, err = newDB(ctx, rootstore, WithChangefeed())
+	require.NoError(t, err)
+	defer db.Close(ctx)
+	col, err = db.GetCollectionByName(ctx, "users")
+	require.NoError(t, err)
+	require.NoError(t, col.Create(ctx, docs[1]))
+
+	subCtx, cancel := context.WithCancel(ctx)
+	updates, err := col.SubscribeUpdates(subCtx, 0)
+	require.NoError(t, err)
+	defer closeUpdates(cancel, updates)
+
+	received := receiveUpdates(t, updates, 2)
+	assert.Equal(t, uint64(1), received[0].Sequence)
+	assert.Equal(t, docs[0].Key().String(), received[0].DocKey)
+	assert.Equal(t, uint64(2), received[1].Sequence)
+	assert.Equal(t, docs[1].Key().String(), received[1].DocKey)
+}
+
+func TestSubscribeUpdatesWithoutChangefeed(t *testing.T) {
+	ctx := context.Background()
+	db, err := newMemoryDB(ctx, WithUpdateEvents())
+	require.NoError(t, err)
+	defer db.Close(ctx)
+	col := newChangefeedTestCollection(t, ctx, db)
+
+	_, err = col.SubscribeUpdates(ctx, 0)
+	require.ErrorIs(t, err, ErrChangefeedDisabled)
+}
+
+func TestUpdateEventsHaveNoSequenceWithoutChangefeed(t *testing.T) {
+	ctx := context.Background()
+	db, err := newMemoryDB(ctx, WithUpdateEvents())
+	require.NoError(t, err)
+	defer db.Close(ctx)
+	col := newChangefeedTestCollection(t, ctx, db)
+
+	sub, err := db.Events().Updates.Value().Subscribe()
+	require.NoError(t, err)
+	defer db.Events().Updates.Value().Unsubscribe(sub)
+
+	require.NoError(t, col.Create(ctx, newTestDocs(t, 1)[0]))
+
+	update := <-sub
+	assert.Equal(t, uint64(0), update.Sequence)
+}
@@ -28,11 +28,13 @@ import (
 	"github.com/sourcenetwork/defradb/client"
 	"github.com/sourcenetwork/defradb/client/request"
 	"github.com/sourcenetwork/defradb/core"
+	corecrdt "github.com/sourcenetwork/defradb/core/crdt"
 	"github.com/sourcenetwork/defradb/datastore"
 	"github.com/sourcenetwork/defradb/db/base"
 	"github.com/sourcenetwork/defradb/errors"
 	"github.com/sourcenetwork/defradb/events"
 	"github.com/sourcenetwork/defradb/logging"
+	"github.com/sourcenetwork/defradb/merkle/clock"
 	"github.com/sourcenetwork/defradb/merkle/crdt"
 )
 
@@ -51,6 +53,10 @@ type collection struct {
 	// of the operation in question.
 	txn immutable.Option[datastore.Txn]
 
+	// ingest holds the documents saved by a [collection.CreateBatched] batch, for which the
+	// post-commit work is done once per batch instead of once per document.
+	ingest *ingestBatch
+
 	colID uint32
 
 	schemaID string
@@ -180,6 +186,11 @@ func (db *db) createCollection(
 		return nil, err
 	}
 
+	err = col.recordEmptyQuotaUsage(ctx, txn)
+	if err != nil {
+		return nil, err
+	}
+
 	log.Debug(
 		ctx,
 		"Created collection",
@@ -227,16 +238,10 @@ func (db *db) updateCollection(
 		}
 	}
 
-	globalSchemaBuf, err := json.Marshal(desc.Schema)
-	if err != nil {
-		return nil, err
-	}
-
-	cid, err := core.NewSHA256CidV1(globalSchemaBuf)
+	schemaVersionID, err := newSchemaVersionID(desc.Schema)
 	if err != nil {
 		return nil, err
 	}
-	schemaVersionID := cid.String()
 	desc.Schema.VersionID = schemaVersionID
 
 	buf, err := json.Marshal(desc)
@@ -305,6 +310,17 @@ func (db *db) validateUpdateCollection(
 		return false, NewErrCannotModifySchemaName(existingDesc.Schema.Name, proposedDesc.Schema.Name)
 	}
 
+	if proposedDesc.Schema.AppendOnly != existingDesc.Schema.AppendOnly {
+		// The documents of append-only collections may have been relied upon not to change.
+		return false, NewErrCannotModifyAppendOnly(existingDesc.Schema.Name, existingDesc.Schema.AppendOnly)
+	}
+
+	if proposedDesc.Schema.CostMultiplier < 0 {
+		return false, NewErrInvalidCostMultiplier(proposedDesc.Schema.Name, proposedDesc.Schema.CostMultiplier)
+	}
+	// The cost multipliers may be tuned without changing the fields.
+	hasChanged = hasChanged || proposedDesc.Schema.CostMultiplier != existingDesc.Schema.CostMultiplier
+
 	if proposedDesc.Schema.VersionID != "" && proposedDesc.Schema.VersionID != existingDesc.Schema.VersionID {
 		// If users specify this it will be overwritten, an error is prefered to quietly ignoring it.
 		return false, ErrCannotSetVersionID
@@ -480,12 +496,24 @@ func (c *collection) GetAllDocKeys(ctx context.Context) (<-chan client.DocKeysRe
 		return nil, err
 	}
 
-	return c.getAllDocKeysChan(ctx, txn)
+	return c.getAllDocKeysChan(ctx, txn, false)
+}
+
+// GetAllDocKeysWithHeads returns all the document keys that exist in the collection,
+// along with the CIDs of their current heads.
+func (c *collection) GetAllDocKeysWithHeads(ctx context.Context) (<-chan client.DocKeysResult, error) {
+	txn, err := c.getTxn(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.getAllDocKeysChan(ctx, txn, true)
 }
 
 func (c *collection) getAllDocKeysChan(
 	ctx context.Context,
 	txn datastore.Txn,
+	withHeads bool,
 ) (<-chan client.DocKeysResult, error) {
 	prefix := core.PrimaryDataStoreKey{ // empty path for all keys prefix
 		CollectionId: fmt.Sprint(c.colID),
@@ -528,12 +556,29 @@ func (c *collection) getAllDocKeysChan(
 			key, err := client.NewDocKeyFromString(rawDocKey)
 			if err != nil {
 				resCh <- client.DocKeysResult{
-					Err: res.Error,
+					Err: err,
 				}
 				return
 			}
+
+			var heads []cid.Cid
+			if withHeads {
+				headset := clock.NewHeadSet(
+					txn.Headstore(),
+					core.DataStoreKeyFromDocKey(key).WithFieldId(core.COMPOSITE_NAMESPACE).ToHeadStoreKey(),
+				)
+				heads, _, err = headset.List(ctx)
+				if err != nil {
+					resCh <- client.DocKeysResult{
+						Err: NewErrFailedToGetHeads(err),
+					}
+					return
+				}
+			}
+
 			resCh <- client.DocKeysResult{
-				Key: key,
+				Key:   key,
+				Heads: heads,
 			}
 		}
 	}()
@@ -674,7 +719,7 @@ func (c *collection) create(ctx context.Context, txn datastore.Txn, doc *client.
 		return err
 	}
 
-	return err
+	return c.enforceQuota(ctx, txn, primaryKey.DocKey)
 }
 
 // Update an existing document with the new values.
@@ -717,7 +762,7 @@ func (c *collection) update(ctx context.Context, txn datastore.Txn, doc *client.
 	if err != nil {
 		return err
 	}
-	return nil
+	return c.enforceQuota(ctx, txn, doc.Key().String())
 }
 
 // Save a document into the db.
@@ -755,14 +800,26 @@ func (c *collection) save(
 	doc *client.Document,
 	isCreate bool,
 ) (cid.Cid, error) {
+	if !isCreate {
+		if err := c.checkUpdatable(); err != nil {
+			return cid.Undef, err
+		}
+	}
+	ctx, err := c.db.withFieldKeyring(ctx)
+	if err != nil {
+		return cid.Undef, err
+	}
+
 	// NOTE: We delay the final Clean() call until we know
 	// the commit on the transaction is successful. If we didn't
 	// wait, and just did it here, then *if* the commit fails down
 	// the line, then we have no way to roll back the state
 	// side-effect on the document func called here.
-	txn.OnSuccess(func() {
-		doc.Clean()
-	})
+	if c.ingest == nil {
+		txn.OnSuccess(func() {
+			doc.Clean()
+		})
+	}
 
 	// New batch transaction/store (optional/todo)
 	// Ensute/Set doc object marker
@@ -789,8 +846,10 @@ func (c *collection) save(
 				return cid.Undef, client.NewErrFieldNotExist(k)
 			}
 
+			// A secondary relation ID set to nil clears the value held by this document itself, as
+			// written under a schema in which this was the primary side of the relation.
 			relationFieldDescription, isSecondaryRelationID := c.isSecondaryIDField(fieldDescription)
-			if isSecondaryRelationID {
+			if isSecondaryRelationID && val.Value() != nil {
 				primaryId := val.Value().(string)
 
 				err = c.patchPrimaryDoc(ctx, txn, relationFieldDescription, primaryKey.DocKey, primaryId)
@@ -803,6 +862,18 @@ func (c *collection) save(
 				continue
 			}
 
+			if !val.IsDelete() {
+				err = validateIntegerValue(fieldDescription, val.Value())
+				if err != nil {
+					return cid.Undef, err
+				}
+			}
+
+			val, err = c.encryptFieldValue(ctx, fieldDescription, val)
+			if err != nil {
+				return cid.Undef, err
+			}
+
 			node, _, err := c.saveDocValue(ctx, txn, fieldKey, val)
 			if err != nil {
 				return cid.Undef, err
@@ -843,20 +914,26 @@ func (c *collection) save(
 		return cid.Undef, err
 	}
 
-	if c.db.events.Updates.HasValue() {
-		txn.OnSuccess(
-			func() {
-				c.db.events.Updates.Value().Publish(
-					events.Update{
-						DocKey:   doc.Key().String(),
-						Cid:      headNode.Cid(),
-						SchemaID: c.schemaID,
-						Block:    headNode,
-						Priority: priority,
-					},
-				)
-			},
-		)
+	update := events.Update{
+		DocKey:   doc.Key().String(),
+		Cid:      headNode.Cid(),
+		SchemaID: c.schemaID,
+		Block:    headNode,
+		Priority: priority,
+	}
+
+	if c.ingest != nil {
+		update, err = c.recordUpdate(ctx, txn, update)
+		if err != nil {
+			return cid.Undef, err
+		}
+		c.ingest.add(doc, update)
+		return headNode.Cid(), nil
+	}
+
+	err = c.publishUpdate(ctx, txn, update)
+	if err != nil {
+		return cid.Undef, err
 	}
 
 	txn.OnSuccess(func() {
@@ -871,6 +948,10 @@ func (c *collection) save(
 // If the document doesn't exist, then it will return false, and a ErrDocumentNotFound error.
 // This operation will all state relating to the given DocKey. This includes data, block, and head storage.
 func (c *collection) Delete(ctx context.Context, key client.DocKey) (bool, error) {
+	if err := c.checkUpdatable(); err != nil {
+		return false, err
+	}
+
 	txn, err := c.getTxn(ctx, false)
 	if err != nil {
 		return false, err
@@ -889,7 +970,7 @@ func (c *collection) Delete(ctx context.Context, key client.DocKey) (bool, error
 		return false, ErrDocumentDeleted
 	}
 
-	err = c.applyDelete(ctx, txn, primaryKey)
+	err = c.applyDelete(ctx, txn, primaryKey, client.Deleted)
 	if err != nil {
 		return false, err
 	}
@@ -965,6 +1046,7 @@ func (c *collection) saveValueToMerkleCRDT(
 	key core.DataStoreKey,
 	ctype client.CType,
 	args ...any) (ipld.Node, uint64, error) {
+	ctx = corecrdt.WithDeltaCipher(ctx, c.db.keyring.Cipher(c.Name()))
 	switch ctype {
 	case client.LWW_REGISTER:
 		merkleCRDT, err := c.db.crdtFactory.InstanceWithStores(
@@ -991,6 +1073,12 @@ func (c *collection) saveValueToMerkleCRDT(
 		lwwreg := merkleCRDT.(*crdt.MerkleLWWRegister)
 		return lwwreg.Set(ctx, bytes)
 	case client.COMPOSITE:
+		if c.ingest == nil {
+			txn.OnSuccess(func() {
+				c.db.updates.markUpdated(c.colID, c.db.now())
+			})
+		}
+
 		key = key.WithFieldId(core.COMPOSITE_NAMESPACE)
 		merkleCRDT, err := c.db.crdtFactory.InstanceWithStores(
 			txn,
@@ -1016,16 +1104,36 @@ func (c *collection) saveValueToMerkleCRDT(
 			return nil, 0, ErrUnknownCRDTArgument
 		}
 		comp := merkleCRDT.(*crdt.MerkleCompositeDAG)
+		status := client.Active
 		if len(args) > 2 {
-			status, ok := args[2].(client.DocumentStatus)
+			status, ok = args[2].(client.DocumentStatus)
 			if !ok {
 				return nil, 0, ErrUnknownCRDTArgument
 			}
-			if status.IsDeleted() {
-				return comp.Delete(ctx, links)
-			}
 		}
-		return comp.Set(ctx, bytes, links)
+
+		var node ipld.Node
+		var priority uint64
+		if status.IsDeleted() {
+			node, priority, err = comp.Delete(ctx, links, status)
+		} else {
+			node, priority, err = comp.Set(ctx, bytes, links)
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+
+		// Record the time of the commit, so that the document can be requested as it was
+		// at a given time.
+		err = txn.Systemstore().Put(
+			ctx,
+			core.NewCommitTimeKey(key.DocKey, c.db.now(), node.Cid()).ToDS(),
+			[]byte{status.UInt8()},
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		return node, priority, nil
 	}
 	return nil, 0, ErrUnknownCRDT
 }
@@ -0,0 +1,55 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+
+	corecrdt "github.com/sourcenetwork/defradb/core/crdt"
+	"github.com/sourcenetwork/defradb/datastore"
+)
+
+// checkUpdatable returns an error if the collection is append-only, its documents not being
+// updatable nor deletable.
+func (c *collection) checkUpdatable() error {
+	if c.desc.Schema.AppendOnly {
+		return NewErrAppendOnlyCollection(c.Name())
+	}
+	return nil
+}
+
+// validateAppendOnlyMerge returns an error if the collection is append-only and the given
+// commit to merge is not the creation of a document unknown to the store.
+//
+// Append-only collections only accept new documents, so that their DAGs are proof that the
+// documents were never changed. The deletes of the documents pruned by the retention policies and
+// quotas of other nodes are rejected as well, each node pruning its own documents by its own
+// policies.
+func (c *collection) validateAppendOnlyMerge(
+	ctx context.Context,
+	txn datastore.Txn,
+	delta *corecrdt.CompositeDAGDelta,
+) error {
+	if !c.desc.Schema.AppendOnly {
+		return nil
+	}
+	if !delta.IsCreation() {
+		return NewErrAppendOnlyCollection(c.Name())
+	}
+	found, _, err := c.exists(ctx, txn, c.getPrimaryKey(string(delta.DocKey)))
+	if err != nil {
+		return err
+	}
+	if found {
+		return NewErrAppendOnlyCollection(c.Name())
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/client"
+)
+
+func newAppendOnlyCollection(
+	t *testing.T,
+	ctx context.Context,
+	options ...Option,
+) (*implicitTxnDB, client.Collection) {
+	db, err := newMemoryDB(ctx, options...)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close(ctx) })
+
+	err = db.AddSchema(ctx, `type Log @appendOnly { Message: String }`)
+	require.NoError(t, err)
+	col, err := db.GetCollectionByName(ctx, "Log")
+	require.NoError(t, err)
+	require.True(t, col.Schema().AppendOnly)
+	return db, col
+}
+
+func TestAppendOnlyCollectionRejectsUpdatesAndDeletes(t *testing.T) {
+	ctx := context.Background()
+	db, col := newAppendOnlyCollection(t, ctx)
+
+	res := db.ExecRequest(ctx, `mutation { create_Log(data: "{\"Message\": \"started\"}") { _key } }`)
+	require.Empty(t, res.GQL.Errors)
+	docKey := res.GQL.Data.([]map[string]any)[0]["_key"].(string)
+
+	res = db.ExecRequest(
+		ctx,
+		fmt.Sprintf(`mutation { update_Log(id: %q, data: "{\"Message\": \"stopped\"}") { _key } }`, docKey),
+	)
+	require.Len(t, res.GQL.Errors, 1)
+	assert.ErrorIs(t, res.GQL.Errors[0], ErrAppendOnlyCollection)
+
+	res = db.ExecRequest(ctx, fmt.Sprintf(`mutation { delete_Log(id: %q) { _key } }`, docKey))
+	require.Len(t, res.GQL.Errors, 1)
+	assert.ErrorIs(t, res.GQL.Errors[0], ErrAppendOnlyCollection)
+
+	key, err := client.NewDocKeyFromString(docKey)
+	require.NoError(t, err)
+	doc, err := col.Get(ctx, key, false)
+	require.NoError(t, err)
+	err = doc.Set("Message", "stopped")
+	require.NoError(t, err)
+	err = col.Save(ctx, doc)
+	assert.ErrorIs(t, err, ErrAppendOnlyCollection)
+
+	_, err = col.Delete(ctx, key)
+	assert.ErrorIs(t, err, ErrAppendOnlyCollection)
+
+	res = db.ExecRequest(ctx, `query { Log { Message } }`)
+	require.Empty(t, res.GQL.Errors)
+	assert.Equal(t, []map[string]any{{"Message": "started"}}, res.GQL.Data)
+}
+
+func TestAppendOnlyCollectionMergesOnlyNewDocuments(t *testing.T) {
+	ctx := context.Background()
+	_, offline := newAppendOnlyCollection(t, ctx)
+	_, col := newAppendOnlyCollection(t, ctx)
+	require.Equal(t, offline.SchemaID(), col.SchemaID())
+
+	doc, err := client.NewDocFromJSON([]byte(`{"Message": "started"}`))
+	require.NoError(t, err)
+	err = offline.Create(ctx, doc)
+	require.NoError(t, err)
+
+	root, err := col.MergeBlocks(ctx, getCommitBlocks(t, ctx, offline, doc.Head()))
+	require.NoError(t, err)
+	assert.Equal(t, doc.Head(), root)
+
+	// A tampering peer could still commit an update, which must not be merged.
+	tampering := offline.(*collection)
+	tampering.desc.Schema.AppendOnly = false
+	err = doc.Set("Message", "stopped")
+	require.NoError(t, err)
+	err = tampering.Update(ctx, doc)
+	require.NoError(t, err)
+
+	_, err = col.MergeBlocks(ctx, getCommitBlocks(t, ctx, offline, doc.Head()))
+	assert.ErrorIs(t, err, ErrAppendOnlyCollection)
+
+	merged, err := col.Get(ctx, doc.Key(), false)
+	require.NoError(t, err)
+	message, err := merged.Get("Message")
+	require.NoError(t, err)
+	assert.Equal(t, "started", message)
+}
+
+func TestAppendOnlyCollectionDoesNotMergePrunes(t *testing.T) {
+	ctx := context.Background()
+	_, offline := newAppendOnlyCollection(
+		t,
+		ctx,
+		WithQuotas(CollectionQuota{Collection: "Log", MaxDocuments: 1, Prune: true}),
+	)
+	_, col := newAppendOnlyCollection(t, ctx)
+
+	doc, err := client.NewDocFromJSON([]byte(`{"Message": "started"}`))
+	require.NoError(t, err)
+	err = offline.Create(ctx, doc)
+	require.NoError(t, err)
+	_, err = col.MergeBlocks(ctx, getCommitBlocks(t, ctx, offline, doc.Head()))
+	require.NoError(t, err)
+
+	// The other node prunes the document by its own quota, which must not be merged.
+	next, err := client.NewDocFromJSON([]byte(`{"Message": "stopped"}`))
+	require.NoError(t, err)
+	err = offline.Create(ctx, next)
+	require.NoError(t, err)
+	heads := getDocHeads(t, ctx, offline)[doc.Key().String()]
+	require.Len(t, heads, 1)
+	require.NotEqual(t, doc.Head(), heads[0])
+
+	_, err = col.MergeBlocks(ctx, getCommitBlocks(t, ctx, offline, heads[0]))
+	assert.ErrorIs(t, err, ErrAppendOnlyCollection)
+
+	_, err = col.Get(ctx, doc.Key(), false)
+	assert.NoError(t, err)
+}
+
+func TestPatchSchemaCannotModifyAppendOnly(t *testing.T) {
+	ctx := context.Background()
+	db, _ := newAppendOnlyCollection(t, ctx)
+
+	err := db.PatchSchema(ctx, `[{"op": "replace", "path": "/Log/Schema/AppendOnly", "value": false}]`)
+	assert.ErrorIs(t, err, ErrCannotModifyAppendOnly)
+}
@@ -0,0 +1,156 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+
+	"github.com/ipfs/go-cid"
+	mh "github.com/multiformats/go-multihash"
+	"github.com/sourcenetwork/immutable"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/client/request"
+	"github.com/sourcenetwork/defradb/datastore"
+	"github.com/sourcenetwork/defradb/db/base"
+)
+
+// cloneCollection creates a collection with the current schema of the source collection of the
+// given options under its new name, and copies the documents of the source collection matching its
+// filter into it.
+func (db *db) cloneCollection(
+	ctx context.Context,
+	txn datastore.Txn,
+	opts client.CloneCollectionOptions,
+) (client.CloneCollectionResult, error) {
+	if db.schemaAdmin != nil {
+		return client.CloneCollectionResult{}, ErrSchemaSignatureRequired
+	}
+	if opts.Name == "" {
+		return client.CloneCollectionResult{}, ErrCollectionNameEmpty
+	}
+
+	col, err := db.getCollectionByName(ctx, txn, opts.Source)
+	if err != nil {
+		return client.CloneCollectionResult{}, err
+	}
+	source := col.(*collection)
+	for _, field := range source.Schema().Fields {
+		if field.IsObject() || field.RelationName != "" {
+			return client.CloneCollectionResult{}, NewErrCloneOfRelatedCollection(source.Name(), field.Name)
+		}
+	}
+
+	docKeys, err := source.getClonedDocKeys(ctx, txn, opts.Filter)
+	if err != nil {
+		return client.CloneCollectionResult{}, err
+	}
+
+	desc := source.Description()
+	desc.Name = opts.Name
+	desc.ID = 0
+	desc.Schema.Name = opts.Name
+	desc.Schema.SchemaID = ""
+	desc.Schema.VersionID = ""
+	desc.Schema.Fields = append([]client.FieldDescription{}, desc.Schema.Fields...)
+	col, err = db.createCollection(ctx, txn, desc)
+	if err != nil {
+		return client.CloneCollectionResult{}, err
+	}
+	clone := col.(*collection)
+
+	for _, docKey := range docKeys {
+		doc, err := source.get(ctx, txn, source.getPrimaryKey(docKey), false)
+		if err != nil {
+			return client.CloneCollectionResult{}, err
+		}
+		if err := clone.createClone(ctx, txn, doc); err != nil {
+			return client.CloneCollectionResult{}, err
+		}
+	}
+
+	result := client.CloneCollectionResult{
+		Collection: clone.Description(),
+		Documents:  len(docKeys),
+	}
+	return result, db.loadSchema(ctx, txn)
+}
+
+// getClonedDocKeys returns the keys of the documents of the collection matching the given filter,
+// or of all its documents if it is empty.
+func (c *collection) getClonedDocKeys(ctx context.Context, txn datastore.Txn, filter string) ([]string, error) {
+	var planFilter any = immutable.None[request.Filter]()
+	if filter != "" {
+		planFilter = filter
+	}
+	selectionPlan, err := c.makeSelectionPlan(ctx, txn, planFilter)
+	if err != nil {
+		return nil, err
+	}
+	if err := selectionPlan.Start(); err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := selectionPlan.Close(); err != nil {
+			log.ErrorE(ctx, "Failed to close the selection plan, after clone", err)
+		}
+	}()
+
+	docKeys := []string{}
+	for {
+		next, err := selectionPlan.Next()
+		if err != nil {
+			return nil, err
+		}
+		if !next {
+			return docKeys, nil
+		}
+		doc := selectionPlan.Value()
+		docKeys = append(docKeys, doc.GetKey())
+	}
+}
+
+// createClone creates a copy of the given document of another collection in the collection.
+//
+// As documents with the same content have the same key regardless of their collection, the key of
+// the copy is derived from the key of the document and the schema of the collection instead, so
+// that the copy has a history of its own.
+func (c *collection) createClone(ctx context.Context, txn datastore.Txn, doc *client.Document) error {
+	pref := cid.Prefix{
+		Version:  1,
+		Codec:    cid.Raw,
+		MhType:   mh.SHA2_256,
+		MhLength: -1, // default length
+	}
+	keyCid, err := pref.Sum([]byte(c.schemaID + "/" + doc.Key().String()))
+	if err != nil {
+		return err
+	}
+
+	values, err := doc.ToMap()
+	if err != nil {
+		return err
+	}
+	values[request.KeyFieldName] = client.NewDocKeyV0(keyCid).String()
+	clone, err := client.NewDocFromMap(values)
+	if err != nil {
+		return err
+	}
+
+	if len(clone.Values()) == 0 {
+		valueKey := c.getDSKeyFromDockey(clone.Key())
+		if err := txn.Datastore().Put(ctx, valueKey.ToDS(), []byte{base.ObjectMarker}); err != nil {
+			return err
+		}
+	}
+	_, err = c.save(ctx, txn, clone, true)
+	return err
+}
@@ -0,0 +1,82 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/client"
+)
+
+func TestCloneCollection(t *testing.T) {
+	ctx := context.Background()
+	col := newTestCollectionWithDocs(
+		t,
+		ctx,
+		`{"Name": "John", "Age": 21}`,
+		`{"Name": "Islam", "Age": 33}`,
+		`{"Name": "Fred", "Age": 40}`,
+	)
+	d := &implicitTxnDB{col.(*collection).db}
+
+	result, err := d.CloneCollection(ctx, client.CloneCollectionOptions{
+		Source: "users",
+		Name:   "staging",
+		Filter: `{Age: {_gt: 30}}`,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Documents)
+	assert.Equal(t, "staging", result.Collection.Name)
+	assert.NotEqual(t, col.SchemaID(), result.Collection.Schema.SchemaID)
+
+	res := d.ExecRequest(ctx, `query { staging(order: {Age: ASC}) { Name Age } }`)
+	require.Empty(t, res.GQL.Errors)
+	assert.Equal(t, []map[string]any{
+		{"Name": "Islam", "Age": uint64(33)},
+		{"Name": "Fred", "Age": uint64(40)},
+	}, res.GQL.Data)
+
+	// The copies have a history of their own, so that updating them leaves the source untouched.
+	res = d.ExecRequest(ctx, `mutation { update_staging(data: "{\"Age\": 50}") { _key } }`)
+	require.Empty(t, res.GQL.Errors)
+	res = d.ExecRequest(ctx, `query { users(filter: {Age: {_eq: 50}}) { Name } }`)
+	require.Empty(t, res.GQL.Errors)
+	assert.Empty(t, res.GQL.Data)
+}
+
+func TestCloneCollectionToExistingName(t *testing.T) {
+	ctx := context.Background()
+	col := newTestCollectionWithDocs(t, ctx)
+	d := &implicitTxnDB{col.(*collection).db}
+
+	_, err := d.CloneCollection(ctx, client.CloneCollectionOptions{Source: "users", Name: "users"})
+	assert.ErrorIs(t, err, ErrCollectionAlreadyExists)
+}
+
+func TestCloneCollectionWithRelation(t *testing.T) {
+	ctx := context.Background()
+	d, err := newMemoryDB(ctx)
+	require.NoError(t, err)
+	defer d.Close(ctx)
+
+	err = d.AddSchema(ctx, `
+		type Author { name: String books: [Book] }
+		type Book { title: String author: Author }
+	`)
+	require.NoError(t, err)
+
+	_, err = d.CloneCollection(ctx, client.CloneCollectionOptions{Source: "Book", Name: "Draft"})
+	assert.ErrorIs(t, err, ErrCloneOfRelatedCollection)
+}
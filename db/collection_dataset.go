@@ -0,0 +1,165 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+
+	"github.com/fxamacker/cbor/v2"
+	dag "github.com/ipfs/boxo/ipld/merkledag"
+	"github.com/ipfs/go-cid"
+	ipld "github.com/ipfs/go-ipld-format"
+	mh "github.com/multiformats/go-multihash"
+
+	"github.com/sourcenetwork/defradb/client"
+)
+
+// datasetHeader is the data of a dataset block.
+type datasetHeader struct {
+	// SchemaID is the ID of the schema of the documents of the dataset.
+	SchemaID string
+}
+
+// PublishDataset stores a dataset block linking to the current heads of the documents of the
+// collection, and returns its CID.
+//
+// The dataset block holds the ID of the schema of the collection, and has a link named by the key
+// of its document to each head.
+func (c *collection) PublishDataset(ctx context.Context) (cid.Cid, error) {
+	data, err := cbor.Marshal(datasetHeader{SchemaID: c.schemaID})
+	if err != nil {
+		return cid.Undef, err
+	}
+	docs, err := c.GetAllDocKeysWithHeads(ctx)
+	if err != nil {
+		return cid.Undef, err
+	}
+
+	nd := dag.NodeWithData(data)
+	err = nd.SetCidBuilder(cid.V1Builder{
+		Codec:    cid.DagProtobuf,
+		MhType:   mh.SHA2_256,
+		MhLength: -1,
+	})
+	if err != nil {
+		return cid.Undef, err
+	}
+	for doc := range docs {
+		if doc.Err != nil {
+			return cid.Undef, doc.Err
+		}
+		for _, head := range doc.Heads {
+			if err := nd.AddRawLink(doc.Key.String(), &ipld.Link{Cid: head}); err != nil {
+				return cid.Undef, err
+			}
+		}
+	}
+
+	txn, err := c.getTxn(ctx, false)
+	if err != nil {
+		return cid.Undef, err
+	}
+	defer c.discardImplicitTxn(ctx, txn)
+
+	if err := txn.DAGstore().Put(ctx, nd); err != nil {
+		return cid.Undef, err
+	}
+	return nd.Cid(), c.commitImplicitTxn(ctx, txn)
+}
+
+// ImportDataset fetches the dataset with the given root CID, along with the DAGs of the documents
+// it links to, with the given fetcher, and merges the documents into the collection.
+//
+// Each head is merged with the blocks of its DAG that the node doesn't have, as [MergeBlocks]
+// does with the blocks of a commit produced outside of the node.
+func (c *collection) ImportDataset(
+	ctx context.Context,
+	root cid.Cid,
+	fetcher ipld.NodeGetter,
+) (client.ImportDatasetResult, error) {
+	result := client.ImportDatasetResult{}
+
+	rootNode, err := fetcher.Get(ctx, root)
+	if err != nil {
+		return client.ImportDatasetResult{}, err
+	}
+	dataset, err := dag.DecodeProtobuf(rootNode.RawData())
+	if err != nil {
+		return client.ImportDatasetResult{}, NewErrInvalidDataset(root, err)
+	}
+	header := datasetHeader{}
+	if err := cbor.Unmarshal(dataset.Data(), &header); err != nil || header.SchemaID == "" {
+		return client.ImportDatasetResult{}, NewErrInvalidDataset(root, err)
+	}
+	if header.SchemaID != c.schemaID {
+		return client.ImportDatasetResult{}, NewErrDatasetOfOtherSchema(c.Name(), header.SchemaID)
+	}
+	result.Blocks++
+
+	for _, link := range dataset.Links() {
+		result.Heads++
+		blocks, err := c.fetchDocBlocks(ctx, fetcher, link.Cid)
+		if err != nil {
+			return client.ImportDatasetResult{}, err
+		}
+		if len(blocks) == 0 {
+			result.Known++
+			continue
+		}
+		result.Blocks += len(blocks)
+
+		if _, err := c.MergeBlocks(ctx, blocks); err != nil {
+			return client.ImportDatasetResult{}, err
+		}
+		result.Merged++
+	}
+	return result, nil
+}
+
+// fetchDocBlocks returns the data of the blocks of the DAG of a document from the given head that
+// are not stored by the node, fetched with the given fetcher.
+//
+// The ancestors of the stored blocks are not fetched, as the node has them too.
+func (c *collection) fetchDocBlocks(
+	ctx context.Context,
+	fetcher ipld.NodeGetter,
+	head cid.Cid,
+) ([][]byte, error) {
+	blocks := [][]byte{}
+	visited := map[cid.Cid]struct{}{}
+	queue := []cid.Cid{head}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if _, ok := visited[current]; ok {
+			continue
+		}
+		visited[current] = struct{}{}
+
+		exists, err := c.db.Blockstore().Has(ctx, current)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			continue
+		}
+
+		nd, err := fetcher.Get(ctx, current)
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, nd.RawData())
+		for _, link := range nd.Links() {
+			queue = append(queue, link.Cid)
+		}
+	}
+	return blocks, nil
+}
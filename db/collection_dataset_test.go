@@ -0,0 +1,101 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ipfs/boxo/blockservice"
+	"github.com/ipfs/boxo/exchange/offline"
+	dag "github.com/ipfs/boxo/ipld/merkledag"
+	ipld "github.com/ipfs/go-ipld-format"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/client"
+)
+
+// newBlockFetcher returns a block fetcher reading the blocks of the database of the given
+// collection, as the peers holding them would serve them.
+func newBlockFetcher(col client.Collection) ipld.NodeGetter {
+	bs := col.(*collection).db.Blockstore()
+	return dag.NewDAGService(blockservice.New(bs, offline.Exchange(bs)))
+}
+
+func TestImportDataset(t *testing.T) {
+	ctx := context.Background()
+	published := newTestCollectionWithDocs(
+		t,
+		ctx,
+		`{"Name": "John", "Age": 21}`,
+		`{"Name": "Islam", "Age": 33}`,
+	)
+	doc, err := client.NewDocFromJSON([]byte(`{"Name": "Fred", "Age": 40}`))
+	require.NoError(t, err)
+	require.NoError(t, published.Create(ctx, doc))
+	require.NoError(t, doc.Set("Age", 41))
+	require.NoError(t, published.Update(ctx, doc))
+
+	root, err := published.PublishDataset(ctx)
+	require.NoError(t, err)
+
+	col := newTestCollectionWithDocs(t, ctx)
+	result, err := col.ImportDataset(ctx, root, newBlockFetcher(published))
+	require.NoError(t, err)
+	// The dataset, the 3 composite commits of the created documents with their 2 field commits,
+	// and the commit of the update with its field commit.
+	assert.Equal(t, client.ImportDatasetResult{Heads: 3, Merged: 3, Blocks: 12}, result)
+
+	imported, err := col.Get(ctx, doc.Key(), false)
+	require.NoError(t, err)
+	age, err := imported.Get("Age")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(41), age)
+
+	count, err := col.Count(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), count)
+
+	result, err = col.ImportDataset(ctx, root, newBlockFetcher(published))
+	require.NoError(t, err)
+	assert.Equal(t, client.ImportDatasetResult{Heads: 3, Known: 3, Blocks: 1}, result)
+}
+
+func TestImportDatasetWithInvalidRoot(t *testing.T) {
+	ctx := context.Background()
+	published := newTestCollectionWithDocs(t, ctx, `{"Name": "John", "Age": 21}`)
+	docs, err := published.GetAllDocKeysWithHeads(ctx)
+	require.NoError(t, err)
+	head := (<-docs).Heads[0]
+
+	col := newTestCollectionWithDocs(t, ctx)
+	_, err = col.ImportDataset(ctx, head, newBlockFetcher(published))
+	assert.ErrorIs(t, err, ErrInvalidDataset)
+}
+
+func TestImportDatasetOfOtherSchema(t *testing.T) {
+	ctx := context.Background()
+	published := newTestCollectionWithDocs(t, ctx, `{"Name": "John", "Age": 21}`)
+	root, err := published.PublishDataset(ctx)
+	require.NoError(t, err)
+
+	db, err := newMemoryDB(ctx)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close(ctx) })
+	err = db.AddSchema(ctx, `type books { Name: String }`)
+	require.NoError(t, err)
+	col, err := db.GetCollectionByName(ctx, "books")
+	require.NoError(t, err)
+
+	_, err = col.ImportDataset(ctx, root, newBlockFetcher(published))
+	assert.ErrorIs(t, err, ErrDatasetOfOtherSchema)
+}
@@ -50,6 +50,10 @@ func (c *collection) DeleteWithKey(
 	ctx context.Context,
 	key client.DocKey,
 ) (*client.DeleteResult, error) {
+	if err := c.checkUpdatable(); err != nil {
+		return nil, err
+	}
+
 	txn, err := c.getTxn(ctx, false)
 	if err != nil {
 		return nil, err
@@ -71,6 +75,10 @@ func (c *collection) DeleteWithKeys(
 	ctx context.Context,
 	keys []client.DocKey,
 ) (*client.DeleteResult, error) {
+	if err := c.checkUpdatable(); err != nil {
+		return nil, err
+	}
+
 	txn, err := c.getTxn(ctx, false)
 	if err != nil {
 		return nil, err
@@ -91,6 +99,10 @@ func (c *collection) DeleteWithFilter(
 	ctx context.Context,
 	filter any,
 ) (*client.DeleteResult, error) {
+	if err := c.checkUpdatable(); err != nil {
+		return nil, err
+	}
+
 	txn, err := c.getTxn(ctx, false)
 	if err != nil {
 		return nil, err
@@ -114,7 +126,7 @@ func (c *collection) deleteWithKey(
 ) (*client.DeleteResult, error) {
 	// Check the docKey we have been given to delete with actually has a corresponding
 	//  document (i.e. document actually exists in the collection).
-	err := c.applyDelete(ctx, txn, key)
+	err := c.applyDelete(ctx, txn, key, status)
 	if err != nil {
 		return nil, err
 	}
@@ -142,7 +154,7 @@ func (c *collection) deleteWithKeys(
 		dsKey := c.getPrimaryKeyFromDocKey(key)
 
 		// Apply the function that will perform the full deletion of this document.
-		err := c.applyDelete(ctx, txn, dsKey)
+		err := c.applyDelete(ctx, txn, dsKey, status)
 		if err != nil {
 			return nil, err
 		}
@@ -206,7 +218,7 @@ func (c *collection) deleteWithFilter(
 		}
 
 		// Delete the document that is associated with this key we got from the filter.
-		err = c.applyDelete(ctx, txn, key)
+		err = c.applyDelete(ctx, txn, key, status)
 		if err != nil {
 			return nil, err
 		}
@@ -220,10 +232,16 @@ func (c *collection) deleteWithFilter(
 	return results, nil
 }
 
+// applyDelete deletes the document of the given key, committing the given deleted status.
+//
+// Append-only collections are only checked by the user-facing deletes, so that the documents
+// of these collections can still be pruned by the retention policies and quotas. These commit
+// the [client.Pruned] status, whose deletes the peers accept for append-only collections.
 func (c *collection) applyDelete(
 	ctx context.Context,
 	txn datastore.Txn,
 	key core.PrimaryDataStoreKey,
+	status client.DocumentStatus,
 ) error {
 	found, isDeleted, err := c.exists(ctx, txn, key)
 	if err != nil {
@@ -262,27 +280,25 @@ func (c *collection) applyDelete(
 		client.COMPOSITE,
 		[]byte{},
 		dagLinks,
-		client.Deleted,
+		status,
 	)
 	if err != nil {
 		return err
 	}
 
-	if c.db.events.Updates.HasValue() {
-		txn.OnSuccess(
-			func() {
-				c.db.events.Updates.Value().Publish(
-					events.Update{
-						DocKey:   key.DocKey,
-						Cid:      headNode.Cid(),
-						SchemaID: c.schemaID,
-						Block:    headNode,
-						Priority: priority,
-					},
-				)
-			},
-		)
+	if err := c.recordQuotaUsage(ctx, txn, key.DocKey); err != nil {
+		return err
 	}
 
-	return nil
+	return c.publishUpdate(
+		ctx,
+		txn,
+		events.Update{
+			DocKey:   key.DocKey,
+			Cid:      headNode.Cid(),
+			SchemaID: c.schemaID,
+			Block:    headNode,
+			Priority: priority,
+		},
+	)
 }
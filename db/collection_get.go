@@ -50,11 +50,17 @@ func (c *collection) get(
 	key core.PrimaryDataStoreKey,
 	showDeleted bool,
 ) (*client.Document, error) {
+	ctx, err := c.db.withFieldKeyring(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	// create a new document fetcher
 	df := new(fetcher.DocumentFetcher)
+	df.SetCache(c.db.docCache)
 	desc := &c.desc
 	// initialize it with the primary index
-	err := df.Init(&c.desc, nil, false, showDeleted)
+	err = df.Init(&c.desc, nil, false, showDeleted)
 	if err != nil {
 		_ = df.Close()
 		return nil, err
@@ -0,0 +1,111 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/events"
+)
+
+// ingestBatch holds the documents saved by a batch of [collection.CreateBatched] along with
+// their update.
+type ingestBatch struct {
+	docs    []*client.Document
+	updates []events.Update
+}
+
+func (b *ingestBatch) add(doc *client.Document, update events.Update) {
+	b.docs = append(b.docs, doc)
+	b.updates = append(b.updates, update)
+}
+
+// CreateBatched creates new documents in batches of the given size, each batch in its own
+// transaction.
+//
+// Will verify the DocKeys/CIDs to ensure that the new documents are correctly formatted.
+func (c *collection) CreateBatched(ctx context.Context, docs []*client.Document, batchSize int) error {
+	if batchSize <= 0 {
+		return NewErrInvalidBatchSize(batchSize)
+	}
+
+	for start := 0; start < len(docs); start += batchSize {
+		end := start + batchSize
+		if end > len(docs) {
+			end = len(docs)
+		}
+		err := c.createBatch(ctx, docs[start:end])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createBatch creates the given documents in a single transaction.
+func (c *collection) createBatch(ctx context.Context, docs []*client.Document) error {
+	txn, err := c.getTxn(ctx, false)
+	if err != nil {
+		return err
+	}
+	defer c.discardImplicitTxn(ctx, txn)
+
+	batch := &ingestBatch{
+		docs:    make([]*client.Document, 0, len(docs)),
+		updates: make([]events.Update, 0, len(docs)),
+	}
+	txn.OnSuccess(func() {
+		c.completeBatch(batch)
+	})
+
+	ingestCol := c.withIngestBatch(batch)
+	for _, doc := range docs {
+		err = ingestCol.create(ctx, txn, doc)
+		if err != nil {
+			return err
+		}
+	}
+	return c.commitImplicitTxn(ctx, txn)
+}
+
+// completeBatch does the post-commit work of the documents of the given committed batch, that
+// is done for each document on commit outside of batches.
+func (c *collection) completeBatch(batch *ingestBatch) {
+	if len(batch.docs) == 0 {
+		return
+	}
+	c.db.updates.markUpdated(c.colID, c.db.now())
+
+	for i, doc := range batch.docs {
+		doc.Clean()
+		doc.SetHead(batch.updates[i].Cid)
+	}
+
+	if !c.db.events.Updates.HasValue() {
+		return
+	}
+	for _, update := range batch.updates {
+		c.db.events.Updates.Value().Publish(update)
+	}
+}
+
+// withIngestBatch returns a copy of the collection that saves documents into the given batch.
+func (c *collection) withIngestBatch(batch *ingestBatch) *collection {
+	return &collection{
+		db:       c.db,
+		txn:      c.txn,
+		ingest:   batch,
+		desc:     c.desc,
+		colID:    c.colID,
+		schemaID: c.schemaID,
+	}
+}
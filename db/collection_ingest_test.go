@@ -0,0 +1,125 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	badger "github.com/dgraph-io/badger/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/client"
+	badgerds "github.com/sourcenetwork/defradb/datastore/badger/v3"
+)
+
+func newTestDocs(t *testing.T, count int) []*client.Document {
+	docs := make([]*client.Document, count)
+	for i := range docs {
+		doc, err := client.NewDocFromJSON([]byte(fmt.Sprintf(`{"Name": "User %d", "Age": %d}`, i, i)))
+		require.NoError(t, err)
+		docs[i] = doc
+	}
+	return docs
+}
+
+func TestCollectionCreateBatched(t *testing.T) {
+	ctx := context.Background()
+	opts := badgerds.Options{Options: badger.DefaultOptions("").WithInMemory(true)}
+	rootstore, err := badgerds.NewDatastore("", &opts)
+	require.NoError(t, err)
+	db, err := newDB(ctx, rootstore, WithUpdateEvents())
+	require.NoError(t, err)
+	defer db.Close(ctx)
+
+	err = db.AddSchema(ctx, `type users { Name: String Age: Int }`)
+	require.NoError(t, err)
+	col, err := db.GetCollectionByName(ctx, "users")
+	require.NoError(t, err)
+
+	updates, err := db.Events().Updates.Value().Subscribe()
+	require.NoError(t, err)
+
+	docs := newTestDocs(t, 5)
+	err = col.CreateBatched(ctx, docs, 2)
+	require.NoError(t, err)
+
+	count, err := col.Count(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(5), count)
+
+	for _, doc := range docs {
+		assert.True(t, doc.Head().Defined())
+		for _, value := range doc.Values() {
+			assert.False(t, value.IsDirty())
+		}
+
+		update := <-updates
+		assert.Equal(t, doc.Key().String(), update.DocKey)
+		assert.Equal(t, doc.Head(), update.Cid)
+		assert.Equal(t, uint64(1), update.Priority)
+	}
+}
+
+func TestCollectionCreateBatchedWithError(t *testing.T) {
+	ctx := context.Background()
+	col := newTestCollectionWithDocs(t, ctx)
+
+	docs := newTestDocs(t, 5)
+	err := col.Create(ctx, docs[3])
+	require.NoError(t, err)
+
+	err = col.CreateBatched(ctx, docs, 2)
+	require.ErrorIs(t, err, ErrDocumentAlreadyExists)
+
+	// the first batch was committed before the error, the second one wasn't.
+	count, err := col.Count(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), count)
+	assert.True(t, docs[1].Head().Defined())
+	assert.False(t, docs[2].Head().Defined())
+}
+
+func TestCollectionCreateBatchedWithTxn(t *testing.T) {
+	ctx := context.Background()
+	col := newTestCollectionWithDocs(t, ctx)
+
+	txn, err := col.(*collection).db.NewTxn(ctx, false)
+	require.NoError(t, err)
+	defer txn.Discard(ctx)
+
+	docs := newTestDocs(t, 5)
+	err = col.WithTxn(txn).CreateBatched(ctx, docs, 2)
+	require.NoError(t, err)
+
+	count, err := col.Count(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), count)
+	assert.False(t, docs[0].Head().Defined())
+
+	err = txn.Commit(ctx)
+	require.NoError(t, err)
+
+	count, err = col.Count(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(5), count)
+	assert.True(t, docs[4].Head().Defined())
+}
+
+func TestCollectionCreateBatchedWithInvalidBatchSize(t *testing.T) {
+	ctx := context.Background()
+	col := newTestCollectionWithDocs(t, ctx)
+
+	err := col.CreateBatched(ctx, newTestDocs(t, 1), 0)
+	require.ErrorIs(t, err, ErrInvalidBatchSize)
+}
@@ -0,0 +1,352 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"bytes"
+	"context"
+
+	dag "github.com/ipfs/boxo/ipld/merkledag"
+	"github.com/ipfs/go-cid"
+	mh "github.com/multiformats/go-multihash"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/core"
+	corecrdt "github.com/sourcenetwork/defradb/core/crdt"
+	"github.com/sourcenetwork/defradb/datastore"
+	"github.com/sourcenetwork/defradb/events"
+	"github.com/sourcenetwork/defradb/merkle/clock"
+)
+
+// RecordRemoteCommit records the time, given by the clock of the database, at which the given
+// composite commit of the document with the given key, produced by another node, is received,
+// so that the document can be requested as it was at a given time.
+func (c *collection) RecordRemoteCommit(
+	ctx context.Context,
+	docKey string,
+	commit cid.Cid,
+	status client.DocumentStatus,
+) error {
+	txn, err := c.getTxn(ctx, false)
+	if err != nil {
+		return err
+	}
+	defer c.discardImplicitTxn(ctx, txn)
+
+	err = txn.Systemstore().Put(ctx, core.NewCommitTimeKey(docKey, c.db.now(), commit).ToDS(), []byte{status.UInt8()})
+	if err != nil {
+		return err
+	}
+	return c.commitImplicitTxn(ctx, txn)
+}
+
+// mergeBlock is a block to merge, along with the field whose DAG it belongs to.
+type mergeBlock struct {
+	node *dag.ProtoNode
+	// field is the name of the field whose DAG the block belongs to, or empty if it is a commit
+	// of the document.
+	field string
+}
+
+// MergeBlocks merges the given blocks, holding a commit of a document produced outside of the
+// node, into the document.
+//
+// The blocks are validated and merged like the blocks received from other peers. Their CIDs
+// are derived from their data.
+func (c *collection) MergeBlocks(ctx context.Context, blocks [][]byte) (cid.Cid, error) {
+	if len(blocks) == 0 {
+		return cid.Undef, ErrNoBlocksToMerge
+	}
+
+	nodes := make(map[cid.Cid]*dag.ProtoNode, len(blocks))
+	order := make([]cid.Cid, 0, len(blocks))
+	for i, data := range blocks {
+		nd, err := decodeMergeBlock(data)
+		if err != nil {
+			return cid.Undef, NewErrInvalidBlock(i, err)
+		}
+		if _, ok := nodes[nd.Cid()]; !ok {
+			order = append(order, nd.Cid())
+		}
+		nodes[nd.Cid()] = nd
+	}
+
+	linked := map[cid.Cid]struct{}{}
+	for _, nd := range nodes {
+		for _, link := range nd.Links() {
+			linked[link.Cid] = struct{}{}
+		}
+	}
+	root := cid.Undef
+	for _, blockCid := range order {
+		if _, ok := linked[blockCid]; ok {
+			continue
+		}
+		if root.Defined() {
+			return cid.Undef, ErrMergeRootNotFound
+		}
+		root = blockCid
+	}
+	if !root.Defined() {
+		return cid.Undef, ErrMergeRootNotFound
+	}
+
+	txn, err := c.getTxn(ctx, false)
+	if err != nil {
+		return cid.Undef, err
+	}
+	defer c.discardImplicitTxn(ctx, txn)
+
+	exists, err := txn.DAGstore().Has(ctx, root)
+	if err != nil {
+		return cid.Undef, err
+	}
+	if exists {
+		return root, nil
+	}
+
+	rootDelta, err := c.validateMergeCommit(ctx, txn, nodes[root], nil)
+	if err != nil {
+		return cid.Undef, err
+	}
+	if err := c.validateAppendOnlyMerge(ctx, txn, rootDelta); err != nil {
+		return cid.Undef, err
+	}
+
+	merges, err := c.orderMergeBlocks(ctx, txn, nodes, root, rootDelta)
+	if err != nil {
+		return cid.Undef, err
+	}
+
+	key, err := client.NewDocKeyFromString(string(rootDelta.DocKey))
+	if err != nil {
+		return cid.Undef, err
+	}
+	docKey := c.getPrimaryKeyFromDocKey(key).ToDataStoreKey()
+	for _, merge := range merges {
+		err := c.mergeBlock(ctx, txn, docKey, merge)
+		if err != nil {
+			return cid.Undef, err
+		}
+	}
+	if err := c.recordQuotaUsage(ctx, txn, docKey.DocKey); err != nil {
+		return cid.Undef, err
+	}
+
+	txn.OnSuccess(func() {
+		c.db.updates.markUpdated(c.colID, c.db.now())
+	})
+	err = c.publishUpdate(ctx, txn, events.Update{
+		DocKey:   string(rootDelta.DocKey),
+		Cid:      root,
+		SchemaID: c.schemaID,
+		Block:    nodes[root],
+		Priority: rootDelta.Priority,
+	})
+	if err != nil {
+		return cid.Undef, err
+	}
+
+	return root, c.commitImplicitTxn(ctx, txn)
+}
+
+// decodeMergeBlock decodes the given block data, deriving its CID as the CIDs of the blocks of
+// the documents are.
+func decodeMergeBlock(data []byte) (*dag.ProtoNode, error) {
+	nd, err := dag.DecodeProtobuf(data)
+	if err != nil {
+		return nil, err
+	}
+	err = nd.SetCidBuilder(cid.V1Builder{
+		Codec:    cid.DagProtobuf,
+		MhType:   mh.SHA2_256,
+		MhLength: -1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// The data must be the canonical encoding of the block, so that its CID identifies it.
+	encoded, err := nd.EncodeProtobuf(false)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(encoded, data) {
+		return nil, ErrNonCanonicalBlock
+	}
+	return nd, nil
+}
+
+// validateMergeCommit returns the delta of the given commit of a document of the collection.
+//
+// The commit must be of the same document as the given root delta, if any.
+func (c *collection) validateMergeCommit(
+	ctx context.Context,
+	txn datastore.Txn,
+	nd *dag.ProtoNode,
+	root *corecrdt.CompositeDAGDelta,
+) (*corecrdt.CompositeDAGDelta, error) {
+	delta, err := corecrdt.CompositeDAG{}.DeltaDecode(nd)
+	if err != nil {
+		return nil, NewErrInvalidCommit(nd.Cid(), err)
+	}
+	compositeDelta := delta.(*corecrdt.CompositeDAGDelta)
+
+	if _, err := client.NewDocKeyFromString(string(compositeDelta.DocKey)); err != nil {
+		return nil, NewErrInvalidCommit(nd.Cid(), err)
+	}
+	if root != nil && !bytes.Equal(compositeDelta.DocKey, root.DocKey) {
+		return nil, NewErrInvalidCommit(nd.Cid(), ErrCommitOfOtherDocument)
+	}
+
+	col, err := c.db.getCollectionByVersionID(ctx, txn, compositeDelta.SchemaVersionID)
+	if err != nil || col.schemaID != c.schemaID {
+		return nil, NewErrBlockOfOtherCollection(c.Name(), compositeDelta.SchemaVersionID)
+	}
+	return compositeDelta, nil
+}
+
+// orderMergeBlocks returns the given blocks in the order in which they are merged, each after
+// the given blocks it links to, along with the field whose DAG each belongs to.
+//
+// The links of the blocks must either be to given blocks, or to blocks already stored, which are
+// not merged again.
+func (c *collection) orderMergeBlocks(
+	ctx context.Context,
+	txn datastore.Txn,
+	nodes map[cid.Cid]*dag.ProtoNode,
+	root cid.Cid,
+	rootDelta *corecrdt.CompositeDAGDelta,
+) ([]mergeBlock, error) {
+	merges := make([]mergeBlock, 0, len(nodes))
+	visited := map[cid.Cid]struct{}{}
+
+	var visit func(current cid.Cid, field string) error
+	visit = func(current cid.Cid, field string) error {
+		if _, ok := visited[current]; ok {
+			return nil
+		}
+		visited[current] = struct{}{}
+
+		nd := nodes[current]
+		if field == "" && current != root {
+			if _, err := c.validateMergeCommit(ctx, txn, nd, rootDelta); err != nil {
+				return err
+			}
+		}
+
+		for _, link := range nd.Links() {
+			linkField := field
+			if link.Name != core.HEAD {
+				fieldDesc, ok := c.desc.GetField(link.Name)
+				if field != "" || !ok || fieldDesc.IsObject() {
+					return client.NewErrFieldNotExist(link.Name)
+				}
+				linkField = link.Name
+			}
+
+			known, err := txn.DAGstore().Has(ctx, link.Cid)
+			if err != nil {
+				return err
+			}
+			if known {
+				continue
+			}
+			if _, given := nodes[link.Cid]; !given {
+				return NewErrMissingBlock(link.Cid)
+			}
+			if err := visit(link.Cid, linkField); err != nil {
+				return err
+			}
+		}
+
+		merges = append(merges, mergeBlock{node: nd, field: field})
+		return nil
+	}
+
+	if err := visit(root, ""); err != nil {
+		return nil, err
+	}
+	return merges, nil
+}
+
+// mergeBlock stores the given block and merges its delta into the document with the given key,
+// as the blocks received from other peers are.
+func (c *collection) mergeBlock(
+	ctx context.Context,
+	txn datastore.Txn,
+	docKey core.DataStoreKey,
+	merge mergeBlock,
+) error {
+	ctype := client.COMPOSITE
+	key := docKey.WithFieldId(core.COMPOSITE_NAMESPACE)
+	if merge.field != "" {
+		fieldDesc, _ := c.desc.GetField(merge.field)
+		ctype = fieldDesc.Typ
+		key = docKey.WithFieldId(fieldDesc.ID.String())
+	}
+
+	merkleCRDT, err := c.db.crdtFactory.InstanceWithStores(
+		txn,
+		core.NewCollectionSchemaVersionKey(c.Schema().VersionID),
+		events.EmptyUpdateChannel,
+		ctype,
+		key,
+	)
+	if err != nil {
+		return err
+	}
+
+	delta, err := merkleCRDT.DeltaDecode(merge.node)
+	if err != nil {
+		return NewErrInvalidCommit(merge.node.Cid(), err)
+	}
+
+	if err := txn.DAGstore().Put(ctx, merge.node); err != nil {
+		return err
+	}
+
+	if compositeDelta, isComposite := delta.(*corecrdt.CompositeDAGDelta); isComposite {
+		// Record the time at which the commit was merged, so that the document can be
+		// requested as it was at a given time.
+		err = txn.Systemstore().Put(
+			ctx,
+			core.NewCommitTimeKey(docKey.DocKey, c.db.now(), merge.node.Cid()).ToDS(),
+			[]byte{compositeDelta.Status.UInt8()},
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	stats := &corecrdt.MergeStats{}
+	ctx = corecrdt.WithMergeStats(ctx, stats)
+	ctx = corecrdt.WithDeltaCipher(ctx, c.db.keyring.Cipher(c.Name()))
+	_, err = merkleCRDT.Clock().ProcessNode(
+		ctx,
+		&clock.CrdtNodeGetter{DeltaExtractor: merkleCRDT.DeltaDecode},
+		merge.node.Cid(),
+		delta.GetPriority(),
+		delta,
+		merge.node,
+	)
+	if err != nil {
+		return err
+	}
+
+	txn.OnSuccess(func() {
+		c.db.merges.record(ctx, c.colID, client.RemoteMerge{
+			Priority:  delta.GetPriority(),
+			Commit:    merge.field == "",
+			Conflicts: stats.Conflicts(),
+		})
+	})
+	return nil
+}
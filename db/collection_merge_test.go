@@ -0,0 +1,198 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+	"testing"
+
+	dag "github.com/ipfs/boxo/ipld/merkledag"
+	"github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/core"
+)
+
+// getCommitBlocks returns the raw data of the commit with the given CID and of the field blocks
+// it links to.
+func getCommitBlocks(t *testing.T, ctx context.Context, col client.Collection, commit cid.Cid) [][]byte {
+	store := col.(*collection).db.Blockstore()
+	block, err := store.Get(ctx, commit)
+	require.NoError(t, err)
+	blocks := [][]byte{block.RawData()}
+
+	nd, err := dag.DecodeProtobuf(block.RawData())
+	require.NoError(t, err)
+	for _, link := range nd.Links() {
+		if link.Name == core.HEAD {
+			continue
+		}
+		fieldBlock, err := store.Get(ctx, link.Cid)
+		require.NoError(t, err)
+		blocks = append(blocks, fieldBlock.RawData())
+	}
+	return blocks
+}
+
+func TestCollectionMergeBlocks(t *testing.T) {
+	ctx := context.Background()
+	offline := newTestCollectionWithDocs(t, ctx)
+	col := newTestCollectionWithDocs(t, ctx)
+
+	doc, err := client.NewDocFromJSON([]byte(`{"Name": "John", "Age": 21}`))
+	require.NoError(t, err)
+	err = offline.Create(ctx, doc)
+	require.NoError(t, err)
+
+	root, err := col.MergeBlocks(ctx, getCommitBlocks(t, ctx, offline, doc.Head()))
+	require.NoError(t, err)
+	assert.Equal(t, doc.Head(), root)
+
+	err = doc.Set("Age", 22)
+	require.NoError(t, err)
+	err = offline.Update(ctx, doc)
+	require.NoError(t, err)
+
+	root, err = col.MergeBlocks(ctx, getCommitBlocks(t, ctx, offline, doc.Head()))
+	require.NoError(t, err)
+	assert.Equal(t, doc.Head(), root)
+
+	merged, err := col.Get(ctx, doc.Key(), false)
+	require.NoError(t, err)
+	name, err := merged.Get("Name")
+	require.NoError(t, err)
+	assert.Equal(t, "John", name)
+	age, err := merged.Get("Age")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(22), age)
+	assert.Equal(t, getDocHeads(t, ctx, offline), getDocHeads(t, ctx, col))
+}
+
+func TestCollectionMergeBlocksWithSeveralCommits(t *testing.T) {
+	ctx := context.Background()
+	offline := newTestCollectionWithDocs(t, ctx)
+	col := newTestCollectionWithDocs(t, ctx)
+
+	doc, err := client.NewDocFromJSON([]byte(`{"Name": "John", "Age": 21}`))
+	require.NoError(t, err)
+	err = offline.Create(ctx, doc)
+	require.NoError(t, err)
+	blocks := getCommitBlocks(t, ctx, offline, doc.Head())
+
+	err = doc.Set("Age", 22)
+	require.NoError(t, err)
+	err = offline.Update(ctx, doc)
+	require.NoError(t, err)
+	blocks = append(getCommitBlocks(t, ctx, offline, doc.Head()), blocks...)
+
+	root, err := col.MergeBlocks(ctx, blocks)
+	require.NoError(t, err)
+	assert.Equal(t, doc.Head(), root)
+
+	merged, err := col.Get(ctx, doc.Key(), false)
+	require.NoError(t, err)
+	age, err := merged.Get("Age")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(22), age)
+	assert.Equal(t, getDocHeads(t, ctx, offline), getDocHeads(t, ctx, col))
+}
+
+func TestCollectionMergeBlocksWithKnownCommit(t *testing.T) {
+	ctx := context.Background()
+	col := newTestCollectionWithDocs(t, ctx)
+
+	doc, err := client.NewDocFromJSON([]byte(`{"Name": "John", "Age": 21}`))
+	require.NoError(t, err)
+	err = col.Create(ctx, doc)
+	require.NoError(t, err)
+
+	root, err := col.MergeBlocks(ctx, getCommitBlocks(t, ctx, col, doc.Head()))
+	require.NoError(t, err)
+	assert.Equal(t, doc.Head(), root)
+}
+
+func TestCollectionMergeBlocksWithMissingBlock(t *testing.T) {
+	ctx := context.Background()
+	offline := newTestCollectionWithDocs(t, ctx)
+	col := newTestCollectionWithDocs(t, ctx)
+
+	doc, err := client.NewDocFromJSON([]byte(`{"Name": "John", "Age": 21}`))
+	require.NoError(t, err)
+	err = offline.Create(ctx, doc)
+	require.NoError(t, err)
+	err = doc.Set("Age", 22)
+	require.NoError(t, err)
+	err = offline.Update(ctx, doc)
+	require.NoError(t, err)
+
+	_, err = col.MergeBlocks(ctx, getCommitBlocks(t, ctx, offline, doc.Head()))
+	require.ErrorIs(t, err, ErrMissingBlock)
+
+	_, err = col.Get(ctx, doc.Key(), false)
+	require.ErrorIs(t, err, client.ErrDocumentNotFound)
+}
+
+func TestCollectionMergeBlocksWithoutBlocks(t *testing.T) {
+	ctx := context.Background()
+	col := newTestCollectionWithDocs(t, ctx)
+
+	_, err := col.MergeBlocks(ctx, nil)
+	require.ErrorIs(t, err, ErrNoBlocksToMerge)
+}
+
+func TestCollectionMergeBlocksWithSeveralRoots(t *testing.T) {
+	ctx := context.Background()
+	offline := newTestCollectionWithDocs(t, ctx)
+	col := newTestCollectionWithDocs(t, ctx)
+
+	var blocks [][]byte
+	for _, data := range []string{`{"Name": "John"}`, `{"Name": "Islam"}`} {
+		doc, err := client.NewDocFromJSON([]byte(data))
+		require.NoError(t, err)
+		err = offline.Create(ctx, doc)
+		require.NoError(t, err)
+		blocks = append(blocks, getCommitBlocks(t, ctx, offline, doc.Head())...)
+	}
+
+	_, err := col.MergeBlocks(ctx, blocks)
+	require.ErrorIs(t, err, ErrMergeRootNotFound)
+}
+
+func TestCollectionMergeBlocksWithInvalidBlock(t *testing.T) {
+	ctx := context.Background()
+	col := newTestCollectionWithDocs(t, ctx)
+
+	_, err := col.MergeBlocks(ctx, [][]byte{[]byte("not a block")})
+	require.ErrorIs(t, err, ErrInvalidBlock)
+}
+
+func TestCollectionMergeBlocksOfOtherCollection(t *testing.T) {
+	ctx := context.Background()
+	offline := newTestCollectionWithDocs(t, ctx)
+
+	db, err := newMemoryDB(ctx)
+	require.NoError(t, err)
+	defer db.Close(ctx)
+	err = db.AddSchema(ctx, `type books { Name: String }`)
+	require.NoError(t, err)
+	col, err := db.GetCollectionByName(ctx, "books")
+	require.NoError(t, err)
+
+	doc, err := client.NewDocFromJSON([]byte(`{"Name": "John"}`))
+	require.NoError(t, err)
+	err = offline.Create(ctx, doc)
+	require.NoError(t, err)
+
+	_, err = col.MergeBlocks(ctx, getCommitBlocks(t, ctx, offline, doc.Head()))
+	require.ErrorIs(t, err, ErrBlockOfOtherCollection)
+}
@@ -0,0 +1,128 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+
+	dag "github.com/ipfs/boxo/ipld/merkledag"
+	"github.com/ipfs/go-cid"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/core"
+	"github.com/sourcenetwork/defradb/datastore"
+	"github.com/sourcenetwork/defradb/merkle/clock"
+)
+
+// GetProof returns a Merkle proof that the current state of the document with the given
+// DocKey derives from the commit with the given CID.
+//
+// The proof holds the shortest chain of composite commits from each current head of the
+// document to the given commit.
+func (c *collection) GetProof(ctx context.Context, key client.DocKey, root cid.Cid) (client.Proof, error) {
+	txn, err := c.getTxn(ctx, true)
+	if err != nil {
+		return client.Proof{}, err
+	}
+	defer c.discardImplicitTxn(ctx, txn)
+
+	headset := clock.NewHeadSet(
+		txn.Headstore(),
+		core.DataStoreKeyFromDocKey(key).WithFieldId(core.COMPOSITE_NAMESPACE).ToHeadStoreKey(),
+	)
+	heads, _, err := headset.List(ctx)
+	if err != nil {
+		return client.Proof{}, NewErrFailedToGetHeads(err)
+	}
+	if len(heads) == 0 {
+		return client.Proof{}, client.ErrDocumentNotFound
+	}
+
+	proof := client.Proof{
+		Root:   root,
+		Heads:  heads,
+		Blocks: []client.ProofBlock{},
+	}
+	included := map[cid.Cid]struct{}{}
+	for _, head := range heads {
+		chain, err := findCommitChain(ctx, txn.DAGstore(), head, root)
+		if err != nil {
+			return client.Proof{}, err
+		}
+		if chain == nil {
+			return client.Proof{}, client.NewErrCommitNotInHistory(key.String(), root.String())
+		}
+		for _, block := range chain {
+			if _, ok := included[block.Cid]; ok {
+				continue
+			}
+			included[block.Cid] = struct{}{}
+			proof.Blocks = append(proof.Blocks, block)
+		}
+	}
+
+	return proof, nil
+}
+
+// findCommitChain returns the blocks of the shortest chain of commits from the given head to
+// the given root, following the links to the previous heads of each commit.
+//
+// Returns nil if the root can't be reached from the head.
+func findCommitChain(
+	ctx context.Context,
+	store datastore.DAGStore,
+	head cid.Cid,
+	root cid.Cid,
+) ([]client.ProofBlock, error) {
+	// parents maps the visited commits to the commit they were first reached from.
+	parents := map[cid.Cid]cid.Cid{head: cid.Undef}
+	data := map[cid.Cid][]byte{}
+	queue := []cid.Cid{head}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		block, err := store.Get(ctx, current)
+		if err != nil {
+			return nil, err
+		}
+		data[current] = block.RawData()
+
+		if current.Equals(root) {
+			chain := []client.ProofBlock{}
+			for c := current; c.Defined(); c = parents[c] {
+				chain = append(chain, client.ProofBlock{Cid: c, Data: data[c]})
+			}
+			// the chain was built from the root, reverse it so that it starts from the head.
+			for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+				chain[i], chain[j] = chain[j], chain[i]
+			}
+			return chain, nil
+		}
+
+		nd, err := dag.DecodeProtobuf(block.RawData())
+		if err != nil {
+			return nil, err
+		}
+		for _, link := range nd.Links() {
+			if link.Name != core.HEAD {
+				continue
+			}
+			if _, visited := parents[link.Cid]; visited {
+				continue
+			}
+			parents[link.Cid] = current
+			queue = append(queue, link.Cid)
+		}
+	}
+
+	return nil, nil
+}
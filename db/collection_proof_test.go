@@ -0,0 +1,135 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+	"testing"
+
+	dag "github.com/ipfs/boxo/ipld/merkledag"
+	"github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/core"
+)
+
+func getDocHeads(t *testing.T, ctx context.Context, col client.Collection) map[string][]cid.Cid {
+	keysCh, err := col.GetAllDocKeysWithHeads(ctx)
+	require.NoError(t, err)
+
+	heads := map[string][]cid.Cid{}
+	for res := range keysCh {
+		require.NoError(t, res.Err)
+		heads[res.Key.String()] = res.Heads
+	}
+	return heads
+}
+
+func TestCollectionGetProof(t *testing.T) {
+	ctx := context.Background()
+	col := newTestCollectionWithDocs(t, ctx, `{"Name": "John", "Age": 21}`)
+
+	var key client.DocKey
+	var root cid.Cid
+	for k, heads := range getDocHeads(t, ctx, col) {
+		key, _ = client.NewDocKeyFromString(k)
+		root = heads[0]
+	}
+
+	for _, age := range []int{22, 23} {
+		doc, err := col.Get(ctx, key, false)
+		require.NoError(t, err)
+		err = doc.Set("Age", age)
+		require.NoError(t, err)
+		err = col.Update(ctx, doc)
+		require.NoError(t, err)
+	}
+
+	proof, err := col.GetProof(ctx, key, root)
+	require.NoError(t, err)
+
+	heads := getDocHeads(t, ctx, col)[key.String()]
+	assert.Equal(t, heads, proof.Heads)
+	assert.Equal(t, root, proof.Root)
+	require.Len(t, proof.Blocks, 3)
+	assert.Equal(t, heads[0], proof.Blocks[0].Cid)
+	assert.Equal(t, root, proof.Blocks[2].Cid)
+
+	for i, block := range proof.Blocks {
+		// each block must hash to its CID and link to the next block of the chain.
+		sum, err := block.Cid.Prefix().Sum(block.Data)
+		require.NoError(t, err)
+		assert.Equal(t, block.Cid, sum)
+
+		if i == len(proof.Blocks)-1 {
+			continue
+		}
+		nd, err := dag.DecodeProtobuf(block.Data)
+		require.NoError(t, err)
+		linked := false
+		for _, link := range nd.Links() {
+			linked = linked || (link.Name == core.HEAD && link.Cid == proof.Blocks[i+1].Cid)
+		}
+		assert.True(t, linked)
+	}
+}
+
+func TestCollectionGetProofWithHeadAsRoot(t *testing.T) {
+	ctx := context.Background()
+	col := newTestCollectionWithDocs(t, ctx, `{"Name": "John", "Age": 21}`)
+
+	for k, heads := range getDocHeads(t, ctx, col) {
+		key, err := client.NewDocKeyFromString(k)
+		require.NoError(t, err)
+
+		proof, err := col.GetProof(ctx, key, heads[0])
+		require.NoError(t, err)
+		require.Len(t, proof.Blocks, 1)
+		assert.Equal(t, heads[0], proof.Blocks[0].Cid)
+	}
+}
+
+func TestCollectionGetProofWithCommitOfOtherDoc(t *testing.T) {
+	ctx := context.Background()
+	col := newTestCollectionWithDocs(
+		t,
+		ctx,
+		`{"Name": "John", "Age": 21}`,
+		`{"Name": "Islam", "Age": 33}`,
+	)
+
+	docHeads := getDocHeads(t, ctx, col)
+	require.Len(t, docHeads, 2)
+	keys := []client.DocKey{}
+	roots := []cid.Cid{}
+	for k, heads := range docHeads {
+		key, err := client.NewDocKeyFromString(k)
+		require.NoError(t, err)
+		keys = append(keys, key)
+		roots = append(roots, heads[0])
+	}
+
+	_, err := col.GetProof(ctx, keys[0], roots[1])
+	assert.ErrorIs(t, err, client.ErrCommitNotInHistory)
+}
+
+func TestCollectionGetProofWithUnknownDoc(t *testing.T) {
+	ctx := context.Background()
+	col := newTestCollectionWithDocs(t, ctx)
+
+	key, err := client.NewDocKeyFromString("bae-52b9170d-b77a-5887-b877-cbdbb99b009f")
+	require.NoError(t, err)
+
+	_, err = col.GetProof(ctx, key, cid.Undef)
+	assert.ErrorIs(t, err, client.ErrDocumentNotFound)
+}
@@ -0,0 +1,159 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+
+	dag "github.com/ipfs/boxo/ipld/merkledag"
+	"github.com/ipfs/go-cid"
+	ds "github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/query"
+	ipld "github.com/ipfs/go-ipld-format"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/core"
+	"github.com/sourcenetwork/defradb/datastore"
+)
+
+// purge removes the history of the deleted document with the given key: the blocks of its
+// commits along with their authors and times, its heads and its stored values.
+//
+// The document is then unknown to the store, as if it had never been created. Peers that still
+// hold it may however sync it back.
+func (c *collection) purge(ctx context.Context, txn datastore.Txn, key core.PrimaryDataStoreKey) error {
+	found, isDeleted, err := c.exists(ctx, txn, key)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return client.ErrDocumentNotFound
+	}
+	if !isDeleted {
+		return NewErrDocumentNotDeleted(key.DocKey)
+	}
+
+	headKeys, err := queryKeys(ctx, txn.Headstore(), core.HeadStoreKey{DocKey: key.DocKey}.ToString())
+	if err != nil {
+		return err
+	}
+	heads := make([]cid.Cid, 0, len(headKeys))
+	for _, k := range headKeys {
+		headKey, err := core.NewHeadStoreKey(k)
+		if err != nil {
+			return err
+		}
+		heads = append(heads, headKey.Cid)
+	}
+	if err := purgeBlocks(ctx, txn, heads); err != nil {
+		return err
+	}
+	if err := deleteKeys(ctx, txn.Headstore(), headKeys); err != nil {
+		return err
+	}
+
+	commitTimeKeys, err := queryKeys(ctx, txn.Systemstore(), core.CommitTimeKey{DocKey: key.DocKey}.ToString())
+	if err != nil {
+		return err
+	}
+	if err := deleteKeys(ctx, txn.Systemstore(), commitTimeKeys); err != nil {
+		return err
+	}
+
+	for _, instanceType := range []core.InstanceType{core.ValueKey, core.PriorityKey, core.DeletedKey} {
+		prefix := core.DataStoreKey{
+			CollectionID: key.CollectionId,
+			InstanceType: instanceType,
+			DocKey:       key.DocKey,
+		}
+		valueKeys, err := queryKeys(ctx, txn.Datastore(), prefix.ToString())
+		if err != nil {
+			return err
+		}
+		if err := deleteKeys(ctx, txn.Datastore(), valueKeys); err != nil {
+			return err
+		}
+	}
+	return txn.Datastore().Delete(ctx, key.ToDS())
+}
+
+// purgeBlocks deletes the blocks reachable from the given heads, along with their authors.
+//
+// Blocks that are not stored, such as those of commits that were never synced, are skipped.
+func purgeBlocks(ctx context.Context, txn datastore.Txn, heads []cid.Cid) error {
+	visited := map[cid.Cid]struct{}{}
+	queue := append([]cid.Cid{}, heads...)
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if _, ok := visited[current]; ok {
+			continue
+		}
+		visited[current] = struct{}{}
+
+		block, err := txn.DAGstore().Get(ctx, current)
+		if ipld.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		nd, err := dag.DecodeProtobuf(block.RawData())
+		if err != nil {
+			return err
+		}
+		for _, link := range nd.Links() {
+			queue = append(queue, link.Cid)
+		}
+
+		if err := txn.DAGstore().DeleteBlock(ctx, current); err != nil {
+			return err
+		}
+		if err := txn.Systemstore().Delete(ctx, core.NewCommitAuthorKey(current).ToDS()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// queryKeys returns the keys of the given store that start with the given prefix.
+func queryKeys(ctx context.Context, store datastore.DSReaderWriter, prefix string) ([]string, error) {
+	q, err := store.Query(ctx, query.Query{
+		Prefix:   prefix,
+		KeysOnly: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := q.Close(); err != nil {
+			log.ErrorE(ctx, "Failed to close key query", err)
+		}
+	}()
+
+	var keys []string
+	for res := range q.Next() {
+		if res.Error != nil {
+			return nil, res.Error
+		}
+		keys = append(keys, res.Key)
+	}
+	return keys, nil
+}
+
+func deleteKeys(ctx context.Context, store datastore.DSReaderWriter, keys []string) error {
+	for _, k := range keys {
+		if err := store.Delete(ctx, ds.NewKey(k)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
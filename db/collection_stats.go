@@ -0,0 +1,232 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ipfs/go-datastore/query"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/core"
+	"github.com/sourcenetwork/defradb/datastore"
+	"github.com/sourcenetwork/defradb/db/base"
+)
+
+// updateTracker keeps track of the time of the last document write of each collection.
+type updateTracker struct {
+	mu          sync.Mutex
+	lastUpdates map[uint32]time.Time
+}
+
+// markUpdated records that a document of the given collection has been written at the given time.
+func (t *updateTracker) markUpdated(colID uint32, at time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.lastUpdates == nil {
+		t.lastUpdates = make(map[uint32]time.Time)
+	}
+	t.lastUpdates[colID] = at
+}
+
+// lastUpdated returns the time of the last document write of the given collection, if any.
+func (t *updateTracker) lastUpdated(colID uint32) *time.Time {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	last, ok := t.lastUpdates[colID]
+	if !ok {
+		return nil
+	}
+	return &last
+}
+
+// Count returns the number of documents in the collection matching the given filter.
+//
+// All the documents that have not been deleted are counted if the filter is nil.
+func (c *collection) Count(ctx context.Context, filter any) (uint64, error) {
+	txn, err := c.getTxn(ctx, true)
+	if err != nil {
+		return 0, err
+	}
+	defer c.discardImplicitTxn(ctx, txn)
+
+	var count uint64
+	if filter == nil {
+		count, _, err = c.countPrimaryKeys(ctx, txn)
+	} else {
+		count, err = c.countWithFilter(ctx, txn, filter)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return count, c.commitImplicitTxn(ctx, txn)
+}
+
+// Stats returns statistics about the documents of the collection.
+func (c *collection) Stats(ctx context.Context) (client.CollectionStats, error) {
+	txn, err := c.getTxn(ctx, true)
+	if err != nil {
+		return client.CollectionStats{}, err
+	}
+	defer c.discardImplicitTxn(ctx, txn)
+
+	docCount, tombstoneCount, err := c.countPrimaryKeys(ctx, txn)
+	if err != nil {
+		return client.CollectionStats{}, err
+	}
+
+	stats := client.CollectionStats{
+		Name:           c.Name(),
+		DocCount:       docCount,
+		TombstoneCount: tombstoneCount,
+		LastUpdatedAt:  c.db.updates.lastUpdated(c.colID),
+	}
+	c.db.merges.addStats(c.colID, &stats)
+
+	if docCount > 0 {
+		size, err := c.liveValuesSize(ctx, txn)
+		if err != nil {
+			return client.CollectionStats{}, err
+		}
+		stats.AvgDocSize = float64(size) / float64(docCount)
+	}
+
+	return stats, c.commitImplicitTxn(ctx, txn)
+}
+
+// countPrimaryKeys returns the number of active and deleted documents of the collection,
+// using only the primary keys and their deletion markers.
+func (c *collection) countPrimaryKeys(
+	ctx context.Context,
+	txn datastore.Txn,
+) (active uint64, deleted uint64, err error) {
+	prefix := core.PrimaryDataStoreKey{
+		CollectionId: fmt.Sprint(c.colID),
+	}
+	q, err := txn.Datastore().Query(ctx, query.Query{
+		Prefix: prefix.ToString(),
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	defer func() {
+		if err := q.Close(); err != nil {
+			log.ErrorE(ctx, "Failed to close count query", err)
+		}
+	}()
+
+	for res := range q.Next() {
+		if res.Error != nil {
+			return 0, 0, res.Error
+		}
+		if bytes.Equal(res.Value, []byte{base.DeletedObjectMarker}) {
+			deleted++
+		} else {
+			active++
+		}
+	}
+	return active, deleted, nil
+}
+
+// countWithFilter returns the number of documents of the collection matching the given filter.
+func (c *collection) countWithFilter(ctx context.Context, txn datastore.Txn, filter any) (uint64, error) {
+	selectionPlan, err := c.makeSelectionPlan(ctx, txn, filter)
+	if err != nil {
+		return 0, err
+	}
+	if err = selectionPlan.Start(); err != nil {
+		return 0, err
+	}
+
+	defer func() {
+		if err := selectionPlan.Close(); err != nil {
+			log.ErrorE(ctx, "Failed to close the selection plan, after filter count", err)
+		}
+	}()
+
+	var count uint64
+	for {
+		next, err := selectionPlan.Next()
+		if err != nil {
+			return 0, err
+		}
+		if !next {
+			return count, nil
+		}
+		count++
+	}
+}
+
+// liveValuesSize returns the total size in bytes of the stored field values of the documents
+// of the collection that have not been deleted.
+func (c *collection) liveValuesSize(ctx context.Context, txn datastore.Txn) (uint64, error) {
+	sizes, err := c.liveValueSizes(ctx, txn)
+	if err != nil {
+		return 0, err
+	}
+	var size uint64
+	for _, docSize := range sizes {
+		size += docSize
+	}
+	return size, nil
+}
+
+// liveValueSizes returns the size in bytes of the stored field values of each document of the
+// collection that has not been deleted, by document key.
+func (c *collection) liveValueSizes(ctx context.Context, txn datastore.Txn) (map[string]uint64, error) {
+	prefix := core.DataStoreKey{
+		CollectionID: fmt.Sprint(c.colID),
+		InstanceType: core.ValueKey,
+	}
+	q, err := txn.Datastore().Query(ctx, query.Query{
+		Prefix:       prefix.ToString(),
+		KeysOnly:     true,
+		ReturnsSizes: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := q.Close(); err != nil {
+			log.ErrorE(ctx, "Failed to close document size query", err)
+		}
+	}()
+
+	sizes := map[string]uint64{}
+	deleted := map[string]bool{}
+	for res := range q.Next() {
+		if res.Error != nil {
+			return nil, res.Error
+		}
+		key, err := core.NewDataStoreKey(res.Key)
+		if err != nil {
+			return nil, err
+		}
+		isDeleted, checked := deleted[key.DocKey]
+		if !checked {
+			_, isDeleted, err = c.exists(ctx, txn, key.ToPrimaryDataStoreKey())
+			if err != nil {
+				return nil, err
+			}
+			deleted[key.DocKey] = isDeleted
+		}
+		if !isDeleted {
+			sizes[key.DocKey] += uint64(res.Size)
+		}
+	}
+	return sizes, nil
+}
@@ -0,0 +1,120 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/client"
+)
+
+func newTestCollectionWithDocs(t *testing.T, ctx context.Context, docs ...string) client.Collection {
+	db, err := newMemoryDB(ctx)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close(ctx) })
+
+	err = db.AddSchema(ctx, `type users { Name: String Age: Int Weight: Float }`)
+	require.NoError(t, err)
+	col, err := db.GetCollectionByName(ctx, "users")
+	require.NoError(t, err)
+
+	for _, data := range docs {
+		doc, err := client.NewDocFromJSON([]byte(data))
+		require.NoError(t, err)
+		err = col.Create(ctx, doc)
+		require.NoError(t, err)
+	}
+	return col
+}
+
+func TestCollectionCount(t *testing.T) {
+	ctx := context.Background()
+	col := newTestCollectionWithDocs(
+		t,
+		ctx,
+		`{"Name": "John", "Age": 21}`,
+		`{"Name": "Islam", "Age": 33}`,
+		`{"Name": "Fred", "Age": 40}`,
+	)
+
+	count, err := col.Count(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), count)
+
+	count, err = col.Count(ctx, `{Age: {_gt: 30}}`)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), count)
+}
+
+func TestCollectionCountWithInvalidFilter(t *testing.T) {
+	ctx := context.Background()
+	col := newTestCollectionWithDocs(t, ctx)
+
+	_, err := col.Count(ctx, 1)
+	assert.ErrorIs(t, err, ErrInvalidFilter)
+}
+
+func TestCollectionStats(t *testing.T) {
+	ctx := context.Background()
+	col := newTestCollectionWithDocs(t, ctx)
+
+	stats, err := col.Stats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, client.CollectionStats{Name: "users"}, stats)
+
+	for _, data := range []string{`{"Name": "John", "Age": 21}`, `{"Name": "Fred", "Age": 40}`} {
+		doc, err := client.NewDocFromJSON([]byte(data))
+		require.NoError(t, err)
+		err = col.Create(ctx, doc)
+		require.NoError(t, err)
+
+		if data == `{"Name": "Fred", "Age": 40}` {
+			_, err = col.Delete(ctx, doc.Key())
+			require.NoError(t, err)
+		}
+	}
+
+	stats, err = col.Stats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), stats.DocCount)
+	assert.Equal(t, uint64(1), stats.TombstoneCount)
+	assert.Greater(t, stats.AvgDocSize, float64(0))
+	assert.NotNil(t, stats.LastUpdatedAt)
+
+	count, err := col.Count(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), count)
+}
+
+func TestCollectionStatsWithRemoteMerges(t *testing.T) {
+	ctx := context.Background()
+	col := newTestCollectionWithDocs(t, ctx)
+
+	col.RecordRemoteMerge(client.RemoteMerge{Priority: 1, Commit: true})
+	col.RecordRemoteMerge(client.RemoteMerge{Priority: 1, Conflicts: 1})
+	col.RecordRemoteMerge(client.RemoteMerge{Priority: 3, Commit: true, Conflicts: 2})
+
+	stats, err := col.Stats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), stats.RemoteDeltasMerged)
+	assert.Equal(t, uint64(3), stats.LWWConflicts)
+	assert.Equal(t, float64(2), stats.AvgDAGDepth)
+
+	metrics, err := col.(*collection).db.Metrics(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, metrics, mergedDeltasMetric)
+	assert.Contains(t, metrics, conflictsMetric)
+	assert.Contains(t, metrics, dagDepthMetric)
+}
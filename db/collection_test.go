@@ -15,6 +15,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/ipfs/go-cid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -343,3 +344,30 @@ func TestGetCollectionByNameReturnsErrorGivenEmptyString(t *testing.T) {
 	_, err = db.GetCollectionByName(ctx, "")
 	assert.EqualError(t, err, "collection name can't be empty")
 }
+
+func TestGetAllDocKeysWithHeads(t *testing.T) {
+	ctx := context.Background()
+	db, err := newMemoryDB(ctx)
+	require.NoError(t, err)
+	defer db.Close(ctx)
+
+	col, err := newTestCollectionWithSchema(t, ctx, db)
+	require.NoError(t, err)
+
+	doc, err := client.NewDocFromJSON([]byte(`{"Name": "John", "Age": 21}`))
+	require.NoError(t, err)
+	err = col.Create(ctx, doc)
+	require.NoError(t, err)
+
+	keysCh, err := col.GetAllDocKeysWithHeads(ctx)
+	require.NoError(t, err)
+
+	results := []client.DocKeysResult{}
+	for res := range keysCh {
+		require.NoError(t, res.Err)
+		results = append(results, res)
+	}
+	require.Len(t, results, 1)
+	assert.Equal(t, doc.Key().String(), results[0].Key.String())
+	assert.Equal(t, []cid.Cid{doc.Head()}, results[0].Heads)
+}
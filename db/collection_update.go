@@ -13,6 +13,7 @@ package db
 import (
 	"context"
 	"fmt"
+	"math"
 	"strings"
 
 	cbor "github.com/fxamacker/cbor/v2"
@@ -280,6 +281,14 @@ func (c *collection) applyMerge(
 	doc map[string]any,
 	merge *fastjson.Object,
 ) error {
+	if err := c.checkUpdatable(); err != nil {
+		return err
+	}
+	ctx, err := c.db.withFieldKeyring(ctx)
+	if err != nil {
+		return err
+	}
+
 	keyStr, ok := doc["_key"].(string)
 	if !ok {
 		return ErrDocMissingKey
@@ -321,13 +330,21 @@ func (c *collection) applyMerge(
 			continue
 		}
 
-		cborVal, err := validateFieldSchema(mval, fd)
+		// A null value clears the field, as per the JSON Merge Patch semantics.
+		var cborVal any
+		if mval.Type() != fastjson.TypeNull {
+			var err error
+			cborVal, err = validateFieldSchema(mval, fd)
+			if err != nil {
+				return err
+			}
+		}
+		val, err := c.encryptFieldValue(ctx, fd, client.NewCBORValue(fd.Typ, cborVal))
 		if err != nil {
 			return err
 		}
-		mergeCBOR[mfield] = cborVal
+		mergeCBOR[mfield] = val.Value()
 
-		val := client.NewCBORValue(fd.Typ, cborVal)
 		fieldKey, fieldExists := c.tryGetFieldKey(key, mfield)
 		if !fieldExists {
 			return client.NewErrFieldNotExist(mfield)
@@ -367,23 +384,23 @@ func (c *collection) applyMerge(
 		return err
 	}
 
-	if c.db.events.Updates.HasValue() {
-		txn.OnSuccess(
-			func() {
-				c.db.events.Updates.Value().Publish(
-					events.Update{
-						DocKey:   keyStr,
-						Cid:      headNode.Cid(),
-						SchemaID: c.schemaID,
-						Block:    headNode,
-						Priority: priority,
-					},
-				)
-			},
-		)
+	err = c.publishUpdate(
+		ctx,
+		txn,
+		events.Update{
+			DocKey:   keyStr,
+			Cid:      headNode.Cid(),
+			SchemaID: c.schemaID,
+			Block:    headNode,
+			Priority: priority,
+		},
+	)
+	if err != nil {
+		return err
 	}
-
-	return nil
+	// The merge is the one of the patch of a local update, not of a delta from another node, so
+	// the write is checked against the quota.
+	return c.enforceQuota(ctx, txn, keyStr)
 }
 
 // isSecondaryIDField returns true if the given field description represents a secondary relation field ID.
@@ -459,13 +476,13 @@ func validateFieldSchema(val *fastjson.Value, field client.FieldDescription) (an
 		return getNillableArray(val, getBool)
 
 	case client.FieldKind_FLOAT:
-		return getFloat64(val)
+		return getFiniteFloat64(field.Name)(val)
 
 	case client.FieldKind_FLOAT_ARRAY:
-		return getArray(val, getFloat64)
+		return getArray(val, getFiniteFloat64(field.Name))
 
 	case client.FieldKind_NILLABLE_FLOAT_ARRAY:
-		return getNillableArray(val, getFloat64)
+		return getNillableArray(val, getFiniteFloat64(field.Name))
 
 	case client.FieldKind_DATETIME:
 		// @TODO: Requires Typed Document refactor
@@ -478,6 +495,20 @@ func validateFieldSchema(val *fastjson.Value, field client.FieldDescription) (an
 	case client.FieldKind_INT:
 		return getInt64(val)
 
+	case client.FieldKind_INT32:
+		i, err := getInt64(val)
+		if err != nil {
+			return nil, NewErrIntegerOutOfRange(field.Name, field.Kind, val.String())
+		}
+		return i, validateIntegerValue(field, i)
+
+	case client.FieldKind_UINT64:
+		u, err := val.Uint64()
+		if err != nil {
+			return nil, NewErrIntegerOutOfRange(field.Name, field.Kind, val.String())
+		}
+		return u, nil
+
 	case client.FieldKind_INT_ARRAY:
 		return getArray(val, getInt64)
 
@@ -491,6 +522,30 @@ func validateFieldSchema(val *fastjson.Value, field client.FieldDescription) (an
 	return nil, client.NewErrUnhandledType("FieldKind", field.Kind)
 }
 
+// validateIntegerValue returns an error if the given value of the given field is not a whole
+// number in the range of the field's kind, if it is an Int32 or UInt64 field.
+func validateIntegerValue(field client.FieldDescription, value any) error {
+	switch field.Kind {
+	case client.FieldKind_INT32:
+		if i, ok := value.(int64); ok && i >= math.MinInt32 && i <= math.MaxInt32 {
+			return nil
+		}
+		return NewErrIntegerOutOfRange(field.Name, field.Kind, value)
+
+	case client.FieldKind_UINT64:
+		switch v := value.(type) {
+		case uint64:
+			return nil
+		case int64:
+			if v >= 0 {
+				return nil
+			}
+		}
+		return NewErrIntegerOutOfRange(field.Name, field.Kind, value)
+	}
+	return nil
+}
+
 func getString(v *fastjson.Value) (string, error) {
 	b, err := v.StringBytes()
 	return string(b), err
@@ -500,8 +555,19 @@ func getBool(v *fastjson.Value) (bool, error) {
 	return v.Bool()
 }
 
-func getFloat64(v *fastjson.Value) (float64, error) {
-	return v.Float64()
+// getFiniteFloat64 returns a getter of the float values of the field with the given name,
+// which rejects NaN and infinities as the JSON parser accepts them.
+func getFiniteFloat64(field string) func(*fastjson.Value) (float64, error) {
+	return func(v *fastjson.Value) (float64, error) {
+		f, err := v.Float64()
+		if err != nil {
+			return 0, err
+		}
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return 0, client.NewErrNonFiniteFloat(field, f)
+		}
+		return f, nil
+	}
 }
 
 func getInt64(v *fastjson.Value) (int64, error) {
@@ -595,7 +661,12 @@ func (c *collection) makeSelectionPlan(
 		return nil, err
 	}
 
+	ctx, err = c.db.withFieldKeyring(ctx)
+	if err != nil {
+		return nil, err
+	}
 	planner := planner.New(ctx, c.db.WithTxn(txn), txn)
+	planner.SetDocumentCache(c.db.docCache)
 	return planner.MakePlan(&request.Request{
 		Queries: []*request.OperationDefinition{
 			{
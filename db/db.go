@@ -17,19 +17,25 @@ package db
 import (
 	"context"
 	"sync"
+	"time"
 
 	blockstore "github.com/ipfs/boxo/blockstore"
 	ds "github.com/ipfs/go-datastore"
 	dsq "github.com/ipfs/go-datastore/query"
+	"github.com/libp2p/go-libp2p/core/crypto"
 	"github.com/sourcenetwork/immutable"
 
 	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/client/request"
 	"github.com/sourcenetwork/defradb/core"
+	corecrdt "github.com/sourcenetwork/defradb/core/crdt"
 	"github.com/sourcenetwork/defradb/datastore"
+	"github.com/sourcenetwork/defradb/db/fetcher"
 	"github.com/sourcenetwork/defradb/errors"
 	"github.com/sourcenetwork/defradb/events"
 	"github.com/sourcenetwork/defradb/logging"
 	"github.com/sourcenetwork/defradb/merkle/crdt"
+	"github.com/sourcenetwork/defradb/metric"
 	"github.com/sourcenetwork/defradb/request/graphql"
 )
 
@@ -63,8 +69,106 @@ type db struct {
 	// The maximum number of retries per transaction.
 	maxTxnRetries immutable.Option[int]
 
+	// The initial delay before retrying a request whose transaction conflicted.
+	// Requests aren't retried if not set.
+	txnRetryBackoff immutable.Option[time.Duration]
+
 	// The options used to init the database
 	options any
+
+	// The requests currently being executed.
+	requests requestTracker
+
+	// The time of the last document write of each collection.
+	updates updateTracker
+
+	// The merges of the deltas produced by other nodes into the documents of each collection.
+	merges *mergeTracker
+
+	// The meter the metrics of the database are gathered by.
+	meter metric.Meter
+
+	// The maximum number of documents cached per collection. Documents aren't cached if not set.
+	docCacheSize immutable.Option[int]
+
+	// The cache of the recently fetched documents, if enabled.
+	docCache *fetcher.DocumentCache
+
+	// now returns the current time, as recorded with the commits and the document writes.
+	now func() time.Time
+
+	// Whether the updates of the documents are persisted in the changefeed of their collection.
+	changefeed bool
+
+	// The dispatcher of the document events to the webhooks, set if the update events are enabled.
+	webhooks *webhookDispatcher
+
+	// The maximum number of attempts made to deliver each event to a webhook.
+	webhookMaxAttempts int
+
+	// The delay before the first retry of the delivery of an event to a webhook.
+	webhookBackoff time.Duration
+
+	// The identity the schema updates must be signed by, if set.
+	schemaAdmin crypto.PubKey
+
+	// The keys the deltas of the encrypted collections are encrypted with, by collection name.
+	keyring *corecrdt.Keyring
+
+	// The keys of the encrypted fields readable by each identity, by identity then field name.
+	identityFieldKeys map[string]map[string][]byte
+
+	// The masking rules of the fields masked in the exports and subscriptions, by field name of
+	// the form `Collection.field`.
+	maskingRules map[string]MaskingRule
+	// The secret key of the hashes of the hashed fields.
+	maskingKey []byte
+
+	// The forwarder of the queries for the collections the database does not hold, if set.
+	forwarder RequestForwarder
+
+	// The maximum number of concurrent read only transactions. It is unlimited if not set.
+	maxReadTxns immutable.Option[int]
+
+	// The maximum cost of the requests, weighted by the cost multipliers of the collections. It is
+	// unlimited if zero.
+	maxRequestCost int
+
+	// The pool of the transactions opened with NewTxn and NewConcurrentTxn.
+	txns *txnPool
+
+	// The hooks called before the execution of each request.
+	requestHooks []RequestHook
+
+	// The interval at which the documents past their retention window are deleted.
+	retentionInterval time.Duration
+
+	// The retention windows of the collections whose expired documents are deleted.
+	retentionPolicies []RetentionPolicy
+
+	// The pruner of the expired documents, set if any retention policy is set.
+	retention *retentionPruner
+
+	// The mutex protecting the databases of the branches.
+	branchesMu sync.Mutex
+
+	// The databases of the branches opened with GetBranch, by branch name.
+	branches map[string]*implicitTxnDB
+
+	// The integrity check of the system keyspace run on open, if set.
+	integrityCheck immutable.Option[client.CheckIntegrityOptions]
+
+	// The mutex protecting the report of the last integrity check.
+	integrityMu sync.Mutex
+
+	// The report of the last integrity check, if any.
+	integrityReport immutable.Option[client.IntegrityReport]
+
+	// The storage quotas of the collections, by collection name.
+	quotas map[string]CollectionQuota
+
+	// The storage used by the documents of each collection with a quota.
+	quotaUsages quotaTracker
 }
 
 // Functional option type.
@@ -75,9 +179,30 @@ const updateEventBufferSize = 100
 // WithUpdateEvents enables the update events channel.
 func WithUpdateEvents() Option {
 	return func(db *db) {
-		db.events = events.Events{
-			Updates: immutable.Some(events.New[events.Update](0, updateEventBufferSize)),
-		}
+		db.events.Updates = immutable.Some(events.New[events.Update](0, updateEventBufferSize))
+	}
+}
+
+// WithChangefeed enables the persisted changefeed of each collection, in which the local
+// updates of the documents are recorded with monotonically increasing sequence numbers.
+//
+// It also enables the update events channel, so that the changefeed can be subscribed to.
+// Concurrent transactions writing to the same collection conflict with each other as they
+// share the sequence of its changefeed.
+func WithChangefeed() Option {
+	return func(db *db) {
+		db.changefeed = true
+	}
+}
+
+// WithWebhookRetries sets the maximum number of attempts made to deliver each event to a webhook,
+// and the delay before the first retry, which doubles with each retry.
+//
+// It defaults to 5 attempts, with a delay of one second before the first retry.
+func WithWebhookRetries(maxAttempts int, backoff time.Duration) Option {
+	return func(db *db) {
+		db.webhookMaxAttempts = maxAttempts
+		db.webhookBackoff = backoff
 	}
 }
 
@@ -88,6 +213,119 @@ func WithMaxRetries(num int) Option {
 	}
 }
 
+// WithTxnRetryBackoff enables the automatic retry of the requests whose transaction conflicts
+// with another transaction, up to the maximum number of retries per transaction.
+//
+// The delay before each retry starts from the given backoff and doubles with each retry.
+func WithTxnRetryBackoff(backoff time.Duration) Option {
+	return func(db *db) {
+		db.txnRetryBackoff = immutable.Some(backoff)
+	}
+}
+
+// WithDocumentCache enables the caching of up to the given number of recently fetched
+// documents per collection.
+//
+// Cached documents are removed on writes if the update events channel is enabled.
+func WithDocumentCache(size int) Option {
+	return func(db *db) {
+		db.docCacheSize = immutable.Some(size)
+	}
+}
+
+// WithClock sets the function returning the current time, as recorded with the commits and
+// the document writes. It defaults to time.Now.
+//
+// This allows the tests to control the times the documents are requested at.
+func WithClock(now func() time.Time) Option {
+	return func(db *db) {
+		db.now = now
+	}
+}
+
+// WithSchemaAdmin requires the schema additions and patches to be signed by the given identity.
+//
+// This is intended for networked nodes, so that a compromised peer can't push malicious schema
+// changes into the shared dataset. The signed updates are recorded and shared with the other
+// nodes so that they can verify them as well.
+func WithSchemaAdmin(admin crypto.PubKey) Option {
+	return func(db *db) {
+		db.schemaAdmin = admin
+	}
+}
+
+// WithKeyring encrypts the data of the deltas of the collections with a key in the given keyring.
+//
+// The blocks of these collections can then be replicated by the peers that don't have the key,
+// such as relays, without them being able to read their contents.
+func WithKeyring(keyring *corecrdt.Keyring) Option {
+	return func(db *db) {
+		db.keyring = keyring
+	}
+}
+
+// WithIdentityFieldKeys sets the AES-256 keys of the encrypted fields, by field name of the form
+// `Collection.field`, that the requests authenticated as each identity are given, by identity.
+//
+// They are given in addition to the keys presented along with the requests.
+func WithIdentityFieldKeys(keys map[string]map[string][]byte) Option {
+	return func(db *db) {
+		db.identityFieldKeys = keys
+	}
+}
+
+// WithRequestForwarder forwards the queries for the collections the database does not hold to
+// an upstream database with the given forwarder, so that a light node can hold only some of the
+// collections locally.
+func WithRequestForwarder(forwarder RequestForwarder) Option {
+	return func(db *db) {
+		db.forwarder = forwarder
+	}
+}
+
+// WithMaxReadTxns sets the maximum number of concurrent read only transactions, such as those
+// of the queries. The transactions in excess are queued until others end, or until their
+// context is done.
+//
+// This bounds the memory held by the snapshots of the read only transactions when many queries
+// are executed concurrently.
+func WithMaxReadTxns(num int) Option {
+	return func(db *db) {
+		db.maxReadTxns = immutable.Some(num)
+	}
+}
+
+// WithMaxRequestCost sets the maximum cost of the requests, which are rejected before their
+// execution if they exceed it.
+//
+// The cost of a selection of the documents of a collection is the cost multiplier of the
+// collection times one plus the costs of its nested selections, so that the selections nested in
+// those of heavyweight collections weigh more. The cost of a request is the sum of the costs of
+// its selections.
+func WithMaxRequestCost(cost int) Option {
+	return func(db *db) {
+		db.maxRequestCost = cost
+	}
+}
+
+// RequestHook is called before the execution of a request with its parsed form and its context,
+// from which its [client.RequestContext] can be read.
+//
+// The request is not executed if the hook returns an error, which is returned as the error of the
+// request. Hooks can thus both audit the requests and restrict them, e.g. to the collections the
+// identity of the request may access.
+type RequestHook func(ctx context.Context, req *request.Request) error
+
+// WithRequestHook adds a hook called before the execution of each request, after the hooks
+// added before it.
+//
+// Introspection requests, and requests forwarded upstream, are not passed to the hooks.
+func WithRequestHook(hook RequestHook) Option {
+	return func(db *db) {
+		db.requestHooks = append(db.requestHooks, hook)
+	}
+}
+
 // NewDB creates a new instance of the DB using the given options.
 func NewDB(ctx context.Context, rootstore datastore.RootStore, options ...Option) (client.DB, error) {
 	return newDB(ctx, rootstore, options...)
@@ -112,6 +350,10 @@ func newDB(ctx context.Context, rootstore datastore.RootStore, options ...Option
 
 		parser:  parser,
 		options: options,
+		now:     time.Now,
+
+		webhookMaxAttempts: defaultWebhookMaxAttempts,
+		webhookBackoff:     defaultWebhookBackoff,
 	}
 
 	// apply options
@@ -122,22 +364,84 @@ func newDB(ctx context.Context, rootstore datastore.RootStore, options ...Option
 		opt(db)
 	}
 
+	db.meter.Register(metricsName)
+	db.merges, err = newMergeTracker(&db.meter)
+	if err != nil {
+		return nil, err
+	}
+
+	maxReadTxns := 0
+	if db.maxReadTxns.HasValue() {
+		maxReadTxns = db.maxReadTxns.Value()
+	}
+	db.txns = newTxnPool(maxReadTxns)
+
+	if db.changefeed && !db.events.Updates.HasValue() {
+		WithUpdateEvents()(db)
+	}
+
+	err = db.initQuotas()
+	if err != nil {
+		return nil, err
+	}
+
+	if db.docCacheSize.HasValue() {
+		db.docCache, err = fetcher.NewDocumentCache(db.docCacheSize.Value())
+		if err != nil {
+			return nil, err
+		}
+		err = db.startDocumentCacheEviction()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, keys := range db.identityFieldKeys {
+		if _, err := corecrdt.NewKeyring(keys); err != nil {
+			return nil, err
+		}
+	}
+
+	err = db.checkIntegrityOnOpen(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	err = db.initialize(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	err = db.initQuotaUsages(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.startWebhookDispatcher(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.startRetentionPruner()
+	if err != nil {
+		return nil, err
+	}
+
 	return &implicitTxnDB{db}, nil
 }
 
 // NewTxn creates a new transaction.
 func (db *db) NewTxn(ctx context.Context, readonly bool) (datastore.Txn, error) {
-	return datastore.NewTxnFrom(ctx, db.rootstore, readonly)
+	return db.txns.open(ctx, readonly, func() (datastore.Txn, error) {
+		return datastore.NewTxnFrom(ctx, db.rootstore, readonly)
+	})
 }
 
 // NewConcurrentTxn creates a new transaction that supports concurrent API calls.
 func (db *db) NewConcurrentTxn(ctx context.Context, readonly bool) (datastore.Txn, error) {
-	return datastore.NewConcurrentTxnFrom(ctx, db.rootstore, readonly)
+	return db.txns.open(ctx, readonly, func() (datastore.Txn, error) {
+		return datastore.NewConcurrentTxnFrom(ctx, db.rootstore, readonly)
+	})
 }
 
 // WithTxn returns a new [client.Store] that respects the given transaction.
@@ -233,9 +537,22 @@ func (db *db) PrintDump(ctx context.Context) error {
 // This is the place for any last minute cleanup or releasing of resources (i.e.: Badger instance).
 func (db *db) Close(ctx context.Context) {
 	log.Info(ctx, "Closing DefraDB process...")
+	if db.retention != nil {
+		db.retention.close()
+	}
 	if db.events.Updates.HasValue() {
 		db.events.Updates.Value().Close()
 	}
+	if db.events.Quotas.HasValue() {
+		db.events.Quotas.Value().Close()
+	}
+	if db.webhooks != nil {
+		db.webhooks.close()
+	}
+	db.closeBranches(ctx)
+	if err := db.meter.Close(ctx); err != nil {
+		log.ErrorE(ctx, "Failure closing the meter", err)
+	}
 
 	err := db.rootstore.Close()
 	if err != nil {
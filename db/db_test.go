@@ -25,13 +25,13 @@ import (
 	"github.com/sourcenetwork/defradb/merkle/clock"
 )
 
-func newMemoryDB(ctx context.Context) (*implicitTxnDB, error) {
+func newMemoryDB(ctx context.Context, options ...Option) (*implicitTxnDB, error) {
 	opts := badgerds.Options{Options: badger.DefaultOptions("").WithInMemory(true)}
 	rootstore, err := badgerds.NewDatastore("", &opts)
 	if err != nil {
 		return nil, err
 	}
-	return newDB(ctx, rootstore)
+	return newDB(ctx, rootstore, options...)
 }
 
 func TestNewDB(t *testing.T) {
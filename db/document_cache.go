@@ -0,0 +1,34 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+// startDocumentCacheEviction removes the documents that are written to, either locally or by
+// remote merges, from the document cache, as long as the update events channel is enabled.
+//
+// The cache never returns values read at other heads than the current ones of a document,
+// so this only frees the space of the documents whose cached values are outdated.
+func (db *db) startDocumentCacheEviction() error {
+	if !db.events.Updates.HasValue() {
+		return nil
+	}
+
+	updates, err := db.events.Updates.Value().Subscribe()
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		for update := range updates {
+			db.docCache.Remove(update.SchemaID, update.DocKey)
+		}
+	}()
+	return nil
+}
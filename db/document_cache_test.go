@@ -0,0 +1,91 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/client"
+)
+
+func TestNewDBWithInvalidDocumentCacheSize(t *testing.T) {
+	ctx := context.Background()
+	_, err := newMemoryDB(ctx, WithDocumentCache(0))
+	require.Error(t, err)
+}
+
+func TestDocumentCacheReturnsUpdatedValues(t *testing.T) {
+	ctx := context.Background()
+	db, err := newMemoryDB(ctx, WithDocumentCache(10))
+	require.NoError(t, err)
+	defer db.Close(ctx)
+	col, err := newTestCollectionWithSchema(t, ctx, db)
+	require.NoError(t, err)
+
+	doc, err := client.NewDocFromJSON([]byte(`{"Name": "John", "Age": 21}`))
+	require.NoError(t, err)
+	err = col.Save(ctx, doc)
+	require.NoError(t, err)
+
+	fetched, err := col.Get(ctx, doc.Key(), false)
+	require.NoError(t, err)
+	name, err := fetched.Get("Name")
+	require.NoError(t, err)
+	require.Equal(t, "John", name)
+	require.Equal(t, 1, db.docCache.Len(col.SchemaID()))
+
+	err = doc.Set("Name", "Pete")
+	require.NoError(t, err)
+	err = col.Save(ctx, doc)
+	require.NoError(t, err)
+
+	// Without the update events channel, the outdated document stays cached but isn't returned.
+	require.Equal(t, 1, db.docCache.Len(col.SchemaID()))
+	fetched, err = col.Get(ctx, doc.Key(), false)
+	require.NoError(t, err)
+	name, err = fetched.Get("Name")
+	require.NoError(t, err)
+	require.Equal(t, "Pete", name)
+}
+
+func TestDocumentCacheRemovesUpdatedDocuments(t *testing.T) {
+	ctx := context.Background()
+	db, err := newMemoryDB(ctx, WithUpdateEvents(), WithDocumentCache(10))
+	require.NoError(t, err)
+	defer db.Close(ctx)
+	col, err := newTestCollectionWithSchema(t, ctx, db)
+	require.NoError(t, err)
+
+	doc, err := client.NewDocFromJSON([]byte(`{"Name": "John", "Age": 21}`))
+	require.NoError(t, err)
+	err = col.Save(ctx, doc)
+	require.NoError(t, err)
+
+	_, err = col.Get(ctx, doc.Key(), false)
+	require.NoError(t, err)
+	require.Equal(t, 1, db.docCache.Len(col.SchemaID()))
+
+	err = doc.Set("Name", "Pete")
+	require.NoError(t, err)
+	err = col.Save(ctx, doc)
+	require.NoError(t, err)
+
+	require.Eventually(
+		t,
+		func() bool { return db.docCache.Len(col.SchemaID()) == 0 },
+		time.Second,
+		10*time.Millisecond,
+	)
+}
@@ -0,0 +1,156 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/fxamacker/cbor/v2"
+	dsq "github.com/ipfs/go-datastore/query"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/core"
+	"github.com/sourcenetwork/defradb/db/base"
+)
+
+// dumpKeyspaces maps the keyspaces that can be dumped to their prefix in the rootstore,
+// as laid out by [datastore.MultiStoreFrom].
+var dumpKeyspaces = map[string]string{
+	"system": "/db/system",
+	"data":   "/db/data",
+	"heads":  "/db/heads",
+	"blocks": "/db/blocks",
+}
+
+// dumpDecMode decodes CBOR maps into JSON compatible maps.
+var dumpDecMode, _ = cbor.DecOptions{
+	DefaultMapType: reflect.TypeOf(map[string]any{}),
+}.DecMode()
+
+// Dump returns the entries of the rootstore matching the given options, ordered by key.
+func (db *db) Dump(ctx context.Context, opts client.DumpOptions) (<-chan client.DumpEntry, error) {
+	prefix := opts.Prefix
+	if opts.Keyspace != "" {
+		keyspacePrefix, ok := dumpKeyspaces[opts.Keyspace]
+		if !ok {
+			return nil, NewErrUnknownKeyspace(opts.Keyspace)
+		}
+		prefix = keyspacePrefix + prefix
+	}
+
+	results, err := db.multistore.Rootstore().Query(ctx, dsq.Query{
+		Prefix:       prefix,
+		KeysOnly:     !opts.Decode,
+		ReturnsSizes: true,
+		Orders:       []dsq.Order{dsq.OrderByKey{}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make(chan client.DumpEntry)
+	go func() {
+		defer func() {
+			if err := results.Close(); err != nil {
+				log.ErrorE(ctx, "Failed to close dump query", err)
+			}
+			close(entries)
+		}()
+
+		for res := range results.Next() {
+			entry := client.DumpEntry{
+				Key:  res.Key,
+				Size: res.Size,
+				Err:  res.Error,
+			}
+			if res.Error == nil && opts.Decode {
+				entry.Size = len(res.Value)
+				entry.Value = decodeDumpValue(res.Key, res.Value)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case entries <- entry:
+			}
+			if res.Error != nil {
+				return
+			}
+		}
+	}()
+
+	return entries, nil
+}
+
+// decodeDumpValue decodes the given rootstore value according to the store it belongs to.
+//
+// It returns nil if the encoding of the value is not known.
+func decodeDumpValue(key string, value []byte) any {
+	switch {
+	case strings.HasPrefix(key, dumpKeyspaces["data"]+"/"):
+		dsKey, err := core.NewDataStoreKey(strings.TrimPrefix(key, dumpKeyspaces["data"]))
+		if err != nil {
+			return nil
+		}
+		switch dsKey.InstanceType {
+		case core.ValueKey, core.DeletedKey:
+			// field values are prefixed with a byte indicating their CRDT type.
+			if len(value) == 0 {
+				return nil
+			}
+			var decoded any
+			if err := dumpDecMode.Unmarshal(value[1:], &decoded); err != nil {
+				return nil
+			}
+			return decoded
+		case core.PriorityKey:
+			return decodeUvarint(value)
+		default:
+			// primary keys hold the status of the document.
+			if len(value) == 1 && value[0] == base.DeletedObjectMarker {
+				return client.DocumentStatusToString[client.Deleted]
+			}
+			if len(value) == 1 && value[0] == base.ObjectMarker {
+				return client.DocumentStatusToString[client.Active]
+			}
+			return nil
+		}
+
+	case strings.HasPrefix(key, dumpKeyspaces["heads"]+"/"):
+		// heads hold the height of the head.
+		return decodeUvarint(value)
+
+	case strings.HasPrefix(key, dumpKeyspaces["system"]+"/"):
+		if json.Valid(value) {
+			return json.RawMessage(value)
+		}
+		if utf8.Valid(value) {
+			return string(value)
+		}
+		return nil
+
+	default:
+		return nil
+	}
+}
+
+func decodeUvarint(value []byte) any {
+	decoded, n := binary.Uvarint(value)
+	if n <= 0 {
+		return nil
+	}
+	return decoded
+}
@@ -0,0 +1,92 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/client"
+)
+
+func collectDump(t *testing.T, entries <-chan client.DumpEntry) []client.DumpEntry {
+	result := []client.DumpEntry{}
+	for entry := range entries {
+		require.NoError(t, entry.Err)
+		result = append(result, entry)
+	}
+	return result
+}
+
+func TestDumpDataKeyspace(t *testing.T) {
+	ctx := context.Background()
+	db, err := newMemoryDB(ctx)
+	require.NoError(t, err)
+	defer db.Close(ctx)
+
+	col, err := newTestCollectionWithSchema(t, ctx, db)
+	require.NoError(t, err)
+
+	doc, err := client.NewDocFromJSON([]byte(`{"Name": "John", "Age": 21}`))
+	require.NoError(t, err)
+	err = col.Create(ctx, doc)
+	require.NoError(t, err)
+
+	entries, err := db.Dump(ctx, client.DumpOptions{Keyspace: "data", Decode: true})
+	require.NoError(t, err)
+
+	values := map[string]any{}
+	for _, entry := range collectDump(t, entries) {
+		assert.True(t, strings.HasPrefix(entry.Key, "/db/data/"))
+		assert.Greater(t, entry.Size, 0)
+		values[strings.TrimPrefix(entry.Key, "/db/data")] = entry.Value
+	}
+
+	key := doc.Key().String()
+	assert.Equal(t, "Active", values["/1/pk/"+key])
+	assert.Equal(t, "John", values["/1/v/"+key+"/1"])
+	assert.Equal(t, uint64(21), values["/1/v/"+key+"/2"])
+	assert.Equal(t, uint64(1), values["/1/p/"+key+"/1"])
+}
+
+func TestDumpWithPrefixWithoutDecoding(t *testing.T) {
+	ctx := context.Background()
+	db, err := newMemoryDB(ctx)
+	require.NoError(t, err)
+	defer db.Close(ctx)
+
+	_, err = newTestCollectionWithSchema(t, ctx, db)
+	require.NoError(t, err)
+
+	entries, err := db.Dump(ctx, client.DumpOptions{Keyspace: "system", Prefix: "/collection"})
+	require.NoError(t, err)
+
+	result := collectDump(t, entries)
+	require.NotEmpty(t, result)
+	for _, entry := range result {
+		assert.True(t, strings.HasPrefix(entry.Key, "/db/system/collection"))
+		assert.Nil(t, entry.Value)
+	}
+}
+
+func TestDumpWithUnknownKeyspace(t *testing.T) {
+	ctx := context.Background()
+	db, err := newMemoryDB(ctx)
+	require.NoError(t, err)
+	defer db.Close(ctx)
+
+	_, err = db.Dump(ctx, client.DumpOptions{Keyspace: "unknown"})
+	assert.ErrorIs(t, err, ErrUnknownKeyspace)
+}
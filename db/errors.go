@@ -11,6 +11,8 @@
 package db
 
 import (
+	"github.com/ipfs/go-cid"
+
 	"github.com/sourcenetwork/defradb/client"
 	"github.com/sourcenetwork/defradb/errors"
 )
@@ -25,7 +27,9 @@ const (
 	errAddCollectionWithPatch        string = "unknown collection, adding collections via patch is not supported"
 	errCollectionIDDoesntMatch       string = "CollectionID does not match existing"
 	errSchemaIDDoesntMatch           string = "SchemaID does not match existing"
+	errSchemaVersionIDDoesntMatch    string = "schema VersionID does not match the shared one"
 	errCannotModifySchemaName        string = "modifying the schema name is not supported"
+	errCannotModifyAppendOnly        string = "modifying whether the schema is append-only is not supported"
 	errCannotSetVersionID            string = "setting the VersionID is not supported. It is updated automatically"
 	errCannotSetFieldID              string = "explicitly setting a field ID value is not supported"
 	errCannotAddRelationalField      string = "the adding of new relation fields is not yet supported"
@@ -35,6 +39,37 @@ const (
 	errInvalidCRDTType               string = "only default or LWW (last writer wins) CRDT types are supported"
 	errCannotDeleteField             string = "deleting an existing field is not supported"
 	errFieldKindNotFound             string = "no type found for given name"
+	errRequestCancelled              string = "the request was cancelled"
+	errUnknownKeyspace               string = "unknown keyspace"
+	errInvalidBatchSize              string = "batch size must be greater than zero"
+	errChangefeedDisabled            string = "the changefeed is not enabled"
+	errInvalidWebhookURL             string = "the webhook URL must be an absolute http or https URL"
+	errWebhookDeliveryFailed         string = "the webhook responded with an unsuccessful status"
+	errSignedSchemaUpdateNotFound    string = "no signed schema update produced the schema version"
+	errIntegerOutOfRange             string = "the value is not a whole number in the range of the kind of the field"
+	errNoBlocksToMerge               string = "no blocks to merge"
+	errInvalidBlock                  string = "the block is not a valid block of a document"
+	errNonCanonicalBlock             string = "the block is not canonically encoded"
+	errInvalidCommit                 string = "the block is not a valid commit of the document"
+	errCommitOfOtherDocument         string = "the commit is of another document than the commit linking to it"
+	errMergeRootNotFound             string = "the blocks to merge must hold a single commit that no other block links to"
+	errBlockOfOtherCollection        string = "the block is a commit of a document of another collection"
+	errMissingBlock                  string = "the linked block is neither given nor known"
+	errReadTxnNotAcquired            string = "gave up waiting for a read transaction"
+	errDocumentNotDeleted            string = "only deleted documents can be purged"
+	errInvalidRetentionMaxAge        string = "the retention max age must be greater than zero"
+	errAppendOnlyCollection          string = "the documents of an append-only collection can't be updated or deleted"
+	errMissingFieldKey               string = "the field is encrypted and no key is available to encrypt its value"
+	errRequestTooCostly              string = "the request exceeds the maximum cost"
+	errInvalidCostMultiplier         string = "the cost multiplier of a schema can't be negative"
+	errNoBlockFetcher                string = "the blocks can't be repaired without a block fetcher. P2P might be disabled"
+	errRefetchedBlockMismatch        string = "the fetched block has a different hash than its CID"
+	errInvalidDataset                string = "the dataset root is not a valid block"
+	errDatasetOfOtherSchema          string = "the dataset is of documents of another schema than the collection's"
+	errCloneOfRelatedCollection      string = "a collection with relation fields can't be cloned"
+	errQuotaExceeded                 string = "the write exceeds the storage quota of the collection"
+	errInvalidQuota                  string = "the quota must limit the number of documents or the size of the collection"
+	errInvalidQuotaUsage             string = "the recorded usage of the document is invalid"
 )
 
 var (
@@ -44,44 +79,106 @@ var (
 	// ErrDocVerification occurs when a documents contents fail the verification during a Create()
 	// call against the supplied Document Key.
 	ErrDocVerification         = errors.New(errDocVerification)
-	ErrSubscriptionsNotAllowed = errors.New("server does not accept subscriptions")
-	ErrDeleteTargetEmpty       = errors.New("the doc delete targeter cannot be empty")
-	ErrDeleteEmpty             = errors.New("the doc delete cannot be empty")
-	ErrUpdateTargetEmpty       = errors.New("the doc update targeter cannot be empty")
-	ErrUpdateEmpty             = errors.New("the doc update cannot be empty")
-	ErrInvalidMergeValueType   = errors.New(
+	ErrSubscriptionsNotAllowed = errors.WithCode(
+		errors.CodeSubscriptionsDisabled,
+		errors.New("server does not accept subscriptions"),
+	)
+	ErrDeleteTargetEmpty     = errors.New("the doc delete targeter cannot be empty")
+	ErrDeleteEmpty           = errors.New("the doc delete cannot be empty")
+	ErrUpdateTargetEmpty     = errors.New("the doc update targeter cannot be empty")
+	ErrUpdateEmpty           = errors.New("the doc update cannot be empty")
+	ErrInvalidMergeValueType = errors.New(
 		"the type of value in the merge patch doesn't match the schema",
 	)
 	ErrMissingDocFieldToUpdate  = errors.New("missing document field to update")
 	ErrDocMissingKey            = errors.New("document is missing key")
 	ErrMergeSubTypeNotSupported = errors.New("merge doesn't support sub types yet")
-	ErrInvalidFilter            = errors.New("invalid filter")
-	ErrInvalidOpPath            = errors.New("invalid patch op path")
-	ErrDocumentAlreadyExists    = errors.New("a document with the given dockey already exists")
-	ErrDocumentDeleted          = errors.New("a document with the given dockey has been deleted")
+	ErrInvalidFilter            = errors.WithCode(errors.CodeInvalidFilter, errors.New("invalid filter"))
+	ErrInvalidOpPath            = errors.WithCode(errors.CodeInvalidSchemaPatch, errors.New("invalid patch op path"))
+	ErrDocumentAlreadyExists    = errors.WithCode(
+		errors.CodeDocumentExists,
+		errors.New("a document with the given dockey already exists"),
+	)
+	ErrDocumentDeleted = errors.WithCode(
+		errors.CodeDocumentDeleted,
+		errors.New("a document with the given dockey has been deleted"),
+	)
 	ErrUnknownCRDTArgument      = errors.New("invalid CRDT arguments")
 	ErrUnknownCRDT              = errors.New("unknown crdt")
 	ErrSchemaFirstFieldDocKey   = errors.New("collection schema first field must be a DocKey")
-	ErrCollectionAlreadyExists  = errors.New("collection already exists")
+	ErrCollectionAlreadyExists  = errors.WithCode(errors.CodeCollectionExists, errors.New("collection already exists"))
 	ErrCollectionNameEmpty      = errors.New("collection name can't be empty")
 	ErrSchemaIdEmpty            = errors.New("schema ID can't be empty")
 	ErrSchemaVersionIdEmpty     = errors.New("schema version ID can't be empty")
 	ErrKeyEmpty                 = errors.New("key cannot be empty")
 	ErrAddingP2PCollection      = errors.New(errAddingP2PCollection)
 	ErrRemovingP2PCollection    = errors.New(errRemovingP2PCollection)
-	ErrAddCollectionWithPatch   = errors.New(errAddCollectionWithPatch)
-	ErrCollectionIDDoesntMatch  = errors.New(errCollectionIDDoesntMatch)
-	ErrSchemaIDDoesntMatch      = errors.New(errSchemaIDDoesntMatch)
-	ErrCannotModifySchemaName   = errors.New(errCannotModifySchemaName)
-	ErrCannotSetVersionID       = errors.New(errCannotSetVersionID)
-	ErrCannotSetFieldID         = errors.New(errCannotSetFieldID)
-	ErrCannotAddRelationalField = errors.New(errCannotAddRelationalField)
-	ErrDuplicateField           = errors.New(errDuplicateField)
-	ErrCannotMutateField        = errors.New(errCannotMutateField)
-	ErrCannotMoveField          = errors.New(errCannotMoveField)
-	ErrInvalidCRDTType          = errors.New(errInvalidCRDTType)
-	ErrCannotDeleteField        = errors.New(errCannotDeleteField)
-	ErrFieldKindNotFound        = errors.New(errFieldKindNotFound)
+	ErrAddCollectionWithPatch   = errors.WithCode(errors.CodeInvalidSchemaPatch, errors.New(errAddCollectionWithPatch))
+	ErrCollectionIDDoesntMatch  = errors.WithCode(errors.CodeInvalidSchemaPatch, errors.New(errCollectionIDDoesntMatch))
+	ErrSchemaIDDoesntMatch      = errors.WithCode(errors.CodeInvalidSchemaPatch, errors.New(errSchemaIDDoesntMatch))
+	ErrCannotModifySchemaName   = errors.WithCode(errors.CodeInvalidSchemaPatch, errors.New(errCannotModifySchemaName))
+	ErrCannotModifyAppendOnly   = errors.WithCode(errors.CodeInvalidSchemaPatch, errors.New(errCannotModifyAppendOnly))
+	ErrCannotSetVersionID       = errors.WithCode(errors.CodeInvalidSchemaPatch, errors.New(errCannotSetVersionID))
+	ErrCannotSetFieldID         = errors.WithCode(errors.CodeInvalidSchemaPatch, errors.New(errCannotSetFieldID))
+	ErrCannotAddRelationalField = errors.WithCode(errors.CodeInvalidSchemaPatch, errors.New(errCannotAddRelationalField))
+	ErrDuplicateField           = errors.WithCode(errors.CodeInvalidSchemaPatch, errors.New(errDuplicateField))
+	ErrCannotMutateField        = errors.WithCode(errors.CodeInvalidSchemaPatch, errors.New(errCannotMutateField))
+	ErrCannotMoveField          = errors.WithCode(errors.CodeInvalidSchemaPatch, errors.New(errCannotMoveField))
+	ErrInvalidCRDTType          = errors.WithCode(errors.CodeInvalidSchemaPatch, errors.New(errInvalidCRDTType))
+	ErrCannotDeleteField        = errors.WithCode(errors.CodeInvalidSchemaPatch, errors.New(errCannotDeleteField))
+	ErrFieldKindNotFound        = errors.WithCode(errors.CodeInvalidSchemaPatch, errors.New(errFieldKindNotFound))
+	ErrRequestCancelled         = errors.WithCode(errors.CodeRequestCancelled, errors.New(errRequestCancelled))
+	ErrUnknownKeyspace          = errors.New(errUnknownKeyspace)
+	ErrInvalidBatchSize         = errors.WithCode(errors.CodeInvalidRequest, errors.New(errInvalidBatchSize))
+	ErrChangefeedDisabled       = errors.New(errChangefeedDisabled)
+	ErrWebhooksNotAllowed       = errors.WithCode(
+		errors.CodeSubscriptionsDisabled,
+		errors.New("server does not dispatch webhooks"),
+	)
+	ErrInvalidWebhookURL     = errors.WithCode(errors.CodeInvalidRequest, errors.New(errInvalidWebhookURL))
+	ErrWebhookDeliveryFailed = errors.New(errWebhookDeliveryFailed)
+	// ErrSchemaVersionsEmpty is returned when adding a collection from an empty list of schema versions.
+	ErrSchemaVersionsEmpty = errors.New("schema versions can't be empty")
+	// ErrSchemaVersionIDDoesntMatch is returned when the VersionID of a shared schema version does not
+	// match the one computed from its description.
+	ErrSchemaVersionIDDoesntMatch = errors.New(errSchemaVersionIDDoesntMatch)
+	// ErrSchemaSignatureRequired is returned when updating the schema without a signature while a
+	// schema admin identity is configured.
+	ErrSchemaSignatureRequired = errors.WithCode(
+		errors.CodeUnauthorized,
+		errors.New("schema updates must be signed by the schema admin identity"),
+	)
+	// ErrInvalidSchemaSignature is returned when the signature of a schema update is not that of
+	// the schema admin identity.
+	ErrInvalidSchemaSignature = errors.WithCode(
+		errors.CodeUnauthorized,
+		errors.New("the schema update is not signed by the schema admin identity"),
+	)
+	ErrSignedSchemaUpdateNotFound = errors.New(errSignedSchemaUpdateNotFound)
+	ErrIntegerOutOfRange          = errors.New(errIntegerOutOfRange)
+	ErrNoBlocksToMerge            = errors.New(errNoBlocksToMerge)
+	ErrInvalidBlock               = errors.New(errInvalidBlock)
+	ErrNonCanonicalBlock          = errors.New(errNonCanonicalBlock)
+	ErrInvalidCommit              = errors.New(errInvalidCommit)
+	ErrCommitOfOtherDocument      = errors.New(errCommitOfOtherDocument)
+	ErrMergeRootNotFound          = errors.New(errMergeRootNotFound)
+	ErrBlockOfOtherCollection     = errors.New(errBlockOfOtherCollection)
+	ErrMissingBlock               = errors.New(errMissingBlock)
+	ErrReadTxnNotAcquired         = errors.WithCode(errors.CodeTooManyRequests, errors.New(errReadTxnNotAcquired))
+	ErrDocumentNotDeleted         = errors.New(errDocumentNotDeleted)
+	ErrInvalidRetentionMaxAge     = errors.New(errInvalidRetentionMaxAge)
+	ErrAppendOnlyCollection       = errors.WithCode(errors.CodeInvalidRequest, errors.New(errAppendOnlyCollection))
+	ErrMissingFieldKey            = errors.WithCode(errors.CodeInvalidRequest, errors.New(errMissingFieldKey))
+	ErrRequestTooCostly           = errors.WithCode(errors.CodeInvalidRequest, errors.New(errRequestTooCostly))
+	ErrInvalidCostMultiplier      = errors.WithCode(errors.CodeInvalidSchemaPatch, errors.New(errInvalidCostMultiplier))
+	ErrNoBlockFetcher             = errors.WithCode(errors.CodeInvalidRequest, errors.New(errNoBlockFetcher))
+	ErrRefetchedBlockMismatch     = errors.New(errRefetchedBlockMismatch)
+	ErrInvalidDataset             = errors.New(errInvalidDataset)
+	ErrDatasetOfOtherSchema       = errors.New(errDatasetOfOtherSchema)
+	ErrCloneOfRelatedCollection   = errors.New(errCloneOfRelatedCollection)
+	ErrQuotaExceeded              = errors.WithCode(errors.CodeQuotaExceeded, errors.New(errQuotaExceeded))
+	ErrInvalidQuota               = errors.New(errInvalidQuota)
+	ErrInvalidQuotaUsage          = errors.New(errInvalidQuotaUsage)
 )
 
 // NewErrFailedToGetHeads returns a new error indicating that the heads of a document
@@ -147,6 +244,17 @@ func NewErrSchemaIDDoesntMatch(name, existingID, proposedID string) error {
 	)
 }
 
+// NewErrSchemaVersionIDDoesntMatch returns an error indicating that the VersionID of a schema
+// version shared by another node does not match the one computed from its description.
+func NewErrSchemaVersionIDDoesntMatch(name, sharedID, computedID string) error {
+	return errors.New(
+		errSchemaVersionIDDoesntMatch,
+		errors.NewKV("Name", name),
+		errors.NewKV("SharedID", sharedID),
+		errors.NewKV("ComputedID", computedID),
+	)
+}
+
 func NewErrCannotModifySchemaName(existingName, proposedName string) error {
 	return errors.New(
 		errCannotModifySchemaName,
@@ -155,6 +263,14 @@ func NewErrCannotModifySchemaName(existingName, proposedName string) error {
 	)
 }
 
+func NewErrCannotModifyAppendOnly(name string, appendOnly bool) error {
+	return errors.New(
+		errCannotModifyAppendOnly,
+		errors.NewKV("Name", name),
+		errors.NewKV("AppendOnly", appendOnly),
+	)
+}
+
 func NewErrCannotSetFieldID(name string, id client.FieldID) error {
 	return errors.New(
 		errCannotSetFieldID,
@@ -214,3 +330,166 @@ func NewErrCannotDeleteField(name string, id client.FieldID) error {
 		errors.NewKV("ID", id),
 	)
 }
+
+// NewErrRequestCancelled returns a new error indicating that the request with the given ID was cancelled.
+func NewErrRequestCancelled(id uint64) error {
+	return errors.New(errRequestCancelled, errors.NewKV("ID", id))
+}
+
+// NewErrUnknownKeyspace returns a new error indicating that the given keyspace does not exist.
+func NewErrUnknownKeyspace(keyspace string) error {
+	return errors.New(errUnknownKeyspace, errors.NewKV("Keyspace", keyspace))
+}
+
+// NewErrInvalidBatchSize returns a new error indicating that the given batch size is invalid.
+func NewErrInvalidBatchSize(size int) error {
+	return errors.New(errInvalidBatchSize, errors.NewKV("Size", size))
+}
+
+// NewErrInvalidWebhookURL returns a new error indicating that the given webhook URL is invalid.
+func NewErrInvalidWebhookURL(url string) error {
+	return errors.New(errInvalidWebhookURL, errors.NewKV("URL", url))
+}
+
+// NewErrWebhookDeliveryFailed returns a new error indicating that a webhook responded to the
+// delivery of an event with the given unsuccessful status.
+func NewErrWebhookDeliveryFailed(status int) error {
+	return errors.New(errWebhookDeliveryFailed, errors.NewKV("Status", status))
+}
+
+// NewErrSignedSchemaUpdateNotFound returns a new error indicating that the schema version with the
+// given ID was not produced by a signed schema update.
+func NewErrSignedSchemaUpdateNotFound(schemaVersionID string) error {
+	return errors.New(errSignedSchemaUpdateNotFound, errors.NewKV("SchemaVersionID", schemaVersionID))
+}
+
+// NewErrIntegerOutOfRange returns a new error indicating that the value given to the integer field
+// with the given name doesn't fit its kind.
+func NewErrIntegerOutOfRange(field string, kind client.FieldKind, value any) error {
+	return errors.New(
+		errIntegerOutOfRange,
+		errors.NewKV("Field", field),
+		errors.NewKV("Kind", kind),
+		errors.NewKV("Value", value),
+	)
+}
+
+// NewErrInvalidBlock returns a new error indicating that the block at the given index of the
+// blocks to merge can't be decoded.
+func NewErrInvalidBlock(index int, inner error) error {
+	return errors.Wrap(errInvalidBlock, inner, errors.NewKV("Index", index))
+}
+
+// NewErrInvalidCommit returns a new error indicating that the block to merge with the given CID
+// is not a valid commit of the document.
+func NewErrInvalidCommit(c cid.Cid, inner error) error {
+	return errors.Wrap(errInvalidCommit, inner, errors.NewKV("CID", c))
+}
+
+// NewErrBlockOfOtherCollection returns a new error indicating that the commit to merge into the
+// given collection was made with the schema version of another collection.
+func NewErrBlockOfOtherCollection(collection string, schemaVersionID string) error {
+	return errors.New(
+		errBlockOfOtherCollection,
+		errors.NewKV("Collection", collection),
+		errors.NewKV("SchemaVersionID", schemaVersionID),
+	)
+}
+
+// NewErrMissingBlock returns a new error indicating that a block to merge links to the block with
+// the given CID, which is neither one of the blocks to merge nor already stored.
+func NewErrMissingBlock(c cid.Cid) error {
+	return errors.New(errMissingBlock, errors.NewKV("CID", c))
+}
+
+// NewErrReadTxnNotAcquired returns a new error indicating that the context was done while the
+// read transaction was queued, the maximum number of concurrent read transactions being open.
+func NewErrReadTxnNotAcquired(inner error) error {
+	return errors.Wrap(errReadTxnNotAcquired, inner)
+}
+
+// NewErrDocumentNotDeleted returns a new error indicating that the document with the given key
+// can't be purged, as it has not been deleted.
+func NewErrDocumentNotDeleted(docKey string) error {
+	return errors.New(errDocumentNotDeleted, errors.NewKV("DocKey", docKey))
+}
+
+// NewErrInvalidRetentionMaxAge returns a new error indicating that the retention policy of the
+// given collection does not have a positive max age.
+func NewErrInvalidRetentionMaxAge(collection string) error {
+	return errors.New(errInvalidRetentionMaxAge, errors.NewKV("Collection", collection))
+}
+
+// NewErrAppendOnlyCollection returns a new error indicating that a document of the given
+// append-only collection can't be updated or deleted, either locally or by a merge.
+func NewErrAppendOnlyCollection(collection string) error {
+	return errors.New(errAppendOnlyCollection, errors.NewKV("Collection", collection))
+}
+
+// NewErrMissingFieldKey returns a new error indicating that a value can't be written to the given
+// encrypted field, as the caller did not present its key.
+func NewErrMissingFieldKey(collection string, field string) error {
+	return errors.New(errMissingFieldKey, errors.NewKV("Collection", collection), errors.NewKV("Field", field))
+}
+
+// NewErrRequestTooCostly returns a new error indicating that the request has the given cost, which
+// exceeds the given maximum cost of the requests.
+func NewErrRequestTooCostly(cost int, maxCost int) error {
+	return errors.New(errRequestTooCostly, errors.NewKV("Cost", cost), errors.NewKV("MaxCost", maxCost))
+}
+
+// NewErrInvalidCostMultiplier returns a new error indicating that the given cost multiplier of the
+// given schema is negative.
+func NewErrInvalidCostMultiplier(name string, multiplier int) error {
+	return errors.New(
+		errInvalidCostMultiplier,
+		errors.NewKV("Schema", name),
+		errors.NewKV("CostMultiplier", multiplier),
+	)
+}
+
+// NewErrInvalidDataset returns a new error indicating that the root block of the dataset with the
+// given CID can't be decoded, or is not a dataset block.
+func NewErrInvalidDataset(root cid.Cid, inner error) error {
+	return errors.Wrap(errInvalidDataset, inner, errors.NewKV("CID", root))
+}
+
+// NewErrDatasetOfOtherSchema returns a new error indicating that a dataset of documents of the
+// schema with the given ID can't be imported into the given collection of another schema.
+func NewErrDatasetOfOtherSchema(collection string, schemaID string) error {
+	return errors.New(errDatasetOfOtherSchema, errors.NewKV("Collection", collection), errors.NewKV("SchemaID", schemaID))
+}
+
+// NewErrCloneOfRelatedCollection returns a new error indicating that the given collection can't be
+// cloned, as the given field is a relation to another collection.
+func NewErrCloneOfRelatedCollection(collection string, field string) error {
+	return errors.New(
+		errCloneOfRelatedCollection,
+		errors.NewKV("Collection", collection),
+		errors.NewKV("Field", field),
+	)
+}
+
+// NewErrQuotaExceeded returns a new error indicating that the write of the document with the given
+// key exceeds the storage quota of the given collection.
+func NewErrQuotaExceeded(collection string, docKey string, documents uint64, bytes uint64) error {
+	return errors.New(
+		errQuotaExceeded,
+		errors.NewKV("Collection", collection),
+		errors.NewKV("DocKey", docKey),
+		errors.NewKV("Documents", documents),
+		errors.NewKV("Bytes", bytes),
+	)
+}
+
+// NewErrInvalidQuota returns a new error indicating that the quota of the given collection limits
+// neither the number of its documents nor its size.
+func NewErrInvalidQuota(collection string) error {
+	return errors.New(errInvalidQuota, errors.NewKV("Collection", collection))
+}
+
+// NewErrInvalidQuotaUsage returns a new error indicating that the value of the given key of the
+// recorded usage of a document can't be decoded.
+func NewErrInvalidQuotaUsage(key string) error {
+	return errors.New(errInvalidQuotaUsage, errors.NewKV("Key", key))
+}
@@ -0,0 +1,323 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package fetcher
+
+import (
+	"context"
+	"time"
+
+	"github.com/ipfs/go-cid"
+	ds "github.com/ipfs/go-datastore"
+	dsq "github.com/ipfs/go-datastore/query"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/core"
+	"github.com/sourcenetwork/defradb/datastore"
+	"github.com/sourcenetwork/defradb/db/base"
+)
+
+var (
+	_ Fetcher = (*AtTimeFetcher)(nil)
+)
+
+// AtTimeFetcher fetches the documents of a collection as they were at a given time.
+//
+// Each document is resolved to the latest of its composite commits that was committed to the
+// local store at or before the given time, using the commit times recorded under the
+// [core.CommitTimeKey]s, and is then fetched at that commit by a [VersionedFetcher].
+// Documents with no commit before the given time didn't exist yet, and are not returned.
+type AtTimeFetcher struct {
+	time time.Time
+
+	col         *client.CollectionDescription
+	fields      []*client.FieldDescription
+	reverse     bool
+	showDeleted bool
+
+	txn datastore.Txn
+
+	// The versions of the documents to fetch, in the order they are to be fetched.
+	versions []documentVersion
+	// The index of the next version to fetch.
+	versionIndex int
+
+	// The fetcher of the current version, if any.
+	current *VersionedFetcher
+	// The status of the document at the current version.
+	currentStatus client.DocumentStatus
+}
+
+// documentVersion is the version of a document at a given time.
+type documentVersion struct {
+	key    core.DataStoreKey
+	cid    cid.Cid
+	status client.DocumentStatus
+}
+
+// NewAtTimeFetcher returns a new AtTimeFetcher fetching the documents as they were at the
+// given time.
+func NewAtTimeFetcher(t time.Time) *AtTimeFetcher {
+	return &AtTimeFetcher{
+		time: t,
+	}
+}
+
+// Init initializes the AtTimeFetcher.
+func (f *AtTimeFetcher) Init(
+	col *client.CollectionDescription,
+	fields []*client.FieldDescription,
+	reverse bool,
+	showDeleted bool,
+) error {
+	f.col = col
+	f.fields = fields
+	f.reverse = reverse
+	f.showDeleted = showDeleted
+	return nil
+}
+
+// Start resolves the versions of the documents within the given spans at the time of
+// the fetcher.
+func (f *AtTimeFetcher) Start(ctx context.Context, txn datastore.Txn, spans core.Spans) error {
+	if f.col == nil {
+		return client.NewErrUninitializeProperty("AtTimeFetcher", "CollectionDescription")
+	}
+	if err := f.closeCurrent(); err != nil {
+		return err
+	}
+
+	docKeys, err := f.getDocKeys(ctx, txn, spans)
+	if err != nil {
+		return err
+	}
+
+	f.txn = txn
+	f.versionIndex = 0
+	f.versions = make([]documentVersion, 0, len(docKeys))
+	for _, docKey := range docKeys {
+		version, exists, err := f.getVersion(ctx, txn, docKey)
+		if err != nil {
+			return err
+		}
+		if !exists || (version.status.IsDeleted() && !f.showDeleted) {
+			continue
+		}
+		f.versions = append(f.versions, version)
+	}
+
+	if f.reverse {
+		for i, j := 0, len(f.versions)-1; i < j; i, j = i+1, j-1 {
+			f.versions[i], f.versions[j] = f.versions[j], f.versions[i]
+		}
+	}
+	return nil
+}
+
+// getDocKeys returns the keys of the documents of the collection within the given spans,
+// in ascending order.
+func (f *AtTimeFetcher) getDocKeys(ctx context.Context, txn datastore.Txn, spans core.Spans) ([]string, error) {
+	// Spans restricting the fetch to given documents don't require the documents of the
+	// collection to be scanned.
+	if spans.HasValue && len(spans.Value) > 0 {
+		docKeys := make([]string, 0, len(spans.Value))
+		for _, span := range core.MergeAscending(spans.Value) {
+			if span.Start().DocKey == "" || span.End() != span.Start().PrefixEnd() {
+				docKeys = nil
+				break
+			}
+			docKeys = append(docKeys, span.Start().DocKey)
+		}
+		if docKeys != nil {
+			return docKeys, nil
+		}
+	}
+
+	prefix := core.PrimaryDataStoreKey{
+		CollectionId: f.col.IDString(),
+	}
+	q, err := txn.Datastore().Query(ctx, dsq.Query{
+		Prefix:   prefix.ToString(),
+		KeysOnly: true,
+		Orders:   []dsq.Order{dsq.OrderByKey{}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	entries, err := q.Rest()
+	if closeErr := q.Close(); closeErr != nil {
+		return nil, closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	docKeys := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		docKey := ds.NewKey(entry.Key).BaseNamespace()
+		if spans.HasValue && !spansContain(spans, base.MakeDocKey(*f.col, docKey)) {
+			continue
+		}
+		docKeys = append(docKeys, docKey)
+	}
+	return docKeys, nil
+}
+
+// spansContain returns true if the given key is within any of the given spans.
+func spansContain(spans core.Spans, key core.DataStoreKey) bool {
+	keyString := key.ToString()
+	for _, span := range spans.Value {
+		if keyString >= span.Start().ToString() && keyString < span.End().ToString() {
+			return true
+		}
+	}
+	return false
+}
+
+// getVersion returns the version of the document with the given key at the time of the
+// fetcher, and false if the document has no commit before that time.
+func (f *AtTimeFetcher) getVersion(
+	ctx context.Context,
+	txn datastore.Txn,
+	docKey string,
+) (documentVersion, bool, error) {
+	prefix := core.CommitTimeKey{DocKey: docKey}
+	q, err := txn.Systemstore().Query(ctx, dsq.Query{
+		Prefix: prefix.ToString() + "/",
+		Orders: []dsq.Order{dsq.OrderByKey{}},
+	})
+	if err != nil {
+		return documentVersion{}, false, err
+	}
+	entries, err := q.Rest()
+	if closeErr := q.Close(); closeErr != nil {
+		return documentVersion{}, false, closeErr
+	}
+	if err != nil {
+		return documentVersion{}, false, err
+	}
+
+	var version documentVersion
+	var exists bool
+	for _, entry := range entries {
+		key, err := core.NewCommitTimeKeyFromString(entry.Key)
+		if err != nil {
+			return documentVersion{}, false, err
+		}
+		// The keys are ordered by time, so the following commits are all after the time.
+		if key.Time().After(f.time) {
+			break
+		}
+		status := client.Active
+		if len(entry.Value) > 0 {
+			status = client.DocumentStatus(entry.Value[0])
+		}
+		version = documentVersion{
+			key:    base.MakeDocKey(*f.col, docKey),
+			cid:    key.Cid,
+			status: status,
+		}
+		exists = true
+	}
+	return version, exists, nil
+}
+
+// FetchNext returns the next document as it was at the time of the fetcher.
+func (f *AtTimeFetcher) FetchNext(ctx context.Context) (*encodedDocument, error) {
+	for {
+		if f.current == nil {
+			hasNext, err := f.startNextVersion(ctx)
+			if err != nil || !hasNext {
+				return nil, err
+			}
+		}
+
+		encdoc, err := f.current.FetchNext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if encdoc != nil {
+			return encdoc, nil
+		}
+
+		if err := f.closeCurrent(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// FetchNextDecoded returns the next document as it was at the time of the fetcher.
+func (f *AtTimeFetcher) FetchNextDecoded(ctx context.Context) (*client.Document, error) {
+	encdoc, err := f.FetchNext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if encdoc == nil {
+		return nil, nil
+	}
+	return encdoc.Decode()
+}
+
+// FetchNextDoc returns the next document as it was at the time of the fetcher, as a core.Doc.
+func (f *AtTimeFetcher) FetchNextDoc(
+	ctx context.Context,
+	mapping *core.DocumentMapping,
+) ([]byte, core.Doc, error) {
+	encdoc, err := f.FetchNext(ctx)
+	if err != nil {
+		return nil, core.Doc{}, err
+	}
+	if encdoc == nil {
+		return nil, core.Doc{}, nil
+	}
+
+	doc, err := encdoc.DecodeToDoc(mapping)
+	if err != nil {
+		return nil, core.Doc{}, err
+	}
+	doc.Status = f.currentStatus
+	return encdoc.Key, doc, nil
+}
+
+// startNextVersion starts the fetcher of the next version to fetch, returning false if
+// there are none left.
+func (f *AtTimeFetcher) startNextVersion(ctx context.Context) (bool, error) {
+	if f.versionIndex >= len(f.versions) {
+		return false, nil
+	}
+	version := f.versions[f.versionIndex]
+	f.versionIndex++
+
+	vf := new(VersionedFetcher)
+	if err := vf.Init(f.col, f.fields, false, false); err != nil {
+		return false, err
+	}
+	if err := vf.Start(ctx, f.txn, NewVersionedSpan(version.key, version.cid)); err != nil {
+		return false, err
+	}
+	f.current = vf
+	f.currentStatus = version.status
+	return true, nil
+}
+
+// closeCurrent closes the fetcher of the current version, if any.
+func (f *AtTimeFetcher) closeCurrent() error {
+	if f.current == nil {
+		return nil
+	}
+	err := f.current.Close()
+	f.current = nil
+	return err
+}
+
+// Close closes the AtTimeFetcher.
+func (f *AtTimeFetcher) Close() error {
+	return f.closeCurrent()
+}
@@ -0,0 +1,154 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package fetcher
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/ipfs/go-cid"
+)
+
+// DocumentCache is a cache of the stored field values of the most recently fetched documents,
+// holding up to a given number of documents per collection.
+//
+// The values of a document are cached along with the CIDs of the heads of the document they
+// were read at, and are only returned for those heads. Since the values of a document at
+// a given set of heads never change, the cache never returns stale values, and entries only
+// need to be removed to free the space of the documents that have been written to.
+type DocumentCache struct {
+	size int
+
+	mu sync.Mutex
+	// The cached documents of each collection, by schema ID.
+	collections map[string]*lru.Cache[string, *cachedDocument]
+}
+
+// cachedDocument is the cached values of a document at a given set of heads.
+//
+// A cached document is never modified once added to the cache.
+type cachedDocument struct {
+	heads string
+	// The raw values of the fields of the document, by field ID. A nil value means that
+	// the field has no value.
+	values map[uint32][]byte
+}
+
+// NewDocumentCache returns a new DocumentCache holding up to the given number of documents
+// per collection.
+func NewDocumentCache(size int) (*DocumentCache, error) {
+	if size <= 0 {
+		return nil, NewErrInvalidDocumentCacheSize(size)
+	}
+	return &DocumentCache{
+		size:        size,
+		collections: map[string]*lru.Cache[string, *cachedDocument]{},
+	}, nil
+}
+
+// Get returns the cached values of the given fields of the document with the given key at
+// the given heads, if all of them are cached.
+func (c *DocumentCache) Get(
+	schemaID string,
+	docKey string,
+	heads []cid.Cid,
+	fieldIDs []uint32,
+) (map[uint32][]byte, bool) {
+	docs := c.getCollection(schemaID, false)
+	if docs == nil {
+		return nil, false
+	}
+	doc, ok := docs.Get(docKey)
+	if !ok || doc.heads != headsKey(heads) {
+		return nil, false
+	}
+	for _, fieldID := range fieldIDs {
+		if _, ok := doc.values[fieldID]; !ok {
+			return nil, false
+		}
+	}
+	return doc.values, true
+}
+
+// Add caches the given values of the fields of the document with the given key at the
+// given heads.
+//
+// The values are added to the ones already cached for the same heads, and replace the ones
+// cached for any other heads.
+func (c *DocumentCache) Add(schemaID string, docKey string, heads []cid.Cid, values map[uint32][]byte) {
+	docs := c.getCollection(schemaID, true)
+	if docs == nil {
+		return
+	}
+
+	doc := &cachedDocument{
+		heads:  headsKey(heads),
+		values: make(map[uint32][]byte, len(values)),
+	}
+	if existing, ok := docs.Peek(docKey); ok && existing.heads == doc.heads {
+		for fieldID, value := range existing.values {
+			doc.values[fieldID] = value
+		}
+	}
+	for fieldID, value := range values {
+		doc.values[fieldID] = value
+	}
+	docs.Add(docKey, doc)
+}
+
+// Remove removes the document with the given key from the cache.
+func (c *DocumentCache) Remove(schemaID string, docKey string) {
+	docs := c.getCollection(schemaID, false)
+	if docs == nil {
+		return
+	}
+	docs.Remove(docKey)
+}
+
+// Len returns the number of cached documents of the given collection.
+func (c *DocumentCache) Len(schemaID string) int {
+	docs := c.getCollection(schemaID, false)
+	if docs == nil {
+		return 0
+	}
+	return docs.Len()
+}
+
+// getCollection returns the cached documents of the collection with the given schema ID,
+// creating them if they don't exist and create is true.
+func (c *DocumentCache) getCollection(schemaID string, create bool) *lru.Cache[string, *cachedDocument] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	docs, ok := c.collections[schemaID]
+	if ok || !create {
+		return docs
+	}
+	// The size has been validated on creation of the cache, so this can't fail.
+	docs, err := lru.New[string, *cachedDocument](c.size)
+	if err != nil {
+		return nil
+	}
+	c.collections[schemaID] = docs
+	return docs
+}
+
+// headsKey returns a key identifying the given set of heads, regardless of their order.
+func headsKey(heads []cid.Cid) string {
+	keys := make([]string, len(heads))
+	for i, head := range heads {
+		keys[i] = head.KeyString()
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, "/")
+}
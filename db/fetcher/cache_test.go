@@ -0,0 +1,114 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package fetcher
+
+import (
+	"testing"
+
+	"github.com/ipfs/go-cid"
+	mh "github.com/multiformats/go-multihash"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCid(t *testing.T, data string) cid.Cid {
+	hash, err := mh.Sum([]byte(data), mh.SHA2_256, -1)
+	require.NoError(t, err)
+	return cid.NewCidV1(cid.DagProtobuf, hash)
+}
+
+func TestNewDocumentCacheWithInvalidSize(t *testing.T) {
+	_, err := NewDocumentCache(0)
+	require.ErrorIs(t, err, ErrInvalidDocumentCacheSize)
+}
+
+func TestDocumentCacheGet(t *testing.T) {
+	cache, err := NewDocumentCache(10)
+	require.NoError(t, err)
+	heads := []cid.Cid{newTestCid(t, "a"), newTestCid(t, "b")}
+
+	cache.Add("schema", "doc", heads, map[uint32][]byte{1: []byte("value"), 2: nil})
+
+	values, found := cache.Get("schema", "doc", []cid.Cid{heads[1], heads[0]}, []uint32{1, 2})
+	require.True(t, found)
+	require.Equal(t, map[uint32][]byte{1: []byte("value"), 2: nil}, values)
+
+	_, found = cache.Get("schema", "doc", heads, []uint32{1, 3})
+	require.False(t, found)
+	_, found = cache.Get("schema", "doc", heads[:1], []uint32{1})
+	require.False(t, found)
+	_, found = cache.Get("otherSchema", "doc", heads, []uint32{1})
+	require.False(t, found)
+}
+
+func TestDocumentCacheAddMergesValuesOfSameHeads(t *testing.T) {
+	cache, err := NewDocumentCache(10)
+	require.NoError(t, err)
+	heads := []cid.Cid{newTestCid(t, "a")}
+
+	cache.Add("schema", "doc", heads, map[uint32][]byte{1: []byte("value1")})
+	cache.Add("schema", "doc", heads, map[uint32][]byte{2: []byte("value2")})
+
+	values, found := cache.Get("schema", "doc", heads, []uint32{1, 2})
+	require.True(t, found)
+	require.Equal(t, map[uint32][]byte{1: []byte("value1"), 2: []byte("value2")}, values)
+}
+
+func TestDocumentCacheAddReplacesValuesOfOtherHeads(t *testing.T) {
+	cache, err := NewDocumentCache(10)
+	require.NoError(t, err)
+	oldHeads := []cid.Cid{newTestCid(t, "a")}
+	newHeads := []cid.Cid{newTestCid(t, "b")}
+
+	cache.Add("schema", "doc", oldHeads, map[uint32][]byte{1: []byte("value1")})
+	cache.Add("schema", "doc", newHeads, map[uint32][]byte{2: []byte("value2")})
+
+	_, found := cache.Get("schema", "doc", oldHeads, []uint32{1})
+	require.False(t, found)
+	_, found = cache.Get("schema", "doc", newHeads, []uint32{1})
+	require.False(t, found)
+	values, found := cache.Get("schema", "doc", newHeads, []uint32{2})
+	require.True(t, found)
+	require.Equal(t, map[uint32][]byte{2: []byte("value2")}, values)
+}
+
+func TestDocumentCacheEvictsLeastRecentlyUsedDocuments(t *testing.T) {
+	cache, err := NewDocumentCache(2)
+	require.NoError(t, err)
+	heads := []cid.Cid{newTestCid(t, "a")}
+	values := map[uint32][]byte{1: []byte("value")}
+
+	cache.Add("schema", "doc1", heads, values)
+	cache.Add("schema", "doc2", heads, values)
+	_, found := cache.Get("schema", "doc1", heads, []uint32{1})
+	require.True(t, found)
+	cache.Add("schema", "doc3", heads, values)
+	cache.Add("otherSchema", "doc4", heads, values)
+
+	require.Equal(t, 2, cache.Len("schema"))
+	require.Equal(t, 1, cache.Len("otherSchema"))
+	_, found = cache.Get("schema", "doc2", heads, []uint32{1})
+	require.False(t, found)
+	_, found = cache.Get("schema", "doc1", heads, []uint32{1})
+	require.True(t, found)
+}
+
+func TestDocumentCacheRemove(t *testing.T) {
+	cache, err := NewDocumentCache(10)
+	require.NoError(t, err)
+	heads := []cid.Cid{newTestCid(t, "a")}
+
+	cache.Add("schema", "doc", heads, map[uint32][]byte{1: []byte("value")})
+	cache.Remove("schema", "doc")
+
+	_, found := cache.Get("schema", "doc", heads, []uint32{1})
+	require.False(t, found)
+	require.Equal(t, 0, cache.Len("schema"))
+}
@@ -15,7 +15,6 @@ import (
 	"sort"
 	"strings"
 
-	"github.com/ipfs/go-cid"
 	dsq "github.com/ipfs/go-datastore/query"
 	"github.com/sourcenetwork/immutable"
 
@@ -78,7 +77,8 @@ func (hf *HeadFetcher) Start(
 	return nil
 }
 
-func (hf *HeadFetcher) FetchNext() (*cid.Cid, error) {
+// FetchNext returns the key of the next head, or nil if there are none left.
+func (hf *HeadFetcher) FetchNext() (*core.HeadStoreKey, error) {
 	res, available := hf.kvIter.NextSync()
 	if res.Error != nil {
 		return nil, res.Error
@@ -97,7 +97,7 @@ func (hf *HeadFetcher) FetchNext() (*cid.Cid, error) {
 		return hf.FetchNext()
 	}
 
-	return &headStoreKey.Cid, nil
+	return &headStoreKey, nil
 }
 
 func (hf *HeadFetcher) Close() error {
@@ -18,6 +18,7 @@ import (
 
 	"github.com/sourcenetwork/defradb/client"
 	"github.com/sourcenetwork/defradb/core"
+	corecrdt "github.com/sourcenetwork/defradb/core/crdt"
 )
 
 type EPTuple []encProperty
@@ -34,62 +35,88 @@ type encProperty struct {
 // Decode returns the decoded value and CRDT type for the given property.
 func (e encProperty) Decode() (client.CType, any, error) {
 	ctype := client.CType(e.Raw[0])
-	buf := e.Raw[1:]
+	val, err := DecodeFieldValue(e.Desc, e.Raw[1:])
+	return ctype, val, err
+}
+
+// DecryptFieldValue returns the given CBOR encoded value of the given field of the given
+// collection, decrypted with the key of the field held by the given keyring if it is encrypted.
+//
+// Returns false if it is encrypted and the keyring doesn't hold the key it was encrypted with,
+// the value then being unreadable.
+func DecryptFieldValue(keyring *corecrdt.Keyring, collection string, field string, buf []byte) ([]byte, bool) {
+	if !corecrdt.IsEncryptedFieldValue(buf) {
+		return buf, true
+	}
+	cipher := keyring.Cipher(corecrdt.FieldKeyName(collection, field))
+	if cipher == nil {
+		return nil, false
+	}
+	plain, err := cipher.DecryptFieldValue(buf)
+	if err != nil {
+		return nil, false
+	}
+	return plain, true
+}
+
+// DecodeFieldValue returns the given CBOR encoded value of the given field, decoded to the
+// type of the field.
+func DecodeFieldValue(desc client.FieldDescription, buf []byte) (any, error) {
 	var val any
 	err := cbor.Unmarshal(buf, &val)
 	if err != nil {
-		return ctype, nil, err
+		return nil, err
 	}
 
 	if array, isArray := val.([]any); isArray {
 		var ok bool
-		switch e.Desc.Kind {
+		switch desc.Kind {
 		case client.FieldKind_BOOL_ARRAY:
 			boolArray := make([]bool, len(array))
 			for i, untypedValue := range array {
 				boolArray[i], ok = untypedValue.(bool)
 				if !ok {
-					return ctype, nil, client.NewErrUnexpectedType[bool](e.Desc.Name, untypedValue)
+					return nil, client.NewErrUnexpectedType[bool](desc.Name, untypedValue)
 				}
 			}
 			val = boolArray
 
 		case client.FieldKind_NILLABLE_BOOL_ARRAY:
-			val, err = convertNillableArray[bool](e.Desc.Name, array)
+			val, err = convertNillableArray[bool](desc.Name, array)
 			if err != nil {
-				return ctype, nil, err
+				return nil, err
 			}
 
 		case client.FieldKind_INT_ARRAY:
 			intArray := make([]int64, len(array))
 			for i, untypedValue := range array {
-				intArray[i], err = convertToInt(fmt.Sprintf("%s[%v]", e.Desc.Name, i), untypedValue)
+				intArray[i], err = convertToInt(fmt.Sprintf("%s[%v]", desc.Name, i), untypedValue)
 				if err != nil {
-					return ctype, nil, err
+					return nil, err
 				}
 			}
 			val = intArray
 
 		case client.FieldKind_NILLABLE_INT_ARRAY:
-			val, err = convertNillableArrayWithConverter(e.Desc.Name, array, convertToInt)
+			val, err = convertNillableArrayWithConverter(desc.Name, array, convertToInt)
 			if err != nil {
-				return ctype, nil, err
+				return nil, err
 			}
 
 		case client.FieldKind_FLOAT_ARRAY:
 			floatArray := make([]float64, len(array))
 			for i, untypedValue := range array {
-				floatArray[i], ok = untypedValue.(float64)
-				if !ok {
-					return ctype, nil, client.NewErrUnexpectedType[float64](e.Desc.Name, untypedValue)
+				floatArray[i], err = convertToFloat(fmt.Sprintf("%s[%v]", desc.Name, i), untypedValue)
+				if err != nil {
+					return nil, err
 				}
 			}
 			val = floatArray
 
 		case client.FieldKind_NILLABLE_FLOAT_ARRAY:
-			val, err = convertNillableArray[float64](e.Desc.Name, array)
+			val, err = convertNillableArrayWithConverter(desc.Name, array, convertToFloat)
 			if err != nil {
-				return ctype, nil, err
+				return nil, err
 			}
 
 		case client.FieldKind_STRING_ARRAY:
@@ -97,34 +124,39 @@ func (e encProperty) Decode() (client.CType, any, error) {
 			for i, untypedValue := range array {
 				stringArray[i], ok = untypedValue.(string)
 				if !ok {
-					return ctype, nil, client.NewErrUnexpectedType[string](e.Desc.Name, untypedValue)
+					return nil, client.NewErrUnexpectedType[string](desc.Name, untypedValue)
 				}
 			}
 			val = stringArray
 
 		case client.FieldKind_NILLABLE_STRING_ARRAY:
-			val, err = convertNillableArray[string](e.Desc.Name, array)
+			val, err = convertNillableArray[string](desc.Name, array)
 			if err != nil {
-				return ctype, nil, err
+				return nil, err
 			}
 		}
 	} else { // CBOR often encodes values typed as floats as ints
-		switch e.Desc.Kind {
+		switch desc.Kind {
 		case client.FieldKind_FLOAT:
 			switch v := val.(type) {
 			case int64:
-				return ctype, float64(v), nil
+				return float64(v), nil
 			case int:
-				return ctype, float64(v), nil
+				return float64(v), nil
 			case uint64:
-				return ctype, float64(v), nil
+				return float64(v), nil
 			case uint:
-				return ctype, float64(v), nil
+				return float64(v), nil
+			}
+
+		case client.FieldKind_UINT64:
+			if val != nil {
+				return convertToUint(desc.Name, val)
 			}
 		}
 	}
 
-	return ctype, val, nil
+	return val, nil
 }
 
 func convertNillableArray[T any](propertyName string, items []any) ([]immutable.Option[T], error) {
@@ -176,6 +208,32 @@ func convertToInt(propertyName string, untypedValue any) (int64, error) {
 	}
 }
 
+// convertToUint returns the given value of an unsigned integer field as an uint64, the values
+// written before the field was validated possibly being negative.
+func convertToUint(propertyName string, untypedValue any) (uint64, error) {
+	switch value := untypedValue.(type) {
+	case uint64:
+		return value, nil
+	default:
+		return 0, client.NewErrUnexpectedType[uint64](propertyName, untypedValue)
+	}
+}
+
+// convertToFloat returns the given value of an item of a float array as a float, the whole floats
+// being encoded as ints by CBOR like those of single float fields.
+func convertToFloat(propertyName string, untypedValue any) (float64, error) {
+	switch value := untypedValue.(type) {
+	case uint64:
+		return float64(value), nil
+	case int64:
+		return float64(value), nil
+	case float64:
+		return value, nil
+	default:
+		return 0, client.NewErrUnexpectedType[float64](propertyName, untypedValue)
+	}
+}
+
 // @todo: Implement Encoded Document type
 type encodedDocument struct {
 	Key        []byte
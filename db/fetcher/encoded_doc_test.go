@@ -0,0 +1,85 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package fetcher
+
+import (
+	"math"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/client"
+)
+
+func TestDecodeFieldValueFloatRoundTrip(t *testing.T) {
+	desc := client.FieldDescription{Name: "Rate", Kind: client.FieldKind_FLOAT}
+
+	for _, value := range []float64{
+		0,
+		-2.5,
+		0.1,
+		math.MaxFloat64,
+		-math.MaxFloat64,
+		math.SmallestNonzeroFloat64,
+	} {
+		buf, err := cbor.Marshal(value)
+		require.NoError(t, err)
+
+		decoded, err := DecodeFieldValue(desc, buf)
+		require.NoError(t, err)
+		require.Equal(t, value, decoded)
+	}
+}
+
+func TestDecodeFieldValueFloatEncodedAsInt(t *testing.T) {
+	desc := client.FieldDescription{Name: "Rate", Kind: client.FieldKind_FLOAT}
+
+	// Whole floats are stored as ints by documents.
+	buf, err := cbor.Marshal(int64(-3))
+	require.NoError(t, err)
+
+	decoded, err := DecodeFieldValue(desc, buf)
+	require.NoError(t, err)
+	require.Equal(t, float64(-3), decoded)
+}
+
+func TestDecodeFieldValueFloatArrayRoundTrip(t *testing.T) {
+	desc := client.FieldDescription{Name: "Rates", Kind: client.FieldKind_FLOAT_ARRAY}
+
+	buf, err := cbor.Marshal([]any{0.1, int64(2), math.MaxFloat64})
+	require.NoError(t, err)
+
+	decoded, err := DecodeFieldValue(desc, buf)
+	require.NoError(t, err)
+	require.Equal(t, []float64{0.1, 2, math.MaxFloat64}, decoded)
+}
+
+func TestDecodeFieldValueUInt64(t *testing.T) {
+	desc := client.FieldDescription{Name: "Balance", Kind: client.FieldKind_UINT64}
+
+	buf, err := cbor.Marshal(uint64(math.MaxUint64))
+	require.NoError(t, err)
+
+	decoded, err := DecodeFieldValue(desc, buf)
+	require.NoError(t, err)
+	require.Equal(t, uint64(math.MaxUint64), decoded)
+}
+
+func TestDecodeFieldValueUInt64WithNegativeValue(t *testing.T) {
+	desc := client.FieldDescription{Name: "Balance", Kind: client.FieldKind_UINT64}
+
+	buf, err := cbor.Marshal(int64(-1))
+	require.NoError(t, err)
+
+	_, err = DecodeFieldValue(desc, buf)
+	require.Error(t, err)
+}
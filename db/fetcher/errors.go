@@ -25,6 +25,7 @@ const (
 	errVFetcherFailedToDecodeNode   string = "(version fetcher) failed to decode protobuf"
 	errVFetcherFailedToGetDagLink   string = "(version fetcher) failed to get node link from DAG"
 	errFailedToGetDagNode           string = "failed to get DAG Node"
+	errInvalidDocumentCacheSize     string = "the size of the document cache must be greater than zero"
 )
 
 var (
@@ -38,6 +39,7 @@ var (
 	ErrVFetcherFailedToDecodeNode   = errors.New(errVFetcherFailedToDecodeNode)
 	ErrVFetcherFailedToGetDagLink   = errors.New(errVFetcherFailedToGetDagLink)
 	ErrFailedToGetDagNode           = errors.New(errFailedToGetDagNode)
+	ErrInvalidDocumentCacheSize     = errors.New(errInvalidDocumentCacheSize)
 	ErrSingleSpanOnly               = errors.New("spans must contain only a single entry")
 )
 
@@ -91,3 +93,8 @@ func NewErrVFetcherFailedToGetDagLink(inner error) error {
 func NewErrFailedToGetDagNode(inner error) error {
 	return errors.Wrap(errFailedToGetDagNode, inner)
 }
+
+// NewErrInvalidDocumentCacheSize returns an error indicating that the given document cache size is invalid.
+func NewErrInvalidDocumentCacheSize(size int) error {
+	return errors.New(errInvalidDocumentCacheSize, errors.NewKV("Size", size))
+}
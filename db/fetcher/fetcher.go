@@ -13,14 +13,20 @@ package fetcher
 import (
 	"bytes"
 	"context"
+	"strconv"
 
+	"github.com/ipfs/go-cid"
+	ds "github.com/ipfs/go-datastore"
 	dsq "github.com/ipfs/go-datastore/query"
 
 	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/client/request"
 	"github.com/sourcenetwork/defradb/core"
+	corecrdt "github.com/sourcenetwork/defradb/core/crdt"
 	"github.com/sourcenetwork/defradb/datastore"
 	"github.com/sourcenetwork/defradb/datastore/iterable"
 	"github.com/sourcenetwork/defradb/db/base"
+	"github.com/sourcenetwork/defradb/merkle/clock"
 )
 
 // Fetcher is the interface for collecting documents from the underlying data store.
@@ -51,6 +57,16 @@ type DocumentFetcher struct {
 	schemaFields map[uint32]client.FieldDescription
 	fields       []*client.FieldDescription
 
+	// The IDs of the fields to fetch, if only some of the stored fields of the documents are to
+	// be fetched, or if the documents are cached. Nil otherwise.
+	selectedFieldIDs []uint32
+	// fetchColumns is true if the documents are fetched by scanning their existence markers and
+	// getting the keys of their selected fields, instead of scanning the keys of all their fields.
+	fetchColumns bool
+	// keysOnly is true if only the keys of the documents are fetched, scanning the keys of their
+	// fields without copying their values.
+	keysOnly bool
+
 	doc         *encodedDocument
 	decodedDoc  *client.Document
 	initialized bool
@@ -61,12 +77,40 @@ type DocumentFetcher struct {
 	kvEnd             bool
 	isReadingDocument bool
 
+	// columnIter reads the values of the selected fields when fetching columns. They are read by
+	// iterating over their keys, so that they are read the same way as when scanning documents.
+	columnIter iterable.Iterator
+
+	// cache holds the values of the recently fetched documents, if set. The values of the
+	// active documents are read from it when fetching columns.
+	cache *DocumentCache
+
+	// fieldKeyring holds the keys of the encrypted fields readable by the caller. The values of the
+	// other encrypted fields are left unset.
+	fieldKeyring *corecrdt.Keyring
+
 	// Since deleted documents are stored under a different instance type than active documents,
 	// we use a parallel fetcher to be able to return the documents in the expected order.
 	// That being lexicographically ordered dockeys.
 	deletedDocFetcher *DocumentFetcher
 }
 
+// SetCache sets the cache of the documents read by the fetcher. It must be set before Init.
+//
+// When set, the active documents are always fetched by column, so that the values of their
+// selected fields can be read from the cache.
+func (df *DocumentFetcher) SetCache(cache *DocumentCache) {
+	df.cache = cache
+}
+
+// SetKeysOnly sets whether only the keys of the documents are fetched, such as when they are
+// only counted. It must be set before Init.
+//
+// When set, the fetched documents have no properties, whatever the fields given to Init.
+func (df *DocumentFetcher) SetKeysOnly(keysOnly bool) {
+	df.keysOnly = keysOnly
+}
+
 // Init implements DocumentFetcher.
 func (df *DocumentFetcher) Init(
 	col *client.CollectionDescription,
@@ -87,6 +131,7 @@ func (df *DocumentFetcher) Init(
 		if df.deletedDocFetcher == nil {
 			df.deletedDocFetcher = new(DocumentFetcher)
 		}
+		df.deletedDocFetcher.keysOnly = df.keysOnly
 		return df.deletedDocFetcher.init(col, fields, reverse)
 	}
 
@@ -117,14 +162,53 @@ func (df *DocumentFetcher) init(
 		}
 	}
 	df.kvIter = nil
+	if df.columnIter != nil {
+		if err := df.columnIter.Close(); err != nil {
+			return err
+		}
+	}
+	df.columnIter = nil
 
 	df.schemaFields = make(map[uint32]client.FieldDescription)
 	for _, field := range col.Schema.Fields {
 		df.schemaFields[uint32(field.ID)] = field
 	}
+	df.selectedFieldIDs = selectedFieldIDs(col, fields)
+	if df.selectedFieldIDs == nil && df.cache != nil {
+		df.selectedFieldIDs = storedFieldIDs(col)
+	}
 	return nil
 }
 
+// selectedFieldIDs returns the IDs of the given fields if they are only some of the stored fields
+// of the given collection, and nil otherwise.
+func selectedFieldIDs(col *client.CollectionDescription, fields []*client.FieldDescription) []uint32 {
+	if fields == nil {
+		return nil
+	}
+
+	if len(fields) >= len(storedFieldIDs(col)) {
+		return nil
+	}
+
+	ids := make([]uint32, len(fields))
+	for i, field := range fields {
+		ids[i] = uint32(field.ID)
+	}
+	return ids
+}
+
+// storedFieldIDs returns the IDs of all the stored fields of the given collection.
+func storedFieldIDs(col *client.CollectionDescription) []uint32 {
+	ids := []uint32{}
+	for _, field := range col.Schema.Fields {
+		if field.Name != request.KeyFieldName && !field.IsObject() {
+			ids = append(ids, uint32(field.ID))
+		}
+	}
+	return ids
+}
+
 func (df *DocumentFetcher) Start(ctx context.Context, txn datastore.Txn, spans core.Spans) error {
 	err := df.start(ctx, txn, spans, false)
 	if err != nil {
@@ -147,23 +231,22 @@ func (df *DocumentFetcher) start(ctx context.Context, txn datastore.Txn, spans c
 		return client.NewErrUninitializeProperty("DocumentFetcher", "Document")
 	}
 
+	// Only the active documents are fetched by column, as deleted documents keep their values
+	// under their own keys. The keys of the fields are scanned when fetching the keys only, as
+	// the existence markers of the deleted documents are told apart by their values.
+	df.fetchColumns = df.selectedFieldIDs != nil && !withDeleted && !df.keysOnly
+
 	if !spans.HasValue { // no specified spans so create a prefix scan key for the entire collection
-		start := base.MakeCollectionKey(*df.col)
-		if withDeleted {
-			start = start.WithDeletedFlag()
-		} else {
-			start = start.WithValueFlag()
-		}
+		start := df.withInstanceFlag(base.MakeCollectionKey(*df.col), withDeleted)
 		df.spans = core.NewSpans(core.NewSpan(start, start.PrefixEnd()))
 	} else {
 		valueSpans := make([]core.Span, len(spans.Value))
 		for i, span := range spans.Value {
 			// We can only handle value keys, so here we ensure we only read value keys
-			if withDeleted {
-				valueSpans[i] = core.NewSpan(span.Start().WithDeletedFlag(), span.End().WithDeletedFlag())
-			} else {
-				valueSpans[i] = core.NewSpan(span.Start().WithValueFlag(), span.End().WithValueFlag())
-			}
+			valueSpans[i] = core.NewSpan(
+				df.withInstanceFlag(span.Start(), withDeleted),
+				df.withInstanceFlag(span.End(), withDeleted),
+			)
 		}
 
 		spans := core.MergeAscending(valueSpans)
@@ -177,6 +260,7 @@ func (df *DocumentFetcher) start(ctx context.Context, txn datastore.Txn, spans c
 
 	df.curSpanIndex = -1
 	df.txn = txn
+	df.fieldKeyring = corecrdt.FieldKeyringFromContext(ctx)
 
 	if df.reverse {
 		df.order = []dsq.Order{dsq.OrderByKeyDescending{}}
@@ -188,6 +272,27 @@ func (df *DocumentFetcher) start(ctx context.Context, txn datastore.Txn, spans c
 	return err
 }
 
+// withInstanceFlag returns a copy of the given key with the instance type of the keys scanned
+// by the fetcher.
+func (df *DocumentFetcher) withInstanceFlag(key core.DataStoreKey, withDeleted bool) core.DataStoreKey {
+	switch {
+	case withDeleted:
+		return key.WithDeletedFlag()
+	case df.fetchColumns:
+		return key.WithPrimaryFlag()
+	default:
+		return key.WithValueFlag()
+	}
+}
+
+// scansInstance returns true if the fetcher scans the keys of the given instance type.
+func (df *DocumentFetcher) scansInstance(instanceType core.InstanceType) bool {
+	if df.fetchColumns {
+		return instanceType == core.PrimaryKey
+	}
+	return instanceType == core.ValueKey || instanceType == core.DeletedKey
+}
+
 func (df *DocumentFetcher) startNextSpan(ctx context.Context) (bool, error) {
 	nextSpanIndex := df.curSpanIndex + 1
 	if nextSpanIndex >= len(df.spans.Value) {
@@ -197,7 +302,9 @@ func (df *DocumentFetcher) startNextSpan(ctx context.Context) (bool, error) {
 	var err error
 	if df.kvIter == nil {
 		df.kvIter, err = df.txn.Datastore().GetIterator(dsq.Query{
-			Orders: df.order,
+			Prefix:   base.MakeCollectionKey(*df.col).ToString(),
+			Orders:   df.order,
+			KeysOnly: df.keysOnly,
 		})
 	}
 	if err != nil {
@@ -251,7 +358,7 @@ func (df *DocumentFetcher) nextKey(ctx context.Context) (spanDone bool, err erro
 	if err != nil {
 		return false, err
 	}
-	if df.kv != nil && (df.kv.Key.InstanceType != core.ValueKey && df.kv.Key.InstanceType != core.DeletedKey) {
+	if df.kv != nil && !df.scansInstance(df.kv.Key.InstanceType) {
 		// We can only ready value values, if we escape the collection's value keys
 		// then we must be done and can stop reading
 		spanDone = true
@@ -279,25 +386,33 @@ func (df *DocumentFetcher) nextKey(ctx context.Context) (spanDone bool, err erro
 // - Returns true if the entire iterator/span is exhausted
 // - Returns a kv pair instead of internally updating
 func (df *DocumentFetcher) nextKV() (iterDone bool, kv *core.KeyValue, err error) {
-	res, available := df.kvResultsIter.NextSync()
-	if !available {
-		return true, nil, nil
-	}
-	err = res.Error
-	if err != nil {
-		return true, nil, err
-	}
+	for {
+		res, available := df.kvResultsIter.NextSync()
+		if !available {
+			return true, nil, nil
+		}
+		err = res.Error
+		if err != nil {
+			return true, nil, err
+		}
 
-	dsKey, err := core.NewDataStoreKey(res.Key)
-	if err != nil {
-		return true, nil, err
-	}
+		dsKey, err := core.NewDataStoreKey(res.Key)
+		if err != nil {
+			return true, nil, err
+		}
 
-	kv = &core.KeyValue{
-		Key:   dsKey,
-		Value: res.Value,
+		// The existence markers of the deleted documents are skipped, as those are fetched by
+		// the deleted documents fetcher.
+		if dsKey.InstanceType == core.PrimaryKey && bytes.Equal(res.Value, []byte{base.DeletedObjectMarker}) {
+			continue
+		}
+
+		kv = &core.KeyValue{
+			Key:   dsKey,
+			Value: res.Value,
+		}
+		return false, kv, nil
 	}
-	return false, kv, nil
 }
 
 // processKV continuously processes the key value pairs we've received
@@ -318,6 +433,9 @@ func (df *DocumentFetcher) processKV(kv *core.KeyValue) error {
 		df.doc.Reset()
 		df.doc.Key = []byte(kv.Key.DocKey)
 	}
+	if df.keysOnly {
+		return nil
+	}
 
 	// we have to skip the object marker
 	if bytes.Equal(df.kv.Value, []byte{base.ObjectMarker}) {
@@ -339,14 +457,132 @@ func (df *DocumentFetcher) processKV(kv *core.KeyValue) error {
 	// to better handle dynamic use cases beyond primary indexes. If a
 	// secondary index is provided, we need to extract the indexed/implicit fields
 	// from the KV pair.
+	df.setProperty(fieldDesc, kv.Value)
+	// @todo: Extract Index implicit/stored keys
+	return nil
+}
+
+// setProperty sets the property of the given field of the current encoded document to the given
+// raw value, decrypted if it is encrypted.
+//
+// The property is left unset if its value can't be decrypted with the field keys of the fetcher.
+func (df *DocumentFetcher) setProperty(fieldDesc client.FieldDescription, raw []byte) {
+	if len(raw) > 1 && corecrdt.IsEncryptedFieldValue(raw[1:]) {
+		value, ok := DecryptFieldValue(df.fieldKeyring, df.col.Name, fieldDesc.Name, raw[1:])
+		if !ok {
+			return
+		}
+		raw = append([]byte{raw[0]}, value...)
+	}
 	df.doc.Properties[fieldDesc] = &encProperty{
 		Desc: fieldDesc,
-		Raw:  kv.Value,
+		Raw:  raw,
+	}
+}
+
+// processColumns constructs the current encoded document from the given existence marker of the
+// document, getting the values of the selected fields from their keys.
+func (df *DocumentFetcher) processColumns(ctx context.Context, kv *core.KeyValue) error {
+	if df.doc == nil {
+		return client.NewErrUninitializeProperty("DocumentFetcher", "Document")
+	}
+
+	df.isReadingDocument = true
+	df.doc.Reset()
+	df.doc.Key = []byte(kv.Key.DocKey)
+
+	if df.columnIter == nil {
+		var err error
+		df.columnIter, err = df.txn.Datastore().GetIterator(dsq.Query{
+			Prefix: base.MakeCollectionKey(*df.col).ToString(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	values, err := df.getColumns(ctx, kv.Key)
+	if err != nil {
+		return err
+	}
+
+	for _, fieldID := range df.selectedFieldIDs {
+		value := values[fieldID]
+		if value == nil {
+			continue
+		}
+
+		fieldDesc, exists := df.schemaFields[fieldID]
+		if !exists {
+			return NewErrFieldIdNotFound(fieldID)
+		}
+		df.setProperty(fieldDesc, value)
 	}
-	// @todo: Extract Index implicit/stored keys
 	return nil
 }
 
+// getColumns returns the values of the selected fields of the document with the given key,
+// by field ID. Fields without a value have a nil value.
+//
+// The values are read from the cache if it holds them for the current heads of the document,
+// and are added to it otherwise.
+func (df *DocumentFetcher) getColumns(ctx context.Context, key core.DataStoreKey) (map[uint32][]byte, error) {
+	var heads []cid.Cid
+	if df.cache != nil {
+		var err error
+		heads, _, err = clock.NewHeadSet(
+			df.txn.Headstore(),
+			key.WithFieldId(core.COMPOSITE_NAMESPACE).ToHeadStoreKey(),
+		).List(ctx)
+		if err != nil {
+			return nil, err
+		}
+		values, found := df.cache.Get(df.col.Schema.SchemaID, key.DocKey, heads, df.selectedFieldIDs)
+		if found {
+			return values, nil
+		}
+	}
+
+	valueKey := key.WithValueFlag()
+	values := make(map[uint32][]byte, len(df.selectedFieldIDs))
+	for _, fieldID := range df.selectedFieldIDs {
+		fieldKey := valueKey.WithFieldId(strconv.FormatUint(uint64(fieldID), 10)).ToDS()
+		value, found, err := df.getColumn(ctx, fieldKey)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			value = nil
+		}
+		values[fieldID] = value
+	}
+
+	// Documents without heads are not yet fully written, so their values aren't cached.
+	if df.cache != nil && len(heads) > 0 {
+		df.cache.Add(df.col.Schema.SchemaID, key.DocKey, heads, values)
+	}
+	return values, nil
+}
+
+// getColumn returns the value of the given field key, if any.
+func (df *DocumentFetcher) getColumn(ctx context.Context, key ds.Key) ([]byte, bool, error) {
+	results, err := df.columnIter.IteratePrefix(ctx, key, key)
+	if err != nil {
+		return nil, false, err
+	}
+	res, found := results.NextSync()
+	if err := results.Close(); err != nil {
+		return nil, false, err
+	}
+	if !found {
+		return nil, false, nil
+	}
+	if res.Error != nil {
+		return nil, false, res.Error
+	}
+	return res.Value, true, nil
+}
+
 // FetchNext returns a raw binary encoded document. It iterates over all the relevant
 // keypairs from the underlying store and constructs the document.
 func (df *DocumentFetcher) FetchNext(ctx context.Context) (*encodedDocument, error) {
@@ -365,7 +601,12 @@ func (df *DocumentFetcher) FetchNext(ctx context.Context) (*encodedDocument, err
 	// we'll know when were done when either
 	// A) Reach the end of the iterator
 	for {
-		err := df.processKV(df.kv)
+		var err error
+		if df.fetchColumns {
+			err = df.processColumns(ctx, df.kv)
+		} else {
+			err = df.processKV(df.kv)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -466,6 +707,13 @@ func (df *DocumentFetcher) FetchNextDoc(
 
 // Close closes the DocumentFetcher.
 func (df *DocumentFetcher) Close() error {
+	if df.columnIter != nil {
+		if err := df.columnIter.Close(); err != nil {
+			return err
+		}
+		df.columnIter = nil
+	}
+
 	if df.kvIter == nil {
 		return nil
 	}
@@ -21,6 +21,7 @@ import (
 
 	"github.com/sourcenetwork/defradb/client"
 	"github.com/sourcenetwork/defradb/core"
+	corecrdt "github.com/sourcenetwork/defradb/core/crdt"
 	"github.com/sourcenetwork/defradb/datastore"
 	"github.com/sourcenetwork/defradb/datastore/memory"
 	"github.com/sourcenetwork/defradb/db/base"
@@ -107,7 +108,8 @@ func (vf *VersionedFetcher) Init(
 	vf.queuedCids = list.New()
 	vf.mCRDTs = make(map[uint32]crdt.MerkleCRDT)
 
-	// run the DF init, VersionedFetchers only supports the Primary (0) index
+	// run the DF init, VersionedFetchers only supports the Primary (0) index.
+	// Only the blocks of the selected fields are replayed, so only their values are fetched.
 	vf.DocumentFetcher = new(DocumentFetcher)
 	return vf.DocumentFetcher.Init(col, fields, reverse, showDeleted)
 }
@@ -141,7 +143,9 @@ func (vf *VersionedFetcher) Start(ctx context.Context, txn datastore.Txn, spans
 	}
 
 	vf.txn = txn
-	vf.ctx = ctx
+	// The deltas of encrypted collections are decrypted with the key of the collection when
+	// replayed.
+	vf.ctx = corecrdt.WithDeltaCipher(ctx, corecrdt.KeyringFromContext(ctx).Cipher(vf.col.Name))
 	vf.key = dk
 	vf.version = c
 
@@ -302,7 +306,7 @@ func (vf *VersionedFetcher) seekNext(c cid.Cid, topParent bool) error {
 
 	// loop over links and ignore head links
 	for _, l := range nd.Links() {
-		if l.Name == core.HEAD {
+		if l.Name == core.HEAD || !vf.isSelectedField(l.Name) {
 			continue
 		}
 
@@ -339,7 +343,7 @@ func (vf *VersionedFetcher) merge(c cid.Cid) error {
 	// handle subgraphs
 	// loop over links and ignore head links
 	for _, l := range nd.Links() {
-		if l.Name == core.HEAD {
+		if l.Name == core.HEAD || !vf.isSelectedField(l.Name) {
 			continue
 		}
 
@@ -390,7 +394,13 @@ func (vf *VersionedFetcher) processNode(
 		// compositeClock = compMCRDT
 	}
 
-	delta, err := mcrdt.DeltaDecode(nd)
+	var delta core.Delta
+	if ctype == client.COMPOSITE {
+		// The data of composite deltas holds the whole document, and is not needed to merge them.
+		delta, err = corecrdt.CompositeDAG{}.DeltaDecodeWithoutData(nd)
+	} else {
+		delta, err = mcrdt.DeltaDecode(nd)
+	}
 	if err != nil {
 		return err
 	}
@@ -400,6 +410,21 @@ func (vf *VersionedFetcher) processNode(
 	return err
 }
 
+// isSelectedField returns true if the field with the given name is selected, in which case the
+// blocks of its sub-DAG are replayed.
+func (vf *VersionedFetcher) isSelectedField(name string) bool {
+	if vf.selectedFieldIDs == nil {
+		return true
+	}
+	fieldID := vf.col.Schema.GetFieldKey(name)
+	for _, selectedFieldID := range vf.selectedFieldIDs {
+		if selectedFieldID == fieldID {
+			return true
+		}
+	}
+	return false
+}
+
 func (vf *VersionedFetcher) getDAGNode(c cid.Cid) (*dag.ProtoNode, error) {
 	// get Block
 	blk, err := vf.store.DAGstore().Get(vf.ctx, c)
@@ -350,3 +350,72 @@ func TestFetcherGetOnePrimaryIndexDecoded(t *testing.T) {
 	assert.Equal(t, "John", name)
 	assert.Equal(t, uint64(21), age)
 }
+
+func TestFetcherGetSelectedFieldsDecoded(t *testing.T) {
+	ctx := context.Background()
+	db, err := newMemoryDB(ctx)
+	assert.NoError(t, err)
+
+	col, err := newTestCollectionWithSchema(t, ctx, db)
+	assert.NoError(t, err)
+
+	for _, data := range []string{
+		`{"Name": "John", "Age": 21}`,
+		`{"Name": "Alice"}`,
+		`{"Name": "Bob", "Age": 30}`,
+	} {
+		doc, err := client.NewDocFromJSON([]byte(data))
+		assert.NoError(t, err)
+		err = col.Save(ctx, doc)
+		assert.NoError(t, err)
+	}
+
+	// the deleted documents must not be fetched.
+	deleted, err := col.Delete(ctx, mustNewDocKey(t, "bae-52b9170d-b77a-5887-b877-cbdbb99b009f"))
+	assert.NoError(t, err)
+	assert.True(t, deleted)
+
+	desc := col.Description()
+	ageField, ok := desc.GetField("Age")
+	assert.True(t, ok)
+
+	df := new(fetcher.DocumentFetcher)
+	err = df.Init(&desc, []*client.FieldDescription{&ageField}, false, false)
+	assert.NoError(t, err)
+
+	txn, err := db.NewTxn(ctx, true)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	err = df.Start(ctx, txn, core.Spans{})
+	assert.NoError(t, err)
+
+	ages := []any{}
+	for {
+		ddoc, err := df.FetchNextDecoded(ctx)
+		assert.NoError(t, err)
+		if ddoc == nil {
+			break
+		}
+
+		_, err = ddoc.Get("Name")
+		assert.ErrorIs(t, err, client.ErrFieldNotExist)
+
+		age, err := ddoc.Get("Age")
+		if err != nil {
+			assert.ErrorIs(t, err, client.ErrFieldNotExist)
+			age = nil
+		}
+		ages = append(ages, age)
+	}
+
+	assert.ElementsMatch(t, []any{nil, uint64(30)}, ages)
+}
+
+func mustNewDocKey(t *testing.T, key string) client.DocKey {
+	docKey, err := client.NewDocKeyFromString(key)
+	assert.NoError(t, err)
+	return docKey
+}
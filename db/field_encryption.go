@@ -0,0 +1,92 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+
+	"github.com/sourcenetwork/defradb/client"
+	corecrdt "github.com/sourcenetwork/defradb/core/crdt"
+)
+
+// withFieldKeyring returns a new context holding the keyring of the keys of the encrypted fields
+// the caller presents, either along with the request or through the identity it is authenticated
+// as, the former taking precedence.
+//
+// The context is returned as is if it already holds a keyring of field keys.
+func (db *db) withFieldKeyring(ctx context.Context) (context.Context, error) {
+	if corecrdt.FieldKeyringFromContext(ctx) != nil {
+		return ctx, nil
+	}
+
+	keys := map[string][]byte{}
+	if identity := client.RequestContextFromContext(ctx).Identity; identity != "" {
+		for name, key := range db.identityFieldKeys[identity] {
+			keys[name] = key
+		}
+	}
+	for name, key := range client.FieldKeysFromContext(ctx) {
+		keys[name] = key
+	}
+
+	keyring, err := corecrdt.NewKeyring(keys)
+	if err != nil {
+		return nil, err
+	}
+	return corecrdt.WithFieldKeyring(ctx, keyring), nil
+}
+
+// encryptedValue is the encrypted value of an encrypted field, which is written as is.
+type encryptedValue struct {
+	client.WriteableValue
+	data []byte
+}
+
+func (v encryptedValue) Value() any {
+	return v.data
+}
+
+func (v encryptedValue) Bytes() ([]byte, error) {
+	return v.data, nil
+}
+
+// encryptFieldValue returns the given value of the given field encrypted with the key of the
+// field held by the given context, if the field is encrypted.
+//
+// The value is encrypted before being written, so that neither the datastore nor the deltas of
+// the field and the document hold it in plaintext. Cleared values are left as is.
+func (c *collection) encryptFieldValue(
+	ctx context.Context,
+	field client.FieldDescription,
+	val client.Value,
+) (client.Value, error) {
+	if !field.Encrypted || val.IsDelete() || val.Value() == nil {
+		return val, nil
+	}
+	wval, ok := val.(client.WriteableValue)
+	if !ok {
+		return nil, client.ErrValueTypeMismatch
+	}
+
+	cipher := corecrdt.FieldKeyringFromContext(ctx).Cipher(corecrdt.FieldKeyName(c.Name(), field.Name))
+	if cipher == nil {
+		return nil, NewErrMissingFieldKey(c.Name(), field.Name)
+	}
+	buf, err := wval.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	data, err := cipher.EncryptFieldValue(buf)
+	if err != nil {
+		return nil, err
+	}
+	return encryptedValue{WriteableValue: wval, data: data}, nil
+}
@@ -0,0 +1,132 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/client"
+)
+
+var testFieldKeys = map[string][]byte{"Users.ssn": bytes.Repeat([]byte{1}, 32)}
+
+func newEncryptedFieldDB(t *testing.T, ctx context.Context, opts ...Option) *implicitTxnDB {
+	db, err := newMemoryDB(ctx, opts...)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close(ctx) })
+
+	err = db.AddSchema(ctx, `type Users { name: String ssn: String @encrypted }`)
+	require.NoError(t, err)
+	return db
+}
+
+func TestEncryptedFieldReadableOnlyWithKey(t *testing.T) {
+	ctx := context.Background()
+	db := newEncryptedFieldDB(t, ctx)
+	keyCtx := client.WithFieldKeys(ctx, testFieldKeys)
+
+	res := db.ExecRequest(keyCtx, `mutation { create_Users(data: "{\"name\": \"John\", \"ssn\": \"123-45-6789\"}") { _key } }`)
+	require.Empty(t, res.GQL.Errors)
+
+	res = db.ExecRequest(keyCtx, `query { Users { name ssn } }`)
+	require.Empty(t, res.GQL.Errors)
+	assert.Equal(t, []map[string]any{{"name": "John", "ssn": "123-45-6789"}}, res.GQL.Data)
+
+	res = db.ExecRequest(ctx, `query { Users { name ssn } }`)
+	require.Empty(t, res.GQL.Errors)
+	assert.Equal(t, []map[string]any{{"name": "John", "ssn": nil}}, res.GQL.Data)
+
+	wrongKeyCtx := client.WithFieldKeys(ctx, map[string][]byte{"Users.ssn": bytes.Repeat([]byte{2}, 32)})
+	res = db.ExecRequest(wrongKeyCtx, `query { Users(filter: {ssn: {_eq: "123-45-6789"}}) { name } }`)
+	require.Empty(t, res.GQL.Errors)
+	assert.Empty(t, res.GQL.Data)
+}
+
+func TestEncryptedFieldNotWritableWithoutKey(t *testing.T) {
+	ctx := context.Background()
+	db := newEncryptedFieldDB(t, ctx)
+
+	res := db.ExecRequest(ctx, `mutation { create_Users(data: "{\"name\": \"John\", \"ssn\": \"123-45-6789\"}") { _key } }`)
+	require.Len(t, res.GQL.Errors, 1)
+	assert.ErrorIs(t, res.GQL.Errors[0], ErrMissingFieldKey)
+
+	// The other fields remain writable without the key.
+	res = db.ExecRequest(ctx, `mutation { create_Users(data: "{\"name\": \"John\"}") { _key } }`)
+	require.Empty(t, res.GQL.Errors)
+	docKey := res.GQL.Data.([]map[string]any)[0]["_key"].(string)
+
+	res = db.ExecRequest(
+		ctx,
+		fmt.Sprintf(`mutation { update_Users(id: %q, data: "{\"ssn\": \"123-45-6789\"}") { _key } }`, docKey),
+	)
+	require.Len(t, res.GQL.Errors, 1)
+	assert.ErrorIs(t, res.GQL.Errors[0], ErrMissingFieldKey)
+}
+
+func TestEncryptedFieldKeptOutOfBlocks(t *testing.T) {
+	ctx := client.WithFieldKeys(context.Background(), testFieldKeys)
+	db := newEncryptedFieldDB(t, ctx)
+	col, err := db.GetCollectionByName(ctx, "Users")
+	require.NoError(t, err)
+
+	doc, err := client.NewDocFromJSON([]byte(`{"name": "John", "ssn": "123-45-6789"}`))
+	require.NoError(t, err)
+	err = col.Create(ctx, doc)
+	require.NoError(t, err)
+
+	blocks := getCommitBlocks(t, ctx, col, doc.Head())
+	for _, block := range blocks {
+		assert.NotContains(t, string(block), "123-45-6789")
+	}
+	assert.Contains(t, string(bytes.Join(blocks, nil)), "John")
+
+	doc, err = col.Get(ctx, doc.Key(), false)
+	require.NoError(t, err)
+	ssn, err := doc.Get("ssn")
+	require.NoError(t, err)
+	assert.Equal(t, "123-45-6789", ssn)
+}
+
+func TestEncryptedFieldReadableWithIdentityKey(t *testing.T) {
+	ctx := context.Background()
+	db := newEncryptedFieldDB(t, ctx, WithIdentityFieldKeys(map[string]map[string][]byte{"admin": testFieldKeys}))
+	adminCtx := client.WithRequestContext(ctx, client.RequestContext{Identity: "admin"})
+
+	res := db.ExecRequest(adminCtx, `mutation { create_Users(data: "{\"name\": \"John\", \"ssn\": \"123-45-6789\"}") { _key } }`)
+	require.Empty(t, res.GQL.Errors)
+
+	res = db.ExecRequest(adminCtx, `query { Users { ssn } }`)
+	require.Empty(t, res.GQL.Errors)
+	assert.Equal(t, []map[string]any{{"ssn": "123-45-6789"}}, res.GQL.Data)
+
+	res = db.ExecRequest(
+		client.WithRequestContext(ctx, client.RequestContext{Identity: "guest"}),
+		`query { Users { ssn } }`,
+	)
+	require.Empty(t, res.GQL.Errors)
+	assert.Equal(t, []map[string]any{{"ssn": nil}}, res.GQL.Data)
+}
+
+func TestNewDBWithInvalidIdentityFieldKey(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := newMemoryDB(
+		ctx,
+		WithIdentityFieldKeys(map[string]map[string][]byte{"admin": {"Users.ssn": []byte("short")}}),
+	)
+	assert.Error(t, err)
+}
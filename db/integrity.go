@@ -0,0 +1,346 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	ds "github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/query"
+	"github.com/sourcenetwork/immutable"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/core"
+	"github.com/sourcenetwork/defradb/datastore"
+	"github.com/sourcenetwork/defradb/errors"
+	"github.com/sourcenetwork/defradb/logging"
+)
+
+// WithIntegrityCheck checks the integrity of the system keyspace on open, before the schema is
+// loaded, repairing the issues known to be fixable if repair is true.
+//
+// The issues are logged, and the report is returned by LastIntegrityReport. The database is
+// opened regardless of the issues, unless they prevent the schema from being loaded.
+func WithIntegrityCheck(repair bool) Option {
+	return func(db *db) {
+		db.integrityCheck = immutable.Some(client.CheckIntegrityOptions{Repair: repair})
+	}
+}
+
+// CheckIntegrity checks the invariants of the system keyspace in a single transaction,
+// repairing the issues known to be fixable if requested, and records the report as the last one.
+func (db *db) CheckIntegrity(
+	ctx context.Context,
+	opts client.CheckIntegrityOptions,
+) (client.IntegrityReport, error) {
+	db.glock.RLock()
+	defer db.glock.RUnlock()
+
+	txn, err := db.NewTxn(ctx, !opts.Repair)
+	if err != nil {
+		return client.IntegrityReport{}, err
+	}
+	defer txn.Discard(ctx)
+
+	report := client.IntegrityReport{
+		CheckedAt: db.now(),
+		Issues:    []client.IntegrityIssue{},
+	}
+	maxColID, err := db.checkCollections(ctx, txn, opts, &report)
+	if err != nil {
+		return client.IntegrityReport{}, err
+	}
+	err = checkSequence(ctx, txn, opts, core.COLLECTION, maxColID, &report)
+	if err != nil {
+		return client.IntegrityReport{}, err
+	}
+	err = checkChangefeedSequences(ctx, txn, opts, &report)
+	if err != nil {
+		return client.IntegrityReport{}, err
+	}
+
+	if opts.Repair {
+		if err := txn.Commit(ctx); err != nil {
+			return client.IntegrityReport{}, err
+		}
+	}
+
+	db.integrityMu.Lock()
+	db.integrityReport = immutable.Some(report)
+	db.integrityMu.Unlock()
+	return report, nil
+}
+
+// LastIntegrityReport returns the report of the last integrity check, if any.
+func (db *db) LastIntegrityReport() immutable.Option[client.IntegrityReport] {
+	db.integrityMu.Lock()
+	defer db.integrityMu.Unlock()
+	return db.integrityReport
+}
+
+// checkIntegrityOnOpen checks the integrity of the system keyspace if enabled, logging the issues
+// found.
+func (db *db) checkIntegrityOnOpen(ctx context.Context) error {
+	if !db.integrityCheck.HasValue() {
+		return nil
+	}
+
+	log.Info(ctx, "Checking the integrity of the system keyspace")
+	report, err := db.CheckIntegrity(ctx, db.integrityCheck.Value())
+	if err != nil {
+		return err
+	}
+	for _, issue := range report.Issues {
+		kvs := []logging.KV{
+			logging.NewKV("Key", issue.Key),
+			logging.NewKV("Problem", issue.Problem),
+			logging.NewKV("Detail", issue.Detail),
+		}
+		switch {
+		case issue.Repaired:
+			log.Info(ctx, "Integrity issue repaired", kvs...)
+		case issue.Fixable:
+			log.Error(ctx, "Integrity issue found, repairable with the repair mode", kvs...)
+		default:
+			log.Error(ctx, "Integrity issue found", kvs...)
+		}
+	}
+	log.Info(
+		ctx,
+		"Integrity checked",
+		logging.NewKV("Collections", report.Collections),
+		logging.NewKV("Issues", len(report.Issues)),
+	)
+	return nil
+}
+
+// checkCollections checks that the descriptions of the current schema versions of the collections
+// are stored and parse, and that their schemas point to these versions.
+//
+// It returns the highest collection ID in use.
+func (db *db) checkCollections(
+	ctx context.Context,
+	txn datastore.Txn,
+	opts client.CheckIntegrityOptions,
+	report *client.IntegrityReport,
+) (uint64, error) {
+	versions, err := collectionVersions(ctx, txn)
+	if err != nil {
+		return 0, err
+	}
+
+	names := make([]string, 0, len(versions))
+	for name := range versions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var maxID uint64
+	for _, name := range names {
+		report.Collections++
+		versionID := versions[name]
+		key := core.NewCollectionSchemaVersionKey(versionID)
+		buf, err := txn.Systemstore().Get(ctx, key.ToDS())
+		if errors.Is(err, ds.ErrNotFound) {
+			report.Issues = append(report.Issues, client.IntegrityIssue{
+				Key:     key.ToString(),
+				Problem: client.IntegrityDescriptionMissing,
+				Detail:  fmt.Sprintf("collection %s has no description for its schema version", name),
+			})
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		var desc client.CollectionDescription
+		if err := json.Unmarshal(buf, &desc); err != nil {
+			report.Issues = append(report.Issues, client.IntegrityIssue{
+				Key:     key.ToString(),
+				Problem: client.IntegrityDescriptionInvalid,
+				Detail:  err.Error(),
+			})
+			continue
+		}
+		if desc.Name != name {
+			report.Issues = append(report.Issues, client.IntegrityIssue{
+				Key:     key.ToString(),
+				Problem: client.IntegrityDescriptionMismatch,
+				Detail:  fmt.Sprintf("description of collection %s is named %s", name, desc.Name),
+			})
+		}
+		if uint64(desc.ID) > maxID {
+			maxID = uint64(desc.ID)
+		}
+
+		err = checkSchemaHead(ctx, txn, opts, desc.Schema.SchemaID, versionID, report)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return maxID, nil
+}
+
+// collectionVersions returns the current schema version IDs of the collections, by name.
+func collectionVersions(ctx context.Context, txn datastore.Txn) (map[string]string, error) {
+	prefix := core.NewCollectionKey("").ToString()
+	q, err := txn.Systemstore().Query(ctx, query.Query{Prefix: prefix})
+	if err != nil {
+		return nil, NewErrFailedToCreateCollectionQuery(err)
+	}
+	defer func() {
+		if err := q.Close(); err != nil {
+			log.ErrorE(ctx, "Failed to close collection query", err)
+		}
+	}()
+
+	versions := map[string]string{}
+	for res := range q.Next() {
+		if res.Error != nil {
+			return nil, res.Error
+		}
+		versions[strings.TrimPrefix(res.Key, prefix+"/")] = string(res.Value)
+	}
+	return versions, nil
+}
+
+// checkSchemaHead checks that the schema with the given ID points to the given version, pointing
+// it to the version if it is to be repaired.
+func checkSchemaHead(
+	ctx context.Context,
+	txn datastore.Txn,
+	opts client.CheckIntegrityOptions,
+	schemaID string,
+	versionID string,
+	report *client.IntegrityReport,
+) error {
+	key := core.NewCollectionSchemaKey(schemaID)
+	buf, err := txn.Systemstore().Get(ctx, key.ToDS())
+	if err != nil && !errors.Is(err, ds.ErrNotFound) {
+		return err
+	}
+	if string(buf) == versionID {
+		return nil
+	}
+
+	issue := client.IntegrityIssue{
+		Key:     key.ToString(),
+		Problem: client.IntegritySchemaHeadMismatch,
+		Detail:  fmt.Sprintf("schema points to version %q instead of %q", string(buf), versionID),
+		Fixable: true,
+	}
+	if opts.Repair {
+		if err := txn.Systemstore().Put(ctx, key.ToDS(), []byte(versionID)); err != nil {
+			return err
+		}
+		issue.Repaired = true
+	}
+	report.Issues = append(report.Issues, issue)
+	return nil
+}
+
+// checkChangefeedSequences checks that the sequence of the changefeed of each collection is not
+// behind the highest sequence number recorded in it.
+func checkChangefeedSequences(
+	ctx context.Context,
+	txn datastore.Txn,
+	opts client.CheckIntegrityOptions,
+	report *client.IntegrityReport,
+) error {
+	q, err := txn.Systemstore().Query(ctx, query.Query{Prefix: core.CHANGEFEED, KeysOnly: true})
+	if err != nil {
+		return err
+	}
+	maxSequences := map[uint32]uint64{}
+	for res := range q.Next() {
+		if res.Error != nil {
+			_ = q.Close()
+			return res.Error
+		}
+		key, err := core.NewChangefeedKeyFromString(res.Key)
+		if err != nil {
+			continue
+		}
+		if key.Sequence > maxSequences[key.CollectionID] {
+			maxSequences[key.CollectionID] = key.Sequence
+		}
+	}
+	if err := q.Close(); err != nil {
+		return err
+	}
+
+	colIDs := make([]uint32, 0, len(maxSequences))
+	for colID := range maxSequences {
+		colIDs = append(colIDs, colID)
+	}
+	sort.Slice(colIDs, func(i, j int) bool { return colIDs[i] < colIDs[j] })
+	for _, colID := range colIDs {
+		err := checkSequence(ctx, txn, opts, changefeedSequenceName(colID), maxSequences[colID], report)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkSequence checks that the counter of the sequence with the given name is not lower than the
+// highest ID it generated, setting the counter to that ID if it is to be repaired.
+func checkSequence(
+	ctx context.Context,
+	txn datastore.Txn,
+	opts client.CheckIntegrityOptions,
+	name string,
+	maxID uint64,
+	report *client.IntegrityReport,
+) error {
+	if maxID == 0 {
+		return nil
+	}
+
+	seq := &sequence{key: core.NewSequenceKey(name)}
+	buf, err := txn.Systemstore().Get(ctx, seq.key.ToDS())
+	if err != nil && !errors.Is(err, ds.ErrNotFound) {
+		return err
+	}
+
+	var detail string
+	switch {
+	case errors.Is(err, ds.ErrNotFound):
+		detail = fmt.Sprintf("counter is missing while IDs up to %d are in use", maxID)
+	case len(buf) != 8:
+		detail = fmt.Sprintf("counter is malformed while IDs up to %d are in use", maxID)
+	case binary.BigEndian.Uint64(buf) < maxID:
+		detail = fmt.Sprintf("counter %d is lower than the ID %d in use", binary.BigEndian.Uint64(buf), maxID)
+	default:
+		return nil
+	}
+
+	issue := client.IntegrityIssue{
+		Key:     seq.key.ToString(),
+		Problem: client.IntegritySequenceBehind,
+		Detail:  detail,
+		Fixable: true,
+	}
+	if opts.Repair {
+		seq.val = maxID
+		if err := seq.update(ctx, txn); err != nil {
+			return err
+		}
+		issue.Repaired = true
+	}
+	report.Issues = append(report.Issues, issue)
+	return nil
+}
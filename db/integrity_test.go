@@ -0,0 +1,111 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/core"
+)
+
+func TestCheckIntegrityWithoutIssues(t *testing.T) {
+	ctx := context.Background()
+	col := newTestCollectionWithDocs(t, ctx, `{"Name": "John", "Age": 21}`)
+	d := &implicitTxnDB{col.(*collection).db}
+
+	assert.False(t, d.LastIntegrityReport().HasValue())
+	report, err := d.CheckIntegrity(ctx, client.CheckIntegrityOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Collections)
+	assert.Empty(t, report.Issues)
+	assert.True(t, report.Healthy())
+	assert.Equal(t, report, d.LastIntegrityReport().Value())
+}
+
+func TestCheckIntegrityRepairsFixableIssues(t *testing.T) {
+	ctx := context.Background()
+	col := newTestCollectionWithDocs(t, ctx)
+	d := &implicitTxnDB{col.(*collection).db}
+
+	seqKey := core.NewSequenceKey(core.COLLECTION)
+	schemaKey := core.NewCollectionSchemaKey(col.SchemaID())
+	require.NoError(t, d.systemstore().Put(ctx, seqKey.ToDS(), make([]byte, 8)))
+	require.NoError(t, d.systemstore().Put(ctx, schemaKey.ToDS(), []byte("bafkreibad")))
+
+	report, err := d.CheckIntegrity(ctx, client.CheckIntegrityOptions{})
+	require.NoError(t, err)
+	require.Len(t, report.Issues, 2)
+	assert.Equal(t, client.IntegritySchemaHeadMismatch, report.Issues[0].Problem)
+	assert.Equal(t, schemaKey.ToString(), report.Issues[0].Key)
+	assert.Equal(t, client.IntegritySequenceBehind, report.Issues[1].Problem)
+	assert.Equal(t, seqKey.ToString(), report.Issues[1].Key)
+	assert.True(t, report.Issues[0].Fixable)
+	assert.False(t, report.Issues[0].Repaired)
+	assert.False(t, report.Healthy())
+
+	report, err = d.CheckIntegrity(ctx, client.CheckIntegrityOptions{Repair: true})
+	require.NoError(t, err)
+	require.Len(t, report.Issues, 2)
+	assert.True(t, report.Issues[0].Repaired)
+	assert.True(t, report.Issues[1].Repaired)
+	assert.True(t, report.Healthy())
+
+	report, err = d.CheckIntegrity(ctx, client.CheckIntegrityOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, report.Issues)
+}
+
+func TestCheckIntegrityWithInvalidDescription(t *testing.T) {
+	ctx := context.Background()
+	col := newTestCollectionWithDocs(t, ctx)
+	d := &implicitTxnDB{col.(*collection).db}
+
+	key := core.NewCollectionSchemaVersionKey(col.Schema().VersionID)
+	require.NoError(t, d.systemstore().Put(ctx, key.ToDS(), []byte("{")))
+
+	report, err := d.CheckIntegrity(ctx, client.CheckIntegrityOptions{Repair: true})
+	require.NoError(t, err)
+	require.Len(t, report.Issues, 1)
+	assert.Equal(t, client.IntegrityDescriptionInvalid, report.Issues[0].Problem)
+	assert.False(t, report.Issues[0].Fixable)
+	assert.False(t, report.Issues[0].Repaired)
+	assert.False(t, report.Healthy())
+}
+
+func TestIntegrityCheckOnOpen(t *testing.T) {
+	ctx := context.Background()
+	col := newTestCollectionWithDocs(t, ctx)
+	d := col.(*collection).db
+
+	seqKey := core.NewSequenceKey(core.COLLECTION)
+	require.NoError(t, d.systemstore().Put(ctx, seqKey.ToDS(), make([]byte, 8)))
+
+	// The database is reopened on the same store, which is closed along with the first one.
+	reopened, err := newDB(ctx, d.rootstore, WithIntegrityCheck(true))
+	require.NoError(t, err)
+
+	report := reopened.LastIntegrityReport()
+	require.True(t, report.HasValue())
+	require.Len(t, report.Value().Issues, 1)
+	assert.True(t, report.Value().Issues[0].Repaired)
+
+	// The collection created after the repair doesn't reuse the ID of the existing one.
+	err = reopened.AddSchema(ctx, `type books { Title: String }`)
+	require.NoError(t, err)
+	books, err := reopened.GetCollectionByName(ctx, "books")
+	require.NoError(t, err)
+	assert.Greater(t, books.ID(), col.ID())
+}
@@ -0,0 +1,110 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	ds "github.com/ipfs/go-datastore"
+	dsq "github.com/ipfs/go-datastore/query"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/core"
+	"github.com/sourcenetwork/defradb/datastore"
+	"github.com/sourcenetwork/defradb/errors"
+)
+
+// kvNamespacePattern matches the valid namespaces of the key-value store.
+var kvNamespacePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// newKVKey returns the key of the given entry of the key-value store, or an error if the namespace
+// or the key of the entry is invalid.
+func newKVKey(namespace string, key string) (core.KVKey, error) {
+	if !kvNamespacePattern.MatchString(namespace) {
+		return core.KVKey{}, client.NewErrInvalidKVNamespace(namespace)
+	}
+	if key == "" {
+		return core.KVKey{}, client.NewErrInvalidKVKey(key)
+	}
+	return core.NewKVKey(namespace, key), nil
+}
+
+func (db *db) getKV(ctx context.Context, txn datastore.Txn, namespace string, key string) ([]byte, error) {
+	kvKey, err := newKVKey(namespace, key)
+	if err != nil {
+		return nil, err
+	}
+	value, err := txn.Systemstore().Get(ctx, kvKey.ToDS())
+	if errors.Is(err, ds.ErrNotFound) {
+		return nil, client.NewErrKVKeyNotFound(namespace, key)
+	}
+	return value, err
+}
+
+func (db *db) setKV(ctx context.Context, txn datastore.Txn, namespace string, key string, value []byte) error {
+	kvKey, err := newKVKey(namespace, key)
+	if err != nil {
+		return err
+	}
+	return txn.Systemstore().Put(ctx, kvKey.ToDS(), value)
+}
+
+func (db *db) deleteKV(ctx context.Context, txn datastore.Txn, namespace string, key string) error {
+	kvKey, err := newKVKey(namespace, key)
+	if err != nil {
+		return err
+	}
+	return txn.Systemstore().Delete(ctx, kvKey.ToDS())
+}
+
+func (db *db) getAllKV(
+	ctx context.Context,
+	txn datastore.Txn,
+	namespace string,
+	prefix string,
+) ([]client.KVEntry, error) {
+	if !kvNamespacePattern.MatchString(namespace) {
+		return nil, client.NewErrInvalidKVNamespace(namespace)
+	}
+
+	// The keys are hex encoded, so the prefix can't be matched by the query, which matches whole
+	// path segments.
+	results, err := txn.Systemstore().Query(ctx, dsq.Query{
+		Prefix: core.NewKVKey(namespace, "").ToString(),
+		Orders: []dsq.Order{dsq.OrderByKey{}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := results.Close(); err != nil {
+			log.ErrorE(ctx, "Failed to close key-value query", err)
+		}
+	}()
+
+	entries := []client.KVEntry{}
+	for result := range results.Next() {
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		key, err := core.NewKVKeyFromString(result.Key)
+		if err != nil {
+			return nil, err
+		}
+		if !strings.HasPrefix(key.Key, prefix) {
+			continue
+		}
+		entries = append(entries, client.KVEntry{Key: key.Key, Value: result.Value})
+	}
+	return entries, nil
+}
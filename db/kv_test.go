@@ -0,0 +1,158 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/client"
+)
+
+func TestKVSetGetAndDelete(t *testing.T) {
+	ctx := context.Background()
+	db, err := newMemoryDB(ctx)
+	require.NoError(t, err)
+	defer db.Close(ctx)
+
+	err = db.SetKV(ctx, "app", "cursor/users", []byte("42"))
+	require.NoError(t, err)
+
+	value, err := db.GetKV(ctx, "app", "cursor/users")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("42"), value)
+
+	err = db.DeleteKV(ctx, "app", "cursor/users")
+	require.NoError(t, err)
+
+	_, err = db.GetKV(ctx, "app", "cursor/users")
+	require.ErrorIs(t, err, client.ErrKVKeyNotFound)
+
+	// Deleting a missing key is not an error.
+	err = db.DeleteKV(ctx, "app", "cursor/users")
+	require.NoError(t, err)
+}
+
+func TestKVNamespacesAreIsolated(t *testing.T) {
+	ctx := context.Background()
+	db, err := newMemoryDB(ctx)
+	require.NoError(t, err)
+	defer db.Close(ctx)
+
+	require.NoError(t, db.SetKV(ctx, "app", "key", []byte("a")))
+	require.NoError(t, db.SetKV(ctx, "app2", "key", []byte("b")))
+	// The key can't escape its namespace.
+	require.NoError(t, db.SetKV(ctx, "app", "../app2/key", []byte("c")))
+
+	value, err := db.GetKV(ctx, "app2", "key")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("b"), value)
+
+	entries, err := db.GetAllKV(ctx, "app", "")
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		[]client.KVEntry{
+			{Key: "../app2/key", Value: []byte("c")},
+			{Key: "key", Value: []byte("a")},
+		},
+		entries,
+	)
+}
+
+func TestKVGetAllWithPrefix(t *testing.T) {
+	ctx := context.Background()
+	db, err := newMemoryDB(ctx)
+	require.NoError(t, err)
+	defer db.Close(ctx)
+
+	for _, key := range []string{"cursor/users", "settings", "cursor/books", "cursors"} {
+		require.NoError(t, db.SetKV(ctx, "app", key, []byte(key)))
+	}
+
+	entries, err := db.GetAllKV(ctx, "app", "cursor/")
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		[]client.KVEntry{
+			{Key: "cursor/books", Value: []byte("cursor/books")},
+			{Key: "cursor/users", Value: []byte("cursor/users")},
+		},
+		entries,
+	)
+
+	entries, err = db.GetAllKV(ctx, "other", "")
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestKVWithInvalidNamespaceOrKey(t *testing.T) {
+	ctx := context.Background()
+	db, err := newMemoryDB(ctx)
+	require.NoError(t, err)
+	defer db.Close(ctx)
+
+	for _, namespace := range []string{"", "app/other", "..", "app name"} {
+		err = db.SetKV(ctx, namespace, "key", []byte("value"))
+		require.ErrorIs(t, err, client.ErrInvalidKVNamespace)
+		_, err = db.GetAllKV(ctx, namespace, "")
+		require.ErrorIs(t, err, client.ErrInvalidKVNamespace)
+	}
+
+	_, err = db.GetKV(ctx, "app", "")
+	require.ErrorIs(t, err, client.ErrInvalidKVKey)
+}
+
+func TestKVWithTxnIsWrittenAlongsideDocuments(t *testing.T) {
+	ctx := context.Background()
+	db, err := newMemoryDB(ctx)
+	require.NoError(t, err)
+	defer db.Close(ctx)
+
+	err = db.AddSchema(ctx, `type users { Name: String }`)
+	require.NoError(t, err)
+
+	write := func(cursor string, commit bool) {
+		txn, err := db.NewTxn(ctx, false)
+		require.NoError(t, err)
+		store := db.WithTxn(txn)
+		res := store.ExecRequest(ctx, `mutation { create_users(data: "{\"Name\": \"John\"}") { _key } }`)
+		require.Empty(t, res.GQL.Errors)
+		require.NoError(t, store.SetKV(ctx, "app", "cursor", []byte(cursor)))
+
+		// The value is visible within the transaction before it is committed.
+		value, err := store.GetKV(ctx, "app", "cursor")
+		require.NoError(t, err)
+		assert.Equal(t, []byte(cursor), value)
+
+		if commit {
+			require.NoError(t, txn.Commit(ctx))
+		} else {
+			txn.Discard(ctx)
+		}
+	}
+
+	write("discarded", false)
+	_, err = db.GetKV(ctx, "app", "cursor")
+	require.ErrorIs(t, err, client.ErrKVKeyNotFound)
+
+	write("committed", true)
+	value, err := db.GetKV(ctx, "app", "cursor")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("committed"), value)
+
+	res := db.ExecRequest(ctx, `query { users { Name } }`)
+	require.Empty(t, res.GQL.Errors)
+	assert.Len(t, res.GQL.Data, 1)
+}
@@ -0,0 +1,257 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/client/request"
+	"github.com/sourcenetwork/defradb/datastore"
+)
+
+// MaskMethod is the way the values of a masked field are masked.
+type MaskMethod int
+
+const (
+	// MaskRedact replaces the values with null.
+	MaskRedact MaskMethod = iota
+	// MaskHash replaces the values with the hex encoded HMAC-SHA256 of their JSON encoding, keyed
+	// by the hash key of the node, so that the documents sharing a value can still be correlated
+	// but the values can't be guessed from their hashes.
+	MaskHash
+	// MaskTruncate keeps the leading characters of the values, as strings, and drops the others.
+	MaskTruncate
+)
+
+// MaskingRule is the masking of the values of a field, such as a field holding personal data,
+// applied to the documents shared outside of operations: the exports and the subscription
+// payloads.
+type MaskingRule struct {
+	// Collection is the name of the collection of the field.
+	Collection string
+	// Field is the name of the field.
+	Field string
+	// Method is the way the values of the field are masked.
+	Method MaskMethod
+	// Length is the number of leading characters kept by [MaskTruncate].
+	Length int
+}
+
+// WithMasking masks the values of the fields of the given rules in the subscription payloads,
+// and in the results of the queries executed in a context given by [client.WithMaskedResults].
+// The hashed values are keyed by the given secret hash key of the node.
+//
+// The aggregates of the masked fields are redacted.
+func WithMasking(hashKey []byte, rules ...MaskingRule) Option {
+	return func(db *db) {
+		db.maskingKey = hashKey
+		db.maskingRules = make(map[string]MaskingRule, len(rules))
+		for _, rule := range rules {
+			db.maskingRules[rule.Collection+"."+rule.Field] = rule
+		}
+	}
+}
+
+// mask returns the given value masked, hashed with the given key. Null values are left as is.
+func (rule MaskingRule) mask(hashKey []byte, value any) any {
+	if value == nil {
+		return nil
+	}
+	switch rule.Method {
+	case MaskHash:
+		buf, err := json.Marshal(value)
+		if err != nil {
+			buf = []byte(fmt.Sprint(value))
+		}
+		mac := hmac.New(sha256.New, hashKey)
+		mac.Write(buf)
+		return hex.EncodeToString(mac.Sum(nil))
+	case MaskTruncate:
+		runes := []rune(fmt.Sprint(value))
+		if len(runes) > rule.Length {
+			runes = runes[:rule.Length]
+		}
+		return string(runes)
+	default:
+		return nil
+	}
+}
+
+// masker masks the documents resulting from a selection.
+type masker struct {
+	db    *db
+	ctx   context.Context
+	txn   datastore.Txn
+	descs map[string]client.CollectionDescription
+}
+
+// maskResults masks the given documents of the given collection, resulting from the given
+// selections, according to the masking rules of the database.
+func (db *db) maskResults(
+	ctx context.Context,
+	txn datastore.Txn,
+	collection string,
+	fields []request.Selection,
+	docs []map[string]any,
+) error {
+	if len(db.maskingRules) == 0 {
+		return nil
+	}
+	m := &masker{
+		db:    db,
+		ctx:   ctx,
+		txn:   txn,
+		descs: map[string]client.CollectionDescription{},
+	}
+	for _, doc := range docs {
+		if err := m.maskDocument(collection, fields, doc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// description returns the description of the collection with the given name.
+func (m *masker) description(name string) (client.CollectionDescription, error) {
+	if desc, ok := m.descs[name]; ok {
+		return desc, nil
+	}
+	col, err := m.db.getCollectionByName(m.ctx, m.txn, name)
+	if err != nil {
+		return client.CollectionDescription{}, err
+	}
+	m.descs[name] = col.Description()
+	return col.Description(), nil
+}
+
+// rule returns the masking rule of the given field of the given collection, if any.
+func (m *masker) rule(collection string, field string) (MaskingRule, bool) {
+	rule, ok := m.db.maskingRules[collection+"."+field]
+	return rule, ok
+}
+
+// maskDocument masks the values of the given document of the given collection, by walking the
+// selections it results from, so that the aliased fields and the related documents are masked
+// as well.
+func (m *masker) maskDocument(collection string, fields []request.Selection, doc map[string]any) error {
+	for _, selection := range fields {
+		switch s := selection.(type) {
+		case *request.Field:
+			if rule, ok := m.rule(collection, s.Name); ok {
+				key := resultKey(*s)
+				doc[key] = rule.mask(m.db.maskingKey, doc[key])
+			}
+
+		case *request.Aggregate:
+			masked, err := m.aggregatesMaskedField(collection, s)
+			if err != nil {
+				return err
+			}
+			if masked {
+				doc[resultKey(s.Field)] = nil
+			}
+
+		case *request.Select:
+			target := collection
+			if s.Name != request.GroupFieldName {
+				desc, err := m.description(collection)
+				if err != nil {
+					return err
+				}
+				field, ok := desc.GetField(s.Name)
+				if !ok || !field.IsObject() {
+					continue
+				}
+				target = field.Schema
+			}
+			if err := m.maskRelated(target, s.Fields, doc[resultKey(s.Field)]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// resultKey returns the key of the value of the given field in the resulting documents.
+func resultKey(field request.Field) string {
+	if field.Alias.HasValue() {
+		return field.Alias.Value()
+	}
+	return field.Name
+}
+
+// maskRelated masks the given related documents of the given collection, either a single
+// document or a list of them.
+func (m *masker) maskRelated(collection string, fields []request.Selection, value any) error {
+	switch v := value.(type) {
+	case map[string]any:
+		return m.maskDocument(collection, fields, v)
+	case []map[string]any:
+		for _, doc := range v {
+			if err := m.maskDocument(collection, fields, doc); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, item := range v {
+			if doc, ok := item.(map[string]any); ok {
+				if err := m.maskDocument(collection, fields, doc); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// aggregatesMaskedField returns true if the given aggregate of the documents of the given
+// collection aggregates the values of a masked field, either of the collection or of a
+// related collection.
+func (m *masker) aggregatesMaskedField(collection string, aggregate *request.Aggregate) (bool, error) {
+	for _, target := range aggregate.Targets {
+		if _, ok := m.rule(collection, target.HostName); ok {
+			return true, nil
+		}
+		if !target.ChildName.HasValue() {
+			continue
+		}
+		desc, err := m.description(collection)
+		if err != nil {
+			return false, err
+		}
+		field, ok := desc.GetField(target.HostName)
+		if !ok || !field.IsObject() {
+			continue
+		}
+		related := field.Schema
+		for _, name := range target.ChildPath {
+			relatedDesc, err := m.description(related)
+			if err != nil {
+				return false, err
+			}
+			field, ok := relatedDesc.GetField(name)
+			if !ok || !field.IsObject() {
+				break
+			}
+			related = field.Schema
+		}
+		if _, ok := m.rule(related, target.ChildName.Value()); ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,101 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/client"
+)
+
+func newMaskedDB(t *testing.T, ctx context.Context) *implicitTxnDB {
+	db, err := newMemoryDB(
+		ctx,
+		WithUpdateEvents(),
+		WithMasking(
+			[]byte("secret"),
+			MaskingRule{Collection: "Author", Field: "email", Method: MaskHash},
+			MaskingRule{Collection: "Book", Field: "isbn", Method: MaskTruncate, Length: 3},
+			MaskingRule{Collection: "Book", Field: "price", Method: MaskRedact},
+		),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close(ctx) })
+
+	err = db.AddSchema(ctx, `
+		type Author { name: String email: String books: [Book] }
+		type Book { title: String isbn: String price: Int author: Author }
+	`)
+	require.NoError(t, err)
+	return db
+}
+
+func TestMaskedQueryResults(t *testing.T) {
+	ctx := context.Background()
+	db := newMaskedDB(t, ctx)
+
+	res := db.ExecRequest(ctx, `mutation { create_Author(data: "{\"name\": \"John\", \"email\": \"john@example.com\"}") { _key } }`)
+	require.Empty(t, res.GQL.Errors)
+	authorKey := res.GQL.Data.([]map[string]any)[0]["_key"].(string)
+	res = db.ExecRequest(
+		ctx,
+		`mutation { create_Book(data: "{\"title\": \"Go\", \"isbn\": \"978-0134190440\", \"price\": 30, \"author_id\": \"`+
+			authorKey+`\"}") { _key } }`,
+	)
+	require.Empty(t, res.GQL.Errors)
+
+	request := `query { Author { name mail: email books { title isbn } _sum(books: {field: price}) } }`
+	res = db.ExecRequest(client.WithMaskedResults(ctx), request)
+	require.Empty(t, res.GQL.Errors)
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte(`"john@example.com"`))
+	assert.Equal(t, []map[string]any{{
+		"name":  "John",
+		"mail":  hex.EncodeToString(mac.Sum(nil)),
+		"books": []map[string]any{{"title": "Go", "isbn": "978"}},
+		"_sum":  nil,
+	}}, res.GQL.Data)
+
+	res = db.ExecRequest(ctx, request)
+	require.Empty(t, res.GQL.Errors)
+	assert.Equal(t, "john@example.com", res.GQL.Data.([]map[string]any)[0]["mail"])
+}
+
+func TestMaskedSubscriptionPayloads(t *testing.T) {
+	ctx := context.Background()
+	db := newMaskedDB(t, ctx)
+
+	res := db.ExecRequest(ctx, `subscription { Book { title price } }`)
+	require.Empty(t, res.GQL.Errors)
+	require.NotNil(t, res.Pub)
+	defer res.Pub.Unsubscribe()
+
+	res2 := db.ExecRequest(ctx, `mutation { create_Book(data: "{\"title\": \"Go\", \"price\": 30}") { _key } }`)
+	require.Empty(t, res2.GQL.Errors)
+
+	select {
+	case payload := <-res.Pub.Stream():
+		result, ok := payload.(client.GQLResult)
+		require.True(t, ok)
+		require.Empty(t, result.Errors)
+		assert.Equal(t, []map[string]any{{"title": "Go", "price": nil}}, result.Data)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the subscription payload")
+	}
+}
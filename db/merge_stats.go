@@ -0,0 +1,140 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/unit"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/logging"
+	"github.com/sourcenetwork/defradb/metric"
+)
+
+const (
+	metricsName = "defradb"
+
+	mergedDeltasMetric = "merge.remoteDeltas"
+	conflictsMetric    = "merge.lwwConflicts"
+	dagDepthMetric     = "merge.dagDepth"
+)
+
+// mergeTracker keeps track of the merges of the deltas produced by other nodes into the documents
+// of each collection, and records them in the metrics of the database.
+type mergeTracker struct {
+	mu    sync.Mutex
+	stats map[uint32]*mergeStats
+
+	deltas    instrument.Int64Counter
+	conflicts instrument.Int64Counter
+	dagDepths instrument.Int64Histogram
+}
+
+// mergeStats are the merge statistics of a collection.
+type mergeStats struct {
+	deltas    uint64
+	conflicts uint64
+	commits   uint64
+	// The sum of the depths of the merged commits.
+	depths uint64
+}
+
+// newMergeTracker returns a merge tracker recording the merges in the metrics of the given meter.
+func newMergeTracker(meter *metric.Meter) (*mergeTracker, error) {
+	deltas, err := meter.GetSyncCounter(mergedDeltasMetric, unit.Dimensionless)
+	if err != nil {
+		return nil, err
+	}
+	conflicts, err := meter.GetSyncCounter(conflictsMetric, unit.Dimensionless)
+	if err != nil {
+		return nil, err
+	}
+	dagDepths, err := meter.GetSyncHistogram(dagDepthMetric, unit.Dimensionless)
+	if err != nil {
+		return nil, err
+	}
+	return &mergeTracker{
+		stats:     map[uint32]*mergeStats{},
+		deltas:    deltas,
+		conflicts: conflicts,
+		dagDepths: dagDepths,
+	}, nil
+}
+
+// record counts the given merge into a document of the given collection.
+func (t *mergeTracker) record(ctx context.Context, colID uint32, merge client.RemoteMerge) {
+	t.mu.Lock()
+	stats, ok := t.stats[colID]
+	if !ok {
+		stats = &mergeStats{}
+		t.stats[colID] = stats
+	}
+	stats.deltas++
+	stats.conflicts += merge.Conflicts
+	if merge.Commit {
+		stats.commits++
+		stats.depths += merge.Priority
+	}
+	t.mu.Unlock()
+
+	t.deltas.Add(ctx, 1)
+	if merge.Conflicts > 0 {
+		t.conflicts.Add(ctx, int64(merge.Conflicts))
+	}
+	if merge.Commit {
+		t.dagDepths.Record(ctx, int64(merge.Priority))
+	}
+}
+
+// addStats sets the merge statistics of the given collection to the given collection statistics.
+func (t *mergeTracker) addStats(colID uint32, colStats *client.CollectionStats) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stats, ok := t.stats[colID]
+	if !ok {
+		return
+	}
+	colStats.RemoteDeltasMerged = stats.deltas
+	colStats.LWWConflicts = stats.conflicts
+	if stats.commits > 0 {
+		colStats.AvgDAGDepth = float64(stats.depths) / float64(stats.commits)
+	}
+}
+
+// RecordRemoteMerge counts the given merge of a delta produced by another node into a document of
+// the collection in its statistics, once the transaction of the collection is committed if it has
+// one, and records the usage of the document in the quota of the collection within it.
+func (c *collection) RecordRemoteMerge(merge client.RemoteMerge) {
+	ctx := context.Background()
+	record := func() {
+		c.db.merges.record(ctx, c.colID, merge)
+	}
+	if !c.txn.HasValue() {
+		record()
+		return
+	}
+	if merge.DocKey != "" {
+		if err := c.recordQuotaUsage(ctx, c.txn.Value(), merge.DocKey); err != nil {
+			log.ErrorE(ctx, "Failed to record the quota usage of a merged document", err,
+				logging.NewKV("DocKey", merge.DocKey))
+		}
+	}
+	c.txn.Value().OnSuccess(record)
+}
+
+// Metrics returns the metrics gathered by the database, in JSON.
+func (db *db) Metrics(ctx context.Context) (string, error) {
+	return db.meter.DumpScopeMetricsString(ctx)
+}
@@ -0,0 +1,751 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"sync"
+
+	ds "github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/query"
+	"github.com/sourcenetwork/immutable"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/core"
+	"github.com/sourcenetwork/defradb/datastore"
+	"github.com/sourcenetwork/defradb/errors"
+	"github.com/sourcenetwork/defradb/events"
+	"github.com/sourcenetwork/defradb/logging"
+)
+
+const quotaEventBufferSize = 100
+
+// CollectionQuota is the storage quota of a collection, such as the one of a tenant of a
+// multi-tenant node, past which the writes to the collection are rejected, or its oldest documents
+// deleted.
+type CollectionQuota struct {
+	// Collection is the name of the collection.
+	Collection string
+	// MaxDocuments is the maximum number of documents of the collection. It is unlimited if zero.
+	MaxDocuments uint64
+	// MaxBytes is the maximum size in bytes of the field values of the documents of the
+	// collection. It is unlimited if zero.
+	MaxBytes uint64
+	// Prune deletes the oldest documents of the collection to make room for the writes exceeding
+	// the quota, rather than rejecting them.
+	Prune bool
+}
+
+// exceededBy returns true if the given number of documents or size exceed the quota.
+func (q CollectionQuota) exceededBy(documents uint64, size uint64) bool {
+	return (q.MaxDocuments > 0 && documents > q.MaxDocuments) || (q.MaxBytes > 0 && size > q.MaxBytes)
+}
+
+// WithQuotas sets the storage quotas of the collections.
+//
+// The documents created or updated locally, including by the merge patches of the update
+// requests, are checked against the quota of their collection within the transaction writing
+// them, and a `QuotaExceeded` event is published on the quota events channel for each write
+// exceeding it. The documents merged from other nodes count towards the usage of their
+// collection, but their merges are neither rejected nor pruned for it.
+//
+// The size and age of each document of the collections with a quota are recorded in the system
+// store, under two keys per document, and the number of documents and bytes of each of these
+// collections under one key, which every write to the collection reads and writes. Concurrent
+// writes to the same collection thus conflict, one of their transactions failing to commit.
+func WithQuotas(quotas ...CollectionQuota) Option {
+	return func(db *db) {
+		db.quotas = make(map[string]CollectionQuota, len(quotas))
+		for _, quota := range quotas {
+			db.quotas[quota.Collection] = quota
+		}
+	}
+}
+
+// initQuotas checks the quotas and enables the quota events channel, if any quota is set.
+func (db *db) initQuotas() error {
+	if len(db.quotas) == 0 {
+		return nil
+	}
+	for _, quota := range db.quotas {
+		if quota.MaxDocuments == 0 && quota.MaxBytes == 0 {
+			return NewErrInvalidQuota(quota.Collection)
+		}
+	}
+	db.events.Quotas = immutable.Some(events.New[events.QuotaExceeded](0, quotaEventBufferSize))
+	return nil
+}
+
+// initQuotaUsages clears the recorded usages of the collections that no longer have a quota, and
+// records the usages of the existing collections with one, if they are not.
+//
+// The usages of the collections without a quota are not kept up to date by their writes, so they
+// are recorded again from their documents if a quota is set again.
+func (db *db) initQuotaUsages(ctx context.Context) error {
+	cleared, cols, err := db.getQuotaCollections(ctx)
+	if err != nil {
+		return err
+	}
+	for _, colID := range cleared {
+		if err := db.clearQuotaUsage(ctx, colID); err != nil {
+			return err
+		}
+	}
+	for _, col := range cols {
+		if err := col.initQuotaUsage(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getQuotaCollections returns the IDs of the collections whose usages are recorded but no longer
+// have a quota, and the existing collections with a quota.
+//
+// It is read from a write transaction, as is the initialization of the database, so that opening
+// the database takes no read transaction from the pool.
+func (db *db) getQuotaCollections(ctx context.Context) ([]uint32, []*collection, error) {
+	txn, err := db.NewTxn(ctx, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer txn.Discard(ctx)
+
+	entries, err := queryRest(ctx, txn.Systemstore(), query.Query{Prefix: core.QUOTA_COLLECTION + "/"})
+	if err != nil {
+		return nil, nil, err
+	}
+	var cleared []uint32
+	for _, entry := range entries {
+		if _, ok := db.quotas[string(entry.Value)]; ok {
+			continue
+		}
+		key, err := core.NewQuotaCollectionKeyFromString(entry.Key)
+		if err != nil {
+			return nil, nil, err
+		}
+		cleared = append(cleared, key.CollectionID)
+	}
+
+	var cols []*collection
+	for name := range db.quotas {
+		col, err := db.getCollectionByName(ctx, txn, name)
+		if errors.Is(err, ds.ErrNotFound) {
+			// The usage of the collections created later is recorded at their first write.
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		cols = append(cols, col.(*collection))
+	}
+	return cleared, cols, nil
+}
+
+// clearQuotaUsage deletes the recorded usage of the collection with the given ID.
+func (db *db) clearQuotaUsage(ctx context.Context, colID uint32) error {
+	txn, err := db.NewTxn(ctx, true)
+	if err != nil {
+		return err
+	}
+	defer txn.Discard(ctx)
+
+	batch := &quotaBatch{db: db}
+	defer batch.discard(ctx)
+
+	prefixes := []string{
+		core.NewQuotaUsageKey(colID, "").ToString() + "/",
+		core.NewQuotaAgeKey(colID, 0, "").ToString() + "/",
+	}
+	for _, prefix := range prefixes {
+		err := eachEntry(ctx, txn.Systemstore(), query.Query{Prefix: prefix, KeysOnly: true}, func(entry query.Entry) error {
+			return batch.delete(ctx, ds.NewKey(entry.Key))
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if err := batch.delete(ctx, core.NewQuotaTotalKey(colID).ToDS()); err != nil {
+		return err
+	}
+	if err := batch.delete(ctx, core.NewQuotaCollectionKey(colID).ToDS()); err != nil {
+		return err
+	}
+	return batch.commit(ctx)
+}
+
+// quotaTracker tracks the collections whose usages are recorded, so that the writes are checked
+// against their quotas without scanning the collections.
+//
+// The usages are recorded in the system store, so that only the IDs of the collections are kept
+// in memory.
+type quotaTracker struct {
+	mu       sync.Mutex
+	recorded map[uint32]struct{}
+
+	// Serializes the recording of the usages of the collections.
+	recordMu sync.Mutex
+	// Serializes the updates of the recorded usages, so that the documents written in parallel by
+	// a transaction are counted once.
+	writeMu sync.Mutex
+}
+
+// isRecorded returns true if the usage of the given collection is known to be recorded.
+func (t *quotaTracker) isRecorded(colID uint32) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	_, ok := t.recorded[colID]
+	return ok
+}
+
+// setRecorded records that the usage of the given collection is recorded.
+func (t *quotaTracker) setRecorded(colID uint32) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.recorded == nil {
+		t.recorded = make(map[uint32]struct{})
+	}
+	t.recorded[colID] = struct{}{}
+}
+
+// enforceQuota checks that the collection, with the write of the document with the given key,
+// does not exceed its quota, if any.
+//
+// If it does, either the oldest other documents are deleted until it doesn't, if the quota is to
+// be pruned, or an error is returned.
+func (c *collection) enforceQuota(ctx context.Context, txn datastore.Txn, docKey string) error {
+	quota, ok := c.db.quotas[c.Name()]
+	if !ok {
+		return nil
+	}
+	documents, bytes, err := c.updateQuotaUsage(ctx, txn, docKey)
+	if err != nil {
+		return err
+	}
+	if !quota.exceededBy(documents, bytes) {
+		return nil
+	}
+
+	event := events.QuotaExceeded{
+		Collection:   c.Name(),
+		DocKey:       docKey,
+		Documents:    documents,
+		Bytes:        bytes,
+		MaxDocuments: quota.MaxDocuments,
+		MaxBytes:     quota.MaxBytes,
+	}
+
+	if quota.Prune {
+		// The oldest documents are only pruned if deleting them makes room for the write.
+		oldest, enough, err := c.oldestQuotaDocs(ctx, txn, docKey, quota, documents, bytes)
+		if err != nil {
+			return err
+		}
+		if enough {
+			for _, key := range oldest {
+				if err := c.applyDelete(ctx, txn, c.getPrimaryKey(key), client.Pruned); err != nil {
+					return err
+				}
+			}
+			event.Pruned = len(oldest)
+			txn.OnSuccess(func() {
+				c.db.publishQuotaExceeded(ctx, event)
+			})
+			return nil
+		}
+	}
+
+	c.db.publishQuotaExceeded(ctx, event)
+	return NewErrQuotaExceeded(c.Name(), docKey, event.Documents, event.Bytes)
+}
+
+// oldestQuotaDocs returns the keys of the oldest documents of the collection, other than the given
+// one, whose deletion by the given transaction brings the given usage within the given quota, and
+// true if it does.
+func (c *collection) oldestQuotaDocs(
+	ctx context.Context,
+	txn datastore.Txn,
+	except string,
+	quota CollectionQuota,
+	documents uint64,
+	bytes uint64,
+) ([]string, bool, error) {
+	var oldest []string
+	q := query.Query{
+		Prefix: core.NewQuotaAgeKey(c.colID, 0, "").ToString() + "/",
+		Orders: []query.Order{query.OrderByKey{}},
+	}
+	err := eachEntry(ctx, txn.Systemstore(), q, func(entry query.Entry) error {
+		if !quota.exceededBy(documents, bytes) {
+			return errStopIteration
+		}
+		key, err := core.NewQuotaAgeKeyFromString(entry.Key)
+		if err != nil {
+			return err
+		}
+		if key.DocKey == except {
+			return nil
+		}
+		size, n := binary.Uvarint(entry.Value)
+		if n <= 0 {
+			return NewErrInvalidQuotaUsage(entry.Key)
+		}
+		oldest = append(oldest, key.DocKey)
+		documents--
+		bytes -= size
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return oldest, !quota.exceededBy(documents, bytes), nil
+}
+
+// recordQuotaUsage records the usage of the document with the given key, as deleted or merged
+// from another node by the given transaction, in the usage of the quota of the collection, if any.
+func (c *collection) recordQuotaUsage(ctx context.Context, txn datastore.Txn, docKey string) error {
+	if _, ok := c.db.quotas[c.Name()]; !ok {
+		return nil
+	}
+	_, _, err := c.updateQuotaUsage(ctx, txn, docKey)
+	return err
+}
+
+// updateQuotaUsage records the usage of the document with the given key, as written by the given
+// transaction, and returns the number of documents and bytes of the collection with it.
+//
+// The usage of the collection is read and written by the transaction, so that it conflicts with
+// the other transactions writing to the collection.
+func (c *collection) updateQuotaUsage(
+	ctx context.Context,
+	txn datastore.Txn,
+	docKey string,
+) (documents uint64, bytes uint64, err error) {
+	if err := c.initQuotaUsage(ctx); err != nil {
+		return 0, 0, err
+	}
+	live, size, err := c.docUsage(ctx, txn, docKey)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	c.db.quotaUsages.writeMu.Lock()
+	defer c.db.quotaUsages.writeMu.Unlock()
+
+	totalKey := core.NewQuotaTotalKey(c.colID)
+	documents, bytes, err = getQuotaTotal(ctx, txn.Systemstore(), totalKey)
+	if err != nil {
+		return 0, 0, err
+	}
+	usageKey := core.NewQuotaUsageKey(c.colID, docKey)
+	prevSize, createdAt, existed, err := getQuotaDocUsage(ctx, txn.Systemstore(), usageKey)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if existed {
+		documents--
+		bytes -= prevSize
+	}
+	if live {
+		if !existed {
+			createdAt = c.db.now().UnixNano()
+		}
+		err = putQuotaDocUsage(ctx, txn.Systemstore(), c.colID, docKey, size, createdAt)
+		if err != nil {
+			return 0, 0, err
+		}
+		documents++
+		bytes += size
+	} else if existed {
+		if err := txn.Systemstore().Delete(ctx, usageKey.ToDS()); err != nil {
+			return 0, 0, err
+		}
+		ageKey := core.NewQuotaAgeKey(c.colID, createdAt, docKey)
+		if err := txn.Systemstore().Delete(ctx, ageKey.ToDS()); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	err = txn.Systemstore().Put(ctx, totalKey.ToDS(), encodeQuotaTotal(documents, bytes))
+	if err != nil {
+		return 0, 0, err
+	}
+	return documents, bytes, nil
+}
+
+// initQuotaUsage records the usages of the documents of the collection if they are not.
+func (c *collection) initQuotaUsage(ctx context.Context) error {
+	tracker := &c.db.quotaUsages
+	if tracker.isRecorded(c.colID) {
+		return nil
+	}
+	tracker.recordMu.Lock()
+	defer tracker.recordMu.Unlock()
+	if tracker.isRecorded(c.colID) {
+		return nil
+	}
+
+	recorded, err := c.hasQuotaUsages(ctx)
+	if err != nil {
+		return err
+	}
+	if !recorded {
+		if err := c.recordQuotaUsages(ctx); err != nil {
+			return err
+		}
+	}
+	tracker.setRecorded(c.colID)
+	return nil
+}
+
+// recordEmptyQuotaUsage records the usage of the collection, created by the given transaction, if
+// it has a quota.
+//
+// It is recorded by the transaction creating the collection, so that it does not conflict with
+// the transactions writing the first documents of the collection.
+func (c *collection) recordEmptyQuotaUsage(ctx context.Context, txn datastore.Txn) error {
+	if _, ok := c.db.quotas[c.Name()]; !ok {
+		return nil
+	}
+	err := txn.Systemstore().Put(ctx, core.NewQuotaTotalKey(c.colID).ToDS(), encodeQuotaTotal(0, 0))
+	if err != nil {
+		return err
+	}
+	return txn.Systemstore().Put(ctx, core.NewQuotaCollectionKey(c.colID).ToDS(), []byte(c.Name()))
+}
+
+// hasQuotaUsages returns true if the usages of the documents of the collection are recorded.
+func (c *collection) hasQuotaUsages(ctx context.Context) (bool, error) {
+	txn, err := c.db.NewTxn(ctx, true)
+	if err != nil {
+		return false, err
+	}
+	defer txn.Discard(ctx)
+
+	return txn.Systemstore().Has(ctx, core.NewQuotaCollectionKey(c.colID).ToDS())
+}
+
+// recordQuotaUsages records the usage of each document of the collection, scanning its primary
+// keys, and the sizes of the values and the time of the first commit of each of its documents.
+//
+// The documents are aged from their first commit. The ones committed before the times of commits
+// were recorded are the oldest.
+func (c *collection) recordQuotaUsages(ctx context.Context) error {
+	txn, err := c.db.NewTxn(ctx, true)
+	if err != nil {
+		return err
+	}
+	defer txn.Discard(ctx)
+
+	batch := &quotaBatch{db: c.db}
+	defer batch.discard(ctx)
+
+	var documents, bytes uint64
+	primaryPrefix := core.PrimaryDataStoreKey{
+		CollectionId: fmt.Sprint(c.colID),
+	}
+	q := query.Query{Prefix: primaryPrefix.ToString(), KeysOnly: true}
+	err = eachEntry(ctx, txn.Datastore(), q, func(entry query.Entry) error {
+		key, err := core.NewDataStoreKey(entry.Key)
+		if err != nil {
+			return err
+		}
+		live, size, err := c.docUsage(ctx, txn, key.DocKey)
+		if err != nil || !live {
+			return err
+		}
+		createdAt, err := firstCommitTime(ctx, txn, key.DocKey)
+		if err != nil {
+			return err
+		}
+		documents++
+		bytes += size
+		return batch.write(ctx, func(store datastore.DSReaderWriter) error {
+			return putQuotaDocUsage(ctx, store, c.colID, key.DocKey, size, createdAt)
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	err = batch.put(ctx, core.NewQuotaTotalKey(c.colID).ToDS(), encodeQuotaTotal(documents, bytes))
+	if err != nil {
+		return err
+	}
+	// The collection is marked last, so that its usages are recorded again if they were not all.
+	err = batch.put(ctx, core.NewQuotaCollectionKey(c.colID).ToDS(), []byte(c.Name()))
+	if err != nil {
+		return err
+	}
+	return batch.commit(ctx)
+}
+
+// firstCommitTime returns the time of the first commit of the document with the given key, in
+// nanoseconds since the Unix epoch, or zero if it was not recorded.
+func firstCommitTime(ctx context.Context, txn datastore.Txn, docKey string) (int64, error) {
+	entries, err := queryRest(ctx, txn.Systemstore(), query.Query{
+		Prefix:   core.CommitTimeKey{DocKey: docKey}.ToString() + "/",
+		KeysOnly: true,
+		Orders:   []query.Order{query.OrderByKey{}},
+		Limit:    1,
+	})
+	if err != nil || len(entries) == 0 {
+		return 0, err
+	}
+	key, err := core.NewCommitTimeKeyFromString(entries[0].Key)
+	if err != nil {
+		return 0, err
+	}
+	return key.UnixNano, nil
+}
+
+// getQuotaDocUsage returns the recorded size of the document of the given key and the time it was
+// first recorded at, and false if it is not recorded.
+func getQuotaDocUsage(
+	ctx context.Context,
+	store datastore.DSReaderWriter,
+	key core.QuotaUsageKey,
+) (size uint64, createdAt int64, found bool, err error) {
+	value, err := store.Get(ctx, key.ToDS())
+	if errors.Is(err, ds.ErrNotFound) {
+		return 0, 0, false, nil
+	}
+	if err != nil {
+		return 0, 0, false, err
+	}
+	size, createdAt, err = decodeQuotaDocUsage(key.ToString(), value)
+	return size, createdAt, err == nil, err
+}
+
+// putQuotaDocUsage records the given size of the document of the given key, first recorded at the
+// given time.
+func putQuotaDocUsage(
+	ctx context.Context,
+	store datastore.DSReaderWriter,
+	colID uint32,
+	docKey string,
+	size uint64,
+	createdAt int64,
+) error {
+	usage := binary.AppendVarint(binary.AppendUvarint(nil, size), createdAt)
+	err := store.Put(ctx, core.NewQuotaUsageKey(colID, docKey).ToDS(), usage)
+	if err != nil {
+		return err
+	}
+	return store.Put(ctx, core.NewQuotaAgeKey(colID, createdAt, docKey).ToDS(), binary.AppendUvarint(nil, size))
+}
+
+// getQuotaTotal returns the recorded number of documents and size of the collection of the given
+// key, zero if it is not recorded.
+func getQuotaTotal(
+	ctx context.Context,
+	store datastore.DSReaderWriter,
+	key core.QuotaTotalKey,
+) (documents uint64, bytes uint64, err error) {
+	value, err := store.Get(ctx, key.ToDS())
+	if errors.Is(err, ds.ErrNotFound) {
+		return 0, 0, nil
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	documents, n := binary.Uvarint(value)
+	if n <= 0 {
+		return 0, 0, NewErrInvalidQuotaUsage(key.ToString())
+	}
+	bytes, m := binary.Uvarint(value[n:])
+	if m <= 0 {
+		return 0, 0, NewErrInvalidQuotaUsage(key.ToString())
+	}
+	return documents, bytes, nil
+}
+
+// encodeQuotaTotal returns the value of the recorded usage of a collection.
+func encodeQuotaTotal(documents uint64, bytes uint64) []byte {
+	return binary.AppendUvarint(binary.AppendUvarint(nil, documents), bytes)
+}
+
+// decodeQuotaDocUsage returns the size and the creation time of the given recorded usage of a
+// document.
+func decodeQuotaDocUsage(key string, value []byte) (uint64, int64, error) {
+	size, n := binary.Uvarint(value)
+	if n <= 0 {
+		return 0, 0, NewErrInvalidQuotaUsage(key)
+	}
+	createdAt, m := binary.Varint(value[n:])
+	if m <= 0 {
+		return 0, 0, NewErrInvalidQuotaUsage(key)
+	}
+	return size, createdAt, nil
+}
+
+// quotaBatch writes to the system store in transactions of a bounded size, so that recording or
+// clearing the usages of a large collection holds a bounded number of writes in memory.
+type quotaBatch struct {
+	db  *db
+	txn datastore.Txn
+	// The number of writes of the current transaction.
+	writes int
+}
+
+// quotaBatchSize is the maximum number of writes of a transaction of a quotaBatch.
+const quotaBatchSize = 1000
+
+// write calls the given function with the system store of the current transaction, and commits it
+// if it is full.
+func (b *quotaBatch) write(ctx context.Context, fn func(store datastore.DSReaderWriter) error) error {
+	if b.txn == nil {
+		txn, err := b.db.NewTxn(ctx, false)
+		if err != nil {
+			return err
+		}
+		b.txn = txn
+	}
+	if err := fn(b.txn.Systemstore()); err != nil {
+		return err
+	}
+	b.writes++
+	if b.writes < quotaBatchSize {
+		return nil
+	}
+	return b.commit(ctx)
+}
+
+func (b *quotaBatch) put(ctx context.Context, key ds.Key, value []byte) error {
+	return b.write(ctx, func(store datastore.DSReaderWriter) error {
+		return store.Put(ctx, key, value)
+	})
+}
+
+func (b *quotaBatch) delete(ctx context.Context, key ds.Key) error {
+	return b.write(ctx, func(store datastore.DSReaderWriter) error {
+		return store.Delete(ctx, key)
+	})
+}
+
+// commit commits the current transaction, if any.
+func (b *quotaBatch) commit(ctx context.Context) error {
+	if b.txn == nil {
+		return nil
+	}
+	txn := b.txn
+	b.txn = nil
+	b.writes = 0
+	return txn.Commit(ctx)
+}
+
+// discard discards the current transaction, if any.
+func (b *quotaBatch) discard(ctx context.Context) {
+	if b.txn != nil {
+		b.txn.Discard(ctx)
+		b.txn = nil
+	}
+}
+
+// docUsage returns whether the document with the given key exists and has not been deleted, and
+// the size in bytes of its stored field values.
+func (c *collection) docUsage(ctx context.Context, txn datastore.Txn, docKey string) (bool, uint64, error) {
+	found, isDeleted, err := c.exists(ctx, txn, c.getPrimaryKey(docKey))
+	if err != nil || !found || isDeleted {
+		return false, 0, err
+	}
+
+	prefix := core.DataStoreKey{
+		CollectionID: fmt.Sprint(c.colID),
+		InstanceType: core.ValueKey,
+		DocKey:       docKey,
+	}
+	var size uint64
+	q := query.Query{
+		Prefix:       prefix.ToString() + "/",
+		KeysOnly:     true,
+		ReturnsSizes: true,
+	}
+	err = eachEntry(ctx, txn.Datastore(), q, func(entry query.Entry) error {
+		size += uint64(entry.Size)
+		return nil
+	})
+	if err != nil {
+		return false, 0, err
+	}
+	return true, size, nil
+}
+
+// errStopIteration stops an iteration of eachEntry without an error.
+var errStopIteration = errors.New("stop iteration")
+
+// eachEntry calls the given function with each entry of the given query of the given store, until
+// it returns an error.
+func eachEntry(
+	ctx context.Context,
+	store datastore.DSReaderWriter,
+	q query.Query,
+	fn func(entry query.Entry) error,
+) (err error) {
+	results, err := store.Query(ctx, q)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := results.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+	for result := range results.Next() {
+		if result.Error != nil {
+			return result.Error
+		}
+		if err := fn(result.Entry); err != nil {
+			if errors.Is(err, errStopIteration) {
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// queryRest returns all the entries of the given query of the given store.
+func queryRest(ctx context.Context, store datastore.DSReaderWriter, q query.Query) ([]query.Entry, error) {
+	results, err := store.Query(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := results.Rest()
+	if closeErr := results.Close(); closeErr != nil {
+		return nil, closeErr
+	}
+	return entries, err
+}
+
+// publishQuotaExceeded logs the given quota event and publishes it on the quota events channel.
+func (db *db) publishQuotaExceeded(ctx context.Context, event events.QuotaExceeded) {
+	kvs := []logging.KV{
+		logging.NewKV("Collection", event.Collection),
+		logging.NewKV("DocKey", event.DocKey),
+		logging.NewKV("Documents", event.Documents),
+		logging.NewKV("Bytes", event.Bytes),
+	}
+	if event.Pruned > 0 {
+		kvs = append(kvs, logging.NewKV("Pruned", event.Pruned))
+		log.Info(ctx, "Pruned the oldest documents exceeding the quota", kvs...)
+	} else {
+		log.Info(ctx, "Rejected a write exceeding the quota", kvs...)
+	}
+	if db.events.Quotas.HasValue() {
+		db.events.Quotas.Value().Publish(event)
+	}
+}
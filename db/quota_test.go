@@ -0,0 +1,302 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	badger "github.com/dgraph-io/badger/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/datastore"
+	badgerds "github.com/sourcenetwork/defradb/datastore/badger/v3"
+	"github.com/sourcenetwork/defradb/errors"
+	"github.com/sourcenetwork/defradb/events"
+)
+
+func TestQuotaRejectsWritesExceedingMaxDocuments(t *testing.T) {
+	ctx := context.Background()
+	db, err := newMemoryDB(ctx, WithQuotas(CollectionQuota{Collection: "logs", MaxDocuments: 2}))
+	require.NoError(t, err)
+	defer db.Close(ctx)
+
+	err = db.AddSchema(ctx, `type logs { Message: String }`)
+	require.NoError(t, err)
+	sub, err := db.Events().Quotas.Value().Subscribe()
+	require.NoError(t, err)
+
+	for _, message := range []string{"first", "second"} {
+		res := db.ExecRequest(ctx, `mutation { create_logs(data: "{\"Message\": \"`+message+`\"}") { _key } }`)
+		require.Empty(t, res.GQL.Errors)
+	}
+	res := db.ExecRequest(ctx, `mutation { create_logs(data: "{\"Message\": \"third\"}") { _key } }`)
+	require.Len(t, res.GQL.Errors, 1)
+	assert.Equal(t, errors.CodeQuotaExceeded, errors.CodeOf(res.GQL.Errors[0]))
+
+	select {
+	case event := <-sub:
+		assert.Equal(t, "logs", event.Collection)
+		assert.Equal(t, uint64(3), event.Documents)
+		assert.Equal(t, uint64(2), event.MaxDocuments)
+		assert.Zero(t, event.Pruned)
+	case <-time.After(time.Second):
+		t.Fatal("quota event not published")
+	}
+
+	res = db.ExecRequest(ctx, `query { logs { Message } }`)
+	require.Empty(t, res.GQL.Errors)
+	assert.Len(t, res.GQL.Data, 2)
+}
+
+func TestQuotaPrunesOldestDocuments(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	db, err := newMemoryDB(
+		ctx,
+		WithClock(func() time.Time { return now }),
+		WithQuotas(CollectionQuota{Collection: "logs", MaxDocuments: 2, Prune: true}),
+	)
+	require.NoError(t, err)
+	defer db.Close(ctx)
+
+	err = db.AddSchema(ctx, `type logs { Message: String }`)
+	require.NoError(t, err)
+	sub, err := db.Events().Quotas.Value().Subscribe()
+	require.NoError(t, err)
+
+	for _, message := range []string{"first", "second", "third"} {
+		now = now.Add(time.Minute)
+		res := db.ExecRequest(ctx, `mutation { create_logs(data: "{\"Message\": \"`+message+`\"}") { _key } }`)
+		require.Empty(t, res.GQL.Errors)
+	}
+
+	var event events.QuotaExceeded
+	select {
+	case event = <-sub:
+	case <-time.After(time.Second):
+		t.Fatal("quota event not published")
+	}
+	assert.Equal(t, 1, event.Pruned)
+
+	res := db.ExecRequest(ctx, `query { logs(order: {Message: ASC}) { Message } }`)
+	require.Empty(t, res.GQL.Errors)
+	assert.Equal(t, []map[string]any{{"Message": "second"}, {"Message": "third"}}, res.GQL.Data)
+}
+
+func TestQuotaPrunesOldestDocumentsOfAppendOnlyCollection(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	db, err := newMemoryDB(
+		ctx,
+		WithClock(func() time.Time { return now }),
+		WithQuotas(CollectionQuota{Collection: "logs", MaxDocuments: 1, Prune: true}),
+	)
+	require.NoError(t, err)
+	defer db.Close(ctx)
+
+	err = db.AddSchema(ctx, `type logs @appendOnly { Message: String }`)
+	require.NoError(t, err)
+
+	for _, message := range []string{"first", "second"} {
+		now = now.Add(time.Minute)
+		res := db.ExecRequest(ctx, `mutation { create_logs(data: "{\"Message\": \"`+message+`\"}") { _key } }`)
+		require.Empty(t, res.GQL.Errors)
+	}
+
+	res := db.ExecRequest(ctx, `query { logs { Message } }`)
+	require.Empty(t, res.GQL.Errors)
+	assert.Equal(t, []map[string]any{{"Message": "second"}}, res.GQL.Data)
+}
+
+func TestQuotaRejectsWritesExceedingMaxBytes(t *testing.T) {
+	ctx := context.Background()
+	db, err := newMemoryDB(ctx, WithQuotas(CollectionQuota{Collection: "logs", MaxBytes: 64}))
+	require.NoError(t, err)
+	defer db.Close(ctx)
+
+	err = db.AddSchema(ctx, `type logs { Message: String }`)
+	require.NoError(t, err)
+
+	res := db.ExecRequest(ctx, `mutation { create_logs(data: "{\"Message\": \"short\"}") { _key } }`)
+	require.Empty(t, res.GQL.Errors)
+	key := res.GQL.Data.([]map[string]any)[0]["_key"].(string)
+
+	long := `mutation { update_logs(id: "` + key + `", data: "{\"Message\": \"` +
+		"this message is longer than the size of the quota of the collection, in bytes" +
+		`\"}") { _key } }`
+	res = db.ExecRequest(ctx, long)
+	require.Len(t, res.GQL.Errors, 1)
+	assert.ErrorIs(t, res.GQL.Errors[0], ErrQuotaExceeded)
+}
+
+func TestQuotaCountsDeletedAndMergedDocuments(t *testing.T) {
+	ctx := context.Background()
+	db, err := newMemoryDB(ctx, WithQuotas(CollectionQuota{Collection: "logs", MaxDocuments: 2}))
+	require.NoError(t, err)
+	defer db.Close(ctx)
+
+	err = db.AddSchema(ctx, `type logs { Message: String }`)
+	require.NoError(t, err)
+	col, err := db.GetCollectionByName(ctx, "logs")
+	require.NoError(t, err)
+
+	create := func(message string) []error {
+		res := db.ExecRequest(ctx, `mutation { create_logs(data: "{\"Message\": \"`+message+`\"}") { _key } }`)
+		return res.GQL.Errors
+	}
+	require.Empty(t, create("first"))
+	require.Empty(t, create("second"))
+
+	// Deleting a document makes room for another.
+	res := db.ExecRequest(ctx, `mutation { delete_logs(filter: {Message: {_eq: "first"}}) { _key } }`)
+	require.Empty(t, res.GQL.Errors)
+	require.Empty(t, create("third"))
+
+	// The documents merged from other nodes are never rejected, but count towards the usage.
+	other, err := newMemoryDB(ctx)
+	require.NoError(t, err)
+	defer other.Close(ctx)
+	err = other.AddSchema(ctx, `type logs { Message: String }`)
+	require.NoError(t, err)
+	offline, err := other.GetCollectionByName(ctx, "logs")
+	require.NoError(t, err)
+	doc, err := client.NewDocFromJSON([]byte(`{"Message": "merged"}`))
+	require.NoError(t, err)
+	err = offline.Create(ctx, doc)
+	require.NoError(t, err)
+	_, err = col.MergeBlocks(ctx, getCommitBlocks(t, ctx, offline, doc.Head()))
+	require.NoError(t, err)
+
+	res = db.ExecRequest(ctx, `mutation { delete_logs(filter: {Message: {_eq: "second"}}) { _key } }`)
+	require.Empty(t, res.GQL.Errors)
+	errs := create("fourth")
+	require.Len(t, errs, 1)
+	assert.ErrorIs(t, errs[0], ErrQuotaExceeded)
+}
+
+func TestQuotaCountsTheWritesOfPendingTransaction(t *testing.T) {
+	ctx := context.Background()
+	db, err := newMemoryDB(ctx, WithQuotas(CollectionQuota{Collection: "logs", MaxDocuments: 2}))
+	require.NoError(t, err)
+	defer db.Close(ctx)
+
+	err = db.AddSchema(ctx, `type logs { Message: String }`)
+	require.NoError(t, err)
+	col, err := db.GetCollectionByName(ctx, "logs")
+	require.NoError(t, err)
+
+	create := func(txn datastore.Txn, message string) error {
+		doc, err := client.NewDocFromJSON([]byte(`{"Message": "` + message + `"}`))
+		require.NoError(t, err)
+		return col.WithTxn(txn).Create(ctx, doc)
+	}
+
+	// The writes of a discarded transaction are not counted.
+	txn, err := db.NewTxn(ctx, false)
+	require.NoError(t, err)
+	require.NoError(t, create(txn, "first"))
+	require.NoError(t, create(txn, "second"))
+	txn.Discard(ctx)
+
+	txn, err = db.NewTxn(ctx, false)
+	require.NoError(t, err)
+	defer txn.Discard(ctx)
+	require.NoError(t, create(txn, "first"))
+	require.NoError(t, create(txn, "second"))
+	assert.ErrorIs(t, create(txn, "third"), ErrQuotaExceeded)
+}
+
+func TestQuotaConflictsConcurrentWrites(t *testing.T) {
+	ctx := context.Background()
+	db, err := newMemoryDB(ctx, WithQuotas(CollectionQuota{Collection: "logs", MaxDocuments: 1}))
+	require.NoError(t, err)
+	defer db.Close(ctx)
+
+	err = db.AddSchema(ctx, `type logs { Message: String }`)
+	require.NoError(t, err)
+	col, err := db.GetCollectionByName(ctx, "logs")
+	require.NoError(t, err)
+
+	create := func(txn datastore.Txn, message string) error {
+		doc, err := client.NewDocFromJSON([]byte(`{"Message": "` + message + `"}`))
+		require.NoError(t, err)
+		return col.WithTxn(txn).Create(ctx, doc)
+	}
+
+	// Each transaction is within the quota on its own, but not both.
+	first, err := db.NewTxn(ctx, false)
+	require.NoError(t, err)
+	defer first.Discard(ctx)
+	second, err := db.NewTxn(ctx, false)
+	require.NoError(t, err)
+	defer second.Discard(ctx)
+	require.NoError(t, create(first, "first"))
+	require.NoError(t, create(second, "second"))
+
+	require.NoError(t, first.Commit(ctx))
+	require.Error(t, second.Commit(ctx))
+
+	res := db.ExecRequest(ctx, `query { logs { Message } }`)
+	require.Empty(t, res.GQL.Errors)
+	assert.Equal(t, []map[string]any{{"Message": "first"}}, res.GQL.Data)
+}
+
+func TestQuotaWithoutLimit(t *testing.T) {
+	ctx := context.Background()
+	_, err := newMemoryDB(ctx, WithQuotas(CollectionQuota{Collection: "logs"}))
+	assert.ErrorIs(t, err, ErrInvalidQuota)
+}
+
+func TestQuotaUsageIsPersisted(t *testing.T) {
+	ctx := context.Background()
+	path := t.TempDir()
+	opts := badgerds.Options{Options: badger.DefaultOptions(path)}
+	quota := WithQuotas(CollectionQuota{Collection: "logs", MaxDocuments: 2})
+
+	open := func(options ...Option) *implicitTxnDB {
+		rootstore, err := badgerds.NewDatastore(path, &opts)
+		require.NoError(t, err)
+		db, err := newDB(ctx, rootstore, options...)
+		require.NoError(t, err)
+		return db
+	}
+	create := func(db *implicitTxnDB, message string) []error {
+		res := db.ExecRequest(ctx, `mutation { create_logs(data: "{\"Message\": \"`+message+`\"}") { _key } }`)
+		return res.GQL.Errors
+	}
+
+	// The documents written before the quota was set count towards its usage.
+	db := open()
+	err := db.AddSchema(ctx, `type logs { Message: String }`)
+	require.NoError(t, err)
+	require.Empty(t, create(db, "first"))
+	require.Empty(t, create(db, "second"))
+	db.Close(ctx)
+
+	db = open(quota)
+	errs := create(db, "third")
+	require.Len(t, errs, 1)
+	assert.ErrorIs(t, errs[0], ErrQuotaExceeded)
+	res := db.ExecRequest(ctx, `mutation { delete_logs(filter: {Message: {_eq: "first"}}) { _key } }`)
+	require.Empty(t, res.GQL.Errors)
+	db.Close(ctx)
+
+	db = open(quota)
+	defer db.Close(ctx)
+	require.Empty(t, create(db, "third"))
+	errs = create(db, "fourth")
+	require.Len(t, errs, 1)
+	assert.ErrorIs(t, errs[0], ErrQuotaExceeded)
+}
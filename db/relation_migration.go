@@ -0,0 +1,280 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/datastore"
+	"github.com/sourcenetwork/defradb/errors"
+)
+
+// MigrateRelations rewrites the relation ID fields of the documents to match the current schema,
+// in batches of documents each migrated in its own transaction.
+func (db *db) MigrateRelations(
+	ctx context.Context,
+	opts client.MigrateRelationsOptions,
+) (<-chan client.MigrateRelationsProgress, error) {
+	if opts.BatchSize <= 0 {
+		return nil, NewErrInvalidBatchSize(opts.BatchSize)
+	}
+
+	cols, err := db.getMigratedCollections(ctx, opts.Collections)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := make(chan client.MigrateRelationsProgress)
+	go func() {
+		defer close(progress)
+
+		send := func(report client.MigrateRelationsProgress) bool {
+			select {
+			case <-ctx.Done():
+				return false
+			case progress <- report:
+				return true
+			}
+		}
+
+		report := client.MigrateRelationsProgress{}
+		for _, col := range cols {
+			report.Collection = col.Name()
+			err := col.migrateRelations(ctx, opts, &report, send)
+			if err != nil {
+				report.Err = err
+				send(report)
+				return
+			}
+		}
+		report.Done = true
+		send(report)
+	}()
+
+	return progress, nil
+}
+
+// getMigratedCollections returns the collections of the given names, or all the collections if
+// none are given.
+func (db *db) getMigratedCollections(ctx context.Context, names []string) ([]*collection, error) {
+	txn, err := db.NewTxn(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	defer txn.Discard(ctx)
+
+	var cols []client.Collection
+	if len(names) == 0 {
+		cols, err = db.getAllCollections(ctx, txn)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, name := range names {
+		col, err := db.getCollectionByName(ctx, txn, name)
+		if err != nil {
+			return nil, err
+		}
+		cols = append(cols, col)
+	}
+
+	migrated := make([]*collection, len(cols))
+	for i, col := range cols {
+		migrated[i] = col.(*collection)
+	}
+	return migrated, nil
+}
+
+// migrateRelations migrates the relation ID fields of the documents of the collection, adding its
+// progress to the given report, which is sent after each batch.
+func (c *collection) migrateRelations(
+	ctx context.Context,
+	opts client.MigrateRelationsOptions,
+	report *client.MigrateRelationsProgress,
+	send func(client.MigrateRelationsProgress) bool,
+) error {
+	var fields []client.FieldDescription
+	for _, field := range c.desc.Schema.Fields {
+		if field.RelationType == client.Relation_Type_INTERNAL_ID {
+			fields = append(fields, field)
+		}
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	keys, err := c.GetAllDocKeys(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// The keys are drained so that the goroutine listing them is not left blocked.
+		cancel()
+		for range keys {
+		}
+	}()
+
+	batch := make([]client.DocKey, 0, opts.BatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		migrated, skipped, err := c.migrateRelationsBatch(ctx, batch, fields, opts.Keys)
+		if err != nil {
+			return err
+		}
+		report.Scanned += len(batch)
+		report.Migrated += migrated
+		report.Skipped += skipped
+		batch = batch[:0]
+		if !send(*report) {
+			return ctx.Err()
+		}
+		return nil
+	}
+
+	for res := range keys {
+		if res.Err != nil {
+			return res.Err
+		}
+		batch = append(batch, res.Key)
+		if len(batch) == opts.BatchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return flush()
+}
+
+// migrateRelationsBatch migrates the given relation ID fields of the documents of the given keys
+// in a single transaction, and returns the number of relation IDs migrated and skipped.
+func (c *collection) migrateRelationsBatch(
+	ctx context.Context,
+	keys []client.DocKey,
+	fields []client.FieldDescription,
+	rekeyed map[string]string,
+) (int, int, error) {
+	txn, err := c.db.NewTxn(ctx, false)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer txn.Discard(ctx)
+
+	col := c.WithTxn(txn).(*collection)
+	var migrated, skipped int
+	for _, key := range keys {
+		doc, err := col.Get(ctx, key, false)
+		if err != nil {
+			if errors.Is(err, client.ErrDocumentNotFound) {
+				// The document has been deleted since its key was listed.
+				continue
+			}
+			return 0, 0, err
+		}
+		// Only the migrated fields are to be written.
+		doc.Clean()
+
+		changed := false
+		for _, field := range fields {
+			value, err := doc.Get(field.Name)
+			if err != nil {
+				// The relation is not set.
+				continue
+			}
+			relatedKey, ok := value.(string)
+			if !ok || relatedKey == "" {
+				continue
+			}
+			if newKey, isRekeyed := rekeyed[relatedKey]; isRekeyed {
+				relatedKey = newKey
+			}
+
+			relationField, isSecondary := col.isSecondaryIDField(field)
+			if !isSecondary {
+				if relatedKey != value {
+					if err := doc.Set(field.Name, relatedKey); err != nil {
+						return 0, 0, err
+					}
+					changed = true
+					migrated++
+				}
+				continue
+			}
+
+			moved, err := col.moveSecondaryID(ctx, txn, relationField, key.String(), relatedKey)
+			if err != nil {
+				return 0, 0, err
+			}
+			if !moved {
+				skipped++
+				continue
+			}
+			if err := doc.SetAs(field.Name, nil, client.LWW_REGISTER); err != nil {
+				return 0, 0, err
+			}
+			changed = true
+			migrated++
+		}
+
+		if changed {
+			if err := col.Update(ctx, doc); err != nil {
+				return 0, 0, err
+			}
+		}
+	}
+
+	return migrated, skipped, txn.Commit(ctx)
+}
+
+// moveSecondaryID sets the relation ID of the primary document of the given key to the document
+// of the given key, which holds it on the secondary side of the given relation field.
+//
+// It returns false if the primary document does not exist or already references another document.
+func (c *collection) moveSecondaryID(
+	ctx context.Context,
+	txn datastore.Txn,
+	relationField client.FieldDescription,
+	docKey string,
+	primaryKey string,
+) (bool, error) {
+	primaryDocKey, err := client.NewDocKeyFromString(primaryKey)
+	if err != nil {
+		return false, nil
+	}
+
+	primaryCol, err := c.db.getCollectionByName(ctx, txn, relationField.Schema)
+	if err != nil {
+		return false, err
+	}
+	primaryCol = primaryCol.WithTxn(txn)
+	primaryField, _ := primaryCol.Description().GetRelation(relationField.RelationName)
+
+	primaryDoc, err := primaryCol.Get(ctx, primaryDocKey, false)
+	if err != nil {
+		if errors.Is(err, client.ErrDocumentNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	current, err := primaryDoc.Get(primaryField.Name + "_id")
+	if err == nil && current != nil && current != "" {
+		return current == docKey, nil
+	}
+
+	return true, c.patchPrimaryDoc(ctx, txn, relationField, docKey, primaryKey)
+}
@@ -0,0 +1,183 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/client"
+)
+
+const migrationTestSchema = `
+	type book {
+		name: String
+		author: author
+	}
+
+	type author {
+		name: String
+		published: book @primary
+	}
+`
+
+func createMigrationTestDoc(t *testing.T, ctx context.Context, col client.Collection, json string) *client.Document {
+	doc, err := client.NewDocFromJSON([]byte(json))
+	require.NoError(t, err)
+	require.NoError(t, col.Create(ctx, doc))
+	return doc
+}
+
+// setRelationID sets the given relation ID field of the given document as it would have been
+// written under a schema in which its collection was the primary side of the relation.
+func setRelationID(
+	t *testing.T,
+	ctx context.Context,
+	db *implicitTxnDB,
+	col client.Collection,
+	key client.DocKey,
+	field string,
+	value string,
+) {
+	txn, err := db.NewTxn(ctx, false)
+	require.NoError(t, err)
+	defer txn.Discard(ctx)
+
+	c := col.(*collection)
+	fieldKey, ok := c.tryGetFieldKey(c.getPrimaryKeyFromDocKey(key), field)
+	require.True(t, ok)
+	_, _, err = c.saveDocValue(ctx, txn, fieldKey, client.NewCBORValue(client.LWW_REGISTER, value))
+	require.NoError(t, err)
+	require.NoError(t, txn.Commit(ctx))
+}
+
+func collectMigrationProgress(t *testing.T, progress <-chan client.MigrateRelationsProgress) []client.MigrateRelationsProgress {
+	var reports []client.MigrateRelationsProgress
+	for report := range progress {
+		require.NoError(t, report.Err)
+		reports = append(reports, report)
+	}
+	return reports
+}
+
+func TestMigrateRelationsMovesSecondaryIDs(t *testing.T) {
+	ctx := context.Background()
+	db, err := newMemoryDB(ctx)
+	require.NoError(t, err)
+	defer db.Close(ctx)
+	require.NoError(t, db.AddSchema(ctx, migrationTestSchema))
+
+	books, err := db.GetCollectionByName(ctx, "book")
+	require.NoError(t, err)
+	authors, err := db.GetCollectionByName(ctx, "author")
+	require.NoError(t, err)
+
+	book1 := createMigrationTestDoc(t, ctx, books, `{"name": "Painted House"}`)
+	book2 := createMigrationTestDoc(t, ctx, books, `{"name": "Theif Lord"}`)
+	book3 := createMigrationTestDoc(t, ctx, books, `{"name": "The Client"}`)
+	author1 := createMigrationTestDoc(t, ctx, authors, `{"name": "John Grisham"}`)
+	author2 := createMigrationTestDoc(t, ctx, authors, `{"name": "Cornelia Funke"}`)
+
+	setRelationID(t, ctx, db, books, book1.Key(), "author_id", author1.Key().String())
+	// The author of the second book already references the third book.
+	setRelationID(t, ctx, db, authors, author2.Key(), "published_id", book3.Key().String())
+	setRelationID(t, ctx, db, books, book2.Key(), "author_id", author2.Key().String())
+
+	progress, err := db.MigrateRelations(ctx, client.MigrateRelationsOptions{
+		Collections: []string{"book"},
+		BatchSize:   2,
+	})
+	require.NoError(t, err)
+	reports := collectMigrationProgress(t, progress)
+
+	// The keys are migrated in their order, so the batches are only known by their size.
+	require.Len(t, reports, 3)
+	assert.Equal(t, 2, reports[0].Scanned)
+	assert.Equal(
+		t,
+		client.MigrateRelationsProgress{Collection: "book", Scanned: 3, Migrated: 1, Skipped: 1},
+		reports[1],
+	)
+	assert.Equal(
+		t,
+		client.MigrateRelationsProgress{Collection: "book", Scanned: 3, Migrated: 1, Skipped: 1, Done: true},
+		reports[2],
+	)
+
+	author, err := authors.Get(ctx, author1.Key(), false)
+	require.NoError(t, err)
+	publishedID, err := author.Get("published_id")
+	require.NoError(t, err)
+	assert.Equal(t, book1.Key().String(), publishedID)
+
+	book, err := books.Get(ctx, book1.Key(), false)
+	require.NoError(t, err)
+	authorID, err := book.Get("author_id")
+	require.NoError(t, err)
+	assert.Nil(t, authorID)
+
+	book, err = books.Get(ctx, book2.Key(), false)
+	require.NoError(t, err)
+	authorID, err = book.Get("author_id")
+	require.NoError(t, err)
+	assert.Equal(t, author2.Key().String(), authorID)
+}
+
+func TestMigrateRelationsRewritesRekeyedIDs(t *testing.T) {
+	ctx := context.Background()
+	db, err := newMemoryDB(ctx)
+	require.NoError(t, err)
+	defer db.Close(ctx)
+	require.NoError(t, db.AddSchema(ctx, migrationTestSchema))
+
+	books, err := db.GetCollectionByName(ctx, "book")
+	require.NoError(t, err)
+	authors, err := db.GetCollectionByName(ctx, "author")
+	require.NoError(t, err)
+
+	book := createMigrationTestDoc(t, ctx, books, `{"name": "Painted House"}`)
+	author := createMigrationTestDoc(
+		t, ctx, authors,
+		`{"name": "John Grisham", "published_id": "bae-fd541c25-229e-5280-b44b-e5c2af3e374d"}`,
+	)
+
+	progress, err := db.MigrateRelations(ctx, client.MigrateRelationsOptions{
+		BatchSize: 10,
+		Keys:      map[string]string{"bae-fd541c25-229e-5280-b44b-e5c2af3e374d": book.Key().String()},
+	})
+	require.NoError(t, err)
+	reports := collectMigrationProgress(t, progress)
+
+	require.NotEmpty(t, reports)
+	last := reports[len(reports)-1]
+	assert.True(t, last.Done)
+	assert.Equal(t, 2, last.Scanned)
+	assert.Equal(t, 1, last.Migrated)
+
+	doc, err := authors.Get(ctx, author.Key(), false)
+	require.NoError(t, err)
+	publishedID, err := doc.Get("published_id")
+	require.NoError(t, err)
+	assert.Equal(t, book.Key().String(), publishedID)
+}
+
+func TestMigrateRelationsWithInvalidBatchSize(t *testing.T) {
+	ctx := context.Background()
+	db, err := newMemoryDB(ctx)
+	require.NoError(t, err)
+	defer db.Close(ctx)
+
+	_, err = db.MigrateRelations(ctx, client.MigrateRelationsOptions{})
+	assert.ErrorIs(t, err, ErrInvalidBatchSize)
+}
@@ -13,15 +13,37 @@ package db
 import (
 	"context"
 
+	"github.com/graphql-go/graphql/language/ast"
+
 	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/client/request"
+	corecrdt "github.com/sourcenetwork/defradb/core/crdt"
 	"github.com/sourcenetwork/defradb/datastore"
+	"github.com/sourcenetwork/defradb/logging"
 	"github.com/sourcenetwork/defradb/planner"
 )
 
-// execRequest executes a request against the database.
-func (db *db) execRequest(ctx context.Context, request string, txn datastore.Txn) *client.RequestResult {
+// RequestForwarder forwards requests to an upstream database.
+type RequestForwarder interface {
+	// ForwardRequest executes the given request, with the variables of the given context, on the
+	// upstream database.
+	//
+	// It is executed outside of the transaction of the request, if any.
+	ForwardRequest(ctx context.Context, request string) *client.RequestResult
+}
+
+// execRequest executes a request, of the given AST, against the database.
+func (db *db) execRequest(
+	ctx context.Context,
+	request string,
+	ast *ast.Document,
+	txn datastore.Txn,
+) *client.RequestResult {
+	// The deltas of the encrypted collections are decrypted when replayed.
+	ctx = corecrdt.WithKeyring(ctx, db.keyring)
 	res := &client.RequestResult{}
-	ast, err := db.parser.BuildRequestAST(request)
+	// The values of the encrypted fields are only readable and writable with their key.
+	ctx, err := db.withFieldKeyring(ctx)
 	if err != nil {
 		res.GQL.Errors = []error{err}
 		return res
@@ -30,12 +52,30 @@ func (db *db) execRequest(ctx context.Context, request string, txn datastore.Txn
 		return db.parser.ExecuteIntrospection(request)
 	}
 
-	parsedRequest, errors := db.parser.Parse(ast)
+	// Requests querying collections the database does not hold are executed upstream as a whole.
+	if fields := db.parser.UnknownQueryFields(ast); db.forwarder != nil && len(fields) > 0 {
+		log.Debug(ctx, "Forwarding request upstream", logging.NewKV("Fields", fields))
+		return db.forwarder.ForwardRequest(ctx, request)
+	}
+
+	parsedRequest, errors := db.parser.Parse(ast, client.RequestVariablesFromContext(ctx))
 	if len(errors) > 0 {
 		res.GQL.Errors = errors
 		return res
 	}
 
+	if err := db.checkRequestCost(ctx, txn, parsedRequest); err != nil {
+		res.GQL.Errors = []error{err}
+		return res
+	}
+
+	for _, hook := range db.requestHooks {
+		if err := hook(ctx, parsedRequest); err != nil {
+			res.GQL.Errors = []error{err}
+			return res
+		}
+	}
+
 	pub, subRequest, err := db.checkForClientSubscriptions(parsedRequest)
 	if err != nil {
 		res.GQL.Errors = []error{err}
@@ -48,18 +88,64 @@ func (db *db) execRequest(ctx context.Context, request string, txn datastore.Txn
 		return res
 	}
 
+	id, ctx := db.requests.track(ctx, request)
 	planner := planner.New(ctx, db.WithTxn(txn), txn)
+	planner.OnPlan(func(summary string) {
+		db.requests.setPlan(id, summary)
+	})
+	planner.SetDocumentCache(db.docCache)
 
 	results, err := planner.RunRequest(ctx, parsedRequest)
+	if cancelled := db.requests.done(id); cancelled {
+		res.GQL.Errors = []error{NewErrRequestCancelled(id)}
+		return res
+	}
 	if err != nil {
 		res.GQL.Errors = []error{err}
 		return res
 	}
 
+	if client.MaskedResultsFromContext(ctx) && len(parsedRequest.Queries) > 0 {
+		if err := db.maskQueryResults(ctx, txn, parsedRequest.Queries[0], results); err != nil {
+			res.GQL.Errors = []error{err}
+			return res
+		}
+	}
+
 	res.GQL.Data = results
 	return res
 }
 
+// maskQueryResults masks the given results of the given query, selecting the documents of a
+// collection, according to the masking rules of the database.
+func (db *db) maskQueryResults(
+	ctx context.Context,
+	txn datastore.Txn,
+	query *request.OperationDefinition,
+	results []map[string]any,
+) error {
+	if len(query.Selections) == 0 {
+		return nil
+	}
+	sel, ok := query.Selections[0].(*request.Select)
+	if !ok || sel.Root != request.ObjectSelection {
+		return nil
+	}
+	return db.maskResults(ctx, txn, sel.Name, sel.Fields, results)
+}
+
+// isReadOnlyRequest returns true if the request of the given AST has no mutation, in which case it
+// can be executed on a read only transaction.
+func isReadOnlyRequest(doc *ast.Document) bool {
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if ok && op.Operation == ast.OperationTypeMutation {
+			return false
+		}
+	}
+	return true
+}
+
 // ExecIntrospection executes an introspection request against the database.
 func (db *db) ExecIntrospection(request string) *client.RequestResult {
 	return db.parser.ExecuteIntrospection(request)
@@ -0,0 +1,164 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/request/graphql/parser"
+)
+
+// waitForNextInstant makes sure the commits made after it are at a later time than the
+// returned one.
+func waitForNextInstant() time.Time {
+	t := time.Now()
+	time.Sleep(10 * time.Millisecond)
+	return t
+}
+
+func atTimeRequest(t time.Time, fields string) string {
+	return fmt.Sprintf(`query { users(atTime: %q) { %s } }`, t.Format(time.RFC3339Nano), fields)
+}
+
+// assertSingleUser asserts that the given results contain a single user with the given
+// values, regardless of the integer type the age was decoded to.
+func assertSingleUser(t *testing.T, data any, name string, age int) {
+	users, ok := data.([]map[string]any)
+	require.True(t, ok)
+	require.Len(t, users, 1)
+	assert.Equal(t, name, users[0]["Name"])
+	assert.EqualValues(t, age, users[0]["Age"])
+}
+
+func TestExecRequestWithAtTime(t *testing.T) {
+	ctx := context.Background()
+	db, err := newMemoryDB(ctx)
+	require.NoError(t, err)
+	defer db.Close(ctx)
+
+	err = db.AddSchema(ctx, `type users { Name: String Age: Int }`)
+	require.NoError(t, err)
+
+	beforeCreate := waitForNextInstant()
+
+	res := db.ExecRequest(ctx, `mutation { create_users(data: "{\"Name\": \"John\", \"Age\": 21}") { _key } }`)
+	require.Empty(t, res.GQL.Errors)
+	docKey := res.GQL.Data.([]map[string]any)[0]["_key"].(string)
+
+	afterCreate := waitForNextInstant()
+
+	res = db.ExecRequest(
+		ctx,
+		fmt.Sprintf(`mutation { update_users(id: %q, data: "{\"Age\": 22}") { _key } }`, docKey),
+	)
+	require.Empty(t, res.GQL.Errors)
+
+	afterUpdate := waitForNextInstant()
+
+	res = db.ExecRequest(ctx, atTimeRequest(beforeCreate, "Name Age"))
+	require.Empty(t, res.GQL.Errors)
+	assert.Equal(t, []map[string]any{}, res.GQL.Data)
+
+	res = db.ExecRequest(ctx, atTimeRequest(afterCreate, "Name Age"))
+	require.Empty(t, res.GQL.Errors)
+	assertSingleUser(t, res.GQL.Data, "John", 21)
+
+	res = db.ExecRequest(ctx, atTimeRequest(afterUpdate, "Name Age"))
+	require.Empty(t, res.GQL.Errors)
+	assertSingleUser(t, res.GQL.Data, "John", 22)
+}
+
+func TestExecRequestWithAtTimeAndDeletedDocument(t *testing.T) {
+	ctx := context.Background()
+	db, err := newMemoryDB(ctx)
+	require.NoError(t, err)
+	defer db.Close(ctx)
+
+	err = db.AddSchema(ctx, `type users { Name: String }`)
+	require.NoError(t, err)
+
+	res := db.ExecRequest(ctx, `mutation { create_users(data: "{\"Name\": \"John\"}") { _key } }`)
+	require.Empty(t, res.GQL.Errors)
+	docKey := res.GQL.Data.([]map[string]any)[0]["_key"].(string)
+
+	beforeDelete := waitForNextInstant()
+
+	res = db.ExecRequest(ctx, fmt.Sprintf(`mutation { delete_users(id: %q) { _key } }`, docKey))
+	require.Empty(t, res.GQL.Errors)
+
+	afterDelete := waitForNextInstant()
+
+	res = db.ExecRequest(ctx, atTimeRequest(beforeDelete, "Name"))
+	require.Empty(t, res.GQL.Errors)
+	assert.Equal(t, []map[string]any{{"Name": "John"}}, res.GQL.Data)
+
+	res = db.ExecRequest(ctx, atTimeRequest(afterDelete, "Name"))
+	require.Empty(t, res.GQL.Errors)
+	assert.Equal(t, []map[string]any{}, res.GQL.Data)
+}
+
+func TestExecRequestWithAtTimeAndCid(t *testing.T) {
+	ctx := context.Background()
+	db, err := newMemoryDB(ctx)
+	require.NoError(t, err)
+	defer db.Close(ctx)
+
+	err = db.AddSchema(ctx, `type users { Name: String }`)
+	require.NoError(t, err)
+
+	res := db.ExecRequest(
+		ctx,
+		fmt.Sprintf(
+			`query { users(cid: "bafybeieqnthjlvr64aodivtvtwgqelpjjvkmceyz4aqerkk5h23kjoivmu", atTime: %q) { Name } }`,
+			time.Now().Format(time.RFC3339Nano),
+		),
+	)
+	require.Len(t, res.GQL.Errors, 1)
+	assert.ErrorIs(t, res.GQL.Errors[0], parser.ErrCidWithAtTime)
+}
+
+func TestExecRequestWithAtTimeAndClock(t *testing.T) {
+	ctx := context.Background()
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	db, err := newMemoryDB(ctx, WithClock(func() time.Time { return now }))
+	require.NoError(t, err)
+	defer db.Close(ctx)
+
+	err = db.AddSchema(ctx, `type users { Name: String Age: Int }`)
+	require.NoError(t, err)
+
+	res := db.ExecRequest(ctx, `mutation { create_users(data: "{\"Name\": \"John\", \"Age\": 21}") { _key } }`)
+	require.Empty(t, res.GQL.Errors)
+	docKey := res.GQL.Data.([]map[string]any)[0]["_key"].(string)
+
+	now = now.Add(time.Hour)
+
+	res = db.ExecRequest(
+		ctx,
+		fmt.Sprintf(`mutation { update_users(id: %q, data: "{\"Age\": 22}") { _key } }`, docKey),
+	)
+	require.Empty(t, res.GQL.Errors)
+
+	res = db.ExecRequest(ctx, atTimeRequest(now.Add(-time.Minute), "Name Age"))
+	require.Empty(t, res.GQL.Errors)
+	assertSingleUser(t, res.GQL.Data, "John", 21)
+
+	res = db.ExecRequest(ctx, atTimeRequest(now, "Name Age"))
+	require.Empty(t, res.GQL.Errors)
+	assertSingleUser(t, res.GQL.Data, "John", 22)
+}
@@ -0,0 +1,170 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+	"math"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/client/request"
+	"github.com/sourcenetwork/defradb/datastore"
+)
+
+// coster computes the cost of the selections of a request.
+type coster struct {
+	db    *db
+	ctx   context.Context
+	txn   datastore.Txn
+	descs map[string]client.CollectionDescription
+}
+
+// checkRequestCost returns an error if the given request exceeds the maximum cost of the requests.
+func (db *db) checkRequestCost(ctx context.Context, txn datastore.Txn, req *request.Request) error {
+	if db.maxRequestCost <= 0 {
+		return nil
+	}
+	c := &coster{
+		db:    db,
+		ctx:   ctx,
+		txn:   txn,
+		descs: map[string]client.CollectionDescription{},
+	}
+
+	cost := 0
+	for _, operations := range [][]*request.OperationDefinition{req.Queries, req.Mutations, req.Subscription} {
+		for _, operation := range operations {
+			for _, selection := range operation.Selections {
+				selectionCost, err := c.rootCost(selection)
+				if err != nil {
+					return err
+				}
+				cost = addCost(cost, selectionCost)
+			}
+		}
+	}
+	if cost > db.maxRequestCost {
+		return NewErrRequestTooCostly(cost, db.maxRequestCost)
+	}
+	return nil
+}
+
+// rootCost returns the cost of the given top level selection of an operation.
+func (c *coster) rootCost(selection request.Selection) (int, error) {
+	switch s := selection.(type) {
+	case *request.Select:
+		if s.Root == request.CommitSelection {
+			return 1, nil
+		}
+		return c.collectionCost(s.Name, s.Fields)
+	case *request.ObjectMutation:
+		return c.collectionCost(s.Collection, s.Fields)
+	case *request.ObjectSubscription:
+		return c.collectionCost(s.Collection, s.Fields)
+	}
+	return 0, nil
+}
+
+// collectionCost returns the cost of a selection of the documents of the given collection, with
+// the given nested selections.
+func (c *coster) collectionCost(collection string, fields []request.Selection) (int, error) {
+	desc, err := c.description(collection)
+	if err != nil {
+		return 0, err
+	}
+
+	cost := 1
+	for _, selection := range fields {
+		switch s := selection.(type) {
+		case *request.Select:
+			var selectionCost int
+			switch {
+			case s.Join.HasValue():
+				selectionCost, err = c.collectionCost(s.Join.Value().Collection, s.Fields)
+			case s.Name == request.GroupFieldName || s.HistoryField.HasValue():
+				selectionCost, err = c.collectionCost(collection, s.Fields)
+			default:
+				field, ok := desc.GetField(s.Name)
+				if !ok || !field.IsObject() {
+					continue
+				}
+				selectionCost, err = c.collectionCost(field.Schema, s.Fields)
+			}
+			if err != nil {
+				return 0, err
+			}
+			cost = addCost(cost, selectionCost)
+
+		case *request.Aggregate:
+			aggregateCost, err := c.aggregateCost(desc, s)
+			if err != nil {
+				return 0, err
+			}
+			cost = addCost(cost, aggregateCost)
+		}
+	}
+	return mulCost(costMultiplier(desc), cost), nil
+}
+
+// aggregateCost returns the cost of the given aggregate of the documents of the collection of the
+// given description, that of the related documents it aggregates if any.
+func (c *coster) aggregateCost(desc client.CollectionDescription, aggregate *request.Aggregate) (int, error) {
+	cost := 0
+	for _, target := range aggregate.Targets {
+		field, ok := desc.GetField(target.HostName)
+		if !ok || !field.IsObject() {
+			continue
+		}
+		related, err := c.description(field.Schema)
+		if err != nil {
+			return 0, err
+		}
+		cost = addCost(cost, costMultiplier(related))
+	}
+	return cost, nil
+}
+
+// description returns the description of the collection with the given name.
+func (c *coster) description(name string) (client.CollectionDescription, error) {
+	if desc, ok := c.descs[name]; ok {
+		return desc, nil
+	}
+	col, err := c.db.getCollectionByName(c.ctx, c.txn, name)
+	if err != nil {
+		return client.CollectionDescription{}, err
+	}
+	c.descs[name] = col.Description()
+	return col.Description(), nil
+}
+
+// costMultiplier returns the cost multiplier of the collection of the given description.
+func costMultiplier(desc client.CollectionDescription) int {
+	if desc.Schema.CostMultiplier <= 0 {
+		return 1
+	}
+	return desc.Schema.CostMultiplier
+}
+
+// addCost returns the sum of the given costs, capped to the maximum int.
+func addCost(a int, b int) int {
+	if a > math.MaxInt-b {
+		return math.MaxInt
+	}
+	return a + b
+}
+
+// mulCost returns the product of the given costs, capped to the maximum int.
+func mulCost(a int, b int) int {
+	if a != 0 && b > math.MaxInt/a {
+		return math.MaxInt
+	}
+	return a * b
+}
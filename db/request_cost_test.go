@@ -0,0 +1,77 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCostLimitedDB(t *testing.T, ctx context.Context) *implicitTxnDB {
+	db, err := newMemoryDB(ctx, WithMaxRequestCost(25))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close(ctx) })
+
+	err = db.AddSchema(ctx, `
+		type Author { name: String books: [Book] }
+		type Book @cost(multiplier: 10) { title: String author: Author }
+	`)
+	require.NoError(t, err)
+	return db
+}
+
+func TestRequestCostWithinMaximum(t *testing.T) {
+	ctx := context.Background()
+	db := newCostLimitedDB(t, ctx)
+
+	for _, request := range []string{
+		`query { Author { name } }`,
+		`query { Book { title } }`,
+		`query { Author { books { title } } }`,
+		`query { Author { _count(books: {}) } }`,
+		`query { Book { title } Author { books { title } } }`,
+		`mutation { create_Book(data: "{\"title\": \"Go\"}") { _key } }`,
+	} {
+		res := db.ExecRequest(ctx, request)
+		assert.Empty(t, res.GQL.Errors, request)
+	}
+}
+
+func TestRequestCostExceedingMaximumRejectsRequest(t *testing.T) {
+	ctx := context.Background()
+	db := newCostLimitedDB(t, ctx)
+
+	for _, request := range []string{
+		`query { Book { author { books { title } } } }`,
+		`query { Book { title } Author { books { title } } other: Book { title } }`,
+	} {
+		res := db.ExecRequest(ctx, request)
+		require.Len(t, res.GQL.Errors, 1, request)
+		assert.ErrorIs(t, res.GQL.Errors[0], ErrRequestTooCostly, request)
+	}
+}
+
+func TestPatchSchemaCostMultiplier(t *testing.T) {
+	ctx := context.Background()
+	db := newCostLimitedDB(t, ctx)
+
+	err := db.PatchSchema(ctx, `[{"op": "replace", "path": "/Book/Schema/CostMultiplier", "value": -1}]`)
+	assert.ErrorIs(t, err, ErrInvalidCostMultiplier)
+
+	err = db.PatchSchema(ctx, `[{"op": "replace", "path": "/Book/Schema/CostMultiplier", "value": 1}]`)
+	require.NoError(t, err)
+
+	res := db.ExecRequest(ctx, `query { Book { author { books { title } } } }`)
+	assert.Empty(t, res.GQL.Errors)
+}
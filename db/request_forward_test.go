@@ -0,0 +1,66 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/client"
+)
+
+// testForwarder executes the forwarded requests on another database.
+type testForwarder struct {
+	upstream client.DB
+	requests []string
+}
+
+func (f *testForwarder) ForwardRequest(ctx context.Context, request string) *client.RequestResult {
+	f.requests = append(f.requests, request)
+	return f.upstream.ExecRequest(ctx, request)
+}
+
+func TestExecRequestForwardsQueriesOfUnknownCollections(t *testing.T) {
+	ctx := context.Background()
+	upstream, err := newMemoryDB(ctx)
+	require.NoError(t, err)
+	defer upstream.Close(ctx)
+	err = upstream.AddSchema(ctx, `type books { Title: String }`)
+	require.NoError(t, err)
+	res := upstream.ExecRequest(ctx, `mutation { create_books(data: "{\"Title\": \"Dune\"}") { _key } }`)
+	require.Empty(t, res.GQL.Errors)
+
+	forwarder := &testForwarder{upstream: upstream}
+	db, err := newMemoryDB(ctx, WithRequestForwarder(forwarder))
+	require.NoError(t, err)
+	defer db.Close(ctx)
+	err = db.AddSchema(ctx, `type users { Name: String }`)
+	require.NoError(t, err)
+	res = db.ExecRequest(ctx, `mutation { create_users(data: "{\"Name\": \"John\"}") { _key } }`)
+	require.Empty(t, res.GQL.Errors)
+
+	res = db.ExecRequest(ctx, `query { books { Title } }`)
+	require.Empty(t, res.GQL.Errors)
+	assert.Equal(t, []map[string]any{{"Title": "Dune"}}, res.GQL.Data)
+
+	res = db.ExecRequest(ctx, `query { users { Name } }`)
+	require.Empty(t, res.GQL.Errors)
+	assert.Equal(t, []map[string]any{{"Name": "John"}}, res.GQL.Data)
+
+	// Mutations are executed locally.
+	res = db.ExecRequest(ctx, `mutation { create_books(data: "{\"Title\": \"Emma\"}") { _key } }`)
+	assert.NotEmpty(t, res.GQL.Errors)
+
+	assert.Equal(t, []string{`query { books { Title } }`}, forwarder.requests)
+}
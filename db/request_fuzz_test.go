@@ -0,0 +1,122 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fuzzSchema is the schema of the database the fuzzed requests are executed against.
+const fuzzSchema = `
+	type User {
+		name: String
+		age: Int
+		points: Float
+		verified: Boolean
+		books: [Book]
+	}
+
+	type Book {
+		title: String
+		rating: Float
+		author: User
+	}
+`
+
+// newFuzzDB returns a new in-memory database, with the fuzzing schema and a few documents.
+func newFuzzDB(f *testing.F) (*implicitTxnDB, func()) {
+	ctx := context.Background()
+	db, err := newMemoryDB(ctx)
+	require.NoError(f, err)
+
+	err = db.AddSchema(ctx, fuzzSchema)
+	require.NoError(f, err)
+
+	res := db.ExecRequest(
+		ctx,
+		`mutation {
+			create_User(data: "{\"name\": \"John\", \"age\": 30, \"points\": 4.2, \"verified\": true}") {
+				_key
+			}
+		}`,
+	)
+	require.Empty(f, res.GQL.Errors)
+
+	return db, func() { db.Close(ctx) }
+}
+
+// FuzzExecRequest executes arbitrary requests, which must fail with errors instead of panicking.
+//
+// The inputs making it panic are stored in testdata/fuzz/FuzzExecRequest by `go test -fuzz`,
+// and are run again by `go test` from then on.
+func FuzzExecRequest(f *testing.F) {
+	seeds := []string{
+		`query { User { name age } }`,
+		`query { User(filter: {age: {_gt: 20}}) { name books { title } } }`,
+		`query { User(groupBy: [age], order: {age: ASC}) { age _group { name } } }`,
+		`query { User { name _count(books: {}) _sum(books: {field: rating}) } }`,
+		`query { Book(filter: {author: {name: {_like: "J%"}}}) { title author { name } } }`,
+		`query { User(limit: 1, offset: 1) { _key _version { cid } } }`,
+		`query { commits(dockey: "bae-123", order: {height: DESC}) { cid height } }`,
+		`query @explain { User(filter: {_or: [{age: {_lt: 1}}, {name: {_eq: "x"}}]}) { name } }`,
+		`mutation { create_Book(data: "{\"title\": \"A\", \"rating\": 2}") { _key } }`,
+		`mutation { update_User(filter: {age: {_ge: 0}}, data: "{\"age\": 1}") { name } }`,
+		`mutation { delete_User(ids: ["bae-123"]) { _key } }`,
+		`{ __schema { types { name } } }`,
+		`query { User(filter: {`,
+	}
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+
+	db, closeDB := newFuzzDB(f)
+	defer closeDB()
+
+	f.Fuzz(func(t *testing.T, request string) {
+		// Results and errors are both fine, as long as the request doesn't panic.
+		_ = db.ExecRequest(context.Background(), request)
+	})
+}
+
+// FuzzUpdateWithFilter updates documents with arbitrary JSON filters, which must fail with
+// errors instead of panicking.
+func FuzzUpdateWithFilter(f *testing.F) {
+	seeds := []string{
+		`{"age": {"_gt": 20}}`,
+		`{"name": {"_in": ["John", "Bob"]}}`,
+		`{"_and": [{"age": {"_lt": 40}}, {"verified": {"_eq": true}}]}`,
+		`{"_not": {"points": {"_ne": null}}}`,
+		`{"books": {"title": {"_eq": "A"}}}`,
+		`{"age": 30}`,
+		`{"_or": []}`,
+		`{}`,
+		`[]`,
+		`{"age": {"_gt": "twenty"}}`,
+	}
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+
+	db, closeDB := newFuzzDB(f)
+	defer closeDB()
+
+	ctx := context.Background()
+	col, err := db.GetCollectionByName(ctx, "User")
+	require.NoError(f, err)
+
+	f.Fuzz(func(t *testing.T, filter string) {
+		// Results and errors are both fine, as long as the update doesn't panic.
+		_, _ = col.UpdateWithFilter(ctx, filter, `{"points": 1}`)
+	})
+}
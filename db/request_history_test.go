@@ -0,0 +1,73 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecRequestWithHistoryTimestamps(t *testing.T) {
+	ctx := context.Background()
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	db, err := newMemoryDB(ctx, WithClock(func() time.Time { return now }))
+	require.NoError(t, err)
+	defer db.Close(ctx)
+
+	err = db.AddSchema(ctx, `type users { Name: String Age: Int }`)
+	require.NoError(t, err)
+
+	res := db.ExecRequest(ctx, `mutation { create_users(data: "{\"Name\": \"John\", \"Age\": 21}") { _key } }`)
+	require.Empty(t, res.GQL.Errors)
+	docKey := res.GQL.Data.([]map[string]any)[0]["_key"].(string)
+
+	now = now.Add(time.Hour)
+
+	res = db.ExecRequest(
+		ctx,
+		fmt.Sprintf(`mutation { update_users(id: %q, data: "{\"Age\": 22}") { _key } }`, docKey),
+	)
+	require.Empty(t, res.GQL.Errors)
+
+	now = now.Add(time.Hour)
+
+	// Updating another field doesn't add to the history of the age.
+	res = db.ExecRequest(
+		ctx,
+		fmt.Sprintf(`mutation { update_users(id: %q, data: "{\"Name\": \"Johnny\"}") { _key } }`, docKey),
+	)
+	require.Empty(t, res.GQL.Errors)
+
+	res = db.ExecRequest(ctx, `query { users { _history(field: Age) { value height timestamp } } }`)
+	require.Empty(t, res.GQL.Errors)
+
+	users, ok := res.GQL.Data.([]map[string]any)
+	require.True(t, ok)
+	require.Len(t, users, 1)
+
+	history, ok := users[0]["_history"].([]map[string]any)
+	require.True(t, ok)
+	require.Len(t, history, 2)
+
+	assert.EqualValues(t, 22, history[0]["value"])
+	assert.Equal(t, int64(2), history[0]["height"])
+	assert.Equal(t, "2023-01-01T01:00:00Z", history[0]["timestamp"])
+
+	assert.EqualValues(t, 21, history[1]["value"])
+	assert.Equal(t, int64(1), history[1]["height"])
+	assert.Equal(t, "2023-01-01T00:00:00Z", history[1]["timestamp"])
+}
@@ -0,0 +1,81 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/client/request"
+	"github.com/sourcenetwork/defradb/errors"
+)
+
+func TestRequestHookWithRequestContext(t *testing.T) {
+	ctx := context.Background()
+	var requestContexts []client.RequestContext
+	var mutations []int
+	db, err := newMemoryDB(ctx, WithRequestHook(func(ctx context.Context, req *request.Request) error {
+		requestContexts = append(requestContexts, client.RequestContextFromContext(ctx))
+		mutations = append(mutations, len(req.Mutations))
+		return nil
+	}))
+	require.NoError(t, err)
+	defer db.Close(ctx)
+
+	err = db.AddSchema(ctx, `type users { Name: String }`)
+	require.NoError(t, err)
+
+	requestContext := client.RequestContext{
+		Identity: "alice",
+		Metadata: map[string]string{"X-Request-ID": "42"},
+	}
+	res := db.ExecRequest(
+		client.WithRequestContext(ctx, requestContext),
+		`mutation { create_users(data: "{\"Name\": \"John\"}") { _key } }`,
+	)
+	require.Empty(t, res.GQL.Errors)
+	res = db.ExecRequest(ctx, `query { users { Name } }`)
+	require.Empty(t, res.GQL.Errors)
+
+	assert.Equal(t, []client.RequestContext{requestContext, {}}, requestContexts)
+	assert.Equal(t, []int{1, 0}, mutations)
+}
+
+func TestRequestHookWithErrorRejectsRequest(t *testing.T) {
+	ctx := context.Background()
+	errReadOnly := errors.New("anonymous requests are read only")
+	db, err := newMemoryDB(
+		ctx,
+		WithRequestHook(func(ctx context.Context, req *request.Request) error {
+			if len(req.Mutations) > 0 && client.RequestContextFromContext(ctx).Identity == "" {
+				return errReadOnly
+			}
+			return nil
+		}),
+	)
+	require.NoError(t, err)
+	defer db.Close(ctx)
+
+	err = db.AddSchema(ctx, `type users { Name: String }`)
+	require.NoError(t, err)
+
+	res := db.ExecRequest(ctx, `mutation { create_users(data: "{\"Name\": \"John\"}") { _key } }`)
+	require.Len(t, res.GQL.Errors, 1)
+	assert.ErrorIs(t, res.GQL.Errors[0], errReadOnly)
+
+	res = db.ExecRequest(ctx, `query { users { Name } }`)
+	require.Empty(t, res.GQL.Errors)
+	assert.Empty(t, res.GQL.Data)
+}
@@ -0,0 +1,208 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/logging"
+)
+
+// withPlannerLog sends the logs of the planner to a temporary file for the duration of the test,
+// returning the path of the file.
+func withPlannerLog(t *testing.T) string {
+	logFile := path.Join(t.TempDir(), "planner.log")
+	logging.SetConfig(logging.Config{
+		OverridesByLoggerName: map[string]logging.Config{
+			"defra.planner": {
+				EncoderFormat: logging.NewEncoderFormatOption(logging.JSON),
+				OutputPaths:   []string{logFile},
+			},
+		},
+	})
+	t.Cleanup(func() {
+		logging.SetConfig(logging.Config{
+			OverridesByLoggerName: map[string]logging.Config{
+				"defra.planner": {OutputPaths: []string{"stderr"}},
+			},
+		})
+	})
+	return logFile
+}
+
+// readLogLines returns the log lines of the given file, decoded from JSON.
+func readLogLines(t *testing.T, logFile string) []map[string]any {
+	f, err := os.Open(logFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	require.NoError(t, err)
+	defer f.Close() //nolint:errcheck
+
+	lines := []map[string]any{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := map[string]any{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestExecRequestWithPlanDebug(t *testing.T) {
+	ctx := context.Background()
+	db, err := newMemoryDB(ctx)
+	require.NoError(t, err)
+	defer db.Close(ctx)
+
+	err = db.AddSchema(ctx, `type users { Name: String Age: Int }`)
+	require.NoError(t, err)
+
+	logFile := withPlannerLog(t)
+
+	res := db.ExecRequest(
+		client.WithPlanDebug(ctx),
+		`query { users(groupBy: [Age], order: {Age: ASC}, limit: 1) { Age _count(_group: {}) } }`,
+	)
+	require.Empty(t, res.GQL.Errors)
+
+	messagesBySteps := map[string][]string{}
+	for _, line := range readLogLines(t, logFile) {
+		step, _ := line["Step"].(string)
+		messagesBySteps[step] = append(messagesBySteps[step], line["msg"].(string))
+	}
+
+	assert.Equal(
+		t,
+		map[string][]string{
+			"select":   {"Planning a select node"},
+			"optimize": {"Optimizing the plan", "Optimized the plan"},
+			"expand": {
+				"Wiring a group node",
+				"Using the first scan of the plan as the group source",
+				"Aggregating the groups as the documents are read",
+				"Wiring an aggregate node",
+				"Wiring an order node",
+				"Wiring a limit node",
+			},
+			"run": {"Executing the plan"},
+		},
+		messagesBySteps,
+	)
+}
+
+func TestExecRequestWithPlanDebugAndLimitPushedDown(t *testing.T) {
+	ctx := context.Background()
+	db, err := newMemoryDB(ctx)
+	require.NoError(t, err)
+	defer db.Close(ctx)
+
+	err = db.AddSchema(ctx, `
+		type users {
+			Name: String
+			books: [books]
+		}
+		type books {
+			Title: String
+			author: users
+		}
+	`)
+	require.NoError(t, err)
+
+	logFile := withPlannerLog(t)
+
+	res := db.ExecRequest(
+		client.WithPlanDebug(ctx),
+		`query { users(limit: 1, offset: 1) { Name books { Title } } }`,
+	)
+	require.Empty(t, res.GQL.Errors)
+
+	messages := []string{}
+	for _, line := range readLogLines(t, logFile) {
+		if line["Step"] == "expand" {
+			messages = append(messages, line["msg"].(string))
+		}
+	}
+	assert.Equal(t, []string{"Wiring a limit node", "Pushing the limit down to the scan node"}, messages)
+}
+
+func TestExecRequestWithPlanDebugAndCountScanningKeysOnly(t *testing.T) {
+	ctx := context.Background()
+	db, err := newMemoryDB(ctx)
+	require.NoError(t, err)
+	defer db.Close(ctx)
+
+	err = db.AddSchema(ctx, `type users { Name: String Age: Int }`)
+	require.NoError(t, err)
+
+	var keys []string
+	for _, user := range []string{`{"Name": "John", "Age": 21}`, `{"Name": "Bob", "Age": 32}`, `{}`} {
+		doc, err := client.NewDocFromJSON([]byte(user))
+		require.NoError(t, err)
+		res := db.ExecRequest(ctx, fmt.Sprintf(`mutation { create_users(data: %q) { _key } }`, user))
+		require.Empty(t, res.GQL.Errors)
+		keys = append(keys, doc.Key().String())
+	}
+	res := db.ExecRequest(ctx, fmt.Sprintf(`mutation { delete_users(id: %q) { _key } }`, keys[0]))
+	require.Empty(t, res.GQL.Errors)
+
+	for request, expected := range map[string]struct {
+		count    int
+		keysOnly bool
+	}{
+		`query { _count(users: {}) }`:                                 {count: 2, keysOnly: true},
+		`query { _count(users: {filter: {_key: {_ne: "bae-123"}}}) }`: {count: 2, keysOnly: true},
+		`query { _count(users: {filter: {Age: {_gt: 30}}}) }`:         {count: 1},
+		// The documents counted are selected apart from the ones summed.
+		`query { _count(users: {}) _sum(users: {field: Age}) }`: {count: 2, keysOnly: true},
+	} {
+		logFile := withPlannerLog(t)
+
+		res := db.ExecRequest(client.WithPlanDebug(ctx), request)
+		require.Empty(t, res.GQL.Errors, request)
+		assert.Equal(t, expected.count, res.GQL.Data.([]map[string]any)[0]["_count"], request)
+
+		var keysOnly bool
+		for _, line := range readLogLines(t, logFile) {
+			if line["msg"] == "Scanning the keys of the documents only" {
+				keysOnly = true
+			}
+		}
+		assert.Equal(t, expected.keysOnly, keysOnly, request)
+	}
+}
+
+func TestExecRequestWithoutPlanDebug(t *testing.T) {
+	ctx := context.Background()
+	db, err := newMemoryDB(ctx)
+	require.NoError(t, err)
+	defer db.Close(ctx)
+
+	err = db.AddSchema(ctx, `type users { Name: String }`)
+	require.NoError(t, err)
+
+	logFile := withPlannerLog(t)
+
+	res := db.ExecRequest(ctx, `query { users { Name } }`)
+	require.Empty(t, res.GQL.Errors)
+
+	assert.Empty(t, readLogLines(t, logFile))
+}
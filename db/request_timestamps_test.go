@@ -0,0 +1,73 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecRequestWithTimestampFields(t *testing.T) {
+	ctx := context.Background()
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	db, err := newMemoryDB(ctx, WithClock(func() time.Time { return now }))
+	require.NoError(t, err)
+	defer db.Close(ctx)
+
+	err = db.AddSchema(ctx, `type users { Name: String Age: Int }`)
+	require.NoError(t, err)
+
+	res := db.ExecRequest(ctx, `mutation { create_users(data: "{\"Name\": \"John\", \"Age\": 21}") { _key } }`)
+	require.Empty(t, res.GQL.Errors)
+	docKey := res.GQL.Data.([]map[string]any)[0]["_key"].(string)
+
+	now = now.Add(time.Hour)
+
+	res = db.ExecRequest(ctx, `mutation { create_users(data: "{\"Name\": \"Bob\", \"Age\": 32}") { _key } }`)
+	require.Empty(t, res.GQL.Errors)
+
+	now = now.Add(time.Hour)
+
+	res = db.ExecRequest(
+		ctx,
+		fmt.Sprintf(`mutation { update_users(id: %q, data: "{\"Age\": 22}") { _key } }`, docKey),
+	)
+	require.Empty(t, res.GQL.Errors)
+
+	res = db.ExecRequest(ctx, `query { users(order: {_updatedAt: DESC}) { Name _createdAt _updatedAt } }`)
+	require.Empty(t, res.GQL.Errors)
+	assert.Equal(
+		t,
+		[]map[string]any{
+			{
+				"Name":       "John",
+				"_createdAt": "2023-01-01T00:00:00.000000000Z",
+				"_updatedAt": "2023-01-01T02:00:00.000000000Z",
+			},
+			{
+				"Name":       "Bob",
+				"_createdAt": "2023-01-01T01:00:00.000000000Z",
+				"_updatedAt": "2023-01-01T01:00:00.000000000Z",
+			},
+		},
+		res.GQL.Data,
+	)
+
+	res = db.ExecRequest(ctx, `query { users(filter: {_createdAt: {_gt: "2023-01-01T00:30:00Z"}}) { Name } }`)
+	require.Empty(t, res.GQL.Errors)
+	assert.Equal(t, []map[string]any{{"Name": "Bob"}}, res.GQL.Data)
+}
@@ -0,0 +1,123 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sourcenetwork/defradb/client"
+)
+
+// activeRequest is a request being executed and the function cancelling its context.
+type activeRequest struct {
+	info      client.ActiveRequest
+	cancel    context.CancelFunc
+	cancelled bool
+}
+
+// requestTracker keeps track of the requests currently being executed.
+type requestTracker struct {
+	mu       sync.Mutex
+	nextID   uint64
+	requests map[uint64]*activeRequest
+}
+
+// track registers a new active request and returns its ID along with a context
+// that will be cancelled if the request is cancelled.
+//
+// The caller must call done with the returned ID once the request has completed.
+func (t *requestTracker) track(ctx context.Context, request string) (uint64, context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.requests == nil {
+		t.requests = make(map[uint64]*activeRequest)
+	}
+	t.nextID++
+	t.requests[t.nextID] = &activeRequest{
+		info: client.ActiveRequest{
+			ID:        t.nextID,
+			Request:   request,
+			StartedAt: time.Now(),
+			Context:   client.RequestContextFromContext(ctx),
+		},
+		cancel: cancel,
+	}
+	return t.nextID, ctx
+}
+
+// setPlan sets the plan summary of the active request with the given ID.
+func (t *requestTracker) setPlan(id uint64, plan string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if r, ok := t.requests[id]; ok {
+		r.info.Plan = plan
+	}
+}
+
+// done unregisters the request with the given ID and returns true if it was cancelled.
+func (t *requestTracker) done(id uint64) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	r, ok := t.requests[id]
+	if !ok {
+		return false
+	}
+	delete(t.requests, id)
+	r.cancel()
+	return r.cancelled
+}
+
+// list returns the active requests ordered by ID.
+func (t *requestTracker) list() []client.ActiveRequest {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	requests := make([]client.ActiveRequest, 0, len(t.requests))
+	for _, r := range t.requests {
+		requests = append(requests, r.info)
+	}
+	sort.Slice(requests, func(i, j int) bool {
+		return requests[i].ID < requests[j].ID
+	})
+	return requests
+}
+
+// cancel cancels the context of the active request with the given ID.
+func (t *requestTracker) cancel(id uint64) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	r, ok := t.requests[id]
+	if !ok {
+		return client.NewErrRequestNotFound(id)
+	}
+	r.cancelled = true
+	r.cancel()
+	return nil
+}
+
+// ActiveRequests returns the requests that are currently being executed.
+func (db *db) ActiveRequests() []client.ActiveRequest {
+	return db.requests.list()
+}
+
+// CancelRequest cancels the active request with the given ID.
+func (db *db) CancelRequest(id uint64) error {
+	return db.requests.cancel(id)
+}
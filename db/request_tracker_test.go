@@ -0,0 +1,93 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/client"
+)
+
+func TestRequestTrackerTrackAndDone(t *testing.T) {
+	tracker := requestTracker{}
+
+	id, ctx := tracker.track(context.Background(), "query { User { name } }")
+	tracker.setPlan(id, "selectTopNode -> selectNode -> scanNode")
+
+	requests := tracker.list()
+	require.Len(t, requests, 1)
+	assert.Equal(t, id, requests[0].ID)
+	assert.Equal(t, "query { User { name } }", requests[0].Request)
+	assert.Equal(t, "selectTopNode -> selectNode -> scanNode", requests[0].Plan)
+	assert.NoError(t, ctx.Err())
+
+	assert.False(t, tracker.done(id))
+	assert.Empty(t, tracker.list())
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+}
+
+func TestRequestTrackerTrackWithRequestContext(t *testing.T) {
+	tracker := requestTracker{}
+	requestContext := client.RequestContext{
+		Identity: "alice",
+		Metadata: map[string]string{"X-Request-ID": "42"},
+	}
+	ctx := client.WithRequestContext(context.Background(), requestContext)
+
+	id, _ := tracker.track(ctx, "query { User { name } }")
+	defer tracker.done(id)
+
+	requests := tracker.list()
+	require.Len(t, requests, 1)
+	assert.Equal(t, requestContext, requests[0].Context)
+}
+
+func TestRequestTrackerCancel(t *testing.T) {
+	tracker := requestTracker{}
+
+	first, firstCtx := tracker.track(context.Background(), "first")
+	second, secondCtx := tracker.track(context.Background(), "second")
+	assert.NotEqual(t, first, second)
+
+	err := tracker.cancel(first)
+	require.NoError(t, err)
+	assert.ErrorIs(t, firstCtx.Err(), context.Canceled)
+	assert.NoError(t, secondCtx.Err())
+
+	assert.True(t, tracker.done(first))
+	assert.False(t, tracker.done(second))
+}
+
+func TestRequestTrackerCancelWithUnknownID(t *testing.T) {
+	tracker := requestTracker{}
+
+	err := tracker.cancel(1)
+	assert.ErrorIs(t, err, client.ErrRequestNotFound)
+}
+
+func TestExecRequestUntracksCompletedRequest(t *testing.T) {
+	ctx := context.Background()
+	db, err := newMemoryDB(ctx)
+	require.NoError(t, err)
+	defer db.Close(ctx)
+
+	err = db.AddSchema(ctx, `type User { name: String }`)
+	require.NoError(t, err)
+
+	res := db.ExecRequest(ctx, `query { User { name } }`)
+	require.Empty(t, res.GQL.Errors)
+
+	assert.Empty(t, db.ActiveRequests())
+}
@@ -0,0 +1,174 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/client/request"
+	"github.com/sourcenetwork/defradb/logging"
+)
+
+const (
+	defaultRetentionInterval = time.Hour
+
+	// retentionTimeLayout is the layout of the times of the timestamp meta fields, which are
+	// compared as strings by the filters.
+	retentionTimeLayout = "2006-01-02T15:04:05.000000000Z07:00"
+)
+
+// RetentionPolicy is the retention window of the documents of a collection, such as a
+// collection of logs or telemetry, past which they are deleted.
+type RetentionPolicy struct {
+	// Collection is the name of the collection.
+	Collection string
+	// MaxAge is the age past which the documents are deleted.
+	MaxAge time.Duration
+	// ByUpdate ages the documents from their last update, rather than from their creation.
+	ByUpdate bool
+	// PurgeHistory removes the history of the deleted documents, rather than keeping their
+	// commits, so that their space is freed.
+	PurgeHistory bool
+}
+
+// WithRetention enables the deletion of the documents older than the max age of the retention
+// policy of their collection, which is checked at the given interval.
+//
+// The interval defaults to one hour.
+func WithRetention(interval time.Duration, policies ...RetentionPolicy) Option {
+	return func(db *db) {
+		db.retentionInterval = interval
+		db.retentionPolicies = policies
+	}
+}
+
+// retentionPruner deletes the documents past the retention window of their collection.
+type retentionPruner struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// startRetentionPruner starts deleting the expired documents at the retention interval, if
+// any retention policy is set.
+func (db *db) startRetentionPruner() error {
+	if len(db.retentionPolicies) == 0 {
+		return nil
+	}
+	for _, policy := range db.retentionPolicies {
+		if policy.MaxAge <= 0 {
+			return NewErrInvalidRetentionMaxAge(policy.Collection)
+		}
+	}
+	interval := db.retentionInterval
+	if interval <= 0 {
+		interval = defaultRetentionInterval
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	db.retention = &retentionPruner{
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	go func() {
+		defer close(db.retention.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				db.pruneExpired(ctx)
+			}
+		}
+	}()
+	return nil
+}
+
+// close stops the pruner, waiting for the pruning in progress to end.
+func (p *retentionPruner) close() {
+	p.cancel()
+	<-p.done
+}
+
+// pruneExpired deletes the expired documents of each collection with a retention policy.
+//
+// The errors are logged, so that the other collections are still pruned.
+func (db *db) pruneExpired(ctx context.Context) {
+	for _, policy := range db.retentionPolicies {
+		count, err := db.pruneCollection(ctx, policy)
+		if err != nil {
+			log.ErrorE(
+				ctx,
+				"Failed to prune expired documents",
+				err,
+				logging.NewKV("Collection", policy.Collection),
+			)
+			continue
+		}
+		if count > 0 {
+			log.Info(
+				ctx,
+				"Pruned expired documents",
+				logging.NewKV("Collection", policy.Collection),
+				logging.NewKV("Count", count),
+			)
+		}
+	}
+}
+
+// pruneCollection deletes the documents of the collection of the given policy that are older
+// than its max age, and purges their history if required, in a single transaction.
+//
+// Returns the number of deleted documents.
+func (db *db) pruneCollection(ctx context.Context, policy RetentionPolicy) (int64, error) {
+	txn, err := db.NewTxn(ctx, false)
+	if err != nil {
+		return 0, err
+	}
+	defer txn.Discard(ctx)
+
+	col, err := db.getCollectionByName(ctx, txn, policy.Collection)
+	if err != nil {
+		return 0, err
+	}
+
+	field := request.CreatedAtFieldName
+	if policy.ByUpdate {
+		field = request.UpdatedAtFieldName
+	}
+	cutoff := db.now().Add(-policy.MaxAge).UTC().Format(retentionTimeLayout)
+	// The internal delete is used, as the collections of logs and telemetry retention is meant
+	// for are often append-only, rejecting the deletes of their users.
+	c := col.(*collection)
+	res, err := c.deleteWithFilter(
+		ctx,
+		txn,
+		fmt.Sprintf(`{%s: {_lt: %q}}`, field, cutoff),
+		client.Pruned,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	if policy.PurgeHistory {
+		for _, docKey := range res.DocKeys {
+			if err := c.purge(ctx, txn, c.getPrimaryKey(docKey)); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return res.Count, txn.Commit(ctx)
+}
@@ -0,0 +1,156 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPruneExpiredDocuments(t *testing.T) {
+	ctx := context.Background()
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	db, err := newMemoryDB(
+		ctx,
+		WithClock(func() time.Time { return now }),
+		WithRetention(
+			time.Hour,
+			RetentionPolicy{Collection: "logs", MaxAge: 48 * time.Hour, PurgeHistory: true},
+			RetentionPolicy{Collection: "metrics", MaxAge: 24 * time.Hour, ByUpdate: true},
+		),
+	)
+	require.NoError(t, err)
+	defer db.Close(ctx)
+
+	err = db.AddSchema(ctx, `type logs { Message: String } type metrics { Name: String Value: Int }`)
+	require.NoError(t, err)
+
+	create := func(request string) string {
+		res := db.ExecRequest(ctx, request)
+		require.Empty(t, res.GQL.Errors)
+		return res.GQL.Data.([]map[string]any)[0]["_key"].(string)
+	}
+	oldLog := create(`mutation { create_logs(data: "{\"Message\": \"old\"}") { _key } }`)
+	create(`mutation { create_metrics(data: "{\"Name\": \"idle\", \"Value\": 1}") { _key } }`)
+	active := create(`mutation { create_metrics(data: "{\"Name\": \"active\", \"Value\": 1}") { _key } }`)
+
+	now = now.Add(36 * time.Hour)
+	create(`mutation { create_logs(data: "{\"Message\": \"new\"}") { _key } }`)
+	res := db.ExecRequest(
+		ctx,
+		fmt.Sprintf(`mutation { update_metrics(id: %q, data: "{\"Value\": 2}") { _key } }`, active),
+	)
+	require.Empty(t, res.GQL.Errors)
+
+	now = now.Add(24 * time.Hour)
+	db.pruneExpired(ctx)
+
+	res = db.ExecRequest(ctx, `query { logs { Message } }`)
+	require.Empty(t, res.GQL.Errors)
+	assert.Equal(t, []map[string]any{{"Message": "new"}}, res.GQL.Data)
+
+	res = db.ExecRequest(ctx, `query { metrics { Name } }`)
+	require.Empty(t, res.GQL.Errors)
+	assert.Equal(t, []map[string]any{{"Name": "active"}}, res.GQL.Data)
+
+	// The history of the pruned log is purged, while that of the pruned metric is kept.
+	res = db.ExecRequest(ctx, fmt.Sprintf(`query { commits(dockey: %q) { cid } }`, oldLog))
+	require.Empty(t, res.GQL.Errors)
+	assert.Empty(t, res.GQL.Data)
+
+	res = db.ExecRequest(ctx, `query { metrics(showDeleted: true) { Name _deleted } }`)
+	require.Empty(t, res.GQL.Errors)
+	assert.Contains(t, res.GQL.Data, map[string]any{"Name": "idle", "_deleted": true})
+}
+
+func TestPruneExpiredDocumentsOfAppendOnlyCollection(t *testing.T) {
+	ctx := context.Background()
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	db, err := newMemoryDB(
+		ctx,
+		WithClock(func() time.Time { return now }),
+		WithRetention(time.Hour, RetentionPolicy{Collection: "logs", MaxAge: 24 * time.Hour}),
+	)
+	require.NoError(t, err)
+	defer db.Close(ctx)
+
+	err = db.AddSchema(ctx, `type logs @appendOnly { Message: String }`)
+	require.NoError(t, err)
+
+	res := db.ExecRequest(ctx, `mutation { create_logs(data: "{\"Message\": \"old\"}") { _key } }`)
+	require.Empty(t, res.GQL.Errors)
+	now = now.Add(36 * time.Hour)
+	res = db.ExecRequest(ctx, `mutation { create_logs(data: "{\"Message\": \"new\"}") { _key } }`)
+	require.Empty(t, res.GQL.Errors)
+
+	count, err := db.pruneCollection(ctx, db.retentionPolicies[0])
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	res = db.ExecRequest(ctx, `query { logs { Message } }`)
+	require.Empty(t, res.GQL.Errors)
+	assert.Equal(t, []map[string]any{{"Message": "new"}}, res.GQL.Data)
+}
+
+func TestPruneExpiredDocumentsWithUnknownCollection(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := newMemoryDB(
+		ctx,
+		WithRetention(time.Hour, RetentionPolicy{Collection: "logs", MaxAge: time.Hour}),
+	)
+	require.NoError(t, err)
+	defer db.Close(ctx)
+
+	_, err = db.pruneCollection(ctx, db.retentionPolicies[0])
+	assert.Error(t, err)
+}
+
+func TestNewDBWithInvalidRetentionMaxAge(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := newMemoryDB(
+		ctx,
+		WithRetention(time.Hour, RetentionPolicy{Collection: "logs"}),
+	)
+	assert.ErrorIs(t, err, ErrInvalidRetentionMaxAge)
+}
+
+func TestPurgeLiveDocument(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := newMemoryDB(ctx)
+	require.NoError(t, err)
+	defer db.Close(ctx)
+
+	err = db.AddSchema(ctx, `type logs { Message: String }`)
+	require.NoError(t, err)
+	res := db.ExecRequest(ctx, `mutation { create_logs(data: "{\"Message\": \"a\"}") { _key } }`)
+	require.Empty(t, res.GQL.Errors)
+	docKey := res.GQL.Data.([]map[string]any)[0]["_key"].(string)
+
+	txn, err := db.NewTxn(ctx, false)
+	require.NoError(t, err)
+	defer txn.Discard(ctx)
+	col, err := db.getCollectionByName(ctx, txn, "logs")
+	require.NoError(t, err)
+	c := col.(*collection)
+
+	err = c.purge(ctx, txn, c.getPrimaryKey(docKey))
+	assert.ErrorIs(t, err, ErrDocumentNotDeleted)
+}
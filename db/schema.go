@@ -16,14 +16,22 @@ import (
 	"strings"
 
 	jsonpatch "github.com/evanphx/json-patch/v5"
+	ds "github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/query"
 
 	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/core"
 	"github.com/sourcenetwork/defradb/datastore"
 )
 
 // addSchema takes the provided schema in SDL format, and applies it to the database,
 // and creates the necessary collections, request types, etc.
 func (db *db) addSchema(ctx context.Context, txn datastore.Txn, schemaString string) error {
+	update, err := db.verifySchemaUpdate(ctx, client.SchemaUpdateAdd, schemaString)
+	if err != nil {
+		return err
+	}
+
 	existingDescriptions, err := db.getCollectionDescriptions(ctx, txn)
 	if err != nil {
 		return err
@@ -40,7 +48,11 @@ func (db *db) addSchema(ctx context.Context, txn datastore.Txn, schemaString str
 	}
 
 	for _, desc := range newDescriptions {
-		if _, err := db.createCollection(ctx, txn, desc); err != nil {
+		col, err := db.createCollection(ctx, txn, desc)
+		if err != nil {
+			return err
+		}
+		if err := putSignedSchemaUpdate(ctx, txn, col.Schema().VersionID, update); err != nil {
 			return err
 		}
 	}
@@ -86,6 +98,11 @@ func (db *db) getCollectionDescriptions(
 // been made, if the net result of the patch matches the current persisted description then no changes
 // will be applied.
 func (db *db) patchSchema(ctx context.Context, txn datastore.Txn, patchString string) error {
+	update, err := db.verifySchemaUpdate(ctx, client.SchemaUpdatePatch, patchString)
+	if err != nil {
+		return err
+	}
+
 	patch, err := jsonpatch.DecodePatch([]byte(patchString))
 	if err != nil {
 		return err
@@ -125,7 +142,14 @@ func (db *db) patchSchema(ctx context.Context, txn datastore.Txn, patchString st
 	}
 
 	for _, desc := range newDescriptions {
-		if _, err := db.updateCollection(ctx, txn, desc); err != nil {
+		col, err := db.updateCollection(ctx, txn, desc)
+		if err != nil {
+			return err
+		}
+		if col.Schema().VersionID == collectionsByName[desc.Name].Schema.VersionID {
+			continue
+		}
+		if err := putSignedSchemaUpdate(ctx, txn, col.Schema().VersionID, update); err != nil {
 			return err
 		}
 	}
@@ -150,6 +174,155 @@ func (db *db) getCollectionsByName(
 	return collectionsByName, nil
 }
 
+// newSchemaVersionID returns the ID of the schema version with the given description, the
+// VersionID of which is that of the version it derives from.
+func newSchemaVersionID(schema client.SchemaDescription) (string, error) {
+	buf, err := json.Marshal(schema)
+	if err != nil {
+		return "", err
+	}
+	cid, err := core.NewSHA256CidV1(buf)
+	if err != nil {
+		return "", err
+	}
+	return cid.String(), nil
+}
+
+// getSchemaVersions returns the descriptions of all the versions of the schema with the given
+// ID, ordered from the initial version to the current one.
+func (db *db) getSchemaVersions(
+	ctx context.Context,
+	txn datastore.Txn,
+	schemaID string,
+) ([]client.CollectionDescription, error) {
+	if schemaID == "" {
+		return nil, ErrSchemaIdEmpty
+	}
+
+	q, err := txn.Systemstore().Query(ctx, query.Query{
+		Prefix: core.NewCollectionSchemaVersionKey("").ToString(),
+	})
+	if err != nil {
+		return nil, NewErrFailedToCreateCollectionQuery(err)
+	}
+	defer func() {
+		if err := q.Close(); err != nil {
+			log.ErrorE(ctx, "Failed to close collection query", err)
+		}
+	}()
+
+	versionsByID := map[string]client.CollectionDescription{}
+	for res := range q.Next() {
+		if res.Error != nil {
+			return nil, res.Error
+		}
+		var desc client.CollectionDescription
+		if err := json.Unmarshal(res.Value, &desc); err != nil {
+			return nil, err
+		}
+		if desc.Schema.SchemaID == schemaID {
+			versionsByID[desc.Schema.VersionID] = desc
+		}
+	}
+
+	// The ID of each version derives from the ID of the previous one, which chains the versions
+	// from the initial one, the ID of which is the schema ID.
+	current, ok := versionsByID[schemaID]
+	if !ok {
+		return nil, ds.ErrNotFound
+	}
+	versions := []client.CollectionDescription{current}
+	delete(versionsByID, schemaID)
+	for len(versionsByID) > 0 {
+		var next *client.CollectionDescription
+		for _, version := range versionsByID {
+			previous := version.Schema
+			previous.VersionID = current.Schema.VersionID
+			versionID, err := newSchemaVersionID(previous)
+			if err != nil {
+				return nil, err
+			}
+			if versionID == version.Schema.VersionID {
+				next = &version
+				break
+			}
+		}
+		if next == nil {
+			break
+		}
+		current = *next
+		versions = append(versions, current)
+		delete(versionsByID, current.Schema.VersionID)
+	}
+	return versions, nil
+}
+
+// addSchemaVersions adds the collection of the given schema versions, ordered from the initial
+// version to the current one, as shared by another node.
+//
+// The versions are replayed and the ID of each is recomputed, and must match the given one, so
+// that the collection is the same as that of the node sharing it.
+//
+// The versions are unsigned, so they can't be added if a schema admin identity is configured, in
+// which case the signed updates that produced them must be replayed instead.
+func (db *db) addSchemaVersions(
+	ctx context.Context,
+	txn datastore.Txn,
+	versions []client.CollectionDescription,
+) (client.Collection, error) {
+	if db.schemaAdmin != nil {
+		return nil, ErrSchemaSignatureRequired
+	}
+	if len(versions) == 0 {
+		return nil, ErrSchemaVersionsEmpty
+	}
+
+	initial := versions[0]
+	desc := initial
+	desc.ID = 0
+	desc.Schema.SchemaID = ""
+	desc.Schema.VersionID = ""
+	desc.Schema.Fields = append([]client.FieldDescription{}, initial.Schema.Fields...)
+	col, err := db.createCollection(ctx, txn, desc)
+	if err != nil {
+		return nil, err
+	}
+	if initial.Schema.VersionID != initial.Schema.SchemaID || col.SchemaID() != initial.Schema.SchemaID {
+		return nil, NewErrSchemaVersionIDDoesntMatch(initial.Name, initial.Schema.VersionID, col.SchemaID())
+	}
+
+	for _, version := range versions[1:] {
+		// Fields are only appended, the existing ones are kept as they are, and the IDs of the new
+		// ones are assigned on update, as when patching the schema.
+		existingFields := col.Schema().Fields
+		if len(version.Schema.Fields) < len(existingFields) {
+			return nil, NewErrSchemaVersionIDDoesntMatch(version.Name, version.Schema.VersionID, "")
+		}
+		desc := version
+		desc.ID = col.ID()
+		desc.Schema.VersionID = col.Schema().VersionID
+		desc.Schema.Fields = append([]client.FieldDescription{}, existingFields...)
+		for _, field := range version.Schema.Fields[len(existingFields):] {
+			field.ID = 0
+			desc.Schema.Fields = append(desc.Schema.Fields, field)
+		}
+
+		col, err = db.updateCollection(ctx, txn, desc)
+		if err != nil {
+			return nil, err
+		}
+		if col.Schema().VersionID != version.Schema.VersionID {
+			return nil, NewErrSchemaVersionIDDoesntMatch(
+				version.Name,
+				version.Schema.VersionID,
+				col.Schema().VersionID,
+			)
+		}
+	}
+
+	return col, db.loadSchema(ctx, txn)
+}
+
 // substituteSchemaPatch handles any substitution of values that may be required before
 // the patch can be applied.
 //
@@ -0,0 +1,88 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddSchemaVersionsSharesPatchedSchema(t *testing.T) {
+	ctx := context.Background()
+	source, err := newMemoryDB(ctx)
+	require.NoError(t, err)
+	defer source.Close(ctx)
+
+	err = source.AddSchema(ctx, `type users { Name: String }`)
+	require.NoError(t, err)
+	for _, field := range []string{"Email", "City"} {
+		err = source.PatchSchema(ctx, `[{ "op": "add", "path": "/users/Schema/Fields/-", "value": {
+			"Name": "`+field+`", "Kind": "String"
+		}}]`)
+		require.NoError(t, err)
+	}
+	col, err := source.GetCollectionByName(ctx, "users")
+	require.NoError(t, err)
+
+	versions, err := source.GetSchemaVersions(ctx, col.SchemaID())
+	require.NoError(t, err)
+	require.Len(t, versions, 3)
+	assert.Equal(t, col.SchemaID(), versions[0].Schema.VersionID)
+	assert.Equal(t, col.Schema().VersionID, versions[2].Schema.VersionID)
+
+	target, err := newMemoryDB(ctx)
+	require.NoError(t, err)
+	defer target.Close(ctx)
+
+	// The local collection ID of the target differs from that of the source.
+	err = target.AddSchema(ctx, `type books { Title: String }`)
+	require.NoError(t, err)
+
+	adopted, err := target.AddSchemaVersions(ctx, versions)
+	require.NoError(t, err)
+	assert.Equal(t, col.SchemaID(), adopted.SchemaID())
+	assert.Equal(t, col.Schema().VersionID, adopted.Schema().VersionID)
+
+	res := target.ExecRequest(ctx, `mutation {
+		create_users(data: "{\"Name\": \"John\", \"City\": \"Paris\"}") { City }
+	}`)
+	require.Empty(t, res.GQL.Errors)
+	assert.Equal(t, []map[string]any{{"City": "Paris"}}, res.GQL.Data)
+}
+
+func TestAddSchemaVersionsWithTamperedVersion(t *testing.T) {
+	ctx := context.Background()
+	source, err := newMemoryDB(ctx)
+	require.NoError(t, err)
+	defer source.Close(ctx)
+
+	err = source.AddSchema(ctx, `type users { Name: String }`)
+	require.NoError(t, err)
+	col, err := source.GetCollectionByName(ctx, "users")
+	require.NoError(t, err)
+	versions, err := source.GetSchemaVersions(ctx, col.SchemaID())
+	require.NoError(t, err)
+
+	versions[0].Schema.Fields[1].Name = "Email"
+
+	target, err := newMemoryDB(ctx)
+	require.NoError(t, err)
+	defer target.Close(ctx)
+
+	_, err = target.AddSchemaVersions(ctx, versions)
+	require.ErrorIs(t, err, ErrSchemaVersionIDDoesntMatch)
+
+	_, err = target.GetCollectionByName(ctx, "users")
+	require.Error(t, err)
+}
@@ -0,0 +1,87 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+	"encoding/json"
+
+	ds "github.com/ipfs/go-datastore"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/core"
+	"github.com/sourcenetwork/defradb/datastore"
+)
+
+// verifySchemaUpdate verifies that the given schema update is signed by the schema admin identity,
+// if one is configured, and returns the signed update.
+//
+// The returned update has no signature if no schema admin identity is configured, in which case
+// it is not recorded.
+func (db *db) verifySchemaUpdate(
+	ctx context.Context,
+	kind client.SchemaUpdateKind,
+	update string,
+) (client.SignedSchemaUpdate, error) {
+	if db.schemaAdmin == nil {
+		return client.SignedSchemaUpdate{Kind: kind, Update: update}, nil
+	}
+
+	signature, ok := client.SchemaSignatureFromContext(ctx)
+	if !ok || len(signature) == 0 {
+		return client.SignedSchemaUpdate{}, ErrSchemaSignatureRequired
+	}
+	valid, err := db.schemaAdmin.Verify(client.SchemaUpdatePayload(kind, update), signature)
+	if err != nil || !valid {
+		return client.SignedSchemaUpdate{}, ErrInvalidSchemaSignature
+	}
+
+	return client.SignedSchemaUpdate{Kind: kind, Update: update, Signature: signature}, nil
+}
+
+// putSignedSchemaUpdate records the given signed update as the one that produced the schema
+// version with the given ID, so that it can be verified by the other nodes.
+func putSignedSchemaUpdate(
+	ctx context.Context,
+	txn datastore.Txn,
+	schemaVersionID string,
+	update client.SignedSchemaUpdate,
+) error {
+	if len(update.Signature) == 0 {
+		return nil
+	}
+
+	buf, err := json.Marshal(update)
+	if err != nil {
+		return err
+	}
+	return txn.Systemstore().Put(ctx, core.NewSchemaUpdateKey(schemaVersionID).ToDS(), buf)
+}
+
+func (db *db) getSignedSchemaUpdate(
+	ctx context.Context,
+	txn datastore.Txn,
+	schemaVersionID string,
+) (client.SignedSchemaUpdate, error) {
+	buf, err := txn.Systemstore().Get(ctx, core.NewSchemaUpdateKey(schemaVersionID).ToDS())
+	if err != nil {
+		if err == ds.ErrNotFound {
+			return client.SignedSchemaUpdate{}, NewErrSignedSchemaUpdateNotFound(schemaVersionID)
+		}
+		return client.SignedSchemaUpdate{}, err
+	}
+
+	var update client.SignedSchemaUpdate
+	if err := json.Unmarshal(buf, &update); err != nil {
+		return client.SignedSchemaUpdate{}, err
+	}
+	return update, nil
+}
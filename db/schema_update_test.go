@@ -0,0 +1,131 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/errors"
+)
+
+func signSchemaUpdate(
+	t *testing.T,
+	ctx context.Context,
+	key crypto.PrivKey,
+	kind client.SchemaUpdateKind,
+	update string,
+) context.Context {
+	signature, err := key.Sign(client.SchemaUpdatePayload(kind, update))
+	require.NoError(t, err)
+	return client.WithSchemaSignature(ctx, signature)
+}
+
+func TestSignedSchemaUpdates(t *testing.T) {
+	ctx := context.Background()
+	admin, adminPub, err := crypto.GenerateKeyPair(crypto.Ed25519, 0)
+	require.NoError(t, err)
+	db, err := newMemoryDB(ctx, WithSchemaAdmin(adminPub))
+	require.NoError(t, err)
+	defer db.Close(ctx)
+
+	schema := `type users { Name: String }`
+	err = db.AddSchema(signSchemaUpdate(t, ctx, admin, client.SchemaUpdateAdd, schema), schema)
+	require.NoError(t, err)
+	col, err := db.GetCollectionByName(ctx, "users")
+	require.NoError(t, err)
+
+	update, err := db.GetSignedSchemaUpdate(ctx, col.Schema().VersionID)
+	require.NoError(t, err)
+	assert.Equal(t, client.SchemaUpdateAdd, update.Kind)
+	assert.Equal(t, schema, update.Update)
+
+	patch := `[{ "op": "add", "path": "/users/Schema/Fields/-", "value": {"Name": "Email", "Kind": "String"} }]`
+	err = db.PatchSchema(signSchemaUpdate(t, ctx, admin, client.SchemaUpdatePatch, patch), patch)
+	require.NoError(t, err)
+	col, err = db.GetCollectionByName(ctx, "users")
+	require.NoError(t, err)
+
+	update, err = db.GetSignedSchemaUpdate(ctx, col.Schema().VersionID)
+	require.NoError(t, err)
+	assert.Equal(t, client.SchemaUpdatePatch, update.Kind)
+	assert.Equal(t, patch, update.Update)
+}
+
+func TestSignedSchemaUpdatesWithoutSignature(t *testing.T) {
+	ctx := context.Background()
+	_, adminPub, err := crypto.GenerateKeyPair(crypto.Ed25519, 0)
+	require.NoError(t, err)
+	db, err := newMemoryDB(ctx, WithSchemaAdmin(adminPub))
+	require.NoError(t, err)
+	defer db.Close(ctx)
+
+	err = db.AddSchema(ctx, `type users { Name: String }`)
+	require.ErrorIs(t, err, ErrSchemaSignatureRequired)
+	assert.Equal(t, errors.CodeUnauthorized, errors.CodeOf(err))
+
+	_, err = db.GetCollectionByName(ctx, "users")
+	require.Error(t, err)
+}
+
+func TestSignedSchemaUpdatesWithInvalidSignature(t *testing.T) {
+	ctx := context.Background()
+	admin, adminPub, err := crypto.GenerateKeyPair(crypto.Ed25519, 0)
+	require.NoError(t, err)
+	other, _, err := crypto.GenerateKeyPair(crypto.Ed25519, 0)
+	require.NoError(t, err)
+	db, err := newMemoryDB(ctx, WithSchemaAdmin(adminPub))
+	require.NoError(t, err)
+	defer db.Close(ctx)
+
+	schema := `type users { Name: String }`
+	err = db.AddSchema(signSchemaUpdate(t, ctx, other, client.SchemaUpdateAdd, schema), schema)
+	require.ErrorIs(t, err, ErrInvalidSchemaSignature)
+
+	// The signature of the schema can't be used for another one.
+	err = db.AddSchema(signSchemaUpdate(t, ctx, admin, client.SchemaUpdateAdd, schema), `type books { Title: String }`)
+	require.ErrorIs(t, err, ErrInvalidSchemaSignature)
+
+	// Nor for a patch.
+	err = db.PatchSchema(signSchemaUpdate(t, ctx, admin, client.SchemaUpdateAdd, schema), schema)
+	require.ErrorIs(t, err, ErrInvalidSchemaSignature)
+}
+
+func TestSignedSchemaUpdatesRejectsUnsignedVersions(t *testing.T) {
+	ctx := context.Background()
+	source, err := newMemoryDB(ctx)
+	require.NoError(t, err)
+	defer source.Close(ctx)
+
+	err = source.AddSchema(ctx, `type users { Name: String }`)
+	require.NoError(t, err)
+	col, err := source.GetCollectionByName(ctx, "users")
+	require.NoError(t, err)
+	versions, err := source.GetSchemaVersions(ctx, col.SchemaID())
+	require.NoError(t, err)
+
+	_, err = source.GetSignedSchemaUpdate(ctx, col.Schema().VersionID)
+	require.ErrorIs(t, err, ErrSignedSchemaUpdateNotFound)
+
+	_, adminPub, err := crypto.GenerateKeyPair(crypto.Ed25519, 0)
+	require.NoError(t, err)
+	target, err := newMemoryDB(ctx, WithSchemaAdmin(adminPub))
+	require.NoError(t, err)
+	defer target.Close(ctx)
+
+	_, err = target.AddSchemaVersions(ctx, versions)
+	require.ErrorIs(t, err, ErrSchemaSignatureRequired)
+}
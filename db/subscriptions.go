@@ -52,6 +52,10 @@ func (db *db) handleSubscription(
 	r *request.ObjectSubscription,
 ) {
 	for evt := range pub.Event() {
+		if evt.IsRemote {
+			continue
+		}
+
 		txn, err := db.NewTxn(ctx, false)
 		if err != nil {
 			log.Error(ctx, err.Error())
@@ -75,6 +79,15 @@ func (db *db) handleSubscription(
 			continue
 		}
 
+		// The subscription payloads are shared outside of operations, so they are masked.
+		err = db.maskResults(ctx, txn, r.Collection, s.Fields, result)
+		if err != nil {
+			pub.Publish(client.GQLResult{
+				Errors: []error{err},
+			})
+			continue
+		}
+
 		pub.Publish(client.GQLResult{
 			Data: result,
 		})
@@ -13,6 +13,8 @@ package db
 import (
 	"context"
 
+	"github.com/graphql-go/graphql/language/ast"
+
 	"github.com/sourcenetwork/defradb/client"
 	"github.com/sourcenetwork/defradb/datastore"
 )
@@ -32,26 +34,61 @@ type explicitTxnDB struct {
 }
 
 // ExecRequest executes a request against the database.
+//
+// The request runs on a new transaction, at the isolation level given by the context, which is
+// read only if the request has no mutation. If enabled, the request is retried on a new
+// transaction when its transaction conflicts with another one.
 func (db *implicitTxnDB) ExecRequest(ctx context.Context, request string) *client.RequestResult {
-	txn, err := db.NewTxn(ctx, false)
+	ast, err := db.parser.BuildRequestAST(request)
 	if err != nil {
-		res := &client.RequestResult{}
+		return &client.RequestResult{GQL: client.GQLResult{Errors: []error{err}}}
+	}
+
+	var res *client.RequestResult
+	err = db.retryTxnConflicts(ctx, func() error {
+		var err error
+		res, err = db.execRequestOnNewTxn(ctx, request, ast)
+		return err
+	})
+	if err != nil {
+		if res == nil {
+			res = &client.RequestResult{}
+		}
 		res.GQL.Errors = []error{err}
-		return res
+	}
+	return res
+}
+
+// execRequestOnNewTxn executes a request on a new transaction and commits it.
+//
+// Returns an error if the transaction can't be created or committed, in which case the result
+// may be nil.
+func (db *implicitTxnDB) execRequestOnNewTxn(
+	ctx context.Context,
+	request string,
+	ast *ast.Document,
+) (*client.RequestResult, error) {
+	txn, err := db.newRequestTxn(ctx, isReadOnlyRequest(ast))
+	if err != nil {
+		return nil, err
 	}
 	defer txn.Discard(ctx)
 
-	res := db.execRequest(ctx, request, txn)
+	res := db.execRequest(ctx, request, ast, txn)
 	if len(res.GQL.Errors) > 0 {
-		return res
+		return res, nil
 	}
 
-	if err := txn.Commit(ctx); err != nil {
-		res.GQL.Errors = []error{err}
-		return res
-	}
+	return res, txn.Commit(ctx)
+}
 
-	return res
+// newRequestTxn returns a new transaction to run a request on, at the isolation level given by
+// the context.
+func (db *implicitTxnDB) newRequestTxn(ctx context.Context, readonly bool) (datastore.Txn, error) {
+	if client.IsolationLevelFromContext(ctx) == client.ReadCommittedIsolation {
+		return datastore.NewReadCommittedTxnFrom(db.rootstore), nil
+	}
+	return db.NewTxn(ctx, readonly)
 }
 
 // ExecRequest executes a transaction request against the database.
@@ -59,7 +96,11 @@ func (db *explicitTxnDB) ExecRequest(
 	ctx context.Context,
 	request string,
 ) *client.RequestResult {
-	return db.execRequest(ctx, request, db.txn)
+	ast, err := db.parser.BuildRequestAST(request)
+	if err != nil {
+		return &client.RequestResult{GQL: client.GQLResult{Errors: []error{err}}}
+	}
+	return db.execRequest(ctx, request, ast, db.txn)
 }
 
 // GetCollectionByName returns an existing collection within the database.
@@ -170,6 +211,82 @@ func (db *explicitTxnDB) RemoveP2PCollection(ctx context.Context, collectionID s
 	return db.removeP2PCollection(ctx, db.txn, collectionID)
 }
 
+// GetKV returns the value of the given key in the given namespace of the key-value store.
+func (db *implicitTxnDB) GetKV(ctx context.Context, namespace string, key string) ([]byte, error) {
+	txn, err := db.NewTxn(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	defer txn.Discard(ctx)
+
+	return db.getKV(ctx, txn, namespace, key)
+}
+
+// GetKV returns the value of the given key in the given namespace of the key-value store.
+func (db *explicitTxnDB) GetKV(ctx context.Context, namespace string, key string) ([]byte, error) {
+	return db.getKV(ctx, db.txn, namespace, key)
+}
+
+// SetKV sets the value of the given key in the given namespace of the key-value store.
+func (db *implicitTxnDB) SetKV(ctx context.Context, namespace string, key string, value []byte) error {
+	txn, err := db.NewTxn(ctx, false)
+	if err != nil {
+		return err
+	}
+	defer txn.Discard(ctx)
+
+	err = db.setKV(ctx, txn, namespace, key, value)
+	if err != nil {
+		return err
+	}
+
+	return txn.Commit(ctx)
+}
+
+// SetKV sets the value of the given key in the given namespace of the key-value store.
+func (db *explicitTxnDB) SetKV(ctx context.Context, namespace string, key string, value []byte) error {
+	return db.setKV(ctx, db.txn, namespace, key, value)
+}
+
+// DeleteKV deletes the value of the given key in the given namespace of the key-value store.
+func (db *implicitTxnDB) DeleteKV(ctx context.Context, namespace string, key string) error {
+	txn, err := db.NewTxn(ctx, false)
+	if err != nil {
+		return err
+	}
+	defer txn.Discard(ctx)
+
+	err = db.deleteKV(ctx, txn, namespace, key)
+	if err != nil {
+		return err
+	}
+
+	return txn.Commit(ctx)
+}
+
+// DeleteKV deletes the value of the given key in the given namespace of the key-value store.
+func (db *explicitTxnDB) DeleteKV(ctx context.Context, namespace string, key string) error {
+	return db.deleteKV(ctx, db.txn, namespace, key)
+}
+
+// GetAllKV returns the entries of the given namespace of the key-value store whose key starts
+// with the given prefix, ordered by key.
+func (db *implicitTxnDB) GetAllKV(ctx context.Context, namespace string, prefix string) ([]client.KVEntry, error) {
+	txn, err := db.NewTxn(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	defer txn.Discard(ctx)
+
+	return db.getAllKV(ctx, txn, namespace, prefix)
+}
+
+// GetAllKV returns the entries of the given namespace of the key-value store whose key starts
+// with the given prefix, ordered by key.
+func (db *explicitTxnDB) GetAllKV(ctx context.Context, namespace string, prefix string) ([]client.KVEntry, error) {
+	return db.getAllKV(ctx, db.txn, namespace, prefix)
+}
+
 // GetAllCollections gets all the currently defined collections.
 func (db *implicitTxnDB) GetAllCollections(ctx context.Context) ([]client.Collection, error) {
 	txn, err := db.NewTxn(ctx, true)
@@ -256,6 +373,112 @@ func (db *explicitTxnDB) PatchSchema(ctx context.Context, patchString string) er
 	return db.patchSchema(ctx, db.txn, patchString)
 }
 
+// GetSchemaVersions returns the descriptions of all the versions of the schema with the given
+// schema ID, ordered from the initial version to the current one.
+func (db *implicitTxnDB) GetSchemaVersions(
+	ctx context.Context,
+	schemaID string,
+) ([]client.CollectionDescription, error) {
+	txn, err := db.NewTxn(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	defer txn.Discard(ctx)
+
+	return db.getSchemaVersions(ctx, txn, schemaID)
+}
+
+// GetSchemaVersions returns the descriptions of all the versions of the schema with the given
+// schema ID, ordered from the initial version to the current one.
+func (db *explicitTxnDB) GetSchemaVersions(
+	ctx context.Context,
+	schemaID string,
+) ([]client.CollectionDescription, error) {
+	return db.getSchemaVersions(ctx, db.txn, schemaID)
+}
+
+// AddSchemaVersions adds the collection of the given schema versions, as returned by
+// GetSchemaVersions on another node, so that the collection is shared between them.
+func (db *implicitTxnDB) AddSchemaVersions(
+	ctx context.Context,
+	versions []client.CollectionDescription,
+) (client.Collection, error) {
+	txn, err := db.NewTxn(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	defer txn.Discard(ctx)
+
+	col, err := db.addSchemaVersions(ctx, txn, versions)
+	if err != nil {
+		return nil, err
+	}
+
+	return col, txn.Commit(ctx)
+}
+
+// AddSchemaVersions adds the collection of the given schema versions, as returned by
+// GetSchemaVersions on another node, so that the collection is shared between them.
+func (db *explicitTxnDB) AddSchemaVersions(
+	ctx context.Context,
+	versions []client.CollectionDescription,
+) (client.Collection, error) {
+	return db.addSchemaVersions(ctx, db.txn, versions)
+}
+
+// CloneCollection creates a collection with the current schema of the source collection under a
+// new name, and copies the documents of the source collection into it.
+func (db *implicitTxnDB) CloneCollection(
+	ctx context.Context,
+	opts client.CloneCollectionOptions,
+) (client.CloneCollectionResult, error) {
+	txn, err := db.NewTxn(ctx, false)
+	if err != nil {
+		return client.CloneCollectionResult{}, err
+	}
+	defer txn.Discard(ctx)
+
+	result, err := db.cloneCollection(ctx, txn, opts)
+	if err != nil {
+		return client.CloneCollectionResult{}, err
+	}
+
+	return result, txn.Commit(ctx)
+}
+
+// CloneCollection creates a collection with the current schema of the source collection under a
+// new name, and copies the documents of the source collection into it.
+func (db *explicitTxnDB) CloneCollection(
+	ctx context.Context,
+	opts client.CloneCollectionOptions,
+) (client.CloneCollectionResult, error) {
+	return db.cloneCollection(ctx, db.txn, opts)
+}
+
+// GetSignedSchemaUpdate returns the signed schema update that produced the schema version with
+// the given ID.
+func (db *implicitTxnDB) GetSignedSchemaUpdate(
+	ctx context.Context,
+	schemaVersionID string,
+) (client.SignedSchemaUpdate, error) {
+	txn, err := db.NewTxn(ctx, true)
+	if err != nil {
+		return client.SignedSchemaUpdate{}, err
+	}
+	defer txn.Discard(ctx)
+
+	return db.getSignedSchemaUpdate(ctx, txn, schemaVersionID)
+}
+
+// GetSignedSchemaUpdate returns the signed schema update that produced the schema version with
+// the given ID.
+func (db *explicitTxnDB) GetSignedSchemaUpdate(
+	ctx context.Context,
+	schemaVersionID string,
+) (client.SignedSchemaUpdate, error) {
+	return db.getSignedSchemaUpdate(ctx, db.txn, schemaVersionID)
+}
+
 // SetReplicator adds a new replicator to the database.
 func (db *implicitTxnDB) SetReplicator(ctx context.Context, rep client.Replicator) error {
 	txn, err := db.NewTxn(ctx, false)
@@ -0,0 +1,44 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/datastore"
+)
+
+func TestExecRequestWithReadCommittedIsolation(t *testing.T) {
+	ctx := context.Background()
+	db, err := newMemoryDB(ctx)
+	require.NoError(t, err)
+	defer db.Close(ctx)
+
+	err = db.AddSchema(ctx, `type users { Name: String }`)
+	require.NoError(t, err)
+	res := db.ExecRequest(ctx, `mutation { create_users(data: "{\"Name\": \"John\"}") { _key } }`)
+	require.Empty(t, res.GQL.Errors)
+
+	readCommittedCtx := client.WithIsolationLevel(ctx, client.ReadCommittedIsolation)
+
+	res = db.ExecRequest(readCommittedCtx, `query { users { Name } }`)
+	require.Empty(t, res.GQL.Errors)
+	assert.Equal(t, []map[string]any{{"Name": "John"}}, res.GQL.Data)
+
+	res = db.ExecRequest(readCommittedCtx, `mutation { create_users(data: "{\"Name\": \"Fred\"}") { _key } }`)
+	require.Len(t, res.GQL.Errors, 1)
+	assert.ErrorIs(t, res.GQL.Errors[0], datastore.ErrReadOnlyTxn)
+}
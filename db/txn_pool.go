@@ -0,0 +1,149 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/datastore"
+)
+
+// txnPool keeps track of the open transactions, and limits the number of concurrent read only
+// transactions if a maximum is set.
+//
+// Each open read only transaction holds a snapshot of the datastore, which keeps the memory of
+// the snapshot from being released. The read only transactions in excess are thus queued until
+// others end, instead of being opened at once.
+type txnPool struct {
+	// slots holds a token per open read only transaction, or is nil if their number is unlimited.
+	slots chan struct{}
+
+	activeReads      atomic.Int64
+	activeWrites     atomic.Int64
+	queuedReads      atomic.Int64
+	totalReads       atomic.Uint64
+	totalWrites      atomic.Uint64
+	totalQueuedReads atomic.Uint64
+}
+
+// newTxnPool returns a new pool allowing up to the given number of concurrent read only
+// transactions, or an unlimited number if it is not positive.
+func newTxnPool(maxReads int) *txnPool {
+	pool := &txnPool{}
+	if maxReads > 0 {
+		pool.slots = make(chan struct{}, maxReads)
+	}
+	return pool
+}
+
+// open opens a new transaction with the given function, once a slot is available if it is read
+// only, and returns it wrapped so that its slot is released once it is committed or discarded.
+//
+// Returns ErrReadTxnNotAcquired if the given context is done while the transaction is queued.
+func (p *txnPool) open(
+	ctx context.Context,
+	readonly bool,
+	newTxn func() (datastore.Txn, error),
+) (datastore.Txn, error) {
+	if !readonly {
+		txn, err := newTxn()
+		if err != nil {
+			return nil, err
+		}
+		p.totalWrites.Add(1)
+		p.activeWrites.Add(1)
+		return &pooledTxn{Txn: txn, release: func() { p.activeWrites.Add(-1) }}, nil
+	}
+
+	if err := p.acquireRead(ctx); err != nil {
+		return nil, err
+	}
+	txn, err := newTxn()
+	if err != nil {
+		p.releaseRead()
+		return nil, err
+	}
+	p.totalReads.Add(1)
+	return &pooledTxn{Txn: txn, release: p.releaseRead}, nil
+}
+
+// acquireRead waits for a slot for a read only transaction to be available and takes it.
+func (p *txnPool) acquireRead(ctx context.Context) error {
+	if p.slots != nil {
+		select {
+		case p.slots <- struct{}{}:
+		default:
+			p.totalQueuedReads.Add(1)
+			p.queuedReads.Add(1)
+			defer p.queuedReads.Add(-1)
+
+			select {
+			case p.slots <- struct{}{}:
+			case <-ctx.Done():
+				return NewErrReadTxnNotAcquired(ctx.Err())
+			}
+		}
+	}
+	p.activeReads.Add(1)
+	return nil
+}
+
+// releaseRead releases the slot of a read only transaction.
+func (p *txnPool) releaseRead() {
+	p.activeReads.Add(-1)
+	if p.slots != nil {
+		<-p.slots
+	}
+}
+
+// stats returns the statistics of the transactions of the pool.
+func (p *txnPool) stats() client.TxnStats {
+	return client.TxnStats{
+		ActiveReads:      p.activeReads.Load(),
+		ActiveWrites:     p.activeWrites.Load(),
+		QueuedReads:      p.queuedReads.Load(),
+		MaxReads:         cap(p.slots),
+		TotalReads:       p.totalReads.Load(),
+		TotalWrites:      p.totalWrites.Load(),
+		TotalQueuedReads: p.totalQueuedReads.Load(),
+	}
+}
+
+// pooledTxn is a transaction of a pool, which releases its slot once it ends.
+type pooledTxn struct {
+	datastore.Txn
+
+	release func()
+	once    sync.Once
+}
+
+// Commit commits the transaction and releases its slot.
+func (t *pooledTxn) Commit(ctx context.Context) error {
+	defer t.once.Do(t.release)
+	return t.Txn.Commit(ctx)
+}
+
+// Discard discards the transaction and releases its slot.
+//
+// It may be called after the transaction has been committed, in which case the slot has
+// already been released.
+func (t *pooledTxn) Discard(ctx context.Context) {
+	defer t.once.Do(t.release)
+	t.Txn.Discard(ctx)
+}
+
+// TxnStats returns the statistics of the transactions opened by the database.
+func (db *db) TxnStats() client.TxnStats {
+	return db.txns.stats()
+}
@@ -0,0 +1,121 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxnStatsOfRequests(t *testing.T) {
+	ctx := context.Background()
+	db, err := newMemoryDB(ctx)
+	require.NoError(t, err)
+	defer db.Close(ctx)
+
+	err = db.AddSchema(ctx, `type users { Name: String }`)
+	require.NoError(t, err)
+	before := db.TxnStats()
+
+	res := db.ExecRequest(ctx, `mutation { create_users(data: "{\"Name\": \"John\"}") { _key } }`)
+	require.Empty(t, res.GQL.Errors)
+	res = db.ExecRequest(ctx, `query { users { Name } }`)
+	require.Empty(t, res.GQL.Errors)
+
+	stats := db.TxnStats()
+	assert.Equal(t, before.TotalWrites+1, stats.TotalWrites)
+	assert.Equal(t, before.TotalReads+1, stats.TotalReads)
+	assert.Equal(t, int64(0), stats.ActiveReads)
+	assert.Equal(t, int64(0), stats.ActiveWrites)
+}
+
+func TestMaxReadTxnsQueuesReadTxns(t *testing.T) {
+	ctx := context.Background()
+	db, err := newMemoryDB(ctx, WithMaxReadTxns(1))
+	require.NoError(t, err)
+	defer db.Close(ctx)
+
+	txn, err := db.NewTxn(ctx, true)
+	require.NoError(t, err)
+
+	// Read-write transactions are not limited.
+	writeTxn, err := db.NewTxn(ctx, false)
+	require.NoError(t, err)
+	writeTxn.Discard(ctx)
+
+	opened := make(chan error)
+	go func() {
+		queuedTxn, err := db.NewTxn(ctx, true)
+		if err == nil {
+			queuedTxn.Discard(ctx)
+		}
+		opened <- err
+	}()
+
+	require.Eventually(t, func() bool {
+		return db.TxnStats().QueuedReads == 1
+	}, time.Second, time.Millisecond)
+	stats := db.TxnStats()
+	assert.Equal(t, int64(1), stats.ActiveReads)
+	assert.Equal(t, int64(0), stats.ActiveWrites)
+	assert.Equal(t, 1, stats.MaxReads)
+	assert.Equal(t, uint64(1), stats.TotalQueuedReads)
+
+	txn.Discard(ctx)
+	require.NoError(t, <-opened)
+
+	stats = db.TxnStats()
+	assert.Equal(t, int64(0), stats.ActiveReads)
+	assert.Equal(t, int64(0), stats.QueuedReads)
+	assert.Equal(t, uint64(2), stats.TotalReads)
+}
+
+func TestMaxReadTxnsWithContextDoneWhileQueued(t *testing.T) {
+	ctx := context.Background()
+	db, err := newMemoryDB(ctx, WithMaxReadTxns(1))
+	require.NoError(t, err)
+	defer db.Close(ctx)
+
+	txn, err := db.NewTxn(ctx, true)
+	require.NoError(t, err)
+	defer txn.Discard(ctx)
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, err = db.NewTxn(timeoutCtx, true)
+	require.ErrorIs(t, err, ErrReadTxnNotAcquired)
+
+	stats := db.TxnStats()
+	assert.Equal(t, int64(1), stats.ActiveReads)
+	assert.Equal(t, int64(0), stats.QueuedReads)
+}
+
+func TestMaxReadTxnsReleasesCommittedTxn(t *testing.T) {
+	ctx := context.Background()
+	db, err := newMemoryDB(ctx, WithMaxReadTxns(1))
+	require.NoError(t, err)
+	defer db.Close(ctx)
+
+	for i := 0; i < 3; i++ {
+		txn, err := db.NewTxn(ctx, true)
+		require.NoError(t, err)
+		err = txn.Commit(ctx)
+		require.NoError(t, err)
+		// Discarding a committed transaction must not release its slot twice.
+		txn.Discard(ctx)
+	}
+
+	assert.Equal(t, int64(0), db.TxnStats().ActiveReads)
+}
@@ -0,0 +1,73 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/datastore"
+	"github.com/sourcenetwork/defradb/logging"
+)
+
+// retryTxnConflicts calls the given function, which is expected to run on a new transaction,
+// until it doesn't return a transaction conflict error.
+//
+// The function is only called once if the retry of conflicting transactions isn't enabled.
+// Otherwise it is called up to the maximum number of retries per transaction, waiting for an
+// exponentially increasing delay between calls, and ErrMaxTxnRetries is returned if all of
+// the calls conflict.
+func (db *db) retryTxnConflicts(ctx context.Context, fn func() error) error {
+	err := fn()
+	if !db.txnRetryBackoff.HasValue() {
+		return err
+	}
+
+	for retry := 1; retry < db.MaxTxnRetries(); retry++ {
+		if !datastore.IsTxnConflict(err) {
+			return err
+		}
+
+		delay := txnRetryDelay(db.txnRetryBackoff.Value(), retry)
+		log.Debug(
+			ctx,
+			"Retrying conflicting transaction",
+			logging.NewKV("Retry", retry),
+			logging.NewKV("Delay", delay),
+		)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+
+		err = fn()
+	}
+
+	if datastore.IsTxnConflict(err) {
+		return client.NewErrMaxTxnRetries(err)
+	}
+	return err
+}
+
+// txnRetryDelay returns the delay to wait for before the given retry.
+//
+// The delay doubles with each retry, and is jittered so that the retries of transactions that
+// conflicted together are less likely to conflict again.
+func txnRetryDelay(backoff time.Duration, retry int) time.Duration {
+	delay := backoff << (retry - 1)
+	if delay <= 0 {
+		return 0
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
@@ -0,0 +1,119 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/datastore"
+)
+
+func TestRetryTxnConflictsWithoutBackoff(t *testing.T) {
+	ctx := context.Background()
+	db, err := newMemoryDB(ctx)
+	require.NoError(t, err)
+	defer db.Close(ctx)
+
+	calls := 0
+	err = db.retryTxnConflicts(ctx, func() error {
+		calls++
+		return datastore.ErrTxnConflict
+	})
+	require.ErrorIs(t, err, datastore.ErrTxnConflict)
+	assert.Equal(t, 1, calls)
+}
+
+func TestRetryTxnConflictsWithBackoff(t *testing.T) {
+	ctx := context.Background()
+	db, err := newMemoryDB(ctx)
+	require.NoError(t, err)
+	defer db.Close(ctx)
+	WithTxnRetryBackoff(time.Millisecond)(db.db)
+
+	calls := 0
+	err = db.retryTxnConflicts(ctx, func() error {
+		calls++
+		if calls < 3 {
+			return datastore.ErrTxnConflict
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls)
+}
+
+func TestRetryTxnConflictsWithBackoffAndOtherError(t *testing.T) {
+	ctx := context.Background()
+	db, err := newMemoryDB(ctx)
+	require.NoError(t, err)
+	defer db.Close(ctx)
+	WithTxnRetryBackoff(time.Millisecond)(db.db)
+
+	calls := 0
+	err = db.retryTxnConflicts(ctx, func() error {
+		calls++
+		return client.ErrDocumentNotFound
+	})
+	require.ErrorIs(t, err, client.ErrDocumentNotFound)
+	assert.Equal(t, 1, calls)
+}
+
+func TestRetryTxnConflictsWithBackoffAndMaxRetries(t *testing.T) {
+	ctx := context.Background()
+	db, err := newMemoryDB(ctx)
+	require.NoError(t, err)
+	defer db.Close(ctx)
+	WithTxnRetryBackoff(time.Millisecond)(db.db)
+	WithMaxRetries(3)(db.db)
+
+	calls := 0
+	err = db.retryTxnConflicts(ctx, func() error {
+		calls++
+		return datastore.ErrTxnConflict
+	})
+	require.ErrorIs(t, err, client.ErrMaxTxnRetries)
+	require.ErrorIs(t, err, datastore.ErrTxnConflict)
+	assert.Equal(t, 3, calls)
+}
+
+func TestRetryTxnConflictsWithBackoffAndCancelledContext(t *testing.T) {
+	ctx := context.Background()
+	db, err := newMemoryDB(ctx)
+	require.NoError(t, err)
+	defer db.Close(ctx)
+	WithTxnRetryBackoff(time.Hour)(db.db)
+
+	cancelledCtx, cancel := context.WithCancel(ctx)
+	cancel()
+
+	calls := 0
+	err = db.retryTxnConflicts(cancelledCtx, func() error {
+		calls++
+		return datastore.ErrTxnConflict
+	})
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, calls)
+}
+
+func TestTxnRetryDelay(t *testing.T) {
+	for retry := 1; retry < 5; retry++ {
+		maxDelay := time.Millisecond << (retry - 1)
+		delay := txnRetryDelay(time.Millisecond, retry)
+		assert.GreaterOrEqual(t, delay, maxDelay/2)
+		assert.LessOrEqual(t, delay, maxDelay)
+	}
+}
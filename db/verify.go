@@ -0,0 +1,211 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+	"time"
+
+	dag "github.com/ipfs/boxo/ipld/merkledag"
+	blocks "github.com/ipfs/go-block-format"
+	"github.com/ipfs/go-cid"
+	ipld "github.com/ipfs/go-ipld-format"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/datastore"
+	"github.com/sourcenetwork/defradb/errors"
+)
+
+// blockFetchTimeout is the time given to the block fetcher to fetch a block to repair.
+const blockFetchTimeout = 30 * time.Second
+
+// VerifyBlocks walks the DAGs of the documents from their current heads, verifying that each
+// block is stored and that its data matches its CID.
+//
+// The missing and corrupt blocks are reported, and re-fetched with the fetcher of the given
+// options if they are to be repaired, the walk then continuing from the repaired blocks.
+func (db *db) VerifyBlocks(
+	ctx context.Context,
+	opts client.VerifyBlocksOptions,
+) (client.VerifyBlocksReport, error) {
+	if opts.Repair && opts.Fetcher == nil {
+		return client.VerifyBlocksReport{}, ErrNoBlockFetcher
+	}
+
+	cols, err := db.getMigratedCollections(ctx, opts.Collections)
+	if err != nil {
+		return client.VerifyBlocksReport{}, err
+	}
+
+	report := client.VerifyBlocksReport{Issues: []client.BlockIssue{}}
+	verified := map[cid.Cid]struct{}{}
+	for _, col := range cols {
+		if err := db.verifyCollectionBlocks(ctx, opts, col, verified, &report); err != nil {
+			return client.VerifyBlocksReport{}, err
+		}
+	}
+	return report, nil
+}
+
+// verifyCollectionBlocks verifies the blocks of the DAGs of the documents of the given collection.
+func (db *db) verifyCollectionBlocks(
+	ctx context.Context,
+	opts client.VerifyBlocksOptions,
+	col *collection,
+	verified map[cid.Cid]struct{},
+	report *client.VerifyBlocksReport,
+) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	docs, err := col.GetAllDocKeysWithHeads(ctx)
+	if err != nil {
+		return err
+	}
+	for doc := range docs {
+		if doc.Err != nil {
+			return doc.Err
+		}
+		report.Documents++
+		err := db.verifyDocBlocks(ctx, opts, col.Name(), doc.Key.String(), doc.Heads, verified, report)
+		if err != nil {
+			// The channel is drained so that the query of the keys is closed.
+			cancel()
+			for range docs {
+			}
+			return err
+		}
+	}
+	// The channel is closed early if the context is done.
+	return ctx.Err()
+}
+
+// verifyDocBlocks verifies the blocks of the DAG of the given document reachable from the given
+// heads, skipping the given blocks already verified and adding the blocks it verifies to them.
+func (db *db) verifyDocBlocks(
+	ctx context.Context,
+	opts client.VerifyBlocksOptions,
+	colName string,
+	docKey string,
+	heads []cid.Cid,
+	verified map[cid.Cid]struct{},
+	report *client.VerifyBlocksReport,
+) error {
+	queue := append([]cid.Cid{}, heads...)
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if _, ok := verified[current]; ok {
+			continue
+		}
+		verified[current] = struct{}{}
+		report.Blocks++
+
+		nd, problem, err := db.verifyBlock(ctx, current)
+		if err != nil {
+			return err
+		}
+		if problem != "" {
+			issue := client.BlockIssue{
+				Collection: colName,
+				DocKey:     docKey,
+				Cid:        current.String(),
+				Problem:    problem,
+			}
+			if opts.Repair {
+				nd, err = db.repairBlock(ctx, opts.Fetcher, current, problem)
+				if err != nil {
+					issue.Error = err.Error()
+				}
+				issue.Repaired = err == nil
+			}
+			report.Issues = append(report.Issues, issue)
+		}
+		if nd == nil {
+			// The links of a block that is neither stored nor repaired are unknown.
+			continue
+		}
+		for _, link := range nd.Links() {
+			queue = append(queue, link.Cid)
+		}
+	}
+	return nil
+}
+
+// verifyBlock returns the node of the block with the given CID, or the problem found with it.
+func (db *db) verifyBlock(ctx context.Context, c cid.Cid) (*dag.ProtoNode, client.BlockProblem, error) {
+	block, err := db.Blockstore().Get(ctx, c)
+	if ipld.IsNotFound(err) {
+		return nil, client.BlockMissing, nil
+	}
+	if errors.Is(err, datastore.ErrInvalidCompressedBlock) || errors.Is(err, datastore.ErrHashMismatch) {
+		return nil, client.BlockCorrupt, nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	nd, ok := validBlockNode(c, block.RawData())
+	if !ok {
+		return nil, client.BlockCorrupt, nil
+	}
+	return nd, "", nil
+}
+
+// repairBlock replaces the block with the given CID and problem by a copy fetched with the given
+// fetcher, and returns its node.
+func (db *db) repairBlock(
+	ctx context.Context,
+	fetcher ipld.NodeGetter,
+	c cid.Cid,
+	problem client.BlockProblem,
+) (*dag.ProtoNode, error) {
+	// The corrupt copy is deleted first so that a fetcher reading from the blockstore of the node
+	// before reaching out to its peers does not return it.
+	if problem == client.BlockCorrupt {
+		if err := db.Blockstore().DeleteBlock(ctx, c); err != nil {
+			return nil, err
+		}
+	}
+
+	fetchCtx, cancel := context.WithTimeout(ctx, blockFetchTimeout)
+	defer cancel()
+	fetched, err := fetcher.Get(fetchCtx, c)
+	if err != nil {
+		return nil, err
+	}
+	nd, ok := validBlockNode(c, fetched.RawData())
+	if !ok {
+		return nil, ErrRefetchedBlockMismatch
+	}
+
+	block, err := blocks.NewBlockWithCid(fetched.RawData(), c)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Blockstore().Put(ctx, block); err != nil {
+		return nil, err
+	}
+	return nd, nil
+}
+
+// validBlockNode returns the node decoded from the given data if it matches the given CID.
+func validBlockNode(c cid.Cid, data []byte) (*dag.ProtoNode, bool) {
+	sum, err := c.Prefix().Sum(data)
+	if err != nil || !sum.Equals(c) {
+		return nil, false
+	}
+	nd, err := dag.DecodeProtobuf(data)
+	if err != nil {
+		return nil, false
+	}
+	return nd, true
+}
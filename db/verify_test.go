@@ -0,0 +1,129 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ipfs/boxo/blockservice"
+	blockstore "github.com/ipfs/boxo/blockstore"
+	"github.com/ipfs/boxo/exchange/offline"
+	dag "github.com/ipfs/boxo/ipld/merkledag"
+	blocks "github.com/ipfs/go-block-format"
+	"github.com/ipfs/go-cid"
+	ds "github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/client"
+)
+
+// newVerifiedDoc returns the database of a collection holding a document, and the CID of the
+// current head of the document, with a copy of the blocks of the database in a block fetcher.
+func newVerifiedDoc(t *testing.T, ctx context.Context) (*db, cid.Cid, blockstore.Blockstore, client.VerifyBlocksOptions) {
+	col := newTestCollectionWithDocs(t, ctx, `{"Name": "John", "Age": 21}`)
+	d := col.(*collection).db
+
+	docs, err := col.GetAllDocKeysWithHeads(ctx)
+	require.NoError(t, err)
+	doc := <-docs
+	require.NoError(t, doc.Err)
+	require.Len(t, doc.Heads, 1)
+
+	copies := blockstore.NewBlockstore(dssync.MutexWrap(ds.NewMapDatastore()))
+	keys, err := d.Blockstore().AllKeysChan(ctx)
+	require.NoError(t, err)
+	for key := range keys {
+		block, err := d.Blockstore().Get(ctx, key)
+		require.NoError(t, err)
+		require.NoError(t, copies.Put(ctx, block))
+	}
+	fetcher := dag.NewDAGService(blockservice.New(copies, offline.Exchange(copies)))
+
+	return d, doc.Heads[0], copies, client.VerifyBlocksOptions{Fetcher: fetcher}
+}
+
+func TestVerifyBlocks(t *testing.T) {
+	ctx := context.Background()
+	d, _, _, _ := newVerifiedDoc(t, ctx)
+
+	report, err := d.VerifyBlocks(ctx, client.VerifyBlocksOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Documents)
+	// The composite commit and the commits of the two fields.
+	assert.Equal(t, 3, report.Blocks)
+	assert.Empty(t, report.Issues)
+}
+
+func TestVerifyBlocksWithCorruptBlock(t *testing.T) {
+	ctx := context.Background()
+	d, head, _, opts := newVerifiedDoc(t, ctx)
+
+	require.NoError(t, d.Blockstore().DeleteBlock(ctx, head))
+	corrupt, err := blocks.NewBlockWithCid([]byte("corrupt"), head)
+	require.NoError(t, err)
+	require.NoError(t, d.Blockstore().Put(ctx, corrupt))
+
+	report, err := d.VerifyBlocks(ctx, client.VerifyBlocksOptions{Collections: []string{"users"}})
+	require.NoError(t, err)
+	// The links of the corrupt block can't be followed.
+	assert.Equal(t, 1, report.Blocks)
+	require.Len(t, report.Issues, 1)
+	assert.Equal(t, "users", report.Issues[0].Collection)
+	assert.Equal(t, head.String(), report.Issues[0].Cid)
+	assert.Equal(t, client.BlockCorrupt, report.Issues[0].Problem)
+	assert.False(t, report.Issues[0].Repaired)
+
+	opts.Repair = true
+	report, err = d.VerifyBlocks(ctx, opts)
+	require.NoError(t, err)
+	assert.Equal(t, 3, report.Blocks)
+	require.Len(t, report.Issues, 1)
+	assert.True(t, report.Issues[0].Repaired)
+
+	report, err = d.VerifyBlocks(ctx, client.VerifyBlocksOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 3, report.Blocks)
+	assert.Empty(t, report.Issues)
+}
+
+func TestVerifyBlocksWithMissingBlock(t *testing.T) {
+	ctx := context.Background()
+	d, head, copies, opts := newVerifiedDoc(t, ctx)
+
+	block, err := d.Blockstore().Get(ctx, head)
+	require.NoError(t, err)
+	nd, err := dag.DecodeProtobuf(block.RawData())
+	require.NoError(t, err)
+	missing := nd.Links()[0].Cid
+	require.NoError(t, d.Blockstore().DeleteBlock(ctx, missing))
+	require.NoError(t, copies.DeleteBlock(ctx, missing))
+
+	opts.Repair = true
+	report, err := d.VerifyBlocks(ctx, opts)
+	require.NoError(t, err)
+	assert.Equal(t, 3, report.Blocks)
+	require.Len(t, report.Issues, 1)
+	assert.Equal(t, missing.String(), report.Issues[0].Cid)
+	assert.Equal(t, client.BlockMissing, report.Issues[0].Problem)
+	assert.False(t, report.Issues[0].Repaired)
+	assert.NotEmpty(t, report.Issues[0].Error)
+}
+
+func TestVerifyBlocksRepairWithoutFetcher(t *testing.T) {
+	ctx := context.Background()
+	d, _, _, _ := newVerifiedDoc(t, ctx)
+
+	_, err := d.VerifyBlocks(ctx, client.VerifyBlocksOptions{Repair: true})
+	assert.ErrorIs(t, err, ErrNoBlockFetcher)
+}
@@ -0,0 +1,246 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strconv"
+
+	"github.com/ipfs/go-cid"
+	ds "github.com/ipfs/go-datastore"
+	dsq "github.com/ipfs/go-datastore/query"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/core"
+	"github.com/sourcenetwork/defradb/datastore"
+	"github.com/sourcenetwork/defradb/errors"
+	"github.com/sourcenetwork/defradb/logging"
+)
+
+// webhookSequenceName is the name of the sequence the IDs of the webhooks are assigned from.
+const webhookSequenceName = "webhook"
+
+// AddWebhook registers the given webhook, replacing any existing webhook with the same ID.
+func (db *db) AddWebhook(ctx context.Context, webhook client.Webhook) (client.Webhook, error) {
+	if db.webhooks == nil {
+		return client.Webhook{}, ErrWebhooksNotAllowed
+	}
+
+	parsedURL, err := url.Parse(webhook.URL)
+	if err != nil || !parsedURL.IsAbs() || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") {
+		return client.Webhook{}, NewErrInvalidWebhookURL(webhook.URL)
+	}
+
+	txn, err := db.NewTxn(ctx, false)
+	if err != nil {
+		return client.Webhook{}, err
+	}
+	defer txn.Discard(ctx)
+
+	col, err := db.getCollectionByName(ctx, txn, webhook.Collection)
+	if err != nil {
+		return client.Webhook{}, err
+	}
+	if webhook.Filter != "" {
+		_, err = db.parser.NewFilterFromString(webhook.Collection, webhook.Filter)
+		if err != nil {
+			return client.Webhook{}, err
+		}
+	}
+
+	if webhook.ID == "" {
+		seq, err := db.getSequence(ctx, txn, webhookSequenceName)
+		if err != nil {
+			return client.Webhook{}, err
+		}
+		id, err := seq.next(ctx, txn)
+		if err != nil {
+			return client.Webhook{}, err
+		}
+		webhook.ID = strconv.FormatUint(id, 10)
+	}
+
+	buf, err := json.Marshal(webhook)
+	if err != nil {
+		return client.Webhook{}, err
+	}
+	err = txn.Systemstore().Put(ctx, core.NewWebhookKey(webhook.ID).ToDS(), buf)
+	if err != nil {
+		return client.Webhook{}, err
+	}
+	if err := txn.Commit(ctx); err != nil {
+		return client.Webhook{}, err
+	}
+
+	db.webhooks.set(webhook, col.SchemaID())
+	return webhook, nil
+}
+
+// DeleteWebhook deletes the webhook with the given ID.
+func (db *db) DeleteWebhook(ctx context.Context, id string) error {
+	if db.webhooks == nil {
+		return ErrWebhooksNotAllowed
+	}
+
+	txn, err := db.NewTxn(ctx, false)
+	if err != nil {
+		return err
+	}
+	defer txn.Discard(ctx)
+
+	key := core.NewWebhookKey(id).ToDS()
+	exists, err := txn.Systemstore().Has(ctx, key)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return client.NewErrWebhookNotFound(id)
+	}
+	if err := txn.Systemstore().Delete(ctx, key); err != nil {
+		return err
+	}
+	if err := txn.Commit(ctx); err != nil {
+		return err
+	}
+
+	db.webhooks.remove(id)
+	return nil
+}
+
+// GetAllWebhooks returns all the registered webhooks.
+func (db *db) GetAllWebhooks(ctx context.Context) ([]client.Webhook, error) {
+	txn, err := db.NewTxn(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	defer txn.Discard(ctx)
+
+	return db.getAllWebhooks(ctx, txn)
+}
+
+func (db *db) getAllWebhooks(ctx context.Context, txn datastore.Txn) ([]client.Webhook, error) {
+	results, err := txn.Systemstore().Query(ctx, dsq.Query{
+		Prefix: core.NewWebhookKey("").ToString(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := results.Close(); err != nil {
+			log.ErrorE(ctx, "Failed to close webhooks query", err)
+		}
+	}()
+
+	webhooks := []client.Webhook{}
+	for result := range results.Next() {
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		var webhook client.Webhook
+		if err := json.Unmarshal(result.Value, &webhook); err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	return webhooks, nil
+}
+
+// GetWebhookDeadLetters returns the events that could not be delivered to the webhooks.
+func (db *db) GetWebhookDeadLetters(ctx context.Context) ([]client.WebhookDeadLetter, error) {
+	txn, err := db.NewTxn(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	defer txn.Discard(ctx)
+
+	results, err := txn.Systemstore().Query(ctx, dsq.Query{
+		Prefix: core.WebhookDeadLetterKey{}.ToString(),
+		Orders: []dsq.Order{dsq.OrderByKey{}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := results.Close(); err != nil {
+			log.ErrorE(ctx, "Failed to close webhook dead letters query", err)
+		}
+	}()
+
+	deadLetters := []client.WebhookDeadLetter{}
+	for result := range results.Next() {
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		var deadLetter client.WebhookDeadLetter
+		if err := json.Unmarshal(result.Value, &deadLetter); err != nil {
+			return nil, err
+		}
+		deadLetters = append(deadLetters, deadLetter)
+	}
+	return deadLetters, nil
+}
+
+// saveWebhookDeadLetter records the given event, of the commit with the given CID, that could not
+// be delivered.
+func (db *db) saveWebhookDeadLetter(
+	ctx context.Context,
+	deadLetter client.WebhookDeadLetter,
+	c cid.Cid,
+) error {
+	txn, err := db.NewTxn(ctx, false)
+	if err != nil {
+		return err
+	}
+	defer txn.Discard(ctx)
+
+	buf, err := json.Marshal(deadLetter)
+	if err != nil {
+		return err
+	}
+	key := core.NewWebhookDeadLetterKey(deadLetter.FailedAt, deadLetter.WebhookID, c)
+	if err := txn.Systemstore().Put(ctx, key.ToDS(), buf); err != nil {
+		return err
+	}
+	return txn.Commit(ctx)
+}
+
+// loadWebhooks registers the persisted webhooks with the dispatcher.
+func (db *db) loadWebhooks(ctx context.Context) error {
+	txn, err := db.NewTxn(ctx, true)
+	if err != nil {
+		return err
+	}
+	defer txn.Discard(ctx)
+
+	webhooks, err := db.getAllWebhooks(ctx, txn)
+	if err != nil {
+		return err
+	}
+	for _, webhook := range webhooks {
+		col, err := db.getCollectionByName(ctx, txn, webhook.Collection)
+		if errors.Is(err, ds.ErrNotFound) {
+			log.Info(
+				ctx,
+				"Ignoring webhook of unknown collection",
+				logging.NewKV("ID", webhook.ID),
+				logging.NewKV("Collection", webhook.Collection),
+			)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		db.webhooks.set(webhook, col.SchemaID())
+	}
+	return nil
+}
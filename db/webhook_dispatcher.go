@@ -0,0 +1,306 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/core/crdt"
+	"github.com/sourcenetwork/defradb/errors"
+	"github.com/sourcenetwork/defradb/events"
+	"github.com/sourcenetwork/defradb/logging"
+)
+
+const (
+	defaultWebhookMaxAttempts = 5
+	defaultWebhookBackoff     = time.Second
+	webhookTimeout            = 10 * time.Second
+)
+
+// registeredWebhook is a webhook along with the schema ID of its collection.
+type registeredWebhook struct {
+	client.Webhook
+	schemaID string
+}
+
+// webhookDispatcher POSTs the events of the documents to the registered webhooks.
+//
+// Each event is delivered in its own goroutine, so that slow webhooks don't hold the update
+// events back. Events are thus not guaranteed to be delivered in order.
+type webhookDispatcher struct {
+	db     *db
+	client *http.Client
+
+	maxAttempts int
+	backoff     time.Duration
+
+	mu       sync.RWMutex
+	webhooks map[string]registeredWebhook
+
+	// ctx is cancelled once the database is closed, ending the pending deliveries.
+	ctx        context.Context
+	cancel     context.CancelFunc
+	deliveries sync.WaitGroup
+}
+
+// startWebhookDispatcher starts dispatching the update events to the persisted webhooks.
+func (db *db) startWebhookDispatcher(ctx context.Context) error {
+	if !db.events.Updates.HasValue() {
+		return nil
+	}
+
+	dispatcherCtx, cancel := context.WithCancel(context.Background())
+	db.webhooks = &webhookDispatcher{
+		db:          db,
+		client:      &http.Client{Timeout: webhookTimeout},
+		maxAttempts: db.webhookMaxAttempts,
+		backoff:     db.webhookBackoff,
+		webhooks:    map[string]registeredWebhook{},
+		ctx:         dispatcherCtx,
+		cancel:      cancel,
+	}
+	if err := db.loadWebhooks(ctx); err != nil {
+		return err
+	}
+
+	updates, err := db.events.Updates.Value().Subscribe()
+	if err != nil {
+		return err
+	}
+	go func() {
+		for update := range updates {
+			db.webhooks.dispatch(update)
+		}
+	}()
+	return nil
+}
+
+func (d *webhookDispatcher) set(webhook client.Webhook, schemaID string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.webhooks[webhook.ID] = registeredWebhook{
+		Webhook:  webhook,
+		schemaID: schemaID,
+	}
+}
+
+func (d *webhookDispatcher) remove(id string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.webhooks, id)
+}
+
+// close cancels the pending deliveries and waits for them to end.
+func (d *webhookDispatcher) close() {
+	d.cancel()
+	d.deliveries.Wait()
+}
+
+// dispatch starts the delivery of the given update to the webhooks of its collection.
+//
+// Remote updates are not dispatched, as their commit is not known.
+func (d *webhookDispatcher) dispatch(update events.Update) {
+	if update.IsRemote {
+		return
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	for _, webhook := range d.webhooks {
+		if webhook.schemaID != update.SchemaID {
+			continue
+		}
+		d.deliveries.Add(1)
+		go func(webhook registeredWebhook) {
+			defer d.deliveries.Done()
+			d.deliver(webhook, update)
+		}(webhook)
+	}
+}
+
+// deliver POSTs the event of the given update to the given webhook, retrying with backoff until
+// it succeeds or the attempts are exhausted, in which case the event is recorded as a dead letter.
+func (d *webhookDispatcher) deliver(webhook registeredWebhook, update events.Update) {
+	event, matches, err := d.newEvent(webhook, update)
+	if err != nil {
+		d.saveDeadLetter(webhook, event, update, 0, err)
+		return
+	}
+	if !matches {
+		return
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		d.saveDeadLetter(webhook, event, update, 0, err)
+		return
+	}
+
+	backoff := d.backoff
+	for attempt := 1; ; attempt++ {
+		err = d.post(webhook, event.Event, payload)
+		if err == nil {
+			return
+		}
+		if attempt >= d.maxAttempts {
+			d.saveDeadLetter(webhook, event, update, attempt, err)
+			return
+		}
+
+		select {
+		case <-d.ctx.Done():
+			d.saveDeadLetter(webhook, event, update, attempt, err)
+			return
+		case <-time.After(backoff):
+			backoff *= 2
+		}
+	}
+}
+
+// newEvent returns the event of the given update, and whether it matches the filter of the
+// given webhook.
+func (d *webhookDispatcher) newEvent(
+	webhook registeredWebhook,
+	update events.Update,
+) (client.WebhookEvent, bool, error) {
+	event := client.WebhookEvent{
+		Collection: webhook.Collection,
+		DocKey:     update.DocKey,
+		Cid:        update.Cid.String(),
+	}
+
+	delta, err := crdt.CompositeDAG{}.DeltaDecodeWithoutData(update.Block)
+	if err != nil {
+		return event, false, err
+	}
+	switch {
+	case delta.(*crdt.CompositeDAGDelta).Status.IsDeleted():
+		event.Event = client.WebhookEventDelete
+		return event, true, nil
+	case update.Priority == 1:
+		event.Event = client.WebhookEventCreate
+	default:
+		event.Event = client.WebhookEventUpdate
+	}
+
+	txn, err := d.db.NewTxn(d.ctx, true)
+	if err != nil {
+		return event, false, err
+	}
+	defer txn.Discard(d.ctx)
+
+	col, err := d.db.getCollectionBySchemaID(d.ctx, txn, update.SchemaID)
+	if err != nil {
+		return event, false, err
+	}
+	col = col.WithTxn(txn)
+
+	key, err := client.NewDocKeyFromString(update.DocKey)
+	if err != nil {
+		return event, false, err
+	}
+	if webhook.Filter != "" {
+		count, err := col.Count(
+			d.ctx,
+			fmt.Sprintf(`{_and: [%s, {_key: {_eq: %q}}]}`, webhook.Filter, update.DocKey),
+		)
+		if err != nil {
+			return event, false, err
+		}
+		if count == 0 {
+			return event, false, nil
+		}
+	}
+
+	doc, err := col.Get(d.ctx, key, false)
+	if errors.Is(err, client.ErrDocumentNotFound) {
+		// The document has been deleted since, its delete event will follow.
+		return event, true, nil
+	}
+	if err != nil {
+		return event, false, err
+	}
+	event.Document, err = doc.ToMap()
+	if err != nil {
+		return event, false, err
+	}
+	return event, true, nil
+}
+
+// post POSTs the given payload to the given webhook, signing it with its secret if it has one.
+func (d *webhookDispatcher) post(webhook registeredWebhook, eventType string, payload []byte) error {
+	req, err := http.NewRequestWithContext(d.ctx, http.MethodPost, webhook.URL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Defra-Event", eventType)
+	if webhook.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(webhook.Secret))
+		mac.Write(payload)
+		req.Header.Set(client.WebhookSignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	res, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			log.ErrorE(d.ctx, "Failed to close webhook response body", err)
+		}
+	}()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return NewErrWebhookDeliveryFailed(res.StatusCode)
+	}
+	return nil
+}
+
+// saveDeadLetter records the given event that could not be delivered to the given webhook.
+func (d *webhookDispatcher) saveDeadLetter(
+	webhook registeredWebhook,
+	event client.WebhookEvent,
+	update events.Update,
+	attempts int,
+	deliveryErr error,
+) {
+	log.ErrorE(
+		d.ctx,
+		"Failed to deliver webhook event",
+		deliveryErr,
+		logging.NewKV("ID", webhook.ID),
+		logging.NewKV("DocKey", update.DocKey),
+		logging.NewKV("Attempts", attempts),
+	)
+
+	deadLetter := client.WebhookDeadLetter{
+		WebhookID: webhook.ID,
+		URL:       webhook.URL,
+		Event:     event,
+		Attempts:  attempts,
+		Error:     deliveryErr.Error(),
+		FailedAt:  d.db.now(),
+	}
+	// The dead letter is saved even if the database is being closed, which the store outlives.
+	err := d.db.saveWebhookDeadLetter(context.Background(), deadLetter, update.Cid)
+	if err != nil {
+		log.ErrorE(d.ctx, "Failed to save webhook dead letter", err, logging.NewKV("ID", webhook.ID))
+	}
+}
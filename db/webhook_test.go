@@ -0,0 +1,304 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package db
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	badger "github.com/dgraph-io/badger/v3"
+	ds "github.com/ipfs/go-datastore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/client"
+	badgerds "github.com/sourcenetwork/defradb/datastore/badger/v3"
+)
+
+// webhookReceiver is a test server recording the payloads POSTed to it.
+type webhookReceiver struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	status   int
+	events   []client.WebhookEvent
+	payloads [][]byte
+	headers  []http.Header
+	received chan struct{}
+}
+
+func newWebhookReceiver(t *testing.T, status int) *webhookReceiver {
+	r := &webhookReceiver{
+		status:   status,
+		received: make(chan struct{}, 100),
+	}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		payload, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		var event client.WebhookEvent
+		require.NoError(t, json.Unmarshal(payload, &event))
+
+		r.mu.Lock()
+		r.events = append(r.events, event)
+		r.payloads = append(r.payloads, payload)
+		r.headers = append(r.headers, req.Header.Clone())
+		r.mu.Unlock()
+
+		rw.WriteHeader(r.status)
+		r.received <- struct{}{}
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+// waitFor waits for the given number of payloads to be received.
+func (r *webhookReceiver) waitFor(t *testing.T, count int) {
+	for i := 0; i < count; i++ {
+		select {
+		case <-r.received:
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "timed out waiting for webhook payloads", "received: %v", i)
+		}
+	}
+}
+
+// eventsByType returns the received events by type, as their delivery order is not guaranteed.
+func (r *webhookReceiver) eventsByType() map[string][]client.WebhookEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	events := map[string][]client.WebhookEvent{}
+	for _, event := range r.events {
+		events[event.Event] = append(events[event.Event], event)
+	}
+	return events
+}
+
+func TestWebhookReceivesDocumentEvents(t *testing.T) {
+	ctx := context.Background()
+	db, err := newMemoryDB(ctx, WithUpdateEvents())
+	require.NoError(t, err)
+	defer db.Close(ctx)
+	col := newChangefeedTestCollection(t, ctx, db)
+	receiver := newWebhookReceiver(t, http.StatusOK)
+
+	webhook, err := db.AddWebhook(ctx, client.Webhook{URL: receiver.URL, Collection: "users"})
+	require.NoError(t, err)
+	assert.Equal(t, "1", webhook.ID)
+
+	doc := newTestDocs(t, 1)[0]
+	require.NoError(t, col.Create(ctx, doc))
+	receiver.waitFor(t, 1)
+	require.NoError(t, doc.Set("Age", 30))
+	require.NoError(t, col.Update(ctx, doc))
+	receiver.waitFor(t, 1)
+	_, err = col.Delete(ctx, doc.Key())
+	require.NoError(t, err)
+	receiver.waitFor(t, 1)
+
+	events := receiver.eventsByType()
+	require.Len(t, events[client.WebhookEventCreate], 1)
+	created := events[client.WebhookEventCreate][0]
+	assert.Equal(t, "users", created.Collection)
+	assert.Equal(t, doc.Key().String(), created.DocKey)
+	assert.NotEmpty(t, created.Cid)
+	assert.NotEmpty(t, created.Document["Name"])
+
+	require.Len(t, events[client.WebhookEventUpdate], 1)
+	updated := events[client.WebhookEventUpdate][0]
+	assert.Equal(t, doc.Key().String(), updated.DocKey)
+	assert.Equal(t, float64(30), updated.Document["Age"])
+
+	require.Len(t, events[client.WebhookEventDelete], 1)
+	deleted := events[client.WebhookEventDelete][0]
+	assert.Equal(t, doc.Key().String(), deleted.DocKey)
+	assert.Nil(t, deleted.Document)
+}
+
+func TestWebhookPayloadsAreSignedWithSecret(t *testing.T) {
+	ctx := context.Background()
+	db, err := newMemoryDB(ctx, WithUpdateEvents())
+	require.NoError(t, err)
+	defer db.Close(ctx)
+	col := newChangefeedTestCollection(t, ctx, db)
+	receiver := newWebhookReceiver(t, http.StatusOK)
+
+	_, err = db.AddWebhook(ctx, client.Webhook{URL: receiver.URL, Collection: "users", Secret: "secret"})
+	require.NoError(t, err)
+	require.NoError(t, col.Create(ctx, newTestDocs(t, 1)[0]))
+	receiver.waitFor(t, 1)
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(receiver.payloads[0])
+	expected := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	assert.Equal(t, expected, receiver.headers[0].Get(client.WebhookSignatureHeader))
+	assert.Equal(t, client.WebhookEventCreate, receiver.headers[0].Get("X-Defra-Event"))
+}
+
+func TestWebhookWithFilterReceivesMatchingDocuments(t *testing.T) {
+	ctx := context.Background()
+	db, err := newMemoryDB(ctx, WithUpdateEvents())
+	require.NoError(t, err)
+	defer db.Close(ctx)
+	col := newChangefeedTestCollection(t, ctx, db)
+	receiver := newWebhookReceiver(t, http.StatusOK)
+
+	_, err = db.AddWebhook(ctx, client.Webhook{
+		URL:        receiver.URL,
+		Collection: "users",
+		Filter:     `{Age: {_gt: 40}}`,
+	})
+	require.NoError(t, err)
+
+	young, err := client.NewDocFromJSON([]byte(`{"Name": "John", "Age": 21}`))
+	require.NoError(t, err)
+	old, err := client.NewDocFromJSON([]byte(`{"Name": "Islam", "Age": 62}`))
+	require.NoError(t, err)
+	require.NoError(t, col.Create(ctx, young))
+	require.NoError(t, col.Create(ctx, old))
+	receiver.waitFor(t, 1)
+
+	// Give a wrongly delivered event the time to arrive.
+	time.Sleep(100 * time.Millisecond)
+	events := receiver.eventsByType()
+	require.Len(t, events[client.WebhookEventCreate], 1)
+	assert.Equal(t, old.Key().String(), events[client.WebhookEventCreate][0].DocKey)
+}
+
+func TestWebhookDeliveryIsRetriedThenDeadLettered(t *testing.T) {
+	ctx := context.Background()
+	db, err := newMemoryDB(ctx, WithUpdateEvents(), WithWebhookRetries(3, time.Millisecond))
+	require.NoError(t, err)
+	defer db.Close(ctx)
+	col := newChangefeedTestCollection(t, ctx, db)
+	receiver := newWebhookReceiver(t, http.StatusInternalServerError)
+
+	webhook, err := db.AddWebhook(ctx, client.Webhook{URL: receiver.URL, Collection: "users"})
+	require.NoError(t, err)
+	doc := newTestDocs(t, 1)[0]
+	require.NoError(t, col.Create(ctx, doc))
+	receiver.waitFor(t, 3)
+
+	var deadLetters []client.WebhookDeadLetter
+	require.Eventually(t, func() bool {
+		deadLetters, err = db.GetWebhookDeadLetters(ctx)
+		require.NoError(t, err)
+		return len(deadLetters) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, webhook.ID, deadLetters[0].WebhookID)
+	assert.Equal(t, receiver.URL, deadLetters[0].URL)
+	assert.Equal(t, 3, deadLetters[0].Attempts)
+	assert.Equal(t, NewErrWebhookDeliveryFailed(http.StatusInternalServerError).Error(), deadLetters[0].Error)
+	assert.Equal(t, client.WebhookEventCreate, deadLetters[0].Event.Event)
+	assert.Equal(t, doc.Key().String(), deadLetters[0].Event.DocKey)
+}
+
+func TestDeleteWebhookStopsDelivery(t *testing.T) {
+	ctx := context.Background()
+	db, err := newMemoryDB(ctx, WithUpdateEvents())
+	require.NoError(t, err)
+	defer db.Close(ctx)
+	col := newChangefeedTestCollection(t, ctx, db)
+	deleted := newWebhookReceiver(t, http.StatusOK)
+	kept := newWebhookReceiver(t, http.StatusOK)
+
+	webhook, err := db.AddWebhook(ctx, client.Webhook{URL: deleted.URL, Collection: "users"})
+	require.NoError(t, err)
+	_, err = db.AddWebhook(ctx, client.Webhook{URL: kept.URL, Collection: "users"})
+	require.NoError(t, err)
+	require.NoError(t, db.DeleteWebhook(ctx, webhook.ID))
+
+	webhooks, err := db.GetAllWebhooks(ctx)
+	require.NoError(t, err)
+	require.Len(t, webhooks, 1)
+	assert.Equal(t, kept.URL, webhooks[0].URL)
+
+	require.NoError(t, col.Create(ctx, newTestDocs(t, 1)[0]))
+	kept.waitFor(t, 1)
+	time.Sleep(100 * time.Millisecond)
+	assert.Empty(t, deleted.eventsByType())
+
+	err = db.DeleteWebhook(ctx, webhook.ID)
+	require.ErrorIs(t, err, client.ErrWebhookNotFound)
+}
+
+func TestAddWebhookWithInvalidWebhook(t *testing.T) {
+	ctx := context.Background()
+	db, err := newMemoryDB(ctx, WithUpdateEvents())
+	require.NoError(t, err)
+	defer db.Close(ctx)
+	newChangefeedTestCollection(t, ctx, db)
+
+	_, err = db.AddWebhook(ctx, client.Webhook{URL: "/hook", Collection: "users"})
+	require.ErrorIs(t, err, ErrInvalidWebhookURL)
+
+	_, err = db.AddWebhook(ctx, client.Webhook{URL: "ftp://example.com", Collection: "users"})
+	require.ErrorIs(t, err, ErrInvalidWebhookURL)
+
+	_, err = db.AddWebhook(ctx, client.Webhook{URL: "https://example.com", Collection: "books"})
+	require.ErrorIs(t, err, ds.ErrNotFound)
+
+	_, err = db.AddWebhook(ctx, client.Webhook{
+		URL:        "https://example.com",
+		Collection: "users",
+		Filter:     `{Age: {_gt: }`,
+	})
+	require.Error(t, err)
+}
+
+func TestAddWebhookWithoutUpdateEvents(t *testing.T) {
+	ctx := context.Background()
+	db, err := newMemoryDB(ctx)
+	require.NoError(t, err)
+	defer db.Close(ctx)
+	newChangefeedTestCollection(t, ctx, db)
+
+	_, err = db.AddWebhook(ctx, client.Webhook{URL: "https://example.com", Collection: "users"})
+	require.ErrorIs(t, err, ErrWebhooksNotAllowed)
+}
+
+func TestWebhooksArePersisted(t *testing.T) {
+	ctx := context.Background()
+	path := t.TempDir()
+	opts := badgerds.Options{Options: badger.DefaultOptions(path)}
+	receiver := newWebhookReceiver(t, http.StatusOK)
+
+	rootstore, err := badgerds.NewDatastore(path, &opts)
+	require.NoError(t, err)
+	db, err := newDB(ctx, rootstore, WithUpdateEvents())
+	require.NoError(t, err)
+	newChangefeedTestCollection(t, ctx, db)
+	_, err = db.AddWebhook(ctx, client.Webhook{URL: receiver.URL, Collection: "users"})
+	require.NoError(t, err)
+	db.Close(ctx)
+
+	rootstore, err = badgerds.NewDatastore(path, &opts)
+	require.NoError(t, err)
+	db, err = newDB(ctx, rootstore, WithUpdateEvents())
+	require.NoError(t, err)
+	defer db.Close(ctx)
+	col, err := db.GetCollectionByName(ctx, "users")
+	require.NoError(t, err)
+
+	doc := newTestDocs(t, 1)[0]
+	require.NoError(t, col.Create(ctx, doc))
+	receiver.waitFor(t, 1)
+	assert.Equal(t, doc.Key().String(), receiver.eventsByType()[client.WebhookEventCreate][0].DocKey)
+}
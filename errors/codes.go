@@ -0,0 +1,96 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package errors
+
+import (
+	"errors"
+	"sync"
+)
+
+// Code is a stable, machine-readable identifier of a kind of error.
+//
+// Codes allow clients to branch on errors without matching their messages, which may change.
+type Code string
+
+// Codes of the errors returned by DefraDB.
+//
+// Codes are part of the public API and must not be changed once released.
+const (
+	// CodeUnknown is the code of errors that have not been assigned a code.
+	CodeUnknown Code = "UNKNOWN"
+
+	CodeCollectionNotFound    Code = "COLLECTION_NOT_FOUND"
+	CodeCollectionExists      Code = "COLLECTION_ALREADY_EXISTS"
+	CodeDocumentNotFound      Code = "DOCUMENT_NOT_FOUND"
+	CodeDocumentExists        Code = "DOCUMENT_ALREADY_EXISTS"
+	CodeDocumentDeleted       Code = "DOCUMENT_DELETED"
+	CodeCommitNotInHistory    Code = "COMMIT_NOT_IN_HISTORY"
+	CodeInvalidDocKey         Code = "INVALID_DOCKEY"
+	CodeFieldNotExist         Code = "FIELD_NOT_EXIST"
+	CodeInvalidFilter         Code = "INVALID_FILTER"
+	CodeInvalidRequest        Code = "INVALID_REQUEST"
+	CodeInvalidSchema         Code = "INVALID_SCHEMA"
+	CodeInvalidSchemaPatch    Code = "INVALID_SCHEMA_PATCH"
+	CodeTransactionConflict   Code = "TRANSACTION_CONFLICT"
+	CodeRequestCancelled      Code = "REQUEST_CANCELLED"
+	CodeRequestNotFound       Code = "REQUEST_NOT_FOUND"
+	CodeUnauthorized          Code = "UNAUTHORIZED"
+	CodeTooManyRequests       Code = "TOO_MANY_REQUESTS"
+	CodeUnavailable           Code = "UNAVAILABLE"
+	CodeSubscriptionsDisabled Code = "SUBSCRIPTIONS_DISABLED"
+	CodeWebhookNotFound       Code = "WEBHOOK_NOT_FOUND"
+	CodeKVKeyNotFound         Code = "KV_KEY_NOT_FOUND"
+	CodeQuotaExceeded         Code = "QUOTA_EXCEEDED"
+)
+
+var (
+	codesMutex sync.RWMutex
+	// codes maps error messages to their code.
+	//
+	// Errors are identified by their message, as done by [defraError.Is], so that both the
+	// package level errors and the errors returned by their `NewErrX` constructors have a code.
+	codes = map[string]Code{}
+)
+
+// WithCode assigns the given code to all errors with the message of the given error and returns
+// the error unchanged.
+//
+// It is intended to be used when declaring package level errors, e.g.
+// `ErrX = errors.WithCode(errors.CodeX, errors.New(errX))`.
+func WithCode(code Code, err error) error {
+	codesMutex.Lock()
+	defer codesMutex.Unlock()
+	codes[messageOf(err)] = code
+	return err
+}
+
+// CodeOf returns the code of the outermost error of the chain of the given error that has one.
+//
+// Returns CodeUnknown if none do.
+func CodeOf(err error) Code {
+	codesMutex.RLock()
+	defer codesMutex.RUnlock()
+	for ; err != nil; err = errors.Unwrap(err) {
+		if code, ok := codes[messageOf(err)]; ok {
+			return code
+		}
+	}
+	return CodeUnknown
+}
+
+// messageOf returns the message identifying the given error, without its inner error and
+// key-value pairs.
+func messageOf(err error) string {
+	if defraErr, ok := err.(*defraError); ok { //nolint:errorlint
+		return defraErr.message
+	}
+	return err.Error()
+}
@@ -0,0 +1,61 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCodeOfErrorWithCode(t *testing.T) {
+	err := WithCode(CodeCollectionNotFound, New("codes test: collection"))
+
+	assert.Equal(t, CodeCollectionNotFound, CodeOf(err))
+}
+
+func TestCodeOfErrorWithSameMessageAndKvps(t *testing.T) {
+	const errorMessage string = "codes test: kvps"
+	_ = WithCode(CodeInvalidFilter, New(errorMessage))
+
+	err := New(errorMessage, NewKV("Filter", "{}"))
+
+	assert.Equal(t, CodeInvalidFilter, CodeOf(err))
+}
+
+func TestCodeOfWrappedError(t *testing.T) {
+	inner := WithCode(CodeDocumentNotFound, New("codes test: inner"))
+
+	assert.Equal(t, CodeDocumentNotFound, CodeOf(Wrap("codes test: outer", inner)))
+	assert.Equal(t, CodeDocumentNotFound, CodeOf(fmt.Errorf("outer: %w", inner)))
+}
+
+func TestCodeOfReturnsOutermostCode(t *testing.T) {
+	inner := WithCode(CodeDocumentNotFound, New("codes test: outermost inner"))
+	outer := WithCode(CodeCollectionNotFound, New("codes test: outermost outer"))
+
+	assert.Equal(t, CodeCollectionNotFound, CodeOf(Wrap("codes test: outermost outer", inner)))
+	assert.Equal(t, CodeCollectionNotFound, CodeOf(outer))
+}
+
+func TestCodeOfNonDefraError(t *testing.T) {
+	_ = WithCode(CodeTransactionConflict, errors.New("codes test: conflict"))
+
+	assert.Equal(t, CodeTransactionConflict, CodeOf(errors.New("codes test: conflict")))
+	assert.Equal(t, CodeTransactionConflict, CodeOf(WithStack(errors.New("codes test: conflict"))))
+}
+
+func TestCodeOfErrorWithoutCode(t *testing.T) {
+	assert.Equal(t, CodeUnknown, CodeOf(New("codes test: no code")))
+	assert.Equal(t, CodeUnknown, CodeOf(nil))
+}
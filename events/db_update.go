@@ -30,4 +30,13 @@ type Update struct {
 	SchemaID string
 	Block    ipld.Node
 	Priority uint64
+
+	// IsRemote is true if the update has been merged from another node. The Block and
+	// Priority of remote updates are not set.
+	IsRemote bool
+
+	// Sequence is the position of the update within the persisted changefeed of its
+	// collection, starting at one. It is zero if the changefeed is not enabled, and for
+	// remote updates.
+	Sequence uint64
 }
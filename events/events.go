@@ -49,4 +49,8 @@ func New[T any](commandBufferSize int, eventBufferSize int) Channel[T] {
 type Events struct {
 	// Updates publishes an `Update` for each document written to in the database.
 	Updates UpdateChannel
+
+	// Quotas publishes a `QuotaExceeded` for each write exceeding the storage quota of a
+	// collection.
+	Quotas QuotaChannel
 }
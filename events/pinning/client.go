@@ -0,0 +1,136 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package pinning
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/ipfs/go-cid"
+)
+
+// The types of the supported pinning remotes.
+const (
+	// TypeService is a remote implementing the IPFS Pinning Service API
+	// (https://ipfs.github.io/pinning-services-api-spec/).
+	TypeService = "service"
+
+	// TypeKubo is the RPC API of a Kubo IPFS node (https://docs.ipfs.tech/reference/kubo/rpc/).
+	TypeKubo = "kubo"
+)
+
+// Client pins blocks to a remote IPFS node or pinning service.
+type Client interface {
+	// Pin recursively pins the DAG of the block with the given CID, naming the pin with the
+	// given name. The remote fetches the blocks from the given origins, the multiaddresses of
+	// the peers providing them.
+	Pin(ctx context.Context, c cid.Cid, name string, origins []string) error
+}
+
+// NewClient returns a client of the remote of the given type at the given URL, authenticated
+// with the given access token if not empty.
+func NewClient(remoteType string, remoteURL string, accessToken string) (Client, error) {
+	switch remoteType {
+	case TypeService:
+		return &serviceClient{
+			url:         strings.TrimSuffix(remoteURL, "/"),
+			accessToken: accessToken,
+			client:      &http.Client{},
+		}, nil
+	case TypeKubo:
+		return &kuboClient{
+			url:         strings.TrimSuffix(remoteURL, "/"),
+			accessToken: accessToken,
+			client:      &http.Client{},
+		}, nil
+	default:
+		return nil, NewErrUnknownType(remoteType)
+	}
+}
+
+// servicePin is the pin object of the Pinning Service API.
+type servicePin struct {
+	Cid     string   `json:"cid"`
+	Name    string   `json:"name,omitempty"`
+	Origins []string `json:"origins,omitempty"`
+}
+
+type serviceClient struct {
+	url         string
+	accessToken string
+	client      *http.Client
+}
+
+func (c *serviceClient) Pin(ctx context.Context, blockCid cid.Cid, name string, origins []string) error {
+	body, err := json.Marshal(servicePin{
+		Cid:     blockCid.String(),
+		Name:    name,
+		Origins: origins,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url+"/pins", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return send(c.client, req, c.accessToken)
+}
+
+type kuboClient struct {
+	url         string
+	accessToken string
+	client      *http.Client
+}
+
+func (c *kuboClient) Pin(ctx context.Context, blockCid cid.Cid, name string, origins []string) error {
+	query := url.Values{}
+	query.Set("arg", blockCid.String())
+	query.Set("recursive", "true")
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		c.url+"/api/v0/pin/add?"+query.Encode(),
+		nil,
+	)
+	if err != nil {
+		return err
+	}
+	return send(c.client, req, c.accessToken)
+}
+
+// send sends the given request, authenticated with the given access token if not empty, and
+// returns an error if it is unsuccessful.
+func send(client *http.Client, req *http.Request, accessToken string) error {
+	if accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			log.ErrorE(req.Context(), "Failed to close pinning response body", err)
+		}
+	}()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return NewErrPinFailed(res.StatusCode, string(body))
+	}
+	return nil
+}
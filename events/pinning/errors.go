@@ -0,0 +1,39 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package pinning
+
+import (
+	"github.com/sourcenetwork/defradb/errors"
+)
+
+const (
+	errUnknownType          string = "unknown pinning remote type"
+	errPinFailed            string = "the pinning remote responded with an unsuccessful status"
+	errUpdateEventsDisabled string = "the update events of the database are disabled"
+)
+
+var (
+	ErrUnknownType          = errors.New(errUnknownType)
+	ErrPinFailed            = errors.New(errPinFailed)
+	ErrUpdateEventsDisabled = errors.New(errUpdateEventsDisabled)
+)
+
+// NewErrUnknownType returns a new error indicating that the given pinning remote type is not
+// supported.
+func NewErrUnknownType(remoteType string) error {
+	return errors.New(errUnknownType, errors.NewKV("Type", remoteType))
+}
+
+// NewErrPinFailed returns a new error indicating that the pinning remote responded to a pin
+// request with the given unsuccessful status and body.
+func NewErrPinFailed(status int, body string) error {
+	return errors.New(errPinFailed, errors.NewKV("Status", status), errors.NewKV("Body", body))
+}
@@ -0,0 +1,149 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+/*
+Package pinning pins the DAG blocks of the documents to a remote IPFS node or pinning service,
+giving off-node durability to the content-addressed history of the documents.
+*/
+package pinning
+
+import (
+	"context"
+	"sync"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/events"
+	"github.com/sourcenetwork/defradb/logging"
+)
+
+var log = logging.MustNewLogger("defra.pinning")
+
+// queueSize is the number of updates waiting to be pinned above which updates are dropped,
+// so that a slow remote does not hold the update events back.
+const queueSize = 1024
+
+// Pinner pins the head block of each update of the documents of the configured collections.
+//
+// Pins are recursive, so pinning the head of a document pins its whole history. Each pin is
+// named after the collection and the key of its document (e.g. `User/bae-...`).
+type Pinner struct {
+	db      client.DB
+	client  Client
+	origins []string
+
+	// collections holds the names of the collections whose documents are pinned, all the
+	// collections being pinned if empty.
+	collections map[string]struct{}
+
+	// names caches the names of the collections by schema ID.
+	names map[string]string
+
+	queue chan events.Update
+	done  chan struct{}
+	once  sync.Once
+}
+
+// New returns a new Pinner pinning the blocks of the documents of the given collections, or of
+// all the collections if none are given, with the given client.
+//
+// The remote fetches the blocks from the given origins, the multiaddresses of this node.
+func New(db client.DB, client Client, collections []string, origins []string) *Pinner {
+	p := &Pinner{
+		db:          db,
+		client:      client,
+		origins:     origins,
+		collections: map[string]struct{}{},
+		names:       map[string]string{},
+		queue:       make(chan events.Update, queueSize),
+		done:        make(chan struct{}),
+	}
+	for _, name := range collections {
+		p.collections[name] = struct{}{}
+	}
+	return p
+}
+
+// Start starts pinning the blocks of the updates of the database, until the database is closed.
+func (p *Pinner) Start(ctx context.Context) error {
+	if !p.db.Events().Updates.HasValue() {
+		return ErrUpdateEventsDisabled
+	}
+	// The names of the existing collections are cached upfront, so that the pending updates can
+	// still be pinned once the database is closed.
+	cols, err := p.db.GetAllCollections(ctx)
+	if err != nil {
+		return err
+	}
+	for _, col := range cols {
+		p.names[col.SchemaID()] = col.Name()
+	}
+
+	updates, err := p.db.Events().Updates.Value().Subscribe()
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		defer close(p.queue)
+		for update := range updates {
+			select {
+			case p.queue <- update:
+			default:
+				log.Error(
+					ctx,
+					"Dropping pin of update, too many pins pending",
+					logging.NewKV("DocKey", update.DocKey),
+					logging.NewKV("Cid", update.Cid),
+				)
+			}
+		}
+	}()
+
+	go func() {
+		defer close(p.done)
+		for update := range p.queue {
+			if err := p.pin(ctx, update); err != nil {
+				log.ErrorE(
+					ctx,
+					"Failed to pin update",
+					err,
+					logging.NewKV("DocKey", update.DocKey),
+					logging.NewKV("Cid", update.Cid),
+				)
+			}
+		}
+	}()
+	return nil
+}
+
+// Wait waits for the pending updates to be pinned.
+//
+// It must be called once the database is closed, which ends the update events.
+func (p *Pinner) Wait() {
+	p.once.Do(func() {
+		<-p.done
+	})
+}
+
+func (p *Pinner) pin(ctx context.Context, update events.Update) error {
+	name, ok := p.names[update.SchemaID]
+	if !ok {
+		col, err := p.db.GetCollectionBySchemaID(ctx, update.SchemaID)
+		if err != nil {
+			return err
+		}
+		name = col.Name()
+		p.names[update.SchemaID] = name
+	}
+	if _, ok := p.collections[name]; len(p.collections) > 0 && !ok {
+		return nil
+	}
+	return p.client.Pin(ctx, update.Cid, name+"/"+update.DocKey, p.origins)
+}
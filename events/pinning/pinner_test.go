@@ -0,0 +1,170 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package pinning
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	badger "github.com/dgraph-io/badger/v3"
+	"github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/client"
+	badgerds "github.com/sourcenetwork/defradb/datastore/badger/v3"
+	"github.com/sourcenetwork/defradb/db"
+)
+
+type pin struct {
+	cid     cid.Cid
+	name    string
+	origins []string
+}
+
+// mockClient records the pins.
+type mockClient struct {
+	mu   sync.Mutex
+	pins []pin
+}
+
+func (c *mockClient) Pin(ctx context.Context, blockCid cid.Cid, name string, origins []string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pins = append(c.pins, pin{cid: blockCid, name: name, origins: origins})
+	return nil
+}
+
+func newTestDB(t *testing.T, ctx context.Context) client.DB {
+	opts := badgerds.Options{Options: badger.DefaultOptions("").WithInMemory(true)}
+	rootstore, err := badgerds.NewDatastore("", &opts)
+	require.NoError(t, err)
+	defra, err := db.NewDB(ctx, rootstore, db.WithUpdateEvents())
+	require.NoError(t, err)
+	err = defra.AddSchema(ctx, `type User { name: String } type Book { name: String }`)
+	require.NoError(t, err)
+	return defra
+}
+
+func createDoc(t *testing.T, ctx context.Context, defra client.DB, collection string) *client.Document {
+	col, err := defra.GetCollectionByName(ctx, collection)
+	require.NoError(t, err)
+	doc, err := client.NewDocFromJSON([]byte(`{"name": "John"}`))
+	require.NoError(t, err)
+	require.NoError(t, col.Create(ctx, doc))
+	return doc
+}
+
+func TestPinnerPinsHeadsOfDocuments(t *testing.T) {
+	ctx := context.Background()
+	defra := newTestDB(t, ctx)
+	pinClient := &mockClient{}
+	pinner := New(defra, pinClient, nil, []string{"/ip4/127.0.0.1/tcp/9171/p2p/peer"})
+	require.NoError(t, pinner.Start(ctx))
+
+	user := createDoc(t, ctx, defra, "User")
+	book := createDoc(t, ctx, defra, "Book")
+	defra.Close(ctx)
+	pinner.Wait()
+
+	require.Len(t, pinClient.pins, 2)
+	assert.Equal(t, user.Head(), pinClient.pins[0].cid)
+	assert.Equal(t, "User/"+user.Key().String(), pinClient.pins[0].name)
+	assert.Equal(t, []string{"/ip4/127.0.0.1/tcp/9171/p2p/peer"}, pinClient.pins[0].origins)
+	assert.Equal(t, book.Head(), pinClient.pins[1].cid)
+	assert.Equal(t, "Book/"+book.Key().String(), pinClient.pins[1].name)
+}
+
+func TestPinnerPinsConfiguredCollections(t *testing.T) {
+	ctx := context.Background()
+	defra := newTestDB(t, ctx)
+	pinClient := &mockClient{}
+	pinner := New(defra, pinClient, []string{"Book"}, nil)
+	require.NoError(t, pinner.Start(ctx))
+
+	createDoc(t, ctx, defra, "User")
+	book := createDoc(t, ctx, defra, "Book")
+	defra.Close(ctx)
+	pinner.Wait()
+
+	require.Len(t, pinClient.pins, 1)
+	assert.Equal(t, book.Head(), pinClient.pins[0].cid)
+}
+
+func TestServiceClientPin(t *testing.T) {
+	var received servicePin
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, http.MethodPost, req.Method)
+		assert.Equal(t, "/psa/pins", req.URL.Path)
+		authorization = req.Header.Get("Authorization")
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&received))
+		rw.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	pinClient, err := NewClient(TypeService, server.URL+"/psa/", "token")
+	require.NoError(t, err)
+	blockCid, err := cid.Decode("bafybeifwfw3g4q6tagffdwq4orrouoosdlsc5rb67q2uj7oplkq7ax5ysm")
+	require.NoError(t, err)
+	err = pinClient.Pin(context.Background(), blockCid, "User/bae-1", []string{"/ip4/127.0.0.1/tcp/9171"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Bearer token", authorization)
+	assert.Equal(t, servicePin{
+		Cid:     blockCid.String(),
+		Name:    "User/bae-1",
+		Origins: []string{"/ip4/127.0.0.1/tcp/9171"},
+	}, received)
+}
+
+func TestKuboClientPin(t *testing.T) {
+	var arg, recursive string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, http.MethodPost, req.Method)
+		assert.Equal(t, "/api/v0/pin/add", req.URL.Path)
+		arg = req.URL.Query().Get("arg")
+		recursive = req.URL.Query().Get("recursive")
+	}))
+	defer server.Close()
+
+	pinClient, err := NewClient(TypeKubo, server.URL, "")
+	require.NoError(t, err)
+	blockCid, err := cid.Decode("bafybeifwfw3g4q6tagffdwq4orrouoosdlsc5rb67q2uj7oplkq7ax5ysm")
+	require.NoError(t, err)
+	require.NoError(t, pinClient.Pin(context.Background(), blockCid, "User/bae-1", nil))
+
+	assert.Equal(t, blockCid.String(), arg)
+	assert.Equal(t, "true", recursive)
+}
+
+func TestClientPinWithUnsuccessfulStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		http.Error(rw, "invalid token", http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	pinClient, err := NewClient(TypeService, server.URL, "token")
+	require.NoError(t, err)
+	blockCid, err := cid.Decode("bafybeifwfw3g4q6tagffdwq4orrouoosdlsc5rb67q2uj7oplkq7ax5ysm")
+	require.NoError(t, err)
+	err = pinClient.Pin(context.Background(), blockCid, "User/bae-1", nil)
+	require.ErrorIs(t, err, ErrPinFailed)
+}
+
+func TestNewClientWithUnknownType(t *testing.T) {
+	_, err := NewClient("pinata", "https://api.pinata.cloud", "")
+	require.ErrorIs(t, err, ErrUnknownType)
+}
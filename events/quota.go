@@ -0,0 +1,44 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package events
+
+import (
+	"github.com/sourcenetwork/immutable"
+)
+
+// QuotaChannel is the bus onto which the quota events are published.
+type QuotaChannel = immutable.Option[Channel[QuotaExceeded]]
+
+// EmptyQuotaChannel is an empty QuotaChannel.
+var EmptyQuotaChannel = immutable.None[Channel[QuotaExceeded]]()
+
+// QuotaExceeded is published when a write exceeds the storage quota of a collection.
+type QuotaExceeded struct {
+	// Collection is the name of the collection.
+	Collection string
+	// DocKey is the key of the document whose write exceeded the quota.
+	DocKey string
+
+	// Documents is the number of documents of the collection with the write.
+	Documents uint64
+	// Bytes is the size in bytes of the field values of the documents of the collection with
+	// the write.
+	Bytes uint64
+
+	// MaxDocuments is the maximum number of documents of the collection, or zero if unlimited.
+	MaxDocuments uint64
+	// MaxBytes is the maximum size in bytes of the collection, or zero if unlimited.
+	MaxBytes uint64
+
+	// Pruned is the number of the oldest documents deleted to make room for the write. The
+	// write has been rejected if zero.
+	Pruned int
+}
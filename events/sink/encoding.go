@@ -0,0 +1,101 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sink
+
+import (
+	"encoding/json"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/linkedin/goavro/v2"
+)
+
+// The serialization formats of the published events.
+const (
+	FormatJSON = "json"
+	FormatCBOR = "cbor"
+	FormatAvro = "avro"
+)
+
+// AvroSchema is the Avro schema of the events published in the Avro format.
+//
+// The events are encoded in the Avro binary encoding, without any header, so consumers must be
+// given this schema.
+const AvroSchema = `{
+	"type": "record",
+	"name": "Event",
+	"namespace": "network.source.defradb",
+	"fields": [
+		{"name": "docKey", "type": "string"},
+		{"name": "cid", "type": "string"},
+		{"name": "schemaID", "type": "string"},
+		{"name": "collection", "type": "string"},
+		{"name": "priority", "type": "long"},
+		{"name": "sequence", "type": "long"},
+		{"name": "isRemote", "type": "boolean"},
+		{"name": "block", "type": "bytes"}
+	]
+}`
+
+// Encoder serializes the events into the payloads of the published messages.
+type Encoder interface {
+	Encode(Event) ([]byte, error)
+}
+
+// NewEncoder returns the encoder of the given serialization format.
+func NewEncoder(format string) (Encoder, error) {
+	switch format {
+	case FormatJSON:
+		return jsonEncoder{}, nil
+	case FormatCBOR:
+		return cborEncoder{}, nil
+	case FormatAvro:
+		codec, err := goavro.NewCodec(AvroSchema)
+		if err != nil {
+			return nil, err
+		}
+		return avroEncoder{codec: codec}, nil
+	default:
+		return nil, NewErrUnknownFormat(format)
+	}
+}
+
+type jsonEncoder struct{}
+
+func (jsonEncoder) Encode(event Event) ([]byte, error) {
+	return json.Marshal(event)
+}
+
+type cborEncoder struct{}
+
+func (cborEncoder) Encode(event Event) ([]byte, error) {
+	return cbor.Marshal(event)
+}
+
+type avroEncoder struct {
+	codec *goavro.Codec
+}
+
+func (e avroEncoder) Encode(event Event) ([]byte, error) {
+	block := event.Block
+	if block == nil {
+		block = []byte{}
+	}
+	return e.codec.BinaryFromNative(nil, map[string]any{
+		"docKey":     event.DocKey,
+		"cid":        event.Cid,
+		"schemaID":   event.SchemaID,
+		"collection": event.Collection,
+		"priority":   int64(event.Priority),
+		"sequence":   int64(event.Sequence),
+		"isRemote":   event.IsRemote,
+		"block":      block,
+	})
+}
@@ -0,0 +1,90 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sink
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/linkedin/goavro/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testEvent = Event{
+	DocKey:     "bae-52b9170d-b77a-5887-b877-cbdbb99b009f",
+	Cid:        "bafybeifwfw3g4q6tagffdwq4orrouoosdlsc5rb67q2uj7oplkq7ax5ysm",
+	SchemaID:   "bafkreibwyhaiseplnbhzycj4o2dv6pi3yzfdhxhtuynu5vbaxs3p6ncrbm",
+	Collection: "User",
+	Priority:   2,
+	Sequence:   7,
+	Block:      []byte{0x12, 0x34},
+}
+
+func TestJSONEncoder(t *testing.T) {
+	encoder, err := NewEncoder(FormatJSON)
+	require.NoError(t, err)
+	payload, err := encoder.Encode(testEvent)
+	require.NoError(t, err)
+
+	var event Event
+	require.NoError(t, json.Unmarshal(payload, &event))
+	assert.Equal(t, testEvent, event)
+}
+
+func TestCBOREncoder(t *testing.T) {
+	encoder, err := NewEncoder(FormatCBOR)
+	require.NoError(t, err)
+	payload, err := encoder.Encode(testEvent)
+	require.NoError(t, err)
+
+	var event Event
+	require.NoError(t, cbor.Unmarshal(payload, &event))
+	assert.Equal(t, testEvent, event)
+}
+
+func TestAvroEncoder(t *testing.T) {
+	encoder, err := NewEncoder(FormatAvro)
+	require.NoError(t, err)
+	payload, err := encoder.Encode(testEvent)
+	require.NoError(t, err)
+
+	codec, err := goavro.NewCodec(AvroSchema)
+	require.NoError(t, err)
+	native, _, err := codec.NativeFromBinary(payload)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"docKey":     testEvent.DocKey,
+		"cid":        testEvent.Cid,
+		"schemaID":   testEvent.SchemaID,
+		"collection": testEvent.Collection,
+		"priority":   int64(2),
+		"sequence":   int64(7),
+		"isRemote":   false,
+		"block":      testEvent.Block,
+	}, native)
+}
+
+func TestAvroEncoderWithoutBlock(t *testing.T) {
+	encoder, err := NewEncoder(FormatAvro)
+	require.NoError(t, err)
+	event := testEvent
+	event.Block = nil
+	event.IsRemote = true
+	_, err = encoder.Encode(event)
+	require.NoError(t, err)
+}
+
+func TestNewEncoderWithUnknownFormat(t *testing.T) {
+	_, err := NewEncoder("xml")
+	require.ErrorIs(t, err, ErrUnknownFormat)
+}
@@ -0,0 +1,38 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sink
+
+import (
+	"github.com/sourcenetwork/defradb/errors"
+)
+
+const (
+	errUnknownType          string = "unknown events sink type"
+	errUnknownFormat        string = "unknown events sink format"
+	errUpdateEventsDisabled string = "the update events of the database are disabled"
+)
+
+var (
+	ErrUnknownType          = errors.New(errUnknownType)
+	ErrUnknownFormat        = errors.New(errUnknownFormat)
+	ErrUpdateEventsDisabled = errors.New(errUpdateEventsDisabled)
+)
+
+// NewErrUnknownType returns a new error indicating that the given sink type is not supported.
+func NewErrUnknownType(brokerType string) error {
+	return errors.New(errUnknownType, errors.NewKV("Type", brokerType))
+}
+
+// NewErrUnknownFormat returns a new error indicating that the given serialization format is not
+// supported.
+func NewErrUnknownFormat(format string) error {
+	return errors.New(errUnknownFormat, errors.NewKV("Format", format))
+}
@@ -0,0 +1,99 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sink
+
+import (
+	"context"
+	"strings"
+
+	"github.com/nats-io/nats.go"
+	"github.com/segmentio/kafka-go"
+)
+
+// The types of the supported message brokers.
+const (
+	TypeKafka = "kafka"
+	TypeNATS  = "nats"
+)
+
+// DocKeyHeader is the header of the NATS messages holding the key of the document of the event,
+// NATS messages having no key.
+const DocKeyHeader = "Defra-DocKey"
+
+// NewPublisher returns a publisher to the broker of the given type at the given address.
+//
+// The address of a Kafka cluster is a comma separated list of brokers (e.g. `localhost:9092`), and
+// the address of a NATS server is its URL (e.g. `nats://localhost:4222`).
+func NewPublisher(brokerType string, address string) (Publisher, error) {
+	switch brokerType {
+	case TypeKafka:
+		return NewKafkaPublisher(strings.Split(address, ",")), nil
+	case TypeNATS:
+		return NewNATSPublisher(address)
+	default:
+		return nil, NewErrUnknownType(brokerType)
+	}
+}
+
+type kafkaPublisher struct {
+	writer *kafka.Writer
+}
+
+// NewKafkaPublisher returns a publisher to the Kafka cluster of the given brokers.
+//
+// The messages are partitioned by key, and the topics are created if they don't exist and the
+// cluster allows it.
+func NewKafkaPublisher(brokers []string) Publisher {
+	return &kafkaPublisher{
+		writer: &kafka.Writer{
+			Addr:                   kafka.TCP(brokers...),
+			Balancer:               &kafka.Hash{},
+			AllowAutoTopicCreation: true,
+		},
+	}
+}
+
+func (p *kafkaPublisher) Publish(ctx context.Context, topic string, key []byte, payload []byte) error {
+	return p.writer.WriteMessages(ctx, kafka.Message{
+		Topic: topic,
+		Key:   key,
+		Value: payload,
+	})
+}
+
+func (p *kafkaPublisher) Close() error {
+	return p.writer.Close()
+}
+
+type natsPublisher struct {
+	conn *nats.Conn
+}
+
+// NewNATSPublisher returns a publisher to the NATS server at the given URL, the topics being the
+// subjects of the messages.
+func NewNATSPublisher(url string) (Publisher, error) {
+	conn, err := nats.Connect(url)
+	if err != nil {
+		return nil, err
+	}
+	return &natsPublisher{conn: conn}, nil
+}
+
+func (p *natsPublisher) Publish(ctx context.Context, topic string, key []byte, payload []byte) error {
+	msg := nats.NewMsg(topic)
+	msg.Header.Set(DocKeyHeader, string(key))
+	msg.Data = payload
+	return p.conn.PublishMsg(msg)
+}
+
+func (p *natsPublisher) Close() error {
+	return p.conn.Drain()
+}
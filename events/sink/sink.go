@@ -0,0 +1,172 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+/*
+Package sink publishes the update events of the database to external message brokers (Kafka or
+NATS), so that stream processing pipelines can be fed directly from DefraDB.
+*/
+package sink
+
+import (
+	"context"
+	"sync"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/events"
+	"github.com/sourcenetwork/defradb/logging"
+)
+
+var log = logging.MustNewLogger("defra.sink")
+
+// Publisher publishes messages to the topics of a message broker.
+type Publisher interface {
+	// Publish publishes the given payload to the given topic, keyed with the given key.
+	Publish(ctx context.Context, topic string, key []byte, payload []byte) error
+
+	// Close closes the connection to the broker, flushing the pending messages.
+	Close() error
+}
+
+// Event is the message published for each update event of the database.
+type Event struct {
+	// DocKey is the key of the updated document.
+	DocKey string `json:"docKey" cbor:"docKey"`
+
+	// Cid is the CID of the commit of the update.
+	Cid string `json:"cid" cbor:"cid"`
+
+	// SchemaID is the ID of the schema of the collection of the document.
+	SchemaID string `json:"schemaID" cbor:"schemaID"`
+
+	// Collection is the name of the collection of the document.
+	Collection string `json:"collection" cbor:"collection"`
+
+	// Priority is the height of the commit in the DAG of the document.
+	Priority uint64 `json:"priority" cbor:"priority"`
+
+	// Sequence is the position of the update within the changefeed of the collection, zero if
+	// the changefeed is disabled.
+	Sequence uint64 `json:"sequence" cbor:"sequence"`
+
+	// IsRemote is true if the update has been merged from another node.
+	IsRemote bool `json:"isRemote" cbor:"isRemote"`
+
+	// Block is the raw data of the block of the commit. It is not set for remote updates.
+	Block []byte `json:"block,omitempty" cbor:"block,omitempty"`
+}
+
+// Sink publishes the update events of a database to a topic.
+//
+// The events are keyed by document key, so that brokers partitioning by key keep the events of
+// each document in order.
+type Sink struct {
+	db        client.DB
+	publisher Publisher
+	encoder   Encoder
+	topic     string
+
+	// collections caches the names of the collections by schema ID.
+	collections map[string]string
+
+	done chan struct{}
+	once sync.Once
+}
+
+// New returns a new Sink publishing the update events of the given database to the given topic,
+// encoded with the given encoder.
+func New(db client.DB, publisher Publisher, encoder Encoder, topic string) *Sink {
+	return &Sink{
+		db:          db,
+		publisher:   publisher,
+		encoder:     encoder,
+		topic:       topic,
+		collections: map[string]string{},
+		done:        make(chan struct{}),
+	}
+}
+
+// Start starts publishing the update events of the database.
+//
+// The events are published until the database is closed.
+func (s *Sink) Start(ctx context.Context) error {
+	if !s.db.Events().Updates.HasValue() {
+		return ErrUpdateEventsDisabled
+	}
+	updates, err := s.db.Events().Updates.Value().Subscribe()
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		defer close(s.done)
+		for update := range updates {
+			if err := s.publish(ctx, update); err != nil {
+				log.ErrorE(
+					ctx,
+					"Failed to publish update event",
+					err,
+					logging.NewKV("DocKey", update.DocKey),
+					logging.NewKV("Cid", update.Cid),
+				)
+			}
+		}
+	}()
+	return nil
+}
+
+// Close waits for the pending events to be published and closes the publisher.
+//
+// It must be called once the database is closed, which ends the update events.
+func (s *Sink) Close() error {
+	var err error
+	s.once.Do(func() {
+		<-s.done
+		err = s.publisher.Close()
+	})
+	return err
+}
+
+func (s *Sink) publish(ctx context.Context, update events.Update) error {
+	event, err := s.newEvent(ctx, update)
+	if err != nil {
+		return err
+	}
+	payload, err := s.encoder.Encode(event)
+	if err != nil {
+		return err
+	}
+	return s.publisher.Publish(ctx, s.topic, []byte(event.DocKey), payload)
+}
+
+func (s *Sink) newEvent(ctx context.Context, update events.Update) (Event, error) {
+	collection, ok := s.collections[update.SchemaID]
+	if !ok {
+		col, err := s.db.GetCollectionBySchemaID(ctx, update.SchemaID)
+		if err != nil {
+			return Event{}, err
+		}
+		collection = col.Name()
+		s.collections[update.SchemaID] = collection
+	}
+
+	event := Event{
+		DocKey:     update.DocKey,
+		Cid:        update.Cid.String(),
+		SchemaID:   update.SchemaID,
+		Collection: collection,
+		Priority:   update.Priority,
+		Sequence:   update.Sequence,
+		IsRemote:   update.IsRemote,
+	}
+	if update.Block != nil {
+		event.Block = update.Block.RawData()
+	}
+	return event, nil
+}
@@ -0,0 +1,120 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	badger "github.com/dgraph-io/badger/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/client"
+	badgerds "github.com/sourcenetwork/defradb/datastore/badger/v3"
+	"github.com/sourcenetwork/defradb/db"
+)
+
+type message struct {
+	topic   string
+	key     []byte
+	payload []byte
+}
+
+// mockPublisher records the published messages.
+type mockPublisher struct {
+	mu       sync.Mutex
+	messages []message
+	closed   bool
+}
+
+func (p *mockPublisher) Publish(ctx context.Context, topic string, key []byte, payload []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.messages = append(p.messages, message{topic: topic, key: key, payload: payload})
+	return nil
+}
+
+func (p *mockPublisher) Close() error {
+	p.closed = true
+	return nil
+}
+
+func newTestDB(t *testing.T, ctx context.Context, options ...db.Option) client.DB {
+	opts := badgerds.Options{Options: badger.DefaultOptions("").WithInMemory(true)}
+	rootstore, err := badgerds.NewDatastore("", &opts)
+	require.NoError(t, err)
+	defra, err := db.NewDB(ctx, rootstore, options...)
+	require.NoError(t, err)
+	return defra
+}
+
+func TestSinkPublishesUpdateEvents(t *testing.T) {
+	ctx := context.Background()
+	defra := newTestDB(t, ctx, db.WithUpdateEvents())
+	err := defra.AddSchema(ctx, `type User { name: String }`)
+	require.NoError(t, err)
+	col, err := defra.GetCollectionByName(ctx, "User")
+	require.NoError(t, err)
+
+	publisher := &mockPublisher{}
+	encoder, err := NewEncoder(FormatJSON)
+	require.NoError(t, err)
+	sink := New(defra, publisher, encoder, "updates")
+	require.NoError(t, sink.Start(ctx))
+
+	doc, err := client.NewDocFromJSON([]byte(`{"name": "John"}`))
+	require.NoError(t, err)
+	require.NoError(t, col.Create(ctx, doc))
+	require.NoError(t, doc.Set("name", "Islam"))
+	require.NoError(t, col.Update(ctx, doc))
+
+	defra.Close(ctx)
+	require.NoError(t, sink.Close())
+	assert.True(t, publisher.closed)
+
+	require.Len(t, publisher.messages, 2)
+	for i, msg := range publisher.messages {
+		assert.Equal(t, "updates", msg.topic)
+		assert.Equal(t, doc.Key().String(), string(msg.key))
+
+		var event Event
+		require.NoError(t, json.Unmarshal(msg.payload, &event))
+		assert.Equal(t, doc.Key().String(), event.DocKey)
+		assert.Equal(t, "User", event.Collection)
+		assert.Equal(t, col.SchemaID(), event.SchemaID)
+		assert.Equal(t, uint64(i+1), event.Priority)
+		assert.False(t, event.IsRemote)
+		assert.NotEmpty(t, event.Block)
+	}
+
+	var last Event
+	require.NoError(t, json.Unmarshal(publisher.messages[1].payload, &last))
+	assert.Equal(t, doc.Head().String(), last.Cid)
+}
+
+func TestSinkWithoutUpdateEvents(t *testing.T) {
+	ctx := context.Background()
+	defra := newTestDB(t, ctx)
+	defer defra.Close(ctx)
+
+	encoder, err := NewEncoder(FormatJSON)
+	require.NoError(t, err)
+	sink := New(defra, &mockPublisher{}, encoder, "updates")
+	require.ErrorIs(t, sink.Start(ctx), ErrUpdateEventsDisabled)
+}
+
+func TestNewPublisherWithUnknownType(t *testing.T) {
+	_, err := NewPublisher("rabbitmq", "localhost:5672")
+	require.ErrorIs(t, err, ErrUnknownType)
+}
@@ -48,21 +48,21 @@ func (l *logger) Debug(ctx context.Context, message string, keyvals ...KV) {
 	l.syncLock.RLock()
 	defer l.syncLock.RUnlock()
 
-	l.logger.Debug(message, toZapFields(keyvals)...)
+	l.logger.Debug(message, toZapFields(withTraceID(ctx, keyvals))...)
 }
 
 func (l *logger) Info(ctx context.Context, message string, keyvals ...KV) {
 	l.syncLock.RLock()
 	defer l.syncLock.RUnlock()
 
-	l.logger.Info(message, toZapFields(keyvals)...)
+	l.logger.Info(message, toZapFields(withTraceID(ctx, keyvals))...)
 }
 
 func (l *logger) Error(ctx context.Context, message string, keyvals ...KV) {
 	l.syncLock.RLock()
 	defer l.syncLock.RUnlock()
 
-	l.logger.Error(message, toZapFields(keyvals)...)
+	l.logger.Error(message, toZapFields(withTraceID(ctx, keyvals))...)
 }
 
 func (l *logger) ErrorE(ctx context.Context, message string, err error, keyvals ...KV) {
@@ -73,14 +73,14 @@ func (l *logger) ErrorE(ctx context.Context, message string, err error, keyvals
 	l.syncLock.RLock()
 	defer l.syncLock.RUnlock()
 
-	l.logger.Error(message, toZapFields(kvs)...)
+	l.logger.Error(message, toZapFields(withTraceID(ctx, kvs))...)
 }
 
 func (l *logger) Fatal(ctx context.Context, message string, keyvals ...KV) {
 	l.syncLock.RLock()
 	defer l.syncLock.RUnlock()
 
-	l.logger.Fatal(message, toZapFields(keyvals)...)
+	l.logger.Fatal(message, toZapFields(withTraceID(ctx, keyvals))...)
 }
 
 func (l *logger) FatalE(ctx context.Context, message string, err error, keyvals ...KV) {
@@ -91,7 +91,7 @@ func (l *logger) FatalE(ctx context.Context, message string, err error, keyvals
 	l.syncLock.RLock()
 	defer l.syncLock.RUnlock()
 
-	l.logger.Fatal(message, toZapFields(kvs)...)
+	l.logger.Fatal(message, toZapFields(withTraceID(ctx, kvs))...)
 }
 
 func (l *logger) FeedbackInfo(ctx context.Context, message string, keyvals ...KV) {
@@ -182,6 +182,14 @@ func (l *logger) ApplyConfig(config Config) {
 	}
 }
 
+// withTraceID adds the trace ID of the given context, if any, to the given key-value pairs.
+func withTraceID(ctx context.Context, keyvals []KV) []KV {
+	if traceID := TraceIDFromContext(ctx); traceID != "" {
+		return append(keyvals, NewKV("TraceID", traceID))
+	}
+	return keyvals
+}
+
 func withStackTrace(err error, keyvals []KV) []KV {
 	if stack, hasStack := getStackTrace(err); hasStack {
 		return append(keyvals, NewKV("stacktrace", stack))
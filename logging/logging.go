@@ -70,6 +70,23 @@ func MustNewLogger(name string) Logger {
 	return logger
 }
 
+type traceIDContextKey struct{}
+
+// WithTraceID returns a new context whose log entries are tagged with the given trace ID, so that
+// the entries of a request can be correlated.
+func WithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, traceIDContextKey{}, traceID)
+}
+
+// TraceIDFromContext returns the trace ID of the given context, or an empty string if it has none.
+func TraceIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	traceID, _ := ctx.Value(traceIDContextKey{}).(string)
+	return traceID
+}
+
 // SetConfig updates all registered loggers with the given config.
 func SetConfig(newConfig Config) {
 	updatedConfig := setConfig(newConfig)
@@ -281,6 +281,28 @@ func TestLogWritesMessagesToLog(t *testing.T) {
 	}
 }
 
+func TestLogWritesTraceIDOfContextToLog(t *testing.T) {
+	defer clearConfig()
+	defer clearRegistry("TestLogName")
+	ctx := WithTraceID(context.Background(), "trace-1")
+	logger, logPath := getLogger(t)
+
+	logger.Info(ctx, "test log message")
+	logger.Info(context.Background(), "test log message")
+	logger.Flush()
+
+	logLines, err := getLogLines(t, logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logLines) != 2 {
+		t.Fatalf("expecting exactly 2 log lines but got %d lines", len(logLines))
+	}
+	assert.Equal(t, "trace-1", logLines[0]["TraceID"])
+	_, hasTraceID := logLines[1]["TraceID"]
+	assert.False(t, hasTraceID)
+}
+
 func TestLogWritesMessagesToLogGivenUpdatedLogLevel(t *testing.T) {
 	defer clearConfig()
 	defer clearRegistry("TestLogName")
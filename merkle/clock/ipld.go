@@ -112,7 +112,9 @@ func (ng *CrdtNodeGetter) GetDeltas(ctx context.Context, cids []cid.Cid) <-chan
 // 	return d, err
 // }
 
-func makeNode(delta core.Delta, heads []cid.Cid) (ipld.Node, error) {
+// NewNode returns the block of the given delta, linking to the given heads and to the sub-DAGs
+// of the delta if it is a composite delta, as it is stored in the DAG store.
+func NewNode(delta core.Delta, heads []cid.Cid) (ipld.Node, error) {
 	var data []byte
 	var err error
 	if delta != nil {
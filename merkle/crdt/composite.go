@@ -86,16 +86,17 @@ func NewMerkleCompositeDAG(
 	}
 }
 
-// Delete sets the values of CompositeDAG for a delete.
+// Delete sets the values of CompositeDAG for a delete, with the given deleted status.
 func (m *MerkleCompositeDAG) Delete(
 	ctx context.Context,
 	links []core.DAGLink,
+	status client.DocumentStatus,
 ) (ipld.Node, uint64, error) {
 	// Set() call on underlying CompositeDAG CRDT
 	// persist/publish delta
 	log.Debug(ctx, "Applying delta-mutator 'Delete' on CompositeDAG")
 	delta := m.reg.Set([]byte{}, links)
-	delta.Status = client.Deleted
+	delta.Status = status
 	nd, err := m.Publish(ctx, delta)
 	if err != nil {
 		return nil, 0, err
@@ -113,6 +114,11 @@ func (m *MerkleCompositeDAG) Set(
 	// Set() call on underlying CompositeDAG CRDT
 	// persist/publish delta
 	log.Debug(ctx, "Applying delta-mutator 'Set' on CompositeDAG")
+	// The patch is encrypted if the deltas of the collection are.
+	patch, err := corecrdt.EncryptDeltaData(ctx, patch)
+	if err != nil {
+		return nil, 0, err
+	}
 	delta := m.reg.Set(patch, links)
 	nd, err := m.Publish(ctx, delta)
 	if err != nil {
@@ -79,6 +79,12 @@ func NewMerkleLWWRegister(
 
 // Set the value of the register.
 func (mlwwreg *MerkleLWWRegister) Set(ctx context.Context, value []byte) (ipld.Node, uint64, error) {
+	// The value is encrypted if the deltas of the collection are.
+	value, err := corecrdt.EncryptDeltaData(ctx, value)
+	if err != nil {
+		return nil, 0, err
+	}
+
 	// Set() call on underlying LWWRegister CRDT
 	// persist/publish delta
 	delta := mlwwreg.reg.Set(value)
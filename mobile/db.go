@@ -0,0 +1,254 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package mobile
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"sync"
+
+	"github.com/sourcenetwork/defradb/client"
+	badgerds "github.com/sourcenetwork/defradb/datastore/badger/v3"
+	"github.com/sourcenetwork/defradb/db"
+	"github.com/sourcenetwork/defradb/errors"
+	"github.com/sourcenetwork/defradb/logging"
+	"github.com/sourcenetwork/defradb/node"
+)
+
+var log = logging.MustNewLogger("defra.mobile")
+
+// Options configures an embedded database.
+type Options struct {
+	// Path is the directory the database stores its data and the key of its P2P node in, such as
+	// a subdirectory of the files directory of the app.
+	Path string
+	// ListenAddress is the multiaddress the P2P node listens on.
+	ListenAddress string
+	// Peers is the comma separated list of the multiaddresses of the peers the P2P node connects
+	// to when syncing starts.
+	Peers string
+	// EnablePubSub enables the sync of the documents of the P2P collections with the peers.
+	EnablePubSub bool
+	// EnableMDNS enables the discovery of the peers on the local network.
+	EnableMDNS bool
+	// EnableRelay enables the relaying of the connections, for peers behind NATs.
+	EnableRelay bool
+	// MaxConnections is the number of connections above which the P2P node prunes its
+	// connections, to spare the battery of the device.
+	MaxConnections int
+}
+
+// NewOptions returns the default options of a database stored at the given path.
+func NewOptions(path string) *Options {
+	return &Options{
+		Path:           path,
+		ListenAddress:  "/ip4/0.0.0.0/tcp/9171",
+		EnablePubSub:   true,
+		MaxConnections: 50,
+	}
+}
+
+// DB is an embedded database.
+//
+// Its methods may be called from any thread, and they block until they complete, so they
+// should not be called from the main thread of the app.
+type DB struct {
+	opts *Options
+	db   client.DB
+
+	// ctx is the context of the operations of the database, which is canceled once it is closed.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu sync.Mutex
+	// node is the P2P node syncing the database, nil if the database is not syncing.
+	node   *node.Node
+	closed bool
+}
+
+// Open opens the database stored with the given options, creating it if needed.
+//
+// The database does not sync with its peers until StartSync is called.
+func Open(opts *Options) (*DB, error) {
+	if opts == nil || opts.Path == "" {
+		return nil, ErrMissingPath
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	rootstore, err := badgerds.NewDatastore(filepath.Join(opts.Path, "data"), nil)
+	if err != nil {
+		cancel()
+		return nil, errors.Wrap("failed to open datastore", err)
+	}
+	defra, err := db.NewDB(ctx, rootstore, db.WithUpdateEvents())
+	if err != nil {
+		cancel()
+		return nil, errors.Wrap("failed to create database", err)
+	}
+	return &DB{opts: opts, db: defra, ctx: ctx, cancel: cancel}, nil
+}
+
+// Close stops syncing and closes the database.
+//
+// Calling Close more than once has no effect.
+func (d *DB) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed {
+		return nil
+	}
+	d.closed = true
+
+	var err error
+	if d.node != nil {
+		err = d.node.Close()
+		d.node = nil
+	}
+	d.db.Close(d.ctx)
+	d.cancel()
+	return err
+}
+
+// store returns the database, or an error if it is closed.
+func (d *DB) store() (client.DB, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed {
+		return nil, ErrClosed
+	}
+	return d.db, nil
+}
+
+// AddSchema adds the types of the given GraphQL SDL to the schema of the database.
+func (d *DB) AddSchema(sdl string) error {
+	store, err := d.store()
+	if err != nil {
+		return err
+	}
+	return store.AddSchema(d.ctx, sdl)
+}
+
+// PatchSchema updates the schema of the database with the given JSON patch.
+func (d *DB) PatchSchema(patch string) error {
+	store, err := d.store()
+	if err != nil {
+		return err
+	}
+	return store.PatchSchema(d.ctx, patch)
+}
+
+// Request executes the given GraphQL query or mutation request, and returns its result as JSON:
+// an object with the `data` of the request and its `errors`, if any.
+//
+// The errors of the request are part of its result, only the errors preventing the request
+// from being executed are returned.
+func (d *DB) Request(request string) (string, error) {
+	store, err := d.store()
+	if err != nil {
+		return "", err
+	}
+	res := store.ExecRequest(d.ctx, request)
+	if res.Pub != nil {
+		res.Pub.Unsubscribe()
+		return "", ErrSubscription
+	}
+	return resultJSON(res.GQL)
+}
+
+// gqlError is the JSON representation of an error of a request.
+type gqlError struct {
+	Message string `json:"message"`
+}
+
+// gqlResult is the JSON representation of the result of a request.
+type gqlResult struct {
+	Data   any        `json:"data"`
+	Errors []gqlError `json:"errors,omitempty"`
+}
+
+func resultJSON(res client.GQLResult) (string, error) {
+	result := gqlResult{Data: res.Data}
+	for _, err := range res.Errors {
+		result.Errors = append(result.Errors, gqlError{Message: err.Error()})
+	}
+	b, err := json.Marshal(result)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// ResultHandler receives the results of a subscription.
+//
+// It is implemented by the app, and is called from a background thread.
+type ResultHandler interface {
+	// OnResult receives a result of the subscription as JSON, in the format of the results of
+	// Request.
+	OnResult(result string)
+}
+
+// Subscription is a subscription to the updates of the documents of a collection.
+type Subscription struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Subscribe executes the given GraphQL subscription request, whose results are passed to the
+// given handler until the subscription is canceled or the database is closed.
+func (d *DB) Subscribe(request string, handler ResultHandler) (*Subscription, error) {
+	store, err := d.store()
+	if err != nil {
+		return nil, err
+	}
+	res := store.ExecRequest(d.ctx, request)
+	if len(res.GQL.Errors) > 0 {
+		return nil, res.GQL.Errors[0]
+	}
+	if res.Pub == nil {
+		return nil, ErrNotSubscription
+	}
+
+	ctx, cancel := context.WithCancel(d.ctx)
+	sub := &Subscription{cancel: cancel, done: make(chan struct{})}
+	go func() {
+		defer close(sub.done)
+		for {
+			select {
+			case <-ctx.Done():
+				res.Pub.Unsubscribe()
+				return
+			case v, open := <-res.Pub.Stream():
+				if !open {
+					// The publisher unsubscribed itself, as the handler did not keep up.
+					return
+				}
+				gqlRes, ok := v.(client.GQLResult)
+				if !ok {
+					continue
+				}
+				result, err := resultJSON(gqlRes)
+				if err != nil {
+					log.ErrorE(ctx, "Failed to encode subscription result", err)
+					continue
+				}
+				handler.OnResult(result)
+			}
+		}
+	}()
+	return sub, nil
+}
+
+// Cancel ends the subscription. The handler is not called once Cancel returns.
+func (s *Subscription) Cancel() {
+	s.cancel()
+	<-s.done
+}
@@ -0,0 +1,107 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package mobile
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSchema = `type User {
+	name: String
+	age: Int
+}`
+
+func openTestDB(t *testing.T, path string) *DB {
+	opts := NewOptions(path)
+	opts.ListenAddress = "/ip4/127.0.0.1/tcp/0"
+	d, err := Open(opts)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, d.Close()) })
+	return d
+}
+
+type resultChannel chan string
+
+func (ch resultChannel) OnResult(result string) {
+	ch <- result
+}
+
+func TestOpenRequiresPath(t *testing.T) {
+	_, err := Open(NewOptions(""))
+	assert.ErrorIs(t, err, ErrMissingPath)
+	_, err = Open(nil)
+	assert.ErrorIs(t, err, ErrMissingPath)
+}
+
+func TestRequest(t *testing.T) {
+	d := openTestDB(t, t.TempDir())
+	require.NoError(t, d.AddSchema(testSchema))
+
+	_, err := d.Request(`mutation { create_User(data: "{\"name\": \"John\", \"age\": 21}") { _key } }`)
+	require.NoError(t, err)
+
+	result, err := d.Request(`query { User { name age } }`)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"data": [{"name": "John", "age": 21}]}`, result)
+
+	result, err = d.Request(`query { Book { name } }`)
+	require.NoError(t, err)
+	res := gqlResult{}
+	require.NoError(t, json.Unmarshal([]byte(result), &res))
+	assert.NotEmpty(t, res.Errors)
+}
+
+func TestReopenKeepsDocuments(t *testing.T) {
+	path := t.TempDir()
+	d, err := Open(NewOptions(path))
+	require.NoError(t, err)
+	require.NoError(t, d.AddSchema(testSchema))
+	_, err = d.Request(`mutation { create_User(data: "{\"name\": \"John\"}") { _key } }`)
+	require.NoError(t, err)
+	require.NoError(t, d.Close())
+
+	_, err = d.Request(`query { User { name } }`)
+	assert.ErrorIs(t, err, ErrClosed)
+
+	d = openTestDB(t, path)
+	result, err := d.Request(`query { User { name } }`)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"data": [{"name": "John"}]}`, result)
+}
+
+func TestSubscribe(t *testing.T) {
+	d := openTestDB(t, t.TempDir())
+	require.NoError(t, d.AddSchema(testSchema))
+
+	_, err := d.Subscribe(`query { User { name } }`, make(resultChannel))
+	assert.ErrorIs(t, err, ErrNotSubscription)
+	_, err = d.Request(`subscription { User { name } }`)
+	assert.ErrorIs(t, err, ErrSubscription)
+
+	results := make(resultChannel, 1)
+	sub, err := d.Subscribe(`subscription { User { name } }`, results)
+	require.NoError(t, err)
+
+	_, err = d.Request(`mutation { create_User(data: "{\"name\": \"John\"}") { _key } }`)
+	require.NoError(t, err)
+	select {
+	case result := <-results:
+		assert.JSONEq(t, `{"data": [{"name": "John"}]}`, result)
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for the subscription result")
+	}
+	sub.Cancel()
+}
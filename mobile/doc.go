@@ -0,0 +1,25 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+/*
+Package mobile embeds a DefraDB instance in iOS and Android apps, so that they can run local-first
+databases that sync with their peers.
+
+The exported API is restricted to the types supported by gomobile: requests and their results are
+JSON strings, subscriptions deliver their results to callbacks, and there are neither channels nor
+contexts. The bindings are generated with:
+
+	gomobile bind -target=android ./mobile
+	gomobile bind -target=ios ./mobile
+
+The P2P node syncing the database can be started and stopped at any time, so that apps can stop
+syncing when they are sent to the background, and resume when they are brought back.
+*/
+package mobile
@@ -0,0 +1,41 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package mobile
+
+import (
+	"github.com/sourcenetwork/defradb/errors"
+)
+
+const (
+	errMissingPath        string = "the path of the database is required"
+	errClosed             string = "the database is closed"
+	errSyncing            string = "the database is already syncing"
+	errNotSyncing         string = "the database is not syncing"
+	errNotSubscription    string = "the request is not a subscription"
+	errSubscription       string = "subscriptions must be requested with Subscribe"
+	errInvalidPeerAddress string = "invalid peer address"
+)
+
+var (
+	ErrMissingPath        = errors.New(errMissingPath)
+	ErrClosed             = errors.New(errClosed)
+	ErrSyncing            = errors.New(errSyncing)
+	ErrNotSyncing         = errors.New(errNotSyncing)
+	ErrNotSubscription    = errors.New(errNotSubscription)
+	ErrSubscription       = errors.New(errSubscription)
+	ErrInvalidPeerAddress = errors.New(errInvalidPeerAddress)
+)
+
+// NewErrInvalidPeerAddress returns a new error indicating that the given peer address is not a
+// valid multiaddress of a peer.
+func NewErrInvalidPeerAddress(inner error, address string) error {
+	return errors.Wrap(errInvalidPeerAddress, inner, errors.NewKV("Address", address))
+}
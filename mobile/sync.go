@@ -0,0 +1,200 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package mobile
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/errors"
+	netutils "github.com/sourcenetwork/defradb/net/utils"
+	"github.com/sourcenetwork/defradb/node"
+)
+
+const (
+	defaultMaxConnections = 50
+	connectionGracePeriod = 20 * time.Second
+)
+
+// nodeDB is the database of a P2P node, which outlives the node so that syncing can be stopped
+// and resumed: it is not closed with the node.
+type nodeDB struct {
+	client.DB
+}
+
+func (nodeDB) Close(context.Context) {}
+
+// StartSync starts the P2P node syncing the database with its peers, which reconnects to the
+// peers it was connected to before it stopped.
+func (d *DB) StartSync() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed {
+		return ErrClosed
+	}
+	if d.node != nil {
+		return ErrSyncing
+	}
+
+	peers, err := parsePeers(d.opts.Peers)
+	if err != nil {
+		return err
+	}
+
+	maxConnections := d.opts.MaxConnections
+	if maxConnections <= 0 {
+		maxConnections = defaultMaxConnections
+	}
+	connManager, err := node.NewConnManager(maxConnections/4, maxConnections, connectionGracePeriod)
+	if err != nil {
+		return err
+	}
+	opts := []node.NodeOpt{
+		node.DataPath(d.opts.Path),
+		node.WithPubSub(d.opts.EnablePubSub),
+		node.WithMDNS(d.opts.EnableMDNS),
+		node.WithEnableRelay(d.opts.EnableRelay),
+		node.WithRejoinPeers(true),
+		func(opt *node.Options) error {
+			opt.ConnManager = connManager
+			return nil
+		},
+	}
+	if d.opts.ListenAddress != "" {
+		opts = append(opts, node.ListenP2PAddrStrings(d.opts.ListenAddress))
+	}
+
+	n, err := node.NewNode(d.ctx, nodeDB{d.db}, opts...)
+	if err != nil {
+		return errors.Wrap("failed to create P2P node", err)
+	}
+	if addrs := append(n.KnownPeers(), peers...); len(addrs) != 0 {
+		n.Boostrap(addrs)
+	}
+	if err := n.Start(); err != nil {
+		if e := n.Close(); e != nil {
+			log.ErrorE(d.ctx, "Failed to close P2P node", e)
+		}
+		return errors.Wrap("failed to start P2P node", err)
+	}
+	d.node = n
+	return nil
+}
+
+// StopSync stops the P2P node, such as when the app is sent to the background. The database is
+// still usable, and syncing can be resumed with StartSync.
+func (d *DB) StopSync() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.node == nil {
+		return ErrNotSyncing
+	}
+	err := d.node.Close()
+	d.node = nil
+	return err
+}
+
+// IsSyncing returns true if the P2P node syncing the database is started.
+func (d *DB) IsSyncing() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.node != nil
+}
+
+// syncNode returns the P2P node syncing the database, or an error if it is not syncing.
+func (d *DB) syncNode() (*node.Node, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.node == nil {
+		return nil, ErrNotSyncing
+	}
+	return d.node, nil
+}
+
+// PeerID returns the peer ID of the P2P node, or an empty string if the database is not
+// syncing.
+func (d *DB) PeerID() string {
+	n, err := d.syncNode()
+	if err != nil {
+		return ""
+	}
+	return n.PeerID().String()
+}
+
+// ListenAddresses returns the comma separated list of the multiaddresses the P2P node listens
+// on, or an empty string if the database is not syncing.
+func (d *DB) ListenAddresses() string {
+	n, err := d.syncNode()
+	if err != nil {
+		return ""
+	}
+	addrs := n.ListenAddrs()
+	values := make([]string, len(addrs))
+	for i, addr := range addrs {
+		values[i] = addr.String()
+	}
+	return strings.Join(values, ",")
+}
+
+// Connect connects the P2P node to the peers of the given comma separated list of
+// multiaddresses, which must include their peer IDs.
+func (d *DB) Connect(peers string) error {
+	n, err := d.syncNode()
+	if err != nil {
+		return err
+	}
+	addrs, err := parsePeers(peers)
+	if err != nil {
+		return err
+	}
+	n.Boostrap(addrs)
+	return nil
+}
+
+// AddP2PCollection syncs the documents of the collection of the given schema ID with the peers.
+//
+// The collection is still synced once syncing is stopped and resumed.
+func (d *DB) AddP2PCollection(schemaID string) error {
+	n, err := d.syncNode()
+	if err != nil {
+		return err
+	}
+	return n.AddP2PCollections([]string{schemaID})
+}
+
+// RemoveP2PCollection stops syncing the documents of the collection of the given schema ID.
+func (d *DB) RemoveP2PCollection(schemaID string) error {
+	n, err := d.syncNode()
+	if err != nil {
+		return err
+	}
+	return n.RemoveP2PCollections([]string{schemaID})
+}
+
+func parsePeers(peers string) ([]peer.AddrInfo, error) {
+	var addrs []peer.AddrInfo
+	for _, address := range strings.Split(peers, ",") {
+		address = strings.TrimSpace(address)
+		if address == "" {
+			continue
+		}
+		info, err := netutils.ParsePeers([]string{address})
+		if err != nil {
+			return nil, NewErrInvalidPeerAddress(err, address)
+		}
+		addrs = append(addrs, info...)
+	}
+	return addrs, nil
+}
@@ -0,0 +1,60 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package mobile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartStopSync(t *testing.T) {
+	d := openTestDB(t, t.TempDir())
+	require.NoError(t, d.AddSchema(testSchema))
+	assert.False(t, d.IsSyncing())
+	assert.Empty(t, d.PeerID())
+	assert.ErrorIs(t, d.StopSync(), ErrNotSyncing)
+
+	require.NoError(t, d.StartSync())
+	assert.True(t, d.IsSyncing())
+	assert.ErrorIs(t, d.StartSync(), ErrSyncing)
+	peerID := d.PeerID()
+	assert.NotEmpty(t, peerID)
+	assert.NotEmpty(t, d.ListenAddresses())
+
+	// The database is still usable once syncing is stopped, and the node keeps its identity
+	// when syncing is resumed.
+	require.NoError(t, d.StopSync())
+	assert.False(t, d.IsSyncing())
+	_, err := d.Request(`mutation { create_User(data: "{\"name\": \"John\"}") { _key } }`)
+	require.NoError(t, err)
+
+	require.NoError(t, d.StartSync())
+	assert.Equal(t, peerID, d.PeerID())
+}
+
+func TestConnectRequiresSync(t *testing.T) {
+	d := openTestDB(t, t.TempDir())
+	assert.ErrorIs(t, d.Connect(""), ErrNotSyncing)
+	assert.ErrorIs(t, d.AddP2PCollection("schema"), ErrNotSyncing)
+}
+
+func TestStartSyncWithInvalidPeers(t *testing.T) {
+	opts := NewOptions(t.TempDir())
+	opts.Peers = "/ip4/127.0.0.1/tcp/9171, not an address"
+	d, err := Open(opts)
+	require.NoError(t, err)
+	defer d.Close()
+
+	assert.ErrorIs(t, d.StartSync(), ErrInvalidPeerAddress)
+	assert.False(t, d.IsSyncing())
+}
@@ -18,8 +18,12 @@ import (
 	"time"
 
 	"github.com/libp2p/go-libp2p/core/peer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 
 	"github.com/sourcenetwork/defradb/client"
+	corenet "github.com/sourcenetwork/defradb/core/net"
+	"github.com/sourcenetwork/defradb/datastore"
 	"github.com/sourcenetwork/defradb/errors"
 	"github.com/sourcenetwork/defradb/events"
 	"github.com/sourcenetwork/defradb/logging"
@@ -51,9 +55,7 @@ func (s *server) pushLog(ctx context.Context, evt events.Update, pid peer.ID) er
 		Cid:      &pb.ProtoCid{Cid: evt.Cid},
 		SchemaID: []byte(evt.SchemaID),
 		Creator:  s.peer.host.ID().String(),
-		Log: &pb.Document_Log{
-			Block: evt.Block.RawData(),
-		},
+		Log:      s.newLog(evt.Block.RawData(), pid),
 	}
 	req := &pb.PushLogRequest{
 		Body: body,
@@ -70,11 +72,66 @@ func (s *server) pushLog(ctx context.Context, evt events.Update, pid peer.ID) er
 		return errors.Wrap("failed to push log", err)
 	}
 
-	cctx, cancel := context.WithTimeout(ctx, PushTimeout)
-	defer cancel()
+	for retry := 0; ; retry++ {
+		var trailer metadata.MD
+		cctx, cancel := context.WithTimeout(ctx, PushTimeout)
+		_, err = client.PushLog(cctx, req, grpc.Trailer(&trailer))
+		cancel()
+		if err == nil {
+			return nil
+		}
 
-	if _, err := client.PushLog(cctx, req); err != nil {
-		return errors.Wrap(fmt.Sprintf("Failed PushLog RPC request %s for %s to %s", evt.Cid, dockey, pid), err)
+		// The peer throttling its ingestion signals when the log can be pushed again.
+		delay, throttled := retryAfter(err, trailer)
+		if !throttled || retry == maxPushRetries {
+			return errors.Wrap(fmt.Sprintf("Failed PushLog RPC request %s for %s to %s", evt.Cid, dockey, pid), err)
+		}
+		log.Debug(
+			ctx,
+			"Push log throttled by peer, retrying",
+			logging.NewKV("DocKey", dockey),
+			logging.NewKV("PID", pid),
+			logging.NewKV("RetryAfter", delay),
+		)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// newLog returns the log holding the given block to push to the given peer, the block being
+// compressed if the peer accepts compressed blocks and compression makes it smaller.
+//
+// The protocols of a peer are known once connected to it, so the blocks pushed before the first
+// connection are not compressed.
+func (s *server) newLog(block []byte, pid peer.ID) *pb.Document_Log {
+	supported, err := s.peer.host.Peerstore().SupportsProtocols(pid, corenet.CompressionProtocol)
+	if err != nil || len(supported) == 0 {
+		return &pb.Document_Log{Block: block}
+	}
+	compressed := datastore.CompressBlock(block)
+	if len(compressed) >= len(block) {
+		return &pb.Document_Log{Block: block}
+	}
+	return &pb.Document_Log{
+		Block:       compressed,
+		Compression: pb.Compression_ZSTD,
+	}
+}
+
+// logBlock returns the uncompressed block of the given log.
+func logBlock(log *pb.Document_Log) ([]byte, error) {
+	switch log.Compression {
+	case pb.Compression_NONE:
+		return log.Block, nil
+	case pb.Compression_ZSTD:
+		if !datastore.IsCompressedBlock(log.Block) {
+			return nil, datastore.ErrInvalidCompressedBlock
+		}
+		return datastore.DecompressBlock(log.Block)
+	default:
+		return nil, errors.New("unsupported block compression", errors.NewKV("Compression", log.Compression))
 	}
-	return nil
 }
@@ -0,0 +1,51 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package net
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/datastore"
+	pb "github.com/sourcenetwork/defradb/net/pb"
+)
+
+func TestLogBlock(t *testing.T) {
+	block := []byte("a block")
+
+	data, err := logBlock(&pb.Document_Log{Block: block})
+	require.NoError(t, err)
+	require.Equal(t, block, data)
+
+	data, err = logBlock(&pb.Document_Log{
+		Block:       datastore.CompressBlock(block),
+		Compression: pb.Compression_ZSTD,
+	})
+	require.NoError(t, err)
+	require.Equal(t, block, data)
+}
+
+func TestLogBlockWithInvalidCompressedBlock(t *testing.T) {
+	_, err := logBlock(&pb.Document_Log{
+		Block:       []byte("a block"),
+		Compression: pb.Compression_ZSTD,
+	})
+	require.ErrorIs(t, err, datastore.ErrInvalidCompressedBlock)
+
+	_, err = logBlock(&pb.Document_Log{
+		Block:       []byte("a block"),
+		Compression: 7,
+	})
+	require.Error(t, err)
+}
@@ -19,6 +19,7 @@ import (
 
 	"github.com/ipfs/go-cid"
 	ipld "github.com/ipfs/go-ipld-format"
+	libpeer "github.com/libp2p/go-libp2p/core/peer"
 
 	"github.com/sourcenetwork/defradb/client"
 	"github.com/sourcenetwork/defradb/core"
@@ -28,6 +29,14 @@ import (
 
 var (
 	DAGSyncTimeout = time.Second * 60
+
+	// DAGFetchBatchSize is the maximum number of blocks requested at once in a single want-list
+	// when fetching the children of a block.
+	DAGFetchBatchSize = 64
+
+	// MaxConcurrentPeerSyncs is the maximum number of DAG syncs triggered by the push logs of a
+	// single peer that are processed concurrently.
+	MaxConcurrentPeerSyncs = 4
 )
 
 // A DAGSyncer is an abstraction to an IPLD-based p2p storage layer.  A
@@ -201,3 +210,62 @@ func (s *cidSafeSet) Remove(c cid.Cid) {
 	}
 	s.mux.Unlock()
 }
+
+// peerLimiter limits the number of concurrent DAG syncs per peer, so that a peer pushing many
+// long histories at once does not use all the fetch capacity of the node.
+type peerLimiter struct {
+	limit int
+	slots map[libpeer.ID]*peerSlots
+	mux   sync.Mutex
+}
+
+type peerSlots struct {
+	sem   chan struct{}
+	users int
+}
+
+func newPeerLimiter(limit int) *peerLimiter {
+	return &peerLimiter{
+		limit: limit,
+		slots: make(map[libpeer.ID]*peerSlots),
+	}
+}
+
+// Acquire waits for a free sync slot of the given peer, or for the given context to be done.
+func (l *peerLimiter) Acquire(ctx context.Context, pid libpeer.ID) error {
+	l.mux.Lock()
+	slots, ok := l.slots[pid]
+	if !ok {
+		slots = &peerSlots{sem: make(chan struct{}, l.limit)}
+		l.slots[pid] = slots
+	}
+	slots.users++
+	l.mux.Unlock()
+
+	select {
+	case slots.sem <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		l.leave(pid, slots)
+		return ctx.Err()
+	}
+}
+
+// Release frees a sync slot of the given peer acquired with Acquire.
+func (l *peerLimiter) Release(pid libpeer.ID) {
+	l.mux.Lock()
+	slots := l.slots[pid]
+	l.mux.Unlock()
+	<-slots.sem
+	l.leave(pid, slots)
+}
+
+// leave forgets the slots of the given peer once no sync uses or waits for them.
+func (l *peerLimiter) leave(pid libpeer.ID, slots *peerSlots) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	slots.users--
+	if slots.users == 0 {
+		delete(l.slots, pid)
+	}
+}
@@ -0,0 +1,54 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package net
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	libpeer "github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeerLimiterLimitsConcurrentSyncsPerPeer(t *testing.T) {
+	ctx := context.Background()
+	limiter := newPeerLimiter(2)
+	peerA := libpeer.ID("a")
+	peerB := libpeer.ID("b")
+
+	require.NoError(t, limiter.Acquire(ctx, peerA))
+	require.NoError(t, limiter.Acquire(ctx, peerA))
+
+	// The slots of a peer are independent of the slots of the others.
+	require.NoError(t, limiter.Acquire(ctx, peerB))
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	err := limiter.Acquire(timeoutCtx, peerA)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	limiter.Release(peerA)
+	require.NoError(t, limiter.Acquire(ctx, peerA))
+}
+
+func TestPeerLimiterForgetsIdlePeers(t *testing.T) {
+	ctx := context.Background()
+	limiter := newPeerLimiter(1)
+	peerA := libpeer.ID("a")
+
+	require.NoError(t, limiter.Acquire(ctx, peerA))
+	limiter.Release(peerA)
+	assert.Empty(t, limiter.slots)
+}
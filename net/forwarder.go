@@ -0,0 +1,143 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package net
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/graphql-go/graphql/language/ast"
+	gqlp "github.com/graphql-go/graphql/language/parser"
+	libpeer "github.com/libp2p/go-libp2p/core/peer"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/db"
+	"github.com/sourcenetwork/defradb/errors"
+	pb "github.com/sourcenetwork/defradb/net/pb"
+)
+
+var _ db.RequestForwarder = (*RequestForwarder)(nil)
+
+// traceIDMetadataKey is the key of the gRPC metadata holding the trace ID of a forwarded request.
+const traceIDMetadataKey = "x-trace-id"
+
+// RequestForwarder forwards the queries for the collections a node does not hold to an upstream
+// peer, which must serve forwarded requests.
+//
+// It is given to both the database and the peer of the node, the latter being used to reach the
+// upstream peer.
+type RequestForwarder struct {
+	upstream libpeer.AddrInfo
+
+	mu   sync.RWMutex
+	peer *Peer
+}
+
+// NewRequestForwarder creates a new request forwarder to the upstream peer of the given
+// multiaddress, which must include its peer ID.
+func NewRequestForwarder(upstream string) (*RequestForwarder, error) {
+	info, err := libpeer.AddrInfoFromString(upstream)
+	if err != nil {
+		return nil, errors.Wrap("invalid upstream peer address", err)
+	}
+	return &RequestForwarder{upstream: *info}, nil
+}
+
+// Upstream returns the ID of the upstream peer.
+func (f *RequestForwarder) Upstream() libpeer.ID {
+	return f.upstream.ID
+}
+
+func (f *RequestForwarder) setPeer(p *Peer) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.peer = p
+}
+
+// ForwardRequest executes the given query, with the variables of the given context, on the
+// upstream peer.
+func (f *RequestForwarder) ForwardRequest(ctx context.Context, request string) *client.RequestResult {
+	res := &client.RequestResult{}
+	reply, err := f.forwardRequest(ctx, request)
+	if err != nil {
+		res.GQL.Errors = []error{err}
+		return res
+	}
+
+	for _, msg := range reply.Errors {
+		res.GQL.Errors = append(res.GQL.Errors, errors.New(msg))
+	}
+	if len(reply.Data) > 0 {
+		var data []map[string]any
+		if err := json.Unmarshal(reply.Data, &data); err != nil {
+			res.GQL.Errors = append(res.GQL.Errors, errors.Wrap("failed to decode upstream data", err))
+			return res
+		}
+		res.GQL.Data = data
+	}
+	return res
+}
+
+func (f *RequestForwarder) forwardRequest(ctx context.Context, request string) (*pb.ExecRequestReply, error) {
+	f.mu.RLock()
+	p := f.peer
+	f.mu.RUnlock()
+	if p == nil {
+		return nil, errors.New("request forwarder is not attached to a peer")
+	}
+	if err := checkForwardedRequest(request); err != nil {
+		return nil, err
+	}
+
+	if len(f.upstream.Addrs) > 0 {
+		if err := p.host.Connect(ctx, f.upstream); err != nil {
+			return nil, errors.Wrap("failed to connect to upstream peer", err)
+		}
+	}
+	peerClient, err := p.server.dial(f.upstream.ID)
+	if err != nil {
+		return nil, errors.Wrap("failed to dial upstream peer", err)
+	}
+
+	variables, err := json.Marshal(client.RequestVariablesFromContext(ctx))
+	if err != nil {
+		return nil, errors.Wrap("failed to encode request variables", err)
+	}
+	cctx, cancel := context.WithTimeout(ctx, PullTimeout)
+	defer cancel()
+	if traceID := client.RequestContextFromContext(ctx).TraceID; traceID != "" {
+		cctx = metadata.AppendToOutgoingContext(cctx, traceIDMetadataKey, traceID)
+	}
+	reply, err := peerClient.ExecRequest(cctx, &pb.ExecRequestRequest{Request: request, Variables: variables})
+	if err != nil {
+		return nil, errors.Wrap("failed ExecRequest RPC request to upstream peer", err)
+	}
+	return reply, nil
+}
+
+// checkForwardedRequest returns an error if the given request is not made of queries only.
+func checkForwardedRequest(request string) error {
+	doc, err := gqlp.Parse(gqlp.ParseParams{Source: request})
+	if err != nil {
+		return err
+	}
+	for _, def := range doc.Definitions {
+		opDef, isOpDef := def.(*ast.OperationDefinition)
+		if isOpDef && opDef.Operation != ast.OperationTypeQuery {
+			return errors.New("only queries can be forwarded", errors.NewKV("Operation", opDef.Operation))
+		}
+	}
+	return nil
+}
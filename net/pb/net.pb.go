@@ -27,6 +27,34 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
 
+// Compression is the compression of a block sent over the network.
+type Compression int32
+
+const (
+	// NONE is an uncompressed block.
+	Compression_NONE Compression = 0
+	// ZSTD is a zstd compressed block, only sent to the peers supporting it.
+	Compression_ZSTD Compression = 1
+)
+
+var Compression_name = map[int32]string{
+	0: "NONE",
+	1: "ZSTD",
+}
+
+var Compression_value = map[string]int32{
+	"NONE": 0,
+	"ZSTD": 1,
+}
+
+func (x Compression) String() string {
+	return proto.EnumName(Compression_name, int32(x))
+}
+
+func (Compression) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_a5b10ce944527a32, []int{0}
+}
+
 // Log represents a thread log.
 type Document struct {
 	// ID of the document.
@@ -72,6 +100,8 @@ var xxx_messageInfo_Document proto.InternalMessageInfo
 type Document_Log struct {
 	// block is the top-level node's raw data as an ipld.Block.
 	Block []byte `protobuf:"bytes,1,opt,name=block,proto3" json:"block,omitempty"`
+	// compression is the compression of the block.
+	Compression Compression `protobuf:"varint,2,opt,name=compression,proto3,enum=net.pb.Compression" json:"compression,omitempty"`
 }
 
 func (m *Document_Log) Reset()         { *m = Document_Log{} }
@@ -114,6 +144,13 @@ func (m *Document_Log) GetBlock() []byte {
 	return nil
 }
 
+func (m *Document_Log) GetCompression() Compression {
+	if m != nil {
+		return m.Compression
+	}
+	return Compression_NONE
+}
+
 type GetDocGraphRequest struct {
 }
 
@@ -375,15 +412,15 @@ func (m *PushLogRequest) GetBody() *PushLogRequest_Body {
 }
 
 type PushLogRequest_Body struct {
-	// docKey is the target DocKey.
+	// docKey is the DocKey of the document that is affected by the log.
 	DocKey *ProtoDocKey `protobuf:"bytes,1,opt,name=docKey,proto3,customtype=ProtoDocKey" json:"docKey,omitempty"`
-	// cid is the target CID.
+	// cid is the CID of the composite of the document.
 	Cid *ProtoCid `protobuf:"bytes,2,opt,name=cid,proto3,customtype=ProtoCid" json:"cid,omitempty"`
-	//
+	// schemaID is the SchemaID of the collection that the document resides in.
 	SchemaID []byte `protobuf:"bytes,3,opt,name=schemaID,proto3" json:"schemaID,omitempty"`
-	// from is the peer ID of the peer that created the log
+	// creator is the peer ID of the peer that created the log.
 	Creator string `protobuf:"bytes,4,opt,name=creator,proto3" json:"creator,omitempty"`
-	// record is the actual record payload.
+	// log hold the block that represent version of the document.
 	Log *Document_Log `protobuf:"bytes,5,opt,name=log,proto3" json:"log,omitempty"`
 }
 
@@ -549,7 +586,217 @@ func (m *GetHeadLogReply) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_GetHeadLogReply proto.InternalMessageInfo
 
+type GetSchemaRequest struct {
+	// schemaID is the SchemaID of the collection whose schema is requested.
+	SchemaID string `protobuf:"bytes,1,opt,name=schemaID,proto3" json:"schemaID,omitempty"`
+}
+
+func (m *GetSchemaRequest) Reset()         { *m = GetSchemaRequest{} }
+func (m *GetSchemaRequest) String() string { return proto.CompactTextString(m) }
+func (*GetSchemaRequest) ProtoMessage()    {}
+func (*GetSchemaRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a5b10ce944527a32, []int{11}
+}
+func (m *GetSchemaRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *GetSchemaRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_GetSchemaRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *GetSchemaRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetSchemaRequest.Merge(m, src)
+}
+func (m *GetSchemaRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *GetSchemaRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetSchemaRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetSchemaRequest proto.InternalMessageInfo
+
+func (m *GetSchemaRequest) GetSchemaID() string {
+	if m != nil {
+		return m.SchemaID
+	}
+	return ""
+}
+
+type GetSchemaReply struct {
+	// versions are the JSON encoded descriptions of the versions of the schema, ordered from the
+	// initial version to the current one.
+	Versions [][]byte `protobuf:"bytes,1,rep,name=versions,proto3" json:"versions,omitempty"`
+	// updates are the JSON encoded signed schema updates that produced each of the versions, if
+	// the schema updates of the peer are signed.
+	Updates [][]byte `protobuf:"bytes,2,rep,name=updates,proto3" json:"updates,omitempty"`
+}
+
+func (m *GetSchemaReply) Reset()         { *m = GetSchemaReply{} }
+func (m *GetSchemaReply) String() string { return proto.CompactTextString(m) }
+func (*GetSchemaReply) ProtoMessage()    {}
+func (*GetSchemaReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a5b10ce944527a32, []int{12}
+}
+func (m *GetSchemaReply) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *GetSchemaReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_GetSchemaReply.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *GetSchemaReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetSchemaReply.Merge(m, src)
+}
+func (m *GetSchemaReply) XXX_Size() int {
+	return m.Size()
+}
+func (m *GetSchemaReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetSchemaReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetSchemaReply proto.InternalMessageInfo
+
+func (m *GetSchemaReply) GetVersions() [][]byte {
+	if m != nil {
+		return m.Versions
+	}
+	return nil
+}
+
+func (m *GetSchemaReply) GetUpdates() [][]byte {
+	if m != nil {
+		return m.Updates
+	}
+	return nil
+}
+
+type ExecRequestRequest struct {
+	// request is the GraphQL query forwarded by the peer.
+	Request string `protobuf:"bytes,1,opt,name=request,proto3" json:"request,omitempty"`
+	// variables are the JSON encoded values of the variables of the request.
+	Variables []byte `protobuf:"bytes,2,opt,name=variables,proto3" json:"variables,omitempty"`
+}
+
+func (m *ExecRequestRequest) Reset()         { *m = ExecRequestRequest{} }
+func (m *ExecRequestRequest) String() string { return proto.CompactTextString(m) }
+func (*ExecRequestRequest) ProtoMessage()    {}
+func (*ExecRequestRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a5b10ce944527a32, []int{13}
+}
+func (m *ExecRequestRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ExecRequestRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ExecRequestRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ExecRequestRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExecRequestRequest.Merge(m, src)
+}
+func (m *ExecRequestRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *ExecRequestRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExecRequestRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExecRequestRequest proto.InternalMessageInfo
+
+func (m *ExecRequestRequest) GetRequest() string {
+	if m != nil {
+		return m.Request
+	}
+	return ""
+}
+
+func (m *ExecRequestRequest) GetVariables() []byte {
+	if m != nil {
+		return m.Variables
+	}
+	return nil
+}
+
+type ExecRequestReply struct {
+	// data is the JSON encoded data produced by the request.
+	Data []byte `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	// errors are the messages of the errors raised by the request.
+	Errors []string `protobuf:"bytes,2,rep,name=errors,proto3" json:"errors,omitempty"`
+}
+
+func (m *ExecRequestReply) Reset()         { *m = ExecRequestReply{} }
+func (m *ExecRequestReply) String() string { return proto.CompactTextString(m) }
+func (*ExecRequestReply) ProtoMessage()    {}
+func (*ExecRequestReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a5b10ce944527a32, []int{14}
+}
+func (m *ExecRequestReply) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ExecRequestReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ExecRequestReply.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ExecRequestReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExecRequestReply.Merge(m, src)
+}
+func (m *ExecRequestReply) XXX_Size() int {
+	return m.Size()
+}
+func (m *ExecRequestReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExecRequestReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExecRequestReply proto.InternalMessageInfo
+
+func (m *ExecRequestReply) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *ExecRequestReply) GetErrors() []string {
+	if m != nil {
+		return m.Errors
+	}
+	return nil
+}
+
 func init() {
+	proto.RegisterEnum("net.pb.Compression", Compression_name, Compression_value)
 	proto.RegisterType((*Document)(nil), "net.pb.Document")
 	proto.RegisterType((*Document_Log)(nil), "net.pb.Document.Log")
 	proto.RegisterType((*GetDocGraphRequest)(nil), "net.pb.GetDocGraphRequest")
@@ -563,42 +810,59 @@ func init() {
 	proto.RegisterType((*GetHeadLogRequest)(nil), "net.pb.GetHeadLogRequest")
 	proto.RegisterType((*PushLogReply)(nil), "net.pb.PushLogReply")
 	proto.RegisterType((*GetHeadLogReply)(nil), "net.pb.GetHeadLogReply")
+	proto.RegisterType((*GetSchemaRequest)(nil), "net.pb.GetSchemaRequest")
+	proto.RegisterType((*GetSchemaReply)(nil), "net.pb.GetSchemaReply")
+	proto.RegisterType((*ExecRequestRequest)(nil), "net.pb.ExecRequestRequest")
+	proto.RegisterType((*ExecRequestReply)(nil), "net.pb.ExecRequestReply")
 }
 
 func init() { proto.RegisterFile("net.proto", fileDescriptor_a5b10ce944527a32) }
 
 var fileDescriptor_a5b10ce944527a32 = []byte{
-	// 480 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x93, 0xc1, 0x6e, 0xda, 0x40,
-	0x10, 0x86, 0x71, 0x20, 0x40, 0x06, 0x12, 0x9a, 0x81, 0xb4, 0xce, 0x46, 0x72, 0x22, 0x0e, 0x6d,
-	0x2f, 0x35, 0x52, 0x2a, 0x55, 0xea, 0x95, 0x52, 0x91, 0xaa, 0x39, 0x44, 0xee, 0x13, 0xd8, 0xeb,
-	0xad, 0x8d, 0x0a, 0x59, 0x6a, 0xd6, 0x95, 0x78, 0x8b, 0xbe, 0x48, 0xdf, 0xa3, 0xc7, 0xf4, 0x56,
-	0xe5, 0x10, 0x55, 0xf0, 0x04, 0x7d, 0x83, 0x6a, 0x67, 0x63, 0xc0, 0xe0, 0x43, 0x6f, 0x9e, 0xf9,
-	0xff, 0x99, 0xd9, 0xf9, 0x46, 0x86, 0x83, 0x5b, 0xa1, 0xdc, 0x69, 0x22, 0x95, 0xc4, 0x2a, 0x7d,
-	0x06, 0xec, 0x55, 0x34, 0x52, 0x71, 0x1a, 0xb8, 0x5c, 0x4e, 0x7a, 0x91, 0x8c, 0x64, 0x8f, 0xe4,
-	0x20, 0xfd, 0x4c, 0x11, 0x05, 0xf4, 0x65, 0xca, 0xba, 0x09, 0xd4, 0x07, 0x92, 0xa7, 0x13, 0x71,
-	0xab, 0xf0, 0x05, 0x54, 0x43, 0xc9, 0x3f, 0x8a, 0xb9, 0x6d, 0x5d, 0x58, 0x2f, 0x9b, 0xfd, 0xd6,
-	0xfd, 0xc3, 0x79, 0xe3, 0x46, 0xdb, 0x06, 0x94, 0xf6, 0x1e, 0x65, 0xbc, 0x80, 0x4a, 0x2c, 0xfc,
-	0xd0, 0xae, 0x90, 0xad, 0x79, 0xff, 0x70, 0x5e, 0x27, 0xdb, 0xbb, 0x51, 0xe8, 0x91, 0xc2, 0xce,
-	0xa0, 0x7c, 0x2d, 0x23, 0xec, 0xc0, 0x7e, 0x30, 0x96, 0xfc, 0x8b, 0x69, 0xe8, 0x99, 0xa0, 0xdb,
-	0x01, 0x1c, 0x0a, 0x35, 0x90, 0x7c, 0x98, 0xf8, 0xd3, 0xd8, 0x13, 0x5f, 0x53, 0x31, 0x53, 0x5d,
-	0x84, 0x27, 0xb9, 0xec, 0x74, 0x3c, 0xef, 0x9e, 0x40, 0xfb, 0x26, 0x9d, 0xc5, 0xdb, 0xd6, 0x36,
-	0x1c, 0xe7, 0xd3, 0xda, 0xdb, 0x82, 0xc3, 0xa1, 0x50, 0xd7, 0x32, 0xca, 0x5c, 0x87, 0xd0, 0xc8,
-	0x12, 0x5a, 0xff, 0x6b, 0xc1, 0x91, 0xae, 0x5a, 0x3b, 0xb0, 0x07, 0x95, 0x40, 0x86, 0x66, 0xdd,
-	0xc6, 0xe5, 0x99, 0x6b, 0x10, 0xba, 0x79, 0x97, 0xdb, 0x97, 0xe1, 0xdc, 0x23, 0x23, 0xfb, 0x61,
-	0x41, 0x45, 0x87, 0xff, 0x8f, 0xca, 0x81, 0x32, 0x1f, 0x85, 0xf6, 0x5e, 0x01, 0x29, 0x2d, 0x20,
-	0x83, 0xfa, 0x8c, 0xc7, 0x62, 0xe2, 0x7f, 0x18, 0xd8, 0x65, 0x82, 0xb4, 0x8a, 0xd1, 0x86, 0x1a,
-	0x4f, 0x84, 0xaf, 0x64, 0x42, 0xa4, 0x0f, 0xbc, 0x2c, 0xc4, 0xe7, 0x50, 0x1e, 0xcb, 0xc8, 0xde,
-	0xa7, 0x77, 0x77, 0xb2, 0x77, 0x67, 0x87, 0x74, 0xf5, 0xe3, 0xb5, 0x41, 0x83, 0x1a, 0x0a, 0x75,
-	0x25, 0xfc, 0x70, 0x83, 0xcb, 0x11, 0x34, 0x57, 0x1b, 0x6a, 0x30, 0xc7, 0xd0, 0xda, 0x34, 0x4d,
-	0xc7, 0xf3, 0xcb, 0x5f, 0x7b, 0x50, 0xfb, 0x24, 0x92, 0x6f, 0x23, 0x2e, 0xf0, 0x3d, 0x61, 0xcc,
-	0x58, 0x23, 0xcb, 0xa6, 0xed, 0x9e, 0x90, 0xd9, 0x85, 0x9a, 0x9e, 0x51, 0xc2, 0x2b, 0x33, 0x75,
-	0xd5, 0x27, 0x47, 0x7b, 0xbb, 0xd1, 0x69, 0xb1, 0x68, 0x3a, 0xbd, 0x81, 0xaa, 0xb9, 0x2b, 0x9e,
-	0x6c, 0xcc, 0x5b, 0x2f, 0xc8, 0xda, 0xdb, 0x69, 0x53, 0xf7, 0x16, 0x6a, 0x8f, 0x7b, 0xe3, 0xd3,
-	0xe2, 0x53, 0xb3, 0xce, 0x4e, 0xde, 0x94, 0xf6, 0x01, 0xd6, 0x88, 0xf0, 0x74, 0xa3, 0x7f, 0x9e,
-	0x2d, 0x7b, 0x56, 0x24, 0x51, 0x8f, 0xbe, 0xfd, 0x73, 0xe1, 0x58, 0x77, 0x0b, 0xc7, 0xfa, 0xb3,
-	0x70, 0xac, 0xef, 0x4b, 0xa7, 0x74, 0xb7, 0x74, 0x4a, 0xbf, 0x97, 0x4e, 0x29, 0xa8, 0xd2, 0xaf,
-	0xf8, 0xfa, 0x5f, 0x00, 0x00, 0x00, 0xff, 0xff, 0x4b, 0x46, 0x27, 0x1c, 0xce, 0x03, 0x00, 0x00,
+	// 673 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x54, 0xcd, 0x6e, 0xd3, 0x40,
+	0x10, 0xce, 0x36, 0x69, 0x7e, 0x26, 0x6d, 0x9a, 0x6e, 0xd2, 0xe2, 0x1a, 0xe4, 0x06, 0x1f, 0xa0,
+	0x42, 0xc2, 0x95, 0x8a, 0x40, 0xe2, 0x02, 0x52, 0x9a, 0x90, 0x22, 0xaa, 0x52, 0xb9, 0x9c, 0xb8,
+	0xf9, 0x67, 0x49, 0x22, 0x92, 0xae, 0x59, 0xdb, 0x15, 0x79, 0x0b, 0x5e, 0x83, 0x03, 0xef, 0xc1,
+	0xb1, 0x47, 0xe8, 0xa1, 0x42, 0xed, 0x13, 0xf0, 0x06, 0x68, 0x77, 0xbd, 0xb1, 0x9d, 0xe6, 0xc0,
+	0x6d, 0x66, 0xbe, 0x6f, 0x67, 0x67, 0xbf, 0xf9, 0x6c, 0xa8, 0x9d, 0x93, 0xc8, 0x0a, 0x18, 0x8d,
+	0x28, 0x2e, 0x8b, 0xd0, 0xd5, 0x9f, 0x0e, 0xc7, 0xd1, 0x28, 0x76, 0x2d, 0x8f, 0x4e, 0xf7, 0x87,
+	0x74, 0x48, 0xf7, 0x05, 0xec, 0xc6, 0x9f, 0x44, 0x26, 0x12, 0x11, 0xc9, 0x63, 0xe6, 0x77, 0x04,
+	0xd5, 0x1e, 0xf5, 0xe2, 0x29, 0x39, 0x8f, 0xf0, 0x63, 0x28, 0xfb, 0xd4, 0x7b, 0x47, 0x66, 0x1a,
+	0xea, 0xa0, 0xbd, 0xb5, 0xee, 0xc6, 0xd5, 0xf5, 0x6e, 0xfd, 0x94, 0xf3, 0x7a, 0xa2, 0x6c, 0x27,
+	0x30, 0xee, 0x40, 0x69, 0x44, 0x1c, 0x5f, 0x2b, 0x09, 0xda, 0xda, 0xd5, 0xf5, 0x6e, 0x55, 0xd0,
+	0x0e, 0xc7, 0xbe, 0x2d, 0x10, 0xdd, 0x86, 0xe2, 0x31, 0x1d, 0xe2, 0x36, 0xac, 0xba, 0x13, 0xea,
+	0x7d, 0x96, 0x0d, 0x6d, 0x99, 0xe0, 0xe7, 0x50, 0xf7, 0xe8, 0x34, 0x60, 0x24, 0x0c, 0xc7, 0xf4,
+	0x5c, 0x5b, 0xe9, 0xa0, 0xbd, 0xc6, 0x41, 0xcb, 0x92, 0x2f, 0xb0, 0x0e, 0x53, 0xc8, 0xce, 0xf2,
+	0xcc, 0x36, 0xe0, 0x01, 0x89, 0x7a, 0xd4, 0x1b, 0x30, 0x27, 0x18, 0xd9, 0xe4, 0x4b, 0x4c, 0xc2,
+	0xc8, 0xc4, 0xd0, 0xcc, 0x55, 0x83, 0xc9, 0xcc, 0xdc, 0x82, 0xd6, 0x69, 0x1c, 0x8e, 0x16, 0xa9,
+	0x2d, 0xd8, 0xcc, 0x97, 0x39, 0x77, 0x03, 0xd6, 0x07, 0x24, 0x3a, 0xa6, 0x43, 0xc5, 0x5a, 0x87,
+	0xba, 0x2a, 0x70, 0xfc, 0x2f, 0x82, 0x06, 0x3f, 0x95, 0x32, 0xf0, 0x3e, 0x94, 0x5c, 0xea, 0x4b,
+	0x95, 0xea, 0x07, 0xf7, 0xd5, 0xe0, 0x79, 0x96, 0xd5, 0xa5, 0xfe, 0xcc, 0x16, 0x44, 0xfd, 0x07,
+	0x82, 0x12, 0x4f, 0xff, 0x5f, 0x61, 0x03, 0x8a, 0xde, 0xd8, 0x17, 0xd2, 0x2c, 0x0a, 0xcc, 0x01,
+	0xac, 0x43, 0x35, 0xf4, 0x46, 0x64, 0xea, 0xbc, 0xed, 0x69, 0x45, 0xa1, 0xed, 0x3c, 0xc7, 0x1a,
+	0x54, 0x3c, 0x46, 0x9c, 0x88, 0x32, 0xb1, 0xa0, 0x9a, 0xad, 0x52, 0xfc, 0x08, 0x8a, 0x13, 0x3a,
+	0xd4, 0x56, 0xc5, 0xdc, 0x6d, 0x35, 0xb7, 0xda, 0xbf, 0xc5, 0x87, 0xe7, 0x04, 0x2e, 0xd4, 0x80,
+	0x44, 0x47, 0xc4, 0xf1, 0x33, 0xba, 0x34, 0x60, 0x6d, 0xfe, 0x42, 0x2e, 0xcc, 0x26, 0x6c, 0x64,
+	0x49, 0xbc, 0x64, 0x89, 0x5d, 0x9c, 0x89, 0x41, 0x94, 0x58, 0xd9, 0x49, 0x91, 0x18, 0x67, 0x9e,
+	0x9b, 0x6f, 0xa0, 0x91, 0xe1, 0x07, 0x93, 0x19, 0x67, 0x5f, 0x10, 0xc6, 0xd7, 0x1d, 0x6a, 0xa8,
+	0x53, 0xe4, 0xef, 0x52, 0x39, 0x7f, 0x57, 0x1c, 0xf8, 0x4e, 0x44, 0x42, 0x6d, 0x45, 0x40, 0x2a,
+	0x35, 0x8f, 0x01, 0xf7, 0xbf, 0x12, 0x2f, 0xb9, 0x52, 0xdd, 0xac, 0x41, 0x85, 0xc9, 0x30, 0xb9,
+	0x58, 0xa5, 0xf8, 0x01, 0xd4, 0x2e, 0x1c, 0x36, 0x76, 0xdc, 0x89, 0xe8, 0xc5, 0xe5, 0x4b, 0x0b,
+	0xe6, 0x2b, 0x68, 0xe6, 0xba, 0xf1, 0xb9, 0x30, 0x94, 0x7c, 0x27, 0x72, 0x12, 0x1f, 0x8b, 0x18,
+	0x6f, 0x43, 0x99, 0x30, 0x46, 0x99, 0x1c, 0xa7, 0x66, 0x27, 0xd9, 0x93, 0x87, 0x50, 0xcf, 0x78,
+	0x18, 0x57, 0xa1, 0x74, 0xf2, 0xfe, 0xa4, 0xdf, 0x2c, 0xf0, 0xe8, 0xe3, 0xd9, 0x87, 0x5e, 0x13,
+	0x1d, 0xfc, 0x2e, 0x42, 0xe5, 0x8c, 0xb0, 0x8b, 0xb1, 0x47, 0x70, 0x5f, 0xf8, 0x4d, 0x99, 0x12,
+	0xeb, 0x6a, 0x2d, 0x77, 0xbd, 0xae, 0x6b, 0x4b, 0x31, 0xae, 0x7c, 0x01, 0x1f, 0xc9, 0xf5, 0xcc,
+	0xfb, 0xe4, 0x6c, 0xb9, 0xd8, 0x68, 0x67, 0x39, 0x28, 0x3b, 0xbd, 0x80, 0xb2, 0xfc, 0x00, 0xf0,
+	0x56, 0xe6, 0xbe, 0xd4, 0x09, 0x7a, 0x6b, 0xb1, 0x2c, 0xcf, 0xbd, 0x84, 0x4a, 0x62, 0x10, 0xbc,
+	0xbd, 0xfc, 0x9b, 0xd0, 0xdb, 0x77, 0xea, 0xf2, 0x68, 0x17, 0x20, 0xf5, 0x12, 0xde, 0xc9, 0xf4,
+	0xcf, 0x9b, 0x50, 0xbf, 0xb7, 0x0c, 0x92, 0x3d, 0x5e, 0x43, 0x6d, 0x6e, 0x26, 0x9c, 0x55, 0x2a,
+	0xe7, 0x47, 0x7d, 0x7b, 0x09, 0x22, 0x1b, 0xf4, 0xa1, 0x9e, 0xd9, 0x7b, 0xba, 0x88, 0xbb, 0xd6,
+	0x4a, 0x17, 0xb1, 0x68, 0x14, 0xb3, 0xd0, 0xd5, 0x7e, 0xde, 0x18, 0xe8, 0xf2, 0xc6, 0x40, 0x7f,
+	0x6e, 0x0c, 0xf4, 0xed, 0xd6, 0x28, 0x5c, 0xde, 0x1a, 0x85, 0x5f, 0xb7, 0x46, 0xc1, 0x2d, 0x8b,
+	0x7f, 0xee, 0xb3, 0x7f, 0x01, 0x00, 0x00, 0xff, 0xff, 0xc2, 0x58, 0x4f, 0x84, 0xb7, 0x05, 0x00,
+	0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -623,6 +887,10 @@ type ServiceClient interface {
 	PushLog(ctx context.Context, in *PushLogRequest, opts ...grpc.CallOption) (*PushLogReply, error)
 	// GetHeadLog from this peer
 	GetHeadLog(ctx context.Context, in *GetHeadLogRequest, opts ...grpc.CallOption) (*GetHeadLogReply, error)
+	// GetSchema from this peer, if it shares its schemas.
+	GetSchema(ctx context.Context, in *GetSchemaRequest, opts ...grpc.CallOption) (*GetSchemaReply, error)
+	// ExecRequest on this peer, if it serves the queries forwarded by its peers.
+	ExecRequest(ctx context.Context, in *ExecRequestRequest, opts ...grpc.CallOption) (*ExecRequestReply, error)
 }
 
 type serviceClient struct {
@@ -678,6 +946,24 @@ func (c *serviceClient) GetHeadLog(ctx context.Context, in *GetHeadLogRequest, o
 	return out, nil
 }
 
+func (c *serviceClient) GetSchema(ctx context.Context, in *GetSchemaRequest, opts ...grpc.CallOption) (*GetSchemaReply, error) {
+	out := new(GetSchemaReply)
+	err := c.cc.Invoke(ctx, "/net.pb.Service/GetSchema", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serviceClient) ExecRequest(ctx context.Context, in *ExecRequestRequest, opts ...grpc.CallOption) (*ExecRequestReply, error) {
+	out := new(ExecRequestReply)
+	err := c.cc.Invoke(ctx, "/net.pb.Service/ExecRequest", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ServiceServer is the server API for Service service.
 type ServiceServer interface {
 	// GetDocGraph from this peer.
@@ -690,6 +976,10 @@ type ServiceServer interface {
 	PushLog(context.Context, *PushLogRequest) (*PushLogReply, error)
 	// GetHeadLog from this peer
 	GetHeadLog(context.Context, *GetHeadLogRequest) (*GetHeadLogReply, error)
+	// GetSchema from this peer, if it shares its schemas.
+	GetSchema(context.Context, *GetSchemaRequest) (*GetSchemaReply, error)
+	// ExecRequest on this peer, if it serves the queries forwarded by its peers.
+	ExecRequest(context.Context, *ExecRequestRequest) (*ExecRequestReply, error)
 }
 
 // UnimplementedServiceServer can be embedded to have forward compatible implementations.
@@ -711,6 +1001,12 @@ func (*UnimplementedServiceServer) PushLog(ctx context.Context, req *PushLogRequ
 func (*UnimplementedServiceServer) GetHeadLog(ctx context.Context, req *GetHeadLogRequest) (*GetHeadLogReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetHeadLog not implemented")
 }
+func (*UnimplementedServiceServer) GetSchema(ctx context.Context, req *GetSchemaRequest) (*GetSchemaReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSchema not implemented")
+}
+func (*UnimplementedServiceServer) ExecRequest(ctx context.Context, req *ExecRequestRequest) (*ExecRequestReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExecRequest not implemented")
+}
 
 func RegisterServiceServer(s *grpc.Server, srv ServiceServer) {
 	s.RegisterService(&_Service_serviceDesc, srv)
@@ -806,6 +1102,42 @@ func _Service_GetHeadLog_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Service_GetSchema_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSchemaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceServer).GetSchema(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/net.pb.Service/GetSchema",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceServer).GetSchema(ctx, req.(*GetSchemaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Service_ExecRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExecRequestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceServer).ExecRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/net.pb.Service/ExecRequest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceServer).ExecRequest(ctx, req.(*ExecRequestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Service_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "net.pb.Service",
 	HandlerType: (*ServiceServer)(nil),
@@ -830,6 +1162,14 @@ var _Service_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetHeadLog",
 			Handler:    _Service_GetHeadLog_Handler,
 		},
+		{
+			MethodName: "GetSchema",
+			Handler:    _Service_GetSchema_Handler,
+		},
+		{
+			MethodName: "ExecRequest",
+			Handler:    _Service_ExecRequest_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "net.proto",
@@ -902,6 +1242,11 @@ func (m *Document_Log) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.Compression != 0 {
+		i = encodeVarintNet(dAtA, i, uint64(m.Compression))
+		i--
+		dAtA[i] = 0x10
+	}
 	if len(m.Block) > 0 {
 		i -= len(m.Block)
 		copy(dAtA[i:], m.Block)
@@ -1227,69 +1572,219 @@ func (m *GetHeadLogReply) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func encodeVarintNet(dAtA []byte, offset int, v uint64) int {
-	offset -= sovNet(v)
-	base := offset
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
+func (m *GetSchemaRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	dAtA[offset] = uint8(v)
-	return base
+	return dAtA[:n], nil
 }
-func (m *Document) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if m.DocKey != nil {
-		l = m.DocKey.Size()
-		n += 1 + l + sovNet(uint64(l))
-	}
-	if m.Head != nil {
-		l = m.Head.Size()
-		n += 1 + l + sovNet(uint64(l))
-	}
-	return n
+
+func (m *GetSchemaRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *Document_Log) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+func (m *GetSchemaRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Block)
-	if l > 0 {
-		n += 1 + l + sovNet(uint64(l))
+	if len(m.SchemaID) > 0 {
+		i -= len(m.SchemaID)
+		copy(dAtA[i:], m.SchemaID)
+		i = encodeVarintNet(dAtA, i, uint64(len(m.SchemaID)))
+		i--
+		dAtA[i] = 0xa
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *GetDocGraphRequest) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *GetSchemaReply) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	var l int
-	_ = l
-	return n
+	return dAtA[:n], nil
 }
 
-func (m *GetDocGraphReply) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	return n
+func (m *GetSchemaReply) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *PushDocGraphRequest) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+func (m *GetSchemaReply) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Updates) > 0 {
+		for iNdEx := len(m.Updates) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Updates[iNdEx])
+			copy(dAtA[i:], m.Updates[iNdEx])
+			i = encodeVarintNet(dAtA, i, uint64(len(m.Updates[iNdEx])))
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if len(m.Versions) > 0 {
+		for iNdEx := len(m.Versions) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Versions[iNdEx])
+			copy(dAtA[i:], m.Versions[iNdEx])
+			i = encodeVarintNet(dAtA, i, uint64(len(m.Versions[iNdEx])))
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ExecRequestRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ExecRequestRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ExecRequestRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Variables) > 0 {
+		i -= len(m.Variables)
+		copy(dAtA[i:], m.Variables)
+		i = encodeVarintNet(dAtA, i, uint64(len(m.Variables)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Request) > 0 {
+		i -= len(m.Request)
+		copy(dAtA[i:], m.Request)
+		i = encodeVarintNet(dAtA, i, uint64(len(m.Request)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ExecRequestReply) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ExecRequestReply) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ExecRequestReply) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Errors) > 0 {
+		for iNdEx := len(m.Errors) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Errors[iNdEx])
+			copy(dAtA[i:], m.Errors[iNdEx])
+			i = encodeVarintNet(dAtA, i, uint64(len(m.Errors[iNdEx])))
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if len(m.Data) > 0 {
+		i -= len(m.Data)
+		copy(dAtA[i:], m.Data)
+		i = encodeVarintNet(dAtA, i, uint64(len(m.Data)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintNet(dAtA []byte, offset int, v uint64) int {
+	offset -= sovNet(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *Document) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.DocKey != nil {
+		l = m.DocKey.Size()
+		n += 1 + l + sovNet(uint64(l))
+	}
+	if m.Head != nil {
+		l = m.Head.Size()
+		n += 1 + l + sovNet(uint64(l))
+	}
+	return n
+}
+
+func (m *Document_Log) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Block)
+	if l > 0 {
+		n += 1 + l + sovNet(uint64(l))
+	}
+	if m.Compression != 0 {
+		n += 1 + sovNet(uint64(m.Compression))
+	}
+	return n
+}
+
+func (m *GetDocGraphRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	return n
+}
+
+func (m *GetDocGraphReply) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	return n
+}
+
+func (m *PushDocGraphRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
 	var l int
 	_ = l
 	return n
@@ -1391,6 +1886,76 @@ func (m *GetHeadLogReply) Size() (n int) {
 	return n
 }
 
+func (m *GetSchemaRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.SchemaID)
+	if l > 0 {
+		n += 1 + l + sovNet(uint64(l))
+	}
+	return n
+}
+
+func (m *GetSchemaReply) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Versions) > 0 {
+		for _, b := range m.Versions {
+			l = len(b)
+			n += 1 + l + sovNet(uint64(l))
+		}
+	}
+	if len(m.Updates) > 0 {
+		for _, b := range m.Updates {
+			l = len(b)
+			n += 1 + l + sovNet(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *ExecRequestRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Request)
+	if l > 0 {
+		n += 1 + l + sovNet(uint64(l))
+	}
+	l = len(m.Variables)
+	if l > 0 {
+		n += 1 + l + sovNet(uint64(l))
+	}
+	return n
+}
+
+func (m *ExecRequestReply) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Data)
+	if l > 0 {
+		n += 1 + l + sovNet(uint64(l))
+	}
+	if len(m.Errors) > 0 {
+		for _, s := range m.Errors {
+			l = len(s)
+			n += 1 + l + sovNet(uint64(l))
+		}
+	}
+	return n
+}
+
 func sovNet(x uint64) (n int) {
 	return (math_bits.Len64(x|1) + 6) / 7
 }
@@ -1580,6 +2145,25 @@ func (m *Document_Log) Unmarshal(dAtA []byte) error {
 				m.Block = []byte{}
 			}
 			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Compression", wireType)
+			}
+			m.Compression = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNet
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Compression |= Compression(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipNet(dAtA[iNdEx:])
@@ -2359,6 +2943,434 @@ func (m *GetHeadLogReply) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *GetSchemaRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowNet
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GetSchemaRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GetSchemaRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SchemaID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNet
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthNet
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthNet
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SchemaID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipNet(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthNet
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *GetSchemaReply) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowNet
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GetSchemaReply: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GetSchemaReply: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Versions", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNet
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthNet
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthNet
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Versions = append(m.Versions, make([]byte, postIndex-iNdEx))
+			copy(m.Versions[len(m.Versions)-1], dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Updates", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNet
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthNet
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthNet
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Updates = append(m.Updates, make([]byte, postIndex-iNdEx))
+			copy(m.Updates[len(m.Updates)-1], dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipNet(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthNet
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ExecRequestRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowNet
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ExecRequestRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ExecRequestRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Request", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNet
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthNet
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthNet
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Request = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Variables", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNet
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthNet
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthNet
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Variables = append(m.Variables[:0], dAtA[iNdEx:postIndex]...)
+			if m.Variables == nil {
+				m.Variables = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipNet(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthNet
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ExecRequestReply) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowNet
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ExecRequestReply: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ExecRequestReply: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Data", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNet
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthNet
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthNet
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Data = append(m.Data[:0], dAtA[iNdEx:postIndex]...)
+			if m.Data == nil {
+				m.Data = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Errors", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNet
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthNet
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthNet
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Errors = append(m.Errors, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipNet(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthNet
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipNet(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
@@ -29,6 +29,7 @@ import (
 	gostream "github.com/libp2p/go-libp2p-gostream"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
 	"github.com/libp2p/go-libp2p/core/host"
+	libp2pnetwork "github.com/libp2p/go-libp2p/core/network"
 	"github.com/libp2p/go-libp2p/core/peer"
 	peerstore "github.com/libp2p/go-libp2p/core/peerstore"
 	"github.com/libp2p/go-libp2p/core/routing"
@@ -37,6 +38,7 @@ import (
 
 	"github.com/sourcenetwork/defradb/client"
 	"github.com/sourcenetwork/defradb/core"
+	corecrdt "github.com/sourcenetwork/defradb/core/crdt"
 	corenet "github.com/sourcenetwork/defradb/core/net"
 	"github.com/sourcenetwork/defradb/datastore"
 	"github.com/sourcenetwork/defradb/errors"
@@ -73,6 +75,20 @@ type Peer struct {
 	// outstanding log request currently being processed
 	queuedChildren *cidSafeSet
 
+	// schemaSync is set when the schemas are shared with, and adopted from, the other peers.
+	schemaSync bool
+
+	// keyring holds the keys of the collections whose deltas are encrypted. The blocks of the
+	// encrypted collections whose key is not in the keyring are stored and replicated, but not
+	// merged.
+	keyring *corecrdt.Keyring
+
+	// serveRequests is set when the queries forwarded by the other peers are executed.
+	serveRequests bool
+
+	// ingestLimits are the rate limits of the ingestion of the deltas pushed by the other peers.
+	ingestLimits IngestLimits
+
 	// replicators is a map from collectionName => peerId
 	replicators map[string]map[peer.ID]struct{}
 	mu          sync.Mutex
@@ -96,6 +112,11 @@ func NewPeer(
 	tcpAddr ma.Multiaddr,
 	serverOptions []grpc.ServerOption,
 	dialOptions []grpc.DialOption,
+	schemaSync bool,
+	keyring *corecrdt.Keyring,
+	serveRequests bool,
+	forwarder *RequestForwarder,
+	ingestLimits IngestLimits,
 ) (*Peer, error) {
 	if db == nil {
 		return nil, errors.New("database object can't be empty")
@@ -114,12 +135,19 @@ func NewPeer(
 		sendJobs:       make(chan *dagJob),
 		replicators:    make(map[string]map[peer.ID]struct{}),
 		queuedChildren: newCidSafeSet(),
+		schemaSync:     schemaSync,
+		keyring:        keyring,
+		serveRequests:  serveRequests,
+		ingestLimits:   ingestLimits,
 	}
 	var err error
 	p.server, err = newServer(p, db, dialOptions...)
 	if err != nil {
 		return nil, err
 	}
+	if forwarder != nil {
+		forwarder.setPeer(p)
+	}
 
 	err = p.loadReplicators(p.ctx)
 	if err != nil {
@@ -142,6 +170,11 @@ func (p *Peer) Start() error {
 		return err
 	}
 
+	// advertise that we accept compressed blocks
+	p.host.SetStreamHandler(corenet.CompressionProtocol, func(s libp2pnetwork.Stream) {
+		_ = s.Reset()
+	})
+
 	if p.ps != nil {
 		if !p.db.Events().Updates.HasValue() {
 			return errors.New("tried to subscribe to update channel, but update channel is nil")
@@ -187,6 +220,7 @@ func (p *Peer) Close() error {
 	}
 	stopGRPCServer(p.ctx, p.p2pRPC)
 	// stopGRPCServer(p.tcpRPC)
+	p.host.RemoveStreamHandler(corenet.CompressionProtocol)
 
 	// close event emitters
 	if p.server.pubSubEmitter != nil {
@@ -223,6 +257,15 @@ func (p *Peer) handleBroadcastLoop() {
 			return
 		}
 
+		// Remote updates have already been broadcasted by the node that made them.
+		if update.IsRemote {
+			continue
+		}
+
+		if err := p.setLocalCommitAuthor(update); err != nil {
+			log.ErrorE(p.ctx, "Failed to record the author of a local commit", err, logging.NewKV("CID", update.Cid))
+		}
+
 		// check log priority, 1 is new doc log
 		// 2 is update log
 		var err error
@@ -678,6 +721,21 @@ func (p *Peer) handleDocUpdateLog(evt events.Update) error {
 	return nil
 }
 
+// setLocalCommitAuthor records this peer as the author of the commits of the given update.
+func (p *Peer) setLocalCommitAuthor(evt events.Update) error {
+	txn, err := p.db.NewTxn(p.ctx, false)
+	if err != nil {
+		return err
+	}
+	defer txn.Discard(p.ctx)
+
+	err = setCommitAuthor(p.ctx, txn.Systemstore(), evt.Block, p.host.ID().String())
+	if err != nil {
+		return err
+	}
+	return txn.Commit(p.ctx)
+}
+
 func (p *Peer) pushLogToReplicators(ctx context.Context, lg events.Update) {
 	// push to each peer (replicator)
 	peers := make(map[string]struct{})
@@ -23,7 +23,9 @@ import (
 
 	"github.com/sourcenetwork/defradb/client"
 	"github.com/sourcenetwork/defradb/core"
+	corecrdt "github.com/sourcenetwork/defradb/core/crdt"
 	"github.com/sourcenetwork/defradb/datastore"
+	"github.com/sourcenetwork/defradb/db"
 	"github.com/sourcenetwork/defradb/db/base"
 	"github.com/sourcenetwork/defradb/errors"
 	"github.com/sourcenetwork/defradb/events"
@@ -68,8 +70,33 @@ func (p *Peer) processLog(
 		return nil, err
 	}
 
-	ng := p.createNodeGetter(crdt, getter)
-	cids, err := crdt.Clock().ProcessNode(ctx, ng, c, delta.GetPriority(), delta, nd)
+	if compositeDelta, isComposite := delta.(*corecrdt.CompositeDAGDelta); isComposite {
+		err = col.WithTxn(txn).RecordRemoteCommit(ctx, dockey.DocKey, c, compositeDelta.Status)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var cids []cid.Cid
+	cipher := p.keyring.Cipher(col.Name())
+	if cipher == nil && corecrdt.IsEncryptedDelta(delta) {
+		// The block can't be read without the key of the collection, so it is only stored, for
+		// the peers that have the key to sync it from this one.
+		cids, err = unknownLinks(ctx, txn, nd)
+	} else {
+		ng := p.createNodeGetter(crdt, getter)
+		stats := &corecrdt.MergeStats{}
+		mergeCtx := corecrdt.WithMergeStats(corecrdt.WithDeltaCipher(ctx, cipher), stats)
+		cids, err = crdt.Clock().ProcessNode(mergeCtx, ng, c, delta.GetPriority(), delta, nd)
+		if err == nil {
+			col.RecordRemoteMerge(client.RemoteMerge{
+				DocKey:    dockey.DocKey,
+				Priority:  delta.GetPriority(),
+				Commit:    field == "",
+				Conflicts: stats.Conflicts(),
+			})
+		}
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -82,6 +109,53 @@ func (p *Peer) processLog(
 	return cids, nil
 }
 
+// validateAppendOnlyLog returns an error if the collection is append-only and the given block is
+// not the creation of a document unknown to the store, append-only collections only accepting new
+// documents from the peers. The deletes of the documents pruned by the peers are rejected too, each
+// node pruning its own documents by its own retention policies and quotas.
+func validateAppendOnlyLog(
+	ctx context.Context,
+	txn datastore.Txn,
+	col client.Collection,
+	dockey core.DataStoreKey,
+	nd ipld.Node,
+) error {
+	if !col.Schema().AppendOnly {
+		return nil
+	}
+	delta, err := corecrdt.CompositeDAG{}.DeltaDecode(nd)
+	if err != nil {
+		return errors.Wrap("failed to decode delta object", err)
+	}
+	if !delta.(*corecrdt.CompositeDAGDelta).IsCreation() {
+		return db.NewErrAppendOnlyCollection(col.Name())
+	}
+	primaryKey := base.MakeCollectionKey(col.Description()).WithInstanceInfo(dockey).ToPrimaryDataStoreKey()
+	exists, err := txn.Datastore().Has(ctx, primaryKey.ToDS())
+	if err != nil {
+		return err
+	}
+	if exists {
+		return db.NewErrAppendOnlyCollection(col.Name())
+	}
+	return nil
+}
+
+// unknownLinks returns the CIDs of the blocks the given block links to that are not in the DAG store.
+func unknownLinks(ctx context.Context, txn datastore.Txn, nd ipld.Node) ([]cid.Cid, error) {
+	var cids []cid.Cid
+	for _, link := range nd.Links() {
+		known, err := txn.DAGstore().Has(ctx, link.Cid)
+		if err != nil {
+			return nil, err
+		}
+		if !known {
+			cids = append(cids, link.Cid)
+		}
+	}
+	return cids, nil
+}
+
 func initCRDTForType(
 	ctx context.Context,
 	txn datastore.MultiStore,
@@ -120,6 +194,25 @@ func initCRDTForType(
 	)
 }
 
+// setCommitAuthor records the given peer as the author of the given block and of the field
+// blocks it links to, which are part of the same update.
+func setCommitAuthor(ctx context.Context, store datastore.DSReaderWriter, nd ipld.Node, author string) error {
+	err := store.Put(ctx, core.NewCommitAuthorKey(nd.Cid()).ToDS(), []byte(author))
+	if err != nil {
+		return err
+	}
+	for _, link := range nd.Links() {
+		if link.Name == core.HEAD {
+			continue
+		}
+		err = store.Put(ctx, core.NewCommitAuthorKey(link.Cid).ToDS(), []byte(author))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func decodeBlockBuffer(buf []byte, cid cid.Cid) (ipld.Node, error) {
 	blk, err := blocks.NewBlockWithCid(buf, cid)
 	if err != nil {
@@ -155,6 +248,9 @@ func (p *Peer) handleChildBlocks(
 	ctx, cancel := context.WithTimeout(p.ctx, DAGSyncTimeout)
 	defer cancel()
 
+	// The field names of the children to fetch, by CID.
+	fieldNames := make(map[cid.Cid]string, len(children))
+	wanted := make([]cid.Cid, 0, len(children))
 	for _, c := range children {
 		if !p.queuedChildren.Visit(c) { // reserve for processing
 			continue
@@ -174,42 +270,60 @@ func (p *Peer) handleChildBlocks(
 		if fieldName == "" && field != "" {
 			fieldName = field
 		}
+		fieldNames[c] = fieldName
+		wanted = append(wanted, c)
+	}
 
-		// get object
-		cNode, err := getter.Get(ctx, c)
-		if err != nil {
-			log.ErrorE(ctx, "Failed to get node", err, logging.NewKV("CID", c))
-			continue
+	// The children are requested in batches, each batch being sent as a single want-list and its
+	// blocks being fetched in parallel, instead of one block at a time.
+	for len(wanted) > 0 {
+		batch := wanted
+		if len(batch) > DAGFetchBatchSize {
+			batch = batch[:DAGFetchBatchSize]
 		}
+		wanted = wanted[len(batch):]
+
+		fetched := make(map[cid.Cid]struct{}, len(batch))
+		for result := range getter.GetMany(ctx, batch) {
+			if result.Err != nil {
+				log.ErrorE(ctx, "Failed to get node", result.Err, logging.NewKV("DocKey", dockey))
+				continue
+			}
+			cNode := result.Node
+			fetched[cNode.Cid()] = struct{}{}
 
-		log.Debug(
-			ctx,
-			"Submitting new job to DAG queue",
-			logging.NewKV("Collection", col.Name()),
-			logging.NewKV("DocKey", dockey),
-			logging.NewKV("Field", fieldName),
-			logging.NewKV("CID", cNode.Cid()))
-
-		session.Add(1)
-		job := &dagJob{
-			collection: col,
-			dockey:     dockey,
-			fieldName:  fieldName,
-			session:    session,
-			nodeGetter: getter,
-			node:       cNode,
-			txn:        txn,
+			log.Debug(
+				ctx,
+				"Submitting new job to DAG queue",
+				logging.NewKV("Collection", col.Name()),
+				logging.NewKV("DocKey", dockey),
+				logging.NewKV("Field", fieldNames[cNode.Cid()]),
+				logging.NewKV("CID", cNode.Cid()))
+
+			session.Add(1)
+			job := &dagJob{
+				collection: col,
+				dockey:     dockey,
+				fieldName:  fieldNames[cNode.Cid()],
+				session:    session,
+				nodeGetter: getter,
+				node:       cNode,
+				txn:        txn,
+			}
+
+			select {
+			case p.sendJobs <- job:
+			case <-p.ctx.Done():
+				session.Done()
+				return // jump out
+			}
 		}
 
-		select {
-		case p.sendJobs <- job:
-		case <-p.ctx.Done():
-			return // jump out
+		// Release the children we failed to get, so that a later sync may fetch them.
+		for _, c := range batch {
+			if _, ok := fetched[c]; !ok {
+				p.queuedChildren.Remove(c)
+			}
 		}
 	}
-
-	// Clear up any children we failed to get from queued children
-	// for _, child := range children {
-	// 	p.queuedChildren.Remove(child)
-	// }
 }
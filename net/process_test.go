@@ -0,0 +1,182 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package net
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/core"
+	corecrdt "github.com/sourcenetwork/defradb/core/crdt"
+	"github.com/sourcenetwork/defradb/db"
+)
+
+func newTestKeyring(t *testing.T) *corecrdt.Keyring {
+	keyring, err := corecrdt.NewKeyring(map[string][]byte{"users": bytes.Repeat([]byte{1}, corecrdt.DeltaKeySize)})
+	require.NoError(t, err)
+	return keyring
+}
+
+// processTestLogs processes the head block of the given document of the source database, and the
+// blocks it links to, on a peer of the given database.
+func processTestLogs(
+	t *testing.T,
+	ctx context.Context,
+	source client.DB,
+	target client.DB,
+	keyring *corecrdt.Keyring,
+	doc *client.Document,
+	head cid.Cid,
+) {
+	p := &Peer{db: target, keyring: keyring, queuedChildren: newCidSafeSet()}
+	txn, err := target.NewTxn(ctx, false)
+	require.NoError(t, err)
+	defer txn.Discard(ctx)
+	col, err := target.WithTxn(txn).GetCollectionByName(ctx, "users")
+	require.NoError(t, err)
+	dockey := core.DataStoreKeyFromDocKey(doc.Key())
+
+	block, err := source.Blockstore().Get(ctx, head)
+	require.NoError(t, err)
+	nd, err := decodeBlockBuffer(block.RawData(), head)
+	require.NoError(t, err)
+	children, err := p.processLog(ctx, txn, col, dockey, head, "", nd, nil, false)
+	require.NoError(t, err)
+	require.Len(t, children, 1)
+
+	block, err = source.Blockstore().Get(ctx, children[0])
+	require.NoError(t, err)
+	child, err := decodeBlockBuffer(block.RawData(), children[0])
+	require.NoError(t, err)
+	_, err = p.processLog(ctx, txn, col, dockey, children[0], nd.Links()[0].Name, child, nil, false)
+	require.NoError(t, err)
+
+	require.NoError(t, txn.Commit(ctx))
+}
+
+func TestProcessLogOfEncryptedCollection(t *testing.T) {
+	ctx := context.Background()
+	keyring := newTestKeyring(t)
+	schema := `type users { Name: String }`
+
+	source := newTestDB(t, ctx, db.WithKeyring(keyring))
+	require.NoError(t, source.AddSchema(ctx, schema))
+	col, err := source.GetCollectionByName(ctx, "users")
+	require.NoError(t, err)
+	doc, err := client.NewDocFromJSON([]byte(`{"Name": "John"}`))
+	require.NoError(t, err)
+	require.NoError(t, col.Create(ctx, doc))
+	head := doc.Head()
+
+	// The contents of the blocks are encrypted.
+	block, err := source.Blockstore().Get(ctx, head)
+	require.NoError(t, err)
+	assert.NotContains(t, string(block.RawData()), "John")
+
+	// A peer without the key stores the blocks, so that they can be synced from it, without
+	// merging them.
+	relay := newTestDB(t, ctx)
+	require.NoError(t, relay.AddSchema(ctx, schema))
+	processTestLogs(t, ctx, source, relay, nil, doc, head)
+
+	has, err := relay.Blockstore().Has(ctx, head)
+	require.NoError(t, err)
+	assert.True(t, has)
+	res := relay.ExecRequest(ctx, `query { users { Name } }`)
+	require.Empty(t, res.GQL.Errors)
+	assert.Equal(t, []map[string]any{}, res.GQL.Data)
+
+	// A peer with the key merges them.
+	reader := newTestDB(t, ctx, db.WithKeyring(keyring))
+	require.NoError(t, reader.AddSchema(ctx, schema))
+	processTestLogs(t, ctx, relay, reader, keyring, doc, head)
+
+	res = reader.ExecRequest(ctx, `query { users { Name } }`)
+	require.Empty(t, res.GQL.Errors)
+	assert.Equal(t, []map[string]any{{"Name": "John"}}, res.GQL.Data)
+}
+
+func TestProcessLogRecordsCommitTimeOfDatabaseClock(t *testing.T) {
+	ctx := context.Background()
+	schema := `type users { Name: String }`
+
+	source := newTestDB(t, ctx)
+	require.NoError(t, source.AddSchema(ctx, schema))
+	col, err := source.GetCollectionByName(ctx, "users")
+	require.NoError(t, err)
+	doc, err := client.NewDocFromJSON([]byte(`{"Name": "John"}`))
+	require.NoError(t, err)
+	require.NoError(t, col.Create(ctx, doc))
+	head := doc.Head()
+
+	now := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	target := newTestDB(t, ctx, db.WithClock(func() time.Time { return now }))
+	require.NoError(t, target.AddSchema(ctx, schema))
+	processTestLogs(t, ctx, source, target, nil, doc, head)
+
+	txn, err := target.NewTxn(ctx, true)
+	require.NoError(t, err)
+	defer txn.Discard(ctx)
+	has, err := txn.Systemstore().Has(ctx, core.NewCommitTimeKey(doc.Key().String(), now, head).ToDS())
+	require.NoError(t, err)
+	assert.True(t, has)
+}
+
+func TestValidateAppendOnlyLog(t *testing.T) {
+	ctx := context.Background()
+
+	source := newTestDB(t, ctx)
+	require.NoError(t, source.AddSchema(ctx, `type users { Name: String }`))
+	sourceCol, err := source.GetCollectionByName(ctx, "users")
+	require.NoError(t, err)
+	doc, err := client.NewDocFromJSON([]byte(`{"Name": "John"}`))
+	require.NoError(t, err)
+	require.NoError(t, sourceCol.Create(ctx, doc))
+	creation := doc.Head()
+	require.NoError(t, doc.Set("Name", "Fred"))
+	require.NoError(t, sourceCol.Update(ctx, doc))
+	update := doc.Head()
+
+	target := newTestDB(t, ctx)
+	require.NoError(t, target.AddSchema(ctx, `type users @appendOnly { Name: String }`))
+	col, err := target.GetCollectionByName(ctx, "users")
+	require.NoError(t, err)
+	dockey := core.DataStoreKeyFromDocKey(doc.Key())
+
+	validate := func(head cid.Cid) error {
+		txn, err := target.NewTxn(ctx, true)
+		require.NoError(t, err)
+		defer txn.Discard(ctx)
+		block, err := source.Blockstore().Get(ctx, head)
+		require.NoError(t, err)
+		nd, err := decodeBlockBuffer(block.RawData(), head)
+		require.NoError(t, err)
+		return validateAppendOnlyLog(ctx, txn, col, dockey, nd)
+	}
+
+	assert.NoError(t, validate(creation))
+	assert.ErrorIs(t, validate(update), db.ErrAppendOnlyCollection)
+
+	// Once the document is known, it can't be created again.
+	known, err := client.NewDocFromJSON([]byte(`{"Name": "John"}`))
+	require.NoError(t, err)
+	require.NoError(t, col.Create(ctx, known))
+	assert.ErrorIs(t, validate(creation), db.ErrAppendOnlyCollection)
+}
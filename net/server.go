@@ -14,6 +14,7 @@ package net
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"sync"
 
@@ -24,12 +25,14 @@ import (
 	rpc "github.com/textileio/go-libp2p-pubsub-rpc"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
 	grpcpeer "google.golang.org/grpc/peer"
 
 	"github.com/sourcenetwork/defradb/client"
 	"github.com/sourcenetwork/defradb/core"
 	"github.com/sourcenetwork/defradb/datastore/badger/v3"
 	"github.com/sourcenetwork/defradb/errors"
+	"github.com/sourcenetwork/defradb/events"
 	"github.com/sourcenetwork/defradb/logging"
 	pb "github.com/sourcenetwork/defradb/net/pb"
 )
@@ -56,6 +59,17 @@ type server struct {
 	// This is used to prevent multiple concurrent processing of the same document and
 	// limit unecessary transaction conflicts.
 	docQueue *docQueue
+
+	// syncLimiter limits the number of concurrent DAG syncs triggered by the push logs of each
+	// peer.
+	syncLimiter *peerLimiter
+
+	// ingestThrottle limits the rates at which the push logs of each peer and into each
+	// collection are ingested. It is nil if they are unlimited.
+	ingestThrottle *ingestThrottle
+
+	// schemaMu serializes the adoption of the schemas of the other peers.
+	schemaMu sync.Mutex
 }
 
 // pubsubTopic is a wrapper of rpc.Topic to be able to track if the topic has
@@ -76,6 +90,8 @@ func newServer(p *Peer, db client.DB, opts ...grpc.DialOption) (*server, error)
 		docQueue: &docQueue{
 			docs: make(map[string]chan struct{}),
 		},
+		syncLimiter:    newPeerLimiter(MaxConcurrentPeerSyncs),
+		ingestThrottle: newIngestThrottle(p.ingestLimits),
 	}
 
 	cred := insecure.NewCredentials()
@@ -238,8 +254,21 @@ func (s *server) PushLog(ctx context.Context, req *pb.PushLogRequest) (*pb.PushL
 	}
 
 	schemaID := string(req.Body.SchemaID)
+	if err := s.ingestThrottle.wait(ctx, pid, schemaID); err != nil {
+		return nil, err
+	}
+
+	if err := s.syncLimiter.Acquire(ctx, pid); err != nil {
+		return nil, err
+	}
+	defer s.syncLimiter.Release(pid)
+
 	docKey := core.DataStoreKeyFromDocKey(req.Body.DocKey.DocKey)
 
+	if err := s.ensureSchema(ctx, pid, schemaID); err != nil {
+		return nil, err
+	}
+
 	var txnErr error
 	for retry := 0; retry < s.peer.db.MaxTxnRetries(); retry++ {
 		// To prevent a potential deadlock on DAG sync if an error occures mid process, we handle
@@ -264,10 +293,17 @@ func (s *server) PushLog(ctx context.Context, req *pb.PushLogRequest) (*pb.PushL
 		}
 
 		// handleComposite
-		nd, err := decodeBlockBuffer(req.Body.Log.Block, cid)
+		block, err := logBlock(req.Body.Log)
+		if err != nil {
+			return nil, errors.Wrap("failed to read log block", err)
+		}
+		nd, err := decodeBlockBuffer(block, cid)
 		if err != nil {
 			return nil, errors.Wrap("failed to decode block to ipld.Node", err)
 		}
+		if err := validateAppendOnlyLog(ctx, txn, col, docKey, nd); err != nil {
+			return nil, err
+		}
 
 		cids, err := s.peer.processLog(ctx, txn, col, docKey, cid, "", nd, getter, false)
 		if err != nil {
@@ -280,6 +316,12 @@ func (s *server) PushLog(ctx context.Context, req *pb.PushLogRequest) (*pb.PushL
 			)
 		}
 
+		if req.Body.Creator != "" {
+			if err := setCommitAuthor(ctx, txn.Systemstore(), nd, req.Body.Creator); err != nil {
+				return nil, err
+			}
+		}
+
 		// handleChildren
 		if len(cids) > 0 { // we have child nodes to get
 			log.Debug(
@@ -305,6 +347,15 @@ func (s *server) PushLog(ctx context.Context, req *pb.PushLogRequest) (*pb.PushL
 			return &pb.PushLogReply{}, txnErr
 		}
 
+		if s.db.Events().Updates.HasValue() {
+			s.db.Events().Updates.Value().Publish(events.Update{
+				DocKey:   docKey.DocKey,
+				Cid:      cid,
+				SchemaID: schemaID,
+				IsRemote: true,
+			})
+		}
+
 		// Once processed, subscribe to the dockey topic on the pubsub network unless we already
 		// suscribe to the collection.
 		if !s.hasPubSubTopic(col.SchemaID()) {
@@ -327,6 +378,191 @@ func (s *server) GetHeadLog(
 	return nil, nil
 }
 
+// GetSchema receives a get schema request
+func (s *server) GetSchema(ctx context.Context, req *pb.GetSchemaRequest) (*pb.GetSchemaReply, error) {
+	if !s.peer.schemaSync {
+		return nil, errors.New("this peer does not share its schemas")
+	}
+
+	versions, err := s.db.GetSchemaVersions(ctx, req.SchemaID)
+	if err != nil {
+		return nil, errors.Wrap(fmt.Sprintf("failed to get versions of schema %s", req.SchemaID), err)
+	}
+	reply := &pb.GetSchemaReply{}
+	for _, version := range versions {
+		buf, err := json.Marshal(version)
+		if err != nil {
+			return nil, err
+		}
+		reply.Versions = append(reply.Versions, buf)
+	}
+
+	// The signed updates are only shared if all the versions have one, as they are replayed in
+	// place of the versions.
+	for _, version := range versions {
+		update, err := s.db.GetSignedSchemaUpdate(ctx, version.Schema.VersionID)
+		if err != nil {
+			reply.Updates = nil
+			break
+		}
+		buf, err := json.Marshal(update)
+		if err != nil {
+			return nil, err
+		}
+		reply.Updates = append(reply.Updates, buf)
+	}
+	return reply, nil
+}
+
+// ExecRequest receives a query forwarded by a peer that does not hold the queried collections.
+func (s *server) ExecRequest(ctx context.Context, req *pb.ExecRequestRequest) (*pb.ExecRequestReply, error) {
+	if !s.peer.serveRequests {
+		return nil, errors.New("this peer does not serve forwarded requests")
+	}
+	// Mutations are not forwarded, so that the peers can't write to the documents of this peer.
+	if err := checkForwardedRequest(req.Request); err != nil {
+		return nil, err
+	}
+
+	var variables map[string]any
+	if len(req.Variables) > 0 {
+		if err := json.Unmarshal(req.Variables, &variables); err != nil {
+			return nil, errors.Wrap("failed to decode request variables", err)
+		}
+	}
+	// The request hooks of the database are told which peer forwarded the request, and the logs
+	// of the request are tagged with the trace ID given by the peer.
+	requestContext := client.RequestContext{TraceID: traceIDFromMetadata(ctx)}
+	if pid, err := peerIDFromContext(ctx); err == nil {
+		requestContext.PeerID = pid.String()
+	}
+	ctx = client.WithRequestContext(ctx, requestContext)
+	if requestContext.TraceID != "" {
+		ctx = logging.WithTraceID(ctx, requestContext.TraceID)
+	}
+	res := s.db.ExecRequest(client.WithRequestVariables(ctx, variables), req.Request)
+
+	reply := &pb.ExecRequestReply{}
+	for _, err := range res.GQL.Errors {
+		reply.Errors = append(reply.Errors, err.Error())
+	}
+	if res.GQL.Data != nil {
+		buf, err := json.Marshal(res.GQL.Data)
+		if err != nil {
+			return nil, err
+		}
+		reply.Data = buf
+	}
+	return reply, nil
+}
+
+// ensureSchema adopts the schema of the given ID from the given peer if the collection of the
+// schema does not exist and schema sync is enabled.
+func (s *server) ensureSchema(ctx context.Context, pid libpeer.ID, schemaID string) error {
+	if !s.peer.schemaSync {
+		return nil
+	}
+
+	s.schemaMu.Lock()
+	defer s.schemaMu.Unlock()
+
+	if _, err := s.db.GetCollectionBySchemaID(ctx, schemaID); err == nil {
+		return nil
+	}
+
+	log.Info(ctx, "Requesting unknown schema from peer", logging.NewKV("SchemaID", schemaID), logging.NewKV("PID", pid))
+	peerClient, err := s.dial(pid)
+	if err != nil {
+		return errors.Wrap("failed to request schema", err)
+	}
+	cctx, cancel := context.WithTimeout(ctx, PullTimeout)
+	defer cancel()
+	reply, err := peerClient.GetSchema(cctx, &pb.GetSchemaRequest{SchemaID: schemaID})
+	if err != nil {
+		return errors.Wrap(fmt.Sprintf("failed GetSchema RPC request %s to %s", schemaID, pid), err)
+	}
+
+	versions := make([]client.CollectionDescription, len(reply.Versions))
+	for i, buf := range reply.Versions {
+		if err := json.Unmarshal(buf, &versions[i]); err != nil {
+			return errors.Wrap("failed to decode schema version", err)
+		}
+	}
+	if len(versions) == 0 || versions[0].Schema.SchemaID != schemaID {
+		return errors.New("peer replied with another schema", errors.NewKV("SchemaID", schemaID))
+	}
+
+	var col client.Collection
+	if len(reply.Updates) > 0 {
+		col, err = s.replaySchemaUpdates(ctx, versions, reply.Updates)
+	} else {
+		col, err = s.db.AddSchemaVersions(ctx, versions)
+	}
+	if err != nil {
+		return errors.Wrap(fmt.Sprintf("failed to adopt schema %s", schemaID), err)
+	}
+	log.Info(
+		ctx,
+		"Adopted schema of peer",
+		logging.NewKV("Collection", col.Name()),
+		logging.NewKV("SchemaVersionID", col.Schema().VersionID),
+		logging.NewKV("PID", pid),
+	)
+	return nil
+}
+
+// replaySchemaUpdates applies the given signed schema updates, which are verified by the db if it
+// requires signed schema updates, and checks that they produced the given versions.
+func (s *server) replaySchemaUpdates(
+	ctx context.Context,
+	versions []client.CollectionDescription,
+	updates [][]byte,
+) (client.Collection, error) {
+	if len(updates) != len(versions) {
+		return nil, errors.New("peer did not reply with a signed update per schema version")
+	}
+
+	txn, err := s.db.NewTxn(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	defer txn.Discard(ctx)
+	store := s.db.WithTxn(txn)
+
+	for _, buf := range updates {
+		var update client.SignedSchemaUpdate
+		if err := json.Unmarshal(buf, &update); err != nil {
+			return nil, errors.Wrap("failed to decode signed schema update", err)
+		}
+		uctx := client.WithSchemaSignature(ctx, update.Signature)
+		switch update.Kind {
+		case client.SchemaUpdateAdd:
+			err = store.AddSchema(uctx, update.Update)
+		case client.SchemaUpdatePatch:
+			err = store.PatchSchema(uctx, update.Update)
+		default:
+			err = errors.New("unknown schema update kind", errors.NewKV("Kind", update.Kind))
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	current := versions[len(versions)-1]
+	col, err := store.GetCollectionByName(ctx, current.Name)
+	if err != nil {
+		return nil, err
+	}
+	if col.SchemaID() != versions[0].Schema.SchemaID || col.Schema().VersionID != current.Schema.VersionID {
+		return nil, errors.New(
+			"signed schema updates do not produce the shared schema version",
+			errors.NewKV("SchemaVersionID", current.Schema.VersionID),
+		)
+	}
+
+	return col, txn.Commit(ctx)
+}
+
 // addPubSubTopic subscribes to a topic on the pubsub network
 func (s *server) addPubSubTopic(topic string, subscribe bool) error {
 	if s.peer.ps == nil {
@@ -499,6 +735,16 @@ func peerIDFromContext(ctx context.Context) (libpeer.ID, error) {
 	return pid, nil
 }
 
+// traceIDFromMetadata returns the trace ID of the request forwarded by a peer, or an empty string
+// if it has none.
+func traceIDFromMetadata(ctx context.Context) string {
+	values := metadata.ValueFromIncomingContext(ctx, traceIDMetadataKey)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
 // KEEPING AS REFERENCE
 //
 // logFromProto returns a thread log from a proto log.
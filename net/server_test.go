@@ -0,0 +1,193 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package net
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	badger "github.com/dgraph-io/badger/v3"
+	"github.com/libp2p/go-libp2p/core/crypto"
+	libpeer "github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	grpcpeer "google.golang.org/grpc/peer"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/client/request"
+	badgerds "github.com/sourcenetwork/defradb/datastore/badger/v3"
+	"github.com/sourcenetwork/defradb/db"
+	pb "github.com/sourcenetwork/defradb/net/pb"
+)
+
+func newTestDB(t *testing.T, ctx context.Context, options ...db.Option) client.DB {
+	opts := badgerds.Options{Options: badger.DefaultOptions("").WithInMemory(true)}
+	rootstore, err := badgerds.NewDatastore("", &opts)
+	require.NoError(t, err)
+	database, err := db.NewDB(ctx, rootstore, options...)
+	require.NoError(t, err)
+	t.Cleanup(func() { database.Close(ctx) })
+	return database
+}
+
+func signedSchemaReply(t *testing.T, ctx context.Context, admin crypto.PrivKey) *pb.GetSchemaReply {
+	source := newTestDB(t, ctx, db.WithSchemaAdmin(admin.GetPublic()))
+	sign := func(kind client.SchemaUpdateKind, update string) context.Context {
+		signature, err := admin.Sign(client.SchemaUpdatePayload(kind, update))
+		require.NoError(t, err)
+		return client.WithSchemaSignature(ctx, signature)
+	}
+
+	schema := `type users { Name: String }`
+	require.NoError(t, source.AddSchema(sign(client.SchemaUpdateAdd, schema), schema))
+	patch := `[{ "op": "add", "path": "/users/Schema/Fields/-", "value": {"Name": "Email", "Kind": "String"} }]`
+	require.NoError(t, source.PatchSchema(sign(client.SchemaUpdatePatch, patch), patch))
+	col, err := source.GetCollectionByName(ctx, "users")
+	require.NoError(t, err)
+
+	s := &server{peer: &Peer{schemaSync: true}, db: source}
+	reply, err := s.GetSchema(ctx, &pb.GetSchemaRequest{SchemaID: col.SchemaID()})
+	require.NoError(t, err)
+	require.Len(t, reply.Versions, 2)
+	require.Len(t, reply.Updates, 2)
+	return reply
+}
+
+func decodeSchemaVersions(t *testing.T, reply *pb.GetSchemaReply) []client.CollectionDescription {
+	versions := make([]client.CollectionDescription, len(reply.Versions))
+	for i, buf := range reply.Versions {
+		require.NoError(t, json.Unmarshal(buf, &versions[i]))
+	}
+	return versions
+}
+
+func TestReplaySchemaUpdates(t *testing.T) {
+	ctx := context.Background()
+	admin, _, err := crypto.GenerateKeyPair(crypto.Ed25519, 0)
+	require.NoError(t, err)
+	reply := signedSchemaReply(t, ctx, admin)
+	versions := decodeSchemaVersions(t, reply)
+
+	target := newTestDB(t, ctx, db.WithSchemaAdmin(admin.GetPublic()))
+	s := &server{db: target}
+	col, err := s.replaySchemaUpdates(ctx, versions, reply.Updates)
+	require.NoError(t, err)
+	require.Equal(t, versions[1].Schema.VersionID, col.Schema().VersionID)
+
+	// The signed updates are recorded, so that the target can share them in turn.
+	_, err = target.GetSignedSchemaUpdate(ctx, col.Schema().VersionID)
+	require.NoError(t, err)
+}
+
+func TestReplaySchemaUpdatesWithAnotherAdmin(t *testing.T) {
+	ctx := context.Background()
+	admin, _, err := crypto.GenerateKeyPair(crypto.Ed25519, 0)
+	require.NoError(t, err)
+	_, otherAdmin, err := crypto.GenerateKeyPair(crypto.Ed25519, 0)
+	require.NoError(t, err)
+	reply := signedSchemaReply(t, ctx, admin)
+
+	target := newTestDB(t, ctx, db.WithSchemaAdmin(otherAdmin))
+	s := &server{db: target}
+	_, err = s.replaySchemaUpdates(ctx, decodeSchemaVersions(t, reply), reply.Updates)
+	require.ErrorIs(t, err, db.ErrInvalidSchemaSignature)
+
+	_, err = target.GetCollectionByName(ctx, "users")
+	require.Error(t, err)
+}
+
+func TestReplaySchemaUpdatesWithTamperedVersion(t *testing.T) {
+	ctx := context.Background()
+	admin, _, err := crypto.GenerateKeyPair(crypto.Ed25519, 0)
+	require.NoError(t, err)
+	reply := signedSchemaReply(t, ctx, admin)
+	versions := decodeSchemaVersions(t, reply)
+
+	// A peer can't pass off a signed update for another version.
+	versions[1].Schema.VersionID = versions[0].Schema.VersionID
+
+	target := newTestDB(t, ctx, db.WithSchemaAdmin(admin.GetPublic()))
+	s := &server{db: target}
+	_, err = s.replaySchemaUpdates(ctx, versions, reply.Updates)
+	require.Error(t, err)
+
+	_, err = target.GetCollectionByName(ctx, "users")
+	require.Error(t, err)
+}
+
+func TestExecRequest(t *testing.T) {
+	ctx := context.Background()
+	upstream := newTestDB(t, ctx)
+	require.NoError(t, upstream.AddSchema(ctx, `type books { Title: String }`))
+	res := upstream.ExecRequest(ctx, `mutation { create_books(data: "{\"Title\": \"Dune\"}") { _key } }`)
+	require.Empty(t, res.GQL.Errors)
+	s := &server{peer: &Peer{serveRequests: true}, db: upstream}
+
+	reply, err := s.ExecRequest(ctx, &pb.ExecRequestRequest{
+		Request:   `query($title: String) { books(filter: {Title: {_eq: $title}}) { Title } }`,
+		Variables: []byte(`{"title": "Dune"}`),
+	})
+	require.NoError(t, err)
+	assert.Empty(t, reply.Errors)
+	assert.JSONEq(t, `[{"Title": "Dune"}]`, string(reply.Data))
+
+	_, err = s.ExecRequest(ctx, &pb.ExecRequestRequest{
+		Request: `mutation { create_books(data: "{\"Title\": \"Emma\"}") { _key } }`,
+	})
+	assert.Error(t, err)
+
+	s.peer.serveRequests = false
+	_, err = s.ExecRequest(ctx, &pb.ExecRequestRequest{Request: `query { books { Title } }`})
+	assert.Error(t, err)
+}
+
+func TestExecRequestWithForwardingPeerInRequestContext(t *testing.T) {
+	ctx := context.Background()
+	var requestContext client.RequestContext
+	upstream := newTestDB(t, ctx, db.WithRequestHook(func(ctx context.Context, _ *request.Request) error {
+		requestContext = client.RequestContextFromContext(ctx)
+		return nil
+	}))
+	require.NoError(t, upstream.AddSchema(ctx, `type books { Title: String }`))
+	s := &server{peer: &Peer{serveRequests: true}, db: upstream}
+
+	key, _, err := crypto.GenerateKeyPair(crypto.Ed25519, 0)
+	require.NoError(t, err)
+	pid, err := libpeer.IDFromPrivateKey(key)
+	require.NoError(t, err)
+	ctx = grpcpeer.NewContext(ctx, &grpcpeer.Peer{Addr: addr{pid}})
+
+	reply, err := s.ExecRequest(ctx, &pb.ExecRequestRequest{Request: `query { books { Title } }`})
+	require.NoError(t, err)
+	assert.Empty(t, reply.Errors)
+	assert.Equal(t, client.RequestContext{PeerID: pid.String()}, requestContext)
+}
+
+func TestExecRequestWithForwardedTraceIDInRequestContext(t *testing.T) {
+	ctx := context.Background()
+	var requestContext client.RequestContext
+	upstream := newTestDB(t, ctx, db.WithRequestHook(func(ctx context.Context, _ *request.Request) error {
+		requestContext = client.RequestContextFromContext(ctx)
+		return nil
+	}))
+	require.NoError(t, upstream.AddSchema(ctx, `type books { Title: String }`))
+	s := &server{peer: &Peer{serveRequests: true}, db: upstream}
+
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(traceIDMetadataKey, "trace-1"))
+	reply, err := s.ExecRequest(ctx, &pb.ExecRequestRequest{Request: `query { books { Title } }`})
+	require.NoError(t, err)
+	assert.Empty(t, reply.Errors)
+	assert.Equal(t, client.RequestContext{TraceID: "trace-1"}, requestContext)
+}
@@ -0,0 +1,215 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package net
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	libpeer "github.com/libp2p/go-libp2p/core/peer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// retryAfterMetadataKey is the key of the gRPC trailer holding the delay, in milliseconds, after
+// which a push log rejected by the ingest throttle can be retried.
+const retryAfterMetadataKey = "x-retry-after-ms"
+
+// maxPushRetries is the maximum number of times a push log rejected by the ingest throttle of the
+// receiving peer is retried.
+const maxPushRetries = 3
+
+// IngestLimits are the rate limits of the ingestion of the deltas pushed by the other peers, so
+// that a bursty peer can't starve the local requests.
+//
+// The push logs exceeding the rates wait for their turn, holding back their peer. Once too many
+// wait, the following ones are rejected with a retry delay, which the pushing peers wait for
+// before retrying.
+type IngestLimits struct {
+	// PeerRate is the number of push logs ingested per second from each peer. It is unlimited if
+	// zero.
+	PeerRate float64
+	// CollectionRate is the number of push logs ingested per second into each collection, from
+	// all the peers. It is unlimited if zero.
+	CollectionRate float64
+	// Burst is the number of push logs ingested at once above the rates. It defaults to one.
+	Burst int
+	// MaxQueue is the maximum number of push logs of each peer waiting for their turn, past which
+	// they are rejected. It is unlimited if zero.
+	MaxQueue int
+}
+
+// tokenBucket is a token bucket refilled at `rate` tokens per second, holding up to `burst` tokens.
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int, now time.Time) *tokenBucket {
+	return &tokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst), last: now}
+}
+
+// delay returns the time to wait for a token to be available.
+func (b *tokenBucket) delay(now time.Time) time.Duration {
+	b.refill(now)
+	if b.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
+
+// take consumes a token, which may be borrowed from the refill to come.
+func (b *tokenBucket) take(now time.Time) {
+	b.refill(now)
+	b.tokens--
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+}
+
+// ingestThrottle limits the rates at which the push logs of each peer, and into each collection,
+// are ingested.
+type ingestThrottle struct {
+	limits IngestLimits
+
+	mu          sync.Mutex
+	peers       map[libpeer.ID]*tokenBucket
+	collections map[string]*tokenBucket
+	queued      map[libpeer.ID]int
+}
+
+// newIngestThrottle returns a throttle enforcing the given limits, or nil if they are unlimited.
+func newIngestThrottle(limits IngestLimits) *ingestThrottle {
+	if limits.PeerRate <= 0 && limits.CollectionRate <= 0 {
+		return nil
+	}
+	if limits.Burst <= 0 {
+		limits.Burst = 1
+	}
+	return &ingestThrottle{
+		limits:      limits,
+		peers:       make(map[libpeer.ID]*tokenBucket),
+		collections: make(map[string]*tokenBucket),
+		queued:      make(map[libpeer.ID]int),
+	}
+}
+
+// wait waits for the turn of a push log of the given peer into the collection with the given
+// schema ID, or for the given context to be done.
+//
+// If too many push logs of the peer are already waiting, it returns a ResourceExhausted error
+// instead, setting the delay after which the push log can be retried in the trailer of the
+// response.
+func (t *ingestThrottle) wait(ctx context.Context, pid libpeer.ID, schemaID string) error {
+	if t == nil {
+		return nil
+	}
+
+	t.mu.Lock()
+	now := time.Now()
+	var buckets []*tokenBucket
+	if t.limits.PeerRate > 0 {
+		bucket, ok := t.peers[pid]
+		if !ok {
+			bucket = newTokenBucket(t.limits.PeerRate, t.limits.Burst, now)
+			t.peers[pid] = bucket
+		}
+		buckets = append(buckets, bucket)
+	}
+	if t.limits.CollectionRate > 0 {
+		bucket, ok := t.collections[schemaID]
+		if !ok {
+			bucket = newTokenBucket(t.limits.CollectionRate, t.limits.Burst, now)
+			t.collections[schemaID] = bucket
+		}
+		buckets = append(buckets, bucket)
+	}
+
+	var delay time.Duration
+	for _, bucket := range buckets {
+		if d := bucket.delay(now); d > delay {
+			delay = d
+		}
+	}
+	if delay == 0 {
+		for _, bucket := range buckets {
+			bucket.take(now)
+		}
+		t.mu.Unlock()
+		return nil
+	}
+	if t.limits.MaxQueue > 0 && t.queued[pid] >= t.limits.MaxQueue {
+		t.mu.Unlock()
+		return rejectPush(ctx, delay)
+	}
+	// The tokens are taken ahead of time, so that the push logs waiting are served in order.
+	for _, bucket := range buckets {
+		bucket.take(now)
+	}
+	t.queued[pid]++
+	t.mu.Unlock()
+
+	defer func() {
+		t.mu.Lock()
+		t.queued[pid]--
+		if t.queued[pid] == 0 {
+			delete(t.queued, pid)
+		}
+		t.mu.Unlock()
+	}()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// rejectPush returns the error rejecting a push log, signaling to the pushing peer the delay after
+// which it can be retried.
+func rejectPush(ctx context.Context, retryAfter time.Duration) error {
+	// Setting the trailer fails for the push logs received through pubsub, which are not retried.
+	_ = grpc.SetTrailer(ctx, metadata.Pairs(retryAfterMetadataKey, strconv.FormatInt(retryAfter.Milliseconds(), 10)))
+	return status.Error(codes.ResourceExhausted, "too many push logs, retry later")
+}
+
+// retryAfter returns the delay after which the push log rejected with the given error and trailer
+// can be retried, and false if it was not rejected by the ingest throttle of the receiving peer.
+func retryAfter(err error, trailer metadata.MD) (time.Duration, bool) {
+	if status.Code(err) != codes.ResourceExhausted {
+		return 0, false
+	}
+	values := trailer.Get(retryAfterMetadataKey)
+	if len(values) == 0 {
+		return 0, false
+	}
+	ms, err := strconv.ParseInt(values[0], 10, 64)
+	if err != nil || ms < 0 {
+		return 0, false
+	}
+	return time.Duration(ms) * time.Millisecond, true
+}
@@ -0,0 +1,106 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package net
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	libpeer "github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestIngestThrottleWithoutLimits(t *testing.T) {
+	throttle := newIngestThrottle(IngestLimits{Burst: 1, MaxQueue: 1})
+	assert.Nil(t, throttle)
+	assert.NoError(t, throttle.wait(context.Background(), libpeer.ID("a"), "schema"))
+}
+
+func TestIngestThrottleLimitsPeerRate(t *testing.T) {
+	ctx := context.Background()
+	throttle := newIngestThrottle(IngestLimits{PeerRate: 20, Burst: 2})
+	peerA := libpeer.ID("a")
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		require.NoError(t, throttle.wait(ctx, peerA, "schema"))
+	}
+	// The burst is ingested at once, and the following push logs are spaced at the peer rate.
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+
+	// The rate of a peer is independent of the rates of the others.
+	start = time.Now()
+	require.NoError(t, throttle.wait(ctx, libpeer.ID("b"), "schema"))
+	assert.Less(t, time.Since(start), 40*time.Millisecond)
+}
+
+func TestIngestThrottleLimitsCollectionRate(t *testing.T) {
+	ctx := context.Background()
+	throttle := newIngestThrottle(IngestLimits{CollectionRate: 1, Burst: 1})
+
+	require.NoError(t, throttle.wait(ctx, libpeer.ID("a"), "schema"))
+
+	// The rate of a collection is shared by all the peers.
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	err := throttle.wait(timeoutCtx, libpeer.ID("b"), "schema")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	require.NoError(t, throttle.wait(ctx, libpeer.ID("b"), "other"))
+}
+
+func TestIngestThrottleRejectsPastMaxQueue(t *testing.T) {
+	ctx := context.Background()
+	throttle := newIngestThrottle(IngestLimits{PeerRate: 1, Burst: 1, MaxQueue: 1})
+	peerA := libpeer.ID("a")
+
+	require.NoError(t, throttle.wait(ctx, peerA, "schema"))
+
+	queued := make(chan error)
+	waitCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		queued <- throttle.wait(waitCtx, peerA, "schema")
+	}()
+	require.Eventually(t, func() bool {
+		throttle.mu.Lock()
+		defer throttle.mu.Unlock()
+		return throttle.queued[peerA] == 1
+	}, time.Second, time.Millisecond)
+
+	err := throttle.wait(ctx, peerA, "schema")
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	cancel()
+	assert.ErrorIs(t, <-queued, context.Canceled)
+	assert.Empty(t, throttle.queued)
+}
+
+func TestRetryAfter(t *testing.T) {
+	rejected := status.Error(codes.ResourceExhausted, "too many push logs, retry later")
+
+	delay, ok := retryAfter(rejected, metadata.Pairs(retryAfterMetadataKey, "1500"))
+	assert.True(t, ok)
+	assert.Equal(t, 1500*time.Millisecond, delay)
+
+	_, ok = retryAfter(rejected, metadata.MD{})
+	assert.False(t, ok)
+
+	_, ok = retryAfter(status.Error(codes.Internal, "failed"), metadata.Pairs(retryAfterMetadataKey, "1500"))
+	assert.False(t, ok)
+}
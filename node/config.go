@@ -19,6 +19,9 @@ import (
 	"github.com/libp2p/go-libp2p/p2p/net/connmgr"
 	ma "github.com/multiformats/go-multiaddr"
 	"google.golang.org/grpc"
+
+	corecrdt "github.com/sourcenetwork/defradb/core/crdt"
+	"github.com/sourcenetwork/defradb/net"
 )
 
 // Options is the node options.
@@ -28,6 +31,14 @@ type Options struct {
 	DataPath          string
 	EnablePubSub      bool
 	EnableRelay       bool
+	EnableSchemaSync  bool
+	Keyring           *corecrdt.Keyring
+	ServeRequests     bool
+	RejoinPeers       bool
+	EnableMDNS        bool
+	EnableRendezvous  bool
+	RequestForwarder  *net.RequestForwarder
+	IngestLimits      net.IngestLimits
 	GRPCServerOptions []grpc.ServerOption
 	GRPCDialOptions   []grpc.DialOption
 	ConnManager       cconnmgr.ConnManager
@@ -82,6 +93,75 @@ func WithEnableRelay(enable bool) NodeOpt {
 	}
 }
 
+// WithSchemaSync enables the sharing of schemas between peers.
+func WithSchemaSync(enable bool) NodeOpt {
+	return func(opt *Options) error {
+		opt.EnableSchemaSync = enable
+		return nil
+	}
+}
+
+// WithKeyring sets the keys of the collections whose deltas are encrypted, which must be the
+// keyring of the database of the node.
+func WithKeyring(keyring *corecrdt.Keyring) NodeOpt {
+	return func(opt *Options) error {
+		opt.Keyring = keyring
+		return nil
+	}
+}
+
+// WithServeRequests enables the execution of the queries forwarded by the peers that do not
+// hold the queried collections.
+func WithServeRequests(enable bool) NodeOpt {
+	return func(opt *Options) error {
+		opt.ServeRequests = enable
+		return nil
+	}
+}
+
+// WithRejoinPeers enables the remembering of the peers the node dials, so that it can reconnect
+// to them when it restarts.
+func WithRejoinPeers(enable bool) NodeOpt {
+	return func(opt *Options) error {
+		opt.RejoinPeers = enable
+		return nil
+	}
+}
+
+// WithMDNS enables the discovery of the peers on the local network through mDNS.
+func WithMDNS(enable bool) NodeOpt {
+	return func(opt *Options) error {
+		opt.EnableMDNS = enable
+		return nil
+	}
+}
+
+// WithRendezvous enables the discovery of the peers holding the same P2P collections through
+// the DHT.
+func WithRendezvous(enable bool) NodeOpt {
+	return func(opt *Options) error {
+		opt.EnableRendezvous = enable
+		return nil
+	}
+}
+
+// WithRequestForwarder sets the forwarder of the queries for the collections the node does not
+// hold, which must be the forwarder of the database of the node.
+func WithRequestForwarder(forwarder *net.RequestForwarder) NodeOpt {
+	return func(opt *Options) error {
+		opt.RequestForwarder = forwarder
+		return nil
+	}
+}
+
+// WithIngestLimits sets the rate limits of the ingestion of the deltas pushed by the peers.
+func WithIngestLimits(limits net.IngestLimits) NodeOpt {
+	return func(opt *Options) error {
+		opt.IngestLimits = limits
+		return nil
+	}
+}
+
 // ListenP2PAddrStrings sets the address to listen on given as strings.
 func ListenP2PAddrStrings(addrs ...string) NodeOpt {
 	return func(opt *Options) error {
@@ -0,0 +1,177 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package node
+
+import (
+	"context"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/p2p/discovery/mdns"
+	drouting "github.com/libp2p/go-libp2p/p2p/discovery/routing"
+
+	"github.com/sourcenetwork/defradb/errors"
+	"github.com/sourcenetwork/defradb/logging"
+)
+
+// mdnsServiceName is the name of the mDNS service the nodes advertise themselves under, so that
+// they only discover each other and not the other libp2p hosts of the local network.
+const mdnsServiceName = "_defradb._udp"
+
+// rendezvousInterval is the interval at which the node advertises the P2P collections it holds
+// on the DHT and looks for the peers holding the same collections.
+const rendezvousInterval = time.Minute
+
+// discoveredPeerTimeout is the timeout of the connections to the discovered peers.
+const discoveredPeerTimeout = 10 * time.Second
+
+// RendezvousNamespace returns the namespace the peers holding the given P2P collection advertise
+// themselves under on the DHT.
+func RendezvousNamespace(collectionID string) string {
+	return "defradb/collection/" + collectionID
+}
+
+// mdnsNotifee connects the node to the peers found on the local network.
+type mdnsNotifee struct {
+	n *Node
+}
+
+func (m mdnsNotifee) HandlePeerFound(pinfo peer.AddrInfo) {
+	go m.n.connectToDiscoveredPeer(m.n.ctx, pinfo, "mDNS")
+}
+
+// SetMDNS starts or stops the discovery of the peers on the local network through mDNS.
+//
+// The node connects to the peers it discovers, whether they hold the same collections or not.
+func (n *Node) SetMDNS(enable bool) error {
+	n.discoveryMu.Lock()
+	defer n.discoveryMu.Unlock()
+
+	if enable == (n.mdns != nil) {
+		return nil
+	}
+	if !enable {
+		err := n.mdns.Close()
+		n.mdns = nil
+		if err != nil {
+			return errors.Wrap("failed to stop mDNS discovery", err)
+		}
+		log.Info(n.ctx, "Stopped mDNS discovery")
+		return nil
+	}
+
+	service := mdns.NewMdnsService(n.host, mdnsServiceName, mdnsNotifee{n: n})
+	if err := service.Start(); err != nil {
+		return errors.Wrap("failed to start mDNS discovery", err)
+	}
+	n.mdns = service
+	log.Info(n.ctx, "Started mDNS discovery")
+	return nil
+}
+
+// SetRendezvous starts or stops the discovery of the peers holding the same P2P collections
+// through the DHT.
+//
+// While it is enabled, the node periodically advertises each of its P2P collections under its
+// [RendezvousNamespace] and connects to the other peers advertising them.
+func (n *Node) SetRendezvous(enable bool) {
+	n.discoveryMu.Lock()
+	defer n.discoveryMu.Unlock()
+
+	if enable == (n.stopRendezvous != nil) {
+		return
+	}
+	if !enable {
+		n.stopRendezvous()
+		n.stopRendezvous = nil
+		log.Info(n.ctx, "Stopped DHT rendezvous")
+		return
+	}
+
+	ctx, cancel := context.WithCancel(n.ctx)
+	n.stopRendezvous = cancel
+	go n.rendezvousLoop(ctx)
+	log.Info(n.ctx, "Started DHT rendezvous")
+}
+
+// rendezvousLoop runs the rendezvous of the P2P collections of the node until the given context
+// is done.
+func (n *Node) rendezvousLoop(ctx context.Context) {
+	disc := drouting.NewRoutingDiscovery(n.dht)
+	ticker := time.NewTicker(rendezvousInterval)
+	defer ticker.Stop()
+
+	for {
+		n.rendezvous(ctx, disc)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// rendezvous advertises the P2P collections of the node and connects to the other peers
+// advertising them.
+func (n *Node) rendezvous(ctx context.Context, disc *drouting.RoutingDiscovery) {
+	collections, err := n.Peer.GetAllP2PCollections()
+	if err != nil {
+		log.ErrorE(ctx, "Failed to get the P2P collections to advertise", err)
+		return
+	}
+	for _, col := range collections {
+		ns := RendezvousNamespace(col.ID)
+		// Advertising fails until the node knows some DHT peers, which is not an error.
+		if _, err := disc.Advertise(ctx, ns); err != nil {
+			log.Debug(
+				ctx,
+				"Failed to advertise collection",
+				logging.NewKV("Namespace", ns),
+				logging.NewKV("Error", err),
+			)
+		}
+		peers, err := disc.FindPeers(ctx, ns)
+		if err != nil {
+			log.Debug(
+				ctx,
+				"Failed to find peers",
+				logging.NewKV("Namespace", ns),
+				logging.NewKV("Error", err),
+			)
+			continue
+		}
+		for pinfo := range peers {
+			go n.connectToDiscoveredPeer(ctx, pinfo, "DHT rendezvous")
+		}
+	}
+}
+
+// connectToDiscoveredPeer connects to the given peer found through the given discovery mechanism,
+// unless it is the node itself or it is already connected.
+func (n *Node) connectToDiscoveredPeer(ctx context.Context, pinfo peer.AddrInfo, source string) {
+	if pinfo.ID == n.host.ID() || n.host.Network().Connectedness(pinfo.ID) == network.Connected {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, discoveredPeerTimeout)
+	defer cancel()
+	if err := n.host.Connect(ctx, pinfo); err != nil {
+		log.Debug(
+			ctx,
+			"Cannot connect to discovered peer",
+			logging.NewKV("PeerID", pinfo.ID),
+			logging.NewKV("Source", source),
+			logging.NewKV("Error", err),
+		)
+		return
+	}
+	log.Info(ctx, "Connected to discovered peer", logging.NewKV("PeerID", pinfo.ID), logging.NewKV("Source", source))
+}
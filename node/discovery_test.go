@@ -0,0 +1,105 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package node
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRendezvousNamespace(t *testing.T) {
+	assert.Equal(
+		t,
+		"defradb/collection/bafkreihzcu7ltnvtwgtihdm4y5vzhzvynhyfskx7cl4amjdvkfnt3ayxwe",
+		RendezvousNamespace("bafkreihzcu7ltnvtwgtihdm4y5vzhzvynhyfskx7cl4amjdvkfnt3ayxwe"),
+	)
+}
+
+func TestNodeWithMDNS(t *testing.T) {
+	n, err := NewNode(
+		context.Background(),
+		FixtureNewMemoryDBWithBroadcaster(t),
+		ListenP2PAddrStrings("/ip4/127.0.0.1/tcp/0"),
+		// DataPath() is a required option with the current implementation of key management
+		DataPath(t.TempDir()),
+		WithMDNS(true),
+	)
+	require.NoError(t, err)
+	assert.NotNil(t, n.mdns)
+
+	require.NoError(t, n.SetMDNS(false))
+	assert.Nil(t, n.mdns)
+	require.NoError(t, n.SetMDNS(true))
+	assert.NotNil(t, n.mdns)
+
+	require.NoError(t, n.Close())
+	assert.Nil(t, n.mdns)
+}
+
+func TestNodeWithRendezvous(t *testing.T) {
+	n, err := NewNode(
+		context.Background(),
+		FixtureNewMemoryDBWithBroadcaster(t),
+		ListenP2PAddrStrings("/ip4/127.0.0.1/tcp/0"),
+		// DataPath() is a required option with the current implementation of key management
+		DataPath(t.TempDir()),
+		WithRendezvous(true),
+	)
+	require.NoError(t, err)
+	assert.NotNil(t, n.stopRendezvous)
+
+	n.SetRendezvous(false)
+	assert.Nil(t, n.stopRendezvous)
+	n.SetRendezvous(true)
+	assert.NotNil(t, n.stopRendezvous)
+
+	require.NoError(t, n.Close())
+	assert.Nil(t, n.stopRendezvous)
+}
+
+func TestNodeWithoutDiscovery(t *testing.T) {
+	n, err := NewNode(
+		context.Background(),
+		FixtureNewMemoryDBWithBroadcaster(t),
+		ListenP2PAddrStrings("/ip4/127.0.0.1/tcp/0"),
+		// DataPath() is a required option with the current implementation of key management
+		DataPath(t.TempDir()),
+	)
+	require.NoError(t, err)
+	defer n.Close() //nolint:errcheck
+
+	assert.Nil(t, n.mdns)
+	assert.Nil(t, n.stopRendezvous)
+}
+
+func TestNodesWithMDNSConnectToEachOther(t *testing.T) {
+	newNode := func() *Node {
+		n, err := NewNode(
+			context.Background(),
+			FixtureNewMemoryDBWithBroadcaster(t),
+			ListenP2PAddrStrings("/ip4/127.0.0.1/tcp/0"),
+			// DataPath() is a required option with the current implementation of key management
+			DataPath(t.TempDir()),
+			WithMDNS(true),
+		)
+		require.NoError(t, err)
+		return n
+	}
+	n1 := newNode()
+	defer n1.Close() //nolint:errcheck
+	n2 := newNode()
+	defer n2.Close() //nolint:errcheck
+
+	require.NoError(t, n2.WaitForPeerConnectionEvent(n1.PeerID()))
+}
@@ -19,6 +19,7 @@ package node
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"sync"
@@ -38,7 +39,9 @@ import (
 	"github.com/libp2p/go-libp2p/core/host"
 	"github.com/libp2p/go-libp2p/core/network"
 	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/peerstore"
 	"github.com/libp2p/go-libp2p/core/routing"
+	"github.com/libp2p/go-libp2p/p2p/discovery/mdns"
 	"github.com/libp2p/go-libp2p/p2p/host/peerstore/pstoreds"
 	"github.com/multiformats/go-multiaddr"
 	"github.com/textileio/go-libp2p-pubsub-rpc/finalizer"
@@ -55,6 +58,10 @@ var (
 
 const evtWaitTimeout = 10 * time.Second
 
+// knownPeerKey is the key of the metadata of the peerstore marking the peers the node dialed,
+// which it reconnects to when it restarts.
+const knownPeerKey = "defra:known"
+
 // Node is a networked peer instance of DefraDB.
 type Node struct {
 	// embed the DB interface into the node
@@ -75,6 +82,21 @@ type Node struct {
 	// receives an event when a pushLog request has been processed.
 	pushLogEvent chan net.EvtReceivedPushLog
 
+	// rejoinPeers is true if the node remembers the peers it dials.
+	rejoinPeers bool
+
+	// discoveryMu guards the discovery services, which can be started and stopped at runtime.
+	discoveryMu sync.Mutex
+	// mdns is the mDNS discovery service, or nil if it is stopped.
+	mdns mdns.Service
+	// stopRendezvous stops the DHT rendezvous, or is nil if it is stopped.
+	stopRendezvous context.CancelFunc
+
+	// bootstrapped is true once the node has bootstrapped with its peers.
+	bootstrapped atomic.Bool
+
+	closeOnce sync.Once
+
 	ctx context.Context
 }
 
@@ -164,6 +186,11 @@ func NewNode(
 		options.TCPAddr,
 		options.GRPCServerOptions,
 		options.GRPCDialOptions,
+		options.EnableSchemaSync,
+		options.Keyring,
+		options.ServeRequests,
+		options.RequestForwarder,
+		options.IngestLimits,
 	)
 	if err != nil {
 		return nil, fin.Cleanup(err)
@@ -184,13 +211,23 @@ func NewNode(
 		dht:          ddht,
 		pubsub:       ps,
 		DB:           db,
+		rejoinPeers:  options.RejoinPeers,
 		ctx:          ctx,
 	}
 
+	if n.rejoinPeers {
+		n.rememberDialedPeers()
+	}
 	n.subscribeToPeerConnectionEvents()
 	n.subscribeToPubSubEvents()
 	n.subscribeToPushLogEvents()
 
+	// The node is still usable without mDNS, e.g. if multicast is not available.
+	if err := n.SetMDNS(options.EnableMDNS); err != nil {
+		log.ErrorE(ctx, "Failed to enable peer discovery", err)
+	}
+	n.SetRendezvous(options.EnableRendezvous)
+
 	return n, nil
 }
 
@@ -224,6 +261,56 @@ func (n *Node) Boostrap(addrs []peer.AddrInfo) {
 		log.ErrorE(n.ctx, "Problem bootstraping using DHT", err)
 		return
 	}
+	n.bootstrapped.Store(true)
+}
+
+// Bootstrapped returns true once the node has bootstrapped with its peers, even if it could not
+// connect to any of them.
+func (n *Node) Bootstrapped() bool {
+	return n.bootstrapped.Load()
+}
+
+// rememberDialedPeers records the addresses of the peers the node dials in the peerstore, which is
+// persisted in the rootstore, without expiration so that they are still known once the node
+// restarts.
+//
+// The addresses of the peers dialing the node are not recorded, as they are usually not those
+// the peers listen on.
+func (n *Node) rememberDialedPeers() {
+	n.host.Network().Notify(&network.NotifyBundle{
+		ConnectedF: func(_ network.Network, conn network.Conn) {
+			if conn.Stat().Direction != network.DirOutbound {
+				return
+			}
+			ps := n.host.Peerstore()
+			ps.AddAddr(conn.RemotePeer(), conn.RemoteMultiaddr(), peerstore.PermanentAddrTTL)
+			if err := ps.Put(conn.RemotePeer(), knownPeerKey, true); err != nil {
+				log.ErrorE(n.ctx, "Failed to remember peer", err, logging.NewKV("PeerID", conn.RemotePeer()))
+			}
+		},
+	})
+}
+
+// KnownPeers returns the peers the node dialed, including before it restarted, so that it can
+// reconnect to them.
+//
+// Returns nil if the node does not remember the peers it dials.
+func (n *Node) KnownPeers() []peer.AddrInfo {
+	if !n.rejoinPeers {
+		return nil
+	}
+	ps := n.host.Peerstore()
+	var peers []peer.AddrInfo
+	for _, id := range ps.PeersWithAddrs() {
+		if id == n.host.ID() {
+			continue
+		}
+		if _, err := ps.Get(id, knownPeerKey); err != nil {
+			continue
+		}
+		peers = append(peers, ps.PeerInfo(id))
+	}
+	return peers
 }
 
 // ListenAddrs returns the Multiaddr list of the hosts' listening addresses.
@@ -428,7 +515,54 @@ func newDHT(ctx context.Context, h host.Host, dsb ds.Batching) (*dualdht.DHT, er
 	return dualdht.New(ctx, h, dhtOpts...)
 }
 
-// Close closes the node and all its services.
-func (n Node) Close() error {
-	return n.Peer.Close()
+// Close gracefully shuts down the node and all its services.
+//
+// Services are closed in dependency order: the peer discovery is stopped first, then the
+// peer is closed so that no new P2P work is accepted and in-flight broadcasts are stopped,
+// then the DHT and the libp2p host, and finally the database, which flushes any open update
+// subscriptions before closing the underlying datastore.
+//
+// Calling Close more than once has no effect.
+func (n *Node) Close() error {
+	var err error
+	n.closeOnce.Do(func() {
+		err = n.close()
+	})
+	return err
+}
+
+func (n *Node) close() error {
+	var errs []error
+	if n.host != nil {
+		n.SetRendezvous(false)
+		if err := n.SetMDNS(false); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if n.Peer != nil {
+		if err := n.Peer.Close(); err != nil {
+			errs = append(errs, errors.Wrap("failed to close peer", err))
+		}
+	}
+	if closer, ok := n.dht.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			errs = append(errs, errors.Wrap("failed to close DHT", err))
+		}
+	}
+	if n.host != nil {
+		if err := n.host.Close(); err != nil {
+			errs = append(errs, errors.Wrap("failed to close libp2p host", err))
+		}
+	}
+	if n.DB != nil {
+		n.DB.Close(n.ctx)
+	}
+
+	for _, err := range errs {
+		log.ErrorE(n.ctx, "Error closing node", err)
+	}
+	if len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
 }
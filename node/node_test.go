@@ -17,10 +17,12 @@ import (
 	badger "github.com/dgraph-io/badger/v3"
 	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/sourcenetwork/defradb/client"
 	badgerds "github.com/sourcenetwork/defradb/datastore/badger/v3"
 	"github.com/sourcenetwork/defradb/db"
+	"github.com/sourcenetwork/defradb/net"
 	netutils "github.com/sourcenetwork/defradb/net/utils"
 )
 
@@ -102,6 +104,20 @@ func TestNodeClose(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestNodeCloseTwice(t *testing.T) {
+	db := FixtureNewMemoryDBWithBroadcaster(t)
+	n, err := NewNode(
+		context.Background(),
+		db,
+		DataPath(t.TempDir()),
+	)
+	assert.NoError(t, err)
+	err = n.Close()
+	assert.NoError(t, err)
+	err = n.Close()
+	assert.NoError(t, err)
+}
+
 func TestNewNodeBootstrapWithNoPeer(t *testing.T) {
 	db := FixtureNewMemoryDBWithBroadcaster(t)
 	ctx := context.Background()
@@ -113,7 +129,9 @@ func TestNewNodeBootstrapWithNoPeer(t *testing.T) {
 		DataPath(t.TempDir()),
 	)
 	assert.NoError(t, err)
+	assert.False(t, n1.Bootstrapped())
 	n1.Boostrap([]peer.AddrInfo{})
+	assert.True(t, n1.Bootstrapped())
 }
 
 func TestNewNodeBootstrapWithOnePeer(t *testing.T) {
@@ -173,6 +191,122 @@ func TestNewNodeBootstrapWithOneValidPeerAndManyInvalidPeers(t *testing.T) {
 	n2.Boostrap(addrs)
 }
 
+func TestNodeRejoinsKnownPeersAfterRestart(t *testing.T) {
+	ctx := context.Background()
+	n1, err := NewNode(
+		ctx,
+		FixtureNewMemoryDBWithBroadcaster(t),
+		ListenP2PAddrStrings("/ip4/127.0.0.1/tcp/0"),
+		// DataPath() is a required option with the current implementation of key management
+		DataPath(t.TempDir()),
+	)
+	require.NoError(t, err)
+	defer n1.Close() //nolint:errcheck
+
+	// The peerstore must outlive the node, so the database is not in memory.
+	dbPath := t.TempDir()
+	newNode := func() *Node {
+		rootstore, err := badgerds.NewDatastore(dbPath, &badgerds.Options{Options: badger.DefaultOptions(dbPath)})
+		require.NoError(t, err)
+		database, err := db.NewDB(ctx, rootstore, db.WithUpdateEvents())
+		require.NoError(t, err)
+		n, err := NewNode(
+			ctx,
+			database,
+			ListenP2PAddrStrings("/ip4/127.0.0.1/tcp/0"),
+			DataPath(dbPath),
+			WithRejoinPeers(true),
+		)
+		require.NoError(t, err)
+		return n
+	}
+
+	n2 := newNode()
+	assert.Empty(t, n2.KnownPeers())
+	n2.Boostrap([]peer.AddrInfo{{ID: n1.PeerID(), Addrs: n1.ListenAddrs()}})
+	require.Len(t, n2.KnownPeers(), 1)
+	require.NoError(t, n2.Close())
+
+	n2 = newNode()
+	defer n2.Close() //nolint:errcheck
+	known := n2.KnownPeers()
+	require.Len(t, known, 1)
+	assert.Equal(t, n1.PeerID(), known[0].ID)
+	assert.NotEmpty(t, known[0].Addrs)
+}
+
+func TestNodeWithoutRejoinPeersHasNoKnownPeers(t *testing.T) {
+	ctx := context.Background()
+	n1, err := NewNode(
+		ctx,
+		FixtureNewMemoryDBWithBroadcaster(t),
+		ListenP2PAddrStrings("/ip4/127.0.0.1/tcp/0"),
+		// DataPath() is a required option with the current implementation of key management
+		DataPath(t.TempDir()),
+	)
+	require.NoError(t, err)
+	defer n1.Close() //nolint:errcheck
+	n2, err := NewNode(
+		ctx,
+		FixtureNewMemoryDBWithBroadcaster(t),
+		ListenP2PAddrStrings("/ip4/127.0.0.1/tcp/0"),
+		// DataPath() is a required option with the current implementation of key management
+		DataPath(t.TempDir()),
+	)
+	require.NoError(t, err)
+	defer n2.Close() //nolint:errcheck
+
+	n2.Boostrap([]peer.AddrInfo{{ID: n1.PeerID(), Addrs: n1.ListenAddrs()}})
+	assert.Nil(t, n2.KnownPeers())
+}
+
+func TestNodeForwardsRequestsUpstream(t *testing.T) {
+	ctx := context.Background()
+	upstreamDB := FixtureNewMemoryDBWithBroadcaster(t)
+	err := upstreamDB.AddSchema(ctx, `type books { Title: String }`)
+	require.NoError(t, err)
+	res := upstreamDB.ExecRequest(ctx, `mutation { create_books(data: "{\"Title\": \"Dune\"}") { _key } }`)
+	require.Empty(t, res.GQL.Errors)
+	upstream, err := NewNode(
+		ctx,
+		upstreamDB,
+		ListenP2PAddrStrings("/ip4/127.0.0.1/tcp/0"),
+		WithPubSub(false),
+		WithServeRequests(true),
+		DataPath(t.TempDir()),
+	)
+	require.NoError(t, err)
+	defer upstream.Close()
+	require.NoError(t, upstream.Start())
+
+	forwarder, err := net.NewRequestForwarder(
+		upstream.ListenAddrs()[0].String() + "/p2p/" + upstream.PeerID().String(),
+	)
+	require.NoError(t, err)
+	opts := badgerds.Options{Options: badger.DefaultOptions("").WithInMemory(true)}
+	rootstore, err := badgerds.NewDatastore("", &opts)
+	require.NoError(t, err)
+	edgeDB, err := db.NewDB(ctx, rootstore, db.WithRequestForwarder(forwarder))
+	require.NoError(t, err)
+	edge, err := NewNode(
+		ctx,
+		edgeDB,
+		ListenP2PAddrStrings("/ip4/127.0.0.1/tcp/0"),
+		WithPubSub(false),
+		WithRequestForwarder(forwarder),
+		DataPath(t.TempDir()),
+	)
+	require.NoError(t, err)
+	defer edge.Close()
+
+	res = edge.ExecRequest(
+		client.WithRequestVariables(ctx, map[string]any{"title": "Dune"}),
+		`query($title: String) { books(filter: {Title: {_eq: $title}}) { Title } }`,
+	)
+	require.Empty(t, res.GQL.Errors)
+	assert.Equal(t, []map[string]any{{"Title": "Dune"}}, res.GQL.Data)
+}
+
 func mergeOptions(nodeOpts ...NodeOpt) (Options, error) {
 	var options Options
 	var nodeOpt NodeOpt
@@ -98,6 +98,8 @@ func (n *averageNode) Next() (bool, error) {
 		n.currentValue.Fields[n.virtualFieldIndex] = sum / float64(count)
 	case int64:
 		n.currentValue.Fields[n.virtualFieldIndex] = float64(sum) / float64(count)
+	case uint64:
+		n.currentValue.Fields[n.virtualFieldIndex] = float64(sum) / float64(count)
 	default:
 		return false, client.NewErrUnhandledType("sum", sumProp)
 	}
@@ -112,7 +114,11 @@ func (n *averageNode) SetPlan(p planNode) { n.plan = p }
 func (n *averageNode) Explain(explainType request.ExplainType) (map[string]any, error) {
 	switch explainType {
 	case request.SimpleExplain:
-		return map[string]any{}, nil
+		simpleExplainMap := map[string]any{}
+		if field := responseFieldOf(n.documentMapping, n.virtualFieldIndex, request.AverageFieldName); field != "" {
+			simpleExplainMap[responseFieldLabel] = field
+		}
+		return simpleExplainMap, nil
 
 	case request.ExecuteExplain:
 		return map[string]any{
@@ -15,11 +15,14 @@ import (
 	dag "github.com/ipfs/boxo/ipld/merkledag"
 	blocks "github.com/ipfs/go-block-format"
 	cid "github.com/ipfs/go-cid"
+	ds "github.com/ipfs/go-datastore"
 	ipld "github.com/ipfs/go-ipld-format"
 
 	"github.com/sourcenetwork/defradb/client/request"
 	"github.com/sourcenetwork/defradb/core"
+	corecrdt "github.com/sourcenetwork/defradb/core/crdt"
 	"github.com/sourcenetwork/defradb/db/fetcher"
+	"github.com/sourcenetwork/defradb/errors"
 	"github.com/sourcenetwork/defradb/planner/mapper"
 )
 
@@ -33,6 +36,10 @@ type dagScanNode struct {
 	visitedNodes map[string]bool
 
 	queuedCids []*cid.Cid
+	// fieldIds maps the CIDs yielded so far to the ID of the field they are for.
+	fieldIds map[string]string
+	// selectsPeer is true if the authoring peers of the commits have been requested.
+	selectsPeer bool
 
 	fetcher      fetcher.HeadFetcher
 	spans        core.Spans
@@ -51,6 +58,7 @@ func (p *Planner) DAGScan(commitSelect *mapper.CommitSelect) *dagScanNode {
 		planner:      p,
 		visitedNodes: make(map[string]bool),
 		queuedCids:   []*cid.Cid{},
+		fieldIds:     make(map[string]string),
 		commitSelect: commitSelect,
 		docMapper:    docMapper{&commitSelect.DocumentMapping},
 	}
@@ -79,6 +87,12 @@ func (n *dagScanNode) Init() error {
 		}
 	}
 
+	for _, field := range n.commitSelect.Fields {
+		if field.GetName() == request.PeerFieldName {
+			n.selectsPeer = true
+		}
+	}
+
 	return n.fetcher.Start(n.planner.ctx, n.planner.txn, n.spans, n.commitSelect.FieldName)
 }
 
@@ -189,12 +203,13 @@ func (n *dagScanNode) Next() (bool, error) {
 		currentCid = n.queuedCids[0]
 		n.queuedCids = n.queuedCids[1:(len(n.queuedCids))]
 	} else {
-		cid, err := n.fetcher.FetchNext()
-		if err != nil || cid == nil {
+		headKey, err := n.fetcher.FetchNext()
+		if err != nil || headKey == nil {
 			return false, err
 		}
 
-		currentCid = cid
+		currentCid = &headKey.Cid
+		n.fieldIds[currentCid.String()] = headKey.FieldId
 		// Reset the depthVisited for each head yielded by headset
 		n.depthVisited = 0
 	}
@@ -237,6 +252,10 @@ func (n *dagScanNode) Next() (bool, error) {
 
 		for i, h := range heads {
 			n.queuedCids[len(heads)-i-1] = &h.Cid
+			// previous heads are for the same field as the current commit.
+			if _, ok := n.fieldIds[h.Cid.String()]; !ok {
+				n.fieldIds[h.Cid.String()] = n.fieldIds[currentCid.String()]
+			}
 		}
 	}
 
@@ -246,6 +265,14 @@ func (n *dagScanNode) Next() (bool, error) {
 		return n.Next()
 	}
 
+	if n.commitSelect.FieldNameFilter.HasValue() &&
+		n.commitSelect.DocumentMapping.FirstOfName(currentValue, request.FieldNameFieldName) !=
+			n.commitSelect.FieldNameFilter.Value() {
+		// If a specific field name has been requested, and the current item is not
+		// for it, keep searching.
+		return n.Next()
+	}
+
 	n.currentValue = currentValue
 	return true, nil
 }
@@ -298,28 +325,27 @@ func (n *dagScanNode) dagBlockToNodeDoc(block blocks.Block) (core.Doc, []*ipld.L
 		return core.Doc{}, nil, err
 	}
 
-	// @todo: Wrap delta unmarshaling into a proper typed interface.
-	var delta map[string]any
+	// The entries of the delta are only decoded when read, as its data may hold a whole document.
+	var delta map[string]cbor.RawMessage
 	if err := cbor.Unmarshal(nd.Data(), &delta); err != nil {
 		return core.Doc{}, nil, err
 	}
 
-	prio, ok := delta["Priority"].(uint64)
-	if !ok {
+	var prio uint64
+	if err := cbor.Unmarshal(delta["Priority"], &prio); err != nil {
 		return core.Doc{}, nil, ErrDeltaMissingPriority
 	}
 
-	schemaVersionId, ok := delta["SchemaVersionID"].(string)
-	if ok {
+	var schemaVersionId string
+	if err := cbor.Unmarshal(delta["SchemaVersionID"], &schemaVersionId); err == nil {
 		n.commitSelect.DocumentMapping.SetFirstOfName(&commit,
 			request.SchemaVersionIDFieldName, schemaVersionId)
 	}
 
 	n.commitSelect.DocumentMapping.SetFirstOfName(&commit, request.HeightFieldName, int64(prio))
-	n.commitSelect.DocumentMapping.SetFirstOfName(&commit, request.DeltaFieldName, delta["Data"])
 
-	dockey, ok := delta["DocKey"].([]byte)
-	if !ok {
+	var dockey []byte
+	if err := cbor.Unmarshal(delta["DocKey"], &dockey); err != nil {
 		return core.Doc{}, nil, ErrDeltaMissingDockey
 	}
 
@@ -334,6 +360,42 @@ func (n *dagScanNode) dagBlockToNodeDoc(block blocks.Block) (core.Doc, []*ipld.L
 	n.commitSelect.DocumentMapping.SetFirstOfName(&commit,
 		request.CollectionIDFieldName, int64(collection.ID()))
 
+	if rawData, ok := delta["Data"]; ok && len(n.commitSelect.DocumentMapping.IndexesByName[request.DeltaFieldName]) > 0 {
+		var data any
+		if err := cbor.Unmarshal(rawData, &data); err != nil {
+			return core.Doc{}, nil, err
+		}
+		// The data of the deltas of the encrypted collections is only readable with their key.
+		if buf, ok := data.([]byte); ok && corecrdt.IsEncryptedDeltaData(buf) {
+			if c := corecrdt.KeyringFromContext(n.planner.ctx).Cipher(collection.Name()); c != nil {
+				data, err = c.Decrypt(buf)
+				if err != nil {
+					return core.Doc{}, nil, err
+				}
+			}
+		}
+		n.commitSelect.DocumentMapping.SetFirstOfName(&commit, request.DeltaFieldName, data)
+	}
+
+	fieldId := n.fieldIds[cid.String()]
+	n.commitSelect.DocumentMapping.SetFirstOfName(&commit, request.FieldIDFieldName, fieldId)
+	for _, field := range collection.Schema().Fields {
+		if field.ID.String() == fieldId {
+			n.commitSelect.DocumentMapping.SetFirstOfName(&commit, request.FieldNameFieldName, field.Name)
+			break
+		}
+	}
+
+	if n.selectsPeer {
+		author, err := n.planner.txn.Systemstore().Get(n.planner.ctx, core.NewCommitAuthorKey(cid).ToDS())
+		if err != nil && !errors.Is(err, ds.ErrNotFound) {
+			return core.Doc{}, nil, err
+		}
+		if err == nil {
+			n.commitSelect.DocumentMapping.SetFirstOfName(&commit, request.PeerFieldName, string(author))
+		}
+	}
+
 	heads := make([]*ipld.Link, 0)
 
 	// links
@@ -87,9 +87,13 @@ func (n *countNode) simpleExplain() (map[string]any, error) {
 		sourceExplanations[i] = simpleExplainMap
 	}
 
-	return map[string]any{
+	simpleExplainMap := map[string]any{
 		sourcesLabel: sourceExplanations,
-	}, nil
+	}
+	if field := responseFieldOf(n.documentMapping, n.virtualFieldIndex, request.CountFieldName); field != "" {
+		simpleExplainMap[responseFieldLabel] = field
+	}
+	return simpleExplainMap, nil
 }
 
 // Explain method returns a map containing all attributes of this node that
@@ -122,6 +126,18 @@ func (n *countNode) Next() (bool, error) {
 	var count int
 	for _, source := range n.aggregateMapping {
 		property := n.currentValue.Fields[source.Index]
+		if aggregates, isAggregated := property.(*groupAggregates); isAggregated {
+			// The documents of a streamed group are counted as they are read.
+			count += aggregates.count
+			continue
+		}
+		if source.IsNested {
+			// The nested items are counted by the inner count of each item of the host.
+			if docs, isDocs := property.([]core.Doc); isDocs {
+				count += sumInnerCounts(docs, source.ChildTarget.Index)
+			}
+			continue
+		}
 		v := reflect.ValueOf(property)
 		switch v.Kind() {
 		// v.Len will panic if v is not one of these types, we don't want it to panic
@@ -185,6 +201,19 @@ func countDocs(docs []core.Doc) int {
 	return count
 }
 
+// sumInnerCounts sums the counts at the given index of the documents in a slice, skipping
+// over hidden items (a grouping mechanic).
+func sumInnerCounts(docs []core.Doc, countIndex int) int {
+	count := 0
+	for _, doc := range docs {
+		if innerCount, isInt := doc.Fields[countIndex].(int); isInt && !doc.Hidden {
+			count += innerCount
+		}
+	}
+
+	return count
+}
+
 func countItems[T any](source []T, filter *mapper.Filter, limit *mapper.Limit) (int, error) {
 	items := enumerable.New(source)
 	if filter != nil {
@@ -0,0 +1,37 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package planner
+
+import (
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/logging"
+)
+
+// The steps of the construction of a plan, as logged in the plan debug mode.
+const (
+	// planStepSelect is the step selecting the nodes planning each statement of the request.
+	planStepSelect = "select"
+	// planStepExpand is the step wiring the nodes of the plan together.
+	planStepExpand = "expand"
+	// planStepOptimize is the step optimizing the plan, before it is run.
+	planStepOptimize = "optimize"
+	// planStepRun is the step choosing how the plan is run.
+	planStepRun = "run"
+)
+
+// debugPlan logs the given decision made at the given step of the construction of the plan,
+// if the plan debug mode is enabled by the context of the planner.
+func (p *Planner) debugPlan(step string, message string, keyvals ...logging.KV) {
+	if !client.PlanDebugFromContext(p.ctx) {
+		return
+	}
+	log.Info(p.ctx, message, append([]logging.KV{logging.NewKV("Step", step)}, keyvals...)...)
+}
@@ -16,6 +16,10 @@ const (
 	errUnknownDependency              string = "given field does not exist"
 	errFailedToClosePlan              string = "failed to close the plan"
 	errFailedToCollectExecExplainInfo string = "failed to collect execution explain information"
+	errInvalidJoinField               string = "collections can only be joined on scalar fields"
+	errInvalidHistoryField            string = "history can only be requested for scalar fields"
+	errNonFiniteAggregate             string = "the result of the aggregate is not a finite number"
+	errIntegerSumOverflow             string = "the sum of the integers overflows the range of its type"
 )
 
 var (
@@ -33,6 +37,10 @@ var (
 	ErrUnknownExplainRequestType           = errors.New("can not explain request of unknown type")
 	ErrFailedToCollectExecExplainInfo      = errors.New(errFailedToCollectExecExplainInfo)
 	ErrUnknownDependency                   = errors.New(errUnknownDependency)
+	ErrInvalidJoinField                    = errors.New(errInvalidJoinField)
+	ErrInvalidHistoryField                 = errors.New(errInvalidHistoryField)
+	ErrNonFiniteAggregate                  = errors.New(errNonFiniteAggregate)
+	ErrIntegerSumOverflow                  = errors.New(errIntegerSumOverflow)
 )
 
 func NewErrUnknownDependency(name string) error {
@@ -46,3 +54,23 @@ func NewErrFailedToClosePlan(inner error, location string) error {
 func NewErrFailedToCollectExecExplainInfo(inner error) error {
 	return errors.Wrap(errFailedToCollectExecExplainInfo, inner)
 }
+
+func NewErrInvalidJoinField(collection string, field string) error {
+	return errors.New(errInvalidJoinField, errors.NewKV("Collection", collection), errors.NewKV("Field", field))
+}
+
+func NewErrInvalidHistoryField(collection string, field string) error {
+	return errors.New(errInvalidHistoryField, errors.NewKV("Collection", collection), errors.NewKV("Field", field))
+}
+
+// NewErrNonFiniteAggregate returns an error indicating that an aggregate of floats resulted in
+// the given NaN or infinity, from overflowing the range of floats, which can't be returned.
+func NewErrNonFiniteAggregate(value float64) error {
+	return errors.New(errNonFiniteAggregate, errors.NewKV("Value", value))
+}
+
+// NewErrIntegerSumOverflow returns an error indicating that a sum of integers resulted in the given
+// value, which is outside of the range of the type of the sum.
+func NewErrIntegerSumOverflow(value string) error {
+	return errors.New(errIntegerSumOverflow, errors.NewKV("Value", value))
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/iancoleman/strcase"
 
 	"github.com/sourcenetwork/defradb/client/request"
+	"github.com/sourcenetwork/defradb/core"
 )
 
 type explainablePlanNode interface {
@@ -38,14 +39,18 @@ var (
 	_ explainablePlanNode = (*dagScanNode)(nil)
 	_ explainablePlanNode = (*deleteNode)(nil)
 	_ explainablePlanNode = (*groupNode)(nil)
+	_ explainablePlanNode = (*historyNode)(nil)
 	_ explainablePlanNode = (*limitNode)(nil)
+	_ explainablePlanNode = (*metaNode)(nil)
 	_ explainablePlanNode = (*orderNode)(nil)
+	_ explainablePlanNode = (*runningAggregateNode)(nil)
 	_ explainablePlanNode = (*scanNode)(nil)
 	_ explainablePlanNode = (*selectNode)(nil)
 	_ explainablePlanNode = (*selectTopNode)(nil)
 	_ explainablePlanNode = (*sumNode)(nil)
 	_ explainablePlanNode = (*topLevelNode)(nil)
 	_ explainablePlanNode = (*typeIndexJoin)(nil)
+	_ explainablePlanNode = (*unionNode)(nil)
 	_ explainablePlanNode = (*updateNode)(nil)
 )
 
@@ -59,10 +64,29 @@ const (
 	idsLabel            = "ids"
 	limitLabel          = "limit"
 	offsetLabel         = "offset"
+	responseFieldLabel  = "responseField"
 	sourcesLabel        = "sources"
 	spansLabel          = "spans"
 )
 
+// responseFieldOf returns the field of the response that the value at the given index of the
+// documents of the given mapping is rendered as, of the form `alias: name` if it is aliased, so
+// that the plan nodes producing it can be mapped back to the request.
+//
+// Returns an empty string if the value is not rendered, such as an aggregate only used by another.
+func responseFieldOf(mapping *core.DocumentMapping, index int, name string) string {
+	for _, renderKey := range mapping.RenderKeys {
+		if renderKey.Index != index {
+			continue
+		}
+		if renderKey.Key == name {
+			return name
+		}
+		return renderKey.Key + ": " + name
+	}
+	return ""
+}
+
 // buildSimpleExplainGraph builds the explainGraph from the given top level plan.
 //
 // Request:
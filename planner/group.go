@@ -11,6 +11,10 @@
 package planner
 
 import (
+	"container/heap"
+
+	"golang.org/x/text/collate"
+
 	"github.com/sourcenetwork/defradb/client"
 	"github.com/sourcenetwork/defradb/client/request"
 	"github.com/sourcenetwork/defradb/core"
@@ -34,6 +38,15 @@ type groupNode struct {
 	// The data sources that this node will draw data from.
 	dataSources []*dataSource
 
+	// Whether the documents are aggregated per group as they are read, instead of being retained
+	// in their group. This is the case when the documents of the groups are not rendered, and are
+	// only counted or summed.
+	streamed bool
+
+	// The indexes of the fields of the documents that are summed for each child select of the
+	// streamed groups, by index of the child select.
+	streamedSums map[int][]int
+
 	values       []core.Doc
 	currentIndex int
 
@@ -66,10 +79,12 @@ func (p *Planner) GroupBy(n *mapper.GroupBy, parsed *mapper.Select, childSelects
 		return nil, nil
 	}
 
+	streamedSums, streamed := streamedGroupSums(parsed, childSelects)
+
 	dataSources := []*dataSource{}
 	// GroupBy must always have at least one data source, for example
 	// childSelects may be empty if no group members are requested
-	if len(childSelects) == 0 {
+	if len(childSelects) == 0 || streamed {
 		dataSources = append(
 			dataSources,
 			// If there are no child selects, then we just take the first field index of name _group
@@ -83,7 +98,9 @@ func (p *Planner) GroupBy(n *mapper.GroupBy, parsed *mapper.Select, childSelects
 			// groups will only group on the fields they explicitly reference
 			childSelect.GroupBy.Fields = append(childSelect.GroupBy.Fields, n.Fields...)
 		}
-		dataSources = append(dataSources, newDataSource(childSelect.Index))
+		if !streamed {
+			dataSources = append(dataSources, newDataSource(childSelect.Index))
+		}
 	}
 
 	groupNodeObj := groupNode{
@@ -91,6 +108,8 @@ func (p *Planner) GroupBy(n *mapper.GroupBy, parsed *mapper.Select, childSelects
 		childSelects:  childSelects,
 		groupByFields: n.Fields,
 		dataSources:   dataSources,
+		streamed:      streamed,
+		streamedSums:  streamedSums,
 		docMapper:     docMapper{&parsed.DocumentMapping},
 	}
 	return &groupNodeObj, nil
@@ -148,6 +167,13 @@ func (n *groupNode) Source() planNode { return n.dataSources[0].Source() }
 func (n *groupNode) Next() (bool, error) {
 	n.execInfo.iterations++
 
+	if n.values == nil && n.streamed {
+		err := n.aggregateGroups()
+		if err != nil {
+			return false, err
+		}
+	}
+
 	if n.values == nil {
 		values, err := join(n.dataSources, n.groupByFields, n.documentMapping)
 		if err != nil {
@@ -170,6 +196,16 @@ func (n *groupNode) Next() (bool, error) {
 				}
 
 				childDocs := subSelect.([]core.Doc)
+				if childSelect.OrderBy != nil && childSelect.Limit != nil {
+					// Only the first items of each group by its order are retained, instead of
+					// retaining and hiding the whole group.
+					var dropped uint64
+					childDocs, dropped = orderGroupDocs(childDocs, childSelect.OrderBy, childSelect.Limit)
+					group.Fields[childSelect.Index] = childDocs
+
+					n.execInfo.hiddenAfterLimit += dropped
+				}
+
 				if childSelect.Limit != nil {
 					l := uint64(len(childDocs))
 
@@ -200,6 +236,161 @@ func (n *groupNode) Next() (bool, error) {
 	return false, nil
 }
 
+// aggregateGroups reads the documents of the source, folding each into the aggregates of its
+// group instead of retaining it.
+//
+// The groups are kept in the order in which they are first read, as when the documents are
+// retained.
+func (n *groupNode) aggregateGroups() error {
+	source := n.dataSources[0].parentSource
+	groups := orderedMap{
+		values:       []core.Doc{},
+		indexesByKey: map[string]int{},
+	}
+
+	for {
+		hasNext, err := source.Next()
+		if err != nil {
+			return err
+		}
+		if !hasNext {
+			break
+		}
+
+		doc := source.Value()
+		key := generateKey(doc, n.groupByFields)
+		index, exists := groups.indexesByKey[key]
+		if !exists {
+			index = len(groups.values)
+			groups.values = append(groups.values, n.newStreamedGroup(doc))
+			groups.indexesByKey[key] = index
+		}
+		group := groups.values[index]
+
+		for _, childSelect := range n.childSelects {
+			if childSelect.Filter != nil {
+				passes, err := mapper.RunFilter(doc, childSelect.Filter)
+				if err != nil {
+					return err
+				}
+				if !passes {
+					continue
+				}
+			}
+
+			aggregates := group.Fields[childSelect.Index].(*groupAggregates)
+			aggregates.count++
+			for _, fieldIndex := range n.streamedSums[childSelect.Index] {
+				if fieldIndex < len(doc.Fields) {
+					aggregates.sums[fieldIndex].add(doc.Fields[fieldIndex])
+				}
+			}
+		}
+	}
+
+	n.values = groups.values
+	n.execInfo.groups += uint64(len(n.values))
+	n.execInfo.childSelections += uint64(len(n.values) * len(n.childSelects))
+	return nil
+}
+
+// newStreamedGroup returns a new streamed group of the given document, the first of the group,
+// with empty aggregates for each of the child selects.
+func (n *groupNode) newStreamedGroup(doc core.Doc) core.Doc {
+	group := doc.Clone()
+	for _, childSelect := range n.childSelects {
+		// The source may use a smaller doc mapping, if so the field slice must be extended.
+		if childSelect.Index >= len(group.Fields) {
+			fields := make(core.DocFields, childSelect.Index+1)
+			copy(fields, group.Fields)
+			group.Fields = fields
+		}
+
+		aggregates := &groupAggregates{sums: map[int]*numericSum{}}
+		for _, fieldIndex := range n.streamedSums[childSelect.Index] {
+			aggregates.sums[fieldIndex] = &numericSum{}
+		}
+		group.Fields[childSelect.Index] = aggregates
+	}
+	return group
+}
+
+// groupAggregates holds the aggregates of the documents of a child select of a streamed group,
+// which the aggregate nodes read in place of the documents.
+type groupAggregates struct {
+	// count is the number of documents of the child select.
+	count int
+
+	// sums are the sums of the fields of the documents of the child select, by field index.
+	sums map[int]*numericSum
+}
+
+// streamedGroupSums returns the indexes of the fields that are summed for each of the given child
+// selects of a group, by index of the child select, and true if the documents of the groups can be
+// aggregated as they are read instead of being retained.
+//
+// This is the case if the documents of the child selects are not rendered, and are only counted or
+// summed as a whole, including through an average.
+func streamedGroupSums(parsed *mapper.Select, childSelects []*mapper.Select) (map[int][]int, bool) {
+	sums := make(map[int][]int, len(childSelects))
+	for _, childSelect := range childSelects {
+		if !isStreamableChildSelect(parsed, childSelect) {
+			return nil, false
+		}
+		sums[childSelect.Index] = []int{}
+	}
+
+	for _, field := range parsed.Fields {
+		aggregate, isAggregate := field.(*mapper.Aggregate)
+		if !isAggregate {
+			continue
+		}
+		for _, target := range aggregate.AggregateTargets {
+			if _, isChildSelect := sums[target.Index]; !isChildSelect {
+				continue
+			}
+			switch aggregate.Name {
+			case request.CountFieldName:
+				if target.IsNested {
+					return nil, false
+				}
+			case request.SumFieldName:
+				_, isInnerAggregate := request.Aggregates[target.ChildTarget.Name]
+				if target.IsNested || !target.ChildTarget.HasValue || isInnerAggregate {
+					return nil, false
+				}
+				sums[target.Index] = append(sums[target.Index], target.ChildTarget.Index)
+			case request.AverageFieldName:
+				// The average is computed from its count and sum, which are aggregates of their own.
+			default:
+				return nil, false
+			}
+		}
+	}
+	return sums, true
+}
+
+// isStreamableChildSelect returns true if the documents of the given child select of a group can
+// be aggregated as they are read, as they are neither rendered, ordered, limited, grouped nor
+// joined with other documents.
+func isStreamableChildSelect(parsed *mapper.Select, childSelect *mapper.Select) bool {
+	for _, renderKey := range parsed.DocumentMapping.RenderKeys {
+		if renderKey.Index == childSelect.Index {
+			return false
+		}
+	}
+	if childSelect.Limit != nil || childSelect.OrderBy != nil || childSelect.GroupBy != nil ||
+		childSelect.DocKeys.HasValue() || childSelect.Cid.HasValue() {
+		return false
+	}
+	for _, field := range childSelect.Fields {
+		if _, isField := field.(*mapper.Field); !isField {
+			return false
+		}
+	}
+	return true
+}
+
 func (n *groupNode) simpleExplain() (map[string]any, error) {
 	simpleExplainMap := map[string]any{}
 
@@ -326,3 +517,97 @@ func (n *groupNode) Explain(explainType request.ExplainType) (map[string]any, er
 		return nil, ErrUnknownExplainRequestType
 	}
 }
+
+// orderGroupDocs returns the first of the given documents of a group in the given order, up to
+// the end of the given limit, along with the number of documents dropped.
+//
+// The documents to retain are selected using a heap bounded by the limit, so the group does
+// not need to be sorted beyond them. Documents that are equal by the given order are ordered by
+// their keys.
+func orderGroupDocs(docs []core.Doc, order *mapper.OrderBy, limit *mapper.Limit) ([]core.Doc, uint64) {
+	capacity := uint64(len(docs))
+	if limit.Limit+limit.Offset < capacity {
+		capacity = limit.Limit + limit.Offset
+	}
+
+	h := &groupDocHeap{
+		ordering: order.Conditions,
+		collator: newCollator(order.Collation),
+		items:    make([]groupDocItem, 0, capacity),
+	}
+	var dropped uint64
+	for i, doc := range docs {
+		item := groupDocItem{doc: doc, sequence: i}
+		if uint64(h.Len()) < capacity {
+			heap.Push(h, item)
+			continue
+		}
+
+		dropped++
+		// The root of the heap is the last of the retained items, which the new item replaces
+		// if it comes before it.
+		if h.Len() > 0 && h.before(item, h.items[0]) {
+			h.items[0] = item
+			heap.Fix(h, 0)
+		}
+	}
+
+	ordered := make([]core.Doc, h.Len())
+	for i := len(ordered) - 1; i >= 0; i-- {
+		ordered[i] = heap.Pop(h).(groupDocItem).doc
+	}
+	return ordered, dropped
+}
+
+// groupDocItem is a document of a group, along with the position in which it was given.
+type groupDocItem struct {
+	doc      core.Doc
+	sequence int
+}
+
+// groupDocHeap is a heap of the documents of a group, with the last of them by the heap's
+// ordering at its root.
+type groupDocHeap struct {
+	ordering []mapper.OrderCondition
+	collator *collate.Collator
+	items    []groupDocItem
+}
+
+var _ heap.Interface = (*groupDocHeap)(nil)
+
+// before returns true if the given item a comes before b, falling back to their keys, and then
+// to the order in which they were given, if they are equal by the ordering.
+func (h *groupDocHeap) before(a groupDocItem, b groupDocItem) bool {
+	for _, order := range h.ordering {
+		compare := compareValues(
+			getDocProp(a.doc, order.FieldIndexes),
+			getDocProp(b.doc, order.FieldIndexes),
+			h.collator,
+		)
+		if compare == 0 {
+			continue
+		}
+		if order.Direction == mapper.DESC {
+			return compare > 0
+		}
+		return compare < 0
+	}
+	if keyA, keyB := a.doc.GetKey(), b.doc.GetKey(); keyA != keyB {
+		return keyA < keyB
+	}
+	return a.sequence < b.sequence
+}
+
+func (h *groupDocHeap) Len() int { return len(h.items) }
+
+func (h *groupDocHeap) Less(i, j int) bool { return h.before(h.items[j], h.items[i]) }
+
+func (h *groupDocHeap) Swap(i, j int) { h.items[i], h.items[j] = h.items[j], h.items[i] }
+
+func (h *groupDocHeap) Push(item any) { h.items = append(h.items, item.(groupDocItem)) }
+
+func (h *groupDocHeap) Pop() any {
+	last := h.items[len(h.items)-1]
+	h.items = h.items[:len(h.items)-1]
+	return last
+}
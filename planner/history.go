@@ -0,0 +1,311 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package planner
+
+import (
+	"sort"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+	dag "github.com/ipfs/boxo/ipld/merkledag"
+	cid "github.com/ipfs/go-cid"
+	dsq "github.com/ipfs/go-datastore/query"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/client/request"
+	"github.com/sourcenetwork/defradb/core"
+	corecrdt "github.com/sourcenetwork/defradb/core/crdt"
+	"github.com/sourcenetwork/defradb/db/fetcher"
+	"github.com/sourcenetwork/defradb/planner/mapper"
+)
+
+// historyNode is the plan node yielding the history of the values of a field of a document,
+// by walking the DAG of the field's register from its heads.
+//
+// It is appended to the documents of its parent, which give it their key through its spans.
+type historyNode struct {
+	documentIterator
+	docMapper
+
+	p *Planner
+
+	collection client.CollectionDescription
+	field      client.FieldDescription
+
+	docKey  string
+	entries []core.Doc
+	index   int
+
+	execInfo historyExecInfo
+}
+
+type historyExecInfo struct {
+	// Total number of commits walked.
+	commits uint64
+}
+
+// History returns the plan node of the given history selection.
+func (p *Planner) History(historySelect *mapper.Select) (*historyNode, error) {
+	desc, err := p.getCollectionDesc(historySelect.CollectionName)
+	if err != nil {
+		return nil, err
+	}
+
+	fieldName := historySelect.HistoryField.Value()
+	field, ok := desc.GetField(fieldName)
+	if !ok || field.IsObject() || field.Name == request.KeyFieldName {
+		return nil, NewErrInvalidHistoryField(desc.Name, fieldName)
+	}
+
+	return &historyNode{
+		p:          p,
+		collection: desc,
+		field:      field,
+		docMapper:  docMapper{&historySelect.DocumentMapping},
+	}, nil
+}
+
+func (n *historyNode) Kind() string {
+	return "historyNode"
+}
+
+func (n *historyNode) Init() error {
+	n.entries = nil
+	n.index = 0
+	if n.docKey == "" {
+		return nil
+	}
+
+	times, err := n.commitTimes()
+	if err != nil {
+		return err
+	}
+
+	heads, err := n.heads()
+	if err != nil {
+		return err
+	}
+
+	ctx := corecrdt.WithDeltaCipher(
+		n.p.ctx,
+		corecrdt.KeyringFromContext(n.p.ctx).Cipher(n.collection.Name),
+	)
+	fieldKeyring := corecrdt.FieldKeyringFromContext(n.p.ctx)
+	visited := map[cid.Cid]struct{}{}
+	heights := map[cid.Cid]uint64{}
+	var cids []cid.Cid
+	queue := heads
+	for len(queue) > 0 {
+		c := queue[0]
+		queue = queue[1:]
+		if _, ok := visited[c]; ok {
+			continue
+		}
+		visited[c] = struct{}{}
+		n.execInfo.commits++
+
+		block, err := n.p.txn.DAGstore().Get(n.p.ctx, c)
+		if err != nil {
+			return err
+		}
+		nd, err := dag.DecodeProtobuf(block.RawData())
+		if err != nil {
+			return err
+		}
+
+		var delta corecrdt.LWWRegDelta
+		if err := cbor.Unmarshal(nd.Data(), &delta); err != nil {
+			return err
+		}
+		data, err := corecrdt.DecryptDeltaData(ctx, delta.Data)
+		if err != nil {
+			return err
+		}
+		// The values of encrypted fields are only readable with their key, and are null otherwise.
+		var value any
+		if data, ok := fetcher.DecryptFieldValue(fieldKeyring, n.collection.Name, n.field.Name, data); ok {
+			value, err = fetcher.DecodeFieldValue(n.field, data)
+			if err != nil {
+				return err
+			}
+		}
+
+		entry := n.documentMapping.NewDoc()
+		n.documentMapping.SetFirstOfName(&entry, request.ValueFieldName, value)
+		n.documentMapping.SetFirstOfName(&entry, request.CidFieldName, c.String())
+		n.documentMapping.SetFirstOfName(&entry, request.HeightFieldName, int64(delta.Priority))
+		if t, ok := times[c]; ok {
+			n.documentMapping.SetFirstOfName(&entry, request.TimestampFieldName, t.UTC().Format(time.RFC3339Nano))
+		}
+		n.entries = append(n.entries, entry)
+		cids = append(cids, c)
+		heights[c] = delta.Priority
+
+		for _, link := range nd.Links() {
+			if link.Name == core.HEAD {
+				queue = append(queue, link.Cid)
+			}
+		}
+	}
+
+	// The values are yielded from the latest to the earliest, the concurrent ones by CID.
+	sort.Sort(historyByHeight{entries: n.entries, cids: cids, heights: heights})
+
+	return nil
+}
+
+// historyByHeight sorts the entries of a history, and their CIDs, from the highest to the
+// lowest commit.
+type historyByHeight struct {
+	entries []core.Doc
+	cids    []cid.Cid
+	heights map[cid.Cid]uint64
+}
+
+func (h historyByHeight) Len() int { return len(h.entries) }
+
+func (h historyByHeight) Less(i, j int) bool {
+	hi, hj := h.heights[h.cids[i]], h.heights[h.cids[j]]
+	if hi != hj {
+		return hi > hj
+	}
+	return h.cids[i].String() < h.cids[j].String()
+}
+
+func (h historyByHeight) Swap(i, j int) {
+	h.entries[i], h.entries[j] = h.entries[j], h.entries[i]
+	h.cids[i], h.cids[j] = h.cids[j], h.cids[i]
+}
+
+// heads returns the heads of the DAG of the field of the document.
+func (n *historyNode) heads() ([]cid.Cid, error) {
+	prefix := core.HeadStoreKey{DocKey: n.docKey, FieldId: n.field.ID.String()}
+	q, err := n.p.txn.Headstore().Query(n.p.ctx, dsq.Query{
+		Prefix:   prefix.ToString() + "/",
+		KeysOnly: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	entries, err := q.Rest()
+	if closeErr := q.Close(); closeErr != nil {
+		return nil, closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	heads := make([]cid.Cid, 0, len(entries))
+	for _, entry := range entries {
+		key, err := core.NewHeadStoreKey(entry.Key)
+		if err != nil {
+			return nil, err
+		}
+		heads = append(heads, key.Cid)
+	}
+	return heads, nil
+}
+
+// commitTimes returns the times at which the commits of the field of the document were recorded,
+// which are those of the composite commits they are part of.
+func (n *historyNode) commitTimes() (map[cid.Cid]time.Time, error) {
+	prefix := core.CommitTimeKey{DocKey: n.docKey}
+	q, err := n.p.txn.Systemstore().Query(n.p.ctx, dsq.Query{
+		Prefix:   prefix.ToString() + "/",
+		KeysOnly: true,
+		Orders:   []dsq.Order{dsq.OrderByKey{}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	entries, err := q.Rest()
+	if closeErr := q.Close(); closeErr != nil {
+		return nil, closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	times := map[cid.Cid]time.Time{}
+	for _, entry := range entries {
+		key, err := core.NewCommitTimeKeyFromString(entry.Key)
+		if err != nil {
+			return nil, err
+		}
+		block, err := n.p.txn.DAGstore().Get(n.p.ctx, key.Cid)
+		if err != nil {
+			return nil, err
+		}
+		nd, err := dag.DecodeProtobuf(block.RawData())
+		if err != nil {
+			return nil, err
+		}
+		for _, link := range nd.Links() {
+			if link.Name != n.field.Name {
+				continue
+			}
+			// The keys are ordered by time, so the earliest record of a commit is kept.
+			if _, ok := times[link.Cid]; !ok {
+				times[link.Cid] = key.Time()
+			}
+		}
+	}
+	return times, nil
+}
+
+func (n *historyNode) Start() error {
+	return nil
+}
+
+// Spans sets the document whose field's history is yielded, which is the only value of the
+// given spans.
+func (n *historyNode) Spans(spans core.Spans) {
+	if len(spans.Value) == 0 {
+		return
+	}
+	n.docKey = spans.Value[0].Start().DocKey
+}
+
+func (n *historyNode) Next() (bool, error) {
+	if n.index >= len(n.entries) {
+		return false, nil
+	}
+	n.currentValue = n.entries[n.index]
+	n.index++
+	return true, nil
+}
+
+func (n *historyNode) Close() error {
+	return nil
+}
+
+func (n *historyNode) Source() planNode { return nil }
+
+func (n *historyNode) Append() bool { return true }
+
+// Explain method returns a map containing all attributes of this node that
+// are to be explained, subscribes / opts-in this node to be an explainablePlanNode.
+func (n *historyNode) Explain(explainType request.ExplainType) (map[string]any, error) {
+	switch explainType {
+	case request.SimpleExplain:
+		return map[string]any{
+			fieldNameLabel: n.field.Name,
+		}, nil
+
+	case request.ExecuteExplain:
+		return map[string]any{
+			"commits": n.execInfo.commits,
+		}, nil
+
+	default:
+		return nil, ErrUnknownExplainRequestType
+	}
+}
@@ -28,6 +28,10 @@ type limitNode struct {
 	offset   uint64
 	rowIndex uint64
 
+	// pushedDown is true if the limit is applied by the scan node of the select, in which case
+	// the documents it yields are already within the limit.
+	pushedDown bool
+
 	execInfo limitExecInfo
 }
 
@@ -67,6 +71,10 @@ func (n *limitNode) Value() core.Doc        { return n.plan.Value() }
 func (n *limitNode) Next() (bool, error) {
 	n.execInfo.iterations++
 
+	if n.pushedDown {
+		return n.plan.Next()
+	}
+
 	// check if we're passed the limit
 	if n.limit != 0 && n.rowIndex >= n.limit+n.offset {
 		return false, nil
@@ -10,7 +10,11 @@
 
 package mapper
 
-import "github.com/sourcenetwork/defradb/core"
+import (
+	"github.com/sourcenetwork/immutable"
+
+	"github.com/sourcenetwork/defradb/core"
+)
 
 // An optional child target.
 type OptionalChildTarget struct {
@@ -36,6 +40,16 @@ type AggregateTarget struct {
 	// This may be empty if the aggregate targets a whole collection (e.g. Count),
 	// or if `HostIndex` is an inline array.
 	ChildTarget OptionalChildTarget
+
+	// If true, the aggregate targets items nested within the items of the host, which are
+	// aggregated by the `ChildTarget` of each of the host's items.
+	//
+	// This is an inner aggregate of the same kind as this one.
+	IsNested bool
+
+	// The number of items, ending with each item, aggregated by each value of a running
+	// aggregate. If none, each value aggregates all the items up to its item.
+	Window immutable.Option[uint64]
 }
 
 // Aggregate represents an aggregate operation definition.
@@ -25,6 +25,9 @@ type CommitSelect struct {
 	// The field for which commits have been requested.
 	FieldName immutable.Option[string]
 
+	// The name of the field for which commits have been requested.
+	FieldNameFilter immutable.Option[string]
+
 	// The maximum depth to yield results for.
 	Depth immutable.Option[uint64]
 
@@ -45,5 +48,7 @@ func (s *CommitSelect) cloneTo(index int) *CommitSelect {
 		DocKey:    s.DocKey,
 		FieldName: s.FieldName,
 		Cid:       s.Cid,
+
+		FieldNameFilter: s.FieldNameFilter,
 	}
 }
@@ -99,8 +99,13 @@ func toSelect(
 		Targetable:      toTargetable(thisIndex, selectRequest, mapping),
 		DocumentMapping: *mapping,
 		Cid:             selectRequest.CID,
+		AtTime:          selectRequest.AtTime,
 		CollectionName:  collectionName,
 		Fields:          fields,
+		Depth:           selectRequest.Depth,
+		IncludeMissing:  selectRequest.IncludeMissing,
+		Join:            selectRequest.Join,
+		HistoryField:    selectRequest.HistoryField,
 	}, nil
 }
 
@@ -177,10 +182,14 @@ func resolveAggregates(
 
 			// If the host has not been requested the child mapping may not yet exist and
 			// we must create it before we can convert the filter.
-			childIsMapped := len(mapping.IndexesByName[target.hostExternalName]) != 0
+			//
+			// Targets nested within the items of the host are aggregated through an inner
+			// aggregate, which must be added to the host when creating it, so they never
+			// share an existing host.
+			childIsMapped := len(mapping.IndexesByName[target.hostExternalName]) != 0 && len(target.childPath) == 0
+			childName := target.childExternalName
 
 			var hasHost bool
-			var convertedFilter *Filter
 			if childIsMapped {
 				fieldDesc, isField := desc.GetField(target.hostExternalName)
 				if isField && !fieldDesc.IsObject() {
@@ -210,16 +219,7 @@ func resolveAggregates(
 						OrderBy: order,
 					}
 				} else {
-					childObjectIndex := mapping.FirstIndexOfName(target.hostExternalName)
-					childMapping := mapping.ChildMappings[childObjectIndex]
-					convertedFilter = ToFilter(target.filter, childMapping)
-					host, hasHost = tryGetTarget(
-						target.hostExternalName,
-						convertedFilter,
-						target.limit,
-						toOrderBy(target.order, childMapping),
-						fields,
-					)
+					host, hasHost = tryGetJoinTarget(target, fields)
 				}
 			}
 
@@ -241,12 +241,37 @@ func resolveAggregates(
 
 				mapAggregateNestedTargets(target, hostSelectRequest, selectRequest.Root)
 
+				if len(target.childPath) > 0 {
+					innerAggregate := toInnerAggregateRequest(aggregate.field.Name, target)
+					hostSelectRequest.Fields = append(hostSelectRequest.Fields, innerAggregate)
+					// The host's items are then aggregated using their inner aggregate.
+					childName = innerAggregate.Name
+				}
+
 				childMapping, childDesc, err := getTopLevelInfo(descriptionsRepo, hostSelectRequest, childCollectionName)
 				if err != nil {
 					return nil, err
 				}
 
-				childFields, _, err := getRequestables(hostSelectRequest, childMapping, childDesc, descriptionsRepo)
+				childFields, childAggregates, err := getRequestables(
+					hostSelectRequest,
+					childMapping,
+					childDesc,
+					descriptionsRepo,
+				)
+				if err != nil {
+					return nil, err
+				}
+
+				childAggregates = appendUnderlyingAggregates(childAggregates, childMapping)
+				childFields, err = resolveAggregates(
+					hostSelectRequest,
+					childAggregates,
+					childFields,
+					childMapping,
+					childDesc,
+					descriptionsRepo,
+				)
 				if err != nil {
 					return nil, err
 				}
@@ -254,11 +279,9 @@ func resolveAggregates(
 				childMapping = childMapping.CloneWithoutRender()
 				mapping.SetChildAt(index, childMapping)
 
-				if !childIsMapped {
-					// If the child was not mapped, the filter will not have been converted yet
-					// so we must do that now.
-					convertedFilter = ToFilter(target.filter, mapping.ChildMappings[index])
-				}
+				// The filter must be converted using the mapping of the new join, as the joins of
+				// the same name selected under other aliases may map their children differently.
+				convertedFilter := ToFilter(target.filter, mapping.ChildMappings[index])
 
 				dummyJoin := &Select{
 					Targetable: Targetable{
@@ -296,14 +319,14 @@ func resolveAggregates(
 				}
 			}
 
-			if target.childExternalName != "" {
+			if childName != "" {
 				hostSelect, isHostSelectable := host.AsSelect()
 				if !isHostSelectable {
 					// I believe this is dead code as the gql library should always catch this error first
 					return nil, client.NewErrUnhandledType("host", host)
 				}
 
-				if len(hostSelect.IndexesByName[target.childExternalName]) == 0 {
+				if len(hostSelect.IndexesByName[childName]) == 0 {
 					// I believe this is dead code as the gql library should always catch this error first
 					return nil, ErrUnableToIdAggregateChild
 				}
@@ -312,8 +335,8 @@ func resolveAggregates(
 					// If there are multiple children of the same name there is no way
 					// for us (or the consumer) to identify which one they are hoping for
 					// so we take the first.
-					Index:    hostSelect.IndexesByName[target.childExternalName][0],
-					Name:     target.childExternalName,
+					Index:    hostSelect.IndexesByName[childName][0],
+					Name:     childName,
 					HasValue: true,
 				}
 			}
@@ -321,6 +344,8 @@ func resolveAggregates(
 			aggregateTargets[i] = AggregateTarget{
 				Targetable:  *hostTarget,
 				ChildTarget: childTarget,
+				IsNested:    len(target.childPath) > 0,
+				Window:      target.window,
 			}
 		}
 
@@ -344,6 +369,42 @@ func resolveAggregates(
 	return fields, nil
 }
 
+// toInnerAggregateRequest returns the aggregate, of the given name, that aggregates the
+// given nested target for each of the items of its host.
+//
+// The host's items can then be aggregated using their inner aggregate, for example
+// `_sum(published: {field: {reviews: rating}})` is the sum of the
+// `_sum(reviews: {field: rating})` of each of the published items.
+func toInnerAggregateRequest(name string, target *aggregateRequestTarget) *request.Aggregate {
+	innerTarget := &request.AggregateTarget{
+		HostName:  target.childPath[0],
+		ChildName: immutable.Some(target.childExternalName),
+		ChildPath: target.childPath[1:],
+	}
+
+	if name == request.CountFieldName && len(innerTarget.ChildPath) == 0 {
+		// Counts target the items themselves, of which only those with a value are counted,
+		// as nil items are not aggregated.
+		innerTarget.ChildName = immutable.None[string]()
+		innerTarget.Filter = immutable.Some(
+			request.Filter{
+				Conditions: map[string]any{
+					target.childExternalName: map[string]any{
+						"_ne": nil,
+					},
+				},
+			},
+		)
+	}
+
+	return &request.Aggregate{
+		Field: request.Field{
+			Name: name,
+		},
+		Targets: []*request.AggregateTarget{innerTarget},
+	}
+}
+
 func mapAggregateNestedTargets(
 	target *aggregateRequestTarget,
 	hostSelectRequest *request.Select,
@@ -405,7 +466,7 @@ var aggregateDependencies = map[string][]string{
 //
 // It will try and make use of existing aggregates that match the targeting parameters
 // before creating new ones.  It will also adjust the target filters if required (e.g.
-// average skips nil items).
+// average and running aggregates skip nil items).
 func appendUnderlyingAggregates(
 	inputAggregates []*aggregateRequest,
 	mapping *core.DocumentMapping,
@@ -417,24 +478,19 @@ func appendUnderlyingAggregates(
 	for i := 0; i < len(aggregates); i++ {
 		aggregate := aggregates[i]
 
+		if _, isRunning := request.RunningAggregates[aggregate.field.Name]; isRunning {
+			// Running aggregates have no dependencies, but skip nil items themselves.
+			appendNotNilFilters(aggregate.targets)
+			continue
+		}
+
 		dependencies, hasDependencies := aggregateDependencies[aggregate.field.Name]
 		// If the aggregate has no dependencies, then we don't need to do anything and we continue.
 		if !hasDependencies {
 			continue
 		}
 
-		for _, target := range aggregate.targets {
-			if target.childExternalName != "" {
-				if _, isAggregate := request.Aggregates[target.childExternalName]; isAggregate {
-					continue
-				}
-			}
-			// Append a not-nil filter if the target is not an aggregate.
-			// If the target has no childExternalName we assume it is an inline-array (and thus not an aggregate).
-			// Aggregate-targets are excluded here as they are assumed to always have a value and
-			// amending the filter introduces significant complexity for both machine and developer.
-			appendNotNilFilter(target, target.childExternalName)
-		}
+		appendNotNilFilters(aggregate.targets)
 
 		for _, dependencyName := range dependencies {
 			var newAggregate *aggregateRequest
@@ -450,6 +506,27 @@ func appendUnderlyingAggregates(
 	return aggregates
 }
 
+// appendNotNilFilters appends a not nil filter for the aggregated child of each of the given
+// targets, apart from those aggregating an aggregate.
+func appendNotNilFilters(targets []*aggregateRequestTarget) {
+	for _, target := range targets {
+		if target.childExternalName != "" {
+			if _, isAggregate := request.Aggregates[target.childExternalName]; isAggregate {
+				continue
+			}
+		}
+		if len(target.childPath) > 0 {
+			// Nested targets are aggregated by inner aggregates, which skip nil items themselves.
+			continue
+		}
+		// Append a not-nil filter if the target is not an aggregate.
+		// If the target has no childExternalName we assume it is an inline-array (and thus not an aggregate).
+		// Aggregate-targets are excluded here as they are assumed to always have a value and
+		// amending the filter introduces significant complexity for both machine and developer.
+		appendNotNilFilter(target, target.childExternalName)
+	}
+}
+
 // appendIfNotExists attempts to match the given name and targets against existing
 // aggregates, if a match is not found, it will append a new aggregate.
 func appendIfNotExists(
@@ -584,10 +661,16 @@ func getCollectionName(
 
 	if selectRequest.Name == request.GroupFieldName {
 		return parentCollectionName, nil
-	} else if selectRequest.Root == request.CommitSelection {
+	} else if selectRequest.Root == request.CommitSelection ||
+		selectRequest.Root == request.HistorySelection ||
+		selectRequest.Root == request.MetaSelection {
 		return parentCollectionName, nil
 	}
 
+	if selectRequest.Join.HasValue() {
+		return selectRequest.Join.Value().Collection, nil
+	}
+
 	if parentCollectionName != "" {
 		parentDescription, err := descriptionsRepo.getCollectionDesc(parentCollectionName)
 		if err != nil {
@@ -642,11 +725,29 @@ func getTopLevelInfo(
 		mapping.SetTypeName(collectionName)
 
 		mapping.Add(mapping.GetNextIndex(), request.DeletedFieldName)
+		mapping.Add(mapping.GetNextIndex(), request.CreatedAtFieldName)
+		mapping.Add(mapping.GetNextIndex(), request.UpdatedAtFieldName)
 
 		return mapping, &desc, nil
 	}
 
-	if selectRequest.Name == request.LinksFieldName {
+	if selectRequest.Root == request.HistorySelection {
+		for i, f := range request.HistoryFields {
+			mapping.Add(i, f)
+		}
+
+		// Setting the type name must be done after adding the fields, as
+		// the typeName index is dynamic, but the field indexes are not
+		mapping.SetTypeName(request.HistoryTypeName)
+	} else if selectRequest.Root == request.MetaSelection {
+		for i, f := range request.MetaFields {
+			mapping.Add(i, f)
+		}
+
+		// Setting the type name must be done after adding the fields, as
+		// the typeName index is dynamic, but the field indexes are not
+		mapping.SetTypeName(request.MetaTypeName)
+	} else if selectRequest.Name == request.LinksFieldName {
 		for i, f := range request.LinksFields {
 			mapping.Add(i, f)
 		}
@@ -697,7 +798,25 @@ func resolveInnerFilterDependencies(
 	newFields := []Requestable{}
 
 	for key := range source {
-		if strings.HasPrefix(key, "_") && key != request.KeyFieldName {
+		if isFilterOperator(key) {
+			// The conditions within operators such as `_or` may also refer to relations, which
+			// must be joined as well.
+			for _, innerFilter := range innerFilters(source[key]) {
+				allFields := make([]Requestable, 0, len(existingFields)+len(newFields))
+				allFields = append(allFields, existingFields...)
+				allFields = append(allFields, newFields...)
+				innerFields, err := resolveInnerFilterDependencies(
+					descriptionsRepo,
+					parentCollectionName,
+					innerFilter,
+					mapping,
+					allFields,
+				)
+				if err != nil {
+					return nil, err
+				}
+				newFields = append(newFields, innerFields...)
+			}
 			continue
 		}
 
@@ -836,6 +955,8 @@ func ToCommitSelect(
 		FieldName: selectRequest.FieldName,
 		Depth:     selectRequest.Depth,
 		Cid:       selectRequest.Cid,
+
+		FieldNameFilter: selectRequest.FieldNameFilter,
 	}, nil
 }
 
@@ -857,13 +978,18 @@ func ToMutation(ctx context.Context, txn datastore.Txn, mutationRequest *request
 }
 
 func toTargetable(index int, selectRequest *request.Select, docMap *core.DocumentMapping) Targetable {
+	orderBy := toOrderBy(selectRequest.OrderBy, docMap)
+	if orderBy != nil {
+		orderBy.Collation = selectRequest.Collation
+	}
+
 	return Targetable{
 		Field:       toField(index, selectRequest),
 		DocKeys:     selectRequest.DocKeys,
 		Filter:      ToFilter(selectRequest.Filter, docMap),
 		Limit:       toLimit(selectRequest.Limit, selectRequest.Offset),
 		GroupBy:     toGroupBy(selectRequest.GroupBy, docMap),
-		OrderBy:     toOrderBy(selectRequest.OrderBy, docMap),
+		OrderBy:     orderBy,
 		ShowDeleted: selectRequest.ShowDeleted,
 	}
 }
@@ -895,6 +1021,33 @@ func ToFilter(source immutable.Option[request.Filter], mapping *core.DocumentMap
 	}
 }
 
+// isFilterOperator returns true if the given filter key is an operator rather than the name of a
+// property, the names of the properties not starting with an underscore besides those of the meta
+// fields that can be filtered by.
+func isFilterOperator(key string) bool {
+	if !strings.HasPrefix(key, "_") || key == request.KeyFieldName {
+		return false
+	}
+	_, isTimestamp := request.TimestampFields[key]
+	return !isTimestamp
+}
+
+// innerFilters returns the filters combined by the given operator clause, such as those of an
+// `_or` clause.
+func innerFilters(clause any) []map[string]any {
+	clauses, ok := clause.([]any)
+	if !ok {
+		return nil
+	}
+	filters := []map[string]any{}
+	for _, innerClause := range clauses {
+		if innerFilter, ok := innerClause.(map[string]any); ok {
+			filters = append(filters, innerFilter)
+		}
+	}
+	return filters
+}
+
 // toFilterMap converts a consumer-defined filter key-value into a filter clause
 // keyed by field index.
 //
@@ -904,7 +1057,7 @@ func toFilterMap(
 	sourceClause any,
 	mapping *core.DocumentMapping,
 ) (connor.FilterKey, any) {
-	if strings.HasPrefix(sourceKey, "_") && sourceKey != request.KeyFieldName {
+	if isFilterOperator(sourceKey) {
 		key := &Operator{
 			Operation: sourceKey,
 		}
@@ -1071,7 +1224,21 @@ func (s Targetable) equal(other Targetable) bool {
 		return false
 	}
 
-	return true
+	if !equalDocKeys(s.DocKeys, other.DocKeys) {
+		return false
+	}
+
+	return s.ShowDeleted == other.ShowDeleted
+}
+
+func equalDocKeys(x immutable.Option[[]string], y immutable.Option[[]string]) bool {
+	if x.HasValue() != y.HasValue() {
+		return false
+	}
+	if !x.HasValue() {
+		return true
+	}
+	return reflect.DeepEqual(x.Value(), y.Value())
 }
 
 func (l *Limit) equal(other *Limit) bool {
@@ -1148,7 +1315,7 @@ func (o *OrderBy) equal(other *OrderBy) bool {
 		return o == nil
 	}
 
-	if len(o.Conditions) != len(other.Conditions) {
+	if len(o.Conditions) != len(other.Conditions) || o.Collation != other.Collation {
 		return false
 	}
 
@@ -1203,6 +1370,10 @@ type aggregateRequestTarget struct {
 	// This name may match zero to many field names requested by the consumer.
 	childExternalName string
 
+	// The names of the relations leading from the items of the host to the child,
+	// if the child is not a property of the host's items. Optional.
+	childPath []string
+
 	// The aggregate filter specified by the consumer for this target. Optional.
 	filter immutable.Option[request.Filter]
 
@@ -1212,6 +1383,9 @@ type aggregateRequestTarget struct {
 	// The order in which items should be aggregated. Affects results when used with
 	// limit. Optional.
 	order immutable.Option[request.OrderBy]
+
+	// The number of items aggregated by each value of a running aggregate. Optional.
+	window immutable.Option[uint64]
 }
 
 // Returns the source of the aggregate as requested by the consumer
@@ -1222,9 +1396,11 @@ func getAggregateSources(field *request.Aggregate) ([]*aggregateRequestTarget, e
 		targets[i] = &aggregateRequestTarget{
 			hostExternalName:  target.HostName,
 			childExternalName: target.ChildName.Value(),
+			childPath:         target.ChildPath,
 			filter:            target.Filter,
 			limit:             toLimit(target.Limit, target.Offset),
 			order:             target.OrderBy,
+			window:            target.Window,
 		}
 	}
 
@@ -1259,6 +1435,10 @@ collectionLoop:
 				continue collectionLoop
 			}
 
+			if !reflect.DeepEqual(target.childPath, potentialMatchingTarget.childPath) {
+				continue collectionLoop
+			}
+
 			if !target.filter.HasValue() && potentialMatchingTarget.filter.HasValue() {
 				continue collectionLoop
 			}
@@ -1316,6 +1496,124 @@ func tryGetTarget(
 	return nil, false
 }
 
+// tryGetJoinTarget scans the given collection of Requestables for a join that matches the name,
+// filter, limit and order of the given aggregate target.
+//
+// The joins of the same name may be selected more than once under different aliases, each
+// with its own arguments and child mapping, so the filter and order of the target are converted
+// using the mapping of each join, skipping those that don't map the properties they refer to.
+func tryGetJoinTarget(target *aggregateRequestTarget, collection []Requestable) (Requestable, bool) {
+	for _, field := range collection {
+		if field == nil {
+			continue
+		}
+		join, isSelect := field.AsSelect()
+		if !isSelect || join.Name != target.hostExternalName {
+			continue
+		}
+		if !canMapFilter(target.filter, &join.DocumentMapping) || !canMapOrderBy(target.order, &join.DocumentMapping) {
+			continue
+		}
+
+		host, hasHost := tryGetTarget(
+			target.hostExternalName,
+			ToFilter(target.filter, &join.DocumentMapping),
+			target.limit,
+			toOrderBy(target.order, &join.DocumentMapping),
+			[]Requestable{field},
+		)
+		if hasHost {
+			return host, true
+		}
+	}
+	return nil, false
+}
+
+// canMapFilter returns true if all the properties the given filter refers to are mapped by the
+// given mapping, in which case the filter can be converted using it.
+func canMapFilter(source immutable.Option[request.Filter], mapping *core.DocumentMapping) bool {
+	if !source.HasValue() {
+		return true
+	}
+	for key, clause := range source.Value().Conditions {
+		if !canMapFilterClause(key, clause, mapping) {
+			return false
+		}
+	}
+	return true
+}
+
+// canMapFilterClause returns true if all the properties the given filter key-value refers to are
+// mapped by the given mapping. It mirrors the conversion made by toFilterMap.
+func canMapFilterClause(sourceKey string, sourceClause any, mapping *core.DocumentMapping) bool {
+	if isFilterOperator(sourceKey) {
+		innerClauses, isArray := sourceClause.([]any)
+		if !isArray {
+			return true
+		}
+		for _, innerClause := range innerClauses {
+			innerMapClause, isMap := innerClause.(map[string]any)
+			if !isMap {
+				continue
+			}
+			for innerKey, innerValue := range innerMapClause {
+				if !canMapFilterClause(innerKey, innerValue, mapping) {
+					return false
+				}
+			}
+		}
+		return true
+	}
+
+	if len(mapping.IndexesByName[sourceKey]) == 0 {
+		return false
+	}
+	mapClause, isMap := sourceClause.(map[string]any)
+	if !isMap {
+		return true
+	}
+
+	index := mapping.FirstIndexOfName(sourceKey)
+	for innerKey, innerValue := range mapClause {
+		innerMapping := mapping
+		if _, isInnerMap := innerValue.(map[string]any); isInnerMap {
+			if index >= len(mapping.ChildMappings) || mapping.ChildMappings[index] == nil {
+				return false
+			}
+			innerMapping = mapping.ChildMappings[index]
+		}
+		if !canMapFilterClause(innerKey, innerValue, innerMapping) {
+			return false
+		}
+	}
+	return true
+}
+
+// canMapOrderBy returns true if all the properties the given order refers to are mapped by the
+// given mapping, in which case the order can be converted using it.
+func canMapOrderBy(source immutable.Option[request.OrderBy], mapping *core.DocumentMapping) bool {
+	if !source.HasValue() {
+		return true
+	}
+	for _, condition := range source.Value().Conditions {
+		currentMapping := mapping
+		for i, field := range condition.Fields {
+			if len(currentMapping.IndexesByName[field]) == 0 {
+				return false
+			}
+			if i == len(condition.Fields)-1 {
+				break
+			}
+			index := currentMapping.FirstIndexOfName(field)
+			if index >= len(currentMapping.ChildMappings) || currentMapping.ChildMappings[index] == nil {
+				return false
+			}
+			currentMapping = currentMapping.ChildMappings[index]
+		}
+	}
+	return true
+}
+
 // appendNotNilFilter appends a not nil filter for the given child field
 // to the given Select.
 func appendNotNilFilter(field *aggregateRequestTarget, childField string) {
@@ -11,8 +11,11 @@
 package mapper
 
 import (
+	"time"
+
 	"github.com/sourcenetwork/immutable"
 
+	"github.com/sourcenetwork/defradb/client/request"
 	"github.com/sourcenetwork/defradb/core"
 )
 
@@ -30,6 +33,9 @@ type Select struct {
 	// A commit identifier that can be specified to request data at a given time.
 	Cid immutable.Option[string]
 
+	// A time that can be specified to request the documents as they were at that time.
+	AtTime immutable.Option[time.Time]
+
 	// The name of the collection that this Select selects data from.
 	CollectionName string
 
@@ -38,6 +44,22 @@ type Select struct {
 	// These can include stuff such as version information, aggregates, and other
 	// Selects.
 	Fields []Requestable
+
+	// The number of levels, including this one, to which this self-referencing relation
+	// is expanded, if it is the level of a recursive traversal.
+	Depth immutable.Option[uint64]
+
+	// Whether the host documents are returned when the related document of this to-one
+	// relation is missing. They are returned unless it is set to false.
+	IncludeMissing immutable.Option[bool]
+
+	// The fields this collection is joined on with the parent collection, if this Select is
+	// an ad-hoc join rather than a relation.
+	Join immutable.Option[request.Join]
+
+	// The name of the field whose history of values this Select yields, if this Select is the
+	// history of a field.
+	HistoryField immutable.Option[string]
 }
 
 func (s *Select) AsTargetable() (*Targetable, bool) {
@@ -57,8 +79,13 @@ func (s *Select) cloneTo(index int) *Select {
 		Targetable:      *s.Targetable.cloneTo(index),
 		DocumentMapping: s.DocumentMapping,
 		Cid:             s.Cid,
+		AtTime:          s.AtTime,
 		CollectionName:  s.CollectionName,
 		Fields:          s.Fields,
+		Depth:           s.Depth,
+		IncludeMissing:  s.IncludeMissing,
+		Join:            s.Join,
+		HistoryField:    s.HistoryField,
 	}
 }
 
@@ -13,6 +13,7 @@ package mapper
 import (
 	"github.com/sourcenetwork/immutable"
 
+	"github.com/sourcenetwork/defradb/client/request"
 	"github.com/sourcenetwork/defradb/connor"
 	"github.com/sourcenetwork/defradb/core"
 )
@@ -124,6 +125,9 @@ type OrderCondition struct {
 
 type OrderBy struct {
 	Conditions []OrderCondition
+
+	// The collation with which strings are compared, if not by their bytes.
+	Collation immutable.Option[request.Collation]
 }
 
 // Targetable represents a targetable property.
@@ -0,0 +1,205 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package planner
+
+import (
+	dag "github.com/ipfs/boxo/ipld/merkledag"
+	cid "github.com/ipfs/go-cid"
+	dsq "github.com/ipfs/go-datastore/query"
+
+	"github.com/sourcenetwork/defradb/client/request"
+	"github.com/sourcenetwork/defradb/core"
+	corecrdt "github.com/sourcenetwork/defradb/core/crdt"
+	"github.com/sourcenetwork/defradb/planner/mapper"
+)
+
+// metaNode is the plan node yielding the metadata of a document, by walking its composite DAG
+// from its heads.
+//
+// It is appended to the documents of its parent, which give it their key through its spans, and
+// yields a single document.
+type metaNode struct {
+	documentIterator
+	docMapper
+
+	p *Planner
+
+	docKey string
+	done   bool
+
+	execInfo metaExecInfo
+}
+
+type metaExecInfo struct {
+	// Total number of commits walked.
+	commits uint64
+}
+
+// Meta returns the plan node of the given metadata selection.
+func (p *Planner) Meta(metaSelect *mapper.Select) *metaNode {
+	return &metaNode{
+		p:         p,
+		docMapper: docMapper{&metaSelect.DocumentMapping},
+	}
+}
+
+func (n *metaNode) Kind() string {
+	return "metaNode"
+}
+
+func (n *metaNode) Init() error {
+	n.done = true
+	if n.docKey == "" {
+		return nil
+	}
+
+	heads, err := n.heads()
+	if err != nil {
+		return err
+	}
+	if len(heads) == 0 {
+		return nil
+	}
+
+	var (
+		latest   *corecrdt.CompositeDAGDelta
+		count    int64
+		visited  = map[cid.Cid]struct{}{}
+		queue    = heads
+		headCIDs = make([]string, len(heads))
+	)
+	for i, head := range heads {
+		headCIDs[i] = head.String()
+	}
+
+	for len(queue) > 0 {
+		c := queue[0]
+		queue = queue[1:]
+		if _, ok := visited[c]; ok {
+			continue
+		}
+		visited[c] = struct{}{}
+		count++
+		n.execInfo.commits++
+
+		block, err := n.p.txn.DAGstore().Get(n.p.ctx, c)
+		if err != nil {
+			return err
+		}
+		nd, err := dag.DecodeProtobuf(block.RawData())
+		if err != nil {
+			return err
+		}
+		delta, err := corecrdt.CompositeDAG{}.DeltaDecodeWithoutData(nd)
+		if err != nil {
+			return err
+		}
+		compositeDelta := delta.(*corecrdt.CompositeDAGDelta)
+		// The status and schema version are those of the highest commit.
+		if latest == nil || compositeDelta.Priority > latest.Priority {
+			latest = compositeDelta
+		}
+
+		for _, link := range nd.Links() {
+			if link.Name == core.HEAD {
+				queue = append(queue, link.Cid)
+			}
+		}
+	}
+
+	n.currentValue = n.documentMapping.NewDoc()
+	n.documentMapping.SetFirstOfName(&n.currentValue, request.HeadsFieldName, headCIDs)
+	n.documentMapping.SetFirstOfName(&n.currentValue, request.SchemaVersionIDFieldName, latest.SchemaVersionID)
+	n.documentMapping.SetFirstOfName(&n.currentValue, request.DeletedStatusFieldName, latest.Status.IsDeleted())
+	n.documentMapping.SetFirstOfName(&n.currentValue, request.DeltaCountFieldName, count)
+	n.done = false
+
+	return nil
+}
+
+// heads returns the heads of the composite DAG of the document, ordered by CID.
+func (n *metaNode) heads() ([]cid.Cid, error) {
+	prefix := core.HeadStoreKey{DocKey: n.docKey, FieldId: core.COMPOSITE_NAMESPACE}
+	q, err := n.p.txn.Headstore().Query(n.p.ctx, dsq.Query{
+		Prefix:   prefix.ToString() + "/",
+		KeysOnly: true,
+		Orders:   []dsq.Order{dsq.OrderByKey{}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	entries, err := q.Rest()
+	if closeErr := q.Close(); closeErr != nil {
+		return nil, closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	heads := make([]cid.Cid, 0, len(entries))
+	for _, entry := range entries {
+		key, err := core.NewHeadStoreKey(entry.Key)
+		if err != nil {
+			return nil, err
+		}
+		heads = append(heads, key.Cid)
+	}
+	return heads, nil
+}
+
+func (n *metaNode) Start() error {
+	return nil
+}
+
+// Spans sets the document whose metadata is yielded, which is the only value of the given spans.
+func (n *metaNode) Spans(spans core.Spans) {
+	if len(spans.Value) == 0 {
+		return
+	}
+	n.docKey = spans.Value[0].Start().DocKey
+}
+
+func (n *metaNode) Next() (bool, error) {
+	if n.done {
+		return false, nil
+	}
+	n.done = true
+	return true, nil
+}
+
+func (n *metaNode) Close() error {
+	return nil
+}
+
+func (n *metaNode) Source() planNode { return nil }
+
+func (n *metaNode) Append() bool { return true }
+
+// Single returns true, as the metadata of a document is appended as an object rather than as
+// a list.
+func (n *metaNode) Single() bool { return true }
+
+// Explain method returns a map containing all attributes of this node that
+// are to be explained, subscribes / opts-in this node to be an explainablePlanNode.
+func (n *metaNode) Explain(explainType request.ExplainType) (map[string]any, error) {
+	switch explainType {
+	case request.SimpleExplain:
+		return map[string]any{}, nil
+
+	case request.ExecuteExplain:
+		return map[string]any{
+			"commits": n.execInfo.commits,
+		}, nil
+
+	default:
+		return nil, ErrUnknownExplainRequestType
+	}
+}
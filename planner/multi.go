@@ -49,6 +49,13 @@ type appendNode interface {
 	Append() bool
 }
 
+// singleNode is an appendNode yielding at most one document, which is appended
+// as an object rather than as a list.
+type singleNode interface {
+	appendNode
+	Single() bool
+}
+
 // parallelNode implements the MultiNode interface. It
 // enables parallel execution of planNodes. This is needed
 // if a single request has multiple Select statements at the
@@ -231,6 +238,15 @@ func (p *parallelNode) nextAppend(index int, plan appendNode) (bool, error) {
 
 		results = append(results, plan.Value())
 	}
+
+	if single, ok := plan.(singleNode); ok && single.Single() {
+		if len(results) == 0 {
+			p.currentValue.Fields[p.childIndexes[index]] = nil
+		} else {
+			p.currentValue.Fields[p.childIndexes[index]] = results[0]
+		}
+		return true, nil
+	}
 	p.currentValue.Fields[p.childIndexes[index]] = results
 	return true, nil
 }
@@ -272,7 +288,13 @@ _version: commitSelectTopNode(append)
 ]
 */
 
-func (p *parallelNode) Source() planNode { return p.multiscan }
+func (p *parallelNode) Source() planNode {
+	if p.multiscan == nil {
+		// avoid returning a typed nil, which would not compare equal to nil
+		return nil
+	}
+	return p.multiscan
+}
 
 func (p *parallelNode) Children() []planNode {
 	return p.children
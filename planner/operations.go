@@ -17,11 +17,14 @@ var (
 	_ planNode = (*dagScanNode)(nil)
 	_ planNode = (*deleteNode)(nil)
 	_ planNode = (*groupNode)(nil)
+	_ planNode = (*historyNode)(nil)
 	_ planNode = (*limitNode)(nil)
+	_ planNode = (*metaNode)(nil)
 	_ planNode = (*multiScanNode)(nil)
 	_ planNode = (*orderNode)(nil)
 	_ planNode = (*parallelNode)(nil)
 	_ planNode = (*pipeNode)(nil)
+	_ planNode = (*runningAggregateNode)(nil)
 	_ planNode = (*scanNode)(nil)
 	_ planNode = (*selectNode)(nil)
 	_ planNode = (*selectTopNode)(nil)
@@ -30,6 +33,7 @@ var (
 	_ planNode = (*typeIndexJoin)(nil)
 	_ planNode = (*typeJoinMany)(nil)
 	_ planNode = (*typeJoinOne)(nil)
+	_ planNode = (*typeJoinOn)(nil)
 	_ planNode = (*updateNode)(nil)
 	_ planNode = (*valuesNode)(nil)
 
@@ -11,6 +11,8 @@
 package planner
 
 import (
+	"golang.org/x/text/collate"
+
 	"github.com/sourcenetwork/defradb/client"
 	"github.com/sourcenetwork/defradb/client/request"
 	"github.com/sourcenetwork/defradb/core"
@@ -46,6 +48,9 @@ type orderNode struct {
 	plan planNode
 
 	ordering []mapper.OrderCondition
+	// collator compares the strings by the requested collation, or is nil if they are
+	// compared by their bytes.
+	collator *collate.Collator
 
 	// simplified planNode interface
 	// used for iterating through
@@ -81,6 +86,7 @@ func (p *Planner) OrderBy(parsed *mapper.Select, n *mapper.OrderBy) (*orderNode,
 	return &orderNode{
 		p:         p,
 		ordering:  n.Conditions,
+		collator:  newCollator(n.Collation),
 		needSort:  true,
 		docMapper: docMapper{&parsed.DocumentMapping},
 	}, nil
@@ -157,7 +163,7 @@ func (n *orderNode) Next() (bool, error) {
 	for n.needSort {
 		// make sure our orderStrategy is initialized
 		if n.orderStrategy == nil {
-			v := n.p.newContainerValuesNode(n.ordering)
+			v := n.p.newContainerValuesNode(n.ordering, n.collator)
 			n.orderStrategy = newAllSortStrategy(v)
 		}
 
@@ -12,11 +12,13 @@ package planner
 
 import (
 	"context"
+	"strings"
 
 	"github.com/sourcenetwork/defradb/client"
 	"github.com/sourcenetwork/defradb/client/request"
 	"github.com/sourcenetwork/defradb/core"
 	"github.com/sourcenetwork/defradb/datastore"
+	"github.com/sourcenetwork/defradb/db/fetcher"
 	"github.com/sourcenetwork/defradb/logging"
 	"github.com/sourcenetwork/defradb/planner/mapper"
 )
@@ -89,16 +91,39 @@ type Planner struct {
 	db  client.Store
 
 	ctx context.Context
+
+	// onPlan is called with a summary of the plan once it has been made.
+	onPlan func(summary string)
+
+	// docCache is the cache of the recently fetched documents, if enabled.
+	docCache *fetcher.DocumentCache
+
+	// recursionPaths holds the paths of the recursive traversals being planned, by the
+	// next level of the traversal to be planned.
+	recursionPaths map[*mapper.Select]*recursionPath
 }
 
 func New(ctx context.Context, db client.Store, txn datastore.Txn) *Planner {
 	return &Planner{
-		txn: txn,
-		db:  db,
-		ctx: ctx,
+		txn:            txn,
+		db:             db,
+		ctx:            ctx,
+		recursionPaths: map[*mapper.Select]*recursionPath{},
 	}
 }
 
+// OnPlan registers a function that is called with a summary of the plan
+// (e.g. `selectTopNode -> selectNode -> scanNode`) once it has been made by RunRequest.
+func (p *Planner) OnPlan(fn func(summary string)) {
+	p.onPlan = fn
+}
+
+// SetDocumentCache sets the cache of the recently fetched documents that the documents
+// are read from.
+func (p *Planner) SetDocumentCache(cache *fetcher.DocumentCache) {
+	p.docCache = cache
+}
+
 func (p *Planner) newPlan(stmt any) (planNode, error) {
 	switch n := stmt.(type) {
 	case *request.Request:
@@ -126,9 +151,16 @@ func (p *Planner) newPlan(stmt any) (planNode, error) {
 			// If this Select is an aggregate, then it must be a top-level
 			// aggregate and we need to resolve it within the context of a
 			// top-level node.
+			p.debugPlan(
+				planStepSelect,
+				"Planning a top-level node",
+				logging.NewKV("Aggregate", n.Name),
+				logging.NewKV("Reason", "the request is a top-level aggregate"),
+			)
 			return p.Top(m)
 		}
 
+		p.debugPlan(planStepSelect, "Planning a select node", logging.NewKV("Collection", m.CollectionName))
 		return p.Select(m)
 
 	case *request.CommitSelect:
@@ -136,6 +168,7 @@ func (p *Planner) newPlan(stmt any) (planNode, error) {
 		if err != nil {
 			return nil, err
 		}
+		p.debugPlan(planStepSelect, "Planning a commit select node", logging.NewKV("Name", n.Name))
 		return p.CommitSelect(m)
 
 	case *request.ObjectMutation:
@@ -143,6 +176,12 @@ func (p *Planner) newPlan(stmt any) (planNode, error) {
 		if err != nil {
 			return nil, err
 		}
+		p.debugPlan(
+			planStepSelect,
+			"Planning a mutation node",
+			logging.NewKV("Collection", m.CollectionName),
+			logging.NewKV("Mutation", n.Name),
+		)
 		return p.newObjectMutationPlan(m)
 	}
 
@@ -174,11 +213,15 @@ func (p *Planner) makePlan(stmt any) (planNode, error) {
 		return nil, err
 	}
 
+	p.debugPlan(planStepOptimize, "Optimizing the plan", logging.NewKV("Plan", summarizePlan(planNode)))
+
 	err = p.optimizePlan(planNode)
 	if err != nil {
 		return nil, err
 	}
 
+	p.debugPlan(planStepOptimize, "Optimized the plan", logging.NewKV("Plan", summarizePlan(planNode)))
+
 	err = planNode.Init()
 	return planNode, err
 }
@@ -201,6 +244,18 @@ func (p *Planner) expandPlan(planNode planNode, parentPlan *selectTopNode) error
 	case *typeIndexJoin:
 		return p.expandTypeIndexJoinPlan(n, parentPlan)
 
+	case *unionNode:
+		for _, branch := range n.branches {
+			if branch.plan == nil {
+				continue
+			}
+			// The plans of the branches select other documents, so they have no parent plan.
+			if err := p.expandPlan(branch.plan, nil); err != nil {
+				return err
+			}
+		}
+		return p.expandPlan(n.source, parentPlan)
+
 	case *groupNode:
 		for _, dataSource := range n.dataSources {
 			// We only care about expanding the child source here, it is assumed that the parent source
@@ -256,6 +311,12 @@ func (p *Planner) expandSelectTopNodePlan(plan *selectTopNode, parentPlan *selec
 
 	// if group
 	if plan.group != nil {
+		p.debugPlan(
+			planStepExpand,
+			"Wiring a group node",
+			logging.NewKV("GroupBy", groupByFieldNames(plan.group)),
+			logging.NewKV("Reason", "the request groups the documents"),
+		)
 		err := p.expandGroupNodePlan(plan)
 		if err != nil {
 			return err
@@ -267,6 +328,11 @@ func (p *Planner) expandSelectTopNodePlan(plan *selectTopNode, parentPlan *selec
 
 	// if order
 	if plan.order != nil {
+		p.debugPlan(
+			planStepExpand,
+			"Wiring an order node",
+			logging.NewKV("Reason", "the request orders the documents"),
+		)
 		plan.order.plan = plan.planNode
 		plan.planNode = plan.order
 	}
@@ -288,6 +354,12 @@ func (p *Planner) expandAggregatePlans(plan *selectTopNode) {
 	// execute *before* any aggregate dependent on them.
 	for i := len(plan.aggregates) - 1; i >= 0; i-- {
 		aggregate := plan.aggregates[i]
+		p.debugPlan(
+			planStepExpand,
+			"Wiring an aggregate node",
+			logging.NewKV("Kind", aggregate.Kind()),
+			logging.NewKV("Reason", "the aggregate is computed from the documents it is wired after"),
+		)
 		aggregate.SetPlan(plan.planNode)
 		plan.planNode = aggregate
 	}
@@ -308,6 +380,8 @@ func (p *Planner) expandTypeIndexJoinPlan(plan *typeIndexJoin, parentPlan *selec
 		return p.expandPlan(node.subType, parentPlan)
 	case *typeJoinMany:
 		return p.expandPlan(node.subType, parentPlan)
+	case *typeJoinOn:
+		return p.expandPlan(node.subType, parentPlan)
 	}
 	return client.NewErrUnhandledType("join plan", plan.joinPlan)
 }
@@ -325,6 +399,23 @@ func (p *Planner) expandGroupNodePlan(topNodeSelect *selectTopNode) error {
 		}
 		sourceNode = commitNode
 	}
+	p.debugPlan(
+		planStepExpand,
+		"Using the first scan of the plan as the group source",
+		logging.NewKV("Kind", sourceNode.Kind()),
+	)
+
+	if topNodeSelect.group.streamed {
+		p.debugPlan(
+			planStepExpand,
+			"Aggregating the groups as the documents are read",
+			logging.NewKV("Reason", "the documents of the groups are only counted or summed"),
+		)
+		// The documents are not retained by a pipe for the child selects, as they are folded into
+		// the aggregates of their group as they are read.
+		topNodeSelect.group.dataSources[0].parentSource = topNodeSelect.planNode
+		return nil
+	}
 
 	// Check for any existing pipe nodes in the topNodeSelect, we should use it if there is one
 	pipe, hasPipe := walkAndFindPlanType[*pipeNode](topNodeSelect.planNode)
@@ -380,12 +471,82 @@ func (p *Planner) expandLimitPlan(topNodeSelect *selectTopNode, parentPlan *sele
 	// Limits get more complicated with groups and have to be handled internally, so we ensure
 	// any limit topNodeSelect is disabled here
 	if parentPlan != nil && parentPlan.group != nil && len(parentPlan.group.childSelects) != 0 {
+		p.debugPlan(
+			planStepExpand,
+			"Dropping a limit node",
+			logging.NewKV("Reason", "the limit is applied by the parent group node"),
+		)
 		topNodeSelect.limit = nil
 		return
 	}
 
+	p.debugPlan(
+		planStepExpand,
+		"Wiring a limit node",
+		logging.NewKV("Limit", topNodeSelect.limit.limit),
+		logging.NewKV("Offset", topNodeSelect.limit.offset),
+	)
 	topNodeSelect.limit.plan = topNodeSelect.planNode
 	topNodeSelect.planNode = topNodeSelect.limit
+
+	if scan, ok := limitPushDownScan(topNodeSelect); ok {
+		p.debugPlan(
+			planStepExpand,
+			"Pushing the limit down to the scan node",
+			logging.NewKV("Reason", "the documents out of the limit are not joined"),
+		)
+		scan.limit = topNodeSelect.limit.limit
+		scan.offset = topNodeSelect.limit.offset
+		topNodeSelect.limit.pushedDown = true
+	}
+}
+
+// limitPushDownScan returns the scan node the limit of the given select can be pushed down to, so
+// that the joins stop once the limit is reached instead of joining the documents out of it.
+//
+// The limit can only be pushed down if the documents of the scan are joined, and are neither
+// filtered, ordered nor grouped once joined, nor dropped by the joins.
+func limitPushDownScan(topNodeSelect *selectTopNode) (*scanNode, bool) {
+	if topNodeSelect.group != nil || topNodeSelect.order != nil {
+		return nil, false
+	}
+	slct := topNodeSelect.selectNode
+	if slct.filter != nil && len(slct.filter.Conditions) != 0 {
+		return nil, false
+	}
+	// The spans of the requested documents may be replaced, in which case they are filtered
+	// once joined.
+	if slct.selectReq.DocKeys.HasValue() {
+		return nil, false
+	}
+	if _, isScan := slct.source.(*scanNode); isScan {
+		return nil, false
+	}
+	if dropsRootDocs(slct.source) {
+		return nil, false
+	}
+	return walkAndFindPlanType[*scanNode](slct.source)
+}
+
+// dropsRootDocs returns true if any join of the given plan drops the root documents whose related
+// document is missing.
+func dropsRootDocs(plan planNode) bool {
+	for plan != nil {
+		switch node := plan.(type) {
+		case MultiNode:
+			for _, child := range node.Children() {
+				if dropsRootDocs(child) {
+					return true
+				}
+			}
+		case *typeJoinOne:
+			if !node.includeMissing {
+				return true
+			}
+		}
+		plan = plan.Source()
+	}
+	return false
 }
 
 // walkAndReplace walks through the provided plan, and searches for an instance
@@ -411,6 +572,8 @@ func (p *Planner) walkAndReplacePlan(planNode, target, replace planNode) error {
 		node.root = replace
 	case *typeJoinMany:
 		node.root = replace
+	case *typeJoinOn:
+		node.root = replace
 	case *pipeNode:
 		/* Do nothing - pipe nodes should not be replaced */
 	// @todo: add more nodes that apply here
@@ -456,6 +619,10 @@ func (p *Planner) executeRequest(
 	docMap := planNode.DocumentMap()
 
 	for hasNext {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
 		copy := docMap.ToMap(planNode.Value())
 		docs = append(docs, copy)
 
@@ -483,20 +650,29 @@ func (p *Planner) RunRequest(
 		}
 	}()
 
+	if p.onPlan != nil {
+		p.onPlan(summarizePlan(planNode))
+	}
+
 	// Ensure subscription request doesn't ever end up with an explain directive.
 	if len(req.Subscription) > 0 && req.Subscription[0].Directives.ExplainType.HasValue() {
 		return nil, ErrCantExplainSubscriptionRequest
 	}
 
 	if len(req.Queries) > 0 && req.Queries[0].Directives.ExplainType.HasValue() {
-		return p.explainRequest(ctx, planNode, req.Queries[0].Directives.ExplainType.Value())
+		explainType := req.Queries[0].Directives.ExplainType.Value()
+		p.debugPlan(planStepRun, "Explaining the plan", logging.NewKV("ExplainType", explainType))
+		return p.explainRequest(ctx, planNode, explainType)
 	}
 
 	if len(req.Mutations) > 0 && req.Mutations[0].Directives.ExplainType.HasValue() {
-		return p.explainRequest(ctx, planNode, req.Mutations[0].Directives.ExplainType.Value())
+		explainType := req.Mutations[0].Directives.ExplainType.Value()
+		p.debugPlan(planStepRun, "Explaining the plan", logging.NewKV("ExplainType", explainType))
+		return p.explainRequest(ctx, planNode, explainType)
 	}
 
 	// This won't / should NOT execute if it's any kind of explain request.
+	p.debugPlan(planStepRun, "Executing the plan")
 	return p.executeRequest(ctx, planNode)
 }
 
@@ -528,3 +704,21 @@ func (p *Planner) RunSubscriptionRequest(
 func (p *Planner) MakePlan(request *request.Request) (planNode, error) {
 	return p.makePlan(request)
 }
+
+// summarizePlan returns the kinds of the given plan node and its sources, outermost first.
+func summarizePlan(plan planNode) string {
+	kinds := []string{}
+	for node := plan; node != nil; node = node.Source() {
+		kinds = append(kinds, node.Kind())
+	}
+	return strings.Join(kinds, " -> ")
+}
+
+// groupByFieldNames returns the names of the fields the given group node groups by.
+func groupByFieldNames(group *groupNode) []string {
+	names := make([]string, len(group.groupByFields))
+	for i, field := range group.groupByFields {
+		names[i] = field.Name
+	}
+	return names
+}
@@ -0,0 +1,87 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package planner
+
+import (
+	"github.com/sourcenetwork/defradb/planner/mapper"
+)
+
+// recursionPath is the path of the documents being expanded by the levels of a recursive
+// traversal of a self-referencing relation, requested using the depth argument.
+//
+// The levels of a traversal are executed depth first, each level adding the document it is
+// joining to the path for the duration of the join. The path thus holds the ancestors of the
+// documents yielded by the level being executed, and any of those documents that is on the path
+// is part of a cycle and is not returned.
+type recursionPath struct {
+	docKeys map[string]struct{}
+}
+
+func newRecursionPath() *recursionPath {
+	return &recursionPath{
+		docKeys: map[string]struct{}{},
+	}
+}
+
+// enter adds the document with the given key to the path, returning false if it is already on it.
+func (p *recursionPath) enter(docKey string) bool {
+	if _, ok := p.docKeys[docKey]; ok {
+		return false
+	}
+	p.docKeys[docKey] = struct{}{}
+	return true
+}
+
+// leave removes the document with the given key from the path.
+func (p *recursionPath) leave(docKey string) {
+	delete(p.docKeys, docKey)
+}
+
+// contains returns true if the document with the given key is on the path.
+//
+// It is safe to call on a nil path, which contains no documents.
+func (p *recursionPath) contains(docKey string) bool {
+	if p == nil {
+		return false
+	}
+	_, ok := p.docKeys[docKey]
+	return ok
+}
+
+// recursionPathOf returns the path of the recursive traversal the given select is a level of,
+// or nil if it isn't part of one.
+//
+// The path is registered for the next level of the traversal, if any, which must be planned
+// after this one.
+func (p *Planner) recursionPathOf(subType *mapper.Select) *recursionPath {
+	if !subType.Depth.HasValue() {
+		return nil
+	}
+
+	path, ok := p.recursionPaths[subType]
+	if ok {
+		delete(p.recursionPaths, subType)
+	} else {
+		path = newRecursionPath()
+	}
+
+	for _, field := range subType.Fields {
+		if field == nil {
+			continue
+		}
+		child, isSelect := field.AsSelect()
+		if isSelect && child.Name == subType.Name && child.Depth.HasValue() &&
+			child.Depth.Value() == subType.Depth.Value()-1 {
+			p.recursionPaths[child] = path
+		}
+	}
+	return path
+}
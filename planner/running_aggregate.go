@@ -0,0 +1,227 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package planner
+
+import (
+	"math"
+
+	"github.com/sourcenetwork/immutable"
+
+	"github.com/sourcenetwork/defradb/client/request"
+	"github.com/sourcenetwork/defradb/core"
+	"github.com/sourcenetwork/defradb/planner/mapper"
+)
+
+// runningAggregateNode computes the running sums or averages of the items of ordered child sets,
+// returning a value per item.
+type runningAggregateNode struct {
+	documentIterator
+	docMapper
+
+	p    *Planner
+	plan planNode
+
+	isAverage         bool
+	virtualFieldIndex int
+	aggregateMapping  []mapper.AggregateTarget
+
+	execInfo runningAggregateExecInfo
+}
+
+type runningAggregateExecInfo struct {
+	// Total number of times runningAggregateNode was executed.
+	iterations uint64
+}
+
+// RunningAggregate returns a new node computing the given running aggregate.
+func (p *Planner) RunningAggregate(field *mapper.Aggregate) (*runningAggregateNode, error) {
+	return &runningAggregateNode{
+		p:                 p,
+		isAverage:         field.Name == request.RunningAverageFieldName,
+		aggregateMapping:  field.AggregateTargets,
+		virtualFieldIndex: field.Index,
+		docMapper:         docMapper{&field.DocumentMapping},
+	}, nil
+}
+
+func (n *runningAggregateNode) Kind() string {
+	return "runningAggregateNode"
+}
+
+func (n *runningAggregateNode) Init() error {
+	return n.plan.Init()
+}
+
+func (n *runningAggregateNode) Start() error { return n.plan.Start() }
+
+func (n *runningAggregateNode) Spans(spans core.Spans) { n.plan.Spans(spans) }
+
+func (n *runningAggregateNode) Close() error { return n.plan.Close() }
+
+func (n *runningAggregateNode) Source() planNode { return n.plan }
+
+func (n *runningAggregateNode) SetPlan(p planNode) { n.plan = p }
+
+func (n *runningAggregateNode) simpleExplain() (map[string]any, error) {
+	sourceExplanations := make([]map[string]any, len(n.aggregateMapping))
+
+	for i, source := range n.aggregateMapping {
+		simpleExplainMap := map[string]any{}
+
+		// Add the filter attribute if it exists.
+		if source.Filter == nil || source.Filter.ExternalConditions == nil {
+			simpleExplainMap[filterLabel] = nil
+		} else {
+			simpleExplainMap[filterLabel] = source.Filter.ExternalConditions
+		}
+
+		// Add the main field name.
+		simpleExplainMap[fieldNameLabel] = source.Field.Name
+
+		// Add the child field name if it exists.
+		if source.ChildTarget.HasValue {
+			simpleExplainMap[childFieldNameLabel] = source.ChildTarget.Name
+		} else {
+			simpleExplainMap[childFieldNameLabel] = nil
+		}
+
+		// Add the window if it exists.
+		if source.Window.HasValue() {
+			simpleExplainMap[request.WindowClause] = source.Window.Value()
+		} else {
+			simpleExplainMap[request.WindowClause] = nil
+		}
+
+		sourceExplanations[i] = simpleExplainMap
+	}
+
+	name := request.RunningSumFieldName
+	if n.isAverage {
+		name = request.RunningAverageFieldName
+	}
+	simpleExplainMap := map[string]any{
+		sourcesLabel: sourceExplanations,
+	}
+	if field := responseFieldOf(n.documentMapping, n.virtualFieldIndex, name); field != "" {
+		simpleExplainMap[responseFieldLabel] = field
+	}
+	return simpleExplainMap, nil
+}
+
+// Explain method returns a map containing all attributes of this node that
+// are to be explained, subscribes / opts-in this node to be an explainablePlanNode.
+func (n *runningAggregateNode) Explain(explainType request.ExplainType) (map[string]any, error) {
+	switch explainType {
+	case request.SimpleExplain:
+		return n.simpleExplain()
+
+	case request.ExecuteExplain:
+		return map[string]any{
+			"iterations": n.execInfo.iterations,
+		}, nil
+
+	default:
+		return nil, ErrUnknownExplainRequestType
+	}
+}
+
+func (n *runningAggregateNode) Next() (bool, error) {
+	n.execInfo.iterations++
+
+	hasNext, err := n.plan.Next()
+	if err != nil || !hasNext {
+		return hasNext, err
+	}
+
+	n.currentValue = n.plan.Value()
+
+	values := []float64{}
+	for _, source := range n.aggregateMapping {
+		childCollection, isDocs := n.currentValue.Fields[source.Index].([]core.Doc)
+		if !isDocs {
+			continue
+		}
+
+		items := make([]float64, 0, len(childCollection))
+		for _, childItem := range childCollection {
+			// Hidden items are skipped (a grouping mechanic).
+			if childItem.Hidden {
+				continue
+			}
+			items = append(items, toRunningItem(childItem.Fields[source.ChildTarget.Index]))
+		}
+
+		values = append(values, runningValues(items, source.Window, n.isAverage)...)
+	}
+	for _, value := range values {
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return false, NewErrNonFiniteAggregate(value)
+		}
+	}
+
+	n.currentValue.Fields[n.virtualFieldIndex] = values
+
+	return true, nil
+}
+
+// toRunningItem returns the given value of an item as a float, or zero if it cannot be
+// aggregated.
+func toRunningItem(value any) float64 {
+	switch v := value.(type) {
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	case float64:
+		return v
+	default:
+		// return nothing, cannot be summed
+		return 0
+	}
+}
+
+// runningValues returns the running sums, or averages, of the given items.
+//
+// Each value aggregates the item at the same position, along with the items before it up to
+// the given window if any.
+func runningValues(items []float64, window immutable.Option[uint64], isAverage bool) []float64 {
+	values := make([]float64, len(items))
+
+	var cumulativeSum float64
+	for i, item := range items {
+		start := 0
+		if window.HasValue() && uint64(i+1) > window.Value() {
+			start = i + 1 - int(window.Value())
+		}
+
+		var sum float64
+		if start == 0 {
+			cumulativeSum += item
+			sum = cumulativeSum
+		} else {
+			// The items are summed again for each window, instead of subtracting the item
+			// leaving the window, to avoid accumulating float rounding errors.
+			for _, windowItem := range items[start : i+1] {
+				sum += windowItem
+			}
+		}
+
+		if isAverage {
+			values[i] = sum / float64(i+1-start)
+		} else {
+			values[i] = sum
+		}
+	}
+
+	return values
+}
@@ -11,14 +11,25 @@
 package planner
 
 import (
+	"time"
+
+	dsq "github.com/ipfs/go-datastore/query"
+	"github.com/sourcenetwork/immutable"
+
 	"github.com/sourcenetwork/defradb/client"
 	"github.com/sourcenetwork/defradb/client/request"
+	"github.com/sourcenetwork/defradb/connor"
 	"github.com/sourcenetwork/defradb/core"
 	"github.com/sourcenetwork/defradb/db/base"
 	"github.com/sourcenetwork/defradb/db/fetcher"
+	"github.com/sourcenetwork/defradb/logging"
 	"github.com/sourcenetwork/defradb/planner/mapper"
 )
 
+// timestampLayout is the layout of the times of the timestamp meta fields, with a fixed number of
+// digits so that the times are ordered as strings.
+const timestampLayout = "2006-01-02T15:04:05.000000000Z07:00"
+
 type scanExecInfo struct {
 	// Total number of times scan was issued.
 	iterations uint64
@@ -38,16 +49,30 @@ type scanNode struct {
 	p    *Planner
 	desc client.CollectionDescription
 
+	// The select this scan fetches the documents of, from which the fields to fetch are found.
+	parsed *mapper.Select
+
 	fields []*client.FieldDescription
 	docKey []byte
 
 	showDeleted bool
 
+	// timestamps is true if the times of the first and last commits of the documents are used.
+	timestamps bool
+	// The time the documents are fetched at, after which their commits are ignored.
+	atTime immutable.Option[time.Time]
+
 	spans   core.Spans
 	reverse bool
 
 	filter *mapper.Filter
 
+	// limit and offset restrict the documents returned by the scan if the limit of the select has
+	// been pushed down to it, in which case matches counts the documents that passed the filter.
+	limit   uint64
+	offset  uint64
+	matches uint64
+
 	scanInitialized bool
 
 	fetcher fetcher.Fetcher
@@ -60,6 +85,20 @@ func (n *scanNode) Kind() string {
 }
 
 func (n *scanNode) Init() error {
+	n.initFields()
+
+	if df, ok := n.fetcher.(*fetcher.DocumentFetcher); ok {
+		keysOnly := n.keysOnly()
+		if keysOnly {
+			n.p.debugPlan(
+				planStepRun,
+				"Scanning the keys of the documents only",
+				logging.NewKV("Collection", n.desc.Name),
+			)
+		}
+		df.SetKeysOnly(keysOnly)
+	}
+
 	// init the fetcher
 	if err := n.fetcher.Init(&n.desc, n.fields, n.reverse, n.showDeleted); err != nil {
 		return err
@@ -67,6 +106,164 @@ func (n *scanNode) Init() error {
 	return n.initScan()
 }
 
+// initFields sets the fields to fetch to the stored fields of the collection used by the select
+// of the scan, as the values of the other fields would be discarded once fetched.
+//
+// All the fields are fetched if the select might use any of them.
+func (n *scanNode) initFields() {
+	n.fields = nil
+	n.timestamps = true
+	if n.parsed == nil {
+		return
+	}
+	indexes := map[int]struct{}{}
+	names := map[string]struct{}{}
+	if !addSelectDependencies(n.parsed, indexes, names) {
+		return
+	}
+	// The filter of the scan may hold conditions added when joining it to its parent.
+	if n.filter != nil && !addFilterDependencies(n.filter.Conditions, indexes) {
+		return
+	}
+
+	n.timestamps = false
+	for name := range request.TimestampFields {
+		for _, index := range n.documentMapping.IndexesByName[name] {
+			if _, ok := indexes[index]; ok {
+				n.timestamps = true
+			}
+		}
+	}
+
+	relatedIDs := map[string]struct{}{}
+	for name := range names {
+		relatedIDs[name+"_id"] = struct{}{}
+	}
+
+	n.fields = []*client.FieldDescription{}
+	for i, field := range n.desc.Schema.Fields {
+		if field.Name == request.KeyFieldName || field.IsObject() {
+			continue
+		}
+		// Joining a related object requires the field holding its ID.
+		if _, ok := relatedIDs[field.Name]; ok {
+			n.fields = append(n.fields, &n.desc.Schema.Fields[i])
+			continue
+		}
+		for _, index := range n.documentMapping.IndexesByName[field.Name] {
+			if _, ok := indexes[index]; ok {
+				n.fields = append(n.fields, &n.desc.Schema.Fields[i])
+				break
+			}
+		}
+	}
+}
+
+// keysOnly returns true if none of the fields of the documents are used, such as when they are
+// only counted, in which case only their keys need to be scanned.
+func (n *scanNode) keysOnly() bool {
+	return n.fields != nil && len(n.fields) == 0 && !n.timestamps
+}
+
+// fetchAllFieldsOfScanNode makes the scan node of the given plan fetch all the fields of its
+// documents.
+//
+// It is used for the selects whose documents may be aggregated by another node, as aggregates
+// may use fields that the select does not.
+func fetchAllFieldsOfScanNode(plan planNode) {
+	switch node := plan.(type) {
+	case *scanNode:
+		node.parsed = nil
+	case nil:
+		return
+	default:
+		fetchAllFieldsOfScanNode(node.Source())
+	}
+}
+
+// addSelectDependencies adds the indexes of the properties used by the given select to the given
+// indexes, and the names of its child selects to the given names.
+//
+// Returns false if the select might use any of the properties.
+func addSelectDependencies(slct *mapper.Select, indexes map[int]struct{}, names map[string]struct{}) bool {
+	if slct.GroupBy != nil {
+		return false
+	}
+	if slct.Filter != nil && !addFilterDependencies(slct.Filter.Conditions, indexes) {
+		return false
+	}
+	if slct.OrderBy != nil {
+		for _, condition := range slct.OrderBy.Conditions {
+			if len(condition.FieldIndexes) > 0 {
+				indexes[condition.FieldIndexes[0]] = struct{}{}
+			}
+		}
+	}
+
+	for _, requestable := range slct.Fields {
+		switch r := requestable.(type) {
+		case *mapper.Field:
+			indexes[r.Index] = struct{}{}
+		case *mapper.Select:
+			if r.Name == request.GroupFieldName {
+				return false
+			}
+			// Joining documents ad-hoc requires the field they are joined on.
+			if r.Join.HasValue() {
+				for _, index := range slct.IndexesByName[r.Join.Value().LocalField] {
+					indexes[index] = struct{}{}
+				}
+				continue
+			}
+			names[r.Name] = struct{}{}
+		case *mapper.Aggregate:
+			addAggregateDependencies(r, indexes)
+		case *mapper.CommitSelect:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// addAggregateDependencies adds the indexes of the host properties targeted by the given aggregate
+// and its dependencies to the given indexes.
+func addAggregateDependencies(aggregate *mapper.Aggregate, indexes map[int]struct{}) {
+	for _, target := range aggregate.AggregateTargets {
+		indexes[target.Index] = struct{}{}
+	}
+	for _, dependency := range aggregate.Dependencies {
+		addAggregateDependencies(dependency, indexes)
+	}
+}
+
+// addFilterDependencies adds the indexes of the properties used by the given filter conditions to
+// the given indexes.
+//
+// Returns false if the conditions might use any of the properties.
+func addFilterDependencies(conditions map[connor.FilterKey]any, indexes map[int]struct{}) bool {
+	for key, value := range conditions {
+		switch k := key.(type) {
+		case *mapper.PropertyIndex:
+			indexes[k.Index] = struct{}{}
+		case *mapper.Operator:
+			clauses, ok := value.([]any)
+			if !ok {
+				return false
+			}
+			for _, clause := range clauses {
+				innerConditions, ok := clause.(map[connor.FilterKey]any)
+				if !ok || !addFilterDependencies(innerConditions, indexes) {
+					return false
+				}
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
 func (n *scanNode) initCollection(desc client.CollectionDescription) error {
 	n.desc = desc
 	return nil
@@ -89,6 +286,7 @@ func (n *scanNode) initScan() error {
 		return err
 	}
 
+	n.matches = 0
 	n.scanInitialized = true
 	return nil
 }
@@ -102,9 +300,16 @@ func (n *scanNode) Next() (bool, error) {
 	if n.spans.HasValue && len(n.spans.Value) == 0 {
 		return false, nil
 	}
+	if n.limit != 0 && n.matches >= n.limit+n.offset {
+		return false, nil
+	}
 
 	// keep scanning until we find a doc that passes the filter
 	for {
+		if err := n.p.ctx.Err(); err != nil {
+			return false, err
+		}
+
 		var err error
 		n.docKey, n.currentValue, err = n.fetcher.FetchNextDoc(n.p.ctx, n.documentMapping)
 		if err != nil {
@@ -120,19 +325,77 @@ func (n *scanNode) Next() (bool, error) {
 			request.DeletedFieldName,
 			n.currentValue.Status.IsDeleted(),
 		)
+		if n.timestamps {
+			err = n.setTimestamps()
+			if err != nil {
+				return false, err
+			}
+		}
 		passed, err := mapper.RunFilter(n.currentValue, n.filter)
 		if err != nil {
 			return false, err
 		}
 		if passed {
 			n.execInfo.filterMatches++
-			return true, nil
+			n.matches++
+			if n.matches > n.offset {
+				return true, nil
+			}
 		}
 	}
 }
 
+// setTimestamps sets the times of the first and last commits of the current document, from the
+// keys of its commits by time.
+//
+// The times are nil for the documents committed before the times of commits were recorded.
+func (n *scanNode) setTimestamps() error {
+	prefix := core.CommitTimeKey{DocKey: n.currentValue.GetKey()}
+	q, err := n.p.txn.Systemstore().Query(n.p.ctx, dsq.Query{
+		Prefix:   prefix.ToString() + "/",
+		KeysOnly: true,
+		Orders:   []dsq.Order{dsq.OrderByKey{}},
+	})
+	if err != nil {
+		return err
+	}
+	entries, err := q.Rest()
+	if closeErr := q.Close(); closeErr != nil {
+		return closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	var createdAt, updatedAt any
+	for _, entry := range entries {
+		key, err := core.NewCommitTimeKeyFromString(entry.Key)
+		if err != nil {
+			return err
+		}
+		// The keys are ordered by time, so the following commits are all after the time.
+		if n.atTime.HasValue() && key.Time().After(n.atTime.Value()) {
+			break
+		}
+		formatted := key.Time().UTC().Format(timestampLayout)
+		if createdAt == nil {
+			createdAt = formatted
+		}
+		updatedAt = formatted
+	}
+	n.documentMapping.SetFirstOfName(&n.currentValue, request.CreatedAtFieldName, createdAt)
+	n.documentMapping.SetFirstOfName(&n.currentValue, request.UpdatedAtFieldName, updatedAt)
+	return nil
+}
+
+// Spans sets the spans to scan, coalescing the overlapping and adjacent ones so that their keys
+// are scanned at once.
 func (n *scanNode) Spans(spans core.Spans) {
-	n.spans = spans
+	if !spans.HasValue {
+		n.spans = spans
+		return
+	}
+	n.spans = core.NewSpans(core.MergeAscending(spans.Value)...)
 }
 
 func (n *scanNode) Close() error {
@@ -142,12 +405,16 @@ func (n *scanNode) Close() error {
 func (n *scanNode) Source() planNode { return nil }
 
 // explainSpans explains the spans attribute.
+//
+// Each span is rendered symbolically, in terms of the collection and the documents it covers,
+// alongside its raw keys, so that the plans remain readable whatever the format of the keys.
 func (n *scanNode) explainSpans() []map[string]any {
 	spansExplainer := []map[string]any{}
 	for _, span := range n.spans.Value {
 		spanExplainer := map[string]any{
-			"start": span.Start().ToString(),
-			"end":   span.End().ToString(),
+			"start":  span.Start().ToString(),
+			"end":    span.End().ToString(),
+			"symbol": spanSymbol(n.desc, span),
 		}
 
 		spansExplainer = append(spansExplainer, spanExplainer)
@@ -156,6 +423,49 @@ func (n *scanNode) explainSpans() []map[string]any {
 	return spansExplainer
 }
 
+// spanSymbol renders the given span of the given collection symbolically.
+//
+// A span covering all the keys under a prefix is rendered as the prefix, e.g. `users/*` for all
+// the documents of the users collection or `users/bae-...` for one of them. Other spans are
+// rendered as the half-open range between their keys, e.g. `[users/bae-a, users/bae-b)`.
+func spanSymbol(desc client.CollectionDescription, span core.Span) string {
+	start := span.Start()
+	if span.End() == start.PrefixEnd() {
+		if start.DocKey == "" && start.FieldId == "" {
+			return keySymbol(desc, start) + "/*"
+		}
+		return keySymbol(desc, start)
+	}
+	return "[" + keySymbol(desc, start) + ", " + keySymbol(desc, span.End()) + ")"
+}
+
+// keySymbol renders the given key of the given collection with the names of the collection and of
+// the field, rather than their IDs. Keys of other collections are rendered raw.
+func keySymbol(desc client.CollectionDescription, key core.DataStoreKey) string {
+	if key.CollectionID != desc.IDString() {
+		return key.ToString()
+	}
+	symbol := desc.Name
+	if key.DocKey != "" {
+		symbol += "/" + key.DocKey
+	}
+	if key.FieldId != "" {
+		symbol += "/" + fieldSymbol(desc, key.FieldId)
+	}
+	return symbol
+}
+
+// fieldSymbol returns the name of the field of the given collection with the given ID, or the ID
+// if it is not a field of the collection.
+func fieldSymbol(desc client.CollectionDescription, fieldID string) string {
+	for _, field := range desc.Schema.Fields {
+		if field.ID.String() == fieldID {
+			return field.Name
+		}
+	}
+	return fieldID
+}
+
 func (n *scanNode) simpleExplain() (map[string]any, error) {
 	simpleExplainMap := map[string]any{}
 
@@ -206,12 +516,18 @@ func (p *Planner) Scan(parsed *mapper.Select) *scanNode {
 	var f fetcher.Fetcher
 	if parsed.Cid.HasValue() {
 		f = new(fetcher.VersionedFetcher)
+	} else if parsed.AtTime.HasValue() {
+		f = fetcher.NewAtTimeFetcher(parsed.AtTime.Value())
 	} else {
-		f = new(fetcher.DocumentFetcher)
+		df := new(fetcher.DocumentFetcher)
+		df.SetCache(p.docCache)
+		f = df
 	}
 	return &scanNode{
 		p:         p,
 		fetcher:   f,
+		parsed:    parsed,
+		atTime:    parsed.AtTime,
 		docMapper: docMapper{&parsed.DocumentMapping},
 	}
 }
@@ -14,7 +14,9 @@ import (
 	cid "github.com/ipfs/go-cid"
 	"github.com/sourcenetwork/immutable"
 
+	"github.com/sourcenetwork/defradb/client"
 	"github.com/sourcenetwork/defradb/client/request"
+	"github.com/sourcenetwork/defradb/connor"
 	"github.com/sourcenetwork/defradb/core"
 	"github.com/sourcenetwork/defradb/db/base"
 	"github.com/sourcenetwork/defradb/db/fetcher"
@@ -183,6 +185,9 @@ func (n *selectNode) Next() (bool, error) {
 }
 
 func (n *selectNode) Spans(spans core.Spans) {
+	// The given spans replace the ones restricting the scan to the requested documents,
+	// so they must be filtered again.
+	n.docKeys = n.selectReq.DocKeys
 	n.source.Spans(spans)
 }
 
@@ -243,9 +248,12 @@ func (n *selectNode) initSource() ([]aggregateNode, error) {
 	// apply the root filter to the source
 	// and rootSubType filters to the selectNode
 	// @todo: simulate splitting for now
+	var joinedFilter *mapper.Filter
 	origScan, ok := n.source.(*scanNode)
 	if ok {
-		origScan.filter = n.filter
+		// The conditions within operators such as `_or` that refer to related documents can only
+		// be checked once those are joined, so they are left to the select.
+		origScan.filter, joinedFilter = splitJoinedConditions(n.filter, &n.selectReq.DocumentMapping)
 		origScan.showDeleted = n.selectReq.ShowDeleted
 		n.filter = nil
 
@@ -269,16 +277,151 @@ func (n *selectNode) initSource() ([]aggregateNode, error) {
 			// @todo: When running the optimizer, check if the filter object
 			// contains a _key equality condition, and upgrade it to a point lookup
 			// instead of a prefix scan + filter via the Primary Index (0), like here:
-			spans := make([]core.Span, len(n.selectReq.DocKeys.Value()))
-			for i, docKey := range n.selectReq.DocKeys.Value() {
+			//
+			// The keys of the documents all have the same length, so the span of a valid key only
+			// covers the document of the key, and not the ones whose keys start with it. The
+			// invalid keys are given no span, as they can't be the key of any document.
+			spans := make([]core.Span, 0, len(n.selectReq.DocKeys.Value()))
+			for _, docKey := range n.selectReq.DocKeys.Value() {
+				if _, err := client.NewDocKeyFromString(docKey); err != nil {
+					continue
+				}
 				dockeyIndexKey := base.MakeDocKey(sourcePlan.info.collectionDescription, docKey)
-				spans[i] = core.NewSpan(dockeyIndexKey, dockeyIndexKey.PrefixEnd())
+				spans = append(spans, core.NewSpan(dockeyIndexKey, dockeyIndexKey.PrefixEnd()))
 			}
 			origScan.Spans(core.NewSpans(spans...))
+
+			// The spans restrict the scan to the given documents, so they don't need to be
+			// filtered again unless the spans are replaced.
+			n.docKeys = immutable.None[[]string]()
+		}
+	}
+
+	aggregates, err := n.initFields(n.selectReq)
+	if err != nil {
+		return nil, err
+	}
+	if joinedFilter == nil {
+		return aggregates, nil
+	}
+	n.filter = mergeFilters(n.filter, joinedFilter)
+
+	// If the documents matching the branches of an `_or` condition can be found separately, the
+	// scan is restricted to them rather than scanning the whole collection.
+	if n.selectReq.DocKeys.HasValue() {
+		return aggregates, nil
+	}
+	union, isUnion, err := n.planner.Union(n, origScan, joinedFilter)
+	if err != nil {
+		return nil, err
+	}
+	if isUnion {
+		n.source = union
+	}
+	return aggregates, nil
+}
+
+// splitJoinedConditions splits the given filter of the documents of the given mapping into the
+// conditions that can be checked before their related documents are joined, and the operators
+// whose conditions refer to related documents.
+//
+// The conditions directly on related documents are split later on by their joins.
+func splitJoinedConditions(
+	filter *mapper.Filter,
+	mapping *core.DocumentMapping,
+) (*mapper.Filter, *mapper.Filter) {
+	if filter == nil {
+		return nil, nil
+	}
+
+	var joined *mapper.Filter
+	for key, condition := range filter.Conditions {
+		op, isOp := key.(*mapper.Operator)
+		if !isOp || !hasJoinedCondition(condition, mapping) {
+			continue
+		}
+		if joined == nil {
+			joined = mapper.NewFilter()
+		}
+		joined.Conditions[key] = condition
+		if external, ok := filter.ExternalConditions[op.Operation]; ok {
+			if joined.ExternalConditions == nil {
+				joined.ExternalConditions = map[string]any{}
+			}
+			joined.ExternalConditions[op.Operation] = external
+		}
+	}
+	if joined == nil {
+		return filter, nil
+	}
+
+	root := mapper.NewFilter()
+	for key, condition := range filter.Conditions {
+		if _, ok := joined.Conditions[key]; !ok {
+			root.Conditions[key] = condition
+		}
+	}
+	for name, condition := range filter.ExternalConditions {
+		if _, ok := joined.ExternalConditions[name]; ok {
+			continue
 		}
+		if root.ExternalConditions == nil {
+			root.ExternalConditions = map[string]any{}
+		}
+		root.ExternalConditions[name] = condition
+	}
+	return root, joined
+}
+
+// hasJoinedCondition returns true if the given operand of an operator contains a condition on a
+// property of the documents of the given mapping holding their related documents.
+func hasJoinedCondition(operand any, mapping *core.DocumentMapping) bool {
+	switch typedOperand := operand.(type) {
+	case map[connor.FilterKey]any:
+		for key, condition := range typedOperand {
+			switch typedKey := key.(type) {
+			case *mapper.PropertyIndex:
+				if typedKey.Index < len(mapping.ChildMappings) && mapping.ChildMappings[typedKey.Index] != nil {
+					return true
+				}
+			case *mapper.Operator:
+				if hasJoinedCondition(condition, mapping) {
+					return true
+				}
+			}
+		}
+	case []any:
+		for _, inner := range typedOperand {
+			if hasJoinedCondition(inner, mapping) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// mergeFilters returns a filter with the conditions of both given filters.
+func mergeFilters(a *mapper.Filter, b *mapper.Filter) *mapper.Filter {
+	if a == nil || len(a.Conditions) == 0 {
+		return b
+	}
+	if b == nil {
+		return a
 	}
 
-	return n.initFields(n.selectReq)
+	merged := mapper.NewFilter()
+	for _, filter := range []*mapper.Filter{a, b} {
+		for key, condition := range filter.Conditions {
+			merged.Conditions[key] = condition
+		}
+		for name, condition := range filter.ExternalConditions {
+			if merged.ExternalConditions == nil {
+				merged.ExternalConditions = map[string]any{}
+			}
+			merged.ExternalConditions[name] = condition
+		}
+	}
+	return merged
 }
 
 func (n *selectNode) initFields(selectReq *mapper.Select) ([]aggregateNode, error) {
@@ -298,6 +441,8 @@ func (n *selectNode) initFields(selectReq *mapper.Select) ([]aggregateNode, erro
 				plan, aggregateError = n.planner.Sum(f, selectReq)
 			case request.AverageFieldName:
 				plan, aggregateError = n.planner.Average(f)
+			case request.RunningSumFieldName, request.RunningAverageFieldName:
+				plan, aggregateError = n.planner.RunningAggregate(f)
 			}
 
 			if aggregateError != nil {
@@ -330,6 +475,19 @@ func (n *selectNode) initFields(selectReq *mapper.Select) ([]aggregateNode, erro
 				if err := n.addSubPlan(f.Index, commitPlan); err != nil {
 					return nil, err
 				}
+			} else if f.HistoryField.HasValue() {
+				historyPlan, err := n.planner.History(f)
+				if err != nil {
+					return nil, err
+				}
+
+				if err := n.addSubPlan(f.Index, historyPlan); err != nil {
+					return nil, err
+				}
+			} else if f.Name == request.MetaFieldName {
+				if err := n.addSubPlan(f.Index, n.planner.Meta(f)); err != nil {
+					return nil, err
+				}
 			} else if f.Name == request.GroupFieldName {
 				if selectReq.GroupBy == nil {
 					return nil, ErrGroupOutsideOfGroupBy
@@ -391,6 +549,13 @@ func (p *Planner) SubSelect(selectReq *mapper.Select) (planNode, error) {
 	// if this is a sub select plan, we need to remove the render node
 	// as the final top level selectTopNode will handle all sub renders
 	top := plan.(*selectTopNode)
+	fetchAllFieldsOfScanNode(top.selectNode)
+
+	// The documents of sub selects are restricted by their joins, once for each of the documents
+	// they are joined to, in which case the union would be resolved each time.
+	if union, ok := top.selectNode.source.(*unionNode); ok {
+		top.selectNode.source = union.source
+	}
 	return top, nil
 }
 
@@ -11,6 +11,9 @@
 package planner
 
 import (
+	"math"
+	"math/big"
+
 	"github.com/sourcenetwork/immutable"
 	"github.com/sourcenetwork/immutable/enumerable"
 
@@ -28,6 +31,7 @@ type sumNode struct {
 	plan planNode
 
 	isFloat           bool
+	isUnsigned        bool
 	virtualFieldIndex int
 	aggregateMapping  []mapper.AggregateTarget
 
@@ -43,63 +47,81 @@ func (p *Planner) Sum(
 	field *mapper.Aggregate,
 	parent *mapper.Select,
 ) (*sumNode, error) {
-	isFloat := false
-	for _, target := range field.AggregateTargets {
-		isTargetFloat, err := p.isValueFloat(parent, &target)
-		if err != nil {
-			return nil, err
-		}
-		// If one source property is a float, the result will be a float - no need to check the rest
-		if isTargetFloat {
-			isFloat = true
-			break
-		}
+	kind, err := p.sumKind(parent, field.AggregateTargets)
+	if err != nil {
+		return nil, err
 	}
 
 	return &sumNode{
 		p:                 p,
-		isFloat:           isFloat,
+		isFloat:           kind == client.FieldKind_FLOAT,
+		isUnsigned:        kind == client.FieldKind_UINT64,
 		aggregateMapping:  field.AggregateTargets,
 		virtualFieldIndex: field.Index,
 		docMapper:         docMapper{&field.DocumentMapping},
 	}, nil
 }
 
-// Returns true if the value to be summed is a float, otherwise false.
-func (p *Planner) isValueFloat(
+// sumKind returns the kind of the sum of the given targets, which is a float if one of them is
+// a float, an unsigned integer if they all are, and a signed integer otherwise.
+func (p *Planner) sumKind(
+	parent *mapper.Select,
+	targets []mapper.AggregateTarget,
+) (client.FieldKind, error) {
+	kind := client.FieldKind_UINT64
+	for _, target := range targets {
+		targetKind, err := p.valueKind(parent, &target)
+		if err != nil {
+			return 0, err
+		}
+		switch targetKind {
+		case client.FieldKind_FLOAT:
+			// If one source property is a float, the result will be a float - no need to check the rest
+			return client.FieldKind_FLOAT, nil
+		case client.FieldKind_INT:
+			kind = client.FieldKind_INT
+		}
+	}
+	if len(targets) == 0 {
+		return client.FieldKind_INT, nil
+	}
+	return kind, nil
+}
+
+// valueKind returns the kind of the value to be summed, FieldKind_FLOAT if it is a float,
+// FieldKind_UINT64 if it is an unsigned integer, and FieldKind_INT otherwise.
+func (p *Planner) valueKind(
 	parent *mapper.Select,
 	source *mapper.AggregateTarget,
-) (bool, error) {
+) (client.FieldKind, error) {
 	// It is important that averages are floats even if their underlying values are ints
 	// else sum will round them down to the nearest whole number
 	if source.ChildTarget.Name == request.AverageFieldName {
-		return true, nil
+		return client.FieldKind_FLOAT, nil
 	}
 
 	if !source.ChildTarget.HasValue {
 		parentDescription, err := p.getCollectionDesc(parent.CollectionName)
 		if err != nil {
-			return false, err
+			return 0, err
 		}
 
 		fieldDescription, fieldDescriptionFound := parentDescription.GetField(source.Name)
 		if !fieldDescriptionFound {
-			return false, client.NewErrFieldNotExist(source.Name)
+			return 0, client.NewErrFieldNotExist(source.Name)
 		}
-		return fieldDescription.Kind == client.FieldKind_FLOAT_ARRAY ||
-			fieldDescription.Kind == client.FieldKind_FLOAT ||
-			fieldDescription.Kind == client.FieldKind_NILLABLE_FLOAT_ARRAY, nil
+		return numericKind(fieldDescription.Kind), nil
 	}
 
 	// If path length is two, we are summing a group or a child relationship
 	if source.ChildTarget.Name == request.CountFieldName {
-		// If we are summing a count, we know it is an int and can return false early
-		return false, nil
+		// If we are summing a count, we know it is an int and can return early
+		return client.FieldKind_INT, nil
 	}
 
 	child, isChildSelect := parent.FieldAt(source.Index).AsSelect()
 	if !isChildSelect {
-		return false, ErrMissingChildSelect
+		return 0, ErrMissingChildSelect
 	}
 
 	if _, isAggregate := request.Aggregates[source.ChildTarget.Name]; isAggregate {
@@ -107,37 +129,32 @@ func (p *Planner) isValueFloat(
 		// the root field in order to determine the value type.  This is recursive to allow handling
 		// of N-depth aggregations (e.g. sum of sum of sum of...)
 		sourceField := child.FieldAt(source.ChildTarget.Index).(*mapper.Aggregate)
-
-		for _, aggregateTarget := range sourceField.AggregateTargets {
-			isFloat, err := p.isValueFloat(
-				child,
-				&aggregateTarget,
-			)
-			if err != nil {
-				return false, err
-			}
-
-			// If one source property is a float, the result will be a float - no need to check the rest
-			if isFloat {
-				return true, nil
-			}
-		}
-		return false, nil
+		return p.sumKind(child, sourceField.AggregateTargets)
 	}
 
 	childCollectionDescription, err := p.getCollectionDesc(child.CollectionName)
 	if err != nil {
-		return false, err
+		return 0, err
 	}
 
 	fieldDescription, fieldDescriptionFound := childCollectionDescription.GetField(source.ChildTarget.Name)
 	if !fieldDescriptionFound {
-		return false, client.NewErrFieldNotExist(source.ChildTarget.Name)
+		return 0, client.NewErrFieldNotExist(source.ChildTarget.Name)
 	}
 
-	return fieldDescription.Kind == client.FieldKind_FLOAT_ARRAY ||
-		fieldDescription.Kind == client.FieldKind_FLOAT ||
-		fieldDescription.Kind == client.FieldKind_NILLABLE_FLOAT_ARRAY, nil
+	return numericKind(fieldDescription.Kind), nil
+}
+
+// numericKind returns the kind of the values of the given field kind, as summed.
+func numericKind(kind client.FieldKind) client.FieldKind {
+	switch kind {
+	case client.FieldKind_FLOAT, client.FieldKind_FLOAT_ARRAY, client.FieldKind_NILLABLE_FLOAT_ARRAY:
+		return client.FieldKind_FLOAT
+	case client.FieldKind_UINT64:
+		return client.FieldKind_UINT64
+	default:
+		return client.FieldKind_INT
+	}
 }
 
 func (n *sumNode) Kind() string {
@@ -182,9 +199,13 @@ func (n *sumNode) simpleExplain() (map[string]any, error) {
 		sourceExplanations[i] = simpleExplainMap
 	}
 
-	return map[string]any{
+	simpleExplainMap := map[string]any{
 		sourcesLabel: sourceExplanations,
-	}, nil
+	}
+	if field := responseFieldOf(n.documentMapping, n.virtualFieldIndex, request.SumFieldName); field != "" {
+		simpleExplainMap[responseFieldLabel] = field
+	}
+	return simpleExplainMap, nil
 }
 
 // Explain method returns a map containing all attributes of this node that
@@ -214,71 +235,66 @@ func (n *sumNode) Next() (bool, error) {
 
 	n.currentValue = n.plan.Value()
 
-	sum := float64(0)
+	var sum numericSum
 
 	for _, source := range n.aggregateMapping {
 		child := n.currentValue.Fields[source.Index]
-		var collectionSum float64
 		var err error
 		switch childCollection := child.(type) {
+		case *groupAggregates:
+			// The documents of a streamed group are summed as they are read.
+			if fieldSum, ok := childCollection.sums[source.ChildTarget.Index]; ok {
+				sum.merge(fieldSum)
+			}
 		case []core.Doc:
-			collectionSum = sumDocs(childCollection, func(childItem core.Doc) float64 {
-				childProperty := childItem.Fields[source.ChildTarget.Index]
-				switch v := childProperty.(type) {
-				case int:
-					return float64(v)
-				case int64:
-					return float64(v)
-				case uint64:
-					return float64(v)
-				case float64:
-					return v
-				default:
-					// return nothing, cannot be summed
-					return 0
-				}
+			sumDocs(&sum, childCollection, func(childItem core.Doc) any {
+				return childItem.Fields[source.ChildTarget.Index]
 			})
 		case []int64:
-			collectionSum, err = sumItems(
+			err = sumItems(
+				&sum,
 				childCollection,
 				&source,
 				lessN[int64],
-				func(childItem int64) float64 {
-					return float64(childItem)
+				func(childItem int64) any {
+					return childItem
 				},
 			)
 
 		case []immutable.Option[int64]:
-			collectionSum, err = sumItems(
+			err = sumItems(
+				&sum,
 				childCollection,
 				&source,
 				lessO[int64],
-				func(childItem immutable.Option[int64]) float64 {
+				func(childItem immutable.Option[int64]) any {
 					if !childItem.HasValue() {
-						return 0
+						return nil
 					}
-					return float64(childItem.Value())
+					return childItem.Value()
 				},
 			)
 
 		case []float64:
-			collectionSum, err = sumItems(
+			err = sumItems(
+				&sum,
 				childCollection,
 				&source,
 				lessN[float64],
-				func(childItem float64) float64 {
+				func(childItem float64) any {
 					return childItem
 				},
 			)
 
 		case []immutable.Option[float64]:
-			collectionSum, err = sumItems(
+			err = sumItems(
+				&sum,
 				childCollection,
 				&source,
 				lessO[float64],
-				func(childItem immutable.Option[float64]) float64 {
+				func(childItem immutable.Option[float64]) any {
 					if !childItem.HasValue() {
-						return 0
+						return nil
 					}
 					return childItem.Value()
 				},
@@ -287,40 +303,88 @@ func (n *sumNode) Next() (bool, error) {
 		if err != nil {
 			return false, err
 		}
-		sum += collectionSum
 	}
 
-	var typedSum any
-	if n.isFloat {
-		typedSum = sum
-	} else {
-		typedSum = int64(sum)
+	typedSum, err := sum.value(n.isFloat, n.isUnsigned)
+	if err != nil {
+		return false, err
 	}
 	n.currentValue.Fields[n.virtualFieldIndex] = typedSum
 
 	return true, nil
 }
 
+// numericSum accumulates the values of a sum, the whole ones exactly so that the sums overflowing
+// the type of the result return an error instead of wrapping.
+type numericSum struct {
+	float   float64
+	integer big.Int
+}
+
+// add adds the given value to the sum, values that are not numbers being skipped as they
+// cannot be summed.
+func (s *numericSum) add(value any) {
+	switch v := value.(type) {
+	case int:
+		s.integer.Add(&s.integer, big.NewInt(int64(v)))
+	case int64:
+		s.integer.Add(&s.integer, big.NewInt(v))
+	case uint64:
+		s.integer.Add(&s.integer, new(big.Int).SetUint64(v))
+	case float64:
+		s.float += v
+	}
+}
+
+// merge adds the given sum to the sum.
+func (s *numericSum) merge(other *numericSum) {
+	s.float += other.float
+	s.integer.Add(&s.integer, &other.integer)
+}
+
+// value returns the sum as a float64 if it is a float, or else as an int64, or as an uint64 if it
+// is unsigned and above the range of int64 values.
+func (s *numericSum) value(isFloat bool, isUnsigned bool) (any, error) {
+	if isFloat {
+		integer, _ := new(big.Float).SetInt(&s.integer).Float64()
+		sum := s.float + integer
+		if math.IsNaN(sum) || math.IsInf(sum, 0) {
+			return nil, NewErrNonFiniteAggregate(sum)
+		}
+		return sum, nil
+	}
+
+	// Floats are only summed with integers as floats, but truncate them regardless.
+	integer, _ := new(big.Float).SetFloat64(s.float).Int(nil)
+	integer.Add(integer, &s.integer)
+	switch {
+	case integer.IsInt64():
+		return integer.Int64(), nil
+	case isUnsigned && integer.IsUint64():
+		return integer.Uint64(), nil
+	default:
+		return nil, NewErrIntegerSumOverflow(integer.String())
+	}
+}
+
 // offsets sums the documents in a slice, skipping over hidden items (a grouping mechanic).
 // Docs should be counted with this function to avoid applying offsets twice (once in the
 // select, then once here).
-func sumDocs(docs []core.Doc, toFloat func(core.Doc) float64) float64 {
-	var sum float64 = 0
+func sumDocs(sum *numericSum, docs []core.Doc, toNumber func(core.Doc) any) {
 	for _, doc := range docs {
 		if !doc.Hidden {
-			sum += toFloat(doc)
+			sum.add(toNumber(doc))
 		}
 	}
-
-	return sum
 }
 
 func sumItems[T any](
+	sum *numericSum,
 	source []T,
 	aggregateTarget *mapper.AggregateTarget,
 	less func(T, T) bool,
-	toFloat func(T) float64,
-) (float64, error) {
+	toNumber func(T) any,
+) error {
 	items := enumerable.New(source)
 	if aggregateTarget.Filter != nil {
 		items = enumerable.Where(items, func(item T) (bool, error) {
@@ -341,12 +405,9 @@ func sumItems[T any](
 		items = enumerable.Take(items, aggregateTarget.Limit.Limit)
 	}
 
-	var sum float64 = 0
-	err := enumerable.ForEach(items, func(item T) {
-		sum += toFloat(item)
+	return enumerable.ForEach(items, func(item T) {
+		sum.add(toNumber(item))
 	})
-
-	return sum, err
 }
 
 func (n *sumNode) SetPlan(p planNode) { n.plan = p }
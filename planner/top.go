@@ -191,9 +191,11 @@ func (p *Planner) Top(m *mapper.Select) (*topLevelNode, error) {
 
 	aggregateChildren := []planNode{}
 	aggregateChildIndexes := []int{}
+	aggregatedIndexes := map[int]struct{}{}
 	for _, field := range m.Fields {
 		switch f := field.(type) {
 		case *mapper.Aggregate:
+			addAggregatedIndexes(f, aggregatedIndexes)
 			var child planNode
 			var err error
 			switch field.GetName() {
@@ -219,6 +221,13 @@ func (p *Planner) Top(m *mapper.Select) (*topLevelNode, error) {
 		}
 	}
 
+	// The aggregates may use fields of the selected documents that their select does not.
+	for i, child := range node.children {
+		if _, ok := aggregatedIndexes[node.childIndexes[i]]; ok {
+			fetchAllFieldsOfScanNode(child.(*selectTopNode).selectNode)
+		}
+	}
+
 	// Iterate through the aggregates backwards to ensure dependencies
 	// execute *before* any aggregate dependent on them.
 	for i := len(aggregateChildren) - 1; i >= 0; i-- {
@@ -228,3 +237,18 @@ func (p *Planner) Top(m *mapper.Select) (*topLevelNode, error) {
 
 	return &node, nil
 }
+
+// addAggregatedIndexes adds the indexes of the selects whose fields may be used by the given
+// aggregate or its dependencies to the given indexes.
+//
+// Counts only use the number of the selected documents, so their targets are not added.
+func addAggregatedIndexes(aggregate *mapper.Aggregate, indexes map[int]struct{}) {
+	if aggregate.Name != request.CountFieldName {
+		for _, target := range aggregate.AggregateTargets {
+			indexes[target.Index] = struct{}{}
+		}
+	}
+	for _, dependency := range aggregate.Dependencies {
+		addAggregatedIndexes(dependency, indexes)
+	}
+}
@@ -56,6 +56,9 @@ type typeIndexJoin struct {
 	// based on the relationship of the sub types
 	joinPlan planNode
 
+	// The field of the response the joined documents are rendered as.
+	responseField string
+
 	execInfo typeIndexJoinExecInfo
 }
 
@@ -70,14 +73,24 @@ func (p *Planner) makeTypeIndexJoin(
 	subType *mapper.Select,
 ) (*typeIndexJoin, error) {
 	typeJoin := &typeIndexJoin{
-		p:         p,
-		docMapper: docMapper{parent.documentMapping},
+		p:             p,
+		docMapper:     docMapper{parent.documentMapping},
+		responseField: responseFieldOf(parent.documentMapping, subType.Index, subType.Name),
 	}
 
 	// handle join relation strategies
 	var joinPlan planNode
 	var err error
 
+	if subType.Join.HasValue() { // Ad-hoc join, on the equality of a field of each type
+		joinPlan, err = p.makeTypeJoinOn(parent, source, subType)
+		if err != nil {
+			return nil, err
+		}
+		typeJoin.joinPlan = joinPlan
+		return typeJoin, nil
+	}
+
 	desc := parent.sourceInfo.collectionDescription
 	typeFieldDesc, ok := desc.GetField(subType.Name)
 	if !ok {
@@ -141,6 +154,7 @@ func (n *typeIndexJoin) simpleExplain() (map[string]any, error) {
 		joinSubTypeLabel            = "subType"
 		joinSubTypeNameLabel        = "subTypeName"
 		joinRootLabel               = "rootName"
+		joinForeignFieldLabel       = "foreignField"
 	)
 
 	simpleExplainMap := map[string]any{}
@@ -148,6 +162,11 @@ func (n *typeIndexJoin) simpleExplain() (map[string]any, error) {
 	// Add the type attribute.
 	simpleExplainMap[joinTypeLabel] = n.joinPlan.Kind()
 
+	// Add the response field attribute if the joined documents are rendered.
+	if n.responseField != "" {
+		simpleExplainMap[responseFieldLabel] = n.responseField
+	}
+
 	switch joinType := n.joinPlan.(type) {
 	case *typeJoinOne:
 		// Add the direction attribute.
@@ -182,6 +201,20 @@ func (n *typeIndexJoin) simpleExplain() (map[string]any, error) {
 		// Add the joined (subType) type's entire explain graph.
 		simpleExplainMap[joinSubTypeLabel] = subTypeExplainGraph
 
+	case *typeJoinOn:
+		// Add the attribute(s).
+		simpleExplainMap[joinRootLabel] = joinType.localField
+		simpleExplainMap[joinSubTypeNameLabel] = joinType.subTypeName
+		simpleExplainMap[joinForeignFieldLabel] = joinType.foreignField
+
+		subTypeExplainGraph, err := buildSimpleExplainGraph(joinType.subType)
+		if err != nil {
+			return nil, err
+		}
+
+		// Add the joined (subType) type's entire explain graph.
+		simpleExplainMap[joinSubTypeLabel] = subTypeExplainGraph
+
 	default:
 		return simpleExplainMap, client.NewErrUnhandledType("join plan", n.joinPlan)
 	}
@@ -253,8 +286,15 @@ type typeJoinOne struct {
 
 	primary bool
 
+	// includeMissing is whether the root documents whose related document is missing are
+	// returned, with the related document set to nil.
+	includeMissing bool
+
 	spans     core.Spans
 	subSelect *mapper.Select
+
+	// recursion is the path of the recursive traversal this join is a level of, if any.
+	recursion *recursionPath
 }
 
 func (p *Planner) makeTypeJoinOne(
@@ -268,6 +308,11 @@ func (p *Planner) makeTypeJoinOne(
 		subType.ShowDeleted = parent.selectReq.ShowDeleted
 	}
 
+	// The related documents are selected as they were at the same time as their parents.
+	subType.AtTime = parent.selectReq.AtTime
+
+	recursion := p.recursionPathOf(subType)
+
 	selectPlan, err := p.SubSelect(subType)
 	if err != nil {
 		return nil, err
@@ -301,7 +346,9 @@ func (p *Planner) makeTypeJoinOne(
 		subTypeFieldName: subTypeField.Name,
 		subType:          selectPlan,
 		primary:          isPrimary,
+		includeMissing:   !subType.IncludeMissing.HasValue() || subType.IncludeMissing.Value(),
 		docMapper:        docMapper{parent.documentMapping},
+		recursion:        recursion,
 	}, nil
 }
 
@@ -329,21 +376,43 @@ func (n *typeJoinOne) Spans(spans core.Spans) {
 }
 
 func (n *typeJoinOne) Next() (bool, error) {
-	hasNext, err := n.root.Next()
-	if err != nil || !hasNext {
-		return hasNext, err
+	for {
+		hasNext, err := n.root.Next()
+		if err != nil || !hasNext {
+			return hasNext, err
+		}
+
+		doc, found := n.join(n.root.Value())
+		if found || n.includeMissing {
+			n.currentValue = doc
+			return true, nil
+		}
+	}
+}
+
+// join sets the related document of the given root document, returning the root document and
+// true if the related document has been found.
+//
+// The related document is left nil if it is missing, whether the relation is not set, the related
+// document does not exist or it does not match the filter of the sub type.
+func (n *typeJoinOne) join(doc core.Doc) (core.Doc, bool) {
+	if n.recursion != nil {
+		docKey := doc.GetKey()
+		if !n.recursion.enter(docKey) {
+			// The document is already being expanded by a level above, which will not
+			// return it, so there is no need to expand it again.
+			return doc, true
+		}
+		defer n.recursion.leave(docKey)
 	}
 
-	doc := n.root.Value()
 	if n.primary {
-		n.currentValue = n.valuesPrimary(doc)
-	} else {
-		n.currentValue = n.valuesSecondary(doc)
+		return n.valuesPrimary(doc)
 	}
-	return true, nil
+	return n.valuesSecondary(doc)
 }
 
-func (n *typeJoinOne) valuesSecondary(doc core.Doc) core.Doc {
+func (n *typeJoinOne) valuesSecondary(doc core.Doc) (core.Doc, bool) {
 	fkIndex := &mapper.PropertyIndex{
 		Index: n.subType.DocumentMap().FirstIndexOfName(n.subTypeFieldName + "_id"),
 	}
@@ -354,32 +423,40 @@ func (n *typeJoinOne) valuesSecondary(doc core.Doc) core.Doc {
 	// We have to reset the scan node after appending the new key-filter
 	if err := n.subType.Init(); err != nil {
 		log.ErrorE(n.p.ctx, "Sub-type initialization error at scan node reset", err)
-		return doc
+		return doc, false
 	}
 
 	// using the doc._key as a filter
 	err := appendFilterToScanNode(n.subType, filter)
 	if err != nil {
-		return core.Doc{}
+		// The root document is still returned, with the related document missing, rather
+		// than an empty document.
+		log.ErrorE(n.p.ctx, "Sub-type key filter error", err)
+		return doc, false
 	}
 
 	next, err := n.subType.Next()
 	if !next || err != nil {
-		return doc
+		return doc, false
 	}
 
 	subdoc := n.subType.Value()
+	if n.recursion.contains(subdoc.GetKey()) {
+		// The related document is an ancestor of the root document, which is not expanded
+		// again so that cycles are not traversed, but is not missing.
+		return doc, true
+	}
 	doc.Fields[n.subSelect.Index] = subdoc
-	return doc
+	return doc, true
 }
 
-func (n *typeJoinOne) valuesPrimary(doc core.Doc) core.Doc {
+func (n *typeJoinOne) valuesPrimary(doc core.Doc) (core.Doc, bool) {
 	// get the subtype doc key
 	subDocKey := n.docMapper.documentMapping.FirstOfName(doc, n.subTypeName+"_id")
 
 	subDocKeyStr, ok := subDocKey.(string)
 	if !ok {
-		return doc
+		return doc, false
 	}
 
 	// create the collection key for the sub doc
@@ -396,27 +473,30 @@ func (n *typeJoinOne) valuesPrimary(doc core.Doc) core.Doc {
 	// re-initialize the sub type plan
 	if err := n.subType.Init(); err != nil {
 		log.ErrorE(n.p.ctx, "Sub-type initialization error at scan node reset", err)
-		return doc
+		return doc, false
 	}
 
 	// if we don't find any docs from our point span lookup
 	// or if we encounter an error just return the base doc,
-	// with an empty map for the subdoc
+	// with a nil subdoc
 	next, err := n.subType.Next()
 
 	if err != nil {
 		log.ErrorE(n.p.ctx, "Sub-type initialization error at scan node reset", err)
-		return doc
+		return doc, false
 	}
 
 	if !next {
-		return doc
+		return doc, false
 	}
 
 	subDoc := n.subType.Value()
+	if n.recursion.contains(subDoc.GetKey()) {
+		return doc, true
+	}
 	doc.Fields[n.subSelect.Index] = subDoc
 
-	return doc
+	return doc, true
 }
 
 func (n *typeJoinOne) Close() error {
@@ -445,6 +525,9 @@ type typeJoinMany struct {
 	subTypeName string
 
 	subSelect *mapper.Select
+
+	// recursion is the path of the recursive traversal this join is a level of, if any.
+	recursion *recursionPath
 }
 
 func (p *Planner) makeTypeJoinMany(
@@ -458,6 +541,11 @@ func (p *Planner) makeTypeJoinMany(
 		subType.ShowDeleted = parent.selectReq.ShowDeleted
 	}
 
+	// The related documents are selected as they were at the same time as their parents.
+	subType.AtTime = parent.selectReq.AtTime
+
+	recursion := p.recursionPathOf(subType)
+
 	selectPlan, err := p.SubSelect(subType)
 	if err != nil {
 		return nil, err
@@ -486,6 +574,7 @@ func (p *Planner) makeTypeJoinMany(
 		rootName:    rootField.Name,
 		subType:     selectPlan,
 		docMapper:   docMapper{parent.documentMapping},
+		recursion:   recursion,
 	}, nil
 }
 
@@ -520,6 +609,17 @@ func (n *typeJoinMany) Next() (bool, error) {
 
 	n.currentValue = n.root.Value()
 
+	if n.recursion != nil {
+		docKey := n.currentValue.GetKey()
+		if !n.recursion.enter(docKey) {
+			// The document is already being expanded by a level above, which will not
+			// return it, so there is no need to expand it again.
+			n.currentValue.Fields[n.subSelect.Index] = []core.Doc{}
+			return true, nil
+		}
+		defer n.recursion.leave(docKey)
+	}
+
 	// check if theres an index
 	// if there is, scan and aggregate resuts
 	// if not, then manually scan the subtype table
@@ -554,6 +654,9 @@ func (n *typeJoinMany) Next() (bool, error) {
 			}
 
 			subdoc := n.subType.Value()
+			if n.recursion.contains(subdoc.GetKey()) {
+				continue
+			}
 			subdocs = append(subdocs, subdoc)
 		}
 	}
@@ -572,6 +675,149 @@ func (n *typeJoinMany) Close() error {
 
 func (n *typeJoinMany) Source() planNode { return n.root }
 
+// typeJoinOn is the plan node for an ad-hoc join, where the sub type documents
+// are those whose foreign field is equal to the local field of the root document,
+// regardless of any relation between the two types.
+type typeJoinOn struct {
+	documentIterator
+	docMapper
+
+	p *Planner
+
+	root    planNode
+	subType planNode
+
+	subTypeName  string
+	localField   string
+	foreignField string
+
+	subSelect *mapper.Select
+}
+
+func (p *Planner) makeTypeJoinOn(
+	parent *selectNode,
+	source planNode,
+	subType *mapper.Select,
+) (*typeJoinOn, error) {
+	join := subType.Join.Value()
+	if err := checkJoinField(parent.sourceInfo.collectionDescription, join.LocalField); err != nil {
+		return nil, err
+	}
+	subTypeCollectionDesc, err := p.getCollectionDesc(subType.CollectionName)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkJoinField(subTypeCollectionDesc, join.ForeignField); err != nil {
+		return nil, err
+	}
+
+	// The joined documents are selected as they were at the same time as the root ones.
+	subType.ShowDeleted = parent.selectReq.ShowDeleted
+	subType.AtTime = parent.selectReq.AtTime
+
+	selectPlan, err := p.SubSelect(subType)
+	if err != nil {
+		return nil, err
+	}
+
+	return &typeJoinOn{
+		p:            p,
+		root:         source,
+		subSelect:    subType,
+		subTypeName:  subType.Name,
+		localField:   join.LocalField,
+		foreignField: join.ForeignField,
+		subType:      selectPlan,
+		docMapper:    docMapper{parent.documentMapping},
+	}, nil
+}
+
+// checkJoinField returns an error if the given collection can't be joined on the given field.
+func checkJoinField(desc client.CollectionDescription, name string) error {
+	field, ok := desc.GetField(name)
+	if !ok || field.IsObject() {
+		return NewErrInvalidJoinField(desc.Name, name)
+	}
+	return nil
+}
+
+func (n *typeJoinOn) Kind() string {
+	return "typeJoinOn"
+}
+
+func (n *typeJoinOn) Init() error {
+	if err := n.subType.Init(); err != nil {
+		return err
+	}
+	return n.root.Init()
+}
+
+func (n *typeJoinOn) Start() error {
+	if err := n.subType.Start(); err != nil {
+		return err
+	}
+	return n.root.Start()
+}
+
+func (n *typeJoinOn) Spans(spans core.Spans) {
+	n.root.Spans(spans)
+	n.subType.Spans(spans)
+}
+
+func (n *typeJoinOn) Next() (bool, error) {
+	hasNext, err := n.root.Next()
+	if err != nil || !hasNext {
+		return hasNext, err
+	}
+
+	n.currentValue = n.root.Value()
+
+	// Documents with no value for the local field don't join any document.
+	subdocs := make([]core.Doc, 0)
+	value := n.documentMapping.FirstOfName(n.currentValue, n.localField)
+	if value != nil {
+		fieldIndex := &mapper.PropertyIndex{
+			Index: n.subSelect.FirstIndexOfName(n.foreignField),
+		}
+		filter := map[connor.FilterKey]any{
+			fieldIndex: value,
+		}
+		err := appendFilterToScanNode(n.subType, filter)
+		if err != nil {
+			return false, err
+		}
+
+		// reset scan node
+		if err := n.subType.Init(); err != nil {
+			return false, err
+		}
+
+		for {
+			next, err := n.subType.Next()
+			if err != nil {
+				return false, err
+			}
+			if !next {
+				break
+			}
+			subdocs = append(subdocs, n.subType.Value())
+		}
+	}
+
+	n.currentValue.Fields[n.subSelect.Index] = subdocs
+	return true, nil
+}
+
+func (n *typeJoinOn) Close() error {
+	if err := n.root.Close(); err != nil {
+		return err
+	}
+
+	return n.subType.Close()
+}
+
+func (n *typeJoinOn) Source() planNode { return n.root }
+
 func appendFilterToScanNode(plan planNode, filterCondition map[connor.FilterKey]any) error {
 	switch node := plan.(type) {
 	case *scanNode:
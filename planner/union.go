@@ -0,0 +1,413 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package planner
+
+import (
+	"sort"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/client/request"
+	"github.com/sourcenetwork/defradb/connor"
+	"github.com/sourcenetwork/defradb/core"
+	"github.com/sourcenetwork/defradb/db/base"
+	"github.com/sourcenetwork/defradb/planner/mapper"
+)
+
+type unionExecInfo struct {
+	// Total number of times unionNode was executed.
+	iterations uint64
+
+	// Number of distinct documents found by the branches of the union.
+	docKeys uint64
+}
+
+// unionNode restricts the scan of a select whose filter is an `_or` of conditions to the documents
+// matching any of its branches, each branch being resolved to the keys of its documents separately.
+//
+// The branches filtering by `_key` are resolved to the given keys, and those filtering by a
+// relation whose related documents hold the foreign key are resolved by a scan of the related
+// documents matching the branch. The keys of the branches are then merged, deduplicating the
+// documents matching several branches, and the documents are fetched from their keys rather than
+// by a full scan of the collection. They are still filtered as the keys of a branch may match
+// more documents than the branch itself.
+type unionNode struct {
+	docMapper
+
+	p *Planner
+
+	// The source of the select, which fetches its documents from the scan.
+	source planNode
+	scan   *scanNode
+
+	branches []*unionBranch
+
+	// spanned is true if the spans of the scan have been replaced, in which case the scan is not
+	// restricted to the keys of the branches.
+	spanned bool
+
+	execInfo unionExecInfo
+}
+
+// unionBranch is a branch of the `_or` filter of a union, resolved to the keys of its documents.
+type unionBranch struct {
+	// The keys of the documents of the branch if it filters by `_key`.
+	docKeys []string
+
+	// The plan of the related documents matching the branch if it filters by a relation, along
+	// with the name of the relation field and the index of the foreign key of the related
+	// documents.
+	plan       planNode
+	fieldName  string
+	foreignKey string
+	fkIndex    int
+}
+
+// Union returns a union of the branches of the `_or` condition of the given filter of the given
+// select, restricting the given scan to the documents matching any of them.
+//
+// Returns false if the filter has no `_or` condition or if any of its branches cannot be resolved
+// to the keys of its documents, in which case the whole collection must be scanned.
+func (p *Planner) Union(
+	parent *selectNode,
+	scan *scanNode,
+	filter *mapper.Filter,
+) (*unionNode, bool, error) {
+	if filter == nil {
+		return nil, false, nil
+	}
+	var conditions []any
+	for key, condition := range filter.Conditions {
+		if op, ok := key.(*mapper.Operator); ok && op.Operation == "_or" {
+			conditions, _ = condition.([]any)
+		}
+	}
+	if len(conditions) == 0 {
+		return nil, false, nil
+	}
+	// The external conditions are only used to explain the plans of the branches.
+	externals, _ := filter.ExternalConditions["_or"].([]any)
+
+	branches := make([]*unionBranch, 0, len(conditions))
+	for i, condition := range conditions {
+		branchConditions, ok := condition.(map[connor.FilterKey]any)
+		if !ok {
+			return nil, false, nil
+		}
+		var external map[string]any
+		if i < len(externals) {
+			external, _ = externals[i].(map[string]any)
+		}
+		branch, ok, err := p.unionBranch(parent, branchConditions, external)
+		if err != nil || !ok {
+			return nil, false, err
+		}
+		branches = append(branches, branch)
+	}
+
+	return &unionNode{
+		p:         p,
+		source:    parent.source,
+		scan:      scan,
+		branches:  branches,
+		docMapper: parent.docMapper,
+	}, true, nil
+}
+
+// unionBranch resolves the given conditions of a branch of an `_or` filter of the given select to
+// the keys of the documents matching them, from any of the conditions as they must all match.
+//
+// Returns false if none of the conditions can be resolved.
+func (p *Planner) unionBranch(
+	parent *selectNode,
+	conditions map[connor.FilterKey]any,
+	external map[string]any,
+) (*unionBranch, bool, error) {
+	// The conditions are tried in the order of their properties, so that the plan does not depend
+	// on the order of the map, the keys being tried first.
+	props := []*mapper.PropertyIndex{}
+	for key := range conditions {
+		if prop, ok := key.(*mapper.PropertyIndex); ok {
+			props = append(props, prop)
+		}
+	}
+	sort.Slice(props, func(i, j int) bool { return props[i].Index < props[j].Index })
+
+	for _, prop := range props {
+		condition, ok := conditions[prop].(map[connor.FilterKey]any)
+		if !ok {
+			continue
+		}
+
+		if prop.Index == core.DocKeyFieldIndex {
+			if docKeys, ok := docKeysOfCondition(condition); ok {
+				return &unionBranch{docKeys: docKeys}, true, nil
+			}
+			continue
+		}
+
+		join, ok := joinedSelect(parent.selectReq, prop.Index)
+		if !ok {
+			continue
+		}
+		joinExternal, _ := external[join.Name].(map[string]any)
+		branch, ok, err := p.relationUnionBranch(parent, join, condition, joinExternal)
+		if err != nil || ok {
+			return branch, ok, err
+		}
+	}
+	return nil, false, nil
+}
+
+// relationUnionBranch resolves a branch filtering by the given joined select to the documents
+// referenced by the related documents matching the given conditions.
+//
+// Returns false if the documents of the select hold the foreign key of the relation, as it would
+// require a full scan to find those referencing the related documents.
+func (p *Planner) relationUnionBranch(
+	parent *selectNode,
+	join *mapper.Select,
+	conditions map[connor.FilterKey]any,
+	external map[string]any,
+) (*unionBranch, bool, error) {
+	fieldDesc, ok := parent.sourceInfo.collectionDescription.GetField(join.Name)
+	if !ok || fieldDesc.RelationType&client.Relation_Type_Primary > 0 {
+		return nil, false, nil
+	}
+	relatedDesc, err := p.getCollectionDesc(join.CollectionName)
+	if err != nil {
+		return nil, false, err
+	}
+	relatedField, ok := relatedDesc.GetRelation(fieldDesc.RelationName)
+	if !ok {
+		return nil, false, nil
+	}
+	foreignKey := relatedField.Name + "_id"
+	fkIndexes := join.DocumentMapping.IndexesByName[foreignKey]
+	if len(fkIndexes) == 0 {
+		return nil, false, nil
+	}
+
+	related := &mapper.Select{
+		Targetable: mapper.Targetable{
+			Field: join.Field,
+			Filter: &mapper.Filter{
+				Conditions:         conditions,
+				ExternalConditions: external,
+			},
+		},
+		CollectionName:  join.CollectionName,
+		DocumentMapping: join.DocumentMapping,
+		Fields:          join.Fields,
+	}
+	related.ShowDeleted = parent.selectReq.ShowDeleted
+	related.AtTime = parent.selectReq.AtTime
+
+	plan, err := p.SubSelect(related)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return &unionBranch{
+		plan:       plan,
+		fieldName:  join.Name,
+		foreignKey: foreignKey,
+		fkIndex:    fkIndexes[0],
+	}, true, nil
+}
+
+// docKeysOfCondition returns the keys given to the `_eq` or `_in` operator of the given condition
+// on the key of the documents.
+func docKeysOfCondition(condition map[connor.FilterKey]any) ([]string, bool) {
+	for key, value := range condition {
+		op, ok := key.(*mapper.Operator)
+		if !ok {
+			continue
+		}
+		switch op.Operation {
+		case "_eq":
+			if docKey, ok := value.(string); ok {
+				return []string{docKey}, true
+			}
+		case "_in":
+			values, ok := value.([]any)
+			if !ok {
+				continue
+			}
+			docKeys := make([]string, 0, len(values))
+			for _, v := range values {
+				docKey, ok := v.(string)
+				if !ok {
+					return nil, false
+				}
+				docKeys = append(docKeys, docKey)
+			}
+			return docKeys, true
+		}
+	}
+	return nil, false
+}
+
+// joinedSelect returns the select joined at the given index of the given select.
+func joinedSelect(slct *mapper.Select, index int) (*mapper.Select, bool) {
+	for _, field := range slct.Fields {
+		if join, ok := field.AsSelect(); ok && join.Index == index {
+			return join, true
+		}
+	}
+	return nil, false
+}
+
+func (n *unionNode) Kind() string {
+	return "unionNode"
+}
+
+func (n *unionNode) Init() error {
+	if !n.spanned {
+		spans, err := n.resolveSpans()
+		if err != nil {
+			return err
+		}
+		n.scan.Spans(spans)
+	}
+	return n.source.Init()
+}
+
+// resolveSpans returns the spans of the documents matching any of the branches of the union.
+func (n *unionNode) resolveSpans() (core.Spans, error) {
+	docKeys := map[string]struct{}{}
+	for _, branch := range n.branches {
+		for _, docKey := range branch.docKeys {
+			docKeys[docKey] = struct{}{}
+		}
+		if branch.plan == nil {
+			continue
+		}
+
+		if err := branch.plan.Init(); err != nil {
+			return core.Spans{}, err
+		}
+		if err := branch.plan.Start(); err != nil {
+			return core.Spans{}, err
+		}
+		for {
+			hasNext, err := branch.plan.Next()
+			if err != nil {
+				return core.Spans{}, err
+			}
+			if !hasNext {
+				break
+			}
+			if docKey, ok := branch.plan.Value().Fields[branch.fkIndex].(string); ok && docKey != "" {
+				docKeys[docKey] = struct{}{}
+			}
+		}
+	}
+	n.execInfo.docKeys = uint64(len(docKeys))
+
+	// An empty set of spans would scan the whole collection, so a span matching no document is
+	// used if no document matches any of the branches.
+	if len(docKeys) == 0 {
+		start := base.MakeCollectionKey(n.scan.desc)
+		return core.NewSpans(core.NewSpan(start, start)), nil
+	}
+
+	spans := make([]core.Span, 0, len(docKeys))
+	for docKey := range docKeys {
+		dockeyIndexKey := base.MakeDocKey(n.scan.desc, docKey)
+		spans = append(spans, core.NewSpan(dockeyIndexKey, dockeyIndexKey.PrefixEnd()))
+	}
+	return core.NewSpans(spans...), nil
+}
+
+func (n *unionNode) Start() error {
+	return n.source.Start()
+}
+
+// Spans replaces the spans of the scan, which is then no longer restricted to the documents of the
+// branches of the union.
+func (n *unionNode) Spans(spans core.Spans) {
+	n.spanned = true
+	n.source.Spans(spans)
+}
+
+func (n *unionNode) Next() (bool, error) {
+	n.execInfo.iterations++
+
+	return n.source.Next()
+}
+
+func (n *unionNode) Value() core.Doc {
+	return n.source.Value()
+}
+
+func (n *unionNode) Close() error {
+	for _, branch := range n.branches {
+		if branch.plan == nil {
+			continue
+		}
+		if err := branch.plan.Close(); err != nil {
+			return err
+		}
+	}
+	return n.source.Close()
+}
+
+func (n *unionNode) Source() planNode { return n.source }
+
+func (n *unionNode) simpleExplain() (map[string]any, error) {
+	const (
+		branchesLabel   = "branches"
+		foreignKeyLabel = "foreignKey"
+		planLabel       = "plan"
+	)
+
+	branches := make([]map[string]any, 0, len(n.branches))
+	for _, branch := range n.branches {
+		if branch.plan == nil {
+			branches = append(branches, map[string]any{
+				idsLabel: branch.docKeys,
+			})
+			continue
+		}
+
+		planExplainGraph, err := buildSimpleExplainGraph(branch.plan)
+		if err != nil {
+			return nil, err
+		}
+		branches = append(branches, map[string]any{
+			fieldNameLabel:  branch.fieldName,
+			foreignKeyLabel: branch.foreignKey,
+			planLabel:       planExplainGraph,
+		})
+	}
+
+	return map[string]any{
+		branchesLabel: branches,
+	}, nil
+}
+
+// Explain method returns a map containing all attributes of this node that
+// are to be explained, subscribes / opts-in this node to be an explainablePlanNode.
+func (n *unionNode) Explain(explainType request.ExplainType) (map[string]any, error) {
+	switch explainType {
+	case request.SimpleExplain:
+		return n.simpleExplain()
+
+	case request.ExecuteExplain:
+		return map[string]any{
+			"iterations": n.execInfo.iterations,
+			"docKeys":    n.execInfo.docKeys,
+		}, nil
+
+	default:
+		return nil, ErrUnknownExplainRequestType
+	}
+}
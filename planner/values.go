@@ -13,6 +13,11 @@ package planner
 import (
 	"sort"
 
+	"github.com/sourcenetwork/immutable"
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+
+	"github.com/sourcenetwork/defradb/client/request"
 	"github.com/sourcenetwork/defradb/core"
 	"github.com/sourcenetwork/defradb/db/base"
 	"github.com/sourcenetwork/defradb/db/container"
@@ -32,15 +37,22 @@ type valuesNode struct {
 	// plan planNode
 
 	ordering []mapper.OrderCondition
+	// collator compares the strings by the requested collation, or is nil if they are
+	// compared by their bytes.
+	collator *collate.Collator
 
 	docs     *container.DocumentContainer
 	docIndex int
 }
 
-func (p *Planner) newContainerValuesNode(ordering []mapper.OrderCondition) *valuesNode {
+func (p *Planner) newContainerValuesNode(
+	ordering []mapper.OrderCondition,
+	collator *collate.Collator,
+) *valuesNode {
 	return &valuesNode{
 		p:        p,
 		ordering: ordering,
+		collator: collator,
 		docs:     container.NewDocumentContainer(0),
 		docIndex: -1,
 	}
@@ -92,28 +104,59 @@ func (n *valuesNode) Less(i, j int) bool {
 
 // docValueLess extracts and compare field values of a document, returns true only if strictly less when ASC,
 // and true if greater than or equal when DESC, otherwise returns false.
+//
+// Documents that are equal by all the orderings are ordered by ascending key, so that their order
+// doesn't depend on the order in which they were scanned, which may differ between nodes.
 func (n *valuesNode) docValueLess(docA, docB core.Doc) bool {
 	for _, order := range n.ordering {
-		compare := base.Compare(
+		compare := compareValues(
 			getDocProp(docA, order.FieldIndexes),
 			getDocProp(docB, order.FieldIndexes),
+			n.collator,
 		)
+		if compare == 0 {
+			continue
+		}
 
 		if order.Direction == mapper.DESC {
-			if compare > 0 {
-				return true
-			} else {
-				return false
-			}
-		} else { // Otherwise assume order.Direction == mapper.ASC
-			if compare < 0 {
-				return true
-			} else {
-				return false
+			return compare > 0
+		}
+		// Otherwise assume order.Direction == mapper.ASC
+		return compare < 0
+	}
+	return docA.GetKey() < docB.GetKey()
+}
+
+// newCollator returns the collator comparing strings by the given collation, or nil if there
+// is none and they are compared by their bytes.
+func newCollator(collation immutable.Option[request.Collation]) *collate.Collator {
+	if !collation.HasValue() {
+		return nil
+	}
+
+	var options []collate.Option
+	if collation.Value().CaseInsensitive {
+		options = append(options, collate.IgnoreCase)
+	}
+	if collation.Value().Numeric {
+		options = append(options, collate.Numeric)
+	}
+	// The locale is validated when the request is parsed, and the root collation is used if
+	// it is empty.
+	return collate.New(language.Make(collation.Value().Locale), options...)
+}
+
+// compareValues compares the given values like base.Compare, comparing strings with the
+// given collator if it isn't nil.
+func compareValues(a, b any, collator *collate.Collator) int {
+	if collator != nil {
+		if strA, ok := a.(string); ok {
+			if strB, ok := b.(string); ok {
+				return collator.CompareString(strA, strB)
 			}
 		}
 	}
-	return false
+	return base.Compare(a, b)
 }
 
 // Swap implements the golang sort.Sort interface.
@@ -65,6 +65,11 @@ func (p *parser) IsIntrospection(ast *ast.Document) bool {
 	return defrap.IsIntrospectionQuery(*schema, ast)
 }
 
+func (p *parser) UnknownQueryFields(ast *ast.Document) []string {
+	schema := p.schemaManager.Schema()
+	return defrap.UnknownQueryFields(*schema, ast)
+}
+
 func (p *parser) ExecuteIntrospection(request string) *client.RequestResult {
 	schema := p.schemaManager.Schema()
 	params := gql.Params{Schema: *schema, RequestString: request}
@@ -84,9 +89,9 @@ func (p *parser) ExecuteIntrospection(request string) *client.RequestResult {
 	return res
 }
 
-func (p *parser) Parse(ast *ast.Document) (*request.Request, []error) {
+func (p *parser) Parse(ast *ast.Document, variables map[string]any) (*request.Request, []error) {
 	schema := p.schemaManager.Schema()
-	validationResult := gql.ValidateDocument(schema, ast, nil)
+	validationResult := gql.ValidateDocument(schema, ast, validationRules)
 	if !validationResult.IsValid {
 		errors := make([]error, len(validationResult.Errors))
 		for i, err := range validationResult.Errors {
@@ -95,7 +100,7 @@ func (p *parser) Parse(ast *ast.Document) (*request.Request, []error) {
 		return nil, errors
 	}
 
-	query, parsingErrors := defrap.ParseRequest(*schema, ast)
+	query, parsingErrors := defrap.ParseRequest(*schema, ast, variables)
 	if len(parsingErrors) > 0 {
 		return nil, parsingErrors
 	}
@@ -40,6 +40,9 @@ func parseCommitSelect(schema gql.Schema, parent *gql.Object, field *ast.Field)
 		} else if prop == request.FieldName {
 			raw := argument.Value.(*ast.StringValue)
 			commit.FieldName = immutable.Some(raw.Value)
+		} else if prop == request.FieldNameFieldName {
+			raw := argument.Value.(*ast.StringValue)
+			commit.FieldNameFilter = immutable.Some(raw.Value)
 		} else if prop == request.OrderClause {
 			obj := argument.Value.(*ast.ObjectValue)
 			cond, err := ParseConditionsInOrder(obj)
@@ -94,7 +97,7 @@ func parseCommitSelect(schema gql.Schema, parent *gql.Object, field *ast.Field)
 		// values
 		commit.Depth = immutable.Some(uint64(1))
 
-		if !commit.FieldName.HasValue() {
+		if !commit.FieldName.HasValue() && !commit.FieldNameFilter.HasValue() {
 			// latest commits defaults to composite commits only at the moment
 			commit.FieldName = immutable.Some(core.COMPOSITE_NAMESPACE)
 		}
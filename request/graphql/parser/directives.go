@@ -0,0 +1,93 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package parser
+
+import (
+	gql "github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// variableValues returns the values of the variables of the given operation, by variable name.
+//
+// The variables that aren't given a value take the default value of their definition, if any, as
+// an AST value.
+func variableValues(def *ast.OperationDefinition, variables map[string]any) map[string]any {
+	values := make(map[string]any, len(variables))
+	for name, value := range variables {
+		values[name] = value
+	}
+	for _, varDef := range def.VariableDefinitions {
+		name := varDef.Variable.Name.Value
+		if _, ok := values[name]; ok || varDef.DefaultValue == nil {
+			continue
+		}
+		values[name] = varDef.DefaultValue
+	}
+	return values
+}
+
+// shouldInclude returns false if the given directives hold an @skip directive whose condition
+// is true, or an @include directive whose condition is false.
+func shouldInclude(directives []*ast.Directive, variables map[string]any) (bool, error) {
+	for _, directive := range directives {
+		switch directive.Name.Value {
+		case gql.SkipDirective.Name:
+			skip, err := directiveCondition(directive, variables)
+			if err != nil {
+				return false, err
+			}
+			if skip {
+				return false, nil
+			}
+
+		case gql.IncludeDirective.Name:
+			include, err := directiveCondition(directive, variables)
+			if err != nil {
+				return false, err
+			}
+			if !include {
+				return false, nil
+			}
+		}
+	}
+	return true, nil
+}
+
+// directiveCondition returns the value of the `if` argument of the given @skip or @include
+// directive.
+func directiveCondition(directive *ast.Directive, variables map[string]any) (bool, error) {
+	for _, argument := range directive.Arguments {
+		if argument.Name.Value != "if" {
+			continue
+		}
+
+		switch value := argument.Value.(type) {
+		case *ast.BooleanValue:
+			return value.Value, nil
+
+		case *ast.Variable:
+			name := value.Name.Value
+			variable, ok := variables[name]
+			if !ok {
+				return false, NewErrMissingVariableValue(name)
+			}
+			if defaultValue, ok := variable.(ast.Value); ok {
+				variable = defaultValue.GetValue()
+			}
+			condition, ok := variable.(bool)
+			if !ok {
+				return false, NewErrInvalidDirectiveCondition(directive.Name.Value, variable)
+			}
+			return condition, nil
+		}
+	}
+	return false, NewErrInvalidDirectiveCondition(directive.Name.Value, nil)
+}
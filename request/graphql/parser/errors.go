@@ -12,6 +12,17 @@ package parser
 
 import "github.com/sourcenetwork/defradb/errors"
 
+const (
+	errMissingVariableValue      string = "no value was given for the variable"
+	errInvalidDirectiveCondition string = "the if argument of the directive must be a boolean"
+	errUnknownFragment           string = "unknown fragment"
+	errInvalidDepth              string = "the depth must be greater than zero"
+	errInvalidAtTime             string = "the atTime argument must be an RFC3339 time"
+	errInvalidWindow             string = "the window must be greater than zero"
+	errNestedRunningAggregate    string = "running aggregates cannot target fields nested within related objects"
+	errInvalidCollationLocale    string = "the locale of the collation must be a BCP 47 language tag"
+)
+
 var (
 	ErrFilterMissingArgumentType      = errors.New("couldn't find filter argument type")
 	ErrInvalidOrderDirection          = errors.New("invalid order direction string")
@@ -23,4 +34,64 @@ var (
 	ErrInvalidNumberOfExplainArgs     = errors.New("invalid number of arguments to an explain request")
 	ErrUnknownExplainType             = errors.New("invalid / unknown explain type")
 	ErrUnknownGQLOperation            = errors.New("unknown GraphQL operation type")
+	ErrMissingVariableValue           = errors.New(errMissingVariableValue)
+	ErrInvalidDirectiveCondition      = errors.New(errInvalidDirectiveCondition)
+	ErrUnknownFragment                = errors.New(errUnknownFragment)
+	ErrInvalidDepth                   = errors.New(errInvalidDepth)
+	ErrInvalidAtTime                  = errors.New(errInvalidAtTime)
+	ErrCidWithAtTime                  = errors.New("the cid and atTime arguments cannot be used together")
+	ErrInvalidWindow                  = errors.New(errInvalidWindow)
+	ErrNestedRunningAggregate         = errors.New(errNestedRunningAggregate)
+	ErrInvalidCollationLocale         = errors.New(errInvalidCollationLocale)
 )
+
+// NewErrMissingVariableValue returns an error indicating that no value was given for the
+// variable with the given name.
+func NewErrMissingVariableValue(name string) error {
+	return errors.New(errMissingVariableValue, errors.NewKV("Variable", name))
+}
+
+// NewErrInvalidDirectiveCondition returns an error indicating that the condition of the
+// directive with the given name isn't a boolean.
+func NewErrInvalidDirectiveCondition(directive string, value any) error {
+	return errors.New(
+		errInvalidDirectiveCondition,
+		errors.NewKV("Directive", directive),
+		errors.NewKV("Value", value),
+	)
+}
+
+// NewErrUnknownFragment returns an error indicating that no fragment is defined with the given name.
+func NewErrUnknownFragment(name string) error {
+	return errors.New(errUnknownFragment, errors.NewKV("Fragment", name))
+}
+
+// NewErrInvalidAtTime returns an error indicating that the given atTime argument value could not
+// be parsed as a time.
+func NewErrInvalidAtTime(value string, inner error) error {
+	return errors.Wrap(errInvalidAtTime, inner, errors.NewKV("Value", value))
+}
+
+// NewErrInvalidDepth returns an error indicating that the depth requested for the field with
+// the given name is invalid.
+func NewErrInvalidDepth(field string, depth uint64) error {
+	return errors.New(errInvalidDepth, errors.NewKV("Field", field), errors.NewKV("Depth", depth))
+}
+
+// NewErrInvalidWindow returns an error indicating that the window requested for the target of
+// the aggregate with the given name is invalid.
+func NewErrInvalidWindow(aggregate string, window uint64) error {
+	return errors.New(errInvalidWindow, errors.NewKV("Aggregate", aggregate), errors.NewKV("Window", window))
+}
+
+// NewErrNestedRunningAggregate returns an error indicating that the running aggregate with the
+// given name targets a field nested within the related objects of the given host.
+func NewErrNestedRunningAggregate(aggregate string, host string) error {
+	return errors.New(errNestedRunningAggregate, errors.NewKV("Aggregate", aggregate), errors.NewKV("Host", host))
+}
+
+// NewErrInvalidCollationLocale returns an error indicating that the given locale of a collation
+// could not be parsed as a language tag.
+func NewErrInvalidCollationLocale(locale string, inner error) error {
+	return errors.Wrap(errInvalidCollationLocale, inner, errors.NewKV("Locale", locale))
+}
@@ -67,6 +67,34 @@ func NewFilterFromString(
 
 type parseFn func(*ast.ObjectValue) (any, error)
 
+// ParseOrderBy parses the conditions of the given order argument, which is either an object
+// ordering by each of its fields in turn, or a list of them ordering by the fields of each
+// object in turn, e.g. `order: [{Age: DESC}, {Name: ASC}]`.
+func ParseOrderBy(value ast.Value) ([]request.OrderCondition, error) {
+	switch v := value.(type) {
+	case *ast.ObjectValue:
+		return ParseConditionsInOrder(v)
+
+	case *ast.ListValue:
+		conditions := make([]request.OrderCondition, 0, len(v.Values))
+		for _, item := range v.Values {
+			obj, ok := item.(*ast.ObjectValue)
+			if !ok {
+				return nil, client.NewErrUnexpectedType[*ast.ObjectValue]("order", item)
+			}
+			cond, err := ParseConditionsInOrder(obj)
+			if err != nil {
+				return nil, err
+			}
+			conditions = append(conditions, cond...)
+		}
+		return conditions, nil
+
+	default:
+		return nil, client.NewErrUnexpectedType[*ast.ObjectValue]("order", value)
+	}
+}
+
 // ParseConditionsInOrder is similar to ParseConditions, except instead
 // of returning a map[string]any, we return a []any. This
 // is to maintain the ordering info of the statements within the ObjectValue.
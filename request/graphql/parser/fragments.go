@@ -0,0 +1,154 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package parser
+
+import (
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// fragmentDefinitions returns the named fragments defined in the given document, by name.
+func fragmentDefinitions(doc *ast.Document) map[string]*ast.FragmentDefinition {
+	fragments := map[string]*ast.FragmentDefinition{}
+	for _, def := range doc.Definitions {
+		if fragment, isFragment := def.(*ast.FragmentDefinition); isFragment {
+			fragments[fragment.Name.Value] = fragment
+		}
+	}
+	return fragments
+}
+
+// expandSelections replaces the fragment spreads and inline fragments of the given selection
+// set, and of its child selection sets, with the fields they select, and removes the selections
+// excluded by their @skip or @include directives.
+//
+// The fields selected more than once under the same name are merged into a single field, holding
+// the selections of all of them. The variables of the arguments of the fields are replaced by their
+// values.
+func expandSelections(
+	selectionSet *ast.SelectionSet,
+	fragments map[string]*ast.FragmentDefinition,
+	variables map[string]any,
+) error {
+	if selectionSet == nil {
+		return nil
+	}
+
+	collector := &fieldCollector{
+		fragments:    fragments,
+		variables:    variables,
+		fieldIndexes: map[string]int{},
+	}
+	err := collector.collect(selectionSet)
+	if err != nil {
+		return err
+	}
+
+	selections := make([]ast.Selection, len(collector.fields))
+	for i, field := range collector.fields {
+		err := expandSelections(field.SelectionSet, fragments, variables)
+		if err != nil {
+			return err
+		}
+		selections[i] = field
+	}
+	selectionSet.Selections = selections
+	return nil
+}
+
+// fieldCollector collects the fields selected by a selection set, including those selected
+// by its fragments.
+type fieldCollector struct {
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]any
+
+	// The collected fields, in order of selection. They are copies of the selected fields, so
+	// that the fields of the fragments used more than once aren't modified when merged.
+	fields []*ast.Field
+	// The index of each collected field, by response name.
+	fieldIndexes map[string]int
+}
+
+// collect collects the fields selected by the given selection set.
+func (c *fieldCollector) collect(selectionSet *ast.SelectionSet) error {
+	for _, selection := range selectionSet.Selections {
+		switch node := selection.(type) {
+		case *ast.Field:
+			include, err := shouldInclude(node.Directives, c.variables)
+			if err != nil {
+				return err
+			}
+			if include {
+				c.add(node)
+			}
+
+		case *ast.FragmentSpread:
+			include, err := shouldInclude(node.Directives, c.variables)
+			if err != nil {
+				return err
+			}
+			if !include {
+				continue
+			}
+			fragment, ok := c.fragments[node.Name.Value]
+			if !ok {
+				return NewErrUnknownFragment(node.Name.Value)
+			}
+			err = c.collect(fragment.SelectionSet)
+			if err != nil {
+				return err
+			}
+
+		case *ast.InlineFragment:
+			include, err := shouldInclude(node.Directives, c.variables)
+			if err != nil {
+				return err
+			}
+			if !include {
+				continue
+			}
+			err = c.collect(node.SelectionSet)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// add adds the given field to the collected fields, merging its selections into those of the
+// collected field of the same response name if any.
+func (c *fieldCollector) add(field *ast.Field) {
+	name := field.Name.Value
+	if field.Alias != nil {
+		name = field.Alias.Value
+	}
+
+	index, exists := c.fieldIndexes[name]
+	if !exists {
+		copied := *field
+		copied.Arguments = InlineVariables(field.Arguments, c.variables)
+		if field.SelectionSet != nil {
+			copied.SelectionSet = &ast.SelectionSet{
+				Kind:       field.SelectionSet.Kind,
+				Loc:        field.SelectionSet.Loc,
+				Selections: append([]ast.Selection{}, field.SelectionSet.Selections...),
+			}
+		}
+		c.fieldIndexes[name] = len(c.fields)
+		c.fields = append(c.fields, &copied)
+		return
+	}
+
+	existing := c.fields[index]
+	if existing.SelectionSet != nil && field.SelectionSet != nil {
+		existing.SelectionSet.Selections = append(existing.SelectionSet.Selections, field.SelectionSet.Selections...)
+	}
+}
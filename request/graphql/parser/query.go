@@ -12,10 +12,13 @@ package parser
 
 import (
 	"strconv"
+	"strings"
+	"time"
 
 	gql "github.com/graphql-go/graphql"
 	"github.com/graphql-go/graphql/language/ast"
 	"github.com/sourcenetwork/immutable"
+	"golang.org/x/text/language"
 
 	"github.com/sourcenetwork/defradb/client"
 
@@ -105,6 +108,11 @@ func parseSelect(
 
 	fieldDef := gql.GetFieldDef(schema, parent, slct.Name)
 
+	var join request.Join
+	if strings.HasPrefix(slct.Name, request.JoinFieldPrefix) {
+		join.Collection = strings.TrimPrefix(slct.Name, request.JoinFieldPrefix)
+	}
+
 	// parse arguments
 	for _, argument := range field.Arguments {
 		prop := argument.Name.Value
@@ -137,6 +145,13 @@ func parseSelect(
 		case request.Cid: // parse single CID query field
 			val := astValue.(*ast.StringValue)
 			slct.CID = immutable.Some(val.Value)
+		case request.AtTime:
+			val := astValue.(*ast.StringValue)
+			atTime, err := time.Parse(time.RFC3339, val.Value)
+			if err != nil {
+				return nil, NewErrInvalidAtTime(val.Value, err)
+			}
+			slct.AtTime = immutable.Some(atTime)
 		case request.LimitClause: // parse limit/offset
 			val := astValue.(*ast.IntValue)
 			limit, err := strconv.ParseUint(val.Value, 10, 64)
@@ -152,8 +167,7 @@ func parseSelect(
 			}
 			slct.Offset = immutable.Some(offset)
 		case request.OrderClause: // parse order by
-			obj := astValue.(*ast.ObjectValue)
-			cond, err := ParseConditionsInOrder(obj)
+			cond, err := ParseOrderBy(astValue)
 			if err != nil {
 				return nil, err
 			}
@@ -162,6 +176,12 @@ func parseSelect(
 					Conditions: cond,
 				},
 			)
+		case request.CollationClause:
+			collation, err := parseCollation(astValue.(*ast.ObjectValue))
+			if err != nil {
+				return nil, err
+			}
+			slct.Collation = immutable.Some(collation)
 		case request.GroupByClause:
 			obj := astValue.(*ast.ListValue)
 			fields := make([]string, 0)
@@ -177,9 +197,38 @@ func parseSelect(
 		case request.ShowDeleted:
 			val := astValue.(*ast.BooleanValue)
 			slct.ShowDeleted = val.Value
+		case request.DepthClause:
+			val := astValue.(*ast.IntValue)
+			depth, err := strconv.ParseUint(val.Value, 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			if depth == 0 {
+				return nil, NewErrInvalidDepth(slct.Name, depth)
+			}
+			slct.Depth = immutable.Some(depth)
+		case request.IncludeMissingClause:
+			val := astValue.(*ast.BooleanValue)
+			slct.IncludeMissing = immutable.Some(val.Value)
+		case request.LocalFieldClause:
+			join.LocalField = astValue.(*ast.EnumValue).Value
+		case request.ForeignFieldClause:
+			join.ForeignField = astValue.(*ast.EnumValue).Value
+		case request.FieldName:
+			if rootType == request.HistorySelection {
+				slct.HistoryField = immutable.Some(astValue.(*ast.EnumValue).Value)
+			}
 		}
 	}
 
+	if join.Collection != "" {
+		slct.Join = immutable.Some(join)
+	}
+
+	if slct.CID.HasValue() && slct.AtTime.HasValue() {
+		return nil, ErrCidWithAtTime
+	}
+
 	// if theres no field selections, just return
 	if field.SelectionSet == nil {
 		return slct, nil
@@ -196,9 +245,69 @@ func parseSelect(
 		return nil, err
 	}
 
+	if slct.Depth.HasValue() {
+		err = expandDepth(schema, slct, fieldObject, field)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return slct, err
 }
 
+// expandDepth expands the given self-referencing select to the depth requested, by selecting
+// it again within its own fields, with the same arguments and fields, down to its last level.
+func expandDepth(schema gql.Schema, slct *request.Select, fieldObject *gql.Object, field *ast.Field) error {
+	parent := slct
+	for depth := slct.Depth.Value() - 1; depth > 0; depth-- {
+		// The fields are parsed again for each level so that the levels don't share them.
+		fields, err := parseSelectFields(schema, slct.Root, fieldObject, field.SelectionSet)
+		if err != nil {
+			return err
+		}
+
+		child := &request.Select{
+			Field:       slct.Field,
+			DocKeys:     slct.DocKeys,
+			CID:         slct.CID,
+			Root:        slct.Root,
+			Limit:       slct.Limit,
+			Offset:      slct.Offset,
+			OrderBy:     slct.OrderBy,
+			GroupBy:     slct.GroupBy,
+			Filter:      slct.Filter,
+			Collation:   slct.Collation,
+			Fields:      fields,
+			ShowDeleted: slct.ShowDeleted,
+			Depth:       immutable.Some(depth),
+
+			IncludeMissing: slct.IncludeMissing,
+		}
+		parent.Fields = append(parent.Fields, child)
+		parent = child
+	}
+	return nil
+}
+
+// parseCollation parses the given collation argument, validating its locale.
+func parseCollation(obj *ast.ObjectValue) (request.Collation, error) {
+	var collation request.Collation
+	for _, field := range obj.Fields {
+		switch field.Name.Value {
+		case request.LocaleArgName:
+			collation.Locale = field.Value.(*ast.StringValue).Value
+			if _, err := language.Parse(collation.Locale); err != nil {
+				return request.Collation{}, NewErrInvalidCollationLocale(collation.Locale, err)
+			}
+		case request.CaseInsensitiveArgName:
+			collation.CaseInsensitive = field.Value.(*ast.BooleanValue).Value
+		case request.NumericArgName:
+			collation.Numeric = field.Value.(*ast.BooleanValue).Value
+		}
+	}
+	return collation, nil
+}
+
 func parseAggregate(schema gql.Schema, parent *gql.Object, field *ast.Field, index int) (*request.Aggregate, error) {
 	targets := make([]*request.AggregateTarget, len(field.Arguments))
 
@@ -217,10 +326,12 @@ func parseAggregate(schema gql.Schema, parent *gql.Object, field *ast.Field, ind
 			var order immutable.Option[request.OrderBy]
 
 			fieldArg, hasFieldArg := tryGet(argumentValue, request.FieldName)
+			var childPath []string
 			if hasFieldArg {
-				if innerPathStringValue, isString := fieldArg.Value.GetValue().(string); isString {
-					childName = innerPathStringValue
-				}
+				childPath, childName = parseAggregateChildPath(fieldArg.Value)
+			}
+			if _, isRunning := request.RunningAggregates[field.Name.Value]; isRunning && len(childPath) > 0 {
+				return nil, NewErrNestedRunningAggregate(field.Name.Value, hostName)
 			}
 
 			filterArg, hasFilterArg := tryGet(argumentValue, request.FilterClause)
@@ -258,6 +369,19 @@ func parseAggregate(schema gql.Schema, parent *gql.Object, field *ast.Field, ind
 				limit = immutable.Some(limitValue)
 			}
 
+			var window immutable.Option[uint64]
+			windowArg, hasWindowArg := tryGet(argumentValue, request.WindowClause)
+			if hasWindowArg {
+				windowValue, err := strconv.ParseUint(windowArg.Value.(*ast.IntValue).Value, 10, 64)
+				if err != nil {
+					return nil, err
+				}
+				if windowValue == 0 {
+					return nil, NewErrInvalidWindow(field.Name.Value, windowValue)
+				}
+				window = immutable.Some(windowValue)
+			}
+
 			offsetArg, hasOffsetArg := tryGet(argumentValue, request.OffsetClause)
 			if hasOffsetArg {
 				offsetValue, err := strconv.ParseUint(offsetArg.Value.(*ast.IntValue).Value, 10, 64)
@@ -285,12 +409,12 @@ func parseAggregate(schema gql.Schema, parent *gql.Object, field *ast.Field, ind
 						},
 					)
 
-				case *ast.ObjectValue:
-					// For relations the order arg will be the complex order object as used by the host object
-					// for non-aggregate ordering
+				case *ast.ObjectValue, *ast.ListValue:
+					// For relations the order arg will be the complex order object, or list of them, as
+					// used by the host object for non-aggregate ordering
 
 					// We use the parser package parsing for convienience here
-					orderConditions, err := ParseConditionsInOrder(orderArgValue)
+					orderConditions, err := ParseOrderBy(orderArgValue)
 					if err != nil {
 						return nil, err
 					}
@@ -306,10 +430,12 @@ func parseAggregate(schema gql.Schema, parent *gql.Object, field *ast.Field, ind
 			targets[i] = &request.AggregateTarget{
 				HostName:  hostName,
 				ChildName: immutable.Some(childName),
+				ChildPath: childPath,
 				Filter:    filter,
 				Limit:     limit,
 				Offset:    offset,
 				OrderBy:   order,
+				Window:    window,
 			}
 		}
 	}
@@ -322,3 +448,52 @@ func parseAggregate(schema gql.Schema, parent *gql.Object, field *ast.Field, ind
 		Targets: targets,
 	}, nil
 }
+
+// parseAggregateChildPath returns the relations leading to the aggregated child and the name
+// of the child, from the given `field` argument value of an aggregate target.
+//
+// The child is either named directly (e.g. `rating`), or nested within the relations it is
+// reached through (e.g. `{reviews: rating}`).
+func parseAggregateChildPath(value ast.Value) ([]string, string) {
+	var childPath []string
+	for {
+		switch typedValue := value.GetValue().(type) {
+		case string:
+			return childPath, typedValue
+		case []*ast.ObjectField:
+			// The schema only accepts a single relation per level.
+			if len(typedValue) != 1 {
+				return childPath, ""
+			}
+			childPath = append(childPath, typedValue[0].Name.Value)
+			value = typedValue[0].Value
+		default:
+			return childPath, ""
+		}
+	}
+}
+
+// UnknownQueryFields returns the names of the top-level fields of the query operations of the
+// given document that are not fields of the query type of the given schema, such as the names
+// of the collections the schema does not hold.
+func UnknownQueryFields(schema gql.Schema, doc *ast.Document) []string {
+	var names []string
+	fields := schema.QueryType().Fields()
+	for _, def := range doc.Definitions {
+		astOpDef, isOpDef := def.(*ast.OperationDefinition)
+		if !isOpDef || astOpDef.Operation != ast.OperationTypeQuery {
+			continue
+		}
+
+		for _, selection := range astOpDef.SelectionSet.Selections {
+			node, isField := selection.(*ast.Field)
+			if !isField || strings.HasPrefix(node.Name.Value, "__") {
+				continue
+			}
+			if _, isKnown := fields[node.Name.Value]; !isKnown {
+				names = append(names, node.Name.Value)
+			}
+		}
+	}
+	return names
+}
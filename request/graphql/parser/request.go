@@ -23,7 +23,9 @@ import (
 
 // ParseRequest parses a root ast.Document, and returns a formatted Request object.
 // Requires a non-nil doc, will error otherwise.
-func ParseRequest(schema gql.Schema, doc *ast.Document) (*request.Request, []error) {
+//
+// The given variables hold the values of the variables of the request, by variable name.
+func ParseRequest(schema gql.Schema, doc *ast.Document, variables map[string]any) (*request.Request, []error) {
 	if doc == nil {
 		return nil, []error{client.NewErrUninitializeProperty("ParseRequest", "doc")}
 	}
@@ -34,12 +36,18 @@ func ParseRequest(schema gql.Schema, doc *ast.Document) (*request.Request, []err
 		Subscription: make([]*request.OperationDefinition, 0),
 	}
 
+	fragments := fragmentDefinitions(doc)
 	for _, def := range doc.Definitions {
 		astOpDef, isOpDef := def.(*ast.OperationDefinition)
 		if !isOpDef {
 			continue
 		}
 
+		err := expandSelections(astOpDef.SelectionSet, fragments, variableValues(astOpDef, variables))
+		if err != nil {
+			return nil, []error{err}
+		}
+
 		switch astOpDef.Operation {
 		case ast.OperationTypeQuery:
 			parsedQueryOpDef, errs := parseQueryOperationDefinition(schema, astOpDef)
@@ -180,6 +188,10 @@ func parseSelectFields(
 				switch node.Name.Value {
 				case request.VersionFieldName:
 					subroot = request.CommitSelection
+				case request.HistoryFieldName:
+					subroot = request.HistorySelection
+				case request.MetaFieldName:
+					subroot = request.MetaSelection
 				}
 
 				s, err := parseSelect(schema, subroot, parent, node, i)
@@ -0,0 +1,139 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// InlineVariables returns the given arguments with the variables of their values replaced by the
+// given values of the variables, so that the arguments are parsed the same whether their values
+// are given inline or with variables.
+//
+// The arguments whose value is a variable that isn't given a value are dropped, as if they
+// weren't given.
+func InlineVariables(arguments []*ast.Argument, variables map[string]any) []*ast.Argument {
+	inlined := make([]*ast.Argument, 0, len(arguments))
+	for _, argument := range arguments {
+		value, ok := inlineValue(argument.Value, variables)
+		if !ok {
+			continue
+		}
+		copied := *argument
+		copied.Value = value
+		inlined = append(inlined, &copied)
+	}
+	return inlined
+}
+
+// inlineValue returns the given value with its variables replaced by their given values, or false
+// if the value is a variable that isn't given a value.
+func inlineValue(value ast.Value, variables map[string]any) (ast.Value, bool) {
+	switch v := value.(type) {
+	case *ast.Variable:
+		variable, ok := variables[v.Name.Value]
+		if !ok {
+			return nil, false
+		}
+		return astValue(variable), true
+
+	case *ast.ObjectValue:
+		fields := make([]*ast.ObjectField, 0, len(v.Fields))
+		for _, field := range v.Fields {
+			fieldValue, ok := inlineValue(field.Value, variables)
+			if !ok {
+				continue
+			}
+			fields = append(fields, ast.NewObjectField(&ast.ObjectField{Name: field.Name, Value: fieldValue}))
+		}
+		return ast.NewObjectValue(&ast.ObjectValue{Fields: fields}), true
+
+	case *ast.ListValue:
+		values := make([]ast.Value, len(v.Values))
+		for i, item := range v.Values {
+			itemValue, ok := inlineValue(item, variables)
+			if !ok {
+				itemValue = ast.NewNullValue(&ast.NullValue{})
+			}
+			values[i] = itemValue
+		}
+		return ast.NewListValue(&ast.ListValue{Values: values}), true
+
+	default:
+		return value, true
+	}
+}
+
+// astValue returns the AST value of the given value of a variable, such as a value decoded from
+// JSON, or the default value of its definition.
+//
+// The fields of the objects are ordered by name, as the decoded objects don't keep the order of
+// their fields. The orderings by several fields given with a variable must thus be given as a
+// list of them, e.g. `[{Age: DESC}, {Name: ASC}]`.
+func astValue(value any) ast.Value {
+	switch v := value.(type) {
+	case ast.Value:
+		return v
+	case nil:
+		return ast.NewNullValue(&ast.NullValue{})
+	case bool:
+		return ast.NewBooleanValue(&ast.BooleanValue{Value: v})
+	case string:
+		return ast.NewStringValue(&ast.StringValue{Value: v})
+	case int:
+		return ast.NewIntValue(&ast.IntValue{Value: strconv.Itoa(v)})
+	case int64:
+		return ast.NewIntValue(&ast.IntValue{Value: strconv.FormatInt(v, 10)})
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+			return ast.NewIntValue(&ast.IntValue{Value: strconv.FormatFloat(v, 'f', -1, 64)})
+		}
+		return ast.NewFloatValue(&ast.FloatValue{Value: strconv.FormatFloat(v, 'g', -1, 64)})
+	case map[string]any:
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fields := make([]*ast.ObjectField, len(names))
+		for i, name := range names {
+			fields[i] = ast.NewObjectField(&ast.ObjectField{
+				Name:  ast.NewName(&ast.Name{Value: name}),
+				Value: astValue(v[name]),
+			})
+		}
+		return ast.NewObjectValue(&ast.ObjectValue{Fields: fields})
+	case []any:
+		values := make([]ast.Value, len(v))
+		for i, item := range v {
+			values[i] = astValue(item)
+		}
+		return ast.NewListValue(&ast.ListValue{Values: values})
+	default:
+		// The other values, such as typed slices and maps, are given the form of their JSON
+		// encoding.
+		buf, err := json.Marshal(v)
+		if err != nil {
+			return ast.NewStringValue(&ast.StringValue{Value: fmt.Sprint(v)})
+		}
+		var decoded any
+		if err := json.Unmarshal(buf, &decoded); err != nil {
+			return ast.NewStringValue(&ast.StringValue{Value: fmt.Sprint(v)})
+		}
+		return astValue(decoded)
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strconv"
 
 	"github.com/sourcenetwork/defradb/client"
 	"github.com/sourcenetwork/defradb/client/request"
@@ -38,6 +39,11 @@ func FromString(ctx context.Context, schemaString string) ([]client.CollectionDe
 		return nil, err
 	}
 
+	err = validateDocument(doc)
+	if err != nil {
+		return nil, err
+	}
+
 	desc, err := fromAst(ctx, doc)
 	return desc, err
 }
@@ -102,7 +108,7 @@ func fromAstDefinition(
 			if kind == client.FieldKind_FOREIGN_OBJECT {
 				schema = field.Type.(*ast.Named).Name.Value
 				relationType = client.Relation_Type_ONE
-				if _, exists := findDirective(field, "primary"); exists {
+				if _, exists := findDirective(field.Directives, "primary"); exists {
 					relationType |= client.Relation_Type_Primary
 				}
 
@@ -136,6 +142,11 @@ func fromAstDefinition(
 			}
 		}
 
+		_, encrypted := findDirective(field.Directives, "encrypted")
+		if encrypted && schema != "" {
+			return client.CollectionDescription{}, NewErrEncryptedRelationField(def.Name.Value, field.Name.Value)
+		}
+
 		fieldDescription := client.FieldDescription{
 			Name:         field.Name.Value,
 			Kind:         kind,
@@ -143,6 +154,7 @@ func fromAstDefinition(
 			Schema:       schema,
 			RelationName: relationName,
 			RelationType: relationType,
+			Encrypted:    encrypted,
 		}
 
 		fieldDescriptions = append(fieldDescriptions, fieldDescription)
@@ -159,20 +171,55 @@ func fromAstDefinition(
 		return fieldDescriptions[i].Name < fieldDescriptions[j].Name
 	})
 
+	_, appendOnly := findDirective(def.Directives, "appendOnly")
+
+	costMultiplier, err := getCostMultiplier(def)
+	if err != nil {
+		return client.CollectionDescription{}, err
+	}
+
 	return client.CollectionDescription{
 		Name: def.Name.Value,
 		Schema: client.SchemaDescription{
-			Name:   def.Name.Value,
-			Fields: fieldDescriptions,
+			Name:           def.Name.Value,
+			Fields:         fieldDescriptions,
+			AppendOnly:     appendOnly,
+			CostMultiplier: costMultiplier,
 		},
 	}, nil
 }
 
+// getCostMultiplier returns the cost multiplier of the given object, declared with the
+// `@cost(multiplier: N)` directive, or zero if it has none.
+func getCostMultiplier(def *ast.ObjectDefinition) (int, error) {
+	directive, exists := findDirective(def.Directives, "cost")
+	if !exists {
+		return 0, nil
+	}
+	for _, argument := range directive.Arguments {
+		if argument.Name.Value != "multiplier" {
+			continue
+		}
+		value, isInt := argument.Value.(*ast.IntValue)
+		if !isInt {
+			return 0, NewErrInvalidCostMultiplier(def.Name.Value, argument.Value.GetValue())
+		}
+		multiplier, err := strconv.Atoi(value.Value)
+		if err != nil || multiplier <= 0 {
+			return 0, NewErrInvalidCostMultiplier(def.Name.Value, value.Value)
+		}
+		return multiplier, nil
+	}
+	return 0, NewErrInvalidCostMultiplier(def.Name.Value, nil)
+}
+
 func astTypeToKind(t ast.Type) (client.FieldKind, error) {
 	const (
 		typeID       string = "ID"
 		typeBoolean  string = "Boolean"
 		typeInt      string = "Int"
+		typeInt32    string = "Int32"
+		typeUInt64   string = "UInt64"
 		typeFloat    string = "Float"
 		typeDateTime string = "DateTime"
 		typeString   string = "String"
@@ -191,6 +238,8 @@ func astTypeToKind(t ast.Type) (client.FieldKind, error) {
 				return client.FieldKind_FLOAT_ARRAY, nil
 			case typeString:
 				return client.FieldKind_STRING_ARRAY, nil
+			case typeInt32, typeUInt64:
+				return 0, NewErrArrayOfTypeNotSupported(innerAstTypeVal.Type.(*ast.Named).Name.Value)
 			default:
 				return 0, NewErrNonNullForTypeNotSupported(innerAstTypeVal.Type.(*ast.Named).Name.Value)
 			}
@@ -205,6 +254,8 @@ func astTypeToKind(t ast.Type) (client.FieldKind, error) {
 				return client.FieldKind_NILLABLE_FLOAT_ARRAY, nil
 			case typeString:
 				return client.FieldKind_NILLABLE_STRING_ARRAY, nil
+			case typeInt32, typeUInt64:
+				return 0, NewErrArrayOfTypeNotSupported(astTypeVal.Type.(*ast.Named).Name.Value)
 			default:
 				return client.FieldKind_FOREIGN_OBJECT_ARRAY, nil
 			}
@@ -218,6 +269,10 @@ func astTypeToKind(t ast.Type) (client.FieldKind, error) {
 			return client.FieldKind_BOOL, nil
 		case typeInt:
 			return client.FieldKind_INT, nil
+		case typeInt32:
+			return client.FieldKind_INT32, nil
+		case typeUInt64:
+			return client.FieldKind_UINT64, nil
 		case typeFloat:
 			return client.FieldKind_FLOAT, nil
 		case typeDateTime:
@@ -236,8 +291,8 @@ func astTypeToKind(t ast.Type) (client.FieldKind, error) {
 	}
 }
 
-func findDirective(field *ast.FieldDefinition, directiveName string) (*ast.Directive, bool) {
-	for _, directive := range field.Directives {
+func findDirective(directives []*ast.Directive, directiveName string) (*ast.Directive, bool) {
+	for _, directive := range directives {
 		if directive.Name.Value == directiveName {
 			return directive, true
 		}
@@ -14,6 +14,7 @@ import (
 	gql "github.com/graphql-go/graphql"
 
 	"github.com/sourcenetwork/defradb/client"
+	schemaTypes "github.com/sourcenetwork/defradb/request/graphql/schema/types"
 )
 
 var (
@@ -45,6 +46,8 @@ var (
 		client.FieldKind_INT:                   gql.Int,
 		client.FieldKind_INT_ARRAY:             gql.NewList(gql.NewNonNull(gql.Int)),
 		client.FieldKind_NILLABLE_INT_ARRAY:    gql.NewList(gql.Int),
+		client.FieldKind_INT32:                 schemaTypes.Int32Scalar,
+		client.FieldKind_UINT64:                schemaTypes.UInt64Scalar,
 		client.FieldKind_FLOAT:                 gql.Float,
 		client.FieldKind_FLOAT_ARRAY:           gql.NewList(gql.NewNonNull(gql.Float)),
 		client.FieldKind_NILLABLE_FLOAT_ARRAY:  gql.NewList(gql.Float),
@@ -63,6 +66,8 @@ var (
 		client.FieldKind_INT:                   client.LWW_REGISTER,
 		client.FieldKind_INT_ARRAY:             client.LWW_REGISTER,
 		client.FieldKind_NILLABLE_INT_ARRAY:    client.LWW_REGISTER,
+		client.FieldKind_INT32:                 client.LWW_REGISTER,
+		client.FieldKind_UINT64:                client.LWW_REGISTER,
 		client.FieldKind_FLOAT:                 client.LWW_REGISTER,
 		client.FieldKind_FLOAT_ARRAY:           client.LWW_REGISTER,
 		client.FieldKind_NILLABLE_FLOAT_ARRAY:  client.LWW_REGISTER,
@@ -86,6 +91,24 @@ An optional set of dockeys for this field. Only documents with a dockey
  matching a dockey in the given set will be returned.  If no documents match,
  the result will be null/empty. If an empty set is provided, this argument will
  be ignored.
+`
+	depthArgDescription string = `
+An optional number of levels to which this self-referencing relation should be
+ expanded. Each level selects the same fields, with the same arguments, as the
+ level above it. Documents that are already an ancestor of the document being
+ expanded are not returned again, so that cycles are not traversed.
+`
+	localFieldArgDescription string = `
+The field of the host record that the joined records are matched on.
+`
+	foreignFieldArgDescription string = `
+The field of the joined records that must be equal to the matched field of the
+ host record.
+`
+	numericFieldsArgDescription string = `
+The property to be aggregated. Either the name of a numeric property of the
+ aggregated type, or a property nested within its related types, declared as the
+ name of the relation holding it, e.g. {reviews: rating}.
 `
 	cidArgDescription string = `
 An optional value that specifies the commit ID of the document to return.
@@ -93,11 +116,26 @@ An optional value that specifies the commit ID of the document to return.
  corresponds to an older version of a document the document will be returned
  at the state it was in at the time of that commit. If a matching commit is
  not found then an empty set will be returned.
+`
+	atTimeArgDescription string = `
+An optional RFC3339 time at which the documents should be returned as they were.
+ Each document is returned at the state of its latest commit made to this node at
+ or before the given time, and documents created after it are not returned. The
+ related documents are returned as they were at the same time. This argument
+ cannot be used together with the cid argument.
 `
 	singleFieldFilterArgDescription string = `
 An optional filter for this join, if the related record does
  not meet the filter criteria the host record will still be returned,
  but the value of this field will be null.
+`
+	includeMissingArgDescription string = `
+An optional flag for this join, true by default. The related record is missing if
+ the relation is not set, if it references a record that does not exist, or if it
+ does not meet the filter of this field. Host records with a missing related record
+ are returned with the value of this field set to null, and the fields of the
+ missing record are null when filtering the host records. If false, these host
+ records are not returned.
 `
 	listFieldFilterArgDescription string = `
 An optional filter for this join, if none of the related records meet the filter
@@ -174,11 +212,33 @@ The group field may be used to return a set of records belonging to the group.
  It must be used alongside a 'groupBy' argument on the parent selector. It may
  contain any field on the type being grouped, including those used by the
  groupBy.
+`
+	joinFieldDescription string = `
+The join field returns the records of the joined type whose 'foreignField' is
+ equal to the 'localField' of the host record, regardless of any declared
+ relation between the two types.
 `
 	deletedFieldDescription string = `
 Indicates as to whether or not this document has been deleted.
+`
+	createdAtFieldDescription string = `
+The time at which the first commit of this document was recorded by this node.
+`
+	updatedAtFieldDescription string = `
+The time at which the latest commit of this document was recorded by this node.
 `
 	versionFieldDescription string = `
 Returns the head commit for this document.
+`
+	historyFieldDescription string = `
+Returns the values that the given field of this document has held, one for each commit to the
+ field, from the latest to the earliest.
+`
+	historyFieldArgDescription string = `
+The field whose history of values is returned.
+`
+	metaFieldDescription string = `
+Returns the metadata of this document: its head commits, schema version, deleted status and
+ number of commits.
 `
 )
@@ -571,6 +571,99 @@ func TestSingleSimpleType(t *testing.T) {
 				},
 			},
 		},
+		{
+			description: "Append-only type",
+			sdl: `
+			type log @appendOnly {
+				message: String
+			}
+			`,
+			targetDescs: []client.CollectionDescription{
+				{
+					Name: "log",
+					Schema: client.SchemaDescription{
+						Name: "log",
+						Fields: []client.FieldDescription{
+							{
+								Name: "_key",
+								Kind: client.FieldKind_DocKey,
+								Typ:  client.NONE_CRDT,
+							},
+							{
+								Name: "message",
+								Kind: client.FieldKind_STRING,
+								Typ:  client.LWW_REGISTER,
+							},
+						},
+						AppendOnly: true,
+					},
+				},
+			},
+		},
+		{
+			description: "Type with encrypted field",
+			sdl: `
+			type user {
+				name: String
+				ssn: String @encrypted
+			}
+			`,
+			targetDescs: []client.CollectionDescription{
+				{
+					Name: "user",
+					Schema: client.SchemaDescription{
+						Name: "user",
+						Fields: []client.FieldDescription{
+							{
+								Name: "_key",
+								Kind: client.FieldKind_DocKey,
+								Typ:  client.NONE_CRDT,
+							},
+							{
+								Name: "name",
+								Kind: client.FieldKind_STRING,
+								Typ:  client.LWW_REGISTER,
+							},
+							{
+								Name:      "ssn",
+								Kind:      client.FieldKind_STRING,
+								Typ:       client.LWW_REGISTER,
+								Encrypted: true,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "Type with cost multiplier",
+			sdl: `
+			type report @cost(multiplier: 10) {
+				title: String
+			}
+			`,
+			targetDescs: []client.CollectionDescription{
+				{
+					Name: "report",
+					Schema: client.SchemaDescription{
+						Name: "report",
+						Fields: []client.FieldDescription{
+							{
+								Name: "_key",
+								Kind: client.FieldKind_DocKey,
+								Typ:  client.NONE_CRDT,
+							},
+							{
+								Name: "title",
+								Kind: client.FieldKind_STRING,
+								Typ:  client.LWW_REGISTER,
+							},
+						},
+						CostMultiplier: 10,
+					},
+				},
+			},
+		},
 	}
 
 	for _, test := range cases {
@@ -595,3 +688,22 @@ type descriptionTestCase struct {
 	sdl         string
 	targetDescs []client.CollectionDescription
 }
+
+func TestEncryptedRelationField(t *testing.T) {
+	_, err := FromString(context.Background(), `
+		type book {
+			author: author @encrypted
+		}
+		type author {
+			books: [book]
+		}
+	`)
+	assert.ErrorIs(t, err, ErrEncryptedRelationField)
+}
+
+func TestInvalidCostMultiplier(t *testing.T) {
+	for _, directive := range []string{`@cost`, `@cost(multiplier: 0)`, `@cost(multiplier: "10")`} {
+		_, err := FromString(context.Background(), `type report `+directive+` { title: String }`)
+		assert.ErrorIs(t, err, ErrInvalidCostMultiplier, directive)
+	}
+}
@@ -22,6 +22,10 @@ const (
 	errTypeNotFound               string = "no type found for given name"
 	errRelationNotFound           string = "no relation found"
 	errNonNullForTypeNotSupported string = "NonNull variants for type are not supported"
+	errArrayOfTypeNotSupported    string = "arrays of the type are not supported"
+	errInvalidSchema              string = "the schema is invalid"
+	errEncryptedRelationField     string = "relation fields can't be encrypted"
+	errInvalidCostMultiplier      string = "the cost multiplier must be a positive integer"
 )
 
 var (
@@ -34,6 +38,10 @@ var (
 	ErrTypeNotFound               = errors.New(errTypeNotFound)
 	ErrRelationNotFound           = errors.New(errRelationNotFound)
 	ErrNonNullForTypeNotSupported = errors.New(errNonNullForTypeNotSupported)
+	ErrArrayOfTypeNotSupported    = errors.New(errArrayOfTypeNotSupported)
+	ErrInvalidSchema              = errors.WithCode(errors.CodeInvalidSchema, errors.New(errInvalidSchema))
+	ErrEncryptedRelationField     = errors.WithCode(errors.CodeInvalidSchema, errors.New(errEncryptedRelationField))
+	ErrInvalidCostMultiplier      = errors.WithCode(errors.CodeInvalidSchema, errors.New(errInvalidCostMultiplier))
 	ErrRelationMutlipleTypes      = errors.New("relation type can only be either One or Many, not both")
 	ErrRelationMissingTypes       = errors.New("relation is missing its defined types and fields")
 	ErrRelationInvalidType        = errors.New("relation has an invalid type to be finalize")
@@ -97,6 +105,21 @@ func NewErrTypeNotFound(typeName string) error {
 	)
 }
 
+func NewErrArrayOfTypeNotSupported(typeName string) error {
+	return errors.New(
+		errArrayOfTypeNotSupported,
+		errors.NewKV("Type", typeName),
+	)
+}
+
+// NewErrInvalidSchema returns an error holding the diagnostics of the semantic errors of a schema.
+func NewErrInvalidSchema(diagnostics Diagnostics) error {
+	return errors.New(
+		errInvalidSchema,
+		errors.NewKV("Diagnostics", diagnostics),
+	)
+}
+
 func NewErrNonNullForTypeNotSupported(typeName string) error {
 	return errors.New(
 		errNonNullForTypeNotSupported,
@@ -110,3 +133,19 @@ func NewErrRelationNotFound(relationName string) error {
 		errors.NewKV("RelationName", relationName),
 	)
 }
+
+func NewErrEncryptedRelationField(objectName, fieldName string) error {
+	return errors.New(
+		errEncryptedRelationField,
+		errors.NewKV("Object", objectName),
+		errors.NewKV("Field", fieldName),
+	)
+}
+
+func NewErrInvalidCostMultiplier(objectName string, multiplier any) error {
+	return errors.New(
+		errInvalidCostMultiplier,
+		errors.NewKV("Object", objectName),
+		errors.NewKV("Multiplier", multiplier),
+	)
+}
@@ -13,8 +13,10 @@ package schema
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	gql "github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
 
 	"github.com/sourcenetwork/defradb/client"
 
@@ -157,6 +159,12 @@ func (g *Generator) generate(ctx context.Context, collections []client.Collectio
 		}
 	}
 
+	// The join, history and meta fields are added last, so that the aggregate inputs of the types don't
+	// include them, and their arguments may use the field enums of the types.
+	g.genJoinFields()
+	g.genHistoryFields()
+	g.genMetaFields()
+
 	appendCommitChildGroupField()
 
 	// resolve types
@@ -214,6 +222,7 @@ func (g *Generator) expandInputArgument(obj *gql.Object) error {
 			if err != nil {
 				return err
 			}
+			addDepthArgument(obj, f, t, expandedField)
 
 			obj.AddFieldConfig(f, expandedField)
 
@@ -233,7 +242,13 @@ func (g *Generator) expandInputArgument(obj *gql.Object) error {
 				if err != nil {
 					return err
 				}
+				addDepthArgument(obj, f, listObjType, expandedField)
 				obj.AddFieldConfig(f, expandedField)
+			} else if _, isAggregate := request.Aggregates[f]; isAggregate {
+				// Running aggregates return a list of values
+				if err := g.createExpandedFieldAggregate(obj, def); err != nil {
+					return err
+				}
 			}
 		case *gql.Scalar:
 			if _, isAggregate := request.Aggregates[f]; isAggregate {
@@ -306,6 +321,7 @@ func (g *Generator) createExpandedFieldSingle(
 				g.manager.schema.TypeMap()[typeName+"FilterArg"],
 				singleFieldFilterArgDescription,
 			),
+			request.IncludeMissingClause: schemaTypes.NewArgConfig(gql.Boolean, includeMissingArgDescription),
 		},
 	}
 	return field, nil
@@ -333,9 +349,13 @@ func (g *Generator) createExpandedFieldList(
 				schemaTypes.GroupByArgDescription,
 			),
 			"order": schemaTypes.NewArgConfig(
-				g.manager.schema.TypeMap()[typeName+"OrderArg"],
+				gql.NewList(g.manager.schema.TypeMap()[typeName+"OrderArg"]),
 				schemaTypes.OrderArgDescription,
 			),
+			request.CollationClause: schemaTypes.NewArgConfig(
+				schemaTypes.CollationInputObject,
+				schemaTypes.CollationArgDescription,
+			),
 			request.LimitClause:  schemaTypes.NewArgConfig(gql.Int, schemaTypes.LimitArgDescription),
 			request.OffsetClause: schemaTypes.NewArgConfig(gql.Int, schemaTypes.OffsetArgDescription),
 		},
@@ -344,6 +364,87 @@ func (g *Generator) createExpandedFieldList(
 	return field, nil
 }
 
+// addDepthArgument adds the depth argument to the given expanded field if it is a
+// self-referencing relation, as only those can be recursively expanded.
+func addDepthArgument(obj *gql.Object, fieldName string, fieldType *gql.Object, field *gql.Field) {
+	if fieldName == request.GroupFieldName || fieldType.Name() != obj.Name() {
+		return
+	}
+	field.Args[request.DepthClause] = schemaTypes.NewArgConfig(gql.Int, depthArgDescription)
+}
+
+// genJoinFields adds a field to each type for each type it may be joined with by an ad-hoc
+// join, on the equality of a field of each.
+func (g *Generator) genJoinFields() {
+	typeMap := g.manager.schema.TypeMap()
+	for _, obj := range g.typeDefs {
+		for _, joined := range g.typeDefs {
+			typeName := joined.Name()
+			field := &gql.Field{
+				Name:        request.JoinFieldName(typeName),
+				Description: joinFieldDescription,
+				Type:        gql.NewList(joined),
+				Args: gql.FieldConfigArgument{
+					request.LocalFieldClause: schemaTypes.NewArgConfig(
+						gql.NewNonNull(typeMap[genTypeName(obj, "Fields")]),
+						localFieldArgDescription,
+					),
+					request.ForeignFieldClause: schemaTypes.NewArgConfig(
+						gql.NewNonNull(typeMap[typeName+"Fields"]),
+						foreignFieldArgDescription,
+					),
+					request.FilterClause: schemaTypes.NewArgConfig(
+						typeMap[typeName+"FilterArg"],
+						listFieldFilterArgDescription,
+					),
+					request.OrderClause: schemaTypes.NewArgConfig(
+						gql.NewList(typeMap[typeName+"OrderArg"]),
+						schemaTypes.OrderArgDescription,
+					),
+					request.CollationClause: schemaTypes.NewArgConfig(
+						schemaTypes.CollationInputObject,
+						schemaTypes.CollationArgDescription,
+					),
+					request.LimitClause:  schemaTypes.NewArgConfig(gql.Int, schemaTypes.LimitArgDescription),
+					request.OffsetClause: schemaTypes.NewArgConfig(gql.Int, schemaTypes.OffsetArgDescription),
+				},
+			}
+			obj.AddFieldConfig(field.Name, field)
+		}
+	}
+}
+
+// genHistoryFields adds the field returning the history of the values of a given field to each type.
+func (g *Generator) genHistoryFields() {
+	typeMap := g.manager.schema.TypeMap()
+	for _, obj := range g.typeDefs {
+		field := &gql.Field{
+			Name:        request.HistoryFieldName,
+			Description: historyFieldDescription,
+			Type:        gql.NewList(schemaTypes.HistoryObject),
+			Args: gql.FieldConfigArgument{
+				request.FieldName: schemaTypes.NewArgConfig(
+					gql.NewNonNull(typeMap[genTypeName(obj, "Fields")]),
+					historyFieldArgDescription,
+				),
+			},
+		}
+		obj.AddFieldConfig(field.Name, field)
+	}
+}
+
+// genMetaFields adds the field returning the metadata of the document to each type.
+func (g *Generator) genMetaFields() {
+	for _, obj := range g.typeDefs {
+		field := &gql.Field{
+			Name:        request.MetaFieldName,
+			Description: metaFieldDescription,
+			Type:        schemaTypes.MetaObject,
+		}
+		obj.AddFieldConfig(field.Name, field)
+	}
+}
+
 // @todo: Add Schema Directives (IE: relation, etc..)
 
 // @todo: Add validation support for the AST
@@ -435,6 +536,16 @@ func (g *Generator) buildTypes(
 				Type:        gql.Boolean,
 			}
 
+			// add the fields of the times of the first and last commits of the document
+			fields[request.CreatedAtFieldName] = &gql.Field{
+				Description: createdAtFieldDescription,
+				Type:        gql.DateTime,
+			}
+			fields[request.UpdatedAtFieldName] = &gql.Field{
+				Description: updatedAtFieldDescription,
+				Type:        gql.DateTime,
+			}
+
 			gqlType, ok := g.manager.schema.TypeMap()[collection.Name]
 			if !ok {
 				return nil, NewErrObjectNotFoundDuringThunk(collection.Name)
@@ -473,6 +584,11 @@ func (g *Generator) genAggregateFields(ctx context.Context) error {
 			return err
 		}
 
+		err = g.appendIfNotExists(g.genRunningAggregateArgInputs(t))
+		if err != nil {
+			return err
+		}
+
 		numericInlineArrayInputs := g.genNumericInlineArraySelectorObject(t)
 		for _, obj := range numericInlineArrayInputs {
 			err = g.appendIfNotExists(obj)
@@ -515,6 +631,10 @@ func (g *Generator) genAggregateFields(ctx context.Context) error {
 			return err
 		}
 		t.AddFieldConfig(averageField.Name, &averageField)
+
+		for _, runningField := range g.genRunningAggregateFieldConfigs(t) {
+			t.AddFieldConfig(runningField.Name, runningField)
+		}
 	}
 
 	queryType := g.manager.schema.QueryType()
@@ -684,6 +804,53 @@ func (g *Generator) genAverageFieldConfig(obj *gql.Object) (gql.Field, error) {
 	return field, nil
 }
 
+// genRunningAggregateFieldConfigs returns the running aggregate fields of the given object,
+// which may aggregate the child sets of its one-to-many relations.
+//
+// No fields are returned if the object has no such relations.
+func (g *Generator) genRunningAggregateFieldConfigs(obj *gql.Object) []*gql.Field {
+	childTypesByFieldName := map[string]gql.Type{}
+
+	for _, field := range obj.Fields() {
+		listType, isList := field.Type.(*gql.List)
+		if !isList || isNumericArray(listType) || field.Name == request.GroupFieldName {
+			continue
+		}
+
+		subRunningType, isSubTypeRunnable := g.manager.schema.TypeMap()[genRunningObjectSelectorName(field.Type.Name())]
+		// If the item is not in the type map, it must contain no summable
+		//  fields (e.g. no Int/Floats)
+		if !isSubTypeRunnable {
+			continue
+		}
+		childTypesByFieldName[field.Name] = subRunningType
+	}
+
+	if len(childTypesByFieldName) == 0 {
+		return nil
+	}
+
+	runningSumField := &gql.Field{
+		Name:        request.RunningSumFieldName,
+		Description: schemaTypes.RunningSumFieldDescription,
+		Type:        gql.NewList(gql.Float),
+		Args:        gql.FieldConfigArgument{},
+	}
+	runningAverageField := &gql.Field{
+		Name:        request.RunningAverageFieldName,
+		Description: schemaTypes.RunningAverageFieldDescription,
+		Type:        gql.NewList(gql.Float),
+		Args:        gql.FieldConfigArgument{},
+	}
+
+	for name, inputObject := range childTypesByFieldName {
+		runningSumField.Args[name] = schemaTypes.NewArgConfig(inputObject, inputObject.Description())
+		runningAverageField.Args[name] = schemaTypes.NewArgConfig(inputObject, inputObject.Description())
+	}
+
+	return []*gql.Field{runningSumField, runningAverageField}
+}
+
 func (g *Generator) genNumericInlineArraySelectorObject(obj *gql.Object) []*gql.InputObject {
 	objects := []*gql.InputObject{}
 	for _, field := range obj.Fields() {
@@ -724,6 +891,10 @@ func genNumericObjectSelectorName(hostName string) string {
 	return fmt.Sprintf("%s__%s", hostName, "NumericSelector")
 }
 
+func genRunningObjectSelectorName(hostName string) string {
+	return fmt.Sprintf("%s__%s", hostName, "RunningSelector")
+}
+
 func genNumericInlineArraySelectorName(hostName string, fieldName string) string {
 	return fmt.Sprintf("%s__%s__%s", hostName, fieldName, "NumericSelector")
 }
@@ -788,71 +959,170 @@ func genObjectCountName(hostName string) string {
 // declaring which fields are available for aggregation.
 func (g *Generator) genNumericAggregateBaseArgInputs(obj *gql.Object) *gql.InputObject {
 	var fieldThunk gql.InputObjectConfigFieldMapThunk = func() (gql.InputObjectConfigFieldMap, error) {
-		fieldsEnum, enumExists := g.manager.schema.TypeMap()[genTypeName(obj, "NumericFieldsArg")]
-		if !enumExists {
-			fieldsEnumCfg := gql.EnumConfig{
-				Name:   genTypeName(obj, "NumericFieldsArg"),
-				Values: gql.EnumValueConfigMap{},
-			}
+		return g.genNumericAggregateBaseArgFields(obj)
+	}
 
-			hasSumableFields := false
-			// generate basic filter operator blocks for all the sumable types
-			for _, field := range obj.Fields() {
-				if field.Type == gql.Float || field.Type == gql.Int {
-					hasSumableFields = true
-					fieldsEnumCfg.Values[field.Name] = &gql.EnumValueConfig{Value: field.Name}
-					continue
-				}
+	return gql.NewInputObject(gql.InputObjectConfig{
+		Name:   genNumericObjectSelectorName(obj.Name()),
+		Fields: fieldThunk,
+	})
+}
 
-				if list, isList := field.Type.(*gql.List); isList {
-					hasSumableFields = true
-					if isNumericArray(list) {
-						fieldsEnumCfg.Values[field.Name] = &gql.EnumValueConfig{Value: field.Name}
-					} else {
-						// If it is a related list, we need to add count in here so that we can sum it
-						fieldsEnumCfg.Values[request.CountFieldName] = &gql.EnumValueConfig{Value: request.CountFieldName}
-					}
-				}
-			}
-			// A child aggregate will always be aggregatable, as it can be present via an inner grouping
-			fieldsEnumCfg.Values[request.SumFieldName] = &gql.EnumValueConfig{Value: request.SumFieldName}
-			fieldsEnumCfg.Values[request.AverageFieldName] = &gql.EnumValueConfig{Value: request.AverageFieldName}
+// Generates the running aggregate input object-type for the given gql object, declaring
+// the same fields as the base numeric aggregate input, along with the window of the
+// running aggregate.
+func (g *Generator) genRunningAggregateArgInputs(obj *gql.Object) *gql.InputObject {
+	var fieldThunk gql.InputObjectConfigFieldMapThunk = func() (gql.InputObjectConfigFieldMap, error) {
+		fields, err := g.genNumericAggregateBaseArgFields(obj)
+		if err != nil || fields == nil {
+			return fields, err
+		}
 
-			if !hasSumableFields {
-				return nil, nil
-			}
+		fields[request.WindowClause] = &gql.InputObjectFieldConfig{
+			Type:        gql.Int,
+			Description: schemaTypes.WindowArgDescription,
+		}
+		return fields, nil
+	}
 
-			fieldsEnum = gql.NewEnum(fieldsEnumCfg)
+	return gql.NewInputObject(gql.InputObjectConfig{
+		Name:   genRunningObjectSelectorName(obj.Name()),
+		Fields: fieldThunk,
+	})
+}
 
-			err := g.manager.schema.AppendType(fieldsEnum)
-			if err != nil {
-				return nil, err
-			}
+// Generates the fields of the base (numeric-only) aggregate input object-type for the given
+// gql object, returning nil if the object has no fields available for aggregation.
+func (g *Generator) genNumericAggregateBaseArgFields(obj *gql.Object) (gql.InputObjectConfigFieldMap, error) {
+	fieldsArg, argExists := g.manager.schema.TypeMap()[genTypeName(obj, "NumericFieldsArg")]
+	if !argExists {
+		_, hasSumableFields := numericAggregateFieldNames(obj)
+		if !hasSumableFields {
+			return nil, nil
 		}
 
-		return gql.InputObjectConfigFieldMap{
-			"field": &gql.InputObjectFieldConfig{
-				Type: gql.NewNonNull(fieldsEnum),
-			},
-			request.LimitClause: &gql.InputObjectFieldConfig{
-				Type:        gql.Int,
-				Description: schemaTypes.LimitArgDescription,
+		// The target may either be a property of the object, or a property nested within
+		// its related objects (e.g. `{reviews: rating}`), which an enum cannot express.
+		fieldsArg = gql.NewScalar(gql.ScalarConfig{
+			Name:        genTypeName(obj, "NumericFieldsArg"),
+			Description: numericFieldsArgDescription,
+			Serialize: func(value any) any {
+				return value
 			},
-			request.OffsetClause: &gql.InputObjectFieldConfig{
-				Type:        gql.Int,
-				Description: schemaTypes.OffsetArgDescription,
+			ParseValue: func(value any) any {
+				return parseNumericFieldsArgValue(obj, value)
 			},
-			request.OrderClause: &gql.InputObjectFieldConfig{
-				Type:        g.manager.schema.TypeMap()[genTypeName(obj, "OrderArg")],
-				Description: schemaTypes.OrderArgDescription,
+			ParseLiteral: func(valueAST ast.Value) any {
+				return parseNumericFieldsArgLiteral(obj, valueAST)
 			},
-		}, nil
+		})
+
+		err := g.manager.schema.AppendType(fieldsArg)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	return gql.NewInputObject(gql.InputObjectConfig{
-		Name:   genNumericObjectSelectorName(obj.Name()),
-		Fields: fieldThunk,
-	})
+	return gql.InputObjectConfigFieldMap{
+		"field": &gql.InputObjectFieldConfig{
+			Type: gql.NewNonNull(fieldsArg),
+		},
+		request.LimitClause: &gql.InputObjectFieldConfig{
+			Type:        gql.Int,
+			Description: schemaTypes.LimitArgDescription,
+		},
+		request.OffsetClause: &gql.InputObjectFieldConfig{
+			Type:        gql.Int,
+			Description: schemaTypes.OffsetArgDescription,
+		},
+		request.OrderClause: &gql.InputObjectFieldConfig{
+			Type:        gql.NewList(g.manager.schema.TypeMap()[genTypeName(obj, "OrderArg")]),
+			Description: schemaTypes.OrderArgDescription,
+		},
+	}, nil
+}
+
+// numericAggregateFieldNames returns the names of the properties of the given object that can
+// be the target of a numeric aggregate, and true if any of them is a field of the object.
+func numericAggregateFieldNames(obj *gql.Object) (map[string]struct{}, bool) {
+	names := map[string]struct{}{}
+	hasSumableFields := false
+	for _, field := range obj.Fields() {
+		if field.Type == gql.Float || field.Type == gql.Int ||
+			field.Type == schemaTypes.Int32Scalar || field.Type == schemaTypes.UInt64Scalar {
+			hasSumableFields = true
+			names[field.Name] = struct{}{}
+			continue
+		}
+
+		if list, isList := field.Type.(*gql.List); isList {
+			hasSumableFields = true
+			if isNumericArray(list) {
+				names[field.Name] = struct{}{}
+			} else {
+				// If it is a related list, we need to add count in here so that we can sum it
+				names[request.CountFieldName] = struct{}{}
+			}
+		}
+	}
+	// A child aggregate will always be aggregatable, as it can be present via an inner grouping
+	names[request.SumFieldName] = struct{}{}
+	names[request.AverageFieldName] = struct{}{}
+
+	return names, hasSumableFields
+}
+
+// parseNumericFieldsArgValue returns the given numeric aggregate target of the given object if
+// it is valid, otherwise nil.
+func parseNumericFieldsArgValue(obj *gql.Object, value any) any {
+	name, isString := value.(string)
+	if !isString {
+		return nil
+	}
+	names, _ := numericAggregateFieldNames(obj)
+	if _, isValid := names[name]; !isValid {
+		return nil
+	}
+	return name
+}
+
+// parseNumericFieldsArgLiteral returns the given numeric aggregate target of the given object
+// if it is valid, otherwise nil.
+//
+// Targets nested within related objects are declared as a single relation name
+// holding the target within the related object, e.g. `{reviews: rating}`.
+func parseNumericFieldsArgLiteral(obj *gql.Object, valueAST ast.Value) any {
+	switch value := valueAST.(type) {
+	case *ast.EnumValue:
+		return parseNumericFieldsArgValue(obj, value.Value)
+
+	case *ast.ObjectValue:
+		if len(value.Fields) != 1 {
+			return nil
+		}
+		relationName := value.Fields[0].Name.Value
+		if relationName == request.GroupFieldName {
+			return nil
+		}
+		field, isField := obj.Fields()[relationName]
+		if !isField {
+			return nil
+		}
+		list, isList := field.Type.(*gql.List)
+		if !isList {
+			return nil
+		}
+		relatedObj, isObject := list.OfType.(*gql.Object)
+		if !isObject {
+			return nil
+		}
+		target := parseNumericFieldsArgLiteral(relatedObj, value.Fields[0].Value)
+		if target == nil {
+			return nil
+		}
+		return map[string]any{relationName: target}
+	}
+	return nil
 }
 
 func appendCommitChildGroupField() {
@@ -1009,6 +1279,11 @@ func (g *Generator) genTypeFilterArgInput(obj *gql.Object) *gql.InputObject {
 			// @todo: Extract object field loop into its own utility func
 			for f, field := range obj.Fields() {
 				if _, ok := request.ReservedFields[f]; ok && f != request.KeyFieldName {
+					if _, isTimestamp := request.TimestampFields[f]; !isTimestamp {
+						continue
+					}
+				}
+				if strings.HasPrefix(f, request.JoinFieldPrefix) {
 					continue
 				}
 				// scalars (leafs)
@@ -1108,6 +1383,11 @@ func (g *Generator) genTypeOrderArgInput(obj *gql.Object) *gql.InputObject {
 
 			for f, field := range obj.Fields() {
 				if _, ok := request.ReservedFields[f]; ok && f != request.KeyFieldName {
+					if _, isTimestamp := request.TimestampFields[f]; !isTimestamp {
+						continue
+					}
+				}
+				if strings.HasPrefix(f, request.JoinFieldPrefix) {
 					continue
 				}
 				typeMap := g.manager.schema.TypeMap()
@@ -1160,15 +1440,20 @@ func (g *Generator) genTypeQueryableFieldList(
 		Description: obj.Description(),
 		Type:        gql.NewList(obj),
 		Args: gql.FieldConfigArgument{
-			"dockey":  schemaTypes.NewArgConfig(gql.String, dockeyArgDescription),
-			"dockeys": schemaTypes.NewArgConfig(gql.NewList(gql.NewNonNull(gql.String)), dockeysArgDescription),
-			"cid":     schemaTypes.NewArgConfig(gql.String, cidArgDescription),
-			"filter":  schemaTypes.NewArgConfig(config.filter, selectFilterArgDescription),
+			"dockey":       schemaTypes.NewArgConfig(gql.String, dockeyArgDescription),
+			"dockeys":      schemaTypes.NewArgConfig(gql.NewList(gql.NewNonNull(gql.String)), dockeysArgDescription),
+			"cid":          schemaTypes.NewArgConfig(gql.String, cidArgDescription),
+			request.AtTime: schemaTypes.NewArgConfig(gql.DateTime, atTimeArgDescription),
+			"filter":       schemaTypes.NewArgConfig(config.filter, selectFilterArgDescription),
 			"groupBy": schemaTypes.NewArgConfig(
 				gql.NewList(gql.NewNonNull(config.groupBy)),
 				schemaTypes.GroupByArgDescription,
 			),
-			"order":              schemaTypes.NewArgConfig(config.order, schemaTypes.OrderArgDescription),
+			"order": schemaTypes.NewArgConfig(gql.NewList(config.order), schemaTypes.OrderArgDescription),
+			request.CollationClause: schemaTypes.NewArgConfig(
+				schemaTypes.CollationInputObject,
+				schemaTypes.CollationArgDescription,
+			),
 			request.ShowDeleted:  schemaTypes.NewArgConfig(gql.Boolean, showDeletedArgDescription),
 			request.LimitClause:  schemaTypes.NewArgConfig(gql.Int, schemaTypes.LimitArgDescription),
 			request.OffsetClause: schemaTypes.NewArgConfig(gql.Int, schemaTypes.OffsetArgDescription),
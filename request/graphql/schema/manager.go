@@ -112,6 +112,8 @@ func defaultMutationType() *gql.Object {
 func defaultDirectivesType() []*gql.Directive {
 	return []*gql.Directive{
 		schemaTypes.ExplainDirective,
+		gql.IncludeDirective,
+		gql.SkipDirective,
 	}
 }
 
@@ -138,11 +140,14 @@ func defaultTypes() []gql.Type {
 		gql.ID,
 		gql.Int,
 		gql.String,
+		schemaTypes.Int32Scalar,
+		schemaTypes.UInt64Scalar,
 
 		// Base Query types
 
 		// Sort/Order enum
 		schemaTypes.OrderingEnum,
+		schemaTypes.CollationInputObject,
 
 		// Filter scalar blocks
 		schemaTypes.BooleanOperatorBlock,
@@ -153,6 +158,8 @@ func defaultTypes() []gql.Type {
 		schemaTypes.IdOperatorBlock,
 		schemaTypes.IntOperatorBlock,
 		schemaTypes.NotNullIntOperatorBlock,
+		schemaTypes.Int32OperatorBlock,
+		schemaTypes.UInt64OperatorBlock,
 		schemaTypes.StringOperatorBlock,
 		schemaTypes.NotNullstringOperatorBlock,
 
@@ -160,6 +167,10 @@ func defaultTypes() []gql.Type {
 		schemaTypes.CommitLinkObject,
 		schemaTypes.CommitObject,
 
+		schemaTypes.FieldValueScalar,
+		schemaTypes.HistoryObject,
+		schemaTypes.MetaObject,
+
 		schemaTypes.ExplainEnum,
 	}
 }
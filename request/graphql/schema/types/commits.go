@@ -37,6 +37,9 @@ var (
 	// 	CollectionID: Int
 	// 	SchemaVersionID: String
 	// 	Delta: String
+	// 	FieldId: String
+	// 	FieldName: String
+	// 	Peer: String
 	// 	Previous: [Commit]
 	//  Links: [Commit]
 	// }
@@ -71,6 +74,18 @@ var (
 				Description: commitDeltaFieldDescription,
 				Type:        gql.String,
 			},
+			"fieldId": &gql.Field{
+				Description: commitFieldIDFieldDescription,
+				Type:        gql.String,
+			},
+			"fieldName": &gql.Field{
+				Description: commitFieldNameFieldDescription,
+				Type:        gql.String,
+			},
+			"peer": &gql.Field{
+				Description: commitPeerFieldDescription,
+				Type:        gql.String,
+			},
 			"links": &gql.Field{
 				Description: commitLinksDescription,
 				Type:        gql.NewList(CommitLinkObject),
@@ -159,10 +174,11 @@ var (
 		Description: commitsQueryDescription,
 		Type:        gql.NewList(CommitObject),
 		Args: gql.FieldConfigArgument{
-			"dockey": NewArgConfig(gql.ID, commitDockeyArgDescription),
-			"field":  NewArgConfig(gql.String, commitFieldArgDescription),
-			"order":  NewArgConfig(CommitsOrderArg, OrderArgDescription),
-			"cid":    NewArgConfig(gql.ID, commitCIDArgDescription),
+			"dockey":    NewArgConfig(gql.ID, commitDockeyArgDescription),
+			"field":     NewArgConfig(gql.String, commitFieldArgDescription),
+			"fieldName": NewArgConfig(gql.String, commitFieldNameArgDescription),
+			"order":     NewArgConfig(CommitsOrderArg, OrderArgDescription),
+			"cid":       NewArgConfig(gql.ID, commitCIDArgDescription),
 			"groupBy": NewArgConfig(
 				gql.NewList(
 					gql.NewNonNull(
@@ -182,8 +198,9 @@ var (
 		Description: latestCommitsQueryDescription,
 		Type:        gql.NewList(CommitObject),
 		Args: gql.FieldConfigArgument{
-			"dockey": NewArgConfig(gql.NewNonNull(gql.ID), commitDockeyArgDescription),
-			"field":  NewArgConfig(gql.String, commitFieldArgDescription),
+			"dockey":    NewArgConfig(gql.NewNonNull(gql.ID), commitDockeyArgDescription),
+			"field":     NewArgConfig(gql.String, commitFieldArgDescription),
+			"fieldName": NewArgConfig(gql.String, commitFieldNameArgDescription),
 		},
 	}
 )
@@ -13,7 +13,12 @@ package types
 const (
 	OrderArgDescription string = `
 An optional set of field-orders which may be used to sort the results. An
- empty set will be ignored.
+ empty set will be ignored. A list of sets, e.g. [{Age: DESC}, {Name: ASC}], sorts
+ the results by the fields of each set in turn, in the order of the list, the
+ following ones only ordering the documents that are equal by the preceding
+ ones. Documents that are equal by all the given fields are
+ ordered by ascending dockey, so that the results, and the pages of them selected
+ with limit and offset, are the same on every node holding the same documents.
 `
 	GroupByArgDescription string = `
 An optional set of fields for which to group the contents of this field by.
@@ -31,6 +36,26 @@ An optional value that caps the number of results to the number provided.
 An optional value that skips the given number of results that would have
  otherwise been returned.  Commonly used alongside the 'limit' argument,
  this argument will still work on its own.
+`
+	CollationArgDescription string = `
+An optional collation with which the string fields are compared when ordering the
+ results, instead of by their bytes. Strings equal by the collation, such as strings
+ differing only by case when it is case-insensitive, are ordered by ascending dockey.
+`
+	collationDescription string = `
+Collation describes how strings are compared when ordering documents, following the
+ Unicode Collation Algorithm.
+`
+	collationLocaleDescription string = `
+The BCP 47 tag of the language whose collation rules apply, for example 'sv' or 'de-u-co-phonebk'.
+ The root Unicode collation applies if it is omitted.
+`
+	collationCaseInsensitiveDescription string = `
+Whether strings differing only by case are equal.
+`
+	collationNumericDescription string = `
+Whether sequences of digits are compared by their numeric value, so that 'file2' comes before
+ 'file10'.
 `
 	commitDescription string = `
 Commit represents an individual commit to a MerkleCRDT, every mutation to a
@@ -48,6 +73,11 @@ An optional field ID parameter for this commit query. Only commits for a fields
  matching this ID will be returned. Specifying 'C' will limit the results to 
  composite (document level) commits only, otherwise field IDs are numeric. If no
  fields match, the result set will be empty.
+`
+	commitFieldNameArgDescription string = `
+An optional field name parameter for this commit query. Only commits for a field
+ with this name will be returned. Unlike the 'field' parameter, composite commits
+ can't be selected by name. If no fields match, the result set will be empty.
 `
 	commitCIDArgDescription string = `
 An optional value that specifies the commit ID of the commits to return. If a
@@ -83,12 +113,58 @@ The ID of the schema version that this commit was committed against. This ID all
 `
 	commitDeltaFieldDescription string = `
 The CBOR encoded representation of the value that is saved as part of this commit.
+`
+	commitFieldIDFieldDescription string = `
+The ID of the field that this commit is for, 'C' for composite (document level) commits.
+`
+	commitFieldNameFieldDescription string = `
+The name of the field that this commit is for, null for composite (document level) commits.
+`
+	commitPeerFieldDescription string = `
+The ID of the peer that authored this commit. It is only known for commits created while
+ P2P is enabled and for commits received from other peers, and is null otherwise.
 `
 	commitLinkNameFieldDescription string = `
 The Name of the field that this linked commit mutated.
 `
 	commitLinkCIDFieldDescription string = `
 The CID of this linked commit.
+`
+	historyDescription string = `
+FieldHistory represents a value that a field of a document has held, as set by an individual
+ commit to the field.
+`
+	historyValueFieldDescription string = `
+The value that the field was set to by this commit, of the type of the field.
+`
+	historyCIDFieldDescription string = `
+The CID of the commit that set this value.
+`
+	historyHeightFieldDescription string = `
+The height of the commit that set this value in the DAG of the field.
+`
+	historyTimestampFieldDescription string = `
+The time at which the commit that set this value was recorded by this node, when it was made
+ locally or received from another peer. Null if the time is unknown.
+`
+	metaDescription string = `
+DocMeta holds the metadata of a document, as recorded in its DAG.
+`
+	metaHeadsFieldDescription string = `
+The CIDs of the head commits of this document. There is more than one head only while
+ concurrent commits have not been merged.
+`
+	metaSchemaVersionIDFieldDescription string = `
+The ID of the schema version of the latest commit of this document.
+`
+	metaDeletedFieldDescription string = `
+Indicates whether this document has been deleted.
+`
+	metaDeltaCountFieldDescription string = `
+The number of commits of this document, including the one creating it.
+`
+	fieldValueDescription string = `
+The value of a field of any type, returned as is.
 `
 	commitFieldsEnumDescription string = `
 These are the set of fields supported for grouping by in a commits query.
@@ -99,8 +175,8 @@ Returns a set of commits matching any provided criteria. If no arguments are
 `
 	latestCommitsQueryDescription string = `
 Returns a set of head commits matching any provided criteria. If no arguments are
- provided all head commits in the system will be returned. If no 'field' argument
- is provided only composite commits will be returned. This is equivalent to
+ provided all head commits in the system will be returned. If neither a 'field' nor
+ a 'fieldName' argument is provided only composite commits will be returned. This is equivalent to
  a 'commits' query with Depth: 1, and a differing 'field' default value.
 `
 	CountFieldDescription string = `
@@ -116,6 +192,22 @@ Returns the total sum of the specified field values within the specified child s
 Returns the average of the specified field values within the specified child sets. If
  multiple fields/sets are specified, the combined average of all items within each set
  (true average, not an average of averages) will be returned as a single value.
+`
+	RunningSumFieldDescription string = `
+Returns the running sum of the specified field values within the specified child sets,
+ in their order. Each value is the sum of the item and of the items before it, limited
+ to the given window of items if any. If multiple fields/sets are specified, the running
+ sums of each of them will be returned one after the other.
+`
+	RunningAverageFieldDescription string = `
+Returns the running average of the specified field values within the specified child
+ sets, in their order. Each value is the average of the item and of the items before it,
+ limited to the given window of items if any (a moving average). If multiple fields/sets
+ are specified, the running averages of each of them will be returned one after the other.
+`
+	WindowArgDescription string = `
+An optional number of items to which each value of a running aggregate is limited,
+ consisting of the item and the items before it.
 `
 	booleanOperatorBlockDescription string = `
 These are the set of filter operators available for use when filtering on Boolean
@@ -140,6 +232,22 @@ These are the set of filter operators available for use when filtering on Float!
 	intOperatorBlockDescription string = `
 These are the set of filter operators available for use when filtering on Int
  values.
+`
+	int32OperatorBlockDescription string = `
+These are the set of filter operators available for use when filtering on Int32
+ values.
+`
+	uint64OperatorBlockDescription string = `
+These are the set of filter operators available for use when filtering on UInt64
+ values.
+`
+	int32ScalarDescription string = `
+The Int32 scalar type represents signed whole numeric values between -(2^31) and 2^31 - 1,
+ the values outside of that range being rejected when written.
+`
+	uint64ScalarDescription string = `
+The UInt64 scalar type represents unsigned whole numeric values between 0 and 2^64 - 1,
+ the values outside of that range being rejected when written.
 `
 	notNullIntOperatorBlockDescription string = `
 These are the set of filter operators available for use when filtering on Int!
@@ -0,0 +1,59 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	gql "github.com/graphql-go/graphql"
+
+	"github.com/sourcenetwork/defradb/client/request"
+)
+
+var (
+	// FieldValueScalar is the value of a field of any type, which the history of a field holds
+	// whatever the type of the field.
+	FieldValueScalar = gql.NewScalar(gql.ScalarConfig{
+		Name:        "FieldValue",
+		Description: fieldValueDescription,
+		Serialize: func(value any) any {
+			return value
+		},
+	})
+
+	// HistoryObject represents a value held by a field of a document
+	// type FieldHistory {
+	// 	value: FieldValue
+	// 	cid: String
+	// 	height: Int
+	// 	timestamp: DateTime
+	// }
+	HistoryObject = gql.NewObject(gql.ObjectConfig{
+		Name:        request.HistoryTypeName,
+		Description: historyDescription,
+		Fields: gql.Fields{
+			request.ValueFieldName: &gql.Field{
+				Description: historyValueFieldDescription,
+				Type:        FieldValueScalar,
+			},
+			request.CidFieldName: &gql.Field{
+				Description: historyCIDFieldDescription,
+				Type:        gql.String,
+			},
+			request.HeightFieldName: &gql.Field{
+				Description: historyHeightFieldDescription,
+				Type:        gql.Int,
+			},
+			request.TimestampFieldName: &gql.Field{
+				Description: historyTimestampFieldDescription,
+				Type:        gql.DateTime,
+			},
+		},
+	})
+)
@@ -0,0 +1,48 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	gql "github.com/graphql-go/graphql"
+
+	"github.com/sourcenetwork/defradb/client/request"
+)
+
+// MetaObject represents the metadata of a document
+//
+//	type DocMeta {
+//		heads: [String]
+//		schemaVersionId: String
+//		deleted: Boolean
+//		deltaCount: Int
+//	}
+var MetaObject = gql.NewObject(gql.ObjectConfig{
+	Name:        request.MetaTypeName,
+	Description: metaDescription,
+	Fields: gql.Fields{
+		request.HeadsFieldName: &gql.Field{
+			Description: metaHeadsFieldDescription,
+			Type:        gql.NewList(gql.String),
+		},
+		request.SchemaVersionIDFieldName: &gql.Field{
+			Description: metaSchemaVersionIDFieldDescription,
+			Type:        gql.String,
+		},
+		request.DeletedStatusFieldName: &gql.Field{
+			Description: metaDeletedFieldDescription,
+			Type:        gql.Boolean,
+		},
+		request.DeltaCountFieldName: &gql.Field{
+			Description: metaDeltaCountFieldDescription,
+			Type:        gql.Int,
+		},
+	},
+})
@@ -0,0 +1,150 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package types
+
+import (
+	"math"
+	"strconv"
+
+	gql "github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+var (
+	// Int32Scalar is a signed integer of 32 bits, held as an int64 like the Int fields.
+	Int32Scalar = gql.NewScalar(gql.ScalarConfig{
+		Name:        "Int32",
+		Description: int32ScalarDescription,
+		Serialize:   coerceInt32,
+		ParseValue:  coerceInt32,
+		ParseLiteral: func(valueAST ast.Value) any {
+			if intValue, ok := valueAST.(*ast.IntValue); ok {
+				if value, err := strconv.ParseInt(intValue.Value, 10, 32); err == nil {
+					return value
+				}
+			}
+			return nil
+		},
+	})
+
+	// UInt64Scalar is an unsigned integer of 64 bits.
+	UInt64Scalar = gql.NewScalar(gql.ScalarConfig{
+		Name:        "UInt64",
+		Description: uint64ScalarDescription,
+		Serialize:   coerceUInt64,
+		ParseValue:  coerceUInt64,
+		ParseLiteral: func(valueAST ast.Value) any {
+			if intValue, ok := valueAST.(*ast.IntValue); ok {
+				if value, err := strconv.ParseUint(intValue.Value, 10, 64); err == nil {
+					return value
+				}
+			}
+			return nil
+		},
+	})
+
+	// Int32OperatorBlock filter block for Int32 types.
+	Int32OperatorBlock = newIntegerOperatorBlock("Int32OperatorBlock", int32OperatorBlockDescription, Int32Scalar)
+
+	// UInt64OperatorBlock filter block for UInt64 types.
+	UInt64OperatorBlock = newIntegerOperatorBlock(
+		"UInt64OperatorBlock",
+		uint64OperatorBlockDescription,
+		UInt64Scalar,
+	)
+)
+
+// coerceInt32 returns the given value as an int64 if it is a whole number in the range of an
+// int32, or nil otherwise.
+func coerceInt32(value any) any {
+	var i int64
+	switch v := value.(type) {
+	case int:
+		i = int64(v)
+	case int64:
+		i = v
+	case uint64:
+		if v > math.MaxInt32 {
+			return nil
+		}
+		i = int64(v)
+	case float64:
+		if v != math.Trunc(v) || v < math.MinInt32 || v > math.MaxInt32 {
+			return nil
+		}
+		i = int64(v)
+	default:
+		return nil
+	}
+	if i < math.MinInt32 || i > math.MaxInt32 {
+		return nil
+	}
+	return i
+}
+
+// coerceUInt64 returns the given value as an uint64 if it is a whole number in the range of an
+// uint64, or nil otherwise.
+func coerceUInt64(value any) any {
+	switch v := value.(type) {
+	case int:
+		if v < 0 {
+			return nil
+		}
+		return uint64(v)
+	case int64:
+		if v < 0 {
+			return nil
+		}
+		return uint64(v)
+	case uint64:
+		return v
+	case float64:
+		// Floats of 2^64 and above don't fit, the largest uint64 not being a float.
+		if v != math.Trunc(v) || v < 0 || v >= math.MaxUint64 {
+			return nil
+		}
+		return uint64(v)
+	default:
+		return nil
+	}
+}
+
+// newIntegerOperatorBlock returns the filter block of the integer scalar of the given type.
+func newIntegerOperatorBlock(name string, description string, scalar *gql.Scalar) *gql.InputObject {
+	operators := gql.InputObjectConfigFieldMap{}
+	for operator, operatorDescription := range map[string]string{
+		"_eq": eqOperatorDescription,
+		"_ne": neOperatorDescription,
+		"_gt": gtOperatorDescription,
+		"_ge": geOperatorDescription,
+		"_lt": ltOperatorDescription,
+		"_le": leOperatorDescription,
+	} {
+		operators[operator] = &gql.InputObjectFieldConfig{
+			Description: operatorDescription,
+			Type:        scalar,
+		}
+	}
+	operators["_in"] = &gql.InputObjectFieldConfig{
+		Description: inOperatorDescription,
+		Type:        gql.NewList(scalar),
+	}
+	operators["_nin"] = &gql.InputObjectFieldConfig{
+		Description: ninOperatorDescription,
+		Type:        gql.NewList(scalar),
+	}
+
+	return gql.NewInputObject(gql.InputObjectConfig{
+		Name:        name,
+		Description: description,
+		Fields:      operators,
+	})
+}
@@ -12,6 +12,8 @@ package types
 
 import (
 	gql "github.com/graphql-go/graphql"
+
+	"github.com/sourcenetwork/defradb/client/request"
 )
 
 const (
@@ -40,6 +42,32 @@ var (
 		},
 	})
 
+	// CollationInputObject is the input of the collation argument.
+	//
+	// input Collation {
+	// 	locale: String
+	// 	caseInsensitive: Boolean
+	// 	numeric: Boolean
+	// }
+	CollationInputObject = gql.NewInputObject(gql.InputObjectConfig{
+		Name:        "Collation",
+		Description: collationDescription,
+		Fields: gql.InputObjectConfigFieldMap{
+			request.LocaleArgName: &gql.InputObjectFieldConfig{
+				Description: collationLocaleDescription,
+				Type:        gql.String,
+			},
+			request.CaseInsensitiveArgName: &gql.InputObjectFieldConfig{
+				Description: collationCaseInsensitiveDescription,
+				Type:        gql.Boolean,
+			},
+			request.NumericArgName: &gql.InputObjectFieldConfig{
+				Description: collationNumericDescription,
+				Type:        gql.Boolean,
+			},
+		},
+	})
+
 	ExplainEnum = gql.NewEnum(gql.EnumConfig{
 		Name:        "ExplainType",
 		Description: "ExplainType is an enum selecting the type of explanation done by the @explain directive.",
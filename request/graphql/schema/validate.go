@@ -0,0 +1,316 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package schema
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	gql "github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/location"
+
+	"github.com/sourcenetwork/defradb/client/request"
+)
+
+// DiagnosticCode identifies the kind of a semantic error of a schema.
+type DiagnosticCode string
+
+const (
+	DiagnosticUnknownType          DiagnosticCode = "UNKNOWN_TYPE"
+	DiagnosticDuplicateType        DiagnosticCode = "DUPLICATE_TYPE"
+	DiagnosticDuplicateField       DiagnosticCode = "DUPLICATE_FIELD"
+	DiagnosticReservedFieldName    DiagnosticCode = "RESERVED_FIELD_NAME"
+	DiagnosticRelationNameConflict DiagnosticCode = "RELATION_NAME_CONFLICT"
+)
+
+// Diagnostic is a semantic error of a schema, at the position of the definition causing it.
+type Diagnostic struct {
+	Code    DiagnosticCode
+	Message string
+	// Line and Column are the 1-based position of the definition in the schema.
+	Line   int
+	Column int
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%v:%v %s: %s", d.Line, d.Column, d.Code, d.Message)
+}
+
+// Diagnostics are the semantic errors of a schema, ordered by position.
+type Diagnostics []Diagnostic
+
+func (d Diagnostics) String() string {
+	diagnostics := make([]string, len(d))
+	for i, diagnostic := range d {
+		diagnostics[i] = diagnostic.String()
+	}
+	return strings.Join(diagnostics, "; ")
+}
+
+// relationField is a field of an object referencing another object of the schema.
+type relationField struct {
+	host  string
+	field *ast.FieldDefinition
+	// target is the name of the referenced object.
+	target string
+}
+
+// validateDocument returns an error holding the diagnostics of the semantic errors of the
+// given schema, or nil if there are none.
+//
+// The errors are reported together and before the schema is converted into collection
+// descriptions, so that they can all be fixed at once.
+func validateDocument(doc *ast.Document) error {
+	var diagnostics Diagnostics
+	report := func(node ast.Node, code DiagnosticCode, format string, args ...any) {
+		diagnostic := Diagnostic{
+			Code:    code,
+			Message: fmt.Sprintf(format, args...),
+		}
+		if loc := node.GetLoc(); loc != nil {
+			position := location.GetLocation(loc.Source, loc.Start)
+			diagnostic.Line = position.Line
+			diagnostic.Column = position.Column
+		}
+		diagnostics = append(diagnostics, diagnostic)
+	}
+
+	objects := map[string]struct{}{}
+	definitions := []*ast.ObjectDefinition{}
+	for _, def := range doc.Definitions {
+		object, ok := def.(*ast.ObjectDefinition)
+		if !ok {
+			continue
+		}
+		if _, exists := objects[object.Name.Value]; exists {
+			report(object.Name, DiagnosticDuplicateType, "the type %s is already defined", object.Name.Value)
+			continue
+		}
+		objects[object.Name.Value] = struct{}{}
+		definitions = append(definitions, object)
+	}
+
+	knownTypes := make([]string, 0, len(objects)+len(scalarTypeNames()))
+	for name := range objects {
+		knownTypes = append(knownTypes, name)
+	}
+	for name := range scalarTypeNames() {
+		knownTypes = append(knownTypes, name)
+	}
+	sort.Strings(knownTypes)
+
+	relations := map[string][]relationField{}
+	relationNames := []string{}
+	for _, object := range definitions {
+		fields := map[string]struct{}{}
+		for _, field := range object.Fields {
+			name := field.Name.Value
+			if request.ReservedFields[name] {
+				report(
+					field.Name,
+					DiagnosticReservedFieldName,
+					"the field %s of the type %s has a name reserved by DefraDB, rename it",
+					name,
+					object.Name.Value,
+				)
+			}
+			if _, exists := fields[name]; exists {
+				report(
+					field.Name,
+					DiagnosticDuplicateField,
+					"the field %s of the type %s is already defined",
+					name,
+					object.Name.Value,
+				)
+			}
+			fields[name] = struct{}{}
+
+			typeName, isList := namedType(field.Type)
+			if _, isScalar := scalarTypeNames()[typeName]; isScalar {
+				continue
+			}
+			if _, isObject := objects[typeName]; !isObject {
+				report(
+					field.Type,
+					DiagnosticUnknownType,
+					"the field %s of the type %s references the unknown type %s%s",
+					name,
+					object.Name.Value,
+					typeName,
+					suggestion(typeName, knownTypes),
+				)
+				continue
+			}
+
+			if !isList {
+				// An _id field is added for every relation to a single object.
+				idField := fmt.Sprintf("%s_id", name)
+				if _, exists := fields[idField]; exists {
+					report(
+						field.Name,
+						DiagnosticDuplicateField,
+						"the field %s of the type %s is generated for the relation %s, rename one of them",
+						idField,
+						object.Name.Value,
+						name,
+					)
+				}
+				fields[idField] = struct{}{}
+			}
+
+			relationName, err := getRelationshipName(field, object.Name.Value, typeName)
+			if err != nil {
+				// Invalid relation names are reported when the relations are registered.
+				continue
+			}
+			if _, exists := relations[relationName]; !exists {
+				relationNames = append(relationNames, relationName)
+			}
+			relations[relationName] = append(relations[relationName], relationField{
+				host:   object.Name.Value,
+				field:  field,
+				target: typeName,
+			})
+		}
+	}
+
+	for _, relationName := range relationNames {
+		fields := relations[relationName]
+		first := fields[0]
+		for _, other := range fields[1:] {
+			switch {
+			case other.field != fields[1].field:
+				report(
+					other.field.Name,
+					DiagnosticRelationNameConflict,
+					"the relation %s of the field %s of the type %s is already defined by the fields %s.%s "+
+						"and %s.%s, name the relation of this field differently",
+					relationName,
+					other.field.Name.Value,
+					other.host,
+					first.host,
+					first.field.Name.Value,
+					fields[1].host,
+					fields[1].field.Name.Value,
+				)
+
+			case other.host != first.target || other.target != first.host:
+				report(
+					other.field.Name,
+					DiagnosticRelationNameConflict,
+					"the relation %s of the field %s of the type %s links %s to %s, but the field %s.%s "+
+						"links %s to %s, both fields of a relation must link the same two types",
+					relationName,
+					other.field.Name.Value,
+					other.host,
+					other.host,
+					other.target,
+					first.host,
+					first.field.Name.Value,
+					first.host,
+					first.target,
+				)
+			}
+		}
+	}
+
+	if len(diagnostics) == 0 {
+		return nil
+	}
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		if diagnostics[i].Line != diagnostics[j].Line {
+			return diagnostics[i].Line < diagnostics[j].Line
+		}
+		return diagnostics[i].Column < diagnostics[j].Column
+	})
+	return NewErrInvalidSchema(diagnostics)
+}
+
+// scalarTypeNames returns the names of the scalar types fields can have.
+func scalarTypeNames() map[string]struct{} {
+	names := map[string]struct{}{}
+	for _, t := range fieldKindToGQLType {
+		if scalar, ok := t.(*gql.Scalar); ok {
+			names[scalar.Name()] = struct{}{}
+		}
+	}
+	return names
+}
+
+// namedType returns the name of the type of the given field type, whether it is a list or not.
+func namedType(t ast.Type) (string, bool) {
+	isList := false
+	for {
+		switch typed := t.(type) {
+		case *ast.NonNull:
+			t = typed.Type
+		case *ast.List:
+			isList = true
+			t = typed.Type
+		case *ast.Named:
+			return typed.Name.Value, isList
+		default:
+			return "", isList
+		}
+	}
+}
+
+// suggestion returns a suggestion of the known type the given unknown type was possibly meant to
+// be, or an empty string if none is close enough.
+func suggestion(unknownType string, knownTypes []string) string {
+	best := ""
+	bestDistance := 3
+	for _, known := range knownTypes {
+		distance := editDistance(strings.ToLower(unknownType), strings.ToLower(known))
+		if distance < bestDistance {
+			best = known
+			bestDistance = distance
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return fmt.Sprintf(", did you mean %s?", best)
+}
+
+// editDistance returns the Levenshtein distance between the given strings.
+func editDistance(a string, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
+
+func minInt(values ...int) int {
+	result := values[0]
+	for _, value := range values[1:] {
+		if value < result {
+			result = value
+		}
+	}
+	return result
+}
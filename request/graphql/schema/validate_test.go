@@ -0,0 +1,151 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package schema
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/errors"
+)
+
+func TestFromStringWithValidSchema(t *testing.T) {
+	_, err := FromString(context.Background(), `
+		type Book {
+			name: String
+			author: Author
+		}
+		type Author {
+			name: String
+			published: [Book]
+		}
+	`)
+	require.NoError(t, err)
+}
+
+func TestFromStringWithUnknownTypeSuggestsCloseType(t *testing.T) {
+	_, err := FromString(context.Background(), `
+		type Book {
+			name: String
+			author: Autor
+		}
+		type Author {
+			name: String
+		}
+	`)
+
+	require.ErrorIs(t, err, ErrInvalidSchema)
+	assert.Equal(t, errors.CodeInvalidSchema, errors.CodeOf(err))
+	assert.Contains(
+		t,
+		err.Error(),
+		"4:12 UNKNOWN_TYPE: the field author of the type Book references the unknown type Autor, did you mean Author?",
+	)
+}
+
+func TestFromStringWithReservedFieldName(t *testing.T) {
+	_, err := FromString(context.Background(), `
+		type Book {
+			_key: String
+		}
+	`)
+
+	require.ErrorIs(t, err, ErrInvalidSchema)
+	assert.Contains(
+		t,
+		err.Error(),
+		"3:4 RESERVED_FIELD_NAME: the field _key of the type Book has a name reserved by DefraDB, rename it",
+	)
+}
+
+func TestFromStringWithFieldConflictingWithRelationIDField(t *testing.T) {
+	_, err := FromString(context.Background(), `
+		type Book {
+			author_id: String
+			author: Author
+		}
+		type Author {
+			published: [Book]
+		}
+	`)
+
+	require.ErrorIs(t, err, ErrInvalidSchema)
+	assert.Contains(
+		t,
+		err.Error(),
+		"4:4 DUPLICATE_FIELD: the field author_id of the type Book is generated for the relation author",
+	)
+}
+
+func TestFromStringWithRelationNameUsedThrice(t *testing.T) {
+	_, err := FromString(context.Background(), `
+		type Book {
+			author: Author @relation(name: "written")
+			editor: Author @relation(name: "written")
+		}
+		type Author {
+			published: [Book] @relation(name: "written")
+		}
+	`)
+
+	require.ErrorIs(t, err, ErrInvalidSchema)
+	assert.Contains(
+		t,
+		err.Error(),
+		"7:4 RELATION_NAME_CONFLICT: the relation written of the field published of the type Author is already "+
+			"defined by the fields Book.author and Book.editor",
+	)
+}
+
+func TestFromStringWithRelationNameLinkingDifferentTypes(t *testing.T) {
+	_, err := FromString(context.Background(), `
+		type Book {
+			author: Author @relation(name: "link")
+		}
+		type Author {
+			name: String
+		}
+		type Publisher {
+			published: [Book] @relation(name: "link")
+		}
+	`)
+
+	require.ErrorIs(t, err, ErrInvalidSchema)
+	assert.Contains(
+		t,
+		err.Error(),
+		"9:4 RELATION_NAME_CONFLICT: the relation link of the field published of the type Publisher links "+
+			"Publisher to Book, but the field Book.author links Book to Author",
+	)
+}
+
+func TestFromStringReportsAllDiagnosticsInOrder(t *testing.T) {
+	_, err := FromString(context.Background(), `
+		type Book {
+			name: Strin
+			_version: String
+		}
+		type Book {}
+	`)
+
+	require.ErrorIs(t, err, ErrInvalidSchema)
+	assert.Equal(
+		t,
+		"the schema is invalid. Diagnostics: "+
+			"3:10 UNKNOWN_TYPE: the field name of the type Book references the unknown type Strin, did you mean String?; "+
+			"4:4 RESERVED_FIELD_NAME: the field _version of the type Book has a name reserved by DefraDB, rename it; "+
+			"6:8 DUPLICATE_TYPE: the type Book is already defined",
+		err.Error(),
+	)
+}
@@ -0,0 +1,178 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package graphql
+
+import (
+	"fmt"
+	"reflect"
+
+	gql "github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/kinds"
+	"github.com/graphql-go/graphql/language/visitor"
+)
+
+// validationRules are the rules requests are validated against. They are the rules of the
+// GraphQL specification, with possibleFragmentSpreadsRule and variablesInAllowedPositionRule in
+// place of the graphql-go ones.
+var validationRules = func() []gql.ValidationRuleFn {
+	rules := make([]gql.ValidationRuleFn, len(gql.SpecifiedRules))
+	for i, rule := range gql.SpecifiedRules {
+		switch reflect.ValueOf(rule).Pointer() {
+		case reflect.ValueOf(gql.PossibleFragmentSpreadsRule).Pointer():
+			rules[i] = possibleFragmentSpreadsRule
+		case reflect.ValueOf(gql.VariablesInAllowedPositionRule).Pointer():
+			rules[i] = variablesInAllowedPositionRule
+		default:
+			rules[i] = rule
+		}
+	}
+	return rules
+}()
+
+// possibleFragmentSpreadsRule is the PossibleFragmentSpreadsRule of graphql-go, except that the
+// inline fragments without type condition are not checked.
+//
+// Those always apply to the type of their parent selection, but graphql-go wrongly reports them
+// when they are selected within a list field.
+func possibleFragmentSpreadsRule(context *gql.ValidationContext) *gql.ValidationRuleInstance {
+	instance := gql.PossibleFragmentSpreadsRule(context)
+	checkInlineFragment := instance.VisitorOpts.KindFuncMap[kinds.InlineFragment].Kind
+	instance.VisitorOpts.KindFuncMap[kinds.InlineFragment] = visitor.NamedVisitFuncs{
+		Kind: func(p visitor.VisitFuncParams) (string, any) {
+			if node, ok := p.Node.(*ast.InlineFragment); ok && node != nil && node.TypeCondition == nil {
+				return visitor.ActionNoChange, nil
+			}
+			return checkInlineFragment(p)
+		},
+	}
+	return instance
+}
+
+// variablesInAllowedPositionRule is the VariablesInAllowedPositionRule of graphql-go, except that
+// the variables of an input object type may be used where a list of it is expected, such as in
+// the order argument.
+//
+// Their values are coerced into a list of a single object, as are the object literals, so that
+// the requests declaring the variables of the order argument as a single object remain valid.
+func variablesInAllowedPositionRule(context *gql.ValidationContext) *gql.ValidationRuleInstance {
+	varDefs := map[string]*ast.VariableDefinition{}
+	return &gql.ValidationRuleInstance{
+		VisitorOpts: &visitor.VisitorOptions{
+			KindFuncMap: map[string]visitor.NamedVisitFuncs{
+				kinds.OperationDefinition: {
+					Enter: func(p visitor.VisitFuncParams) (string, any) {
+						varDefs = map[string]*ast.VariableDefinition{}
+						return visitor.ActionNoChange, nil
+					},
+					Leave: func(p visitor.VisitFuncParams) (string, any) {
+						operation, ok := p.Node.(*ast.OperationDefinition)
+						if !ok {
+							return visitor.ActionNoChange, nil
+						}
+						for _, usage := range context.RecursiveVariableUsages(operation) {
+							if usage == nil || usage.Node == nil || usage.Node.Name == nil || usage.Type == nil {
+								continue
+							}
+							varDef, ok := varDefs[usage.Node.Name.Value]
+							if !ok {
+								continue
+							}
+							varType := typeFromAST(context.Schema(), varDef.Type)
+							if varType == nil {
+								continue
+							}
+							// The variables with a default value are never null.
+							if _, ok := varType.(*gql.NonNull); !ok && varDef.DefaultValue != nil {
+								varType = gql.NewNonNull(varType)
+							}
+							if isInputSubType(varType, usage.Type) {
+								continue
+							}
+							context.ReportError(gqlerrors.NewError(
+								fmt.Sprintf(
+									`Variable "$%v" of type "%v" used in position expecting type "%v".`,
+									usage.Node.Name.Value,
+									varType,
+									usage.Type,
+								),
+								[]ast.Node{varDef, usage.Node},
+								"",
+								nil,
+								[]int{},
+								nil,
+							))
+						}
+						return visitor.ActionNoChange, nil
+					},
+				},
+				kinds.VariableDefinition: {
+					Kind: func(p visitor.VisitFuncParams) (string, any) {
+						if varDef, ok := p.Node.(*ast.VariableDefinition); ok && varDef.Variable != nil &&
+							varDef.Variable.Name != nil {
+							varDefs[varDef.Variable.Name.Value] = varDef
+						}
+						return visitor.ActionNoChange, nil
+					},
+				},
+			},
+		},
+	}
+}
+
+// typeFromAST returns the type of the given schema the given type refers to, or nil if the
+// schema doesn't hold it.
+func typeFromAST(schema *gql.Schema, typeAST ast.Type) gql.Type {
+	switch t := typeAST.(type) {
+	case *ast.List:
+		if ofType := typeFromAST(schema, t.Type); ofType != nil {
+			return gql.NewList(ofType)
+		}
+	case *ast.NonNull:
+		if ofType := typeFromAST(schema, t.Type); ofType != nil {
+			return gql.NewNonNull(ofType)
+		}
+	case *ast.Named:
+		if t.Name != nil {
+			if named := schema.Type(t.Name.Value); named != nil {
+				return named
+			}
+		}
+	}
+	return nil
+}
+
+// isInputSubType returns true if the values of the given input type may be given where the given
+// expected type is expected, an input object being coerced into a list of it.
+func isInputSubType(inputType gql.Type, expected gql.Type) bool {
+	if inputType == expected {
+		return true
+	}
+	if expected, ok := expected.(*gql.NonNull); ok {
+		if inputType, ok := inputType.(*gql.NonNull); ok {
+			return isInputSubType(inputType.OfType, expected.OfType)
+		}
+		return false
+	}
+	if inputType, ok := inputType.(*gql.NonNull); ok {
+		return isInputSubType(inputType.OfType, expected)
+	}
+	if expected, ok := expected.(*gql.List); ok {
+		if inputType, ok := inputType.(*gql.List); ok {
+			return isInputSubType(inputType.OfType, expected.OfType)
+		}
+		if _, ok := inputType.(*gql.InputObject); ok {
+			return isInputSubType(inputType, expected.OfType)
+		}
+	}
+	return false
+}
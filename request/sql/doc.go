@@ -0,0 +1,18 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+/*
+Package sql provides a SQL frontend for a constrained dialect of SELECT statements.
+
+The statements are translated into GQL requests, which are executed like any other request, and
+the returned documents are flattened into rows. Collections are joined along their relation fields;
+see Select for the supported dialect.
+*/
+package sql
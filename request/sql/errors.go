@@ -0,0 +1,87 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"github.com/sourcenetwork/defradb/errors"
+)
+
+const (
+	errSyntax               string = "syntax error"
+	errUnsupportedStatement string = "unsupported statement"
+	errUnsupportedFeature   string = "unsupported SQL feature"
+	errUndefinedTable       string = "relation does not exist"
+	errUndefinedColumn      string = "column does not exist"
+	errAmbiguousColumn      string = "column reference is ambiguous"
+	errDuplicateAlias       string = "table name specified more than once"
+	errNotRelation          string = "column is not a relation field"
+	errNullComparison       string = "comparisons with NULL must use IS NULL or IS NOT NULL"
+	errSingleStatement      string = "exactly one SELECT statement is expected"
+)
+
+var (
+	ErrSyntax               = errors.New(errSyntax)
+	ErrUnsupportedStatement = errors.New(errUnsupportedStatement)
+	ErrUnsupportedFeature   = errors.New(errUnsupportedFeature)
+	ErrUndefinedTable       = errors.New(errUndefinedTable)
+	ErrUndefinedColumn      = errors.New(errUndefinedColumn)
+	ErrAmbiguousColumn      = errors.New(errAmbiguousColumn)
+	ErrDuplicateAlias       = errors.New(errDuplicateAlias)
+	ErrNotRelation          = errors.New(errNotRelation)
+	ErrNullComparison       = errors.New(errNullComparison)
+	ErrSingleStatement      = errors.New(errSingleStatement)
+)
+
+// NewErrSyntax returns a new error indicating that a statement is not valid at the given token.
+func NewErrSyntax(near string) error {
+	return errors.New(errSyntax, errors.NewKV("Near", near))
+}
+
+// NewErrUnsupportedStatement returns a new error indicating that a statement of the given kind
+// can't be executed.
+func NewErrUnsupportedStatement(kind string) error {
+	return errors.New(errUnsupportedStatement, errors.NewKV("Statement", kind))
+}
+
+// NewErrUnsupportedFeature returns a new error indicating that a statement uses the given
+// feature, which is not part of the supported dialect.
+func NewErrUnsupportedFeature(feature string) error {
+	return errors.New(errUnsupportedFeature, errors.NewKV("Feature", feature))
+}
+
+// NewErrUndefinedTable returns a new error indicating that no collection, or no collection of
+// the statement, has the given name or alias.
+func NewErrUndefinedTable(name string) error {
+	return errors.New(errUndefinedTable, errors.NewKV("Name", name))
+}
+
+// NewErrUndefinedColumn returns a new error indicating that no collection of the statement has a
+// scalar field of the given name.
+func NewErrUndefinedColumn(name string) error {
+	return errors.New(errUndefinedColumn, errors.NewKV("Name", name))
+}
+
+// NewErrAmbiguousColumn returns a new error indicating that more than one collection of the
+// statement has a field of the given name.
+func NewErrAmbiguousColumn(name string) error {
+	return errors.New(errAmbiguousColumn, errors.NewKV("Name", name))
+}
+
+// NewErrDuplicateAlias returns a new error indicating that more than one collection of the
+// statement has the given alias.
+func NewErrDuplicateAlias(alias string) error {
+	return errors.New(errDuplicateAlias, errors.NewKV("Alias", alias))
+}
+
+// NewErrNotRelation returns a new error indicating that a joined field is not a relation field.
+func NewErrNotRelation(name string) error {
+	return errors.New(errNotRelation, errors.NewKV("Name", name))
+}
@@ -0,0 +1,685 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	ds "github.com/ipfs/go-datastore"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/client/request"
+	"github.com/sourcenetwork/defradb/errors"
+)
+
+// constantLabel is the label of the columns of constants, unless given.
+const constantLabel = "?column?"
+
+// Column is a column of the rows of a result.
+type Column struct {
+	Name string
+	// Kind is the kind of the field of the column, or the kind matching its constant.
+	Kind client.FieldKind
+}
+
+// Result is the result of a SELECT statement.
+type Result struct {
+	Columns []Column
+	// Rows are the values of the columns for each selected row.
+	Rows [][]any
+}
+
+// Query executes the given SQL, which must be a single SELECT statement, on the given store.
+func Query(ctx context.Context, store client.Store, sql string) (*Result, error) {
+	statements, err := Parse(sql)
+	if err != nil {
+		return nil, err
+	}
+	if len(statements) != 1 {
+		return nil, ErrSingleStatement
+	}
+	sel, ok := statements[0].(*Select)
+	if !ok {
+		return nil, NewErrUnsupportedStatement(statements[0].Tag())
+	}
+	return Execute(ctx, store, sel)
+}
+
+// Execute executes the given SELECT statement on the given store.
+//
+// The statement is translated into a GQL request of the scanned collection, in which the joined
+// collections are selected as its relations and the conditions on their fields are filters of
+// the relations. The returned documents are then flattened into rows. The ordering, limit and
+// offset apply to the rows, so the limit and offset are applied once the documents are flattened
+// if the statement has joins that may drop or multiply the rows of a document.
+func Execute(ctx context.Context, store client.Store, stmt *Select) (*Result, error) {
+	s, err := newScan(ctx, store, stmt)
+	if err != nil {
+		return nil, err
+	}
+	if s.root() == nil {
+		return &Result{Columns: s.columns(), Rows: [][]any{s.project(nil)}}, nil
+	}
+
+	gqlRequest, err := s.request()
+	if err != nil {
+		return nil, err
+	}
+	res := store.ExecRequest(ctx, gqlRequest)
+	if len(res.GQL.Errors) > 0 {
+		return nil, res.GQL.Errors[0]
+	}
+	docs, ok := res.GQL.Data.([]map[string]any)
+	if !ok {
+		return nil, client.NewErrUnexpectedType[[]map[string]any]("scan result", res.GQL.Data)
+	}
+	return &Result{Columns: s.columns(), Rows: s.rows(docs)}, nil
+}
+
+// source is a collection of a statement: the scanned collection, or a joined one.
+type source struct {
+	index      int
+	alias      string
+	collection client.Collection
+	columns    []Column
+
+	// parent is the source holding the relation the source is joined along, nil for the scanned
+	// collection.
+	parent   *source
+	relation string
+	// many is true if the source is joined along a one-to-many relation.
+	many bool
+	// inner is true if the rows without related documents are dropped.
+	inner    bool
+	children []*source
+
+	// fields are the requested fields of the source.
+	fields     []string
+	conditions []fieldCondition
+}
+
+type fieldCondition struct {
+	field string
+	op    string
+	value any
+}
+
+func (src *source) column(name string) (Column, bool) {
+	for _, c := range src.columns {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return Column{}, false
+}
+
+func (src *source) requestField(name string) {
+	for _, f := range src.fields {
+		if f == name {
+			return
+		}
+	}
+	src.fields = append(src.fields, name)
+}
+
+// path returns the relation fields leading to the source from the given ancestor.
+func (src *source) path(ancestor *source) []string {
+	var path []string
+	for s := src; s != ancestor; s = s.parent {
+		path = append([]string{s.relation}, path...)
+	}
+	return path
+}
+
+// queryRoot returns the source selecting the documents the source is selected within: the
+// nearest of itself and its ancestors that is scanned or joined along a one-to-many relation.
+func (src *source) queryRoot() *source {
+	s := src
+	for s.parent != nil && !s.many {
+		s = s.parent
+	}
+	return s
+}
+
+// output is a column of the result.
+type output struct {
+	label string
+	// source is the source of the field of the column, nil for a constant.
+	source *source
+	column Column
+	value  any
+}
+
+type orderField struct {
+	source *source
+	field  string
+	desc   bool
+}
+
+// scan is a statement resolved against the collections of a store.
+type scan struct {
+	sources []*source
+	outputs []output
+	orderBy []orderField
+	limit   *uint64
+	offset  *uint64
+}
+
+func newScan(ctx context.Context, store client.Store, stmt *Select) (*scan, error) {
+	s := &scan{limit: stmt.limit, offset: stmt.offset}
+	if stmt.from != nil {
+		if err := s.addSources(ctx, store, stmt); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, item := range stmt.items {
+		if err := s.addOutputs(item); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, cond := range stmt.where {
+		src, col, err := s.resolve(cond.column)
+		if err != nil {
+			return nil, err
+		}
+		if src.parent != nil {
+			if cond.op == "_eq" && cond.value == nil {
+				return nil, NewErrUnsupportedFeature("IS NULL conditions on the fields of joined collections")
+			}
+			// The condition can't hold without a related document, as in SQL for a LEFT join.
+			for s := src; s.parent != nil; s = s.parent {
+				s.inner = true
+			}
+		}
+		src.conditions = append(src.conditions, fieldCondition{field: col.Name, op: cond.op, value: cond.value})
+	}
+
+	for _, o := range stmt.orderBy {
+		src, col, err := s.resolve(o.column)
+		if err != nil {
+			return nil, err
+		}
+		if src.queryRoot() != s.root() {
+			return nil, NewErrUnsupportedFeature("ordering by the fields of a one-to-many join")
+		}
+		s.orderBy = append(s.orderBy, orderField{source: src, field: col.Name, desc: o.desc})
+	}
+	return s, nil
+}
+
+// addSources adds the scanned and joined collections of the given statement.
+func (s *scan) addSources(ctx context.Context, store client.Store, stmt *Select) error {
+	col, err := getCollection(ctx, store, stmt.from.name)
+	if err != nil {
+		return err
+	}
+	s.addSource(&source{alias: stmt.from.alias, collection: col})
+
+	for _, j := range stmt.joins {
+		parent := s.source(j.parent)
+		if parent == nil {
+			return NewErrUndefinedTable(j.parent)
+		}
+		if s.source(j.alias) != nil {
+			return NewErrDuplicateAlias(j.alias)
+		}
+		for _, child := range parent.children {
+			if child.relation == j.relation {
+				return NewErrUnsupportedFeature("joining a relation more than once")
+			}
+		}
+
+		field, ok := parent.collection.Description().GetField(j.relation)
+		if !ok || !field.IsObject() {
+			return NewErrNotRelation(j.parent + "." + j.relation)
+		}
+		related, err := getCollection(ctx, store, field.Schema)
+		if err != nil {
+			return err
+		}
+		child := &source{
+			alias:      j.alias,
+			collection: related,
+			parent:     parent,
+			relation:   j.relation,
+			many:       field.IsObjectArray(),
+			inner:      !j.left,
+		}
+		parent.children = append(parent.children, child)
+		s.addSource(child)
+	}
+	return nil
+}
+
+func (s *scan) addSource(src *source) {
+	src.index = len(s.sources)
+	src.columns = collectionColumns(src.collection)
+	src.fields = []string{request.KeyFieldName}
+	s.sources = append(s.sources, src)
+}
+
+func getCollection(ctx context.Context, store client.Store, name string) (client.Collection, error) {
+	col, err := store.GetCollectionByName(ctx, name)
+	if errors.Is(err, ds.ErrNotFound) {
+		return nil, NewErrUndefinedTable(name)
+	}
+	return col, err
+}
+
+// collectionColumns returns the columns of the given collection: its key and its scalar fields,
+// in the order of its schema. Relations are not columns, but their ID fields are.
+func collectionColumns(col client.Collection) []Column {
+	columns := []Column{{Name: request.KeyFieldName, Kind: client.FieldKind_DocKey}}
+	for _, field := range col.Schema().Fields {
+		if field.Name == request.KeyFieldName || field.IsObject() {
+			continue
+		}
+		columns = append(columns, Column{Name: field.Name, Kind: field.Kind})
+	}
+	return columns
+}
+
+func (s *scan) root() *source {
+	if len(s.sources) == 0 {
+		return nil
+	}
+	return s.sources[0]
+}
+
+// source returns the source of the given alias, nil if none.
+func (s *scan) source(alias string) *source {
+	for _, src := range s.sources {
+		if src.alias == alias {
+			return src
+		}
+	}
+	return nil
+}
+
+// resolve returns the source and the column of the given column reference.
+//
+// Unqualified columns must be columns of exactly one source.
+func (s *scan) resolve(ref columnRef) (*source, Column, error) {
+	if ref.table != "" {
+		src := s.source(ref.table)
+		if src == nil {
+			return nil, Column{}, NewErrUndefinedTable(ref.table)
+		}
+		col, ok := src.column(ref.name)
+		if !ok {
+			return nil, Column{}, NewErrUndefinedColumn(ref.String())
+		}
+		return src, col, nil
+	}
+
+	var found *source
+	var col Column
+	for _, src := range s.sources {
+		if c, ok := src.column(ref.name); ok {
+			if found != nil {
+				return nil, Column{}, NewErrAmbiguousColumn(ref.name)
+			}
+			found, col = src, c
+		}
+	}
+	if found == nil {
+		return nil, Column{}, NewErrUndefinedColumn(ref.name)
+	}
+	return found, col, nil
+}
+
+func (s *scan) addOutputs(item selectItem) error {
+	switch {
+	case item.constant:
+		label := item.label
+		if label == "" {
+			label = constantLabel
+		}
+		s.outputs = append(s.outputs, output{
+			label:  label,
+			column: Column{Name: label, Kind: constantKind(item.value)},
+			value:  item.value,
+		})
+
+	case item.star:
+		sources := s.sources
+		if item.column.table != "" {
+			src := s.source(item.column.table)
+			if src == nil {
+				return NewErrUndefinedTable(item.column.table)
+			}
+			sources = []*source{src}
+		} else if len(sources) == 0 {
+			return NewErrSyntax("*")
+		}
+		for _, src := range sources {
+			for _, col := range src.columns {
+				src.requestField(col.Name)
+				s.outputs = append(s.outputs, output{label: col.Name, source: src, column: col})
+			}
+		}
+
+	default:
+		src, col, err := s.resolve(item.column)
+		if err != nil {
+			return err
+		}
+		label := item.label
+		if label == "" {
+			label = col.Name
+		}
+		src.requestField(col.Name)
+		s.outputs = append(s.outputs, output{label: label, source: src, column: col})
+	}
+	return nil
+}
+
+func constantKind(value any) client.FieldKind {
+	switch value.(type) {
+	case bool:
+		return client.FieldKind_BOOL
+	case int64:
+		return client.FieldKind_INT
+	case float64:
+		return client.FieldKind_FLOAT
+	default:
+		return client.FieldKind_STRING
+	}
+}
+
+func (s *scan) columns() []Column {
+	columns := make([]Column, len(s.outputs))
+	for i, o := range s.outputs {
+		columns[i] = Column{Name: o.label, Kind: o.column.Kind}
+	}
+	return columns
+}
+
+// pushesPagination returns true if the limit and offset can be applied to the documents of the
+// scanned collection, which is the case if each of them is one row.
+func (s *scan) pushesPagination() bool {
+	for _, src := range s.sources[1:] {
+		if src.many || src.inner {
+			return false
+		}
+	}
+	return true
+}
+
+// request returns the GQL request of the scan.
+func (s *scan) request() (string, error) {
+	root := s.root()
+	args, err := s.filterArgs(root)
+	if err != nil {
+		return "", err
+	}
+	if len(s.orderBy) > 0 {
+		args = append(args, s.orderArg())
+	}
+	if s.pushesPagination() {
+		if s.limit != nil {
+			args = append(args, fmt.Sprintf("%s: %d", request.LimitClause, *s.limit))
+		}
+		if s.offset != nil {
+			args = append(args, fmt.Sprintf("%s: %d", request.OffsetClause, *s.offset))
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("query {\n")
+	if err := s.writeSelection(&b, root.collection.Name(), args, root); err != nil {
+		return "", err
+	}
+	b.WriteString("}")
+	return b.String(), nil
+}
+
+func (s *scan) writeSelection(b *strings.Builder, name string, args []string, src *source) error {
+	b.WriteString(name)
+	if len(args) > 0 {
+		b.WriteString("(" + strings.Join(args, ", ") + ")")
+	}
+	b.WriteString(" {\n")
+	for _, field := range src.fields {
+		b.WriteString(field + "\n")
+	}
+	for _, child := range src.children {
+		var childArgs []string
+		if child.many {
+			var err error
+			childArgs, err = s.filterArgs(child)
+			if err != nil {
+				return err
+			}
+		}
+		if err := s.writeSelection(b, child.relation, childArgs, child); err != nil {
+			return err
+		}
+	}
+	b.WriteString("}\n")
+	return nil
+}
+
+// filterArgs returns the filter argument of the selection of the given source, holding the
+// conditions on its fields and on those of the sources selected within it.
+//
+// The conditions are merged into a single object, as the conditions on the fields of relations
+// can't be combined with _and. Only the fields of the source itself may be compared more than
+// once with the same operator.
+func (s *scan) filterArgs(src *source) ([]string, error) {
+	filter := newGQLObject()
+	var extra []string
+	for _, other := range s.sources {
+		if other.queryRoot() != src {
+			continue
+		}
+		path := other.path(src)
+		for _, cond := range other.conditions {
+			value, err := gqlValue(cond.value)
+			if err != nil {
+				return nil, err
+			}
+			ops := filter.object(append(path, cond.field)...)
+			if _, ok := ops.values[cond.op]; !ok {
+				ops.set(cond.op, value)
+				continue
+			}
+			if len(path) > 0 {
+				return nil, NewErrUnsupportedFeature("comparing a field of a joined collection twice with an operator")
+			}
+			extra = append(extra, fmt.Sprintf("{%s: {%s: %s}}", cond.field, cond.op, value))
+		}
+	}
+	if len(extra) > 0 {
+		filter.set("_and", "["+strings.Join(extra, ", ")+"]")
+	}
+	if len(filter.keys) == 0 {
+		return nil, nil
+	}
+	return []string{request.FilterClause + ": " + filter.String()}, nil
+}
+
+// gqlObject is a GQL object literal, which keeps the order of its fields.
+type gqlObject struct {
+	keys    []string
+	values  map[string]string
+	objects map[string]*gqlObject
+}
+
+func newGQLObject() *gqlObject {
+	return &gqlObject{values: map[string]string{}, objects: map[string]*gqlObject{}}
+}
+
+// object returns the object nested under the given path of fields, adding it if needed.
+func (o *gqlObject) object(path ...string) *gqlObject {
+	for _, key := range path {
+		child, ok := o.objects[key]
+		if !ok {
+			child = newGQLObject()
+			o.objects[key] = child
+			o.keys = append(o.keys, key)
+		}
+		o = child
+	}
+	return o
+}
+
+// set sets the given field to the given literal.
+func (o *gqlObject) set(key string, value string) {
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = value
+}
+
+func (o *gqlObject) String() string {
+	entries := make([]string, len(o.keys))
+	for i, key := range o.keys {
+		if child, ok := o.objects[key]; ok {
+			entries[i] = key + ": " + child.String()
+		} else {
+			entries[i] = key + ": " + o.values[key]
+		}
+	}
+	return "{" + strings.Join(entries, ", ") + "}"
+}
+
+// gqlValue returns the GQL literal of the given constant.
+func gqlValue(value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "null", nil
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	default:
+		// The JSON representations of strings, integers and booleans are valid GQL literals.
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	}
+}
+
+// orderArg returns the order argument of the selection of the scanned collection.
+//
+// Each ordering is given its own object of the list form of the argument, so that the orderings
+// on the fields of a joined collection, nested in it, keep their precedence when interleaved with
+// other orderings.
+func (s *scan) orderArg() string {
+	if len(s.orderBy) == 1 {
+		return request.OrderClause + ": " + s.orderObject(s.orderBy[0]).String()
+	}
+	objects := make([]string, len(s.orderBy))
+	for i, o := range s.orderBy {
+		objects[i] = s.orderObject(o).String()
+	}
+	return request.OrderClause + ": [" + strings.Join(objects, ", ") + "]"
+}
+
+// orderObject returns the object of the order argument of the given ordering.
+func (s *scan) orderObject(o orderField) *gqlObject {
+	direction := request.ASC
+	if o.desc {
+		direction = request.DESC
+	}
+	order := newGQLObject()
+	order.object(o.source.path(s.root())...).set(o.field, string(direction))
+	return order
+}
+
+// rows flattens the given documents of the scanned collection into rows.
+func (s *scan) rows(docs []map[string]any) [][]any {
+	rows := [][]any{}
+	for _, doc := range docs {
+		// bound holds the documents of the sources for each row of the document.
+		bound := [][]map[string]any{make([]map[string]any, len(s.sources))}
+		bound[0][0] = doc
+		for _, src := range s.sources[1:] {
+			var next [][]map[string]any
+			for _, row := range bound {
+				related := relatedDocs(row[src.parent.index], src.relation)
+				if len(related) == 0 {
+					if src.inner {
+						continue
+					}
+					related = []map[string]any{nil}
+				}
+				for _, r := range related {
+					joined := make([]map[string]any, len(row))
+					copy(joined, row)
+					joined[src.index] = r
+					next = append(next, joined)
+				}
+			}
+			bound = next
+		}
+		for _, row := range bound {
+			rows = append(rows, s.project(row))
+		}
+	}
+
+	if !s.pushesPagination() {
+		if s.offset != nil {
+			if *s.offset >= uint64(len(rows)) {
+				return [][]any{}
+			}
+			rows = rows[*s.offset:]
+		}
+		if s.limit != nil && *s.limit < uint64(len(rows)) {
+			rows = rows[:*s.limit]
+		}
+	}
+	return rows
+}
+
+// relatedDocs returns the documents of the given relation field of the given document.
+func relatedDocs(doc map[string]any, relation string) []map[string]any {
+	if doc == nil {
+		return nil
+	}
+	switch v := doc[relation].(type) {
+	case map[string]any:
+		return []map[string]any{v}
+	case []map[string]any:
+		return v
+	case []any:
+		docs := make([]map[string]any, 0, len(v))
+		for _, item := range v {
+			if d, ok := item.(map[string]any); ok {
+				docs = append(docs, d)
+			}
+		}
+		return docs
+	default:
+		return nil
+	}
+}
+
+// project returns the values of the output columns for the given documents of the sources.
+func (s *scan) project(docs []map[string]any) []any {
+	values := make([]any, len(s.outputs))
+	for i, o := range s.outputs {
+		switch {
+		case o.source == nil:
+			values[i] = o.value
+		case docs[o.source.index] != nil:
+			values[i] = docs[o.source.index][o.column.Name]
+		}
+	}
+	return values
+}
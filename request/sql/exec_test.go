@@ -0,0 +1,215 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	badger "github.com/dgraph-io/badger/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/client"
+	badgerds "github.com/sourcenetwork/defradb/datastore/badger/v3"
+	"github.com/sourcenetwork/defradb/db"
+)
+
+func newTestStore(t *testing.T, ctx context.Context) client.Store {
+	opts := badgerds.Options{Options: badger.DefaultOptions("").WithInMemory(true)}
+	rootstore, err := badgerds.NewDatastore("", &opts)
+	require.NoError(t, err)
+	defra, err := db.NewDB(ctx, rootstore)
+	require.NoError(t, err)
+	t.Cleanup(func() { defra.Close(ctx) })
+
+	err = defra.AddSchema(ctx, `
+		type Author {
+			name: String
+			age: Int
+			verified: Boolean
+			published: [Book]
+		}
+		type Book {
+			name: String
+			rating: Float
+			author: Author
+		}`)
+	require.NoError(t, err)
+
+	authors, err := defra.GetCollectionByName(ctx, "Author")
+	require.NoError(t, err)
+	books, err := defra.GetCollectionByName(ctx, "Book")
+	require.NoError(t, err)
+	create := func(col client.Collection, doc string) string {
+		d, err := client.NewDocFromJSON([]byte(doc))
+		require.NoError(t, err)
+		require.NoError(t, col.Create(ctx, d))
+		return d.Key().String()
+	}
+
+	john := create(authors, `{"name": "John", "age": 65, "verified": true}`)
+	cornelia := create(authors, `{"name": "Cornelia", "age": 48, "verified": false}`)
+	create(authors, `{"name": "Fred", "age": 30}`)
+	create(books, fmt.Sprintf(`{"name": "Painted House", "rating": 4.9, "author_id": %q}`, john))
+	create(books, fmt.Sprintf(`{"name": "A Time for Mercy", "rating": 4.5, "author_id": %q}`, john))
+	create(books, fmt.Sprintf(`{"name": "Theif", "rating": 4.8, "author_id": %q}`, cornelia))
+	create(books, `{"name": "Anonymous", "rating": 2}`)
+	return defra
+}
+
+func TestQuerySelect(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t, ctx)
+
+	result, err := Query(ctx, store, `
+		SELECT name, age AS years FROM Author
+		WHERE age > 40 AND verified IS NOT NULL ORDER BY age LIMIT 5`)
+	require.NoError(t, err)
+	assert.Equal(t, []Column{
+		{Name: "name", Kind: client.FieldKind_STRING},
+		{Name: "years", Kind: client.FieldKind_INT},
+	}, result.Columns)
+	assert.Equal(t, [][]any{{"Cornelia", uint64(48)}, {"John", uint64(65)}}, result.Rows)
+}
+
+func TestQuerySelectConstants(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t, ctx)
+
+	result, err := Query(ctx, store, `SELECT 1, 'a' AS letter`)
+	require.NoError(t, err)
+	assert.Equal(t, []Column{
+		{Name: "?column?", Kind: client.FieldKind_INT},
+		{Name: "letter", Kind: client.FieldKind_STRING},
+	}, result.Columns)
+	assert.Equal(t, [][]any{{int64(1), "a"}}, result.Rows)
+}
+
+func TestQueryJoinOneToMany(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t, ctx)
+
+	result, err := Query(ctx, store, `
+		SELECT a.name, b.name AS book FROM Author a JOIN a.published b
+		WHERE b.rating > 4.6 ORDER BY a.age DESC`)
+	require.NoError(t, err)
+	assert.Equal(t, [][]any{{"John", "Painted House"}, {"Cornelia", "Theif"}}, result.Rows)
+
+	result, err = Query(ctx, store, `
+		SELECT a.name, b.name AS book FROM Author a LEFT JOIN a.published b
+		ORDER BY a.age OFFSET 1`)
+	require.NoError(t, err)
+	// The offset applies to the rows, not to the authors.
+	assert.Len(t, result.Rows, 3)
+	assert.Equal(t, []any{"Cornelia", "Theif"}, result.Rows[0])
+	assert.ElementsMatch(t, [][]any{
+		{"John", "Painted House"},
+		{"John", "A Time for Mercy"},
+	}, result.Rows[1:])
+}
+
+func TestQueryJoinOneToOne(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t, ctx)
+
+	result, err := Query(ctx, store, `
+		SELECT Book.name, author.name FROM Book LEFT JOIN Book.author
+		ORDER BY author.age, Book.name DESC`)
+	require.NoError(t, err)
+	assert.Equal(t, [][]any{
+		{"Anonymous", nil},
+		{"Theif", "Cornelia"},
+		{"Painted House", "John"},
+		{"A Time for Mercy", "John"},
+	}, result.Rows)
+
+	result, err = Query(ctx, store, `
+		SELECT b.name, a.name FROM Book b JOIN b.author a ORDER BY a.verified, b.name DESC, a.age`)
+	require.NoError(t, err)
+	assert.Equal(t, [][]any{
+		{"Theif", "Cornelia"},
+		{"Painted House", "John"},
+		{"A Time for Mercy", "John"},
+	}, result.Rows)
+
+	result, err = Query(ctx, store, `
+		SELECT b.name FROM Book b JOIN b.author a WHERE a.verified = true ORDER BY b.rating LIMIT 1`)
+	require.NoError(t, err)
+	assert.Equal(t, [][]any{{"A Time for Mercy"}}, result.Rows)
+}
+
+func TestQueryStar(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t, ctx)
+
+	result, err := Query(ctx, store, `SELECT a.*, b.name FROM Author a JOIN a.published b WHERE a.name = 'Cornelia'`)
+	require.NoError(t, err)
+	names := make([]string, len(result.Columns))
+	for i, c := range result.Columns {
+		names[i] = c.Name
+	}
+	assert.Equal(t, []string{"_key", "age", "name", "verified", "name"}, names)
+	require.Len(t, result.Rows, 1)
+	assert.Equal(t, []any{uint64(48), "Cornelia", false, "Theif"}, result.Rows[0][1:])
+}
+
+func TestQueryErrors(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t, ctx)
+
+	for sql, expected := range map[string]error{
+		`SELECT name`:                                                         ErrUndefinedColumn,
+		`SELECT * FROM Publisher`:                                             ErrUndefinedTable,
+		`SELECT title FROM Book`:                                              ErrUndefinedColumn,
+		`SELECT name FROM Book JOIN Book.author`:                              ErrAmbiguousColumn,
+		`SELECT x.name FROM Book`:                                             ErrUndefinedTable,
+		`SELECT name FROM Book JOIN Book.name`:                                ErrNotRelation,
+		`SELECT a.name FROM Book a JOIN a.author a`:                           ErrDuplicateAlias,
+		`SELECT a.name FROM Author a JOIN a.published b ORDER BY b.rating`:    ErrUnsupportedFeature,
+		`SELECT b.name FROM Book b LEFT JOIN b.author a WHERE a.name IS NULL`: ErrUnsupportedFeature,
+		`SELECT 1; SELECT 2`:                                                  ErrSingleStatement,
+		`SET a = 1`:                                                           ErrUnsupportedStatement,
+	} {
+		_, err := Query(ctx, store, sql)
+		assert.ErrorIs(t, err, expected, sql)
+	}
+}
+
+func TestScanRequest(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t, ctx)
+
+	statements, err := Parse(`
+		SELECT a.name, b.name FROM Author a LEFT JOIN a.published b JOIN b.author c
+		WHERE a.age > 18 AND a.age > 20 AND a.age < 65.5 AND b.rating >= 4 AND c.verified = true
+		ORDER BY a.name LIMIT 2`)
+	require.NoError(t, err)
+	s, err := newScan(ctx, store, statements[0].(*Select))
+	require.NoError(t, err)
+
+	request, err := s.request()
+	require.NoError(t, err)
+	assert.Equal(t, `query {
+Author(filter: {age: {_gt: 18, _lt: 65.5}, _and: [{age: {_gt: 20}}]}, order: {name: ASC}) {
+_key
+name
+published(filter: {rating: {_ge: 4}, author: {verified: {_eq: true}}}) {
+_key
+name
+author {
+_key
+}
+}
+}
+}`, request)
+}
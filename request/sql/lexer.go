@@ -0,0 +1,152 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	// tokenIdent is an unquoted identifier or a keyword.
+	tokenIdent
+	tokenQuotedIdent
+	tokenString
+	tokenNumber
+	tokenSymbol
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+}
+
+// lex splits the given SQL into tokens, dropping whitespace and comments.
+func lex(sql string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(sql); {
+		c := sql[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f':
+			i++
+
+		case strings.HasPrefix(sql[i:], "--"):
+			end := strings.IndexByte(sql[i:], '\n')
+			if end < 0 {
+				end = len(sql) - i
+			}
+			i += end
+
+		case strings.HasPrefix(sql[i:], "/*"):
+			end := strings.Index(sql[i+2:], "*/")
+			if end < 0 {
+				return nil, NewErrSyntax("unterminated comment")
+			}
+			i += end + 4
+
+		case c == '\'' || c == '"':
+			value, n, ok := lexQuoted(sql[i:], c)
+			if !ok {
+				return nil, NewErrSyntax("unterminated quoted string")
+			}
+			kind := tokenString
+			if c == '"' {
+				kind = tokenQuotedIdent
+			}
+			tokens = append(tokens, token{kind: kind, value: value})
+			i += n
+
+		case isDigit(c) || (c == '.' && i+1 < len(sql) && isDigit(sql[i+1])):
+			n := lexNumber(sql[i:])
+			tokens = append(tokens, token{kind: tokenNumber, value: sql[i : i+n]})
+			i += n
+
+		case isIdentStart(c):
+			n := 1
+			for i+n < len(sql) && (isIdentStart(sql[i+n]) || isDigit(sql[i+n]) || sql[i+n] == '$') {
+				n++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, value: sql[i : i+n]})
+			i += n
+
+		default:
+			n := 1
+			if i+1 < len(sql) {
+				switch sql[i : i+2] {
+				case "<=", ">=", "<>", "!=":
+					n = 2
+				}
+			}
+			if n == 1 && !strings.ContainsRune("*,;().=<>-", rune(c)) {
+				return nil, NewErrSyntax(string(c))
+			}
+			tokens = append(tokens, token{kind: tokenSymbol, value: sql[i : i+n]})
+			i += n
+		}
+	}
+	return tokens, nil
+}
+
+// lexQuoted returns the content of the string quoted with the given quote at the start of the
+// given SQL, with its doubled quotes unescaped, and the length of the quoted string.
+func lexQuoted(sql string, quote byte) (string, int, bool) {
+	var value strings.Builder
+	for i := 1; i < len(sql); i++ {
+		if sql[i] != quote {
+			value.WriteByte(sql[i])
+			continue
+		}
+		if i+1 < len(sql) && sql[i+1] == quote {
+			value.WriteByte(quote)
+			i++
+			continue
+		}
+		return value.String(), i + 1, true
+	}
+	return "", 0, false
+}
+
+// lexNumber returns the length of the numeric constant at the start of the given SQL.
+func lexNumber(sql string) int {
+	n := 0
+	for n < len(sql) && isDigit(sql[n]) {
+		n++
+	}
+	if n < len(sql) && sql[n] == '.' {
+		n++
+		for n < len(sql) && isDigit(sql[n]) {
+			n++
+		}
+	}
+	if n < len(sql) && (sql[n] == 'e' || sql[n] == 'E') {
+		m := n + 1
+		if m < len(sql) && (sql[m] == '+' || sql[m] == '-') {
+			m++
+		}
+		if m < len(sql) && isDigit(sql[m]) {
+			n = m
+			for n < len(sql) && isDigit(sql[n]) {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
@@ -0,0 +1,548 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Statement is a parsed SQL statement, either a *Select or a *Set.
+type Statement interface {
+	// Tag returns the command tag completing the statement.
+	Tag() string
+}
+
+// Set is a SET statement. The session parameters are not used, so it has no effect, but it is
+// accepted as clients set them when connecting.
+type Set struct{}
+
+func (*Set) Tag() string { return "SET" }
+
+// Select is a SELECT statement of the supported dialect:
+//
+//	SELECT * | table.* | expression [[AS] label] [, ...]
+//	  [FROM collection [[AS] alias]
+//	    [[INNER | LEFT [OUTER]] JOIN table.relation [[AS] alias] [...]]
+//	    [WHERE condition [AND ...]]
+//	    [ORDER BY column [ASC | DESC] [, ...]]
+//	    [LIMIT count | ALL] [OFFSET start [ROW | ROWS]]]
+//
+// where an expression is a column or a constant, and a condition compares a column to a
+// constant, or is `column IS [NOT] NULL`. Columns may be qualified with the alias of their
+// collection, which defaults to its name.
+//
+// Collections are joined along their relation fields, so that the join conditions are implied:
+// `FROM author a JOIN a.published b` joins each author to the books it published.
+//
+// A statement without a FROM clause selects constants, such as the `SELECT 1` used by clients to
+// check connections.
+type Select struct {
+	items []selectItem
+	// from is the scanned collection, nil for a selection of constants.
+	from    *tableRef
+	joins   []join
+	where   []condition
+	orderBy []ordering
+	limit   *uint64
+	offset  *uint64
+}
+
+func (*Select) Tag() string { return "SELECT" }
+
+type tableRef struct {
+	name  string
+	alias string
+}
+
+type columnRef struct {
+	// table is the alias of the collection of the column, empty if unqualified.
+	table string
+	name  string
+}
+
+func (c columnRef) String() string {
+	if c.table == "" {
+		return c.name
+	}
+	return c.table + "." + c.name
+}
+
+type selectItem struct {
+	// star is set for `*`, or for `table.*` with the table of the column.
+	star bool
+	// constant is set for constants, of the given value.
+	constant bool
+	value    any
+	column   columnRef
+	label    string
+}
+
+type join struct {
+	// left is true for LEFT joins, which keep the rows without related documents.
+	left bool
+	// parent is the alias of the collection holding the joined relation field.
+	parent   string
+	relation string
+	alias    string
+}
+
+// condition compares a column to a constant.
+type condition struct {
+	column columnRef
+	// op is the GQL filter operator of the comparison.
+	op    string
+	value any
+}
+
+type ordering struct {
+	column columnRef
+	desc   bool
+}
+
+// comparisonOps maps the SQL comparison operators to the GQL filter operators.
+var comparisonOps = map[string]string{
+	"=":  "_eq",
+	"<>": "_ne",
+	"!=": "_ne",
+	"<":  "_lt",
+	"<=": "_le",
+	">":  "_gt",
+	">=": "_ge",
+}
+
+// reservedKeywords are the keywords that can't be used as unquoted identifiers.
+var reservedKeywords = map[string]struct{}{
+	"SELECT": {}, "FROM": {}, "WHERE": {}, "AND": {}, "OR": {}, "NOT": {}, "ORDER": {}, "BY": {},
+	"LIMIT": {}, "OFFSET": {}, "ASC": {}, "DESC": {}, "IS": {}, "NULL": {}, "TRUE": {},
+	"FALSE": {}, "ALL": {}, "AS": {}, "JOIN": {}, "INNER": {}, "LEFT": {}, "OUTER": {}, "ON": {},
+	"ROW": {}, "ROWS": {},
+}
+
+// Parse parses the statements of the given SQL, separated by semicolons.
+//
+// Identifiers are case sensitive, quoted or not, as are the names of collections and fields.
+func Parse(sql string) ([]Statement, error) {
+	tokens, err := lex(sql)
+	if err != nil {
+		return nil, err
+	}
+
+	var statements []Statement
+	p := &parser{tokens: tokens}
+	for {
+		for p.acceptSymbol(";") {
+		}
+		if p.peek().kind == tokenEOF {
+			return statements, nil
+		}
+		stmt, err := p.parseStatement()
+		if err != nil {
+			return nil, err
+		}
+		statements = append(statements, stmt)
+		if !p.atEnd() {
+			return nil, p.syntaxError()
+		}
+	}
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	if p.pos >= len(p.tokens) {
+		return token{kind: tokenEOF}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.peek()
+	if p.pos < len(p.tokens) {
+		p.pos++
+	}
+	return t
+}
+
+// atEnd returns true if the statement being parsed has no more tokens.
+func (p *parser) atEnd() bool {
+	t := p.peek()
+	return t.kind == tokenEOF || (t.kind == tokenSymbol && t.value == ";")
+}
+
+// acceptKeyword consumes the next token if it is the given keyword.
+func (p *parser) acceptKeyword(keyword string) bool {
+	t := p.peek()
+	if t.kind == tokenIdent && strings.EqualFold(t.value, keyword) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// acceptSymbol consumes the next token if it is the given symbol.
+func (p *parser) acceptSymbol(symbol string) bool {
+	t := p.peek()
+	if t.kind == tokenSymbol && t.value == symbol {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expectKeyword(keyword string) error {
+	if !p.acceptKeyword(keyword) {
+		return p.syntaxError()
+	}
+	return nil
+}
+
+func (p *parser) syntaxError() error {
+	t := p.peek()
+	if t.kind == tokenEOF {
+		return NewErrSyntax("end of input")
+	}
+	return NewErrSyntax(t.value)
+}
+
+func (p *parser) parseStatement() (Statement, error) {
+	switch {
+	case p.acceptKeyword("SELECT"):
+		return p.parseSelect()
+
+	case p.acceptKeyword("SET"):
+		// The session parameters are not used, the statement is only checked to be complete.
+		for !p.atEnd() {
+			p.next()
+		}
+		return &Set{}, nil
+
+	default:
+		t := p.peek()
+		if t.kind != tokenIdent {
+			return nil, p.syntaxError()
+		}
+		return nil, NewErrUnsupportedStatement(strings.ToUpper(t.value))
+	}
+}
+
+func (p *parser) parseSelect() (*Select, error) {
+	stmt := &Select{}
+	for {
+		item, err := p.parseSelectItem()
+		if err != nil {
+			return nil, err
+		}
+		stmt.items = append(stmt.items, item)
+		if !p.acceptSymbol(",") {
+			break
+		}
+	}
+
+	if !p.acceptKeyword("FROM") {
+		return stmt, nil
+	}
+	table, ok := p.acceptIdentifier()
+	if !ok {
+		return nil, p.syntaxError()
+	}
+	alias, err := p.parseAlias(table)
+	if err != nil {
+		return nil, err
+	}
+	stmt.from = &tableRef{name: table, alias: alias}
+
+	for {
+		j, ok, err := p.parseJoin()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+		stmt.joins = append(stmt.joins, j)
+	}
+
+	if p.acceptKeyword("WHERE") {
+		for {
+			cond, err := p.parseCondition()
+			if err != nil {
+				return nil, err
+			}
+			stmt.where = append(stmt.where, cond)
+			if !p.acceptKeyword("AND") {
+				break
+			}
+		}
+	}
+
+	if p.acceptKeyword("ORDER") {
+		if err := p.expectKeyword("BY"); err != nil {
+			return nil, err
+		}
+		for {
+			column, err := p.parseColumn()
+			if err != nil {
+				return nil, err
+			}
+			desc := p.acceptKeyword("DESC")
+			if !desc {
+				p.acceptKeyword("ASC")
+			}
+			stmt.orderBy = append(stmt.orderBy, ordering{column: column, desc: desc})
+			if !p.acceptSymbol(",") {
+				break
+			}
+		}
+	}
+
+	if p.acceptKeyword("LIMIT") && !p.acceptKeyword("ALL") {
+		limit, err := p.parseCount()
+		if err != nil {
+			return nil, err
+		}
+		stmt.limit = &limit
+	}
+	if p.acceptKeyword("OFFSET") {
+		offset, err := p.parseCount()
+		if err != nil {
+			return nil, err
+		}
+		stmt.offset = &offset
+		if !p.acceptKeyword("ROWS") {
+			p.acceptKeyword("ROW")
+		}
+	}
+	return stmt, nil
+}
+
+func (p *parser) parseSelectItem() (selectItem, error) {
+	if p.acceptSymbol("*") {
+		return selectItem{star: true}, nil
+	}
+
+	var item selectItem
+	if name, ok := p.acceptIdentifier(); ok {
+		item.column = columnRef{name: name}
+		if p.acceptSymbol(".") {
+			if p.acceptSymbol("*") {
+				return selectItem{star: true, column: columnRef{table: name}}, nil
+			}
+			column, ok := p.acceptIdentifier()
+			if !ok {
+				return selectItem{}, p.syntaxError()
+			}
+			item.column = columnRef{table: name, name: column}
+		}
+	} else {
+		value, err := p.parseConstant()
+		if err != nil {
+			return selectItem{}, err
+		}
+		item.constant = true
+		item.value = value
+	}
+
+	if p.acceptKeyword("AS") {
+		label, ok := p.acceptIdentifier()
+		if !ok {
+			return selectItem{}, p.syntaxError()
+		}
+		item.label = label
+	} else if label, ok := p.acceptIdentifier(); ok {
+		item.label = label
+	}
+	return item, nil
+}
+
+// parseJoin parses a join, and returns false if there is none.
+func (p *parser) parseJoin() (join, bool, error) {
+	j := join{}
+	switch {
+	case p.acceptKeyword("LEFT"):
+		j.left = true
+		p.acceptKeyword("OUTER")
+		if err := p.expectKeyword("JOIN"); err != nil {
+			return join{}, false, err
+		}
+	case p.acceptKeyword("INNER"):
+		if err := p.expectKeyword("JOIN"); err != nil {
+			return join{}, false, err
+		}
+	case p.acceptKeyword("JOIN"):
+	default:
+		return join{}, false, nil
+	}
+
+	parent, ok := p.acceptIdentifier()
+	if !ok {
+		return join{}, false, p.syntaxError()
+	}
+	if !p.acceptSymbol(".") {
+		return join{}, false, NewErrUnsupportedFeature("joins of collections, join along relation fields instead")
+	}
+	relation, ok := p.acceptIdentifier()
+	if !ok {
+		return join{}, false, p.syntaxError()
+	}
+	alias, err := p.parseAlias(relation)
+	if err != nil {
+		return join{}, false, err
+	}
+	if p.acceptKeyword("ON") {
+		return join{}, false, NewErrUnsupportedFeature("join conditions, the joins along relation fields imply them")
+	}
+
+	j.parent = parent
+	j.relation = relation
+	j.alias = alias
+	return j, true, nil
+}
+
+// parseAlias parses the optional alias of a collection, which defaults to the given name.
+func (p *parser) parseAlias(name string) (string, error) {
+	if p.acceptKeyword("AS") {
+		alias, ok := p.acceptIdentifier()
+		if !ok {
+			return "", p.syntaxError()
+		}
+		return alias, nil
+	}
+	if alias, ok := p.acceptIdentifier(); ok {
+		return alias, nil
+	}
+	return name, nil
+}
+
+// acceptIdentifier consumes the next token if it is an identifier that is not a reserved keyword,
+// and returns it.
+func (p *parser) acceptIdentifier() (string, bool) {
+	t := p.peek()
+	switch t.kind {
+	case tokenQuotedIdent:
+		p.pos++
+		return t.value, true
+	case tokenIdent:
+		if _, reserved := reservedKeywords[strings.ToUpper(t.value)]; reserved {
+			return "", false
+		}
+		p.pos++
+		return t.value, true
+	default:
+		return "", false
+	}
+}
+
+// parseColumn parses a column, qualified or not.
+func (p *parser) parseColumn() (columnRef, error) {
+	name, ok := p.acceptIdentifier()
+	if !ok {
+		return columnRef{}, p.syntaxError()
+	}
+	if !p.acceptSymbol(".") {
+		return columnRef{name: name}, nil
+	}
+	column, ok := p.acceptIdentifier()
+	if !ok {
+		return columnRef{}, p.syntaxError()
+	}
+	return columnRef{table: name, name: column}, nil
+}
+
+func (p *parser) parseCondition() (condition, error) {
+	column, err := p.parseColumn()
+	if err != nil {
+		return condition{}, err
+	}
+
+	if p.acceptKeyword("IS") {
+		op := "_eq"
+		if p.acceptKeyword("NOT") {
+			op = "_ne"
+		}
+		if err := p.expectKeyword("NULL"); err != nil {
+			return condition{}, err
+		}
+		return condition{column: column, op: op, value: nil}, nil
+	}
+
+	t := p.peek()
+	op, ok := comparisonOps[t.value]
+	if t.kind != tokenSymbol || !ok {
+		return condition{}, p.syntaxError()
+	}
+	p.pos++
+	value, err := p.parseConstant()
+	if err != nil {
+		return condition{}, err
+	}
+	if value == nil {
+		return condition{}, ErrNullComparison
+	}
+	return condition{column: column, op: op, value: value}, nil
+}
+
+// parseConstant parses a string, numeric, boolean or null constant.
+//
+// Integers are returned as int64, other numbers as float64.
+func (p *parser) parseConstant() (any, error) {
+	switch {
+	case p.acceptKeyword("NULL"):
+		return nil, nil
+	case p.acceptKeyword("TRUE"):
+		return true, nil
+	case p.acceptKeyword("FALSE"):
+		return false, nil
+	}
+
+	negative := p.acceptSymbol("-")
+	t := p.peek()
+	switch {
+	case t.kind == tokenString && !negative:
+		p.pos++
+		return t.value, nil
+
+	case t.kind == tokenNumber:
+		p.pos++
+		value := t.value
+		if negative {
+			value = "-" + value
+		}
+		if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return i, nil
+		}
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, NewErrSyntax(value)
+		}
+		return f, nil
+
+	default:
+		return nil, p.syntaxError()
+	}
+}
+
+// parseCount parses the non negative integer of a LIMIT or OFFSET clause.
+func (p *parser) parseCount() (uint64, error) {
+	t := p.peek()
+	if t.kind != tokenNumber {
+		return 0, p.syntaxError()
+	}
+	p.pos++
+	n, err := strconv.ParseUint(t.value, 10, 64)
+	if err != nil {
+		return 0, NewErrSyntax(t.value)
+	}
+	return n, nil
+}
@@ -0,0 +1,112 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSelect(t *testing.T) {
+	statements, err := Parse(`
+		-- the adult users
+		SELECT name, "Age" AS age FROM "User"
+		WHERE "Age" >= 18 AND name <> 'O''Brien' AND verified IS NOT NULL /* and more */
+		ORDER BY "Age" DESC, name
+		LIMIT 10 OFFSET 5 ROWS;`)
+	require.NoError(t, err)
+
+	limit, offset := uint64(10), uint64(5)
+	assert.Equal(t, []Statement{&Select{
+		items: []selectItem{
+			{column: columnRef{name: "name"}},
+			{column: columnRef{name: "Age"}, label: "age"},
+		},
+		from: &tableRef{name: "User", alias: "User"},
+		where: []condition{
+			{column: columnRef{name: "Age"}, op: "_ge", value: int64(18)},
+			{column: columnRef{name: "name"}, op: "_ne", value: "O'Brien"},
+			{column: columnRef{name: "verified"}, op: "_ne", value: nil},
+		},
+		orderBy: []ordering{{column: columnRef{name: "Age"}, desc: true}, {column: columnRef{name: "name"}}},
+		limit:   &limit,
+		offset:  &offset,
+	}}, statements)
+}
+
+func TestParseSelectJoins(t *testing.T) {
+	statements, err := Parse(`
+		SELECT a.name, b.*, p.name publisher FROM Author a
+		JOIN a.published b LEFT OUTER JOIN b.publisher AS p INNER JOIN p.owner
+		WHERE b.rating > 4.5 AND owner.name = 'Jane'`)
+	require.NoError(t, err)
+
+	assert.Equal(t, []Statement{&Select{
+		items: []selectItem{
+			{column: columnRef{table: "a", name: "name"}},
+			{star: true, column: columnRef{table: "b"}},
+			{column: columnRef{table: "p", name: "name"}, label: "publisher"},
+		},
+		from: &tableRef{name: "Author", alias: "a"},
+		joins: []join{
+			{parent: "a", relation: "published", alias: "b"},
+			{left: true, parent: "b", relation: "publisher", alias: "p"},
+			{parent: "p", relation: "owner", alias: "owner"},
+		},
+		where: []condition{
+			{column: columnRef{table: "b", name: "rating"}, op: "_gt", value: 4.5},
+			{column: columnRef{table: "owner", name: "name"}, op: "_eq", value: "Jane"},
+		},
+	}}, statements)
+}
+
+func TestParseMultipleStatements(t *testing.T) {
+	statements, err := Parse(`SET client_encoding = 'UTF8'; select * from User limit all;; SELECT 1, -2.5 x, true`)
+	require.NoError(t, err)
+
+	assert.Equal(t, []Statement{
+		&Set{},
+		&Select{items: []selectItem{{star: true}}, from: &tableRef{name: "User", alias: "User"}},
+		&Select{items: []selectItem{
+			{constant: true, value: int64(1)},
+			{constant: true, value: -2.5, label: "x"},
+			{constant: true, value: true},
+		}},
+	}, statements)
+}
+
+func TestParseEmpty(t *testing.T) {
+	statements, err := Parse(" ; -- nothing")
+	require.NoError(t, err)
+	assert.Empty(t, statements)
+}
+
+func TestParseErrors(t *testing.T) {
+	for sql, expected := range map[string]error{
+		`SELECT name FROM User WHERE name = 'John`:         ErrSyntax,
+		`SELECT name FROM User WHERE age > 1 OR 1`:         ErrSyntax,
+		`SELECT count(*) FROM User`:                        ErrSyntax,
+		`SELECT name FROM User LIMIT -1`:                   ErrSyntax,
+		`SELECT name FROM`:                                 ErrSyntax,
+		`SELECT name FROM User JOIN`:                       ErrSyntax,
+		`SELECT name FROM User WHERE name = NULL`:          ErrNullComparison,
+		`SELECT name FROM User JOIN Book`:                  ErrUnsupportedFeature,
+		`SELECT name FROM User JOIN User.books ON id = 1`:  ErrUnsupportedFeature,
+		`DELETE FROM User`:                                 ErrUnsupportedStatement,
+		`SELECT name FROM User; UPDATE User SET a = 1`:     ErrUnsupportedStatement,
+		`SELECT name FROM User u LEFT JOIN u.books b, c.d`: ErrSyntax,
+	} {
+		_, err := Parse(sql)
+		assert.ErrorIs(t, err, expected, sql)
+	}
+}
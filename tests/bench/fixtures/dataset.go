@@ -0,0 +1,278 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package fixtures
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/errors"
+)
+
+const (
+	// AuthorCollectionName is the name of the author collection of the synthetic datasets.
+	AuthorCollectionName = "Author"
+
+	// BookCollectionName is the name of the book collection of the synthetic datasets.
+	BookCollectionName = "Book"
+)
+
+// DatasetSchema is the schema of the synthetic author/book datasets generated by NewDataset.
+const DatasetSchema = `
+	type Author {
+		name: String
+		age: Int
+		verified: Boolean
+		books: [Book]
+	}
+
+	type Book {
+		name: String
+		rating: Float
+		genre: String
+		author: Author
+	}
+`
+
+// Distribution draws a value from the given source of randomness.
+type Distribution func(r *rand.Rand) float64
+
+// Constant returns a distribution always drawing the given value.
+func Constant(value float64) Distribution {
+	return func(r *rand.Rand) float64 {
+		return value
+	}
+}
+
+// Uniform returns a distribution drawing values uniformly within [min, max).
+func Uniform(min, max float64) Distribution {
+	return func(r *rand.Rand) float64 {
+		return min + r.Float64()*(max-min)
+	}
+}
+
+// Normal returns a normal distribution of the given mean and standard deviation, whose values
+// are clamped within [min, max].
+func Normal(mean, stdDev, min, max float64) Distribution {
+	return func(r *rand.Rand) float64 {
+		return math.Max(min, math.Min(max, mean+r.NormFloat64()*stdDev))
+	}
+}
+
+// DatasetConfig configures a synthetic author/book dataset.
+type DatasetConfig struct {
+	// Seed is the seed of the source of randomness the dataset is generated from.
+	//
+	// Datasets generated with the same config, including the seed, are identical.
+	Seed int64
+
+	// AuthorCount is the number of authors in the dataset.
+	AuthorCount int
+
+	// BooksPerAuthor is the distribution of the number of books of each author, rounded
+	// down to the nearest integer.
+	BooksPerAuthor Distribution
+
+	// AuthorAge is the distribution of the age of the authors, rounded down to the
+	// nearest integer.
+	AuthorAge Distribution
+
+	// VerifiedRatio is the probability of an author to be verified.
+	VerifiedRatio float64
+
+	// BookRating is the distribution of the rating of the books, rounded to one decimal.
+	BookRating Distribution
+
+	// Genres are the genres the books are uniformly assigned from.
+	Genres []string
+}
+
+// DefaultDatasetConfig returns the config of a dataset with the given number of authors, with
+// three books each.
+func DefaultDatasetConfig(authorCount int) DatasetConfig {
+	return DatasetConfig{
+		Seed:           hashSeed("defradb"),
+		AuthorCount:    authorCount,
+		BooksPerAuthor: Constant(3),
+		AuthorAge:      Uniform(20, 80),
+		VerifiedRatio:  0.5,
+		BookRating:     Uniform(0, 5),
+		Genres:         []string{"fiction", "history", "science", "poetry", "travel"},
+	}
+}
+
+// Author is an author of a synthetic dataset.
+type Author struct {
+	Name     string `json:"name"`
+	Age      int    `json:"age"`
+	Verified bool   `json:"verified"`
+
+	// Key is the key of the author document.
+	Key client.DocKey `json:"-"`
+}
+
+// Book is a book of a synthetic dataset.
+type Book struct {
+	Name     string  `json:"name"`
+	Rating   float64 `json:"rating"`
+	Genre    string  `json:"genre"`
+	AuthorID string  `json:"author_id"`
+
+	// AuthorIndex is the index of the author of the book within the authors of the dataset.
+	AuthorIndex int `json:"-"`
+}
+
+// Dataset is a synthetic author/book dataset, of the DatasetSchema.
+type Dataset struct {
+	Authors []Author
+	Books   []Book
+}
+
+// NewDataset generates the synthetic dataset of the given config.
+//
+// The authors are named `Author <index>` and the books `Book <author index>-<index>`, with the
+// indexes zero-padded so that ordering by name follows the order of generation.
+func NewDataset(config DatasetConfig) (Dataset, error) {
+	if config.AuthorCount < 0 {
+		return Dataset{}, errors.New("author count must not be negative")
+	}
+	if config.BooksPerAuthor == nil || config.AuthorAge == nil || config.BookRating == nil {
+		return Dataset{}, errors.New("dataset distributions must be set")
+	}
+	if len(config.Genres) == 0 {
+		return Dataset{}, errors.New("dataset genres must not be empty")
+	}
+
+	r := rand.New(rand.NewSource(config.Seed))
+	dataset := Dataset{
+		Authors: make([]Author, config.AuthorCount),
+	}
+
+	for i := range dataset.Authors {
+		author := Author{
+			Name:     fmt.Sprintf("Author %06d", i),
+			Age:      int(config.AuthorAge(r)),
+			Verified: r.Float64() < config.VerifiedRatio,
+		}
+		doc, err := newDoc(author)
+		if err != nil {
+			return Dataset{}, err
+		}
+		author.Key = doc.Key()
+		dataset.Authors[i] = author
+
+		bookCount := int(config.BooksPerAuthor(r))
+		for j := 0; j < bookCount; j++ {
+			dataset.Books = append(dataset.Books, Book{
+				Name:        fmt.Sprintf("Book %06d-%03d", i, j),
+				Rating:      math.Round(config.BookRating(r)*10) / 10,
+				Genre:       config.Genres[r.Intn(len(config.Genres))],
+				AuthorID:    author.Key.String(),
+				AuthorIndex: i,
+			})
+		}
+	}
+
+	return dataset, nil
+}
+
+// BooksOf returns the books of the author at the given index.
+func (d Dataset) BooksOf(authorIndex int) []Book {
+	books := []Book{}
+	for _, book := range d.Books {
+		if book.AuthorIndex == authorIndex {
+			books = append(books, book)
+		}
+	}
+	return books
+}
+
+// AuthorDocs returns the JSON documents of the authors.
+func (d Dataset) AuthorDocs() ([]string, error) {
+	return toJSONDocs(d.Authors)
+}
+
+// BookDocs returns the JSON documents of the books.
+func (d Dataset) BookDocs() ([]string, error) {
+	return toJSONDocs(d.Books)
+}
+
+// Load creates the documents of the dataset in the given database, whose schema must already
+// contain the DatasetSchema.
+//
+// The documents are created one at a time through their collection, the same way as any
+// other document.
+func (d Dataset) Load(ctx context.Context, db client.DB) error {
+	authors, err := db.GetCollectionByName(ctx, AuthorCollectionName)
+	if err != nil {
+		return err
+	}
+	books, err := db.GetCollectionByName(ctx, BookCollectionName)
+	if err != nil {
+		return err
+	}
+
+	for _, author := range d.Authors {
+		if err := createDoc(ctx, authors, author); err != nil {
+			return err
+		}
+	}
+	for _, book := range d.Books {
+		if err := createDoc(ctx, books, book); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func createDoc(ctx context.Context, col client.Collection, value any) error {
+	doc, err := newDoc(value)
+	if err != nil {
+		return err
+	}
+	if err := col.Create(ctx, doc); err != nil {
+		return errors.Wrap("failed to create document", err)
+	}
+	return nil
+}
+
+func newDoc(value any) (*client.Document, error) {
+	buf, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return client.NewDocFromJSON(buf)
+}
+
+func toJSONDocs[T any](values []T) ([]string, error) {
+	docs := make([]string, len(values))
+	for i, value := range values {
+		buf, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		docs[i] = string(buf)
+	}
+	return docs, nil
+}
+
+// hashSeed returns a seed derived from the given string, using the FNV-1a hash.
+func hashSeed(s string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	return int64(h.Sum64())
+}
@@ -0,0 +1,100 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package fixtures
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDatasetIsDeterministic(t *testing.T) {
+	config := DefaultDatasetConfig(50)
+	config.BooksPerAuthor = Uniform(0, 10)
+
+	first, err := NewDataset(config)
+	require.NoError(t, err)
+	second, err := NewDataset(config)
+	require.NoError(t, err)
+
+	assert.Equal(t, first, second)
+
+	config.Seed++
+	third, err := NewDataset(config)
+	require.NoError(t, err)
+
+	assert.NotEqual(t, first, third)
+}
+
+func TestNewDatasetWithConstantFanOut(t *testing.T) {
+	config := DefaultDatasetConfig(20)
+	config.BooksPerAuthor = Constant(4)
+
+	dataset, err := NewDataset(config)
+	require.NoError(t, err)
+
+	require.Len(t, dataset.Authors, 20)
+	require.Len(t, dataset.Books, 80)
+	for i, author := range dataset.Authors {
+		books := dataset.BooksOf(i)
+		require.Len(t, books, 4)
+		for _, book := range books {
+			assert.Equal(t, author.Key.String(), book.AuthorID)
+		}
+	}
+}
+
+func TestNewDatasetWithNormalDistributionIsClamped(t *testing.T) {
+	config := DefaultDatasetConfig(200)
+	config.AuthorAge = Normal(40, 50, 18, 99)
+
+	dataset, err := NewDataset(config)
+	require.NoError(t, err)
+
+	for _, author := range dataset.Authors {
+		assert.GreaterOrEqual(t, author.Age, 18)
+		assert.LessOrEqual(t, author.Age, 99)
+	}
+}
+
+func TestNewDatasetWithNegativeAuthorCount(t *testing.T) {
+	_, err := NewDataset(DefaultDatasetConfig(-1))
+	require.Error(t, err)
+}
+
+func TestNewDatasetWithoutGenres(t *testing.T) {
+	config := DefaultDatasetConfig(1)
+	config.Genres = nil
+
+	_, err := NewDataset(config)
+	require.Error(t, err)
+}
+
+func TestDatasetDocs(t *testing.T) {
+	config := DefaultDatasetConfig(1)
+	config.BooksPerAuthor = Constant(1)
+	config.Genres = []string{"poetry"}
+
+	dataset, err := NewDataset(config)
+	require.NoError(t, err)
+
+	authorDocs, err := dataset.AuthorDocs()
+	require.NoError(t, err)
+	require.Len(t, authorDocs, 1)
+	assert.Contains(t, authorDocs[0], `"name":"Author 000000"`)
+
+	bookDocs, err := dataset.BookDocs()
+	require.NoError(t, err)
+	require.Len(t, bookDocs, 1)
+	assert.Contains(t, bookDocs[0], `"genre":"poetry"`)
+	assert.Contains(t, bookDocs[0], `"author_id":"`+dataset.Authors[0].Key.String()+`"`)
+}
@@ -0,0 +1,49 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package planner
+
+import (
+	"context"
+	"testing"
+)
+
+var countQuery = `
+	query {
+		Author {
+			name
+			_count(books: {})
+		}
+	}
+`
+
+func Benchmark_Planner_Exec_Count_10(b *testing.B) {
+	ctx := context.Background()
+	err := runPlanExecBench(b, ctx, 10, countQuery)
+	if err != nil {
+		b.Fatal(err)
+	}
+}
+
+func Benchmark_Planner_Exec_Count_100(b *testing.B) {
+	ctx := context.Background()
+	err := runPlanExecBench(b, ctx, 100, countQuery)
+	if err != nil {
+		b.Fatal(err)
+	}
+}
+
+func Benchmark_Planner_Exec_Count_1000(b *testing.B) {
+	ctx := context.Background()
+	err := runPlanExecBench(b, ctx, 1000, countQuery)
+	if err != nil {
+		b.Fatal(err)
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package planner
+
+import (
+	"context"
+	"testing"
+)
+
+var filterQuery = `
+	query {
+		Author(filter: {age: {_gt: 40}, verified: {_eq: true}}) {
+			_key
+			name
+			age
+		}
+	}
+`
+
+func Benchmark_Planner_Exec_Filter_10(b *testing.B) {
+	ctx := context.Background()
+	err := runPlanExecBench(b, ctx, 10, filterQuery)
+	if err != nil {
+		b.Fatal(err)
+	}
+}
+
+func Benchmark_Planner_Exec_Filter_100(b *testing.B) {
+	ctx := context.Background()
+	err := runPlanExecBench(b, ctx, 100, filterQuery)
+	if err != nil {
+		b.Fatal(err)
+	}
+}
+
+func Benchmark_Planner_Exec_Filter_1000(b *testing.B) {
+	ctx := context.Background()
+	err := runPlanExecBench(b, ctx, 1000, filterQuery)
+	if err != nil {
+		b.Fatal(err)
+	}
+}
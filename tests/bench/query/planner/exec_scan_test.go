@@ -0,0 +1,51 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package planner
+
+import (
+	"context"
+	"testing"
+)
+
+var scanQuery = `
+	query {
+		Author {
+			_key
+			name
+			age
+			verified
+		}
+	}
+`
+
+func Benchmark_Planner_Exec_Scan_10(b *testing.B) {
+	ctx := context.Background()
+	err := runPlanExecBench(b, ctx, 10, scanQuery)
+	if err != nil {
+		b.Fatal(err)
+	}
+}
+
+func Benchmark_Planner_Exec_Scan_100(b *testing.B) {
+	ctx := context.Background()
+	err := runPlanExecBench(b, ctx, 100, scanQuery)
+	if err != nil {
+		b.Fatal(err)
+	}
+}
+
+func Benchmark_Planner_Exec_Scan_1000(b *testing.B) {
+	ctx := context.Background()
+	err := runPlanExecBench(b, ctx, 1000, scanQuery)
+	if err != nil {
+		b.Fatal(err)
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package planner
+
+import (
+	"context"
+	"testing"
+)
+
+var typeJoinOneQuery = `
+	query {
+		Book {
+			name
+			author {
+				name
+				age
+			}
+		}
+	}
+`
+
+func Benchmark_Planner_Exec_TypeJoinOne_10(b *testing.B) {
+	ctx := context.Background()
+	err := runPlanExecBench(b, ctx, 10, typeJoinOneQuery)
+	if err != nil {
+		b.Fatal(err)
+	}
+}
+
+func Benchmark_Planner_Exec_TypeJoinOne_100(b *testing.B) {
+	ctx := context.Background()
+	err := runPlanExecBench(b, ctx, 100, typeJoinOneQuery)
+	if err != nil {
+		b.Fatal(err)
+	}
+}
+
+func Benchmark_Planner_Exec_TypeJoinOne_1000(b *testing.B) {
+	ctx := context.Background()
+	err := runPlanExecBench(b, ctx, 1000, typeJoinOneQuery)
+	if err != nil {
+		b.Fatal(err)
+	}
+}
+
+var typeJoinManyQuery = `
+	query {
+		Author {
+			name
+			books {
+				name
+				rating
+			}
+		}
+	}
+`
+
+func Benchmark_Planner_Exec_TypeJoinMany_10(b *testing.B) {
+	ctx := context.Background()
+	err := runPlanExecBench(b, ctx, 10, typeJoinManyQuery)
+	if err != nil {
+		b.Fatal(err)
+	}
+}
+
+func Benchmark_Planner_Exec_TypeJoinMany_100(b *testing.B) {
+	ctx := context.Background()
+	err := runPlanExecBench(b, ctx, 100, typeJoinManyQuery)
+	if err != nil {
+		b.Fatal(err)
+	}
+}
+
+func Benchmark_Planner_Exec_TypeJoinMany_1000(b *testing.B) {
+	ctx := context.Background()
+	err := runPlanExecBench(b, ctx, 1000, typeJoinManyQuery)
+	if err != nil {
+		b.Fatal(err)
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package planner
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/core"
+	"github.com/sourcenetwork/defradb/db/fetcher"
+	"github.com/sourcenetwork/defradb/errors"
+	benchutils "github.com/sourcenetwork/defradb/tests/bench"
+	"github.com/sourcenetwork/defradb/tests/bench/fixtures"
+)
+
+// setupAuthorBookDB creates a new database with the default synthetic author/book dataset,
+// containing the given number of authors.
+//
+// The dataset is fully deterministic so that runs can be compared with each other.
+func setupAuthorBookDB(b *testing.B, ctx context.Context, authorCount int) (client.DB, error) {
+	dataset, err := fixtures.NewDataset(fixtures.DefaultDatasetConfig(authorCount))
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := benchutils.NewTestDB(ctx, b)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.AddSchema(ctx, fixtures.DatasetSchema); err != nil {
+		db.Close(ctx)
+		return nil, errors.Wrap("couldn't load schema", err)
+	}
+
+	if err := dataset.Load(ctx, db); err != nil {
+		db.Close(ctx)
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// runPlanExecBench benchmarks the execution of the given query against the synthetic
+// author/book dataset with the given number of authors.
+func runPlanExecBench(
+	b *testing.B,
+	ctx context.Context,
+	authorCount int,
+	query string,
+) error {
+	db, err := setupAuthorBookDB(b, ctx, authorCount)
+	if err != nil {
+		return err
+	}
+	defer db.Close(ctx)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		res := db.ExecRequest(ctx, query)
+		if len(res.GQL.Errors) > 0 {
+			return errors.New(fmt.Sprintf("Query error: %v", res.GQL.Errors))
+		}
+	}
+	b.StopTimer()
+
+	return nil
+}
+
+// runFetcherBench benchmarks a full scan of the author collection of the synthetic dataset
+// using the document fetcher directly, bypassing the planner.
+func runFetcherBench(
+	b *testing.B,
+	ctx context.Context,
+	authorCount int,
+) error {
+	db, err := setupAuthorBookDB(b, ctx, authorCount)
+	if err != nil {
+		return err
+	}
+	defer db.Close(ctx)
+
+	col, err := db.GetCollectionByName(ctx, fixtures.AuthorCollectionName)
+	if err != nil {
+		return err
+	}
+	desc := col.Description()
+
+	txn, err := db.NewTxn(ctx, true)
+	if err != nil {
+		return err
+	}
+	defer txn.Discard(ctx)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		df := new(fetcher.DocumentFetcher)
+		if err := df.Init(&desc, nil, false, false); err != nil {
+			return err
+		}
+		if err := df.Start(ctx, txn, core.Spans{}); err != nil {
+			return err
+		}
+
+		count := 0
+		for {
+			doc, err := df.FetchNextDecoded(ctx)
+			if err != nil {
+				return err
+			}
+			if doc == nil {
+				break
+			}
+			count++
+		}
+		if err := df.Close(); err != nil {
+			return err
+		}
+		if count != authorCount {
+			return errors.New(fmt.Sprintf("expected %v documents, got %v", authorCount, count))
+		}
+	}
+	b.StopTimer()
+
+	return nil
+}
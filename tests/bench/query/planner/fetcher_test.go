@@ -0,0 +1,40 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package planner
+
+import (
+	"context"
+	"testing"
+)
+
+func Benchmark_Planner_Fetcher_Scan_10(b *testing.B) {
+	ctx := context.Background()
+	err := runFetcherBench(b, ctx, 10)
+	if err != nil {
+		b.Fatal(err)
+	}
+}
+
+func Benchmark_Planner_Fetcher_Scan_100(b *testing.B) {
+	ctx := context.Background()
+	err := runFetcherBench(b, ctx, 100)
+	if err != nil {
+		b.Fatal(err)
+	}
+}
+
+func Benchmark_Planner_Fetcher_Scan_1000(b *testing.B) {
+	ctx := context.Background()
+	err := runFetcherBench(b, ctx, 1000)
+	if err != nil {
+		b.Fatal(err)
+	}
+}
@@ -39,7 +39,7 @@ func runQueryParserBench(
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		ast, _ := parser.BuildRequestAST(query)
-		_, errs := parser.Parse(ast)
+		_, errs := parser.Parse(ast, nil)
 		if errs != nil {
 			return errors.Wrap("failed to parse query string", errors.New(fmt.Sprintf("%v", errs)))
 		}
@@ -67,7 +67,7 @@ func runMakePlanBench(
 	}
 
 	ast, _ := parser.BuildRequestAST(query)
-	q, errs := parser.Parse(ast)
+	q, errs := parser.Parse(ast, nil)
 	if len(errs) > 0 {
 		return errors.Wrap("failed to parse query string", errors.New(fmt.Sprintf("%v", errs)))
 	}
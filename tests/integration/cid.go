@@ -0,0 +1,186 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/ipfs/go-cid"
+	ipld "github.com/ipfs/go-ipld-format"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/client"
+	"github.com/sourcenetwork/defradb/core"
+	"github.com/sourcenetwork/defradb/core/crdt"
+	"github.com/sourcenetwork/defradb/merkle/clock"
+)
+
+// DocCreateCID may be used in the `Results` of a request in place of the CID of the commit
+// creating a document, which is then derived at runtime from the created document and the
+// schema of its collection.
+//
+// Unlike hard-coded CIDs, it doesn't need updating whenever the encoding of the commits changes.
+type DocCreateCID struct {
+	// CollectionID is the index of the collection of the document, as used by CreateDoc.
+	CollectionID int
+
+	// DocID is the index of the document within its collection, in the order in which the
+	// documents are created.
+	DocID int
+
+	// FieldName is the name of the field whose commit CID is derived.
+	//
+	// The CID of the composite commit of the document is derived if empty.
+	FieldName string
+}
+
+// resolveDocCreateCIDs returns the given expected results, with the DocCreateCIDs they contain
+// replaced by the CIDs derived from the documents created by the given test case.
+func resolveDocCreateCIDs(
+	t *testing.T,
+	testCase TestCase,
+	collections []client.Collection,
+	results []map[string]any,
+) []map[string]any {
+	if results == nil {
+		return nil
+	}
+
+	resolved := make([]map[string]any, len(results))
+	for i, result := range results {
+		resolved[i] = resolveDocCreateCIDsOfValue(t, testCase, collections, result).(map[string]any)
+	}
+	return resolved
+}
+
+func resolveDocCreateCIDsOfValue(
+	t *testing.T,
+	testCase TestCase,
+	collections []client.Collection,
+	value any,
+) any {
+	switch v := value.(type) {
+	case DocCreateCID:
+		return deriveDocCreateCID(t, testCase, collections, v).String()
+
+	case map[string]any:
+		resolved := make(map[string]any, len(v))
+		for key, item := range v {
+			resolved[key] = resolveDocCreateCIDsOfValue(t, testCase, collections, item)
+		}
+		return resolved
+
+	case []map[string]any:
+		return resolveDocCreateCIDs(t, testCase, collections, v)
+
+	case []any:
+		resolved := make([]any, len(v))
+		for i, item := range v {
+			resolved[i] = resolveDocCreateCIDsOfValue(t, testCase, collections, item)
+		}
+		return resolved
+
+	default:
+		return value
+	}
+}
+
+// deriveDocCreateCID returns the CID of the given commit, derived from the document created by
+// the matching CreateDoc action of the test case, the same way the commit was made on save.
+func deriveDocCreateCID(
+	t *testing.T,
+	testCase TestCase,
+	collections []client.Collection,
+	target DocCreateCID,
+) cid.Cid {
+	doc := getCreatedDoc(t, testCase, target.CollectionID, target.DocID)
+	collection := collections[target.CollectionID]
+	schemaVersionKey := core.NewCollectionSchemaVersionKey(collection.Schema().VersionID)
+	dataStoreKey := core.DataStoreKey{DocKey: doc.Key().String()}
+
+	links := []core.DAGLink{}
+	docProperties := map[string]any{}
+	for name, field := range doc.Fields() {
+		if isSecondaryRelationIDField(collection.Description(), name) {
+			// The value of secondary relation IDs is saved on the related document instead.
+			continue
+		}
+
+		val, err := doc.GetValueWithField(field)
+		require.NoError(t, err)
+
+		bytes := []byte{}
+		if val.IsDelete() {
+			docProperties[name] = nil
+		} else {
+			bytes, err = val.(client.WriteableValue).Bytes()
+			require.NoError(t, err)
+			docProperties[name] = val.Value()
+		}
+
+		delta := crdt.NewLWWRegister(nil, schemaVersionKey, dataStoreKey).Set(bytes)
+		node := newCreateNode(t, delta)
+		if name == target.FieldName {
+			return node.Cid()
+		}
+		links = append(links, core.DAGLink{Name: name, Cid: node.Cid()})
+	}
+	require.Empty(t, target.FieldName, "the document has no field %s", target.FieldName)
+
+	em, err := cbor.CanonicalEncOptions().EncMode()
+	require.NoError(t, err)
+	data, err := em.Marshal(docProperties)
+	require.NoError(t, err)
+
+	delta := crdt.NewCompositeDAG(nil, schemaVersionKey, nil, dataStoreKey).Set(data, links)
+	return newCreateNode(t, delta).Cid()
+}
+
+// newCreateNode returns the block of the given delta, as the first commit of its DAG.
+func newCreateNode(t *testing.T, delta core.Delta) ipld.Node {
+	delta.SetPriority(1)
+	node, err := clock.NewNode(delta, nil)
+	require.NoError(t, err)
+	return node
+}
+
+// getCreatedDoc returns the document created by the CreateDoc action of the given test case
+// creating the given document of the given collection.
+func getCreatedDoc(t *testing.T, testCase TestCase, collectionID int, docID int) *client.Document {
+	docIndex := 0
+	for _, a := range testCase.Actions {
+		action, ok := a.(CreateDoc)
+		if !ok || action.CollectionID != collectionID {
+			continue
+		}
+		if docIndex == docID {
+			doc, err := client.NewDocFromJSON([]byte(action.Doc))
+			require.NoError(t, err)
+			return doc
+		}
+		docIndex++
+	}
+	require.FailNow(t, "document not found", "collection: %v, doc: %v", collectionID, docID)
+	return nil
+}
+
+// isSecondaryRelationIDField returns true if the given field holds the ID of a related document
+// hosting the relation.
+func isSecondaryRelationIDField(desc client.CollectionDescription, name string) bool {
+	field, ok := desc.GetField(name)
+	if !ok || field.RelationType != client.Relation_Type_INTERNAL_ID {
+		return false
+	}
+	relationField, ok := desc.GetField(strings.TrimSuffix(name, "_id"))
+	return ok && !relationField.IsPrimaryRelation()
+}
@@ -76,8 +76,9 @@ func TestDefaultExplainRequestWithFullBasicGraph(t *testing.T) {
 								"collectionName": "author",
 								"spans": []dataMap{
 									{
-										"start": "/3",
-										"end":   "/4",
+										"start":  "/3",
+										"end":    "/4",
+										"symbol": "author/*",
 									},
 								},
 							},
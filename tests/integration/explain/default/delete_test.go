@@ -56,8 +56,9 @@ func TestExplainDeletionUsingMultiAndSingleIDs_Success(t *testing.T) {
 										"filter":         nil,
 										"spans": []dataMap{
 											{
-												"end":   "/3/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9e",
-												"start": "/3/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9d",
+												"end":    "/3/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9e",
+												"start":  "/3/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9d",
+												"symbol": "author/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9d",
 											},
 										},
 									},
@@ -115,12 +116,14 @@ func TestExplainDeletionUsingMultiAndSingleIDs_Success(t *testing.T) {
 										"filter":         nil,
 										"spans": []dataMap{
 											{
-												"end":   "/3/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9e",
-												"start": "/3/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9d",
+												"end":    "/3/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9e",
+												"start":  "/3/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9d",
+												"symbol": "author/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9d",
 											},
 											{
-												"end":   "/3/bae-bfbfc89c-0d63-5ea4-81a3-3ebd295be67g",
-												"start": "/3/bae-bfbfc89c-0d63-5ea4-81a3-3ebd295be67f",
+												"end":    "/3/bae-bfbfc89c-0d63-5ea4-81a3-3ebd295be67g",
+												"start":  "/3/bae-bfbfc89c-0d63-5ea4-81a3-3ebd295be67f",
+												"symbol": "author/bae-bfbfc89c-0d63-5ea4-81a3-3ebd295be67f",
 											},
 										},
 									},
@@ -189,12 +192,14 @@ func TestExplainDeletionUsingMultiAndSingleIDs_Success(t *testing.T) {
 										"filter":         nil,
 										"spans": []dataMap{
 											{
-												"end":   "/3/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9e",
-												"start": "/3/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9d",
+												"end":    "/3/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9e",
+												"start":  "/3/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9d",
+												"symbol": "author/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9d",
 											},
 											{
-												"end":   "/3/bae-bfbfc89c-0d63-5ea4-81a3-3ebd295be67g",
-												"start": "/3/bae-bfbfc89c-0d63-5ea4-81a3-3ebd295be67f",
+												"end":    "/3/bae-bfbfc89c-0d63-5ea4-81a3-3ebd295be67g",
+												"start":  "/3/bae-bfbfc89c-0d63-5ea4-81a3-3ebd295be67f",
+												"symbol": "author/bae-bfbfc89c-0d63-5ea4-81a3-3ebd295be67f",
 											},
 										},
 									},
@@ -245,8 +250,9 @@ func TestExplainDeletionUsingMultiAndSingleIDs_Success(t *testing.T) {
 										"filter":         nil,
 										"spans": []dataMap{
 											{
-												"end":   "/3/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9e",
-												"start": "/3/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9d",
+												"end":    "/3/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9e",
+												"start":  "/3/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9d",
+												"symbol": "author/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9d",
 											},
 										},
 									},
@@ -311,8 +317,9 @@ func TestExplainDeletionOfDocumentsWithFilter_Success(t *testing.T) {
 										},
 										"spans": []dataMap{
 											{
-												"end":   "/4",
-												"start": "/3",
+												"end":    "/4",
+												"start":  "/3",
+												"symbol": "author/*",
 											},
 										},
 									},
@@ -411,8 +418,9 @@ func TestExplainDeletionOfDocumentsWithFilter_Success(t *testing.T) {
 										},
 										"spans": []dataMap{
 											{
-												"end":   "/4",
-												"start": "/3",
+												"end":    "/4",
+												"start":  "/3",
+												"symbol": "author/*",
 											},
 										},
 									},
@@ -480,8 +488,9 @@ func TestExplainDeletionOfDocumentsWithFilter_Success(t *testing.T) {
 										"filter":         dataMap{},
 										"spans": []dataMap{
 											{
-												"end":   "/4",
-												"start": "/3",
+												"end":    "/4",
+												"start":  "/3",
+												"symbol": "author/*",
 											},
 										},
 									},
@@ -540,8 +549,9 @@ func TestExplainDeletionUsingMultiIdsAndSingleIdAndFilter_Failure(t *testing.T)
 										"filter":         nil,
 										"spans": []dataMap{
 											{
-												"end":   "/3/bae-6a6482a8-24e1-5c73-a237-ca569e41507f",
-												"start": "/3/bae-6a6482a8-24e1-5c73-a237-ca569e41507e",
+												"end":    "/3/bae-6a6482a8-24e1-5c73-a237-ca569e41507f",
+												"start":  "/3/bae-6a6482a8-24e1-5c73-a237-ca569e41507e",
+												"symbol": "author/bae-6a6482a8-24e1-5c73-a237-ca569e41507e",
 											},
 										},
 									},
@@ -584,12 +594,9 @@ func TestExplainDeletionUsingMultiIdsAndSingleIdAndFilter_Failure(t *testing.T)
 										"filter":         nil,
 										"spans": []dataMap{
 											{
-												"end":   "/3/bae-028383cc-d6ba-5df7-959f-2bdce3536a06",
-												"start": "/3/bae-028383cc-d6ba-5df7-959f-2bdce3536a05",
-											},
-											{
-												"end":   "/3/bae-028383cc-d6ba-5df7-959f-2bdce3536a04",
-												"start": "/3/bae-028383cc-d6ba-5df7-959f-2bdce3536a03",
+												"end":    "/3/bae-028383cc-d6ba-5df7-959f-2bdce3536a06",
+												"start":  "/3/bae-028383cc-d6ba-5df7-959f-2bdce3536a03",
+												"symbol": "[author/bae-028383cc-d6ba-5df7-959f-2bdce3536a03, author/bae-028383cc-d6ba-5df7-959f-2bdce3536a06)",
 											},
 										},
 									},
@@ -675,13 +682,11 @@ func TestExplainDeletionUsingMultiIdsAndSingleIdAndFilter_Failure(t *testing.T)
 										},
 										"spans": []dataMap{
 											{
-												"end":   "/3/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9e",
-												"start": "/3/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9d",
-											},
-											{
-												"end":   "/3/tesu",
-												"start": "/3/test",
+												"end":    "/3/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9e",
+												"start":  "/3/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9d",
+												"symbol": "author/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9d",
 											},
+											// "test" is not a valid key, so it is given no span.
 										},
 									},
 								},
@@ -728,8 +733,9 @@ func TestExplainDeletionUsingMultiIdsAndSingleIdAndFilter_Failure(t *testing.T)
 										},
 										"spans": []dataMap{
 											{
-												"end":   "/4",
-												"start": "/3",
+												"end":    "/4",
+												"start":  "/3",
+												"symbol": "author/*",
 											},
 										},
 									},
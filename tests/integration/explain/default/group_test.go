@@ -73,8 +73,9 @@ func TestExplainSimpleGroupByOnParent(t *testing.T) {
 									"filter":         nil,
 									"spans": []dataMap{
 										{
-											"start": "/3",
-											"end":   "/4",
+											"start":  "/3",
+											"end":    "/4",
+											"symbol": "author/*",
 										},
 									},
 								},
@@ -146,8 +147,9 @@ func TestExplainGroupByTwoFieldsOnParent(t *testing.T) {
 									"filter":         nil,
 									"spans": []dataMap{
 										{
-											"start": "/3",
-											"end":   "/4",
+											"start":  "/3",
+											"end":    "/4",
+											"symbol": "author/*",
 										},
 									},
 								},
@@ -68,6 +68,7 @@ func TestExplainGroupByWithAverageOnAnInnerField(t *testing.T) {
 				"explain": dataMap{
 					"selectTopNode": dataMap{
 						"averageNode": dataMap{
+							"responseField": "_avg",
 							"countNode": dataMap{
 								"sources": []dataMap{
 									{
@@ -115,8 +116,9 @@ func TestExplainGroupByWithAverageOnAnInnerField(t *testing.T) {
 												"filter":         nil,
 												"spans": []dataMap{
 													{
-														"start": "/3",
-														"end":   "/4",
+														"start":  "/3",
+														"end":    "/4",
+														"symbol": "author/*",
 													},
 												},
 											},
@@ -190,6 +192,7 @@ func TestExplainGroupByWithAnAverageInsideTheInnerGroupOnAField(t *testing.T) {
 				"explain": dataMap{
 					"selectTopNode": dataMap{
 						"averageNode": dataMap{
+							"responseField": "_avg",
 							"countNode": dataMap{
 								"sources": []dataMap{
 									{
@@ -225,8 +228,9 @@ func TestExplainGroupByWithAnAverageInsideTheInnerGroupOnAField(t *testing.T) {
 												"filter":         nil,
 												"spans": []dataMap{
 													{
-														"start": "/3",
-														"end":   "/4",
+														"start":  "/3",
+														"end":    "/4",
+														"symbol": "author/*",
 													},
 												},
 											},
@@ -303,6 +307,7 @@ func TestExplainGroupByWithAnAverageInsideTheInnerGroupAndNestedGroupBy(t *testi
 				"explain": dataMap{
 					"selectTopNode": dataMap{
 						"averageNode": dataMap{
+							"responseField": "_avg",
 							"countNode": dataMap{
 								"sources": []dataMap{
 									{
@@ -338,8 +343,9 @@ func TestExplainGroupByWithAnAverageInsideTheInnerGroupAndNestedGroupBy(t *testi
 												"filter":         nil,
 												"spans": []dataMap{
 													{
-														"start": "/3",
-														"end":   "/4",
+														"start":  "/3",
+														"end":    "/4",
+														"symbol": "author/*",
 													},
 												},
 											},
@@ -417,6 +423,7 @@ func TestExplainGroupByWihAnAverageInsideTheInnerGroupAndNestedGroupByWithAnAver
 				"explain": dataMap{
 					"selectTopNode": dataMap{
 						"averageNode": dataMap{
+							"responseField": "_avg",
 							"countNode": dataMap{
 								"sources": []dataMap{
 									{
@@ -452,8 +459,9 @@ func TestExplainGroupByWihAnAverageInsideTheInnerGroupAndNestedGroupByWithAnAver
 												"filter":         nil,
 												"spans": []dataMap{
 													{
-														"start": "/3",
-														"end":   "/4",
+														"start":  "/3",
+														"end":    "/4",
+														"symbol": "author/*",
 													},
 												},
 											},
@@ -78,8 +78,9 @@ func TestExplainQueryWithDockeysFilterOnInnerGroupBy(t *testing.T) {
 									"filter":         nil,
 									"spans": []dataMap{
 										{
-											"start": "/3",
-											"end":   "/4",
+											"start":  "/3",
+											"end":    "/4",
+											"symbol": "author/*",
 										},
 									},
 								},
@@ -157,8 +158,9 @@ func TestExplainQueryWithDockeyOnParentGroupBy(t *testing.T) {
 									"filter":         nil,
 									"spans": []dataMap{
 										{
-											"start": "/3/bae-6a4c5bc5-b044-5a03-a868-8260af6f2254",
-											"end":   "/3/bae-6a4c5bc5-b044-5a03-a868-8260af6f2255",
+											"start":  "/3/bae-6a4c5bc5-b044-5a03-a868-8260af6f2254",
+											"end":    "/3/bae-6a4c5bc5-b044-5a03-a868-8260af6f2255",
+											"symbol": "author/bae-6a4c5bc5-b044-5a03-a868-8260af6f2254",
 										},
 									},
 								},
@@ -244,12 +246,14 @@ func TestExplainQuerySimpleWithDockeysAndFilter(t *testing.T) {
 									},
 									"spans": []dataMap{
 										{
-											"start": "/3/bae-6a4c5bc5-b044-5a03-a868-8260af6f2254",
-											"end":   "/3/bae-6a4c5bc5-b044-5a03-a868-8260af6f2255",
+											"start":  "/3/bae-4ea9d148-13f3-5a48-a0ef-9ffd344caeed",
+											"end":    "/3/bae-4ea9d148-13f3-5a48-a0ef-9ffd344caeee",
+											"symbol": "author/bae-4ea9d148-13f3-5a48-a0ef-9ffd344caeed",
 										},
 										{
-											"start": "/3/bae-4ea9d148-13f3-5a48-a0ef-9ffd344caeed",
-											"end":   "/3/bae-4ea9d148-13f3-5a48-a0ef-9ffd344caeee",
+											"start":  "/3/bae-6a4c5bc5-b044-5a03-a868-8260af6f2254",
+											"end":    "/3/bae-6a4c5bc5-b044-5a03-a868-8260af6f2255",
+											"symbol": "author/bae-6a4c5bc5-b044-5a03-a868-8260af6f2254",
 										},
 									},
 								},
@@ -80,8 +80,9 @@ func TestExplainGroupByWithFilterOnParent(t *testing.T) {
 									},
 									"spans": []dataMap{
 										{
-											"start": "/3",
-											"end":   "/4",
+											"start":  "/3",
+											"end":    "/4",
+											"symbol": "author/*",
 										},
 									},
 								},
@@ -157,8 +158,9 @@ func TestExplainGroupByWithFilterOnInnerGroupSelection(t *testing.T) {
 									"collectionName": "author",
 									"spans": []dataMap{
 										{
-											"start": "/3",
-											"end":   "/4",
+											"start":  "/3",
+											"end":    "/4",
+											"symbol": "author/*",
 										},
 									},
 								},
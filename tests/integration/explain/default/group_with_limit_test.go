@@ -97,8 +97,9 @@ func TestExplainGroupByWithGroupLimitAndOffsetOnParentGroupBy(t *testing.T) {
 										"filter":         nil,
 										"spans": []dataMap{
 											{
-												"start": "/3",
-												"end":   "/4",
+												"start":  "/3",
+												"end":    "/4",
+												"symbol": "author/*",
 											},
 										},
 									},
@@ -191,8 +192,9 @@ func TestExplainGroupByWithGroupLimitAndOffsetOnChild(t *testing.T) {
 									"filter":         nil,
 									"spans": []dataMap{
 										{
-											"start": "/3",
-											"end":   "/4",
+											"start":  "/3",
+											"end":    "/4",
+											"symbol": "author/*",
 										},
 									},
 								},
@@ -297,8 +299,9 @@ func TestExplainGroupByWithGroupLimitOnChildMultipleRendered(t *testing.T) {
 									"filter":         nil,
 									"spans": []dataMap{
 										{
-											"start": "/3",
-											"end":   "/4",
+											"start":  "/3",
+											"end":    "/4",
+											"symbol": "author/*",
 										},
 									},
 								},
@@ -395,8 +398,9 @@ func TestExplainGroupByWithGroupLimitOnParentAndChild(t *testing.T) {
 										"filter":         nil,
 										"spans": []dataMap{
 											{
-												"start": "/3",
-												"end":   "/4",
+												"start":  "/3",
+												"end":    "/4",
+												"symbol": "author/*",
 											},
 										},
 									},
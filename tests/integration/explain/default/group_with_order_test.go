@@ -96,8 +96,9 @@ func TestExplainGroupByWithOrderOnParentGroup(t *testing.T) {
 										"filter":         nil,
 										"spans": []dataMap{
 											{
-												"start": "/3",
-												"end":   "/4",
+												"start":  "/3",
+												"end":    "/4",
+												"symbol": "author/*",
 											},
 										},
 									},
@@ -191,8 +192,9 @@ func TestExplainGroupByWithOrderOnTheChildGroup(t *testing.T) {
 									"filter":         nil,
 									"spans": []dataMap{
 										{
-											"start": "/3",
-											"end":   "/4",
+											"start":  "/3",
+											"end":    "/4",
+											"symbol": "author/*",
 										},
 									},
 								},
@@ -292,8 +294,9 @@ func TestExplainGroupByWithOrderOnTheChildGroupAndOnParentGroup(t *testing.T) {
 										"filter":         nil,
 										"spans": []dataMap{
 											{
-												"start": "/3",
-												"end":   "/4",
+												"start":  "/3",
+												"end":    "/4",
+												"symbol": "author/*",
 											},
 										},
 									},
@@ -393,8 +396,9 @@ func TestExplainGroupByWithOrderOnTheNestedChildOfChildGroup(t *testing.T) {
 									"filter":         nil,
 									"spans": []dataMap{
 										{
-											"start": "/3",
-											"end":   "/4",
+											"start":  "/3",
+											"end":    "/4",
+											"symbol": "author/*",
 										},
 									},
 								},
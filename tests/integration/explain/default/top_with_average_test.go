@@ -62,8 +62,9 @@ func TestExplainTopLevelAverageQuery(t *testing.T) {
 										},
 										"spans": []dataMap{
 											{
-												"end":   "/4",
-												"start": "/3",
+												"end":    "/4",
+												"start":  "/3",
+												"symbol": "author/*",
 											},
 										},
 									},
@@ -100,7 +101,9 @@ func TestExplainTopLevelAverageQuery(t *testing.T) {
 							},
 						},
 						{
-							"averageNode": dataMap{},
+							"averageNode": dataMap{
+								"responseField": "_avg",
+							},
 						},
 					},
 				},
@@ -168,8 +171,9 @@ func TestExplainTopLevelAverageQueryWithFilter(t *testing.T) {
 										},
 										"spans": []dataMap{
 											{
-												"end":   "/4",
-												"start": "/3",
+												"end":    "/4",
+												"start":  "/3",
+												"symbol": "author/*",
 											},
 										},
 									},
@@ -208,7 +212,9 @@ func TestExplainTopLevelAverageQueryWithFilter(t *testing.T) {
 							},
 						},
 						{
-							"averageNode": dataMap{},
+							"averageNode": dataMap{
+								"responseField": "_avg",
+							},
 						},
 					},
 				},
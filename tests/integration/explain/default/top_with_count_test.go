@@ -54,8 +54,9 @@ func TestExplainTopLevelCountQuery(t *testing.T) {
 										"filter":         nil,
 										"spans": []dataMap{
 											{
-												"start": "/3",
-												"end":   "/4",
+												"start":  "/3",
+												"end":    "/4",
+												"symbol": "author/*",
 											},
 										},
 									},
@@ -64,6 +65,7 @@ func TestExplainTopLevelCountQuery(t *testing.T) {
 						},
 						{
 							"countNode": dataMap{
+								"responseField": "_count",
 								"sources": []dataMap{
 									{
 										"fieldName": "author",
@@ -136,8 +138,9 @@ func TestExplainTopLevelCountQueryWithFilter(t *testing.T) {
 										},
 										"spans": []dataMap{
 											{
-												"start": "/3",
-												"end":   "/4",
+												"start":  "/3",
+												"end":    "/4",
+												"symbol": "author/*",
 											},
 										},
 									},
@@ -146,6 +149,7 @@ func TestExplainTopLevelCountQueryWithFilter(t *testing.T) {
 						},
 						{
 							"countNode": dataMap{
+								"responseField": "_count",
 								"sources": []dataMap{
 									{
 										"fieldName": "author",
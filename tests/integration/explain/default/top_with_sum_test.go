@@ -58,8 +58,9 @@ func TestExplainTopLevelSumQuery(t *testing.T) {
 										"filter":         nil,
 										"spans": []dataMap{
 											{
-												"start": "/3",
-												"end":   "/4",
+												"start":  "/3",
+												"end":    "/4",
+												"symbol": "author/*",
 											},
 										},
 									},
@@ -68,6 +69,7 @@ func TestExplainTopLevelSumQuery(t *testing.T) {
 						},
 						{
 							"sumNode": dataMap{
+								"responseField": "_sum",
 								"sources": []dataMap{
 									{
 										"fieldName":      "author",
@@ -142,8 +144,9 @@ func TestExplainTopLevelSumQueryWithFilter(t *testing.T) {
 										},
 										"spans": []dataMap{
 											{
-												"start": "/3",
-												"end":   "/4",
+												"start":  "/3",
+												"end":    "/4",
+												"symbol": "author/*",
 											},
 										},
 									},
@@ -152,6 +155,7 @@ func TestExplainTopLevelSumQueryWithFilter(t *testing.T) {
 						},
 						{
 							"sumNode": dataMap{
+								"responseField": "_sum",
 								"sources": []dataMap{
 									{
 										"fieldName":      "author",
@@ -0,0 +1,87 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package test_explain_default
+
+import (
+	"testing"
+
+	explainUtils "github.com/sourcenetwork/defradb/tests/integration/explain"
+)
+
+func TestDefaultExplainRequestWithAdHocJoin(t *testing.T) {
+	test := explainUtils.ExplainRequestTestCase{
+
+		Description: "Explain (default) request with an ad-hoc join.",
+
+		Request: `query @explain {
+			article {
+				name
+				_join_book(localField: name, foreignField: name) {
+					pages
+				}
+			}
+		}`,
+
+		ExpectedPatterns: []dataMap{
+			{
+				"explain": dataMap{
+					"selectTopNode": dataMap{
+						"selectNode": dataMap{
+							"typeIndexJoin": dataMap{
+								"root": dataMap{
+									"scanNode": dataMap{},
+								},
+								"subType": dataMap{
+									"selectTopNode": dataMap{
+										"selectNode": dataMap{
+											"scanNode": dataMap{},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+
+		ExpectedTargets: []explainUtils.PlanNodeTargetCase{
+			{
+				TargetNodeName: "typeIndexJoin",
+				ExpectedAttributes: dataMap{
+					"joinType":      "typeJoinOn",
+					"rootName":      "name",
+					"subTypeName":   "_join_book",
+					"foreignField":  "name",
+					"responseField": "_join_book",
+				},
+			},
+			{
+				TargetNodeName:   "scanNode",
+				OccurancesToSkip: 1,
+				ExpectedAttributes: dataMap{
+					"collectionID":   "2",
+					"collectionName": "book",
+					"filter":         nil,
+					"spans": []dataMap{
+						{
+							"start":  "/2",
+							"end":    "/3",
+							"symbol": "book/*",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	runExplainTest(t, test)
+}
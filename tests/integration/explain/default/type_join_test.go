@@ -121,9 +121,10 @@ func TestExplainQueryWithAOneToOneJoin(t *testing.T) {
 						"selectNode": dataMap{
 							"filter": nil,
 							"typeIndexJoin": dataMap{
-								"direction": "primary",
-								"joinType":  "typeJoinOne",
-								"rootName":  "author",
+								"responseField": "OnlyEmail: contact",
+								"direction":     "primary",
+								"joinType":      "typeJoinOne",
+								"rootName":      "author",
 								"root": dataMap{
 									"scanNode": dataMap{
 										"filter":         nil,
@@ -131,8 +132,9 @@ func TestExplainQueryWithAOneToOneJoin(t *testing.T) {
 										"collectionName": "author",
 										"spans": []dataMap{
 											{
-												"start": "/3",
-												"end":   "/4",
+												"start":  "/3",
+												"end":    "/4",
+												"symbol": "author/*",
 											},
 										},
 									},
@@ -148,8 +150,9 @@ func TestExplainQueryWithAOneToOneJoin(t *testing.T) {
 												"collectionName": "authorContact",
 												"spans": []dataMap{
 													{
-														"start": "/4",
-														"end":   "/5",
+														"start":  "/4",
+														"end":    "/5",
+														"symbol": "authorContact/*",
 													},
 												},
 											},
@@ -278,9 +281,10 @@ func TestExplainQueryWithMultipleOneToOneJoins(t *testing.T) {
 							"parallelNode": []dataMap{
 								{
 									"typeIndexJoin": dataMap{
-										"joinType":  "typeJoinOne",
-										"direction": "primary",
-										"rootName":  "author",
+										"responseField": "OnlyEmail: contact",
+										"joinType":      "typeJoinOne",
+										"direction":     "primary",
+										"rootName":      "author",
 										"root": dataMap{
 											"scanNode": dataMap{
 												"filter":         nil,
@@ -288,8 +292,9 @@ func TestExplainQueryWithMultipleOneToOneJoins(t *testing.T) {
 												"collectionName": "author",
 												"spans": []dataMap{
 													{
-														"start": "/3",
-														"end":   "/4",
+														"start":  "/3",
+														"end":    "/4",
+														"symbol": "author/*",
 													},
 												},
 											},
@@ -305,8 +310,9 @@ func TestExplainQueryWithMultipleOneToOneJoins(t *testing.T) {
 														"collectionName": "authorContact",
 														"spans": []dataMap{
 															{
-																"start": "/4",
-																"end":   "/5",
+																"start":  "/4",
+																"end":    "/5",
+																"symbol": "authorContact/*",
 															},
 														},
 													},
@@ -317,9 +323,10 @@ func TestExplainQueryWithMultipleOneToOneJoins(t *testing.T) {
 								},
 								{
 									"typeIndexJoin": dataMap{
-										"joinType":  "typeJoinOne",
-										"direction": "primary",
-										"rootName":  "author",
+										"responseField": "contact",
+										"joinType":      "typeJoinOne",
+										"direction":     "primary",
+										"rootName":      "author",
 										"root": dataMap{
 											"scanNode": dataMap{
 												"filter":         nil,
@@ -327,8 +334,9 @@ func TestExplainQueryWithMultipleOneToOneJoins(t *testing.T) {
 												"collectionName": "author",
 												"spans": []dataMap{
 													{
-														"start": "/3",
-														"end":   "/4",
+														"start":  "/3",
+														"end":    "/4",
+														"symbol": "author/*",
 													},
 												},
 											},
@@ -344,8 +352,9 @@ func TestExplainQueryWithMultipleOneToOneJoins(t *testing.T) {
 														"collectionName": "authorContact",
 														"spans": []dataMap{
 															{
-																"start": "/4",
-																"end":   "/5",
+																"start":  "/4",
+																"end":    "/5",
+																"symbol": "authorContact/*",
 															},
 														},
 													},
@@ -475,9 +484,10 @@ func TestExplainQueryWithTwoLeveLDeepNestedJoins(t *testing.T) {
 						"selectNode": dataMap{
 							"filter": nil,
 							"typeIndexJoin": dataMap{
-								"joinType":  "typeJoinOne",
-								"direction": "primary",
-								"rootName":  "author",
+								"responseField": "contact",
+								"joinType":      "typeJoinOne",
+								"direction":     "primary",
+								"rootName":      "author",
 								"root": dataMap{
 									"scanNode": dataMap{
 										"filter":         nil,
@@ -485,8 +495,9 @@ func TestExplainQueryWithTwoLeveLDeepNestedJoins(t *testing.T) {
 										"collectionName": "author",
 										"spans": []dataMap{
 											{
-												"start": "/3",
-												"end":   "/4",
+												"start":  "/3",
+												"end":    "/4",
+												"symbol": "author/*",
 											},
 										},
 									},
@@ -497,9 +508,10 @@ func TestExplainQueryWithTwoLeveLDeepNestedJoins(t *testing.T) {
 										"selectNode": dataMap{
 											"filter": nil,
 											"typeIndexJoin": dataMap{
-												"joinType":  "typeJoinOne",
-												"direction": "primary",
-												"rootName":  "contact",
+												"responseField": "address",
+												"joinType":      "typeJoinOne",
+												"direction":     "primary",
+												"rootName":      "contact",
 												"root": dataMap{
 													"scanNode": dataMap{
 														"filter":         nil,
@@ -507,8 +519,9 @@ func TestExplainQueryWithTwoLeveLDeepNestedJoins(t *testing.T) {
 														"collectionName": "authorContact",
 														"spans": []dataMap{
 															{
-																"start": "/4",
-																"end":   "/5",
+																"start":  "/4",
+																"end":    "/5",
+																"symbol": "authorContact/*",
 															},
 														},
 													},
@@ -524,8 +537,9 @@ func TestExplainQueryWithTwoLeveLDeepNestedJoins(t *testing.T) {
 																"collectionName": "contactAddress",
 																"spans": []dataMap{
 																	{
-																		"start": "/5",
-																		"end":   "/6",
+																		"start":  "/5",
+																		"end":    "/6",
+																		"symbol": "contactAddress/*",
 																	},
 																},
 															},
@@ -79,8 +79,9 @@ func TestExplainSimpleMutationUpdateWithBooleanFilter(t *testing.T) {
 									},
 									"spans": []dataMap{
 										{
-											"end":   "/4",
-											"start": "/3",
+											"end":    "/4",
+											"start":  "/3",
+											"symbol": "author/*",
 										},
 									},
 								},
@@ -152,12 +153,14 @@ func TestExplainSimpleMutationUpdateWithIdInFilter(t *testing.T) {
 									"filter":         nil,
 									"spans": []dataMap{
 										{
-											"end":   "/3/bae-bfbfc89c-0d63-5ea4-81a3-3ebd295be67g",
-											"start": "/3/bae-bfbfc89c-0d63-5ea4-81a3-3ebd295be67f",
+											"end":    "/3/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9e",
+											"start":  "/3/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9d",
+											"symbol": "author/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9d",
 										},
 										{
-											"end":   "/3/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9e",
-											"start": "/3/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9d",
+											"end":    "/3/bae-bfbfc89c-0d63-5ea4-81a3-3ebd295be67g",
+											"start":  "/3/bae-bfbfc89c-0d63-5ea4-81a3-3ebd295be67f",
+											"symbol": "author/bae-bfbfc89c-0d63-5ea4-81a3-3ebd295be67f",
 										},
 									},
 								},
@@ -225,8 +228,9 @@ func TestExplainSimpleMutationUpdateWithIdEqualsFilter(t *testing.T) {
 									"filter":         nil,
 									"spans": []dataMap{
 										{
-											"end":   "/3/bae-bfbfc89c-0d63-5ea4-81a3-3ebd295be67g",
-											"start": "/3/bae-bfbfc89c-0d63-5ea4-81a3-3ebd295be67f",
+											"end":    "/3/bae-bfbfc89c-0d63-5ea4-81a3-3ebd295be67g",
+											"start":  "/3/bae-bfbfc89c-0d63-5ea4-81a3-3ebd295be67f",
+											"symbol": "author/bae-bfbfc89c-0d63-5ea4-81a3-3ebd295be67f",
 										},
 									},
 								},
@@ -311,12 +315,14 @@ func TestExplainSimpleMutationUpdateWithIdAndFilter(t *testing.T) {
 									},
 									"spans": []dataMap{
 										{
-											"end":   "/3/bae-bfbfc89c-0d63-5ea4-81a3-3ebd295be67g",
-											"start": "/3/bae-bfbfc89c-0d63-5ea4-81a3-3ebd295be67f",
+											"end":    "/3/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9e",
+											"start":  "/3/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9d",
+											"symbol": "author/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9d",
 										},
 										{
-											"end":   "/3/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9e",
-											"start": "/3/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9d",
+											"end":    "/3/bae-bfbfc89c-0d63-5ea4-81a3-3ebd295be67g",
+											"start":  "/3/bae-bfbfc89c-0d63-5ea4-81a3-3ebd295be67f",
+											"symbol": "author/bae-bfbfc89c-0d63-5ea4-81a3-3ebd295be67f",
 										},
 									},
 								},
@@ -46,6 +46,7 @@ func TestExplainSimpleAverageQueryOnArrayField(t *testing.T) {
 				"explain": dataMap{
 					"selectTopNode": dataMap{
 						"averageNode": dataMap{
+							"responseField": "_avg",
 							"countNode": dataMap{
 								"sources": []dataMap{
 									{
@@ -69,8 +70,9 @@ func TestExplainSimpleAverageQueryOnArrayField(t *testing.T) {
 											"filter":         nil,
 											"spans": []dataMap{
 												{
-													"start": "/2",
-													"end":   "/3",
+													"start":  "/2",
+													"end":    "/3",
+													"symbol": "book/*",
 												},
 											},
 										},
@@ -147,6 +149,7 @@ func TestExplainAverageQueryOnJoinedField(t *testing.T) {
 				"explain": dataMap{
 					"selectTopNode": dataMap{
 						"averageNode": dataMap{
+							"responseField": "_avg",
 							"countNode": dataMap{
 								"sources": []dataMap{
 									{
@@ -182,8 +185,9 @@ func TestExplainAverageQueryOnJoinedField(t *testing.T) {
 													"filter":         nil,
 													"spans": []dataMap{
 														{
-															"start": "/3",
-															"end":   "/4",
+															"start":  "/3",
+															"end":    "/4",
+															"symbol": "author/*",
 														},
 													},
 												},
@@ -203,8 +207,9 @@ func TestExplainAverageQueryOnJoinedField(t *testing.T) {
 															},
 															"spans": []dataMap{
 																{
-																	"start": "/2",
-																	"end":   "/3",
+																	"start":  "/2",
+																	"end":    "/3",
+																	"symbol": "book/*",
 																},
 															},
 														},
@@ -311,6 +316,7 @@ func TestExplainAverageQueryOnMultipleJoinedFieldWithFilter(t *testing.T) {
 				"explain": dataMap{
 					"selectTopNode": dataMap{
 						"averageNode": dataMap{
+							"responseField": "_avg",
 							"countNode": dataMap{
 								"sources": []dataMap{
 									{
@@ -367,8 +373,9 @@ func TestExplainAverageQueryOnMultipleJoinedFieldWithFilter(t *testing.T) {
 															"filter":         nil,
 															"spans": []dataMap{
 																{
-																	"start": "/3",
-																	"end":   "/4",
+																	"start":  "/3",
+																	"end":    "/4",
+																	"symbol": "author/*",
 																},
 															},
 														},
@@ -388,8 +395,9 @@ func TestExplainAverageQueryOnMultipleJoinedFieldWithFilter(t *testing.T) {
 																	},
 																	"spans": []dataMap{
 																		{
-																			"start": "/2",
-																			"end":   "/3",
+																			"start":  "/2",
+																			"end":    "/3",
+																			"symbol": "book/*",
 																		},
 																	},
 																},
@@ -409,8 +417,9 @@ func TestExplainAverageQueryOnMultipleJoinedFieldWithFilter(t *testing.T) {
 															"filter":         nil,
 															"spans": []dataMap{
 																{
-																	"start": "/3",
-																	"end":   "/4",
+																	"start":  "/3",
+																	"end":    "/4",
+																	"symbol": "author/*",
 																},
 															},
 														},
@@ -431,8 +440,9 @@ func TestExplainAverageQueryOnMultipleJoinedFieldWithFilter(t *testing.T) {
 																	},
 																	"spans": []dataMap{
 																		{
-																			"start": "/1",
-																			"end":   "/2",
+																			"start":  "/1",
+																			"end":    "/2",
+																			"symbol": "article/*",
 																		},
 																	},
 																},
@@ -87,6 +87,7 @@ func TestExplainQueryOneToManyWithACount(t *testing.T) {
 				"explain": dataMap{
 					"selectTopNode": dataMap{
 						"countNode": dataMap{
+							"responseField": "numberOfBooks: _count",
 							"sources": []dataMap{
 								{
 									"filter":    nil,
@@ -105,8 +106,9 @@ func TestExplainQueryOneToManyWithACount(t *testing.T) {
 											"collectionName": "author",
 											"spans": []dataMap{
 												{
-													"start": "/3",
-													"end":   "/4",
+													"start":  "/3",
+													"end":    "/4",
+													"symbol": "author/*",
 												},
 											},
 										},
@@ -122,8 +124,9 @@ func TestExplainQueryOneToManyWithACount(t *testing.T) {
 													"collectionName": "book",
 													"spans": []dataMap{
 														{
-															"start": "/2",
-															"end":   "/3",
+															"start":  "/2",
+															"end":    "/3",
+															"symbol": "book/*",
 														},
 													},
 												},
@@ -57,8 +57,9 @@ func TestDefaultExplainRequestWithDocKeyFilter(t *testing.T) {
 					"filter":         nil,
 					"spans": []dataMap{
 						{
-							"start": "/3/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9d",
-							"end":   "/3/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9e",
+							"start":  "/3/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9d",
+							"end":    "/3/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9e",
+							"symbol": "author/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9d",
 						},
 					},
 				},
@@ -104,8 +105,9 @@ func TestDefaultExplainRequestWithDocKeysFilterUsingOneKey(t *testing.T) {
 					"filter":         nil,
 					"spans": []dataMap{
 						{
-							"start": "/3/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9d",
-							"end":   "/3/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9e",
+							"start":  "/3/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9d",
+							"end":    "/3/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9e",
+							"symbol": "author/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9d",
 						},
 					},
 				},
@@ -162,12 +164,9 @@ func TestDefaultExplainRequestWithDocKeysFilterUsingMultipleButDuplicateKeys(t *
 					"filter":         nil,
 					"spans": []dataMap{
 						{
-							"start": "/3/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9d",
-							"end":   "/3/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9e",
-						},
-						{
-							"start": "/3/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9d",
-							"end":   "/3/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9e",
+							"start":  "/3/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9d",
+							"end":    "/3/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9e",
+							"symbol": "author/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9d",
 						},
 					},
 				},
@@ -224,12 +223,14 @@ func TestDefaultExplainRequestWithDocKeysFilterUsingMultipleUniqueKeys(t *testin
 					"filter":         nil,
 					"spans": []dataMap{
 						{
-							"start": "/3/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9d",
-							"end":   "/3/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9e",
+							"start":  "/3/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9d",
+							"end":    "/3/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9e",
+							"symbol": "author/bae-079d0bd8-4b1b-5f5f-bd95-4d915c277f9d",
 						},
 						{
-							"start": "/3/bae-bfbfc89c-0d63-5ea4-81a3-3ebd295be67f",
-							"end":   "/3/bae-bfbfc89c-0d63-5ea4-81a3-3ebd295be67g",
+							"start":  "/3/bae-bfbfc89c-0d63-5ea4-81a3-3ebd295be67f",
+							"end":    "/3/bae-bfbfc89c-0d63-5ea4-81a3-3ebd295be67g",
+							"symbol": "author/bae-bfbfc89c-0d63-5ea4-81a3-3ebd295be67f",
 						},
 					},
 				},
@@ -285,8 +286,9 @@ func TestDefaultExplainRequestWithMatchingKeyFilter(t *testing.T) {
 					},
 					"spans": []dataMap{
 						{
-							"start": "/3",
-							"end":   "/4",
+							"start":  "/3",
+							"end":    "/4",
+							"symbol": "author/*",
 						},
 					},
 				},
@@ -0,0 +1,217 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package test_explain_default
+
+import (
+	"testing"
+
+	testUtils "github.com/sourcenetwork/defradb/tests/integration"
+)
+
+func TestExplainQueryWithOrFilterOnKeyAndRelation(t *testing.T) {
+	test := testUtils.RequestTestCase{
+
+		Description: "Explain a query with an or filter on the key and a relation.",
+
+		Request: `query @explain {
+			author(filter: {_or: [
+				{_key: {_eq: "bae-41598f0c-19bc-5da6-813b-e80f14a10df3"}},
+				{books: {name: {_eq: "Theif Lord"}}}
+			]}) {
+				name
+			}
+		}`,
+
+		Results: []dataMap{
+			{
+				"explain": dataMap{
+					"selectTopNode": dataMap{
+						"selectNode": dataMap{
+							"filter": dataMap{
+								"_or": []any{
+									dataMap{
+										"_key": dataMap{
+											"_eq": "bae-41598f0c-19bc-5da6-813b-e80f14a10df3",
+										},
+									},
+									dataMap{
+										"books": dataMap{
+											"name": dataMap{
+												"_eq": "Theif Lord",
+											},
+										},
+									},
+								},
+							},
+							"unionNode": dataMap{
+								"branches": []dataMap{
+									{
+										"ids": []string{"bae-41598f0c-19bc-5da6-813b-e80f14a10df3"},
+									},
+									{
+										"fieldName":  "books",
+										"foreignKey": "author_id",
+										"plan": dataMap{
+											"selectTopNode": dataMap{
+												"selectNode": dataMap{
+													"filter": nil,
+													"scanNode": dataMap{
+														"collectionID":   "2",
+														"collectionName": "book",
+														"filter": dataMap{
+															"name": dataMap{
+																"_eq": "Theif Lord",
+															},
+														},
+														"spans": []dataMap{
+															{
+																"start":  "/2",
+																"end":    "/3",
+																"symbol": "book/*",
+															},
+														},
+													},
+												},
+											},
+										},
+									},
+								},
+								"typeIndexJoin": dataMap{
+									"joinType": "typeJoinMany",
+									"rootName": "author",
+									"root": dataMap{
+										"scanNode": dataMap{
+											"filter":         nil,
+											"collectionID":   "3",
+											"collectionName": "author",
+											"spans": []dataMap{
+												{
+													"start":  "/3/bae-41598f0c-19bc-5da6-813b-e80f14a10df3",
+													"end":    "/3/bae-41598f0c-19bc-5da6-813b-e80f14a10df4",
+													"symbol": "author/bae-41598f0c-19bc-5da6-813b-e80f14a10df3",
+												},
+											},
+										},
+									},
+									"subTypeName": "books",
+									"subType": dataMap{
+										"selectTopNode": dataMap{
+											"selectNode": dataMap{
+												"filter": nil,
+												"scanNode": dataMap{
+													"filter":         nil,
+													"collectionID":   "2",
+													"collectionName": "book",
+													"spans": []dataMap{
+														{
+															"start":  "/2",
+															"end":    "/3",
+															"symbol": "book/*",
+														},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	executeTestCase(t, test)
+}
+
+func TestExplainQueryWithOrFilterOnScalarAndRelation(t *testing.T) {
+	test := testUtils.RequestTestCase{
+
+		Description: "Explain a query with an or filter on a scalar field and a relation.",
+
+		Request: `query @explain {
+			author(filter: {_or: [
+				{age: {_gt: 60}},
+				{books: {name: {_eq: "Theif Lord"}}}
+			]}) {
+				name
+			}
+		}`,
+
+		Results: []dataMap{
+			{
+				"explain": dataMap{
+					"selectTopNode": dataMap{
+						"selectNode": dataMap{
+							"filter": dataMap{
+								"_or": []any{
+									dataMap{
+										"age": dataMap{
+											"_gt": 60,
+										},
+									},
+									dataMap{
+										"books": dataMap{
+											"name": dataMap{
+												"_eq": "Theif Lord",
+											},
+										},
+									},
+								},
+							},
+							"typeIndexJoin": dataMap{
+								"joinType": "typeJoinMany",
+								"rootName": "author",
+								"root": dataMap{
+									"scanNode": dataMap{
+										"filter":         nil,
+										"collectionID":   "3",
+										"collectionName": "author",
+										"spans": []dataMap{
+											{
+												"start":  "/3",
+												"end":    "/4",
+												"symbol": "author/*",
+											},
+										},
+									},
+								},
+								"subTypeName": "books",
+								"subType": dataMap{
+									"selectTopNode": dataMap{
+										"selectNode": dataMap{
+											"filter": nil,
+											"scanNode": dataMap{
+												"filter":         nil,
+												"collectionID":   "2",
+												"collectionName": "book",
+												"spans": []dataMap{
+													{
+														"start":  "/2",
+														"end":    "/3",
+														"symbol": "book/*",
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	executeTestCase(t, test)
+}
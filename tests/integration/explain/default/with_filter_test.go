@@ -61,8 +61,9 @@ func TestDefaultExplainRequestWithStringEqualFilter(t *testing.T) {
 					},
 					"spans": []dataMap{
 						{
-							"start": "/3",
-							"end":   "/4",
+							"start":  "/3",
+							"end":    "/4",
+							"symbol": "author/*",
 						},
 					},
 				},
@@ -118,8 +119,9 @@ func TestDefaultExplainRequestWithIntegerEqualFilter(t *testing.T) {
 					},
 					"spans": []dataMap{
 						{
-							"start": "/3",
-							"end":   "/4",
+							"start":  "/3",
+							"end":    "/4",
+							"symbol": "author/*",
 						},
 					},
 				},
@@ -175,8 +177,9 @@ func TestDefaultExplainRequestWithGreaterThanFilter(t *testing.T) {
 					},
 					"spans": []dataMap{
 						{
-							"start": "/3",
-							"end":   "/4",
+							"start":  "/3",
+							"end":    "/4",
+							"symbol": "author/*",
 						},
 					},
 				},
@@ -245,8 +248,9 @@ func TestDefaultExplainRequestWithLogicalCompoundAndFilter(t *testing.T) {
 					},
 					"spans": []dataMap{
 						{
-							"start": "/3",
-							"end":   "/4",
+							"start":  "/3",
+							"end":    "/4",
+							"symbol": "author/*",
 						},
 					},
 				},
@@ -315,8 +319,9 @@ func TestDefaultExplainRequestWithLogicalCompoundOrFilter(t *testing.T) {
 					},
 					"spans": []dataMap{
 						{
-							"start": "/3",
-							"end":   "/4",
+							"start":  "/3",
+							"end":    "/4",
+							"symbol": "author/*",
 						},
 					},
 				},
@@ -380,8 +385,9 @@ func TestDefaultExplainRequestWithMatchInsideList(t *testing.T) {
 					},
 					"spans": []dataMap{
 						{
-							"start": "/3",
-							"end":   "/4",
+							"start":  "/3",
+							"end":    "/4",
+							"symbol": "author/*",
 						},
 					},
 				},
@@ -74,8 +74,9 @@ func TestExplainQueryWithOnlyLimitSpecified(t *testing.T) {
 									"filter":         nil,
 									"spans": []dataMap{
 										{
-											"start": "/3",
-											"end":   "/4",
+											"start":  "/3",
+											"end":    "/4",
+											"symbol": "author/*",
 										},
 									},
 								},
@@ -148,8 +149,9 @@ func TestExplainQueryWithOnlyOffsetSpecified(t *testing.T) {
 									"filter":         nil,
 									"spans": []dataMap{
 										{
-											"start": "/3",
-											"end":   "/4",
+											"start":  "/3",
+											"end":    "/4",
+											"symbol": "author/*",
 										},
 									},
 								},
@@ -222,8 +224,9 @@ func TestExplainQueryWithBothLimitAndOffset(t *testing.T) {
 									"filter":         nil,
 									"spans": []dataMap{
 										{
-											"start": "/3",
-											"end":   "/4",
+											"start":  "/3",
+											"end":    "/4",
+											"symbol": "author/*",
 										},
 									},
 								},
@@ -349,8 +352,9 @@ func TestExplainQueryWithOnlyLimitOnChild(t *testing.T) {
 						"selectNode": dataMap{
 							"filter": nil,
 							"typeIndexJoin": dataMap{
-								"joinType": "typeJoinMany",
-								"rootName": "author",
+								"responseField": "articles",
+								"joinType":      "typeJoinMany",
+								"rootName":      "author",
 								"root": dataMap{
 									"scanNode": dataMap{
 										"collectionID":   "3",
@@ -358,8 +362,9 @@ func TestExplainQueryWithOnlyLimitOnChild(t *testing.T) {
 										"filter":         nil,
 										"spans": []dataMap{
 											{
-												"start": "/3",
-												"end":   "/4",
+												"start":  "/3",
+												"end":    "/4",
+												"symbol": "author/*",
 											},
 										},
 									},
@@ -378,8 +383,9 @@ func TestExplainQueryWithOnlyLimitOnChild(t *testing.T) {
 													"filter":         nil,
 													"spans": []dataMap{
 														{
-															"start": "/1",
-															"end":   "/2",
+															"start":  "/1",
+															"end":    "/2",
+															"symbol": "article/*",
 														},
 													},
 												},
@@ -509,8 +515,9 @@ func TestExplainQueryWithOnlyOffsetOnChild(t *testing.T) {
 						"selectNode": dataMap{
 							"filter": nil,
 							"typeIndexJoin": dataMap{
-								"joinType": "typeJoinMany",
-								"rootName": "author",
+								"responseField": "articles",
+								"joinType":      "typeJoinMany",
+								"rootName":      "author",
 								"root": dataMap{
 									"scanNode": dataMap{
 										"collectionID":   "3",
@@ -518,8 +525,9 @@ func TestExplainQueryWithOnlyOffsetOnChild(t *testing.T) {
 										"filter":         nil,
 										"spans": []dataMap{
 											{
-												"start": "/3",
-												"end":   "/4",
+												"start":  "/3",
+												"end":    "/4",
+												"symbol": "author/*",
 											},
 										},
 									},
@@ -538,8 +546,9 @@ func TestExplainQueryWithOnlyOffsetOnChild(t *testing.T) {
 													"filter":         nil,
 													"spans": []dataMap{
 														{
-															"start": "/1",
-															"end":   "/2",
+															"start":  "/1",
+															"end":    "/2",
+															"symbol": "article/*",
 														},
 													},
 												},
@@ -669,8 +678,9 @@ func TestExplainQueryWithBothLimitAndOffsetOnChild(t *testing.T) {
 						"selectNode": dataMap{
 							"filter": nil,
 							"typeIndexJoin": dataMap{
-								"joinType": "typeJoinMany",
-								"rootName": "author",
+								"responseField": "articles",
+								"joinType":      "typeJoinMany",
+								"rootName":      "author",
 								"root": dataMap{
 									"scanNode": dataMap{
 										"collectionID":   "3",
@@ -678,8 +688,9 @@ func TestExplainQueryWithBothLimitAndOffsetOnChild(t *testing.T) {
 										"filter":         nil,
 										"spans": []dataMap{
 											{
-												"start": "/3",
-												"end":   "/4",
+												"start":  "/3",
+												"end":    "/4",
+												"symbol": "author/*",
 											},
 										},
 									},
@@ -698,8 +709,9 @@ func TestExplainQueryWithBothLimitAndOffsetOnChild(t *testing.T) {
 													"filter":         nil,
 													"spans": []dataMap{
 														{
-															"start": "/1",
-															"end":   "/2",
+															"start":  "/1",
+															"end":    "/2",
+															"symbol": "article/*",
 														},
 													},
 												},
@@ -832,8 +844,9 @@ func TestExplainQueryWithLimitOnChildAndBothLimitAndOffsetOnParent(t *testing.T)
 							"selectNode": dataMap{
 								"filter": nil,
 								"typeIndexJoin": dataMap{
-									"joinType": "typeJoinMany",
-									"rootName": "author",
+									"responseField": "articles",
+									"joinType":      "typeJoinMany",
+									"rootName":      "author",
 									"root": dataMap{
 										"scanNode": dataMap{
 											"collectionID":   "3",
@@ -841,8 +854,9 @@ func TestExplainQueryWithLimitOnChildAndBothLimitAndOffsetOnParent(t *testing.T)
 											"filter":         nil,
 											"spans": []dataMap{
 												{
-													"start": "/3",
-													"end":   "/4",
+													"start":  "/3",
+													"end":    "/4",
+													"symbol": "author/*",
 												},
 											},
 										},
@@ -861,8 +875,9 @@ func TestExplainQueryWithLimitOnChildAndBothLimitAndOffsetOnParent(t *testing.T)
 														"filter":         nil,
 														"spans": []dataMap{
 															{
-																"start": "/1",
-																"end":   "/2",
+																"start":  "/1",
+																"end":    "/2",
+																"symbol": "article/*",
 															},
 														},
 													},
@@ -991,6 +1006,7 @@ func TestExplainQueryWithMultipleConflictingInnerLimits(t *testing.T) {
 				"explain": dataMap{
 					"selectTopNode": dataMap{
 						"countNode": dataMap{
+							"responseField": "numberOfArts: _count",
 							"sources": []dataMap{
 								{
 									"fieldName": "articles",
@@ -1002,8 +1018,9 @@ func TestExplainQueryWithMultipleConflictingInnerLimits(t *testing.T) {
 								"parallelNode": []dataMap{
 									{
 										"typeIndexJoin": dataMap{
-											"joinType": "typeJoinMany",
-											"rootName": "author",
+											"responseField": "articles",
+											"joinType":      "typeJoinMany",
+											"rootName":      "author",
 											"root": dataMap{
 												"scanNode": dataMap{
 													"collectionID":   "3",
@@ -1011,8 +1028,9 @@ func TestExplainQueryWithMultipleConflictingInnerLimits(t *testing.T) {
 													"filter":         nil,
 													"spans": []dataMap{
 														{
-															"end":   "/4",
-															"start": "/3",
+															"end":    "/4",
+															"start":  "/3",
+															"symbol": "author/*",
 														},
 													},
 												},
@@ -1031,8 +1049,9 @@ func TestExplainQueryWithMultipleConflictingInnerLimits(t *testing.T) {
 																"filter":         nil,
 																"spans": []dataMap{
 																	{
-																		"end":   "/2",
-																		"start": "/1",
+																		"end":    "/2",
+																		"start":  "/1",
+																		"symbol": "article/*",
 																	},
 																},
 															},
@@ -1053,8 +1072,9 @@ func TestExplainQueryWithMultipleConflictingInnerLimits(t *testing.T) {
 													"filter":         nil,
 													"spans": []dataMap{
 														{
-															"end":   "/4",
-															"start": "/3",
+															"end":    "/4",
+															"start":  "/3",
+															"symbol": "author/*",
 														},
 													},
 												},
@@ -1070,8 +1090,9 @@ func TestExplainQueryWithMultipleConflictingInnerLimits(t *testing.T) {
 															"filter":         nil,
 															"spans": []dataMap{
 																{
-																	"end":   "/2",
-																	"start": "/1",
+																	"end":    "/2",
+																	"start":  "/1",
+																	"symbol": "article/*",
 																},
 															},
 														},
@@ -1204,6 +1225,7 @@ func TestExplainQueryWithMultipleConflictingInnerLimitsAndOuterLimit(t *testing.
 							"limit":  uint64(3),
 							"offset": uint64(1),
 							"countNode": dataMap{
+								"responseField": "numberOfArts: _count",
 								"sources": []dataMap{
 									{
 										"fieldName": "articles",
@@ -1215,8 +1237,9 @@ func TestExplainQueryWithMultipleConflictingInnerLimitsAndOuterLimit(t *testing.
 									"parallelNode": []dataMap{
 										{
 											"typeIndexJoin": dataMap{
-												"joinType": "typeJoinMany",
-												"rootName": "author",
+												"responseField": "articles",
+												"joinType":      "typeJoinMany",
+												"rootName":      "author",
 												"root": dataMap{
 													"scanNode": dataMap{
 														"collectionID":   "3",
@@ -1224,8 +1247,9 @@ func TestExplainQueryWithMultipleConflictingInnerLimitsAndOuterLimit(t *testing.
 														"filter":         nil,
 														"spans": []dataMap{
 															{
-																"end":   "/4",
-																"start": "/3",
+																"end":    "/4",
+																"start":  "/3",
+																"symbol": "author/*",
 															},
 														},
 													},
@@ -1244,8 +1268,9 @@ func TestExplainQueryWithMultipleConflictingInnerLimitsAndOuterLimit(t *testing.
 																	"filter":         nil,
 																	"spans": []dataMap{
 																		{
-																			"end":   "/2",
-																			"start": "/1",
+																			"end":    "/2",
+																			"start":  "/1",
+																			"symbol": "article/*",
 																		},
 																	},
 																},
@@ -1266,8 +1291,9 @@ func TestExplainQueryWithMultipleConflictingInnerLimitsAndOuterLimit(t *testing.
 														"filter":         nil,
 														"spans": []dataMap{
 															{
-																"end":   "/4",
-																"start": "/3",
+																"end":    "/4",
+																"start":  "/3",
+																"symbol": "author/*",
 															},
 														},
 													},
@@ -1283,8 +1309,9 @@ func TestExplainQueryWithMultipleConflictingInnerLimitsAndOuterLimit(t *testing.
 																"filter":         nil,
 																"spans": []dataMap{
 																	{
-																		"end":   "/2",
-																		"start": "/1",
+																		"end":    "/2",
+																		"start":  "/1",
+																		"symbol": "article/*",
 																	},
 																},
 															},
@@ -58,8 +58,9 @@ func TestExplainAscendingOrderQueryOnParent(t *testing.T) {
 									"collectionName": "author",
 									"spans": []dataMap{
 										{
-											"start": "/3",
-											"end":   "/4",
+											"start":  "/3",
+											"end":    "/4",
+											"symbol": "author/*",
 										},
 									},
 								},
@@ -124,8 +125,9 @@ func TestExplainQueryWithMultiOrderFieldsOnParent(t *testing.T) {
 									"collectionName": "author",
 									"spans": []dataMap{
 										{
-											"start": "/3",
-											"end":   "/4",
+											"start":  "/3",
+											"end":    "/4",
+											"symbol": "author/*",
 										},
 									},
 								},
@@ -208,8 +210,9 @@ func TestExplainQueryWithOrderFieldOnChild(t *testing.T) {
 						"selectNode": dataMap{
 							"filter": nil,
 							"typeIndexJoin": dataMap{
-								"joinType": "typeJoinMany",
-								"rootName": "author",
+								"responseField": "articles",
+								"joinType":      "typeJoinMany",
+								"rootName":      "author",
 								"root": dataMap{
 									"scanNode": dataMap{
 										"collectionID":   "3",
@@ -217,8 +220,9 @@ func TestExplainQueryWithOrderFieldOnChild(t *testing.T) {
 										"filter":         nil,
 										"spans": []dataMap{
 											{
-												"start": "/3",
-												"end":   "/4",
+												"start":  "/3",
+												"end":    "/4",
+												"symbol": "author/*",
 											},
 										},
 									},
@@ -243,8 +247,9 @@ func TestExplainQueryWithOrderFieldOnChild(t *testing.T) {
 													"filter":         nil,
 													"spans": []dataMap{
 														{
-															"start": "/1",
-															"end":   "/2",
+															"start":  "/1",
+															"end":    "/2",
+															"symbol": "article/*",
 														},
 													},
 												},
@@ -326,8 +331,9 @@ func TestExplainQueryWithOrderOnBothTheParentAndChild(t *testing.T) {
 							"selectNode": dataMap{
 								"filter": nil,
 								"typeIndexJoin": dataMap{
-									"joinType": "typeJoinMany",
-									"rootName": "author",
+									"responseField": "articles",
+									"joinType":      "typeJoinMany",
+									"rootName":      "author",
 									"root": dataMap{
 										"scanNode": dataMap{
 											"collectionID":   "3",
@@ -335,8 +341,9 @@ func TestExplainQueryWithOrderOnBothTheParentAndChild(t *testing.T) {
 											"filter":         nil,
 											"spans": []dataMap{
 												{
-													"start": "/3",
-													"end":   "/4",
+													"start":  "/3",
+													"end":    "/4",
+													"symbol": "author/*",
 												},
 											},
 										},
@@ -361,8 +368,9 @@ func TestExplainQueryWithOrderOnBothTheParentAndChild(t *testing.T) {
 														"filter":         nil,
 														"spans": []dataMap{
 															{
-																"start": "/1",
-																"end":   "/2",
+																"start":  "/1",
+																"end":    "/2",
+																"symbol": "article/*",
 															},
 														},
 													},
@@ -449,8 +457,9 @@ func TestExplainQueryWhereParentIsOrderedByChild(t *testing.T) {
 							"selectNode": dataMap{
 								"filter": nil,
 								"typeIndexJoin": dataMap{
-									"joinType": "typeJoinMany",
-									"rootName": "author",
+									"responseField": "articles",
+									"joinType":      "typeJoinMany",
+									"rootName":      "author",
 									"root": dataMap{
 										"scanNode": dataMap{
 											"collectionID":   "3",
@@ -458,8 +467,9 @@ func TestExplainQueryWhereParentIsOrderedByChild(t *testing.T) {
 											"filter":         nil,
 											"spans": []dataMap{
 												{
-													"start": "/3",
-													"end":   "/4",
+													"start":  "/3",
+													"end":    "/4",
+													"symbol": "author/*",
 												},
 											},
 										},
@@ -475,8 +485,9 @@ func TestExplainQueryWhereParentIsOrderedByChild(t *testing.T) {
 													"filter":         nil,
 													"spans": []dataMap{
 														{
-															"start": "/1",
-															"end":   "/2",
+															"start":  "/1",
+															"end":    "/2",
+															"symbol": "article/*",
 														},
 													},
 												},
@@ -0,0 +1,123 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package test_explain_default
+
+import (
+	"testing"
+
+	testUtils "github.com/sourcenetwork/defradb/tests/integration"
+)
+
+func TestExplainQueryRunningSumOfRelatedOneToManyFieldWithWindow(t *testing.T) {
+	test := testUtils.RequestTestCase{
+		Description: "Explain a running sum query of a One-to-Many related sub-type with a window.",
+
+		Request: `query @explain {
+			author {
+				name
+				_runningSum(books: {field: pages, window: 2})
+			}
+		}`,
+
+		Docs: map[int][]string{
+			// books
+			1: {
+				`{
+					"name": "Painted House",
+					"author_id": "bae-25fafcc7-f251-58c1-9495-ead73e676fb8",
+					"pages": 22
+				}`,
+			},
+
+			// authors
+			2: {
+				// _key: "bae-25fafcc7-f251-58c1-9495-ead73e676fb8"
+				`{
+					"name": "John Grisham",
+					"age": 65,
+					"verified": true,
+					"contact_id": "bae-1fe427b8-ab8d-56c3-9df2-826a6ce86fed"
+				}`,
+			},
+		},
+
+		Results: []dataMap{
+			{
+				"explain": dataMap{
+					"selectTopNode": dataMap{
+						"runningAggregateNode": dataMap{
+							"responseField": "_runningSum",
+							"sources": []dataMap{
+								{
+									"fieldName":      "books",
+									"childFieldName": "pages",
+									"window":         uint64(2),
+									"filter": dataMap{
+										"pages": dataMap{
+											"_ne": nil,
+										},
+									},
+								},
+							},
+							"selectNode": dataMap{
+								"filter": nil,
+								"typeIndexJoin": dataMap{
+									"joinType": "typeJoinMany",
+									"rootName": "author",
+									"root": dataMap{
+										"scanNode": dataMap{
+											"collectionID":   "3",
+											"collectionName": "author",
+											"filter":         nil,
+											"spans": []dataMap{
+												{
+													"start":  "/3",
+													"end":    "/4",
+													"symbol": "author/*",
+												},
+											},
+										},
+									},
+									"subTypeName": "books",
+									"subType": dataMap{
+										"selectTopNode": dataMap{
+											"selectNode": dataMap{
+												"filter": nil,
+												"scanNode": dataMap{
+													"collectionID":   "2",
+													"collectionName": "book",
+													"filter": dataMap{
+														"pages": dataMap{
+															"_ne": nil,
+														},
+													},
+													"spans": []dataMap{
+														{
+															"start":  "/2",
+															"end":    "/3",
+															"symbol": "book/*",
+														},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	executeTestCase(t, test)
+}
@@ -74,6 +74,7 @@ func TestExplainQuerySumOfRelatedOneToManyField(t *testing.T) {
 				"explain": dataMap{
 					"selectTopNode": dataMap{
 						"sumNode": dataMap{
+							"responseField": "TotalPages: _sum",
 							"sources": []dataMap{
 								{
 									"fieldName":      "books",
@@ -93,8 +94,9 @@ func TestExplainQuerySumOfRelatedOneToManyField(t *testing.T) {
 											"filter":         nil,
 											"spans": []dataMap{
 												{
-													"start": "/3",
-													"end":   "/4",
+													"start":  "/3",
+													"end":    "/4",
+													"symbol": "author/*",
 												},
 											},
 										},
@@ -110,8 +112,9 @@ func TestExplainQuerySumOfRelatedOneToManyField(t *testing.T) {
 													"filter":         nil,
 													"spans": []dataMap{
 														{
-															"start": "/2",
-															"end":   "/3",
+															"start":  "/2",
+															"end":    "/3",
+															"symbol": "book/*",
 														},
 													},
 												},
@@ -194,6 +197,7 @@ func TestExplainQuerySumOfRelatedOneToManyFieldWithFilter(t *testing.T) {
 				"explain": dataMap{
 					"selectTopNode": dataMap{
 						"sumNode": dataMap{
+							"responseField": "TotalPages: _sum",
 							"sources": []dataMap{
 								{
 									"fieldName":      "articles",
@@ -217,8 +221,9 @@ func TestExplainQuerySumOfRelatedOneToManyFieldWithFilter(t *testing.T) {
 											"filter":         nil,
 											"spans": []dataMap{
 												{
-													"start": "/3",
-													"end":   "/4",
+													"start":  "/3",
+													"end":    "/4",
+													"symbol": "author/*",
 												},
 											},
 										},
@@ -238,8 +243,9 @@ func TestExplainQuerySumOfRelatedOneToManyFieldWithFilter(t *testing.T) {
 													},
 													"spans": []dataMap{
 														{
-															"start": "/1",
-															"end":   "/2",
+															"start":  "/1",
+															"end":    "/2",
+															"symbol": "article/*",
 														},
 													},
 												},
@@ -300,6 +306,7 @@ func TestExplainQuerySumOfInlineArrayField_ShouldHaveEmptyChildField(t *testing.
 				"explain": dataMap{
 					"selectTopNode": dataMap{
 						"sumNode": dataMap{
+							"responseField": "NotSureWhySomeoneWouldSumTheChapterPagesButHereItIs: _sum",
 							"sources": []dataMap{
 								{
 									"fieldName":      "chapterPages",
@@ -315,8 +322,9 @@ func TestExplainQuerySumOfInlineArrayField_ShouldHaveEmptyChildField(t *testing.
 									"filter":         nil,
 									"spans": []dataMap{
 										{
-											"start": "/2",
-											"end":   "/3",
+											"start":  "/2",
+											"end":    "/3",
+											"symbol": "book/*",
 										},
 									},
 								},
@@ -408,6 +416,7 @@ func TestExplainQuerySumOfRelatedOneToManyFieldWithManySources(t *testing.T) {
 				"explain": dataMap{
 					"selectTopNode": dataMap{
 						"sumNode": dataMap{
+							"responseField": "TotalPages: _sum",
 							"sources": []dataMap{
 								{
 									"childFieldName": "pages",
@@ -435,8 +444,9 @@ func TestExplainQuerySumOfRelatedOneToManyFieldWithManySources(t *testing.T) {
 													"filter":         nil,
 													"spans": []dataMap{
 														{
-															"start": "/3",
-															"end":   "/4",
+															"start":  "/3",
+															"end":    "/4",
+															"symbol": "author/*",
 														},
 													},
 												},
@@ -452,8 +462,9 @@ func TestExplainQuerySumOfRelatedOneToManyFieldWithManySources(t *testing.T) {
 															"filter":         nil,
 															"spans": []dataMap{
 																{
-																	"start": "/2",
-																	"end":   "/3",
+																	"start":  "/2",
+																	"end":    "/3",
+																	"symbol": "book/*",
 																},
 															},
 														},
@@ -473,8 +484,9 @@ func TestExplainQuerySumOfRelatedOneToManyFieldWithManySources(t *testing.T) {
 													"filter":         nil,
 													"spans": []dataMap{
 														{
-															"start": "/3",
-															"end":   "/4",
+															"start":  "/3",
+															"end":    "/4",
+															"symbol": "author/*",
 														},
 													},
 												},
@@ -490,8 +502,9 @@ func TestExplainQuerySumOfRelatedOneToManyFieldWithManySources(t *testing.T) {
 															"filter":         nil,
 															"spans": []dataMap{
 																{
-																	"start": "/1",
-																	"end":   "/2",
+																	"start":  "/1",
+																	"end":    "/2",
+																	"symbol": "article/*",
 																},
 															},
 														},
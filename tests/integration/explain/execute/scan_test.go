@@ -246,3 +246,80 @@ func TestExecuteExplainRequestWithDocumentsButNoMatches(t *testing.T) {
 
 	executeTestCase(t, test)
 }
+
+func TestExecuteExplainRequestWithDocKeys(t *testing.T) {
+	test := testUtils.TestCase{
+
+		Description: "Explain (execute) request with dockeys only scans the spans of the requested documents.",
+
+		Actions: []any{
+			gqlSchemaExecuteExplain(),
+
+			testUtils.CreateDoc{
+				CollectionID: 2,
+
+				// bae-111e8e29-0530-52ae-815f-14c7ba46d277
+				Doc: `{
+					"name": "Andy",
+					"age": 64
+				}`,
+			},
+
+			testUtils.CreateDoc{
+				CollectionID: 2,
+
+				// bae-e147be24-bf9c-5d38-8c7b-ad18e4034c53
+				Doc: `{
+					"name": "Shahzad",
+					"age": 48
+				}`,
+			},
+
+			testUtils.CreateDoc{
+				CollectionID: 2,
+				Doc: `{
+					"name": "John",
+					"age": 70
+				}`,
+			},
+
+			testUtils.Request{
+				Request: `query @explain(type: execute) {
+					Author(dockeys: [
+						"bae-e147be24-bf9c-5d38-8c7b-ad18e4034c53",
+						"bae-111e8e29-0530-52ae-815f-14c7ba46d277"
+					]) {
+						name
+						age
+					}
+				}`,
+
+				Results: []dataMap{
+					{
+						"explain": dataMap{
+							"executionSuccess": true,
+							"sizeOfResult":     2,
+							"planExecutions":   uint64(3),
+							"selectTopNode": dataMap{
+								"selectNode": dataMap{
+									"iterations":    uint64(3),
+									"filterMatches": uint64(2),
+									"scanNode": dataMap{
+										"iterations": uint64(3),
+										// The two requested documents, and the last fetch that
+										// finds no more documents in their spans. The third
+										// document is never fetched, as it is outside them.
+										"docFetches":    uint64(3),
+										"filterMatches": uint64(2),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	executeTestCase(t, test)
+}
@@ -98,8 +98,9 @@ func TestExecuteExplainRequestWithBothLimitAndOffsetOnParentAndLimitOnChild(t *t
 								"limitNode": dataMap{
 									"iterations": uint64(2),
 									"selectNode": dataMap{
-										"iterations":    uint64(2),
-										"filterMatches": uint64(2),
+										"iterations": uint64(2),
+										// The offset document is skipped by the scan node, before being joined.
+										"filterMatches": uint64(1),
 										"typeIndexJoin": dataMap{
 											"iterations": uint64(2),
 											"scanNode": dataMap{
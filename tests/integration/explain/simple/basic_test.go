@@ -49,8 +49,9 @@ func TestSimpleExplainRequest(t *testing.T) {
 								"collectionName": "author",
 								"spans": []dataMap{
 									{
-										"start": "/3",
-										"end":   "/4",
+										"start":  "/3",
+										"end":    "/4",
+										"symbol": "author/*",
 									},
 								},
 							},
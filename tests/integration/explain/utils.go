@@ -39,27 +39,30 @@ var (
 		"subType": {},
 
 		// These are all valid nodes.
-		"averageNode":   {},
-		"countNode":     {},
-		"createNode":    {},
-		"dagScanNode":   {},
-		"deleteNode":    {},
-		"groupNode":     {},
-		"limitNode":     {},
-		"multiScanNode": {},
-		"orderNode":     {},
-		"parallelNode":  {},
-		"pipeNode":      {},
-		"scanNode":      {},
-		"selectNode":    {},
-		"selectTopNode": {},
-		"sumNode":       {},
-		"topLevelNode":  {},
-		"typeIndexJoin": {},
-		"typeJoinMany":  {},
-		"typeJoinOne":   {},
-		"updateNode":    {},
-		"valuesNode":    {},
+		"averageNode":          {},
+		"countNode":            {},
+		"createNode":           {},
+		"dagScanNode":          {},
+		"deleteNode":           {},
+		"groupNode":            {},
+		"historyNode":          {},
+		"limitNode":            {},
+		"multiScanNode":        {},
+		"orderNode":            {},
+		"parallelNode":         {},
+		"pipeNode":             {},
+		"runningAggregateNode": {},
+		"scanNode":             {},
+		"selectNode":           {},
+		"selectTopNode":        {},
+		"sumNode":              {},
+		"topLevelNode":         {},
+		"typeIndexJoin":        {},
+		"typeJoinMany":         {},
+		"typeJoinOne":          {},
+		"typeJoinOn":           {},
+		"updateNode":           {},
+		"valuesNode":           {},
 	}
 )
 
@@ -0,0 +1,97 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package update
+
+import (
+	"testing"
+
+	testUtils "github.com/sourcenetwork/defradb/tests/integration"
+)
+
+func TestSimpleMutationUpdateWithNaNFloat(t *testing.T) {
+	test := testUtils.RequestTestCase{
+		Description: "Simple update mutation with a NaN float",
+		Request: `mutation {
+					update_user(data: "{\"points\": NaN}") {
+						name
+						points
+					}
+				}`,
+		Docs: map[int][]string{
+			0: {
+				`{
+					"name": "John",
+					"age": 27,
+					"verified": true,
+					"points": 42.1
+				}`,
+			},
+		},
+		ExpectedError: "float values must be finite, NaN and infinities are not supported",
+	}
+
+	ExecuteTestCase(t, test)
+}
+
+func TestSimpleMutationUpdateWithOverflowingFloat(t *testing.T) {
+	test := testUtils.RequestTestCase{
+		Description: "Simple update mutation with a float too large to be represented",
+		Request: `mutation {
+					update_user(data: "{\"points\": 1e400}") {
+						name
+						points
+					}
+				}`,
+		Docs: map[int][]string{
+			0: {
+				`{
+					"name": "John",
+					"age": 27,
+					"verified": true,
+					"points": 42.1
+				}`,
+			},
+		},
+		ExpectedError: "float values must be finite, NaN and infinities are not supported",
+	}
+
+	ExecuteTestCase(t, test)
+}
+
+func TestSimpleMutationUpdateWithLargestFloat(t *testing.T) {
+	test := testUtils.RequestTestCase{
+		Description: "Simple update mutation with the largest float",
+		Request: `mutation {
+					update_user(data: "{\"points\": 1.7976931348623157e308}") {
+						name
+						points
+					}
+				}`,
+		Docs: map[int][]string{
+			0: {
+				`{
+					"name": "John",
+					"age": 27,
+					"verified": true,
+					"points": 42.1
+				}`,
+			},
+		},
+		Results: []map[string]any{
+			{
+				"name":   "John",
+				"points": 1.7976931348623157e308,
+			},
+		},
+	}
+
+	ExecuteTestCase(t, test)
+}
@@ -0,0 +1,94 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package replicator
+
+import (
+	"testing"
+
+	"github.com/sourcenetwork/immutable"
+
+	"github.com/sourcenetwork/defradb/db"
+	testUtils "github.com/sourcenetwork/defradb/tests/integration"
+)
+
+func TestP2POneToOneReplicatorRejectsQuotaPruneOfAppendOnlyCollection(t *testing.T) {
+	// The first node keeps a single document of the collection, pruning the oldest one.
+	source := testUtils.RandomNetworkingConfig()
+	source.DBOptions = []db.Option{
+		db.WithQuotas(db.CollectionQuota{Collection: "Users", MaxDocuments: 1, Prune: true}),
+	}
+
+	test := testUtils.TestCase{
+		Actions: []any{
+			source,
+			testUtils.RandomNetworkingConfig(),
+			testUtils.SchemaUpdate{
+				Schema: `
+					type Users @appendOnly {
+						Name: String
+					}
+				`,
+			},
+			testUtils.ConfigureReplicator{
+				SourceNodeID: 0,
+				TargetNodeID: 1,
+			},
+			testUtils.CreateDoc{
+				NodeID: immutable.Some(0),
+				Doc: `{
+					"Name": "John"
+				}`,
+			},
+			testUtils.WaitForSync{},
+			testUtils.CreateDoc{
+				// John is pruned from the first node, but the second node rejects his deletion,
+				// the collection being append-only.
+				NodeID: immutable.Some(0),
+				Doc: `{
+					"Name": "Andy"
+				}`,
+				Pruned: 1,
+			},
+			testUtils.WaitForSync{},
+			testUtils.Request{
+				NodeID: immutable.Some(0),
+				Request: `query {
+					Users {
+						Name
+					}
+				}`,
+				Results: []map[string]any{
+					{
+						"Name": "Andy",
+					},
+				},
+			},
+			testUtils.Request{
+				NodeID: immutable.Some(1),
+				Request: `query {
+					Users(order: {Name: ASC}) {
+						Name
+					}
+				}`,
+				Results: []map[string]any{
+					{
+						"Name": "Andy",
+					},
+					{
+						"Name": "John",
+					},
+				},
+			},
+		},
+	}
+
+	testUtils.ExecuteTestCase(t, []string{"Users"}, test)
+}
@@ -0,0 +1,119 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package replicator
+
+import (
+	"testing"
+
+	"github.com/sourcenetwork/immutable"
+
+	testUtils "github.com/sourcenetwork/defradb/tests/integration"
+)
+
+func schemaSyncNetworkingConfig() testUtils.ConfigureNode {
+	cfg := testUtils.RandomNetworkingConfig()
+	cfg.Config.Net.SchemaSyncEnabled = true
+	return cfg
+}
+
+// The target node adopts the schema, including its updates, of the collection it replicates.
+func TestP2POneToOneReplicatorWithSchemaSync(t *testing.T) {
+	test := testUtils.TestCase{
+		Actions: []any{
+			schemaSyncNetworkingConfig(),
+			schemaSyncNetworkingConfig(),
+			testUtils.SchemaUpdate{
+				NodeID: immutable.Some(0),
+				Schema: `
+					type Users {
+						Name: String
+					}
+				`,
+			},
+			testUtils.SchemaPatch{
+				NodeID: immutable.Some(0),
+				Patch: `
+					[
+						{ "op": "add", "path": "/Users/Schema/Fields/-", "value": {"Name": "Email", "Kind": "String"} }
+					]
+				`,
+			},
+			testUtils.ConfigureReplicator{
+				SourceNodeID: 0,
+				TargetNodeID: 1,
+			},
+			testUtils.CreateDoc{
+				NodeID: immutable.Some(0),
+				Doc: `{
+					"Name": "John",
+					"Email": "john@source.network"
+				}`,
+			},
+			testUtils.WaitForSync{},
+			testUtils.Request{
+				NodeID: immutable.Some(1),
+				Request: `query {
+					Users {
+						Name
+						Email
+					}
+				}`,
+				Results: []map[string]any{
+					{
+						"Name":  "John",
+						"Email": "john@source.network",
+					},
+				},
+			},
+		},
+	}
+
+	testUtils.ExecuteTestCase(t, []string{"Users"}, test)
+}
+
+// Without schema sync the target node does not adopt the schema, and the document is not synced.
+func TestP2POneToOneReplicatorWithoutSchemaSync(t *testing.T) {
+	test := testUtils.TestCase{
+		Actions: []any{
+			testUtils.RandomNetworkingConfig(),
+			testUtils.RandomNetworkingConfig(),
+			testUtils.SchemaUpdate{
+				NodeID: immutable.Some(0),
+				Schema: `
+					type Users {
+						Name: String
+					}
+				`,
+			},
+			testUtils.ConfigureReplicator{
+				SourceNodeID: 0,
+				TargetNodeID: 1,
+			},
+			testUtils.CreateDoc{
+				NodeID: immutable.Some(0),
+				Doc: `{
+					"Name": "John"
+				}`,
+			},
+			testUtils.Request{
+				NodeID: immutable.Some(1),
+				Request: `query {
+					Users {
+						Name
+					}
+				}`,
+				ExpectedError: "Cannot query field \"Users\" on type \"Query\".",
+			},
+		},
+	}
+
+	testUtils.ExecuteTestCase(t, []string{"Users"}, test)
+}
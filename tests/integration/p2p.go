@@ -319,8 +319,9 @@ func configureReplicator(
 
 			// A document created on the source or one that is created on all nodes will be sent to the target even
 			// it already has it. It will create a `received push log` event on the target which we need to wait for.
+			// The deletes of the documents it prunes are sent as well.
 			if !action.NodeID.HasValue() || action.NodeID.Value() == cfg.SourceNodeID {
-				sourceToTargetEvents[waitIndex] += 1
+				sourceToTargetEvents[waitIndex] += 1 + action.Pruned
 			}
 
 			currentdocID++
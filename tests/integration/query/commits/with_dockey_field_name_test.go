@@ -0,0 +1,175 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package commits
+
+import (
+	"testing"
+
+	testUtils "github.com/sourcenetwork/defradb/tests/integration"
+)
+
+func TestQueryCommitsWithDockeyAndFieldName(t *testing.T) {
+	test := testUtils.TestCase{
+		Description: "Simple all commits query with dockey and field name",
+		Actions: []any{
+			updateUserCollectionSchema(),
+			testUtils.CreateDoc{
+				CollectionID: 0,
+				Doc: `{
+						"Name":	"John",
+						"Age":	21
+					}`,
+			},
+			testUtils.UpdateDoc{
+				CollectionID: 0,
+				DocID:        0,
+				Doc: `{
+						"Age":	22
+					}`,
+			},
+			testUtils.Request{
+				Request: `query {
+						commits(dockey: "bae-52b9170d-b77a-5887-b877-cbdbb99b009f", fieldName: "Age") {
+							cid
+							height
+							fieldId
+							fieldName
+						}
+					}`,
+				Results: []map[string]any{
+					{
+						"cid":       "bafybeiepww5b67jrrliuiy27erfjuivwnjca5ptdpbxrrjrqkh3b2hckyy",
+						"height":    int64(2),
+						"fieldId":   "1",
+						"fieldName": "Age",
+					},
+					{
+						"cid":       "bafybeihxvx3f7eejvco6zbxsidoeuph6ywpbo33lrqm3picna2aj7pdeiu",
+						"height":    int64(1),
+						"fieldId":   "1",
+						"fieldName": "Age",
+					},
+				},
+			},
+		},
+	}
+
+	testUtils.ExecuteTestCase(t, []string{"users"}, test)
+}
+
+func TestQueryCommitsWithDockeyAndUnknownFieldName(t *testing.T) {
+	test := testUtils.TestCase{
+		Description: "Simple all commits query with dockey and unknown field name",
+		Actions: []any{
+			updateUserCollectionSchema(),
+			testUtils.CreateDoc{
+				CollectionID: 0,
+				Doc: `{
+						"Name":	"John",
+						"Age":	21
+					}`,
+			},
+			testUtils.Request{
+				Request: `query {
+						commits(dockey: "bae-52b9170d-b77a-5887-b877-cbdbb99b009f", fieldName: "not a field") {
+							cid
+						}
+					}`,
+				Results: []map[string]any{},
+			},
+		},
+	}
+
+	testUtils.ExecuteTestCase(t, []string{"users"}, test)
+}
+
+func TestQueryCommitsWithDockeyReturnsFieldIdAndName(t *testing.T) {
+	test := testUtils.TestCase{
+		Description: "Simple all commits query with dockey returning field ids and names",
+		Actions: []any{
+			updateUserCollectionSchema(),
+			testUtils.CreateDoc{
+				CollectionID: 0,
+				Doc: `{
+						"Name":	"John",
+						"Age":	21
+					}`,
+			},
+			testUtils.Request{
+				Request: `query {
+						commits(dockey: "bae-52b9170d-b77a-5887-b877-cbdbb99b009f") {
+							fieldId
+							fieldName
+							peer
+						}
+					}`,
+				Results: []map[string]any{
+					{
+						"fieldId":   "1",
+						"fieldName": "Age",
+						"peer":      nil,
+					},
+					{
+						"fieldId":   "2",
+						"fieldName": "Name",
+						"peer":      nil,
+					},
+					{
+						"fieldId":   "C",
+						"fieldName": nil,
+						"peer":      nil,
+					},
+				},
+			},
+		},
+	}
+
+	testUtils.ExecuteTestCase(t, []string{"users"}, test)
+}
+
+func TestQueryLatestCommitsWithDockeyAndFieldName(t *testing.T) {
+	test := testUtils.TestCase{
+		Description: "Simple latest commits query with dockey and field name",
+		Actions: []any{
+			updateUserCollectionSchema(),
+			testUtils.CreateDoc{
+				CollectionID: 0,
+				Doc: `{
+						"Name":	"John",
+						"Age":	21
+					}`,
+			},
+			testUtils.UpdateDoc{
+				CollectionID: 0,
+				DocID:        0,
+				Doc: `{
+						"Name":	"Johnny"
+					}`,
+			},
+			testUtils.Request{
+				Request: `query {
+						latestCommits(dockey: "bae-52b9170d-b77a-5887-b877-cbdbb99b009f", fieldName: "Name") {
+							height
+							fieldName
+						}
+					}`,
+				Results: []map[string]any{
+					{
+						"height":    int64(2),
+						"fieldName": "Name",
+					},
+				},
+			},
+		},
+	}
+
+	testUtils.ExecuteTestCase(t, []string{"users"}, test)
+}
@@ -0,0 +1,131 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package join
+
+import (
+	"testing"
+
+	testUtils "github.com/sourcenetwork/defradb/tests/integration"
+)
+
+func TestQueryJoinOnFieldEquality(t *testing.T) {
+	test := testUtils.TestCase{
+		Description: "Ad-hoc join of two collections on the equality of a field of each",
+		Actions: []any{
+			testUtils.Request{
+				Request: `query {
+					Customer(order: {name: ASC}) {
+						name
+						_join_Purchase(localField: email, foreignField: customerEmail, order: {total: DESC}) {
+							item
+							total
+						}
+					}
+				}`,
+				Results: []map[string]any{
+					{
+						"name": "Fred",
+						"_join_Purchase": []map[string]any{
+							{
+								"item":  "Lamp",
+								"total": uint64(40),
+							},
+						},
+					},
+					{
+						"name": "Islam",
+						// Islam has no email, and so no purchases.
+						"_join_Purchase": []map[string]any{},
+					},
+					{
+						"name": "John",
+						"_join_Purchase": []map[string]any{
+							{
+								"item":  "Book",
+								"total": uint64(12),
+							},
+							{
+								"item":  "Pen",
+								"total": uint64(3),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	executeTestCase(t, test)
+}
+
+func TestQueryJoinWithAliasFilterAndLimit(t *testing.T) {
+	test := testUtils.TestCase{
+		Description: "Ad-hoc join with an alias, a filter, and a limit on the joined collection",
+		Actions: []any{
+			testUtils.Request{
+				Request: `query {
+					Purchase(filter: {item: {_eq: "Book"}}) {
+						item
+						buyer: _join_Customer(localField: customerEmail, foreignField: email) {
+							name
+							cityPurchases: _join_Customer(
+								localField: city,
+								foreignField: city,
+								filter: {name: {_ne: "John"}},
+								limit: 1
+							) {
+								name
+							}
+						}
+					}
+				}`,
+				Results: []map[string]any{
+					{
+						"item": "Book",
+						"buyer": []map[string]any{
+							{
+								"name": "John",
+								"cityPurchases": []map[string]any{
+									{
+										"name": "Fred",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	executeTestCase(t, test)
+}
+
+func TestQueryJoinOnObjectField(t *testing.T) {
+	test := testUtils.TestCase{
+		Description: "Ad-hoc join on a relation field",
+		Actions: []any{
+			testUtils.Request{
+				Request: `query {
+					Customer {
+						name
+						_join_Account(localField: account, foreignField: owner) {
+							number
+						}
+					}
+				}`,
+				ExpectedError: "collections can only be joined on scalar fields",
+			},
+		},
+	}
+
+	executeTestCase(t, test)
+}
@@ -0,0 +1,115 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package join
+
+import (
+	"testing"
+
+	testUtils "github.com/sourcenetwork/defradb/tests/integration"
+)
+
+var schema = (`
+	type Customer {
+		name: String
+		email: String
+		city: String
+		account: Account
+	}
+
+	type Account {
+		number: Int
+		owner: Customer @primary
+	}
+
+	type Purchase {
+		customerEmail: String
+		item: String
+		total: Int
+	}
+`)
+
+// dataset holds customers and purchases imported from different sources, which are only
+// linked by the email of the customers.
+var dataset = []any{
+	testUtils.CreateDoc{
+		CollectionID: 0,
+		Doc: `{
+			"name": "John",
+			"email": "john@example.com",
+			"city": "Lisbon"
+		}`,
+	},
+	testUtils.CreateDoc{
+		CollectionID: 0,
+		Doc: `{
+			"name": "Fred",
+			"email": "fred@example.com",
+			"city": "Lisbon"
+		}`,
+	},
+	testUtils.CreateDoc{
+		CollectionID: 0,
+		Doc: `{
+			"name": "Islam",
+			"city": "Cairo"
+		}`,
+	},
+	testUtils.CreateDoc{
+		CollectionID: 2,
+		Doc: `{
+			"customerEmail": "john@example.com",
+			"item": "Book",
+			"total": 12
+		}`,
+	},
+	testUtils.CreateDoc{
+		CollectionID: 2,
+		Doc: `{
+			"customerEmail": "john@example.com",
+			"item": "Pen",
+			"total": 3
+		}`,
+	},
+	testUtils.CreateDoc{
+		CollectionID: 2,
+		Doc: `{
+			"customerEmail": "fred@example.com",
+			"item": "Lamp",
+			"total": 40
+		}`,
+	},
+	testUtils.CreateDoc{
+		CollectionID: 2,
+		Doc: `{
+			"customerEmail": "andy@example.com",
+			"item": "Desk",
+			"total": 250
+		}`,
+	},
+}
+
+func executeTestCase(t *testing.T, test testUtils.TestCase) {
+	testUtils.ExecuteTestCase(
+		t,
+		[]string{"Customer", "Account", "Purchase"},
+		testUtils.TestCase{
+			Description: test.Description,
+			Actions: append(
+				[]any{
+					testUtils.SchemaUpdate{
+						Schema: schema,
+					},
+				},
+				append(dataset, test.Actions...)...,
+			),
+		},
+	)
+}
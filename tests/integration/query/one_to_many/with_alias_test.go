@@ -0,0 +1,171 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package one_to_many
+
+import (
+	"testing"
+
+	testUtils "github.com/sourcenetwork/defradb/tests/integration"
+)
+
+var aliasTestDocs = map[int][]string{
+	//books
+	0: {
+		// bae-b9b83269-1f28-5c3b-ae75-3fb4c00d559d
+		`{
+			"name": "Painted House",
+			"rating": 4.9,
+			"author_id": "bae-41598f0c-19bc-5da6-813b-e80f14a10df3"
+		}`,
+		`{
+			"name": "A Time for Mercy",
+			"rating": 4.5,
+			"author_id": "bae-41598f0c-19bc-5da6-813b-e80f14a10df3"
+		}`,
+		`{
+			"name": "Theif Lord",
+			"rating": 4.8,
+			"author_id": "bae-b769708d-f552-5c3d-a402-ccfd7ac7fb04"
+		}`,
+	},
+	//authors
+	1: {
+		// bae-41598f0c-19bc-5da6-813b-e80f14a10df3
+		`{
+			"name": "John Grisham",
+			"age": 65,
+			"verified": true
+		}`,
+		// bae-b769708d-f552-5c3d-a402-ccfd7ac7fb04
+		`{
+			"name": "Cornelia Funke",
+			"age": 62,
+			"verified": false
+		}`,
+	},
+}
+
+func TestQueryOneToManyWithAliasedChildrenWithDifferentFilters(t *testing.T) {
+	test := testUtils.RequestTestCase{
+		Description: "One-to-many relation query from many side with the same child aliased with different filters",
+		Request: `query {
+			author {
+				name
+				topBooks: published(filter: {rating: {_gt: 4.8}}) {
+					name
+				}
+				otherBooks: published(filter: {rating: {_le: 4.8}}) {
+					name
+					rating
+				}
+			}
+		}`,
+		Docs: aliasTestDocs,
+		Results: []map[string]any{
+			{
+				"name": "John Grisham",
+				"topBooks": []map[string]any{
+					{
+						"name": "Painted House",
+					},
+				},
+				"otherBooks": []map[string]any{
+					{
+						"name":   "A Time for Mercy",
+						"rating": 4.5,
+					},
+				},
+			},
+			{
+				"name":     "Cornelia Funke",
+				"topBooks": []map[string]any{},
+				"otherBooks": []map[string]any{
+					{
+						"name":   "Theif Lord",
+						"rating": 4.8,
+					},
+				},
+			},
+		},
+	}
+
+	executeTestCase(t, test)
+}
+
+func TestQueryOneToManyWithAliasedChildWithDocKeyAndSum(t *testing.T) {
+	test := testUtils.RequestTestCase{
+		Description: "One-to-many relation query from many side with aliased child with dockey and sum of all children",
+		Request: `query {
+			author(filter: {name: {_eq: "John Grisham"}}) {
+				name
+				house: published(dockey: "bae-b9b83269-1f28-5c3b-ae75-3fb4c00d559d") {
+					name
+				}
+				total: _sum(published: {field: rating})
+			}
+		}`,
+		Docs: aliasTestDocs,
+		Results: []map[string]any{
+			{
+				"name": "John Grisham",
+				"house": []map[string]any{
+					{
+						"name": "Painted House",
+					},
+				},
+				"total": 9.4,
+			},
+		},
+	}
+
+	executeTestCase(t, test)
+}
+
+func TestQueryOneToManyWithAliasedChildAndCountWithRelatedFilter(t *testing.T) {
+	test := testUtils.RequestTestCase{
+		Description: "One-to-many relation query from many side with aliased child and count with related filter",
+		Request: `query {
+			author {
+				name
+				books: published {
+					name
+				}
+				count: _count(published: {filter: {author: {name: {_eq: "John Grisham"}}}})
+			}
+		}`,
+		Docs: aliasTestDocs,
+		Results: []map[string]any{
+			{
+				"name": "John Grisham",
+				"books": []map[string]any{
+					{
+						"name": "Painted House",
+					},
+					{
+						"name": "A Time for Mercy",
+					},
+				},
+				"count": 2,
+			},
+			{
+				"name": "Cornelia Funke",
+				"books": []map[string]any{
+					{
+						"name": "Theif Lord",
+					},
+				},
+				"count": 0,
+			},
+		},
+	}
+
+	executeTestCase(t, test)
+}
@@ -35,6 +35,7 @@ func TestQueryOneToManyWithAverageAndChildNeNilFilterSharesJoinField(t *testing.
 				"explain": dataMap{
 					"selectTopNode": dataMap{
 						"averageNode": dataMap{
+							"responseField": "_avg",
 							"countNode": dataMap{
 								"sources": []dataMap{
 									{
@@ -61,8 +62,9 @@ func TestQueryOneToManyWithAverageAndChildNeNilFilterSharesJoinField(t *testing.
 									"selectNode": dataMap{
 										"filter": nil,
 										"typeIndexJoin": dataMap{
-											"joinType": "typeJoinMany",
-											"rootName": "author",
+											"responseField": "published",
+											"joinType":      "typeJoinMany",
+											"rootName":      "author",
 											"root": dataMap{
 												"scanNode": dataMap{
 													"filter":         nil,
@@ -70,8 +72,9 @@ func TestQueryOneToManyWithAverageAndChildNeNilFilterSharesJoinField(t *testing.
 													"collectionName": "author",
 													"spans": []dataMap{
 														{
-															"start": "/2",
-															"end":   "/3",
+															"start":  "/2",
+															"end":    "/3",
+															"symbol": "author/*",
 														},
 													},
 												},
@@ -91,8 +94,9 @@ func TestQueryOneToManyWithAverageAndChildNeNilFilterSharesJoinField(t *testing.
 															"collectionName": "book",
 															"spans": []dataMap{
 																{
-																	"start": "/1",
-																	"end":   "/2",
+																	"start":  "/1",
+																	"end":    "/2",
+																	"symbol": "book/*",
 																},
 															},
 														},
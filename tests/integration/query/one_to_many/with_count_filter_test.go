@@ -173,6 +173,7 @@ func TestQueryOneToManyWithCountWithFilterAndChildFilterSharesJoinField(t *testi
 				"explain": dataMap{
 					"selectTopNode": dataMap{
 						"countNode": dataMap{
+							"responseField": "_count",
 							"sources": []dataMap{
 								{
 									"filter": dataMap{
@@ -186,8 +187,9 @@ func TestQueryOneToManyWithCountWithFilterAndChildFilterSharesJoinField(t *testi
 							"selectNode": dataMap{
 								"filter": nil,
 								"typeIndexJoin": dataMap{
-									"joinType": "typeJoinMany",
-									"rootName": "author",
+									"responseField": "published",
+									"joinType":      "typeJoinMany",
+									"rootName":      "author",
 									"root": dataMap{
 										"scanNode": dataMap{
 											"filter":         nil,
@@ -195,8 +197,9 @@ func TestQueryOneToManyWithCountWithFilterAndChildFilterSharesJoinField(t *testi
 											"collectionName": "author",
 											"spans": []dataMap{
 												{
-													"start": "/2",
-													"end":   "/3",
+													"start":  "/2",
+													"end":    "/3",
+													"symbol": "author/*",
 												},
 											},
 										},
@@ -216,8 +219,9 @@ func TestQueryOneToManyWithCountWithFilterAndChildFilterSharesJoinField(t *testi
 													"collectionName": "book",
 													"spans": []dataMap{
 														{
-															"start": "/1",
-															"end":   "/2",
+															"start":  "/1",
+															"end":    "/2",
+															"symbol": "book/*",
 														},
 													},
 												},
@@ -255,12 +259,14 @@ func TestQueryOneToManyWithCountAndChildFilterDoesNotShareJoinField(t *testing.T
 				"explain": dataMap{
 					"selectTopNode": dataMap{
 						"countNode": dataMap{
+							"responseField": "_count",
 							"selectNode": dataMap{
 								"filter": nil,
 								"parallelNode": []dataMap{
 									{
 										"typeIndexJoin": dataMap{
-											"joinType": "typeJoinMany",
+											"responseField": "published",
+											"joinType":      "typeJoinMany",
 											"root": dataMap{
 												"scanNode": dataMap{
 													"collectionID":   "2",
@@ -268,8 +274,9 @@ func TestQueryOneToManyWithCountAndChildFilterDoesNotShareJoinField(t *testing.T
 													"filter":         nil,
 													"spans": []dataMap{
 														{
-															"end":   "/3",
-															"start": "/2",
+															"end":    "/3",
+															"start":  "/2",
+															"symbol": "author/*",
 														},
 													},
 												},
@@ -289,8 +296,9 @@ func TestQueryOneToManyWithCountAndChildFilterDoesNotShareJoinField(t *testing.T
 															},
 															"spans": []dataMap{
 																{
-																	"end":   "/2",
-																	"start": "/1",
+																	"end":    "/2",
+																	"start":  "/1",
+																	"symbol": "book/*",
 																},
 															},
 														},
@@ -310,8 +318,9 @@ func TestQueryOneToManyWithCountAndChildFilterDoesNotShareJoinField(t *testing.T
 													"filter":         nil,
 													"spans": []dataMap{
 														{
-															"end":   "/3",
-															"start": "/2",
+															"end":    "/3",
+															"start":  "/2",
+															"symbol": "author/*",
 														},
 													},
 												},
@@ -327,8 +336,9 @@ func TestQueryOneToManyWithCountAndChildFilterDoesNotShareJoinField(t *testing.T
 															"filter":         nil,
 															"spans": []dataMap{
 																{
-																	"end":   "/2",
-																	"start": "/1",
+																	"end":    "/2",
+																	"start":  "/1",
+																	"symbol": "book/*",
 																},
 															},
 														},
@@ -0,0 +1,135 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package one_to_many
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcenetwork/defradb/tests/bench/fixtures"
+	testUtils "github.com/sourcenetwork/defradb/tests/integration"
+)
+
+// datasetAuthorCount is the number of authors of the synthetic datasets of these tests.
+const datasetAuthorCount = 100
+
+func newTestDataset(t *testing.T) fixtures.Dataset {
+	config := fixtures.DefaultDatasetConfig(datasetAuthorCount)
+	config.BooksPerAuthor = fixtures.Uniform(0, 6)
+	config.AuthorAge = fixtures.Normal(50, 15, 18, 99)
+
+	dataset, err := fixtures.NewDataset(config)
+	require.NoError(t, err)
+	return dataset
+}
+
+// datasetActions returns the actions creating the schema and the documents of the given dataset.
+func datasetActions(t *testing.T, dataset fixtures.Dataset) []any {
+	actions := []any{
+		testUtils.SchemaUpdate{
+			Schema: fixtures.DatasetSchema,
+		},
+	}
+
+	authorDocs, err := dataset.AuthorDocs()
+	require.NoError(t, err)
+	for _, doc := range authorDocs {
+		actions = append(actions, testUtils.CreateDoc{CollectionID: 0, Doc: doc})
+	}
+
+	bookDocs, err := dataset.BookDocs()
+	require.NoError(t, err)
+	for _, doc := range bookDocs {
+		actions = append(actions, testUtils.CreateDoc{CollectionID: 1, Doc: doc})
+	}
+
+	return actions
+}
+
+func executeDatasetTestCase(t *testing.T, test testUtils.TestCase) {
+	testUtils.ExecuteTestCase(
+		t,
+		[]string{fixtures.AuthorCollectionName, fixtures.BookCollectionName},
+		test,
+	)
+}
+
+func TestQueryOneToManyWithDatasetAndCount(t *testing.T) {
+	dataset := newTestDataset(t)
+
+	results := []map[string]any{}
+	for i, author := range dataset.Authors {
+		results = append(results, map[string]any{
+			"name":   author.Name,
+			"_count": len(dataset.BooksOf(i)),
+		})
+	}
+
+	test := testUtils.TestCase{
+		Description: "One-to-many relation query with count over a synthetic dataset",
+		Actions: append(
+			datasetActions(t, dataset),
+			testUtils.Request{
+				Request: `query {
+					Author(order: {name: ASC}) {
+						name
+						_count(books: {})
+					}
+				}`,
+				Results: results,
+			},
+		),
+	}
+
+	executeDatasetTestCase(t, test)
+}
+
+func TestQueryOneToManyWithDatasetAndFilteredCount(t *testing.T) {
+	dataset := newTestDataset(t)
+
+	results := []map[string]any{}
+	for i, author := range dataset.Authors {
+		if author.Age <= 50 || !author.Verified {
+			continue
+		}
+
+		count := 0
+		for _, book := range dataset.BooksOf(i) {
+			if book.Genre == "poetry" && book.Rating > 2.5 {
+				count++
+			}
+		}
+		results = append(results, map[string]any{
+			"name":   author.Name,
+			"_count": count,
+		})
+	}
+	require.NotEmpty(t, results)
+
+	test := testUtils.TestCase{
+		Description: "One-to-many relation query with filters and filtered count over a synthetic dataset",
+		Actions: append(
+			datasetActions(t, dataset),
+			testUtils.Request{
+				Request: `query {
+					Author(filter: {age: {_gt: 50}, verified: {_eq: true}}, order: {name: ASC}) {
+						name
+						_count(books: {filter: {genre: {_eq: "poetry"}, rating: {_gt: 2.5}}})
+					}
+				}`,
+				Results: results,
+			},
+		),
+	}
+
+	executeDatasetTestCase(t, test)
+}
@@ -0,0 +1,148 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package one_to_many
+
+import (
+	"testing"
+
+	testUtils "github.com/sourcenetwork/defradb/tests/integration"
+)
+
+var orFilterDocs = map[int][]string{
+	//books
+	0: {
+		`{
+			"name": "Painted House",
+			"rating": 4.9,
+			"author_id": "bae-41598f0c-19bc-5da6-813b-e80f14a10df3"
+		}`,
+		`{
+			"name": "A Time for Mercy",
+			"rating": 4.5,
+			"author_id": "bae-41598f0c-19bc-5da6-813b-e80f14a10df3"
+		}`,
+		`{
+			"name": "Theif Lord",
+			"rating": 4.8,
+			"author_id": "bae-b769708d-f552-5c3d-a402-ccfd7ac7fb04"
+		}`,
+	},
+	//authors
+	1: {
+		// bae-41598f0c-19bc-5da6-813b-e80f14a10df3
+		`{
+			"name": "John Grisham",
+			"age": 65,
+			"verified": true
+		}`,
+		// bae-b769708d-f552-5c3d-a402-ccfd7ac7fb04
+		`{
+			"name": "Cornelia Funke",
+			"age": 62,
+			"verified": false
+		}`,
+		`{
+			"name": "Andrew Lone",
+			"age": 30,
+			"verified": true
+		}`,
+	},
+}
+
+func TestQueryOneToManyWithOrFilterOnParentAndChild(t *testing.T) {
+	test := testUtils.RequestTestCase{
+		Description: "One-to-many relation query from the many side, or filter on parent and child",
+		Request: `query {
+			author(filter: {_or: [{age: {_lt: 63}, verified: {_eq: false}}, {published: {rating: {_gt: 4.8}}}]}) {
+				name
+			}
+		}`,
+		Docs: orFilterDocs,
+		Results: []map[string]any{
+			{"name": "John Grisham"},
+			{"name": "Cornelia Funke"},
+		},
+	}
+
+	executeTestCase(t, test)
+}
+
+func TestQueryOneToManyWithOrFilterOnKeyAndChild(t *testing.T) {
+	test := testUtils.RequestTestCase{
+		Description: "One-to-many relation query from the many side, or filter on key and child",
+		Request: `query {
+			author(filter: {_or: [
+				{_key: {_eq: "bae-b769708d-f552-5c3d-a402-ccfd7ac7fb04"}},
+				{published: {name: {_eq: "Painted House"}}},
+				{published: {rating: {_gt: 4.7}}}
+			]}) {
+				name
+				published {
+					name
+				}
+			}
+		}`,
+		Docs: orFilterDocs,
+		Results: []map[string]any{
+			{
+				"name": "John Grisham",
+				"published": []map[string]any{
+					{"name": "Painted House"},
+					{"name": "A Time for Mercy"},
+				},
+			},
+			{
+				"name": "Cornelia Funke",
+				"published": []map[string]any{
+					{"name": "Theif Lord"},
+				},
+			},
+		},
+	}
+
+	executeTestCase(t, test)
+}
+
+func TestQueryOneToManyWithOrFilterOnParentAndChildFromSingleSide(t *testing.T) {
+	test := testUtils.RequestTestCase{
+		Description: "One-to-many relation query from the single side, or filter on child and parent",
+		Request: `query {
+			book(filter: {_or: [{rating: {_gt: 4.8}}, {author: {age: {_lt: 63}}}]}) {
+				name
+			}
+		}`,
+		Docs: orFilterDocs,
+		Results: []map[string]any{
+			{"name": "Theif Lord"},
+			{"name": "Painted House"},
+		},
+	}
+
+	executeTestCase(t, test)
+}
+
+func TestQueryOneToManyWithOrFilterOnKeyAndChildWithoutMatches(t *testing.T) {
+	test := testUtils.RequestTestCase{
+		Description: "One-to-many relation query from the many side, or filter on key and child without matches",
+		Request: `query {
+			author(filter: {_or: [
+				{_key: {_in: []}},
+				{published: {name: {_eq: "Inkheart"}}}
+			]}) {
+				name
+			}
+		}`,
+		Docs:    orFilterDocs,
+		Results: []map[string]any{},
+	}
+
+	executeTestCase(t, test)
+}
@@ -179,3 +179,115 @@ func TestQueryOneToManyWithMultipleChildLimits(t *testing.T) {
 
 	executeTestCase(t, test)
 }
+
+func TestQueryOneToManyWithParentLimitAndOffset(t *testing.T) {
+	test := testUtils.RequestTestCase{
+		Description: "One-to-many relation query from many side with limit and offset on the parent",
+		Request: `query {
+			author(limit: 1, offset: 1) {
+				name
+				published {
+					name
+				}
+			}
+		}`,
+		Docs: map[int][]string{
+			//books
+			0: { // bae-fd541c25-229e-5280-b44b-e5c2af3e374d
+				`{
+					"name": "Painted House",
+					"rating": 4.9,
+					"author_id": "bae-41598f0c-19bc-5da6-813b-e80f14a10df3"
+				}`,
+				`{
+					"name": "Theif Lord",
+					"rating": 4.8,
+					"author_id": "bae-b769708d-f552-5c3d-a402-ccfd7ac7fb04"
+				}`,
+			},
+			//authors
+			1: {
+				// bae-41598f0c-19bc-5da6-813b-e80f14a10df3
+				`{
+					"name": "John Grisham",
+					"age": 65,
+					"verified": true
+				}`,
+				// bae-b769708d-f552-5c3d-a402-ccfd7ac7fb04
+				`{
+					"name": "Cornelia Funke",
+					"age": 62,
+					"verified": false
+				}`,
+			},
+		},
+		Results: []map[string]any{
+			{
+				"name": "Cornelia Funke",
+				"published": []map[string]any{
+					{
+						"name": "Theif Lord",
+					},
+				},
+			},
+		},
+	}
+
+	executeTestCase(t, test)
+}
+
+func TestQueryOneToManyWithParentLimitAndChildFilter(t *testing.T) {
+	test := testUtils.RequestTestCase{
+		Description: "One-to-many relation query from many side with limit on the parent and filter on the child",
+		Request: `query {
+			author(limit: 1, filter: {published: {rating: {_lt: 4.85}}}) {
+				name
+				published {
+					name
+				}
+			}
+		}`,
+		Docs: map[int][]string{
+			//books
+			0: { // bae-fd541c25-229e-5280-b44b-e5c2af3e374d
+				`{
+					"name": "Painted House",
+					"rating": 4.9,
+					"author_id": "bae-41598f0c-19bc-5da6-813b-e80f14a10df3"
+				}`,
+				`{
+					"name": "Theif Lord",
+					"rating": 4.8,
+					"author_id": "bae-b769708d-f552-5c3d-a402-ccfd7ac7fb04"
+				}`,
+			},
+			//authors
+			1: {
+				// bae-41598f0c-19bc-5da6-813b-e80f14a10df3
+				`{
+					"name": "John Grisham",
+					"age": 65,
+					"verified": true
+				}`,
+				// bae-b769708d-f552-5c3d-a402-ccfd7ac7fb04
+				`{
+					"name": "Cornelia Funke",
+					"age": 62,
+					"verified": false
+				}`,
+			},
+		},
+		Results: []map[string]any{
+			{
+				"name": "Cornelia Funke",
+				"published": []map[string]any{
+					{
+						"name": "Theif Lord",
+					},
+				},
+			},
+		},
+	}
+
+	executeTestCase(t, test)
+}
@@ -0,0 +1,162 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package one_to_many
+
+import (
+	"testing"
+
+	testUtils "github.com/sourcenetwork/defradb/tests/integration"
+)
+
+var runningAggregateDocs = map[int][]string{
+	//books
+	0: { // bae-fd541c25-229e-5280-b44b-e5c2af3e374d
+		`{
+			"name": "Painted House",
+			"rating": 5,
+			"author_id": "bae-41598f0c-19bc-5da6-813b-e80f14a10df3"
+		}`,
+		`{
+			"name": "A Time for Mercy",
+			"rating": 4.5,
+			"author_id": "bae-41598f0c-19bc-5da6-813b-e80f14a10df3"
+		}`,
+		`{
+			"name": "The Associate",
+			"rating": 4,
+			"author_id": "bae-41598f0c-19bc-5da6-813b-e80f14a10df3"
+		}`,
+		`{
+			"name": "Sooley",
+			"rating": 3.5,
+			"author_id": "bae-41598f0c-19bc-5da6-813b-e80f14a10df3"
+		}`,
+		`{
+			"name": "Theif Lord",
+			"rating": 4.75,
+			"author_id": "bae-b769708d-f552-5c3d-a402-ccfd7ac7fb04"
+		}`,
+	},
+	//authors
+	1: {
+		// bae-41598f0c-19bc-5da6-813b-e80f14a10df3
+		`{
+			"name": "John Grisham",
+			"age": 65,
+			"verified": true
+		}`,
+		// bae-b769708d-f552-5c3d-a402-ccfd7ac7fb04
+		`{
+			"name": "Cornelia Funke",
+			"age": 62,
+			"verified": false
+		}`,
+	},
+}
+
+func TestQueryOneToManyWithRunningSumWithOrder(t *testing.T) {
+	test := testUtils.RequestTestCase{
+		Description: "One-to-many relation query from many side with running sum with order",
+		Request: `query {
+				author {
+					name
+					_runningSum(published: {field: rating, order: {name: ASC}})
+				}
+			}`,
+		Docs: runningAggregateDocs,
+		Results: []map[string]any{
+			{
+				"name":        "John Grisham",
+				"_runningSum": []float64{4.5, 9.5, 13, 17},
+			},
+			{
+				"name":        "Cornelia Funke",
+				"_runningSum": []float64{4.75},
+			},
+		},
+	}
+
+	executeTestCase(t, test)
+}
+
+func TestQueryOneToManyWithRunningAverageWithWindowAndOrder(t *testing.T) {
+	test := testUtils.RequestTestCase{
+		Description: "One-to-many relation query from many side with running average with window and order",
+		Request: `query {
+				author {
+					name
+					_runningAvg(published: {field: rating, window: 2, order: {rating: DESC}})
+				}
+			}`,
+		Docs: runningAggregateDocs,
+		Results: []map[string]any{
+			{
+				"name":        "John Grisham",
+				"_runningAvg": []float64{5, 4.75, 4.25, 3.75},
+			},
+			{
+				"name":        "Cornelia Funke",
+				"_runningAvg": []float64{4.75},
+			},
+		},
+	}
+
+	executeTestCase(t, test)
+}
+
+func TestQueryOneToManyWithRunningSumWithWindowAndFilterAndLimit(t *testing.T) {
+	test := testUtils.RequestTestCase{
+		Description: "One-to-many relation query from many side with running sum with window, filter and limit",
+		Request: `query {
+				author {
+					name
+					_runningSum(
+						published: {
+							field: rating,
+							window: 2,
+							filter: {rating: {_gt: 3.5}},
+							order: {name: DESC},
+							limit: 3
+						}
+					)
+				}
+			}`,
+		Docs: runningAggregateDocs,
+		Results: []map[string]any{
+			{
+				"name":        "John Grisham",
+				"_runningSum": []float64{4, 9, 9.5},
+			},
+			{
+				"name":        "Cornelia Funke",
+				"_runningSum": []float64{4.75},
+			},
+		},
+	}
+
+	executeTestCase(t, test)
+}
+
+func TestQueryOneToManyWithRunningSumWithZeroWindow(t *testing.T) {
+	test := testUtils.RequestTestCase{
+		Description: "One-to-many relation query from many side with running sum with a window of zero",
+		Request: `query {
+				author {
+					name
+					_runningSum(published: {field: rating, window: 0})
+				}
+			}`,
+		Docs:          runningAggregateDocs,
+		ExpectedError: "the window must be greater than zero",
+	}
+
+	executeTestCase(t, test)
+}
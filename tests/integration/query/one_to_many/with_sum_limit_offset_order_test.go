@@ -412,3 +412,57 @@ func TestQueryOneToManyWithSumWithLimitWithOffsetWithOrderDescAndRenderedChildre
 
 	executeTestCase(t, test)
 }
+
+func TestQueryOneToManyWithSumWithLimitWithOrderList(t *testing.T) {
+	test := testUtils.RequestTestCase{
+		Description: "One-to-many relation query from many side with sum with limit and order list",
+		Request: `query {
+				author {
+					name
+					_sum(published: {field: rating, limit: 1, order: [{name: DESC}, {rating: ASC}]})
+				}
+			}`,
+		Docs: map[int][]string{
+			//books
+			0: {
+				`{
+					"name": "Painted House",
+					"rating": 4.9,
+					"author_id": "bae-41598f0c-19bc-5da6-813b-e80f14a10df3"
+				}`,
+				`{
+					"name": "Sooley",
+					"rating": 4,
+					"author_id": "bae-41598f0c-19bc-5da6-813b-e80f14a10df3"
+				}`,
+				`{
+					"name": "Sooley",
+					"rating": 3.2,
+					"author_id": "bae-41598f0c-19bc-5da6-813b-e80f14a10df3"
+				}`,
+				`{
+					"name": "A Time for Mercy",
+					"rating": 2,
+					"author_id": "bae-41598f0c-19bc-5da6-813b-e80f14a10df3"
+				}`,
+			},
+			//authors
+			1: {
+				// bae-41598f0c-19bc-5da6-813b-e80f14a10df3
+				`{
+					"name": "John Grisham",
+					"age": 65,
+					"verified": true
+				}`,
+			},
+		},
+		Results: []map[string]any{
+			{
+				"name": "John Grisham",
+				"_sum": 3.2,
+			},
+		},
+	}
+
+	executeTestCase(t, test)
+}
@@ -0,0 +1,259 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package one_to_one
+
+import (
+	"testing"
+
+	testUtils "github.com/sourcenetwork/defradb/tests/integration"
+)
+
+func TestQueryOneToOneWithIncludeMissingFalse(t *testing.T) {
+	test := testUtils.RequestTestCase{
+		Description: "One-to-one relation primary direction, without missing children",
+		Request: `query {
+			author {
+				name
+				published(includeMissing: false) {
+					name
+				}
+			}
+		}`,
+		Docs: map[int][]string{
+			//books
+			0: {
+				// bae-fd541c25-229e-5280-b44b-e5c2af3e374d
+				`{
+					"name": "Painted House",
+					"rating": 4.9
+				}`,
+			},
+			//authors
+			1: {
+				`{
+					"name": "John Grisham",
+					"published_id": "bae-fd541c25-229e-5280-b44b-e5c2af3e374d"
+				}`,
+				`{
+					"name": "Cornelia Funke"
+				}`,
+			},
+		},
+		Results: []map[string]any{
+			{
+				"name": "John Grisham",
+				"published": map[string]any{
+					"name": "Painted House",
+				},
+			},
+		},
+	}
+
+	executeTestCase(t, test)
+}
+
+func TestQueryOneToOneWithIncludeMissingFalseSecondaryDirection(t *testing.T) {
+	test := testUtils.RequestTestCase{
+		Description: "One-to-one relation secondary direction, without missing children",
+		Request: `query {
+			book {
+				name
+				author(includeMissing: false) {
+					name
+				}
+			}
+		}`,
+		Docs: map[int][]string{
+			//books
+			0: {
+				// bae-fd541c25-229e-5280-b44b-e5c2af3e374d
+				`{
+					"name": "Painted House",
+					"rating": 4.9
+				}`,
+				`{
+					"name": "Theif Lord",
+					"rating": 4.8
+				}`,
+			},
+			//authors
+			1: {
+				`{
+					"name": "John Grisham",
+					"published_id": "bae-fd541c25-229e-5280-b44b-e5c2af3e374d"
+				}`,
+			},
+		},
+		Results: []map[string]any{
+			{
+				"name": "Painted House",
+				"author": map[string]any{
+					"name": "John Grisham",
+				},
+			},
+		},
+	}
+
+	executeTestCase(t, test)
+}
+
+func TestQueryOneToOneWithIncludeMissingFalseAndLimit(t *testing.T) {
+	test := testUtils.RequestTestCase{
+		Description: "One-to-one relation secondary direction, without missing children, with limit",
+		Request: `query {
+			book(limit: 1) {
+				name
+				author(includeMissing: false) {
+					name
+				}
+			}
+		}`,
+		Docs: map[int][]string{
+			//books
+			0: {
+				// bae-fd541c25-229e-5280-b44b-e5c2af3e374d
+				`{
+					"name": "Painted House",
+					"rating": 4.9
+				}`,
+				// bae-d432bdfb-787d-5a1c-ac29-dc025ab80095
+				`{
+					"name": "Theif Lord",
+					"rating": 4.8
+				}`,
+			},
+			//authors
+			1: {
+				`{
+					"name": "John Grisham",
+					"published_id": "bae-fd541c25-229e-5280-b44b-e5c2af3e374d"
+				}`,
+			},
+		},
+		Results: []map[string]any{
+			{
+				"name": "Painted House",
+				"author": map[string]any{
+					"name": "John Grisham",
+				},
+			},
+		},
+	}
+
+	executeTestCase(t, test)
+}
+
+func TestQueryOneToOneWithIncludeMissingFalseAndChildFilter(t *testing.T) {
+	test := testUtils.RequestTestCase{
+		Description: "One-to-one relation primary direction, without children not matching their filter",
+		Request: `query {
+			author {
+				name
+				published(filter: {rating: {_gt: 4.8}}, includeMissing: false) {
+					name
+				}
+			}
+		}`,
+		Docs: map[int][]string{
+			//books
+			0: {
+				// bae-fd541c25-229e-5280-b44b-e5c2af3e374d
+				`{
+					"name": "Painted House",
+					"rating": 4.9
+				}`,
+				// bae-d432bdfb-787d-5a1c-ac29-dc025ab80095
+				`{
+					"name": "Theif Lord",
+					"rating": 4.8
+				}`,
+			},
+			//authors
+			1: {
+				`{
+					"name": "John Grisham",
+					"published_id": "bae-fd541c25-229e-5280-b44b-e5c2af3e374d"
+				}`,
+				`{
+					"name": "Cornelia Funke",
+					"published_id": "bae-d432bdfb-787d-5a1c-ac29-dc025ab80095"
+				}`,
+			},
+		},
+		Results: []map[string]any{
+			{
+				"name": "John Grisham",
+				"published": map[string]any{
+					"name": "Painted House",
+				},
+			},
+		},
+	}
+
+	executeTestCase(t, test)
+}
+
+func TestQueryOneToOneWithDanglingRelation(t *testing.T) {
+	test := testUtils.RequestTestCase{
+		Description: "One-to-one relation primary direction, child that does not exist",
+		Request: `query {
+			author {
+				name
+				published {
+					name
+				}
+			}
+		}`,
+		Docs: map[int][]string{
+			//authors
+			1: {
+				`{
+					"name": "John Grisham",
+					"published_id": "bae-fd541c25-229e-5280-b44b-e5c2af3e374d"
+				}`,
+			},
+		},
+		Results: []map[string]any{
+			{
+				"name":      "John Grisham",
+				"published": nil,
+			},
+		},
+	}
+
+	executeTestCase(t, test)
+}
+
+func TestQueryOneToOneWithDanglingRelationAndIncludeMissingFalse(t *testing.T) {
+	test := testUtils.RequestTestCase{
+		Description: "One-to-one relation primary direction, without children that do not exist",
+		Request: `query {
+			author {
+				name
+				published(includeMissing: false) {
+					name
+				}
+			}
+		}`,
+		Docs: map[int][]string{
+			//authors
+			1: {
+				`{
+					"name": "John Grisham",
+					"published_id": "bae-fd541c25-229e-5280-b44b-e5c2af3e374d"
+				}`,
+			},
+		},
+		Results: []map[string]any{},
+	}
+
+	executeTestCase(t, test)
+}
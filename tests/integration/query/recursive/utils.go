@@ -0,0 +1,49 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package recursive
+
+import (
+	"testing"
+
+	testUtils "github.com/sourcenetwork/defradb/tests/integration"
+)
+
+var schema = (`
+	type Comment {
+		text: String
+		parent: Comment @relation(name: "thread")
+		replies: [Comment] @relation(name: "thread")
+	}
+
+	type Node {
+		name: String
+		next: Node @relation(name: "link") @primary
+		previous: Node @relation(name: "link")
+	}
+`)
+
+func executeTestCase(t *testing.T, test testUtils.TestCase) {
+	testUtils.ExecuteTestCase(
+		t,
+		[]string{"Comment", "Node"},
+		testUtils.TestCase{
+			Description: test.Description,
+			Actions: append(
+				[]any{
+					testUtils.SchemaUpdate{
+						Schema: schema,
+					},
+				},
+				test.Actions...,
+			),
+		},
+	)
+}
@@ -0,0 +1,165 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package recursive
+
+import (
+	"testing"
+
+	testUtils "github.com/sourcenetwork/defradb/tests/integration"
+)
+
+func TestQueryRecursiveWithDepthWithCycle(t *testing.T) {
+	test := testUtils.TestCase{
+		Description: "Recursive query with depth, with cycle",
+		Actions: []any{
+			testUtils.CreateDoc{
+				CollectionID: 1,
+				// bae-4f40a380-6312-554f-8b18-db19c73b0e72
+				Doc: `{
+					"name": "B"
+				}`,
+			},
+			testUtils.CreateDoc{
+				CollectionID: 1,
+				// bae-825ec86c-cc89-599f-ae54-e7371bee7fcd
+				Doc: `{
+					"name": "A",
+					"next_id": "bae-4f40a380-6312-554f-8b18-db19c73b0e72"
+				}`,
+			},
+			testUtils.UpdateDoc{
+				CollectionID: 1,
+				DocID:        0,
+				Doc: `{
+					"next_id": "bae-825ec86c-cc89-599f-ae54-e7371bee7fcd"
+				}`,
+			},
+			testUtils.Request{
+				Request: `query {
+					Node(filter: {name: {_eq: "A"}}) {
+						name
+						next(depth: 4) {
+							name
+						}
+					}
+				}`,
+				Results: []map[string]any{
+					{
+						"name": "A",
+						"next": map[string]any{
+							"name": "B",
+							"next": nil,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	executeTestCase(t, test)
+}
+
+func TestQueryRecursiveWithDepthWithoutCycle(t *testing.T) {
+	test := testUtils.TestCase{
+		Description: "Recursive query with depth, without cycle",
+		Actions: []any{
+			testUtils.CreateDoc{
+				CollectionID: 1,
+				// bae-4f40a380-6312-554f-8b18-db19c73b0e72
+				Doc: `{
+					"name": "B"
+				}`,
+			},
+			testUtils.CreateDoc{
+				CollectionID: 1,
+				Doc: `{
+					"name": "A",
+					"next_id": "bae-4f40a380-6312-554f-8b18-db19c73b0e72"
+				}`,
+			},
+			testUtils.Request{
+				Request: `query {
+					Node(filter: {name: {_eq: "A"}}) {
+						name
+						next(depth: 4) {
+							name
+						}
+					}
+				}`,
+				Results: []map[string]any{
+					{
+						"name": "A",
+						"next": map[string]any{
+							"name": "B",
+							"next": nil,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	executeTestCase(t, test)
+}
+
+func TestQueryRecursiveWithoutDepthWithCycle(t *testing.T) {
+	test := testUtils.TestCase{
+		Description: "Nested query without depth, with cycle",
+		Actions: []any{
+			testUtils.CreateDoc{
+				CollectionID: 1,
+				Doc: `{
+					"name": "B"
+				}`,
+			},
+			testUtils.CreateDoc{
+				CollectionID: 1,
+				Doc: `{
+					"name": "A",
+					"next_id": "bae-4f40a380-6312-554f-8b18-db19c73b0e72"
+				}`,
+			},
+			testUtils.UpdateDoc{
+				CollectionID: 1,
+				DocID:        0,
+				Doc: `{
+					"next_id": "bae-825ec86c-cc89-599f-ae54-e7371bee7fcd"
+				}`,
+			},
+			testUtils.Request{
+				Request: `query {
+					Node(filter: {name: {_eq: "A"}}) {
+						name
+						next {
+							name
+							next {
+								name
+							}
+						}
+					}
+				}`,
+				Results: []map[string]any{
+					{
+						"name": "A",
+						"next": map[string]any{
+							"name": "B",
+							"next": map[string]any{
+								"name": "A",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	executeTestCase(t, test)
+}
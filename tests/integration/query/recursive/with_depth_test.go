@@ -0,0 +1,215 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package recursive
+
+import (
+	"testing"
+
+	testUtils "github.com/sourcenetwork/defradb/tests/integration"
+)
+
+var commentThread = []any{
+	testUtils.CreateDoc{
+		CollectionID: 0,
+		// bae-54347982-7345-5b37-80ee-efb72cbdf3cb
+		Doc: `{
+			"text": "First!"
+		}`,
+	},
+	testUtils.CreateDoc{
+		CollectionID: 0,
+		// bae-b5d6a82e-cd2d-5911-b928-1567cc74deff
+		Doc: `{
+			"text": "Agreed",
+			"parent_id": "bae-54347982-7345-5b37-80ee-efb72cbdf3cb"
+		}`,
+	},
+	testUtils.CreateDoc{
+		CollectionID: 0,
+		Doc: `{
+			"text": "Disagreed",
+			"parent_id": "bae-54347982-7345-5b37-80ee-efb72cbdf3cb"
+		}`,
+	},
+	testUtils.CreateDoc{
+		CollectionID: 0,
+		Doc: `{
+			"text": "Agreed with the agreement",
+			"parent_id": "bae-b5d6a82e-cd2d-5911-b928-1567cc74deff"
+		}`,
+	},
+}
+
+func TestQueryRecursiveWithDepthOfOne(t *testing.T) {
+	test := testUtils.TestCase{
+		Description: "Recursive query with depth of one",
+		Actions: append(
+			commentThread,
+			testUtils.Request{
+				Request: `query {
+					Comment(filter: {text: {_eq: "First!"}}) {
+						text
+						replies(depth: 1) {
+							text
+						}
+					}
+				}`,
+				Results: []map[string]any{
+					{
+						"text": "First!",
+						"replies": []map[string]any{
+							{
+								"text": "Disagreed",
+							},
+							{
+								"text": "Agreed",
+							},
+						},
+					},
+				},
+			},
+		),
+	}
+
+	executeTestCase(t, test)
+}
+
+func TestQueryRecursiveWithDepth(t *testing.T) {
+	test := testUtils.TestCase{
+		Description: "Recursive query with depth",
+		Actions: append(
+			commentThread,
+			testUtils.Request{
+				Request: `query {
+					Comment(filter: {text: {_eq: "First!"}}) {
+						text
+						replies(depth: 3) {
+							text
+						}
+					}
+				}`,
+				Results: []map[string]any{
+					{
+						"text": "First!",
+						"replies": []map[string]any{
+							{
+								"text":    "Disagreed",
+								"replies": []map[string]any{},
+							},
+							{
+								"text": "Agreed",
+								"replies": []map[string]any{
+									{
+										"text":    "Agreed with the agreement",
+										"replies": []map[string]any{},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		),
+	}
+
+	executeTestCase(t, test)
+}
+
+func TestQueryRecursiveWithDepthLowerThanThread(t *testing.T) {
+	test := testUtils.TestCase{
+		Description: "Recursive query with depth lower than the depth of the thread",
+		Actions: append(
+			commentThread,
+			testUtils.Request{
+				Request: `query {
+					Comment(filter: {text: {_eq: "First!"}}) {
+						text
+						replies(depth: 2, filter: {text: {_like: "Agreed%"}}) {
+							text
+						}
+					}
+				}`,
+				Results: []map[string]any{
+					{
+						"text": "First!",
+						"replies": []map[string]any{
+							{
+								"text": "Agreed",
+								"replies": []map[string]any{
+									{
+										"text": "Agreed with the agreement",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		),
+	}
+
+	executeTestCase(t, test)
+}
+
+func TestQueryRecursiveWithAliasedDepth(t *testing.T) {
+	test := testUtils.TestCase{
+		Description: "Recursive query with aliased field with depth",
+		Actions: append(
+			commentThread,
+			testUtils.Request{
+				Request: `query {
+					Comment(filter: {text: {_eq: "Agreed with the agreement"}}) {
+						text
+						ancestor: parent(depth: 3) {
+							text
+						}
+					}
+				}`,
+				Results: []map[string]any{
+					{
+						"text": "Agreed with the agreement",
+						"ancestor": map[string]any{
+							"text": "Agreed",
+							"ancestor": map[string]any{
+								"text":     "First!",
+								"ancestor": nil,
+							},
+						},
+					},
+				},
+			},
+		),
+	}
+
+	executeTestCase(t, test)
+}
+
+func TestQueryRecursiveWithDepthOfZero(t *testing.T) {
+	test := testUtils.TestCase{
+		Description: "Recursive query with depth of zero",
+		Actions: append(
+			commentThread,
+			testUtils.Request{
+				Request: `query {
+					Comment {
+						text
+						replies(depth: 0) {
+							text
+						}
+					}
+				}`,
+				ExpectedError: "the depth must be greater than zero",
+			},
+		),
+	}
+
+	executeTestCase(t, test)
+}
@@ -0,0 +1,204 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package simple
+
+import (
+	"testing"
+
+	testUtils "github.com/sourcenetwork/defradb/tests/integration"
+)
+
+func TestQuerySimpleWithSkipDirective(t *testing.T) {
+	test := testUtils.TestCase{
+		Description: "Simple query with skip directive",
+		Actions: []any{
+			testUtils.SchemaUpdate{
+				Schema: userCollectionGQLSchema,
+			},
+			testUtils.CreateDoc{
+				Doc: `{
+					"Name": "John",
+					"Age": 21
+				}`,
+			},
+			testUtils.Request{
+				Request: `query {
+					users {
+						Name
+						Age @skip(if: true)
+						_version @skip(if: false) {
+							height
+						}
+					}
+				}`,
+				Results: []map[string]any{
+					{
+						"Name": "John",
+						"_version": []map[string]any{
+							{
+								"height": int64(1),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	testUtils.ExecuteTestCase(t, []string{"users"}, test)
+}
+
+func TestQuerySimpleWithIncludeDirective(t *testing.T) {
+	test := testUtils.TestCase{
+		Description: "Simple query with include directive",
+		Actions: []any{
+			testUtils.SchemaUpdate{
+				Schema: userCollectionGQLSchema,
+			},
+			testUtils.CreateDoc{
+				Doc: `{
+					"Name": "John",
+					"Age": 21
+				}`,
+			},
+			testUtils.Request{
+				Request: `query {
+					users {
+						Name @include(if: true)
+						Age @include(if: false)
+					}
+				}`,
+				Results: []map[string]any{
+					{
+						"Name": "John",
+					},
+				},
+			},
+		},
+	}
+
+	testUtils.ExecuteTestCase(t, []string{"users"}, test)
+}
+
+func TestQuerySimpleWithDirectivesWithVariables(t *testing.T) {
+	test := testUtils.TestCase{
+		Description: "Simple query with skip and include directives with variables",
+		Actions: []any{
+			testUtils.SchemaUpdate{
+				Schema: userCollectionGQLSchema,
+			},
+			testUtils.CreateDoc{
+				Doc: `{
+					"Name": "John",
+					"Age": 21
+				}`,
+			},
+			testUtils.Request{
+				Request: `query($skipName: Boolean!, $withAge: Boolean!) {
+					users {
+						Name @skip(if: $skipName)
+						Age @include(if: $withAge)
+					}
+				}`,
+				Variables: map[string]any{
+					"skipName": true,
+					"withAge":  true,
+				},
+				Results: []map[string]any{
+					{
+						"Age": uint64(21),
+					},
+				},
+			},
+		},
+	}
+
+	testUtils.ExecuteTestCase(t, []string{"users"}, test)
+}
+
+func TestQuerySimpleWithDirectiveWithDefaultVariableValue(t *testing.T) {
+	test := testUtils.TestCase{
+		Description: "Simple query with skip directive with the default value of a variable",
+		Actions: []any{
+			testUtils.SchemaUpdate{
+				Schema: userCollectionGQLSchema,
+			},
+			testUtils.CreateDoc{
+				Doc: `{
+					"Name": "John",
+					"Age": 21
+				}`,
+			},
+			testUtils.Request{
+				Request: `query($skipAge: Boolean = true) {
+					users {
+						Name
+						Age @skip(if: $skipAge)
+					}
+				}`,
+				Results: []map[string]any{
+					{
+						"Name": "John",
+					},
+				},
+			},
+		},
+	}
+
+	testUtils.ExecuteTestCase(t, []string{"users"}, test)
+}
+
+func TestQuerySimpleWithDirectiveWithMissingVariableValue(t *testing.T) {
+	test := testUtils.TestCase{
+		Description: "Simple query with skip directive without a value for its variable",
+		Actions: []any{
+			testUtils.SchemaUpdate{
+				Schema: userCollectionGQLSchema,
+			},
+			testUtils.Request{
+				Request: `query($skipAge: Boolean!) {
+					users {
+						Name
+						Age @skip(if: $skipAge)
+					}
+				}`,
+				ExpectedError: "no value was given for the variable",
+			},
+		},
+	}
+
+	testUtils.ExecuteTestCase(t, []string{"users"}, test)
+}
+
+func TestQuerySimpleWithDirectiveWithInvalidVariableValue(t *testing.T) {
+	test := testUtils.TestCase{
+		Description: "Simple query with skip directive with a non boolean value for its variable",
+		Actions: []any{
+			testUtils.SchemaUpdate{
+				Schema: userCollectionGQLSchema,
+			},
+			testUtils.Request{
+				Request: `query($skipAge: Boolean!) {
+					users {
+						Name
+						Age @skip(if: $skipAge)
+					}
+				}`,
+				Variables: map[string]any{
+					"skipAge": "yes",
+				},
+				ExpectedError: "the if argument of the directive must be a boolean",
+			},
+		},
+	}
+
+	testUtils.ExecuteTestCase(t, []string{"users"}, test)
+}
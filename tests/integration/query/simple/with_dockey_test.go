@@ -92,3 +92,26 @@ func TestQuerySimpleWithDocKeyFilter(t *testing.T) {
 		executeTestCase(t, test)
 	}
 }
+
+func TestQuerySimpleReturnsNothingGivenDocKeyPrefix(t *testing.T) {
+	test := testUtils.RequestTestCase{
+		Description: "Simple query with a dockey that is only a prefix of the key of a document",
+		Request: `query {
+					users(dockey: "bae-52b9170d") {
+						Name
+					}
+				}`,
+		Docs: map[int][]string{
+			0: {
+				// bae-52b9170d-b77a-5887-b877-cbdbb99b009f
+				`{
+					"Name": "John",
+					"Age": 21
+				}`,
+			},
+		},
+		Results: []map[string]any{},
+	}
+
+	executeTestCase(t, test)
+}
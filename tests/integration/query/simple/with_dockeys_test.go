@@ -150,3 +150,26 @@ func TestQuerySimpleReturnsNothinGivenEmptyDocKeysFilter(t *testing.T) {
 
 	executeTestCase(t, test)
 }
+
+func TestQuerySimpleReturnsNothingGivenDocKeysPrefix(t *testing.T) {
+	test := testUtils.RequestTestCase{
+		Description: "Simple query with dockeys that are only prefixes of the key of a document",
+		Request: `query {
+					users(dockeys: ["bae-", "bae-52b9170d"]) {
+						Name
+					}
+				}`,
+		Docs: map[int][]string{
+			0: {
+				// bae-52b9170d-b77a-5887-b877-cbdbb99b009f
+				`{
+					"Name": "John",
+					"Age": 21
+				}`,
+			},
+		},
+		Results: []map[string]any{},
+	}
+
+	executeTestCase(t, test)
+}
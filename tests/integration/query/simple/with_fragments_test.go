@@ -0,0 +1,249 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package simple
+
+import (
+	"testing"
+
+	testUtils "github.com/sourcenetwork/defradb/tests/integration"
+)
+
+func TestQuerySimpleWithFragment(t *testing.T) {
+	test := testUtils.TestCase{
+		Description: "Simple query with a fragment spread",
+		Actions: []any{
+			testUtils.SchemaUpdate{
+				Schema: userCollectionGQLSchema,
+			},
+			testUtils.CreateDoc{
+				Doc: `{
+					"Name": "John",
+					"Age": 21
+				}`,
+			},
+			testUtils.Request{
+				Request: `query {
+					users {
+						...UserFields
+					}
+				}
+
+				fragment UserFields on users {
+					Name
+					Age
+				}`,
+				Results: []map[string]any{
+					{
+						"Name": "John",
+						"Age":  uint64(21),
+					},
+				},
+			},
+		},
+	}
+
+	testUtils.ExecuteTestCase(t, []string{"users"}, test)
+}
+
+func TestQuerySimpleWithNestedFragments(t *testing.T) {
+	test := testUtils.TestCase{
+		Description: "Simple query with a fragment spread within a fragment",
+		Actions: []any{
+			testUtils.SchemaUpdate{
+				Schema: userCollectionGQLSchema,
+			},
+			testUtils.CreateDoc{
+				Doc: `{
+					"Name": "John",
+					"Age": 21
+				}`,
+			},
+			testUtils.Request{
+				Request: `query {
+					users {
+						...UserFields
+					}
+				}
+
+				fragment UserFields on users {
+					Name
+					...UserAge
+				}
+
+				fragment UserAge on users {
+					Age
+				}`,
+				Results: []map[string]any{
+					{
+						"Name": "John",
+						"Age":  uint64(21),
+					},
+				},
+			},
+		},
+	}
+
+	testUtils.ExecuteTestCase(t, []string{"users"}, test)
+}
+
+func TestQuerySimpleWithFragmentAndOverlappingFields(t *testing.T) {
+	test := testUtils.TestCase{
+		Description: "Simple query with a fragment spread selecting fields also selected by the query",
+		Actions: []any{
+			testUtils.SchemaUpdate{
+				Schema: userCollectionGQLSchema,
+			},
+			testUtils.CreateDoc{
+				Doc: `{
+					"Name": "John",
+					"Age": 21
+				}`,
+			},
+			testUtils.Request{
+				Request: `query {
+					users {
+						Name
+						_version {
+							cid
+						}
+						...UserFields
+					}
+				}
+
+				fragment UserFields on users {
+					Name
+					Age
+					_version {
+						height
+					}
+				}`,
+				Results: []map[string]any{
+					{
+						"Name": "John",
+						"Age":  uint64(21),
+						"_version": []map[string]any{
+							{
+								"cid":    "bafybeiaahzxsfz55nuqnsll42wxrbdjmy5si222l4ydbrwb53tpxnzdmwq",
+								"height": int64(1),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	testUtils.ExecuteTestCase(t, []string{"users"}, test)
+}
+
+func TestQuerySimpleWithInlineFragment(t *testing.T) {
+	test := testUtils.TestCase{
+		Description: "Simple query with inline fragments",
+		Actions: []any{
+			testUtils.SchemaUpdate{
+				Schema: userCollectionGQLSchema,
+			},
+			testUtils.CreateDoc{
+				Doc: `{
+					"Name": "John",
+					"Age": 21
+				}`,
+			},
+			testUtils.Request{
+				Request: `query {
+					users {
+						... on users {
+							Name
+						}
+						... {
+							Age
+						}
+					}
+				}`,
+				Results: []map[string]any{
+					{
+						"Name": "John",
+						"Age":  uint64(21),
+					},
+				},
+			},
+		},
+	}
+
+	testUtils.ExecuteTestCase(t, []string{"users"}, test)
+}
+
+func TestQuerySimpleWithSkippedFragments(t *testing.T) {
+	test := testUtils.TestCase{
+		Description: "Simple query with fragments skipped by directives",
+		Actions: []any{
+			testUtils.SchemaUpdate{
+				Schema: userCollectionGQLSchema,
+			},
+			testUtils.CreateDoc{
+				Doc: `{
+					"Name": "John",
+					"Age": 21
+				}`,
+			},
+			testUtils.Request{
+				Request: `query($withAge: Boolean!) {
+					users {
+						...UserName
+						... @include(if: $withAge) {
+							Age
+						}
+						...UserVerified @skip(if: true)
+					}
+				}
+
+				fragment UserName on users {
+					Name
+				}
+
+				fragment UserVerified on users {
+					Verified
+				}`,
+				Variables: map[string]any{
+					"withAge": false,
+				},
+				Results: []map[string]any{
+					{
+						"Name": "John",
+					},
+				},
+			},
+		},
+	}
+
+	testUtils.ExecuteTestCase(t, []string{"users"}, test)
+}
+
+func TestQuerySimpleWithUnknownFragment(t *testing.T) {
+	test := testUtils.TestCase{
+		Description: "Simple query with a spread of an undefined fragment",
+		Actions: []any{
+			testUtils.SchemaUpdate{
+				Schema: userCollectionGQLSchema,
+			},
+			testUtils.Request{
+				Request: `query {
+					users {
+						...UserFields
+					}
+				}`,
+				ExpectedError: `Unknown fragment "UserFields".`,
+			},
+		},
+	}
+
+	testUtils.ExecuteTestCase(t, []string{"users"}, test)
+}
@@ -0,0 +1,118 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package simple
+
+import (
+	"testing"
+
+	testUtils "github.com/sourcenetwork/defradb/tests/integration"
+)
+
+func TestQuerySimpleWithGroupByStringWithCountSumAndAverage(t *testing.T) {
+	test := testUtils.RequestTestCase{
+		Description: "Simple query with group by string, with count, sum and average of the group",
+		Request: `query {
+					users(groupBy: [Name]) {
+						Name
+						_count(_group: {})
+						verified: _count(_group: {filter: {Verified: {_eq: true}}})
+						_sum(_group: {field: HeightM})
+						_avg(_group: {field: Age})
+					}
+				}`,
+		Docs: map[int][]string{
+			0: {
+				`{
+					"Name": "John",
+					"Age": 25,
+					"HeightM": 1.5,
+					"Verified": true
+				}`,
+				`{
+					"Name": "John",
+					"Age": 32,
+					"HeightM": 1.75,
+					"Verified": false
+				}`,
+				`{
+					"Name": "John",
+					"Verified": true
+				}`,
+				`{
+					"Name": "Alice",
+					"Age": 19,
+					"Verified": false
+				}`,
+			},
+		},
+		Results: []map[string]any{
+			{
+				"Name":     "John",
+				"_count":   3,
+				"verified": 2,
+				"_sum":     3.25,
+				"_avg":     float64(28.5),
+			},
+			{
+				"Name":     "Alice",
+				"_count":   1,
+				"verified": 0,
+				"_sum":     float64(0),
+				"_avg":     float64(19),
+			},
+		},
+	}
+
+	executeTestCase(t, test)
+}
+
+func TestQuerySimpleWithGroupByStringWithCountAndRenderedGroup(t *testing.T) {
+	test := testUtils.RequestTestCase{
+		Description: "Simple query with group by string, with count of the group and the group rendered",
+		Request: `query {
+					users(groupBy: [Name]) {
+						Name
+						_count(_group: {})
+						_group {
+							Age
+						}
+					}
+				}`,
+		Docs: map[int][]string{
+			0: {
+				`{
+					"Name": "John",
+					"Age": 25
+				}`,
+				`{
+					"Name": "John",
+					"Age": 32
+				}`,
+			},
+		},
+		Results: []map[string]any{
+			{
+				"Name":   "John",
+				"_count": 2,
+				"_group": []map[string]any{
+					{
+						"Age": uint64(32),
+					},
+					{
+						"Age": uint64(25),
+					},
+				},
+			},
+		},
+	}
+
+	executeTestCase(t, test)
+}
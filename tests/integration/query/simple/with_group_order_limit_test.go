@@ -0,0 +1,289 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package simple
+
+import (
+	"testing"
+
+	testUtils "github.com/sourcenetwork/defradb/tests/integration"
+)
+
+func TestQuerySimpleWithGroupByNumberWithGroupOrderDescendingAndLimit(t *testing.T) {
+	test := testUtils.RequestTestCase{
+		Description: "Simple query with group by number, and child order descending with limit",
+		Request: `query {
+					users(groupBy: [Age]) {
+						Age
+						_group(order: {Name: DESC}, limit: 2) {
+							Name
+						}
+					}
+				}`,
+		Docs: map[int][]string{
+			0: {
+				`{
+					"Name": "John",
+					"Age": 32
+				}`,
+				`{
+					"Name": "Bob",
+					"Age": 32
+				}`,
+				`{
+					"Name": "Zed",
+					"Age": 32
+				}`,
+				`{
+					"Name": "Carlo",
+					"Age": 32
+				}`,
+				`{
+					"Name": "Alice",
+					"Age": 19
+				}`,
+				`{
+					"Name": "Zoe",
+					"Age": 19
+				}`,
+				`{
+					"Name": "Amy",
+					"Age": 19
+				}`,
+			},
+		},
+		Results: []map[string]any{
+			{
+				"Age": uint64(32),
+				"_group": []map[string]any{
+					{
+						"Name": "Zed",
+					},
+					{
+						"Name": "John",
+					},
+				},
+			},
+			{
+				"Age": uint64(19),
+				"_group": []map[string]any{
+					{
+						"Name": "Zoe",
+					},
+					{
+						"Name": "Amy",
+					},
+				},
+			},
+		},
+	}
+
+	executeTestCase(t, test)
+}
+
+func TestQuerySimpleWithGroupByNumberWithGroupOrderAscendingAndLimitAndOffset(t *testing.T) {
+	test := testUtils.RequestTestCase{
+		Description: "Simple query with group by number, and child order ascending with limit and offset",
+		Request: `query {
+					users(groupBy: [Age]) {
+						Age
+						_group(order: {Name: ASC}, limit: 2, offset: 1) {
+							Name
+						}
+					}
+				}`,
+		Docs: map[int][]string{
+			0: {
+				`{
+					"Name": "John",
+					"Age": 32
+				}`,
+				`{
+					"Name": "Bob",
+					"Age": 32
+				}`,
+				`{
+					"Name": "Zed",
+					"Age": 32
+				}`,
+				`{
+					"Name": "Carlo",
+					"Age": 32
+				}`,
+				`{
+					"Name": "Alice",
+					"Age": 19
+				}`,
+				`{
+					"Name": "Zoe",
+					"Age": 19
+				}`,
+			},
+		},
+		Results: []map[string]any{
+			{
+				"Age": uint64(32),
+				"_group": []map[string]any{
+					{
+						"Name": "Carlo",
+					},
+					{
+						"Name": "John",
+					},
+				},
+			},
+			{
+				"Age": uint64(19),
+				"_group": []map[string]any{
+					{
+						"Name": "Zoe",
+					},
+				},
+			},
+		},
+	}
+
+	executeTestCase(t, test)
+}
+
+func TestQuerySimpleWithGroupByNumberWithGroupOrderWithEqualValuesAndLimit(t *testing.T) {
+	test := testUtils.RequestTestCase{
+		Description: "Simple query with group by number, and child order with equal values and limit",
+		Request: `query {
+					users(groupBy: [Age]) {
+						Age
+						_group(order: {Verified: DESC}, limit: 2) {
+							Name
+							Verified
+						}
+					}
+				}`,
+		Docs: map[int][]string{
+			0: {
+				`{
+					"Name": "John",
+					"Age": 32,
+					"Verified": true
+				}`,
+				`{
+					"Name": "Bob",
+					"Age": 32,
+					"Verified": false
+				}`,
+				`{
+					"Name": "Zed",
+					"Age": 32,
+					"Verified": true
+				}`,
+				`{
+					"Name": "Carlo",
+					"Age": 32,
+					"Verified": true
+				}`,
+			},
+		},
+		Results: []map[string]any{
+			{
+				"Age": uint64(32),
+				"_group": []map[string]any{
+					{
+						"Name":     "Zed",
+						"Verified": true,
+					},
+					{
+						"Name":     "John",
+						"Verified": true,
+					},
+				},
+			},
+		},
+	}
+
+	executeTestCase(t, test)
+}
+
+func TestQuerySimpleWithGroupByNumberWithGroupOrderListAndLimit(t *testing.T) {
+	test := testUtils.RequestTestCase{
+		Description: "Simple query with group by number and order list, and child order list of mixed directions with limit",
+		Request: `query {
+					users(groupBy: [Age], order: [{Age: DESC}]) {
+						Age
+						_group(order: [{Verified: DESC}, {Name: ASC}], limit: 2) {
+							Name
+						}
+					}
+				}`,
+		Docs: map[int][]string{
+			0: {
+				`{
+					"Name": "John",
+					"Age": 32,
+					"Verified": true
+				}`,
+				`{
+					"Name": "Bob",
+					"Age": 32,
+					"Verified": false
+				}`,
+				`{
+					"Name": "Zed",
+					"Age": 32,
+					"Verified": true
+				}`,
+				`{
+					"Name": "Carlo",
+					"Age": 32,
+					"Verified": true
+				}`,
+				`{
+					"Name": "Alice",
+					"Age": 19,
+					"Verified": false
+				}`,
+				`{
+					"Name": "Zoe",
+					"Age": 19,
+					"Verified": true
+				}`,
+				`{
+					"Name": "Amy",
+					"Age": 19,
+					"Verified": false
+				}`,
+			},
+		},
+		Results: []map[string]any{
+			{
+				"Age": uint64(32),
+				"_group": []map[string]any{
+					{
+						"Name": "Carlo",
+					},
+					{
+						"Name": "John",
+					},
+				},
+			},
+			{
+				"Age": uint64(19),
+				"_group": []map[string]any{
+					{
+						"Name": "Zoe",
+					},
+					{
+						"Name": "Alice",
+					},
+				},
+			},
+		},
+	}
+
+	executeTestCase(t, test)
+}
@@ -0,0 +1,103 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package simple
+
+import (
+	"testing"
+
+	testUtils "github.com/sourcenetwork/defradb/tests/integration"
+)
+
+func TestQuerySimpleWithHistory(t *testing.T) {
+	test := testUtils.TestCase{
+		Description: "Simple query with the history of a field",
+		Actions: []any{
+			testUtils.SchemaUpdate{
+				Schema: userCollectionGQLSchema,
+			},
+			testUtils.CreateDoc{
+				Doc: `{
+					"Name": "John",
+					"Age": 21
+				}`,
+			},
+			testUtils.UpdateDoc{
+				Doc: `{
+					"Age": 22
+				}`,
+			},
+			testUtils.UpdateDoc{
+				Doc: `{
+					"Name": "Johnny"
+				}`,
+			},
+			testUtils.Request{
+				Request: `query {
+					users {
+						Name
+						_history(field: Age) {
+							value
+							cid
+							height
+						}
+					}
+				}`,
+				Results: []map[string]any{
+					{
+						"Name": "Johnny",
+						"_history": []map[string]any{
+							{
+								"value":  uint64(22),
+								"cid":    "bafybeibxwj4x3d4i5awkg32bhxvhdxc2onfmohzyy6stjeejgexw52vgue",
+								"height": int64(2),
+							},
+							{
+								"value":  uint64(21),
+								"cid":    testUtils.DocCreateCID{FieldName: "Age"},
+								"height": int64(1),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	testUtils.ExecuteTestCase(t, []string{"users"}, test)
+}
+
+func TestQuerySimpleWithHistoryOfKey(t *testing.T) {
+	test := testUtils.TestCase{
+		Description: "Simple query with the history of the key field",
+		Actions: []any{
+			testUtils.SchemaUpdate{
+				Schema: userCollectionGQLSchema,
+			},
+			testUtils.CreateDoc{
+				Doc: `{
+					"Name": "John"
+				}`,
+			},
+			testUtils.Request{
+				Request: `query {
+					users {
+						_history(field: _key) {
+							value
+						}
+					}
+				}`,
+				ExpectedError: "history can only be requested for scalar fields",
+			},
+		},
+	}
+
+	testUtils.ExecuteTestCase(t, []string{"users"}, test)
+}
@@ -0,0 +1,275 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package simple
+
+import (
+	"testing"
+
+	testUtils "github.com/sourcenetwork/defradb/tests/integration"
+)
+
+const accountCollectionGQLSchema = `
+	type Accounts {
+		Name: String
+		Level: Int32
+		Balance: UInt64
+		Points: Int
+	}
+`
+
+func TestQuerySimpleWithInt32AndUInt64Fields(t *testing.T) {
+	test := testUtils.TestCase{
+		Description: "Simple query with Int32 and UInt64 fields at the bounds of their range",
+		Actions: []any{
+			testUtils.SchemaUpdate{
+				Schema: accountCollectionGQLSchema,
+			},
+			testUtils.CreateDoc{
+				Doc: `{
+					"Name": "John",
+					"Level": -2147483648,
+					"Balance": 18446744073709551615
+				}`,
+			},
+			testUtils.Request{
+				Request: `query {
+					Accounts {
+						Name
+						Level
+						Balance
+					}
+				}`,
+				Results: []map[string]any{
+					{
+						"Name":    "John",
+						"Level":   int64(-2147483648),
+						"Balance": uint64(18446744073709551615),
+					},
+				},
+			},
+		},
+	}
+
+	testUtils.ExecuteTestCase(t, []string{"Accounts"}, test)
+}
+
+func TestQuerySimpleWithInt32FieldOutOfRange(t *testing.T) {
+	test := testUtils.TestCase{
+		Description: "Simple create with an Int32 field value out of the range of int32 values",
+		Actions: []any{
+			testUtils.SchemaUpdate{
+				Schema: accountCollectionGQLSchema,
+			},
+			testUtils.CreateDoc{
+				Doc: `{
+					"Name": "John",
+					"Level": 2147483648
+				}`,
+				ExpectedError: "the value is not a whole number in the range of the kind of the field",
+			},
+		},
+	}
+
+	testUtils.ExecuteTestCase(t, []string{"Accounts"}, test)
+}
+
+func TestQuerySimpleWithNegativeUInt64Field(t *testing.T) {
+	test := testUtils.TestCase{
+		Description: "Simple create with a negative UInt64 field value",
+		Actions: []any{
+			testUtils.SchemaUpdate{
+				Schema: accountCollectionGQLSchema,
+			},
+			testUtils.CreateDoc{
+				Doc: `{
+					"Name": "John",
+					"Balance": -1
+				}`,
+				ExpectedError: "the value is not a whole number in the range of the kind of the field",
+			},
+		},
+	}
+
+	testUtils.ExecuteTestCase(t, []string{"Accounts"}, test)
+}
+
+func TestQuerySimpleWithUpdateOfInt32FieldOutOfRange(t *testing.T) {
+	test := testUtils.TestCase{
+		Description: "Simple update mutation with an Int32 field value out of the range of int32 values",
+		Actions: []any{
+			testUtils.SchemaUpdate{
+				Schema: accountCollectionGQLSchema,
+			},
+			testUtils.CreateDoc{
+				Doc: `{
+					"Name": "John",
+					"Level": 1
+				}`,
+			},
+			testUtils.Request{
+				Request: `mutation {
+					update_Accounts(data: "{\"Level\": 2147483648}") {
+						Level
+					}
+				}`,
+				ExpectedError: "the value is not a whole number in the range of the kind of the field",
+			},
+		},
+	}
+
+	testUtils.ExecuteTestCase(t, []string{"Accounts"}, test)
+}
+
+func TestQuerySimpleWithUInt64FilterAboveInt64Range(t *testing.T) {
+	test := testUtils.TestCase{
+		Description: "Simple query with a filter on an UInt64 field above the range of int64 values",
+		Actions: []any{
+			testUtils.SchemaUpdate{
+				Schema: accountCollectionGQLSchema,
+			},
+			testUtils.CreateDoc{
+				Doc: `{
+					"Name": "John",
+					"Balance": 18446744073709551615
+				}`,
+			},
+			testUtils.CreateDoc{
+				Doc: `{
+					"Name": "Bob",
+					"Balance": 9223372036854775807
+				}`,
+			},
+			testUtils.Request{
+				Request: `query {
+					Accounts(filter: {Balance: {_gt: 9223372036854775808}}) {
+						Name
+					}
+				}`,
+				Results: []map[string]any{
+					{
+						"Name": "John",
+					},
+				},
+			},
+			testUtils.Request{
+				Request: `query {
+					Accounts(filter: {Balance: {_eq: 18446744073709551615}}) {
+						Name
+					}
+				}`,
+				Results: []map[string]any{
+					{
+						"Name": "John",
+					},
+				},
+			},
+		},
+	}
+
+	testUtils.ExecuteTestCase(t, []string{"Accounts"}, test)
+}
+
+func TestQuerySimpleWithSumOfUInt64AboveInt64Range(t *testing.T) {
+	test := testUtils.TestCase{
+		Description: "Simple query with the sum of an UInt64 field above the range of int64 values",
+		Actions: []any{
+			testUtils.SchemaUpdate{
+				Schema: accountCollectionGQLSchema,
+			},
+			testUtils.CreateDoc{
+				Doc: `{
+					"Name": "John",
+					"Balance": 9223372036854775807
+				}`,
+			},
+			testUtils.CreateDoc{
+				Doc: `{
+					"Name": "Bob",
+					"Balance": 10
+				}`,
+			},
+			testUtils.Request{
+				Request: `query {
+					_sum(Accounts: {field: Balance})
+				}`,
+				Results: []map[string]any{
+					{
+						"_sum": uint64(9223372036854775817),
+					},
+				},
+			},
+		},
+	}
+
+	testUtils.ExecuteTestCase(t, []string{"Accounts"}, test)
+}
+
+func TestQuerySimpleWithSumOfUInt64Overflowing(t *testing.T) {
+	test := testUtils.TestCase{
+		Description: "Simple query with the sum of an UInt64 field overflowing the range of uint64 values",
+		Actions: []any{
+			testUtils.SchemaUpdate{
+				Schema: accountCollectionGQLSchema,
+			},
+			testUtils.CreateDoc{
+				Doc: `{
+					"Name": "John",
+					"Balance": 18446744073709551615
+				}`,
+			},
+			testUtils.CreateDoc{
+				Doc: `{
+					"Name": "Bob",
+					"Balance": 1
+				}`,
+			},
+			testUtils.Request{
+				Request: `query {
+					_sum(Accounts: {field: Balance})
+				}`,
+				ExpectedError: "the sum of the integers overflows the range of its type",
+			},
+		},
+	}
+
+	testUtils.ExecuteTestCase(t, []string{"Accounts"}, test)
+}
+
+func TestQuerySimpleWithSumOfIntOverflowing(t *testing.T) {
+	test := testUtils.TestCase{
+		Description: "Simple query with the sum of an Int field overflowing the range of int64 values",
+		Actions: []any{
+			testUtils.SchemaUpdate{
+				Schema: accountCollectionGQLSchema,
+			},
+			testUtils.CreateDoc{
+				Doc: `{
+					"Name": "John",
+					"Points": 9223372036854775807
+				}`,
+			},
+			testUtils.CreateDoc{
+				Doc: `{
+					"Name": "Bob",
+					"Points": 1
+				}`,
+			},
+			testUtils.Request{
+				Request: `query {
+					_sum(Accounts: {field: Points})
+				}`,
+				ExpectedError: "the sum of the integers overflows the range of its type",
+			},
+		},
+	}
+
+	testUtils.ExecuteTestCase(t, []string{"Accounts"}, test)
+}
@@ -0,0 +1,148 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package simple
+
+import (
+	"testing"
+
+	testUtils "github.com/sourcenetwork/defradb/tests/integration"
+)
+
+func TestQuerySimpleWithMeta(t *testing.T) {
+	test := testUtils.TestCase{
+		Description: "Simple query with the metadata of a created document",
+		Actions: []any{
+			testUtils.SchemaUpdate{
+				Schema: userCollectionGQLSchema,
+			},
+			testUtils.CreateDoc{
+				Doc: `{
+					"Name": "John",
+					"Age": 21
+				}`,
+			},
+			testUtils.Request{
+				Request: `query {
+					users {
+						Name
+						_meta {
+							heads
+							schemaVersionId
+							deleted
+							deltaCount
+						}
+					}
+				}`,
+				Results: []map[string]any{
+					{
+						"Name": "John",
+						"_meta": map[string]any{
+							"heads":           []string{"bafybeiaahzxsfz55nuqnsll42wxrbdjmy5si222l4ydbrwb53tpxnzdmwq"},
+							"schemaVersionId": "bafkreicl25inntuxlx4fbbcwb2tzbja4iyvujjwhk25pen7ljjdzcpgup4",
+							"deleted":         false,
+							"deltaCount":      int64(1),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	testUtils.ExecuteTestCase(t, []string{"users"}, test)
+}
+
+func TestQuerySimpleWithMetaAfterUpdates(t *testing.T) {
+	test := testUtils.TestCase{
+		Description: "Simple query with the metadata of an updated document",
+		Actions: []any{
+			testUtils.SchemaUpdate{
+				Schema: userCollectionGQLSchema,
+			},
+			testUtils.CreateDoc{
+				Doc: `{
+					"Name": "John",
+					"Age": 21
+				}`,
+			},
+			testUtils.UpdateDoc{
+				Doc: `{
+					"Age": 22
+				}`,
+			},
+			testUtils.UpdateDoc{
+				Doc: `{
+					"Name": "Johnny"
+				}`,
+			},
+			testUtils.Request{
+				Request: `query {
+					users {
+						Name
+						_meta {
+							deleted
+							deltaCount
+						}
+					}
+				}`,
+				Results: []map[string]any{
+					{
+						"Name": "Johnny",
+						"_meta": map[string]any{
+							"deleted":    false,
+							"deltaCount": int64(3),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	testUtils.ExecuteTestCase(t, []string{"users"}, test)
+}
+
+func TestQuerySimpleWithMetaOfDeletedDocument(t *testing.T) {
+	test := testUtils.TestCase{
+		Description: "Simple query with the metadata of a deleted document",
+		Actions: []any{
+			testUtils.SchemaUpdate{
+				Schema: userCollectionGQLSchema,
+			},
+			testUtils.CreateDoc{
+				Doc: `{
+					"Name": "John"
+				}`,
+			},
+			testUtils.DeleteDoc{},
+			testUtils.Request{
+				Request: `query {
+					users(showDeleted: true) {
+						_deleted
+						_meta {
+							deleted
+							deltaCount
+						}
+					}
+				}`,
+				Results: []map[string]any{
+					{
+						"_deleted": true,
+						"_meta": map[string]any{
+							"deleted":    true,
+							"deltaCount": int64(2),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	testUtils.ExecuteTestCase(t, []string{"users"}, test)
+}
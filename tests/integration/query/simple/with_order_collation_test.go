@@ -0,0 +1,268 @@
+// Copyright 2023 Democratized Data Foundation
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package simple
+
+import (
+	"testing"
+
+	testUtils "github.com/sourcenetwork/defradb/tests/integration"
+)
+
+var collationTestDocs = map[int][]string{
+	0: {
+		`{
+			"Name": "Bob",
+			"Age": 32
+		}`,
+		`{
+			"Name": "Émile",
+			"Age": 19
+		}`,
+		`{
+			"Name": "alice",
+			"Age": 21
+		}`,
+		`{
+			"Name": "carlo",
+			"Age": 55
+		}`,
+	},
+}
+
+func TestQuerySimpleWithOrderWithoutCollation(t *testing.T) {
+	test := testUtils.RequestTestCase{
+		Description: "Simple query with order, without collation, strings ordered by bytes",
+		Request: `query {
+					users(order: {Name: ASC}) {
+						Name
+					}
+				}`,
+		Docs: collationTestDocs,
+		Results: []map[string]any{
+			{"Name": "Bob"},
+			{"Name": "alice"},
+			{"Name": "carlo"},
+			{"Name": "Émile"},
+		},
+	}
+
+	executeTestCase(t, test)
+}
+
+func TestQuerySimpleWithOrderWithDefaultCollation(t *testing.T) {
+	test := testUtils.RequestTestCase{
+		Description: "Simple query with order and the root unicode collation",
+		Request: `query {
+					users(order: {Name: ASC}, collation: {}) {
+						Name
+					}
+				}`,
+		Docs: collationTestDocs,
+		Results: []map[string]any{
+			{"Name": "alice"},
+			{"Name": "Bob"},
+			{"Name": "carlo"},
+			{"Name": "Émile"},
+		},
+	}
+
+	executeTestCase(t, test)
+}
+
+func TestQuerySimpleWithOrderDescWithDefaultCollation(t *testing.T) {
+	test := testUtils.RequestTestCase{
+		Description: "Simple query with order descending and the root unicode collation",
+		Request: `query {
+					users(order: {Name: DESC}, collation: {}) {
+						Name
+					}
+				}`,
+		Docs: collationTestDocs,
+		Results: []map[string]any{
+			{"Name": "Émile"},
+			{"Name": "carlo"},
+			{"Name": "Bob"},
+			{"Name": "alice"},
+		},
+	}
+
+	executeTestCase(t, test)
+}
+
+func TestQuerySimpleWithOrderWithLocaleCollation(t *testing.T) {
+	test := testUtils.RequestTestCase{
+		Description: "Simple query with order and the collation of a locale",
+		Request: `query {
+					users(order: {Name: ASC}, collation: {locale: "sv"}) {
+						Name
+					}
+				}`,
+		Docs: map[int][]string{
+			0: {
+				`{
+					"Name": "Ärla",
+					"Age": 21
+				}`,
+				`{
+					"Name": "Zoe",
+					"Age": 32
+				}`,
+				`{
+					"Name": "Anna",
+					"Age": 19
+				}`,
+			},
+		},
+		// In Swedish, Ä is a letter of its own that comes after Z.
+		Results: []map[string]any{
+			{"Name": "Anna"},
+			{"Name": "Zoe"},
+			{"Name": "Ärla"},
+		},
+	}
+
+	executeTestCase(t, test)
+}
+
+func TestQuerySimpleWithOrderWithCaseInsensitiveCollation(t *testing.T) {
+	test := testUtils.RequestTestCase{
+		Description: "Simple query with order and a case-insensitive collation",
+		Request: `query {
+					users(order: {Name: ASC, Age: ASC}, collation: {caseInsensitive: true}) {
+						Name
+						Age
+					}
+				}`,
+		Docs: map[int][]string{
+			0: {
+				`{
+					"Name": "john",
+					"Age": 32
+				}`,
+				`{
+					"Name": "John",
+					"Age": 21
+				}`,
+				`{
+					"Name": "Bob",
+					"Age": 19
+				}`,
+			},
+		},
+		// The names differing only by case are equal, so they are ordered by age.
+		Results: []map[string]any{
+			{
+				"Name": "Bob",
+				"Age":  uint64(19),
+			},
+			{
+				"Name": "John",
+				"Age":  uint64(21),
+			},
+			{
+				"Name": "john",
+				"Age":  uint64(32),
+			},
+		},
+	}
+
+	executeTestCase(t, test)
+}
+
+func TestQuerySimpleWithOrderWithNumericCollation(t *testing.T) {
+	test := testUtils.RequestTestCase{
+		Description: "Simple query with order and a numeric collation",
+		Request: `query {
+					users(order: {Name: ASC}, collation: {numeric: true}) {
+						Name
+					}
+				}`,
+		Docs: map[int][]string{
+			0: {
+				`{
+					"Name": "file10",
+					"Age": 21
+				}`,
+				`{
+					"Name": "file2",
+					"Age": 32
+				}`,
+				`{
+					"Name": "file1",
+					"Age": 19
+				}`,
+			},
+		},
+		Results: []map[string]any{
+			{"Name": "file1"},
+			{"Name": "file2"},
+			{"Name": "file10"},
+		},
+	}
+
+	executeTestCase(t, test)
+}
+
+func TestQuerySimpleWithOrderWithInvalidCollationLocale(t *testing.T) {
+	test := testUtils.RequestTestCase{
+		Description: "Simple query with order and a collation of an invalid locale",
+		Request: `query {
+					users(order: {Name: ASC}, collation: {locale: "not a locale"}) {
+						Name
+					}
+				}`,
+		Docs:          collationTestDocs,
+		ExpectedError: "the locale of the collation must be a BCP 47 language tag",
+	}
+
+	executeTestCase(t, test)
+}
+
+func TestQuerySimpleWithGroupOrderLimitWithCaseInsensitiveCollation(t *testing.T) {
+	test := testUtils.RequestTestCase{
+		Description: "Simple query with group, child order, limit and a case-insensitive collation",
+		Request: `query {
+					users(groupBy: [Age]) {
+						Age
+						_group(order: {Name: ASC}, limit: 2, collation: {caseInsensitive: true}) {
+							Name
+						}
+					}
+				}`,
+		Docs: map[int][]string{
+			0: {
+				`{
+					"Name": "Carlo",
+					"Age": 21
+				}`,
+				`{
+					"Name": "Bob",
+					"Age": 21
+				}`,
+				`{
+					"Name": "alice",
+					"Age": 21
+				}`,
+			},
+		},
+		Results: []map[string]any{
+			{
+				"Age": uint64(21),
+				"_group": []map[string]any{
+					{"Name": "alice"},
+					{"Name": "Bob"},
+				},
+			},
+		},
+	}
+
+	executeTestCase(t, test)
+}
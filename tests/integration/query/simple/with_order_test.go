@@ -335,3 +335,251 @@ func TestQuerySimpleWithNumericOrderDescendingAndBooleanOrderAscending(t *testin
 
 	executeTestCase(t, test)
 }
+
+func TestQuerySimpleWithOrderOfEqualValuesByDocKey(t *testing.T) {
+	test := testUtils.RequestTestCase{
+		Description: "Simple query with order, limit and offset, equal values ordered by dockey",
+		Request: `query {
+					users(order: {Age: DESC}, limit: 2, offset: 1) {
+						_key
+						Name
+					}
+				}`,
+		Docs: map[int][]string{
+			0: {
+				`{
+					"Name": "John",
+					"Age": 21
+				}`,
+				`{
+					"Name": "Bob",
+					"Age": 32
+				}`,
+				`{
+					"Name": "Carlo",
+					"Age": 21
+				}`,
+				`{
+					"Name": "Alice",
+					"Age": 21
+				}`,
+			},
+		},
+		// The documents of the same age are ordered by dockey, regardless of the order in
+		// which they were scanned, so the pages are the same on every node.
+		Results: []map[string]any{
+			{
+				"_key": "bae-52b9170d-b77a-5887-b877-cbdbb99b009f",
+				"Name": "John",
+			},
+			{
+				"_key": "bae-b84bcef5-3646-5695-a462-ed82d1bc9931",
+				"Name": "Alice",
+			},
+		},
+	}
+
+	executeTestCase(t, test)
+}
+
+func TestQuerySimpleWithOrderListOfMixedDirections(t *testing.T) {
+	test := testUtils.RequestTestCase{
+		Description: "Simple query with order list, numeric descending then string ascending",
+		Request: `query {
+					users(order: [{Age: DESC}, {Name: ASC}]) {
+						Name
+						Age
+					}
+				}`,
+		Docs: map[int][]string{
+			0: {
+				`{
+					"Name": "John",
+					"Age": 21
+				}`,
+				`{
+					"Name": "Bob",
+					"Age": 32
+				}`,
+				`{
+					"Name": "Carlo",
+					"Age": 21
+				}`,
+				`{
+					"Name": "Alice",
+					"Age": 32
+				}`,
+			},
+		},
+		Results: []map[string]any{
+			{
+				"Name": "Alice",
+				"Age":  uint64(32),
+			},
+			{
+				"Name": "Bob",
+				"Age":  uint64(32),
+			},
+			{
+				"Name": "Carlo",
+				"Age":  uint64(21),
+			},
+			{
+				"Name": "John",
+				"Age":  uint64(21),
+			},
+		},
+	}
+
+	executeTestCase(t, test)
+}
+
+func TestQuerySimpleWithOrderListOfMixedDirectionsAndLimit(t *testing.T) {
+	test := testUtils.RequestTestCase{
+		Description: "Simple query with order list, string descending then numeric ascending, and limit",
+		Request: `query {
+					users(order: [{Name: DESC}, {Age: ASC}], limit: 3) {
+						Name
+						Age
+					}
+				}`,
+		Docs: map[int][]string{
+			0: {
+				`{
+					"Name": "John",
+					"Age": 40
+				}`,
+				`{
+					"Name": "Bob",
+					"Age": 32
+				}`,
+				`{
+					"Name": "John",
+					"Age": 21
+				}`,
+				`{
+					"Name": "Alice",
+					"Age": 19
+				}`,
+			},
+		},
+		Results: []map[string]any{
+			{
+				"Name": "John",
+				"Age":  uint64(21),
+			},
+			{
+				"Name": "John",
+				"Age":  uint64(40),
+			},
+			{
+				"Name": "Bob",
+				"Age":  uint64(32),
+			},
+		},
+	}
+
+	executeTestCase(t, test)
+}
+
+func TestQuerySimpleWithOrderVariableOfSingleObject(t *testing.T) {
+	test := testUtils.TestCase{
+		Description: "Simple query with order given by a variable of a single order object",
+		Actions: []any{
+			testUtils.SchemaUpdate{
+				Schema: userCollectionGQLSchema,
+			},
+			testUtils.CreateDoc{
+				Doc: `{
+					"Name": "John",
+					"Age": 21
+				}`,
+			},
+			testUtils.CreateDoc{
+				Doc: `{
+					"Name": "Bob",
+					"Age": 32
+				}`,
+			},
+			testUtils.Request{
+				Request: `query($order: usersOrderArg) {
+					users(order: $order) {
+						Name
+					}
+				}`,
+				Variables: map[string]any{
+					"order": map[string]any{"Age": "DESC"},
+				},
+				Results: []map[string]any{
+					{
+						"Name": "Bob",
+					},
+					{
+						"Name": "John",
+					},
+				},
+			},
+		},
+	}
+
+	testUtils.ExecuteTestCase(t, []string{"users"}, test)
+}
+
+func TestQuerySimpleWithOrderVariableOfList(t *testing.T) {
+	test := testUtils.TestCase{
+		Description: "Simple query with order given by a variable of a list of order objects",
+		Actions: []any{
+			testUtils.SchemaUpdate{
+				Schema: userCollectionGQLSchema,
+			},
+			testUtils.CreateDoc{
+				Doc: `{
+					"Name": "John",
+					"Age": 40
+				}`,
+			},
+			testUtils.CreateDoc{
+				Doc: `{
+					"Name": "Bob",
+					"Age": 32
+				}`,
+			},
+			testUtils.CreateDoc{
+				Doc: `{
+					"Name": "John",
+					"Age": 21
+				}`,
+			},
+			testUtils.Request{
+				Request: `query($order: [usersOrderArg]) {
+					users(order: $order) {
+						Name
+						Age
+					}
+				}`,
+				Variables: map[string]any{
+					"order": []any{
+						map[string]any{"Name": "DESC"},
+						map[string]any{"Age": "ASC"},
+					},
+				},
+				Results: []map[string]any{
+					{
+						"Name": "John",
+						"Age":  uint64(21),
+					},
+					{
+						"Name": "John",
+						"Age":  uint64(40),
+					},
+					{
+						"Name": "Bob",
+						"Age":  uint64(32),
+					},
+				},
+			},
+		},
+	}
+
+	testUtils.ExecuteTestCase(t, []string{"users"}, test)
+}
@@ -83,3 +83,27 @@ func TestQuerySimpleWithSum(t *testing.T) {
 
 	executeTestCase(t, test)
 }
+
+func TestQuerySimpleWithSumOverflowingFloat(t *testing.T) {
+	test := testUtils.RequestTestCase{
+		Description: "Simple query, sum of floats overflowing the range of floats",
+		Request: `query {
+					_sum(users: {field: HeightM})
+				}`,
+		Docs: map[int][]string{
+			0: {
+				`{
+					"Name": "John",
+					"HeightM": 1.7976931348623157e308
+				}`,
+				`{
+					"Name": "Bob",
+					"HeightM": 1.7976931348623157e308
+				}`,
+			},
+		},
+		ExpectedError: "the result of the aggregate is not a finite number",
+	}
+
+	executeTestCase(t, test)
+}
@@ -46,14 +46,14 @@ func TestQuerySimpleWithEmbeddedLatestCommit(t *testing.T) {
 				"Age":  uint64(21),
 				"_version": []map[string]any{
 					{
-						"cid": "bafybeiaahzxsfz55nuqnsll42wxrbdjmy5si222l4ydbrwb53tpxnzdmwq",
+						"cid": testUtils.DocCreateCID{},
 						"links": []map[string]any{
 							{
-								"cid":  "bafybeibelbquwus6f4lek2uclnmt5o525qbg46pzgxkjmrfjg3hwg63r4i",
+								"cid":  testUtils.DocCreateCID{FieldName: "Age"},
 								"name": "Age",
 							},
 							{
-								"cid":  "bafybeih7josq7krvco7jc5z2mhiox4dge77zyn3v7gmemfjkkh3xkqwpni",
+								"cid":  testUtils.DocCreateCID{FieldName: "Name"},
 								"name": "Name",
 							},
 						},
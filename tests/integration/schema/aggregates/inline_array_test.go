@@ -244,7 +244,7 @@ func TestSchemaAggregateInlineArrayCreatesUsersSum(t *testing.T) {
 												map[string]any{
 													"name": "order",
 													"type": map[string]any{
-														"name": "usersOrderArg",
+														"name": nil,
 													},
 												},
 											},
@@ -366,7 +366,7 @@ func TestSchemaAggregateInlineArrayCreatesUsersAverage(t *testing.T) {
 												map[string]any{
 													"name": "order",
 													"type": map[string]any{
-														"name": "usersOrderArg",
+														"name": nil,
 													},
 												},
 											},
@@ -400,6 +400,12 @@ var aggregateGroupArg = map[string]any{
 								"name": nil,
 							},
 						},
+						map[string]any{
+							"name": "_createdAt",
+							"type": map[string]any{
+								"name": "DateTimeOperatorBlock",
+							},
+						},
 						map[string]any{
 							"name": "_key",
 							"type": map[string]any{
@@ -418,6 +424,12 @@ var aggregateGroupArg = map[string]any{
 								"name": nil,
 							},
 						},
+						map[string]any{
+							"name": "_updatedAt",
+							"type": map[string]any{
+								"name": "DateTimeOperatorBlock",
+							},
+						},
 					},
 				},
 			},
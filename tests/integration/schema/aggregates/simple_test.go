@@ -196,9 +196,11 @@ func TestSchemaAggregateSimpleCreatesUsersSum(t *testing.T) {
 												map[string]any{
 													"name": "order",
 													"type": map[string]any{
-														"name":   "usersOrderArg",
-														"kind":   "INPUT_OBJECT",
-														"ofType": nil,
+														"name": nil,
+														"kind": "LIST",
+														"ofType": map[string]any{
+															"name": "usersOrderArg",
+														},
 													},
 												},
 											},
@@ -300,9 +302,11 @@ func TestSchemaAggregateSimpleCreatesUsersAverage(t *testing.T) {
 												map[string]any{
 													"name": "order",
 													"type": map[string]any{
-														"name":   "usersOrderArg",
-														"kind":   "INPUT_OBJECT",
-														"ofType": nil,
+														"name": nil,
+														"kind": "LIST",
+														"ofType": map[string]any{
+															"name": "usersOrderArg",
+														},
 													},
 												},
 											},
@@ -60,8 +60,12 @@ var DefaultFields = concat(
 	fields{
 		keyField,
 		versionField,
+		historyField,
+		metaField,
 		groupField,
 		deletedField,
+		createdAtField,
+		updatedAtField,
 	},
 	aggregateFields,
 )
@@ -82,6 +86,22 @@ var deletedField = Field{
 	},
 }
 
+var createdAtField = Field{
+	"name": "_createdAt",
+	"type": map[string]any{
+		"kind": "SCALAR",
+		"name": "DateTime",
+	},
+}
+
+var updatedAtField = Field{
+	"name": "_updatedAt",
+	"type": map[string]any{
+		"kind": "SCALAR",
+		"name": "DateTime",
+	},
+}
+
 var versionField = Field{
 	"name": "_version",
 	"type": map[string]any{
@@ -90,6 +110,22 @@ var versionField = Field{
 	},
 }
 
+var historyField = Field{
+	"name": "_history",
+	"type": map[string]any{
+		"kind": "LIST",
+		"name": nil,
+	},
+}
+
+var metaField = Field{
+	"name": "_meta",
+	"type": map[string]any{
+		"kind": "OBJECT",
+		"name": "DocMeta",
+	},
+}
+
 var groupField = Field{
 	"name": "_group",
 	"type": map[string]any{
@@ -98,6 +134,18 @@ var groupField = Field{
 	},
 }
 
+// JoinField returns the field with which an object may be joined
+// with the given object by an ad-hoc join.
+func JoinField(objectName string) Field {
+	return Field{
+		"name": "_join_" + objectName,
+		"type": map[string]any{
+			"kind": "LIST",
+			"name": nil,
+		},
+	}
+}
+
 var aggregateFields = fields{
 	map[string]any{
 		"name": "_avg",
@@ -122,6 +170,13 @@ var aggregateFields = fields{
 	},
 }
 
+var atTimeArg = Field{
+	"name": "atTime",
+	"type": map[string]any{
+		"name":        "DateTime",
+		"inputFields": nil,
+	},
+}
 var cidArg = Field{
 	"name": "cid",
 	"type": map[string]any{
@@ -155,6 +210,19 @@ var showDeletedArg = Field{
 	},
 }
 
+var collationArg = Field{
+	"name": "collation",
+	"type": map[string]any{
+		"name": "Collation",
+		"inputFields": []any{
+			makeInputObject("caseInsensitive", "Boolean", nil),
+			makeInputObject("locale", "String", nil),
+			makeInputObject("numeric", "Boolean", nil),
+		},
+		"ofType": nil,
+	},
+}
+
 var groupByArg = Field{
 	"name": "groupBy",
 	"type": map[string]any{
@@ -192,7 +260,9 @@ type argDef struct {
 
 func buildOrderArg(objectName string, fields []argDef) Field {
 	inputFields := []any{
+		makeInputObject("_createdAt", "Ordering", nil),
 		makeInputObject("_key", "Ordering", nil),
+		makeInputObject("_updatedAt", "Ordering", nil),
 	}
 
 	for _, field := range fields {
@@ -202,9 +272,13 @@ func buildOrderArg(objectName string, fields []argDef) Field {
 	return Field{
 		"name": "order",
 		"type": Field{
-			"name":        objectName + "OrderArg",
-			"ofType":      nil,
-			"inputFields": inputFields,
+			"name":        nil,
+			"inputFields": nil,
+			"ofType": Field{
+				"kind":        "INPUT_OBJECT",
+				"name":        objectName + "OrderArg",
+				"inputFields": inputFields,
+			},
 		},
 	}
 }
@@ -217,12 +291,14 @@ func buildFilterArg(objectName string, fields []argDef) Field {
 			"kind": "INPUT_OBJECT",
 			"name": filterArgName,
 		}),
+		makeInputObject("_createdAt", "DateTimeOperatorBlock", nil),
 		makeInputObject("_key", "IDOperatorBlock", nil),
 		makeInputObject("_not", "authorFilterArg", nil),
 		makeInputObject("_or", nil, map[string]any{
 			"kind": "INPUT_OBJECT",
 			"name": filterArgName,
 		}),
+		makeInputObject("_updatedAt", "DateTimeOperatorBlock", nil),
 	}
 
 	for _, field := range fields {
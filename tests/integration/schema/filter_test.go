@@ -75,6 +75,13 @@ func TestFilterForSimpleSchema(t *testing.T) {
 															},
 														},
 													},
+													map[string]any{
+														"name": "_createdAt",
+														"type": map[string]any{
+															"name":   "DateTimeOperatorBlock",
+															"ofType": nil,
+														},
+													},
 													map[string]any{
 														"name": "_key",
 														"type": map[string]any{
@@ -98,6 +105,13 @@ func TestFilterForSimpleSchema(t *testing.T) {
 															},
 														},
 													},
+													map[string]any{
+														"name": "_updatedAt",
+														"type": map[string]any{
+															"name":   "DateTimeOperatorBlock",
+															"ofType": nil,
+														},
+													},
 													map[string]any{
 														"name": "name",
 														"type": map[string]any{
@@ -131,10 +145,12 @@ var testFilterForSimpleSchemaArgProps = map[string]any{
 
 var defaultUserArgsWithoutFilter = trimFields(
 	fields{
+		atTimeArg,
 		cidArg,
 		dockeyArg,
 		dockeysArg,
 		showDeletedArg,
+		collationArg,
 		groupByArg,
 		limitArg,
 		offsetArg,
@@ -213,6 +229,13 @@ func TestFilterForOneToOneSchema(t *testing.T) {
 															},
 														},
 													},
+													map[string]any{
+														"name": "_createdAt",
+														"type": map[string]any{
+															"name":   "DateTimeOperatorBlock",
+															"ofType": nil,
+														},
+													},
 													map[string]any{
 														"name": "_key",
 														"type": map[string]any{
@@ -236,6 +259,13 @@ func TestFilterForOneToOneSchema(t *testing.T) {
 															},
 														},
 													},
+													map[string]any{
+														"name": "_updatedAt",
+														"type": map[string]any{
+															"name":   "DateTimeOperatorBlock",
+															"ofType": nil,
+														},
+													},
 													map[string]any{
 														"name": "author",
 														"type": map[string]any{
@@ -283,10 +313,12 @@ var testFilterForOneToOneSchemaArgProps = map[string]any{
 
 var defaultBookArgsWithoutFilter = trimFields(
 	fields{
+		atTimeArg,
 		cidArg,
 		dockeyArg,
 		dockeysArg,
 		showDeletedArg,
+		collationArg,
 		groupByArg,
 		limitArg,
 		offsetArg,
@@ -49,6 +49,16 @@ func TestInputTypeOfOrderFieldWhereSchemaHasRelationType(t *testing.T) {
 										ofType {
 											name
 											kind
+											inputFields {
+												name
+												type {
+													name
+													ofType {
+														name
+														kind
+													}
+												}
+											}
 										}
 										inputFields {
 											name
@@ -80,50 +90,68 @@ func TestInputTypeOfOrderFieldWhereSchemaHasRelationType(t *testing.T) {
 									map[string]any{
 										"name": "order",
 										"type": map[string]any{
-											"name":   "authorOrderArg",
-											"ofType": nil,
-											"inputFields": []any{
-												map[string]any{
-													"name": "_key",
-													"type": map[string]any{
-														"name":   "Ordering",
-														"ofType": nil,
+											"name":        nil,
+											"inputFields": nil,
+											"ofType": map[string]any{
+												"name": "authorOrderArg",
+												"kind": "INPUT_OBJECT",
+												"inputFields": []any{
+													map[string]any{
+														"name": "_createdAt",
+														"type": map[string]any{
+															"name":   "Ordering",
+															"ofType": nil,
+														},
 													},
-												},
-												map[string]any{
-													"name": "age",
-													"type": map[string]any{
-														"name":   "Ordering",
-														"ofType": nil,
+													map[string]any{
+														"name": "_key",
+														"type": map[string]any{
+															"name":   "Ordering",
+															"ofType": nil,
+														},
 													},
-												},
-												map[string]any{
-													"name": "name",
-													"type": map[string]any{
-														"name":   "Ordering",
-														"ofType": nil,
+													map[string]any{
+														"name": "_updatedAt",
+														"type": map[string]any{
+															"name":   "Ordering",
+															"ofType": nil,
+														},
 													},
-												},
-												map[string]any{
-													"name": "verified",
-													"type": map[string]any{
-														"name":   "Ordering",
-														"ofType": nil,
+													map[string]any{
+														"name": "age",
+														"type": map[string]any{
+															"name":   "Ordering",
+															"ofType": nil,
+														},
 													},
-												},
-												// Without the relation type we won't have the following ordering type(s).
-												map[string]any{
-													"name": "wrote",
-													"type": map[string]any{
-														"name":   "bookOrderArg",
-														"ofType": nil,
+													map[string]any{
+														"name": "name",
+														"type": map[string]any{
+															"name":   "Ordering",
+															"ofType": nil,
+														},
 													},
-												},
-												map[string]any{
-													"name": "wrote_id",
-													"type": map[string]any{
-														"name":   "Ordering",
-														"ofType": nil,
+													map[string]any{
+														"name": "verified",
+														"type": map[string]any{
+															"name":   "Ordering",
+															"ofType": nil,
+														},
+													},
+													// Without the relation type we won't have the following ordering type(s).
+													map[string]any{
+														"name": "wrote",
+														"type": map[string]any{
+															"name":   "bookOrderArg",
+															"ofType": nil,
+														},
+													},
+													map[string]any{
+														"name": "wrote_id",
+														"type": map[string]any{
+															"name":   "Ordering",
+															"ofType": nil,
+														},
 													},
 												},
 											},
@@ -146,8 +174,9 @@ var testInputTypeOfOrderFieldWhereSchemaHasRelationTypeArgProps = map[string]any
 	"type": map[string]any{
 		"name": struct{}{},
 		"ofType": map[string]any{
-			"kind": struct{}{},
-			"name": struct{}{},
+			"kind":        struct{}{},
+			"name":        struct{}{},
+			"inputFields": struct{}{},
 		},
 		"inputFields": struct{}{},
 	},
@@ -179,6 +208,7 @@ var defaultGroupArgsWithoutOrder = trimFields(
 				typeName:  "IDOperatorBlock",
 			},
 		}),
+		collationArg,
 		groupByArg,
 		limitArg,
 		offsetArg,
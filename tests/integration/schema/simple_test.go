@@ -73,7 +73,7 @@ func TestSchemaSimpleErrorsGivenDuplicateSchemaInSameSDL(t *testing.T) {
 					type users {}
 					type users {}
 				`,
-				ExpectedError: "schema type already exists",
+				ExpectedError: "3:11 DUPLICATE_TYPE: the type users is already defined",
 			},
 		},
 	}
@@ -140,7 +140,7 @@ func TestSchemaSimpleCreatesSchemaWithDefaultFieldsGivenEmptyType(t *testing.T)
 				ExpectedData: map[string]any{
 					"__type": map[string]any{
 						"name":   "users",
-						"fields": DefaultFields.Tidy(),
+						"fields": DefaultFields.Append(JoinField("users")).Tidy(),
 					},
 				},
 			},
@@ -159,7 +159,7 @@ func TestSchemaSimpleErrorsGivenTypeWithInvalidFieldType(t *testing.T) {
 						Name: NotAType
 					}
 				`,
-				ExpectedError: "no type found for given name",
+				ExpectedError: "3:13 UNKNOWN_TYPE: the field Name of the type users references the unknown type NotAType",
 			},
 		},
 	}
@@ -195,7 +195,7 @@ func TestSchemaSimpleCreatesSchemaGivenTypeWithStringField(t *testing.T) {
 				ExpectedData: map[string]any{
 					"__type": map[string]any{
 						"name": "users",
-						"fields": DefaultFields.Append(
+						"fields": DefaultFields.Append(JoinField("users")).Append(
 							Field{
 								"name": "Name",
 								"type": map[string]any{
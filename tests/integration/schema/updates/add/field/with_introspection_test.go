@@ -51,7 +51,7 @@ func TestSchemaUpdatesAddFieldIntrospection(t *testing.T) {
 				ExpectedData: map[string]any{
 					"__type": map[string]any{
 						"name": "Users",
-						"fields": introspectionUtils.DefaultFields.Append(
+						"fields": introspectionUtils.DefaultFields.Append(introspectionUtils.JoinField("Users")).Append(
 							introspectionUtils.Field{
 								"name": "Name",
 								"type": map[string]any{
@@ -107,7 +107,7 @@ func TestSchemaUpdatesAddFieldIntrospectionDoesNotAmendGQLTypesGivenBadPatch(t *
 					"__type": map[string]any{
 						"name": "Users",
 						// No fields have been added to the GQL [Users] type.
-						"fields": introspectionUtils.DefaultFields.Tidy(),
+						"fields": introspectionUtils.DefaultFields.Append(introspectionUtils.JoinField("Users")).Tidy(),
 					},
 				},
 			},
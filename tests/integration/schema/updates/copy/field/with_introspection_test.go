@@ -55,7 +55,7 @@ func TestSchemaUpdatesCopyFieldIntrospectionWithRemoveIDAndReplaceName(t *testin
 				ExpectedData: map[string]any{
 					"__type": map[string]any{
 						"name": "Users",
-						"fields": introspectionUtils.DefaultFields.Append(
+						"fields": introspectionUtils.DefaultFields.Append(introspectionUtils.JoinField("Users")).Append(
 							introspectionUtils.Field{
 								"name": "Name",
 								"type": map[string]any{
@@ -14,6 +14,7 @@ import (
 	"github.com/sourcenetwork/immutable"
 
 	"github.com/sourcenetwork/defradb/config"
+	"github.com/sourcenetwork/defradb/db"
 )
 
 // TestCase contains the details of the test case to execute.
@@ -44,6 +45,10 @@ type SetupComplete struct{}
 // effected on all nodes.
 type ConfigureNode struct {
 	config.Config
+
+	// DBOptions are the options the database of the node is created with, such as its storage
+	// quotas. Optional.
+	DBOptions []db.Option
 }
 
 // SchemaUpdate is an action that will update the database schema.
@@ -87,6 +92,10 @@ type CreateDoc struct {
 	// The document to create, in JSON string format.
 	Doc string
 
+	// The number of documents the creation is expected to prune to keep the collection within
+	// its storage quota, whose deletes are pushed to the replicators along with it. Optional.
+	Pruned int
+
 	// Any error expected from the action. Optional.
 	//
 	// String can be a partial, and the test will pass if an error is returned that
@@ -161,6 +170,9 @@ type Request struct {
 	// The request to execute.
 	Request string
 
+	// The values of the variables of the request, by variable name. Optional.
+	Variables map[string]any
+
 	// The expected (data) results of the issued request.
 	Results []map[string]any
 
@@ -184,9 +184,10 @@ func NewBadgerMemoryDB(ctx context.Context, dbopts ...db.Option) (client.DB, err
 	return db, nil
 }
 
-func NewInMemoryDB(ctx context.Context) (client.DB, error) {
+func NewInMemoryDB(ctx context.Context, dbopts ...db.Option) (client.DB, error) {
 	rootstore := memory.NewDatastore(ctx)
-	db, err := db.NewDB(ctx, rootstore, db.WithUpdateEvents())
+	dbopts = append(dbopts, db.WithUpdateEvents())
+	db, err := db.NewDB(ctx, rootstore, dbopts...)
 	if err != nil {
 		return nil, err
 	}
@@ -194,7 +195,7 @@ func NewInMemoryDB(ctx context.Context) (client.DB, error) {
 	return db, nil
 }
 
-func NewBadgerFileDB(ctx context.Context, t testing.TB) (client.DB, error) {
+func NewBadgerFileDB(ctx context.Context, t testing.TB, dbopts ...db.Option) (client.DB, error) {
 	var path string
 	if databaseDir == "" {
 		path = t.TempDir()
@@ -202,17 +203,18 @@ func NewBadgerFileDB(ctx context.Context, t testing.TB) (client.DB, error) {
 		path = databaseDir
 	}
 
-	return newBadgerFileDB(ctx, t, path)
+	return newBadgerFileDB(ctx, t, path, dbopts...)
 }
 
-func newBadgerFileDB(ctx context.Context, t testing.TB, path string) (client.DB, error) {
+func newBadgerFileDB(ctx context.Context, t testing.TB, path string, dbopts ...db.Option) (client.DB, error) {
 	opts := badgerds.Options{Options: badger.DefaultOptions(path)}
 	rootstore, err := badgerds.NewDatastore(path, &opts)
 	if err != nil {
 		return nil, err
 	}
 
-	db, err := db.NewDB(ctx, rootstore, db.WithUpdateEvents())
+	dbopts = append(dbopts, db.WithUpdateEvents())
+	db, err := db.NewDB(ctx, rootstore, dbopts...)
 	if err != nil {
 		return nil, err
 	}
@@ -238,24 +240,24 @@ func GetDatabaseTypes() []DatabaseType {
 	return databases
 }
 
-func GetDatabase(ctx context.Context, t *testing.T, dbt DatabaseType) (client.DB, error) {
+func GetDatabase(ctx context.Context, t *testing.T, dbt DatabaseType, dbopts ...db.Option) (client.DB, error) {
 	switch dbt {
 	case badgerIMType:
-		db, err := NewBadgerMemoryDB(ctx, db.WithUpdateEvents())
+		db, err := NewBadgerMemoryDB(ctx, dbopts...)
 		if err != nil {
 			return nil, err
 		}
 		return db, nil
 
 	case badgerFileType:
-		db, err := NewBadgerFileDB(ctx, t)
+		db, err := NewBadgerFileDB(ctx, t, dbopts...)
 		if err != nil {
 			return nil, err
 		}
 		return db, nil
 
 	case defraIMType:
-		db, err := NewInMemoryDB(ctx)
+		db, err := NewInMemoryDB(ctx, dbopts...)
 		if err != nil {
 			return nil, err
 		}
@@ -387,7 +389,7 @@ func executeTestCase(
 			resultsChans = append(resultsChans, resultsChan)
 
 		case Request:
-			executeRequest(ctx, t, nodes, testCase, action)
+			executeRequest(ctx, t, nodes, collections, testCase, action)
 
 		case IntrospectionRequest:
 			assertIntrospectionResults(ctx, t, testCase.Description, db, action)
@@ -429,6 +431,7 @@ func closeNodes(
 		if node.Peer != nil {
 			err := node.Close()
 			require.NoError(t, err)
+			continue
 		}
 		node.DB.Close(ctx)
 	}
@@ -635,7 +638,7 @@ func configureNode(
 	// an in memory store.
 	cfg.Datastore.Badger.Path = t.TempDir()
 
-	db, err := GetDatabase(ctx, t, dbt) //disable change dector, or allow it?
+	db, err := GetDatabase(ctx, t, dbt, cfg.DBOptions...) //disable change dector, or allow it?
 	require.NoError(t, err)
 
 	var n *node.Node
@@ -953,10 +956,16 @@ func executeRequest(
 	ctx context.Context,
 	t *testing.T,
 	nodes []*node.Node,
+	nodeCollections [][]client.Collection,
 	testCase TestCase,
 	action Request,
 ) {
+	if action.Variables != nil {
+		ctx = client.WithRequestVariables(ctx, action.Variables)
+	}
+
 	var expectedErrorRaised bool
+	collections := getNodeCollections(action.NodeID, nodeCollections)
 	for nodeID, node := range getNodes(action.NodeID, nodes) {
 		result := node.DB.ExecRequest(ctx, action.Request)
 
@@ -966,7 +975,7 @@ func executeRequest(
 			t,
 			testCase.Description,
 			&result.GQL,
-			action.Results,
+			resolveDocCreateCIDs(t, testCase, collections[nodeID], action.Results),
 			action.ExpectedError,
 			nodeID,
 			anyOfByFieldKey,